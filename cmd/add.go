@@ -7,12 +7,14 @@ import (
   "os"
   "strconv"
   "strings"
+  "time"
 
   "github.com/spf13/cobra"
   "golang.org/x/term"
   "sshm/internal/auth"
   "sshm/internal/color"
   "sshm/internal/config"
+  "sshm/internal/service"
 )
 
 var addCmd = &cobra.Command{
@@ -30,6 +32,24 @@ CLI Flags:
   • --auth-type: Authentication method - 'key' or 'password' (required for non-interactive)
   • --key-path: Path to SSH key file (required if auth-type is 'key')
   • --passphrase-protected: Whether the SSH key is passphrase protected (default: false)
+  • --resolve-to: IP address to dial instead of resolving the hostname (optional)
+  • --alias: Alternative name for the server, repeatable (optional)
+  • --proxy-jump: Jump host, an sshm server or [user@]host[:port]; repeat or comma-separate for several hops (optional)
+  • --startup-command: Command typed into new tmux windows once connected, repeatable (optional)
+  • --env: NAME=value exported in new tmux windows before the startup commands, repeatable (optional)
+  • --tag: Tag used for grouping and filtering, repeatable (optional)
+  • --require-banner-ack: Show the login banner and require acceptance before connecting (optional)
+  • --protected: Require typing the server name to confirm power actions (optional)
+  • --expires: Archive the server after a duration (4h, 3d) or at a date, for scratch servers (optional)
+  • --timezone: IANA time zone of the host, shown as its local time in the TUI (optional)
+  • --owner, --team, --cost-center, --environment: Governance metadata for 'sshm report' (optional)
+  • --bootstrap-file: Local dotfile copied to the remote home directory on connect, repeatable (optional)
+  • --bootstrap-script: Local script uploaded and sourced on login (optional)
+  • --profile: Profile to assign the server to, repeatable (optional)
+  • --json: Print the added server as JSON instead of messages (optional)
+
+Passwords are read from the terminal, or from the first line of standard input
+when it is not a terminal, so scripts can pipe them in.
 
 The server configuration will be stored securely in ~/.sshm/config.yaml
   
@@ -41,7 +61,16 @@ Examples:
   sshm add web-server --hostname web.example.com --username webuser --auth-type key --key-path ~/.ssh/web_key
   
   # Non-interactive with password authentication  
-  sshm add db-server --hostname db.example.com --username dbuser --auth-type password --port 3306`,
+  sshm add db-server --hostname db.example.com --username dbuser --auth-type password --port 3306
+
+  # Pin the address when DNS is wrong or split-horizon
+  sshm add intranet --hostname app.corp.local --resolve-to 10.0.4.12 --alias app --username ops --auth-type key --key-path ~/.ssh/id_ed25519
+
+  # Reach a private host through the bastion server, then a second hop
+  sshm add db-internal --hostname 10.0.8.20 --username ops --auth-type key --key-path ~/.ssh/id_ed25519 --proxy-jump bastion,admin@10.0.1.5:2222
+
+  # From a script: password on standard input, result as JSON
+  echo "$DB_PASSWORD" | sshm add db-ci --hostname db.example.com --username ci --auth-type password --profile ci --json`,
   Args: cobra.ExactArgs(1),
   RunE: func(cmd *cobra.Command, args []string) error {
     return runAddCommand(cmd, args, cmd.OutOrStdout())
@@ -50,6 +79,14 @@ Examples:
 
 func runAddCommand(cmd *cobra.Command, args []string, output io.Writer) error {
   serverName := strings.TrimSpace(args[0])
+  profiles, _ := cmd.Flags().GetStringSlice("profile")
+
+  // With --json only the added server is printed; prompts go to standard error
+  asJSON, _ := cmd.Flags().GetBool("json")
+  result, prompts := output, output
+  if asJSON {
+    output, prompts = io.Discard, cmd.ErrOrStderr()
+  }
   
   // Validate server name
   if serverName == "" {
@@ -57,10 +94,11 @@ func runAddCommand(cmd *cobra.Command, args []string, output io.Writer) error {
   }
   
   // Load existing configuration
-  cfg, err := config.Load()
+  svc, err := service.Load()
   if err != nil {
     return fmt.Errorf("❌ Failed to load configuration: %w", err)
   }
+  cfg := svc.Config()
 
   // Check if server already exists
   if _, err := cfg.GetServer(serverName); err == nil {
@@ -69,6 +107,10 @@ func runAddCommand(cmd *cobra.Command, args []string, output io.Writer) error {
 
   // Check if we're using CLI flags or interactive mode
   usingFlags := cmd.Flags().Changed("hostname") || cmd.Flags().Changed("username") || cmd.Flags().Changed("auth-type")
+  if asJSON {
+    // Scripts never answer the interactive questions
+    usingFlags = true
+  }
   
   var hostname, username, authType, keyPath string
   var port int
@@ -185,14 +227,12 @@ func runAddCommand(cmd *cobra.Command, args []string, output io.Writer) error {
   var password string
   if authType == "password" {
     if usingFlags {
-      // In CLI flag mode, prompt for password as it's sensitive information
-      fmt.Fprintf(output, "Enter password for %s@%s: ", username, hostname)
-      passwordBytes, err := term.ReadPassword(int(os.Stdin.Fd()))
-      fmt.Println() // New line after password input
+      // In CLI flag mode, prompt for password as it's sensitive information;
+      // scripts pipe it in on standard input instead
+      password, err = readSecret(prompts, os.Stdin, fmt.Sprintf("Enter password for %s@%s: ", username, hostname))
       if err != nil {
         return fmt.Errorf("❌ Failed to read password: %w", err)
       }
-      password = string(passwordBytes)
     } else {
       // In interactive mode, also prompt securely for password
       fmt.Fprint(output, "Enter password: ")
@@ -224,16 +264,44 @@ func runAddCommand(cmd *cobra.Command, args []string, output io.Writer) error {
     server.PassphraseProtected = passphraseProtected
   }
 
+  // Set optional address override and aliases
+  server.ResolveTo, _ = cmd.Flags().GetString("resolve-to")
+  server.Aliases, _ = cmd.Flags().GetStringSlice("alias")
+  server.ProxyJump, _ = cmd.Flags().GetStringSlice("proxy-jump")
+  server.StartupCommands, _ = cmd.Flags().GetStringArray("startup-command")
+  envEntries, _ := cmd.Flags().GetStringArray("env")
+  server.RemoteEnv, err = config.ParseRemoteEnv(envEntries)
+  if err != nil {
+    return fmt.Errorf("❌ %w", err)
+  }
+  server.RequireBannerAck, _ = cmd.Flags().GetBool("require-banner-ack")
+  server.Protected, _ = cmd.Flags().GetBool("protected")
+  server.Tags, _ = cmd.Flags().GetStringSlice("tag")
+
+  // Scratch servers are archived automatically once they expire
+  expires, _ := cmd.Flags().GetString("expires")
+  server.ExpiresAt, err = config.ParseExpiry(expires, time.Now())
+  if err != nil {
+    return fmt.Errorf("❌ %w", err)
+  }
+  server.Timezone, _ = cmd.Flags().GetString("timezone")
+  server.Metadata.Owner, _ = cmd.Flags().GetString("owner")
+  server.Metadata.Team, _ = cmd.Flags().GetString("team")
+  server.Metadata.CostCenter, _ = cmd.Flags().GetString("cost-center")
+  server.Metadata.Environment, _ = cmd.Flags().GetString("environment")
+
+  // Set optional bootstrap files uploaded on connect
+  bootstrapFiles, _ := cmd.Flags().GetStringSlice("bootstrap-file")
+  bootstrapScript, _ := cmd.Flags().GetString("bootstrap-script")
+  if len(bootstrapFiles) > 0 || bootstrapScript != "" {
+    server.Bootstrap = &config.BootstrapConfig{Files: bootstrapFiles, Script: bootstrapScript}
+  }
+
   // Validate the server configuration
   if err := server.Validate(); err != nil {
     return fmt.Errorf("❌ Invalid server configuration: %w", err)
   }
 
-  // Add server to configuration
-  if err := cfg.AddServer(server); err != nil {
-    return fmt.Errorf("❌ Failed to add server: %w", err)
-  }
-
   // Handle password storage for password authentication
   if authType == "password" && password != "" {
     // Initialize password manager with keyring backend
@@ -242,23 +310,20 @@ func runAddCommand(cmd *cobra.Command, args []string, output io.Writer) error {
       return fmt.Errorf("❌ Failed to initialize secure password storage: %w", err)
     }
 
-    // Store password securely in keyring
+    // Store password securely in keyring; the server is saved with the keyring settings
     if err := passwordManager.StoreServerPassword(&server, password); err != nil {
       return fmt.Errorf("❌ Failed to store password securely: %w", err)
     }
 
-    // Update the server in the configuration to reflect keyring usage
-    cfg.RemoveServer(server.Name) // Remove the old entry
-    if err := cfg.AddServer(server); err != nil { // Add updated entry with keyring settings
-      return fmt.Errorf("❌ Failed to update server configuration: %w", err)
-    }
-
     fmt.Fprintf(output, "%s\n", color.InfoMessage("Password stored securely using %s keyring", passwordManager.ServiceName()))
   }
 
-  // Save configuration
-  if err := cfg.Save(); err != nil {
-    return fmt.Errorf("❌ Failed to save configuration: %w", err)
+  // Add server to configuration and its profiles, and save it
+  if err := svc.AddServer(server, profiles...); err != nil {
+    return fmt.Errorf("❌ Failed to add server: %w", err)
+  }
+  if asJSON {
+    return writeJSON(result, serverJSON(server))
   }
 
   fmt.Fprintf(output, "\n%s\n", color.SuccessMessage("Server '%s' added successfully!", serverName))
@@ -274,6 +339,24 @@ func init() {
   addCmd.Flags().StringP("auth-type", "a", "", "Authentication method - 'key' or 'password' (required for non-interactive)")
   addCmd.Flags().StringP("key-path", "k", "", "Path to SSH key file (required if auth-type is 'key')")
   addCmd.Flags().BoolP("passphrase-protected", "P", false, "Whether the SSH key is passphrase protected (default: false)")
+  addCmd.Flags().String("resolve-to", "", "IP address to dial instead of resolving the hostname")
+  addCmd.Flags().StringSlice("alias", nil, "Alternative name for the server (repeatable)")
+  addCmd.Flags().StringSlice("proxy-jump", nil, "Jump host: an sshm server or [user@]host[:port] (repeatable, in order)")
+  addCmd.Flags().StringArray("startup-command", nil, "Command typed into new tmux windows once connected, e.g. 'cd /srv/app && sudo -i' (repeatable)")
+  addCmd.Flags().StringArray("env", nil, "NAME=value exported in new tmux windows before the startup commands (repeatable)")
+  addCmd.Flags().Bool("require-banner-ack", false, "Require accepting the login banner before connecting")
+  addCmd.Flags().Bool("protected", false, "Require typing the server name to confirm power actions")
+  addCmd.Flags().StringSlice("tag", nil, "Tag used for grouping and filtering (repeatable)")
+  addCmd.Flags().String("owner", "", "Person responsible for the server")
+  addCmd.Flags().String("team", "", "Team that owns the server")
+  addCmd.Flags().String("cost-center", "", "Cost center the server is billed to")
+  addCmd.Flags().String("environment", "", "Environment, e.g. production or staging")
+  addCmd.Flags().String("timezone", "", "IANA time zone of the host, e.g. Asia/Tokyo")
+  addCmd.Flags().String("expires", "", "Archive the server after a duration (4h, 3d) or at a date (2026-01-31)")
+  addCmd.Flags().StringSlice("bootstrap-file", nil, "Local dotfile copied to the remote home directory on connect (repeatable)")
+  addCmd.Flags().String("bootstrap-script", "", "Local script uploaded and sourced on login")
+  addCmd.Flags().StringSlice("profile", nil, "Profile to assign the server to (repeatable)")
+  addCmd.Flags().Bool("json", false, "Print the added server as JSON; never prompts except for a password")
   
   // Set color help function directly on this command
   addCmd.SetHelpFunc(func(cmd *cobra.Command, args []string) {
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"sshm/internal/color"
+	"sshm/internal/config"
+	"sshm/internal/shellalias"
+)
+
+var aliasCmd = &cobra.Command{
+	Use:   "alias",
+	Short: "Generate shell aliases for servers",
+	Long: `Generate a sourceable file with one shell alias per server, so that e.g.
+'ssh-prod-web1' runs 'sshm connect prod-web1' from any shell.
+
+The options used by 'alias generate' are remembered; run 'alias sync' after
+adding or removing servers to bring the file up to date.
+
+Examples:
+  sshm alias generate                           # Aliases for all servers in ~/.sshm/aliases.sh
+  sshm alias generate web1 db1 --prefix s-      # Only some servers, named s-web1 and s-db1
+  sshm alias generate --shell fish --output ~/.config/fish/conf.d/sshm.fish
+  sshm alias sync                               # Regenerate with the saved options
+
+Then add 'source ~/.sshm/aliases.sh' to your shell startup file.`,
+}
+
+var aliasGenerateCmd = &cobra.Command{
+	Use:   "generate [server-names...]",
+	Short: "Generate the alias file for all or selected servers",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("❌ Failed to load configuration: %w", err)
+		}
+
+		settings := config.ShellAliasConfig{Servers: args}
+		settings.Prefix, _ = cmd.Flags().GetString("prefix")
+		settings.Shell, _ = cmd.Flags().GetString("shell")
+		settings.Output, _ = cmd.Flags().GetString("output")
+		settings.Functions, _ = cmd.Flags().GetBool("functions")
+		cfg.ShellAliases = settings
+
+		return runAliasSync(cmd.OutOrStdout(), cfg, true)
+	},
+}
+
+var aliasSyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Regenerate the alias file with the saved options",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("❌ Failed to load configuration: %w", err)
+		}
+		return runAliasSync(cmd.OutOrStdout(), cfg, false)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(aliasCmd)
+	aliasCmd.AddCommand(aliasGenerateCmd)
+	aliasCmd.AddCommand(aliasSyncCmd)
+
+	aliasGenerateCmd.Flags().String("prefix", shellalias.DefaultPrefix, "Prefix for alias names")
+	aliasGenerateCmd.Flags().String("shell", "bash", "Shell syntax: bash, zsh or fish")
+	aliasGenerateCmd.Flags().String("output", shellalias.DefaultOutput, "File to write the aliases to")
+	aliasGenerateCmd.Flags().Bool("functions", false, "Generate shell functions instead of aliases")
+}
+
+func runAliasSync(output io.Writer, cfg *config.Config, saveSettings bool) error {
+	path, count, err := shellalias.Write(cfg)
+	if err != nil {
+		return fmt.Errorf("❌ Failed to generate aliases: %w", err)
+	}
+
+	if saveSettings {
+		if err := cfg.Save(); err != nil {
+			return fmt.Errorf("❌ Failed to save configuration: %w", err)
+		}
+	}
+
+	fmt.Fprintf(output, "%s\n", color.SuccessMessage("Wrote %d alias(es) to %s", count, path))
+	if saveSettings {
+		fmt.Fprintf(output, "%s\n", color.InfoText("Add 'source %s' to your shell startup file", path))
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"sshm/internal/color"
+	"sshm/internal/config"
+)
+
+var archiveCmd = &cobra.Command{
+	Use:   "archive",
+	Short: "List, restore or purge expired scratch servers",
+	Long: `Servers added with --expires are moved to the archive once their expiry
+passes, and 'sshm remove --archive' moves servers there instead of deleting
+them. Archived servers are hidden from lists, the TUI and status checks but
+keep their settings and profile memberships until they are purged.
+
+Examples:
+  sshm archive
+  sshm archive restore scratch-1 --expires 2d
+  sshm archive purge`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runArchiveListCommand(cmd.OutOrStdout())
+	},
+}
+
+var archiveRestoreCmd = &cobra.Command{
+	Use:   "restore <server-name>",
+	Short: "Move an archived server back into the inventory",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		expires, _ := cmd.Flags().GetString("expires")
+		return runArchiveRestoreCommand(cmd.OutOrStdout(), args[0], expires)
+	},
+}
+
+var archivePurgeCmd = &cobra.Command{
+	Use:   "purge",
+	Short: "Permanently delete all archived servers",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		yes, _ := cmd.Flags().GetBool("yes")
+		return runArchivePurgeCommand(cmd.OutOrStdout(), yes)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(archiveCmd)
+	archiveCmd.AddCommand(archiveRestoreCmd)
+	archiveCmd.AddCommand(archivePurgeCmd)
+
+	archiveRestoreCmd.Flags().String("expires", "", "New expiry as a duration (4h, 3d) or date; kept forever when omitted")
+	archivePurgeCmd.Flags().BoolP("yes", "y", false, "Skip the confirmation prompt")
+}
+
+func runArchiveListCommand(output io.Writer) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("❌ Failed to load configuration: %w", err)
+	}
+
+	if len(cfg.Archived) == 0 {
+		fmt.Fprintf(output, "%s\n", color.InfoMessage("No archived servers"))
+		return nil
+	}
+
+	fmt.Fprintf(output, "%s\n", color.InfoMessage("Archived servers (%d):", len(cfg.Archived)))
+	for _, entry := range cfg.Archived {
+		event := "expired"
+		if entry.Removed {
+			event = "removed"
+		}
+		line := fmt.Sprintf("  %s  %s@%s:%d  %s %s", entry.Name, entry.Username, entry.Hostname, entry.Port,
+			event, entry.ExpiredAt.Local().Format("2006-01-02 15:04"))
+		if len(entry.Profiles) > 0 {
+			line += fmt.Sprintf("  (profiles: %s)", strings.Join(entry.Profiles, ", "))
+		}
+		fmt.Fprintf(output, "%s\n", color.InfoText("%s", line))
+	}
+	return nil
+}
+
+func runArchiveRestoreCommand(output io.Writer, serverName, expires string) error {
+	expiresAt, err := config.ParseExpiry(expires, time.Now())
+	if err != nil {
+		return fmt.Errorf("❌ %w", err)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("❌ Failed to load configuration: %w", err)
+	}
+	if err := cfg.RestoreArchived(serverName, expiresAt); err != nil {
+		return fmt.Errorf("❌ %w", err)
+	}
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("❌ Failed to save configuration: %w", err)
+	}
+
+	fmt.Fprintf(output, "%s\n", color.SuccessMessage("Restored %s", serverName))
+	if expiresAt != nil {
+		fmt.Fprintf(output, "%s\n", color.InfoText("  Expires: %s", expiresAt.Local().Format("2006-01-02 15:04")))
+	}
+	return nil
+}
+
+func runArchivePurgeCommand(output io.Writer, yes bool) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("❌ Failed to load configuration: %w", err)
+	}
+	if len(cfg.Archived) == 0 {
+		fmt.Fprintf(output, "%s\n", color.InfoMessage("No archived servers"))
+		return nil
+	}
+
+	if !yes {
+		fmt.Fprintf(output, "%s", color.WarningMessage("Permanently delete %d archived server(s)? (y/N): ", len(cfg.Archived)))
+		scanner := bufio.NewScanner(os.Stdin)
+		scanner.Scan()
+		if answer := strings.ToLower(strings.TrimSpace(scanner.Text())); answer != "y" && answer != "yes" {
+			fmt.Fprintf(output, "%s\n", color.InfoMessage("Purge cancelled"))
+			return nil
+		}
+	}
+
+	count := cfg.PurgeArchived()
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("❌ Failed to save configuration: %w", err)
+	}
+	fmt.Fprintf(output, "%s\n", color.SuccessMessage("Purged %d archived server(s)", count))
+	return nil
+}
@@ -0,0 +1,211 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"sshm/internal/audit"
+	"sshm/internal/color"
+	"sshm/internal/config"
+)
+
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Audit servers over SSH",
+	Long: `Run read-only audits across servers over SSH.
+
+Examples:
+  sshm audit updates --profile production
+  sshm audit updates --profile production --csv updates.csv
+  sshm audit keys --profile production --users --known team-keys.pub`,
+}
+
+var auditUpdatesCmd = &cobra.Command{
+	Use:   "updates",
+	Short: "Check pending package updates across servers",
+	Long: `Check pending package updates on every server in a profile (or all servers)
+in parallel. The package manager (apt, dnf or yum) is detected on each host.
+Hosts with security updates outstanding are listed first.
+
+Servers need key or keyring password authentication, since the check runs
+without a terminal.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		profile, _ := cmd.Flags().GetString("profile")
+		csvPath, _ := cmd.Flags().GetString("csv")
+		return runAuditUpdatesCommand(cmd.OutOrStdout(), profile, csvPath)
+	},
+}
+
+var auditKeysCmd = &cobra.Command{
+	Use:   "keys",
+	Short: "Report which public keys grant access to which servers",
+	Long: `Gather the authorized_keys entries on every server in a profile (or all
+servers) and group them by key, showing the user@server logins each key grants.
+
+Keys are recognized from the known_keys configuration section, the public keys
+in ~/.ssh and those next to the servers' key files; --known adds the keys of an
+authorized_keys style file, named by their comments. Any other key is flagged
+as unknown and listed first.
+
+With --users the keys of every user with a login shell are read, not only the
+connecting user's; other users' files are usually only readable as root.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		profile, _ := cmd.Flags().GetString("profile")
+		csvPath, _ := cmd.Flags().GetString("csv")
+		loginUsers, _ := cmd.Flags().GetBool("users")
+		knownFiles, _ := cmd.Flags().GetStringSlice("known")
+		unknownOnly, _ := cmd.Flags().GetBool("unknown-only")
+		return runAuditKeysCommand(cmd.OutOrStdout(), profile, csvPath, loginUsers, knownFiles, unknownOnly)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(auditCmd)
+	auditCmd.AddCommand(auditUpdatesCmd)
+	auditCmd.AddCommand(auditKeysCmd)
+
+	auditUpdatesCmd.Flags().StringP("profile", "p", "", "Audit only servers in this profile")
+	auditUpdatesCmd.Flags().String("csv", "", "Also write the results to this CSV file")
+
+	auditKeysCmd.Flags().StringP("profile", "p", "", "Audit only servers in this profile")
+	auditKeysCmd.Flags().String("csv", "", "Also write every authorized_keys entry to this CSV file")
+	auditKeysCmd.Flags().Bool("users", false, "Read the keys of every user with a login shell")
+	auditKeysCmd.Flags().StringSlice("known", nil, "File of approved public keys, named by their comments (repeatable)")
+	auditKeysCmd.Flags().Bool("unknown-only", false, "List only unknown keys")
+}
+
+func runAuditUpdatesCommand(output io.Writer, profileName, csvPath string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("❌ Failed to load configuration: %w", err)
+	}
+
+	servers := cfg.GetServers()
+	if profileName != "" {
+		servers, err = cfg.GetServersByProfile(profileName)
+		if err != nil {
+			return fmt.Errorf("❌ Profile '%s' not found", profileName)
+		}
+	}
+	if len(servers) == 0 {
+		return fmt.Errorf("❌ No servers to audit")
+	}
+
+	fmt.Fprintf(output, "%s\n", color.InfoMessage("Checking pending updates on %d server(s)...", len(servers)))
+	auditedAt := time.Now()
+	results := audit.Updates(servers)
+
+	w := tabwriter.NewWriter(output, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SERVER\tMANAGER\tPENDING\tSECURITY\tERROR")
+	withSecurity := 0
+	for _, result := range results {
+		if result.HasSecurityUpdates() {
+			withSecurity++
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%s\n", result.Server, result.Manager, result.Total, result.Security, result.Error)
+	}
+	w.Flush()
+
+	if withSecurity > 0 {
+		fmt.Fprintf(output, "%s\n", color.WarningMessage("%d of %d server(s) have security updates outstanding", withSecurity, len(results)))
+	} else {
+		fmt.Fprintf(output, "%s\n", color.SuccessMessage("No security updates outstanding"))
+	}
+
+	if csvPath != "" {
+		file, err := os.Create(csvPath)
+		if err != nil {
+			return fmt.Errorf("❌ Failed to create CSV file: %w", err)
+		}
+		defer file.Close()
+		if err := audit.WriteCSV(file, results, auditedAt); err != nil {
+			return fmt.Errorf("❌ Failed to write CSV file: %w", err)
+		}
+		fmt.Fprintf(output, "%s\n", color.SuccessMessage("Results written to %s", csvPath))
+	}
+	return nil
+}
+
+func runAuditKeysCommand(output io.Writer, profileName, csvPath string, loginUsers bool, knownFiles []string, unknownOnly bool) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("❌ Failed to load configuration: %w", err)
+	}
+
+	servers := cfg.GetServers()
+	if profileName != "" {
+		servers, err = cfg.GetServersByProfile(profileName)
+		if err != nil {
+			return fmt.Errorf("❌ Profile '%s' not found", profileName)
+		}
+	}
+	if len(servers) == 0 {
+		return fmt.Errorf("❌ No servers to audit")
+	}
+
+	known, err := audit.LoadKnownKeys(cfg)
+	if err != nil {
+		return fmt.Errorf("❌ Invalid known_keys: %w", err)
+	}
+	for _, path := range knownFiles {
+		if err := known.AddFile(path); err != nil {
+			return fmt.Errorf("❌ %w", err)
+		}
+	}
+
+	fmt.Fprintf(output, "%s\n", color.InfoMessage("Gathering authorized_keys on %d server(s)...", len(servers)))
+	auditedAt := time.Now()
+	results := audit.AuthorizedKeys(servers, loginUsers)
+	keys := audit.AggregateKeys(results, known)
+
+	w := tabwriter.NewWriter(output, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "FINGERPRINT\tTYPE\tCOMMENT\tKNOWN AS\tLOGINS")
+	for _, key := range keys {
+		if unknownOnly && !key.IsUnknown() {
+			continue
+		}
+		knownAs := key.Known
+		if key.IsUnknown() {
+			knownAs = "UNKNOWN"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", key.Fingerprint, key.Type, strings.Join(key.Comments, ", "), knownAs, strings.Join(key.Logins, ", "))
+	}
+	w.Flush()
+
+	for _, result := range results {
+		switch {
+		case result.Error != "":
+			fmt.Fprintf(output, "%s\n", color.WarningMessage("%s: %s", result.Server, result.Error))
+		case len(result.Unreadable) > 0:
+			fmt.Fprintf(output, "%s\n", color.WarningMessage("%s: couldn't read %s", result.Server, strings.Join(result.Unreadable, ", ")))
+		}
+		if result.Invalid > 0 {
+			fmt.Fprintf(output, "%s\n", color.WarningMessage("%s: %d authorized_keys line(s) aren't public keys", result.Server, result.Invalid))
+		}
+	}
+
+	summary := audit.KeysSummary(results, keys)
+	if audit.UnknownKeyCount(keys) > 0 {
+		fmt.Fprintf(output, "%s\n", color.WarningMessage("%s", summary))
+	} else {
+		fmt.Fprintf(output, "%s\n", color.SuccessMessage("%s", summary))
+	}
+
+	if csvPath != "" {
+		file, err := os.Create(csvPath)
+		if err != nil {
+			return fmt.Errorf("❌ Failed to create CSV file: %w", err)
+		}
+		defer file.Close()
+		if err := audit.WriteKeysCSV(file, results, known, auditedAt); err != nil {
+			return fmt.Errorf("❌ Failed to write CSV file: %w", err)
+		}
+		fmt.Fprintf(output, "%s\n", color.SuccessMessage("Results written to %s", csvPath))
+	}
+	return nil
+}
@@ -1,12 +1,18 @@
 package cmd
 
 import (
+	"bufio"
 	"fmt"
 	"io"
+	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 	"sshm/internal/color"
 	"sshm/internal/config"
+	"sshm/internal/connection"
+	"sshm/internal/secrets"
 	"sshm/internal/tmux"
 )
 
@@ -33,6 +39,12 @@ Session Management:
   • Window names: Named after individual server names
   • Window layout: Each server gets its own window
 
+Passwords and key passphrases:
+  • Secrets stored with 'sshm keyring set' are read from the keyring once
+  • Missing ones are asked for up front, once per shared secret
+  • sshpass answers the prompt in each window; without it, or when a secret
+    is left empty, the windows that still need it typed are listed
+
 Examples:
   sshm batch --profile development   # Connect to all servers in development profile
   sshm batch -p staging             # Connect to all servers in staging profile
@@ -42,7 +54,7 @@ Examples:
 		if profile == "" {
 			return fmt.Errorf("❌ Profile name is required. Use --profile <profile-name>")
 		}
-		return runBatchCommand(profile, cmd.OutOrStdout())
+		return runBatchCommand(profile, cmd.OutOrStdout(), os.Stdin)
 	},
 }
 
@@ -51,7 +63,7 @@ func init() {
 	batchCmd.MarkFlagRequired("profile")
 }
 
-func runBatchCommand(profileName string, output io.Writer) error {
+func runBatchCommand(profileName string, output io.Writer, input io.Reader) error {
 	// Load configuration
 	cfg, err := config.Load()
 	if err != nil {
@@ -79,16 +91,46 @@ func runBatchCommand(profileName string, output io.Writer) error {
 	fmt.Fprintf(output, "%s\n", color.InfoMessage("Creating group session for profile '%s' with %d server(s)...", profileName, len(servers)))
 
 	// Convert config.Server slice to tmux.Server interface slice
-	tmuxServers := make([]tmux.Server, len(servers))
+	profile, _ := cfg.GetProfile(profileName)
+	connectServers := make([]config.Server, len(servers))
 	for i, server := range servers {
-		tmuxServers[i] = &server
+		connectServers[i] = profile.WithLogin(profile.WithSSHTemplate(profile.WithBootstrap(server)))
+	}
+
+	// Keep one reader so piped answers aren't lost between prompts
+	if file, ok := input.(*os.File); !ok || !term.IsTerminal(int(file.Fd())) {
+		input = bufio.NewReader(input)
+	}
+	acks, accepted, err := acknowledgeGroupBanners(output, input, tmuxManager, profileName, servers)
+	if err != nil {
+		return fmt.Errorf("❌ %w", err)
+	}
+	if accepted && !tmuxManager.ProfileSessionExists(profileName) {
+		// New windows may meet hosts that changed their keys since the last connection
+		for _, server := range connectServers {
+			if !confirmHostIdentity(server, output, input) {
+				accepted = false
+				break
+			}
+		}
 	}
+	if !accepted {
+		fmt.Fprintf(output, "%s\n", color.InfoMessage("Group connection cancelled."))
+		return nil
+	}
+	collected, err := collectGroupSecrets(output, input, tmuxManager, profileName, connectServers)
+	if err != nil {
+		return fmt.Errorf("❌ %w", err)
+	}
+	tmuxServers, manual := connection.GroupServers(connectServers, collected, acks)
 
 	// Create group session and connect to all servers
 	sessionName, wasExisting, err := tmuxManager.ConnectToProfile(profileName, tmuxServers)
 	if err != nil {
 		return fmt.Errorf("❌ Failed to create group session: %w", err)
 	}
+	connection.GuardPastes(tmuxManager, sessionName, servers)
+	connection.RecordSession(tmuxManager, sessionName, servers)
 
 	if wasExisting {
 		fmt.Fprintf(output, "%s\n", color.InfoMessage("Found existing group session: %s", sessionName))
@@ -102,6 +144,9 @@ func runBatchCommand(profileName string, output io.Writer) error {
 			fmt.Fprintf(output, "   • Window %d: %s (%s@%s:%d)\n", 
 				i+1, server.Name, server.Username, server.Hostname, server.Port)
 		}
+		for _, name := range manual {
+			fmt.Fprintf(output, "%s\n", color.WarningMessage("Window %s needs its password or passphrase typed", name))
+		}
 	}
 
 	// Attach to the session
@@ -124,27 +169,74 @@ func runBatchCommand(profileName string, output io.Writer) error {
 	return nil
 }
 
-func buildSSHCommandForServer(server config.Server) (string, error) {
-	// Validate server configuration
-	if err := server.Validate(); err != nil {
-		return "", fmt.Errorf("❌ Invalid server configuration for %s: %w", server.Name, err)
+// acknowledgeGroupBanners has the login banner of each server of a new group
+// session that requires it accepted in turn, like single connects do, and
+// returns the accepted ones. Declining any of them cancels the connect.
+func acknowledgeGroupBanners(output io.Writer, input io.Reader, tmuxManager *tmux.Manager, profileName string, servers []config.Server) (connection.BannerAcks, bool, error) {
+	if tmuxManager.ProfileSessionExists(profileName) {
+		return nil, true, nil
+	}
+	flagged := false
+	for _, server := range servers {
+		flagged = flagged || server.RequireBannerAck
+	}
+	if !flagged {
+		return nil, true, nil
 	}
 
-	// Build base SSH command with pseudo-terminal allocation
-	sshCmd := fmt.Sprintf("ssh -t %s@%s", server.Username, server.Hostname)
-
-	// Add port if not default
-	if server.Port != 22 {
-		sshCmd += fmt.Sprintf(" -p %d", server.Port)
+	manager, err := connection.NewManager()
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to initialize connection manager: %w", err)
+	}
+	defer manager.Close()
+	pending, acks, err := manager.PendingBanners(servers)
+	if err != nil {
+		return nil, false, err
 	}
 
-	// Add key-specific options
-	if server.AuthType == "key" && server.KeyPath != "" {
-		sshCmd += fmt.Sprintf(" -i %s", server.KeyPath)
+	reader := bufio.NewReader(input)
+	for _, banner := range pending {
+		fmt.Fprintf(output, "%s\n\n%s\n\n", color.InfoText("Login banner for %s:", banner.Server.Name), strings.TrimRight(banner.Banner, "\n"))
+		fmt.Fprint(output, "Do you accept these terms? (yes/no): ")
+		answer, err := reader.ReadString('\n')
+		if err != nil && answer == "" {
+			return nil, false, fmt.Errorf("failed to read acknowledgment")
+		}
+		if strings.TrimSpace(strings.ToLower(answer)) != "yes" {
+			return nil, false, nil
+		}
+		if err := manager.AcknowledgeBanner(banner.Server, banner.Banner); err != nil {
+			return nil, false, fmt.Errorf("failed to record banner acknowledgment: %w", err)
+		}
+		acks[banner.Server.Name] = true
 	}
+	return acks, true, nil
+}
 
-	// Add common SSH options
-	sshCmd += " -o ServerAliveInterval=60 -o ServerAliveCountMax=3"
+// collectGroupSecrets gathers the passwords and key passphrases the windows of
+// a new group session need, reading stored ones from the keyring and asking for
+// the others up front. It returns nil when the session already exists or
+// sshpass can't answer the prompts.
+func collectGroupSecrets(output io.Writer, input io.Reader, tmuxManager *tmux.Manager, profileName string, servers []config.Server) (*secrets.Collected, error) {
+	if tmuxManager.ProfileSessionExists(profileName) || !connection.CanAnswerPrompts() {
+		return nil, nil
+	}
+	collected := secrets.Collect(servers)
+	if len(collected.Missing) == 0 {
+		return collected, nil
+	}
 
-	return sshCmd, nil
-}
\ No newline at end of file
+	// Keep one reader so piped secrets aren't lost between prompts
+	if file, ok := input.(*os.File); !ok || !term.IsTerminal(int(file.Fd())) {
+		input = bufio.NewReader(input)
+	}
+	fmt.Fprintf(output, "%s\n", color.InfoMessage("%d secret(s) aren't in the keyring; leave one empty to type it in its windows", len(collected.Missing)))
+	for _, request := range collected.Missing {
+		secret, err := readSecret(output, input, request.Label()+": ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the %s: %w", request.Kind, err)
+		}
+		collected.Provide(request, secret)
+	}
+	return collected, nil
+}
@@ -1655,3 +1655,35 @@ func TestRemoveCommandWithYesFlag(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildLauncherItems(t *testing.T) {
+	servers := []config.Server{
+		{Name: "web1", Hostname: "web1.example.com", Port: 22, Username: "deploy", Tags: []string{"nginx"}},
+		{Name: "db1", Hostname: "10.0.0.5", Port: 2222, Username: "admin"},
+	}
+	profiles := []config.Profile{{Name: "production", Servers: []string{"web1"}}}
+
+	result := buildLauncherItems(servers, profiles)
+	if len(result.Items) != 2 {
+		t.Fatalf("Expected 2 items, got %d", len(result.Items))
+	}
+
+	web := result.Items[0]
+	if web.Title != "web1" || web.Arg != "sshm connect web1" {
+		t.Errorf("Unexpected item: %+v", web)
+	}
+	if web.Subtitle != "deploy@web1.example.com:22 · production" {
+		t.Errorf("Unexpected subtitle: %s", web.Subtitle)
+	}
+	if !strings.Contains(web.Match, "nginx") || !strings.Contains(web.Match, "production") {
+		t.Errorf("Expected match terms to include tags and profiles, got: %s", web.Match)
+	}
+	if result.Items[1].Subtitle != "admin@10.0.0.5:2222" {
+		t.Errorf("Unexpected subtitle: %s", result.Items[1].Subtitle)
+	}
+
+	empty := buildLauncherItems(nil, nil)
+	if empty.Items == nil {
+		t.Error("Expected an empty items array rather than null")
+	}
+}
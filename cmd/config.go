@@ -0,0 +1,279 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+	"sshm/internal/color"
+	"sshm/internal/config"
+	"sshm/internal/keyring"
+)
+
+// configPassphraseKey is the keyring entry of the configuration passphrase
+const configPassphraseKey = "config-passphrase"
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Manage the configuration file layout",
+	Long: `Manage how the inventory is stored on disk.
+
+Besides ~/.sshm/config.yaml, servers and profiles can live in any number of YAML
+files under ~/.sshm/conf.d/. They are merged on load and every server and profile
+is written back to the file it came from, which keeps team inventories reviewable
+in git. New servers are stored with the first split profile they are assigned to.
+
+config.yaml can also pull in shared, read-only baselines with an include list of
+local files (relative to the including file) or https:// URLs:
+
+  include:
+    - ~/team/sshm-baseline.yaml
+    - https://config.example.com/sshm/team.yaml
+
+Local servers and profiles override included ones with the same name. Included
+entries are never written back unless you modify them, which turns them into
+local overrides.
+
+Golden images and dev containers can ship a preconfigured inventory. When
+config.yaml doesn't exist yet, sshm writes it from SSHM_BOOTSTRAP (YAML, or
+base64: followed by base64-encoded YAML) or from the https:// URL in
+SSHM_BOOTSTRAP_URL. With SSHM_BOOTSTRAP_LOCK=1 the file is marked locked and made
+read-only, and sshm refuses to save changes to it.
+
+config.yaml can be encrypted at rest with a passphrase (AES-256-GCM under a
+scrypt-derived key). sshm asks for the passphrase when it loads the
+configuration, unless it is in SSHM_CONFIG_PASSPHRASE or was stored in the
+system keyring with 'sshm config encrypt --keyring'. A configuration split into
+conf.d files can't be encrypted.
+
+Examples:
+  sshm config split             # Move each profile and its servers to conf.d/<profile>.yaml
+  sshm config sources           # Show which file each server and profile is stored in
+  sshm config encrypt --keyring # Encrypt config.yaml and keep the passphrase in the keyring
+  sshm config decrypt           # Store config.yaml in plaintext again`,
+}
+
+var configSplitCmd = &cobra.Command{
+	Use:   "split",
+	Short: "Store each profile with its servers in its own file under conf.d",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runConfigSplitCommand(cmd.OutOrStdout())
+	},
+}
+
+var configSourcesCmd = &cobra.Command{
+	Use:   "sources",
+	Short: "Show which file each server and profile is stored in",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runConfigSourcesCommand(cmd.OutOrStdout())
+	},
+}
+
+var configEncryptCmd = &cobra.Command{
+	Use:   "encrypt",
+	Short: "Encrypt the configuration with a passphrase",
+	Long: `Encrypt config.yaml with a passphrase of at least 8 characters. The passphrase
+is read from SSHM_CONFIG_PASSPHRASE or asked for twice. Running it on an
+encrypted configuration changes the passphrase.
+
+With --keyring the passphrase is stored in the system keyring, so sshm unlocks
+the configuration without asking. Where only the file keyring backend is
+available this protects the passphrase much less than the system keychain.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		storeInKeyring, _ := cmd.Flags().GetBool("keyring")
+		passphrase := os.Getenv("SSHM_CONFIG_PASSPHRASE")
+		if passphrase == "" {
+			var err error
+			if passphrase, err = promptNewPassphrase(); err != nil {
+				return err
+			}
+		}
+		return runConfigEncryptCommand(cmd.OutOrStdout(), passphrase, storeInKeyring)
+	},
+}
+
+var configDecryptCmd = &cobra.Command{
+	Use:   "decrypt",
+	Short: "Store the configuration in plaintext again",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runConfigDecryptCommand(cmd.OutOrStdout())
+	},
+}
+
+func init() {
+	configEncryptCmd.Flags().Bool("keyring", false, "Store the passphrase in the system keyring")
+
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configSplitCmd)
+	configCmd.AddCommand(configSourcesCmd)
+	configCmd.AddCommand(configEncryptCmd)
+	configCmd.AddCommand(configDecryptCmd)
+}
+
+func runConfigSplitCommand(output io.Writer) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("❌ Failed to load configuration: %w", err)
+	}
+
+	files, err := cfg.SplitByProfile()
+	if err != nil {
+		return fmt.Errorf("❌ Failed to split configuration: %w", err)
+	}
+	if len(files) == 0 {
+		fmt.Fprintf(output, "%s\n", color.InfoMessage("Every profile is already stored in its own file"))
+		return nil
+	}
+
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("❌ Failed to save configuration: %w", err)
+	}
+
+	for _, file := range files {
+		fmt.Fprintf(output, "%s\n", color.SuccessMessage("Wrote %s", file))
+	}
+	fmt.Fprintf(output, "%s\n", color.InfoText("Servers without a profile remain in config.yaml"))
+	return nil
+}
+
+func runConfigSourcesCommand(output io.Writer) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("❌ Failed to load configuration: %w", err)
+	}
+
+	w := tabwriter.NewWriter(output, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TYPE\tNAME\tFILE")
+	for _, profile := range cfg.GetProfiles() {
+		source := cfg.SourceOfProfile(profile.Name)
+		if cfg.IsIncludedProfile(profile) {
+			source = "(include)"
+		}
+		fmt.Fprintf(w, "profile\t%s\t%s\n", profile.Name, source)
+	}
+	for _, server := range cfg.GetServers() {
+		source := cfg.SourceOfServer(server.Name)
+		if cfg.IsIncludedServer(server) {
+			source = "(include)"
+		}
+		fmt.Fprintf(w, "server\t%s\t%s\n", server.Name, source)
+	}
+	return w.Flush()
+}
+
+// passphraseKeyring returns the keyring the configuration passphrase is stored
+// in. The keyring settings live in the encrypted configuration, so the best
+// available backend is used. It is nil when no keyring can be opened.
+var passphraseKeyring = func() keyring.KeyringManager {
+	return keyring.NewKeyringManagerWithNamespace("auto", keyring.DefaultNamespace)
+}
+
+// configPassphrase returns where the passphrase of an encrypted configuration
+// comes from: SSHM_CONFIG_PASSPHRASE, then the keyring, then a prompt on the
+// terminal when ask is set
+func configPassphrase(ask bool) config.PassphraseFunc {
+	return func(path string) (string, error) {
+		if passphrase := os.Getenv("SSHM_CONFIG_PASSPHRASE"); passphrase != "" {
+			return passphrase, nil
+		}
+		if manager := passphraseKeyring(); manager != nil {
+			if passphrase, err := manager.Retrieve(configPassphraseKey); err == nil && passphrase != "" {
+				return passphrase, nil
+			}
+		}
+		if !ask || !term.IsTerminal(int(os.Stdin.Fd())) {
+			return "", nil
+		}
+		fmt.Fprintf(os.Stderr, "%s ", color.InfoText("🔒 Passphrase for %s:", path))
+		passphrase, err := term.ReadPassword(int(os.Stdin.Fd()))
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return "", fmt.Errorf("failed to read passphrase: %w", err)
+		}
+		return string(passphrase), nil
+	}
+}
+
+// promptNewPassphrase asks for a new passphrase twice
+func promptNewPassphrase() (string, error) {
+	fmt.Print(color.InfoText("New passphrase: "))
+	first, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Println()
+	if err != nil {
+		return "", fmt.Errorf("❌ Failed to read passphrase: %w", err)
+	}
+	fmt.Print(color.InfoText("Repeat passphrase: "))
+	second, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Println()
+	if err != nil {
+		return "", fmt.Errorf("❌ Failed to read passphrase: %w", err)
+	}
+	if string(first) != string(second) {
+		return "", fmt.Errorf("❌ The passphrases don't match")
+	}
+	return string(first), nil
+}
+
+func runConfigEncryptCommand(output io.Writer, passphrase string, storeInKeyring bool) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("❌ Failed to load configuration: %w", err)
+	}
+	wasEncrypted := cfg.Encrypted()
+
+	if err := cfg.SetPassphrase(passphrase); err != nil {
+		return fmt.Errorf("❌ %w", err)
+	}
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("❌ Failed to save configuration: %w", err)
+	}
+	if wasEncrypted {
+		fmt.Fprintf(output, "%s\n", color.SuccessMessage("Changed the configuration passphrase"))
+	} else {
+		fmt.Fprintf(output, "%s\n", color.SuccessMessage("Encrypted the configuration"))
+	}
+
+	manager := passphraseKeyring()
+	if storeInKeyring {
+		if manager == nil {
+			return fmt.Errorf("❌ No keyring is available to store the passphrase in")
+		}
+		if err := manager.Store(configPassphraseKey, passphrase); err != nil {
+			return fmt.Errorf("❌ Failed to store the passphrase in the keyring: %w", err)
+		}
+		fmt.Fprintf(output, "%s\n", color.SuccessMessage("Stored the passphrase in the %s keyring", manager.ServiceName()))
+		return nil
+	}
+	// A passphrase stored before would no longer unlock the configuration
+	if manager != nil {
+		if stored, err := manager.Retrieve(configPassphraseKey); err == nil && stored != passphrase {
+			manager.Delete(configPassphraseKey)
+		}
+	}
+	fmt.Fprintf(output, "%s\n", color.InfoText("sshm will ask for the passphrase whenever it loads the configuration"))
+	return nil
+}
+
+func runConfigDecryptCommand(output io.Writer) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("❌ Failed to load configuration: %w", err)
+	}
+	if !cfg.Encrypted() {
+		fmt.Fprintf(output, "%s\n", color.InfoMessage("The configuration isn't encrypted"))
+		return nil
+	}
+
+	cfg.SetPassphrase("")
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("❌ Failed to save configuration: %w", err)
+	}
+	if manager := passphraseKeyring(); manager != nil {
+		manager.Delete(configPassphraseKey)
+	}
+	fmt.Fprintf(output, "%s\n", color.SuccessMessage("The configuration is stored in plaintext again"))
+	return nil
+}
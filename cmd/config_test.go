@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"sshm/internal/config"
+	"sshm/internal/keyring"
+)
+
+// memoryKeyring keeps the entries the config commands use in memory
+type memoryKeyring struct {
+	keyring.KeyringManager
+	values map[string]string
+}
+
+func (m *memoryKeyring) Store(key, value string) error {
+	m.values[key] = value
+	return nil
+}
+
+func (m *memoryKeyring) Retrieve(key string) (string, error) {
+	value, ok := m.values[key]
+	if !ok {
+		return "", fmt.Errorf("%s not found", key)
+	}
+	return value, nil
+}
+
+func (m *memoryKeyring) Delete(key string) error {
+	delete(m.values, key)
+	return nil
+}
+
+func (m *memoryKeyring) ServiceName() string {
+	return "memory"
+}
+
+func TestConfigEncryptAndDecrypt(t *testing.T) {
+	testDir := t.TempDir()
+	t.Setenv("SSHM_CONFIG_DIR", testDir)
+	t.Setenv("SSHM_CONFIG_PASSPHRASE", "")
+	store := &memoryKeyring{values: make(map[string]string)}
+	original := passphraseKeyring
+	passphraseKeyring = func() keyring.KeyringManager { return store }
+	defer func() { passphraseKeyring = original }()
+	path := filepath.Join(testDir, "config.yaml")
+
+	var output strings.Builder
+	if err := runConfigEncryptCommand(&output, "short", false); err == nil {
+		t.Error("Expected a short passphrase to be rejected")
+	}
+	if err := runConfigEncryptCommand(&output, "correct horse battery", true); err != nil {
+		t.Fatalf("runConfigEncryptCommand() error = %v", err)
+	}
+	if encrypted, err := config.IsEncrypted(path); err != nil || !encrypted {
+		t.Fatalf("Expected %s to be encrypted, got %v, %v", path, encrypted, err)
+	}
+	if passphrase, err := configPassphrase(false)(path); err != nil || passphrase != "correct horse battery" {
+		t.Errorf("Expected the passphrase from the keyring, got %q, %v", passphrase, err)
+	}
+
+	// Changing the passphrase without --keyring drops the stale keyring entry
+	if err := runConfigEncryptCommand(&output, "another long passphrase", false); err != nil {
+		t.Fatalf("runConfigEncryptCommand() error = %v", err)
+	}
+	if !strings.Contains(output.String(), "Changed the configuration passphrase") {
+		t.Errorf("Expected the passphrase change to be reported, got %q", output.String())
+	}
+	if _, ok := store.values[configPassphraseKey]; ok {
+		t.Error("Expected the old passphrase to be removed from the keyring")
+	}
+
+	t.Setenv("SSHM_CONFIG_PASSPHRASE", "from the environment")
+	if passphrase, _ := configPassphrase(false)(path); passphrase != "from the environment" {
+		t.Errorf("Expected SSHM_CONFIG_PASSPHRASE to take precedence, got %q", passphrase)
+	}
+
+	if err := runConfigDecryptCommand(&output); err != nil {
+		t.Fatalf("runConfigDecryptCommand() error = %v", err)
+	}
+	if encrypted, _ := config.IsEncrypted(path); encrypted {
+		t.Error("Expected the configuration in plaintext after decrypt")
+	}
+}
@@ -1,12 +1,17 @@
 package cmd
 
 import (
+  "bufio"
   "fmt"
   "io"
+  "os"
+  "strings"
 
   "github.com/spf13/cobra"
   "sshm/internal/color"
   "sshm/internal/config"
+  "sshm/internal/connection"
+  "sshm/internal/hostkey"
   "sshm/internal/tmux"
 )
 
@@ -20,6 +25,8 @@ This command will:
   • Build the appropriate SSH command with authentication
   • Create a tmux session named after the server
   • Execute the SSH connection within the tmux session
+  • Warn when the host presents other host keys than at earlier connections
+    (protected servers need confirmation before attaching)
   • Attach to the session for interactive use
 
 Requirements:
@@ -30,14 +37,23 @@ Requirements:
 Examples:
   sshm connect production-api   # Connect to production API server
   sshm connect staging-db       # Connect to staging database
-  sshm connect jump-host        # Connect to bastion/jump host`,
+  sshm connect jump-host        # Connect to bastion/jump host
+
+Restricted servers (see 'sshm restrict') run their forced command instead of
+a shell. --command picks one of the other commands allowed for the server:
+  sshm connect noc-gw --command "/usr/local/bin/noc-menu --readonly"`,
   Args: cobra.ExactArgs(1),
   RunE: func(cmd *cobra.Command, args []string) error {
-    return runConnectCommand(args, cmd.OutOrStdout())
+    command, _ := cmd.Flags().GetString("command")
+    return runConnectCommand(args, cmd.OutOrStdout(), command)
   },
 }
 
-func runConnectCommand(args []string, output io.Writer) error {
+func init() {
+  connectCmd.Flags().String("command", "", "Allowed command to run on a restricted server instead of its forced command")
+}
+
+func runConnectCommand(args []string, output io.Writer, command string) error {
   serverName := args[0]
   
   // Load configuration
@@ -60,17 +76,48 @@ func runConnectCommand(args []string, output io.Writer) error {
     return fmt.Errorf("❌ tmux is not available on this system. Please install tmux to use sshm")
   }
 
-  // Build SSH command based on server configuration
-  sshCommand, err := buildSSHCommand(*server)
+  // Servers flagged for compliance must have their login banner accepted first
+  if server.RequireBannerAck {
+    accepted, err := acknowledgeLoginBanner(*server, output)
+    if err != nil {
+      return err
+    }
+    if !accepted {
+      fmt.Fprintf(output, "%s\n", color.InfoMessage("Login banner declined. Connection cancelled."))
+      return nil
+    }
+  }
+
+  // Restricted servers only run their forced command or one of the allowed alternatives
+  if command != "" {
+    restricted, err := server.WithRestrictedCommand(command)
+    if err != nil {
+      return fmt.Errorf("❌ %w", err)
+    }
+    server = &restricted
+  }
+  if server.IsRestricted() {
+    fmt.Fprintf(output, "%s\n", color.InfoMessage("Restricted server: running '%s'", server.RestrictedCommand()))
+  }
+
+  // Build SSH command based on server configuration, with any profile ssh template
+  sshCommand, err := connection.SSHCommand(cfg.WithLogin(cfg.WithSSHTemplate(*server)))
   if err != nil {
     return fmt.Errorf("❌ Failed to build SSH command: %w", err)
   }
 
+  // A new connection may meet a host that changed its keys since the last one;
+  // check before the session opens so a distrusted host is never connected to
+  if !tmuxManager.ServerSessionExists(server.Name) && !confirmHostIdentity(cfg.WithSSHTemplate(*server), output, os.Stdin) {
+    fmt.Fprintf(output, "%s\n", color.InfoMessage("Connection cancelled."))
+    return nil
+  }
+
   fmt.Fprintf(output, "%s\n", color.InfoMessage("Connecting to %s (%s@%s:%d)...", 
     server.Name, server.Username, server.Hostname, server.Port))
 
   // Create tmux session and connect (or reattach to existing)
-  sessionName, wasExisting, err := tmuxManager.ConnectToServer(server.Name, sshCommand)
+  sessionName, wasExisting, err := connection.ConnectSession(tmuxManager, *server, sshCommand)
   if err != nil {
     return fmt.Errorf("❌ Failed to create tmux session: %w", err)
   }
@@ -81,6 +128,13 @@ func runConnectCommand(args []string, output io.Writer) error {
   } else {
     fmt.Fprintf(output, "%s\n", color.InfoMessage("Created tmux session: %s", sessionName))
     fmt.Fprintf(output, "%s\n", color.InfoMessage("SSH command sent to session"))
+    if server.Session != nil && !server.IsRestricted() {
+      names := make([]string, 0, len(server.Session.Windows))
+      for _, window := range server.Session.Windows {
+        names = append(names, window.Name)
+      }
+      fmt.Fprintf(output, "%s\n", color.InfoMessage("Session template windows: %s", strings.Join(names, ", ")))
+    }
   }
 
   // Attach to the session
@@ -99,27 +153,76 @@ func runConnectCommand(args []string, output io.Writer) error {
   return nil
 }
 
-func buildSSHCommand(server config.Server) (string, error) {
-  // Validate server configuration
-  if err := server.Validate(); err != nil {
-    return "", fmt.Errorf("❌ Invalid server configuration: %w", err)
+// confirmHostIdentity compares the host keys the server presents with those seen
+// at earlier connections and warns when they changed. Protected servers only
+// proceed, and the new keys are only trusted, when the user confirms; other
+// servers proceed with the change recorded, and are warned about at every
+// connection until their keys are rotated. Failing to observe the keys doesn't
+// stop the connection.
+func confirmHostIdentity(server config.Server, output io.Writer, input io.Reader) bool {
+  manager, err := connection.NewManager()
+  if err != nil {
+    return true
   }
+  defer manager.Close()
 
-  // Build base SSH command with pseudo-terminal allocation
-  sshCmd := fmt.Sprintf("ssh -t %s@%s", server.Username, server.Hostname)
-  
-  // Add port if not default
-  if server.Port != 22 {
-    sshCmd += fmt.Sprintf(" -p %d", server.Port)
+  change, err := hostkey.ObserveIdentity(manager.GetHistoryManager(), server)
+  if err != nil || change == nil {
+    return true
   }
 
-  // Add key-specific options
-  if server.AuthType == "key" && server.KeyPath != "" {
-    sshCmd += fmt.Sprintf(" -i %s", server.KeyPath)
+  fmt.Fprintf(output, "\n%s\n", color.ErrorMessage("HOST IDENTITY CHANGED"))
+  fmt.Fprintf(output, "%s\n\n", change.Summary())
+  fmt.Fprintf(output, "%s\n\n", color.WarningMessage("Someone may be intercepting the connection, or the host was reinstalled."))
+
+  if !server.Protected {
+    fmt.Fprintf(output, "%s\n\n", color.WarningMessage("Connecting anyway. Once verified, trust the new keys with 'sshm hostkey rotate %s'.", server.Name))
+    return true
   }
 
-  // Add common SSH options
-  sshCmd += " -o ServerAliveInterval=60 -o ServerAliveCountMax=3"
+  fmt.Fprintf(output, "%s", color.WarningMessage("%s is protected. Trust the new keys and connect? (yes/no): ", server.Name))
+  answer, err := bufio.NewReader(input).ReadString('\n')
+  if err != nil && answer == "" {
+    return false
+  }
+  if strings.TrimSpace(strings.ToLower(answer)) != "yes" {
+    return false
+  }
+  if err := hostkey.AcceptIdentity(manager.GetHistoryManager(), change.Server, change.Host, change.Current); err != nil {
+    fmt.Fprintf(output, "%s\n", color.WarningMessage("New host keys not recorded: %v", err))
+  }
+  return true
+}
+
+// acknowledgeLoginBanner shows the server's login banner and records the user's acceptance
+func acknowledgeLoginBanner(server config.Server, output io.Writer) (bool, error) {
+  manager, err := connection.NewManager()
+  if err != nil {
+    return false, fmt.Errorf("❌ Failed to initialize connection manager: %w", err)
+  }
+  defer manager.Close()
 
-  return sshCmd, nil
-}
\ No newline at end of file
+  banner, err := manager.FetchBanner(server)
+  if err != nil {
+    return false, fmt.Errorf("❌ Failed to fetch login banner: %w", err)
+  }
+  if strings.TrimSpace(banner) == "" {
+    return true, nil
+  }
+
+  fmt.Fprintf(output, "%s\n\n%s\n\n", color.InfoText("Login banner for %s:", server.Name), strings.TrimRight(banner, "\n"))
+  fmt.Fprint(output, "Do you accept these terms? (yes/no): ")
+
+  scanner := bufio.NewScanner(os.Stdin)
+  if !scanner.Scan() {
+    return false, fmt.Errorf("❌ Failed to read acknowledgment")
+  }
+  if strings.TrimSpace(strings.ToLower(scanner.Text())) != "yes" {
+    return false, nil
+  }
+
+  if err := manager.AcknowledgeBanner(server, banner); err != nil {
+    return false, fmt.Errorf("❌ Failed to record banner acknowledgment: %w", err)
+  }
+  return true, nil
+}
@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"sshm/internal/color"
+	"sshm/internal/config"
+)
+
+var credentialsCmd = &cobra.Command{
+	Use:   "credentials",
+	Short: "Track key rotation and account expiry dates",
+	Long: `List credential dates that are due soon: key rotations and account expiries
+set per server. The TUI shows a badge in the status bar while any date is within
+the warning window (ui.credential_warning_days, default 14) and lists them on '!'.
+
+Dates are given like server expiries: a duration from now (90d), a date
+(2026-01-31), a date and time, or "never" to clear them.
+
+Examples:
+  sshm credentials                      # Dates due within the warning window
+  sshm credentials --all                # Every recorded date
+  sshm credentials set web1 --key-rotation-due 90d
+  sshm credentials set web1 --account-expires 2026-12-31
+  sshm credentials set web1 --key-rotation-due never`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		all, _ := cmd.Flags().GetBool("all")
+		return runCredentialsListCommand(cmd.OutOrStdout(), all)
+	},
+}
+
+var credentialsSetCmd = &cobra.Command{
+	Use:   "set <server-name>",
+	Short: "Set or clear a server's key rotation and account expiry dates",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var rotation, account *string
+		if cmd.Flags().Changed("key-rotation-due") {
+			value, _ := cmd.Flags().GetString("key-rotation-due")
+			rotation = &value
+		}
+		if cmd.Flags().Changed("account-expires") {
+			value, _ := cmd.Flags().GetString("account-expires")
+			account = &value
+		}
+		return runCredentialsSetCommand(cmd.OutOrStdout(), args[0], rotation, account)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(credentialsCmd)
+	credentialsCmd.AddCommand(credentialsSetCmd)
+
+	credentialsCmd.Flags().Bool("all", false, "List every recorded date, not only those due soon")
+	credentialsSetCmd.Flags().String("key-rotation-due", "", "When the key or password should be rotated (90d, 2026-01-31 or never)")
+	credentialsSetCmd.Flags().String("account-expires", "", "When the account on the server expires (30d, 2026-12-31 or never)")
+}
+
+func runCredentialsListCommand(output io.Writer, all bool) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("❌ Failed to load configuration: %w", err)
+	}
+
+	now := time.Now()
+	window := cfg.UI.CredentialWarningWindow()
+	if all {
+		window = time.Duration(1<<63 - 1)
+	}
+	reminders := cfg.CredentialReminders(now, window)
+	if len(reminders) == 0 {
+		if all {
+			fmt.Fprintf(output, "%s\n", color.InfoMessage("No credential dates recorded. Use 'sshm credentials set' to add some."))
+		} else {
+			fmt.Fprintf(output, "%s\n", color.SuccessMessage("No credentials due within %d days", int(window.Hours()/24)))
+		}
+		return nil
+	}
+
+	w := tabwriter.NewWriter(output, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SERVER\tCREDENTIAL\tDUE\tIN")
+	for _, reminder := range reminders {
+		when := formatTimeUntil(reminder.Due.Sub(now))
+		if reminder.IsOverdue(now) {
+			when = "overdue by " + formatTimeUntil(now.Sub(reminder.Due))
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", reminder.Server, reminder.Kind, reminder.Due.Local().Format("2006-01-02"), when)
+	}
+	w.Flush()
+	return nil
+}
+
+func runCredentialsSetCommand(output io.Writer, serverName string, rotation, account *string) error {
+	if rotation == nil && account == nil {
+		return fmt.Errorf("❌ Set --key-rotation-due, --account-expires or both")
+	}
+
+	now := time.Now()
+	var rotationDue, accountExpires *time.Time
+	var err error
+	if rotation != nil {
+		if rotationDue, err = config.ParseExpiry(*rotation, now); err != nil {
+			return fmt.Errorf("❌ Invalid key rotation date: %w", err)
+		}
+	}
+	if account != nil {
+		if accountExpires, err = config.ParseExpiry(*account, now); err != nil {
+			return fmt.Errorf("❌ Invalid account expiry: %w", err)
+		}
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("❌ Failed to load configuration: %w", err)
+	}
+	server, err := cfg.GetServer(serverName)
+	if err != nil {
+		return fmt.Errorf("❌ Server '%s' not found", serverName)
+	}
+
+	dates := config.CredentialDates{}
+	if server.Credentials != nil {
+		dates = *server.Credentials
+	}
+	if rotation != nil {
+		dates.KeyRotationDue = rotationDue
+	}
+	if account != nil {
+		dates.AccountExpires = accountExpires
+	}
+	server.Credentials = &dates
+	if dates.IsEmpty() {
+		server.Credentials = nil
+	}
+
+	if err := cfg.UpdateServer(*server); err != nil {
+		return fmt.Errorf("❌ %w", err)
+	}
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("❌ Failed to save configuration: %w", err)
+	}
+
+	fmt.Fprintf(output, "%s\n", color.SuccessMessage("Updated credential dates of %s", server.Name))
+	fmt.Fprintf(output, "%s\n", color.InfoText("  Key rotation due: %s", describeCredentialDate(dates.KeyRotationDue)))
+	fmt.Fprintf(output, "%s\n", color.InfoText("  Account expires:  %s", describeCredentialDate(dates.AccountExpires)))
+	return nil
+}
+
+// describeCredentialDate formats an optional credential date
+func describeCredentialDate(date *time.Time) string {
+	if date == nil {
+		return "-"
+	}
+	return date.Local().Format("2006-01-02")
+}
@@ -0,0 +1,174 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"sshm/internal/color"
+	"sshm/internal/config"
+	"sshm/internal/connection"
+	"sshm/internal/daemon"
+	"sshm/internal/events"
+	sshmssh "sshm/internal/ssh"
+	"sshm/internal/statuscache"
+)
+
+var daemonCmd = &cobra.Command{
+	Use:   "daemon",
+	Short: "Check server status in the background and serve a status page",
+	Long: `Run sshm as a long-lived daemon that checks every server at a fixed interval
+and writes the results to the status cache read by 'sshm statusline'. The
+configuration is reloaded each round, so edits are picked up without a restart.
+
+With --http the daemon also serves a minimal read-only status page with server
+statuses and active tmux sessions, for use as a wallboard. The same data is
+available as JSON at /status.json. Set a token to require it as a bearer token
+or as ?token=... in the URL. Binding to a non-loopback address without a token
+is refused.
+
+Servers may configure webhooks, which are called when a check finds the server
+online after being offline or the other way round.
+
+With ssh_sync enabled ('sshm sync --enable') every round also syncs the servers
+with ~/.ssh/config.
+
+With warm_pool enabled in the configuration, the daemon also keeps connections
+open to servers marked warm and to watched servers, reopening them each round,
+so sessions attach without a fresh handshake.
+
+With --events the daemon writes a status_changed JSON object per line whenever
+a server's status changes, to an inherited file descriptor (fd:3), a Unix or
+TCP socket (unix:PATH, tcp:HOST:PORT) or a file, for tooling and tests.
+
+Defaults are read from the daemon section of the configuration file
+(interval_seconds, http_addr, http_token) and can be overridden with flags.
+The daemon runs in the foreground until interrupted; use systemd, launchd or
+tmux to keep it running.
+
+Examples:
+  sshm daemon
+  sshm daemon --interval 30
+  sshm daemon --http 127.0.0.1:8722
+  sshm daemon --http 0.0.0.0:8722 --token "$SSHM_STATUS_TOKEN"
+  sshm daemon --events unix:/tmp/sshm-events.sock`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		interval, _ := cmd.Flags().GetInt("interval")
+		addr, _ := cmd.Flags().GetString("http")
+		token, _ := cmd.Flags().GetString("token")
+		eventsTarget, _ := cmd.Flags().GetString("events")
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		return runDaemonCommand(ctx, cmd.OutOrStdout(), interval, addr, token, eventsTarget)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(daemonCmd)
+
+	daemonCmd.Flags().Int("interval", 0, "Seconds between status check rounds (default: config or 60)")
+	daemonCmd.Flags().String("http", "", "Serve the read-only status page on this address, e.g. 127.0.0.1:8722")
+	daemonCmd.Flags().String("token", "", "Token required to view the status page")
+	daemonCmd.Flags().String("events", "", "Stream status changes as JSON lines to fd:N, unix:PATH, tcp:HOST:PORT or a file")
+}
+
+func runDaemonCommand(ctx context.Context, output io.Writer, interval int, addr, token, eventsTarget string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("❌ Failed to load configuration: %w", err)
+	}
+	settings := resolveDaemonSettings(cfg.Daemon, interval, addr, token)
+
+	if settings.HTTPAddr != "" && settings.HTTPToken == "" && !daemon.IsLoopback(settings.HTTPAddr) {
+		return fmt.Errorf("❌ Refusing to serve the status page on %s without a token (use --token or a loopback address)", settings.HTTPAddr)
+	}
+
+	cachePath, err := statuscache.DefaultPath()
+	if err != nil {
+		return fmt.Errorf("❌ Failed to determine status cache path: %w", err)
+	}
+
+	d := daemon.New(time.Duration(settings.IntervalSeconds)*time.Second, cachePath)
+	d.OnWebhookError(func(server string, hook config.Webhook, err error) {
+		fmt.Fprintf(output, "%s\n", color.ErrorMessage("Webhook of %s: %s", server, err.Error()))
+	})
+	d.OnSSHSyncError(func(err error) {
+		fmt.Fprintf(output, "%s\n", color.ErrorMessage("SSH config sync: %s", err.Error()))
+	})
+
+	if eventsTarget != "" {
+		emitter, err := events.Open(eventsTarget, "daemon")
+		if err != nil {
+			return fmt.Errorf("❌ Failed to open event stream: %w", err)
+		}
+		defer emitter.Close()
+		d.SetEvents(emitter)
+	}
+
+	// Results are kept for availability in 'sshm stats'; the daemon runs without them
+	if manager, err := connection.NewManager(); err == nil {
+		defer manager.Close()
+		d.SetHistory(manager.GetHistoryManager())
+	}
+
+	// Close the connections kept open for servers with controlmaster
+	defer sshmssh.CloseMultiplexed()
+
+	errs := make(chan error, 1)
+	if settings.HTTPAddr != "" {
+		go func() { errs <- d.Serve(ctx, settings.HTTPAddr, settings.HTTPToken) }()
+		fmt.Fprintf(output, "%s\n", color.InfoMessage("Serving the status page on http://%s", settings.HTTPAddr))
+	}
+
+	fmt.Fprintf(output, "%s\n", color.InfoMessage("Checking servers every %ds (Ctrl+C to stop)", settings.IntervalSeconds))
+	go func() {
+		errs <- d.Run(ctx, func(snapshot daemon.Snapshot) {
+			fmt.Fprintf(output, "%s\n", color.InfoText("%s %s", time.Now().Format("15:04:05"), summarizeSnapshot(snapshot)))
+		})
+	}()
+
+	select {
+	case err := <-errs:
+		if err != nil {
+			return fmt.Errorf("❌ Status page failed: %w", err)
+		}
+	case <-ctx.Done():
+	}
+	fmt.Fprintf(output, "%s\n", color.InfoMessage("Daemon stopped"))
+	return nil
+}
+
+// resolveDaemonSettings applies command line overrides to the configured daemon settings
+func resolveDaemonSettings(settings config.DaemonConfig, interval int, addr, token string) config.DaemonConfig {
+	if interval > 0 {
+		settings.IntervalSeconds = interval
+	}
+	if settings.IntervalSeconds <= 0 {
+		settings.IntervalSeconds = int(daemon.DefaultInterval / time.Second)
+	}
+	if addr != "" {
+		settings.HTTPAddr = addr
+	}
+	if token != "" {
+		settings.HTTPToken = token
+	}
+	return settings
+}
+
+// summarizeSnapshot renders a one line summary of a check round
+func summarizeSnapshot(snapshot daemon.Snapshot) string {
+	online := 0
+	for _, server := range snapshot.Servers {
+		if server.Status == "online" {
+			online++
+		}
+	}
+	return fmt.Sprintf("%d/%d servers online, %d active session(s)", online, len(snapshot.Servers), len(snapshot.Sessions))
+}
@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"sshm/internal/color"
+	"sshm/internal/config"
+	"sshm/internal/tunnel"
+)
+
+var dbCmd = &cobra.Command{
+	Use:   "db",
+	Short: "Launch database clients through SSH tunnels",
+	Long: `Configure databases reachable through servers and launch local clients against them.
+
+sshm opens a tunnel to the database using the server's authentication settings,
+then runs the client command with {host}, {port}, {user} and {database} filled in.
+
+Examples:
+  sshm db set orders-db --engine postgres --name orders --user app
+  sshm db set cache --engine redis --host redis.internal
+  sshm db set reports --engine mysql --client "mycli -h {host} -P {port} -u {user} {database}"
+  sshm db connect orders-db    # Open the tunnel and launch psql`,
+}
+
+var dbSetCmd = &cobra.Command{
+	Use:   "set <server-name>",
+	Short: "Set the database reachable through a server",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		database := config.DatabaseConfig{}
+		database.Engine, _ = cmd.Flags().GetString("engine")
+		database.Host, _ = cmd.Flags().GetString("host")
+		database.Port, _ = cmd.Flags().GetInt("port")
+		database.Name, _ = cmd.Flags().GetString("name")
+		database.User, _ = cmd.Flags().GetString("user")
+		database.LocalPort, _ = cmd.Flags().GetInt("local-port")
+		database.Client, _ = cmd.Flags().GetString("client")
+		return runDBSetCommand(args[0], database, cmd.OutOrStdout())
+	},
+}
+
+var dbConnectCmd = &cobra.Command{
+	Use:   "connect <server-name>",
+	Short: "Open a tunnel to the server's database and launch the client",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runDBConnectCommand(args[0], cmd.OutOrStdout())
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(dbCmd)
+	dbCmd.AddCommand(dbSetCmd)
+	dbCmd.AddCommand(dbConnectCmd)
+
+	dbSetCmd.Flags().String("engine", "", "Database engine: postgres, mysql, mongodb or redis (required)")
+	dbSetCmd.Flags().String("host", "", "Database host as seen from the server (default: localhost)")
+	dbSetCmd.Flags().Int("port", 0, "Database port (default: the engine's standard port)")
+	dbSetCmd.Flags().String("name", "", "Database name")
+	dbSetCmd.Flags().String("user", "", "Database user (default: the server's username)")
+	dbSetCmd.Flags().Int("local-port", 0, "Local tunnel port (default: pick a free port)")
+	dbSetCmd.Flags().String("client", "", "Client command template with {host}, {port}, {user} and {database} placeholders")
+	dbSetCmd.MarkFlagRequired("engine")
+}
+
+func runDBSetCommand(serverName string, database config.DatabaseConfig, output io.Writer) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("❌ Failed to load configuration: %w", err)
+	}
+
+	server, err := cfg.GetServer(serverName)
+	if err != nil {
+		return fmt.Errorf("❌ Server '%s' not found. Use 'sshm list' to see available servers", serverName)
+	}
+
+	if err := database.Validate(); err != nil {
+		return fmt.Errorf("❌ Invalid database: %w", err)
+	}
+
+	server.Database = &database
+	if !server.HasTag(config.DatabaseTag) {
+		server.Tags = append(server.Tags, config.DatabaseTag)
+	}
+	if err := cfg.UpdateServer(*server); err != nil {
+		return fmt.Errorf("❌ Failed to update server: %w", err)
+	}
+
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("❌ Failed to save configuration: %w", err)
+	}
+
+	fmt.Fprintf(output, "%s\n", color.SuccessMessage("Database '%s' configured on server '%s'", database.Engine, server.Name))
+	return nil
+}
+
+func runDBConnectCommand(serverName string, output io.Writer) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("❌ Failed to load configuration: %w", err)
+	}
+
+	server, err := cfg.GetServer(serverName)
+	if err != nil {
+		return fmt.Errorf("❌ Server '%s' not found. Use 'sshm list' to see available servers", serverName)
+	}
+	if !server.IsDatabase() {
+		return fmt.Errorf("❌ Server '%s' has no database. Use 'sshm db set %s --engine <engine>' first", server.Name, server.Name)
+	}
+
+	manager := tunnel.NewManager()
+	defer manager.StopAll()
+
+	fmt.Fprintf(output, "%s\n", color.InfoMessage("Opening tunnel to %s database on %s...", server.Database.Engine, server.Name))
+	tun, command, err := manager.StartDatabase(*server)
+	if err != nil {
+		return fmt.Errorf("❌ Failed to open database tunnel: %w", err)
+	}
+
+	fmt.Fprintf(output, "%s\n", color.InfoMessage("Tunnel ready on localhost:%d, running: %s", tun.LocalPort, command))
+	if err := tunnel.ClientProcess(command).Run(); err != nil {
+		return fmt.Errorf("❌ Database client exited with error: %w", err)
+	}
+	return nil
+}
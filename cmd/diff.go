@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"sshm/internal/color"
+	"sshm/internal/config"
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff <file> [other-file]",
+	Short: "Compare two inventories",
+	Long: `Compare two inventories and list added, removed and changed servers and
+profile membership changes.
+
+With one file the changes from that file to the current configuration are shown,
+e.g. what changed since a backup was taken. With two files the changes from the
+first file to the second are shown. Files may be sshm configs or exports in
+YAML or JSON. Passwords are never printed.
+
+Examples:
+  sshm diff ~/.sshm/config.yaml.bak        # What changed since the backup
+  sshm diff current.yaml exported.json     # What importing the export would change`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runDiffCommand(cmd.OutOrStdout(), args)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+}
+
+func runDiffCommand(output io.Writer, args []string) error {
+	old, err := config.ReadInventory(args[0])
+	if err != nil {
+		return fmt.Errorf("❌ %w", err)
+	}
+
+	var new *config.Config
+	if len(args) == 2 {
+		new, err = config.ReadInventory(args[1])
+		if err != nil {
+			return fmt.Errorf("❌ %w", err)
+		}
+	} else {
+		new, err = config.Load()
+		if err != nil {
+			return fmt.Errorf("❌ Failed to load configuration: %w", err)
+		}
+	}
+
+	diff := config.DiffInventories(old, new)
+	if diff.IsEmpty() {
+		fmt.Fprintf(output, "%s\n", color.SuccessMessage("Inventories are identical"))
+		return nil
+	}
+
+	for _, line := range diff.Lines() {
+		switch line.Kind {
+		case config.DiffAdded:
+			fmt.Fprintf(output, "%s\n", color.SuccessText("+ %s", line.Text))
+		case config.DiffRemoved:
+			fmt.Fprintf(output, "%s\n", color.ErrorText("- %s", line.Text))
+		default:
+			fmt.Fprintf(output, "%s\n", color.WarningText("~ %s", line.Text))
+		}
+	}
+
+	fmt.Fprintf(output, "\n%s\n", color.InfoText("%d server(s) added, %d removed, %d changed; %d profile(s) added, %d removed, %d with membership changes",
+		len(diff.AddedServers), len(diff.RemovedServers), len(diff.ChangedServers),
+		len(diff.AddedProfiles), len(diff.RemovedProfiles), len(diff.MembershipChanges)))
+	return nil
+}
@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"sshm/internal/color"
+	"sshm/internal/config"
+	"sshm/internal/power"
+	"sshm/internal/transfer"
+)
+
+var distributeCmd = &cobra.Command{
+	Use:   "distribute <local-file> <remote-path>",
+	Short: "Push a file to the same path on every server of a profile",
+	Long: `Copy a local file, such as a config file, script or certificate, to the
+same remote path on every server in a profile (or on the listed servers) in
+parallel, then report the outcome per host.
+
+Each copy lands in a temporary file next to the destination and only replaces
+it once its SHA-256 checksum matches the local file, so a failed copy never
+leaves a truncated file behind. The file keeps its local permissions. With
+--post a command runs on each server once its copy is in place, e.g. to reload
+the service reading the file.
+
+The remote user needs write access to the destination directory. Pushing asks
+for confirmation unless turned off with 'sshm settings confirm distribute=never';
+protected servers always require typing the profile (or server) name.
+
+Examples:
+  sshm distribute nginx.conf /etc/nginx/nginx.conf --profile web --post "sudo systemctl reload nginx"
+  sshm distribute ca.pem /usr/local/share/ca-certificates/corp.crt --profile all-hosts --post "sudo update-ca-certificates"
+  sshm distribute deploy.sh /opt/app/deploy.sh --server app-01 --server app-02 --yes`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		profile, _ := cmd.Flags().GetString("profile")
+		serverNames, _ := cmd.Flags().GetStringSlice("server")
+		post, _ := cmd.Flags().GetString("post")
+		parallel, _ := cmd.Flags().GetInt("parallel")
+		yes, _ := cmd.Flags().GetBool("yes")
+
+		if (profile == "") == (len(serverNames) == 0) {
+			return fmt.Errorf("❌ Specify either --profile or --server")
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		distribution := transfer.Distribution{Source: args[0], Destination: args[1], PostCommand: post, Parallel: parallel}
+		return runDistributeCommand(ctx, cmd.OutOrStdout(), os.Stdin, distribution, profile, serverNames, yes)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(distributeCmd)
+
+	distributeCmd.Flags().StringP("profile", "p", "", "Push to every server in the profile")
+	distributeCmd.Flags().StringSlice("server", nil, "Push to this server (repeatable)")
+	distributeCmd.Flags().String("post", "", "Command run on each server after its copy is in place")
+	distributeCmd.Flags().Int("parallel", transfer.DefaultDistributionParallel, "Servers copied to at once")
+	distributeCmd.Flags().BoolP("yes", "y", false, "Skip the confirmation prompt (protected servers still require typing the name)")
+}
+
+func runDistributeCommand(ctx context.Context, output io.Writer, input io.Reader, distribution transfer.Distribution, profileName string, serverNames []string, yes bool) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("❌ Failed to load configuration: %w", err)
+	}
+
+	var servers []config.Server
+	target := profileName
+	if profileName != "" {
+		servers, err = cfg.GetServersByProfile(profileName)
+		if err != nil {
+			return fmt.Errorf("❌ Profile '%s' not found", profileName)
+		}
+		if len(servers) == 0 {
+			return fmt.Errorf("❌ No servers found in profile '%s'", profileName)
+		}
+	} else {
+		for _, name := range serverNames {
+			server, err := cfg.GetServer(name)
+			if err != nil {
+				return fmt.Errorf("❌ Server '%s' not found. Use 'sshm list' to see available servers", name)
+			}
+			servers = append(servers, *server)
+		}
+		target = servers[0].Name
+		if len(servers) > 1 {
+			target = strings.Join(serverNames, ",")
+		}
+	}
+
+	description := fmt.Sprintf("push %s to %s", distribution.Source, distribution.Destination)
+	scanner := bufio.NewScanner(input)
+	if power.RequiresTypedConfirmation(servers) {
+		fmt.Fprintf(output, "%s\n", color.WarningMessage("About to %s on protected target '%s' (%d server(s))", description, target, len(servers)))
+		fmt.Fprintf(output, "Type '%s' to confirm: ", target)
+		if !scanner.Scan() || !power.ConfirmationMatches(target, scanner.Text()) {
+			return fmt.Errorf("❌ Confirmation did not match, nothing was done")
+		}
+	} else if !yes && cfg.UI.ShouldConfirm(config.ConfirmDistribute, false) {
+		fmt.Fprintf(output, "%s (y/n): ", color.WarningMessage("About to %s on '%s' (%d server(s)). Continue?", description, target, len(servers)))
+		if !scanner.Scan() {
+			return fmt.Errorf("❌ Cancelled")
+		}
+		answer := strings.TrimSpace(strings.ToLower(scanner.Text()))
+		if answer != "y" && answer != "yes" {
+			return fmt.Errorf("❌ Cancelled")
+		}
+	}
+
+	checksum, err := transfer.Checksum(distribution.Source)
+	if err != nil {
+		return fmt.Errorf("❌ %w", err)
+	}
+	fmt.Fprintf(output, "%s\n", color.InfoMessage("Pushing %s (sha256 %s) to %d server(s)...", distribution.Source, checksum[:12], len(servers)))
+
+	results, err := distribution.Run(ctx, servers, func(result transfer.DistributionResult) {
+		fmt.Fprintf(output, "%s\n", formatDistributionResult(result))
+	})
+	if err != nil {
+		return fmt.Errorf("❌ %w", err)
+	}
+
+	failures := 0
+	for _, result := range results {
+		if result.Err != nil {
+			failures++
+		}
+	}
+	if failures > 0 {
+		return fmt.Errorf("❌ Distribution failed on %d of %d server(s)", failures, len(servers))
+	}
+	fmt.Fprintf(output, "%s\n", color.SuccessMessage("%s is in place on all %d server(s)", distribution.Destination, len(servers)))
+	return nil
+}
+
+// formatDistributionResult renders the outcome on one server as a line
+func formatDistributionResult(result transfer.DistributionResult) string {
+	elapsed := result.Duration.Round(10 * time.Millisecond)
+	if result.Err != nil {
+		return color.WarningMessage("%s: %s failed after %v: %v", result.Server, result.Stage, elapsed, result.Err)
+	}
+	line := color.SuccessMessage("%s: verified in %v", result.Server, elapsed)
+	if result.Output != "" {
+		line += "\n" + color.InfoText("  %s", strings.ReplaceAll(result.Output, "\n", "\n  "))
+	}
+	return line
+}
@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"sshm/internal/color"
+	"sshm/internal/config"
+	"sshm/internal/diagnostics"
+	"sshm/internal/statuscache"
+)
+
+var dnsCmd = &cobra.Command{
+	Use:   "dns <server-name>",
+	Short: "Show the DNS records of a server's hostname",
+	Long: `Resolve the server's hostname (A, AAAA and CNAME records, and the PTR records
+of its addresses) and show each record with its TTL.
+
+The system resolver (the first nameserver in /etc/resolv.conf) is asked unless
+--resolver names another DNS server.
+
+When the status checker has connected to the server before, the address it used
+is compared with the addresses found, which flags DNS changes that have not
+reached the checks yet.
+
+Examples:
+  sshm dns web1
+  sshm dns web1 --resolver 1.1.1.1
+  sshm dns web1 --resolver 10.0.0.2:5353 --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		resolver, _ := cmd.Flags().GetString("resolver")
+		asJSON, _ := cmd.Flags().GetBool("json")
+		return runDNSCommand(cmd.OutOrStdout(), args[0], resolver, asJSON)
+	},
+}
+
+func init() {
+	dnsCmd.Flags().String("resolver", "", "DNS server to ask instead of the system resolver, e.g. 1.1.1.1 or 10.0.0.2:5353")
+	dnsCmd.Flags().Bool("json", false, "Output the records as JSON")
+	rootCmd.AddCommand(dnsCmd)
+}
+
+func runDNSCommand(output io.Writer, serverName, resolver string, asJSON bool) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("❌ Failed to load configuration: %w", err)
+	}
+
+	server, err := cfg.GetServer(serverName)
+	if err != nil {
+		return fmt.Errorf("❌ Server '%s' not found", serverName)
+	}
+
+	// The status cache holds the address of the last check, by the TUI or the daemon
+	lastAddress := ""
+	if path, err := statuscache.DefaultPath(); err == nil {
+		if cache, err := statuscache.Load(path); err == nil {
+			lastAddress = cache.Servers[server.Name].Address
+		}
+	}
+
+	report, err := diagnostics.InspectDNS(context.Background(), server.GetEffectiveHostname(), resolver, lastAddress)
+	if err != nil {
+		return fmt.Errorf("❌ %w", err)
+	}
+
+	if asJSON {
+		encoder := json.NewEncoder(output)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	}
+
+	displayDNSReport(output, server.Name, report)
+	return nil
+}
+
+// displayDNSReport renders the records and the comparison with the last check as text
+func displayDNSReport(output io.Writer, serverName string, report diagnostics.DNSReport) {
+	fmt.Fprintf(output, "%s\n\n", color.Header(fmt.Sprintf("DNS of %s (%s) from %s", serverName, report.Host, report.Resolver)))
+
+	if len(report.Records) == 0 {
+		fmt.Fprintf(output, "%s\n", color.InfoMessage("No records found"))
+	} else {
+		w := tabwriter.NewWriter(output, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "TYPE\tNAME\tVALUE\tTTL")
+		for _, record := range report.Records {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%ds\n", record.Type, record.Name, record.Value, record.TTL)
+		}
+		w.Flush()
+	}
+	fmt.Fprintln(output)
+
+	switch {
+	case report.LastAddress == "":
+		fmt.Fprintf(output, "%s\n", color.InfoMessage("No status check has connected to %s yet", serverName))
+	case report.Changed:
+		fmt.Fprintf(output, "%s\n", color.WarningMessage("The last status check connected to %s, which is not among the resolved addresses", report.LastAddress))
+	default:
+		fmt.Fprintf(output, "%s\n", color.SuccessMessage("The last status check connected to %s, as resolved", report.LastAddress))
+	}
+}
@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+	"sshm/internal/color"
+	"sshm/internal/config"
+	"sshm/internal/service"
+)
+
+var editCmd = &cobra.Command{
+	Use:   "edit <server-name>",
+	Short: "Change a server's settings without prompts",
+	Long: `Change the settings of a server. Only the fields given as flags change;
+the others keep their values. --name renames the server, and its profiles and
+the servers using it as a jump host follow the new name.
+
+List flags (--alias, --tag, --proxy-jump) replace the whole list; pass an
+empty value, e.g. --tag "", to clear it. Passwords and key passphrases are set
+with 'sshm keyring set'.
+
+Examples:
+  sshm edit web1 --hostname web1.internal --port 2222
+  sshm edit web1 --name web-01 --tag prod,web
+  sshm edit db1 --auth-type key --key-path ~/.ssh/db_ed25519 --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		asJSON, _ := cmd.Flags().GetBool("json")
+		return runEditCommand(cmd, cmd.OutOrStdout(), args[0], asJSON)
+	},
+}
+
+func init() {
+	addEditFlags(editCmd)
+	rootCmd.AddCommand(editCmd)
+}
+
+// addEditFlags registers the settings 'sshm edit' can change on cmd
+func addEditFlags(cmd *cobra.Command) {
+	flags := cmd.Flags()
+	flags.String("name", "", "New name of the server")
+	flags.StringP("hostname", "H", "", "Hostname/IP address of the server")
+	flags.IntP("port", "p", 22, "SSH port")
+	flags.StringP("username", "u", "", "Username for authentication")
+	flags.StringP("auth-type", "a", "", "Authentication method - 'key' or 'password'")
+	flags.StringP("key-path", "k", "", "Path to SSH key file")
+	flags.BoolP("passphrase-protected", "P", false, "Whether the SSH key is passphrase protected")
+	flags.String("resolve-to", "", "IP address to dial instead of resolving the hostname")
+	flags.StringSlice("alias", nil, "Alternative names for the server, replacing the current ones")
+	flags.StringSlice("proxy-jump", nil, "Jump hosts in order, replacing the current ones")
+	flags.StringSlice("tag", nil, "Tags, replacing the current ones")
+	flags.Bool("protected", false, "Require typing the server name to confirm power actions")
+	flags.Bool("require-banner-ack", false, "Require accepting the login banner before connecting")
+	flags.String("owner", "", "Person responsible for the server")
+	flags.String("team", "", "Team that owns the server")
+	flags.String("cost-center", "", "Cost center the server is billed to")
+	flags.String("environment", "", "Environment, e.g. production or staging")
+	flags.String("timezone", "", "IANA time zone of the host, e.g. Asia/Tokyo")
+	flags.String("expires", "", "Archive the server after a duration (4h, 3d) or at a date; 'never' clears it")
+	flags.Bool("json", false, "Print the updated server as JSON")
+}
+
+func runEditCommand(cmd *cobra.Command, output io.Writer, serverName string, asJSON bool) error {
+	svc, err := service.Load()
+	if err != nil {
+		return fmt.Errorf("❌ Failed to load configuration: %w", err)
+	}
+	existing, err := svc.Config().GetServer(serverName)
+	if err != nil {
+		return fmt.Errorf("❌ Server '%s' not found. Use 'sshm list' to see available servers", serverName)
+	}
+
+	server, err := editedServer(cmd, *existing)
+	if err != nil {
+		return err
+	}
+	if err := svc.UpdateServer(existing.Name, server); err != nil {
+		return fmt.Errorf("❌ Failed to update server: %w", err)
+	}
+
+	if asJSON {
+		return writeJSON(output, serverJSON(server))
+	}
+	if server.Name != existing.Name {
+		fmt.Fprintf(output, "%s\n", color.SuccessMessage("Server '%s' renamed to '%s' and updated", existing.Name, server.Name))
+		return nil
+	}
+	fmt.Fprintf(output, "%s\n", color.SuccessMessage("Server '%s' updated", server.Name))
+	return nil
+}
+
+// editedServer applies the flags given on the command line to the server
+func editedServer(cmd *cobra.Command, server config.Server) (config.Server, error) {
+	flags := cmd.Flags()
+	changed := 0
+	setString := func(name string, field *string) {
+		if flags.Changed(name) {
+			*field, _ = flags.GetString(name)
+			changed++
+		}
+	}
+	setBool := func(name string, field *bool) {
+		if flags.Changed(name) {
+			*field, _ = flags.GetBool(name)
+			changed++
+		}
+	}
+	setList := func(name string, field *[]string) {
+		if flags.Changed(name) {
+			*field, _ = flags.GetStringSlice(name)
+			if len(*field) == 0 {
+				*field = nil
+			}
+			changed++
+		}
+	}
+
+	setString("name", &server.Name)
+	setString("hostname", &server.Hostname)
+	setString("username", &server.Username)
+	setString("auth-type", &server.AuthType)
+	setString("key-path", &server.KeyPath)
+	setString("resolve-to", &server.ResolveTo)
+	setString("timezone", &server.Timezone)
+	setString("owner", &server.Metadata.Owner)
+	setString("team", &server.Metadata.Team)
+	setString("cost-center", &server.Metadata.CostCenter)
+	setString("environment", &server.Metadata.Environment)
+	setBool("passphrase-protected", &server.PassphraseProtected)
+	setBool("protected", &server.Protected)
+	setBool("require-banner-ack", &server.RequireBannerAck)
+	setList("alias", &server.Aliases)
+	setList("proxy-jump", &server.ProxyJump)
+	setList("tag", &server.Tags)
+
+	if flags.Changed("port") {
+		server.Port, _ = flags.GetInt("port")
+		changed++
+	}
+	if flags.Changed("expires") {
+		expires, _ := flags.GetString("expires")
+		expiresAt, err := config.ParseExpiry(expires, time.Now())
+		if err != nil {
+			return server, fmt.Errorf("❌ %w", err)
+		}
+		server.ExpiresAt = expiresAt
+		changed++
+	}
+	if server.AuthType == "password" {
+		// Key settings don't apply to password authentication
+		server.KeyPath, server.PassphraseProtected = "", false
+	}
+
+	if changed == 0 {
+		return server, fmt.Errorf("❌ Nothing to change; pass the flags of the settings to edit (see 'sshm edit --help')")
+	}
+	return server, nil
+}
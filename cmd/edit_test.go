@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"sshm/internal/config"
+)
+
+func TestEditCommand(t *testing.T) {
+	testDir := t.TempDir()
+	t.Setenv("SSHM_CONFIG_DIR", testDir)
+	cfg := &config.Config{
+		Servers: []config.Server{
+			{Name: "bastion", Hostname: "bastion.example.com", Port: 22, Username: "ops", AuthType: "key", KeyPath: "~/.ssh/id_ed25519"},
+			{Name: "db1", Hostname: "db1.internal", Port: 22, Username: "ops", AuthType: "key", KeyPath: "~/.ssh/id_ed25519", ProxyJump: []string{"bastion"}, Tags: []string{"db"}},
+		},
+		Profiles: []config.Profile{{Name: "prod", Servers: []string{"bastion", "db1"}}},
+	}
+	if err := cfg.SaveToPath(filepath.Join(testDir, "config.yaml")); err != nil {
+		t.Fatal(err)
+	}
+
+	edit := func(server string, args ...string) (string, error) {
+		cmd := &cobra.Command{}
+		addEditFlags(cmd)
+		if err := cmd.ParseFlags(args); err != nil {
+			t.Fatal(err)
+		}
+		var output strings.Builder
+		err := runEditCommand(cmd, &output, server, true)
+		return output.String(), err
+	}
+
+	output, err := edit("db1", "--port", "2222", "--tag", "")
+	if err != nil {
+		t.Fatalf("runEditCommand() error = %v", err)
+	}
+	var printed config.Server
+	if err := json.Unmarshal([]byte(output), &printed); err != nil {
+		t.Fatalf("Expected JSON output, got %q: %v", output, err)
+	}
+	if printed.Port != 2222 || printed.Hostname != "db1.internal" || len(printed.Tags) != 0 {
+		t.Errorf("Expected only the port and tags to change, got %+v", printed)
+	}
+
+	if _, err := edit("bastion", "--name", "jump"); err != nil {
+		t.Fatalf("runEditCommand() rename error = %v", err)
+	}
+	loaded, err := config.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	db1, err := loaded.GetServer("db1")
+	if err != nil || len(db1.ProxyJump) != 1 || db1.ProxyJump[0] != "jump" {
+		t.Errorf("Expected db1 to jump through the renamed server, got %+v (%v)", db1, err)
+	}
+	if profile, _ := loaded.GetProfile("prod"); profile.Servers[0] != "jump" {
+		t.Errorf("Expected the profile to follow the rename, got %v", profile.Servers)
+	}
+
+	if _, err := edit("db1"); err == nil || !strings.Contains(err.Error(), "Nothing to change") {
+		t.Errorf("Expected an edit without flags to fail, got %v", err)
+	}
+	if _, err := edit("missing", "--port", "22"); err == nil {
+		t.Error("Expected editing an unknown server to fail")
+	}
+}
@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"sshm/internal/color"
+	"sshm/internal/config"
+	"sshm/internal/connection"
+	sshmexec "sshm/internal/exec"
+	"sshm/internal/power"
+)
+
+var execCmd = &cobra.Command{
+	Use:   "exec --profile <profile> <command>",
+	Short: "Run a command on every server of a profile in parallel",
+	Long: `Run a command over SSH on every server in a profile at the same time and
+stream the output of each host, prefixed with its name, as it arrives. A
+summary of the servers where the command succeeded or failed follows; the exit
+status is non-zero when it failed anywhere.
+
+--parallel limits the servers running the command at once and --timeout stops
+it on servers that take too long. Ctrl+C stops every running command. Results
+are kept like those of 'sshm power custom' and can be re-run from the TUI.
+
+Running asks for confirmation unless turned off with
+'sshm settings confirm power_action=never'; protected servers always require
+typing the profile name.
+
+Examples:
+  sshm exec --profile web uptime
+  sshm exec -p web -- df -h /
+  sshm exec -p db --parallel 2 --timeout 30s "sudo systemctl restart postgresql"`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		profile, _ := cmd.Flags().GetString("profile")
+		parallel, _ := cmd.Flags().GetInt("parallel")
+		timeout, _ := cmd.Flags().GetDuration("timeout")
+		yes, _ := cmd.Flags().GetBool("yes")
+		if profile == "" {
+			return fmt.Errorf("❌ Specify the servers with --profile")
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		opts := sshmexec.Options{Concurrency: parallel, Timeout: timeout}
+		return runExecCommand(ctx, cmd.OutOrStdout(), os.Stdin, profile, strings.Join(args, " "), opts, yes)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(execCmd)
+
+	execCmd.Flags().StringP("profile", "p", "", "Run the command on every server in the profile")
+	execCmd.Flags().Int("parallel", sshmexec.DefaultConcurrency, "Servers running the command at once")
+	execCmd.Flags().Duration("timeout", 0, "Stop the command on a server after this long (e.g. 30s, 0 for no limit)")
+	execCmd.Flags().BoolP("yes", "y", false, "Skip the confirmation prompt (protected servers still require typing the name)")
+}
+
+func runExecCommand(ctx context.Context, output io.Writer, input io.Reader, profileName, command string, opts sshmexec.Options, yes bool) error {
+	if strings.TrimSpace(command) == "" {
+		return fmt.Errorf("❌ A command is required")
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("❌ Failed to load configuration: %w", err)
+	}
+	servers, err := cfg.GetServersByProfile(profileName)
+	if err != nil {
+		return fmt.Errorf("❌ Profile '%s' not found", profileName)
+	}
+	if len(servers) == 0 {
+		return fmt.Errorf("❌ No servers found in profile '%s'", profileName)
+	}
+
+	scanner := bufio.NewScanner(input)
+	if power.RequiresTypedConfirmation(servers) {
+		fmt.Fprintf(output, "%s\n", color.WarningMessage("About to run '%s' on protected profile '%s' (%d server(s))", command, profileName, len(servers)))
+		fmt.Fprintf(output, "Type '%s' to confirm: ", profileName)
+		if !scanner.Scan() || !power.ConfirmationMatches(profileName, scanner.Text()) {
+			return fmt.Errorf("❌ Confirmation did not match, nothing was done")
+		}
+	} else if !yes && cfg.UI.ShouldConfirm(config.ConfirmPowerAction, false) {
+		fmt.Fprintf(output, "%s (y/n): ", color.WarningMessage("About to run '%s' on '%s' (%d server(s)). Continue?", command, profileName, len(servers)))
+		if !scanner.Scan() {
+			return fmt.Errorf("❌ Cancelled")
+		}
+		answer := strings.TrimSpace(strings.ToLower(scanner.Text()))
+		if answer != "y" && answer != "yes" {
+			return fmt.Errorf("❌ Cancelled")
+		}
+	}
+
+	manager, err := connection.NewManager()
+	if err != nil {
+		return fmt.Errorf("❌ Failed to initialize connection manager: %w", err)
+	}
+	defer manager.Close()
+
+	width := 0
+	for _, server := range servers {
+		width = max(width, len(server.Name))
+	}
+	opts.OnLine = func(server config.Server, line string) {
+		fmt.Fprintf(output, "%s %s\n", color.InfoText("%-*s |", width, server.Name), line)
+	}
+	opts.OnResult = func(result sshmexec.Result) {
+		if result.Failed() {
+			fmt.Fprintf(output, "%s\n", color.WarningMessage("%s: %v", result.Server.Name, result.Err))
+		}
+	}
+
+	started := time.Now()
+	results, err := manager.RunCommand(ctx, servers, command, opts)
+	if err != nil {
+		return fmt.Errorf("❌ %w", err)
+	}
+	printExecSummary(output, results, time.Since(started))
+
+	summary := sshmexec.Summarize(results)
+	if summary.Failed+summary.Cancelled > 0 {
+		return fmt.Errorf("❌ '%s' did not succeed on %d of %d server(s)", command, summary.Failed+summary.Cancelled, len(servers))
+	}
+	return nil
+}
+
+// printExecSummary lists the servers where the command failed or succeeded
+func printExecSummary(output io.Writer, results []sshmexec.Result, elapsed time.Duration) {
+	fmt.Fprintf(output, "\n%s\n", color.Header("Summary"))
+	var succeeded []string
+	for _, result := range results {
+		switch {
+		case !result.Failed():
+			succeeded = append(succeeded, result.Server.Name)
+		case result.Status == sshmexec.StatusCancelled:
+			fmt.Fprintf(output, "  %s\n", color.WarningMessage("%s: cancelled", result.Server.Name))
+		default:
+			fmt.Fprintf(output, "  %s\n", color.ErrorMessage("%s: %v", result.Server.Name, result.Err))
+		}
+	}
+	if len(succeeded) > 0 {
+		fmt.Fprintf(output, "  %s\n", color.SuccessMessage("%s", strings.Join(succeeded, ", ")))
+	}
+	fmt.Fprintf(output, "%s\n", color.InfoText("%s in %v", sshmexec.Summarize(results), elapsed.Round(10*time.Millisecond)))
+}
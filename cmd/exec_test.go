@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"sshm/internal/config"
+	sshmexec "sshm/internal/exec"
+)
+
+func TestExecCommandConfirmation(t *testing.T) {
+	testDir := t.TempDir()
+	t.Setenv("SSHM_CONFIG_DIR", testDir)
+
+	cfg := &config.Config{
+		Servers: []config.Server{
+			{Name: "web1", Hostname: "10.0.0.1", Port: 22, Username: "ops", AuthType: "key", KeyPath: "~/.ssh/id_ed25519"},
+			{Name: "db1", Hostname: "10.0.0.2", Port: 22, Username: "ops", AuthType: "key", KeyPath: "~/.ssh/id_ed25519", Protected: true},
+		},
+		Profiles: []config.Profile{
+			{Name: "web", Servers: []string{"web1"}},
+			{Name: "db", Servers: []string{"db1"}},
+			{Name: "empty"},
+		},
+	}
+	if err := cfg.SaveToPath(filepath.Join(testDir, "config.yaml")); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	var output strings.Builder
+	if err := runExecCommand(ctx, &output, strings.NewReader(""), "missing", "uptime", sshmexec.Options{}, true); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("Expected an unknown profile to be reported, got %v", err)
+	}
+	if err := runExecCommand(ctx, &output, strings.NewReader(""), "empty", "uptime", sshmexec.Options{}, true); err == nil || !strings.Contains(err.Error(), "No servers") {
+		t.Errorf("Expected an empty profile to be reported, got %v", err)
+	}
+	if err := runExecCommand(ctx, &output, strings.NewReader(""), "web", "  ", sshmexec.Options{}, true); err == nil {
+		t.Error("Expected an empty command to be rejected")
+	}
+
+	output.Reset()
+	if err := runExecCommand(ctx, &output, strings.NewReader("n\n"), "web", "uptime", sshmexec.Options{}, false); err == nil || !strings.Contains(err.Error(), "Cancelled") {
+		t.Errorf("Expected declining to cancel the run, got %v", err)
+	}
+	if !strings.Contains(output.String(), "About to run 'uptime' on 'web' (1 server(s))") {
+		t.Errorf("Expected a confirmation prompt, got:\n%s", output.String())
+	}
+
+	// Protected servers need the profile name typed even with --yes
+	output.Reset()
+	if err := runExecCommand(ctx, &output, strings.NewReader("yes\n"), "db", "uptime", sshmexec.Options{}, true); err == nil || !strings.Contains(err.Error(), "did not match") {
+		t.Errorf("Expected the protected profile to require its name, got %v", err)
+	}
+}
@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+	"sshm/internal/color"
+	"sshm/internal/config"
+)
+
+var expireCmd = &cobra.Command{
+	Use:   "expire <server-name> <duration|date|never>",
+	Short: "Set or clear the expiry of a scratch server",
+	Long: `Set when a server expires. Expired servers are archived automatically and
+can be brought back with 'sshm archive restore'.
+
+The expiry is a duration from now (4h, 3d), a date (2026-01-31), a date and
+time (2026-01-31 18:00), or "never" to keep the server permanently.
+
+Examples:
+  sshm expire scratch-1 8h
+  sshm expire scratch-1 2026-01-31
+  sshm expire scratch-1 never`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runExpireCommand(cmd.OutOrStdout(), args[0], args[1])
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(expireCmd)
+}
+
+func runExpireCommand(output io.Writer, serverName, expires string) error {
+	expiresAt, err := config.ParseExpiry(expires, time.Now())
+	if err != nil {
+		return fmt.Errorf("❌ %w", err)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("❌ Failed to load configuration: %w", err)
+	}
+
+	server, err := cfg.GetServer(serverName)
+	if err != nil {
+		return fmt.Errorf("❌ Server '%s' not found", serverName)
+	}
+
+	server.ExpiresAt = expiresAt
+	if err := cfg.UpdateServer(*server); err != nil {
+		return fmt.Errorf("❌ %w", err)
+	}
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("❌ Failed to save configuration: %w", err)
+	}
+
+	if expiresAt == nil {
+		fmt.Fprintf(output, "%s\n", color.SuccessMessage("%s no longer expires", server.Name))
+		return nil
+	}
+	fmt.Fprintf(output, "%s\n", color.SuccessMessage("%s expires %s (in %s)", server.Name,
+		expiresAt.Local().Format("2006-01-02 15:04"), formatTimeUntil(time.Until(*expiresAt))))
+	return nil
+}
+
+// formatTimeUntil renders a remaining duration compactly, e.g. "3d4h" or "45m"
+func formatTimeUntil(d time.Duration) string {
+	if d < time.Minute {
+		return "<1m"
+	}
+	days := int(d.Hours()) / 24
+	hours := int(d.Hours()) % 24
+	minutes := int(d.Minutes()) % 60
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%dd%dh", days, hours)
+	case hours > 0:
+		return fmt.Sprintf("%dh%dm", hours, minutes)
+	default:
+		return fmt.Sprintf("%dm", minutes)
+	}
+}
@@ -1,21 +1,25 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
 
 	"github.com/spf13/cobra"
-	"gopkg.in/yaml.v3"
 	"sshm/internal/color"
 	"sshm/internal/config"
+	"sshm/internal/exporter"
+	"sshm/internal/signing"
 )
 
 var (
-	exportFormat  string
-	exportProfile string
+	exportFormat   string
+	exportProfile  string
+	exportSign     bool
+	exportSignKey  string
+	exportSanitize bool
 )
 
 var exportCmd = &cobra.Command{
@@ -29,6 +33,7 @@ using the --profile flag.
 Supported formats:
   • YAML (default)
   • JSON
+  • Any format registered as an exporter plugin
 
 The file format is automatically detected based on the file extension, but can be
 explicitly specified using the --format flag.
@@ -37,18 +42,44 @@ Examples:
   sshm export servers.yaml                    # Export all to YAML
   sshm export servers.json                    # Export all to JSON
   sshm export --format json servers.txt       # Force JSON format
-  sshm export --profile production prod.yaml  # Export specific profile`,
+  sshm export --profile production prod.yaml  # Export specific profile
+  sshm export --sign servers.yaml             # Also write a GPG signature (servers.yaml.asc)
+  sshm export --sanitize inventory.yaml       # Only names, hosts and profiles
+  sshm export --json servers.yaml             # Print what was exported as JSON
+
+Sanitized exports leave out usernames, ports, key paths, passwords and every
+other access detail, so inventories can be shared with contractors or attached
+to tickets. The fields they keep are configured in the sanitize section of the
+configuration file:
+
+  sanitize:
+    server_fields: [hostname, tags, metadata]
+    profile_fields: [servers, description]
+
+Signed exports are verified automatically by 'sshm import' when the .asc file
+is next to the exported file.`,
 	Args: cobra.ExactArgs(1),
 	RunE: runExport,
 }
 
 func init() {
-	exportCmd.Flags().StringVarP(&exportFormat, "format", "f", "", "Output format (yaml, json) - auto-detected if not specified")
+	exportCmd.Flags().StringVarP(&exportFormat, "format", "f", "", fmt.Sprintf("Output format (%s) - auto-detected if not specified", strings.Join(exporter.Names(), ", ")))
 	exportCmd.Flags().StringVarP(&exportProfile, "profile", "p", "", "Export servers from specified profile only")
+	exportCmd.Flags().BoolVar(&exportSign, "sign", false, "Write a detached GPG signature next to the export")
+	exportCmd.Flags().StringVar(&exportSignKey, "sign-key", "", "GPG key ID used with --sign (default: gpg's default key)")
+	exportCmd.Flags().BoolVar(&exportSanitize, "sanitize", false, "Keep only the fields of the sanitize rules (default: names, hosts and profiles)")
+	exportCmd.Flags().Bool("json", false, "Print what was exported as JSON")
 }
 
 func runExport(cmd *cobra.Command, args []string) error {
 	outputPath := args[0]
+	asJSON, _ := cmd.Flags().GetBool("json")
+	
+	// With --json only the result is printed
+	output := cmd.OutOrStdout()
+	if asJSON {
+		output = io.Discard
+	}
 	
 	// Load current configuration
 	cfg, err := config.Load()
@@ -63,8 +94,9 @@ func runExport(cmd *cobra.Command, args []string) error {
 	}
 	
 	// Validate format
-	if format != "yaml" && format != "json" {
-		return fmt.Errorf("unsupported export format: %s (supported: yaml, json)", format)
+	exp, ok := exporter.Lookup(format)
+	if !ok {
+		return fmt.Errorf("unsupported export format: %s (supported: %s)", format, strings.Join(exporter.Names(), ", "))
 	}
 	
 	// Prepare export configuration
@@ -88,7 +120,7 @@ func runExport(cmd *cobra.Command, args []string) error {
 			Profiles: []config.Profile{*profile},
 		}
 		
-		fmt.Printf("%s\n", color.InfoMessage("Exporting profile '%s' with %d servers", exportProfile, len(servers)))
+		fmt.Fprintf(output, "%s\n", color.InfoMessage("Exporting profile '%s' with %d servers", exportProfile, len(servers)))
 		
 	} else {
 		// Export all servers and profiles
@@ -97,7 +129,15 @@ func runExport(cmd *cobra.Command, args []string) error {
 			Profiles: cfg.GetProfiles(),
 		}
 		
-		fmt.Printf("%s\n", color.InfoMessage("Exporting %d servers and %d profiles", len(exportConfig.Servers), len(exportConfig.Profiles)))
+		fmt.Fprintf(output, "%s\n", color.InfoMessage("Exporting %d servers and %d profiles", len(exportConfig.Servers), len(exportConfig.Profiles)))
+	}
+	
+	if exportSanitize {
+		exportConfig.Servers, exportConfig.Profiles, err = cfg.Sanitize.Sanitized(exportConfig.Servers, exportConfig.Profiles)
+		if err != nil {
+			return fmt.Errorf("failed to sanitize export: %w", err)
+		}
+		fmt.Fprintf(output, "%s\n", color.InfoMessage("Sanitizing: access details are left out"))
 	}
 	
 	// Create output directory if it doesn't exist
@@ -106,20 +146,10 @@ func runExport(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to create output directory: %w", err)
 	}
 	
-	// Marshal configuration based on format
-	var data []byte
-	switch format {
-	case "yaml":
-		data, err = yaml.Marshal(exportConfig)
-		if err != nil {
-			return fmt.Errorf("failed to marshal YAML: %w", err)
-		}
-		
-	case "json":
-		data, err = json.MarshalIndent(exportConfig, "", "  ")
-		if err != nil {
-			return fmt.Errorf("failed to marshal JSON: %w", err)
-		}
+	// Marshal configuration with the format's exporter
+	data, err := exp.Marshal(exportConfig)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", strings.ToUpper(format), err)
 	}
 	
 	// Write to file
@@ -127,22 +157,39 @@ func runExport(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to write export file: %w", err)
 	}
 	
-	fmt.Printf("%s\n", color.SuccessMessage("Configuration exported to %s (%s format)", outputPath, format))
+	fmt.Fprintf(output, "%s\n", color.SuccessMessage("Configuration exported to %s (%s format)", outputPath, format))
+	
+	sigPath := ""
+	if exportSign || exportSignKey != "" {
+		sigPath, err = signing.Sign(outputPath, exportSignKey)
+		if err != nil {
+			return fmt.Errorf("failed to sign export: %w", err)
+		}
+		fmt.Fprintf(output, "%s\n", color.SuccessMessage("Signature written to %s", sigPath))
+	}
 	
+	if asJSON {
+		return writeJSON(cmd.OutOrStdout(), exportJSON{
+			File:      outputPath,
+			Format:    format,
+			Servers:   len(exportConfig.Servers),
+			Profiles:  len(exportConfig.Profiles),
+			Signature: sigPath,
+		})
+	}
 	return nil
 }
 
+// exportJSON is the result of 'sshm export --json'
+type exportJSON struct {
+	File      string `json:"file"`
+	Format    string `json:"format"`
+	Servers   int    `json:"servers"`
+	Profiles  int    `json:"profiles"`
+	Signature string `json:"signature,omitempty"`
+}
+
 // detectExportFormat determines the export format based on file extension
 func detectExportFormat(filePath string) string {
-	ext := strings.ToLower(filepath.Ext(filePath))
-	
-	switch ext {
-	case ".json":
-		return "json"
-	case ".yaml", ".yml":
-		return "yaml"
-	default:
-		// Default to YAML
-		return "yaml"
-	}
+	return exporter.DetectFormat(filePath)
 }
\ No newline at end of file
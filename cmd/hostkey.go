@@ -0,0 +1,353 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/user"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"sshm/internal/color"
+	"sshm/internal/config"
+	"sshm/internal/connection"
+	"sshm/internal/history"
+	"sshm/internal/hostkey"
+)
+
+var hostkeyCmd = &cobra.Command{
+	Use:   "hostkey",
+	Short: "Check and rotate server host keys",
+	Long: `Compare the host keys servers present with ~/.ssh/known_hosts and the
+fingerprints pinned in the configuration (host_keys), and update both in bulk
+after a planned host rekeying. Every rotation is recorded in the history
+database; 'sshm hostkey history' lists the records.
+
+The keys a server presents are also recorded at every connection. When they
+differ from those seen before, sshm connect and the TUI warn, and protected
+servers only connect after the new keys are confirmed. Rotating trusts the
+rotated keys. 'sshm hostkey history' lists the changes seen at connections.
+
+Examples:
+  sshm hostkey check --profile production
+  sshm hostkey rotate web1 web2
+  sshm hostkey rotate --profile production --yes
+  sshm hostkey history web1`,
+}
+
+var hostkeyCheckCmd = &cobra.Command{
+	Use:   "check [server-name...]",
+	Short: "Compare presented host keys with known_hosts and pinned fingerprints",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		profile, _ := cmd.Flags().GetString("profile")
+		knownHosts, _ := cmd.Flags().GetString("known-hosts")
+		return runHostkeyCheckCommand(cmd.OutOrStdout(), args, profile, knownHosts)
+	},
+}
+
+var hostkeyRotateCmd = &cobra.Command{
+	Use:   "rotate [server-name...]",
+	Short: "Pin the presented host keys and replace them in known_hosts",
+	Long: `Fetch the host keys the servers present and, for every server whose pins or
+known_hosts entries differ or that has nothing pinned yet, pin the presented
+fingerprints and replace its known_hosts entries (the previous file is kept as
+known_hosts.old). The changes are listed and confirmed before anything is
+written. Only run this after a planned rekeying: a changed key you didn't
+expect may mean someone is intercepting the connection.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		profile, _ := cmd.Flags().GetString("profile")
+		knownHosts, _ := cmd.Flags().GetString("known-hosts")
+		pinsOnly, _ := cmd.Flags().GetBool("pins-only")
+		yes, _ := cmd.Flags().GetBool("yes")
+		return runHostkeyRotateCommand(cmd.OutOrStdout(), os.Stdin, args, profile, knownHosts, pinsOnly, yes)
+	},
+}
+
+var hostkeyHistoryCmd = &cobra.Command{
+	Use:   "history [server-name]",
+	Short: "List recorded host key rotations and keys changed between connections",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		limit, _ := cmd.Flags().GetInt("limit")
+		serverName := ""
+		if len(args) == 1 {
+			serverName = args[0]
+		}
+		return runHostkeyHistoryCommand(cmd.OutOrStdout(), serverName, limit)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(hostkeyCmd)
+	hostkeyCmd.AddCommand(hostkeyCheckCmd)
+	hostkeyCmd.AddCommand(hostkeyRotateCmd)
+	hostkeyCmd.AddCommand(hostkeyHistoryCmd)
+
+	for _, cmd := range []*cobra.Command{hostkeyCheckCmd, hostkeyRotateCmd} {
+		cmd.Flags().StringP("profile", "p", "", "Use the servers of this profile")
+		cmd.Flags().String("known-hosts", "", "known_hosts file to compare with (default: ~/.ssh/known_hosts)")
+	}
+	hostkeyRotateCmd.Flags().Bool("pins-only", false, "Update the pinned fingerprints but leave known_hosts alone")
+	hostkeyRotateCmd.Flags().BoolP("yes", "y", false, "Skip the confirmation prompt")
+	hostkeyHistoryCmd.Flags().Int("limit", 20, "Maximum number of records to show")
+}
+
+// hostkeyServers resolves the named servers, or a profile's, or all servers
+func hostkeyServers(cfg *config.Config, names []string, profileName string) ([]config.Server, error) {
+	if profileName != "" && len(names) > 0 {
+		return nil, fmt.Errorf("❌ Name servers or use --profile, not both")
+	}
+	if profileName != "" {
+		servers, err := cfg.GetServersByProfile(profileName)
+		if err != nil {
+			return nil, fmt.Errorf("❌ Profile '%s' not found", profileName)
+		}
+		return servers, nil
+	}
+	if len(names) == 0 {
+		return cfg.GetServers(), nil
+	}
+
+	servers := make([]config.Server, 0, len(names))
+	for _, name := range names {
+		server, err := cfg.GetServer(name)
+		if err != nil {
+			return nil, fmt.Errorf("❌ Server '%s' not found. Use 'sshm list' to see available servers", name)
+		}
+		servers = append(servers, *server)
+	}
+	return servers, nil
+}
+
+// resolveKnownHostsPath returns the known_hosts file to use
+func resolveKnownHostsPath(path string) (string, error) {
+	if path != "" {
+		return config.ExpandPath(path)
+	}
+	path, err := hostkey.DefaultKnownHostsPath()
+	if err != nil {
+		return "", fmt.Errorf("❌ %w", err)
+	}
+	return path, nil
+}
+
+// checkHostKeys loads the configuration and checks the selected servers' host keys
+func checkHostKeys(output io.Writer, names []string, profileName, knownHostsPath string) (*config.Config, []hostkey.Report, string, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("❌ Failed to load configuration: %w", err)
+	}
+	servers, err := hostkeyServers(cfg, names, profileName)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	if len(servers) == 0 {
+		return nil, nil, "", fmt.Errorf("❌ No servers to check")
+	}
+	path, err := resolveKnownHostsPath(knownHostsPath)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	fmt.Fprintf(output, "%s\n", color.InfoMessage("Fetching host keys of %d server(s)...", len(servers)))
+	return cfg, hostkey.CheckAll(servers, path), path, nil
+}
+
+func runHostkeyCheckCommand(output io.Writer, names []string, profileName, knownHostsPath string) error {
+	_, reports, _, err := checkHostKeys(output, names, profileName, knownHostsPath)
+	if err != nil {
+		return err
+	}
+	printHostkeyReports(output, reports)
+
+	changed := 0
+	for _, report := range reports {
+		if report.Status == hostkey.StatusChanged {
+			changed++
+		}
+	}
+	if changed > 0 {
+		fmt.Fprintf(output, "%s\n", color.WarningMessage("%d server(s) present keys that differ from known_hosts or their pins. After a planned rekeying, run 'sshm hostkey rotate'.", changed))
+	}
+	return nil
+}
+
+// printHostkeyReports prints one row per server
+func printHostkeyReports(output io.Writer, reports []hostkey.Report) {
+	w := tabwriter.NewWriter(output, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SERVER\tSTATUS\tKNOWN_HOSTS\tPINS\tPRESENTED")
+	for _, report := range reports {
+		if report.Status == hostkey.StatusError {
+			fmt.Fprintf(w, "%s\t%s\t-\t-\t%s\n", report.Server, report.Status, report.Error)
+			continue
+		}
+		var presented []string
+		for _, key := range report.Scanned {
+			presented = append(presented, fmt.Sprintf("%s %s", key.Type, key.Fingerprint))
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", report.Server, report.Status, report.KnownHosts, report.Pins, strings.Join(presented, ", "))
+	}
+	w.Flush()
+}
+
+func runHostkeyRotateCommand(output io.Writer, input io.Reader, names []string, profileName, knownHostsPath string, pinsOnly, yes bool) error {
+	cfg, reports, path, err := checkHostKeys(output, names, profileName, knownHostsPath)
+	if err != nil {
+		return err
+	}
+	printHostkeyReports(output, reports)
+
+	var rotations []hostkey.Report
+	for _, report := range reports {
+		if report.NeedsRotation() {
+			rotations = append(rotations, report)
+		}
+	}
+	if len(rotations) == 0 {
+		fmt.Fprintf(output, "%s\n", color.SuccessMessage("Pins and known_hosts already match the presented keys"))
+		return nil
+	}
+
+	fmt.Fprintf(output, "\n%s\n", color.Header("Planned changes"))
+	for _, report := range rotations {
+		fmt.Fprintf(output, "%s\n", color.InfoText("  %s: pins %s → %s", report.Server, describeFingerprints(report.Pinned), strings.Join(report.Fingerprints(), ", ")))
+		if !pinsOnly && report.KnownHosts != hostkey.MatchOK {
+			fmt.Fprintf(output, "%s\n", color.InfoText("  %s: replace known_hosts entries for %s", report.Server, report.Host))
+		}
+	}
+
+	if !yes {
+		fmt.Fprintf(output, "%s", color.WarningMessage("Apply the changes to %d server(s)? (y/N): ", len(rotations)))
+		scanner := bufio.NewScanner(input)
+		scanner.Scan()
+		if answer := strings.ToLower(strings.TrimSpace(scanner.Text())); answer != "y" && answer != "yes" {
+			fmt.Fprintf(output, "%s\n", color.InfoMessage("Rotation cancelled, nothing was changed"))
+			return nil
+		}
+	}
+
+	var changes []history.HostKeyChange
+	for _, report := range rotations {
+		server, err := cfg.GetServer(report.Server)
+		if err != nil {
+			return fmt.Errorf("❌ Server '%s' not found", report.Server)
+		}
+		change := history.HostKeyChange{
+			ServerName:      server.Name,
+			Host:            report.Host,
+			User:            localUsername(),
+			OldFingerprints: server.HostKeys,
+			NewFingerprints: report.Fingerprints(),
+		}
+
+		if !pinsOnly && report.KnownHosts != hostkey.MatchOK {
+			if err := hostkey.UpdateKnownHosts(path, report); err != nil {
+				fmt.Fprintf(output, "%s\n", color.WarningMessage("%s: %v", server.Name, err))
+			} else {
+				change.KnownHostsUpdated = true
+			}
+		}
+
+		updated := *server
+		updated.HostKeys = report.Fingerprints()
+		if err := cfg.UpdateServer(updated); err != nil {
+			return fmt.Errorf("❌ Failed to update server '%s': %w", server.Name, err)
+		}
+		changes = append(changes, change)
+	}
+
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("❌ Failed to save configuration: %w", err)
+	}
+	recordHostKeyChanges(output, changes, rotations)
+
+	fmt.Fprintf(output, "%s\n", color.SuccessMessage("Rotated host keys of %d server(s)", len(changes)))
+	return nil
+}
+
+// recordHostKeyChanges writes the audit records of a rotation and trusts the
+// rotated keys at later connections; the rotation itself is already saved, so
+// failures are only reported
+func recordHostKeyChanges(output io.Writer, changes []history.HostKeyChange, rotations []hostkey.Report) {
+	manager, err := connection.NewManager()
+	if err != nil {
+		fmt.Fprintf(output, "%s\n", color.WarningMessage("Rotation not recorded in history: %v", err))
+		return
+	}
+	defer manager.Close()
+
+	for _, change := range changes {
+		if _, err := manager.GetHistoryManager().RecordHostKeyChange(change); err != nil {
+			fmt.Fprintf(output, "%s\n", color.WarningMessage("Rotation of %s not recorded in history: %v", change.ServerName, err))
+		}
+	}
+	for _, report := range rotations {
+		if err := hostkey.AcceptIdentity(manager.GetHistoryManager(), report.Server, report.Host, report.Scanned); err != nil {
+			fmt.Fprintf(output, "%s\n", color.WarningMessage("Keys of %s not trusted for later connections: %v", report.Server, err))
+		}
+	}
+}
+
+func runHostkeyHistoryCommand(output io.Writer, serverName string, limit int) error {
+	manager, err := connection.NewManager()
+	if err != nil {
+		return fmt.Errorf("❌ Failed to initialize connection manager: %w", err)
+	}
+	defer manager.Close()
+
+	changes, err := manager.GetHistoryManager().GetHostKeyChanges(serverName, limit)
+	if err != nil {
+		return fmt.Errorf("❌ %w", err)
+	}
+	identityChanges, err := manager.GetHistoryManager().GetHostIdentityChanges(serverName, limit)
+	if err != nil {
+		return fmt.Errorf("❌ %w", err)
+	}
+
+	if len(changes) == 0 {
+		fmt.Fprintf(output, "%s\n", color.InfoMessage("No host key rotations recorded"))
+	} else {
+		w := tabwriter.NewWriter(output, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "WHEN\tSERVER\tUSER\tOLD\tNEW\tKNOWN_HOSTS")
+		for _, change := range changes {
+			knownHosts := "unchanged"
+			if change.KnownHostsUpdated {
+				knownHosts = "updated"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", change.ChangedAt.Local().Format("2006-01-02 15:04"), change.ServerName, change.User,
+				describeFingerprints(change.OldFingerprints), strings.Join(change.NewFingerprints, ", "), knownHosts)
+		}
+		w.Flush()
+	}
+
+	// Keys that changed between connections without a rotation
+	if len(identityChanges) > 0 {
+		fmt.Fprintf(output, "\n%s\n", color.Header("Changed host keys seen at connections"))
+		w := tabwriter.NewWriter(output, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "WHEN\tSERVER\tHOST\tPRESENTED")
+		for _, observation := range identityChanges {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", observation.ObservedAt.Local().Format("2006-01-02 15:04"), observation.ServerName,
+				observation.Host, strings.Join(observation.Keys, ", "))
+		}
+		w.Flush()
+	}
+	return nil
+}
+
+// describeFingerprints lists fingerprints, or "none"
+func describeFingerprints(fingerprints []string) string {
+	if len(fingerprints) == 0 {
+		return "none"
+	}
+	return strings.Join(fingerprints, ", ")
+}
+
+// localUsername returns who is running sshm, for audit records
+func localUsername() string {
+	if current, err := user.Current(); err == nil {
+		return current.Username
+	}
+	return os.Getenv("USER")
+}
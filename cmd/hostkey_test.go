@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+	"sshm/internal/config"
+	"sshm/internal/connection"
+)
+
+func TestHostkeyRotate(t *testing.T) {
+	if _, err := exec.LookPath("ssh-keygen"); err != nil {
+		t.Skip("ssh-keygen not available")
+	}
+
+	public, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := ssh.NewPublicKey(public)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fingerprint := ssh.FingerprintSHA256(key)
+	keyLine := strings.TrimSpace(string(ssh.MarshalAuthorizedKey(key)))
+
+	// A fake ssh-keyscan presents the new key
+	binDir := t.TempDir()
+	script := "#!/bin/sh\necho 'web1.example.com " + keyLine + "'\n"
+	if err := os.WriteFile(filepath.Join(binDir, "ssh-keyscan"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("HOME", t.TempDir())
+	configDir := t.TempDir()
+	t.Setenv("SSHM_CONFIG_DIR", configDir)
+
+	cfg := &config.Config{Servers: []config.Server{{
+		Name: "web1", Hostname: "web1.example.com", Port: 22, Username: "ops", AuthType: "key", KeyPath: "~/.ssh/id_ed25519",
+		HostKeys: []string{"SHA256:oldoldoldoldoldoldoldoldoldoldoldoldoldoldo"},
+	}}}
+	if err := cfg.SaveToPath(filepath.Join(configDir, "config.yaml")); err != nil {
+		t.Fatal(err)
+	}
+	knownHosts := filepath.Join(t.TempDir(), "known_hosts")
+
+	var output bytes.Buffer
+	if err := runHostkeyRotateCommand(&output, strings.NewReader("n\n"), nil, "", knownHosts, false, false); err != nil {
+		t.Fatalf("rotate error = %v", err)
+	}
+	if !strings.Contains(output.String(), "changed") || !strings.Contains(output.String(), fingerprint) {
+		t.Errorf("Expected the change to be listed, got: %s", output.String())
+	}
+	if _, err := os.Stat(knownHosts); !os.IsNotExist(err) {
+		t.Errorf("Expected nothing written when declined")
+	}
+
+	output.Reset()
+	if err := runHostkeyRotateCommand(&output, nil, []string{"web1"}, "", knownHosts, false, true); err != nil {
+		t.Fatalf("rotate error = %v", err)
+	}
+
+	updated, err := config.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	server, _ := updated.GetServer("web1")
+	if len(server.HostKeys) != 1 || server.HostKeys[0] != fingerprint {
+		t.Errorf("Expected the presented key to be pinned, got %v", server.HostKeys)
+	}
+	data, err := os.ReadFile(knownHosts)
+	if err != nil || !strings.Contains(string(data), "web1.example.com "+keyLine) {
+		t.Errorf("Expected known_hosts to contain the new key, got %q, %v", data, err)
+	}
+
+	manager, err := connection.NewManager()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer manager.Close()
+	changes, err := manager.GetHistoryManager().GetHostKeyChanges("web1", 0)
+	if err != nil || len(changes) != 1 || !changes[0].KnownHostsUpdated || changes[0].OldFingerprints[0] != "SHA256:oldoldoldoldoldoldoldoldoldoldoldoldoldoldo" {
+		t.Errorf("Expected an audit record, got %+v, %v", changes, err)
+	}
+}
@@ -1,21 +1,28 @@
 package cmd
 
 import (
-	"encoding/json"
+	"bufio"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
 
 	"github.com/spf13/cobra"
-	"gopkg.in/yaml.v3"
 	"sshm/internal/color"
 	"sshm/internal/config"
+	"sshm/internal/importer"
+	"sshm/internal/service"
+	"sshm/internal/signing"
 )
 
 var (
-	importType    string
-	importProfile string
+	importType           string
+	importProfile        string
+	importSignature      string
+	importInferProfiles  bool
+	importYes            bool
 )
 
 var importCmd = &cobra.Command{
@@ -27,32 +34,69 @@ Supported formats:
   • SSH config files (~/.ssh/config format)
   • YAML configuration files
   • JSON configuration files
+  • Any format registered as an importer plugin
 
-The file type is automatically detected based on the file extension, but can be
-explicitly specified using the --type flag.
+The file type is automatically detected from the file's content, or its name when
+the content is not recognized, but can be explicitly specified using the --type flag.
 
 Examples:
   sshm import ~/.ssh/config              # Import from SSH config
   sshm import servers.yaml               # Import from YAML file
   sshm import --type json servers.txt    # Force JSON parsing
-  sshm import --profile imported servers.yaml  # Import to specific profile`,
+  sshm import --profile imported servers.yaml  # Import to specific profile
+  sshm import --signature require shared.yaml  # Only import files with a valid GPG signature
+  sshm import --json --yes ~/.ssh/config       # Import without prompts, printing the result as JSON
+
+SSH configs are read with the files they Include. Unless --profile is given,
+profiles are proposed from the included file each host is in, wildcard Host
+patterns (*.prod.example.com -> prod) and shared domains, and created once
+confirmed (--yes skips the question, --infer-profiles=false the proposal).
+
+When a detached GPG signature (<file>.asc, written by 'sshm export --sign') is
+next to the file it is verified before importing. --signature controls this:
+  • auto (default): verify when signed, reject invalid signatures
+  • warn: verify when signed, only warn about invalid signatures
+  • require: reject unsigned files and invalid signatures
+  • off: never verify`,
 	Args: cobra.ExactArgs(1),
 	RunE: runImport,
 }
 
 func init() {
-	importCmd.Flags().StringVarP(&importType, "type", "t", "", "File type (ssh, yaml, json) - auto-detected if not specified")
+	importCmd.Flags().StringVarP(&importType, "type", "t", "", fmt.Sprintf("File type (%s) - auto-detected if not specified", strings.Join(importer.Names(), ", ")))
 	importCmd.Flags().StringVarP(&importProfile, "profile", "p", "", "Import servers into specified profile")
+	importCmd.Flags().StringVar(&importSignature, "signature", signing.PolicyAuto, "GPG signature policy (auto, warn, require, off)")
+	importCmd.Flags().BoolVar(&importInferProfiles, "infer-profiles", true, "Propose profiles for hosts imported from an SSH config")
+	importCmd.Flags().BoolVarP(&importYes, "yes", "y", false, "Create the proposed profiles without asking")
+	importCmd.Flags().Bool("json", false, "Print the import result as JSON; proposed profiles are only created with --yes")
+}
+
+// importJSON is the result of 'sshm import --json'
+type importJSON struct {
+	File string `json:"file"`
+	service.ImportResult
 }
 
 func runImport(cmd *cobra.Command, args []string) error {
 	filePath := args[0]
+	asJSON, _ := cmd.Flags().GetBool("json")
+	
+	// With --json only the result is printed
+	output := cmd.OutOrStdout()
+	if asJSON {
+		output = io.Discard
+	}
 	
 	// Check if file exists
 	if _, err := os.Stat(filePath); os.IsNotExist(err) {
 		return fmt.Errorf("file does not exist: %s", filePath)
 	}
 	
+	// Verify the file's signature before trusting its contents
+	if err := checkImportSignature(output, filePath, importSignature); err != nil {
+		return err
+	}
+	
 	// Detect file type if not specified
 	fileType := importType
 	if fileType == "" {
@@ -60,87 +104,27 @@ func runImport(cmd *cobra.Command, args []string) error {
 	}
 	
 	// Validate file type
-	if fileType != "ssh" && fileType != "yaml" && fileType != "json" {
-		return fmt.Errorf("unsupported file type: %s (supported: ssh, yaml, json)", fileType)
+	imp, ok := importer.Lookup(fileType)
+	if !ok {
+		return fmt.Errorf("unsupported file type: %s (supported: %s)", fileType, strings.Join(importer.Names(), ", "))
 	}
 	
 	// Load current configuration
-	cfg, err := config.Load()
+	svc, err := service.Load()
 	if err != nil {
 		return fmt.Errorf("failed to load configuration: %w", err)
 	}
 	
-	var servers []config.Server
-	var profiles []config.Profile
-	
-	// Parse file based on type
-	switch fileType {
-	case "ssh":
-		servers, err = config.ParseSSHConfig(filePath)
-		if err != nil {
-			return fmt.Errorf("failed to parse SSH config: %w", err)
-		}
-		
-	case "yaml", "yml":
-		servers, profiles, err = parseYAMLConfig(filePath)
-		if err != nil {
-			return fmt.Errorf("failed to parse YAML config: %w", err)
-		}
-		
-	case "json":
-		servers, profiles, err = parseJSONConfig(filePath)
-		if err != nil {
-			return fmt.Errorf("failed to parse JSON config: %w", err)
-		}
+	// Parse file with the type's importer
+	servers, profiles, err := parseImportFile(output, imp, filePath)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s file: %w", fileType, err)
 	}
 	
 	if len(servers) == 0 {
 		return fmt.Errorf("no valid server configurations found in file")
 	}
 	
-	// Import servers
-	imported := 0
-	updated := 0
-	
-	for _, server := range servers {
-		// Check if server already exists
-		existing, err := cfg.GetServer(server.Name)
-		if err == nil {
-			// Server exists - update it
-			if err := cfg.RemoveServer(existing.Name); err != nil {
-				fmt.Printf("%s\n", color.WarningMessage("failed to remove existing server %s: %v", existing.Name, err))
-				continue
-			}
-			updated++
-		} else {
-			imported++
-		}
-		
-		// Add the server
-		if err := cfg.AddServer(server); err != nil {
-			fmt.Printf("%s\n", color.WarningMessage("failed to import server %s: %v", server.Name, err))
-			continue
-		}
-	}
-	
-	// Import profiles if any were found
-	for _, profile := range profiles {
-		// Check if profile already exists
-		existing, err := cfg.GetProfile(profile.Name)
-		if err == nil {
-			// Profile exists - update it
-			if err := cfg.RemoveProfile(existing.Name); err != nil {
-				fmt.Printf("%s\n", color.WarningMessage("failed to remove existing profile %s: %v", existing.Name, err))
-				continue
-			}
-		}
-		
-		if err := cfg.AddProfile(profile); err != nil {
-			fmt.Printf("%s\n", color.WarningMessage("failed to import profile %s: %v", profile.Name, err))
-			continue
-		}
-	}
-	
 	// If profile flag is specified, create/update profile with imported servers
 	if importProfile != "" {
 		var serverNames []string
@@ -148,110 +132,156 @@ func runImport(cmd *cobra.Command, args []string) error {
 			serverNames = append(serverNames, server.Name)
 		}
 		
-		profile := config.Profile{
+		profiles = append(profiles, config.Profile{
 			Name:        importProfile,
 			Description: fmt.Sprintf("Servers imported from %s", filepath.Base(filePath)),
 			Servers:     serverNames,
-		}
-		
-		// Remove existing profile if it exists
-		if existing, err := cfg.GetProfile(importProfile); err == nil {
-			if err := cfg.RemoveProfile(existing.Name); err != nil {
-				fmt.Printf("%s\n", color.WarningMessage("failed to remove existing profile %s: %v", existing.Name, err))
-			}
-		}
-		
-		if err := cfg.AddProfile(profile); err != nil {
-			fmt.Printf("%s\n", color.WarningMessage("failed to create profile %s: %v", importProfile, err))
+		})
+	}
+	
+	// Import servers and profiles the way the TUI does, and save them
+	result, err := svc.Import(servers, profiles)
+	if err != nil {
+		return err
+	}
+	for _, skipped := range result.Skipped {
+		fmt.Fprintf(output, "%s\n", color.WarningMessage("failed to import server %s: %s", skipped.Name, skipped.Error))
+	}
+	for _, skipped := range result.SkippedProfiles {
+		fmt.Fprintf(output, "%s\n", color.WarningMessage("failed to import profile %s: %s", skipped.Name, skipped.Error))
+	}
+	if importProfile != "" {
+		if profile, err := svc.Config().GetProfile(importProfile); err == nil {
+			fmt.Fprintf(output, "%s\n", color.SuccessMessage("Created profile '%s' with %d servers", importProfile, len(profile.Servers)))
 		} else {
-			fmt.Printf("%s\n", color.SuccessMessage("Created profile '%s' with %d servers", importProfile, len(serverNames)))
+			fmt.Fprintf(output, "%s\n", color.WarningMessage("failed to create profile %s", importProfile))
+		}
+	}
+	
+	// Group hosts from an SSH config into profiles instead of leaving them all unassigned;
+	// --json never asks, so they're only created with --yes
+	if importProfile == "" && importInferProfiles && strings.EqualFold(imp.Name(), "ssh") && (!asJSON || importYes) {
+		if err := proposeImportProfiles(output, os.Stdin, svc.Config(), filePath, importYes); err != nil {
+			fmt.Fprintf(output, "%s\n", color.WarningMessage("%v", err))
+		} else if err := svc.Save(); err != nil {
+			return err
 		}
 	}
 	
-	// Save configuration
-	if err := cfg.Save(); err != nil {
-		return fmt.Errorf("failed to save configuration: %w", err)
+	if asJSON {
+		return writeJSON(cmd.OutOrStdout(), importJSON{File: filePath, ImportResult: result})
 	}
 	
 	// Print summary
-	fmt.Printf("%s\n", color.SuccessMessage("Import completed:"))
-	fmt.Printf("  • %s\n", color.InfoText("%d servers imported", imported))
-	if updated > 0 {
-		fmt.Printf("  • %s\n", color.InfoText("%d servers updated", updated))
+	fmt.Fprintf(output, "%s\n", color.SuccessMessage("Import completed:"))
+	fmt.Fprintf(output, "  • %s\n", color.InfoText("%d servers imported", result.Added))
+	if result.Updated > 0 {
+		fmt.Fprintf(output, "  • %s\n", color.InfoText("%d servers updated", result.Updated))
 	}
-	if len(profiles) > 0 {
-		fmt.Printf("  • %s\n", color.InfoText("%d profiles imported", len(profiles)))
+	if result.Profiles > 0 {
+		fmt.Fprintf(output, "  • %s\n", color.InfoText("%d profiles imported", result.Profiles))
 	}
 	
 	return nil
 }
 
-// detectFileType determines the file type based on extension
-func detectFileType(filePath string) string {
-	ext := strings.ToLower(filepath.Ext(filePath))
-	base := strings.ToLower(filepath.Base(filePath))
-	
-	switch ext {
-	case ".yaml", ".yml":
-		return "yaml"
-	case ".json":
-		return "json"
+// checkImportSignature verifies the detached signature of an import file according to policy,
+// returning an error when the import must be rejected
+func checkImportSignature(output io.Writer, filePath, policy string) error {
+	if policy == "" {
+		policy = signing.PolicyAuto
+	}
+	switch policy {
+	case signing.PolicyAuto, signing.PolicyWarn, signing.PolicyRequire:
+	case signing.PolicyOff:
+		return nil
 	default:
-		// Check for common SSH config file names
-		if base == "config" || base == "ssh_config" || strings.Contains(base, "ssh") {
-			return "ssh"
+		return fmt.Errorf("unsupported signature policy: %s (supported: %s)", policy, strings.Join(signing.Policies, ", "))
+	}
+	
+	result, err := signing.Verify(filePath)
+	if errors.Is(err, signing.ErrNoSignature) {
+		if policy == signing.PolicyRequire {
+			return fmt.Errorf("%s is not signed (expected %s)", filePath, signing.SignaturePath(filePath))
 		}
-		// Default to YAML for unknown extensions
-		return "yaml"
+		return nil
+	}
+	if err == nil && result.Valid {
+		fmt.Fprintf(output, "%s\n", color.SuccessMessage("Signature verified: %s (key %s)", result.Signer, result.KeyID))
+		return nil
+	}
+	
+	reason := ""
+	if err != nil {
+		reason = err.Error()
+	} else {
+		reason = result.Reason
 	}
+	if policy == signing.PolicyWarn {
+		fmt.Fprintf(output, "%s\n", color.WarningMessage("Signature verification failed: %s", reason))
+		return nil
+	}
+	return fmt.Errorf("signature verification failed for %s: %s (use --signature warn to import anyway)", filePath, reason)
 }
 
-// parseYAMLConfig parses a YAML configuration file
-func parseYAMLConfig(filePath string) ([]config.Server, []config.Profile, error) {
-	data, err := os.ReadFile(filePath)
+// detectFileType determines the file type from its content, or its name when
+// the content is not recognized
+func detectFileType(filePath string) string {
+	return importer.DetectFile(filePath)
+}
+
+// parseImportFile parses a file with an importer, skipping invalid servers
+func parseImportFile(output io.Writer, imp importer.Importer, filePath string) ([]config.Server, []config.Profile, error) {
+	servers, profiles, err := importer.ParseFile(imp, filePath)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to read file: %w", err)
-	}
-	
-	var cfg config.Config
-	if err := yaml.Unmarshal(data, &cfg); err != nil {
-		return nil, nil, fmt.Errorf("failed to parse YAML: %w", err)
+		return nil, nil, err
 	}
 	
 	// Validate servers
 	var validServers []config.Server
-	for _, server := range cfg.Servers {
+	for _, server := range servers {
 		if err := server.Validate(); err != nil {
-			fmt.Printf("%s\n", color.WarningMessage("skipping invalid server %s: %v", server.Name, err))
+			fmt.Fprintf(output, "%s\n", color.WarningMessage("skipping invalid server %s: %v", server.Name, err))
 			continue
 		}
 		validServers = append(validServers, server)
 	}
 	
-	return validServers, cfg.Profiles, nil
+	return validServers, profiles, nil
 }
 
-// parseJSONConfig parses a JSON configuration file
-func parseJSONConfig(filePath string) ([]config.Server, []config.Profile, error) {
-	data, err := os.ReadFile(filePath)
+// proposeImportProfiles shows the profiles inferred for an SSH config's hosts and
+// creates them once confirmed
+func proposeImportProfiles(output io.Writer, input io.Reader, cfg *config.Config, filePath string, yes bool) error {
+	file, err := config.ReadSSHConfig(filePath)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to read file: %w", err)
+		return fmt.Errorf("failed to infer profiles: %w", err)
+	}
+	proposals := config.InferSSHProfiles(file)
+	if len(proposals) == 0 {
+		return nil
 	}
 	
-	var cfg config.Config
-	if err := json.Unmarshal(data, &cfg); err != nil {
-		return nil, nil, fmt.Errorf("failed to parse JSON: %w", err)
+	fmt.Fprintf(output, "%s\n", color.Header("Proposed profiles:"))
+	for _, proposal := range proposals {
+		fmt.Fprintf(output, "  • %s (%d): %s\n", color.InfoText("%s", proposal.Profile), len(proposal.Servers), strings.Join(proposal.Servers, ", "))
+		fmt.Fprintf(output, "    %s\n", strings.Join(proposal.Reasons, "; "))
 	}
 	
-	// Validate servers
-	var validServers []config.Server
-	for _, server := range cfg.Servers {
-		if err := server.Validate(); err != nil {
-			fmt.Printf("%s\n", color.WarningMessage("skipping invalid server %s: %v", server.Name, err))
-			continue
+	if !yes {
+		fmt.Fprintf(output, "%s", color.InfoText("Create these profiles? (y/N): "))
+		scanner := bufio.NewScanner(input)
+		scanner.Scan()
+		if answer := strings.ToLower(strings.TrimSpace(scanner.Text())); answer != "y" && answer != "yes" {
+			fmt.Fprintf(output, "%s\n", color.InfoMessage("Servers left unassigned"))
+			return nil
 		}
-		validServers = append(validServers, server)
 	}
 	
-	return validServers, cfg.Profiles, nil
-}
\ No newline at end of file
+	created, extended, err := cfg.ApplyProfileProposals(proposals)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(output, "%s\n", color.SuccessMessage("Created %d profile(s), added servers to %d existing", created, extended))
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"sshm/internal/config"
@@ -312,3 +313,41 @@ func TestDetectFileType(t *testing.T) {
 		})
 	}
 }
+
+func TestProposeImportProfiles(t *testing.T) {
+	sshConfig := `Host *.prod.example.com
+    User ops
+
+Host db1
+    HostName db1.prod.example.com
+    User ops
+
+Host db2
+    HostName db2.prod.example.com
+    User ops`
+	configPath := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(configPath, []byte(sshConfig), 0644); err != nil {
+		t.Fatalf("Failed to create SSH config: %v", err)
+	}
+
+	declined := &config.Config{}
+	var output bytes.Buffer
+	if err := proposeImportProfiles(&output, strings.NewReader("n\n"), declined, configPath, false); err != nil {
+		t.Fatalf("proposeImportProfiles() error = %v", err)
+	}
+	if !strings.Contains(output.String(), "prod") || !strings.Contains(output.String(), "(2): db1, db2") {
+		t.Errorf("Expected the proposal to be shown, got: %s", output.String())
+	}
+	if len(declined.Profiles) != 0 {
+		t.Errorf("Expected no profiles when declined, got %v", declined.Profiles)
+	}
+
+	accepted := &config.Config{}
+	output.Reset()
+	if err := proposeImportProfiles(&output, strings.NewReader("y\n"), accepted, configPath, false); err != nil {
+		t.Fatalf("proposeImportProfiles() error = %v", err)
+	}
+	if profile, err := accepted.GetProfile("prod"); err != nil || len(profile.Servers) != 2 {
+		t.Errorf("Expected the prod profile with 2 servers, got %+v (%v)", profile, err)
+	}
+}
@@ -21,6 +21,7 @@ var keyringCmd = &cobra.Command{
 The keyring command provides tools for:
   • Checking keyring service status and availability
   • Migrating plaintext credentials to encrypted storage
+  • Storing and forgetting server passwords and key passphrases
   • Managing keyring configuration settings
 
 Supported keyring services:
@@ -32,7 +33,9 @@ Supported keyring services:
 Examples:
   sshm keyring status              # Check keyring availability
   sshm keyring migrate             # Migrate plaintext credentials
-  sshm keyring migrate --server web01  # Migrate specific server`,
+  sshm keyring migrate --server web01  # Migrate specific server
+  sshm keyring set db01            # Store db01's password or key passphrase
+  sshm keyring forget db01         # Stop keeping it in the keyring`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return runKeyringStatusCommand(cmd.OutOrStdout())
 	},
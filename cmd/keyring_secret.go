@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+	"sshm/internal/color"
+	"sshm/internal/config"
+	"sshm/internal/secrets"
+)
+
+var keyringSetCmd = &cobra.Command{
+	Use:   "set <server>",
+	Short: "Store a server's password or key passphrase in the keyring",
+	Long: `Store the password of a password-auth server, or the passphrase of its SSH
+key, in the system keyring (macOS Keychain, Secret Service, Windows Credential
+Manager). The configuration only references the secret by its ID, and
+connections, tunnels and status checks read it from the keyring instead of
+prompting.
+
+The secret is read from the terminal without echo, or from standard input when
+it isn't a terminal. Servers sharing a password can reference the same secret
+with --id.
+
+Examples:
+  sshm keyring set web01                      # Prompt for web01's password
+  sshm keyring set db01                       # Prompt for db01's key passphrase
+  sshm keyring set web02 --id ops-password    # Reference a shared secret
+  pass show ops | sshm keyring set web01      # Read the secret from a pipe`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, _ := cmd.Flags().GetString("id")
+		return runKeyringSetCommand(cmd.OutOrStdout(), cmd.InOrStdin(), args[0], id)
+	},
+}
+
+var keyringForgetCmd = &cobra.Command{
+	Use:   "forget <server>",
+	Short: "Stop keeping a server's password or key passphrase in the keyring",
+	Long: `Remove the reference to a server's stored password or key passphrase, and
+delete the secret from the keyring unless another server references it too.
+Connections to the server prompt for the secret again afterwards.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runKeyringForgetCommand(cmd.OutOrStdout(), args[0])
+	},
+}
+
+func init() {
+	keyringCmd.AddCommand(keyringSetCmd)
+	keyringCmd.AddCommand(keyringForgetCmd)
+
+	keyringSetCmd.Flags().String("id", "", "Secret ID to store under, shared by servers that reference the same ID")
+}
+
+func runKeyringSetCommand(output io.Writer, input io.Reader, serverName, id string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("❌ Failed to load configuration: %w", err)
+	}
+	server, err := cfg.GetServer(serverName)
+	if err != nil {
+		return fmt.Errorf("❌ %w", err)
+	}
+	kind, err := secrets.KindOf(*server)
+	if err != nil {
+		return fmt.Errorf("❌ %w", err)
+	}
+
+	secret, err := readSecret(output, input, fmt.Sprintf("Enter the %s for %s: ", kind, server.Name))
+	if err != nil {
+		return fmt.Errorf("❌ Failed to read the %s: %w", kind, err)
+	}
+	if secret == "" {
+		return fmt.Errorf("❌ The %s must not be empty", kind)
+	}
+
+	if err := secrets.Save(server, id, secret); err != nil {
+		return fmt.Errorf("❌ %w", err)
+	}
+	if err := cfg.UpdateServer(*server); err != nil {
+		return fmt.Errorf("❌ Failed to update server: %w", err)
+	}
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("❌ Failed to save configuration: %w", err)
+	}
+
+	fmt.Fprintf(output, "%s\n", color.SuccessMessage("Stored the %s of %s in the keyring as '%s'", kind, server.Name, server.KeyringID))
+	return nil
+}
+
+func runKeyringForgetCommand(output io.Writer, serverName string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("❌ Failed to load configuration: %w", err)
+	}
+	server, err := cfg.GetServer(serverName)
+	if err != nil {
+		return fmt.Errorf("❌ %w", err)
+	}
+	id := secrets.ID(*server)
+
+	deleted, err := secrets.Forget(cfg, server.Name)
+	if errors.Is(err, secrets.ErrNotFound) {
+		fmt.Fprintf(output, "%s\n", color.InfoMessage("%s doesn't keep a secret in the keyring", server.Name))
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("❌ %w", err)
+	}
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("❌ Failed to save configuration: %w", err)
+	}
+
+	if deleted {
+		fmt.Fprintf(output, "%s\n", color.SuccessMessage("Deleted secret '%s' of %s from the keyring", id, server.Name))
+	} else {
+		fmt.Fprintf(output, "%s\n", color.SuccessMessage("%s no longer references secret '%s', which other servers still use", server.Name, id))
+	}
+	return nil
+}
+
+// readSecret reads a secret without echo from the terminal, or a line from
+// input when it isn't one
+func readSecret(output io.Writer, input io.Reader, prompt string) (string, error) {
+	if file, ok := input.(*os.File); ok && term.IsTerminal(int(file.Fd())) {
+		fmt.Fprint(output, color.InfoText("%s", prompt))
+		secret, err := term.ReadPassword(int(file.Fd()))
+		fmt.Fprintln(output)
+		return string(secret), err
+	}
+	line, err := bufio.NewReader(input).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"sshm/internal/config"
+	"sshm/internal/secrets"
+)
+
+func TestKeyringSetAndForget(t *testing.T) {
+	testDir := t.TempDir()
+	t.Setenv("SSHM_CONFIG_DIR", testDir)
+	store := secrets.NewMemoryStore()
+	defer secrets.Use(store)()
+
+	cfg := &config.Config{Servers: []config.Server{
+		{Name: "web01", Hostname: "web01.example.com", Port: 22, Username: "ops", AuthType: "password", Password: "plain"},
+		{Name: "db01", Hostname: "db01.example.com", Port: 22, Username: "ops", AuthType: "key", KeyPath: "~/.ssh/id_ed25519"},
+	}}
+	if err := cfg.SaveToPath(filepath.Join(testDir, "config.yaml")); err != nil {
+		t.Fatal(err)
+	}
+
+	var output strings.Builder
+	if err := runKeyringSetCommand(&output, strings.NewReader("s3cret\n"), "web01", ""); err != nil {
+		t.Fatalf("runKeyringSetCommand() error = %v", err)
+	}
+	if err := runKeyringSetCommand(&output, strings.NewReader("key pass\n"), "db01", ""); err != nil {
+		t.Fatalf("runKeyringSetCommand() error = %v", err)
+	}
+	loaded, err := config.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	web, _ := loaded.GetServer("web01")
+	if web.Password != "" || web.KeyringID != "password-web01" {
+		t.Errorf("Expected web01 to reference its password instead of storing it, got %+v", web)
+	}
+	if got, _ := store.Get("password-web01"); got != "s3cret" {
+		t.Errorf("Expected the password in the keyring, got %q", got)
+	}
+	db, _ := loaded.GetServer("db01")
+	if !db.PassphraseProtected || db.KeyringID != "passphrase-db01" {
+		t.Errorf("Expected db01 to reference its key passphrase, got %+v", db)
+	}
+
+	output.Reset()
+	if err := runKeyringForgetCommand(&output, "web01"); err != nil {
+		t.Fatalf("runKeyringForgetCommand() error = %v", err)
+	}
+	if !strings.Contains(output.String(), "Deleted secret 'password-web01'") {
+		t.Errorf("Expected the secret to be deleted, got:\n%s", output.String())
+	}
+	if _, err := store.Get("password-web01"); err == nil {
+		t.Error("Expected the password to leave the keyring")
+	}
+
+	if err := runKeyringSetCommand(&output, strings.NewReader("\n"), "web01", ""); err == nil {
+		t.Error("Expected an empty password to be rejected")
+	}
+}
@@ -1,9 +1,12 @@
 package cmd
 
 import (
+  "encoding/json"
   "fmt"
   "io"
+  "strings"
   "text/tabwriter"
+  "time"
 
   "github.com/spf13/cobra"
   "sshm/internal/color"
@@ -25,15 +28,118 @@ The table shows:
 Examples:
   sshm list                     # List all servers
   sshm list --profile dev       # List servers in 'dev' profile
-  sshm list | grep production   # Filter production servers`,
+  sshm list | grep production   # Filter production servers
+  sshm list --launcher-json     # Script-filter items for Raycast/Alfred
+  sshm list --json              # Server settings as JSON for scripts
+
+With --launcher-json the servers are printed as script-filter JSON
+({"items": [{"title", "subtitle", "arg", ...}]}) so an OS launcher can
+fuzzy-search them; each item's "arg" is the command that connects to it.
+With --json the servers are printed with all their settings, except
+plaintext passwords.`,
   RunE: func(cmd *cobra.Command, args []string) error {
     profile, _ := cmd.Flags().GetString("profile")
+    launcherJSON, _ := cmd.Flags().GetBool("launcher-json")
+    if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+      return runListJSON(cmd.OutOrStdout(), profile)
+    }
+    if launcherJSON {
+      return runListLauncherJSON(cmd.OutOrStdout(), profile)
+    }
     return runListCommand(cmd.OutOrStdout(), profile)
   },
 }
 
 func init() {
   listCmd.Flags().StringP("profile", "p", "", "Filter servers by profile name")
+  listCmd.Flags().Bool("launcher-json", false, "Print servers as Raycast/Alfred script-filter JSON")
+  listCmd.Flags().Bool("json", false, "Print servers and their settings as JSON")
+}
+
+func runListJSON(output io.Writer, profileName string) error {
+  cfg, err := config.Load()
+  if err != nil {
+    return fmt.Errorf("❌ Failed to load configuration: %w", err)
+  }
+
+  servers := cfg.GetServers()
+  if profileName != "" {
+    servers, err = cfg.GetServersByProfile(profileName)
+    if err != nil {
+      return fmt.Errorf("❌ Profile '%s' not found", profileName)
+    }
+  }
+  return writeJSON(output, serversJSON(servers))
+}
+
+// launcherItem is a single script-filter entry understood by Alfred and Raycast
+type launcherItem struct {
+  UID          string `json:"uid"`
+  Title        string `json:"title"`
+  Subtitle     string `json:"subtitle"`
+  Arg          string `json:"arg"`
+  Autocomplete string `json:"autocomplete"`
+  Match        string `json:"match"`
+}
+
+// launcherOutput is the top-level script-filter document
+type launcherOutput struct {
+  Items []launcherItem `json:"items"`
+}
+
+func runListLauncherJSON(output io.Writer, profileName string) error {
+  cfg, err := config.Load()
+  if err != nil {
+    return fmt.Errorf("❌ Failed to load configuration: %w", err)
+  }
+
+  servers := cfg.GetServers()
+  if profileName != "" {
+    servers, err = cfg.GetServersByProfile(profileName)
+    if err != nil {
+      return fmt.Errorf("❌ Profile '%s' not found", profileName)
+    }
+  }
+
+  encoder := json.NewEncoder(output)
+  encoder.SetIndent("", "  ")
+  return encoder.Encode(buildLauncherItems(servers, cfg.GetProfiles()))
+}
+
+// buildLauncherItems converts servers into script-filter items whose arg connects to the server
+func buildLauncherItems(servers []config.Server, profiles []config.Profile) launcherOutput {
+  result := launcherOutput{Items: []launcherItem{}}
+
+  for _, server := range servers {
+    var memberOf []string
+    for _, profile := range profiles {
+      for _, name := range profile.Servers {
+        if name == server.Name {
+          memberOf = append(memberOf, profile.Name)
+          break
+        }
+      }
+    }
+
+    subtitle := fmt.Sprintf("%s@%s:%d", server.Username, server.Hostname, server.Port)
+    if len(memberOf) > 0 {
+      subtitle += " · " + strings.Join(memberOf, ", ")
+    }
+
+    matchTerms := append([]string{server.Name, server.Hostname, server.Username}, memberOf...)
+    matchTerms = append(matchTerms, server.Tags...)
+
+    result.Items = append(result.Items, launcherItem{
+      UID:          server.Name,
+      Title:        server.Name,
+      Subtitle:     subtitle,
+      Arg:          "sshm connect " + server.Name,
+      Autocomplete: server.Name,
+      Match:        strings.Join(matchTerms, " "),
+    })
+  }
+
+  return result
 }
 
 func runListCommand(output io.Writer, profileName string) error {
@@ -91,6 +197,14 @@ func runListCommand(output io.Writer, profileName string) error {
   }
 
   w.Flush()
+
+  // Flag scratch servers that are about to be archived
+  now := time.Now()
+  for _, server := range servers {
+    if server.IsExpiringSoon(now) {
+      fmt.Fprintf(output, "%s\n", color.WarningMessage("⏳ %s expires in %s", server.Name, formatTimeUntil(server.ExpiresAt.Sub(now))))
+    }
+  }
   
   fmt.Fprintf(output, "\n%s\n", color.InfoMessage("%s: %d server(s)", contextMessage, len(servers)))
   if profileName != "" {
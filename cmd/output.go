@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+
+	"sshm/internal/config"
+)
+
+// errConfirmationRequired is returned by --json commands that would have to
+// ask before going ahead; they never read answers from standard input
+var errConfirmationRequired = errors.New("❌ Confirmation required; pass --yes to run without asking")
+
+// writeJSON prints v as indented JSON, the output of the --json flags
+func writeJSON(output io.Writer, v interface{}) error {
+	encoder := json.NewEncoder(output)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// serverJSON returns the server as --json prints it, without a plaintext
+// password
+func serverJSON(server config.Server) config.Server {
+	server.Password = ""
+	return server
+}
+
+// serversJSON returns the servers as --json prints them
+func serversJSON(servers []config.Server) []config.Server {
+	printed := make([]config.Server, 0, len(servers))
+	for _, server := range servers {
+		printed = append(printed, serverJSON(server))
+	}
+	return printed
+}
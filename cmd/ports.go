@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"sshm/internal/color"
+	"sshm/internal/config"
+	"sshm/internal/portcheck"
+)
+
+var portsCmd = &cobra.Command{
+	Use:   "ports <server-name>",
+	Short: "Check which ports of a server are open, closed or filtered",
+	Long: `Probe the server's SSH port and a list of common ports from this machine, or
+from another configured server such as a jump host, and report each as open,
+closed (the host refused the connection) or filtered (no answer).
+
+This tells a host that is down apart from one where only SSH is blocked: a
+filtered SSH port next to an open web port means the host is up.
+
+The ports come from port_check.ports in the configuration (default: 22, 80,
+443, 3306, 5432, 6379, 8080), and --ports overrides them. Probes via another
+server need bash and timeout there.
+
+Examples:
+  sshm ports web1
+  sshm ports web1 --ports 22,443,9100
+  sshm ports db1 --via bastion
+  sshm ports web1 --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ports, _ := cmd.Flags().GetString("ports")
+		via, _ := cmd.Flags().GetString("via")
+		timeout, _ := cmd.Flags().GetDuration("timeout")
+		asJSON, _ := cmd.Flags().GetBool("json")
+		return runPortsCommand(cmd.OutOrStdout(), args[0], ports, via, timeout, asJSON)
+	},
+}
+
+func init() {
+	portsCmd.Flags().String("ports", "", "Comma separated ports to check instead of port_check.ports")
+	portsCmd.Flags().String("via", "", "Run the probes from this server, e.g. a jump host")
+	portsCmd.Flags().Duration("timeout", 0, "How long to wait before a port counts as filtered (default: port_check.timeout or 3s)")
+	portsCmd.Flags().Bool("json", false, "Output the results as JSON")
+	rootCmd.AddCommand(portsCmd)
+}
+
+func runPortsCommand(output io.Writer, serverName, portList, viaName string, timeout time.Duration, asJSON bool) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("❌ Failed to load configuration: %w", err)
+	}
+
+	server, err := cfg.GetServer(serverName)
+	if err != nil {
+		return fmt.Errorf("❌ Server '%s' not found", serverName)
+	}
+
+	ports := cfg.PortCheck.PortList()
+	if portList != "" {
+		if ports, err = config.ParsePorts(portList); err != nil {
+			return fmt.Errorf("❌ %w", err)
+		}
+	}
+	if timeout <= 0 {
+		timeout = cfg.PortCheck.TimeoutDuration()
+	}
+
+	var via *config.Server
+	if viaName != "" {
+		if via, err = cfg.GetServer(viaName); err != nil {
+			return fmt.Errorf("❌ Server '%s' not found", viaName)
+		}
+	}
+
+	report, err := portcheck.Run(context.Background(), *server, via, ports, timeout)
+	if err != nil {
+		return fmt.Errorf("❌ %w", err)
+	}
+
+	if asJSON {
+		encoder := json.NewEncoder(output)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	}
+
+	displayPortReport(output, report)
+	return nil
+}
+
+// displayPortReport renders the port states and the verdict as text
+func displayPortReport(output io.Writer, report portcheck.Report) {
+	from := "this machine"
+	if report.Via != "" {
+		from = report.Via
+	}
+	fmt.Fprintf(output, "%s\n\n", color.Header(fmt.Sprintf("Ports of %s (%s) from %s", report.Server, report.Host, from)))
+
+	w := tabwriter.NewWriter(output, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PORT\tSTATE\tDETAIL")
+	for _, result := range report.Results {
+		detail := result.Detail
+		if result.State == portcheck.StateOpen && result.Latency > 0 {
+			detail = result.Latency.Round(time.Millisecond).String()
+		}
+		if result.Port == report.SSHPort {
+			detail = strings.TrimSuffix("ssh, "+detail, ", ")
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\n", result.Port, result.State, detail)
+	}
+	w.Flush()
+	fmt.Fprintln(output)
+
+	for _, result := range report.Results {
+		if result.Port == report.SSHPort && result.State == portcheck.StateOpen {
+			fmt.Fprintf(output, "%s\n", color.SuccessMessage("%s", report.Verdict))
+			return
+		}
+	}
+	fmt.Fprintf(output, "%s\n", color.WarningMessage("%s", report.Verdict))
+}
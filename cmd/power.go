@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"sshm/internal/color"
+	"sshm/internal/config"
+	"sshm/internal/connection"
+	"sshm/internal/power"
+)
+
+var powerCmd = &cobra.Command{
+	Use:   "power <reboot|shutdown|custom> [server-name]",
+	Short: "Reboot, shut down or run a command on a server or profile",
+	Long: `Run a power action over SSH on a server, or on every server in a profile.
+
+Every action asks for confirmation unless turned off with
+'sshm settings confirm power_action=never'. Protected servers always require
+typing the server (or profile) name, even with --yes. Actions are recorded in
+the connection history. After a reboot sshm waits until the host is back.
+
+Reboot and shutdown use 'sudo -n', so the remote user needs passwordless sudo.
+
+Examples:
+  sshm power reboot web-01
+  sshm power shutdown --profile staging
+  sshm power custom web-01 --command "sudo systemctl restart nginx"
+  sshm power reboot web-01 --yes --no-wait`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		profile, _ := cmd.Flags().GetString("profile")
+		command, _ := cmd.Flags().GetString("command")
+		yes, _ := cmd.Flags().GetBool("yes")
+		noWait, _ := cmd.Flags().GetBool("no-wait")
+
+		serverName := ""
+		if len(args) == 2 {
+			serverName = args[1]
+		}
+		if (serverName == "") == (profile == "") {
+			return fmt.Errorf("❌ Specify either a server name or --profile")
+		}
+		return runPowerCommand(cmd.OutOrStdout(), args[0], serverName, profile, command, yes, !noWait)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(powerCmd)
+
+	powerCmd.Flags().StringP("profile", "p", "", "Run the action on every server in the profile")
+	powerCmd.Flags().StringP("command", "c", "", "Command to run for the custom action")
+	powerCmd.Flags().BoolP("yes", "y", false, "Skip the confirmation prompt (protected servers still require typing the name)")
+	powerCmd.Flags().Bool("no-wait", false, "Don't wait for rebooted hosts to come back")
+}
+
+func runPowerCommand(output io.Writer, action, serverName, profileName, command string, yes, wait bool) error {
+	if _, err := power.Command(action, command); err != nil {
+		return fmt.Errorf("❌ %w", err)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("❌ Failed to load configuration: %w", err)
+	}
+
+	var servers []config.Server
+	target := serverName
+	if profileName != "" {
+		servers, err = cfg.GetServersByProfile(profileName)
+		if err != nil {
+			return fmt.Errorf("❌ Profile '%s' not found", profileName)
+		}
+		if len(servers) == 0 {
+			return fmt.Errorf("❌ No servers found in profile '%s'", profileName)
+		}
+		target = profileName
+	} else {
+		server, err := cfg.GetServer(serverName)
+		if err != nil {
+			return fmt.Errorf("❌ Server '%s' not found. Use 'sshm list' to see available servers", serverName)
+		}
+		servers = []config.Server{*server}
+		target = server.Name
+	}
+
+	description := action
+	if action == power.ActionCustom {
+		description = fmt.Sprintf("run '%s'", command)
+	}
+
+	scanner := bufio.NewScanner(os.Stdin)
+	if power.RequiresTypedConfirmation(servers) {
+		fmt.Fprintf(output, "%s\n", color.WarningMessage("About to %s protected target '%s' (%d server(s))", description, target, len(servers)))
+		fmt.Fprintf(output, "Type '%s' to confirm: ", target)
+		if !scanner.Scan() || !power.ConfirmationMatches(target, scanner.Text()) {
+			return fmt.Errorf("❌ Confirmation did not match, nothing was done")
+		}
+	} else if !yes && cfg.UI.ShouldConfirm(config.ConfirmPowerAction, false) {
+		fmt.Fprintf(output, "%s (y/n): ", color.WarningMessage("About to %s '%s' (%d server(s)). Continue?", description, target, len(servers)))
+		if !scanner.Scan() {
+			return fmt.Errorf("❌ Cancelled")
+		}
+		answer := strings.TrimSpace(strings.ToLower(scanner.Text()))
+		if answer != "y" && answer != "yes" {
+			return fmt.Errorf("❌ Cancelled")
+		}
+	}
+
+	manager, err := connection.NewManager()
+	if err != nil {
+		return fmt.Errorf("❌ Failed to initialize connection manager: %w", err)
+	}
+	defer manager.Close()
+
+	var succeeded []config.Server
+	failures := 0
+	for _, server := range servers {
+		result, err := manager.RunPowerAction(server, action, command)
+		if err != nil {
+			failures++
+			fmt.Fprintf(output, "%s\n", color.WarningMessage("%s: %v", server.Name, err))
+		} else {
+			succeeded = append(succeeded, server)
+			fmt.Fprintf(output, "%s\n", color.SuccessMessage("%s: %s sent", server.Name, action))
+		}
+		if result != "" {
+			fmt.Fprintf(output, "%s\n", color.InfoText("%s", result))
+		}
+	}
+
+	if wait && power.WatchAfter(action) {
+		for _, server := range succeeded {
+			fmt.Fprintf(output, "%s\n", color.InfoMessage("Waiting for %s to come back...", server.Name))
+			if err := power.WaitForReturn(server, power.DefaultWaitTimeout, power.DefaultWaitInterval); err != nil {
+				failures++
+				fmt.Fprintf(output, "%s\n", color.WarningMessage("%v", err))
+				continue
+			}
+			fmt.Fprintf(output, "%s\n", color.SuccessMessage("%s is back online", server.Name))
+		}
+	}
+
+	if failures > 0 {
+		return fmt.Errorf("❌ %s failed on %d of %d server(s)", action, failures, len(servers))
+	}
+	return nil
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/spf13/cobra"
 	"sshm/internal/color"
 	"sshm/internal/config"
+	"sshm/internal/service"
 )
 
 var profileCmd = &cobra.Command{
@@ -22,10 +23,16 @@ You can assign servers to profiles and then operate on entire profiles at once.
 
 Examples:
   sshm profile create development    # Create a new profile
+  sshm profile edit dev --name development  # Rename a profile or change its description
   sshm profile list                  # List all profiles
   sshm profile delete staging        # Delete a profile
   sshm profile assign web-dev dev    # Assign server to profile
-  sshm profile unassign web-dev dev  # Remove server from profile`,
+  sshm profile unassign web-dev dev  # Remove server from profile
+  sshm profile quick-stats prod      # Show load/disk/memory stats for prod servers in the TUI
+  sshm profile unassigned            # List servers in no profile, with suggested profiles
+
+Every subcommand takes --json to print its result as JSON for scripts; with
+--json nothing is prompted for.`,
 }
 
 var profileCreateCmd = &cobra.Command{
@@ -34,26 +41,29 @@ var profileCreateCmd = &cobra.Command{
 	Long: `Create a new profile with the specified name.
 	
 You can either provide a description using the --description flag or you will be 
-prompted to enter an optional description for the profile interactively.
+prompted to enter an optional description for the profile interactively
+(except with --json).
 Once created, you can assign servers to this profile using the assign command.
 
 Examples:
   sshm profile create development
   sshm profile create production --description "Production environment servers"
-  sshm profile create staging -d "Staging servers for testing"`,
+  sshm profile create staging -d "Staging servers for testing"
+  sshm profile create ci --json`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		profileName := args[0]
+		asJSON, _ := cmd.Flags().GetBool("json")
 
 		// Load configuration
-		cfg, err := config.Load()
+		svc, err := service.Load()
 		if err != nil {
 			return fmt.Errorf("failed to load configuration: %w", err)
 		}
 
 		// Get description from flag or prompt interactively
 		var description string
-		if cmd.Flags().Changed("description") {
+		if cmd.Flags().Changed("description") || asJSON {
 			description, _ = cmd.Flags().GetString("description")
 		} else {
 			// Prompt for description interactively
@@ -73,30 +83,80 @@ Examples:
 			Servers:     []string{},
 		}
 
-		// Add profile to configuration
-		if err := cfg.AddProfile(profile); err != nil {
+		// Add profile to configuration and save it
+		if err := svc.CreateProfile(profile); err != nil {
 			return fmt.Errorf("failed to add profile: %w", err)
 		}
 
-		// Save configuration
-		if err := cfg.Save(); err != nil {
-			return fmt.Errorf("failed to save configuration: %w", err)
+		if asJSON {
+			return writeJSON(cmd.OutOrStdout(), profile)
 		}
-
 		cmd.Printf("%s\n", color.SuccessMessage("Profile '%s' created successfully", profileName))
 		return nil
 	},
 }
 
+var profileEditCmd = &cobra.Command{
+	Use:   "edit [profile-name]",
+	Short: "Rename a profile or change its description",
+	Long: `Change the name or the description of a profile. Its servers and other
+settings are kept.
+
+Examples:
+  sshm profile edit dev --name development
+  sshm profile edit prod --description "Production servers (EU)"
+  sshm profile edit staging --name stage --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		profileName := args[0]
+
+		// Load configuration
+		svc, err := service.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load configuration: %w", err)
+		}
+
+		profile, err := svc.Config().GetProfile(profileName)
+		if err != nil {
+			return fmt.Errorf("profile '%s' not found", profileName)
+		}
+		if !cmd.Flags().Changed("name") && !cmd.Flags().Changed("description") {
+			return fmt.Errorf("nothing to change; pass --name or --description")
+		}
+
+		// Keep the servers and settings the edit doesn't touch
+		updated := *profile
+		if cmd.Flags().Changed("name") {
+			updated.Name, _ = cmd.Flags().GetString("name")
+		}
+		if cmd.Flags().Changed("description") {
+			updated.Description, _ = cmd.Flags().GetString("description")
+		}
+
+		// Replace the profile and save the configuration
+		if err := svc.UpdateProfile(profileName, updated); err != nil {
+			return fmt.Errorf("failed to update profile: %w", err)
+		}
+
+		if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+			return writeProfileJSON(cmd, svc.Config(), updated.Name)
+		}
+		cmd.Printf("%s\n", color.SuccessMessage("Profile '%s' updated", updated.Name))
+		return nil
+	},
+}
+
 var profileListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List all profiles",
 	Long: `List all configured profiles with their descriptions and assigned servers.
 	
 Shows profile name, description, and the number of servers assigned to each profile.
+With --json the profiles are printed with all their settings and server names.
 
 Examples:
-  sshm profile list`,
+  sshm profile list
+  sshm profile list --json`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// Load configuration
 		cfg, err := config.Load()
@@ -105,6 +165,12 @@ Examples:
 		}
 
 		profiles := cfg.GetProfiles()
+		if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+			if profiles == nil {
+				profiles = []config.Profile{}
+			}
+			return writeJSON(cmd.OutOrStdout(), profiles)
+		}
 		if len(profiles) == 0 {
 			cmd.Printf("%s\n", color.InfoMessage("No profiles configured"))
 			return nil
@@ -137,6 +203,7 @@ This will remove the profile from the configuration but will not delete the serv
 that were assigned to it. The servers will remain in the configuration.
 
 By default, you will be prompted to confirm the deletion. Use --yes to skip confirmation.
+With --json a deletion that needs confirming fails unless --yes is given too.
 
 Examples:
   sshm profile delete staging              # Interactive confirmation
@@ -145,12 +212,14 @@ Examples:
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		profileName := args[0]
+		asJSON, _ := cmd.Flags().GetBool("json")
 
 		// Load configuration
-		cfg, err := config.Load()
+		svc, err := service.Load()
 		if err != nil {
 			return fmt.Errorf("failed to load configuration: %w", err)
 		}
+		cfg := svc.Config()
 
 		// Check if profile exists
 		profile, err := cfg.GetProfile(profileName)
@@ -161,7 +230,10 @@ Examples:
 		// Check if --yes flag is provided for non-interactive mode
 		skipConfirmation, _ := cmd.Flags().GetBool("yes")
 		
-		if !skipConfirmation {
+		if !skipConfirmation && cfg.UI.ShouldConfirm(config.ConfirmDeleteProfile, svc.ProfileHasProtectedServer(profile.Name)) {
+			if asJSON {
+				return errConfirmationRequired
+			}
 			// Show profile details and ask for confirmation
 			fmt.Printf("Profile: %s\n", profile.Name)
 			if profile.Description != "" {
@@ -183,16 +255,15 @@ Examples:
 			}
 		}
 
-		// Remove profile
-		if err := cfg.RemoveProfile(profileName); err != nil {
+		// Remove profile and save the configuration
+		deleted := *profile
+		if err := svc.DeleteProfile(profileName); err != nil {
 			return fmt.Errorf("failed to remove profile: %w", err)
 		}
 
-		// Save configuration
-		if err := cfg.Save(); err != nil {
-			return fmt.Errorf("failed to save configuration: %w", err)
+		if asJSON {
+			return writeJSON(cmd.OutOrStdout(), deleted)
 		}
-
 		cmd.Printf("%s\n", color.SuccessMessage("Profile '%s' deleted successfully", profileName))
 		return nil
 	},
@@ -215,21 +286,19 @@ Examples:
 		profileName := args[1]
 
 		// Load configuration
-		cfg, err := config.Load()
+		svc, err := service.Load()
 		if err != nil {
 			return fmt.Errorf("failed to load configuration: %w", err)
 		}
 
-		// Assign server to profile
-		if err := cfg.AssignServerToProfile(serverName, profileName); err != nil {
+		// Assign server to profile and save the configuration
+		if err := svc.AssignServer(serverName, profileName); err != nil {
 			return fmt.Errorf("failed to assign server to profile: %w", err)
 		}
 
-		// Save configuration
-		if err := cfg.Save(); err != nil {
-			return fmt.Errorf("failed to save configuration: %w", err)
+		if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+			return writeProfileJSON(cmd, svc.Config(), profileName)
 		}
-
 		cmd.Printf("%s\n", color.SuccessMessage("Server '%s' assigned to profile '%s'", serverName, profileName))
 		return nil
 	},
@@ -252,22 +321,157 @@ Examples:
 		profileName := args[1]
 
 		// Load configuration
-		cfg, err := config.Load()
+		svc, err := service.Load()
 		if err != nil {
 			return fmt.Errorf("failed to load configuration: %w", err)
 		}
 
-		// Unassign server from profile
-		if err := cfg.UnassignServerFromProfile(serverName, profileName); err != nil {
+		// Unassign server from profile and save the configuration
+		if err := svc.UnassignServer(serverName, profileName); err != nil {
 			return fmt.Errorf("failed to unassign server from profile: %w", err)
 		}
 
+		if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+			return writeProfileJSON(cmd, svc.Config(), profileName)
+		}
+		cmd.Printf("%s\n", color.SuccessMessage("Server '%s' unassigned from profile '%s'", serverName, profileName))
+		return nil
+	},
+}
+
+var profileQuickStatsCmd = &cobra.Command{
+	Use:   "quick-stats [profile-name]",
+	Short: "Show load, disk and memory stats for a profile's servers in the TUI",
+	Long: `Opt a profile in to quick stats. On each status check the TUI runs a single
+cheap SSH command on the profile's online servers and shows their load average,
+disk and memory usage in a Stats column, e.g. "load 0.4 | 71% disk | 38% mem".
+
+Quick stats add one SSH connection per server per status check, so enable them
+only for profiles where the overhead is acceptable.
+
+Examples:
+  sshm profile quick-stats production
+  sshm profile quick-stats production --off`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		profileName := args[0]
+		off, _ := cmd.Flags().GetBool("off")
+
+		// Load configuration
+		svc, err := service.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load configuration: %w", err)
+		}
+
+		profile, err := svc.Config().GetProfile(profileName)
+		if err != nil {
+			return fmt.Errorf("failed to find profile: %w", err)
+		}
+		updated := *profile
+		updated.QuickStats = !off
+
 		// Save configuration
-		if err := cfg.Save(); err != nil {
-			return fmt.Errorf("failed to save configuration: %w", err)
+		if err := svc.UpdateProfile(profileName, updated); err != nil {
+			return err
 		}
 
-		cmd.Printf("%s\n", color.SuccessMessage("Server '%s' unassigned from profile '%s'", serverName, profileName))
+		if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+			return writeProfileJSON(cmd, svc.Config(), profileName)
+		}
+		if off {
+			cmd.Printf("%s\n", color.SuccessMessage("Quick stats disabled for profile '%s'", profileName))
+		} else {
+			cmd.Printf("%s\n", color.SuccessMessage("Quick stats enabled for profile '%s'", profileName))
+		}
+		return nil
+	},
+}
+
+var profileUnassignedCmd = &cobra.Command{
+	Use:   "unassigned",
+	Short: "List servers that belong to no profile and suggest profiles for them",
+	Long: `List the servers that belong to no profile. For each one that resembles a
+profile, a profile is suggested from its tags and hostname: a tag or name part
+naming the profile, a name or domain shared with the profile's servers, a tag
+in common or the same /24 subnet.
+
+With --apply the suggested assignments are made.
+
+Examples:
+  sshm profile unassigned
+  sshm profile unassigned --apply`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		apply, _ := cmd.Flags().GetBool("apply")
+		asJSON, _ := cmd.Flags().GetBool("json")
+
+		// Load configuration
+		svc, err := service.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load configuration: %w", err)
+		}
+		cfg := svc.Config()
+
+		unassigned := cfg.UnassignedServers()
+		if len(unassigned) == 0 && !asJSON {
+			cmd.Printf("%s\n", color.SuccessMessage("Every server belongs to a profile"))
+			return nil
+		}
+
+		suggested := make(map[string]config.ProfileSuggestion)
+		for _, suggestion := range cfg.SuggestProfileAssignments() {
+			suggested[suggestion.Server] = suggestion
+		}
+
+		if asJSON {
+			var suggestions []config.ProfileSuggestion
+			servers := make([]unassignedJSON, 0, len(unassigned))
+			for _, server := range unassigned {
+				entry := unassignedJSON{Server: server.Name, Hostname: server.Hostname}
+				if suggestion, ok := suggested[server.Name]; ok {
+					entry.SuggestedProfile, entry.Reasons, entry.Assigned = suggestion.Profile, suggestion.Reasons, apply
+					suggestions = append(suggestions, suggestion)
+				}
+				servers = append(servers, entry)
+			}
+			if apply {
+				if _, err := svc.AssignSuggested(suggestions); err != nil {
+					return err
+				}
+			}
+			return writeJSON(cmd.OutOrStdout(), servers)
+		}
+
+		w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "SERVER\tHOSTNAME\tSUGGESTED PROFILE\tWHY")
+		for _, server := range unassigned {
+			suggestion, ok := suggested[server.Name]
+			if !ok {
+				fmt.Fprintf(w, "%s\t%s\t-\t\n", server.Name, server.Hostname)
+				continue
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", server.Name, server.Hostname, suggestion.Profile, strings.Join(suggestion.Reasons, ", "))
+		}
+		w.Flush()
+
+		if !apply {
+			if len(suggested) > 0 {
+				cmd.Printf("\n%s\n", color.InfoMessage("Run with --apply to assign the %d suggested server(s)", len(suggested)))
+			}
+			return nil
+		}
+
+		var suggestions []config.ProfileSuggestion
+		for _, server := range unassigned {
+			if suggestion, ok := suggested[server.Name]; ok {
+				suggestions = append(suggestions, suggestion)
+			}
+		}
+		assigned, err := svc.AssignSuggested(suggestions)
+		if err != nil {
+			return err
+		}
+		cmd.Printf("\n%s\n", color.SuccessMessage("Assigned %d of %d unassigned server(s)", assigned, len(unassigned)))
 		return nil
 	},
 }
@@ -275,14 +479,50 @@ Examples:
 func init() {
 	// Add subcommands to profile command
 	profileCmd.AddCommand(profileCreateCmd)
+	profileCmd.AddCommand(profileEditCmd)
 	profileCmd.AddCommand(profileListCmd)
 	profileCmd.AddCommand(profileDeleteCmd)
 	profileCmd.AddCommand(profileAssignCmd)
 	profileCmd.AddCommand(profileUnassignCmd)
+	profileCmd.AddCommand(profileQuickStatsCmd)
+	profileCmd.AddCommand(profileUnassignedCmd)
 
 	// Add flags for profile create command
 	profileCreateCmd.Flags().StringP("description", "d", "", "Description for the profile")
+
+	// Add flags for profile edit command
+	profileEditCmd.Flags().String("name", "", "New name of the profile")
+	profileEditCmd.Flags().StringP("description", "d", "", "New description of the profile")
 	
 	// Add flags for profile delete command
 	profileDeleteCmd.Flags().BoolP("yes", "y", false, "Skip confirmation prompt and delete profile")
-}
\ No newline at end of file
+
+	// Add flags for profile quick-stats command
+	profileQuickStatsCmd.Flags().Bool("off", false, "Disable quick stats for the profile")
+
+	// Add flags for profile unassigned command
+	profileUnassignedCmd.Flags().Bool("apply", false, "Assign the suggested profiles")
+
+	// Every profile subcommand can print its result as JSON
+	for _, subcommand := range profileCmd.Commands() {
+		subcommand.Flags().Bool("json", false, "Print the result as JSON; never prompts")
+	}
+}
+// writeProfileJSON prints the named profile as --json does
+func writeProfileJSON(cmd *cobra.Command, cfg *config.Config, profileName string) error {
+	profile, err := cfg.GetProfile(profileName)
+	if err != nil {
+		return err
+	}
+	return writeJSON(cmd.OutOrStdout(), profile)
+}
+
+// unassignedJSON is a server of 'sshm profile unassigned --json' with the
+// profile suggested for it, if any
+type unassignedJSON struct {
+	Server           string   `json:"server"`
+	Hostname         string   `json:"hostname"`
+	SuggestedProfile string   `json:"suggested_profile,omitempty"`
+	Reasons          []string `json:"reasons,omitempty"`
+	Assigned         bool     `json:"assigned"`
+}
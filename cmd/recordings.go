@@ -0,0 +1,247 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"sshm/internal/color"
+	"sshm/internal/config"
+	"sshm/internal/recording"
+)
+
+var recordingsCmd = &cobra.Command{
+	Use:   "recordings",
+	Short: "Record sessions and play the recordings back",
+	Long: `Record the tmux sessions of chosen servers as asciinema casts and play them back.
+
+Sessions of servers with recording enabled are recorded from the moment sshm
+creates them, one recording per window, into ~/.sshm/recordings (set
+recording.dir in the configuration to change it). Recordings can be played
+here, in the TUI ('*') or with asciinema itself.
+
+Examples:
+  sshm recordings enable prod-db     # Record new sessions of prod-db
+  sshm recordings list               # List recordings, newest first
+  sshm recordings play prod-db-20261018-093005 --speed 2
+  sshm recordings disable prod-db    # Stop recording new sessions of prod-db`,
+}
+
+var recordingsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List recordings, newest first",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runRecordingsListCommand(cmd.OutOrStdout())
+	},
+}
+
+var recordingsPlayCmd = &cobra.Command{
+	Use:   "play <recording>",
+	Short: "Play a recording in the terminal",
+	Long: `Play a recording in the terminal at the pace it was recorded. The recording
+is a name shown by 'sshm recordings list' or a path to a cast file.
+Press Ctrl+C to stop.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		speed, _ := cmd.Flags().GetFloat64("speed")
+		idleLimit, _ := cmd.Flags().GetDuration("idle-limit")
+		return runRecordingsPlayCommand(args[0], recording.PlayOptions{Speed: speed, IdleLimit: idleLimit})
+	},
+}
+
+var recordingsEnableCmd = &cobra.Command{
+	Use:   "enable <server-name>",
+	Short: "Record new sessions of a server",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runRecordingsToggleCommand(cmd.OutOrStdout(), args[0], true)
+	},
+}
+
+var recordingsDisableCmd = &cobra.Command{
+	Use:   "disable <server-name>",
+	Short: "Stop recording new sessions of a server",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runRecordingsToggleCommand(cmd.OutOrStdout(), args[0], false)
+	},
+}
+
+// recordingsCaptureCmd is what tmux pipes the output of recorded windows into.
+// It never prints anything since tmux would show it in the window.
+var recordingsCaptureCmd = &cobra.Command{
+	Use:          "capture <session> <window> <width> <height>",
+	Short:        "Record the output piped in by tmux",
+	Hidden:       true,
+	SilenceUsage: true,
+	Args:         cobra.ExactArgs(4),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		width, _ := strconv.Atoi(args[2])
+		height, _ := strconv.Atoi(args[3])
+		return runRecordingsCaptureCommand(os.Stdin, args[0], args[1], width, height)
+	},
+}
+
+func init() {
+	recordingsPlayCmd.Flags().Float64("speed", 1, "Playback speed, e.g. 2 for twice as fast")
+	recordingsPlayCmd.Flags().Duration("idle-limit", 0, "Longest pause between output, e.g. 2s (default: as recorded)")
+
+	recordingsCmd.AddCommand(recordingsListCmd)
+	recordingsCmd.AddCommand(recordingsPlayCmd)
+	recordingsCmd.AddCommand(recordingsEnableCmd)
+	recordingsCmd.AddCommand(recordingsDisableCmd)
+	recordingsCmd.AddCommand(recordingsCaptureCmd)
+	rootCmd.AddCommand(recordingsCmd)
+}
+
+// recordingsDir returns the configured recordings directory
+func recordingsDir(cfg *config.Config) (string, error) {
+	dir, err := cfg.Recording.Path()
+	if err != nil {
+		return "", fmt.Errorf("❌ Invalid recordings directory: %w", err)
+	}
+	return dir, nil
+}
+
+func runRecordingsListCommand(output io.Writer) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("❌ Failed to load configuration: %w", err)
+	}
+	dir, err := recordingsDir(cfg)
+	if err != nil {
+		return err
+	}
+	recordings, err := recording.List(dir)
+	if err != nil {
+		return fmt.Errorf("❌ %w", err)
+	}
+
+	if len(recordings) == 0 {
+		fmt.Fprintf(output, "%s\n", color.InfoMessage("No recordings in %s", dir))
+	} else {
+		w := tabwriter.NewWriter(output, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "RECORDING\tSTARTED\tDURATION\tSIZE\tTITLE")
+		for _, rec := range recordings {
+			title := rec.Title
+			if rec.Problem != "" {
+				title = "unreadable: " + rec.Problem
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", recordingName(rec.Path), rec.Started.Format("2006-01-02 15:04"), rec.Duration.Round(time.Second), recording.FormatSize(rec.Size), title)
+		}
+		w.Flush()
+	}
+
+	if servers := cfg.RecordedServers(); len(servers) > 0 {
+		fmt.Fprintf(output, "\n%s\n", color.InfoText("Recording new sessions of: %s", strings.Join(servers, ", ")))
+	} else {
+		fmt.Fprintf(output, "\n%s\n", color.InfoText("No server is recorded; use 'sshm recordings enable <server>'."))
+	}
+	return nil
+}
+
+// recordingName returns the name a recording is played by
+func recordingName(path string) string {
+	return strings.TrimSuffix(filepath.Base(path), recording.Extension)
+}
+
+func runRecordingsPlayCommand(name string, options recording.PlayOptions) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("❌ Failed to load configuration: %w", err)
+	}
+	dir, err := recordingsDir(cfg)
+	if err != nil {
+		return err
+	}
+	path, err := recording.Find(dir, name)
+	if err != nil {
+		return fmt.Errorf("❌ %w", err)
+	}
+	cast, err := recording.Load(path)
+	if err != nil {
+		return fmt.Errorf("❌ Failed to read recording: %w", err)
+	}
+	if err := recording.PlayTerminal(cast, options); err != nil {
+		return fmt.Errorf("❌ Playback failed: %w", err)
+	}
+	return nil
+}
+
+func runRecordingsToggleCommand(output io.Writer, serverName string, record bool) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("❌ Failed to load configuration: %w", err)
+	}
+
+	server, err := cfg.GetServer(serverName)
+	if err != nil {
+		return fmt.Errorf("❌ Server '%s' not found", serverName)
+	}
+	server.Record = record
+	if err := cfg.UpdateServer(*server); err != nil {
+		return fmt.Errorf("❌ %w", err)
+	}
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("❌ Failed to save configuration: %w", err)
+	}
+
+	if !record {
+		fmt.Fprintf(output, "%s\n", color.SuccessMessage("New sessions of %s are no longer recorded", server.Name))
+		return nil
+	}
+	dir, err := recordingsDir(cfg)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(output, "%s\n", color.SuccessMessage("New sessions of %s are recorded into %s", server.Name, dir))
+	return nil
+}
+
+func runRecordingsCaptureCommand(input io.Reader, sessionName, windowName string, width, height int) error {
+	// Without a readable configuration the default directory is used
+	cfg, err := config.Load()
+	if err != nil {
+		cfg = &config.Config{}
+	}
+	dir, err := cfg.Recording.Path()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+
+	started := time.Now()
+	title := sessionName
+	if windowName != "" && windowName != sessionName {
+		title += ":" + windowName
+	}
+	header := recording.Header{Width: width, Height: height, Timestamp: started.Unix(), Title: title}
+	if term := os.Getenv("TERM"); term != "" {
+		header.Env = map[string]string{"TERM": term}
+	}
+
+	// Recordings hold whatever was shown on screen, so only the user may read them
+	file, err := os.OpenFile(filepath.Join(dir, recording.FileName(sessionName, windowName, started)), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	recorder, err := recording.NewRecorder(file, header)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(recorder, input); err != nil {
+		recorder.Close()
+		return err
+	}
+	return recorder.Close()
+}
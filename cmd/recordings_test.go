@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"sshm/internal/recording"
+)
+
+func TestRecordingsCaptureAndList(t *testing.T) {
+	testDir := t.TempDir()
+	t.Setenv("SSHM_CONFIG_DIR", testDir)
+
+	if err := runRecordingsCaptureCommand(strings.NewReader("$ uptime\r\n"), "prod", "web1", 120, 40); err != nil {
+		t.Fatalf("runRecordingsCaptureCommand() error = %v", err)
+	}
+
+	dir := filepath.Join(testDir, "recordings")
+	recordings, err := recording.List(dir)
+	if err != nil || len(recordings) != 1 {
+		t.Fatalf("Expected one recording in %s, got %v, %v", dir, recordings, err)
+	}
+	if info, err := os.Stat(recordings[0].Path); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("Expected the recording to be private, got %v, %v", info.Mode(), err)
+	}
+	cast, err := recording.Load(recordings[0].Path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cast.Header.Title != "prod:web1" || cast.Header.Width != 120 || len(cast.Events) != 1 {
+		t.Errorf("Unexpected recording %+v", cast)
+	}
+
+	var output strings.Builder
+	if err := runRecordingsListCommand(&output); err != nil {
+		t.Fatalf("runRecordingsListCommand() error = %v", err)
+	}
+	if !strings.Contains(output.String(), recordingName(recordings[0].Path)) || !strings.Contains(output.String(), "prod:web1") {
+		t.Errorf("Expected the recording to be listed, got %q", output.String())
+	}
+}
@@ -10,6 +10,8 @@ import (
   "github.com/spf13/cobra"
   "sshm/internal/color"
   "sshm/internal/config"
+  "sshm/internal/service"
+  "sshm/internal/tmux"
 )
 
 var removeCmd = &cobra.Command{
@@ -19,16 +21,24 @@ var removeCmd = &cobra.Command{
 
 This command will:
   • Display the server details to be removed
+  • Show what else is affected: profiles losing the server (and deleted when
+    left empty), servers using it as a jump host, open sessions and tunnels
   • Ask for confirmation before deletion (unless --yes is used)
-  • Remove the server from ~/.sshm/config.yaml
+  • Remove the server from ~/.sshm/config.yaml and its profiles
   • Preserve other server configurations
 
 By default, you will be prompted to confirm the deletion. Use --yes to skip confirmation.
+With --json the outcome is printed as JSON and nothing is asked: a removal that
+needs confirming fails unless --yes is given too.
+With --archive the server is moved to the archive instead, keeping its settings
+and profile memberships for 'sshm archive restore'.
 
 Examples:
   sshm remove production-api      # Interactive confirmation
   sshm remove old-server --yes    # Non-interactive deletion
-  sshm remove test-server -y      # Short flag version`,
+  sshm remove test-server -y      # Short flag version
+  sshm remove old-db --archive    # Archive instead of deleting
+  sshm remove ci-runner -y --json # From a script`,
   Args: cobra.ExactArgs(1),
   RunE: func(cmd *cobra.Command, args []string) error {
     return runRemoveCommand(cmd, args, cmd.OutOrStdout())
@@ -36,24 +46,47 @@ Examples:
 }
 
 func runRemoveCommand(cmd *cobra.Command, args []string, output io.Writer) error {
-  serverName := args[0]
-  
+  // Check if --yes flag is provided for non-interactive mode
+  skipConfirmation, _ := cmd.Flags().GetBool("yes")
+  archive, _ := cmd.Flags().GetBool("archive")
+  asJSON, _ := cmd.Flags().GetBool("json")
+  return removeServer(output, os.Stdin, args[0], skipConfirmation, archive, asJSON)
+}
+
+// removalJSON is what 'sshm remove --json' prints
+type removalJSON struct {
+  Server          string   `json:"server"`
+  Archived        bool     `json:"archived"`
+  Profiles        []string `json:"profiles,omitempty"`         // Profiles the server was removed from
+  DeletedProfiles []string `json:"deleted_profiles,omitempty"` // Profiles left empty and deleted
+  JumpDependents  []string `json:"jump_dependents,omitempty"`  // Servers that used it as a jump host
+  Sessions        []string `json:"sessions,omitempty"`         // Open sessions left running
+}
+
+// removeServer removes or archives a server after showing what the removal
+// affects and, unless skipConfirmation is set, asking for confirmation on input
+func removeServer(output io.Writer, input io.Reader, serverName string, skipConfirmation, archive, asJSON bool) error {
   // Load existing configuration
-  cfg, err := config.Load()
+  svc, err := service.Load()
   if err != nil {
     return fmt.Errorf("❌ Failed to load configuration: %w", err)
   }
+  cfg := svc.Config()
 
   // Check if server exists
-  server, err := cfg.GetServer(serverName)
+  impact, err := cfg.RemovalImpact(serverName)
   if err != nil {
     return fmt.Errorf("❌ Server '%s' not found. Use 'sshm list' to see available servers", serverName)
   }
+  server := impact.Server
+  serverName = server.Name
+  impact.Sessions = openSessionsOf(cfg, serverName)
 
-  // Check if --yes flag is provided for non-interactive mode
-  skipConfirmation, _ := cmd.Flags().GetBool("yes")
-  
-  if !skipConfirmation {
+  // Removals that affect more than the server itself are always confirmed
+  if !skipConfirmation && (impact.HasEffects() || cfg.UI.ShouldConfirm(config.ConfirmDeleteServer, server.Protected)) {
+    if asJSON {
+      return errConfirmationRequired
+    }
     // Display server details and confirmation prompt
     fmt.Fprintf(output, "%s\n", color.InfoMessage("Server to remove:"))
     fmt.Fprintf(output, "   Name: %s\n", server.Name)
@@ -65,10 +98,23 @@ func runRemoveCommand(cmd *cobra.Command, args []string, output io.Writer) error
     }
     fmt.Fprintf(output, "\n")
 
+    if summary := impact.Summary(); len(summary) > 0 {
+      fmt.Fprintf(output, "%s\n", color.WarningMessage("This also affects:"))
+      for _, line := range summary {
+        fmt.Fprintf(output, "   • %s\n", line)
+      }
+    } else {
+      fmt.Fprintf(output, "%s\n", color.InfoText("Nothing else refers to this server."))
+    }
+    if archive {
+      fmt.Fprintf(output, "%s\n", color.InfoText("The server is moved to the archive; 'sshm archive restore %s' brings it back.", server.Name))
+    }
+    fmt.Fprintf(output, "\n")
+
     // Confirmation prompt
     fmt.Fprint(output, "Are you sure you want to remove this server? (y/n): ")
     
-    scanner := bufio.NewScanner(os.Stdin)
+    scanner := bufio.NewScanner(input)
     if !scanner.Scan() {
       return fmt.Errorf("failed to read confirmation")
     }
@@ -80,21 +126,43 @@ func runRemoveCommand(cmd *cobra.Command, args []string, output io.Writer) error
     }
   }
 
-  // Remove server from configuration
-  if err := cfg.RemoveServer(serverName); err != nil {
+  // Remove server from configuration and save it
+  if err := svc.RemoveServer(serverName, archive); err != nil {
     return fmt.Errorf("❌ Failed to remove server: %w", err)
   }
 
-  // Save configuration
-  if err := cfg.Save(); err != nil {
-    return fmt.Errorf("❌ Failed to save configuration: %w", err)
+  if asJSON {
+    result := removalJSON{Server: serverName, Archived: archive, Profiles: impact.Profiles, JumpDependents: impact.JumpDependents, Sessions: impact.Sessions}
+    if !archive {
+      result.DeletedProfiles = impact.EmptiedProfiles
+    }
+    return writeJSON(output, result)
+  }
+  if archive {
+    fmt.Fprintf(output, "%s\n", color.SuccessMessage("Server '%s' moved to the archive", serverName))
+    return nil
   }
-
   fmt.Fprintf(output, "%s\n", color.SuccessMessage("Server '%s' removed successfully!", serverName))
   return nil
 }
 
+// openSessionsOf returns the tmux sessions open to the server; none when tmux
+// isn't running
+func openSessionsOf(cfg *config.Config, serverName string) []string {
+  sessions, err := tmux.NewManager().ListSessions()
+  if err != nil {
+    return nil
+  }
+  var names []string
+  for _, server := range cfg.GetServers() {
+    names = append(names, server.Name)
+  }
+  return tmux.SessionsOfServer(sessions, serverName, names)
+}
+
 func init() {
   // Add flags for remove command
   removeCmd.Flags().BoolP("yes", "y", false, "Skip confirmation prompt and remove server")
+  removeCmd.Flags().Bool("archive", false, "Move the server to the archive instead of deleting it")
+  removeCmd.Flags().Bool("json", false, "Print the outcome as JSON; never prompts")
 }
\ No newline at end of file
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"sshm/internal/config"
+)
+
+func TestRemoveServerShowsImpact(t *testing.T) {
+	testDir := t.TempDir()
+	t.Setenv("SSHM_CONFIG_DIR", testDir)
+	cfg := &config.Config{
+		Servers: []config.Server{
+			{Name: "bastion", Hostname: "bastion.example.com", Port: 22, Username: "ops", AuthType: "key", KeyPath: "~/.ssh/id_ed25519"},
+			{Name: "db1", Hostname: "db1.internal", Port: 22, Username: "ops", AuthType: "key", KeyPath: "~/.ssh/id_ed25519", ProxyJump: []string{"bastion"}},
+		},
+		Profiles: []config.Profile{{Name: "prod", Servers: []string{"bastion", "db1"}}},
+	}
+	if err := cfg.SaveToPath(filepath.Join(testDir, "config.yaml")); err != nil {
+		t.Fatal(err)
+	}
+
+	var output strings.Builder
+	if err := removeServer(&output, strings.NewReader("n\n"), "bastion", false, false, false); err != nil {
+		t.Fatalf("removeServer() error = %v", err)
+	}
+	for _, want := range []string{"Removed from profiles: prod", "Jump host of: db1", "Removal cancelled"} {
+		if !strings.Contains(output.String(), want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, output.String())
+		}
+	}
+
+	output.Reset()
+	if err := removeServer(&output, strings.NewReader("y\n"), "bastion", false, true, false); err != nil {
+		t.Fatalf("removeServer() error = %v", err)
+	}
+	if !strings.Contains(output.String(), "moved to the archive") {
+		t.Errorf("Expected the server to be archived, got:\n%s", output.String())
+	}
+	loaded, err := config.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loaded.GetServer("bastion"); err == nil {
+		t.Error("Expected bastion to leave the inventory")
+	}
+	if len(loaded.Archived) != 1 || loaded.Archived[0].Name != "bastion" || !loaded.Archived[0].Removed {
+		t.Errorf("Expected bastion in the archive, got %+v", loaded.Archived)
+	}
+}
@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"sshm/internal/color"
+	"sshm/internal/config"
+	"sshm/internal/tmux"
+)
+
+var renameCmd = &cobra.Command{
+	Use:   "rename [server-name...]",
+	Short: "Rename servers in bulk with a find/replace or a template",
+	Long: `Rename the named servers, or those of a profile, in one operation. --find
+and --replace substitute text in each name (a regular expression with
+--regexp), then --template puts the result in place of {name}.
+
+The new names are listed and confirmed before anything is changed. Nothing is
+renamed when a new name clashes with another server's name or alias, or when
+two servers would get the same name. Profiles, jump host references and open
+tmux sessions follow the renamed servers.
+
+Examples:
+  sshm rename --profile aws --template "aws-{name}"     # Add a prefix
+  sshm rename web1 web2 --find .example.com             # Strip a domain suffix
+  sshm rename -p prod --find '^(\w+)\..*$' --replace '$1' --regexp
+  sshm rename --profile aws --template "aws-{name}" --dry-run`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		profile, _ := cmd.Flags().GetString("profile")
+		find, _ := cmd.Flags().GetString("find")
+		replace, _ := cmd.Flags().GetString("replace")
+		useRegexp, _ := cmd.Flags().GetBool("regexp")
+		template, _ := cmd.Flags().GetString("template")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		yes, _ := cmd.Flags().GetBool("yes")
+		rename := config.BatchRename{Find: find, Replace: replace, Regexp: useRegexp, Template: template}
+		return runRenameCommand(cmd.OutOrStdout(), os.Stdin, args, profile, rename, dryRun, yes)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(renameCmd)
+
+	renameCmd.Flags().StringP("profile", "p", "", "Rename the servers of this profile")
+	renameCmd.Flags().String("find", "", "Text to replace in each name")
+	renameCmd.Flags().String("replace", "", "Replacement for the found text ($1 refers to a group with --regexp)")
+	renameCmd.Flags().Bool("regexp", false, "Treat --find as a regular expression")
+	renameCmd.Flags().String("template", "", "New name with a {name} placeholder, e.g. aws-{name}")
+	renameCmd.Flags().Bool("dry-run", false, "Only show the new names")
+	renameCmd.Flags().BoolP("yes", "y", false, "Skip the confirmation prompt")
+}
+
+func runRenameCommand(output io.Writer, input io.Reader, names []string, profileName string, rename config.BatchRename, dryRun, yes bool) error {
+	if len(names) == 0 && profileName == "" {
+		return fmt.Errorf("❌ Name the servers to rename or use --profile")
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("❌ Failed to load configuration: %w", err)
+	}
+	servers, err := hostkeyServers(cfg, names, profileName)
+	if err != nil {
+		return err
+	}
+	selected := make([]string, len(servers))
+	for i, server := range servers {
+		selected[i] = server.Name
+	}
+	var before []string
+	for _, server := range cfg.GetServers() {
+		before = append(before, server.Name)
+	}
+
+	plan, err := cfg.PlanBatchRename(selected, rename)
+	if err != nil {
+		return fmt.Errorf("❌ %w", err)
+	}
+	printRenamePlan(output, plan)
+	if len(plan.Collisions) > 0 {
+		return fmt.Errorf("❌ Nothing was renamed: %d new name(s) collide", len(plan.Collisions))
+	}
+	if len(plan.Renames) == 0 {
+		fmt.Fprintf(output, "%s\n", color.SuccessMessage("No server names change"))
+		return nil
+	}
+	if dryRun {
+		return nil
+	}
+
+	if !yes {
+		fmt.Fprintf(output, "%s", color.WarningMessage("Rename %d server(s)? (y/N): ", len(plan.Renames)))
+		scanner := bufio.NewScanner(input)
+		scanner.Scan()
+		if answer := strings.ToLower(strings.TrimSpace(scanner.Text())); answer != "y" && answer != "yes" {
+			fmt.Fprintf(output, "%s\n", color.InfoMessage("Rename cancelled, nothing was changed"))
+			return nil
+		}
+	}
+
+	if _, err := cfg.ApplyBatchRename(selected, rename); err != nil {
+		return fmt.Errorf("❌ %w", err)
+	}
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("❌ Failed to save configuration: %w", err)
+	}
+	fmt.Fprintf(output, "%s\n", color.SuccessMessage("Renamed %d server(s)", len(plan.Renames)))
+	renameServerSessions(output, plan, before)
+	return nil
+}
+
+// printRenamePlan lists the new names of a batch rename and its collisions
+func printRenamePlan(output io.Writer, plan config.RenamePlan) {
+	fmt.Fprintf(output, "%s\n", color.Header("New names"))
+	for _, rename := range plan.Renames {
+		fmt.Fprintf(output, "  %s → %s\n", rename.From, rename.To)
+	}
+	if len(plan.Unchanged) > 0 {
+		fmt.Fprintf(output, "%s\n", color.InfoText("  Unchanged: %s", strings.Join(plan.Unchanged, ", ")))
+	}
+	for _, collision := range plan.Collisions {
+		fmt.Fprintf(output, "%s\n", color.ErrorMessage("%s", collision))
+	}
+}
+
+// renameServerSessions renames the tmux sessions of the renamed servers; the
+// configuration is already saved, so failures are only reported
+func renameServerSessions(output io.Writer, plan config.RenamePlan, serverNames []string) {
+	tmuxManager := tmux.NewManager()
+	if !tmuxManager.IsAvailable() {
+		return
+	}
+	renames := make(map[string]string, len(plan.Renames))
+	for _, rename := range plan.Renames {
+		renames[rename.From] = rename.To
+	}
+	renamed, err := tmuxManager.RenameServerSessions(renames, serverNames)
+	if len(renamed) > 0 {
+		fmt.Fprintf(output, "%s\n", color.InfoMessage("Renamed %d open session(s) to match", len(renamed)))
+	}
+	if err != nil {
+		fmt.Fprintf(output, "%s\n", color.WarningMessage("Sessions not renamed: %v", err))
+	}
+}
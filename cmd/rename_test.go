@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"sshm/internal/config"
+	"sshm/internal/tmux"
+)
+
+func TestRenameCommand(t *testing.T) {
+	testDir := t.TempDir()
+	t.Setenv("SSHM_CONFIG_DIR", testDir)
+	original := tmux.GetExecCommand()
+	defer tmux.SetExecCommand(original)
+	tmux.SetExecCommand(func(name string, arg ...string) *exec.Cmd {
+		return exec.Command("false")
+	})
+
+	cfg := &config.Config{
+		Servers: []config.Server{
+			{Name: "web1.example.com", Hostname: "10.0.0.1", Port: 22, Username: "ops", AuthType: "key", KeyPath: "~/.ssh/id_ed25519"},
+			{Name: "web2.example.com", Hostname: "10.0.0.2", Port: 22, Username: "ops", AuthType: "key", KeyPath: "~/.ssh/id_ed25519"},
+			{Name: "web1", Hostname: "10.0.0.3", Port: 22, Username: "ops", AuthType: "key", KeyPath: "~/.ssh/id_ed25519"},
+		},
+		Profiles: []config.Profile{{Name: "aws", Servers: []string{"web1.example.com", "web2.example.com"}}},
+	}
+	if err := cfg.SaveToPath(filepath.Join(testDir, "config.yaml")); err != nil {
+		t.Fatal(err)
+	}
+
+	var output strings.Builder
+	strip := config.BatchRename{Find: ".example.com"}
+	if err := runRenameCommand(&output, strings.NewReader(""), nil, "aws", strip, false, true); err == nil {
+		t.Fatal("Expected the collision with web1 to refuse the rename")
+	}
+	if !strings.Contains(output.String(), "web1.example.com would be named web1 like web1") {
+		t.Errorf("Expected the collision to be listed, got:\n%s", output.String())
+	}
+
+	output.Reset()
+	prefix := config.BatchRename{Find: ".example.com", Template: "aws-{name}"}
+	if err := runRenameCommand(&output, strings.NewReader("n\n"), nil, "aws", prefix, false, false); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(output.String(), "web2.example.com → aws-web2") || !strings.Contains(output.String(), "Rename cancelled") {
+		t.Errorf("Expected a preview and a cancelled rename, got:\n%s", output.String())
+	}
+
+	output.Reset()
+	if err := runRenameCommand(&output, strings.NewReader("y\n"), nil, "aws", prefix, false, false); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := config.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	profile, _ := loaded.GetProfile("aws")
+	if strings.Join(profile.Servers, ",") != "aws-web1,aws-web2" {
+		t.Errorf("Expected the profile to reference the new names, got %v", profile.Servers)
+	}
+	if _, err := loaded.GetServer("web1"); err != nil {
+		t.Error("Expected web1 outside the profile to keep its name")
+	}
+}
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"sshm/internal/color"
+	"sshm/internal/config"
+)
+
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Summarize servers by owner, team, cost center and environment",
+	Long: `Count servers per owner, team, cost center and environment for inventory
+governance. Servers without a value are reported as "(unset)" so gaps in the
+metadata are easy to spot.
+
+Metadata is set with 'sshm add --owner/--team/--cost-center/--environment'
+or in the TUI server form.
+
+Examples:
+  sshm report
+  sshm report --by owner --by env
+  sshm report --profile production --json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fields, _ := cmd.Flags().GetStringSlice("by")
+		profileName, _ := cmd.Flags().GetString("profile")
+		asJSON, _ := cmd.Flags().GetBool("json")
+		return runReportCommand(cmd.OutOrStdout(), fields, profileName, asJSON)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(reportCmd)
+
+	reportCmd.Flags().StringSlice("by", nil, "Metadata field to group by: owner, team, cost-center or environment (repeatable, default all)")
+	reportCmd.Flags().StringP("profile", "p", "", "Only report servers in this profile")
+	reportCmd.Flags().Bool("json", false, "Output the report as JSON")
+}
+
+// metadataReport is the JSON form of 'sshm report', keyed by metadata field
+type metadataReport map[string][]config.MetadataCount
+
+func runReportCommand(output io.Writer, fields []string, profileName string, asJSON bool) error {
+	if len(fields) == 0 {
+		fields = config.MetadataFields
+	}
+	var resolved []string
+	for _, field := range fields {
+		name, err := config.NormalizeMetadataField(field)
+		if err != nil {
+			return fmt.Errorf("❌ %w", err)
+		}
+		resolved = append(resolved, name)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("❌ Failed to load configuration: %w", err)
+	}
+
+	servers := cfg.GetServers()
+	if profileName != "" {
+		servers, err = cfg.GetServersByProfile(profileName)
+		if err != nil {
+			return fmt.Errorf("❌ Profile '%s' not found", profileName)
+		}
+	}
+
+	report := make(metadataReport)
+	for _, field := range resolved {
+		report[field] = config.CountByMetadata(servers, field)
+	}
+
+	if asJSON {
+		encoder := json.NewEncoder(output)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	}
+
+	if len(servers) == 0 {
+		fmt.Fprintf(output, "%s\n", color.InfoMessage("No servers to report"))
+		return nil
+	}
+
+	for i, field := range resolved {
+		if i > 0 {
+			fmt.Fprintln(output)
+		}
+		fmt.Fprintf(output, "%s\n", color.InfoMessage("By %s:", strings.ReplaceAll(field, "_", " ")))
+		w := tabwriter.NewWriter(output, 0, 0, 2, ' ', 0)
+		for _, count := range report[field] {
+			value := count.Value
+			if value == "" {
+				value = "(unset)"
+			}
+			fmt.Fprintf(w, "  %s\t%d\t%s\n", value, count.Count, strings.Join(count.Servers, ", "))
+		}
+		w.Flush()
+	}
+	fmt.Fprintf(output, "\n%s\n", color.InfoText("%d server(s) reported", len(servers)))
+	return nil
+}
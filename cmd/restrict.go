@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"sshm/internal/color"
+	"sshm/internal/config"
+)
+
+var restrictCmd = &cobra.Command{
+	Use:   "restrict <server-name>",
+	Short: "Limit a server to a forced command instead of a shell",
+	Long: `Restrict connections to a server to a forced command, such as a menu
+script, for operators who should not get a full shell. Connections from the
+CLI and the TUI run the command with port forwarding disabled, and sshm
+refuses to run power actions or update audits on the server.
+
+The placeholders {user}, {host} and {server} in commands are replaced with
+the server's username, address and name. Additional commands allowed with
+--allow can be chosen with 'sshm connect <server> --command <cmd>'.
+
+Without flags the current restriction is shown.
+
+Examples:
+  sshm restrict noc-gw --command "/usr/local/bin/noc-menu {user}"
+  sshm restrict noc-gw --command "noc-menu" --allow "tail -f /var/log/syslog"
+  sshm restrict noc-gw --off`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		command, _ := cmd.Flags().GetString("command")
+		allowed, _ := cmd.Flags().GetStringArray("allow")
+		off, _ := cmd.Flags().GetBool("off")
+		return runRestrictCommand(cmd.OutOrStdout(), args[0], command, allowed, off)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(restrictCmd)
+
+	restrictCmd.Flags().String("command", "", "Forced command run instead of a shell")
+	restrictCmd.Flags().StringArray("allow", nil, "Additional allowed command (repeatable)")
+	restrictCmd.Flags().Bool("off", false, "Remove the restriction")
+}
+
+func runRestrictCommand(output io.Writer, serverName, command string, allowed []string, off bool) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("❌ Failed to load configuration: %w", err)
+	}
+
+	server, err := cfg.GetServer(serverName)
+	if err != nil {
+		return fmt.Errorf("❌ Server '%s' not found", serverName)
+	}
+
+	switch {
+	case off && (command != "" || len(allowed) > 0):
+		return fmt.Errorf("❌ --off cannot be combined with --command or --allow")
+	case off:
+		server.Restricted = nil
+	case command != "":
+		server.Restricted = &config.RestrictedAccess{Command: command, Allowed: allowed}
+	case len(allowed) > 0:
+		if server.Restricted == nil {
+			return fmt.Errorf("❌ Set a forced command with --command before allowing others")
+		}
+		server.Restricted.Allowed = append(server.Restricted.Allowed, allowed...)
+	default:
+		if server.Restricted == nil {
+			fmt.Fprintf(output, "%s\n", color.InfoMessage("%s is not restricted", server.Name))
+			return nil
+		}
+		printRestriction(output, server)
+		return nil
+	}
+
+	if err := cfg.UpdateServer(*server); err != nil {
+		return fmt.Errorf("❌ %w", err)
+	}
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("❌ Failed to save configuration: %w", err)
+	}
+
+	if server.Restricted == nil {
+		fmt.Fprintf(output, "%s\n", color.SuccessMessage("Removed the restriction from %s", server.Name))
+		return nil
+	}
+	fmt.Fprintf(output, "%s\n", color.SuccessMessage("%s is restricted", server.Name))
+	printRestriction(output, server)
+	return nil
+}
+
+// printRestriction lists the forced command and the allowed alternatives
+func printRestriction(output io.Writer, server *config.Server) {
+	fmt.Fprintf(output, "%s\n", color.InfoText("  Forced command: %s", server.Restricted.Command))
+	for _, command := range server.Restricted.Allowed {
+		fmt.Fprintf(output, "%s\n", color.InfoText("  Allowed:        %s", command))
+	}
+}
@@ -7,6 +7,8 @@ import (
 
   "github.com/spf13/cobra"
   "sshm/internal/color"
+  "sshm/internal/config"
+  "sshm/internal/connection"
 )
 
 var rootCmd = &cobra.Command{
@@ -38,6 +40,13 @@ Examples:
 }
 
 func Execute() {
+  // Sessions run this binary to check pastes into protected servers and to record
+  if executable, err := os.Executable(); err == nil {
+    connection.SetExecutable(executable)
+  }
+  // Encrypted configurations are unlocked from the environment, the keyring or a prompt
+  config.SetPassphraseFunc(configPassphrase(true))
+
   if err := rootCmd.Execute(); err != nil {
     fmt.Println(err)
     os.Exit(1)
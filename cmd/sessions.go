@@ -1,13 +1,20 @@
 package cmd
 
 import (
+  "context"
+  "errors"
   "fmt"
   "io"
+  "strings"
   "text/tabwriter"
 
   "github.com/spf13/cobra"
   "sshm/internal/color"
+  "sshm/internal/config"
+  "sshm/internal/connection"
+  "sshm/internal/service"
   "sshm/internal/tmux"
+  "sshm/internal/tui"
 )
 
 var sessionsCmd = &cobra.Command{
@@ -21,7 +28,12 @@ This command helps manage session resources and clean up orphaned sessions.
 Examples:
   sshm sessions list               # List all active tmux sessions
   sshm sessions kill <session>    # Kill a specific session
-  sshm sessions cleanup           # Remove orphaned sshm sessions`,
+  sshm sessions repair <session>  # Reconnect windows whose SSH connection died
+  sshm sessions share <session>   # Invite a teammate to join a session
+  sshm sessions cleanup           # Remove orphaned sshm sessions
+
+The list, kill and cleanup subcommands take --json to print their result as
+JSON for scripts.`,
 }
 
 var sessionsListCmd = &cobra.Command{
@@ -32,6 +44,9 @@ var sessionsListCmd = &cobra.Command{
 Shows both individual server sessions and group profile sessions
 created by sshm, helping you identify active connections and their status.`,
   RunE: func(cmd *cobra.Command, args []string) error {
+    if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+      return runSessionsListJSON(cmd.OutOrStdout())
+    }
     return runSessionsListCommand(cmd.OutOrStdout())
   },
 }
@@ -50,30 +65,171 @@ Examples:
   Args: cobra.ExactArgs(1),
   RunE: func(cmd *cobra.Command, args []string) error {
     sessionName := args[0]
+    if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+      if err := killSession(tmux.NewManager(), sessionName); err != nil {
+        return err
+      }
+      return writeJSON(cmd.OutOrStdout(), map[string]interface{}{"session": sessionName, "killed": true})
+    }
     return runSessionsKillCommand(sessionName, cmd.OutOrStdout())
   },
 }
 
+var sessionsRepairCmd = &cobra.Command{
+  Use:   "repair <session-name>",
+  Short: "Reconnect the windows of a session whose SSH connection died",
+  Long: `Relaunch the SSH connection of every window in a session whose connection
+has exited or never succeeded, without recreating the whole session. Windows
+that are still connected are left alone.
+
+A window is considered disconnected when its pane is dead or back at a local
+shell. Windows of group sessions are matched to servers by name.
+
+Examples:
+  sshm sessions repair production     # Repair the group session of the production profile
+  sshm sessions repair web-01         # Reconnect a single-server session`,
+  Args: cobra.ExactArgs(1),
+  RunE: func(cmd *cobra.Command, args []string) error {
+    return runSessionsRepairCommand(args[0], cmd.OutOrStdout())
+  },
+}
+
+var sessionsShareCmd = &cobra.Command{
+  Use:   "share <session-name>",
+  Short: "Invite a teammate to join a session for pair debugging",
+  Long: `Print the commands a teammate runs to join a session, and open the tmux
+socket to them.
+
+A teammate with an account on this host joins through the tmux socket
+(tmux -S <socket> attach). --user grants that account access to the tmux
+server (tmux 3.3 or later) and --group limits the socket to a group; with
+either, the socket permissions are changed. tmux older than 3.3 can't limit
+who joins, so --user needs --group there. Sharing applies to every session
+of the tmux server. tmux's default socket directory is private to you, so local
+invitations need sshm started with TMUX_TMPDIR set to a shared directory.
+
+A teammate elsewhere joins over ssh, logging in as you, which needs their
+public key in your ~/.ssh/authorized_keys.
+
+Examples:
+  sshm sessions share web-01                         # Print the ssh invitation
+  sshm sessions share web-01 --user alice            # Let alice join locally too
+  sshm sessions share web-01 --group oncall --read-only
+  sshm sessions unshare web-01 --user alice          # Make the socket private again`,
+  Args: cobra.ExactArgs(1),
+  RunE: func(cmd *cobra.Command, args []string) error {
+    options := tmux.ShareOptions{}
+    options.User, _ = cmd.Flags().GetString("user")
+    options.Group, _ = cmd.Flags().GetString("group")
+    options.ReadOnly, _ = cmd.Flags().GetBool("read-only")
+    options.Host, _ = cmd.Flags().GetString("host")
+    options.Login, _ = cmd.Flags().GetString("login")
+    return runSessionsShareCommand(tmux.NewManager(), args[0], options, cmd.OutOrStdout())
+  },
+}
+
+var sessionsUnshareCmd = &cobra.Command{
+  Use:   "unshare <session-name>",
+  Short: "Make a shared session's tmux socket private again",
+  Args:  cobra.ExactArgs(1),
+  RunE: func(cmd *cobra.Command, args []string) error {
+    userName, _ := cmd.Flags().GetString("user")
+    return runSessionsUnshareCommand(tmux.NewManager(), args[0], userName, cmd.OutOrStdout())
+  },
+}
+
+// sessionsNotifyCmd is run by the tmux hooks the TUI installs to tell it the
+// session list changed. It never prints anything since tmux would show it.
+var sessionsNotifyCmd = &cobra.Command{
+  Use:    "notify <socket>",
+  Short:  "Notify a running TUI that tmux sessions changed",
+  Hidden: true,
+  Args:   cobra.ExactArgs(1),
+  RunE: func(cmd *cobra.Command, args []string) error {
+    // The TUI may have exited since the hook was installed
+    _ = tui.NotifySessionChange(args[0])
+    return nil
+  },
+}
+
+// sessionsPasteGuardCmd is run by the paste key of sessions holding a protected
+// server. Small pastes go straight through; larger ones ask on the client first.
+var sessionsPasteGuardCmd = &cobra.Command{
+  Use:          "paste-guard <session> <pane> <client>",
+  Short:        "Paste into a protected server's session, confirming large pastes",
+  Hidden:       true,
+  SilenceUsage: true, // tmux shows the output of run-shell
+  Args:         cobra.ExactArgs(3),
+  RunE: func(cmd *cobra.Command, args []string) error {
+    return runSessionsPasteGuardCommand(tmux.NewManager(), args[0], args[1], args[2])
+  },
+}
+
 var sessionsCleanupCmd = &cobra.Command{
   Use:   "cleanup",
   Short: "Clean up orphaned tmux sessions",
   Long: `Clean up orphaned tmux sessions that may be left running.
   
-This command will identify and optionally remove tmux sessions that
-appear to be orphaned or no longer needed. Use with caution as this
-will terminate active SSH connections.`,
+A session is orphaned when it has no windows left, or nobody is attached
+and it has been idle for two days - the same sessions the TUI cleans up.
+Without --force the sessions are only listed. Use --all to clean up every
+session instead; use with caution as this will terminate active SSH
+connections.
+
+Examples:
+  sshm sessions cleanup                  # List orphaned sessions
+  sshm sessions cleanup --force          # Kill orphaned sessions
+  sshm sessions cleanup --all --force    # Kill every session
+  sshm sessions cleanup --force --json   # Kill orphaned sessions and print the result as JSON`,
   RunE: func(cmd *cobra.Command, args []string) error {
     force, _ := cmd.Flags().GetBool("force")
-    return runSessionsCleanupCommand(force, cmd.OutOrStdout())
+    all, _ := cmd.Flags().GetBool("all")
+    asJSON, _ := cmd.Flags().GetBool("json")
+    return runSessionsCleanupCommand(cmd.Context(), force, all, asJSON, cmd.OutOrStdout())
   },
 }
 
 func init() {
   sessionsCleanupCmd.Flags().BoolP("force", "f", false, "Force cleanup without confirmation")
+  sessionsCleanupCmd.Flags().Bool("all", false, "Clean up every session, not only orphaned ones")
+  for _, command := range []*cobra.Command{sessionsListCmd, sessionsKillCmd, sessionsCleanupCmd} {
+    command.Flags().Bool("json", false, "Print the result as JSON")
+  }
+  sessionsShareCmd.Flags().String("user", "", "Local account allowed to join through the tmux socket")
+  sessionsShareCmd.Flags().String("group", "", "Group given access to the tmux socket")
+  sessionsShareCmd.Flags().Bool("read-only", false, "Teammates can watch but not type")
+  sessionsShareCmd.Flags().String("host", "", "Address teammates ssh to (default: this host's name)")
+  sessionsShareCmd.Flags().String("login", "", "Account teammates log in as over ssh (default: you)")
+  sessionsUnshareCmd.Flags().String("user", "", "Local account whose access is revoked")
   
   sessionsCmd.AddCommand(sessionsListCmd)
   sessionsCmd.AddCommand(sessionsKillCmd)
+  sessionsCmd.AddCommand(sessionsRepairCmd)
+  sessionsCmd.AddCommand(sessionsShareCmd)
+  sessionsCmd.AddCommand(sessionsUnshareCmd)
   sessionsCmd.AddCommand(sessionsCleanupCmd)
+  sessionsCmd.AddCommand(sessionsNotifyCmd)
+  sessionsCmd.AddCommand(sessionsPasteGuardCmd)
+}
+
+func runSessionsPasteGuardCommand(tmuxManager *tmux.Manager, sessionName, paneID, clientName string) error {
+  text, err := tmuxManager.BufferContents()
+  if err != nil {
+    // No buffer, nothing to paste
+    return nil
+  }
+
+  // Without a readable configuration the default limits still protect the session
+  guard := config.PasteGuardConfig{}
+  if cfg, err := config.Load(); err == nil {
+    guard = cfg.PasteGuard
+  }
+  if !guard.NeedsConfirmation(text) {
+    return tmuxManager.PasteBuffer(paneID)
+  }
+
+  prompt := fmt.Sprintf("Paste %d lines (%d bytes) into protected session %s? (y/n)", config.PasteLines(text), len(text), sessionName)
+  return tmuxManager.ConfirmPaste(clientName, paneID, prompt)
 }
 
 func runSessionsListCommand(output io.Writer) error {
@@ -102,62 +258,141 @@ func runSessionsListCommand(output io.Writer) error {
   fmt.Fprintln(w, "SESSION NAME\tTYPE\tSTATUS")
   fmt.Fprintln(w, "------------\t----\t------")
 
+  lostCount := 0
   for _, sessionName := range sessions {
     sessionType := "Individual"
     if isGroupSession(sessionName) {
       sessionType = "Group"
     }
     
-    fmt.Fprintf(w, "%s\t%s\tActive\n", sessionName, sessionType)
+    status := "Active"
+    if lost, err := tmuxManager.LostWindows(sessionName); err == nil && len(lost) > 0 {
+      status = "Connection lost"
+      lostCount++
+    }
+    
+    fmt.Fprintf(w, "%s\t%s\t%s\n", sessionName, sessionType, status)
   }
 
   w.Flush()
   
   fmt.Fprintf(output, "\n%s\n", color.InfoMessage("Active sessions: %d", len(sessions)))
+  if lostCount > 0 {
+    fmt.Fprintf(output, "%s\n", color.WarningMessage("%d session(s) lost their SSH connection; use 'sshm sessions repair <session-name>' to reconnect", lostCount))
+  }
   fmt.Fprintf(output, "%s\n", color.InfoText("Use 'sshm sessions kill <session-name>' to terminate a session"))
   fmt.Fprintf(output, "%s\n", color.InfoText("Use 'tmux attach-session -t <session-name>' to attach to a session"))
   return nil
 }
 
-func runSessionsKillCommand(sessionName string, output io.Writer) error {
-  // Initialize tmux manager
-  tmuxManager := tmux.NewManager()
+// sessionJSON is a session as 'sshm sessions list --json' prints it
+type sessionJSON struct {
+  Name   string `json:"name"`
+  Type   string `json:"type"`
+  Status string `json:"status"`
+}
 
-  // Check if tmux is available
+func runSessionsListJSON(output io.Writer) error {
+  tmuxManager := tmux.NewManager()
   if !tmuxManager.IsAvailable() {
-    return fmt.Errorf("❌ tmux is not available on this system")
+    return fmt.Errorf("❌ tmux is not available on this system. Please install tmux to use session management")
   }
 
-  // Check if session exists
   sessions, err := tmuxManager.ListSessions()
   if err != nil {
     return fmt.Errorf("❌ Failed to list tmux sessions: %w", err)
   }
 
-  sessionExists := false
-  for _, session := range sessions {
-    if session == sessionName {
-      sessionExists = true
-      break
+  printed := make([]sessionJSON, 0, len(sessions))
+  for _, sessionName := range sessions {
+    session := sessionJSON{Name: sessionName, Type: "individual", Status: "active"}
+    if isGroupSession(sessionName) {
+      session.Type = "group"
     }
+    if lost, err := tmuxManager.LostWindows(sessionName); err == nil && len(lost) > 0 {
+      session.Status = "connection lost"
+    }
+    printed = append(printed, session)
   }
+  return writeJSON(output, printed)
+}
 
-  if !sessionExists {
-    return fmt.Errorf("❌ Session '%s' not found", sessionName)
-  }
+func runSessionsKillCommand(sessionName string, output io.Writer) error {
+  // Initialize tmux manager
+  tmuxManager := tmux.NewManager()
 
   // Kill the session
   fmt.Fprintf(output, "%s\n", color.InfoMessage("Killing tmux session '%s'...", sessionName))
-  err = tmuxManager.KillSession(sessionName)
+  if err := killSession(tmuxManager, sessionName); err != nil {
+    return err
+  }
+
+  fmt.Fprintf(output, "%s\n", color.SuccessMessage("Session '%s' terminated successfully", sessionName))
+  return nil
+}
+
+// killSession kills a session the way the TUI does, after checking tmux is there
+func killSession(tmuxManager *tmux.Manager, sessionName string) error {
+  if !tmuxManager.IsAvailable() {
+    return fmt.Errorf("❌ tmux is not available on this system")
+  }
+
+  err := service.KillSession(tmuxManager, sessionName)
+  if errors.Is(err, service.ErrSessionNotFound) {
+    return fmt.Errorf("❌ Session '%s' not found", sessionName)
+  }
   if err != nil {
     return fmt.Errorf("❌ Failed to kill session '%s': %w", sessionName, err)
   }
+  return nil
+}
+
+func runSessionsShareCommand(tmuxManager *tmux.Manager, sessionName string, options tmux.ShareOptions, output io.Writer) error {
+  if !tmuxManager.IsAvailable() {
+    return fmt.Errorf("❌ tmux is not available on this system")
+  }
 
-  fmt.Fprintf(output, "%s\n", color.SuccessMessage("Session '%s' terminated successfully", sessionName))
+  invitation, err := tmuxManager.ShareSession(sessionName, options)
+  if err != nil {
+    return fmt.Errorf("❌ Failed to share session '%s': %w", sessionName, err)
+  }
+
+  fmt.Fprintf(output, "%s\n", color.SuccessMessage("Session '%s' is ready to share", sessionName))
+  fmt.Fprintf(output, "\n%s\n", color.Header("From an account on this host:"))
+  fmt.Fprintf(output, "  %s\n", invitation.Local)
+  fmt.Fprintf(output, "\n%s\n", color.Header("From another machine:"))
+  fmt.Fprintf(output, "  %s\n", invitation.SSH)
+  if len(invitation.Warnings) > 0 {
+    fmt.Fprintln(output)
+    for _, warning := range invitation.Warnings {
+      fmt.Fprintf(output, "%s\n", color.WarningMessage("%s", warning))
+    }
+  }
+  if count, err := tmuxManager.ClientCount(sessionName); err == nil {
+    fmt.Fprintf(output, "\n%s\n", color.InfoText("Clients attached now: %d", count))
+  }
+  return nil
+}
+
+func runSessionsUnshareCommand(tmuxManager *tmux.Manager, sessionName, userName string, output io.Writer) error {
+  if !tmuxManager.IsAvailable() {
+    return fmt.Errorf("❌ tmux is not available on this system")
+  }
+  if err := tmuxManager.UnshareSession(sessionName, userName); err != nil {
+    return fmt.Errorf("❌ Failed to unshare session '%s': %w", sessionName, err)
+  }
+  fmt.Fprintf(output, "%s\n", color.SuccessMessage("The tmux socket of '%s' is private again", sessionName))
   return nil
 }
 
-func runSessionsCleanupCommand(force bool, output io.Writer) error {
+// cleanupJSON is the result of 'sshm sessions cleanup --json': the sessions
+// selected for cleanup and, with --force, what happened to them
+type cleanupJSON struct {
+  Sessions []string `json:"sessions"`
+  service.CleanupResult
+}
+
+func runSessionsCleanupCommand(ctx context.Context, force, all, asJSON bool, output io.Writer) error {
   // Initialize tmux manager
   tmuxManager := tmux.NewManager()
 
@@ -166,18 +401,43 @@ func runSessionsCleanupCommand(force bool, output io.Writer) error {
     return fmt.Errorf("❌ tmux is not available on this system")
   }
 
-  // Get list of sessions
-  sessions, err := tmuxManager.ListSessions()
+  // Get the sessions to clean up
+  var sessions []string
+  var err error
+  if all {
+    sessions, err = tmuxManager.ListSessions()
+  } else {
+    sessions, err = service.OrphanedSessions(tmuxManager)
+  }
   if err != nil {
     return fmt.Errorf("❌ Failed to list tmux sessions: %w", err)
   }
 
+  if asJSON {
+    result := cleanupJSON{Sessions: append([]string{}, sessions...), CleanupResult: service.CleanupResult{Killed: []string{}}}
+    if force {
+      result.CleanupResult, err = service.CleanupSessions(ctx, tmuxManager, sessions, nil)
+      if err != nil {
+        return fmt.Errorf("❌ Cleanup cancelled after terminating %d session(s): %w", len(result.Killed), err)
+      }
+    }
+    return writeJSON(output, result)
+  }
+
   if len(sessions) == 0 {
-    fmt.Fprintf(output, "%s\n", color.InfoMessage("No active tmux sessions found."))
+    if all {
+      fmt.Fprintf(output, "%s\n", color.InfoMessage("No active tmux sessions found."))
+    } else {
+      fmt.Fprintf(output, "%s\n", color.InfoMessage("No orphaned tmux sessions found."))
+    }
     return nil
   }
 
-  fmt.Fprintf(output, "%s\n", color.InfoMessage("Found %d active tmux session(s):", len(sessions)))
+  if all {
+    fmt.Fprintf(output, "%s\n", color.InfoMessage("Found %d active tmux session(s):", len(sessions)))
+  } else {
+    fmt.Fprintf(output, "%s\n", color.InfoMessage("Found %d orphaned tmux session(s):", len(sessions)))
+  }
   for _, sessionName := range sessions {
     sessionType := "Individual"
     if isGroupSession(sessionName) {
@@ -193,21 +453,23 @@ func runSessionsCleanupCommand(force bool, output io.Writer) error {
     return nil
   }
 
-  // Force cleanup - kill all sessions
+  // Force cleanup - kill the selected sessions
   fmt.Fprintf(output, "\n%s\n", color.InfoMessage("Force cleanup enabled. Terminating %d session(s)...", len(sessions)))
-  
-  successCount := 0
+
+  result, err := service.CleanupSessions(ctx, tmuxManager, sessions, nil)
   for _, sessionName := range sessions {
-    err := tmuxManager.KillSession(sessionName)
-    if err != nil {
-      fmt.Fprintf(output, "%s\n", color.ErrorMessage("Failed to kill session '%s': %v", sessionName, err))
-    } else {
-      fmt.Fprintf(output, "%s\n", color.SuccessMessage("Terminated session '%s'", sessionName))
-      successCount++
+    if failure, failed := result.Failed[sessionName]; failed {
+      fmt.Fprintf(output, "%s\n", color.ErrorMessage("Failed to kill session '%s': %s", sessionName, failure))
     }
   }
+  for _, sessionName := range result.Killed {
+    fmt.Fprintf(output, "%s\n", color.SuccessMessage("Terminated session '%s'", sessionName))
+  }
+  if err != nil {
+    return fmt.Errorf("❌ Cleanup cancelled after terminating %d session(s): %w", len(result.Killed), err)
+  }
 
-  fmt.Fprintf(output, "\n%s\n", color.InfoMessage("Cleanup complete: %d/%d sessions terminated", successCount, len(sessions)))
+  fmt.Fprintf(output, "\n%s\n", color.InfoMessage("Cleanup complete: %d/%d sessions terminated", len(result.Killed), len(sessions)))
   return nil
 }
 
@@ -250,4 +512,50 @@ func containsAnyChar(str string, chars ...string) bool {
     }
   }
   return false
-}
\ No newline at end of file
+}
+func runSessionsRepairCommand(sessionName string, output io.Writer) error {
+  cfg, err := config.Load()
+  if err != nil {
+    return fmt.Errorf("❌ Failed to load configuration: %w", err)
+  }
+
+  tmuxManager := tmux.NewManager()
+  if !tmuxManager.IsAvailable() {
+    return fmt.Errorf("❌ tmux is not available on this system")
+  }
+  if !tmuxManager.SessionExists(sessionName) {
+    return fmt.Errorf("❌ Session '%s' not found", sessionName)
+  }
+
+  // Group sessions are named after their profile and reuse its bootstrap
+  profileName := tmux.ServerForSession(sessionName, cfg.ProfileNames())
+  configured := cfg.ServersWithBootstrap(profileName)
+
+  // Windows of servers whose current login banner wasn't accepted stay disconnected
+  manager, err := connection.NewManager()
+  if err != nil {
+    return fmt.Errorf("❌ Failed to initialize connection manager: %w", err)
+  }
+  defer manager.Close()
+  acks := manager.AcknowledgedBanners(configured)
+
+  result, err := tmuxManager.RepairSession(sessionName, connection.TmuxServers(configured, acks))
+  if err != nil {
+    return fmt.Errorf("❌ Failed to repair session '%s': %w", sessionName, err)
+  }
+
+  if len(result.Reconnected) == 0 && len(result.Unknown) == 0 && len(result.Refused) == 0 {
+    fmt.Fprintf(output, "%s\n", color.SuccessMessage("All %d window(s) of '%s' are connected", result.Healthy, sessionName))
+    return nil
+  }
+  if len(result.Reconnected) > 0 {
+    fmt.Fprintf(output, "%s\n", color.SuccessMessage("Reconnected %d window(s): %s", len(result.Reconnected), strings.Join(result.Reconnected, ", ")))
+  }
+  if len(result.Unknown) > 0 {
+    fmt.Fprintf(output, "%s\n", color.WarningMessage("%d disconnected window(s) match no configured server: %s", len(result.Unknown), strings.Join(result.Unknown, ", ")))
+  }
+  if len(result.Refused) > 0 {
+    fmt.Fprintf(output, "%s\n", color.WarningMessage("%d window(s) left disconnected until their login banner is accepted with 'sshm connect': %s", len(result.Refused), strings.Join(result.Refused, ", ")))
+  }
+  return nil
+}
@@ -0,0 +1,308 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+	"sshm/internal/color"
+	"sshm/internal/config"
+)
+
+var settingsCmd = &cobra.Command{
+	Use:   "settings",
+	Short: "Manage SSHM application settings",
+	Long: `Manage application-wide settings stored in ~/.sshm/config.yaml.
+
+Examples:
+  sshm settings idle-lock --minutes 10 --pin   # Lock the TUI after 10 idle minutes, protected by a PIN
+  sshm settings idle-lock --minutes 0          # Disable the idle lock
+  sshm settings status --warn-ms 200 --critical-ms 800 --color "auth failed=purple"
+  sshm settings local-time --column            # Show each host's local time in the server list
+  sshm settings confirm quit=never             # Configure which actions ask for confirmation`,
+}
+
+var settingsConfirmCmd = &cobra.Command{
+	Use:   "confirm [action=mode ...]",
+	Short: "Configure which actions ask for confirmation",
+	Long: `Configure the confirmation prompt of each destructive action. Without
+arguments the current setting of every action is shown.
+
+Actions: delete_server, delete_profile, kill_session, cleanup_sessions, quit
+(quitting the TUI while tunnels are running) and power_action.
+
+Modes:
+  always      Always ask (default)
+  never       Never ask
+  protected   Only ask when a protected server is affected
+
+Typing the name of a protected server before a power action is always required.
+
+Examples:
+  sshm settings confirm
+  sshm settings confirm delete_server=protected kill_session=never
+  sshm settings confirm --reset`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		reset, _ := cmd.Flags().GetBool("reset")
+		return runSettingsConfirmCommand(cmd.OutOrStdout(), args, reset)
+	},
+}
+
+var settingsLocalTimeCmd = &cobra.Command{
+	Use:   "local-time",
+	Short: "Show or hide the host local time column in the TUI",
+	Long: `Toggle the "Local time" column in the TUI server list. The column shows the
+current time of each server with a timezone set ('sshm timezone <server> <zone>').
+The local time is always shown in the server details.
+
+Examples:
+  sshm settings local-time --column
+  sshm settings local-time --column=false`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		column, _ := cmd.Flags().GetBool("column")
+		return runSettingsLocalTimeCommand(cmd.OutOrStdout(), cmd.Flags().Changed("column"), column)
+	},
+}
+
+var settingsIdleLockCmd = &cobra.Command{
+	Use:   "idle-lock",
+	Short: "Configure automatic locking of the TUI when idle",
+	Long: `Configure the idle timer that blanks and locks the TUI after a period without input.
+
+When a PIN is set it must be entered to unlock the TUI; otherwise pressing Enter unlocks it.
+The PIN is stored as a bcrypt hash, never in plaintext.
+
+Examples:
+  sshm settings idle-lock --minutes 15          # Lock after 15 idle minutes
+  sshm settings idle-lock --minutes 15 --pin    # Also require a PIN (prompted securely)
+  sshm settings idle-lock --clear-pin           # Remove the PIN
+  sshm settings idle-lock --minutes 0           # Disable idle locking`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		minutes, _ := cmd.Flags().GetInt("minutes")
+		setPIN, _ := cmd.Flags().GetBool("pin")
+		clearPIN, _ := cmd.Flags().GetBool("clear-pin")
+		return runSettingsIdleLockCommand(cmd.OutOrStdout(), cmd.Flags().Changed("minutes"), minutes, setPIN, clearPIN)
+	},
+}
+
+var settingsStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Configure the TUI status column thresholds and colors",
+	Long: `Configure how connection status is shown in the TUI status column.
+
+Online servers slower than the warning or critical latency threshold are
+colored as warnings or critical. Colors can be overridden per status
+classification (online, checking, unreachable, refused, error, auth failed,
+auth error, unknown) or severity (warn, critical, retried).
+
+The palette sets the base colors of server and session statuses and profile
+badges: default, colorblind (Okabe-Ito colors that stay distinct with red-green
+or blue-yellow color blindness) or monochrome. With symbols, statuses are also
+marked ✓ (online), ~ (slow or in use), ! (needs attention), ✗ (failed) and
+… (checking), so they can be told apart without color; monochrome always
+shows them.
+
+Examples:
+  sshm settings status --warn-ms 200 --critical-ms 800
+  sshm settings status --color unreachable=purple --color warn=orange
+  sshm settings status --palette colorblind --symbols
+  sshm settings status --reset-colors`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		warnMS, _ := cmd.Flags().GetInt("warn-ms")
+		criticalMS, _ := cmd.Flags().GetInt("critical-ms")
+		colors, _ := cmd.Flags().GetStringToString("color")
+		resetColors, _ := cmd.Flags().GetBool("reset-colors")
+		palette, _ := cmd.Flags().GetString("palette")
+		symbols, _ := cmd.Flags().GetBool("symbols")
+		return runSettingsStatusCommand(cmd.OutOrStdout(), cmd.Flags().Changed("warn-ms"), warnMS,
+			cmd.Flags().Changed("critical-ms"), criticalMS, colors, resetColors,
+			palette, cmd.Flags().Changed("symbols"), symbols)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(settingsCmd)
+	settingsCmd.AddCommand(settingsIdleLockCmd)
+	settingsCmd.AddCommand(settingsStatusCmd)
+	settingsCmd.AddCommand(settingsLocalTimeCmd)
+	settingsCmd.AddCommand(settingsConfirmCmd)
+
+	settingsConfirmCmd.Flags().Bool("reset", false, "Ask for confirmation before every action again")
+
+	settingsLocalTimeCmd.Flags().Bool("column", false, "Show the local time column (--column=false hides it)")
+
+	settingsStatusCmd.Flags().Int("warn-ms", 0, "Latency in milliseconds above which online servers are shown as warning")
+	settingsStatusCmd.Flags().Int("critical-ms", 0, "Latency in milliseconds above which online servers are shown as critical")
+	settingsStatusCmd.Flags().StringToString("color", nil, "Color for a status or severity, e.g. --color unreachable=purple (repeatable)")
+	settingsStatusCmd.Flags().Bool("reset-colors", false, "Restore the default status colors")
+	settingsStatusCmd.Flags().String("palette", "", "Status palette: default, colorblind or monochrome")
+	settingsStatusCmd.Flags().Bool("symbols", false, "Mark statuses with ✓ ✗ ~ ! as well as color (--symbols=false turns them off)")
+
+	settingsIdleLockCmd.Flags().IntP("minutes", "m", 0, "Idle minutes before the TUI locks (0 = disabled)")
+	settingsIdleLockCmd.Flags().Bool("pin", false, "Prompt for a PIN required to unlock")
+	settingsIdleLockCmd.Flags().Bool("clear-pin", false, "Remove the unlock PIN")
+}
+
+func runSettingsIdleLockCommand(output io.Writer, minutesChanged bool, minutes int, setPIN, clearPIN bool) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("❌ Failed to load configuration: %w", err)
+	}
+
+	if minutesChanged {
+		if minutes < 0 {
+			return fmt.Errorf("❌ Minutes must not be negative")
+		}
+		cfg.UI.IdleLockMinutes = minutes
+	}
+
+	switch {
+	case setPIN && clearPIN:
+		return fmt.Errorf("❌ --pin and --clear-pin cannot be used together")
+	case setPIN:
+		fmt.Fprint(output, "Enter new PIN: ")
+		pin, err := term.ReadPassword(int(os.Stdin.Fd()))
+		fmt.Fprintln(output)
+		if err != nil {
+			return fmt.Errorf("❌ Failed to read PIN: %w", err)
+		}
+		if err := cfg.UI.SetLockPIN(string(pin)); err != nil {
+			return fmt.Errorf("❌ %w", err)
+		}
+	case clearPIN:
+		cfg.UI.SetLockPIN("")
+	}
+
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("❌ Failed to save configuration: %w", err)
+	}
+
+	if cfg.UI.IdleLockMinutes > 0 {
+		fmt.Fprintf(output, "%s\n", color.SuccessMessage("TUI will lock after %d idle minute(s)", cfg.UI.IdleLockMinutes))
+	} else {
+		fmt.Fprintf(output, "%s\n", color.SuccessMessage("Idle lock disabled"))
+	}
+	if cfg.UI.HasLockPIN() {
+		fmt.Fprintf(output, "%s\n", color.InfoText("A PIN is required to unlock"))
+	}
+	return nil
+}
+
+func runSettingsStatusCommand(output io.Writer, warnChanged bool, warnMS int, criticalChanged bool, criticalMS int, colors map[string]string, resetColors bool, palette string, symbolsChanged, symbols bool) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("❌ Failed to load configuration: %w", err)
+	}
+
+	status := &cfg.UI.Status
+	if warnChanged {
+		status.LatencyWarnMS = warnMS
+	}
+	if criticalChanged {
+		status.LatencyCriticalMS = criticalMS
+	}
+	if resetColors {
+		status.Colors = nil
+	}
+	if palette != "" {
+		status.Palette = strings.ToLower(palette)
+		if status.Palette == config.PaletteDefault {
+			status.Palette = ""
+		}
+	}
+	if symbolsChanged {
+		status.Symbols = symbols
+	}
+	for key, name := range colors {
+		if tcell.GetColor(name) == tcell.ColorDefault {
+			return fmt.Errorf("❌ Unknown color '%s' for '%s'", name, key)
+		}
+		if status.Colors == nil {
+			status.Colors = make(map[string]string)
+		}
+		status.Colors[strings.ToLower(key)] = name
+	}
+
+	if err := status.Validate(); err != nil {
+		return fmt.Errorf("❌ %w", err)
+	}
+
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("❌ Failed to save configuration: %w", err)
+	}
+
+	warn, critical := status.LatencyThresholds()
+	fmt.Fprintf(output, "%s\n", color.SuccessMessage("Status thresholds: warning above %v, critical above %v", warn, critical))
+	symbolsState := "off"
+	if status.ShowSymbols() {
+		symbolsState = "on"
+	}
+	fmt.Fprintf(output, "%s\n", color.InfoText("  palette %s, symbols %s", status.PaletteName(), symbolsState))
+	keys := make([]string, 0, len(status.Colors))
+	for key := range status.Colors {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(output, "%s\n", color.InfoText("  %s → %s", key, status.Colors[key]))
+	}
+	return nil
+}
+
+func runSettingsLocalTimeCommand(output io.Writer, columnChanged, column bool) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("❌ Failed to load configuration: %w", err)
+	}
+
+	if columnChanged {
+		cfg.UI.ShowLocalTime = column
+		if err := cfg.Save(); err != nil {
+			return fmt.Errorf("❌ Failed to save configuration: %w", err)
+		}
+	}
+
+	if cfg.UI.ShowLocalTime {
+		fmt.Fprintf(output, "%s\n", color.SuccessMessage("Local time column is shown"))
+	} else {
+		fmt.Fprintf(output, "%s\n", color.SuccessMessage("Local time column is hidden"))
+	}
+	return nil
+}
+
+func runSettingsConfirmCommand(output io.Writer, assignments []string, reset bool) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("❌ Failed to load configuration: %w", err)
+	}
+
+	if reset {
+		cfg.UI.Confirmations = nil
+	}
+	for _, assignment := range assignments {
+		action, mode, ok := strings.Cut(assignment, "=")
+		if !ok {
+			return fmt.Errorf("❌ Expected action=mode, got '%s'", assignment)
+		}
+		if err := cfg.UI.SetConfirmation(action, mode); err != nil {
+			return fmt.Errorf("❌ %w", err)
+		}
+	}
+
+	if reset || len(assignments) > 0 {
+		if err := cfg.Save(); err != nil {
+			return fmt.Errorf("❌ Failed to save configuration: %w", err)
+		}
+		fmt.Fprintf(output, "%s\n", color.SuccessMessage("Confirmation settings updated"))
+	}
+
+	for _, action := range config.ConfirmActions {
+		fmt.Fprintf(output, "%s\n", color.InfoText("  %-17s %s", action, cfg.UI.ConfirmationMode(action)))
+	}
+	return nil
+}
@@ -0,0 +1,203 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"sshm/internal/color"
+	"sshm/internal/config"
+	"sshm/internal/connection"
+	"sshm/internal/stats"
+)
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Summarize the inventory: servers by auth type, profile and tag, availability and usage",
+	Long: `Summarize the whole inventory: the number of servers by authentication type,
+profile and tag, the share of status checks in the last 24 hours that found
+servers online, the most connected servers, and stale entries that were never
+connected to.
+
+Availability comes from the status checks recorded by the TUI and 'sshm daemon';
+it is empty until one of them has run. Connection counts come from the
+connection history ('sshm history'). 'sshm stats time' reports the hours spent
+attached to sessions per server or profile per week.
+
+Examples:
+  sshm stats
+  sshm stats --top 10
+  sshm stats --json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		top, _ := cmd.Flags().GetInt("top")
+		asJSON, _ := cmd.Flags().GetBool("json")
+		return runStatsCommand(cmd.OutOrStdout(), top, asJSON)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(statsCmd)
+
+	statsCmd.Flags().Int("top", stats.DefaultTop, "Number of most connected servers to list")
+	statsCmd.Flags().Bool("json", false, "Output the statistics as JSON")
+
+	statsCmd.AddCommand(statsTimeCmd)
+	statsTimeCmd.Flags().String("by", stats.TimeByServer, "Group by server or profile")
+	statsTimeCmd.Flags().Int("weeks", stats.DefaultTimeWeeks, "Number of weeks to report, the current one included")
+	statsTimeCmd.Flags().Bool("json", false, "Output the report as JSON")
+}
+
+var statsTimeCmd = &cobra.Command{
+	Use:   "time",
+	Short: "Report hours spent attached to sessions per server or profile per week",
+	Long: `Sum the time spent attached to tmux sessions from the TUI, per week and per
+server or profile, e.g. for billing or capacity discussions. Weeks start on
+Monday in local time; sessions spanning weeks are split between them.
+
+Time is recorded when you detach from a session attached from the TUI. By
+server, profile sessions are reported as "profile <name>" since they cover
+several servers; by profile, server sessions count towards the first profile
+containing the server.
+
+Examples:
+  sshm stats time
+  sshm stats time --by profile --weeks 8
+  sshm stats time --json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		by, _ := cmd.Flags().GetString("by")
+		weeks, _ := cmd.Flags().GetInt("weeks")
+		asJSON, _ := cmd.Flags().GetBool("json")
+		return runStatsTimeCommand(cmd.OutOrStdout(), by, weeks, asJSON)
+	},
+}
+
+func runStatsTimeCommand(output io.Writer, by string, weeks int, asJSON bool) error {
+	manager, err := connection.NewManager()
+	if err != nil {
+		return fmt.Errorf("❌ Failed to initialize connection manager: %w", err)
+	}
+	defer manager.Close()
+
+	report, err := stats.TimeFromHistory(manager.GetHistoryManager(), strings.ToLower(by), weeks, time.Now())
+	if err != nil {
+		return fmt.Errorf("❌ Failed to build the time report: %w", err)
+	}
+
+	if asJSON {
+		encoder := json.NewEncoder(output)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	}
+
+	displayTimeReport(output, report)
+	return nil
+}
+
+// displayTimeReport renders the hours per week as text
+func displayTimeReport(output io.Writer, report []stats.WeekTime) {
+	fmt.Fprintf(output, "%s\n\n", color.Header("Time Attached to Sessions"))
+	if len(report) == 0 {
+		fmt.Fprintf(output, "%s\n", color.InfoMessage("No session time recorded; attach to sessions from the TUI"))
+		return
+	}
+
+	for i := 0; i < len(report); {
+		week := report[i].Week
+		fmt.Fprintf(output, "%s\n", color.InfoMessage("Week of %s:", week.Format("Mon 2006-01-02")))
+		w := tabwriter.NewWriter(output, 0, 0, 2, ' ', 0)
+		var total time.Duration
+		for ; i < len(report) && report[i].Week.Equal(week); i++ {
+			total += report[i].Duration
+			fmt.Fprintf(w, "  %s\t%s\n", report[i].Name, stats.FormatHours(report[i].Duration))
+		}
+		fmt.Fprintf(w, "  %s\t%s\n", "total", stats.FormatHours(total))
+		w.Flush()
+		fmt.Fprintln(output)
+	}
+}
+
+func runStatsCommand(output io.Writer, top int, asJSON bool) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("❌ Failed to load configuration: %w", err)
+	}
+
+	manager, err := connection.NewManager()
+	if err != nil {
+		return fmt.Errorf("❌ Failed to initialize connection manager: %w", err)
+	}
+	defer manager.Close()
+
+	inventory, err := stats.FromHistory(cfg, manager.GetHistoryManager(), top)
+	if err != nil {
+		return fmt.Errorf("❌ Failed to read history: %w", err)
+	}
+
+	if asJSON {
+		encoder := json.NewEncoder(output)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(inventory)
+	}
+
+	displayInventoryStats(output, inventory)
+	return nil
+}
+
+// displayInventoryStats renders the inventory summary as text
+func displayInventoryStats(output io.Writer, inventory stats.Inventory) {
+	fmt.Fprintf(output, "%s\n\n", color.Header("Inventory Statistics"))
+	if inventory.Total == 0 {
+		fmt.Fprintf(output, "%s\n", color.InfoMessage("No servers configured"))
+		return
+	}
+	fmt.Fprintf(output, "Total servers: %s\n", color.Info(fmt.Sprintf("%d", inventory.Total)))
+
+	for _, group := range []struct {
+		title  string
+		counts []stats.Count
+	}{
+		{"By auth type:", inventory.ByAuthType},
+		{"By profile:", inventory.ByProfile},
+		{"By tag:", inventory.ByTag},
+	} {
+		fmt.Fprintf(output, "\n%s\n", color.InfoMessage("%s", group.title))
+		w := tabwriter.NewWriter(output, 0, 0, 2, ' ', 0)
+		for _, count := range group.counts {
+			fmt.Fprintf(w, "  %s\t%d\n", count.Name, count.Count)
+		}
+		w.Flush()
+	}
+
+	fmt.Fprintf(output, "\n%s\n", color.InfoMessage("Availability (last 24h):"))
+	if inventory.Checks == 0 {
+		fmt.Fprintf(output, "  %s\n", color.InfoText("No status checks recorded; run the TUI or 'sshm daemon'"))
+	} else {
+		fmt.Fprintf(output, "  %s online across %d check(s) of %d server(s)\n",
+			color.Info(fmt.Sprintf("%.1f%%", inventory.OnlinePercent())), inventory.Checks, inventory.CheckedServers)
+	}
+
+	fmt.Fprintf(output, "\n%s\n", color.InfoMessage("Most connected:"))
+	if len(inventory.MostConnected) == 0 {
+		fmt.Fprintf(output, "  %s\n", color.InfoText("No connections recorded"))
+	}
+	w := tabwriter.NewWriter(output, 0, 0, 2, ' ', 0)
+	for _, server := range inventory.MostConnected {
+		last := ""
+		if !server.LastConnection.IsZero() {
+			last = "last " + server.LastConnection.Format("2006-01-02 15:04")
+		}
+		fmt.Fprintf(w, "  %s\t%d\t%s\n", server.Name, server.Connections, last)
+	}
+	w.Flush()
+
+	if len(inventory.NeverConnected) > 0 {
+		fmt.Fprintf(output, "\n%s\n", color.WarningMessage("Never connected to (%d): %s",
+			len(inventory.NeverConnected), strings.Join(inventory.NeverConnected, ", ")))
+	}
+}
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"sshm/internal/config"
+	"sshm/internal/statuscache"
+	"sshm/internal/tmux"
+)
+
+// statuslineMaxAge is how old the status cache may be before the summary is marked stale
+const statuslineMaxAge = 10 * time.Minute
+
+var statuslineCmd = &cobra.Command{
+	Use:   "statusline",
+	Short: "Print a short summary for the tmux status line",
+	Long: `Print a one-line summary for embedding in the tmux status line: the server of
+the current tmux session and how many servers are offline.
+
+The summary is read from the status cache written by the TUI, so it never
+touches the network and is cheap to run on every status refresh. A trailing
+"?" means the cache is older than 10 minutes.
+
+Add it to ~/.tmux.conf:
+  set -g status-right '#(sshm statusline --session "#S") %H:%M'
+  set -g status-interval 15`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		session, _ := cmd.Flags().GetString("session")
+		plain, _ := cmd.Flags().GetBool("plain")
+		return runStatuslineCommand(cmd.OutOrStdout(), session, plain)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(statuslineCmd)
+
+	statuslineCmd.Flags().String("session", "", "tmux session name (default: the current session)")
+	statuslineCmd.Flags().Bool("plain", false, "Print without tmux color codes")
+}
+
+func runStatuslineCommand(output io.Writer, session string, plain bool) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("❌ Failed to load configuration: %w", err)
+	}
+
+	path, err := statuscache.DefaultPath()
+	if err != nil {
+		return fmt.Errorf("❌ Failed to locate status cache: %w", err)
+	}
+	cache, err := statuscache.Load(path)
+	if err != nil {
+		return fmt.Errorf("❌ %w", err)
+	}
+
+	if session == "" && os.Getenv("TMUX") != "" {
+		if out, err := exec.Command("tmux", "display-message", "-p", "#S").Output(); err == nil {
+			session = strings.TrimSpace(string(out))
+		}
+	}
+
+	var serverNames []string
+	for _, server := range cfg.GetServers() {
+		serverNames = append(serverNames, server.Name)
+	}
+
+	fmt.Fprintln(output, formatStatusline(tmux.ServerForSession(session, serverNames), cache, plain))
+	return nil
+}
+
+// formatStatusline renders the status line summary, e.g. "web1 | 2 offline"
+func formatStatusline(currentServer string, cache *statuscache.Cache, plain bool) string {
+	paint := func(color, text string) string {
+		if plain {
+			return text
+		}
+		return fmt.Sprintf("#[fg=%s]%s#[default]", color, text)
+	}
+
+	var parts []string
+	if currentServer != "" {
+		serverColor := "green"
+		if entry, ok := cache.Servers[currentServer]; ok && entry.Status != "online" {
+			serverColor = "red"
+		}
+		parts = append(parts, paint(serverColor, "⚡"+currentServer))
+	}
+
+	switch offline := len(cache.Offline()); {
+	case len(cache.Servers) == 0:
+		parts = append(parts, paint("colour245", "no status"))
+	case offline > 0:
+		parts = append(parts, paint("red", fmt.Sprintf("%d offline", offline)))
+	default:
+		parts = append(parts, paint("green", "all online"))
+	}
+
+	line := strings.Join(parts, " | ")
+	if cache.IsStale(statuslineMaxAge) {
+		line += "?"
+	}
+	return line
+}
@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"sshm/internal/color"
+	"sshm/internal/config"
+	"sshm/internal/sshconfig"
+)
+
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Sync servers with ~/.ssh/config in both directions",
+	Long: `Keep the servers in sync with an OpenSSH config file.
+
+New Host blocks, including those in files pulled in with Include, are imported.
+Servers are written to a section at the top of the file marked
+'# BEGIN sshm managed hosts', so plain ssh, scp and editors' remote plugins know
+them too. Everything outside that section is only read: a server whose Host
+block you wrote yourself is only written to the managed section once it differs
+from your block, which the managed section then overrides.
+
+Each sync compares both sides with the previous one. Edits made on one side are
+carried over to the other, including hand edits of the managed section. Hosts
+edited on both sides are conflicts: they are reported and left alone unless
+--prefer (or ssh_sync.prefer in the configuration) says which side wins.
+
+With --enable the TUI syncs when it starts and 'sshm daemon' on every round.
+
+Examples:
+  sshm sync                     # Sync with ~/.ssh/config
+  sshm sync --dry-run           # Show what would change
+  sshm sync --prefer sshm       # Resolve conflicts with sshm's values
+  sshm sync --file ~/.ssh/work  # Sync with another file
+  sshm sync --enable            # Sync automatically from now on`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		prefer, _ := cmd.Flags().GetString("prefer")
+		file, _ := cmd.Flags().GetString("file")
+		enable, _ := cmd.Flags().GetBool("enable")
+		disable, _ := cmd.Flags().GetBool("disable")
+		return runSyncCommand(cmd.OutOrStdout(), dryRun, prefer, file, enable, disable)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(syncCmd)
+
+	syncCmd.Flags().Bool("dry-run", false, "Show what would change without writing anything")
+	syncCmd.Flags().String("prefer", "", "Which side wins conflicts: sshm or ssh (default: config or report them)")
+	syncCmd.Flags().String("file", "", "SSH config file to sync with (default: config or ~/.ssh/config)")
+	syncCmd.Flags().Bool("enable", false, "Sync automatically in the TUI and daemon")
+	syncCmd.Flags().Bool("disable", false, "Stop syncing automatically")
+}
+
+func runSyncCommand(output io.Writer, dryRun bool, prefer, file string, enable, disable bool) error {
+	if enable && disable {
+		return fmt.Errorf("❌ --enable and --disable can't be combined")
+	}
+	if err := config.ValidateSSHSyncPrefer(prefer); err != nil {
+		return fmt.Errorf("❌ %w", err)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("❌ Failed to load configuration: %w", err)
+	}
+
+	settings := cfg.SSHSync
+	if file != "" {
+		settings.Path = file
+	}
+	if prefer != "" {
+		settings.Prefer = prefer
+	}
+	syncer, err := sshconfig.NewSyncer(settings)
+	if err != nil {
+		return fmt.Errorf("❌ %w", err)
+	}
+	syncer.DryRun = dryRun
+
+	if enable || disable {
+		cfg.SSHSync.Enabled = enable
+		if file != "" {
+			cfg.SSHSync.Path = file
+		}
+	}
+
+	result, err := syncer.Sync(cfg)
+	if err != nil {
+		return fmt.Errorf("❌ %w", err)
+	}
+	if !dryRun && (result.ConfigChanged || enable || disable) {
+		if err := cfg.Save(); err != nil {
+			return fmt.Errorf("❌ Failed to save configuration: %w", err)
+		}
+	}
+
+	verb := "Synced"
+	if dryRun {
+		verb = "Dry run against"
+	}
+	fmt.Fprintf(output, "%s\n", color.InfoMessage("%s %s", verb, syncer.Path))
+	if len(result.Changes) == 0 {
+		fmt.Fprintf(output, "%s\n", color.InfoText("Already in sync"))
+	}
+	for _, change := range result.Changes {
+		line := fmt.Sprintf("%s %s: %s", change.Action, change.Host, change.Detail)
+		switch change.Action {
+		case sshconfig.ActionConflict, sshconfig.ActionSkip:
+			fmt.Fprintf(output, "%s\n", color.WarningMessage("%s", line))
+		default:
+			fmt.Fprintf(output, "  %s\n", line)
+		}
+	}
+	for _, conflict := range result.Conflicts {
+		fmt.Fprintf(output, "%s\n", color.InfoText("  %s: sshm has %s@%s:%d, the SSH config %s@%s:%d", conflict.Host,
+			conflict.SSHM.User, conflict.SSHM.Hostname, conflict.SSHM.Port,
+			conflict.SSHConfig.User, conflict.SSHConfig.Hostname, conflict.SSHConfig.Port))
+	}
+
+	switch {
+	case enable:
+		fmt.Fprintf(output, "%s\n", color.SuccessMessage("Automatic sync enabled"))
+	case disable:
+		fmt.Fprintf(output, "%s\n", color.SuccessMessage("Automatic sync disabled"))
+	}
+	if len(result.Conflicts) > 0 {
+		return fmt.Errorf("❌ %d conflict(s) left unresolved; rerun with --prefer sshm or --prefer ssh", len(result.Conflicts))
+	}
+	return nil
+}
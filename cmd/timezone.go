@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"sshm/internal/color"
+	"sshm/internal/config"
+)
+
+var timezoneCmd = &cobra.Command{
+	Use:   "timezone <server-name> [zone|none]",
+	Short: "Set a server's time zone and show its local time",
+	Long: `Set the IANA time zone of a server, such as Europe/Berlin or Asia/Tokyo.
+The TUI shows the host's current local time in the server details, and
+optionally as a column ('sshm settings local-time --column').
+
+Without a zone the server's current local time is shown. Use "none" to
+remove the time zone.
+
+Examples:
+  sshm timezone tokyo-db Asia/Tokyo
+  sshm timezone tokyo-db
+  sshm timezone tokyo-db none`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		zone := ""
+		if len(args) == 2 {
+			zone = args[1]
+		}
+		return runTimezoneCommand(cmd.OutOrStdout(), args[0], zone, len(args) == 2)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(timezoneCmd)
+}
+
+func runTimezoneCommand(output io.Writer, serverName, zone string, set bool) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("❌ Failed to load configuration: %w", err)
+	}
+
+	server, err := cfg.GetServer(serverName)
+	if err != nil {
+		return fmt.Errorf("❌ Server '%s' not found", serverName)
+	}
+
+	if !set {
+		if server.Timezone == "" {
+			fmt.Fprintf(output, "%s\n", color.InfoMessage("%s has no time zone set", server.Name))
+			return nil
+		}
+		fmt.Fprintf(output, "%s\n", color.InfoMessage("%s (%s): %s", server.Name, server.Timezone, server.LocalTime(time.Now())))
+		return nil
+	}
+
+	if strings.EqualFold(zone, "none") {
+		zone = ""
+	}
+	server.Timezone = zone
+	if err := cfg.UpdateServer(*server); err != nil {
+		return fmt.Errorf("❌ %w", err)
+	}
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("❌ Failed to save configuration: %w", err)
+	}
+
+	if zone == "" {
+		fmt.Fprintf(output, "%s\n", color.SuccessMessage("Removed the time zone from %s", server.Name))
+		return nil
+	}
+	fmt.Fprintf(output, "%s\n", color.SuccessMessage("%s is in %s, local time %s", server.Name, zone, server.LocalTime(time.Now())))
+	return nil
+}
@@ -8,6 +8,8 @@ import (
 	"syscall"
 
 	"github.com/spf13/cobra"
+	"sshm/internal/config"
+	"sshm/internal/events"
 	"sshm/internal/tui"
 )
 
@@ -27,7 +29,10 @@ Key features:
   • Context-aware help system
 
 Usage:
-  sshm tui    # Launch the TUI interface
+  sshm tui              # Launch the TUI interface
+  sshm tui --offline    # Browse servers and sessions without network checks
+  sshm tui --events fd:3 3>events.ndjson
+                        # Stream JSON events for tooling and tests
 
 Navigation:
   • Use arrow keys or j/k to navigate
@@ -37,17 +42,46 @@ Navigation:
 	RunE: runTUI,
 }
 
+var (
+	tuiOffline bool
+	tuiEvents  string
+)
+
 func init() {
 	rootCmd.AddCommand(tuiCmd)
+	tuiCmd.Flags().BoolVar(&tuiOffline, "offline", false, "Start in offline mode: no status checks or update checks (toggle with Ctrl+N)")
+	tuiCmd.Flags().StringVar(&tuiEvents, "events", "", "Stream server_selected, connection_started, status_changed and config_saved events as JSON lines to fd:N, unix:PATH, tcp:HOST:PORT or a file")
 }
 
 func runTUI(cmd *cobra.Command, args []string) error {
+	// Must be set before the first status check starts
+	tui.SetStartOffline(tuiOffline)
+	// The TUI asks for the passphrase itself instead of on the terminal
+	config.SetPassphraseFunc(configPassphrase(false))
+
+	if tuiEvents != "" {
+		emitter, err := events.Open(tuiEvents, "tui")
+		if err != nil {
+			return fmt.Errorf("failed to open event stream: %w", err)
+		}
+		defer emitter.Close()
+		tui.SetEventEmitter(emitter)
+	}
+
 	// Create TUI application
 	app, err := tui.NewTUIApp()
 	if err != nil {
 		return fmt.Errorf("failed to create TUI application: %w", err)
 	}
 
+	// Pick up where the previous run left off
+	app.RestoreSavedState()
+
+	// tmux hooks call back into this binary when sessions change
+	if executable, err := os.Executable(); err == nil {
+		app.SetNotifyExecutable(executable)
+	}
+
 	// Setup graceful shutdown
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
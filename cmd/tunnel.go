@@ -0,0 +1,251 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"sshm/internal/color"
+	"sshm/internal/config"
+	"sshm/internal/tunnel"
+)
+
+var tunnelCmd = &cobra.Command{
+	Use:   "tunnel",
+	Short: "Manage local port forwards (ssh -L tunnels)",
+	Long: `Manage local port forwards configured on servers.
+
+Before a tunnel starts, its local port is checked. If the port is already in use
+sshm can pick a free port automatically and reports the port actually bound.
+
+Examples:
+  sshm tunnel add web-01 --name admin --local-port 8080 --remote-port 80
+  sshm tunnel add bastion --name socks --local-port 1080 --dynamic  # SOCKS proxy (ssh -D)
+  sshm tunnel list                  # List configured tunnels and port conflicts
+  sshm tunnel start web-01 admin    # Start a tunnel and keep it open until Ctrl+C
+  sshm tunnel start web-01 --auto-port  # Start all tunnels, choosing free ports when taken
+  sshm tunnel start web-01 admin --open # Start a tunnel and open it in the browser`,
+}
+
+var tunnelAddCmd = &cobra.Command{
+	Use:   "add <server-name>",
+	Short: "Add a tunnel to a server",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name, _ := cmd.Flags().GetString("name")
+		localPort, _ := cmd.Flags().GetInt("local-port")
+		remoteHost, _ := cmd.Flags().GetString("remote-host")
+		remotePort, _ := cmd.Flags().GetInt("remote-port")
+		autoPort, _ := cmd.Flags().GetBool("auto-port")
+		url, _ := cmd.Flags().GetString("url")
+		dynamic, _ := cmd.Flags().GetBool("dynamic")
+		spec := config.Tunnel{Name: name, LocalPort: localPort, RemoteHost: remoteHost, RemotePort: remotePort, AutoPort: autoPort, URL: url, Dynamic: dynamic}
+		return runTunnelAddCommand(args[0], spec, cmd.OutOrStdout())
+	},
+}
+
+var tunnelListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List configured tunnels",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runTunnelListCommand(cmd.OutOrStdout())
+	},
+}
+
+var tunnelStartCmd = &cobra.Command{
+	Use:   "start <server-name> [tunnel-name...]",
+	Short: "Start tunnels and keep them open until interrupted",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		autoPort, _ := cmd.Flags().GetBool("auto-port")
+		open, _ := cmd.Flags().GetBool("open")
+		return runTunnelStartCommand(args[0], args[1:], autoPort, open, cmd.OutOrStdout())
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(tunnelCmd)
+	tunnelCmd.AddCommand(tunnelAddCmd)
+	tunnelCmd.AddCommand(tunnelListCmd)
+	tunnelCmd.AddCommand(tunnelStartCmd)
+
+	tunnelAddCmd.Flags().String("name", "", "Tunnel name (required)")
+	tunnelAddCmd.Flags().Int("local-port", 0, "Local port to bind (0 = pick a free port at start)")
+	tunnelAddCmd.Flags().String("remote-host", "", "Destination host as seen from the server (default: localhost)")
+	tunnelAddCmd.Flags().Int("remote-port", 0, "Destination port (required unless --dynamic)")
+	tunnelAddCmd.Flags().Bool("auto-port", false, "Pick a free local port when the configured one is taken")
+	tunnelAddCmd.Flags().String("url", "", "Browser URL template for the service, e.g. https://localhost:{port}/admin")
+	tunnelAddCmd.Flags().Bool("dynamic", false, "Run a SOCKS proxy on the local port instead of forwarding to a remote port")
+
+	tunnelStartCmd.Flags().Bool("auto-port", false, "Pick free local ports for tunnels whose port is taken")
+	tunnelStartCmd.Flags().Bool("open", false, "Open each started tunnel's URL in the system browser")
+}
+
+func runTunnelAddCommand(serverName string, spec config.Tunnel, output io.Writer) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("❌ Failed to load configuration: %w", err)
+	}
+
+	server, err := cfg.GetServer(serverName)
+	if err != nil {
+		return fmt.Errorf("❌ Server '%s' not found. Use 'sshm list' to see available servers", serverName)
+	}
+
+	if err := spec.Validate(); err != nil {
+		return fmt.Errorf("❌ Invalid tunnel: %w", err)
+	}
+	if _, err := server.GetTunnel(spec.Name); err == nil {
+		return fmt.Errorf("❌ Tunnel '%s' already exists on server '%s'", spec.Name, server.Name)
+	}
+
+	server.Tunnels = append(server.Tunnels, spec)
+	if err := cfg.UpdateServer(*server); err != nil {
+		return fmt.Errorf("❌ Failed to update server: %w", err)
+	}
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("❌ Failed to save configuration: %w", err)
+	}
+
+	fmt.Fprintf(output, "%s\n", color.SuccessMessage("Tunnel '%s' added to server '%s'", spec.Name, server.Name))
+	printTunnelConflicts(cfg, output)
+	return nil
+}
+
+func runTunnelListCommand(output io.Writer) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("❌ Failed to load configuration: %w", err)
+	}
+
+	w := tabwriter.NewWriter(output, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SERVER\tTUNNEL\tLOCAL PORT\tDESTINATION\tPORT STATUS")
+
+	count := 0
+	for _, server := range cfg.GetServers() {
+		for _, spec := range server.Tunnels {
+			count++
+			localPort := "auto"
+			status := "-"
+			if spec.LocalPort != 0 {
+				localPort = fmt.Sprintf("%d", spec.LocalPort)
+				status = "free"
+				if !tunnel.PortAvailable(spec.LocalPort) {
+					status = "in use"
+				}
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", server.Name, spec.Name, localPort, spec.Destination(), status)
+		}
+	}
+	w.Flush()
+
+	if count == 0 {
+		fmt.Fprintf(output, "%s\n", color.InfoMessage("No tunnels configured. Use 'sshm tunnel add' to add one."))
+		return nil
+	}
+
+	printTunnelConflicts(cfg, output)
+	return nil
+}
+
+// printTunnelConflicts warns about tunnels on different servers sharing a local port
+func printTunnelConflicts(cfg *config.Config, output io.Writer) {
+	for _, conflict := range cfg.FindLocalPortConflicts() {
+		fmt.Fprintf(output, "%s\n", color.WarningMessage("Local port %d is configured for multiple tunnels: %s",
+			conflict.Port, strings.Join(conflict.Tunnels, ", ")))
+	}
+}
+
+func runTunnelStartCommand(serverName string, tunnelNames []string, autoPort, open bool, output io.Writer) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("❌ Failed to load configuration: %w", err)
+	}
+
+	server, err := cfg.GetServer(serverName)
+	if err != nil {
+		return fmt.Errorf("❌ Server '%s' not found. Use 'sshm list' to see available servers", serverName)
+	}
+
+	specs := server.Tunnels
+	if len(tunnelNames) > 0 {
+		specs = nil
+		for _, name := range tunnelNames {
+			spec, err := server.GetTunnel(name)
+			if err != nil {
+				return fmt.Errorf("❌ %w", err)
+			}
+			specs = append(specs, *spec)
+		}
+	}
+	if len(specs) == 0 {
+		return fmt.Errorf("❌ Server '%s' has no tunnels. Use 'sshm tunnel add' to add one", server.Name)
+	}
+
+	manager := tunnel.NewManager()
+	defer manager.StopAll()
+
+	for _, spec := range specs {
+		started, err := manager.Start(*server, spec, autoPort)
+
+		var conflict *tunnel.PortConflictError
+		if errors.As(err, &conflict) {
+			fmt.Fprintf(output, "%s\n", color.WarningMessage("%s", conflict.Error()))
+			if !confirmTunnelAutoPort(output) {
+				fmt.Fprintf(output, "%s\n", color.InfoMessage("Skipping tunnel '%s'", spec.Name))
+				continue
+			}
+			started, err = manager.Start(*server, spec, true)
+		}
+		if err != nil {
+			return fmt.Errorf("❌ Failed to start tunnel '%s': %w", spec.Name, err)
+		}
+
+		if started.LocalPort != started.ConfiguredPort && started.ConfiguredPort != 0 {
+			fmt.Fprintf(output, "%s\n", color.SuccessMessage("Tunnel '%s': localhost:%d → %s (port %d was taken)",
+				started.Name, started.LocalPort, started.Destination(), started.ConfiguredPort))
+		} else {
+			fmt.Fprintf(output, "%s\n", color.SuccessMessage("Tunnel '%s': localhost:%d → %s",
+				started.Name, started.LocalPort, started.Destination()))
+		}
+
+		if open && started.URL != "" {
+			if err := tunnel.OpenURL(started.URL); err != nil {
+				fmt.Fprintf(output, "%s\n", color.WarningMessage("%s", err.Error()))
+			} else {
+				fmt.Fprintf(output, "%s\n", color.InfoMessage("Opened %s", started.URL))
+			}
+		}
+	}
+
+	// Check tunnel health and restart tunnels that die until interrupted
+	stopMonitoring := make(chan struct{})
+	defer close(stopMonitoring)
+	manager.StartMonitoring(tunnel.DefaultCheckInterval, stopMonitoring)
+
+	fmt.Fprintf(output, "%s\n", color.InfoText("Tunnels are running and restarted automatically if they fail. Press Ctrl+C to stop."))
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	<-sigChan
+
+	fmt.Fprintf(output, "\n%s\n", color.InfoMessage("Stopping tunnels..."))
+	return nil
+}
+
+// confirmTunnelAutoPort asks whether a free local port should be used instead
+func confirmTunnelAutoPort(output io.Writer) bool {
+	fmt.Fprint(output, "Use a free local port instead? (y/n): ")
+	scanner := bufio.NewScanner(os.Stdin)
+	if !scanner.Scan() {
+		return false
+	}
+	answer := strings.TrimSpace(strings.ToLower(scanner.Text()))
+	return answer == "y" || answer == "yes"
+}
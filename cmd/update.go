@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"sshm/internal/color"
+	"sshm/internal/config"
+	"sshm/internal/update"
+)
+
+var updateCmd = &cobra.Command{
+	Use:   "update",
+	Short: "Update sshm to the latest release",
+	Long: `Check GitHub releases for a newer version of sshm and replace the running
+binary in place. The downloaded binary is verified against the SHA-256
+checksums published with the release before it is installed.
+
+The stable channel only follows final releases; the beta channel also
+follows pre-releases. Passing --channel saves the choice for later updates
+and for the new version notice shown in the TUI status bar.
+
+Examples:
+  sshm update                  # Update to the latest release on the configured channel
+  sshm update --check          # Only report whether a newer version exists
+  sshm update --channel beta   # Switch to the beta channel and update
+  sshm update -y               # Update without asking for confirmation`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		channel, _ := cmd.Flags().GetString("channel")
+		checkOnly, _ := cmd.Flags().GetBool("check")
+		yes, _ := cmd.Flags().GetBool("yes")
+		return runUpdateCommand(cmd.OutOrStdout(), channel, checkOnly, yes)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(updateCmd)
+
+	updateCmd.Flags().String("channel", "", "Release channel to follow: stable or beta (saved)")
+	updateCmd.Flags().Bool("check", false, "Only check for a newer version")
+	updateCmd.Flags().BoolP("yes", "y", false, "Install without asking for confirmation")
+}
+
+func runUpdateCommand(output io.Writer, channel string, checkOnly, yes bool) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("❌ Failed to load configuration: %w", err)
+	}
+
+	if channel != "" {
+		normalized, err := update.NormalizeChannel(channel)
+		if err != nil {
+			return fmt.Errorf("❌ %w", err)
+		}
+		if normalized != cfg.Update.Channel {
+			cfg.Update.Channel = normalized
+			if err := cfg.Save(); err != nil {
+				return fmt.Errorf("❌ Failed to save configuration: %w", err)
+			}
+			fmt.Fprintf(output, "%s\n", color.InfoMessage("Following the %s channel", normalized))
+		}
+	}
+	channel, err = update.NormalizeChannel(cfg.Update.Channel)
+	if err != nil {
+		return fmt.Errorf("❌ %w", err)
+	}
+
+	release, err := update.Latest(channel)
+	if err != nil {
+		return fmt.Errorf("❌ Failed to check for updates: %w", err)
+	}
+
+	if !release.IsNewer() {
+		fmt.Fprintf(output, "%s\n", color.SuccessMessage("sshm %s is up to date (%s channel)", update.Version, channel))
+		return nil
+	}
+
+	fmt.Fprintf(output, "%s\n", color.InfoMessage("New version available: %s → %s", update.Version, release.TagName))
+	if release.HTMLURL != "" {
+		fmt.Fprintf(output, "%s\n", color.InfoText("Release notes: %s", release.HTMLURL))
+	}
+	if checkOnly {
+		fmt.Fprintln(output, color.InfoText("Run 'sshm update' to install it"))
+		return nil
+	}
+
+	if !yes {
+		fmt.Fprintf(output, "%s (y/n): ", color.WarningMessage("Install %s?", release.TagName))
+		scanner := bufio.NewScanner(os.Stdin)
+		if !scanner.Scan() {
+			return fmt.Errorf("❌ Cancelled")
+		}
+		answer := strings.TrimSpace(strings.ToLower(scanner.Text()))
+		if answer != "y" && answer != "yes" {
+			return fmt.Errorf("❌ Cancelled")
+		}
+	}
+
+	exePath, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("❌ Failed to locate the sshm executable: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(exePath); err == nil {
+		exePath = resolved
+	}
+
+	if err := update.Apply(release, exePath); err != nil {
+		return fmt.Errorf("❌ Update failed: %w", err)
+	}
+
+	fmt.Fprintf(output, "%s\n", color.SuccessMessage("Updated sshm to %s", release.TagName))
+	return nil
+}
@@ -0,0 +1,192 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"sshm/internal/color"
+	"sshm/internal/config"
+	"sshm/internal/warmpool"
+)
+
+var warmCmd = &cobra.Command{
+	Use:   "warm [server-name]",
+	Short: "Keep connections open to servers so sessions attach instantly",
+	Long: `Manage the warm pool: master connections kept open to servers marked warm
+and to watched servers, so connecting reuses an authenticated connection
+instead of waiting for a fresh handshake. The TUI and 'sshm daemon' keep the
+pool open while they run; a connection without sessions closes once it has
+been idle for the idle timeout.
+
+Servers must log in without prompting to be kept warm: with a key, the agent,
+an auth provider or a password stored in the keyring.
+
+Without arguments the pool settings and the state of each pooled server are
+listed.
+
+Examples:
+  sshm warm --enable              # Turn the warm pool on
+  sshm warm db-01                 # Keep db-01 warm
+  sshm warm db-01 --off           # Stop keeping db-01 warm
+  sshm warm --size 3 --idle 30    # Keep at most 3 servers warm, closing after 30 idle minutes
+  sshm warm --open                # Open the pool's connections now
+  sshm warm --close               # Close the pool's connections
+  sshm warm                       # Show the pool`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		options := warmOptions{}
+		options.off, _ = cmd.Flags().GetBool("off")
+		options.enable, _ = cmd.Flags().GetBool("enable")
+		options.disable, _ = cmd.Flags().GetBool("disable")
+		options.size, _ = cmd.Flags().GetInt("size")
+		options.idle, _ = cmd.Flags().GetInt("idle")
+		options.open, _ = cmd.Flags().GetBool("open")
+		options.close, _ = cmd.Flags().GetBool("close")
+		options.sizeChanged = cmd.Flags().Changed("size")
+		options.idleChanged = cmd.Flags().Changed("idle")
+		if len(args) == 1 {
+			options.server = args[0]
+		}
+		return runWarmCommand(cmd.OutOrStdout(), options)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(warmCmd)
+
+	warmCmd.Flags().Bool("off", false, "Stop keeping the server warm")
+	warmCmd.Flags().Bool("enable", false, "Turn the warm pool on")
+	warmCmd.Flags().Bool("disable", false, "Turn the warm pool off")
+	warmCmd.Flags().Int("size", 0, "Most servers kept warm at once (0 = default)")
+	warmCmd.Flags().Int("idle", 0, "Minutes a connection without sessions stays open (0 = default)")
+	warmCmd.Flags().Bool("open", false, "Open the pool's connections now")
+	warmCmd.Flags().Bool("close", false, "Close the pool's connections")
+}
+
+// warmOptions are the flags of 'sshm warm'
+type warmOptions struct {
+	server                   string
+	off, enable, disable     bool
+	size, idle               int
+	sizeChanged, idleChanged bool
+	open, close              bool
+}
+
+func runWarmCommand(output io.Writer, options warmOptions) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("❌ Failed to load configuration: %w", err)
+	}
+
+	if options.enable && options.disable {
+		return fmt.Errorf("❌ --enable and --disable can't be combined")
+	}
+	if options.open && options.close {
+		return fmt.Errorf("❌ --open and --close can't be combined")
+	}
+	if options.size < 0 || options.idle < 0 {
+		return fmt.Errorf("❌ Size and idle minutes must not be negative")
+	}
+
+	changed := false
+	if options.enable || options.disable {
+		cfg.WarmPool.Enabled = options.enable
+		changed = true
+	}
+	if options.sizeChanged {
+		cfg.WarmPool.Size = options.size
+		changed = true
+	}
+	if options.idleChanged {
+		cfg.WarmPool.IdleMinutes = options.idle
+		changed = true
+	}
+	if options.server != "" {
+		server, err := cfg.GetServer(options.server)
+		if err != nil {
+			return fmt.Errorf("❌ Server '%s' not found. Use 'sshm list' to see available servers", options.server)
+		}
+		server.Warm = !options.off
+		if err := cfg.UpdateServer(*server); err != nil {
+			return fmt.Errorf("❌ Failed to update server: %w", err)
+		}
+		options.server = server.Name
+		changed = true
+	}
+	if changed {
+		if err := cfg.Save(); err != nil {
+			return fmt.Errorf("❌ Failed to save configuration: %w", err)
+		}
+	}
+
+	switch {
+	case options.server != "" && options.off:
+		fmt.Fprintf(output, "%s\n", color.SuccessMessage("'%s' is no longer kept warm", options.server))
+	case options.server != "":
+		fmt.Fprintf(output, "%s\n", color.SuccessMessage("'%s' is kept warm", options.server))
+	case changed:
+		fmt.Fprintf(output, "%s\n", color.SuccessMessage("Warm pool settings saved"))
+	}
+	if options.server != "" && !cfg.WarmPool.Enabled {
+		fmt.Fprintf(output, "%s\n", color.WarningMessage("The warm pool is off; turn it on with 'sshm warm --enable'"))
+	}
+
+	if options.close {
+		closed := 0
+		for _, server := range cfg.GetServers() {
+			if server.HasWarmConnection() {
+				if err := warmpool.Close(server); err != nil {
+					fmt.Fprintf(output, "%s\n", color.WarningMessage("%s: %v", server.Name, err))
+					continue
+				}
+				closed++
+			}
+		}
+		fmt.Fprintf(output, "%s\n", color.SuccessMessage("Closed %d warm connection(s)", closed))
+		return nil
+	}
+
+	if changed && !options.open {
+		return nil
+	}
+	return printWarmPool(output, cfg, options.open)
+}
+
+// printWarmPool lists the pool settings and each pooled server, opening the
+// missing connections first when open is set
+func printWarmPool(output io.Writer, cfg *config.Config, open bool) error {
+	state := "off"
+	if cfg.WarmPool.Enabled {
+		state = "on"
+	}
+	fmt.Fprintf(output, "%s\n", color.InfoText("Warm pool: %s, size %d, idle timeout %v", state, cfg.WarmPool.PoolSize(), cfg.WarmPool.IdleTimeout()))
+
+	var entries []warmpool.Entry
+	if open {
+		if !cfg.WarmPool.Enabled {
+			return fmt.Errorf("❌ The warm pool is off; turn it on with 'sshm warm --enable'")
+		}
+		entries = warmpool.New().Refresh(cfg)
+	} else {
+		for _, server := range cfg.WarmServers() {
+			entries = append(entries, warmpool.Entry{Server: server.Name, Warm: server.HasWarmConnection()})
+		}
+	}
+
+	if len(entries) == 0 {
+		fmt.Fprintf(output, "%s\n", color.InfoText("No servers are kept warm: mark them with 'sshm warm <server>' or watch them"))
+		return nil
+	}
+	for _, entry := range entries {
+		switch {
+		case entry.Err != nil:
+			fmt.Fprintf(output, "  %s: %s\n", entry.Server, color.WarningMessage("%v", entry.Err))
+		case entry.Warm:
+			fmt.Fprintf(output, "  %s: open\n", entry.Server)
+		default:
+			fmt.Fprintf(output, "  %s: not open\n", entry.Server)
+		}
+	}
+	return nil
+}
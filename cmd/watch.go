@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"sshm/internal/color"
+	"sshm/internal/config"
+)
+
+var watchCmd = &cobra.Command{
+	Use:   "watch [server-name]",
+	Short: "Flag servers for fast status checks in the TUI",
+	Long: `Flag a server as watched so the TUI checks its status at a much faster
+interval than other servers and lists it in the watch overlay. This is handy
+while waiting for a host to come back after a reboot.
+
+Without a server name the watched servers and the watch interval are listed.
+
+Examples:
+  sshm watch web-01               # Watch web-01
+  sshm watch web-01 --off         # Stop watching web-01
+  sshm watch --interval 5         # Check watched servers every 5 seconds
+  sshm watch                      # List watched servers`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		off, _ := cmd.Flags().GetBool("off")
+		interval, _ := cmd.Flags().GetInt("interval")
+		serverName := ""
+		if len(args) == 1 {
+			serverName = args[0]
+		}
+		return runWatchCommand(cmd.OutOrStdout(), serverName, off, cmd.Flags().Changed("interval"), interval)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(watchCmd)
+
+	watchCmd.Flags().Bool("off", false, "Stop watching the server")
+	watchCmd.Flags().Int("interval", 0, "Seconds between status checks of watched servers (0 = default)")
+}
+
+func runWatchCommand(output io.Writer, serverName string, off bool, intervalChanged bool, interval int) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("❌ Failed to load configuration: %w", err)
+	}
+
+	if intervalChanged {
+		if interval < 0 {
+			return fmt.Errorf("❌ Interval must not be negative")
+		}
+		cfg.UI.WatchIntervalSeconds = interval
+	}
+
+	if serverName != "" {
+		server, err := cfg.GetServer(serverName)
+		if err != nil {
+			return fmt.Errorf("❌ Server '%s' not found. Use 'sshm list' to see available servers", serverName)
+		}
+		server.Watch = !off
+		if err := cfg.UpdateServer(*server); err != nil {
+			return fmt.Errorf("❌ Failed to update server: %w", err)
+		}
+		serverName = server.Name
+	}
+
+	if intervalChanged || serverName != "" {
+		if err := cfg.Save(); err != nil {
+			return fmt.Errorf("❌ Failed to save configuration: %w", err)
+		}
+	}
+
+	switch {
+	case serverName != "" && off:
+		fmt.Fprintf(output, "%s\n", color.SuccessMessage("Stopped watching '%s'", serverName))
+	case serverName != "":
+		fmt.Fprintf(output, "%s\n", color.SuccessMessage("Watching '%s' every %v", serverName, cfg.UI.WatchInterval()))
+	case intervalChanged:
+		fmt.Fprintf(output, "%s\n", color.SuccessMessage("Watched servers are checked every %v", cfg.UI.WatchInterval()))
+	default:
+		fmt.Fprintf(output, "%s\n", color.InfoText("Watch interval: %v", cfg.UI.WatchInterval()))
+		watched := 0
+		for _, server := range cfg.GetServers() {
+			if server.Watch {
+				fmt.Fprintf(output, "  %s\n", server.Name)
+				watched++
+			}
+		}
+		if watched == 0 {
+			fmt.Fprintf(output, "%s\n", color.InfoText("No servers are watched"))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,246 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"sshm/internal/color"
+	"sshm/internal/config"
+	"sshm/internal/connection"
+	"sshm/internal/tmux"
+	"sshm/internal/workspace"
+)
+
+var workspaceCmd = &cobra.Command{
+	Use:   "workspace",
+	Short: "Save and restore the layout of your sshm tmux sessions",
+	Long: `A workspace is a named snapshot of the running sshm tmux sessions: which
+sessions exist, their windows and the server each window is connected to.
+Restoring a workspace recreates the sessions that are not running and
+reconnects every window, e.g. to resume yesterday's incident layout after a
+reboot. Workspaces are stored in workspaces.yaml next to the configuration.
+
+Examples:
+  sshm workspace save incident-42
+  sshm workspace list
+  sshm workspace restore incident-42
+  sshm workspace delete incident-42`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runWorkspaceListCommand(cmd.OutOrStdout())
+	},
+}
+
+var workspaceSaveCmd = &cobra.Command{
+	Use:   "save <name>",
+	Short: "Snapshot the running sshm sessions as a workspace",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		force, _ := cmd.Flags().GetBool("force")
+		return runWorkspaceSaveCommand(cmd.OutOrStdout(), args[0], force)
+	},
+}
+
+var workspaceRestoreCmd = &cobra.Command{
+	Use:   "restore <name>",
+	Short: "Recreate a workspace's sessions and reconnect their servers",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runWorkspaceRestoreCommand(cmd.OutOrStdout(), args[0])
+	},
+}
+
+var workspaceListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List saved workspaces",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runWorkspaceListCommand(cmd.OutOrStdout())
+	},
+}
+
+var workspaceDeleteCmd = &cobra.Command{
+	Use:   "delete <name>",
+	Short: "Delete a saved workspace",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runWorkspaceDeleteCommand(cmd.OutOrStdout(), args[0])
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(workspaceCmd)
+	workspaceCmd.AddCommand(workspaceSaveCmd)
+	workspaceCmd.AddCommand(workspaceRestoreCmd)
+	workspaceCmd.AddCommand(workspaceListCmd)
+	workspaceCmd.AddCommand(workspaceDeleteCmd)
+
+	workspaceSaveCmd.Flags().BoolP("force", "f", false, "Overwrite an existing workspace with the same name")
+}
+
+// loadWorkspaces loads the workspace store and returns it with its path
+func loadWorkspaces() (*workspace.Store, string, error) {
+	path, err := workspace.DefaultPath()
+	if err != nil {
+		return nil, "", fmt.Errorf("❌ Failed to determine workspace path: %w", err)
+	}
+	store, err := workspace.Load(path)
+	if err != nil {
+		return nil, "", fmt.Errorf("❌ %w", err)
+	}
+	return store, path, nil
+}
+
+func runWorkspaceSaveCommand(output io.Writer, name string, force bool) error {
+	if strings.TrimSpace(name) == "" {
+		return fmt.Errorf("❌ Workspace name must not be empty")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("❌ Failed to load configuration: %w", err)
+	}
+	store, path, err := loadWorkspaces()
+	if err != nil {
+		return err
+	}
+	if _, err := store.Get(name); err == nil && !force {
+		return fmt.Errorf("❌ Workspace '%s' already exists (use --force to overwrite)", name)
+	}
+
+	manager := tmux.NewManager()
+	if !manager.IsAvailable() {
+		return fmt.Errorf("❌ tmux is not available on this system")
+	}
+
+	var serverNames []string
+	for _, server := range cfg.GetServers() {
+		serverNames = append(serverNames, server.Name)
+	}
+	snapshot, err := workspace.Capture(name, manager, serverNames)
+	if err != nil {
+		return fmt.Errorf("❌ Failed to capture workspace: %w", err)
+	}
+
+	store.Put(snapshot)
+	if err := store.Save(path); err != nil {
+		return fmt.Errorf("❌ %w", err)
+	}
+
+	fmt.Fprintf(output, "%s\n", color.SuccessMessage("Saved workspace '%s' with %d session(s) and %d server(s)",
+		name, len(snapshot.Sessions), len(snapshot.ServerNames())))
+	for _, session := range snapshot.Sessions {
+		fmt.Fprintf(output, "%s\n", color.InfoText("  %s: %s", session.Name, describeWorkspaceWindows(session.Windows)))
+	}
+	return nil
+}
+
+func runWorkspaceRestoreCommand(output io.Writer, name string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("❌ Failed to load configuration: %w", err)
+	}
+	store, _, err := loadWorkspaces()
+	if err != nil {
+		return err
+	}
+	saved, err := store.Get(name)
+	if err != nil {
+		return fmt.Errorf("❌ %w", err)
+	}
+
+	manager := tmux.NewManager()
+	if !manager.IsAvailable() {
+		return fmt.Errorf("❌ tmux is not available on this system")
+	}
+
+	// Servers whose current login banner wasn't accepted aren't reconnected
+	connectionManager, err := connection.NewManager()
+	if err != nil {
+		return fmt.Errorf("❌ Failed to initialize connection manager: %w", err)
+	}
+	defer connectionManager.Close()
+	var servers []config.Server
+	for _, serverName := range saved.ServerNames() {
+		if server, err := cfg.GetServer(serverName); err == nil {
+			servers = append(servers, *server)
+		}
+	}
+	acks := connectionManager.AcknowledgedBanners(servers)
+
+	failed := 0
+	for _, result := range workspace.Restore(*saved, manager, cfg, acks) {
+		switch {
+		case result.Err != nil:
+			failed++
+			fmt.Fprintf(output, "%s\n", color.WarningMessage("%s: %v", result.Session, result.Err))
+		case result.Skipped:
+			fmt.Fprintf(output, "%s\n", color.InfoText("  %s: already running, left as is", result.Session))
+		default:
+			fmt.Fprintf(output, "%s\n", color.SuccessMessage("Restored session '%s'", result.Session))
+		}
+		if len(result.MissingServers) > 0 {
+			fmt.Fprintf(output, "%s\n", color.WarningMessage("%s: servers no longer configured, opened a plain shell instead: %s",
+				result.Session, strings.Join(result.MissingServers, ", ")))
+		}
+		if len(result.BannerServers) > 0 {
+			fmt.Fprintf(output, "%s\n", color.WarningMessage("%s: login banner to accept first with 'sshm connect', opened a plain shell instead: %s",
+				result.Session, strings.Join(result.BannerServers, ", ")))
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("❌ %d session(s) could not be restored", failed)
+	}
+	fmt.Fprintf(output, "%s\n", color.InfoMessage("Use 'tmux attach -t <session>' or the TUI sessions panel to attach"))
+	return nil
+}
+
+func runWorkspaceListCommand(output io.Writer) error {
+	store, _, err := loadWorkspaces()
+	if err != nil {
+		return err
+	}
+
+	if len(store.Workspaces) == 0 {
+		fmt.Fprintf(output, "%s\n", color.InfoMessage("No saved workspaces (use 'sshm workspace save <name>')"))
+		return nil
+	}
+
+	fmt.Fprintf(output, "%s\n", color.InfoMessage("Saved workspaces (%d):", len(store.Workspaces)))
+	for _, saved := range store.Workspaces {
+		fmt.Fprintf(output, "%s\n", color.InfoText("  %s  %d session(s), %d server(s)  saved %s", saved.Name,
+			len(saved.Sessions), len(saved.ServerNames()), saved.CreatedAt.Local().Format("2006-01-02 15:04")))
+	}
+	return nil
+}
+
+func runWorkspaceDeleteCommand(output io.Writer, name string) error {
+	store, path, err := loadWorkspaces()
+	if err != nil {
+		return err
+	}
+	if err := store.Delete(name); err != nil {
+		return fmt.Errorf("❌ %w", err)
+	}
+	if err := store.Save(path); err != nil {
+		return fmt.Errorf("❌ %w", err)
+	}
+	fmt.Fprintf(output, "%s\n", color.SuccessMessage("Workspace '%s' deleted", name))
+	return nil
+}
+
+// describeWorkspaceWindows renders a session's windows as "window (server), ..."
+func describeWorkspaceWindows(windows []workspace.Window) string {
+	parts := make([]string, 0, len(windows))
+	for _, window := range windows {
+		if window.Server != "" && window.Server != window.Name {
+			parts = append(parts, fmt.Sprintf("%s (%s)", window.Name, window.Server))
+		} else {
+			parts = append(parts, window.Name)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,141 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"sshm/internal/config"
+	"sshm/internal/power"
+)
+
+// maxParallel limits how many servers are audited at the same time
+const maxParallel = 5
+
+// updatesScript detects the package manager and prints key=value lines with
+// the number of pending and security updates. It only reads package metadata
+// already on the host and never installs anything.
+const updatesScript = `if command -v apt-get >/dev/null 2>&1; then
+  list=$(apt list --upgradable 2>/dev/null | grep -v '^Listing')
+  echo "manager=apt"
+  echo "total=$(printf '%s\n' "$list" | grep -c .)"
+  echo "security=$(printf '%s\n' "$list" | grep -c -- '-security')"
+elif command -v dnf >/dev/null 2>&1; then
+  echo "manager=dnf"
+  echo "total=$(dnf -q check-update 2>/dev/null | grep -cE '^[[:alnum:]_.+-]+\.[[:alnum:]_]+[[:space:]]')"
+  echo "security=$(dnf -q updateinfo list --security 2>/dev/null | grep -c .)"
+elif command -v yum >/dev/null 2>&1; then
+  echo "manager=yum"
+  echo "total=$(yum -q check-update 2>/dev/null | grep -cE '^[[:alnum:]_.+-]+\.[[:alnum:]_]+[[:space:]]')"
+  echo "security=$(yum -q updateinfo list security 2>/dev/null | grep -c .)"
+else
+  echo "manager=unknown"
+fi`
+
+// runRemote executes a command on a server (variable to allow mocking in tests)
+var runRemote = power.Run
+
+// Result is the pending update summary of one server
+type Result struct {
+	Server   string
+	Manager  string // apt, dnf, yum or unknown
+	Total    int
+	Security int
+	Error    string
+}
+
+// HasSecurityUpdates reports whether the server has security updates outstanding
+func (r Result) HasSecurityUpdates() bool {
+	return r.Error == "" && r.Security > 0
+}
+
+// Updates audits pending package updates on the servers in parallel. Results are
+// sorted with the most security updates first, then by server name.
+func Updates(servers []config.Server) []Result {
+	results := make([]Result, len(servers))
+
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, maxParallel)
+	for i, server := range servers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			output, err := runRemote(server, updatesScript)
+			if err != nil {
+				results[i] = Result{Server: server.Name, Error: err.Error()}
+				return
+			}
+			results[i] = parseUpdates(server.Name, output)
+		}()
+	}
+	wg.Wait()
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Security != results[j].Security {
+			return results[i].Security > results[j].Security
+		}
+		return results[i].Server < results[j].Server
+	})
+	return results
+}
+
+// parseUpdates reads the key=value output of updatesScript
+func parseUpdates(serverName, output string) Result {
+	result := Result{Server: serverName, Manager: "unknown"}
+
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(strings.TrimSpace(scanner.Text()), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "manager":
+			result.Manager = value
+		case "total":
+			result.Total, _ = strconv.Atoi(value)
+		case "security":
+			result.Security, _ = strconv.Atoi(value)
+		}
+	}
+
+	if result.Manager == "unknown" {
+		result.Error = "no supported package manager found (apt, dnf or yum)"
+	}
+	return result
+}
+
+// WriteCSV writes audit results as CSV with a header row
+func WriteCSV(w io.Writer, results []Result, auditedAt time.Time) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"server", "package_manager", "pending_updates", "security_updates", "error", "audited_at"}); err != nil {
+		return err
+	}
+	for _, result := range results {
+		record := []string{
+			result.Server,
+			result.Manager,
+			strconv.Itoa(result.Total),
+			strconv.Itoa(result.Security),
+			result.Error,
+			auditedAt.Format(time.RFC3339),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return nil
+}
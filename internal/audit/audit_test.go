@@ -0,0 +1,73 @@
+package audit
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"sshm/internal/config"
+)
+
+func mockRemote(t *testing.T, outputs map[string]string) {
+	original := runRemote
+	runRemote = func(server config.Server, command string) (string, error) {
+		output, ok := outputs[server.Name]
+		if !ok {
+			return "", errors.New("connection refused")
+		}
+		return output, nil
+	}
+	t.Cleanup(func() { runRemote = original })
+}
+
+func TestParseUpdates(t *testing.T) {
+	result := parseUpdates("web1", "manager=apt\ntotal=12\nsecurity=3\n")
+	if result.Manager != "apt" || result.Total != 12 || result.Security != 3 || result.Error != "" {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+
+	result = parseUpdates("legacy", "manager=unknown\n")
+	if result.Error == "" {
+		t.Error("Expected an error for hosts without a supported package manager")
+	}
+}
+
+func TestUpdatesSortsBySecurityUpdates(t *testing.T) {
+	mockRemote(t, map[string]string{
+		"web1": "manager=apt\ntotal=2\nsecurity=0",
+		"db1":  "manager=dnf\ntotal=9\nsecurity=4",
+	})
+
+	servers := []config.Server{{Name: "web1"}, {Name: "db1"}, {Name: "down"}}
+	results := Updates(servers)
+
+	if len(results) != 3 {
+		t.Fatalf("Expected 3 results, got %d", len(results))
+	}
+	if results[0].Server != "db1" || !results[0].HasSecurityUpdates() {
+		t.Errorf("Expected db1 with security updates first, got %+v", results[0])
+	}
+	for _, result := range results {
+		if result.Server == "down" && result.Error == "" {
+			t.Error("Expected an error for the unreachable server")
+		}
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	results := []Result{{Server: "db1", Manager: "dnf", Total: 9, Security: 4}}
+	if err := WriteCSV(&buf, results, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected header and one row, got %d lines", len(lines))
+	}
+	if lines[1] != "db1,dnf,9,4,,2024-01-02T03:04:05Z" {
+		t.Errorf("Unexpected CSV row: %s", lines[1])
+	}
+}
@@ -0,0 +1,357 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"sshm/internal/config"
+)
+
+// keysScript prints the authorized_keys entries of the connecting user as
+// "key <user> <entry>" lines, with "user <name>" before each user and
+// "unreadable <user> <path>" for files that exist but can't be read. It only
+// reads files.
+const keysScript = `me=$(id -un)
+emit() {
+  echo "user $1"
+  if [ -d "$2/.ssh" ] && ! [ -x "$2/.ssh" ]; then
+    echo "unreadable $1 $2/.ssh"
+    return
+  fi
+  for f in "$2/.ssh/authorized_keys" "$2/.ssh/authorized_keys2"; do
+    if [ -r "$f" ]; then
+      while IFS= read -r line || [ -n "$line" ]; do
+        echo "key $1 $line"
+      done < "$f"
+    elif [ -e "$f" ]; then
+      echo "unreadable $1 $f"
+    fi
+  done
+}
+emit "$me" "$HOME"`
+
+// loginUsersScript extends keysScript to every user with a login shell. Other
+// users' files are usually only readable as root; those that aren't are
+// reported as unreadable.
+const loginUsersScript = `
+{ getent passwd 2>/dev/null || cat /etc/passwd; } | while IFS=: read -r name _ _ _ _ home shell; do
+  case "$shell" in ''|*/nologin|*/false|*/sync|*/shutdown|*/halt) continue ;; esac
+  [ "$name" = "$me" ] && continue
+  emit "$name" "$home"
+done`
+
+// KeyGrant is one authorized_keys entry: a key that logs in as a user
+type KeyGrant struct {
+	User        string
+	Fingerprint string // SHA256:...
+	Type        string // e.g. ssh-ed25519
+	Comment     string
+	Options     []string // e.g. from="10.0.0.0/8" or command="..."
+}
+
+// KeysResult is what the authorized_keys audit found on one server
+type KeysResult struct {
+	Server     string
+	Users      []string // Users whose keys were read, the connecting user first
+	Grants     []KeyGrant
+	Unreadable []string // authorized_keys files or .ssh directories that couldn't be read
+	Invalid    int      // Entries that aren't public keys
+	Error      string
+}
+
+// AuthorizedKeys gathers the authorized_keys entries of the servers in
+// parallel, of the connecting user or, with loginUsers, of every user with a
+// login shell. Results are sorted by server name.
+func AuthorizedKeys(servers []config.Server, loginUsers bool) []KeysResult {
+	script := keysScript
+	if loginUsers {
+		script += loginUsersScript
+	}
+
+	results := make([]KeysResult, len(servers))
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, maxParallel)
+	for i, server := range servers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			output, err := runRemote(server, script)
+			if err != nil {
+				results[i] = KeysResult{Server: server.Name, Error: err.Error()}
+				return
+			}
+			results[i] = parseAuthorizedKeys(server.Name, output)
+		}()
+	}
+	wg.Wait()
+
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Server < results[j].Server })
+	return results
+}
+
+// parseAuthorizedKeys reads the output of keysScript
+func parseAuthorizedKeys(serverName, output string) KeysResult {
+	result := KeysResult{Server: serverName}
+
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024) // Keys with long options
+	for scanner.Scan() {
+		kind, rest, _ := strings.Cut(scanner.Text(), " ")
+		switch kind {
+		case "user":
+			result.Users = append(result.Users, strings.TrimSpace(rest))
+		case "unreadable":
+			result.Unreadable = append(result.Unreadable, strings.TrimSpace(rest))
+		case "key":
+			user, entry, _ := strings.Cut(rest, " ")
+			entry = strings.TrimSpace(entry)
+			if entry == "" || strings.HasPrefix(entry, "#") {
+				continue
+			}
+			publicKey, comment, options, _, err := ssh.ParseAuthorizedKey([]byte(entry))
+			if err != nil {
+				result.Invalid++
+				continue
+			}
+			result.Grants = append(result.Grants, KeyGrant{
+				User:        user,
+				Fingerprint: ssh.FingerprintSHA256(publicKey),
+				Type:        publicKey.Type(),
+				Comment:     comment,
+				Options:     options,
+			})
+		}
+	}
+	return result
+}
+
+// KnownKeys names the public keys the audit recognizes, by fingerprint
+type KnownKeys map[string]string
+
+// LoadKnownKeys collects the keys the audit recognizes: the configuration's
+// known_keys, the public keys in ~/.ssh and those next to the servers' key
+// files. A key keeps the first name it was given.
+func LoadKnownKeys(cfg *config.Config) (KnownKeys, error) {
+	known := make(KnownKeys)
+	for _, entry := range cfg.KnownKeys {
+		fingerprint, err := keyFingerprint(entry.Key)
+		if err != nil {
+			return nil, fmt.Errorf("known key '%s': %w", entry.Name, err)
+		}
+		known.add(fingerprint, entry.Name)
+	}
+
+	if homeDir, err := os.UserHomeDir(); err == nil {
+		paths, _ := filepath.Glob(filepath.Join(homeDir, ".ssh", "*.pub"))
+		for _, path := range paths {
+			known.addPublicKeyFile(path, "local "+filepath.Base(path))
+		}
+	}
+	for _, server := range cfg.GetServers() {
+		if server.KeyPath == "" {
+			continue
+		}
+		if path, err := config.ExpandPath(server.KeyPath + ".pub"); err == nil {
+			known.addPublicKeyFile(path, "local "+filepath.Base(path))
+		}
+	}
+	return known, nil
+}
+
+// AddFile recognizes the keys of an authorized_keys style file, named by
+// their comments, e.g. a team's list of approved keys
+func (k KnownKeys) AddFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	for number, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		publicKey, comment, _, _, err := ssh.ParseAuthorizedKey([]byte(line))
+		if err != nil {
+			return fmt.Errorf("%s line %d is not a public key", path, number+1)
+		}
+		if comment == "" {
+			comment = filepath.Base(path)
+		}
+		k.add(ssh.FingerprintSHA256(publicKey), comment)
+	}
+	return nil
+}
+
+// Name returns what a key is known as, or "" when it's unknown
+func (k KnownKeys) Name(fingerprint string) string {
+	return k[fingerprint]
+}
+
+// add names a key unless it already has a name
+func (k KnownKeys) add(fingerprint, name string) {
+	if _, ok := k[fingerprint]; !ok {
+		k[fingerprint] = name
+	}
+}
+
+// addPublicKeyFile recognizes a single public key file, ignoring unreadable ones
+func (k KnownKeys) addPublicKeyFile(path, name string) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+	if publicKey, _, _, _, err := ssh.ParseAuthorizedKey(data); err == nil {
+		k.add(ssh.FingerprintSHA256(publicKey), name)
+	}
+}
+
+// keyFingerprint returns the fingerprint of a public key line, or the
+// fingerprint itself
+func keyFingerprint(key string) (string, error) {
+	key = strings.TrimSpace(key)
+	if strings.HasPrefix(key, config.HostKeyFingerprintPrefix) {
+		return key, nil
+	}
+	publicKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(key))
+	if err != nil {
+		return "", fmt.Errorf("not a public key or SHA256 fingerprint")
+	}
+	return ssh.FingerprintSHA256(publicKey), nil
+}
+
+// KeyAccess is everywhere one key grants access
+type KeyAccess struct {
+	Fingerprint string
+	Type        string
+	Comments    []string // Comments the key carries in the files, sorted
+	Known       string   // What the key is known as, "" when unknown
+	Logins      []string // user@server it logs in as, sorted
+}
+
+// IsUnknown reports whether the key is neither local nor a known key
+func (a KeyAccess) IsUnknown() bool {
+	return a.Known == ""
+}
+
+// AggregateKeys groups the grants found on the servers by key. Unknown keys
+// come first, then the keys granting the most logins.
+func AggregateKeys(results []KeysResult, known KnownKeys) []KeyAccess {
+	byFingerprint := make(map[string]*KeyAccess)
+	var order []string
+	for _, result := range results {
+		for _, grant := range result.Grants {
+			access, ok := byFingerprint[grant.Fingerprint]
+			if !ok {
+				access = &KeyAccess{Fingerprint: grant.Fingerprint, Type: grant.Type, Known: known.Name(grant.Fingerprint)}
+				byFingerprint[grant.Fingerprint] = access
+				order = append(order, grant.Fingerprint)
+			}
+			if grant.Comment != "" && !containsString(access.Comments, grant.Comment) {
+				access.Comments = append(access.Comments, grant.Comment)
+			}
+			login := grant.User + "@" + result.Server
+			if !containsString(access.Logins, login) {
+				access.Logins = append(access.Logins, login)
+			}
+		}
+	}
+
+	keys := make([]KeyAccess, 0, len(order))
+	for _, fingerprint := range order {
+		access := *byFingerprint[fingerprint]
+		sort.Strings(access.Comments)
+		sort.Strings(access.Logins)
+		keys = append(keys, access)
+	}
+	sort.SliceStable(keys, func(i, j int) bool {
+		if keys[i].IsUnknown() != keys[j].IsUnknown() {
+			return keys[i].IsUnknown()
+		}
+		if len(keys[i].Logins) != len(keys[j].Logins) {
+			return len(keys[i].Logins) > len(keys[j].Logins)
+		}
+		return keys[i].Fingerprint < keys[j].Fingerprint
+	})
+	return keys
+}
+
+// containsString reports whether values holds value
+func containsString(values []string, value string) bool {
+	for _, existing := range values {
+		if existing == value {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteKeysCSV writes one row per authorized_keys entry with a header row
+func WriteKeysCSV(w io.Writer, results []KeysResult, known KnownKeys, auditedAt time.Time) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"server", "user", "fingerprint", "type", "comment", "known_as", "options", "audited_at"}); err != nil {
+		return err
+	}
+	for _, result := range results {
+		for _, grant := range result.Grants {
+			record := []string{
+				result.Server,
+				grant.User,
+				grant.Fingerprint,
+				grant.Type,
+				grant.Comment,
+				known.Name(grant.Fingerprint),
+				strings.Join(grant.Options, ","),
+				auditedAt.Format(time.RFC3339),
+			}
+			if err := writer.Write(record); err != nil {
+				return err
+			}
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return nil
+}
+
+// UnknownKeyCount returns how many distinct keys aren't known
+func UnknownKeyCount(keys []KeyAccess) int {
+	count := 0
+	for _, key := range keys {
+		if key.IsUnknown() {
+			count++
+		}
+	}
+	return count
+}
+
+// KeysSummary describes an authorized_keys audit in one line
+func KeysSummary(results []KeysResult, keys []KeyAccess) string {
+	entries, failed := 0, 0
+	for _, result := range results {
+		entries += len(result.Grants)
+		if result.Error != "" {
+			failed++
+		}
+	}
+	summary := fmt.Sprintf("%d key(s) in %d entries on %d server(s), %d unknown",
+		len(keys), entries, len(results)-failed, UnknownKeyCount(keys))
+	if failed > 0 {
+		summary += ", " + strconv.Itoa(failed) + " unreachable"
+	}
+	return summary
+}
@@ -0,0 +1,159 @@
+package audit
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"sshm/internal/config"
+)
+
+// newPublicKey returns a fresh authorized_keys line and its fingerprint
+func newPublicKey(t *testing.T, comment string) (string, string) {
+	public, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicKey, err := ssh.NewPublicKey(public)
+	if err != nil {
+		t.Fatal(err)
+	}
+	line := strings.TrimSpace(string(ssh.MarshalAuthorizedKey(publicKey))) + " " + comment
+	return line, ssh.FingerprintSHA256(publicKey)
+}
+
+func TestParseAuthorizedKeys(t *testing.T) {
+	alice, aliceFingerprint := newPublicKey(t, "alice@laptop")
+	output := strings.Join([]string{
+		"user ops",
+		"key ops " + alice,
+		"key ops # a comment",
+		"key ops ",
+		"key ops not a key",
+		"user deploy",
+		`key deploy from="10.0.0.0/8",no-pty ` + alice,
+		"unreadable root /root/.ssh",
+	}, "\n")
+
+	result := parseAuthorizedKeys("web1", output)
+	if strings.Join(result.Users, ",") != "ops,deploy" {
+		t.Errorf("Unexpected users: %v", result.Users)
+	}
+	if len(result.Grants) != 2 || result.Invalid != 1 {
+		t.Fatalf("Expected two grants and one invalid line, got %+v", result)
+	}
+	if result.Grants[0].Fingerprint != aliceFingerprint || result.Grants[0].Comment != "alice@laptop" || result.Grants[0].Type != "ssh-ed25519" {
+		t.Errorf("Unexpected grant: %+v", result.Grants[0])
+	}
+	if result.Grants[1].User != "deploy" || strings.Join(result.Grants[1].Options, ",") != `from="10.0.0.0/8",no-pty` {
+		t.Errorf("Expected the deploy grant with its options, got %+v", result.Grants[1])
+	}
+	if len(result.Unreadable) != 1 || result.Unreadable[0] != "root /root/.ssh" {
+		t.Errorf("Unexpected unreadable files: %v", result.Unreadable)
+	}
+}
+
+func TestAuthorizedKeysAggregatesByKey(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	alice, aliceFingerprint := newPublicKey(t, "alice@laptop")
+	stranger, strangerFingerprint := newPublicKey(t, "")
+	mockRemote(t, map[string]string{
+		"web1": "user ops\nkey ops " + alice + "\nkey ops " + stranger,
+		"web2": "user ops\nkey ops " + alice + "\nuser root\nkey root " + alice,
+	})
+
+	results := AuthorizedKeys([]config.Server{{Name: "web2"}, {Name: "down"}, {Name: "web1"}}, false)
+	if len(results) != 3 || results[0].Server != "down" || results[0].Error == "" {
+		t.Fatalf("Expected results sorted by server with an error for down, got %+v", results)
+	}
+
+	cfg := &config.Config{KnownKeys: []config.KnownKey{{Name: "Alice", Key: aliceFingerprint}}}
+	known, err := LoadKnownKeys(cfg)
+	if err != nil {
+		t.Fatalf("LoadKnownKeys() error = %v", err)
+	}
+	keys := AggregateKeys(results, known)
+	if len(keys) != 2 {
+		t.Fatalf("Expected two distinct keys, got %+v", keys)
+	}
+	if keys[0].Fingerprint != strangerFingerprint || !keys[0].IsUnknown() {
+		t.Errorf("Expected the unknown key first, got %+v", keys[0])
+	}
+	if keys[1].Known != "Alice" || strings.Join(keys[1].Logins, " ") != "ops@web1 ops@web2 root@web2" {
+		t.Errorf("Expected Alice's logins, got %+v", keys[1])
+	}
+	if UnknownKeyCount(keys) != 1 {
+		t.Errorf("UnknownKeyCount() = %d", UnknownKeyCount(keys))
+	}
+	if summary := KeysSummary(results, keys); summary != "2 key(s) in 4 entries on 2 server(s), 1 unknown, 1 unreachable" {
+		t.Errorf("KeysSummary() = %q", summary)
+	}
+}
+
+func TestLoadKnownKeys(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	local, localFingerprint := newPublicKey(t, "me@here")
+	os.MkdirAll(filepath.Join(home, ".ssh"), 0700)
+	os.WriteFile(filepath.Join(home, ".ssh", "id_ed25519.pub"), []byte(local+"\n"), 0600)
+	deploy, deployFingerprint := newPublicKey(t, "ci")
+
+	cfg := &config.Config{KnownKeys: []config.KnownKey{{Name: "CI deploy key", Key: deploy}}}
+	known, err := LoadKnownKeys(cfg)
+	if err != nil {
+		t.Fatalf("LoadKnownKeys() error = %v", err)
+	}
+	if known.Name(localFingerprint) != "local id_ed25519.pub" || known.Name(deployFingerprint) != "CI deploy key" {
+		t.Errorf("Unexpected known keys: %v", known)
+	}
+
+	cfg.KnownKeys = []config.KnownKey{{Name: "broken", Key: "ssh-ed25519 nonsense"}}
+	if _, err := LoadKnownKeys(cfg); err == nil {
+		t.Error("Expected an invalid known key to be rejected")
+	}
+}
+
+func TestKnownKeysAddFile(t *testing.T) {
+	bob, bobFingerprint := newPublicKey(t, "bob@team")
+	path := filepath.Join(t.TempDir(), "team.pub")
+	os.WriteFile(path, []byte("# approved keys\n"+bob+"\n\n"), 0600)
+
+	known := make(KnownKeys)
+	if err := known.AddFile(path); err != nil {
+		t.Fatalf("AddFile() error = %v", err)
+	}
+	if known.Name(bobFingerprint) != "bob@team" {
+		t.Errorf("Expected the key named by its comment, got %v", known)
+	}
+
+	os.WriteFile(path, []byte("garbage\n"), 0600)
+	if err := known.AddFile(path); err == nil || !strings.Contains(err.Error(), "line 1") {
+		t.Errorf("Expected an error naming the line, got %v", err)
+	}
+}
+
+func TestWriteKeysCSV(t *testing.T) {
+	alice, aliceFingerprint := newPublicKey(t, "alice@laptop")
+	results := []KeysResult{parseAuthorizedKeys("web1", "user ops\nkey ops no-pty "+alice)}
+	known := KnownKeys{aliceFingerprint: "Alice"}
+
+	var buf bytes.Buffer
+	auditedAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	if err := WriteKeysCSV(&buf, results, known, auditedAt); err != nil {
+		t.Fatalf("WriteKeysCSV() error = %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected a header and one row, got %q", buf.String())
+	}
+	expected := "web1,ops," + aliceFingerprint + ",ssh-ed25519,alice@laptop,Alice,no-pty,2026-03-01T12:00:00Z"
+	if lines[1] != expected {
+		t.Errorf("Unexpected row:\n%s\nwant\n%s", lines[1], expected)
+	}
+}
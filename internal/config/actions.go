@@ -0,0 +1,147 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"sshm/internal/shellquote"
+)
+
+// What a custom action applies to
+const (
+	ActionScopeServer  = "server"
+	ActionScopeProfile = "profile"
+	ActionScopeSession = "session"
+)
+
+// ActionScopes lists the scopes of custom actions
+var ActionScopes = []string{ActionScopeServer, ActionScopeProfile, ActionScopeSession}
+
+// Where a custom action's command runs
+const (
+	ActionRunLocal  = "local"
+	ActionRunRemote = "remote"
+)
+
+// ActionPlaceholders are the placeholders substituted in custom action commands.
+// Server placeholders ({name}, {host}, {user}, {port}, {key}) are filled for
+// server actions, remote actions and sessions of a known server.
+var ActionPlaceholders = []string{"name", "host", "user", "port", "key", "profile", "servers", "session"}
+
+// CustomAction is a user-defined verb shown in the TUI's actions menu, e.g.
+// "Open Grafana" running a local browser or "Restart app" over ssh
+type CustomAction struct {
+	Name    string `yaml:"name" json:"name"`
+	Scope   string `yaml:"scope,omitempty" json:"scope,omitempty"`     // "server" (default), "profile" or "session"
+	Command string `yaml:"command" json:"command"`                     // Command with {placeholders}, e.g. "xdg-open https://grafana/d/{name}"
+	Run     string `yaml:"run,omitempty" json:"run,omitempty"`         // "local" (default) or "remote": on each server over ssh
+	Confirm bool   `yaml:"confirm,omitempty" json:"confirm,omitempty"` // Ask before running
+}
+
+// GetScope returns the scope of the action, server when not set
+func (a *CustomAction) GetScope() string {
+	if scope := strings.ToLower(strings.TrimSpace(a.Scope)); scope != "" {
+		return scope
+	}
+	return ActionScopeServer
+}
+
+// IsRemote reports whether the command runs on the servers over ssh
+func (a *CustomAction) IsRemote() bool {
+	return strings.ToLower(strings.TrimSpace(a.Run)) == ActionRunRemote
+}
+
+// Validate checks the scope, run location and placeholders of the action
+func (a *CustomAction) Validate() error {
+	if strings.TrimSpace(a.Name) == "" {
+		return fmt.Errorf("custom action name is required")
+	}
+	if !contains(ActionScopes, a.GetScope()) {
+		return fmt.Errorf("action '%s': scope must be one of %s, got '%s'", a.Name, strings.Join(ActionScopes, ", "), a.Scope)
+	}
+	if run := strings.ToLower(strings.TrimSpace(a.Run)); run != "" && run != ActionRunLocal && run != ActionRunRemote {
+		return fmt.Errorf("action '%s': run must be '%s' or '%s', got '%s'", a.Name, ActionRunLocal, ActionRunRemote, a.Run)
+	}
+	if strings.TrimSpace(a.Command) == "" {
+		return fmt.Errorf("action '%s': command is required", a.Name)
+	}
+	for _, match := range templatePlaceholder.FindAllStringSubmatch(a.Command, -1) {
+		if !contains(ActionPlaceholders, match[1]) {
+			return fmt.Errorf("action '%s': unknown placeholder {%s} in command (supported: {%s})",
+				a.Name, match[1], strings.Join(ActionPlaceholders, "}, {"))
+		}
+	}
+	return nil
+}
+
+// ActionsFor returns the custom actions of a scope in configuration order
+func (c *Config) ActionsFor(scope string) []CustomAction {
+	var actions []CustomAction
+	for _, action := range c.Actions {
+		if action.GetScope() == scope {
+			actions = append(actions, action)
+		}
+	}
+	return actions
+}
+
+// ActionContext holds the values substituted in an action's command. Server is
+// nil when the action doesn't target a single server.
+type ActionContext struct {
+	Server  *Server
+	Profile string
+	Servers []string
+	Session string
+}
+
+// ExpandCommand substitutes the placeholders of the action's command, quoted
+// for the shell so names can't inject commands; {servers} becomes one word per
+// server. Using a placeholder without a value in this context is an error
+// rather than running a half-filled command.
+func (a *CustomAction) ExpandCommand(ctx ActionContext) (string, error) {
+	// Empty values stay empty so they are reported as missing below
+	quote := func(value string) string {
+		if value == "" {
+			return ""
+		}
+		return shellquote.Arg(value)
+	}
+	values := map[string]string{
+		"profile": quote(ctx.Profile),
+		"servers": shellquote.Join(ctx.Servers),
+		"session": quote(ctx.Session),
+	}
+	if ctx.Server != nil {
+		port := ctx.Server.Port
+		if port == 0 {
+			port = 22
+		}
+		keyPath, err := ExpandPath(ctx.Server.KeyPath)
+		if err != nil {
+			keyPath = ctx.Server.KeyPath
+		}
+		values["name"] = quote(ctx.Server.Name)
+		values["host"] = quote(ctx.Server.GetEffectiveHostname())
+		values["user"] = quote(ctx.Server.Username)
+		values["port"] = strconv.Itoa(port)
+		values["key"] = quote(keyPath)
+	}
+
+	var missing []string
+	expanded := templatePlaceholder.ReplaceAllStringFunc(a.Command, func(match string) string {
+		name := match[1 : len(match)-1]
+		value, ok := values[name]
+		if !ok || value == "" {
+			if name != "key" {
+				missing = append(missing, match)
+			}
+			return ""
+		}
+		return value
+	})
+	if len(missing) > 0 {
+		return "", fmt.Errorf("action '%s': %s not available here", a.Name, strings.Join(missing, ", "))
+	}
+	return strings.TrimSpace(expanded), nil
+}
@@ -0,0 +1,91 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCustomActionValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		action  CustomAction
+		wantErr string
+	}{
+		{"defaults to a local server action", CustomAction{Name: "Grafana", Command: "xdg-open https://grafana/d/{name}"}, ""},
+		{"remote profile action", CustomAction{Name: "Uptime", Scope: "profile", Run: "remote", Command: "uptime"}, ""},
+		{"missing name", CustomAction{Command: "true"}, "name is required"},
+		{"missing command", CustomAction{Name: "Empty"}, "command is required"},
+		{"unknown scope", CustomAction{Name: "X", Scope: "tag", Command: "true"}, "scope must be one of"},
+		{"unknown run", CustomAction{Name: "X", Run: "somewhere", Command: "true"}, "run must be"},
+		{"unknown placeholder", CustomAction{Name: "X", Command: "echo {hostname}"}, "unknown placeholder {hostname}"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.action.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestCustomActionExpandCommand(t *testing.T) {
+	server := &Server{Name: "web1", Hostname: "10.0.0.5", Username: "deploy", AuthType: "password"}
+
+	action := CustomAction{Name: "Copy id", Command: "ssh-copy-id -p {port} {user}@{host} {key}"}
+	command, err := action.ExpandCommand(ActionContext{Server: server})
+	if err != nil {
+		t.Fatalf("ExpandCommand() error = %v", err)
+	}
+	if command != "ssh-copy-id -p 22 deploy@10.0.0.5" {
+		t.Errorf("Unexpected command %q", command)
+	}
+
+	action = CustomAction{Name: "Fleet", Scope: "profile", Command: "notify {profile}: {servers}"}
+	command, err = action.ExpandCommand(ActionContext{Profile: "prod", Servers: []string{"web1", "web2"}})
+	if err != nil {
+		t.Fatalf("ExpandCommand() error = %v", err)
+	}
+	if command != "notify prod: web1 web2" {
+		t.Errorf("Unexpected command %q", command)
+	}
+
+	// Values are quoted so names can't run commands of their own
+	action = CustomAction{Name: "Notify", Scope: "profile", Command: "notify {profile} {servers}"}
+	command, err = action.ExpandCommand(ActionContext{Profile: "prod; rm -rf ~", Servers: []string{"web1", "$(id)"}})
+	if err != nil {
+		t.Fatalf("ExpandCommand() error = %v", err)
+	}
+	if command != "notify 'prod; rm -rf ~' web1 '$(id)'" {
+		t.Errorf("Unexpected command %q", command)
+	}
+
+	action = CustomAction{Name: "Log", Scope: "session", Command: "tmux capture-pane -t {session} > /tmp/{name}.log"}
+	if _, err := action.ExpandCommand(ActionContext{Session: "adhoc"}); err == nil || !strings.Contains(err.Error(), "{name}") {
+		t.Errorf("Expected an error for {name} without a server, got %v", err)
+	}
+}
+
+func TestActionsFor(t *testing.T) {
+	cfg := &Config{Actions: []CustomAction{
+		{Name: "a", Command: "true"},
+		{Name: "b", Scope: "session", Command: "true"},
+		{Name: "c", Scope: "Server", Command: "true"},
+	}}
+	var names []string
+	for _, action := range cfg.ActionsFor(ActionScopeServer) {
+		names = append(names, action.Name)
+	}
+	if strings.Join(names, ",") != "a,c" {
+		t.Errorf("Expected server actions a,c, got %v", names)
+	}
+	if actions := cfg.ActionsFor(ActionScopeProfile); len(actions) != 0 {
+		t.Errorf("Expected no profile actions, got %v", actions)
+	}
+}
@@ -0,0 +1,123 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Authentication chain methods
+const (
+	AuthStepAgent         = "agent"          // Keys held by the SSH agent
+	AuthStepKey           = "key"            // key_path, or the key after "key:"
+	AuthStepPasswordVault = "password-vault" // Password stored in the keyring
+	AuthStepProvider      = "provider"       // auth_provider, or the provider after "provider:"
+)
+
+// AuthStep is one method of a server's authentication chain
+type AuthStep struct {
+	Method   string
+	KeyPath  string // Key file of a key step
+	Provider string // Registered auth provider of a provider step
+}
+
+// String renders the step as written in auth_chain, e.g. "key:~/.ssh/work"
+func (s AuthStep) String() string {
+	if s.Method == AuthStepKey && s.KeyPath != "" {
+		return AuthStepKey + ":" + s.KeyPath
+	}
+	if s.Method == AuthStepProvider && s.Provider != "" {
+		return AuthStepProvider + ":" + s.Provider
+	}
+	return s.Method
+}
+
+// ParseAuthStep parses an auth_chain entry such as "agent", "key",
+// "key:~/.ssh/work", "password-vault", "provider" or "provider:sso"
+func ParseAuthStep(entry string) (AuthStep, error) {
+	method, keyPath, hasPath := strings.Cut(strings.TrimSpace(entry), ":")
+	switch method {
+	case AuthStepAgent, AuthStepPasswordVault:
+		if hasPath {
+			return AuthStep{}, fmt.Errorf("auth chain method '%s' takes no argument", method)
+		}
+		return AuthStep{Method: method}, nil
+	case AuthStepKey:
+		if hasPath && strings.TrimSpace(keyPath) == "" {
+			return AuthStep{}, fmt.Errorf("auth chain entry '%s' is missing the key path", entry)
+		}
+		return AuthStep{Method: method, KeyPath: strings.TrimSpace(keyPath)}, nil
+	case AuthStepProvider:
+		if hasPath && strings.TrimSpace(keyPath) == "" {
+			return AuthStep{}, fmt.Errorf("auth chain entry '%s' is missing the provider name", entry)
+		}
+		return AuthStep{Method: method, Provider: strings.TrimSpace(keyPath)}, nil
+	default:
+		return AuthStep{}, fmt.Errorf("unknown auth chain method '%s' (use agent, key, key:<path>, password-vault or provider:<name>)", entry)
+	}
+}
+
+// AuthSteps returns the methods sshm tries, in order, when it connects to the
+// server itself. Without an auth_chain the order follows auth_type: the key then
+// the agent for key servers, the stored password for password servers, after
+// the auth_provider when one is set.
+func (s *Server) AuthSteps() ([]AuthStep, error) {
+	if len(s.AuthChain) == 0 {
+		if s.AuthProvider != "" {
+			return append([]AuthStep{{Method: AuthStepProvider, Provider: s.AuthProvider}}, s.defaultAuthSteps()...), nil
+		}
+		return s.defaultAuthSteps(), nil
+	}
+
+	steps := make([]AuthStep, 0, len(s.AuthChain))
+	seen := make(map[string]bool)
+	for _, entry := range s.AuthChain {
+		step, err := ParseAuthStep(entry)
+		if err != nil {
+			return nil, err
+		}
+		if step.Method == AuthStepKey && step.KeyPath == "" {
+			if strings.TrimSpace(s.KeyPath) == "" {
+				return nil, fmt.Errorf("auth chain entry 'key' needs key_path (or use key:<path>)")
+			}
+			step.KeyPath = s.KeyPath
+		}
+		if step.Method == AuthStepProvider && step.Provider == "" {
+			if s.AuthProvider == "" {
+				return nil, fmt.Errorf("auth chain entry 'provider' needs auth_provider (or use provider:<name>)")
+			}
+			step.Provider = s.AuthProvider
+		}
+		if seen[step.String()] {
+			return nil, fmt.Errorf("auth chain entry '%s' is listed twice", step)
+		}
+		seen[step.String()] = true
+		steps = append(steps, step)
+	}
+	return steps, nil
+}
+
+// defaultAuthSteps is the chain used when the server has no auth_chain
+func (s *Server) defaultAuthSteps() []AuthStep {
+	switch s.AuthType {
+	case "key":
+		if strings.TrimSpace(s.KeyPath) != "" {
+			return []AuthStep{{Method: AuthStepKey, KeyPath: s.KeyPath}, {Method: AuthStepAgent}}
+		}
+		return []AuthStep{{Method: AuthStepAgent}}
+	case "password":
+		return []AuthStep{{Method: AuthStepPasswordVault}}
+	default:
+		steps := []AuthStep{{Method: AuthStepAgent}}
+		if homeDir, err := os.UserHomeDir(); err == nil {
+			for _, name := range []string{"id_rsa", "id_ed25519", "id_ecdsa"} {
+				keyPath := filepath.Join(homeDir, ".ssh", name)
+				if _, err := os.Stat(keyPath); err == nil {
+					steps = append(steps, AuthStep{Method: AuthStepKey, KeyPath: keyPath})
+				}
+			}
+		}
+		return steps
+	}
+}
@@ -0,0 +1,114 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseAuthStep(t *testing.T) {
+	tests := []struct {
+		entry    string
+		expected AuthStep
+		wantErr  bool
+	}{
+		{"agent", AuthStep{Method: AuthStepAgent}, false},
+		{"key", AuthStep{Method: AuthStepKey}, false},
+		{"key:~/.ssh/work", AuthStep{Method: AuthStepKey, KeyPath: "~/.ssh/work"}, false},
+		{" password-vault ", AuthStep{Method: AuthStepPasswordVault}, false},
+		{"provider", AuthStep{Method: AuthStepProvider}, false},
+		{"provider:sso", AuthStep{Method: AuthStepProvider, Provider: "sso"}, false},
+		{"key:", AuthStep{}, true},
+		{"provider:", AuthStep{}, true},
+		{"agent:foo", AuthStep{}, true},
+		{"kerberos", AuthStep{}, true},
+	}
+	for _, tt := range tests {
+		step, err := ParseAuthStep(tt.entry)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseAuthStep(%q) error = %v, wantErr %v", tt.entry, err, tt.wantErr)
+			continue
+		}
+		if step != tt.expected {
+			t.Errorf("ParseAuthStep(%q) = %+v, want %+v", tt.entry, step, tt.expected)
+		}
+	}
+}
+
+func TestServerAuthSteps(t *testing.T) {
+	server := Server{AuthType: "key", KeyPath: "~/.ssh/id_ed25519", AuthChain: []string{"agent", "key", "key:~/.ssh/work", "password-vault"}}
+	steps, err := server.AuthSteps()
+	if err != nil {
+		t.Fatalf("AuthSteps() error = %v", err)
+	}
+	var rendered []string
+	for _, step := range steps {
+		rendered = append(rendered, step.String())
+	}
+	if got := strings.Join(rendered, ","); got != "agent,key:~/.ssh/id_ed25519,key:~/.ssh/work,password-vault" {
+		t.Errorf("AuthSteps() = %s", got)
+	}
+
+	// Without a chain the order follows auth_type
+	defaults, _ := (&Server{AuthType: "key", KeyPath: "/k"}).AuthSteps()
+	if len(defaults) != 2 || defaults[0].String() != "key:/k" || defaults[1].Method != AuthStepAgent {
+		t.Errorf("Unexpected default key chain: %+v", defaults)
+	}
+	defaults, _ = (&Server{AuthType: "password"}).AuthSteps()
+	if len(defaults) != 1 || defaults[0].Method != AuthStepPasswordVault {
+		t.Errorf("Unexpected default password chain: %+v", defaults)
+	}
+
+	for _, chain := range [][]string{{"agent", "agent"}, {"bogus"}} {
+		if _, err := (&Server{AuthType: "password", AuthChain: chain}).AuthSteps(); err == nil {
+			t.Errorf("Expected an error for chain %v", chain)
+		}
+	}
+	// A bare key step needs key_path
+	if _, err := (&Server{AuthType: "password", AuthChain: []string{"key"}}).AuthSteps(); err == nil {
+		t.Error("Expected an error for a key step without key_path")
+	}
+}
+
+func TestServerValidateAuthChain(t *testing.T) {
+	server := Server{Name: "web", Hostname: "web.example.com", Port: 22, Username: "ops", AuthType: "key", KeyPath: "/k", AuthChain: []string{"agent", "smartcard"}}
+	if err := server.Validate(); err == nil || !strings.Contains(err.Error(), "auth_chain") {
+		t.Errorf("Expected an auth_chain validation error, got %v", err)
+	}
+	server.AuthChain = []string{"agent", "key"}
+	if err := server.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
+
+func TestServerAuthProvider(t *testing.T) {
+	// The provider comes before the auth_type methods
+	server := Server{Name: "web", Hostname: "web.example.com", Port: 22, Username: "ops", AuthProvider: "sso", AuthType: "password"}
+	steps, err := server.AuthSteps()
+	if err != nil {
+		t.Fatalf("AuthSteps() error = %v", err)
+	}
+	if len(steps) != 2 || steps[0].String() != "provider:sso" || steps[1].Method != AuthStepPasswordVault {
+		t.Errorf("Unexpected provider chain: %+v", steps)
+	}
+
+	// A bare provider step uses auth_provider
+	server.AuthChain = []string{"agent", "provider"}
+	steps, err = server.AuthSteps()
+	if err != nil || len(steps) != 2 || steps[1].Provider != "sso" {
+		t.Errorf("AuthSteps() = %+v, %v", steps, err)
+	}
+	if _, err := (&Server{AuthChain: []string{"provider"}}).AuthSteps(); err == nil {
+		t.Error("Expected an error for a provider step without auth_provider")
+	}
+
+	// auth_type may be left out when a provider authenticates
+	server.AuthChain = nil
+	server.AuthType = ""
+	if err := server.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+	server.AuthProvider = "bad name"
+	if err := server.Validate(); err == nil {
+		t.Error("Expected an error for an invalid auth_provider")
+	}
+}
@@ -0,0 +1,98 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Batch edit operations besides setting a metadata field
+const (
+	BatchAddTag    = "add_tag"
+	BatchRemoveTag = "remove_tag"
+)
+
+// BatchEdit is a tag or metadata change applied to many servers in one operation.
+// Field is BatchAddTag, BatchRemoveTag or a canonical metadata field name.
+type BatchEdit struct {
+	Field string
+	Value string
+}
+
+// Validate validates a batch edit
+func (e BatchEdit) Validate() error {
+	switch e.Field {
+	case BatchAddTag, BatchRemoveTag:
+		if strings.TrimSpace(e.Value) == "" {
+			return fmt.Errorf("tag must not be empty")
+		}
+		return nil
+	}
+	if _, err := NormalizeMetadataField(e.Field); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Apply applies the edit to server and reports whether anything changed
+func (e BatchEdit) Apply(server *Server) bool {
+	value := strings.TrimSpace(e.Value)
+	switch e.Field {
+	case BatchAddTag:
+		if server.HasTag(value) {
+			return false
+		}
+		server.Tags = append(server.Tags, value)
+		return true
+	case BatchRemoveTag:
+		if !server.HasTag(value) {
+			return false
+		}
+		var tags []string
+		for _, tag := range server.Tags {
+			if !strings.EqualFold(tag, value) {
+				tags = append(tags, tag)
+			}
+		}
+		server.Tags = tags
+		return true
+	}
+
+	field, err := NormalizeMetadataField(e.Field)
+	if err != nil || server.Metadata.Get(field) == value {
+		return false
+	}
+	server.Metadata.Set(field, value)
+	return true
+}
+
+// ApplyBatchEdit applies the edit to the named servers and returns the names of those
+// that changed. Nothing is changed if the edit would make any server invalid.
+func (c *Config) ApplyBatchEdit(names []string, edit BatchEdit) ([]string, error) {
+	if err := edit.Validate(); err != nil {
+		return nil, err
+	}
+
+	var updated []Server
+	for _, name := range names {
+		server, err := c.GetServer(name)
+		if err != nil {
+			return nil, err
+		}
+		if !edit.Apply(server) {
+			continue
+		}
+		if err := server.Validate(); err != nil {
+			return nil, fmt.Errorf("server '%s': %w", server.Name, err)
+		}
+		updated = append(updated, *server)
+	}
+
+	var changed []string
+	for _, server := range updated {
+		if err := c.UpdateServer(server); err != nil {
+			return changed, err
+		}
+		changed = append(changed, server.Name)
+	}
+	return changed, nil
+}
@@ -0,0 +1,45 @@
+package config
+
+import "testing"
+
+func TestApplyBatchEdit(t *testing.T) {
+	cfg := &Config{Servers: []Server{
+		{Name: "web1", Hostname: "10.0.0.1", Port: 22, Username: "ops", AuthType: "key", KeyPath: "~/.ssh/id_ed25519", Tags: []string{"web"}},
+		{Name: "web2", Hostname: "10.0.0.2", Port: 22, Username: "ops", AuthType: "key", KeyPath: "~/.ssh/id_ed25519"},
+		{Name: "db1", Hostname: "10.0.0.3", Port: 22, Username: "ops", AuthType: "key", KeyPath: "~/.ssh/id_ed25519"},
+	}}
+
+	changed, err := cfg.ApplyBatchEdit([]string{"web1", "web2"}, BatchEdit{Field: BatchAddTag, Value: "Web"})
+	if err != nil {
+		t.Fatalf("ApplyBatchEdit failed: %v", err)
+	}
+	if len(changed) != 1 || changed[0] != "web2" {
+		t.Errorf("Expected only web2 to gain the tag, got %v", changed)
+	}
+
+	changed, err = cfg.ApplyBatchEdit([]string{"web1", "web2", "db1"}, BatchEdit{Field: "env", Value: "production"})
+	if err != nil || len(changed) != 3 {
+		t.Fatalf("Expected all servers updated, got %v, %v", changed, err)
+	}
+	if server, _ := cfg.GetServer("db1"); server.Metadata.Environment != "production" {
+		t.Errorf("Expected environment set on db1, got %+v", server.Metadata)
+	}
+
+	changed, _ = cfg.ApplyBatchEdit([]string{"web1", "web2"}, BatchEdit{Field: BatchRemoveTag, Value: "web"})
+	if len(changed) != 2 {
+		t.Errorf("Expected tag removed from both servers, got %v", changed)
+	}
+	if server, _ := cfg.GetServer("web1"); len(server.Tags) != 0 {
+		t.Errorf("Expected no tags left on web1, got %v", server.Tags)
+	}
+
+	if _, err := cfg.ApplyBatchEdit([]string{"web1"}, BatchEdit{Field: BatchAddTag, Value: " "}); err == nil {
+		t.Error("Expected error for empty tag")
+	}
+	if _, err := cfg.ApplyBatchEdit([]string{"web1", "missing"}, BatchEdit{Field: "owner", Value: "alice"}); err == nil {
+		t.Error("Expected error for unknown server")
+	}
+	if server, _ := cfg.GetServer("web1"); server.Metadata.Owner != "" {
+		t.Error("Failed batch edits must not change any server")
+	}
+}
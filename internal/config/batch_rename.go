@@ -0,0 +1,179 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// BatchRename renames many servers in one operation. Find is replaced with
+// Replace in each name, then the result takes the place of {name} in Template,
+// e.g. Find ".example.com" strips a domain suffix and Template "aws-{name}"
+// adds a prefix.
+type BatchRename struct {
+	Find     string
+	Replace  string
+	Regexp   bool   // Find is a regular expression; Replace may use $1
+	Template string // New name with a {name} placeholder
+}
+
+// ServerRename is a server's name before and after a rename
+type ServerRename struct {
+	From string
+	To   string
+}
+
+// RenamePlan is what a batch rename changes. It is only applied without
+// collisions.
+type RenamePlan struct {
+	Renames    []ServerRename
+	Unchanged  []string
+	Collisions []string // New names clashing with each other or other servers
+}
+
+// Validate validates a batch rename
+func (r BatchRename) Validate() error {
+	if r.Find == "" && strings.TrimSpace(r.Template) == "" {
+		return fmt.Errorf("a text to find or a template is required")
+	}
+	if r.Regexp {
+		if _, err := regexp.Compile(r.Find); err != nil {
+			return fmt.Errorf("invalid regular expression: %w", err)
+		}
+	}
+	return nil
+}
+
+// NewName returns the name a server named name gets; the rename must be valid
+func (r BatchRename) NewName(name string) string {
+	if r.Find != "" {
+		if r.Regexp {
+			name = regexp.MustCompile(r.Find).ReplaceAllString(name, r.Replace)
+		} else {
+			name = strings.ReplaceAll(name, r.Find, r.Replace)
+		}
+	}
+	if template := strings.TrimSpace(r.Template); template != "" {
+		name = strings.ReplaceAll(template, "{name}", name)
+	}
+	return strings.TrimSpace(name)
+}
+
+// PlanBatchRename returns what renaming the named servers changes, including
+// the new names that collide
+func (c *Config) PlanBatchRename(names []string, rename BatchRename) (RenamePlan, error) {
+	var plan RenamePlan
+	if err := rename.Validate(); err != nil {
+		return plan, err
+	}
+
+	final := make(map[string]string, len(c.Servers)) // Server name -> name after the rename
+	for _, server := range c.Servers {
+		final[server.Name] = server.Name
+	}
+	seen := make(map[string]bool)
+	for _, name := range names {
+		server, err := c.GetServer(name)
+		if err != nil {
+			return plan, err
+		}
+		if seen[server.Name] {
+			continue
+		}
+		seen[server.Name] = true
+
+		newName := rename.NewName(server.Name)
+		switch {
+		case newName == server.Name:
+			plan.Unchanged = append(plan.Unchanged, server.Name)
+			continue
+		case newName == "":
+			plan.Collisions = append(plan.Collisions, fmt.Sprintf("%s would get an empty name", server.Name))
+		case c.includedServers[server.Name].Name != "":
+			plan.Collisions = append(plan.Collisions, fmt.Sprintf("%s comes from an include and can only be renamed there", server.Name))
+		}
+		final[server.Name] = newName
+		plan.Renames = append(plan.Renames, ServerRename{From: server.Name, To: newName})
+	}
+
+	isRenamed := make(map[string]bool, len(plan.Renames))
+	for _, renamed := range plan.Renames {
+		isRenamed[renamed.From] = true
+	}
+	for _, renamed := range plan.Renames {
+		if renamed.To == "" {
+			continue
+		}
+		for _, other := range c.Servers {
+			if other.Name == renamed.From {
+				continue
+			}
+			if final[other.Name] == renamed.To {
+				// Report a clash between two renamed servers once
+				if isRenamed[other.Name] && other.Name < renamed.From {
+					continue
+				}
+				plan.Collisions = append(plan.Collisions, fmt.Sprintf("%s would be named %s like %s", renamed.From, renamed.To, other.Name))
+			} else if contains(other.Aliases, renamed.To) {
+				plan.Collisions = append(plan.Collisions, fmt.Sprintf("%s would be named %s, an alias of %s", renamed.From, renamed.To, other.Name))
+			}
+		}
+	}
+	return plan, nil
+}
+
+// ApplyBatchRename renames the named servers along with their profile
+// memberships and the jump host references of other servers. Nothing is
+// renamed if any new name collides.
+func (c *Config) ApplyBatchRename(names []string, rename BatchRename) (RenamePlan, error) {
+	plan, err := c.PlanBatchRename(names, rename)
+	if err != nil {
+		return plan, err
+	}
+	if len(plan.Collisions) > 0 {
+		return plan, fmt.Errorf("the new names collide: %s", strings.Join(plan.Collisions, "; "))
+	}
+
+	renamed := make(map[string]string, len(plan.Renames))
+	for _, r := range plan.Renames {
+		renamed[r.From] = r.To
+	}
+	// Lists are copied rather than changed in place, since they may be shared
+	// with the unmodified servers and profiles of includes
+	renameAll := func(list []string) []string {
+		if list == nil {
+			return nil
+		}
+		names := make([]string, len(list))
+		for i, name := range list {
+			names[i] = name
+			if newName, ok := renamed[name]; ok {
+				names[i] = newName
+			}
+		}
+		return names
+	}
+
+	for i := range c.Servers {
+		c.Servers[i].ProxyJump = renameAll(c.Servers[i].ProxyJump)
+		if newName, ok := renamed[c.Servers[i].Name]; ok {
+			c.Servers[i].Name = newName
+		}
+	}
+	for i := range c.Profiles {
+		c.Profiles[i].Servers = renameAll(c.Profiles[i].Servers)
+	}
+	// Renamed servers stay in the conf.d file they were loaded from
+	sources := make(map[string]string, len(plan.Renames))
+	for _, r := range plan.Renames {
+		if source, ok := c.serverSources[r.From]; ok {
+			sources[r.To] = source
+			delete(c.serverSources, r.From)
+		}
+	}
+	for name, source := range sources {
+		c.setServerSource(name, source)
+	}
+	c.resolveProxyJumps()
+	return plan, nil
+}
@@ -0,0 +1,100 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func batchRenameConfig() *Config {
+	return &Config{
+		Servers: []Server{
+			{Name: "bastion.example.com", Hostname: "10.0.0.1", Port: 22, Username: "ops", AuthType: "key", KeyPath: "~/.ssh/id_ed25519"},
+			{Name: "web1.example.com", Hostname: "10.0.0.2", Port: 22, Username: "ops", AuthType: "key", KeyPath: "~/.ssh/id_ed25519", ProxyJump: []string{"bastion.example.com"}},
+			{Name: "web1", Hostname: "10.0.0.3", Port: 22, Username: "ops", AuthType: "key", KeyPath: "~/.ssh/id_ed25519", Aliases: []string{"w1"}},
+		},
+		Profiles: []Profile{{Name: "prod", Servers: []string{"bastion.example.com", "web1.example.com"}}},
+	}
+}
+
+func TestBatchRenameNewName(t *testing.T) {
+	tests := []struct {
+		rename BatchRename
+		name   string
+		want   string
+	}{
+		{BatchRename{Find: ".example.com"}, "web1.example.com", "web1"},
+		{BatchRename{Template: "aws-{name}"}, "web1", "aws-web1"},
+		{BatchRename{Find: `^(\w+)\..*$`, Replace: "$1", Regexp: true, Template: "prod-{name}"}, "web1.example.com", "prod-web1"},
+	}
+	for _, tt := range tests {
+		if got := tt.rename.NewName(tt.name); got != tt.want {
+			t.Errorf("NewName(%q) with %+v = %q, want %q", tt.name, tt.rename, got, tt.want)
+		}
+	}
+
+	if err := (BatchRename{}).Validate(); err == nil {
+		t.Error("Expected a rename without find text or template to be invalid")
+	}
+	if err := (BatchRename{Find: "(", Regexp: true}).Validate(); err == nil {
+		t.Error("Expected an invalid regular expression to be rejected")
+	}
+}
+
+func TestPlanBatchRenameDetectsCollisions(t *testing.T) {
+	cfg := batchRenameConfig()
+	names := []string{"bastion.example.com", "web1.example.com"}
+
+	plan, err := cfg.PlanBatchRename(names, BatchRename{Find: ".example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Collisions) != 1 || !strings.Contains(plan.Collisions[0], "web1.example.com would be named web1 like web1") {
+		t.Errorf("Expected web1.example.com to collide with web1, got %v", plan.Collisions)
+	}
+	if _, err := cfg.ApplyBatchRename(names, BatchRename{Find: ".example.com"}); err == nil {
+		t.Fatal("Expected colliding renames to be refused")
+	}
+	if cfg.Servers[0].Name != "bastion.example.com" {
+		t.Errorf("Expected nothing renamed after a collision, got %s", cfg.Servers[0].Name)
+	}
+
+	// Two renamed servers taking the same name, and an alias clash
+	plan, _ = cfg.PlanBatchRename(names, BatchRename{Template: "w1"})
+	if len(plan.Collisions) != 3 {
+		t.Errorf("Expected a clash between the renamed servers and two with the alias, got %v", plan.Collisions)
+	}
+
+	// A server may take a name another renamed server gives up
+	cfg = &Config{Servers: []Server{{Name: "a"}, {Name: "a-a"}}}
+	plan, _ = cfg.PlanBatchRename([]string{"a", "a-a"}, BatchRename{Template: "a-{name}"})
+	if len(plan.Collisions) != 0 || len(plan.Renames) != 2 {
+		t.Errorf("Expected a to take the name a-a gives up, got %+v", plan)
+	}
+}
+
+func TestApplyBatchRenameKeepsJumpHostsAndProfiles(t *testing.T) {
+	cfg := batchRenameConfig()
+	original := cfg.Profiles[0].Servers
+
+	plan, err := cfg.ApplyBatchRename([]string{"bastion.example.com", "web1.example.com"}, BatchRename{Find: ".example.com", Template: "aws-{name}"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Renames) != 2 || plan.Renames[1] != (ServerRename{From: "web1.example.com", To: "aws-web1"}) {
+		t.Fatalf("Unexpected plan %+v", plan)
+	}
+	web, err := cfg.GetServer("aws-web1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(web.ProxyJump, []string{"aws-bastion"}) || len(web.JumpHosts()) != 1 {
+		t.Errorf("Expected the jump host reference to follow the rename, got %v", web.ProxyJump)
+	}
+	if !reflect.DeepEqual(cfg.Profiles[0].Servers, []string{"aws-bastion", "aws-web1"}) {
+		t.Errorf("Expected the profile to reference the new names, got %v", cfg.Profiles[0].Servers)
+	}
+	if original[0] != "bastion.example.com" {
+		t.Error("Expected the profile's previous server list to be left alone")
+	}
+}
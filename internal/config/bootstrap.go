@@ -0,0 +1,159 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"sshm/internal/shellquote"
+)
+
+// BootstrapConfig describes files uploaded to a server before each connection.
+// Files are copied into the remote home directory; the script, if any, is copied
+// alongside them and sourced before the login shell starts.
+type BootstrapConfig struct {
+	Files  []string `yaml:"files,omitempty" json:"files,omitempty"`   // Local dotfiles copied to the remote home directory
+	Script string   `yaml:"script,omitempty" json:"script,omitempty"` // Local setup script sourced on login
+}
+
+// Validate validates a bootstrap configuration
+func (b *BootstrapConfig) Validate() error {
+	if len(b.Files) == 0 && strings.TrimSpace(b.Script) == "" {
+		return fmt.Errorf("bootstrap requires at least one file or a script")
+	}
+	for _, file := range b.Files {
+		if strings.TrimSpace(file) == "" {
+			return fmt.Errorf("bootstrap file paths must not be empty")
+		}
+	}
+	return nil
+}
+
+// WithBootstrap returns a copy of server that inherits the profile's bootstrap
+// when the server doesn't define its own. Used for profile (group) connections.
+func (p *Profile) WithBootstrap(server Server) Server {
+	if server.Bootstrap == nil && p.Bootstrap != nil {
+		server.Bootstrap = p.Bootstrap
+	}
+	return server
+}
+
+// WrapSSHCommand prefixes sshCmd with an upload of the server's bootstrap files
+// and appends a remote command that sources the bootstrap script, changes to the
+// working directory and starts the shell. The command is returned unchanged when
+// neither a bootstrap nor a working directory or shell is configured.
+// Restricted servers get their forced command instead and never a bootstrap shell.
+// A custom ssh binary or command template replaces the ssh invocation first.
+//
+// sshCmd is a plain "ssh -t user@host <options>" command; prefix is the sshpass
+// command answering its password prompt, if any, and is put in front of both
+// the upload and the connection.
+func (s *Server) WrapSSHCommand(prefix, sshCmd string) string {
+	templated := withPrefix(prefix, s.ApplySSHTemplate(sshCmd))
+	if s.Restricted != nil {
+		return templated + " -o ClearAllForwardings=yes " + shellquote.Quote(s.RestrictedCommand())
+	}
+	if s.Bootstrap == nil {
+		return withLoginCommand(templated, s.LoginCommand(""))
+	}
+
+	var uploads []string
+	for _, file := range s.Bootstrap.Files {
+		uploads = append(uploads, expandBootstrapPath(file))
+	}
+	script := strings.TrimSpace(s.Bootstrap.Script)
+	if script != "" {
+		uploads = append(uploads, expandBootstrapPath(script))
+	}
+	if len(uploads) == 0 {
+		return withLoginCommand(templated, s.LoginCommand(""))
+	}
+
+	// A failed upload shouldn't block the connection itself
+	wrapped := s.uploadCommand(prefix, sshCmd, uploads) + "; " + templated
+	var remoteScript string
+	if script != "" {
+		remoteScript = "~/" + filepath.Base(script)
+	}
+	return withLoginCommand(wrapped, s.LoginCommand(remoteScript))
+}
+
+// uploadCommand copies the local uploads to the remote home directory with
+// scp. Servers with a custom ssh binary or command template stream them as a
+// tar archive through that command instead, which scp can't run; their upload
+// isn't limited by the transfer bandwidth.
+func (s *Server) uploadCommand(prefix, sshCmd string, uploads []string) string {
+	if s.HasSSHTemplate() {
+		archive := "tar -cf -"
+		for _, upload := range uploads {
+			archive += fmt.Sprintf(" -C %s %s", shellquote.Quote(filepath.Dir(upload)), shellquote.Quote(filepath.Base(upload)))
+		}
+		return archive + " | " + withPrefix(prefix, s.ApplySSHTemplate(sshCmd+" -q")) + " " + shellquote.Quote("tar -xf -")
+	}
+
+	scpCmd := withPrefix(prefix, "scp -q")
+	// scp takes its limit in Kbit/s
+	if bandwidth, err := s.Transfers.BandwidthBytes(); err == nil && bandwidth > 0 {
+		scpCmd += fmt.Sprintf(" -l %d", max(bandwidth*8/1000, 1))
+	}
+	if s.Port != 0 && s.Port != 22 {
+		scpCmd += fmt.Sprintf(" -P %d", s.Port)
+	}
+	if s.AuthType == "key" && s.KeyPath != "" {
+		scpCmd += " -i " + shellquote.Quote(expandBootstrapPath(s.KeyPath))
+	}
+	scpCmd += s.GetSSHOptions()
+	for _, upload := range uploads {
+		scpCmd += " " + shellquote.Quote(upload)
+	}
+	return scpCmd + fmt.Sprintf(" %s@%s:", s.Username, s.GetEffectiveHostname())
+}
+
+// withPrefix puts the sshpass prefix, if any, in front of a command
+func withPrefix(prefix, command string) string {
+	if prefix == "" {
+		return command
+	}
+	return prefix + " " + command
+}
+
+// withLoginCommand appends the quoted remote login command, if any, to an ssh command
+func withLoginCommand(sshCmd, loginCommand string) string {
+	if loginCommand == "" {
+		return sshCmd
+	}
+	return sshCmd + " " + shellquote.Quote(loginCommand)
+}
+
+// expandBootstrapPath expands ~ in a local bootstrap path, keeping it as-is on failure
+func expandBootstrapPath(path string) string {
+	expanded, err := ExpandPath(strings.TrimSpace(path))
+	if err != nil {
+		return path
+	}
+	return expanded
+}
+
+// ProfileNames returns the names of all profiles
+func (c *Config) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for _, profile := range c.Profiles {
+		names = append(names, profile.Name)
+	}
+	return names
+}
+
+// ServersWithBootstrap returns all servers, with the named profile's bootstrap,
+// ssh template and login settings applied to its members as in a group
+// connection; an empty name applies none
+func (c *Config) ServersWithBootstrap(profileName string) []Server {
+	profile, _ := c.GetProfile(profileName)
+	servers := make([]Server, 0, len(c.Servers))
+	for _, server := range c.Servers {
+		if profile != nil && contains(profile.Servers, server.Name) {
+			server = profile.WithLogin(profile.WithSSHTemplate(profile.WithBootstrap(server)))
+		}
+		servers = append(servers, server)
+	}
+	return servers
+}
@@ -0,0 +1,126 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBootstrapValidate(t *testing.T) {
+	if err := (&BootstrapConfig{}).Validate(); err == nil {
+		t.Error("Expected error for empty bootstrap")
+	}
+	if err := (&BootstrapConfig{Files: []string{" "}}).Validate(); err == nil {
+		t.Error("Expected error for empty file path")
+	}
+	if err := (&BootstrapConfig{Script: "/tmp/setup.sh"}).Validate(); err != nil {
+		t.Errorf("Expected valid bootstrap, got: %v", err)
+	}
+}
+
+func TestWrapSSHCommandWithoutBootstrap(t *testing.T) {
+	server := Server{Name: "web", Hostname: "web.example.com", Port: 22, Username: "ops"}
+	sshCmd := "ssh -t ops@web.example.com"
+
+	if got := server.WrapSSHCommand("", sshCmd); got != sshCmd {
+		t.Errorf("Expected command unchanged, got: %s", got)
+	}
+}
+
+func TestWrapSSHCommandWithBootstrap(t *testing.T) {
+	server := Server{
+		Name:     "web",
+		Hostname: "web.example.com",
+		Port:     2222,
+		Username: "ops",
+		AuthType: "key",
+		KeyPath:  "/keys/id_ed25519",
+		Bootstrap: &BootstrapConfig{
+			Files:  []string{"/home/ops/.vimrc"},
+			Script: "/home/ops/setup.sh",
+		},
+	}
+	sshCmd := "ssh -t ops@web.example.com -p 2222"
+
+	got := server.WrapSSHCommand("", sshCmd)
+
+	expectedUpload := "scp -q -P 2222 -i '/keys/id_ed25519' '/home/ops/.vimrc' '/home/ops/setup.sh' ops@web.example.com:; "
+	if !strings.HasPrefix(got, expectedUpload) {
+		t.Errorf("Expected upload prefix %q, got: %s", expectedUpload, got)
+	}
+	if !strings.Contains(got, sshCmd+" '[ -f ~/setup.sh ] && . ~/setup.sh; exec \"$SHELL\" -l'") {
+		t.Errorf("Expected script to be sourced on login, got: %s", got)
+	}
+}
+
+func TestWrapSSHCommandReusesSSHPass(t *testing.T) {
+	server := Server{
+		Name:      "db",
+		Hostname:  "db.example.com",
+		Port:      22,
+		Username:  "ops",
+		Bootstrap: &BootstrapConfig{Files: []string{"/home/ops/.bashrc"}},
+	}
+
+	got := server.WrapSSHCommand("sshpass -e", "ssh -t ops@db.example.com")
+
+	if !strings.HasPrefix(got, "sshpass -e scp -q") {
+		t.Errorf("Expected sshpass prefix on upload, got: %s", got)
+	}
+	if !strings.HasSuffix(got, "; sshpass -e ssh -t ops@db.example.com") {
+		t.Errorf("Expected sshpass prefix on the connection, got: %s", got)
+	}
+	if strings.Contains(got, "exec") {
+		t.Errorf("Expected no remote command without a script, got: %s", got)
+	}
+}
+
+func TestProfileWithBootstrap(t *testing.T) {
+	profileBootstrap := &BootstrapConfig{Files: []string{"~/.bashrc"}}
+	profile := Profile{Name: "dev", Bootstrap: profileBootstrap}
+
+	inherited := profile.WithBootstrap(Server{Name: "a"})
+	if inherited.Bootstrap != profileBootstrap {
+		t.Error("Expected server to inherit profile bootstrap")
+	}
+
+	own := &BootstrapConfig{Script: "~/own.sh"}
+	kept := profile.WithBootstrap(Server{Name: "b", Bootstrap: own})
+	if kept.Bootstrap != own {
+		t.Error("Expected server bootstrap to take precedence")
+	}
+}
+
+func TestWrapSSHCommandLimitsBootstrapBandwidth(t *testing.T) {
+	server := Server{
+		Name:      "vpn",
+		Hostname:  "vpn.example.com",
+		Username:  "ops",
+		Bootstrap: &BootstrapConfig{Files: []string{"/home/ops/.vimrc"}},
+		Transfers: &TransferLimits{Bandwidth: "125K"},
+	}
+
+	got := server.WrapSSHCommand("", "ssh -t ops@vpn.example.com")
+	if !strings.HasPrefix(got, "scp -q -l 1024 ") {
+		t.Errorf("Expected the upload to be limited to 1024 Kbit/s, got: %s", got)
+	}
+}
+
+func TestWrapSSHCommandUploadsThroughTemplate(t *testing.T) {
+	server := Server{
+		Name:        "web",
+		Hostname:    "web.example.com",
+		Port:        22,
+		Username:    "ops",
+		SSHTemplate: "corp-ssh --as {user} {host} {options}",
+		Bootstrap:   &BootstrapConfig{Files: []string{"/home/ops/.vimrc"}, Script: "/opt/setup.sh"},
+	}
+
+	got := server.WrapSSHCommand("sshpass -e", "ssh -t ops@web.example.com -o ServerAliveInterval=60")
+	upload := "tar -cf - -C '/home/ops' '.vimrc' -C '/opt' 'setup.sh' | sshpass -e corp-ssh --as ops web.example.com -o ServerAliveInterval=60 -q 'tar -xf -'; "
+	if !strings.HasPrefix(got, upload) {
+		t.Errorf("Expected the upload to run through the template\n got %s\nwant prefix %s", got, upload)
+	}
+	if strings.Contains(got, "scp") {
+		t.Errorf("Expected no scp with an ssh template, got: %s", got)
+	}
+}
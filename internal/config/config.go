@@ -1,10 +1,13 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"net"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -12,15 +15,49 @@ import (
 // Server represents a server configuration
 type Server struct {
 	Name                string `yaml:"name" json:"name"`
-	Hostname            string `yaml:"hostname" json:"hostname"`
-	Port                int    `yaml:"port" json:"port"`
-	Username            string `yaml:"username" json:"username"`
-	AuthType            string `yaml:"auth_type" json:"auth_type"` // "key" or "password"
+	Hostname            string `yaml:"hostname,omitempty" json:"hostname,omitempty"`
+	Port                int    `yaml:"port,omitempty" json:"port,omitempty"`
+	Username            string `yaml:"username,omitempty" json:"username,omitempty"`
+	AuthType            string `yaml:"auth_type,omitempty" json:"auth_type,omitempty"` // "key" or "password"
 	KeyPath             string `yaml:"key_path,omitempty" json:"key_path,omitempty"`
 	Password            string `yaml:"password,omitempty" json:"password,omitempty"` // For password authentication
 	PassphraseProtected bool   `yaml:"passphrase_protected,omitempty" json:"passphrase_protected,omitempty"`
 	UseKeyring          bool   `yaml:"use_keyring,omitempty" json:"use_keyring,omitempty"`
 	KeyringID           string `yaml:"keyring_id,omitempty" json:"keyring_id,omitempty"`
+	Retry               *RetryPolicy `yaml:"retry,omitempty" json:"retry,omitempty"` // Overrides the global retry policy
+	ResolveTo           string   `yaml:"resolve_to,omitempty" json:"resolve_to,omitempty"` // IP address to dial instead of resolving hostname
+	Aliases             []string `yaml:"aliases,omitempty" json:"aliases,omitempty"`       // Alternative names accepted wherever a server name is
+	RequireBannerAck    bool     `yaml:"require_banner_ack,omitempty" json:"require_banner_ack,omitempty"` // Login banner must be accepted before connecting
+	Bootstrap           *BootstrapConfig `yaml:"bootstrap,omitempty" json:"bootstrap,omitempty"` // Dotfiles/script uploaded on connect
+	Tunnels             []Tunnel         `yaml:"tunnels,omitempty" json:"tunnels,omitempty"`     // Local port forwards managed by sshm
+	Tags                []string         `yaml:"tags,omitempty" json:"tags,omitempty"`           // Free-form labels used for grouping and filtering
+	Database            *DatabaseConfig  `yaml:"database,omitempty" json:"database,omitempty"`   // Database reachable through this server
+	Watch               bool             `yaml:"watch,omitempty" json:"watch,omitempty"`         // Check status at the fast watch interval
+	Protected           bool             `yaml:"protected,omitempty" json:"protected,omitempty"` // Destructive actions require typing the server name
+	Restricted          *RestrictedAccess `yaml:"restricted,omitempty" json:"restricted,omitempty"` // Only a forced command may be run, never a shell
+	ExpiresAt           *time.Time       `yaml:"expires_at,omitempty" json:"expires_at,omitempty"` // Scratch servers are archived once this passes
+	Timezone            string           `yaml:"timezone,omitempty" json:"timezone,omitempty"`     // IANA zone of the host, e.g. Asia/Tokyo
+	Metadata            ServerMetadata   `yaml:"metadata,omitempty" json:"metadata,omitempty"`     // Owner, team, cost center and environment
+	AuthChain           []string         `yaml:"auth_chain,omitempty" json:"auth_chain,omitempty"` // Methods tried in order, e.g. [agent, key:~/.ssh/work, password-vault]
+	AuthProvider        string            `yaml:"auth_provider,omitempty" json:"auth_provider,omitempty"`                 // Registered custom auth backend, tried before the auth_type methods
+	AuthProviderOptions map[string]string `yaml:"auth_provider_options,omitempty" json:"auth_provider_options,omitempty"` // Settings passed to the provider, e.g. realm or CA URL
+	ControlMaster       bool              `yaml:"controlmaster,omitempty" json:"controlmaster,omitempty"`                 // Reuse one connection for sessions, tunnels and status checks
+	Warm                bool              `yaml:"warm,omitempty" json:"warm,omitempty"`                                   // Kept connected by the warm pool so attaching is instant
+	SSHBinary           string           `yaml:"ssh_binary,omitempty" json:"ssh_binary,omitempty"`     // Executable used instead of ssh, e.g. "assh wrapper ssh"
+	SSHTemplate         string           `yaml:"ssh_template,omitempty" json:"ssh_template,omitempty"` // Connection command with {placeholders}, e.g. "{ssh} -o ProxyCommand='cloudflared access ssh --hostname %h' {user}@{host} {options}"
+	Webhooks            []Webhook        `yaml:"webhooks,omitempty" json:"webhooks,omitempty"`         // Called when the server goes online or offline
+	Transfers           *TransferLimits  `yaml:"transfers,omitempty" json:"transfers,omitempty"`       // Caps file transfers to and from this server
+	Session             *SessionTemplate `yaml:"session,omitempty" json:"session,omitempty"`           // Windows created on connect, e.g. shell, logs and htop
+	WorkDir             string           `yaml:"work_dir,omitempty" json:"work_dir,omitempty"`         // Remote directory to start in, e.g. /srv/app (overrides the profile's)
+	Shell               string           `yaml:"shell,omitempty" json:"shell,omitempty"`               // Command started after login instead of the login shell, e.g. "sudo -iu app"
+	StartupCommands     []string          `yaml:"startup_commands,omitempty" json:"startup_commands,omitempty"` // Typed into new session windows once connected, e.g. "cd /srv/app && sudo -i"
+	RemoteEnv           map[string]string `yaml:"remote_env,omitempty" json:"remote_env,omitempty"`             // Exported in new session windows before the startup commands
+	HostKeys            []string         `yaml:"host_keys,omitempty" json:"host_keys,omitempty"`       // Pinned host key fingerprints, e.g. SHA256:..., updated by 'sshm hostkey rotate'
+	Credentials         *CredentialDates `yaml:"credentials,omitempty" json:"credentials,omitempty"`   // Key rotation and account expiry dates for reminders
+	ProxyJump           []string         `yaml:"proxy_jump,omitempty" json:"proxy_jump,omitempty"`     // Hosts connected through in order: sshm server names or [user@]host[:port]
+	Record              bool             `yaml:"record,omitempty" json:"record,omitempty"`             // Sessions are recorded into the recordings directory
+
+	jumpChain []Server // proxy_jump resolved against the other servers, see resolveProxyJumps
 }
 
 // Getter methods for tmux Server interface compatibility
@@ -31,11 +68,64 @@ func (s *Server) GetUsername() string { return s.Username }
 func (s *Server) GetAuthType() string { return s.AuthType }
 func (s *Server) GetKeyPath() string  { return s.KeyPath }
 
+// GetEffectiveHostname returns the address that is actually dialed:
+// the resolve_to override when set, otherwise the hostname
+func (s *Server) GetEffectiveHostname() string {
+	if strings.TrimSpace(s.ResolveTo) != "" {
+		return s.ResolveTo
+	}
+	return s.Hostname
+}
+
+// GetSSHOptions returns extra ssh command line options derived from the server configuration
+func (s *Server) GetSSHOptions() string {
+	var options string
+	if strings.TrimSpace(s.ResolveTo) != "" {
+		// Keep known_hosts entries keyed by the configured hostname rather than the override IP
+		options += fmt.Sprintf(" -o HostKeyAlias=%s", s.Hostname)
+	}
+	if jump := s.ProxyJumpSpec(); jump != "" {
+		options += " -J " + jump
+	}
+	return options
+}
+
+// HasTag reports whether the server carries the given tag (case-insensitive)
+func (s *Server) HasTag(tag string) bool {
+	for _, existing := range s.Tags {
+		if strings.EqualFold(existing, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchesName reports whether the given name is the server's name or one of its aliases
+func (s *Server) MatchesName(name string) bool {
+	if s.Name == name {
+		return true
+	}
+	for _, alias := range s.Aliases {
+		if alias == name {
+			return true
+		}
+	}
+	return false
+}
+
 // Profile represents a profile configuration for organizing servers
 type Profile struct {
 	Name        string   `yaml:"name" json:"name"`
 	Description string   `yaml:"description,omitempty" json:"description,omitempty"`
 	Servers     []string `yaml:"servers" json:"servers"`
+	Bootstrap   *BootstrapConfig `yaml:"bootstrap,omitempty" json:"bootstrap,omitempty"` // Default bootstrap for servers in this profile
+	QuickStats  bool             `yaml:"quick_stats,omitempty" json:"quick_stats,omitempty"` // Fetch load/disk/memory stats with each status check
+	Stealth     bool             `yaml:"stealth,omitempty" json:"stealth,omitempty"`               // Check servers with a TCP connect only, spread over StealthWindow
+	StealthWindow string         `yaml:"stealth_window,omitempty" json:"stealth_window,omitempty"` // Average time between stealth checks, e.g. "30m" (default: 15m)
+	SSHBinary   string           `yaml:"ssh_binary,omitempty" json:"ssh_binary,omitempty"`     // Default ssh executable for servers in this profile
+	SSHTemplate string           `yaml:"ssh_template,omitempty" json:"ssh_template,omitempty"` // Default connection command template for servers in this profile
+	WorkDir     string           `yaml:"work_dir,omitempty" json:"work_dir,omitempty"`         // Default remote directory to start in for servers in this profile
+	Shell       string           `yaml:"shell,omitempty" json:"shell,omitempty"`               // Default command started after login for servers in this profile
 }
 
 // KeyringConfig represents keyring configuration
@@ -45,12 +135,79 @@ type KeyringConfig struct {
 	Namespace string `yaml:"namespace,omitempty" json:"namespace,omitempty"`   // Keyring namespace (default: "sshm")
 }
 
+// ShellAliasConfig controls the shell alias file generated by 'sshm alias'
+type ShellAliasConfig struct {
+	Prefix    string   `yaml:"prefix,omitempty" json:"prefix,omitempty"`       // Prepended to server names (default: "ssh-")
+	Shell     string   `yaml:"shell,omitempty" json:"shell,omitempty"`         // bash, zsh or fish (default: bash)
+	Output    string   `yaml:"output,omitempty" json:"output,omitempty"`       // Generated file (default: ~/.sshm/aliases.sh)
+	Functions bool     `yaml:"functions,omitempty" json:"functions,omitempty"` // Generate shell functions instead of aliases
+	Servers   []string `yaml:"servers,omitempty" json:"servers,omitempty"`     // Servers to generate aliases for (default: all)
+}
+
+// UpdateConfig controls 'sshm update' and the new version notice
+type UpdateConfig struct {
+	Channel       string `yaml:"channel,omitempty" json:"channel,omitempty"`               // stable or beta (default: stable)
+	DisableNotice bool   `yaml:"disable_notice,omitempty" json:"disable_notice,omitempty"` // Don't check for new versions when the TUI starts
+}
+
+// DaemonConfig controls 'sshm daemon', the background status checker and status page
+type DaemonConfig struct {
+	IntervalSeconds int    `yaml:"interval_seconds,omitempty" json:"interval_seconds,omitempty"` // Seconds between status check rounds (default: 60)
+	HTTPAddr        string `yaml:"http_addr,omitempty" json:"http_addr,omitempty"`               // Address of the read-only status page, e.g. 127.0.0.1:8722 (empty = disabled)
+	HTTPToken       string `yaml:"http_token,omitempty" json:"http_token,omitempty"`             // Token required to view the status page
+}
+
+// RetryPolicy controls how failed connection attempts and status checks are retried
+type RetryPolicy struct {
+	Attempts       int      `yaml:"attempts,omitempty" json:"attempts,omitempty"`               // Total attempts including the first (default: 1)
+	InitialBackoff string   `yaml:"initial_backoff,omitempty" json:"initial_backoff,omitempty"` // Delay before the first retry, e.g. "1s"
+	MaxBackoff     string   `yaml:"max_backoff,omitempty" json:"max_backoff,omitempty"`         // Upper bound for the delay, e.g. "30s"
+	Multiplier     float64  `yaml:"multiplier,omitempty" json:"multiplier,omitempty"`           // Backoff growth factor (default: 2)
+	Jitter         float64  `yaml:"jitter,omitempty" json:"jitter,omitempty"`                   // Random spread as a fraction of the delay (0-1)
+	RetryOn        []string `yaml:"retry_on,omitempty" json:"retry_on,omitempty"`               // Failure classifications to retry, e.g. "unreachable"
+}
+
 // Config represents the main configuration structure
 type Config struct {
+	Include    []string      `yaml:"include,omitempty" json:"include,omitempty"` // Local files or https:// URLs with shared servers and profiles
 	Servers    []Server      `yaml:"servers" json:"servers"`
 	Profiles   []Profile     `yaml:"profiles,omitempty" json:"profiles,omitempty"`
 	Keyring    KeyringConfig `yaml:"keyring,omitempty" json:"keyring,omitempty"`
+	Retry      RetryPolicy   `yaml:"retry,omitempty" json:"retry,omitempty"`
+	UI         UIConfig      `yaml:"ui,omitempty" json:"ui,omitempty"`
+	ShellAliases ShellAliasConfig `yaml:"shell_aliases,omitempty" json:"shell_aliases,omitempty"`
+	Update     UpdateConfig  `yaml:"update,omitempty" json:"update,omitempty"`
+	Daemon     DaemonConfig  `yaml:"daemon,omitempty" json:"daemon,omitempty"`
+	StatusChecks StatusCheckConfig `yaml:"status_checks,omitempty" json:"status_checks,omitempty"`
+	Archived   []ArchivedServer `yaml:"archived,omitempty" json:"archived,omitempty"` // Expired scratch servers
+	Sanitize   SanitizeConfig `yaml:"sanitize,omitempty" json:"sanitize,omitempty"` // What 'sshm export --sanitize' keeps
+	Transfers  TransferLimits `yaml:"transfers,omitempty" json:"transfers,omitempty"` // Caps all file transfers together
+	PortCheck  PortCheckConfig `yaml:"port_check,omitempty" json:"port_check,omitempty"` // Ports probed by the port check diagnostic
+	PasteGuard PasteGuardConfig `yaml:"paste_guard,omitempty" json:"paste_guard,omitempty"` // Confirmation before large pastes into protected servers
+	Actions    []CustomAction `yaml:"actions,omitempty" json:"actions,omitempty"` // User-defined verbs in the TUI's actions menu
+	Locked     bool           `yaml:"locked,omitempty" json:"locked,omitempty"`   // Provisioned read-only: sshm refuses to save changes
+	WarmPool   WarmPoolConfig `yaml:"warm_pool,omitempty" json:"warm_pool,omitempty"` // Connections kept open to warm and watched servers
+	SSHSync    SSHSyncConfig  `yaml:"ssh_sync,omitempty" json:"ssh_sync,omitempty"`   // Two-way sync with ~/.ssh/config
+	KnownKeys  []KnownKey     `yaml:"known_keys,omitempty" json:"known_keys,omitempty"` // Public keys the authorized_keys audit doesn't flag
+	Runbooks   RunbooksConfig `yaml:"runbooks,omitempty" json:"runbooks,omitempty"`     // Local markdown runbooks browsable in the TUI
+	Recording  RecordingConfig `yaml:"recording,omitempty" json:"recording,omitempty"`  // Where recorded sessions are saved
 	configPath string        // internal field to track config file path
+
+	// Split layout: conf.d files and which of them each server and profile came from
+	inventoryFiles []string
+	serverSources  map[string]string
+	profileSources map[string]string
+
+	// Unmodified servers and profiles merged from includes, never written back
+	includedServers  map[string]Server
+	includedProfiles map[string]Profile
+
+	// Files on disk as last loaded or saved, to notice edits made by someone else
+	synced       bool
+	diskChecksum string
+	baseline     []byte // The configuration as loaded, the base of three-way merges
+
+	encryption *encryptionKey // Set when config.yaml is saved encrypted
 }
 
 // DefaultConfigPath returns the default configuration file path
@@ -87,9 +244,16 @@ func LoadFromPath(configPath string) (*Config, error) {
 		return nil, fmt.Errorf("failed to create config directory: %w", err)
 	}
 
+	// Preconfigured machines seed the configuration on first run
+	if _, err := os.Stat(configPath); os.IsNotExist(err) {
+		if err := bootstrapFromEnvironment(configPath); err != nil {
+			return nil, err
+		}
+	}
+
 	// If file doesn't exist, return empty config with defaults
 	if _, err := os.Stat(configPath); os.IsNotExist(err) {
-		return &Config{
+		config := &Config{
 			Servers:  []Server{},
 			Profiles: []Profile{},
 			Keyring: KeyringConfig{
@@ -98,11 +262,21 @@ func LoadFromPath(configPath string) (*Config, error) {
 				Namespace: "sshm",
 			},
 			configPath: configPath,
-		}, nil
+		}
+		if err := config.loadInventoryFiles(); err != nil {
+			return nil, err
+		}
+		config.ArchiveExpired(time.Now())
+		config.resolveProxyJumps()
+		config.markSynced()
+		return config, nil
 	}
 
-	// Read file
-	data, err := os.ReadFile(configPath)
+	// Read file, decrypting it when it is encrypted
+	data, key, err := readConfigData(configPath)
+	if errors.Is(err, ErrPassphraseRequired) || errors.Is(err, ErrWrongPassphrase) {
+		return nil, err
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to read config file: %w", err)
 	}
@@ -131,6 +305,22 @@ func LoadFromPath(configPath string) (*Config, error) {
 	}
 
 	config.configPath = configPath
+	config.encryption = key
+
+	// Merge servers and profiles kept in split conf.d files
+	if err := config.loadInventoryFiles(); err != nil {
+		return nil, err
+	}
+
+	// Fill in shared servers and profiles from includes
+	if err := config.applyIncludes(); err != nil {
+		return nil, err
+	}
+
+	// Expired scratch servers leave the inventory and are kept in the archive
+	config.ArchiveExpired(time.Now())
+	config.resolveProxyJumps()
+	config.markSynced()
 	return &config, nil
 }
 
@@ -141,23 +331,44 @@ func (c *Config) Save() error {
 
 // SaveToPath saves the configuration to the specified path with proper permissions
 func (c *Config) SaveToPath(configPath string) error {
+	if c.Locked {
+		return ErrLocked
+	}
+	if c.encryption != nil && c.IsSplit() {
+		return fmt.Errorf("the configuration is encrypted and can't be split into conf.d files")
+	}
+
 	// Create directory if it doesn't exist
 	configDir := filepath.Dir(configPath)
 	if err := os.MkdirAll(configDir, 0700); err != nil {
 		return fmt.Errorf("failed to create config directory: %w", err)
 	}
 
+	// Write servers and profiles kept in conf.d files back to them
+	main, err := c.saveInventoryFiles()
+	if err != nil {
+		return err
+	}
+
 	// Marshal to YAML
-	data, err := yaml.Marshal(c)
+	data, err := yaml.Marshal(main)
 	if err != nil {
 		return fmt.Errorf("failed to marshal config: %w", err)
 	}
+	if c.encryption != nil {
+		if data, err = seal(data, c.encryption); err != nil {
+			return err
+		}
+	}
 
 	// Write file with proper permissions (600 - owner read/write only)
 	if err := os.WriteFile(configPath, data, 0600); err != nil {
 		return fmt.Errorf("failed to write config file: %w", err)
 	}
 
+	if configPath == c.configPath {
+		c.markSynced()
+	}
 	return nil
 }
 
@@ -175,37 +386,96 @@ func (c *Config) AddServer(server Server) error {
 		}
 	}
 
+	// Aliases must not shadow other servers' names or aliases
+	if err := c.checkAliasConflicts(server); err != nil {
+		return err
+	}
+
 	// Set default port if not specified
 	if server.Port == 0 {
 		server.Port = 22
 	}
 
+	if _, err := c.JumpChain(server); err != nil {
+		return fmt.Errorf("invalid server configuration: %w", err)
+	}
+
 	c.Servers = append(c.Servers, server)
+	c.resolveProxyJumps()
 	return nil
 }
 
+// UpdateServer replaces the server with the same name
+func (c *Config) UpdateServer(server Server) error {
+	if err := server.Validate(); err != nil {
+		return fmt.Errorf("invalid server configuration: %w", err)
+	}
+
+	if _, err := c.JumpChain(server); err != nil {
+		return fmt.Errorf("invalid server configuration: %w", err)
+	}
+
+	for i := range c.Servers {
+		if c.Servers[i].Name == server.Name {
+			c.Servers[i] = server
+			c.resolveProxyJumps()
+			return nil
+		}
+	}
+	return fmt.Errorf("server '%s' not found", server.Name)
+}
+
 // RemoveServer removes a server from the configuration by name
 func (c *Config) RemoveServer(name string) error {
 	for i, server := range c.Servers {
 		if server.Name == name {
 			// Remove server from slice
 			c.Servers = append(c.Servers[:i], c.Servers[i+1:]...)
+			c.resolveProxyJumps()
 			return nil
 		}
 	}
 	return fmt.Errorf("server '%s' not found", name)
 }
 
-// GetServer retrieves a server by name
+// GetServer retrieves a server by name, falling back to alias lookup
 func (c *Config) GetServer(name string) (*Server, error) {
 	for _, server := range c.Servers {
 		if server.Name == name {
 			return &server, nil
 		}
 	}
+	for _, server := range c.Servers {
+		if server.MatchesName(name) {
+			return &server, nil
+		}
+	}
 	return nil, fmt.Errorf("server '%s' not found", name)
 }
 
+// checkAliasConflicts verifies that a server's aliases don't collide with other servers
+func (c *Config) checkAliasConflicts(server Server) error {
+	for _, alias := range server.Aliases {
+		if alias == server.Name {
+			continue
+		}
+		for _, existing := range c.Servers {
+			if existing.Name == server.Name {
+				continue
+			}
+			if existing.MatchesName(alias) {
+				return fmt.Errorf("alias '%s' conflicts with server '%s'", alias, existing.Name)
+			}
+		}
+	}
+	for _, existing := range c.Servers {
+		if existing.Name != server.Name && contains(existing.Aliases, server.Name) {
+			return fmt.Errorf("server name '%s' is already an alias of server '%s'", server.Name, existing.Name)
+		}
+	}
+	return nil
+}
+
 // GetServers returns all servers
 func (c *Config) GetServers() []Server {
 	return c.Servers
@@ -229,8 +499,8 @@ func (s *Server) Validate() error {
 		return fmt.Errorf("port must be between 1 and 65535")
 	}
 
-	// Validate auth type
-	if s.AuthType != "key" && s.AuthType != "password" {
+	// Validate auth type; servers using an auth provider may leave it empty
+	if s.AuthType != "key" && s.AuthType != "password" && !(s.AuthType == "" && s.AuthProvider != "") {
 		return fmt.Errorf("auth_type must be 'key' or 'password'")
 	}
 
@@ -239,9 +509,146 @@ func (s *Server) Validate() error {
 		return fmt.Errorf("key_path is required when auth_type is 'key'")
 	}
 
+	if strings.TrimSpace(s.ResolveTo) != "" && net.ParseIP(s.ResolveTo) == nil {
+		return fmt.Errorf("resolve_to must be an IP address")
+	}
+
+	for _, alias := range s.Aliases {
+		if strings.TrimSpace(alias) == "" {
+			return fmt.Errorf("aliases must not be empty")
+		}
+	}
+
+	for _, tag := range s.Tags {
+		if strings.TrimSpace(tag) == "" {
+			return fmt.Errorf("tags must not be empty")
+		}
+	}
+
+	if s.Database != nil {
+		if err := s.Database.Validate(); err != nil {
+			return fmt.Errorf("invalid database: %w", err)
+		}
+	}
+
+	if s.Retry != nil {
+		if err := s.Retry.Validate(); err != nil {
+			return fmt.Errorf("invalid retry policy: %w", err)
+		}
+	}
+
+	if s.Bootstrap != nil {
+		if err := s.Bootstrap.Validate(); err != nil {
+			return fmt.Errorf("invalid bootstrap: %w", err)
+		}
+	}
+
+	if err := validateSSHBinary(s.SSHBinary); err != nil {
+		return err
+	}
+	if err := ValidateSSHTemplate(s.SSHTemplate); err != nil {
+		return err
+	}
+	if err := validateLoginSettings(s.WorkDir, s.Shell); err != nil {
+		return err
+	}
+	if err := validateHostKeys(s.HostKeys); err != nil {
+		return err
+	}
+	if err := validateProxyJump(s.Name, s.ProxyJump); err != nil {
+		return err
+	}
+	if err := validateStartup(s.StartupCommands, s.RemoteEnv); err != nil {
+		return err
+	}
+
+	for _, webhook := range s.Webhooks {
+		if err := webhook.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if s.Transfers != nil {
+		if err := s.Transfers.Validate(); err != nil {
+			return fmt.Errorf("invalid transfer limits: %w", err)
+		}
+	}
+
+	if s.Session != nil {
+		if err := s.Session.Validate(); err != nil {
+			return fmt.Errorf("invalid session template: %w", err)
+		}
+	}
+
+	if s.Restricted != nil {
+		if err := s.Restricted.Validate(); err != nil {
+			return fmt.Errorf("invalid restricted access: %w", err)
+		}
+	}
+
+	if s.Timezone != "" {
+		if _, err := s.Location(); err != nil {
+			return err
+		}
+	}
+
+	if strings.ContainsAny(s.AuthProvider, " \t:") {
+		return fmt.Errorf("auth_provider must be the name of a registered provider")
+	}
+
+	if _, err := s.AuthSteps(); err != nil {
+		return fmt.Errorf("invalid auth_chain: %w", err)
+	}
+
+	tunnelNames := make(map[string]bool)
+	for _, tunnel := range s.Tunnels {
+		if err := tunnel.Validate(); err != nil {
+			return err
+		}
+		if tunnelNames[tunnel.Name] {
+			return fmt.Errorf("duplicate tunnel name '%s'", tunnel.Name)
+		}
+		tunnelNames[tunnel.Name] = true
+	}
+
+	return nil
+}
+
+// Validate validates a retry policy
+func (r *RetryPolicy) Validate() error {
+	if r.Attempts < 0 {
+		return fmt.Errorf("attempts must not be negative")
+	}
+
+	for _, value := range []string{r.InitialBackoff, r.MaxBackoff} {
+		if value == "" {
+			continue
+		}
+		if d, err := time.ParseDuration(value); err != nil || d < 0 {
+			return fmt.Errorf("invalid backoff duration '%s'", value)
+		}
+	}
+
+	if r.Multiplier < 0 {
+		return fmt.Errorf("multiplier must not be negative")
+	}
+
+	if r.Jitter < 0 || r.Jitter > 1 {
+		return fmt.Errorf("jitter must be between 0 and 1")
+	}
+
 	return nil
 }
 
+// RetryPolicyFor returns the retry policy that applies to a server,
+// preferring the server's own override over the global policy
+func (c *Config) RetryPolicyFor(server Server) RetryPolicy {
+	if server.Retry != nil {
+		return *server.Retry
+	}
+	return c.Retry
+}
+
 // ExpandPath expands ~ to the user's home directory in file paths
 func ExpandPath(path string) (string, error) {
 	if !strings.HasPrefix(path, "~") {
@@ -271,6 +678,25 @@ func (p *Profile) Validate() error {
 	if strings.TrimSpace(p.Name) == "" {
 		return fmt.Errorf("profile name is required")
 	}
+	if p.Bootstrap != nil {
+		if err := p.Bootstrap.Validate(); err != nil {
+			return fmt.Errorf("invalid bootstrap: %w", err)
+		}
+	}
+	if p.StealthWindow != "" {
+		if d, err := time.ParseDuration(p.StealthWindow); err != nil || d <= 0 {
+			return fmt.Errorf("invalid stealth window '%s'", p.StealthWindow)
+		}
+	}
+	if err := validateSSHBinary(p.SSHBinary); err != nil {
+		return err
+	}
+	if err := ValidateSSHTemplate(p.SSHTemplate); err != nil {
+		return err
+	}
+	if err := validateLoginSettings(p.WorkDir, p.Shell); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -324,6 +750,37 @@ func (c *Config) GetProfiles() []Profile {
 	return c.Profiles
 }
 
+// ServerProfiles returns the names of the profiles a server belongs to
+func (c *Config) ServerProfiles(serverName string) []string {
+	var profiles []string
+	for _, profile := range c.Profiles {
+		if contains(profile.Servers, serverName) {
+			profiles = append(profiles, profile.Name)
+		}
+	}
+	return profiles
+}
+
+// QuickStatsEnabled reports whether any profile the server belongs to opted in to quick stats
+func (c *Config) QuickStatsEnabled(serverName string) bool {
+	for _, profile := range c.Profiles {
+		if profile.QuickStats && contains(profile.Servers, serverName) {
+			return true
+		}
+	}
+	return false
+}
+
+// AnyQuickStats reports whether any profile opted in to quick stats
+func (c *Config) AnyQuickStats() bool {
+	for _, profile := range c.Profiles {
+		if profile.QuickStats {
+			return true
+		}
+	}
+	return false
+}
+
 // GetServersByProfile retrieves all servers belonging to a specific profile
 func (c *Config) GetServersByProfile(profileName string) ([]Server, error) {
 	profile, err := c.GetProfile(profileName)
@@ -425,4 +882,14 @@ func (c *Config) hasAnyKeyringSettings() bool {
 	}
 	
 	return false
-}
\ No newline at end of file
+}
+
+// contains checks if a string slice contains a specific value
+func contains(slice []string, item string) bool {
+	for _, s := range slice {
+		if s == item {
+			return true
+		}
+	}
+	return false
+}
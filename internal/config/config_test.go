@@ -133,6 +133,27 @@ func TestConfigRemoveServer(t *testing.T) {
 	}
 }
 
+func TestConfigUpdateServer(t *testing.T) {
+	config := &Config{
+		Servers: []Server{
+			{Name: "server1", Hostname: "example1.com", Port: 22, Username: "user1", AuthType: "key", KeyPath: "~/.ssh/id_rsa"},
+		},
+	}
+
+	server, _ := config.GetServer("server1")
+	server.Watch = true
+	if err := config.UpdateServer(*server); err != nil {
+		t.Fatalf("Expected no error updating server, got: %v", err)
+	}
+	if !config.Servers[0].Watch {
+		t.Error("Expected update to be stored in the configuration")
+	}
+
+	if err := config.UpdateServer(Server{Name: "missing", Hostname: "x", Port: 22, Username: "u", AuthType: "key", KeyPath: "/k"}); err == nil {
+		t.Error("Expected error when updating non-existent server")
+	}
+}
+
 func TestConfigRemoveNonExistentServer(t *testing.T) {
 	config := &Config{
 		Servers: []Server{
@@ -517,6 +538,25 @@ func TestConfigGetServersByProfile(t *testing.T) {
 	}
 }
 
+func TestConfigQuickStatsEnabled(t *testing.T) {
+	config := &Config{
+		Profiles: []Profile{
+			{Name: "dev", Servers: []string{"dev1"}},
+			{Name: "prod", Servers: []string{"web1"}, QuickStats: true},
+		},
+	}
+
+	if !config.QuickStatsEnabled("web1") {
+		t.Error("Expected quick stats for server in opted-in profile")
+	}
+	if config.QuickStatsEnabled("dev1") {
+		t.Error("Expected no quick stats for server outside opted-in profiles")
+	}
+	if !config.AnyQuickStats() {
+		t.Error("Expected AnyQuickStats to report the opted-in profile")
+	}
+}
+
 func TestConfigGetServersByNonExistentProfile(t *testing.T) {
 	config := &Config{
 		Servers:  []Server{},
@@ -797,4 +837,64 @@ func TestConfigBackwardCompatibility(t *testing.T) {
 	if len(reloadedConfig.Profiles) != 1 {
 		t.Errorf("Expected 1 profile after reload, got %d", len(reloadedConfig.Profiles))
 	}
-}
\ No newline at end of file
+}
+func TestServerResolveToAndAliases(t *testing.T) {
+	server := Server{
+		Name:      "intranet",
+		Hostname:  "app.corp.local",
+		Port:      22,
+		Username:  "ops",
+		AuthType:  "password",
+		ResolveTo: "10.0.4.12",
+		Aliases:   []string{"app", "corp-app"},
+	}
+
+	if err := server.Validate(); err != nil {
+		t.Fatalf("Expected valid server, got: %v", err)
+	}
+	if got := server.GetEffectiveHostname(); got != "10.0.4.12" {
+		t.Errorf("Expected effective hostname '10.0.4.12', got '%s'", got)
+	}
+	if got := server.GetSSHOptions(); got != " -o HostKeyAlias=app.corp.local" {
+		t.Errorf("Unexpected ssh options: '%s'", got)
+	}
+
+	server.ResolveTo = "not-an-ip"
+	if err := server.Validate(); err == nil {
+		t.Error("Expected error for non-IP resolve_to")
+	}
+
+	server.ResolveTo = ""
+	if got := server.GetEffectiveHostname(); got != "app.corp.local" {
+		t.Errorf("Expected hostname fallback, got '%s'", got)
+	}
+	if got := server.GetSSHOptions(); got != "" {
+		t.Errorf("Expected no extra options, got '%s'", got)
+	}
+}
+
+func TestGetServerByAlias(t *testing.T) {
+	cfg := &Config{}
+	if err := cfg.AddServer(Server{Name: "web-01", Hostname: "web1.example.com", Port: 22, Username: "u", AuthType: "password", Aliases: []string{"web"}}); err != nil {
+		t.Fatalf("Failed to add server: %v", err)
+	}
+
+	server, err := cfg.GetServer("web")
+	if err != nil {
+		t.Fatalf("Expected alias lookup to succeed, got: %v", err)
+	}
+	if server.Name != "web-01" {
+		t.Errorf("Expected 'web-01', got '%s'", server.Name)
+	}
+
+	// Aliases may not shadow other servers
+	err = cfg.AddServer(Server{Name: "web-02", Hostname: "web2.example.com", Port: 22, Username: "u", AuthType: "password", Aliases: []string{"web"}})
+	if err == nil {
+		t.Error("Expected alias conflict error")
+	}
+
+	err = cfg.AddServer(Server{Name: "web", Hostname: "web3.example.com", Port: 22, Username: "u", AuthType: "password"})
+	if err == nil {
+		t.Error("Expected error when a server name equals an existing alias")
+	}
+}
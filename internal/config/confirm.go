@@ -0,0 +1,90 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Confirmation modes
+const (
+	ConfirmAlways    = "always"    // Always ask before the action
+	ConfirmNever     = "never"     // Never ask
+	ConfirmProtected = "protected" // Only ask when a protected server is affected
+)
+
+// Actions with a configurable confirmation prompt
+const (
+	ConfirmDeleteServer    = "delete_server"
+	ConfirmDeleteProfile   = "delete_profile"
+	ConfirmKillSession     = "kill_session"
+	ConfirmCleanupSessions = "cleanup_sessions"
+	ConfirmQuit            = "quit" // Quitting the TUI while tunnels are running
+	ConfirmPowerAction     = "power_action"
+	ConfirmDistribute      = "distribute" // Pushing a file to the servers of a profile
+)
+
+// ConfirmActions lists the actions whose confirmation can be configured
+var ConfirmActions = []string{
+	ConfirmDeleteServer,
+	ConfirmDeleteProfile,
+	ConfirmKillSession,
+	ConfirmCleanupSessions,
+	ConfirmQuit,
+	ConfirmPowerAction,
+	ConfirmDistribute,
+}
+
+// ConfirmModes lists the supported confirmation modes
+var ConfirmModes = []string{ConfirmAlways, ConfirmNever, ConfirmProtected}
+
+// NormalizeConfirmAction resolves an action name such as "delete-server" to its canonical name
+func NormalizeConfirmAction(action string) (string, error) {
+	action = strings.ReplaceAll(strings.ToLower(strings.TrimSpace(action)), "-", "_")
+	if !contains(ConfirmActions, action) {
+		return "", fmt.Errorf("unknown confirmation '%s' (use %s)", action, strings.Join(ConfirmActions, ", "))
+	}
+	return action, nil
+}
+
+// ConfirmationMode returns the configured mode for an action, ConfirmAlways by default
+func (u *UIConfig) ConfirmationMode(action string) string {
+	if mode, ok := u.Confirmations[action]; ok && mode != "" {
+		return mode
+	}
+	return ConfirmAlways
+}
+
+// ShouldConfirm reports whether an action must be confirmed; protected tells
+// whether the action affects a protected server
+func (u *UIConfig) ShouldConfirm(action string, protected bool) bool {
+	switch u.ConfirmationMode(action) {
+	case ConfirmNever:
+		return false
+	case ConfirmProtected:
+		return protected
+	default:
+		return true
+	}
+}
+
+// SetConfirmation sets the mode for an action; ConfirmAlways removes the override
+func (u *UIConfig) SetConfirmation(action, mode string) error {
+	action, err := NormalizeConfirmAction(action)
+	if err != nil {
+		return err
+	}
+	mode = strings.ToLower(strings.TrimSpace(mode))
+	if !contains(ConfirmModes, mode) {
+		return fmt.Errorf("invalid confirmation mode '%s' for %s (use %s)", mode, action, strings.Join(ConfirmModes, ", "))
+	}
+
+	if mode == ConfirmAlways {
+		delete(u.Confirmations, action)
+		return nil
+	}
+	if u.Confirmations == nil {
+		u.Confirmations = make(map[string]string)
+	}
+	u.Confirmations[action] = mode
+	return nil
+}
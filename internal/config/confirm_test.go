@@ -0,0 +1,44 @@
+package config
+
+import "testing"
+
+func TestShouldConfirm(t *testing.T) {
+	var ui UIConfig
+	if !ui.ShouldConfirm(ConfirmDeleteServer, false) {
+		t.Error("Expected confirmation by default")
+	}
+
+	if err := ui.SetConfirmation("delete-server", "protected"); err != nil {
+		t.Fatalf("SetConfirmation failed: %v", err)
+	}
+	if ui.ShouldConfirm(ConfirmDeleteServer, false) {
+		t.Error("Expected no confirmation for unprotected server in protected mode")
+	}
+	if !ui.ShouldConfirm(ConfirmDeleteServer, true) {
+		t.Error("Expected confirmation for protected server in protected mode")
+	}
+
+	if err := ui.SetConfirmation(ConfirmQuit, "never"); err != nil {
+		t.Fatalf("SetConfirmation failed: %v", err)
+	}
+	if ui.ShouldConfirm(ConfirmQuit, true) {
+		t.Error("Expected no confirmation in never mode")
+	}
+
+	if err := ui.SetConfirmation(ConfirmQuit, "always"); err != nil {
+		t.Fatalf("SetConfirmation failed: %v", err)
+	}
+	if _, ok := ui.Confirmations[ConfirmQuit]; ok {
+		t.Error("Expected always to remove the override")
+	}
+}
+
+func TestSetConfirmationInvalid(t *testing.T) {
+	var ui UIConfig
+	if err := ui.SetConfirmation("format_disk", "never"); err == nil {
+		t.Error("Expected error for unknown action")
+	}
+	if err := ui.SetConfirmation(ConfirmKillSession, "sometimes"); err == nil {
+		t.Error("Expected error for unknown mode")
+	}
+}
@@ -0,0 +1,292 @@
+package config
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Kinds of entries merged when the configuration changed on disk
+const (
+	MergeServer   = "server"
+	MergeProfile  = "profile"
+	MergeSettings = "settings"
+)
+
+// MergeConflict is a server, profile or the settings changed one way in memory
+// and another way on disk since the configuration was loaded
+type MergeConflict struct {
+	Kind       string // MergeServer, MergeProfile or MergeSettings
+	Name       string
+	Mine       string // Summary of the in-memory version, "(deleted)" when removed
+	Theirs     string // Summary of the version on disk
+	TakeTheirs bool   // Resolution; the in-memory version is kept by default
+}
+
+// ConfigMerge reconciles in-memory changes with changes made to the files on
+// disk, entry by entry: whatever only one side changed is taken from that side
+// and entries both sides changed differently are resolved through Conflicts
+type ConfigMerge struct {
+	Conflicts    []MergeConflict
+	TheirChanges int // Entries changed only on disk, taken over as they are
+
+	mine, theirs, base *Config
+}
+
+// ChangedOnDisk reports whether config.yaml or a conf.d file was written by
+// someone else since the configuration was loaded or last saved
+func (c *Config) ChangedOnDisk() (bool, error) {
+	if !c.synced {
+		return false, nil
+	}
+	sum, err := inventoryChecksum(c.configPath)
+	if err != nil {
+		return false, fmt.Errorf("failed to check config file: %w", err)
+	}
+	return sum != c.diskChecksum, nil
+}
+
+// PrepareMerge reads the configuration on disk and works out which entries merge
+// cleanly with the in-memory changes and which conflict
+func (c *Config) PrepareMerge() (*ConfigMerge, error) {
+	if !c.synced {
+		return nil, fmt.Errorf("configuration was not loaded from a file")
+	}
+
+	var base Config
+	if err := yaml.Unmarshal(c.baseline, &base); err != nil {
+		return nil, fmt.Errorf("failed to parse configuration as loaded: %w", err)
+	}
+	theirs, err := LoadFromPath(c.configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &ConfigMerge{mine: c, theirs: theirs, base: &base}
+	m.merge()
+	return m, nil
+}
+
+// Apply replaces the in-memory configuration with the merge, resolving each
+// conflict as chosen. Call Save to write the result.
+func (m *ConfigMerge) Apply() {
+	servers, profiles, settings := m.merge()
+
+	// The split layout bookkeeping and baseline follow the files as they are now
+	merged := *m.theirs
+	copySettings(&merged, &settings)
+	merged.Servers = servers
+	merged.Profiles = profiles
+	*m.mine = merged
+}
+
+// merge combines the three versions, recording conflicts and their changes on
+// the first call and applying the chosen resolutions on later ones
+func (m *ConfigMerge) merge() ([]Server, []Profile, Config) {
+	resolved := m.Conflicts != nil
+	takeTheirs := make(map[string]bool)
+	for _, conflict := range m.Conflicts {
+		takeTheirs[conflict.Kind+"/"+conflict.Name] = conflict.TakeTheirs
+	}
+
+	var conflicts []MergeConflict
+	theirChanges := 0
+	resolve := func(kind string) func(name, mine, theirs string) bool {
+		return func(name, mine, theirs string) bool {
+			conflicts = append(conflicts, MergeConflict{Kind: kind, Name: name, Mine: mine, Theirs: theirs})
+			return takeTheirs[kind+"/"+name]
+		}
+	}
+	count := func(n int) { theirChanges += n }
+
+	servers := mergeEntries(m.base.Servers, m.mine.Servers, m.theirs.Servers,
+		func(s Server) string { return s.Name }, describeServer, resolve(MergeServer), count)
+	profiles := mergeEntries(m.base.Profiles, m.mine.Profiles, m.theirs.Profiles,
+		func(p Profile) string { return p.Name }, describeProfile, resolve(MergeProfile), count)
+
+	base := settingsOf(m.base)
+	describeSettings := func(settings *Config) string {
+		return "changed " + strings.Join(changedSettings(&base, settings), ", ")
+	}
+	settings := mergeEntries([]Config{base}, []Config{settingsOf(m.mine)}, []Config{settingsOf(m.theirs)},
+		func(Config) string { return MergeSettings }, describeSettings, resolve(MergeSettings), count)
+
+	if !resolved {
+		m.Conflicts = conflicts
+		m.TheirChanges = theirChanges
+	}
+	if m.Conflicts == nil {
+		m.Conflicts = []MergeConflict{}
+	}
+	return servers, profiles, settings[0]
+}
+
+// mergeEntries merges named entries three ways. Entries come in the order found
+// on disk, followed by the ones only added in memory.
+func mergeEntries[T any](base, mine, theirs []T, name func(T) string, describe func(*T) string,
+	conflict func(name, mine, theirs string) bool, theirChange func(int)) []T {
+	index := func(entries []T) map[string]*T {
+		byName := make(map[string]*T, len(entries))
+		for i := range entries {
+			byName[name(entries[i])] = &entries[i]
+		}
+		return byName
+	}
+	baseByName, mineByName, theirsByName := index(base), index(mine), index(theirs)
+
+	var names []string
+	for _, entry := range theirs {
+		names = append(names, name(entry))
+	}
+	for _, entry := range mine {
+		if theirsByName[name(entry)] == nil {
+			names = append(names, name(entry))
+		}
+	}
+
+	merged := []T{}
+	for _, entryName := range names {
+		b, m, t := baseByName[entryName], mineByName[entryName], theirsByName[entryName]
+		var pick *T
+		switch {
+		case sameEntry(m, t), sameEntry(t, b):
+			pick = m
+		case sameEntry(m, b):
+			pick = t
+			theirChange(1)
+		default:
+			if conflict(entryName, describeEntry(m, describe), describeEntry(t, describe)) {
+				pick = t
+			} else {
+				pick = m
+			}
+		}
+		if pick != nil {
+			merged = append(merged, *pick)
+		}
+	}
+	return merged
+}
+
+// sameEntry compares two versions of an entry, nil meaning absent, by their YAML
+func sameEntry[T any](a, b *T) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	left, errLeft := yaml.Marshal(a)
+	right, errRight := yaml.Marshal(b)
+	return errLeft == nil && errRight == nil && bytes.Equal(left, right)
+}
+
+// describeEntry summarizes a version of an entry for a conflict prompt
+func describeEntry[T any](entry *T, describe func(*T) string) string {
+	if entry == nil {
+		return "(deleted)"
+	}
+	return describe(entry)
+}
+
+func describeServer(server *Server) string {
+	return fmt.Sprintf("%s@%s:%d (%s)", server.Username, server.Hostname, server.Port, server.AuthType)
+}
+
+func describeProfile(profile *Profile) string {
+	return fmt.Sprintf("%d server(s): %s", len(profile.Servers), strings.Join(profile.Servers, ", "))
+}
+
+// settingsOf returns a copy of everything in the configuration but the inventory
+func settingsOf(c *Config) Config {
+	var settings Config
+	copySettings(&settings, c)
+	return settings
+}
+
+// copySettings copies every exported field but servers and profiles
+func copySettings(dst, src *Config) {
+	dstValue := reflect.ValueOf(dst).Elem()
+	srcValue := reflect.ValueOf(src).Elem()
+	configType := dstValue.Type()
+	for i := 0; i < configType.NumField(); i++ {
+		field := configType.Field(i)
+		if !field.IsExported() || field.Name == "Servers" || field.Name == "Profiles" {
+			continue
+		}
+		dstValue.Field(i).Set(srcValue.Field(i))
+	}
+}
+
+// changedSettings lists the YAML sections of the settings that differ from base
+func changedSettings(base, settings *Config) []string {
+	var changed []string
+	baseValue := reflect.ValueOf(base).Elem()
+	value := reflect.ValueOf(settings).Elem()
+	configType := value.Type()
+	for i := 0; i < configType.NumField(); i++ {
+		field := configType.Field(i)
+		name := strings.Split(field.Tag.Get("yaml"), ",")[0]
+		if name == "" || name == "-" || name == "servers" || name == "profiles" {
+			continue
+		}
+		left, right := baseValue.Field(i).Interface(), value.Field(i).Interface()
+		if !sameEntry(&left, &right) {
+			changed = append(changed, name)
+		}
+	}
+	return changed
+}
+
+// markSynced remembers the files on disk as just loaded or saved: their checksum,
+// to notice external edits, and the configuration itself, as the base of merges
+func (c *Config) markSynced() {
+	sum, err := inventoryChecksum(c.configPath)
+	if err != nil {
+		c.synced = false
+		return
+	}
+	baseline, err := yaml.Marshal(c)
+	if err != nil {
+		c.synced = false
+		return
+	}
+	c.diskChecksum, c.baseline, c.synced = sum, baseline, true
+}
+
+// inventoryChecksum hashes config.yaml and the split inventory files under conf.d
+func inventoryChecksum(configPath string) (string, error) {
+	paths := []string{configPath}
+	entries, err := os.ReadDir(ConfDir(configPath))
+	if err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+	var split []string
+	for _, entry := range entries {
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if !entry.IsDir() && (ext == ".yaml" || ext == ".yml") {
+			split = append(split, filepath.Join(ConfDir(configPath), entry.Name()))
+		}
+	}
+	sort.Strings(split)
+	paths = append(paths, split...)
+
+	hash := sha256.New()
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(hash, "%s\x00%d\x00", path, len(data))
+		hash.Write(data)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
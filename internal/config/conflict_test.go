@@ -0,0 +1,166 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+const conflictTestConfig = `servers:
+  - name: a
+    hostname: a.example.com
+    port: 22
+    username: deploy
+    auth_type: key
+    key_path: ~/.ssh/id_rsa
+  - name: b
+    hostname: b.example.com
+    port: 22
+    username: deploy
+    auth_type: key
+    key_path: ~/.ssh/id_rsa
+  - name: c
+    hostname: c.example.com
+    port: 22
+    username: deploy
+    auth_type: key
+    key_path: ~/.ssh/id_rsa
+profiles:
+  - name: prod
+    servers: [a, b]
+`
+
+func conflictTestServer(name string) Server {
+	return Server{Name: name, Hostname: name + ".example.com", Port: 22, Username: "deploy", AuthType: "key", KeyPath: "~/.ssh/id_rsa"}
+}
+
+func TestChangedOnDisk(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	writeTestFile(t, configPath, conflictTestConfig)
+
+	cfg, err := LoadFromPath(configPath)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if changed, err := cfg.ChangedOnDisk(); err != nil || changed {
+		t.Fatalf("Expected no change right after loading, got %v, %v", changed, err)
+	}
+
+	// Our own saves are not external changes
+	if err := cfg.AddServer(conflictTestServer("d")); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if changed, _ := cfg.ChangedOnDisk(); changed {
+		t.Error("Expected own save not to count as a change on disk")
+	}
+
+	writeTestFile(t, configPath, conflictTestConfig)
+	if changed, _ := cfg.ChangedOnDisk(); !changed {
+		t.Error("Expected an external write to be detected")
+	}
+
+	// A configuration not loaded from a file is never out of date
+	if changed, _ := (&Config{}).ChangedOnDisk(); changed {
+		t.Error("Expected an unloaded configuration not to report changes")
+	}
+}
+
+func TestPrepareMergeAndApply(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	writeTestFile(t, configPath, conflictTestConfig)
+
+	mine, err := LoadFromPath(configPath)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Someone else edits b, c and the UI settings and adds d
+	theirs, err := LoadFromPath(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := conflictTestServer("b")
+	b.Hostname = "b2.example.com"
+	c := conflictTestServer("c")
+	c.Port = 2222
+	if err := theirs.UpdateServer(b); err != nil {
+		t.Fatal(err)
+	}
+	if err := theirs.UpdateServer(c); err != nil {
+		t.Fatal(err)
+	}
+	if err := theirs.AddServer(conflictTestServer("d")); err != nil {
+		t.Fatal(err)
+	}
+	theirs.UI.IdleLockMinutes = 5
+	if err := theirs.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Meanwhile we edit c too, remove a and add e
+	c.Port = 22
+	c.Username = "admin"
+	if err := mine.UpdateServer(c); err != nil {
+		t.Fatal(err)
+	}
+	if err := mine.RemoveServer("a"); err != nil {
+		t.Fatal(err)
+	}
+	mine.Profiles[0].Servers = []string{"b"}
+	if err := mine.AddServer(conflictTestServer("e")); err != nil {
+		t.Fatal(err)
+	}
+
+	if changed, _ := mine.ChangedOnDisk(); !changed {
+		t.Fatal("Expected the external save to be detected")
+	}
+	merge, err := mine.PrepareMerge()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(merge.Conflicts) != 1 || merge.Conflicts[0].Kind != MergeServer || merge.Conflicts[0].Name != "c" {
+		t.Fatalf("Expected a single conflict on server c, got %+v", merge.Conflicts)
+	}
+	if merge.Conflicts[0].Mine != "admin@c.example.com:22 (key)" || merge.Conflicts[0].Theirs != "deploy@c.example.com:2222 (key)" {
+		t.Errorf("Unexpected conflict summaries: %+v", merge.Conflicts[0])
+	}
+	if merge.TheirChanges != 3 {
+		t.Errorf("Expected 3 changes taken from disk (b, d, settings), got %d", merge.TheirChanges)
+	}
+
+	merge.Conflicts[0].TakeTheirs = true
+	merge.Apply()
+	if err := mine.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if changed, _ := mine.ChangedOnDisk(); changed {
+		t.Error("Expected the merged configuration to be in sync after saving")
+	}
+
+	saved, err := LoadFromPath(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, server := range saved.Servers {
+		names = append(names, server.Name)
+	}
+	if len(names) != 4 || names[0] != "b" || names[1] != "c" || names[2] != "d" || names[3] != "e" {
+		t.Errorf("Expected servers b, c, d, e, got %v", names)
+	}
+	if server, _ := saved.GetServer("b"); server.Hostname != "b2.example.com" {
+		t.Errorf("Expected their change to b, got %s", server.Hostname)
+	}
+	if server, _ := saved.GetServer("c"); server.Port != 2222 || server.Username != "deploy" {
+		t.Errorf("Expected their version of c, got %+v", server)
+	}
+	if saved.UI.IdleLockMinutes != 5 {
+		t.Errorf("Expected their UI settings, got idle lock %d", saved.UI.IdleLockMinutes)
+	}
+	profile, _ := saved.GetProfile("prod")
+	if len(profile.Servers) != 1 || profile.Servers[0] != "b" {
+		t.Errorf("Expected a removed from prod, got %v", profile.Servers)
+	}
+}
@@ -0,0 +1,67 @@
+package config
+
+import (
+	"sort"
+	"time"
+)
+
+// DefaultCredentialWarningDays is how many days ahead credential reminders start when not configured
+const DefaultCredentialWarningDays = 14
+
+// Credential reminder kinds
+const (
+	CredentialKeyRotation   = "key rotation"
+	CredentialAccountExpiry = "account expiry"
+)
+
+// CredentialDates records when a server's credentials need attention
+type CredentialDates struct {
+	KeyRotationDue *time.Time `yaml:"key_rotation_due,omitempty" json:"key_rotation_due,omitempty"` // When the key or password should be rotated
+	AccountExpires *time.Time `yaml:"account_expires,omitempty" json:"account_expires,omitempty"`   // When the account on the server expires
+}
+
+// IsEmpty reports whether no date is set
+func (c *CredentialDates) IsEmpty() bool {
+	return c == nil || (c.KeyRotationDue == nil && c.AccountExpires == nil)
+}
+
+// CredentialWarningWindow returns how far ahead credential reminders start
+func (u *UIConfig) CredentialWarningWindow() time.Duration {
+	days := u.CredentialWarningDays
+	if days <= 0 {
+		days = DefaultCredentialWarningDays
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// CredentialReminder is a credential date that is due within the warning window or has passed
+type CredentialReminder struct {
+	Server string
+	Kind   string // CredentialKeyRotation or CredentialAccountExpiry
+	Due    time.Time
+}
+
+// IsOverdue reports whether the date has passed
+func (r CredentialReminder) IsOverdue(now time.Time) bool {
+	return !now.Before(r.Due)
+}
+
+// CredentialReminders returns the credential dates of all servers that are due
+// within window of now, overdue ones included, soonest first
+func (c *Config) CredentialReminders(now time.Time, window time.Duration) []CredentialReminder {
+	var reminders []CredentialReminder
+	add := func(server, kind string, due *time.Time) {
+		if due != nil && due.Sub(now) <= window {
+			reminders = append(reminders, CredentialReminder{Server: server, Kind: kind, Due: *due})
+		}
+	}
+	for _, server := range c.Servers {
+		if server.Credentials.IsEmpty() {
+			continue
+		}
+		add(server.Name, CredentialKeyRotation, server.Credentials.KeyRotationDue)
+		add(server.Name, CredentialAccountExpiry, server.Credentials.AccountExpires)
+	}
+	sort.SliceStable(reminders, func(i, j int) bool { return reminders[i].Due.Before(reminders[j].Due) })
+	return reminders
+}
@@ -0,0 +1,42 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestCredentialReminders(t *testing.T) {
+	var cfg Config
+	data := `servers:
+  - name: web1
+    credentials:
+      key_rotation_due: 2026-03-10
+      account_expires: 2026-06-01
+  - name: db1
+    credentials:
+      account_expires: 2026-02-20
+  - name: plain
+`
+	if err := yaml.Unmarshal([]byte(data), &cfg); err != nil {
+		t.Fatalf("Failed to parse credential dates: %v", err)
+	}
+
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	reminders := cfg.CredentialReminders(now, cfg.UI.CredentialWarningWindow())
+	if len(reminders) != 2 {
+		t.Fatalf("Expected the overdue and the upcoming date only, got %+v", reminders)
+	}
+	if reminders[0].Server != "db1" || reminders[0].Kind != CredentialAccountExpiry || !reminders[0].IsOverdue(now) {
+		t.Errorf("Expected db1's overdue account first, got %+v", reminders[0])
+	}
+	if reminders[1].Server != "web1" || reminders[1].Kind != CredentialKeyRotation || reminders[1].IsOverdue(now) {
+		t.Errorf("Expected web1's key rotation second, got %+v", reminders[1])
+	}
+
+	cfg.UI.CredentialWarningDays = 100
+	if got := len(cfg.CredentialReminders(now, cfg.UI.CredentialWarningWindow())); got != 3 {
+		t.Errorf("Expected a wider window to include every date, got %d", got)
+	}
+}
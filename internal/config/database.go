@@ -0,0 +1,95 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DatabaseTag is the tag carried by servers that front a database
+const DatabaseTag = "database"
+
+// DatabaseConfig describes a database reached through a server via a tunnel
+type DatabaseConfig struct {
+	Engine    string `yaml:"engine" json:"engine"`                             // postgres, mysql, mongodb or redis
+	Host      string `yaml:"host,omitempty" json:"host,omitempty"`             // Database host as seen from the server (default: localhost)
+	Port      int    `yaml:"port,omitempty" json:"port,omitempty"`             // Database port (default: the engine's standard port)
+	Name      string `yaml:"name,omitempty" json:"name,omitempty"`             // Database name
+	User      string `yaml:"user,omitempty" json:"user,omitempty"`             // Database user (default: the server's username)
+	LocalPort int    `yaml:"local_port,omitempty" json:"local_port,omitempty"` // Local tunnel port (0 = pick a free port)
+	Client    string `yaml:"client,omitempty" json:"client,omitempty"`         // Client command template
+}
+
+// databaseEngines maps supported engines to their default port and client command template.
+// Templates may use {host}, {port}, {user} and {database} placeholders.
+var databaseEngines = map[string]struct {
+	port   int
+	client string
+}{
+	"postgres": {5432, "psql -h {host} -p {port} -U {user} {database}"},
+	"mysql":    {3306, "mysql -h {host} -P {port} -u {user} -p {database}"},
+	"mongodb":  {27017, "mongosh mongodb://{host}:{port}/{database}"},
+	"redis":    {6379, "redis-cli -h {host} -p {port}"},
+}
+
+// Validate validates a database configuration
+func (d *DatabaseConfig) Validate() error {
+	_, known := databaseEngines[d.Engine]
+	if !known && strings.TrimSpace(d.Client) == "" {
+		return fmt.Errorf("unsupported engine '%s' (supported: postgres, mysql, mongodb, redis); set client to use another engine", d.Engine)
+	}
+	if !known && d.Port == 0 {
+		return fmt.Errorf("port is required for engine '%s'", d.Engine)
+	}
+	if d.Port < 0 || d.Port > 65535 {
+		return fmt.Errorf("port must be between 1 and 65535")
+	}
+	if d.LocalPort < 0 || d.LocalPort > 65535 {
+		return fmt.Errorf("local port must be between 0 and 65535")
+	}
+	return nil
+}
+
+// GetPort returns the database port, falling back to the engine's default
+func (d *DatabaseConfig) GetPort() int {
+	if d.Port != 0 {
+		return d.Port
+	}
+	return databaseEngines[d.Engine].port
+}
+
+// Tunnel returns the port forward used to reach the database
+func (d *DatabaseConfig) Tunnel() Tunnel {
+	return Tunnel{
+		Name:       "db",
+		LocalPort:  d.LocalPort,
+		RemoteHost: d.Host,
+		RemotePort: d.GetPort(),
+		AutoPort:   true,
+	}
+}
+
+// ClientCommand expands the client command template for a tunnel bound to localPort
+func (d *DatabaseConfig) ClientCommand(server Server, localPort int) string {
+	template := strings.TrimSpace(d.Client)
+	if template == "" {
+		template = databaseEngines[d.Engine].client
+	}
+
+	user := d.User
+	if user == "" {
+		user = server.Username
+	}
+
+	replacer := strings.NewReplacer(
+		"{host}", "127.0.0.1",
+		"{port}", fmt.Sprintf("%d", localPort),
+		"{user}", user,
+		"{database}", d.Name,
+	)
+	return strings.TrimSpace(replacer.Replace(template))
+}
+
+// IsDatabase reports whether the server fronts a database with launchable metadata
+func (s *Server) IsDatabase() bool {
+	return s.Database != nil
+}
@@ -0,0 +1,49 @@
+package config
+
+import "testing"
+
+func TestDatabaseValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		database DatabaseConfig
+		wantErr  bool
+	}{
+		{"known engine", DatabaseConfig{Engine: "postgres"}, false},
+		{"unknown engine", DatabaseConfig{Engine: "oracle"}, true},
+		{"custom client without port", DatabaseConfig{Engine: "oracle", Client: "sqlplus"}, true},
+		{"custom client with port", DatabaseConfig{Engine: "oracle", Client: "sqlplus", Port: 1521}, false},
+		{"invalid port", DatabaseConfig{Engine: "mysql", Port: 70000}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.database.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDatabaseTunnel(t *testing.T) {
+	database := DatabaseConfig{Engine: "mysql", Host: "db.internal"}
+	tunnel := database.Tunnel()
+
+	if tunnel.RemotePort != 3306 || tunnel.RemoteHost != "db.internal" || !tunnel.AutoPort {
+		t.Errorf("Unexpected database tunnel: %+v", tunnel)
+	}
+}
+
+func TestDatabaseClientCommand(t *testing.T) {
+	server := Server{Name: "db-01", Username: "ops"}
+
+	postgres := DatabaseConfig{Engine: "postgres", Name: "orders"}
+	if got := postgres.ClientCommand(server, 15432); got != "psql -h 127.0.0.1 -p 15432 -U ops orders" {
+		t.Errorf("Unexpected postgres command: %s", got)
+	}
+
+	custom := DatabaseConfig{Engine: "mongodb", User: "reader", Client: "mongosh --username {user} --port {port}"}
+	if got := custom.ClientCommand(server, 27018); got != "mongosh --username reader --port 27018" {
+		t.Errorf("Unexpected custom command: %s", got)
+	}
+}
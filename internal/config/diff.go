@@ -0,0 +1,249 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Kinds of inventory diff lines
+const (
+	DiffAdded   = "+"
+	DiffRemoved = "-"
+	DiffChanged = "~"
+)
+
+// secretFields are never shown in diffs, only reported as changed
+var secretFields = map[string]bool{"password": true}
+
+// FieldChange is a single changed server field
+type FieldChange struct {
+	Field string
+	Old   string
+	New   string
+}
+
+// ServerChange lists the changed fields of a server present in both inventories
+type ServerChange struct {
+	Name   string
+	Fields []FieldChange
+}
+
+// MembershipChange lists servers added to or removed from a profile
+type MembershipChange struct {
+	Profile string
+	Added   []string
+	Removed []string
+}
+
+// InventoryDiff describes how one inventory differs from another
+type InventoryDiff struct {
+	AddedServers      []string
+	RemovedServers    []string
+	ChangedServers    []ServerChange
+	AddedProfiles     []string
+	RemovedProfiles   []string
+	MembershipChanges []MembershipChange
+}
+
+// DiffLine is one rendered line of an inventory diff
+type DiffLine struct {
+	Kind string // DiffAdded, DiffRemoved or DiffChanged
+	Text string
+}
+
+// ReadInventory reads servers and profiles from a config or export file in YAML or JSON.
+// Unlike LoadFromPath it never creates the file.
+func ReadInventory(path string) (*Config, error) {
+	expanded, err := ExpandPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	data, _, err := readConfigData(expanded)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var cfg Config
+	if strings.EqualFold(filepath.Ext(expanded), ".json") {
+		err = json.Unmarshal(data, &cfg)
+	} else {
+		err = yaml.Unmarshal(data, &cfg)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return &cfg, nil
+}
+
+// DiffInventories compares two inventories, reporting what changed from old to new
+func DiffInventories(old, new *Config) InventoryDiff {
+	var diff InventoryDiff
+
+	oldServers := make(map[string]Server)
+	for _, server := range old.Servers {
+		oldServers[server.Name] = server
+	}
+	newServers := make(map[string]Server)
+	for _, server := range new.Servers {
+		newServers[server.Name] = server
+	}
+
+	for _, name := range sortedKeys(newServers) {
+		oldServer, exists := oldServers[name]
+		if !exists {
+			diff.AddedServers = append(diff.AddedServers, name)
+			continue
+		}
+		if fields := diffServerFields(oldServer, newServers[name]); len(fields) > 0 {
+			diff.ChangedServers = append(diff.ChangedServers, ServerChange{Name: name, Fields: fields})
+		}
+	}
+	for _, name := range sortedKeys(oldServers) {
+		if _, exists := newServers[name]; !exists {
+			diff.RemovedServers = append(diff.RemovedServers, name)
+		}
+	}
+
+	oldProfiles := make(map[string]Profile)
+	for _, profile := range old.Profiles {
+		oldProfiles[profile.Name] = profile
+	}
+	newProfiles := make(map[string]Profile)
+	for _, profile := range new.Profiles {
+		newProfiles[profile.Name] = profile
+	}
+
+	for _, name := range sortedKeys(newProfiles) {
+		oldProfile, exists := oldProfiles[name]
+		if !exists {
+			diff.AddedProfiles = append(diff.AddedProfiles, name)
+			oldProfile = Profile{Name: name}
+		}
+		added, removed := diffMembers(oldProfile.Servers, newProfiles[name].Servers)
+		if len(added) > 0 || len(removed) > 0 {
+			diff.MembershipChanges = append(diff.MembershipChanges, MembershipChange{Profile: name, Added: added, Removed: removed})
+		}
+	}
+	for _, name := range sortedKeys(oldProfiles) {
+		if _, exists := newProfiles[name]; !exists {
+			diff.RemovedProfiles = append(diff.RemovedProfiles, name)
+		}
+	}
+
+	return diff
+}
+
+// IsEmpty reports whether the inventories are identical
+func (d InventoryDiff) IsEmpty() bool {
+	return len(d.AddedServers) == 0 && len(d.RemovedServers) == 0 && len(d.ChangedServers) == 0 &&
+		len(d.AddedProfiles) == 0 && len(d.RemovedProfiles) == 0 && len(d.MembershipChanges) == 0
+}
+
+// Lines renders the diff as human readable lines
+func (d InventoryDiff) Lines() []DiffLine {
+	var lines []DiffLine
+	for _, name := range d.AddedServers {
+		lines = append(lines, DiffLine{DiffAdded, fmt.Sprintf("server %s", name)})
+	}
+	for _, name := range d.RemovedServers {
+		lines = append(lines, DiffLine{DiffRemoved, fmt.Sprintf("server %s", name)})
+	}
+	for _, change := range d.ChangedServers {
+		for _, field := range change.Fields {
+			lines = append(lines, DiffLine{DiffChanged, fmt.Sprintf("server %s: %s %s → %s", change.Name, field.Field, field.Old, field.New)})
+		}
+	}
+	for _, name := range d.AddedProfiles {
+		lines = append(lines, DiffLine{DiffAdded, fmt.Sprintf("profile %s", name)})
+	}
+	for _, name := range d.RemovedProfiles {
+		lines = append(lines, DiffLine{DiffRemoved, fmt.Sprintf("profile %s", name)})
+	}
+	for _, change := range d.MembershipChanges {
+		for _, name := range change.Added {
+			lines = append(lines, DiffLine{DiffAdded, fmt.Sprintf("profile %s: member %s", change.Profile, name)})
+		}
+		for _, name := range change.Removed {
+			lines = append(lines, DiffLine{DiffRemoved, fmt.Sprintf("profile %s: member %s", change.Profile, name)})
+		}
+	}
+	return lines
+}
+
+// diffServerFields compares servers field by field using their YAML field names
+func diffServerFields(old, new Server) []FieldChange {
+	var changes []FieldChange
+
+	oldValue := reflect.ValueOf(old)
+	newValue := reflect.ValueOf(new)
+	serverType := oldValue.Type()
+	for i := 0; i < serverType.NumField(); i++ {
+		field := serverType.Field(i)
+		name := strings.Split(field.Tag.Get("yaml"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+
+		before := formatDiffValue(oldValue.Field(i).Interface())
+		after := formatDiffValue(newValue.Field(i).Interface())
+		if before == after {
+			continue
+		}
+		if secretFields[name] {
+			before, after = "(hidden)", "(changed)"
+		}
+		changes = append(changes, FieldChange{Field: name, Old: before, New: after})
+	}
+	return changes
+}
+
+// formatDiffValue renders a field value compactly; unset values render as "-"
+func formatDiffValue(value interface{}) string {
+	v := reflect.ValueOf(value)
+	if v.IsZero() || ((v.Kind() == reflect.Slice || v.Kind() == reflect.Map) && v.Len() == 0) {
+		return "-"
+	}
+	switch v.Kind() {
+	case reflect.String, reflect.Int, reflect.Bool, reflect.Float64:
+		return fmt.Sprint(value)
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+// diffMembers returns the names present only in new and only in old
+func diffMembers(old, new []string) (added, removed []string) {
+	for _, name := range new {
+		if !contains(old, name) {
+			added = append(added, name)
+		}
+	}
+	for _, name := range old {
+		if !contains(new, name) {
+			removed = append(removed, name)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	return added, removed
+}
+
+// sortedKeys returns the keys of a map in sorted order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,93 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDiffInventories(t *testing.T) {
+	old := &Config{
+		Servers: []Server{
+			{Name: "web1", Hostname: "10.0.0.1", Port: 22, Username: "deploy", Password: "old"},
+			{Name: "legacy", Hostname: "10.0.0.9", Port: 22, Username: "root"},
+		},
+		Profiles: []Profile{
+			{Name: "prod", Servers: []string{"web1", "legacy"}},
+			{Name: "old", Servers: []string{}},
+		},
+	}
+	new := &Config{
+		Servers: []Server{
+			{Name: "web1", Hostname: "10.0.0.2", Port: 22, Username: "deploy", Password: "new", Tags: []string{"web"}},
+			{Name: "web2", Hostname: "10.0.0.3", Port: 22, Username: "deploy"},
+		},
+		Profiles: []Profile{
+			{Name: "prod", Servers: []string{"web1", "web2"}},
+			{Name: "staging", Servers: []string{"web2"}},
+		},
+	}
+
+	diff := DiffInventories(old, new)
+
+	if len(diff.AddedServers) != 1 || diff.AddedServers[0] != "web2" {
+		t.Errorf("Expected web2 added, got %v", diff.AddedServers)
+	}
+	if len(diff.RemovedServers) != 1 || diff.RemovedServers[0] != "legacy" {
+		t.Errorf("Expected legacy removed, got %v", diff.RemovedServers)
+	}
+	if len(diff.ChangedServers) != 1 {
+		t.Fatalf("Expected one changed server, got %v", diff.ChangedServers)
+	}
+
+	fields := make(map[string]FieldChange)
+	for _, field := range diff.ChangedServers[0].Fields {
+		fields[field.Field] = field
+	}
+	if change := fields["hostname"]; change.Old != "10.0.0.1" || change.New != "10.0.0.2" {
+		t.Errorf("Unexpected hostname change: %+v", change)
+	}
+	if change := fields["tags"]; change.Old != "-" || change.New != `["web"]` {
+		t.Errorf("Unexpected tags change: %+v", change)
+	}
+	if change := fields["password"]; change.Old == "old" || change.New == "new" {
+		t.Error("Passwords must not be shown in diffs")
+	}
+
+	if len(diff.AddedProfiles) != 1 || diff.AddedProfiles[0] != "staging" {
+		t.Errorf("Expected staging added, got %v", diff.AddedProfiles)
+	}
+	if len(diff.RemovedProfiles) != 1 || diff.RemovedProfiles[0] != "old" {
+		t.Errorf("Expected old removed, got %v", diff.RemovedProfiles)
+	}
+	if len(diff.MembershipChanges) != 2 {
+		t.Errorf("Expected membership changes for prod and staging, got %+v", diff.MembershipChanges)
+	}
+
+	if diff.IsEmpty() || len(diff.Lines()) == 0 {
+		t.Error("Expected a non-empty diff")
+	}
+	if !DiffInventories(old, old).IsEmpty() {
+		t.Error("Expected identical inventories to produce an empty diff")
+	}
+}
+
+func TestReadInventory(t *testing.T) {
+	dir := t.TempDir()
+	jsonPath := filepath.Join(dir, "export.json")
+	if err := os.WriteFile(jsonPath, []byte(`{"servers":[{"name":"web1","hostname":"h","port":22,"username":"u","auth_type":"key"}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := ReadInventory(jsonPath)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(cfg.Servers) != 1 || cfg.Servers[0].Name != "web1" {
+		t.Errorf("Unexpected servers: %+v", cfg.Servers)
+	}
+
+	if _, err := ReadInventory(filepath.Join(dir, "missing.yaml")); err == nil {
+		t.Error("Expected error for missing file")
+	}
+}
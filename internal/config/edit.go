@@ -0,0 +1,201 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Path returns the path of the main configuration file
+func (c *Config) Path() string {
+	return c.configPath
+}
+
+// ParseServerYAML parses a single server edited as YAML. Unknown keys are
+// rejected so a mistyped field is reported instead of silently dropped.
+func ParseServerYAML(data []byte) (Server, error) {
+	var server Server
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&server); err != nil {
+		if errors.Is(err, io.EOF) {
+			return server, fmt.Errorf("no server found")
+		}
+		return server, fmt.Errorf("invalid YAML: %w", err)
+	}
+	if server.Port == 0 {
+		server.Port = 22
+	}
+	if err := server.Validate(); err != nil {
+		return server, err
+	}
+	return server, nil
+}
+
+// ReplaceServer replaces the server named oldName, which may be renamed, and
+// updates profile memberships and jump host references to the new name
+func (c *Config) ReplaceServer(oldName string, server Server) error {
+	if err := server.Validate(); err != nil {
+		return fmt.Errorf("invalid server configuration: %w", err)
+	}
+	if _, err := c.JumpChain(server); err != nil {
+		return fmt.Errorf("invalid server configuration: %w", err)
+	}
+
+	index := -1
+	for i := range c.Servers {
+		switch c.Servers[i].Name {
+		case oldName:
+			index = i
+		case server.Name:
+			return fmt.Errorf("server with name '%s' already exists", server.Name)
+		}
+	}
+	if index < 0 {
+		return fmt.Errorf("server '%s' not found", oldName)
+	}
+
+	// Alias checks must not see the server's own previous entry
+	previous := c.Servers[index]
+	c.Servers[index] = Server{Name: server.Name}
+	if err := c.checkAliasConflicts(server); err != nil {
+		c.Servers[index] = previous
+		return err
+	}
+	c.Servers[index] = server
+
+	if server.Name != oldName {
+		// Lists are copied rather than changed in place, as in ApplyBatchRename
+		rename := func(list []string) []string {
+			if !contains(list, oldName) {
+				return list
+			}
+			names := make([]string, len(list))
+			for i, name := range list {
+				names[i] = name
+				if name == oldName {
+					names[i] = server.Name
+				}
+			}
+			return names
+		}
+		for i := range c.Profiles {
+			c.Profiles[i].Servers = rename(c.Profiles[i].Servers)
+		}
+		for i := range c.Servers {
+			c.Servers[i].ProxyJump = rename(c.Servers[i].ProxyJump)
+		}
+		if source, ok := c.serverSources[oldName]; ok {
+			delete(c.serverSources, oldName)
+			c.setServerSource(server.Name, source)
+		}
+	}
+	c.resolveProxyJumps()
+	return nil
+}
+
+// ReplaceProfile replaces the profile named oldName, which may be renamed
+func (c *Config) ReplaceProfile(oldName string, profile Profile) error {
+	if err := profile.Validate(); err != nil {
+		return fmt.Errorf("invalid profile configuration: %w", err)
+	}
+
+	index := -1
+	for i := range c.Profiles {
+		switch c.Profiles[i].Name {
+		case oldName:
+			index = i
+		case profile.Name:
+			return fmt.Errorf("profile with name '%s' already exists", profile.Name)
+		}
+	}
+	if index < 0 {
+		return fmt.Errorf("profile '%s' not found", oldName)
+	}
+	if profile.Servers == nil {
+		profile.Servers = []string{}
+	}
+	c.Profiles[index] = profile
+
+	// Renamed profiles stay in the conf.d file they were loaded from
+	if source, ok := c.profileSources[oldName]; ok && profile.Name != oldName {
+		delete(c.profileSources, oldName)
+		c.setProfileSource(profile.Name, source)
+	}
+	return nil
+}
+
+// ReplaceConfigFile validates edited contents of the main configuration file and
+// writes them to configPath. The contents are loaded exactly like the real file,
+// including conf.d and includes, so nothing invalid is ever written. An
+// encrypted file stays encrypted with the same key, and a locked file is never
+// replaced.
+func ReplaceConfigFile(configPath string, data []byte) error {
+	current, key, err := readConfigData(configPath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	var state struct {
+		Locked bool `yaml:"locked"`
+	}
+	if yaml.Unmarshal(current, &state) == nil && state.Locked {
+		return ErrLocked
+	}
+
+	// Seal before writing anything so the edit never reaches the disk in plaintext
+	contents := data
+	if key != nil {
+		if contents, err = seal(data, key); err != nil {
+			return err
+		}
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(configPath), ".config-edit-*.yaml")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath)
+
+	if _, err := tmp.Write(contents); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write temporary file: %w", err)
+	}
+	if err := tmp.Chmod(0600); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write temporary file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write temporary file: %w", err)
+	}
+
+	cfg, err := LoadFromPath(tmpPath)
+	if err != nil {
+		return err
+	}
+	seen := make(map[string]bool)
+	for _, server := range cfg.Servers {
+		if err := server.Validate(); err != nil {
+			return fmt.Errorf("server '%s': %w", server.Name, err)
+		}
+		if seen[server.Name] {
+			return fmt.Errorf("server '%s' is defined more than once", server.Name)
+		}
+		seen[server.Name] = true
+	}
+	for _, profile := range cfg.Profiles {
+		if err := profile.Validate(); err != nil {
+			return fmt.Errorf("profile '%s': %w", profile.Name, err)
+		}
+	}
+
+	if err := os.Rename(tmpPath, configPath); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	return nil
+}
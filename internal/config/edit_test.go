@@ -0,0 +1,121 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseServerYAML(t *testing.T) {
+	server, err := ParseServerYAML([]byte("# comment\nname: web\nhostname: web.example.com\nusername: ops\nauth_type: key\nkey_path: ~/.ssh/id_ed25519\n"))
+	if err != nil {
+		t.Fatalf("ParseServerYAML failed: %v", err)
+	}
+	if server.Port != 22 {
+		t.Errorf("Expected default port 22, got %d", server.Port)
+	}
+
+	if _, err := ParseServerYAML([]byte("name: web\nhostnme: typo.example.com\n")); err == nil {
+		t.Error("Expected error for unknown field")
+	}
+	if _, err := ParseServerYAML([]byte("name: web\n")); err == nil {
+		t.Error("Expected validation error")
+	}
+}
+
+func TestReplaceServerRename(t *testing.T) {
+	cfg := &Config{
+		Servers: []Server{
+			{Name: "web", Hostname: "web.example.com", Port: 22, Username: "ops", AuthType: "key", KeyPath: "~/.ssh/id"},
+			{Name: "db", Hostname: "db.example.com", Port: 22, Username: "ops", AuthType: "key", KeyPath: "~/.ssh/id", Aliases: []string{"database"}},
+		},
+		Profiles: []Profile{{Name: "prod", Servers: []string{"web", "db"}}},
+	}
+
+	renamed := cfg.Servers[0]
+	renamed.Name = "web-01"
+	renamed.Aliases = []string{"web"}
+	if err := cfg.ReplaceServer("web", renamed); err != nil {
+		t.Fatalf("ReplaceServer failed: %v", err)
+	}
+	if cfg.Servers[0].Name != "web-01" || cfg.Profiles[0].Servers[0] != "web-01" {
+		t.Errorf("Expected rename to carry over to profiles: %+v", cfg.Profiles[0])
+	}
+
+	clash := cfg.Servers[0]
+	clash.Name = "db"
+	if err := cfg.ReplaceServer("web-01", clash); err == nil {
+		t.Error("Expected error renaming onto an existing server")
+	}
+	aliased := cfg.Servers[0]
+	aliased.Aliases = []string{"database"}
+	if err := cfg.ReplaceServer("web-01", aliased); err == nil {
+		t.Error("Expected alias conflict")
+	}
+	if len(cfg.Servers[0].Aliases) != 1 || cfg.Servers[0].Aliases[0] != "web" {
+		t.Errorf("Failed replace must leave the server unchanged, got %+v", cfg.Servers[0])
+	}
+}
+
+func TestReplaceConfigFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	original := "servers: []\n"
+	if err := os.WriteFile(path, []byte(original), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	invalid := "servers:\n  - name: web\n"
+	if err := ReplaceConfigFile(path, []byte(invalid)); err == nil {
+		t.Error("Expected validation error")
+	}
+	if data, _ := os.ReadFile(path); string(data) != original {
+		t.Error("Invalid edit must not be written")
+	}
+
+	valid := "servers:\n  - name: web\n    hostname: web.example.com\n    port: 22\n    username: ops\n    auth_type: key\n    key_path: ~/.ssh/id\n"
+	if err := ReplaceConfigFile(path, []byte(valid)); err != nil {
+		t.Fatalf("ReplaceConfigFile failed: %v", err)
+	}
+	if data, _ := os.ReadFile(path); !strings.Contains(string(data), "web.example.com") {
+		t.Error("Expected edited config to be written")
+	}
+	entries, _ := os.ReadDir(dir)
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".config-edit-") {
+			t.Errorf("Temporary file left behind: %s", entry.Name())
+		}
+	}
+}
+
+func TestLockedConfigRefusesEdits(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	original := "locked: true\nservers: []\n"
+	if err := os.WriteFile(path, []byte(original), 0400); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ReplaceConfigFile(path, []byte("servers: []\n")); !errors.Is(err, ErrLocked) {
+		t.Errorf("Expected ErrLocked from ReplaceConfigFile, got %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != original {
+		t.Error("A locked config must not be replaced")
+	}
+
+	cfg, err := LoadFromPath(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.SetPassphrase("correct horse battery"); !errors.Is(err, ErrLocked) {
+		t.Errorf("Expected ErrLocked from SetPassphrase, got %v", err)
+	}
+	cfg.Profiles = []Profile{{Name: "prod"}}
+	if _, err := cfg.SplitByProfile(); !errors.Is(err, ErrLocked) {
+		t.Errorf("Expected ErrLocked from SplitByProfile, got %v", err)
+	}
+	if cfg.IsSplit() {
+		t.Error("A locked config must not be split")
+	}
+}
@@ -0,0 +1,309 @@
+package config
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"golang.org/x/crypto/scrypt"
+	"gopkg.in/yaml.v3"
+)
+
+// EncryptionVersion is the envelope version written by encrypted configurations
+const EncryptionVersion = 1
+
+// Key derivation parameters of new passphrases; the ones used are stored in
+// the file so they can be raised later
+const (
+	scryptN       = 1 << 15
+	scryptR       = 8
+	scryptP       = 1
+	keyLength     = 32 // AES-256
+	saltLength    = 16
+	minPassphrase = 8
+)
+
+var (
+	// ErrPassphraseRequired is returned when loading an encrypted configuration
+	// without a passphrase
+	ErrPassphraseRequired = errors.New("the configuration is encrypted; a passphrase is required to unlock it")
+	// ErrWrongPassphrase is returned when a passphrase doesn't decrypt the configuration
+	ErrWrongPassphrase = errors.New("wrong passphrase for the encrypted configuration")
+)
+
+// PassphraseFunc returns the passphrase of the encrypted configuration at
+// path, or "" when none is available
+type PassphraseFunc func(path string) (string, error)
+
+var passphraseFunc PassphraseFunc
+
+// SetPassphraseFunc sets where the passphrase comes from when an encrypted
+// configuration is loaded and its key isn't unlocked yet
+func SetPassphraseFunc(fn PassphraseFunc) {
+	passphraseFunc = fn
+}
+
+// encryptedFile is the envelope written instead of the configuration when it
+// is encrypted: the YAML encrypted with AES-256-GCM under a key derived from
+// the passphrase with scrypt
+type encryptedFile struct {
+	Version    int    `yaml:"sshm_encrypted"`
+	KDF        string `yaml:"kdf"`
+	N          int    `yaml:"n"`
+	R          int    `yaml:"r"`
+	P          int    `yaml:"p"`
+	Salt       string `yaml:"salt"`
+	Nonce      string `yaml:"nonce"`
+	Ciphertext string `yaml:"ciphertext"`
+}
+
+// encryptionKey is the key of an encrypted configuration with what it was derived with
+type encryptionKey struct {
+	salt    []byte
+	n, r, p int
+	key     []byte
+}
+
+// unlockedKeys holds the keys unlocked in this process by salt, so the
+// passphrase is asked for once however often the configuration is loaded
+var unlockedKeys = struct {
+	sync.Mutex
+	keys map[string]*encryptionKey
+}{keys: make(map[string]*encryptionKey)}
+
+func rememberKey(key *encryptionKey) {
+	unlockedKeys.Lock()
+	defer unlockedKeys.Unlock()
+	unlockedKeys.keys[string(key.salt)] = key
+}
+
+func unlockedKey(salt []byte) *encryptionKey {
+	unlockedKeys.Lock()
+	defer unlockedKeys.Unlock()
+	return unlockedKeys.keys[string(salt)]
+}
+
+// deriveKey derives the key of passphrase with scrypt
+func deriveKey(passphrase string, salt []byte, n, r, p int) (*encryptionKey, error) {
+	key, err := scrypt.Key([]byte(passphrase), salt, n, r, p, keyLength)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive encryption key: %w", err)
+	}
+	return &encryptionKey{salt: salt, n: n, r: r, p: p, key: key}, nil
+}
+
+// parseEncrypted returns the envelope of an encrypted configuration, or nil
+// when data is a plain configuration
+func parseEncrypted(data []byte) (*encryptedFile, error) {
+	var envelope encryptedFile
+	if err := yaml.Unmarshal(data, &envelope); err != nil || envelope.Version == 0 {
+		return nil, nil
+	}
+	if envelope.Version != EncryptionVersion || envelope.KDF != "scrypt" {
+		return nil, fmt.Errorf("unsupported encrypted configuration (version %d, kdf %q)", envelope.Version, envelope.KDF)
+	}
+	return &envelope, nil
+}
+
+func (e *encryptedFile) salt() ([]byte, error) {
+	salt, err := base64.StdEncoding.DecodeString(e.Salt)
+	if err != nil || len(salt) == 0 {
+		return nil, fmt.Errorf("invalid salt in encrypted configuration")
+	}
+	return salt, nil
+}
+
+// open decrypts the envelope with key
+func (e *encryptedFile) open(key *encryptionKey) ([]byte, error) {
+	nonce, err := base64.StdEncoding.DecodeString(e.Nonce)
+	if err != nil {
+		return nil, fmt.Errorf("invalid nonce in encrypted configuration")
+	}
+	ciphertext, err := base64.StdEncoding.DecodeString(e.Ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ciphertext in encrypted configuration")
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(nonce) != aead.NonceSize() {
+		return nil, fmt.Errorf("invalid nonce in encrypted configuration")
+	}
+	plaintext, err := aead.Open(nil, nonce, ciphertext, envelopeAAD())
+	if err != nil {
+		return nil, ErrWrongPassphrase
+	}
+	return plaintext, nil
+}
+
+// unlock decrypts the envelope with a key unlocked before or one derived from
+// passphrase, or asks the passphrase function for one when passphrase is empty
+func (e *encryptedFile) unlock(path, passphrase string) ([]byte, *encryptionKey, error) {
+	salt, err := e.salt()
+	if err != nil {
+		return nil, nil, err
+	}
+	if passphrase == "" {
+		if key := unlockedKey(salt); key != nil {
+			plaintext, err := e.open(key)
+			return plaintext, key, err
+		}
+		if passphraseFunc != nil {
+			if passphrase, err = passphraseFunc(path); err != nil {
+				return nil, nil, err
+			}
+		}
+		if passphrase == "" {
+			return nil, nil, ErrPassphraseRequired
+		}
+	}
+
+	key, err := deriveKey(passphrase, salt, e.N, e.R, e.P)
+	if err != nil {
+		return nil, nil, err
+	}
+	plaintext, err := e.open(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	rememberKey(key)
+	return plaintext, key, nil
+}
+
+// seal encrypts a configuration into an envelope with key
+func seal(data []byte, key *encryptionKey) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	envelope := encryptedFile{
+		Version:    EncryptionVersion,
+		KDF:        "scrypt",
+		N:          key.n,
+		R:          key.r,
+		P:          key.p,
+		Salt:       base64.StdEncoding.EncodeToString(key.salt),
+		Nonce:      base64.StdEncoding.EncodeToString(nonce),
+		Ciphertext: base64.StdEncoding.EncodeToString(aead.Seal(nil, nonce, data, envelopeAAD())),
+	}
+	out, err := yaml.Marshal(envelope)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal encrypted configuration: %w", err)
+	}
+	header := "# Encrypted by sshm. Decrypt with 'sshm config decrypt'.\n"
+	return append([]byte(header), out...), nil
+}
+
+func newAEAD(key *encryptionKey) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key.key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// envelopeAAD binds the ciphertext to the envelope version
+func envelopeAAD() []byte {
+	return []byte(fmt.Sprintf("sshm-config-v%d", EncryptionVersion))
+}
+
+// readConfigData reads a configuration file, decrypting it when it is
+// encrypted. The key is returned for encrypted files, nil otherwise.
+func readConfigData(path string) ([]byte, *encryptionKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	envelope, err := parseEncrypted(data)
+	if err != nil || envelope == nil {
+		return data, nil, err
+	}
+	return envelope.unlock(path, "")
+}
+
+// ReadConfigFile returns the contents of a configuration file, decrypted when
+// it is encrypted
+func ReadConfigFile(path string) ([]byte, error) {
+	data, _, err := readConfigData(path)
+	return data, err
+}
+
+// IsEncrypted reports whether the configuration file at path is encrypted
+func IsEncrypted(path string) (bool, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	envelope, err := parseEncrypted(data)
+	return envelope != nil, err
+}
+
+// Unlock checks passphrase against the encrypted configuration at path and
+// keeps its key for this process, so later loads don't ask again
+func Unlock(path, passphrase string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+	envelope, err := parseEncrypted(data)
+	if err != nil {
+		return err
+	}
+	if envelope == nil {
+		return nil
+	}
+	if passphrase == "" {
+		return ErrPassphraseRequired
+	}
+	_, _, err = envelope.unlock(path, passphrase)
+	return err
+}
+
+// Encrypted reports whether the configuration is saved encrypted
+func (c *Config) Encrypted() bool {
+	return c.encryption != nil
+}
+
+// SetPassphrase makes Save encrypt the configuration with passphrase, or write
+// it in plaintext again when passphrase is empty
+func (c *Config) SetPassphrase(passphrase string) error {
+	if c.Locked {
+		return ErrLocked
+	}
+	if passphrase == "" {
+		c.encryption = nil
+		return nil
+	}
+	if len(passphrase) < minPassphrase {
+		return fmt.Errorf("the passphrase must be at least %d characters", minPassphrase)
+	}
+	// conf.d files would stay readable next to the encrypted config.yaml
+	if c.IsSplit() {
+		return fmt.Errorf("the configuration is split into conf.d files, which can't be encrypted")
+	}
+
+	salt := make([]byte, saltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return fmt.Errorf("failed to generate salt: %w", err)
+	}
+	key, err := deriveKey(passphrase, salt, scryptN, scryptR, scryptP)
+	if err != nil {
+		return err
+	}
+	rememberKey(key)
+	c.encryption = key
+	return nil
+}
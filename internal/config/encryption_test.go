@@ -0,0 +1,163 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// forgetKeys clears the keys unlocked by earlier tests
+func forgetKeys() {
+	unlockedKeys.Lock()
+	defer unlockedKeys.Unlock()
+	unlockedKeys.keys = make(map[string]*encryptionKey)
+}
+
+func TestEncryptedConfigRoundTrip(t *testing.T) {
+	forgetKeys()
+	defer forgetKeys()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+
+	cfg, err := LoadFromPath(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.AddServer(Server{Name: "db1", Hostname: "db1.internal", Port: 22, Username: "admin", AuthType: "key", KeyPath: "~/.ssh/db"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.SetPassphrase("short"); err == nil {
+		t.Error("Expected a short passphrase to be rejected")
+	}
+	if err := cfg.SetPassphrase("correct horse battery"); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"db1.internal", "admin", "~/.ssh/db"} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("Expected %q not to be stored in plaintext", secret)
+		}
+	}
+	if encrypted, err := IsEncrypted(path); err != nil || !encrypted {
+		t.Errorf("Expected the file to be encrypted, got %v, %v", encrypted, err)
+	}
+
+	// A new process has no key and no passphrase
+	forgetKeys()
+	if _, err := LoadFromPath(path); !errors.Is(err, ErrPassphraseRequired) {
+		t.Fatalf("Expected a passphrase to be required, got %v", err)
+	}
+	if err := Unlock(path, "wrong passphrase"); !errors.Is(err, ErrWrongPassphrase) {
+		t.Errorf("Expected a wrong passphrase to be rejected, got %v", err)
+	}
+	if err := Unlock(path, "correct horse battery"); err != nil {
+		t.Fatalf("Unlock failed: %v", err)
+	}
+
+	loaded, err := LoadFromPath(path)
+	if err != nil {
+		t.Fatalf("Expected the unlocked configuration to load, got %v", err)
+	}
+	if !loaded.Encrypted() {
+		t.Error("Expected the loaded configuration to stay encrypted")
+	}
+	if server, err := loaded.GetServer("db1"); err != nil || server.Hostname != "db1.internal" {
+		t.Errorf("Expected db1 to be decrypted, got %v, %v", server, err)
+	}
+
+	// Saving again re-encrypts; clearing the passphrase writes plaintext
+	if err := loaded.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if encrypted, _ := IsEncrypted(path); !encrypted {
+		t.Error("Expected the configuration to be saved encrypted again")
+	}
+	loaded.SetPassphrase("")
+	if err := loaded.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(path); !strings.Contains(string(data), "db1.internal") {
+		t.Error("Expected the decrypted configuration in plaintext")
+	}
+}
+
+func TestPassphraseFunc(t *testing.T) {
+	forgetKeys()
+	defer forgetKeys()
+	defer SetPassphraseFunc(nil)
+	path := filepath.Join(t.TempDir(), "config.yaml")
+
+	cfg, err := LoadFromPath(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.SetPassphrase("correct horse battery")
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+	forgetKeys()
+
+	asked := 0
+	SetPassphraseFunc(func(string) (string, error) {
+		asked++
+		return "correct horse battery", nil
+	})
+	for i := 0; i < 2; i++ {
+		if _, err := LoadFromPath(path); err != nil {
+			t.Fatalf("Load %d failed: %v", i, err)
+		}
+	}
+	if asked != 1 {
+		t.Errorf("Expected the passphrase to be asked for once, got %d", asked)
+	}
+}
+
+func TestReplaceConfigFileKeepsEncryption(t *testing.T) {
+	forgetKeys()
+	defer forgetKeys()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+
+	cfg, err := LoadFromPath(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.SetPassphrase("correct horse battery")
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	edited := "servers:\n  - name: web1\n    hostname: web1.internal\n    port: 22\n    username: deploy\n    auth_type: key\n    key_path: ~/.ssh/id_ed25519\n"
+	if err := ReplaceConfigFile(path, []byte(edited)); err != nil {
+		t.Fatalf("ReplaceConfigFile failed: %v", err)
+	}
+	if encrypted, _ := IsEncrypted(path); !encrypted {
+		t.Fatal("Expected the edited configuration to stay encrypted")
+	}
+	data, err := ReadConfigFile(path)
+	if err != nil || !strings.Contains(string(data), "web1.internal") {
+		t.Errorf("Expected ReadConfigFile to decrypt the edit, got %q, %v", data, err)
+	}
+}
+
+func TestEncryptionRefusesSplitLayout(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	cfg, err := LoadFromPath(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.AddProfile(Profile{Name: "prod"})
+	if _, err := cfg.SplitByProfile(); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.SetPassphrase("correct horse battery"); err == nil {
+		t.Error("Expected a split configuration to refuse encryption")
+	}
+}
@@ -0,0 +1,129 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ExpiringSoonWindow is how long before its expiry a server is flagged as expiring soon
+const ExpiringSoonWindow = 24 * time.Hour
+
+// ArchivedServer is an expired scratch server or one removed with --archive, kept
+// out of the inventory and status checks but available for 'sshm archive restore'
+type ArchivedServer struct {
+	Server    `yaml:",inline"`
+	ExpiredAt time.Time `yaml:"expired_at" json:"expired_at"`                 // When it expired or was removed
+	Profiles  []string  `yaml:"profiles,omitempty" json:"profiles,omitempty"` // Profiles the server was removed from
+	Removed   bool      `yaml:"removed,omitempty" json:"removed,omitempty"`   // Archived by 'sshm remove --archive' rather than expiry
+}
+
+// IsExpired reports whether the server's expiry time has passed
+func (s *Server) IsExpired(now time.Time) bool {
+	return s.ExpiresAt != nil && !now.Before(*s.ExpiresAt)
+}
+
+// IsExpiringSoon reports whether the server expires within ExpiringSoonWindow
+func (s *Server) IsExpiringSoon(now time.Time) bool {
+	return s.ExpiresAt != nil && !s.IsExpired(now) && s.ExpiresAt.Sub(now) <= ExpiringSoonWindow
+}
+
+// ParseExpiry parses an expiry given as a duration from now ("4h", "3d"), a date
+// ("2026-01-31"), a date and time ("2026-01-31 18:00") or RFC 3339. "never" returns nil.
+func ParseExpiry(value string, now time.Time) (*time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || strings.EqualFold(value, "never") {
+		return nil, nil
+	}
+
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n > 0 {
+			expiry := now.Add(time.Duration(n) * 24 * time.Hour)
+			return &expiry, nil
+		}
+	}
+	if duration, err := time.ParseDuration(value); err == nil {
+		if duration <= 0 {
+			return nil, fmt.Errorf("expiry duration must be positive")
+		}
+		expiry := now.Add(duration)
+		return &expiry, nil
+	}
+	if expiry, err := time.Parse(time.RFC3339, value); err == nil {
+		return &expiry, nil
+	}
+	for _, layout := range []string{"2006-01-02 15:04", "2006-01-02"} {
+		if expiry, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return &expiry, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid expiry '%s' (use a duration like 4h or 3d, a date, or 'never')", value)
+}
+
+// ArchiveExpired moves expired servers out of the inventory and their profiles
+// into the archive and returns their names
+func (c *Config) ArchiveExpired(now time.Time) []string {
+	var archived []string
+	kept := c.Servers[:0]
+	for _, server := range c.Servers {
+		if !server.IsExpired(now) {
+			kept = append(kept, server)
+			continue
+		}
+
+		entry := ArchivedServer{Server: server, ExpiredAt: *server.ExpiresAt}
+		for i := range c.Profiles {
+			if contains(c.Profiles[i].Servers, server.Name) {
+				entry.Profiles = append(entry.Profiles, c.Profiles[i].Name)
+				c.Profiles[i].Servers = removeString(c.Profiles[i].Servers, server.Name)
+			}
+		}
+		c.Archived = append(c.Archived, entry)
+		archived = append(archived, server.Name)
+	}
+	c.Servers = kept
+	return archived
+}
+
+// RestoreArchived moves an archived server back into the inventory and the profiles
+// it was removed from. The expiry is replaced with expiresAt (nil keeps it forever).
+func (c *Config) RestoreArchived(name string, expiresAt *time.Time) error {
+	for i, entry := range c.Archived {
+		if entry.Name != name {
+			continue
+		}
+
+		server := entry.Server
+		server.ExpiresAt = expiresAt
+		if err := c.AddServer(server); err != nil {
+			return err
+		}
+		for _, profileName := range entry.Profiles {
+			if profile, err := c.GetProfile(profileName); err == nil && !contains(profile.Servers, name) {
+				profile.Servers = append(profile.Servers, name)
+			}
+		}
+		c.Archived = append(c.Archived[:i], c.Archived[i+1:]...)
+		return nil
+	}
+	return fmt.Errorf("archived server '%s' not found", name)
+}
+
+// PurgeArchived deletes all archived servers and returns how many were removed
+func (c *Config) PurgeArchived() int {
+	count := len(c.Archived)
+	c.Archived = nil
+	return count
+}
+
+// removeString returns values without any occurrence of value
+func removeString(values []string, value string) []string {
+	var result []string
+	for _, v := range values {
+		if v != value {
+			result = append(result, v)
+		}
+	}
+	return result
+}
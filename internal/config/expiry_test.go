@@ -0,0 +1,122 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParseExpiry(t *testing.T) {
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"4h", 4 * time.Hour},
+		{"3d", 72 * time.Hour},
+		{"90m", 90 * time.Minute},
+	}
+	for _, tt := range tests {
+		got, err := ParseExpiry(tt.value, now)
+		if err != nil {
+			t.Fatalf("ParseExpiry(%q) failed: %v", tt.value, err)
+		}
+		if got.Sub(now) != tt.want {
+			t.Errorf("ParseExpiry(%q) = %v after now, want %v", tt.value, got.Sub(now), tt.want)
+		}
+	}
+
+	if got, err := ParseExpiry("never", now); err != nil || got != nil {
+		t.Errorf("Expected nil expiry for 'never', got %v, %v", got, err)
+	}
+	if got, err := ParseExpiry("2026-01-31", now); err != nil || got.Day() != 31 {
+		t.Errorf("Expected date expiry, got %v, %v", got, err)
+	}
+	for _, invalid := range []string{"soon", "-2h", "0d"} {
+		if _, err := ParseExpiry(invalid, now); err == nil {
+			t.Errorf("Expected error for %q", invalid)
+		}
+	}
+}
+
+func TestServerExpiryState(t *testing.T) {
+	now := time.Now()
+	soon := now.Add(2 * time.Hour)
+	later := now.Add(72 * time.Hour)
+	past := now.Add(-time.Minute)
+
+	if (&Server{}).IsExpiringSoon(now) || (&Server{}).IsExpired(now) {
+		t.Error("Servers without an expiry never expire")
+	}
+	if !(&Server{ExpiresAt: &soon}).IsExpiringSoon(now) {
+		t.Error("Expected server expiring in 2h to be expiring soon")
+	}
+	if (&Server{ExpiresAt: &later}).IsExpiringSoon(now) {
+		t.Error("Server expiring in 3 days should not be expiring soon")
+	}
+	expired := &Server{ExpiresAt: &past}
+	if !expired.IsExpired(now) || expired.IsExpiringSoon(now) {
+		t.Error("Expected past expiry to be expired and not expiring soon")
+	}
+}
+
+func TestArchiveAndRestoreExpired(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	cfg := &Config{
+		Servers: []Server{
+			{Name: "prod", Hostname: "prod.example.com", Port: 22, Username: "ops", AuthType: "key", KeyPath: "~/.ssh/id_ed25519"},
+			{Name: "scratch", Hostname: "10.0.0.9", Port: 22, Username: "ops", AuthType: "key", KeyPath: "~/.ssh/id_ed25519", ExpiresAt: &past},
+		},
+		Profiles: []Profile{{Name: "dev", Servers: []string{"prod", "scratch"}}},
+	}
+
+	archived := cfg.ArchiveExpired(time.Now())
+	if len(archived) != 1 || archived[0] != "scratch" {
+		t.Fatalf("Expected scratch to be archived, got %v", archived)
+	}
+	if len(cfg.Servers) != 1 || len(cfg.Profiles[0].Servers) != 1 {
+		t.Fatalf("Expected scratch removed from servers and profiles, got %+v", cfg)
+	}
+	if len(cfg.Archived) != 1 || cfg.Archived[0].Profiles[0] != "dev" {
+		t.Fatalf("Expected archive entry to remember its profile, got %+v", cfg.Archived)
+	}
+
+	if err := cfg.RestoreArchived("scratch", nil); err != nil {
+		t.Fatalf("RestoreArchived failed: %v", err)
+	}
+	server, err := cfg.GetServer("scratch")
+	if err != nil || server.ExpiresAt != nil {
+		t.Fatalf("Expected restored server without expiry, got %+v, %v", server, err)
+	}
+	if len(cfg.Profiles[0].Servers) != 2 || len(cfg.Archived) != 0 {
+		t.Errorf("Expected profile membership restored and archive emptied, got %+v", cfg)
+	}
+	if err := cfg.RestoreArchived("missing", nil); err == nil {
+		t.Error("Expected error restoring unknown server")
+	}
+}
+
+func TestLoadArchivesExpiredServers(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	data := `servers:
+  - name: scratch
+    hostname: 10.0.0.9
+    port: 22
+    username: ops
+    auth_type: agent
+    expires_at: 2020-01-01T00:00:00Z
+`
+	if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadFromPath(path)
+	if err != nil {
+		t.Fatalf("LoadFromPath failed: %v", err)
+	}
+	if len(cfg.Servers) != 0 || len(cfg.Archived) != 1 {
+		t.Errorf("Expected expired server archived on load, got servers=%v archived=%v", cfg.Servers, cfg.Archived)
+	}
+}
@@ -0,0 +1,24 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// HostKeyFingerprintPrefix starts every pinned host key fingerprint, as printed by ssh-keygen -l
+const HostKeyFingerprintPrefix = "SHA256:"
+
+// validateHostKeys checks pinned host key fingerprints
+func validateHostKeys(fingerprints []string) error {
+	for _, fingerprint := range fingerprints {
+		if !strings.HasPrefix(fingerprint, HostKeyFingerprintPrefix) || len(fingerprint) == len(HostKeyFingerprintPrefix) {
+			return fmt.Errorf("host key '%s' must be a SHA256 fingerprint such as SHA256:nThbg6kXUpJWGl7E1IGOCspRomTxdCARLviKw6E5SY8", fingerprint)
+		}
+	}
+	return nil
+}
+
+// PinsHostKey reports whether the fingerprint is pinned for the server
+func (s *Server) PinsHostKey(fingerprint string) bool {
+	return contains(s.HostKeys, fingerprint)
+}
@@ -0,0 +1,240 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ImportEntry is one server read from an import file
+type ImportEntry struct {
+	Index  int // Position in the file's server list, from 0
+	Server Server
+	Err    error // Decode or validation error; the entry must be skipped when set
+}
+
+// rawImportEntry is a decoded but not yet validated entry
+type rawImportEntry struct {
+	index  int
+	server Server
+	err    error
+}
+
+// StreamImport reads the servers of a YAML or JSON export one entry at a time and
+// validates them on parallel workers (0 uses one per CPU). onEntry is called for
+// every entry in file order from a single goroutine; a malformed entry is reported
+// through its Err instead of failing the whole import. The file's profiles are
+// returned once all entries were handled.
+func StreamImport(ctx context.Context, r io.Reader, format string, workers int, onEntry func(ImportEntry)) ([]Profile, error) {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	raw := make(chan rawImportEntry, workers*4)
+	results := make(chan ImportEntry, workers*4)
+
+	var profiles []Profile
+	decodeErr := make(chan error, 1)
+	go func() {
+		defer close(raw)
+		emit := func(index int, server Server, err error) bool {
+			select {
+			case raw <- rawImportEntry{index, server, err}:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+		var err error
+		switch format {
+		case "json":
+			profiles, err = streamJSONServers(r, emit)
+		case "yaml":
+			profiles, err = streamYAMLServers(r, emit)
+		default:
+			err = fmt.Errorf("unsupported format: %s", format)
+		}
+		if err == nil {
+			err = ctx.Err()
+		}
+		decodeErr <- err
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for entry := range raw {
+				if entry.err == nil {
+					entry.err = entry.server.Validate()
+				}
+				results <- ImportEntry{Index: entry.index, Server: entry.server, Err: entry.err}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	// Workers finish out of order; hold results back until their turn comes
+	pending := make(map[int]ImportEntry)
+	next := 0
+	for entry := range results {
+		pending[entry.Index] = entry
+		for {
+			ready, ok := pending[next]
+			if !ok {
+				break
+			}
+			delete(pending, next)
+			onEntry(ready)
+			next++
+		}
+	}
+
+	if err := <-decodeErr; err != nil {
+		return nil, err
+	}
+	return profiles, nil
+}
+
+// streamJSONServers decodes the servers array of a JSON export element by element
+func streamJSONServers(r io.Reader, emit func(int, Server, error) bool) ([]Profile, error) {
+	dec := json.NewDecoder(r)
+	if err := expectJSONDelim(dec, '{'); err != nil {
+		return nil, err
+	}
+
+	var profiles []Profile
+	for dec.More() {
+		token, err := dec.Token()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse JSON: %w", err)
+		}
+		switch token {
+		case "servers":
+			if err := expectJSONDelim(dec, '['); err != nil {
+				return nil, err
+			}
+			for index := 0; dec.More(); index++ {
+				var server Server
+				err := dec.Decode(&server)
+				// A value of the wrong type only spoils this entry; anything else breaks the stream
+				var typeErr *json.UnmarshalTypeError
+				if err != nil && !errors.As(err, &typeErr) {
+					return nil, fmt.Errorf("failed to parse server %d: %w", index+1, err)
+				}
+				if !emit(index, server, err) {
+					return nil, nil
+				}
+			}
+			if err := expectJSONDelim(dec, ']'); err != nil {
+				return nil, err
+			}
+		case "profiles":
+			if err := dec.Decode(&profiles); err != nil {
+				return nil, fmt.Errorf("failed to parse profiles: %w", err)
+			}
+		default:
+			var skipped json.RawMessage
+			if err := dec.Decode(&skipped); err != nil {
+				return nil, fmt.Errorf("failed to parse JSON: %w", err)
+			}
+		}
+	}
+	return profiles, nil
+}
+
+// expectJSONDelim reads the next token and checks it is the given delimiter
+func expectJSONDelim(dec *json.Decoder, delim json.Delim) error {
+	token, err := dec.Token()
+	if err != nil {
+		return fmt.Errorf("failed to parse JSON: %w", err)
+	}
+	if token != delim {
+		return fmt.Errorf("failed to parse JSON: expected '%v', got '%v'", delim, token)
+	}
+	return nil
+}
+
+// streamYAMLServers decodes the servers of a YAML export entry by entry. YAML has
+// no incremental parser, but decoding each node separately keeps a malformed entry
+// from failing the whole file.
+func streamYAMLServers(r io.Reader, emit func(int, Server, error) bool) ([]Profile, error) {
+	var doc yaml.Node
+	if err := yaml.NewDecoder(r).Decode(&doc); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to parse YAML: %w", err)
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("failed to parse YAML: expected a mapping at the top level")
+	}
+
+	var profiles []Profile
+	root := doc.Content[0]
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key, value := root.Content[i].Value, root.Content[i+1]
+		switch key {
+		case "servers":
+			if value.Kind != yaml.SequenceNode {
+				return nil, fmt.Errorf("failed to parse YAML: servers must be a list")
+			}
+			for index, node := range value.Content {
+				var server Server
+				err := node.Decode(&server)
+				if !emit(index, server, err) {
+					return nil, nil
+				}
+			}
+		case "profiles":
+			if err := value.Decode(&profiles); err != nil {
+				return nil, fmt.Errorf("failed to parse profiles: %w", err)
+			}
+		}
+	}
+	return profiles, nil
+}
+
+// UpsertServers adds servers that don't exist yet and replaces those that do, in
+// one pass so large imports don't rescan the server list per entry. Servers must
+// already be validated; servers whose aliases conflict are skipped and reported.
+func (c *Config) UpsertServers(servers []Server) (added, updated int, errs map[string]error) {
+	index := make(map[string]int, len(c.Servers))
+	for i, server := range c.Servers {
+		index[server.Name] = i
+	}
+
+	for _, server := range servers {
+		if len(server.Aliases) > 0 {
+			if err := c.checkAliasConflicts(server); err != nil {
+				if errs == nil {
+					errs = make(map[string]error)
+				}
+				errs[server.Name] = err
+				continue
+			}
+		}
+		if server.Port == 0 {
+			server.Port = 22
+		}
+		if i, ok := index[server.Name]; ok {
+			c.Servers[i] = server
+			updated++
+			continue
+		}
+		index[server.Name] = len(c.Servers)
+		c.Servers = append(c.Servers, server)
+		added++
+	}
+	return added, updated, errs
+}
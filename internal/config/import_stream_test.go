@@ -0,0 +1,105 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestStreamImportJSON(t *testing.T) {
+	var b strings.Builder
+	b.WriteString(`{"servers": [`)
+	for i := 0; i < 50; i++ {
+		fmt.Fprintf(&b, `{"name": "web-%02d", "hostname": "web%d.example.com", "port": 22, "username": "ops", "auth_type": "key", "key_path": "~/.ssh/id_ed25519"},`, i, i)
+	}
+	b.WriteString(`{"name": "bad-port", "hostname": "x.example.com", "port": "ssh", "username": "ops"},`)
+	b.WriteString(`{"name": "no-host", "username": "ops", "auth_type": "key", "key_path": "~/.ssh/id_ed25519"}`)
+	b.WriteString(`], "ui": {"show_local_time": true}, "profiles": [{"name": "web", "servers": ["web-00"]}]}`)
+
+	var entries []ImportEntry
+	profiles, err := StreamImport(context.Background(), strings.NewReader(b.String()), "json", 4, func(entry ImportEntry) {
+		entries = append(entries, entry)
+	})
+	if err != nil {
+		t.Fatalf("StreamImport failed: %v", err)
+	}
+	if len(entries) != 52 {
+		t.Fatalf("Expected 52 entries, got %d", len(entries))
+	}
+	for i, entry := range entries {
+		if entry.Index != i {
+			t.Fatalf("Entries out of file order: index %d at position %d", entry.Index, i)
+		}
+		if i < 50 && entry.Err != nil {
+			t.Errorf("Unexpected error for %s: %v", entry.Server.Name, entry.Err)
+		}
+	}
+	if entries[50].Err == nil || entries[51].Err == nil {
+		t.Error("Expected errors for the malformed and the invalid entry")
+	}
+	if len(profiles) != 1 || profiles[0].Name != "web" {
+		t.Errorf("Unexpected profiles: %+v", profiles)
+	}
+}
+
+func TestStreamImportYAML(t *testing.T) {
+	data := `servers:
+  - name: db
+    hostname: db.example.com
+    port: 22
+    username: ops
+    auth_type: key
+    key_path: ~/.ssh/id_ed25519
+  - name: broken
+    port: [22]
+profiles:
+  - name: data
+    servers: [db]
+`
+	var entries []ImportEntry
+	profiles, err := StreamImport(context.Background(), strings.NewReader(data), "yaml", 0, func(entry ImportEntry) {
+		entries = append(entries, entry)
+	})
+	if err != nil {
+		t.Fatalf("StreamImport failed: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Err != nil || entries[1].Err == nil {
+		t.Errorf("Unexpected entries: %+v", entries)
+	}
+	if len(profiles) != 1 {
+		t.Errorf("Expected 1 profile, got %d", len(profiles))
+	}
+}
+
+func TestStreamImportSyntaxError(t *testing.T) {
+	_, err := StreamImport(context.Background(), strings.NewReader(`{"servers": [{"name": }`), "json", 2, func(ImportEntry) {})
+	if err == nil {
+		t.Error("Expected error for broken JSON")
+	}
+}
+
+func TestUpsertServers(t *testing.T) {
+	cfg := &Config{Servers: []Server{
+		{Name: "web", Hostname: "old.example.com", Port: 22},
+		{Name: "db", Hostname: "db.example.com", Port: 22, Aliases: []string{"database"}},
+	}}
+
+	added, updated, errs := cfg.UpsertServers([]Server{
+		{Name: "web", Hostname: "new.example.com", Port: 22},
+		{Name: "cache", Hostname: "cache.example.com"},
+		{Name: "clash", Hostname: "clash.example.com", Port: 22, Aliases: []string{"database"}},
+	})
+	if added != 1 || updated != 1 {
+		t.Errorf("Expected 1 added and 1 updated, got %d and %d", added, updated)
+	}
+	if errs["clash"] == nil {
+		t.Error("Expected alias conflict for clash")
+	}
+	if cfg.Servers[0].Hostname != "new.example.com" {
+		t.Error("Expected web to be replaced in place")
+	}
+	if cfg.Servers[2].Name != "cache" || cfg.Servers[2].Port != 22 {
+		t.Errorf("Expected cache appended with default port, got %+v", cfg.Servers[2])
+	}
+}
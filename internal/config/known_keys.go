@@ -0,0 +1,9 @@
+package config
+
+// KnownKey is a public key the authorized_keys audit recognizes, e.g. a
+// colleague's or a deploy key. Keys not listed here, nor among the local
+// ~/.ssh/*.pub files and the servers' key files, are flagged as unknown.
+type KnownKey struct {
+	Name string `yaml:"name" json:"name"`
+	Key  string `yaml:"key" json:"key"` // Public key line, or its SHA256:... fingerprint
+}
@@ -0,0 +1,88 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"sshm/internal/shellquote"
+)
+
+// defaultLoginShell starts the user's login shell on the server
+const defaultLoginShell = `"$SHELL" -l`
+
+// HasLoginSettings reports whether the server sets a working directory or shell command
+func (s *Server) HasLoginSettings() bool {
+	return strings.TrimSpace(s.WorkDir) != "" || strings.TrimSpace(s.Shell) != ""
+}
+
+// LoginCommand returns the remote command run after login: source the
+// bootstrap script, if any, change to the working directory and start the shell.
+// It is empty when there is nothing to do besides the default login shell.
+func (s *Server) LoginCommand(script string) string {
+	if script == "" && !s.HasLoginSettings() {
+		return ""
+	}
+
+	var steps []string
+	if script != "" {
+		steps = append(steps, fmt.Sprintf("[ -f %s ] && . %s", script, script))
+	}
+	if dir := strings.TrimSpace(s.WorkDir); dir != "" {
+		// A missing directory leaves the shell in the home directory, after cd's own error
+		steps = append(steps, "cd "+remotePath(dir))
+	}
+	shell := strings.TrimSpace(s.Shell)
+	if shell == "" {
+		shell = defaultLoginShell
+	}
+	steps = append(steps, "exec "+shell)
+	return strings.Join(steps, "; ")
+}
+
+// remotePath quotes a remote path, leaving a leading ~ to be expanded on the server
+func remotePath(path string) string {
+	switch {
+	case path == "~":
+		return `"$HOME"`
+	case strings.HasPrefix(path, "~/"):
+		return `"$HOME"/` + shellquote.Quote(path[2:])
+	default:
+		return shellquote.Quote(path)
+	}
+}
+
+// validateLoginSettings checks a working directory and shell command, which are
+// sent to the server on a single command line
+func validateLoginSettings(workDir, shell string) error {
+	if strings.ContainsAny(workDir, "\r\n") {
+		return fmt.Errorf("work_dir must be a single line")
+	}
+	if strings.ContainsAny(shell, "\r\n") {
+		return fmt.Errorf("shell must be a single line")
+	}
+	return nil
+}
+
+// WithLogin returns a copy of server that inherits the profile's working
+// directory and shell command where the server doesn't set its own
+func (p *Profile) WithLogin(server Server) Server {
+	if strings.TrimSpace(server.WorkDir) == "" {
+		server.WorkDir = p.WorkDir
+	}
+	if strings.TrimSpace(server.Shell) == "" {
+		server.Shell = p.Shell
+	}
+	return server
+}
+
+// WithLogin returns a copy of server that inherits the working directory and
+// shell command of the first of its profiles defining one, for connections made
+// to the server on its own
+func (c *Config) WithLogin(server Server) Server {
+	for _, profile := range c.Profiles {
+		if contains(profile.Servers, server.Name) && (profile.WorkDir != "" || profile.Shell != "") {
+			return profile.WithLogin(server)
+		}
+	}
+	return server
+}
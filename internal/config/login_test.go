@@ -0,0 +1,67 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWrapSSHCommandWithWorkDir(t *testing.T) {
+	server := Server{Name: "app1", Hostname: "app1.example.com", Port: 22, Username: "ops", WorkDir: "/srv/app"}
+	sshCmd := "ssh -t ops@app1.example.com"
+
+	got := server.WrapSSHCommand("", sshCmd)
+	expected := sshCmd + ` 'cd '\''/srv/app'\''; exec "$SHELL" -l'`
+	if got != expected {
+		t.Errorf("Expected %s\ngot      %s", expected, got)
+	}
+}
+
+func TestLoginCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		server   Server
+		script   string
+		expected string
+	}{
+		{"nothing to do", Server{}, "", ""},
+		{"home-relative directory", Server{WorkDir: "~/apps/my app"}, "", `cd "$HOME"/'apps/my app'; exec "$SHELL" -l`},
+		{"shell only", Server{Shell: "sudo -iu app"}, "", "exec sudo -iu app"},
+		{"script, directory and shell", Server{WorkDir: "/srv/app", Shell: "bash -l"}, "~/setup.sh",
+			`[ -f ~/setup.sh ] && . ~/setup.sh; cd '/srv/app'; exec bash -l`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.server.LoginCommand(tt.script); got != tt.expected {
+				t.Errorf("Expected %s\ngot      %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestWithLogin(t *testing.T) {
+	cfg := &Config{Profiles: []Profile{
+		{Name: "web", Servers: []string{"web1"}},
+		{Name: "app", Servers: []string{"app1", "app2"}, WorkDir: "/srv/app", Shell: "bash -l"},
+	}}
+
+	inherited := cfg.WithLogin(Server{Name: "app1"})
+	if inherited.WorkDir != "/srv/app" || inherited.Shell != "bash -l" {
+		t.Errorf("Expected the profile's login settings, got %q and %q", inherited.WorkDir, inherited.Shell)
+	}
+
+	own := cfg.WithLogin(Server{Name: "app2", WorkDir: "/opt/other"})
+	if own.WorkDir != "/opt/other" || own.Shell != "bash -l" {
+		t.Errorf("Expected the server's directory and the profile's shell, got %q and %q", own.WorkDir, own.Shell)
+	}
+
+	if plain := cfg.WithLogin(Server{Name: "web1"}); plain.HasLoginSettings() {
+		t.Errorf("Expected no login settings, got %q and %q", plain.WorkDir, plain.Shell)
+	}
+}
+
+func TestProfileValidateLoginSettings(t *testing.T) {
+	profile := Profile{Name: "app", WorkDir: "/srv/app\nrm -rf /"}
+	if err := profile.Validate(); err == nil || !strings.Contains(err.Error(), "work_dir") {
+		t.Errorf("Expected a multi-line work_dir to be rejected, got %v", err)
+	}
+}
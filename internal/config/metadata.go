@@ -0,0 +1,132 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// MetadataFields are the names of the governance metadata fields, in display order
+var MetadataFields = []string{"owner", "team", "cost_center", "environment"}
+
+// metadataFieldAliases maps short and dashed spellings to metadata field names
+var metadataFieldAliases = map[string]string{
+	"owner":       "owner",
+	"team":        "team",
+	"cost_center": "cost_center",
+	"cost-center": "cost_center",
+	"cost":        "cost_center",
+	"environment": "environment",
+	"env":         "environment",
+}
+
+// ServerMetadata holds governance information about who owns and pays for a server
+type ServerMetadata struct {
+	Owner       string `yaml:"owner,omitempty" json:"owner,omitempty"`
+	Team        string `yaml:"team,omitempty" json:"team,omitempty"`
+	CostCenter  string `yaml:"cost_center,omitempty" json:"cost_center,omitempty"`
+	Environment string `yaml:"environment,omitempty" json:"environment,omitempty"` // e.g. production, staging
+}
+
+// NormalizeMetadataField resolves a metadata field name such as "env" or
+// "cost-center" to its canonical name
+func NormalizeMetadataField(name string) (string, error) {
+	field, ok := metadataFieldAliases[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return "", fmt.Errorf("unknown metadata field '%s' (use %s)", name, strings.Join(MetadataFields, ", "))
+	}
+	return field, nil
+}
+
+// Get returns the value of a canonical metadata field
+func (m ServerMetadata) Get(field string) string {
+	switch field {
+	case "owner":
+		return m.Owner
+	case "team":
+		return m.Team
+	case "cost_center":
+		return m.CostCenter
+	case "environment":
+		return m.Environment
+	}
+	return ""
+}
+
+// Set updates a canonical metadata field; an empty value clears it
+func (m *ServerMetadata) Set(field, value string) error {
+	value = strings.TrimSpace(value)
+	switch field {
+	case "owner":
+		m.Owner = value
+	case "team":
+		m.Team = value
+	case "cost_center":
+		m.CostCenter = value
+	case "environment":
+		m.Environment = value
+	default:
+		return fmt.Errorf("unknown metadata field '%s'", field)
+	}
+	return nil
+}
+
+// IsEmpty reports whether no metadata field is set
+func (m ServerMetadata) IsEmpty() bool {
+	return m == ServerMetadata{}
+}
+
+// Matches reports whether the metadata matches a lowercase search term. Terms of the
+// form "field:value" (e.g. "owner:alice", "env:prod") match only that field;
+// other terms match any field.
+func (m ServerMetadata) Matches(searchLower string) bool {
+	if name, value, ok := strings.Cut(searchLower, ":"); ok {
+		if field, err := NormalizeMetadataField(name); err == nil {
+			return strings.Contains(strings.ToLower(m.Get(field)), strings.TrimSpace(value))
+		}
+	}
+	for _, field := range MetadataFields {
+		if value := m.Get(field); value != "" && strings.Contains(strings.ToLower(value), searchLower) {
+			return true
+		}
+	}
+	return false
+}
+
+// MetadataCount is the number of servers sharing a metadata value
+type MetadataCount struct {
+	Value   string   `json:"value"`
+	Count   int      `json:"count"`
+	Servers []string `json:"servers"`
+}
+
+// CountByMetadata groups servers by the value of a canonical metadata field. Servers
+// without a value are counted under "" and listed last; others are sorted by count.
+func CountByMetadata(servers []Server, field string) []MetadataCount {
+	groups := make(map[string]*MetadataCount)
+	for _, server := range servers {
+		value := strings.TrimSpace(server.Metadata.Get(field))
+		group, exists := groups[value]
+		if !exists {
+			group = &MetadataCount{Value: value}
+			groups[value] = group
+		}
+		group.Count++
+		group.Servers = append(group.Servers, server.Name)
+	}
+
+	counts := make([]MetadataCount, 0, len(groups))
+	for _, group := range groups {
+		counts = append(counts, *group)
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if (counts[i].Value == "") != (counts[j].Value == "") {
+			return counts[j].Value == ""
+		}
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].Value < counts[j].Value
+	})
+	return counts
+}
@@ -0,0 +1,61 @@
+package config
+
+import "testing"
+
+func TestServerMetadataMatches(t *testing.T) {
+	metadata := ServerMetadata{Owner: "Alice", Team: "payments", CostCenter: "CC-42", Environment: "production"}
+
+	tests := []struct {
+		search string
+		want   bool
+	}{
+		{"alice", true},
+		{"cc-42", true},
+		{"owner:ali", true},
+		{"env:prod", true},
+		{"cost-center:cc-42", true},
+		{"team:alice", false},
+		{"bob", false},
+	}
+	for _, tt := range tests {
+		if got := metadata.Matches(tt.search); got != tt.want {
+			t.Errorf("Matches(%q) = %v, want %v", tt.search, got, tt.want)
+		}
+	}
+
+	if (ServerMetadata{}).Matches("owner:") != true {
+		t.Error("An empty field value should match an empty field search")
+	}
+}
+
+func TestNormalizeMetadataField(t *testing.T) {
+	for input, want := range map[string]string{"env": "environment", "Cost-Center": "cost_center", "owner": "owner"} {
+		got, err := NormalizeMetadataField(input)
+		if err != nil || got != want {
+			t.Errorf("NormalizeMetadataField(%q) = %q, %v, want %q", input, got, err, want)
+		}
+	}
+	if _, err := NormalizeMetadataField("region"); err == nil {
+		t.Error("Expected error for unknown field")
+	}
+}
+
+func TestCountByMetadata(t *testing.T) {
+	servers := []Server{
+		{Name: "web1", Metadata: ServerMetadata{Owner: "alice"}},
+		{Name: "web2", Metadata: ServerMetadata{Owner: "alice"}},
+		{Name: "db1", Metadata: ServerMetadata{Owner: "bob"}},
+		{Name: "tmp1"},
+	}
+
+	counts := CountByMetadata(servers, "owner")
+	if len(counts) != 3 {
+		t.Fatalf("Expected 3 groups, got %+v", counts)
+	}
+	if counts[0].Value != "alice" || counts[0].Count != 2 {
+		t.Errorf("Expected alice first with 2 servers, got %+v", counts[0])
+	}
+	if counts[2].Value != "" || counts[2].Servers[0] != "tmp1" {
+		t.Errorf("Expected unset group last, got %+v", counts[2])
+	}
+}
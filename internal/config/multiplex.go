@@ -0,0 +1,66 @@
+package config
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ControlPersist is how long OpenSSH keeps an idle master connection open
+const ControlPersist = "10m"
+
+// ControlDir returns the directory holding the sockets of master connections
+func ControlDir() (string, error) {
+	configPath, err := DefaultConfigPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(configPath), "mux"), nil
+}
+
+// ControlOptions returns the ssh options that make connections to the server
+// share one master connection, or "" when controlmaster is off and the warm
+// pool holds no connection to it. The first connection becomes the master and
+// later ones skip authentication.
+func (s *Server) ControlOptions() string {
+	// A warm connection is reused whether or not controlmaster is on
+	if s.HasWarmConnection() {
+		socket, _ := s.WarmSocketPath()
+		return fmt.Sprintf(" -o ControlMaster=no -o ControlPath=%s", socket)
+	}
+	if !s.ControlMaster {
+		return ""
+	}
+	dir, err := ControlDir()
+	if err != nil {
+		return ""
+	}
+	// ssh doesn't create the directory; without it connections still work, unshared
+	_ = os.MkdirAll(dir, 0700)
+	// %C hashes the local host, remote host, port and user, keeping the path short
+	return fmt.Sprintf(" -o ControlMaster=auto -o ControlPath=%s -o ControlPersist=%s", filepath.Join(dir, "%C"), ControlPersist)
+}
+
+// WarmSocketPath returns the socket of the server's warm pool connection. Unlike
+// %C it is known before ssh runs, so sessions can tell whether it exists.
+func (s *Server) WarmSocketPath() (string, error) {
+	dir, err := ControlDir()
+	if err != nil {
+		return "", err
+	}
+	sum := sha1.Sum([]byte(fmt.Sprintf("%s@%s:%d", s.Username, s.GetEffectiveHostname(), s.Port)))
+	return filepath.Join(dir, "warm-"+hex.EncodeToString(sum[:8])), nil
+}
+
+// HasWarmConnection reports whether the warm pool's socket for the server
+// exists. A stale socket is harmless: ssh then connects directly.
+func (s *Server) HasWarmConnection() bool {
+	socket, err := s.WarmSocketPath()
+	if err != nil {
+		return false
+	}
+	info, err := os.Stat(socket)
+	return err == nil && info.Mode()&os.ModeSocket != 0
+}
@@ -0,0 +1,30 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestControlOptions(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("SSHM_CONFIG_DIR", dir)
+
+	server := Server{Name: "web1"}
+	if options := server.ControlOptions(); options != "" {
+		t.Errorf("Expected no options without controlmaster, got %q", options)
+	}
+
+	server.ControlMaster = true
+	options := server.ControlOptions()
+	controlPath := filepath.Join(dir, "mux", "%C")
+	for _, want := range []string{"-o ControlMaster=auto", "-o ControlPath=" + controlPath, "-o ControlPersist=" + ControlPersist} {
+		if !strings.Contains(options, want) {
+			t.Errorf("Expected %q in %q", want, options)
+		}
+	}
+	if info, err := os.Stat(filepath.Join(dir, "mux")); err != nil || info.Mode().Perm() != 0700 {
+		t.Errorf("Expected a private socket directory, got %v, %v", info, err)
+	}
+}
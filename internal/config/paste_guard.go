@@ -0,0 +1,44 @@
+package config
+
+import "strings"
+
+// Defaults of the paste guard: pasting more than one line or 1 KiB into a
+// protected server's session asks first
+const (
+	DefaultPasteGuardMaxLines = 1
+	DefaultPasteGuardMaxBytes = 1024
+)
+
+// PasteGuardConfig controls the confirmation asked before large pastes into
+// the tmux sessions of protected servers. Pastes through tmux (prefix + ]) are
+// guarded; text pasted by the terminal emulator reaches the pane directly.
+type PasteGuardConfig struct {
+	Disabled bool `yaml:"disabled,omitempty" json:"disabled,omitempty"`   // Paste without asking, even into protected servers
+	MaxLines int  `yaml:"max_lines,omitempty" json:"max_lines,omitempty"` // Longer pastes ask first (0 = 1, any multi-line paste)
+	MaxBytes int  `yaml:"max_bytes,omitempty" json:"max_bytes,omitempty"` // Larger pastes ask first (0 = 1024)
+}
+
+// NeedsConfirmation reports whether pasting text into a protected server must be confirmed
+func (g PasteGuardConfig) NeedsConfirmation(text string) bool {
+	if g.Disabled {
+		return false
+	}
+	maxLines := g.MaxLines
+	if maxLines <= 0 {
+		maxLines = DefaultPasteGuardMaxLines
+	}
+	maxBytes := g.MaxBytes
+	if maxBytes <= 0 {
+		maxBytes = DefaultPasteGuardMaxBytes
+	}
+	return PasteLines(text) > maxLines || len(text) > maxBytes
+}
+
+// PasteLines counts the lines of pasted text; a trailing newline doesn't start a new line
+func PasteLines(text string) int {
+	trimmed := strings.TrimRight(text, "\n")
+	if trimmed == "" {
+		return 0
+	}
+	return strings.Count(trimmed, "\n") + 1
+}
@@ -0,0 +1,36 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPasteGuardNeedsConfirmation(t *testing.T) {
+	tests := []struct {
+		name     string
+		guard    PasteGuardConfig
+		text     string
+		expected bool
+	}{
+		{"single command", PasteGuardConfig{}, "systemctl status nginx\n", false},
+		{"multi-line script", PasteGuardConfig{}, "cd /srv\nrm -rf build\n", true},
+		{"long single line", PasteGuardConfig{}, strings.Repeat("x", 2000), true},
+		{"within custom limits", PasteGuardConfig{MaxLines: 5, MaxBytes: 4096}, "a\nb\nc\n", false},
+		{"disabled", PasteGuardConfig{Disabled: true}, "a\nb\nc\n", false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.guard.NeedsConfirmation(test.text); got != test.expected {
+				t.Errorf("NeedsConfirmation() = %v, want %v", got, test.expected)
+			}
+		})
+	}
+}
+
+func TestPasteLines(t *testing.T) {
+	for text, expected := range map[string]int{"": 0, "ls": 1, "ls\n": 1, "ls\npwd": 2, "ls\n\n": 1} {
+		if got := PasteLines(text); got != expected {
+			t.Errorf("PasteLines(%q) = %d, want %d", text, got, expected)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultCheckPorts are the ports probed unless configured: ssh, web and common databases
+var DefaultCheckPorts = []int{22, 80, 443, 3306, 5432, 6379, 8080}
+
+// DefaultPortCheckTimeout is how long a probe waits before the port counts as filtered
+const DefaultPortCheckTimeout = 3 * time.Second
+
+// PortCheckConfig sets which ports 'sshm ports' and the TUI port check probe
+type PortCheckConfig struct {
+	Ports   []int  `yaml:"ports,omitempty" json:"ports,omitempty"`     // Ports probed on every server (default: 22, 80, 443, 3306, 5432, 6379, 8080)
+	Timeout string `yaml:"timeout,omitempty" json:"timeout,omitempty"` // How long to wait before a port counts as filtered (default: 3s)
+}
+
+// PortList returns the configured ports, or the defaults when none are set
+func (p *PortCheckConfig) PortList() []int {
+	if len(p.Ports) == 0 {
+		return DefaultCheckPorts
+	}
+	return p.Ports
+}
+
+// TimeoutDuration returns the configured timeout, or the default when unset or invalid
+func (p *PortCheckConfig) TimeoutDuration() time.Duration {
+	if d, err := time.ParseDuration(p.Timeout); err == nil && d > 0 {
+		return d
+	}
+	return DefaultPortCheckTimeout
+}
+
+// ParsePorts parses a comma or space separated port list such as "22,80 443"
+func ParsePorts(value string) ([]int, error) {
+	var ports []int
+	for _, field := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ' ' }) {
+		port, err := strconv.Atoi(field)
+		if err != nil || port <= 0 || port > 65535 {
+			return nil, fmt.Errorf("invalid port %q", field)
+		}
+		ports = append(ports, port)
+	}
+	if len(ports) == 0 {
+		return nil, fmt.Errorf("at least one port is required")
+	}
+	return ports, nil
+}
+
+// FormatPorts formats ports as a comma separated list, the inverse of ParsePorts
+func FormatPorts(ports []int) string {
+	fields := make([]string, len(ports))
+	for i, port := range ports {
+		fields[i] = strconv.Itoa(port)
+	}
+	return strings.Join(fields, ",")
+}
@@ -0,0 +1,34 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParsePorts(t *testing.T) {
+	ports, err := ParsePorts("22, 80 443,,3306")
+	if err != nil {
+		t.Fatalf("ParsePorts failed: %v", err)
+	}
+	if FormatPorts(ports) != "22,80,443,3306" {
+		t.Errorf("Unexpected ports %v", ports)
+	}
+
+	for _, value := range []string{"", "ssh", "0", "70000"} {
+		if _, err := ParsePorts(value); err == nil {
+			t.Errorf("Expected ParsePorts(%q) to fail", value)
+		}
+	}
+}
+
+func TestPortCheckDefaults(t *testing.T) {
+	var check PortCheckConfig
+	if len(check.PortList()) != len(DefaultCheckPorts) || check.TimeoutDuration() != DefaultPortCheckTimeout {
+		t.Errorf("Expected the defaults, got %v and %v", check.PortList(), check.TimeoutDuration())
+	}
+
+	check = PortCheckConfig{Ports: []int{2222}, Timeout: "500ms"}
+	if FormatPorts(check.PortList()) != "2222" || check.TimeoutDuration() != 500*time.Millisecond {
+		t.Errorf("Expected the configured values, got %v and %v", check.PortList(), check.TimeoutDuration())
+	}
+}
@@ -0,0 +1,170 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// ProfileProposal is a profile inferred for servers imported from an SSH config
+type ProfileProposal struct {
+	Profile string   `json:"profile"`
+	Servers []string `json:"servers"`
+	Reasons []string `json:"reasons"`
+}
+
+// InferSSHProfiles proposes profiles for the valid servers of an SSH config,
+// preferring in order: the included file a server is defined in, the first
+// wildcard Host pattern it matches ("*.prod.example.com" -> "prod") and a
+// domain it shares with other servers. Each server gets one proposal at most;
+// servers matching nothing are left unassigned.
+func InferSSHProfiles(file *SSHConfigFile) []ProfileProposal {
+	var servers []Server
+	for _, server := range file.Servers {
+		if server.Validate() == nil {
+			servers = append(servers, server)
+		}
+	}
+
+	var proposals []ProfileProposal
+	assigned := make(map[string]bool)
+	propose := func(profile, reason string, names ...string) {
+		if profile == "" {
+			return
+		}
+		index := -1
+		for i := range proposals {
+			if proposals[i].Profile == profile {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			proposals = append(proposals, ProfileProposal{Profile: profile})
+			index = len(proposals) - 1
+		}
+		if !contains(proposals[index].Reasons, reason) {
+			proposals[index].Reasons = append(proposals[index].Reasons, reason)
+		}
+		for _, name := range names {
+			proposals[index].Servers = append(proposals[index].Servers, name)
+			assigned[name] = true
+		}
+	}
+
+	for _, server := range servers {
+		if source := file.Sources[server.Name]; source != "" && source != file.Path {
+			propose(includeProfileName(source), fmt.Sprintf("defined in %s", filepath.Base(source)), server.Name)
+		}
+	}
+
+	for _, server := range servers {
+		if assigned[server.Name] {
+			continue
+		}
+		for _, pattern := range file.Patterns {
+			if matchesHostPattern(pattern, server) {
+				propose(patternProfileName(pattern), fmt.Sprintf("matches Host %s", pattern), server.Name)
+				break
+			}
+		}
+	}
+
+	var domains []string
+	byDomain := make(map[string][]string)
+	for _, server := range servers {
+		if assigned[server.Name] {
+			continue
+		}
+		domain := hostDomain(server.Hostname)
+		if domain == "" {
+			continue
+		}
+		if _, seen := byDomain[domain]; !seen {
+			domains = append(domains, domain)
+		}
+		byDomain[domain] = append(byDomain[domain], server.Name)
+	}
+	for _, domain := range domains {
+		if len(byDomain[domain]) > 1 {
+			propose(domainProfileName(domain), fmt.Sprintf("hostnames under %s", domain), byDomain[domain]...)
+		}
+	}
+	return proposals
+}
+
+// includeProfileName names a profile after an included file: "work" for
+// config.d/work.conf, or the directory for files named config, e.g. work/config
+func includeProfileName(source string) string {
+	name := strings.TrimSuffix(filepath.Base(source), filepath.Ext(source))
+	if strings.EqualFold(name, "config") {
+		name = filepath.Base(filepath.Dir(source))
+	}
+	return strings.ToLower(strings.Trim(name, "."))
+}
+
+// patternProfileName names a profile after the first literal word of a Host
+// pattern: "prod" for *.prod.example.com and "web" for web-*
+func patternProfileName(pattern string) string {
+	for _, token := range nameTokens(pattern) {
+		if !strings.ContainsAny(token, "*?[") {
+			return token
+		}
+	}
+	return ""
+}
+
+// domainProfileName names a profile after the first label of a domain with a
+// subdomain, e.g. "prod" for prod.example.com; registered domains such as
+// example.com name nothing
+func domainProfileName(domain string) string {
+	labels := strings.Split(domain, ".")
+	if len(labels) < 3 {
+		return ""
+	}
+	return labels[0]
+}
+
+// matchesHostPattern reports whether a Host pattern matches the server's name
+// or hostname, case-insensitively
+func matchesHostPattern(pattern string, server Server) bool {
+	pattern = strings.ToLower(pattern)
+	for _, candidate := range []string{server.Name, server.Hostname} {
+		if matched, err := path.Match(pattern, strings.ToLower(candidate)); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+// ApplyProfileProposals creates the proposed profiles, adding the servers to
+// profiles that already exist, and returns how many were created and extended
+func (c *Config) ApplyProfileProposals(proposals []ProfileProposal) (created, extended int, err error) {
+	for _, proposal := range proposals {
+		if existing, getErr := c.GetProfile(proposal.Profile); getErr == nil {
+			added := false
+			for _, name := range proposal.Servers {
+				if !contains(existing.Servers, name) {
+					existing.Servers = append(existing.Servers, name)
+					added = true
+				}
+			}
+			if added {
+				extended++
+			}
+			continue
+		}
+
+		profile := Profile{
+			Name:        proposal.Profile,
+			Description: "Inferred from SSH config: " + strings.Join(proposal.Reasons, ", "),
+			Servers:     append([]string(nil), proposal.Servers...),
+		}
+		if err := c.AddProfile(profile); err != nil {
+			return created, extended, fmt.Errorf("failed to create profile '%s': %w", proposal.Profile, err)
+		}
+		created++
+	}
+	return created, extended, nil
+}
@@ -0,0 +1,113 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestInferSSHProfiles(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "config.d"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	mainConfig := `Include config.d/*
+
+Host *.prod.example.com web-*
+    User ops
+
+Host db1
+    HostName db1.prod.example.com
+    User ops
+
+Host web-eu
+    HostName 10.0.0.5
+    User ops
+
+Host ci1
+    HostName ci1.build.corp.net
+    User ci
+
+Host ci2
+    HostName ci2.build.corp.net
+    User ci
+
+Host home
+    HostName nas.local
+    User me
+`
+	workConfig := `Host jira
+    HostName jira.corp.net
+    User me
+`
+	if err := os.WriteFile(filepath.Join(dir, "config"), []byte(mainConfig), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "config.d", "work.conf"), []byte(workConfig), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := ReadSSHConfig(filepath.Join(dir, "config"))
+	if err != nil {
+		t.Fatalf("ReadSSHConfig() error = %v", err)
+	}
+	if len(file.Servers) != 6 {
+		t.Fatalf("Expected the included host too, got %d servers", len(file.Servers))
+	}
+
+	got := make(map[string]string)
+	for _, proposal := range InferSSHProfiles(file) {
+		got[proposal.Profile] = strings.Join(proposal.Servers, ",")
+	}
+	expected := map[string]string{
+		"work":  "jira",    // Included file
+		"prod":  "db1",     // *.prod.example.com
+		"web":   "web-eu",  // web-*
+		"build": "ci1,ci2", // Shared domain build.corp.net
+	}
+	if len(got) != len(expected) {
+		t.Errorf("Expected proposals %v, got %v", expected, got)
+	}
+	for profile, servers := range expected {
+		if got[profile] != servers {
+			t.Errorf("Expected %s to hold %s, got %q", profile, servers, got[profile])
+		}
+	}
+}
+
+func TestPatternProfileName(t *testing.T) {
+	tests := map[string]string{
+		"*.prod.example.com": "prod",
+		"web-*":              "web",
+		"db?.staging.*":      "staging",
+		"*":                  "",
+	}
+	for pattern, expected := range tests {
+		if got := patternProfileName(pattern); got != expected {
+			t.Errorf("patternProfileName(%q) = %q, want %q", pattern, got, expected)
+		}
+	}
+}
+
+func TestApplyProfileProposals(t *testing.T) {
+	cfg := &Config{Profiles: []Profile{{Name: "prod", Servers: []string{"db1"}}}}
+	proposals := []ProfileProposal{
+		{Profile: "prod", Servers: []string{"db1", "db2"}, Reasons: []string{"matches Host *.prod.example.com"}},
+		{Profile: "work", Servers: []string{"jira"}, Reasons: []string{"defined in work.conf"}},
+	}
+
+	created, extended, err := cfg.ApplyProfileProposals(proposals)
+	if err != nil {
+		t.Fatalf("ApplyProfileProposals() error = %v", err)
+	}
+	if created != 1 || extended != 1 {
+		t.Errorf("Expected 1 created and 1 extended, got %d and %d", created, extended)
+	}
+	if prod, _ := cfg.GetProfile("prod"); strings.Join(prod.Servers, ",") != "db1,db2" {
+		t.Errorf("Expected db2 added to prod, got %v", prod.Servers)
+	}
+	if work, err := cfg.GetProfile("work"); err != nil || !strings.Contains(work.Description, "work.conf") {
+		t.Errorf("Expected the work profile with its reason, got %+v (%v)", work, err)
+	}
+}
@@ -0,0 +1,146 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+)
+
+// ProfileSuggestion proposes adding a server that belongs to no profile to the
+// profile it resembles most
+type ProfileSuggestion struct {
+	Server  string   `json:"server"`
+	Profile string   `json:"profile"`
+	Score   int      `json:"score"`
+	Reasons []string `json:"reasons"`
+}
+
+// UnassignedServers returns the servers that belong to no profile
+func (c *Config) UnassignedServers() []Server {
+	assigned := make(map[string]bool)
+	for _, profile := range c.Profiles {
+		for _, name := range profile.Servers {
+			assigned[name] = true
+		}
+	}
+
+	var servers []Server
+	for _, server := range c.Servers {
+		if !assigned[server.Name] {
+			servers = append(servers, server)
+		}
+	}
+	return servers
+}
+
+// SuggestProfileAssignments proposes a profile for every unassigned server that
+// resembles one: a tag or name naming the profile, a name or domain shared with
+// its members, a tag in common or the same /24 subnet. Servers resembling no
+// profile get no suggestion.
+func (c *Config) SuggestProfileAssignments() []ProfileSuggestion {
+	var suggestions []ProfileSuggestion
+	for _, server := range c.UnassignedServers() {
+		var best ProfileSuggestion
+		for _, profile := range c.Profiles {
+			members, _ := c.GetServersByProfile(profile.Name)
+			score, reasons := scoreProfileMatch(server, profile.Name, members)
+			if score > best.Score {
+				best = ProfileSuggestion{Server: server.Name, Profile: profile.Name, Score: score, Reasons: reasons}
+			}
+		}
+		if best.Score > 0 {
+			suggestions = append(suggestions, best)
+		}
+	}
+
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return suggestions[i].Score > suggestions[j].Score
+	})
+	return suggestions
+}
+
+// scoreProfileMatch rates how much a server resembles a profile and its members
+func scoreProfileMatch(server Server, profileName string, members []Server) (int, []string) {
+	score := 0
+	var reasons []string
+	add := func(points int, format string, args ...interface{}) {
+		score += points
+		reasons = append(reasons, fmt.Sprintf(format, args...))
+	}
+
+	profileLower := strings.ToLower(profileName)
+	for _, tag := range server.Tags {
+		if strings.ToLower(tag) == profileLower {
+			add(3, "tag '%s' matches the profile name", tag)
+		}
+	}
+	for _, token := range nameTokens(server.Name + "." + server.Hostname) {
+		if token == profileLower {
+			add(2, "name contains '%s'", profileName)
+			break
+		}
+	}
+
+	stem, domain, subnet := nameStem(server.Name), hostDomain(server.Hostname), hostSubnet(server.Hostname)
+	var named, sameDomain, sameSubnet, sharedTag bool
+	for _, member := range members {
+		if !named && stem != "" && nameStem(member.Name) == stem {
+			named = true
+			add(2, "named like %s", member.Name)
+		}
+		if !sameDomain && domain != "" && hostDomain(member.Hostname) == domain {
+			sameDomain = true
+			add(2, "same domain as %s", member.Name)
+		}
+		if !sameSubnet && subnet != "" && hostSubnet(member.Hostname) == subnet {
+			sameSubnet = true
+			add(1, "same /24 subnet as %s", member.Name)
+		}
+		if !sharedTag {
+			for _, tag := range server.Tags {
+				if member.HasTag(tag) {
+					sharedTag = true
+					add(1, "shares tag '%s' with %s", tag, member.Name)
+					break
+				}
+			}
+		}
+	}
+	return score, reasons
+}
+
+// nameTokens splits a name or hostname into lowercase words
+func nameTokens(name string) []string {
+	return strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return r == '-' || r == '_' || r == '.'
+	})
+}
+
+// nameStem strips trailing numbers and separators, so "web-03" and "web-12"
+// share the stem "web"
+func nameStem(name string) string {
+	return strings.TrimRight(strings.ToLower(name), "0123456789-_.")
+}
+
+// hostDomain returns the domain of a hostname, e.g. "prod.example.com" for
+// "db1.prod.example.com"; empty for IP addresses and single-label names
+func hostDomain(hostname string) string {
+	if net.ParseIP(hostname) != nil {
+		return ""
+	}
+	_, domain, found := strings.Cut(strings.ToLower(hostname), ".")
+	if !found || !strings.Contains(domain, ".") {
+		return ""
+	}
+	return domain
+}
+
+// hostSubnet returns the /24 network of an IPv4 address, empty otherwise
+func hostSubnet(hostname string) string {
+	ip := net.ParseIP(hostname).To4()
+	if ip == nil {
+		return ""
+	}
+	return ip.Mask(net.CIDRMask(24, 32)).String()
+}
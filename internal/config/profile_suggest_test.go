@@ -0,0 +1,81 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUnassignedServers(t *testing.T) {
+	cfg := &Config{
+		Servers:  []Server{{Name: "web1"}, {Name: "web2"}, {Name: "lab"}},
+		Profiles: []Profile{{Name: "web", Servers: []string{"web1"}}},
+	}
+	unassigned := cfg.UnassignedServers()
+	if len(unassigned) != 2 || unassigned[0].Name != "web2" || unassigned[1].Name != "lab" {
+		t.Errorf("Unexpected unassigned servers: %+v", unassigned)
+	}
+}
+
+func TestSuggestProfileAssignments(t *testing.T) {
+	cfg := &Config{
+		Servers: []Server{
+			{Name: "web-01", Hostname: "web-01.prod.example.com"},
+			{Name: "db-01", Hostname: "10.1.2.10", Tags: []string{"postgres"}},
+			{Name: "web-02", Hostname: "web-02.prod.example.com"},
+			{Name: "db-02", Hostname: "10.1.2.11", Tags: []string{"postgres"}},
+			{Name: "scratch", Hostname: "192.168.7.7", Tags: []string{"Staging"}},
+			{Name: "laptop", Hostname: "laptop.local"},
+		},
+		Profiles: []Profile{
+			{Name: "web", Servers: []string{"web-01"}},
+			{Name: "database", Servers: []string{"db-01"}},
+			{Name: "staging"},
+		},
+	}
+
+	suggestions := cfg.SuggestProfileAssignments()
+	got := make(map[string]ProfileSuggestion)
+	for _, suggestion := range suggestions {
+		got[suggestion.Server] = suggestion
+	}
+
+	if len(suggestions) != 3 {
+		t.Fatalf("Expected 3 suggestions, got %+v", suggestions)
+	}
+	if s := got["web-02"]; s.Profile != "web" || !strings.Contains(strings.Join(s.Reasons, ", "), "same domain as web-01") {
+		t.Errorf("Unexpected suggestion for web-02: %+v", s)
+	}
+	if s := got["db-02"]; s.Profile != "database" || len(s.Reasons) != 3 {
+		t.Errorf("Expected db-02 in database by name, subnet and tag, got %+v", s)
+	}
+	if s := got["scratch"]; s.Profile != "staging" || s.Reasons[0] != "tag 'Staging' matches the profile name" {
+		t.Errorf("Unexpected suggestion for scratch: %+v", s)
+	}
+	if _, ok := got["laptop"]; ok {
+		t.Error("Expected no suggestion for a server resembling no profile")
+	}
+	// Strongest suggestions come first
+	for i := 1; i < len(suggestions); i++ {
+		if suggestions[i].Score > suggestions[i-1].Score {
+			t.Errorf("Expected suggestions ordered by score, got %+v", suggestions)
+		}
+	}
+}
+
+func TestHostPatterns(t *testing.T) {
+	if got := nameStem("web-03"); got != "web" {
+		t.Errorf("nameStem(web-03) = %q", got)
+	}
+	if got := hostDomain("db1.prod.example.com"); got != "prod.example.com" {
+		t.Errorf("hostDomain() = %q", got)
+	}
+	if got := hostDomain("example.com"); got != "" {
+		t.Errorf("Expected no domain for a two-label name, got %q", got)
+	}
+	if got := hostSubnet("10.1.2.10"); got != "10.1.2.0" {
+		t.Errorf("hostSubnet() = %q", got)
+	}
+	if got := hostSubnet("db.example.com"); got != "" {
+		t.Errorf("Expected no subnet for a hostname, got %q", got)
+	}
+}
@@ -0,0 +1,84 @@
+package config
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Environment variables read on first run, when no configuration file exists yet
+const (
+	BootstrapEnv     = "SSHM_BOOTSTRAP"      // Initial configuration as YAML, or base64: followed by base64-encoded YAML
+	BootstrapURLEnv  = "SSHM_BOOTSTRAP_URL"  // https:// URL of the initial configuration
+	BootstrapLockEnv = "SSHM_BOOTSTRAP_LOCK" // "1" or "true" locks the written configuration read-only
+)
+
+// ErrLocked is returned when saving a configuration provisioned read-only
+var ErrLocked = errors.New("the configuration is locked (provisioned read-only); ask your administrator, or remove 'locked: true' from config.yaml to make changes")
+
+// bootstrapFromEnvironment writes the initial configuration from SSHM_BOOTSTRAP or
+// SSHM_BOOTSTRAP_URL to configPath. Nothing is written when neither is set.
+func bootstrapFromEnvironment(configPath string) error {
+	data, source, err := readBootstrap()
+	if err != nil || data == nil {
+		return err
+	}
+
+	var config Config
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return fmt.Errorf("invalid configuration in %s: %w", source, err)
+	}
+	for _, server := range config.Servers {
+		if err := server.Validate(); err != nil {
+			return fmt.Errorf("invalid server '%s' in %s: %w", server.Name, source, err)
+		}
+	}
+	if lock := strings.ToLower(strings.TrimSpace(os.Getenv(BootstrapLockEnv))); lock == "1" || lock == "true" {
+		config.Locked = true
+	}
+
+	data, err = yaml.Marshal(&config)
+	if err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+	mode := os.FileMode(0600)
+	if config.Locked {
+		mode = 0400
+	}
+	if err := os.WriteFile(configPath, data, mode); err != nil {
+		return fmt.Errorf("failed to write config file from %s: %w", source, err)
+	}
+	return nil
+}
+
+// readBootstrap returns the initial configuration and where it came from, or
+// nil when no bootstrap source is set
+func readBootstrap() ([]byte, string, error) {
+	if value := strings.TrimSpace(os.Getenv(BootstrapEnv)); value != "" {
+		encoded, ok := strings.CutPrefix(value, "base64:")
+		if !ok {
+			return []byte(value), BootstrapEnv, nil
+		}
+		data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid base64 in %s: %w", BootstrapEnv, err)
+		}
+		return data, BootstrapEnv, nil
+	}
+
+	if url := strings.TrimSpace(os.Getenv(BootstrapURLEnv)); url != "" {
+		if !strings.HasPrefix(url, "https://") {
+			return nil, "", fmt.Errorf("%s must be an https:// URL", BootstrapURLEnv)
+		}
+		data, err := fetchURL(url)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to fetch initial configuration from %s: %w", url, err)
+		}
+		return data, url, nil
+	}
+	return nil, "", nil
+}
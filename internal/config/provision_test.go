@@ -0,0 +1,105 @@
+package config
+
+import (
+	"encoding/base64"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const bootstrapYAML = `servers:
+  - name: build
+    hostname: build.example.com
+    port: 22
+    username: dev
+    auth_type: key
+    key_path: ~/.ssh/id_ed25519
+`
+
+func TestBootstrapFromEnvironment(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{"yaml", bootstrapYAML},
+		{"base64", "base64:" + base64.StdEncoding.EncodeToString([]byte(bootstrapYAML))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(BootstrapEnv, tt.value)
+			configPath := filepath.Join(t.TempDir(), "config.yaml")
+
+			cfg, err := LoadFromPath(configPath)
+			if err != nil {
+				t.Fatalf("LoadFromPath() error = %v", err)
+			}
+			if _, err := cfg.GetServer("build"); err != nil {
+				t.Errorf("Expected the bootstrapped server, got %v", cfg.Servers)
+			}
+			if _, err := os.Stat(configPath); err != nil {
+				t.Errorf("Expected the configuration to be written: %v", err)
+			}
+			if err := cfg.Save(); err != nil {
+				t.Errorf("Expected an unlocked configuration to save, got %v", err)
+			}
+		})
+	}
+}
+
+func TestBootstrapFromURLLocked(t *testing.T) {
+	original := fetchURL
+	fetchURL = func(url string) ([]byte, error) { return []byte(bootstrapYAML), nil }
+	t.Cleanup(func() { fetchURL = original })
+	t.Setenv(BootstrapURLEnv, "https://inventory.example.com/sshm.yaml")
+	t.Setenv(BootstrapLockEnv, "true")
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+
+	cfg, err := LoadFromPath(configPath)
+	if err != nil {
+		t.Fatalf("LoadFromPath() error = %v", err)
+	}
+	if !cfg.Locked || len(cfg.Servers) != 1 {
+		t.Fatalf("Expected a locked configuration with one server, got %+v", cfg)
+	}
+	if info, err := os.Stat(configPath); err != nil || info.Mode().Perm() != 0400 {
+		t.Errorf("Expected a read-only file, got %v, %v", info.Mode(), err)
+	}
+	if err := cfg.Save(); !errors.Is(err, ErrLocked) {
+		t.Errorf("Expected saving to be refused, got %v", err)
+	}
+}
+
+func TestBootstrapOnlyOnFirstRun(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(configPath, []byte("servers: []\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(BootstrapEnv, bootstrapYAML)
+
+	cfg, err := LoadFromPath(configPath)
+	if err != nil {
+		t.Fatalf("LoadFromPath() error = %v", err)
+	}
+	if len(cfg.Servers) != 0 {
+		t.Errorf("Expected an existing configuration to be kept, got %v", cfg.Servers)
+	}
+}
+
+func TestBootstrapRejectsInvalidSources(t *testing.T) {
+	for env, value := range map[string]string{
+		BootstrapEnv:    "servers:\n  - name: broken\n",
+		BootstrapURLEnv: "http://inventory.example.com/sshm.yaml",
+	} {
+		t.Run(env, func(t *testing.T) {
+			t.Setenv(env, value)
+			configPath := filepath.Join(t.TempDir(), "config.yaml")
+			if _, err := LoadFromPath(configPath); err == nil {
+				t.Error("Expected an error")
+			}
+			if _, err := os.Stat(configPath); !os.IsNotExist(err) {
+				t.Error("Expected nothing to be written")
+			}
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// ParseJumpHop splits a [user@]host[:port] jump destination. The port is 0
+// when not given; IPv6 addresses with a port are written [addr]:port.
+func ParseJumpHop(hop string) (username, host string, port int, err error) {
+	host = hop
+	if at := strings.LastIndex(host, "@"); at >= 0 {
+		username, host = host[:at], host[at+1:]
+		if username == "" {
+			return "", "", 0, fmt.Errorf("jump host '%s' has an empty user", hop)
+		}
+	}
+	if strings.HasPrefix(host, "[") && strings.HasSuffix(host, "]") {
+		host = strings.TrimSuffix(strings.TrimPrefix(host, "["), "]")
+	} else if strings.HasPrefix(host, "[") || strings.Count(host, ":") == 1 {
+		h, p, splitErr := net.SplitHostPort(host)
+		if splitErr != nil {
+			return "", "", 0, fmt.Errorf("invalid jump host '%s': %w", hop, splitErr)
+		}
+		port, err = strconv.Atoi(p)
+		if err != nil || port <= 0 || port > 65535 {
+			return "", "", 0, fmt.Errorf("jump host '%s' has an invalid port", hop)
+		}
+		host = h
+	}
+	if host == "" {
+		return "", "", 0, fmt.Errorf("jump host '%s' has no hostname", hop)
+	}
+	return username, host, port, nil
+}
+
+// validateProxyJump checks the hops of a server's proxy_jump. Whether a hop
+// names another server, and whether the chain loops, is checked by JumpChain.
+func validateProxyJump(name string, hops []string) error {
+	for _, hop := range hops {
+		switch {
+		case strings.TrimSpace(hop) == "":
+			return fmt.Errorf("proxy_jump hops must not be empty")
+		case strings.ContainsAny(hop, " \t,"):
+			return fmt.Errorf("proxy_jump hop '%s' must not contain spaces or commas; list each hop separately", hop)
+		case hop == name:
+			return fmt.Errorf("proxy_jump must not go through the server itself")
+		}
+		if _, _, _, err := ParseJumpHop(hop); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// JumpChain resolves the server's proxy_jump into the hosts connected through,
+// in order. Hops naming an sshm server, by name or alias, use its address,
+// user, port and authentication, and are themselves reached through that
+// server's own proxy_jump; other hops are taken as [user@]host[:port].
+func (c *Config) JumpChain(server Server) ([]Server, error) {
+	return c.jumpChain(server, map[string]bool{server.Name: true})
+}
+
+// jumpChain resolves a chain, visiting holding the servers already on it
+func (c *Config) jumpChain(server Server, visiting map[string]bool) ([]Server, error) {
+	var chain []Server
+	for _, hop := range server.ProxyJump {
+		jump, ok := c.jumpServer(hop)
+		if !ok {
+			literal, err := literalJumpHost(hop)
+			if err != nil {
+				return nil, err
+			}
+			chain = append(chain, literal)
+			continue
+		}
+		if visiting[jump.Name] {
+			return nil, fmt.Errorf("proxy_jump of '%s' loops back through '%s'", server.Name, jump.Name)
+		}
+		visiting[jump.Name] = true
+		before, err := c.jumpChain(jump, visiting)
+		delete(visiting, jump.Name)
+		if err != nil {
+			return nil, err
+		}
+		chain = append(append(chain, before...), jump)
+	}
+	return chain, nil
+}
+
+// jumpServer returns the server a hop names, if any
+func (c *Config) jumpServer(hop string) (Server, bool) {
+	for _, server := range c.Servers {
+		if server.MatchesName(hop) {
+			return server, true
+		}
+	}
+	return Server{}, false
+}
+
+// literalJumpHost describes a hop that isn't an sshm server. It has no
+// auth_type, so status checks try the agent and the default keys.
+func literalJumpHost(hop string) (Server, error) {
+	username, host, port, err := ParseJumpHop(hop)
+	if err != nil {
+		return Server{}, err
+	}
+	return Server{Name: hop, Hostname: host, Username: username, Port: port}, nil
+}
+
+// resolveProxyJumps stores every server's resolved jump chain, used by
+// GetSSHOptions and status checks, which only see the server. A chain that
+// can't be resolved falls back to the hops as written.
+func (c *Config) resolveProxyJumps() {
+	for i := range c.Servers {
+		c.Servers[i].jumpChain = nil
+		if len(c.Servers[i].ProxyJump) == 0 {
+			continue
+		}
+		if chain, err := c.JumpChain(c.Servers[i]); err == nil {
+			c.Servers[i].jumpChain = chain
+		}
+	}
+}
+
+// JumpHosts returns the hosts the server is reached through, in order
+func (s *Server) JumpHosts() []Server {
+	if s.jumpChain != nil || len(s.ProxyJump) == 0 {
+		return s.jumpChain
+	}
+	var chain []Server
+	for _, hop := range s.ProxyJump {
+		if literal, err := literalJumpHost(hop); err == nil {
+			chain = append(chain, literal)
+		}
+	}
+	return chain
+}
+
+// ProxyJumpSpec returns the value of ssh's -J option for the server, e.g.
+// "admin@bastion.example.com,10.0.0.5:2222", or "" without proxy_jump
+func (s *Server) ProxyJumpSpec() string {
+	hops := s.JumpHosts()
+	destinations := make([]string, 0, len(hops))
+	for _, hop := range hops {
+		destinations = append(destinations, jumpDestination(hop))
+	}
+	return strings.Join(destinations, ",")
+}
+
+// jumpDestination writes a hop as [user@]host[:port]
+func jumpDestination(hop Server) string {
+	host := hop.GetEffectiveHostname()
+	if hop.Port != 0 && hop.Port != 22 {
+		host = net.JoinHostPort(host, strconv.Itoa(hop.Port))
+	}
+	if hop.Username != "" {
+		return hop.Username + "@" + host
+	}
+	return host
+}
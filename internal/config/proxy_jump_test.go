@@ -0,0 +1,122 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseJumpHop(t *testing.T) {
+	tests := []struct {
+		hop      string
+		username string
+		host     string
+		port     int
+		wantErr  bool
+	}{
+		{hop: "bastion.example.com", host: "bastion.example.com"},
+		{hop: "admin@bastion.example.com", username: "admin", host: "bastion.example.com"},
+		{hop: "admin@10.0.1.5:2222", username: "admin", host: "10.0.1.5", port: 2222},
+		{hop: "[2001:db8::1]:2200", host: "2001:db8::1", port: 2200},
+		{hop: "[2001:db8::1]", host: "2001:db8::1"},
+		{hop: "@bastion", wantErr: true},
+		{hop: "bastion:0", wantErr: true},
+		{hop: "bastion:ssh", wantErr: true},
+		{hop: "admin@", wantErr: true},
+	}
+	for _, tt := range tests {
+		username, host, port, err := ParseJumpHop(tt.hop)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseJumpHop(%q) expected an error", tt.hop)
+			}
+			continue
+		}
+		if err != nil || username != tt.username || host != tt.host || port != tt.port {
+			t.Errorf("ParseJumpHop(%q) = %q, %q, %d, %v", tt.hop, username, host, port, err)
+		}
+	}
+}
+
+func TestServerValidateProxyJump(t *testing.T) {
+	server := Server{Name: "db", Hostname: "10.0.8.20", Port: 22, Username: "ops", AuthType: "password"}
+	for _, hops := range [][]string{{""}, {"bastion,inner"}, {"my bastion"}, {"db"}, {"bastion:99999"}} {
+		server.ProxyJump = hops
+		if err := server.Validate(); err == nil {
+			t.Errorf("Expected proxy_jump %q to be rejected", hops)
+		}
+	}
+	server.ProxyJump = []string{"bastion", "admin@10.0.1.5:2222"}
+	if err := server.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
+
+func TestJumpChainResolvesServers(t *testing.T) {
+	cfg := &Config{}
+	for _, server := range []Server{
+		{Name: "edge", Hostname: "edge.example.com", Port: 22, Username: "jump", AuthType: "password"},
+		{Name: "bastion", Hostname: "bastion.internal", ResolveTo: "10.0.0.2", Port: 2222, Username: "admin", AuthType: "password", ProxyJump: []string{"edge"}, Aliases: []string{"bh"}},
+		{Name: "db", Hostname: "10.0.8.20", Port: 22, Username: "ops", AuthType: "password", ProxyJump: []string{"bh", "inner.example.com"}},
+	} {
+		if err := cfg.AddServer(server); err != nil {
+			t.Fatalf("AddServer(%s) error = %v", server.Name, err)
+		}
+	}
+
+	db, _ := cfg.GetServer("db")
+	chain, err := cfg.JumpChain(*db)
+	if err != nil {
+		t.Fatalf("JumpChain() error = %v", err)
+	}
+	if got := serverNames(chain); got != "edge,bastion,inner.example.com" {
+		t.Errorf("Expected the bastion's own hop first, got %s", got)
+	}
+
+	if got := db.ProxyJumpSpec(); got != "jump@edge.example.com,admin@10.0.0.2:2222,inner.example.com" {
+		t.Errorf("ProxyJumpSpec() = %s", got)
+	}
+	if got := db.GetSSHOptions(); !strings.HasSuffix(got, " -J jump@edge.example.com,admin@10.0.0.2:2222,inner.example.com") {
+		t.Errorf("GetSSHOptions() = %q", got)
+	}
+
+	// Editing a jump host updates the servers behind it
+	edge, _ := cfg.GetServer("edge")
+	edge.Hostname = "edge2.example.com"
+	if err := cfg.UpdateServer(*edge); err != nil {
+		t.Fatalf("UpdateServer() error = %v", err)
+	}
+	db, _ = cfg.GetServer("db")
+	if got := db.ProxyJumpSpec(); !strings.HasPrefix(got, "jump@edge2.example.com,") {
+		t.Errorf("Expected the edited hop, got %s", got)
+	}
+}
+
+func TestJumpChainRejectsLoops(t *testing.T) {
+	cfg := &Config{}
+	for _, server := range []Server{
+		{Name: "a", Hostname: "a.example.com", Port: 22, Username: "ops", AuthType: "password"},
+		{Name: "b", Hostname: "b.example.com", Port: 22, Username: "ops", AuthType: "password", ProxyJump: []string{"a"}},
+	} {
+		if err := cfg.AddServer(server); err != nil {
+			t.Fatalf("AddServer(%s) error = %v", server.Name, err)
+		}
+	}
+
+	a, _ := cfg.GetServer("a")
+	a.ProxyJump = []string{"b"}
+	if err := cfg.UpdateServer(*a); err == nil || !strings.Contains(err.Error(), "loops") {
+		t.Errorf("Expected a loop error, got %v", err)
+	}
+}
+
+func TestJumpHostsWithoutConfig(t *testing.T) {
+	// A server not yet resolved against a configuration takes its hops as written
+	server := Server{Name: "db", ProxyJump: []string{"admin@bastion:2222"}}
+	hosts := server.JumpHosts()
+	if len(hosts) != 1 || hosts[0].Hostname != "bastion" || hosts[0].Username != "admin" || hosts[0].Port != 2222 {
+		t.Errorf("JumpHosts() = %+v", hosts)
+	}
+	if got := server.GetSSHOptions(); got != " -J admin@bastion:2222" {
+		t.Errorf("GetSSHOptions() = %q", got)
+	}
+}
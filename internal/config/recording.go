@@ -0,0 +1,32 @@
+package config
+
+import "path/filepath"
+
+// RecordingConfig controls where the sessions of servers with record set are
+// recorded, as asciinema casts
+type RecordingConfig struct {
+	Dir string `yaml:"dir,omitempty" json:"dir,omitempty"` // Default: ~/.sshm/recordings
+}
+
+// Path returns the recordings directory, expanded
+func (r *RecordingConfig) Path() (string, error) {
+	if r.Dir != "" {
+		return ExpandPath(r.Dir)
+	}
+	configPath, err := DefaultConfigPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(configPath), "recordings"), nil
+}
+
+// RecordedServers returns the names of the servers whose sessions are recorded
+func (c *Config) RecordedServers() []string {
+	var names []string
+	for _, server := range c.Servers {
+		if server.Record {
+			names = append(names, server.Name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,131 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// RemovalImpact describes what removing a server from the inventory affects
+type RemovalImpact struct {
+	Server          Server
+	Profiles        []string // Profiles the server is removed from
+	EmptiedProfiles []string // Profiles left without servers, deleted along with it
+	JumpDependents  []string // Servers connecting through it with proxy_jump
+	Sessions        []string // Open tmux sessions to it, filled in by the caller
+	RunningTunnels  int      // Tunnels running through it, filled in by the caller
+}
+
+// RemovalImpact returns what removing the named server affects
+func (c *Config) RemovalImpact(name string) (RemovalImpact, error) {
+	server, err := c.GetServer(name)
+	if err != nil {
+		return RemovalImpact{}, err
+	}
+
+	impact := RemovalImpact{Server: *server}
+	for _, profile := range c.Profiles {
+		if !contains(profile.Servers, server.Name) {
+			continue
+		}
+		impact.Profiles = append(impact.Profiles, profile.Name)
+		if len(removeString(profile.Servers, server.Name)) == 0 {
+			impact.EmptiedProfiles = append(impact.EmptiedProfiles, profile.Name)
+		}
+	}
+	for _, other := range c.Servers {
+		if other.Name != server.Name && contains(other.ProxyJump, server.Name) {
+			impact.JumpDependents = append(impact.JumpDependents, other.Name)
+		}
+	}
+	return impact, nil
+}
+
+// HasEffects reports whether removing the server affects anything beyond its
+// own entry
+func (i RemovalImpact) HasEffects() bool {
+	return len(i.Profiles) > 0 || len(i.JumpDependents) > 0 || len(i.Sessions) > 0 ||
+		len(i.Server.Tunnels) > 0 || i.RunningTunnels > 0
+}
+
+// Summary describes the effects of the removal, one per line
+func (i RemovalImpact) Summary() []string {
+	var lines []string
+	if len(i.Profiles) > 0 {
+		lines = append(lines, fmt.Sprintf("Removed from profiles: %s", strings.Join(i.Profiles, ", ")))
+	}
+	if len(i.EmptiedProfiles) > 0 {
+		lines = append(lines, fmt.Sprintf("Profiles left empty and deleted: %s", strings.Join(i.EmptiedProfiles, ", ")))
+	}
+	if len(i.JumpDependents) > 0 {
+		lines = append(lines, fmt.Sprintf("Jump host of: %s (they can't connect without it)", strings.Join(i.JumpDependents, ", ")))
+	}
+	if len(i.Sessions) > 0 {
+		lines = append(lines, fmt.Sprintf("Open sessions: %s (left running)", strings.Join(i.Sessions, ", ")))
+	}
+	if len(i.Server.Tunnels) > 0 {
+		var tunnels []string
+		for _, tunnel := range i.Server.Tunnels {
+			tunnels = append(tunnels, fmt.Sprintf("%s (%d → %s)", tunnel.Name, tunnel.LocalPort, tunnel.Destination()))
+		}
+		lines = append(lines, fmt.Sprintf("Tunnels configured: %s", strings.Join(tunnels, ", ")))
+	}
+	if i.RunningTunnels > 0 {
+		lines = append(lines, fmt.Sprintf("Running tunnels: %d (left running)", i.RunningTunnels))
+	}
+	return lines
+}
+
+// DeleteServer removes a server and its profile memberships, deleting the
+// profiles it leaves empty
+func (c *Config) DeleteServer(name string) error {
+	server, err := c.GetServer(name)
+	if err != nil {
+		return err
+	}
+	if err := c.RemoveServer(server.Name); err != nil {
+		return err
+	}
+
+	profiles := c.Profiles[:0]
+	for _, profile := range c.Profiles {
+		if !contains(profile.Servers, server.Name) {
+			profiles = append(profiles, profile)
+			continue
+		}
+		profile.Servers = removeString(profile.Servers, server.Name)
+		if len(profile.Servers) > 0 {
+			profiles = append(profiles, profile)
+		}
+	}
+	c.Profiles = profiles
+	return nil
+}
+
+// ArchiveServer moves a server out of the inventory and its profiles into the
+// archive, where 'sshm archive restore' brings it back. Profiles it leaves
+// empty are kept for the restore.
+func (c *Config) ArchiveServer(name string, now time.Time) error {
+	server, err := c.GetServer(name)
+	if err != nil {
+		return err
+	}
+	for _, entry := range c.Archived {
+		if entry.Name == server.Name {
+			return fmt.Errorf("the archive already holds a server named '%s'", server.Name)
+		}
+	}
+	if err := c.RemoveServer(server.Name); err != nil {
+		return err
+	}
+
+	entry := ArchivedServer{Server: *server, ExpiredAt: now, Removed: true}
+	for i := range c.Profiles {
+		if contains(c.Profiles[i].Servers, server.Name) {
+			entry.Profiles = append(entry.Profiles, c.Profiles[i].Name)
+			c.Profiles[i].Servers = removeString(c.Profiles[i].Servers, server.Name)
+		}
+	}
+	c.Archived = append(c.Archived, entry)
+	return nil
+}
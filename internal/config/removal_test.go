@@ -0,0 +1,101 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func removalTestConfig() *Config {
+	return &Config{
+		Servers: []Server{
+			{Name: "bastion", Hostname: "bastion.example.com", Port: 22, Username: "ops", AuthType: "key", KeyPath: "~/.ssh/id_ed25519",
+				Tunnels: []Tunnel{{Name: "grafana", LocalPort: 3000, RemotePort: 3000}}},
+			{Name: "db1", Hostname: "db1.internal", Port: 22, Username: "ops", AuthType: "key", KeyPath: "~/.ssh/id_ed25519", ProxyJump: []string{"bastion"}},
+			{Name: "web1", Hostname: "web1.internal", Port: 22, Username: "ops", AuthType: "key", KeyPath: "~/.ssh/id_ed25519"},
+		},
+		Profiles: []Profile{
+			{Name: "edge", Servers: []string{"bastion"}},
+			{Name: "prod", Servers: []string{"bastion", "db1"}},
+		},
+	}
+}
+
+func TestRemovalImpact(t *testing.T) {
+	cfg := removalTestConfig()
+
+	impact, err := cfg.RemovalImpact("bastion")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(impact.Profiles, ",") != "edge,prod" || strings.Join(impact.EmptiedProfiles, ",") != "edge" {
+		t.Errorf("Unexpected profiles %v, emptied %v", impact.Profiles, impact.EmptiedProfiles)
+	}
+	if strings.Join(impact.JumpDependents, ",") != "db1" {
+		t.Errorf("Expected db1 to depend on bastion, got %v", impact.JumpDependents)
+	}
+	if !impact.HasEffects() || len(impact.Summary()) != 4 {
+		t.Errorf("Expected four effects, got %q", impact.Summary())
+	}
+
+	impact, err = cfg.RemovalImpact("web1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if impact.HasEffects() || len(impact.Summary()) != 0 {
+		t.Errorf("Expected web1 to affect nothing else, got %q", impact.Summary())
+	}
+	impact.Sessions = []string{"web1"}
+	if !impact.HasEffects() {
+		t.Error("Expected an open session to count as an effect")
+	}
+
+	if _, err := cfg.RemovalImpact("missing"); err == nil {
+		t.Error("Expected an error for a missing server")
+	}
+}
+
+func TestDeleteServer(t *testing.T) {
+	cfg := removalTestConfig()
+
+	if err := cfg.DeleteServer("bastion"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cfg.GetServer("bastion"); err == nil {
+		t.Error("Expected bastion to be deleted")
+	}
+	if _, err := cfg.GetProfile("edge"); err == nil {
+		t.Error("Expected the emptied profile to be deleted")
+	}
+	if profile, err := cfg.GetProfile("prod"); err != nil || strings.Join(profile.Servers, ",") != "db1" {
+		t.Errorf("Expected prod to keep db1 only, got %v, %v", profile, err)
+	}
+}
+
+func TestArchiveServer(t *testing.T) {
+	cfg := removalTestConfig()
+	now := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+
+	if err := cfg.ArchiveServer("bastion", now); err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Archived) != 1 || !cfg.Archived[0].Removed || !cfg.Archived[0].ExpiredAt.Equal(now) {
+		t.Fatalf("Expected bastion in the archive, got %+v", cfg.Archived)
+	}
+	if strings.Join(cfg.Archived[0].Profiles, ",") != "edge,prod" {
+		t.Errorf("Expected the profile memberships to be kept, got %v", cfg.Archived[0].Profiles)
+	}
+	if _, err := cfg.GetProfile("edge"); err != nil {
+		t.Error("Expected the emptied profile to be kept for the restore")
+	}
+
+	if err := cfg.RestoreArchived("bastion", nil); err != nil {
+		t.Fatalf("RestoreArchived failed: %v", err)
+	}
+	if profile, _ := cfg.GetProfile("edge"); profile == nil || strings.Join(profile.Servers, ",") != "bastion" {
+		t.Errorf("Expected bastion back in edge, got %v", profile)
+	}
+	if err := cfg.ArchiveServer("missing", now); err == nil {
+		t.Error("Expected an error for a missing server")
+	}
+}
@@ -0,0 +1,65 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RestrictedAccess limits connections to a server to a forced command, such as a
+// menu script, instead of a login shell. It is meant for operators who should only
+// have limited access; sshm refuses to run anything else on the server.
+type RestrictedAccess struct {
+	Command string   `yaml:"command" json:"command"`                     // Run instead of a shell; {user}, {host} and {server} are substituted
+	Allowed []string `yaml:"allowed,omitempty" json:"allowed,omitempty"` // Other commands that may be chosen with 'sshm connect --command'
+}
+
+// Validate validates a restricted access configuration
+func (r *RestrictedAccess) Validate() error {
+	if strings.TrimSpace(r.Command) == "" {
+		return fmt.Errorf("restricted access requires a forced command")
+	}
+	for _, command := range r.Allowed {
+		if strings.TrimSpace(command) == "" {
+			return fmt.Errorf("allowed commands must not be empty")
+		}
+	}
+	return nil
+}
+
+// Commands returns the forced command followed by the allowed alternatives
+func (r *RestrictedAccess) Commands() []string {
+	return append([]string{r.Command}, r.Allowed...)
+}
+
+// IsRestricted reports whether connections are limited to a forced command
+func (s *Server) IsRestricted() bool {
+	return s.Restricted != nil
+}
+
+// WithRestrictedCommand returns a copy of server that runs choice instead of the
+// forced command. Only commands listed in the restricted configuration are accepted.
+func (s Server) WithRestrictedCommand(choice string) (Server, error) {
+	if s.Restricted == nil {
+		return s, fmt.Errorf("server '%s' is not restricted", s.Name)
+	}
+	for _, command := range s.Restricted.Commands() {
+		if command == choice {
+			s.Restricted = &RestrictedAccess{Command: choice}
+			return s, nil
+		}
+	}
+	return s, fmt.Errorf("command '%s' is not allowed on '%s' (allowed: %s)",
+		choice, s.Name, strings.Join(s.Restricted.Commands(), ", "))
+}
+
+// RestrictedCommand returns the forced command with {user}, {host} and {server} substituted
+func (s *Server) RestrictedCommand() string {
+	if s.Restricted == nil {
+		return ""
+	}
+	return strings.NewReplacer(
+		"{user}", s.Username,
+		"{host}", s.GetEffectiveHostname(),
+		"{server}", s.Name,
+	).Replace(s.Restricted.Command)
+}
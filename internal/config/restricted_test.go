@@ -0,0 +1,65 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRestrictedAccessValidate(t *testing.T) {
+	if err := (&RestrictedAccess{}).Validate(); err == nil {
+		t.Error("Expected error for missing forced command")
+	}
+	if err := (&RestrictedAccess{Command: "menu", Allowed: []string{" "}}).Validate(); err == nil {
+		t.Error("Expected error for empty allowed command")
+	}
+	if err := (&RestrictedAccess{Command: "menu"}).Validate(); err != nil {
+		t.Errorf("Expected valid restriction, got: %v", err)
+	}
+}
+
+func TestWrapSSHCommandRestricted(t *testing.T) {
+	server := Server{
+		Name:       "noc-gw",
+		Hostname:   "gw.example.com",
+		Username:   "noc",
+		Restricted: &RestrictedAccess{Command: "/usr/local/bin/menu {user}@{server}"},
+		Bootstrap:  &BootstrapConfig{Script: "/home/ops/setup.sh"},
+	}
+	sshCmd := "ssh -t noc@gw.example.com"
+
+	got := server.WrapSSHCommand("", sshCmd)
+	want := sshCmd + " -o ClearAllForwardings=yes '/usr/local/bin/menu noc@noc-gw'"
+	if got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+	if strings.Contains(got, "$SHELL") {
+		t.Error("Restricted servers must never get a bootstrap shell")
+	}
+}
+
+func TestWithRestrictedCommand(t *testing.T) {
+	server := Server{
+		Name:       "noc-gw",
+		Restricted: &RestrictedAccess{Command: "menu", Allowed: []string{"uptime"}},
+	}
+
+	chosen, err := server.WithRestrictedCommand("uptime")
+	if err != nil {
+		t.Fatalf("Expected allowed command to be accepted, got: %v", err)
+	}
+	if chosen.RestrictedCommand() != "uptime" {
+		t.Errorf("Expected 'uptime', got %q", chosen.RestrictedCommand())
+	}
+	if server.Restricted.Command != "menu" {
+		t.Error("Expected the original server to be left unchanged")
+	}
+
+	if _, err := server.WithRestrictedCommand("bash"); err == nil {
+		t.Error("Expected command outside the allowlist to be rejected")
+	}
+
+	unrestricted := Server{Name: "web"}
+	if _, err := unrestricted.WithRestrictedCommand("uptime"); err == nil {
+		t.Error("Expected error for unrestricted server")
+	}
+}
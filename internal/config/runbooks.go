@@ -0,0 +1,16 @@
+package config
+
+// RunbooksConfig points at a local directory of markdown runbooks. A runbook
+// describes the servers and profiles named by its file name, the directories
+// it is in, or the servers and profiles listed in its front matter.
+type RunbooksConfig struct {
+	Dir string `yaml:"dir,omitempty" json:"dir,omitempty"` // e.g. ~/ops/runbooks
+}
+
+// Path returns the runbooks directory, expanded, or "" when none is configured
+func (r *RunbooksConfig) Path() (string, error) {
+	if r.Dir == "" {
+		return "", nil
+	}
+	return ExpandPath(r.Dir)
+}
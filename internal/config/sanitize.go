@@ -0,0 +1,92 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// DefaultSanitizedServerFields are the server fields kept in sanitized exports
+// when the sanitize section doesn't list any
+var DefaultSanitizedServerFields = []string{"name", "hostname"}
+
+// DefaultSanitizedProfileFields are the profile fields kept in sanitized exports
+// when the sanitize section doesn't list any
+var DefaultSanitizedProfileFields = []string{"name", "servers"}
+
+// SanitizeConfig controls what sanitized exports keep, for inventories shared
+// with people who should not get access details. Fields are named as in
+// config.yaml; the server and profile name are always kept.
+type SanitizeConfig struct {
+	ServerFields  []string `yaml:"server_fields,omitempty" json:"server_fields,omitempty"`   // e.g. [hostname, tags, metadata] (default: name, hostname)
+	ProfileFields []string `yaml:"profile_fields,omitempty" json:"profile_fields,omitempty"` // e.g. [servers, description] (default: name, servers)
+}
+
+// Sanitized returns a copy of servers and profiles with only the fields the
+// rules keep, dropping usernames, ports, keys, passwords and everything else
+// that grants or describes access
+func (r SanitizeConfig) Sanitized(servers []Server, profiles []Profile) ([]Server, []Profile, error) {
+	serverFields := r.ServerFields
+	if len(serverFields) == 0 {
+		serverFields = DefaultSanitizedServerFields
+	}
+	profileFields := r.ProfileFields
+	if len(profileFields) == 0 {
+		profileFields = DefaultSanitizedProfileFields
+	}
+
+	sanitizedServers := make([]Server, 0, len(servers))
+	for _, server := range servers {
+		sanitized, err := keepFields(server, serverFields)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid sanitize server_fields: %w", err)
+		}
+		sanitizedServers = append(sanitizedServers, sanitized)
+	}
+	sanitizedProfiles := make([]Profile, 0, len(profiles))
+	for _, profile := range profiles {
+		sanitized, err := keepFields(profile, profileFields)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid sanitize profile_fields: %w", err)
+		}
+		sanitizedProfiles = append(sanitizedProfiles, sanitized)
+	}
+	return sanitizedServers, sanitizedProfiles, nil
+}
+
+// keepFields returns a zero T with the name and the listed fields copied from
+// value. Fields are matched by their yaml key.
+func keepFields[T any](value T, fields []string) (T, error) {
+	keep := map[string]bool{"name": true}
+	for _, field := range fields {
+		keep[strings.ToLower(strings.TrimSpace(field))] = true
+	}
+
+	var result T
+	source := reflect.ValueOf(value)
+	target := reflect.ValueOf(&result).Elem()
+	known := make(map[string]bool)
+	for i := 0; i < source.NumField(); i++ {
+		key, _, _ := strings.Cut(source.Type().Field(i).Tag.Get("yaml"), ",")
+		if key == "" || key == "-" {
+			continue
+		}
+		known[key] = true
+		if keep[key] {
+			target.Field(i).Set(source.Field(i))
+		}
+	}
+
+	for field := range keep {
+		if !known[field] {
+			names := make([]string, 0, len(known))
+			for name := range known {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			return result, fmt.Errorf("unknown field '%s' (known: %s)", field, strings.Join(names, ", "))
+		}
+	}
+	return result, nil
+}
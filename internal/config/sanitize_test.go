@@ -0,0 +1,65 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestSanitizedDefaults(t *testing.T) {
+	servers := []Server{{
+		Name: "web", Hostname: "web.example.com", Port: 2222, Username: "deploy", AuthType: "key",
+		KeyPath: "~/.ssh/id_ed25519", Password: "secret", Tags: []string{"prod"},
+		Metadata: ServerMetadata{Owner: "ops"},
+	}}
+	profiles := []Profile{{Name: "prod", Description: "Production, ask Bob", Servers: []string{"web"}, SSHBinary: "assh"}}
+
+	gotServers, gotProfiles, err := SanitizeConfig{}.Sanitized(servers, profiles)
+	if err != nil {
+		t.Fatalf("Sanitized() error = %v", err)
+	}
+	want := Server{Name: "web", Hostname: "web.example.com"}
+	if len(gotServers) != 1 || gotServers[0].Name != want.Name || gotServers[0].Hostname != want.Hostname ||
+		gotServers[0].Port != 0 || gotServers[0].Username != "" || gotServers[0].KeyPath != "" ||
+		gotServers[0].Password != "" || gotServers[0].Tags != nil || gotServers[0].Metadata.Owner != "" {
+		t.Errorf("unexpected sanitized server: %+v", gotServers)
+	}
+	if len(gotProfiles) != 1 || gotProfiles[0].Description != "" || gotProfiles[0].SSHBinary != "" || len(gotProfiles[0].Servers) != 1 {
+		t.Errorf("unexpected sanitized profile: %+v", gotProfiles)
+	}
+	if servers[0].Username != "deploy" {
+		t.Error("sanitizing must not modify the original servers")
+	}
+
+	data, err := yaml.Marshal(Config{Servers: gotServers, Profiles: gotProfiles})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, leaked := range []string{"deploy", "2222", "id_ed25519", "secret", "Bob", "assh"} {
+		if strings.Contains(string(data), leaked) {
+			t.Errorf("sanitized export leaks %q:\n%s", leaked, data)
+		}
+	}
+}
+
+func TestSanitizedConfiguredFields(t *testing.T) {
+	servers := []Server{{Name: "web", Hostname: "web.example.com", Username: "deploy", Tags: []string{"prod"}}}
+	profiles := []Profile{{Name: "prod", Description: "Production", Servers: []string{"web"}}}
+	rules := SanitizeConfig{ServerFields: []string{"tags"}, ProfileFields: []string{"Description"}}
+
+	gotServers, gotProfiles, err := rules.Sanitized(servers, profiles)
+	if err != nil {
+		t.Fatalf("Sanitized() error = %v", err)
+	}
+	if gotServers[0].Hostname != "" || len(gotServers[0].Tags) != 1 || gotServers[0].Username != "" {
+		t.Errorf("expected only name and tags, got %+v", gotServers[0])
+	}
+	if gotProfiles[0].Description != "Production" || gotProfiles[0].Servers != nil {
+		t.Errorf("expected only name and description, got %+v", gotProfiles[0])
+	}
+
+	if _, _, err := (SanitizeConfig{ServerFields: []string{"nickname"}}).Sanitized(servers, nil); err == nil || !strings.Contains(err.Error(), "nickname") {
+		t.Errorf("expected an error naming the unknown field, got %v", err)
+	}
+}
@@ -0,0 +1,60 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultSessionCommandDelay is how long after ssh starts the window commands
+// are typed, leaving time to log in
+const DefaultSessionCommandDelay = 2 * time.Second
+
+// SessionTemplate lays out the tmux session created when connecting to a
+// server: each window holds its own SSH connection and may then run a command,
+// e.g. a shell, `journalctl -f` and htop side by side.
+type SessionTemplate struct {
+	Windows []SessionWindow `yaml:"windows" json:"windows"`
+	Delay   string          `yaml:"delay,omitempty" json:"delay,omitempty"` // Wait after ssh starts before sending commands (default 2s)
+}
+
+// SessionWindow is one window of a session template
+type SessionWindow struct {
+	Name    string `yaml:"name" json:"name"`
+	Command string `yaml:"command,omitempty" json:"command,omitempty"` // Sent once connected; empty leaves a shell
+}
+
+// CommandDelay returns the wait before window commands are sent
+func (t *SessionTemplate) CommandDelay() time.Duration {
+	if delay, err := time.ParseDuration(strings.TrimSpace(t.Delay)); err == nil && delay >= 0 {
+		return delay
+	}
+	return DefaultSessionCommandDelay
+}
+
+// Validate checks that the template has uniquely named windows and a valid delay
+func (t *SessionTemplate) Validate() error {
+	if len(t.Windows) == 0 {
+		return fmt.Errorf("at least one window is required")
+	}
+	names := make(map[string]bool)
+	for i, window := range t.Windows {
+		name := strings.TrimSpace(window.Name)
+		if name == "" {
+			return fmt.Errorf("window %d needs a name", i+1)
+		}
+		if strings.ContainsAny(name, ".:") {
+			return fmt.Errorf("window name '%s' must not contain '.' or ':'", name)
+		}
+		if names[name] {
+			return fmt.Errorf("duplicate window name '%s'", name)
+		}
+		names[name] = true
+	}
+	if strings.TrimSpace(t.Delay) != "" {
+		if delay, err := time.ParseDuration(strings.TrimSpace(t.Delay)); err != nil || delay < 0 {
+			return fmt.Errorf("invalid delay '%s' (expected e.g. 2s)", t.Delay)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSessionTemplateValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		template SessionTemplate
+		errText  string
+	}{
+		{"valid", SessionTemplate{Windows: []SessionWindow{{Name: "shell"}, {Name: "logs", Command: "journalctl -f"}}, Delay: "3s"}, ""},
+		{"no windows", SessionTemplate{}, "at least one window"},
+		{"unnamed window", SessionTemplate{Windows: []SessionWindow{{Command: "htop"}}}, "needs a name"},
+		{"duplicate name", SessionTemplate{Windows: []SessionWindow{{Name: "logs"}, {Name: "logs"}}}, "duplicate window name"},
+		{"name with colon", SessionTemplate{Windows: []SessionWindow{{Name: "app:logs"}}}, "must not contain"},
+		{"bad delay", SessionTemplate{Windows: []SessionWindow{{Name: "shell"}}, Delay: "soon"}, "invalid delay"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.template.Validate()
+			if test.errText == "" && err != nil {
+				t.Errorf("Expected no error, got %v", err)
+			}
+			if test.errText != "" && (err == nil || !strings.Contains(err.Error(), test.errText)) {
+				t.Errorf("Expected error containing %q, got %v", test.errText, err)
+			}
+		})
+	}
+}
+
+func TestSessionTemplateCommandDelay(t *testing.T) {
+	if delay := (&SessionTemplate{}).CommandDelay(); delay != DefaultSessionCommandDelay {
+		t.Errorf("Expected the default delay, got %v", delay)
+	}
+	if delay := (&SessionTemplate{Delay: "500ms"}).CommandDelay(); delay != 500*time.Millisecond {
+		t.Errorf("Expected 500ms, got %v", delay)
+	}
+}
@@ -0,0 +1,206 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ConfDirName is the directory next to config.yaml holding split inventory files
+const ConfDirName = "conf.d"
+
+// unsafeFileChars matches characters replaced when deriving file names from profile names
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// inventoryFile is the content of a split inventory file under conf.d
+type inventoryFile struct {
+	Servers  []Server  `yaml:"servers,omitempty"`
+	Profiles []Profile `yaml:"profiles,omitempty"`
+}
+
+// ConfDir returns the split inventory directory for a config file path
+func ConfDir(configPath string) string {
+	return filepath.Join(filepath.Dir(configPath), ConfDirName)
+}
+
+// loadInventoryFiles merges the servers and profiles of every YAML file under
+// conf.d into the configuration, remembering which file each one came from
+func (c *Config) loadInventoryFiles() error {
+	dir := ConfDir(c.configPath)
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", dir, err)
+	}
+
+	for _, entry := range entries {
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if entry.IsDir() || (ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		var file inventoryFile
+		if err := yaml.Unmarshal(data, &file); err != nil {
+			return fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+
+		for _, server := range file.Servers {
+			if _, err := c.GetServer(server.Name); err == nil {
+				return fmt.Errorf("server '%s' in %s is already defined in %s", server.Name, path, c.SourceOfServer(server.Name))
+			}
+			c.Servers = append(c.Servers, server)
+			c.setServerSource(server.Name, path)
+		}
+		for _, profile := range file.Profiles {
+			if _, err := c.GetProfile(profile.Name); err == nil {
+				return fmt.Errorf("profile '%s' in %s is already defined in %s", profile.Name, path, c.SourceOfProfile(profile.Name))
+			}
+			c.Profiles = append(c.Profiles, profile)
+			c.setProfileSource(profile.Name, path)
+		}
+		c.inventoryFiles = append(c.inventoryFiles, path)
+	}
+	return nil
+}
+
+// SourceOfServer returns the file a server is stored in
+func (c *Config) SourceOfServer(name string) string {
+	if source := c.serverSources[name]; source != "" {
+		return source
+	}
+	// New servers are stored with the first split profile they belong to
+	for _, profile := range c.Profiles {
+		if source := c.profileSources[profile.Name]; source != "" && contains(profile.Servers, name) {
+			return source
+		}
+	}
+	return c.configPath
+}
+
+// SourceOfProfile returns the file a profile is stored in
+func (c *Config) SourceOfProfile(name string) string {
+	if source := c.profileSources[name]; source != "" {
+		return source
+	}
+	return c.configPath
+}
+
+// IsSplit reports whether part of the inventory lives in conf.d files
+func (c *Config) IsSplit() bool {
+	return len(c.inventoryFiles) > 0
+}
+
+// SplitByProfile moves every profile, with the servers stored alongside it, into
+// its own file under conf.d. Servers without a profile stay in config.yaml.
+// It returns the files the profiles were assigned to; call Save to write them.
+func (c *Config) SplitByProfile() ([]string, error) {
+	if c.Locked {
+		return nil, ErrLocked
+	}
+	dir := ConfDir(c.configPath)
+	var files []string
+	for _, profile := range c.Profiles {
+		if c.profileSources[profile.Name] != "" {
+			continue
+		}
+		name := strings.Trim(unsafeFileChars.ReplaceAllString(profile.Name, "-"), "-")
+		if name == "" {
+			name = "profile"
+		}
+		path := filepath.Join(dir, name+".yaml")
+		c.setProfileSource(profile.Name, path)
+		if !contains(c.inventoryFiles, path) {
+			c.inventoryFiles = append(c.inventoryFiles, path)
+		}
+		files = append(files, path)
+	}
+
+	// Pin servers to the file chosen now so later profile changes don't move them
+	for _, server := range c.Servers {
+		if source := c.SourceOfServer(server.Name); source != c.configPath {
+			c.setServerSource(server.Name, source)
+		}
+	}
+	return files, nil
+}
+
+// saveInventoryFiles writes servers and profiles stored in conf.d files back to
+// them and returns the configuration left for config.yaml
+func (c *Config) saveInventoryFiles() (Config, error) {
+	main := *c
+	main.Servers = []Server{}
+	main.Profiles = []Profile{}
+
+	files := make(map[string]*inventoryFile)
+	for _, path := range c.inventoryFiles {
+		files[path] = &inventoryFile{}
+	}
+
+	for _, server := range c.Servers {
+		if c.IsIncludedServer(server) {
+			continue
+		}
+		if file, ok := files[c.SourceOfServer(server.Name)]; ok {
+			file.Servers = append(file.Servers, server)
+		} else {
+			main.Servers = append(main.Servers, server)
+		}
+	}
+	for _, profile := range c.Profiles {
+		if c.IsIncludedProfile(profile) {
+			continue
+		}
+		if file, ok := files[c.SourceOfProfile(profile.Name)]; ok {
+			file.Profiles = append(file.Profiles, profile)
+		} else {
+			main.Profiles = append(main.Profiles, profile)
+		}
+	}
+
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			return main, fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+		}
+		data, err := yaml.Marshal(files[path])
+		if err != nil {
+			return main, fmt.Errorf("failed to marshal %s: %w", path, err)
+		}
+		if err := os.WriteFile(path, data, 0600); err != nil {
+			return main, fmt.Errorf("failed to write %s: %w", path, err)
+		}
+	}
+	return main, nil
+}
+
+// setServerSource records the file a server was loaded from
+func (c *Config) setServerSource(name, path string) {
+	if c.serverSources == nil {
+		c.serverSources = make(map[string]string)
+	}
+	c.serverSources[name] = path
+}
+
+// setProfileSource records the file a profile was loaded from
+func (c *Config) setProfileSource(name, path string) {
+	if c.profileSources == nil {
+		c.profileSources = make(map[string]string)
+	}
+	c.profileSources[name] = path
+}
@@ -0,0 +1,116 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readTestFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestSplitLayoutLoadAndSave(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.yaml")
+	prodPath := filepath.Join(dir, ConfDirName, "prod.yaml")
+
+	writeTestFile(t, configPath, "servers:\n  - name: local\n    hostname: localhost\n    port: 22\n    username: me\n    auth_type: key\n    key_path: ~/.ssh/id_rsa\n")
+	writeTestFile(t, prodPath, "servers:\n  - name: web1\n    hostname: web1.example.com\n    port: 22\n    username: deploy\n    auth_type: key\n    key_path: ~/.ssh/id_rsa\nprofiles:\n  - name: prod\n    servers: [web1]\n")
+
+	cfg, err := LoadFromPath(configPath)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(cfg.Servers) != 2 || len(cfg.Profiles) != 1 || !cfg.IsSplit() {
+		t.Fatalf("Expected merged inventory, got %d servers and %d profiles", len(cfg.Servers), len(cfg.Profiles))
+	}
+	if cfg.SourceOfServer("web1") != prodPath || cfg.SourceOfServer("local") != configPath {
+		t.Errorf("Unexpected sources: web1=%s local=%s", cfg.SourceOfServer("web1"), cfg.SourceOfServer("local"))
+	}
+
+	// A new server assigned to a split profile is written to that profile's file
+	if err := cfg.AddServer(Server{Name: "web2", Hostname: "web2.example.com", Port: 22, Username: "deploy", AuthType: "key", KeyPath: "~/.ssh/id_rsa"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.AssignServerToProfile("web2", "prod"); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Expected no error saving, got: %v", err)
+	}
+
+	if main := readTestFile(t, configPath); strings.Contains(main, "web1") || strings.Contains(main, "web2") || !strings.Contains(main, "local") {
+		t.Errorf("Unexpected config.yaml content:\n%s", main)
+	}
+	if prod := readTestFile(t, prodPath); !strings.Contains(prod, "web2") || !strings.Contains(prod, "prod") {
+		t.Errorf("Unexpected prod.yaml content:\n%s", prod)
+	}
+
+	reloaded, err := LoadFromPath(configPath)
+	if err != nil {
+		t.Fatalf("Expected no error reloading, got: %v", err)
+	}
+	if len(reloaded.Servers) != 3 {
+		t.Errorf("Expected 3 servers after reload, got %d", len(reloaded.Servers))
+	}
+}
+
+func TestSplitLayoutDuplicateServer(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.yaml")
+	writeTestFile(t, configPath, "servers:\n  - name: web1\n    hostname: a\n")
+	writeTestFile(t, filepath.Join(dir, ConfDirName, "team.yaml"), "servers:\n  - name: web1\n    hostname: b\n")
+
+	if _, err := LoadFromPath(configPath); err == nil {
+		t.Error("Expected error for a server defined in two files")
+	}
+}
+
+func TestSplitByProfile(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.yaml")
+	cfg, err := LoadFromPath(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.Servers = []Server{
+		{Name: "web1", Hostname: "h1", Port: 22, Username: "u", AuthType: "key", KeyPath: "~/.ssh/id_rsa"},
+		{Name: "loose", Hostname: "h2", Port: 22, Username: "u", AuthType: "key", KeyPath: "~/.ssh/id_rsa"},
+	}
+	cfg.Profiles = []Profile{{Name: "Prod EU", Servers: []string{"web1"}}}
+
+	files, err := cfg.SplitByProfile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 || filepath.Base(files[0]) != "Prod-EU.yaml" {
+		t.Fatalf("Unexpected split files: %v", files)
+	}
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	if content := readTestFile(t, files[0]); !strings.Contains(content, "web1") {
+		t.Errorf("Expected web1 in split file:\n%s", content)
+	}
+	if main := readTestFile(t, configPath); !strings.Contains(main, "loose") || strings.Contains(main, "web1") {
+		t.Errorf("Unexpected config.yaml content:\n%s", main)
+	}
+}
@@ -2,24 +2,141 @@ package config
 
 import (
 	"bufio"
+	"bytes"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 )
 
+// maxSSHConfigIncludeDepth stops Include loops, as ssh itself does
+const maxSSHConfigIncludeDepth = 16
+
+// SSHConfigFile holds the servers of an SSH config file and the files it
+// includes, with what is needed to group them into profiles
+type SSHConfigFile struct {
+	Path     string            // The top-level file
+	Servers  []Server
+	Sources  map[string]string // Server name -> file its Host block is in
+	Patterns []string          // Wildcard Host patterns, e.g. "*.prod.example.com"
+}
+
 // ParseSSHConfig parses an SSH config file and extracts server configurations
 func ParseSSHConfig(configPath string) ([]Server, error) {
+	file, err := ReadSSHConfig(configPath)
+	if err != nil {
+		return nil, err
+	}
+	return file.Servers, nil
+}
+
+// ReadSSHConfig reads an SSH config file, following its Include directives.
+// Relative includes are resolved against the file's directory, like ssh does
+// for ~/.ssh/config.
+func ReadSSHConfig(configPath string) (*SSHConfigFile, error) {
 	file, err := os.Open(configPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open SSH config file: %w", err)
 	}
 	defer file.Close()
 
-	var servers []Server
+	parser := newSSHConfigParser(configPath, filepath.Dir(configPath))
+	if err := parser.parse(file, configPath, 0); err != nil {
+		return nil, err
+	}
+	return parser.finish(), nil
+}
+
+// ParseSSHConfigContent parses SSH config content that was read from path,
+// following its Include directives relative to the path's directory
+func ParseSSHConfigContent(content []byte, path string) (*SSHConfigFile, error) {
+	parser := newSSHConfigParser(path, filepath.Dir(path))
+	if err := parser.parse(bytes.NewReader(content), path, 0); err != nil {
+		return nil, err
+	}
+	return parser.finish(), nil
+}
+
+// ParseSSHConfigReader extracts server configurations from SSH config content.
+// Include directives are ignored, having no file to resolve them against.
+func ParseSSHConfigReader(r io.Reader) ([]Server, error) {
+	parser := newSSHConfigParser("", "")
+	if err := parser.parse(r, "", 0); err != nil {
+		return nil, err
+	}
+	return parser.finish().Servers, nil
+}
+
+// sshConfigParser collects the hosts of an SSH config file and its includes
+type sshConfigParser struct {
+	file    *SSHConfigFile
+	baseDir string // Relative includes resolve against it; empty ignores Include
+}
+
+func newSSHConfigParser(path, baseDir string) *sshConfigParser {
+	return &sshConfigParser{file: &SSHConfigFile{Path: path, Sources: make(map[string]string)}, baseDir: baseDir}
+}
+
+// add records a complete host read from source
+func (p *sshConfigParser) add(server *Server, source string) {
+	if server == nil || !isValidServer(server) {
+		return
+	}
+	p.file.Servers = append(p.file.Servers, *server)
+	if source != "" {
+		p.file.Sources[server.Name] = source
+	}
+}
+
+// include parses the files matching an Include argument
+func (p *sshConfigParser) include(pattern string, depth int) error {
+	if p.baseDir == "" {
+		return nil
+	}
+	if depth >= maxSSHConfigIncludeDepth {
+		return fmt.Errorf("SSH config includes are nested more than %d levels deep", maxSSHConfigIncludeDepth)
+	}
+	pattern, err := ExpandPath(pattern)
+	if err != nil {
+		return err
+	}
+	if !filepath.IsAbs(pattern) {
+		pattern = filepath.Join(p.baseDir, pattern)
+	}
+	matches, _ := filepath.Glob(pattern)
+	sort.Strings(matches)
+	for _, match := range matches {
+		file, err := os.Open(match)
+		if err != nil {
+			continue // Like ssh, skip includes that can't be read
+		}
+		err = p.parse(file, match, depth+1)
+		file.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// finish sets the default auth type of servers without identity files
+func (p *sshConfigParser) finish() *SSHConfigFile {
+	for i := range p.file.Servers {
+		if p.file.Servers[i].AuthType == "" {
+			p.file.Servers[i].AuthType = "password"
+		}
+	}
+	return p.file
+}
+
+// parse reads the Host blocks of one file; includes are parsed where they appear
+func (p *sshConfigParser) parse(r io.Reader, source string, depth int) error {
 	var currentHost *Server
 	
-	scanner := bufio.NewScanner(file)
+	scanner := bufio.NewScanner(r)
 	for scanner.Scan() {
 		line := strings.TrimSpace(scanner.Text())
 		
@@ -40,12 +157,15 @@ func ParseSSHConfig(configPath string) ([]Server, error) {
 		switch keyword {
 		case "host":
 			// Save previous host if it was complete
-			if currentHost != nil && isValidServer(currentHost) {
-				servers = append(servers, *currentHost)
-			}
+			p.add(currentHost, source)
 			
-			// Skip wildcard hosts
+			// Skip wildcard hosts, remembering their patterns for grouping
 			if strings.Contains(value, "*") || strings.Contains(value, "?") {
+				for _, pattern := range parts[1:] {
+					if pattern != "*" && !strings.HasPrefix(pattern, "!") && strings.ContainsAny(pattern, "*?") {
+						p.file.Patterns = append(p.file.Patterns, pattern)
+					}
+				}
 				currentHost = nil
 				continue
 			}
@@ -78,26 +198,32 @@ func ParseSSHConfig(configPath string) ([]Server, error) {
 				currentHost.KeyPath = value
 				currentHost.AuthType = "key"
 			}
+			
+		case "proxyjump":
+			if currentHost != nil && !strings.EqualFold(value, "none") {
+				currentHost.ProxyJump = nil
+				for _, hop := range strings.Split(value, ",") {
+					currentHost.ProxyJump = append(currentHost.ProxyJump, strings.TrimPrefix(hop, "ssh://"))
+				}
+			}
+			
+		case "include":
+			for _, pattern := range parts[1:] {
+				if err := p.include(pattern, depth); err != nil {
+					return err
+				}
+			}
 		}
 	}
 	
 	// Don't forget the last host
-	if currentHost != nil && isValidServer(currentHost) {
-		servers = append(servers, *currentHost)
-	}
+	p.add(currentHost, source)
 	
 	if err := scanner.Err(); err != nil {
-		return nil, fmt.Errorf("error reading SSH config file: %w", err)
-	}
-	
-	// Set default auth type for servers without identity files
-	for i := range servers {
-		if servers[i].AuthType == "" {
-			servers[i].AuthType = "password"
-		}
+		return fmt.Errorf("error reading SSH config file: %w", err)
 	}
 	
-	return servers, nil
+	return nil
 }
 
 // isValidServer checks if a server configuration has the minimum required fields
@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -274,4 +275,34 @@ func TestParseSSHConfigErrors(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+func TestParseSSHConfigContent(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "extra"), []byte("Host extra\n    HostName extra.example.com\n    User ops\n"), 0600)
+
+	content := []byte("Include extra\n\nHost main\n    HostName main.example.com\n    User root\n")
+	file, err := ParseSSHConfigContent(content, filepath.Join(dir, "config"))
+	if err != nil {
+		t.Fatalf("ParseSSHConfigContent failed: %v", err)
+	}
+	if len(file.Servers) != 2 || file.Sources["extra"] != filepath.Join(dir, "extra") {
+		t.Errorf("Expected the included host with its source, got %+v", file)
+	}
+}
+
+func TestParseSSHConfigProxyJump(t *testing.T) {
+	content := []byte("Host db\n    HostName 10.0.8.20\n    User ops\n    ProxyJump bastion,ssh://admin@10.0.1.5:2222\n\nHost direct\n    HostName direct.example.com\n    User ops\n    ProxyJump none\n")
+	file, err := ParseSSHConfigContent(content, filepath.Join(t.TempDir(), "config"))
+	if err != nil {
+		t.Fatalf("ParseSSHConfigContent failed: %v", err)
+	}
+	if len(file.Servers) != 2 {
+		t.Fatalf("Expected two hosts, got %+v", file.Servers)
+	}
+	if got := strings.Join(file.Servers[0].ProxyJump, " "); got != "bastion admin@10.0.1.5:2222" {
+		t.Errorf("Expected both hops, got %q", got)
+	}
+	if file.Servers[1].ProxyJump != nil {
+		t.Errorf("Expected ProxyJump none to mean no hops, got %v", file.Servers[1].ProxyJump)
+	}
+}
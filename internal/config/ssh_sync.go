@@ -0,0 +1,40 @@
+package config
+
+import "fmt"
+
+// Which side wins when a host was edited both in sshm and in the SSH config
+const (
+	SSHSyncPreferNone = ""     // Report the conflict and leave both sides alone
+	SSHSyncPreferSSHM = "sshm" // sshm's values are written to the SSH config
+	SSHSyncPreferSSH  = "ssh"  // The SSH config's values are taken into sshm
+)
+
+// SSHSyncConfig keeps the servers in sync with an OpenSSH config file: new Host
+// blocks are imported and servers are written to a managed section of the file
+type SSHSyncConfig struct {
+	Enabled bool   `yaml:"enabled,omitempty" json:"enabled,omitempty"` // Sync when the TUI starts and on every daemon round
+	Path    string `yaml:"path,omitempty" json:"path,omitempty"`       // SSH config file (default: ~/.ssh/config)
+	Prefer  string `yaml:"prefer,omitempty" json:"prefer,omitempty"`   // Conflict resolution: sshm, ssh or empty to report conflicts
+}
+
+// ConfigPath returns the SSH config file to sync with, expanded
+func (s *SSHSyncConfig) ConfigPath() (string, error) {
+	if s.Path == "" {
+		return DefaultSSHConfigPath()
+	}
+	return ExpandPath(s.Path)
+}
+
+// Validate checks the conflict resolution
+func (s *SSHSyncConfig) Validate() error {
+	return ValidateSSHSyncPrefer(s.Prefer)
+}
+
+// ValidateSSHSyncPrefer checks a conflict resolution name
+func ValidateSSHSyncPrefer(prefer string) error {
+	switch prefer {
+	case SSHSyncPreferNone, SSHSyncPreferSSHM, SSHSyncPreferSSH:
+		return nil
+	}
+	return fmt.Errorf("unknown conflict resolution '%s' (use sshm or ssh)", prefer)
+}
@@ -0,0 +1,130 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"sshm/internal/shellquote"
+)
+
+// DefaultSSHTemplate lays out the command of servers that only override the ssh binary
+const DefaultSSHTemplate = "{ssh} -t {user}@{host} {options}"
+
+// SSHTemplatePlaceholders are the placeholders substituted in ssh_template
+var SSHTemplatePlaceholders = []string{"ssh", "user", "host", "port", "key", "name", "options"}
+
+// templatePlaceholder matches a {placeholder} in an ssh_template
+var templatePlaceholder = regexp.MustCompile(`\{([^{}]*)\}`)
+
+// ValidateSSHTemplate checks that a command template uses known placeholders
+// only and names the server through {host} or {name}
+func ValidateSSHTemplate(template string) error {
+	if strings.TrimSpace(template) == "" {
+		return nil
+	}
+	if strings.Count(template, "{") != strings.Count(template, "}") {
+		return fmt.Errorf("unbalanced braces in ssh_template '%s'", template)
+	}
+
+	targetsServer := false
+	for _, match := range templatePlaceholder.FindAllStringSubmatch(template, -1) {
+		if !contains(SSHTemplatePlaceholders, match[1]) {
+			return fmt.Errorf("unknown placeholder {%s} in ssh_template (supported: {%s})",
+				match[1], strings.Join(SSHTemplatePlaceholders, "}, {"))
+		}
+		if match[1] == "host" || match[1] == "name" {
+			targetsServer = true
+		}
+	}
+	if !targetsServer {
+		return fmt.Errorf("ssh_template must contain {host} or {name}")
+	}
+	return nil
+}
+
+// validateSSHBinary checks an ssh_binary override, which may include arguments
+func validateSSHBinary(binary string) error {
+	if binary != "" && strings.TrimSpace(binary) == "" {
+		return fmt.Errorf("ssh_binary must not be blank")
+	}
+	if strings.ContainsAny(binary, "{}") {
+		return fmt.Errorf("ssh_binary must not contain placeholders; use ssh_template")
+	}
+	return nil
+}
+
+// HasSSHTemplate reports whether the server overrides the ssh binary or command
+func (s *Server) HasSSHTemplate() bool {
+	return strings.TrimSpace(s.SSHBinary) != "" || strings.TrimSpace(s.SSHTemplate) != ""
+}
+
+// ApplySSHTemplate rewrites a built "ssh -t user@host <options>" command with
+// the server's ssh binary and command template. The command is returned
+// unchanged when the server overrides neither.
+func (s *Server) ApplySSHTemplate(sshCmd string) string {
+	if !s.HasSSHTemplate() {
+		return sshCmd
+	}
+	target, ok := strings.CutPrefix(sshCmd, "ssh -t ")
+	if !ok {
+		return sshCmd
+	}
+	_, options, _ := strings.Cut(target, " ")
+
+	template := strings.TrimSpace(s.SSHTemplate)
+	if template == "" {
+		template = DefaultSSHTemplate
+	}
+	binary := strings.TrimSpace(s.SSHBinary)
+	if binary == "" {
+		binary = "ssh"
+	}
+	keyPath, err := ExpandPath(s.KeyPath)
+	if err != nil {
+		keyPath = s.KeyPath
+	}
+	// Server fields are quoted; the binary and options are command lines themselves
+	values := map[string]string{
+		"ssh":     binary,
+		"user":    shellquote.Arg(s.Username),
+		"host":    shellquote.Arg(s.GetEffectiveHostname()),
+		"port":    strconv.Itoa(s.Port),
+		"key":     shellquote.Arg(keyPath),
+		"name":    shellquote.Arg(s.Name),
+		"options": strings.TrimSpace(options),
+	}
+	rendered := templatePlaceholder.ReplaceAllStringFunc(template, func(placeholder string) string {
+		if value, ok := values[placeholder[1:len(placeholder)-1]]; ok {
+			return value
+		}
+		return placeholder
+	})
+	return strings.TrimSpace(rendered)
+}
+
+// WithSSHTemplate returns a copy of server that inherits the profile's ssh
+// binary and command template when the server overrides neither
+func (p *Profile) WithSSHTemplate(server Server) Server {
+	if !server.HasSSHTemplate() {
+		server.SSHBinary = p.SSHBinary
+		server.SSHTemplate = p.SSHTemplate
+	}
+	return server
+}
+
+// WithSSHTemplate returns a copy of server that inherits the ssh binary and
+// command template of the first of its profiles defining one, for connections
+// made to the server on its own
+func (c *Config) WithSSHTemplate(server Server) Server {
+	if server.HasSSHTemplate() {
+		return server
+	}
+	for _, profile := range c.Profiles {
+		if contains(profile.Servers, server.Name) && (profile.SSHBinary != "" || profile.SSHTemplate != "") {
+			return profile.WithSSHTemplate(server)
+		}
+	}
+	return server
+}
@@ -0,0 +1,117 @@
+package config
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func templateTestServer() Server {
+	return Server{Name: "web", Hostname: "web.example.com", Port: 2222, Username: "deploy", AuthType: "key", KeyPath: "~/.ssh/id_rsa"}
+}
+
+func TestApplySSHTemplate(t *testing.T) {
+	base := "ssh -t deploy@web.example.com -p 2222 -i ~/.ssh/id_rsa -o ServerAliveInterval=60"
+
+	server := templateTestServer()
+	if got := server.ApplySSHTemplate(base); got != base {
+		t.Errorf("Expected the command unchanged without overrides, got %q", got)
+	}
+
+	server.SSHBinary = "assh wrapper ssh"
+	want := "assh wrapper ssh -t deploy@web.example.com -p 2222 -i ~/.ssh/id_rsa -o ServerAliveInterval=60"
+	if got := server.ApplySSHTemplate(base); got != want {
+		t.Errorf("Binary override:\n got %q\nwant %q", got, want)
+	}
+
+	server.SSHTemplate = "corp-ssh --target {name} --as {user} --port {port} -- {ssh} {host}"
+	want = "corp-ssh --target web --as deploy --port 2222 -- assh wrapper ssh web.example.com"
+	if got := server.ApplySSHTemplate(base); got != want {
+		t.Errorf("Template:\n got %q\nwant %q", got, want)
+	}
+
+	// An sshpass prefix stays in front of the rendered template
+	if got := server.WrapSSHCommand("sshpass -e", base); !strings.HasPrefix(got, "sshpass -e corp-ssh ") {
+		t.Errorf("Expected the sshpass prefix to be kept, got %q", got)
+	}
+}
+
+func TestApplySSHTemplateQuotesServerFields(t *testing.T) {
+	server := templateTestServer()
+	server.Name = "web; touch /tmp/pwned"
+	server.Username = "$(id)"
+	server.SSHTemplate = "{ssh} -i {key} {user}@{host} # {name}"
+
+	home, err := ExpandPath("~")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "ssh -i " + filepath.Join(home, ".ssh/id_rsa") + " '$(id)'@web.example.com # 'web; touch /tmp/pwned'"
+	if got := server.ApplySSHTemplate("ssh -t deploy@web.example.com"); got != want {
+		t.Errorf("Quoted template:\n got %q\nwant %q", got, want)
+	}
+}
+
+func TestWrapSSHCommandUsesTemplate(t *testing.T) {
+	server := templateTestServer()
+	server.SSHBinary = "/opt/bin/ssh"
+	server.Restricted = &RestrictedAccess{Command: "uptime"}
+
+	got := server.WrapSSHCommand("", "ssh -t deploy@web.example.com -p 2222")
+	if !strings.HasPrefix(got, "/opt/bin/ssh -t deploy@web.example.com -p 2222 -o ClearAllForwardings=yes") {
+		t.Errorf("Expected the restricted command after the custom binary, got %q", got)
+	}
+}
+
+func TestValidateSSHTemplate(t *testing.T) {
+	valid := []string{"", "{ssh} -t {user}@{host} {options}", "cloudflared access ssh --hostname {name}"}
+	for _, template := range valid {
+		if err := ValidateSSHTemplate(template); err != nil {
+			t.Errorf("Expected %q to be valid, got: %v", template, err)
+		}
+	}
+
+	invalid := []string{"{ssh} {usr}@{host}", "{ssh} {user}@{host", "{ssh} -t {user}@localhost"}
+	for _, template := range invalid {
+		if err := ValidateSSHTemplate(template); err == nil {
+			t.Errorf("Expected %q to be rejected", template)
+		}
+	}
+
+	server := templateTestServer()
+	server.SSHTemplate = "{ssh} {hostname}"
+	if err := server.Validate(); err == nil {
+		t.Error("Expected server validation to reject an unknown placeholder")
+	}
+	server.SSHTemplate = ""
+	server.SSHBinary = "ssh-{port}"
+	if err := server.Validate(); err == nil {
+		t.Error("Expected server validation to reject placeholders in ssh_binary")
+	}
+
+	profile := Profile{Name: "prod", SSHTemplate: "{ssh}"}
+	if err := profile.Validate(); err == nil {
+		t.Error("Expected profile validation to reject a template without the server")
+	}
+}
+
+func TestWithSSHTemplateInheritance(t *testing.T) {
+	cfg := &Config{
+		Servers: []Server{templateTestServer()},
+		Profiles: []Profile{
+			{Name: "plain", Servers: []string{"web"}},
+			{Name: "edge", Servers: []string{"web"}, SSHBinary: "assh wrapper ssh"},
+		},
+	}
+
+	inherited := cfg.WithSSHTemplate(cfg.Servers[0])
+	if inherited.SSHBinary != "assh wrapper ssh" {
+		t.Errorf("Expected the binary of the first profile defining one, got %q", inherited.SSHBinary)
+	}
+
+	own := templateTestServer()
+	own.SSHTemplate = "{ssh} {host}"
+	if kept := cfg.WithSSHTemplate(own); kept.SSHBinary != "" || kept.SSHTemplate != "{ssh} {host}" {
+		t.Errorf("Expected the server's own template to win, got %+v", kept)
+	}
+}
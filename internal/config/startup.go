@@ -0,0 +1,98 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"sshm/internal/shellquote"
+)
+
+// envNamePattern matches names a POSIX shell accepts for exported variables
+var envNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// StartupInput returns the lines typed into a new session window once the
+// server is connected: an export of remote_env, then the startup_commands.
+// Restricted servers get none, since they never reach a shell.
+func (s *Server) StartupInput() []string {
+	if s.IsRestricted() {
+		return nil
+	}
+	var lines []string
+	if len(s.RemoteEnv) > 0 {
+		assignments := make([]string, 0, len(s.RemoteEnv))
+		for _, name := range sortedEnvNames(s.RemoteEnv) {
+			assignments = append(assignments, name+"="+shellquote.Quote(s.RemoteEnv[name]))
+		}
+		lines = append(lines, "export "+strings.Join(assignments, " "))
+	}
+	for _, command := range s.StartupCommands {
+		if command = strings.TrimSpace(command); command != "" {
+			lines = append(lines, command)
+		}
+	}
+	return lines
+}
+
+// validateStartup checks startup commands and remote environment variables,
+// each typed into the session as a single line
+func validateStartup(commands []string, env map[string]string) error {
+	for _, command := range commands {
+		if strings.TrimSpace(command) == "" {
+			return fmt.Errorf("startup_commands must not be empty")
+		}
+		if strings.ContainsAny(command, "\r\n") {
+			return fmt.Errorf("startup command '%s' must be a single line", strings.TrimSpace(command))
+		}
+	}
+	for name, value := range env {
+		if !envNamePattern.MatchString(name) {
+			return fmt.Errorf("remote_env name '%s' must be letters, digits and underscores, not starting with a digit", name)
+		}
+		if strings.ContainsAny(value, "\r\n") {
+			return fmt.Errorf("remote_env value of '%s' must be a single line", name)
+		}
+	}
+	return nil
+}
+
+// ParseRemoteEnv reads NAME=value entries, e.g. from --env flags or the
+// server form, skipping blank ones
+func ParseRemoteEnv(entries []string) (map[string]string, error) {
+	env := make(map[string]string)
+	for _, entry := range entries {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(entry, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("environment variable '%s' must be written NAME=value", strings.TrimSpace(entry))
+		}
+		env[name] = value
+	}
+	if len(env) == 0 {
+		return nil, nil
+	}
+	return env, nil
+}
+
+// FormatRemoteEnv writes the environment as NAME=value entries sorted by name
+func FormatRemoteEnv(env map[string]string) []string {
+	entries := make([]string, 0, len(env))
+	for _, name := range sortedEnvNames(env) {
+		entries = append(entries, name+"="+env[name])
+	}
+	return entries
+}
+
+// sortedEnvNames returns the variable names in a stable order
+func sortedEnvNames(env map[string]string) []string {
+	names := make([]string, 0, len(env))
+	for name := range env {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,70 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStartupInput(t *testing.T) {
+	server := Server{
+		Name:            "app",
+		StartupCommands: []string{"cd /srv/app && sudo -i", "  "},
+		RemoteEnv:       map[string]string{"RAILS_ENV": "production", "GREETING": "it's me"},
+	}
+	got := server.StartupInput()
+	expected := []string{
+		`export GREETING='it'\''s me' RAILS_ENV='production'`,
+		"cd /srv/app && sudo -i",
+	}
+	if strings.Join(got, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("StartupInput() = %q, want %q", got, expected)
+	}
+
+	if input := (&Server{Name: "plain"}).StartupInput(); input != nil {
+		t.Errorf("Expected no input without settings, got %q", input)
+	}
+	server.Restricted = &RestrictedAccess{Command: "backup"}
+	if input := server.StartupInput(); input != nil {
+		t.Errorf("Expected no input for a restricted server, got %q", input)
+	}
+}
+
+func TestValidateStartup(t *testing.T) {
+	server := Server{Name: "app", Hostname: "app.example.com", Port: 22, Username: "ops", AuthType: "password"}
+	invalid := []Server{
+		{StartupCommands: []string{""}},
+		{StartupCommands: []string{"echo one\necho two"}},
+		{RemoteEnv: map[string]string{"1ABC": "x"}},
+		{RemoteEnv: map[string]string{"MY-VAR": "x"}},
+		{RemoteEnv: map[string]string{"A": "multi\nline"}},
+	}
+	for _, settings := range invalid {
+		candidate := server
+		candidate.StartupCommands, candidate.RemoteEnv = settings.StartupCommands, settings.RemoteEnv
+		if err := candidate.Validate(); err == nil {
+			t.Errorf("Expected %q / %v to be rejected", settings.StartupCommands, settings.RemoteEnv)
+		}
+	}
+
+	server.StartupCommands = []string{"cd /srv/app"}
+	server.RemoteEnv = map[string]string{"_PATH2": "/opt/bin"}
+	if err := server.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
+
+func TestParseRemoteEnv(t *testing.T) {
+	env, err := ParseRemoteEnv([]string{"A=1", "", "URL=https://x?a=b"})
+	if err != nil || env["A"] != "1" || env["URL"] != "https://x?a=b" || len(env) != 2 {
+		t.Errorf("ParseRemoteEnv() = %v, %v", env, err)
+	}
+	if got := strings.Join(FormatRemoteEnv(env), " "); got != "A=1 URL=https://x?a=b" {
+		t.Errorf("FormatRemoteEnv() = %q", got)
+	}
+	if _, err := ParseRemoteEnv([]string{"novalue"}); err == nil {
+		t.Error("Expected an entry without '=' to be rejected")
+	}
+	if env, err := ParseRemoteEnv(nil); env != nil || err != nil {
+		t.Errorf("Expected no environment, got %v, %v", env, err)
+	}
+}
@@ -0,0 +1,48 @@
+package config
+
+import "time"
+
+// Defaults for background status checks
+const (
+	DefaultCheckJitter   = 0.2
+	DefaultStealthWindow = 15 * time.Minute
+)
+
+// StatusCheckConfig controls how background status checks are spread out, so a
+// large inventory isn't swept in synchronized bursts that look like a port scan
+type StatusCheckConfig struct {
+	Jitter       float64 `yaml:"jitter,omitempty" json:"jitter,omitempty"`                 // Random spread of each server's check interval as a fraction (0-1, default: 0.2)
+	MaxPerMinute int     `yaml:"max_per_minute,omitempty" json:"max_per_minute,omitempty"` // Upper bound on checks started per minute (0 = unlimited)
+}
+
+// JitterFraction returns the configured jitter, or the default when unset or out of range
+func (s *StatusCheckConfig) JitterFraction() float64 {
+	if s.Jitter <= 0 || s.Jitter > 1 {
+		return DefaultCheckJitter
+	}
+	return s.Jitter
+}
+
+// StealthWindowDuration returns the average time between stealth checks of the
+// profile's servers
+func (p *Profile) StealthWindowDuration() time.Duration {
+	if d, err := time.ParseDuration(p.StealthWindow); err == nil && d > 0 {
+		return d
+	}
+	return DefaultStealthWindow
+}
+
+// StealthWindowFor reports whether a server belongs to a stealth profile and, if
+// so, the longest window of those profiles
+func (c *Config) StealthWindowFor(serverName string) (time.Duration, bool) {
+	var window time.Duration
+	for i := range c.Profiles {
+		profile := &c.Profiles[i]
+		if profile.Stealth && contains(profile.Servers, serverName) {
+			if d := profile.StealthWindowDuration(); d > window {
+				window = d
+			}
+		}
+	}
+	return window, window > 0
+}
@@ -0,0 +1,51 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStatusCheckJitterFraction(t *testing.T) {
+	for _, tc := range []struct {
+		jitter float64
+		want   float64
+	}{
+		{0, DefaultCheckJitter},
+		{1.5, DefaultCheckJitter},
+		{0.5, 0.5},
+	} {
+		s := StatusCheckConfig{Jitter: tc.jitter}
+		if got := s.JitterFraction(); got != tc.want {
+			t.Errorf("JitterFraction(%v) = %v, want %v", tc.jitter, got, tc.want)
+		}
+	}
+}
+
+func TestStealthWindowFor(t *testing.T) {
+	cfg := &Config{Profiles: []Profile{
+		{Name: "dmz", Servers: []string{"edge"}, Stealth: true},
+		{Name: "audit", Servers: []string{"edge"}, Stealth: true, StealthWindow: "1h"},
+		{Name: "prod", Servers: []string{"web"}},
+	}}
+
+	if window, ok := cfg.StealthWindowFor("edge"); !ok || window != time.Hour {
+		t.Errorf("Expected the longest stealth window, got %v, %v", window, ok)
+	}
+	if _, ok := cfg.StealthWindowFor("web"); ok {
+		t.Error("Expected web not to be stealth")
+	}
+	if window := cfg.Profiles[0].StealthWindowDuration(); window != DefaultStealthWindow {
+		t.Errorf("Expected the default window, got %v", window)
+	}
+}
+
+func TestProfileValidateStealthWindow(t *testing.T) {
+	profile := Profile{Name: "dmz", Stealth: true, StealthWindow: "soon"}
+	if err := profile.Validate(); err == nil {
+		t.Error("Expected an invalid stealth window to be rejected")
+	}
+	profile.StealthWindow = "30m"
+	if err := profile.Validate(); err != nil {
+		t.Errorf("Expected a valid stealth window, got %v", err)
+	}
+}
@@ -0,0 +1,59 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Location returns the server's configured time zone, or nil when none is set
+func (s *Server) Location() (*time.Location, error) {
+	zone := strings.TrimSpace(s.Timezone)
+	if zone == "" {
+		return nil, nil
+	}
+	location, err := time.LoadLocation(zone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone '%s' (use an IANA name like Europe/Berlin)", s.Timezone)
+	}
+	return location, nil
+}
+
+// LocalTime formats now in the server's time zone, e.g. "Tue 09:30 JST (UTC+9)".
+// It returns "" when the server has no valid time zone.
+func (s *Server) LocalTime(now time.Time) string {
+	location, err := s.Location()
+	if err != nil || location == nil {
+		return ""
+	}
+	local := now.In(location)
+	return fmt.Sprintf("%s (%s)", local.Format("Mon 15:04 MST"), formatUTCOffset(local))
+}
+
+// AnyTimezone reports whether any server has a time zone configured
+func (c *Config) AnyTimezone() bool {
+	for _, server := range c.Servers {
+		if server.Timezone != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// formatUTCOffset renders a time's zone offset as UTC+9, UTC-3:30 or UTC
+func formatUTCOffset(t time.Time) string {
+	_, offset := t.Zone()
+	if offset == 0 {
+		return "UTC"
+	}
+	sign := "+"
+	if offset < 0 {
+		sign = "-"
+		offset = -offset
+	}
+	hours, minutes := offset/3600, (offset%3600)/60
+	if minutes != 0 {
+		return fmt.Sprintf("UTC%s%d:%02d", sign, hours, minutes)
+	}
+	return fmt.Sprintf("UTC%s%d", sign, hours)
+}
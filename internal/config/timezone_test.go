@@ -0,0 +1,39 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestServerLocalTime(t *testing.T) {
+	now := time.Date(2026, 3, 3, 0, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		zone string
+		want string
+	}{
+		{"Asia/Tokyo", "Tue 09:30 JST (UTC+9)"},
+		{"Asia/Kolkata", "Tue 06:00 IST (UTC+5:30)"},
+		{"America/New_York", "Mon 19:30 EST (UTC-5)"},
+		{"UTC", "Tue 00:30 UTC (UTC)"},
+		{"", ""},
+		{"Mars/Olympus", ""},
+	}
+	for _, tt := range tests {
+		server := Server{Timezone: tt.zone}
+		if got := server.LocalTime(now); got != tt.want {
+			t.Errorf("LocalTime() with %q = %q, want %q", tt.zone, got, tt.want)
+		}
+	}
+}
+
+func TestServerValidateTimezone(t *testing.T) {
+	server := Server{Name: "tokyo", Hostname: "jp.example.com", Port: 22, Username: "ops", AuthType: "key", KeyPath: "~/.ssh/id_ed25519", Timezone: "Asia/Tokyo"}
+	if err := server.Validate(); err != nil {
+		t.Errorf("Expected valid timezone, got: %v", err)
+	}
+	server.Timezone = "Tokyo"
+	if err := server.Validate(); err == nil {
+		t.Error("Expected error for invalid timezone")
+	}
+}
@@ -0,0 +1,69 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DefaultMaxConcurrentTransfers is how many file transfers run at once unless configured
+const DefaultMaxConcurrentTransfers = 2
+
+// TransferLimits caps file transfers started by sshm, so bulk copies don't
+// saturate slow links. Globally the bandwidth is shared by every transfer;
+// on a server it is shared by the transfers to and from that server.
+type TransferLimits struct {
+	Bandwidth     string `yaml:"bandwidth,omitempty" json:"bandwidth,omitempty"`           // Bytes per second, e.g. "2M" or "512KiB/s" (empty = unlimited)
+	MaxConcurrent int    `yaml:"max_concurrent,omitempty" json:"max_concurrent,omitempty"` // Transfers running at once (0 = 2 globally, unlimited per server)
+}
+
+// BandwidthBytes returns the bandwidth cap in bytes per second, 0 when unlimited
+func (l *TransferLimits) BandwidthBytes() (int64, error) {
+	if l == nil {
+		return 0, nil
+	}
+	return ParseBandwidth(l.Bandwidth)
+}
+
+// Validate checks the bandwidth and concurrency limits
+func (l *TransferLimits) Validate() error {
+	if _, err := l.BandwidthBytes(); err != nil {
+		return err
+	}
+	if l.MaxConcurrent < 0 {
+		return fmt.Errorf("max_concurrent must not be negative")
+	}
+	return nil
+}
+
+// ParseBandwidth parses a rate such as "800K", "2M", "1.5MiB/s" or "500000" into
+// bytes per second. Suffixes are binary (K = 1024); an empty value is unlimited.
+func ParseBandwidth(value string) (int64, error) {
+	text := strings.TrimSpace(value)
+	if text == "" {
+		return 0, nil
+	}
+	text = strings.TrimSuffix(strings.ToUpper(text), "/S")
+	text = strings.TrimSuffix(strings.TrimSuffix(text, "B"), "I")
+
+	multiplier := 1.0
+	if n := len(text); n > 0 {
+		switch text[n-1] {
+		case 'K':
+			multiplier = 1 << 10
+		case 'M':
+			multiplier = 1 << 20
+		case 'G':
+			multiplier = 1 << 30
+		}
+		if multiplier != 1 {
+			text = text[:n-1]
+		}
+	}
+
+	number, err := strconv.ParseFloat(strings.TrimSpace(text), 64)
+	if err != nil || number <= 0 {
+		return 0, fmt.Errorf("invalid bandwidth %q (expected e.g. 512K or 2M per second)", value)
+	}
+	return int64(number * multiplier), nil
+}
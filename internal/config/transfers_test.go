@@ -0,0 +1,46 @@
+package config
+
+import "testing"
+
+func TestParseBandwidth(t *testing.T) {
+	tests := map[string]int64{
+		"":         0,
+		"500000":   500000,
+		"800K":     800 * 1024,
+		"2M":       2 << 20,
+		"1.5MiB/s": 3 << 19,
+		"2mb/s":    2 << 20,
+		"1G":       1 << 30,
+	}
+	for value, expected := range tests {
+		got, err := ParseBandwidth(value)
+		if err != nil || got != expected {
+			t.Errorf("ParseBandwidth(%q) = %d, %v; expected %d", value, got, err, expected)
+		}
+	}
+
+	for _, value := range []string{"fast", "-1M", "0", "M"} {
+		if _, err := ParseBandwidth(value); err == nil {
+			t.Errorf("Expected ParseBandwidth(%q) to fail", value)
+		}
+	}
+}
+
+func TestServerValidatesTransferLimits(t *testing.T) {
+	server := Server{Name: "web1", Hostname: "web1.example.com", Port: 22, Username: "ops", AuthType: "password"}
+
+	server.Transfers = &TransferLimits{Bandwidth: "1M", MaxConcurrent: 1}
+	if err := server.Validate(); err != nil {
+		t.Errorf("Expected valid transfer limits, got %v", err)
+	}
+
+	server.Transfers = &TransferLimits{Bandwidth: "lots"}
+	if err := server.Validate(); err == nil {
+		t.Error("Expected an invalid bandwidth to be rejected")
+	}
+
+	server.Transfers = &TransferLimits{MaxConcurrent: -1}
+	if err := server.Validate(); err == nil {
+		t.Error("Expected a negative concurrency limit to be rejected")
+	}
+}
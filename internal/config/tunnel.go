@@ -0,0 +1,140 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Tunnel describes a local port forward (ssh -L) or, when Dynamic is set, a
+// SOCKS proxy (ssh -D) managed by sshm
+type Tunnel struct {
+	Name       string `yaml:"name" json:"name"`
+	LocalPort  int    `yaml:"local_port" json:"local_port"`
+	RemoteHost string `yaml:"remote_host,omitempty" json:"remote_host,omitempty"` // Defaults to localhost on the server
+	RemotePort int    `yaml:"remote_port" json:"remote_port"`
+	AutoPort   bool   `yaml:"auto_port,omitempty" json:"auto_port,omitempty"` // Pick a free local port when local_port is taken
+	URL        string `yaml:"url,omitempty" json:"url,omitempty"`             // Browser URL template, e.g. https://localhost:{port}/admin
+	Dynamic    bool   `yaml:"dynamic,omitempty" json:"dynamic,omitempty"`     // SOCKS proxy on the local port; no remote host or port
+}
+
+// DefaultTunnelURL is used to open tunnels that don't configure a URL template
+const DefaultTunnelURL = "http://localhost:{port}"
+
+// DefaultSOCKSPort is the local port of SOCKS proxies started without a configured tunnel
+const DefaultSOCKSPort = 1080
+
+// GetRemoteHost returns the forward destination as seen from the server
+func (t *Tunnel) GetRemoteHost() string {
+	if strings.TrimSpace(t.RemoteHost) == "" {
+		return "localhost"
+	}
+	return t.RemoteHost
+}
+
+// ForwardSpec returns the -L argument for the tunnel using the given local port
+func (t *Tunnel) ForwardSpec(localPort int) string {
+	return fmt.Sprintf("%d:%s:%d", localPort, t.GetRemoteHost(), t.RemotePort)
+}
+
+// ForwardArgs returns the ssh arguments that set up the tunnel on the given
+// local port: -D bound to the loopback interface for SOCKS proxies, -L otherwise
+func (t *Tunnel) ForwardArgs(localPort int) []string {
+	if t.Dynamic {
+		return []string{"-D", fmt.Sprintf("127.0.0.1:%d", localPort)}
+	}
+	return []string{"-L", t.ForwardSpec(localPort)}
+}
+
+// Destination describes where the tunnel leads, for display
+func (t *Tunnel) Destination() string {
+	if t.Dynamic {
+		return "SOCKS proxy"
+	}
+	return fmt.Sprintf("%s:%d", t.GetRemoteHost(), t.RemotePort)
+}
+
+// BrowserURL returns the URL for opening the forwarded service, with {port}
+// replaced by the local port actually bound. SOCKS proxies have no URL unless
+// one is configured.
+func (t *Tunnel) BrowserURL(localPort int) string {
+	template := strings.TrimSpace(t.URL)
+	if template == "" && t.Dynamic {
+		return ""
+	}
+	if template == "" {
+		template = DefaultTunnelURL
+	}
+	return strings.ReplaceAll(template, "{port}", fmt.Sprintf("%d", localPort))
+}
+
+// Validate validates a tunnel configuration
+func (t *Tunnel) Validate() error {
+	if strings.TrimSpace(t.Name) == "" {
+		return fmt.Errorf("tunnel name is required")
+	}
+	if t.LocalPort < 0 || t.LocalPort > 65535 {
+		return fmt.Errorf("tunnel '%s': local port must be between 0 and 65535", t.Name)
+	}
+	if t.Dynamic {
+		if t.RemotePort != 0 || strings.TrimSpace(t.RemoteHost) != "" {
+			return fmt.Errorf("tunnel '%s': dynamic (SOCKS) tunnels take no remote host or port", t.Name)
+		}
+	} else if t.RemotePort <= 0 || t.RemotePort > 65535 {
+		return fmt.Errorf("tunnel '%s': remote port must be between 1 and 65535", t.Name)
+	}
+	if url := strings.TrimSpace(t.URL); url != "" && !strings.Contains(url, "://") {
+		return fmt.Errorf("tunnel '%s': url must include a scheme such as http://", t.Name)
+	}
+	return nil
+}
+
+// GetTunnel returns the server's tunnel with the given name
+func (s *Server) GetTunnel(name string) (*Tunnel, error) {
+	for i := range s.Tunnels {
+		if s.Tunnels[i].Name == name {
+			return &s.Tunnels[i], nil
+		}
+	}
+	return nil, fmt.Errorf("tunnel '%s' not found on server '%s'", name, s.Name)
+}
+
+// SOCKSTunnel returns the server's first dynamic tunnel, or a SOCKS proxy on
+// DefaultSOCKSPort that moves to a free port when that one is taken
+func (s *Server) SOCKSTunnel() Tunnel {
+	for _, tunnel := range s.Tunnels {
+		if tunnel.Dynamic {
+			return tunnel
+		}
+	}
+	return Tunnel{Name: "socks", LocalPort: DefaultSOCKSPort, Dynamic: true, AutoPort: true}
+}
+
+// LocalPortConflict describes a local port configured by more than one tunnel
+type LocalPortConflict struct {
+	Port    int
+	Tunnels []string // "server/tunnel" identifiers
+}
+
+// FindLocalPortConflicts reports local ports that are forwarded by more than one
+// configured tunnel. Such tunnels can't run at the same time on their configured port.
+func (c *Config) FindLocalPortConflicts() []LocalPortConflict {
+	owners := make(map[int][]string)
+	for _, server := range c.Servers {
+		for _, tunnel := range server.Tunnels {
+			if tunnel.LocalPort == 0 {
+				continue // Port chosen at launch time
+			}
+			owners[tunnel.LocalPort] = append(owners[tunnel.LocalPort], server.Name+"/"+tunnel.Name)
+		}
+	}
+
+	var conflicts []LocalPortConflict
+	for port, tunnels := range owners {
+		if len(tunnels) > 1 {
+			conflicts = append(conflicts, LocalPortConflict{Port: port, Tunnels: tunnels})
+		}
+	}
+	sort.Slice(conflicts, func(i, j int) bool { return conflicts[i].Port < conflicts[j].Port })
+	return conflicts
+}
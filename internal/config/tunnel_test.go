@@ -0,0 +1,102 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTunnelValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		tunnel  Tunnel
+		wantErr bool
+	}{
+		{"valid", Tunnel{Name: "web", LocalPort: 8080, RemotePort: 80}, false},
+		{"auto local port", Tunnel{Name: "web", RemotePort: 80}, false},
+		{"missing name", Tunnel{LocalPort: 8080, RemotePort: 80}, true},
+		{"missing remote port", Tunnel{Name: "web", LocalPort: 8080}, true},
+		{"local port out of range", Tunnel{Name: "web", LocalPort: 70000, RemotePort: 80}, true},
+		{"url template", Tunnel{Name: "web", RemotePort: 443, URL: "https://localhost:{port}/admin"}, false},
+		{"url without scheme", Tunnel{Name: "web", RemotePort: 80, URL: "localhost:{port}"}, true},
+		{"dynamic", Tunnel{Name: "socks", LocalPort: 1080, Dynamic: true}, false},
+		{"dynamic with remote port", Tunnel{Name: "socks", LocalPort: 1080, RemotePort: 80, Dynamic: true}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.tunnel.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTunnelForwardSpec(t *testing.T) {
+	tunnel := Tunnel{Name: "db", LocalPort: 15432, RemotePort: 5432}
+	if got := tunnel.ForwardSpec(15432); got != "15432:localhost:5432" {
+		t.Errorf("Unexpected forward spec: %s", got)
+	}
+
+	tunnel.RemoteHost = "db.internal"
+	if got := tunnel.ForwardSpec(2000); got != "2000:db.internal:5432" {
+		t.Errorf("Unexpected forward spec: %s", got)
+	}
+}
+
+func TestTunnelBrowserURL(t *testing.T) {
+	tunnel := Tunnel{Name: "web", RemotePort: 80}
+	if got := tunnel.BrowserURL(8080); got != "http://localhost:8080" {
+		t.Errorf("Unexpected default URL: %s", got)
+	}
+
+	tunnel.URL = "https://localhost:{port}/admin"
+	if got := tunnel.BrowserURL(9443); got != "https://localhost:9443/admin" {
+		t.Errorf("Unexpected templated URL: %s", got)
+	}
+}
+
+func TestDynamicTunnel(t *testing.T) {
+	tunnel := Tunnel{Name: "socks", Dynamic: true}
+	if got := strings.Join(tunnel.ForwardArgs(1080), " "); got != "-D 127.0.0.1:1080" {
+		t.Errorf("Unexpected forward args: %s", got)
+	}
+	if got := tunnel.BrowserURL(1080); got != "" {
+		t.Errorf("Expected no browser URL for a SOCKS proxy, got %s", got)
+	}
+
+	server := Server{Name: "bastion"}
+	if got := server.SOCKSTunnel(); !got.Dynamic || got.LocalPort != DefaultSOCKSPort || !got.AutoPort {
+		t.Errorf("Unexpected default SOCKS tunnel: %+v", got)
+	}
+	server.Tunnels = []Tunnel{{Name: "web", RemotePort: 80}, {Name: "proxy", LocalPort: 9050, Dynamic: true}}
+	if got := server.SOCKSTunnel(); got.Name != "proxy" {
+		t.Errorf("Expected the configured dynamic tunnel, got %+v", got)
+	}
+}
+
+func TestFindLocalPortConflicts(t *testing.T) {
+	cfg := &Config{Servers: []Server{
+		{Name: "a", Tunnels: []Tunnel{{Name: "web", LocalPort: 8080, RemotePort: 80}}},
+		{Name: "b", Tunnels: []Tunnel{{Name: "admin", LocalPort: 8080, RemotePort: 8000}, {Name: "db", LocalPort: 5432, RemotePort: 5432}}},
+		{Name: "c", Tunnels: []Tunnel{{Name: "auto", RemotePort: 80}, {Name: "auto2", RemotePort: 81}}},
+	}}
+
+	conflicts := cfg.FindLocalPortConflicts()
+	if len(conflicts) != 1 {
+		t.Fatalf("Expected 1 conflict, got %d: %+v", len(conflicts), conflicts)
+	}
+	if conflicts[0].Port != 8080 || len(conflicts[0].Tunnels) != 2 {
+		t.Errorf("Unexpected conflict: %+v", conflicts[0])
+	}
+}
+
+func TestServerValidateDuplicateTunnelNames(t *testing.T) {
+	server := Server{
+		Name: "web", Hostname: "web.example.com", Port: 22, Username: "ops", AuthType: "key", KeyPath: "~/.ssh/id_rsa",
+		Tunnels: []Tunnel{{Name: "a", RemotePort: 80}, {Name: "a", RemotePort: 81}},
+	}
+	if err := server.Validate(); err == nil {
+		t.Error("Expected error for duplicate tunnel names")
+	}
+}
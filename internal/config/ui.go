@@ -0,0 +1,157 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// UIConfig holds settings that control TUI behavior
+type UIConfig struct {
+	IdleLockMinutes        int                 `yaml:"idle_lock_minutes,omitempty" json:"idle_lock_minutes,omitempty"`               // Lock the TUI after N idle minutes (0 = disabled)
+	LockPINHash            string              `yaml:"lock_pin_hash,omitempty" json:"lock_pin_hash,omitempty"`                       // bcrypt hash of the PIN required to unlock
+	Status                 StatusDisplayConfig `yaml:"status,omitempty" json:"status,omitempty"`                                     // Status column thresholds and colors
+	WatchIntervalSeconds   int                 `yaml:"watch_interval_seconds,omitempty" json:"watch_interval_seconds,omitempty"`     // Status check interval for watched servers
+	ShowLocalTime          bool                `yaml:"show_local_time,omitempty" json:"show_local_time,omitempty"`                   // Show each host's local time as a server list column
+	Confirmations          map[string]string   `yaml:"confirmations,omitempty" json:"confirmations,omitempty"`                       // Confirmation mode per action (always, never, protected)
+	SessionReminderMinutes int                 `yaml:"session_reminder_minutes,omitempty" json:"session_reminder_minutes,omitempty"` // Remind of sessions detached and idle this long, repeating as often (0 = disabled)
+	SessionReminderDesktop bool                `yaml:"session_reminder_desktop,omitempty" json:"session_reminder_desktop,omitempty"` // Also send session reminders as desktop notifications
+	CredentialWarningDays  int                 `yaml:"credential_warning_days,omitempty" json:"credential_warning_days,omitempty"`   // Remind of key rotations and account expiries due within this many days (default 14)
+}
+
+// DefaultWatchInterval is how often watched servers are checked when not configured
+const DefaultWatchInterval = 3 * time.Second
+
+// WatchInterval returns the status check interval for watched servers
+func (u *UIConfig) WatchInterval() time.Duration {
+	if u.WatchIntervalSeconds <= 0 {
+		return DefaultWatchInterval
+	}
+	return time.Duration(u.WatchIntervalSeconds) * time.Second
+}
+
+// SessionReminderInterval returns how long a session may stay detached and idle
+// before the TUI reminds of it, and how often it repeats; 0 when disabled
+func (u *UIConfig) SessionReminderInterval() time.Duration {
+	if u.SessionReminderMinutes <= 0 {
+		return 0
+	}
+	return time.Duration(u.SessionReminderMinutes) * time.Minute
+}
+
+// Default latency thresholds for the status column
+const (
+	DefaultLatencyWarn     = 300 * time.Millisecond
+	DefaultLatencyCritical = time.Second
+)
+
+// StatusDisplayConfig controls how connection status is rendered in the TUI
+type StatusDisplayConfig struct {
+	LatencyWarnMS     int               `yaml:"latency_warn_ms,omitempty" json:"latency_warn_ms,omitempty"`         // Online servers slower than this are shown as warning
+	LatencyCriticalMS int               `yaml:"latency_critical_ms,omitempty" json:"latency_critical_ms,omitempty"` // Online servers slower than this are shown as critical
+	Colors            map[string]string `yaml:"colors,omitempty" json:"colors,omitempty"`                           // Status classification or severity -> color name
+	Palette           string            `yaml:"palette,omitempty" json:"palette,omitempty"`                         // Base colors: default, colorblind or monochrome
+	Symbols           bool              `yaml:"symbols,omitempty" json:"symbols,omitempty"`                         // Mark statuses with ✓ ✗ ~ ! so they don't rely on color
+}
+
+// Status palettes. The colorblind palette uses the Okabe-Ito colors, which stay
+// apart under red-green and blue-yellow color blindness; monochrome drops
+// color altogether and always shows symbols.
+const (
+	PaletteDefault    = "default"
+	PaletteColorblind = "colorblind"
+	PaletteMonochrome = "monochrome"
+)
+
+// StatusPalettes lists the valid palette names
+var StatusPalettes = []string{PaletteDefault, PaletteColorblind, PaletteMonochrome}
+
+// PaletteName returns the palette in use, the default when none is set
+func (s *StatusDisplayConfig) PaletteName() string {
+	if s.Palette == "" {
+		return PaletteDefault
+	}
+	return strings.ToLower(s.Palette)
+}
+
+// ShowSymbols reports whether statuses are marked with symbols as well as color
+func (s *StatusDisplayConfig) ShowSymbols() bool {
+	return s.Symbols || s.PaletteName() == PaletteMonochrome
+}
+
+// LatencyThresholds returns the warning and critical latency thresholds, applying defaults
+func (s *StatusDisplayConfig) LatencyThresholds() (warn, critical time.Duration) {
+	warn, critical = DefaultLatencyWarn, DefaultLatencyCritical
+	if s.LatencyWarnMS > 0 {
+		warn = time.Duration(s.LatencyWarnMS) * time.Millisecond
+	}
+	if s.LatencyCriticalMS > 0 {
+		critical = time.Duration(s.LatencyCriticalMS) * time.Millisecond
+	}
+	return warn, critical
+}
+
+// ColorFor returns the configured color name for a status classification or severity, if any
+func (s *StatusDisplayConfig) ColorFor(key string) (string, bool) {
+	color, ok := s.Colors[strings.ToLower(key)]
+	return color, ok && color != ""
+}
+
+// Validate validates the status display configuration
+func (s *StatusDisplayConfig) Validate() error {
+	if s.LatencyWarnMS < 0 || s.LatencyCriticalMS < 0 {
+		return fmt.Errorf("latency thresholds must not be negative")
+	}
+	warn, critical := s.LatencyThresholds()
+	if warn > critical {
+		return fmt.Errorf("warning latency threshold (%v) must not exceed critical threshold (%v)", warn, critical)
+	}
+	if !contains(StatusPalettes, s.PaletteName()) {
+		return fmt.Errorf("unknown status palette '%s' (valid: %s)", s.Palette, strings.Join(StatusPalettes, ", "))
+	}
+	return nil
+}
+
+// IdleLockTimeout returns the idle duration after which the TUI locks, or 0 if disabled
+func (u *UIConfig) IdleLockTimeout() time.Duration {
+	if u.IdleLockMinutes <= 0 {
+		return 0
+	}
+	return time.Duration(u.IdleLockMinutes) * time.Minute
+}
+
+// SetLockPIN stores a hash of the given PIN; an empty PIN clears it
+func (u *UIConfig) SetLockPIN(pin string) error {
+	pin = strings.TrimSpace(pin)
+	if pin == "" {
+		u.LockPINHash = ""
+		return nil
+	}
+
+	if len(pin) < 4 {
+		return fmt.Errorf("PIN must be at least 4 characters")
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(pin), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("failed to hash PIN: %w", err)
+	}
+
+	u.LockPINHash = string(hash)
+	return nil
+}
+
+// HasLockPIN reports whether a PIN is required to unlock the TUI
+func (u *UIConfig) HasLockPIN() bool {
+	return u.LockPINHash != ""
+}
+
+// VerifyLockPIN checks a PIN against the stored hash. Without a stored PIN any input unlocks.
+func (u *UIConfig) VerifyLockPIN(pin string) bool {
+	if !u.HasLockPIN() {
+		return true
+	}
+	return bcrypt.CompareHashAndPassword([]byte(u.LockPINHash), []byte(strings.TrimSpace(pin))) == nil
+}
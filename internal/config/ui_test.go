@@ -0,0 +1,116 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestIdleLockTimeout(t *testing.T) {
+	ui := UIConfig{}
+	if ui.IdleLockTimeout() != 0 {
+		t.Error("Expected idle lock to be disabled by default")
+	}
+
+	ui.IdleLockMinutes = 15
+	if ui.IdleLockTimeout() != 15*time.Minute {
+		t.Errorf("Expected 15m timeout, got %v", ui.IdleLockTimeout())
+	}
+}
+
+func TestLockPIN(t *testing.T) {
+	ui := UIConfig{}
+
+	if !ui.VerifyLockPIN("anything") {
+		t.Error("Expected any input to unlock when no PIN is set")
+	}
+
+	if err := ui.SetLockPIN("12"); err == nil {
+		t.Error("Expected error for short PIN")
+	}
+
+	if err := ui.SetLockPIN("4821"); err != nil {
+		t.Fatalf("Failed to set PIN: %v", err)
+	}
+	if ui.LockPINHash == "4821" {
+		t.Error("PIN must not be stored in plaintext")
+	}
+	if !ui.VerifyLockPIN("4821") {
+		t.Error("Expected correct PIN to verify")
+	}
+	if ui.VerifyLockPIN("0000") {
+		t.Error("Expected wrong PIN to be rejected")
+	}
+
+	if err := ui.SetLockPIN(""); err != nil {
+		t.Fatalf("Failed to clear PIN: %v", err)
+	}
+	if ui.HasLockPIN() {
+		t.Error("Expected PIN to be cleared")
+	}
+}
+
+func TestUIConfigPersistence(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+
+	cfg, err := LoadFromPath(configPath)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	cfg.UI.IdleLockMinutes = 10
+	if err := cfg.UI.SetLockPIN("2468"); err != nil {
+		t.Fatalf("Failed to set PIN: %v", err)
+	}
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Failed to save config: %v", err)
+	}
+
+	loaded, err := LoadFromPath(configPath)
+	if err != nil {
+		t.Fatalf("Failed to reload config: %v", err)
+	}
+	if loaded.UI.IdleLockMinutes != 10 {
+		t.Errorf("Expected idle lock minutes 10, got %d", loaded.UI.IdleLockMinutes)
+	}
+	if !loaded.UI.VerifyLockPIN("2468") {
+		t.Error("Expected PIN to survive a save/load round trip")
+	}
+}
+
+func TestStatusDisplayThresholds(t *testing.T) {
+	display := StatusDisplayConfig{}
+	warn, critical := display.LatencyThresholds()
+	if warn != DefaultLatencyWarn || critical != DefaultLatencyCritical {
+		t.Errorf("Expected default thresholds, got %v/%v", warn, critical)
+	}
+
+	display = StatusDisplayConfig{LatencyWarnMS: 2000, LatencyCriticalMS: 500}
+	if err := display.Validate(); err == nil {
+		t.Error("Expected error when warning threshold exceeds critical threshold")
+	}
+
+	display = StatusDisplayConfig{Colors: map[string]string{"online": "blue"}}
+	if color, ok := display.ColorFor("Online"); !ok || color != "blue" {
+		t.Errorf("Expected configured color lookup, got %q (%v)", color, ok)
+	}
+}
+
+func TestStatusDisplayPalette(t *testing.T) {
+	display := StatusDisplayConfig{}
+	if display.PaletteName() != PaletteDefault || display.ShowSymbols() {
+		t.Errorf("Expected the default palette without symbols, got %s/%v", display.PaletteName(), display.ShowSymbols())
+	}
+
+	display = StatusDisplayConfig{Palette: "Monochrome"}
+	if err := display.Validate(); err != nil {
+		t.Errorf("Expected monochrome to be valid, got %v", err)
+	}
+	if !display.ShowSymbols() {
+		t.Error("Expected monochrome to always show symbols")
+	}
+
+	display = StatusDisplayConfig{Palette: "rainbow"}
+	if err := display.Validate(); err == nil {
+		t.Error("Expected an unknown palette to be rejected")
+	}
+}
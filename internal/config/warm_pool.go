@@ -0,0 +1,59 @@
+package config
+
+import "time"
+
+// Defaults for the warm connection pool
+const (
+	DefaultWarmPoolSize = 5
+	DefaultWarmIdle     = 10 * time.Minute
+)
+
+// WarmPoolConfig keeps master connections open to servers marked warm and to
+// watched servers, so attaching skips the handshake and authentication
+type WarmPoolConfig struct {
+	Enabled     bool `yaml:"enabled,omitempty" json:"enabled,omitempty"`
+	Size        int  `yaml:"size,omitempty" json:"size,omitempty"`                 // Most servers kept warm at once (default: 5)
+	IdleMinutes int  `yaml:"idle_minutes,omitempty" json:"idle_minutes,omitempty"` // A warm connection without sessions closes after this long once sshm stops renewing it (default: 10)
+}
+
+// PoolSize returns the configured pool size, or the default when unset
+func (w *WarmPoolConfig) PoolSize() int {
+	if w.Size <= 0 {
+		return DefaultWarmPoolSize
+	}
+	return w.Size
+}
+
+// IdleTimeout returns how long a warm connection stays open without sessions
+func (w *WarmPoolConfig) IdleTimeout() time.Duration {
+	if w.IdleMinutes <= 0 {
+		return DefaultWarmIdle
+	}
+	return time.Duration(w.IdleMinutes) * time.Minute
+}
+
+// WarmServers returns the servers the pool keeps connected: those marked warm,
+// then watched ones, in configuration order and up to the pool size. Servers
+// with an ssh_template are left out since their command isn't plain ssh.
+func (c *Config) WarmServers() []Server {
+	if !c.WarmPool.Enabled {
+		return nil
+	}
+	var warm, watched []Server
+	for _, server := range c.GetServers() {
+		if server.SSHTemplate != "" {
+			continue
+		}
+		switch {
+		case server.Warm:
+			warm = append(warm, server)
+		case server.Watch:
+			watched = append(watched, server)
+		}
+	}
+	servers := append(warm, watched...)
+	if size := c.WarmPool.PoolSize(); len(servers) > size {
+		servers = servers[:size]
+	}
+	return servers
+}
@@ -0,0 +1,90 @@
+package config
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWarmServers(t *testing.T) {
+	cfg := &Config{Servers: []Server{
+		{Name: "watched1", Watch: true},
+		{Name: "plain"},
+		{Name: "warm1", Warm: true},
+		{Name: "templated", Warm: true, SSHTemplate: "{ssh} {user}@{host}"},
+		{Name: "watched2", Watch: true},
+		{Name: "warm2", Warm: true},
+	}}
+
+	if servers := cfg.WarmServers(); servers != nil {
+		t.Errorf("Expected no warm servers while the pool is off, got %v", servers)
+	}
+
+	cfg.WarmPool.Enabled = true
+	if got := serverNames(cfg.WarmServers()); got != "warm1,warm2,watched1,watched2" {
+		t.Errorf("Expected warm servers before watched ones, got %s", got)
+	}
+
+	cfg.WarmPool.Size = 3
+	if got := serverNames(cfg.WarmServers()); got != "warm1,warm2,watched1" {
+		t.Errorf("Expected the pool size to cap the servers, got %s", got)
+	}
+}
+
+func TestWarmPoolDefaults(t *testing.T) {
+	var pool WarmPoolConfig
+	if pool.PoolSize() != DefaultWarmPoolSize || pool.IdleTimeout() != DefaultWarmIdle {
+		t.Errorf("Expected defaults, got %d and %v", pool.PoolSize(), pool.IdleTimeout())
+	}
+	pool = WarmPoolConfig{Size: 2, IdleMinutes: 30}
+	if pool.PoolSize() != 2 || pool.IdleTimeout() != 30*time.Minute {
+		t.Errorf("Expected the configured values, got %d and %v", pool.PoolSize(), pool.IdleTimeout())
+	}
+}
+
+func TestControlOptionsUseWarmConnection(t *testing.T) {
+	t.Setenv("SSHM_CONFIG_DIR", t.TempDir())
+
+	server := Server{Name: "db", Hostname: "db.example.com", Port: 22, Username: "ops"}
+	if server.HasWarmConnection() {
+		t.Fatal("Expected no warm connection before the socket exists")
+	}
+
+	socket, err := server.WarmSocketPath()
+	if err != nil {
+		t.Fatalf("WarmSocketPath failed: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(socket), 0700); err != nil {
+		t.Fatal(err)
+	}
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatalf("Failed to create socket: %v", err)
+	}
+	defer listener.Close()
+
+	if !server.HasWarmConnection() {
+		t.Fatal("Expected the warm connection to be found")
+	}
+	options := server.ControlOptions()
+	if !strings.Contains(options, "-o ControlMaster=no") || !strings.Contains(options, "-o ControlPath="+socket) {
+		t.Errorf("Expected the warm socket to be reused, got %q", options)
+	}
+
+	other := server
+	other.Port = 2222
+	if other.HasWarmConnection() {
+		t.Error("Expected another port to have its own socket")
+	}
+}
+
+func serverNames(servers []Server) string {
+	names := make([]string, len(servers))
+	for i, server := range servers {
+		names[i] = server.Name
+	}
+	return strings.Join(names, ",")
+}
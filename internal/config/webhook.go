@@ -0,0 +1,64 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Reachability transitions a webhook can be called on
+const (
+	WebhookOnOnline  = "online"
+	WebhookOnOffline = "offline"
+)
+
+// WebhookPlaceholders are the placeholders substituted in webhook payloads
+var WebhookPlaceholders = []string{"name", "host", "from", "to", "time"}
+
+// Webhook is an HTTP endpoint called when a server goes online or offline, e.g.
+// to start a deploy once a staging box is back
+type Webhook struct {
+	URL     string            `yaml:"url" json:"url"`
+	On      string            `yaml:"on,omitempty" json:"on,omitempty"`           // "online", "offline" or empty for both
+	Method  string            `yaml:"method,omitempty" json:"method,omitempty"`   // Defaults to POST
+	Headers map[string]string `yaml:"headers,omitempty" json:"headers,omitempty"` // e.g. Authorization
+	Payload string            `yaml:"payload,omitempty" json:"payload,omitempty"` // Body with {placeholders}; a JSON summary when empty
+}
+
+// GetMethod returns the HTTP method of the webhook
+func (w *Webhook) GetMethod() string {
+	if strings.TrimSpace(w.Method) == "" {
+		return "POST"
+	}
+	return strings.ToUpper(strings.TrimSpace(w.Method))
+}
+
+// Fires reports whether the webhook is called when a server becomes state
+// (WebhookOnOnline or WebhookOnOffline)
+func (w *Webhook) Fires(state string) bool {
+	on := strings.ToLower(strings.TrimSpace(w.On))
+	return on == "" || on == state
+}
+
+// Validate validates a webhook configuration
+func (w *Webhook) Validate() error {
+	parsed, err := url.Parse(strings.TrimSpace(w.URL))
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("webhook url '%s' must be an http:// or https:// URL", w.URL)
+	}
+	if on := strings.ToLower(strings.TrimSpace(w.On)); on != "" && on != WebhookOnOnline && on != WebhookOnOffline {
+		return fmt.Errorf("webhook on must be '%s' or '%s', got '%s'", WebhookOnOnline, WebhookOnOffline, w.On)
+	}
+	switch w.GetMethod() {
+	case "GET", "POST", "PUT", "PATCH":
+	default:
+		return fmt.Errorf("webhook method must be GET, POST, PUT or PATCH, got '%s'", w.Method)
+	}
+	for _, match := range templatePlaceholder.FindAllStringSubmatch(w.Payload, -1) {
+		if !contains(WebhookPlaceholders, match[1]) {
+			return fmt.Errorf("unknown placeholder {%s} in webhook payload (supported: {%s})",
+				match[1], strings.Join(WebhookPlaceholders, "}, {"))
+		}
+	}
+	return nil
+}
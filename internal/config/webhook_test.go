@@ -0,0 +1,38 @@
+package config
+
+import "testing"
+
+func TestWebhookValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		webhook Webhook
+		wantErr bool
+	}{
+		{"minimal", Webhook{URL: "https://ci.example.com/hook"}, false},
+		{"full", Webhook{URL: "http://localhost:8080/deploy", On: "online", Method: "put", Payload: `{"server": "{name}", "state": "{to}"}`}, false},
+		{"no scheme", Webhook{URL: "ci.example.com/hook"}, true},
+		{"unsupported scheme", Webhook{URL: "ftp://ci.example.com"}, true},
+		{"bad on", Webhook{URL: "https://ci.example.com", On: "flapping"}, true},
+		{"bad method", Webhook{URL: "https://ci.example.com", Method: "DELETE"}, true},
+		{"unknown placeholder", Webhook{URL: "https://ci.example.com", Payload: "{user} is back"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.webhook.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWebhookFires(t *testing.T) {
+	both := Webhook{URL: "https://example.com"}
+	if !both.Fires(WebhookOnOnline) || !both.Fires(WebhookOnOffline) {
+		t.Error("webhook without on must fire on both transitions")
+	}
+	online := Webhook{URL: "https://example.com", On: "Online"}
+	if !online.Fires(WebhookOnOnline) || online.Fires(WebhookOnOffline) {
+		t.Error("webhook on online must only fire when the server comes back")
+	}
+}
@@ -0,0 +1,95 @@
+package connection
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"sshm/internal/config"
+	"sshm/internal/history"
+)
+
+// ErrBannerNotAcknowledged refuses a window for a server whose login banner
+// must be accepted first
+var ErrBannerNotAcknowledged = errors.New("login banner not accepted yet")
+
+// BannerAcks holds the names of the servers whose login banner was accepted for
+// a connect. Windows of servers that require it and aren't in it are refused.
+type BannerAcks map[string]bool
+
+// PendingBanner is a login banner to accept before connecting to its server
+type PendingBanner struct {
+	Server config.Server
+	Banner string
+}
+
+// PendingBanners fetches the login banners of the servers that require them
+// accepted, for a group connect to ask about each one like single connects do.
+// Servers presenting no banner are returned as acknowledged.
+func (m *Manager) PendingBanners(servers []config.Server) ([]PendingBanner, BannerAcks, error) {
+	var pending []PendingBanner
+	acks := make(BannerAcks)
+	for _, server := range servers {
+		if !server.RequireBannerAck {
+			continue
+		}
+		banner, err := m.FetchBanner(server)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to fetch login banner for %s: %w", server.Name, err)
+		}
+		if strings.TrimSpace(banner) == "" {
+			acks[server.Name] = true
+			continue
+		}
+		pending = append(pending, PendingBanner{Server: server, Banner: banner})
+	}
+	return pending, acks, nil
+}
+
+// AcknowledgedBanners returns the servers whose login banner doesn't need asking
+// about again, for connects that can't ask: restoring a workspace and repairing
+// a session. A server counts when it presents no banner or the one it presents
+// was accepted before; servers that can't be reached don't.
+func (m *Manager) AcknowledgedBanners(servers []config.Server) BannerAcks {
+	acks := make(BannerAcks)
+	for _, server := range servers {
+		if !server.RequireBannerAck {
+			continue
+		}
+		banner, err := m.FetchBanner(server)
+		if err != nil {
+			continue
+		}
+		if strings.TrimSpace(banner) == "" || m.bannerAccepted(server.Name, banner) {
+			acks[server.Name] = true
+		}
+	}
+	return acks
+}
+
+// bannerAccepted reports whether banner was accepted for the server before
+func (m *Manager) bannerAccepted(serverName, banner string) bool {
+	acks, err := m.historyManager.GetBannerAcknowledgments(serverName, 0)
+	if err != nil {
+		return false
+	}
+	hash := history.HashBanner(banner)
+	for _, ack := range acks {
+		if ack.BannerHash == hash {
+			return true
+		}
+	}
+	return false
+}
+
+// Unacknowledged returns the names of the servers that require their login
+// banner accepted and aren't in acks
+func (acks BannerAcks) Unacknowledged(servers []config.Server) []string {
+	var names []string
+	for _, server := range servers {
+		if server.RequireBannerAck && !acks[server.Name] {
+			names = append(names, server.Name)
+		}
+	}
+	return names
+}
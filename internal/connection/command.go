@@ -0,0 +1,105 @@
+package connection
+
+import (
+	"fmt"
+
+	"sshm/internal/config"
+	"sshm/internal/monitor"
+	sshsdk "sshm/internal/ssh"
+	"sshm/internal/tmux"
+)
+
+// SSHCommand builds the ssh command that connects to a server. Single and group
+// connects, restored workspaces and repaired sessions all use it, so every
+// window of a server connects with the same options.
+func SSHCommand(server config.Server) (string, error) {
+	return sshCommand(server, "")
+}
+
+// sshCommand builds the ssh command of a server; prefix is the sshpass command
+// answering its password or passphrase prompt, if any
+func sshCommand(server config.Server, prefix string) (string, error) {
+	if err := server.Validate(); err != nil {
+		return "", fmt.Errorf("invalid server configuration: %w", err)
+	}
+
+	// Build base SSH command with pseudo-terminal allocation
+	sshCmd := fmt.Sprintf("ssh -t %s@%s", server.Username, server.GetEffectiveHostname())
+
+	// Add port if not default
+	if server.Port != 22 {
+		sshCmd += fmt.Sprintf(" -p %d", server.Port)
+	}
+
+	// Add key-specific options
+	if server.AuthType == "key" && server.KeyPath != "" {
+		sshCmd += fmt.Sprintf(" -i %s", server.KeyPath)
+	}
+
+	// Add the auth provider's options, e.g. a short-lived certificate
+	if server.AuthProvider != "" {
+		options, err := sshsdk.ProviderSSHOptions(server.AuthProvider, monitor.ProviderRequest(server))
+		if err != nil {
+			return "", fmt.Errorf("failed to prepare auth provider: %w", err)
+		}
+		sshCmd += options
+	}
+
+	// Add common SSH options
+	sshCmd += " -o ServerAliveInterval=60 -o ServerAliveCountMax=3"
+
+	// Add server-specific options (e.g. host key alias for resolve_to)
+	sshCmd += server.GetSSHOptions()
+
+	// Share a master connection when controlmaster is on
+	sshCmd += server.ControlOptions()
+
+	// Upload and source bootstrap files, if configured, with the sshpass prefix
+	// in front of both the upload and the connection
+	return server.WrapSSHCommand(prefix, sshCmd), nil
+}
+
+// commandServer is a server handed to tmux whose windows connect with the
+// command sshCommand builds
+type commandServer struct {
+	*config.Server
+	prefix      string   // sshpass command answering the prompt, if any
+	env         []string // Window environment the prefix reads the secret from
+	bannerAcked bool     // The login banner was accepted, when the server requires it
+}
+
+// SSHCommand implements tmux.CommandBuilder
+func (s commandServer) SSHCommand() (string, error) {
+	if err := s.Refused(); err != nil {
+		return "", err
+	}
+	return sshCommand(*s.Server, s.prefix)
+}
+
+// WindowEnvironment implements tmux.Environment
+func (s commandServer) WindowEnvironment() []string {
+	return s.env
+}
+
+// Refused implements tmux.Refuser: windows of servers whose login banner must
+// be accepted first don't connect until it is
+func (s commandServer) Refused() error {
+	if s.RequireBannerAck && !s.bannerAcked {
+		return ErrBannerNotAcknowledged
+	}
+	return nil
+}
+
+// TmuxServer returns a server for tmux to open a window for
+func TmuxServer(server config.Server, acks BannerAcks) tmux.Server {
+	return commandServer{Server: &server, bannerAcked: acks[server.Name]}
+}
+
+// TmuxServers returns servers for tmux to open windows for
+func TmuxServers(servers []config.Server, acks BannerAcks) []tmux.Server {
+	tmuxServers := make([]tmux.Server, len(servers))
+	for i := range servers {
+		tmuxServers[i] = commandServer{Server: &servers[i], bannerAcked: acks[servers[i].Name]}
+	}
+	return tmuxServers
+}
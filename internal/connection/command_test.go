@@ -0,0 +1,53 @@
+package connection
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+	"sshm/internal/config"
+	sshsdk "sshm/internal/ssh"
+	"sshm/internal/tmux"
+)
+
+type certProvider struct{}
+
+func (certProvider) Name() string { return "test-cert" }
+
+func (certProvider) AuthMethod(request sshsdk.AuthRequest) (ssh.AuthMethod, error) {
+	return ssh.Password("unused"), nil
+}
+
+func (certProvider) SSHArgs(request sshsdk.AuthRequest) ([]string, error) {
+	return []string{"-o", "CertificateFile=/tmp/" + request.ServerName + "-cert.pub"}, nil
+}
+
+// Every window of a server gets its auth provider's options: single connects,
+// group connects, restored workspaces and repaired sessions
+func TestWindowsGetProviderOptions(t *testing.T) {
+	if err := sshsdk.RegisterAuthProvider(certProvider{}); err != nil {
+		t.Fatal(err)
+	}
+	defer sshsdk.UnregisterAuthProvider("test-cert")
+
+	server := config.Server{Name: "web1", Hostname: "web1.example.com", Port: 22, Username: "ops", AuthType: "key", KeyPath: "~/.ssh/id_ed25519", AuthProvider: "test-cert"}
+	want := "-o CertificateFile=/tmp/web1-cert.pub"
+
+	single, err := SSHCommand(server)
+	if err != nil || !strings.Contains(single, want) {
+		t.Fatalf("SSHCommand() = %q, %v, want the provider's options", single, err)
+	}
+
+	group, _ := GroupServers([]config.Server{server}, nil, nil)
+	windows := map[string]tmux.Server{
+		"group connect":     group[0],
+		"workspace restore": TmuxServer(server, nil),
+		"session repair":    TmuxServers([]config.Server{server}, nil)[0],
+	}
+	for path, window := range windows {
+		command, err := window.(tmux.CommandBuilder).SSHCommand()
+		if err != nil || command != single {
+			t.Errorf("%s command = %q, %v, want %q", path, command, err, single)
+		}
+	}
+}
@@ -1,15 +1,22 @@
 package connection
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"golang.org/x/crypto/ssh"
-	"sshm/internal/auth"
 	"sshm/internal/config"
 	"sshm/internal/history"
+	"sshm/internal/hostkey"
+	"sshm/internal/monitor"
+	"sshm/internal/power"
+	"sshm/internal/retry"
+	"sshm/internal/secrets"
+	"sshm/internal/shellquote"
 	sshsdk "sshm/internal/ssh"
 	"sshm/internal/tmux"
 )
@@ -18,6 +25,7 @@ import (
 type Manager struct {
 	historyManager *history.HistoryManager
 	tmuxManager    *tmux.Manager
+	retryPolicy    config.RetryPolicy
 }
 
 // NewManager creates a new connection manager with history tracking
@@ -53,6 +61,11 @@ func (m *Manager) Close() error {
 	return nil
 }
 
+// SetRetryPolicy sets the global retry policy used for connectivity tests
+func (m *Manager) SetRetryPolicy(policy config.RetryPolicy) {
+	m.retryPolicy = policy
+}
+
 // ConnectToServer connects to a single server with history tracking
 func (m *Manager) ConnectToServer(server config.Server) (string, bool, error) {
 	startTime := time.Now()
@@ -77,7 +90,7 @@ func (m *Manager) ConnectToServer(server config.Server) (string, bool, error) {
 	}
 
 	// Test SSH connectivity first
-	if err := m.testSSHConnectivity(server); err != nil {
+	if err := m.testSSHConnectivityWithRetry(server); err != nil {
 		// Update history with failure
 		if connectionID > 0 {
 			m.historyManager.UpdateConnectionEnd(connectionID, time.Now(), "failed", err.Error())
@@ -86,7 +99,7 @@ func (m *Manager) ConnectToServer(server config.Server) (string, bool, error) {
 	}
 
 	// Build SSH command
-	sshCommand, err := buildSSHCommand(server)
+	sshCommand, env, err := buildSSHCommand(server)
 	if err != nil {
 		// Update history with failure
 		if connectionID > 0 {
@@ -96,7 +109,7 @@ func (m *Manager) ConnectToServer(server config.Server) (string, bool, error) {
 	}
 
 	// Create tmux session
-	sessionName, wasExisting, err := m.tmuxManager.ConnectToServer(server.Name, sshCommand)
+	sessionName, wasExisting, err := ConnectSession(m.tmuxManager, server, sshCommand, env...)
 	if err != nil {
 		// Update history with failure
 		if connectionID > 0 {
@@ -117,6 +130,13 @@ func (m *Manager) ConnectToServer(server config.Server) (string, bool, error) {
 
 // ConnectToProfile connects to multiple servers in a profile with history tracking
 func (m *Manager) ConnectToProfile(profileName string, servers []config.Server) (string, bool, error) {
+	// No window opens for a server whose current login banner wasn't accepted
+	acks := m.AcknowledgedBanners(servers)
+	if refused := acks.Unacknowledged(servers); len(refused) > 0 {
+		return "", false, fmt.Errorf("%w for %s; connect to each on its own to accept it",
+			ErrBannerNotAcknowledged, strings.Join(refused, ", "))
+	}
+
 	startTime := time.Now()
 
 	// Record connection attempt for the profile
@@ -159,7 +179,7 @@ func (m *Manager) ConnectToProfile(profileName string, servers []config.Server)
 		}
 		
 		// Test connectivity and record failure if needed
-		if connectErr := m.testSSHConnectivity(server); connectErr != nil {
+		if connectErr := m.testSSHConnectivityWithRetry(server); connectErr != nil {
 			connectivityErrors = append(connectivityErrors, 
 				fmt.Sprintf("%s: %v", server.Name, connectErr))
 			
@@ -183,13 +203,14 @@ func (m *Manager) ConnectToProfile(profileName string, servers []config.Server)
 	}
 
 	// Convert config.Server slice to tmux.Server interface slice
-	tmuxServers := make([]tmux.Server, len(servers))
-	for i, server := range servers {
-		tmuxServers[i] = &server
-	}
+	tmuxServers := TmuxServers(servers, acks)
 
 	// Create group session
 	sessionName, wasExisting, err := m.tmuxManager.ConnectToProfile(profileName, tmuxServers)
+	if err == nil {
+		GuardPastes(m.tmuxManager, sessionName, servers)
+		RecordSession(m.tmuxManager, sessionName, servers)
+	}
 	if err != nil {
 		errorMsg := fmt.Sprintf("failed to create group session: %v", err)
 		
@@ -249,30 +270,149 @@ func (m *Manager) AttachSession(sessionName string) error {
 	return m.tmuxManager.AttachSession(sessionName)
 }
 
-// GetHistoryManager returns the history manager (for testing)
+// FetchBanner retrieves the login banner a server presents before authentication
+func (m *Manager) FetchBanner(server config.Server) (string, error) {
+	clientConfig, err := dialConfig(server, 10*time.Second)
+	if err != nil {
+		return "", err
+	}
+	return sshsdk.FetchBanner(clientConfig)
+}
+
+// dialConfig returns how sshm dials a server itself: at its effective hostname,
+// through its jump hosts, with its host key checked against known_hosts under
+// the name ssh stores it
+func dialConfig(server config.Server, timeout time.Duration) (sshsdk.ClientConfig, error) {
+	clientConfig := sshsdk.ClientConfig{
+		Hostname: server.GetEffectiveHostname(),
+		Port:     server.Port,
+		Username: server.Username,
+		Timeout:  timeout,
+	}
+	if strings.TrimSpace(server.ResolveTo) != "" {
+		clientConfig.HostKeyAlias = server.Hostname
+	}
+	if path, err := hostkey.DefaultKnownHostsPath(); err == nil {
+		clientConfig.KnownHostsFile = path
+	}
+
+	jumps, err := monitor.JumpHops(server)
+	if err != nil {
+		return clientConfig, err
+	}
+	clientConfig.Jumps = jumps
+	return clientConfig, nil
+}
+
+// RunPowerAction runs a power action (reboot, shutdown or a custom command) on a
+// server over ssh and records it in the connection history
+func (m *Manager) RunPowerAction(server config.Server, action, command string) (string, error) {
+	remoteCommand, err := power.Command(action, command)
+	if err != nil {
+		return "", err
+	}
+
+	historyEntry := history.ConnectionHistoryEntry{
+		ServerName:     server.Name,
+		Host:           server.Hostname,
+		User:           server.Username,
+		Port:           server.Port,
+		ConnectionType: "power:" + action,
+		Status:         "attempting",
+		StartTime:      time.Now(),
+	}
+	connectionID, recordErr := m.historyManager.RecordConnection(historyEntry)
+
+	output, err := power.Run(server, remoteCommand)
+	if recordErr == nil && connectionID > 0 {
+		if err != nil {
+			m.historyManager.UpdateConnectionEnd(connectionID, time.Now(), "failed", err.Error())
+		} else {
+			m.historyManager.UpdateConnectionEnd(connectionID, time.Now(), "success", "")
+		}
+	}
+
+	// Keep the last result per server and command for the results panel
+	result := history.CommandResult{ServerName: server.Name, Action: action, Status: history.CommandSucceeded, Output: output}
+	if action == power.ActionCustom {
+		result.Command = command
+	}
+	if err != nil {
+		result.Status = history.CommandFailed
+		result.Error = err.Error()
+	}
+	m.historyManager.RecordCommandResult(result)
+	return output, err
+}
+
+// AcknowledgeBanner records that the user accepted a server's login banner
+func (m *Manager) AcknowledgeBanner(server config.Server, banner string) error {
+	_, err := m.historyManager.RecordBannerAcknowledgment(history.BannerAcknowledgment{
+		ServerName: server.Name,
+		Host:       server.Hostname,
+		User:       server.Username,
+		Banner:     banner,
+	})
+	return err
+}
+
+// GetHistoryManager returns the history manager
 func (m *Manager) GetHistoryManager() *history.HistoryManager {
 	return m.historyManager
 }
 
+// testSSHConnectivityWithRetry tests SSH connectivity, retrying according to the server's retry policy
+func (m *Manager) testSSHConnectivityWithRetry(server config.Server) error {
+	policyConfig := m.retryPolicy
+	if server.Retry != nil {
+		policyConfig = *server.Retry
+	}
+
+	policy, err := retry.FromConfig(policyConfig)
+	if err != nil {
+		return fmt.Errorf("invalid retry policy: %w", err)
+	}
+
+	_, err = retry.Do(context.Background(), policy, sshsdk.ClassifyError, func() error {
+		return m.testSSHConnectivity(server)
+	})
+	return err
+}
+
 // testSSHConnectivity tests SSH connectivity to a server
 func (m *Manager) testSSHConnectivity(server config.Server) error {
-	// Create SSH client configuration
-	sshConfig := sshsdk.ClientConfig{
-		Hostname: server.Hostname,
-		Port:     server.Port,
-		Username: server.Username,
-		Timeout:  10 * time.Second, // 10 second timeout for connectivity test
+	// Create SSH client configuration, dialed like banner fetches
+	sshConfig, err := dialConfig(server, 10*time.Second) // 10 second timeout for connectivity test
+	if err != nil {
+		return err
+	}
+	// Reuse the status checks' connection instead of authenticating again
+	sshConfig.Multiplex = server.ControlMaster
+
+	// Servers with an auth provider authenticate through it
+	if server.AuthProvider != "" {
+		authMethod, err := sshsdk.ProviderAuthMethod(server.AuthProvider, monitor.ProviderRequest(server))
+		if err != nil {
+			return fmt.Errorf("failed to create provider auth: %w", err)
+		}
+		return sshsdk.TestConnection(sshConfig, authMethod)
 	}
 
 	// Determine authentication method
 	var authMethod ssh.AuthMethod
-	var err error
 
 	switch server.AuthType {
 	case "key":
 		if server.KeyPath != "" {
-			// For connectivity test, try without passphrase first
-			authMethod, err = sshsdk.NewKeyAuth(server.KeyPath, "")
+			// For connectivity test, use the passphrase from the keyring or try without one
+			passphrase := ""
+			if secrets.Referenced(server) {
+				passphrase, err = secrets.Lookup(server)
+				if err != nil {
+					return fmt.Errorf("failed to retrieve passphrase from keyring: %w", err)
+				}
+			}
+			authMethod, err = sshsdk.NewKeyAuth(server.KeyPath, passphrase)
 			if err != nil {
 				return fmt.Errorf("failed to create key auth: %w", err)
 			}
@@ -285,15 +425,9 @@ func (m *Manager) testSSHConnectivity(server config.Server) error {
 		}
 	case "password":
 		// For connectivity test, try to retrieve password from keyring
-		if server.UseKeyring && server.KeyringID != "" {
-			// Initialize password manager
-			passwordManager, err := auth.NewPasswordManager("auto") // Use auto to select best available backend
-			if err != nil {
-				return fmt.Errorf("failed to initialize password manager: %w", err)
-			}
-
+		if secrets.Referenced(server) {
 			// Retrieve password from keyring
-			password, err := passwordManager.RetrieveServerPassword(&server)
+			password, err := secrets.Lookup(server)
 			if err != nil {
 				return fmt.Errorf("failed to retrieve password from keyring: %w", err)
 			}
@@ -311,51 +445,87 @@ func (m *Manager) testSSHConnectivity(server config.Server) error {
 	return sshsdk.TestConnection(sshConfig, authMethod)
 }
 
-// buildSSHCommand builds the SSH command string for a server
-func buildSSHCommand(server config.Server) (string, error) {
-	if err := server.Validate(); err != nil {
-		return "", fmt.Errorf("invalid server configuration: %w", err)
+// ConnectSession creates the server's tmux session, laid out by its session
+// template when it has one, or returns an existing session for reattachment.
+// env is set in the environment of the session's windows, see tmux.Environment.
+func ConnectSession(tm *tmux.Manager, server config.Server, sshCommand string, env ...string) (string, bool, error) {
+	var sessionName string
+	var wasExisting bool
+	var err error
+	startup := server.StartupInput()
+	if server.Session == nil || server.IsRestricted() {
+		sessionName, wasExisting, err = tm.ConnectToServer(server.Name, sshCommand, env...)
+		if err == nil && !wasExisting && len(startup) > 0 {
+			go tm.SendWhenConnected(sessionName+":0", startup, tmux.StartupDelay)
+		}
+	} else {
+		windows := make([]tmux.TemplateWindow, 0, len(server.Session.Windows))
+		for _, window := range server.Session.Windows {
+			windows = append(windows, tmux.TemplateWindow{Name: window.Name, Startup: startup, Command: window.Command})
+		}
+		sessionName, wasExisting, err = tm.ConnectWithTemplate(server.Name, sshCommand, windows, server.Session.CommandDelay(), env...)
+	}
+	if err == nil {
+		GuardPastes(tm, sessionName, []config.Server{server})
+		RecordSession(tm, sessionName, []config.Server{server})
 	}
+	return sessionName, wasExisting, err
+}
 
-	var sshCmd string
+// sshmExecutable is the sshm binary tmux runs for guarded pastes and session
+// recordings. Sessions are only set up once the CLI sets it, so tests never
+// bind a test binary.
+var sshmExecutable string
 
-	// Handle password authentication with keyring
-	if server.AuthType == "password" && server.UseKeyring && server.KeyringID != "" {
-		// Try to use sshpass for non-interactive password authentication
-		// Note: This requires sshpass to be installed on the system
-		
-		// Initialize password manager to retrieve password
-		passwordManager, err := auth.NewPasswordManager("auto")
-		if err != nil {
-			// Fall back to interactive SSH if password manager fails
-			sshCmd = fmt.Sprintf("ssh -t %s@%s", server.Username, server.Hostname)
-		} else {
-			password, err := passwordManager.RetrieveServerPassword(&server)
-			if err != nil {
-				// Fall back to interactive SSH if password retrieval fails
-				sshCmd = fmt.Sprintf("ssh -t %s@%s", server.Username, server.Hostname)
-			} else {
-				// Use sshpass with retrieved password
-				sshCmd = fmt.Sprintf("sshpass -p '%s' ssh -t %s@%s", password, server.Username, server.Hostname)
-			}
+// SetExecutable sets the sshm binary tmux runs to check pastes into protected
+// sessions and to record sessions
+func SetExecutable(path string) {
+	sshmExecutable = path
+}
+
+// GuardPastes makes pastes into the session ask first when it holds a
+// protected server. Failing to install the guard doesn't fail the connection.
+func GuardPastes(tm *tmux.Manager, sessionName string, servers []config.Server) {
+	if sshmExecutable == "" {
+		return
+	}
+	for _, server := range servers {
+		if server.Protected {
+			tm.GuardPastes(sessionName, shellquote.Quote(sshmExecutable)+" sessions paste-guard")
+			return
 		}
-	} else {
-		// Build base SSH command with pseudo-terminal allocation
-		sshCmd = fmt.Sprintf("ssh -t %s@%s", server.Username, server.Hostname)
 	}
+}
 
-	// Add port if not default
-	if server.Port != 22 {
-		sshCmd += fmt.Sprintf(" -p %d", server.Port)
+// RecordSession records the session's windows into the recordings directory
+// when it holds a server with record set. Failing to start recording doesn't
+// fail the connection.
+func RecordSession(tm *tmux.Manager, sessionName string, servers []config.Server) {
+	if sshmExecutable == "" {
+		return
 	}
-
-	// Add key-specific options
-	if server.AuthType == "key" && server.KeyPath != "" {
-		sshCmd += fmt.Sprintf(" -i %s", server.KeyPath)
+	for _, server := range servers {
+		if server.Record {
+			tm.RecordPanes(sessionName, shellquote.Quote(sshmExecutable)+" recordings capture")
+			return
+		}
 	}
+}
 
-	// Add common SSH options
-	sshCmd += " -o ServerAliveInterval=60 -o ServerAliveCountMax=3"
-
-	return sshCmd, nil
+// buildSSHCommand builds the SSH command string for a server, and the window
+// environment it needs
+func buildSSHCommand(server config.Server) (string, []string, error) {
+	// Handle password or key passphrase authentication with keyring
+	var prefix string
+	var env []string
+	if secrets.Referenced(server) {
+		// Try to use sshpass to answer the password or passphrase prompt
+		// Note: This requires sshpass to be installed on the system. Without
+		// the secret, fall back to interactive SSH.
+		if flags, secret, err := secrets.SSHPass(server); err == nil {
+			prefix, env = sshpassPrefix(flags), sshpassEnv(secret)
+		}
+	}
+	command, err := sshCommand(server, prefix)
+	return command, env, err
 }
\ No newline at end of file
@@ -0,0 +1,70 @@
+package connection
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"sshm/internal/config"
+	sshmexec "sshm/internal/exec"
+	"sshm/internal/history"
+	"sshm/internal/power"
+)
+
+// RunCommand runs a command on the servers in parallel, recording each run in
+// the connection history and keeping its result for the command results panel
+// like a custom power action
+func (m *Manager) RunCommand(ctx context.Context, servers []config.Server, command string, opts sshmexec.Options) ([]sshmexec.Result, error) {
+	var mu sync.Mutex
+	connectionIDs := make(map[string]int64, len(servers))
+
+	onStart, onResult := opts.OnStart, opts.OnResult
+	opts.OnStart = func(server config.Server) {
+		id, err := m.historyManager.RecordConnection(history.ConnectionHistoryEntry{
+			ServerName:     server.Name,
+			Host:           server.Hostname,
+			User:           server.Username,
+			Port:           server.Port,
+			ConnectionType: "power:" + power.ActionCustom,
+			Status:         "attempting",
+			StartTime:      time.Now(),
+		})
+		if err == nil && id > 0 {
+			mu.Lock()
+			connectionIDs[server.Name] = id
+			mu.Unlock()
+		}
+		if onStart != nil {
+			onStart(server)
+		}
+	}
+	opts.OnResult = func(result sshmexec.Result) {
+		mu.Lock()
+		id := connectionIDs[result.Server.Name]
+		mu.Unlock()
+
+		commandResult := history.CommandResult{
+			ServerName: result.Server.Name,
+			Action:     power.ActionCustom,
+			Command:    command,
+			Status:     history.CommandSucceeded,
+			Output:     result.Output,
+		}
+		if result.Failed() {
+			commandResult.Status = history.CommandFailed
+			commandResult.Error = result.Err.Error()
+		}
+		if id > 0 {
+			status := "success"
+			if result.Failed() {
+				status = "failed"
+			}
+			m.historyManager.UpdateConnectionEnd(id, time.Now(), status, commandResult.Error)
+		}
+		m.historyManager.RecordCommandResult(commandResult)
+		if onResult != nil {
+			onResult(result)
+		}
+	}
+	return sshmexec.Run(ctx, servers, command, opts)
+}
@@ -0,0 +1,57 @@
+package connection
+
+import (
+	"os/exec"
+	"strings"
+
+	"sshm/internal/config"
+	"sshm/internal/secrets"
+	"sshm/internal/tmux"
+)
+
+// lookPath finds sshpass (variable to allow mocking in tests)
+var lookPath = exec.LookPath
+
+// CanAnswerPrompts reports whether password and passphrase prompts can be
+// answered for the user, which takes sshpass
+func CanAnswerPrompts() bool {
+	_, err := lookPath("sshpass")
+	return err == nil
+}
+
+// sshpassPrefix returns the sshpass command that answers ssh's prompt, to put
+// in front of ssh. It reads the secret from SSHPASS, which sshpassEnv sets in
+// the window's environment: the command itself is typed into the window and
+// must not hold it.
+func sshpassPrefix(flags []string) string {
+	return strings.Join(append(append([]string{"sshpass"}, flags...), "-e"), " ")
+}
+
+// sshpassEnv returns the window environment passing secret to sshpassPrefix
+func sshpassEnv(secret string) []string {
+	return []string{"SSHPASS=" + secret}
+}
+
+// GroupServers returns the servers of a group connect for tmux. The windows of
+// servers with a collected secret have sshpass answer their prompt; the names
+// of the servers whose windows still wait for it to be typed are returned too.
+// Servers whose login banner must be accepted first need it in acks.
+func GroupServers(servers []config.Server, collected *secrets.Collected, acks BannerAcks) ([]tmux.Server, []string) {
+	canAnswer := CanAnswerPrompts()
+	tmuxServers := make([]tmux.Server, len(servers))
+	var manual []string
+	for i := range servers {
+		server := &servers[i]
+		tmuxServers[i] = commandServer{Server: server, bannerAcked: acks[server.Name]}
+		if !secrets.Needs(*server) {
+			continue
+		}
+		secret, ok := collected.Secret(server.Name)
+		if !ok || !canAnswer {
+			manual = append(manual, server.Name)
+			continue
+		}
+		tmuxServers[i] = commandServer{Server: server, prefix: sshpassPrefix(secrets.SSHPassFlags(*server)), env: sshpassEnv(secret), bannerAcked: acks[server.Name]}
+	}
+	return tmuxServers, manual
+}
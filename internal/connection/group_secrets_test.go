@@ -0,0 +1,67 @@
+package connection
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"sshm/internal/config"
+	"sshm/internal/secrets"
+	"sshm/internal/tmux"
+)
+
+func TestGroupServersAnswerCollectedSecrets(t *testing.T) {
+	defer secrets.Use(secrets.NewMemoryStore())()
+	original := lookPath
+	defer func() { lookPath = original }()
+	lookPath = func(file string) (string, error) { return "/usr/bin/" + file, nil }
+
+	servers := []config.Server{
+		{Name: "web1", Hostname: "web1.example.com", Port: 22, Username: "ops", AuthType: "password"},
+		{Name: "db1", Hostname: "db1.example.com", Port: 22, Username: "ops", AuthType: "key", KeyPath: "~/.ssh/db", PassphraseProtected: true},
+		{Name: "app1", Hostname: "app1.example.com", Port: 22, Username: "ops", AuthType: "key", KeyPath: "~/.ssh/id_ed25519"},
+	}
+	collected := secrets.Collect(servers)
+	collected.Provide(collected.Missing[0], "it's secret")
+
+	tmuxServers, manual := GroupServers(servers, collected, nil)
+	if !reflect.DeepEqual(manual, []string{"db1"}) {
+		t.Errorf("Expected db1 to need its passphrase typed, got %v", manual)
+	}
+	command, err := tmuxServers[0].(tmux.CommandBuilder).SSHCommand()
+	if err != nil || !strings.HasPrefix(command, "sshpass -e ssh -t ops@web1.example.com") || strings.Contains(command, "secret") {
+		t.Errorf("Expected sshpass to answer web1's password prompt, got %q (%v)", command, err)
+	}
+	// The secret goes to the window's environment, never into the typed command
+	if env := tmuxServers[0].(tmux.Environment).WindowEnvironment(); !reflect.DeepEqual(env, []string{"SSHPASS=it's secret"}) {
+		t.Errorf("Expected web1's password in its window environment, got %v", env)
+	}
+	if command, _ := tmuxServers[2].(tmux.CommandBuilder).SSHCommand(); strings.Contains(command, "sshpass") {
+		t.Errorf("Expected a server without a secret to connect as is, got %q", command)
+	}
+
+	// Without sshpass every window prompts
+	lookPath = func(file string) (string, error) { return "", errors.New("not found") }
+	if _, manual := GroupServers(servers, collected, nil); !reflect.DeepEqual(manual, []string{"web1", "db1"}) {
+		t.Errorf("Expected every server with a secret to be typed in without sshpass, got %v", manual)
+	}
+}
+
+func TestGroupServersRefuseUnacknowledgedBanners(t *testing.T) {
+	servers := []config.Server{
+		{Name: "web1", Hostname: "web1.example.com", Port: 22, Username: "ops", AuthType: "key", KeyPath: "~/.ssh/id_ed25519", RequireBannerAck: true},
+		{Name: "db1", Hostname: "db1.example.com", Port: 22, Username: "ops", AuthType: "key", KeyPath: "~/.ssh/id_ed25519", RequireBannerAck: true},
+	}
+
+	tmuxServers, _ := GroupServers(servers, nil, BannerAcks{"db1": true})
+	if _, err := tmuxServers[0].(tmux.CommandBuilder).SSHCommand(); !errors.Is(err, ErrBannerNotAcknowledged) {
+		t.Errorf("Expected web1's window to be refused until its banner is accepted, got %v", err)
+	}
+	if _, err := tmuxServers[1].(tmux.CommandBuilder).SSHCommand(); err != nil {
+		t.Errorf("Expected db1's accepted banner to let its window connect, got %v", err)
+	}
+	if refused := (BannerAcks{"db1": true}).Unacknowledged(servers); !reflect.DeepEqual(refused, []string{"web1"}) {
+		t.Errorf("Unacknowledged() = %v, want [web1]", refused)
+	}
+}
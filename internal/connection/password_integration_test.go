@@ -185,7 +185,7 @@ func TestPasswordIntegration_BuildSSHCommand(t *testing.T) {
 			}
 
 			// Build SSH command
-			sshCmd, err := buildSSHCommand(tt.server)
+			sshCmd, env, err := buildSSHCommand(tt.server)
 			if err != nil {
 				t.Fatalf("buildSSHCommand() error = %v", err)
 			}
@@ -194,11 +194,14 @@ func TestPasswordIntegration_BuildSSHCommand(t *testing.T) {
 
 			// Verify command structure
 			if tt.expectSshpass {
-				if !contains(sshCmd, "sshpass -p") {
+				if !contains(sshCmd, "sshpass -e") {
 					t.Errorf("Expected command to use sshpass, got: %s", sshCmd)
 				}
-				if !contains(sshCmd, tt.password) {
-					t.Errorf("Expected command to contain password, got: %s", sshCmd)
+				if contains(sshCmd, tt.password) {
+					t.Errorf("Expected the password to stay out of the typed command, got: %s", sshCmd)
+				}
+				if len(env) != 1 || env[0] != "SSHPASS="+tt.password {
+					t.Errorf("Expected the password in the window environment, got: %v", env)
 				}
 			}
 
@@ -307,7 +310,7 @@ func TestPasswordIntegration_ManagerOperations(t *testing.T) {
 		}
 
 		// 5. Test SSH command generation with updated password
-		sshCmd, err := buildSSHCommand(*server)
+		sshCmd, _, err := buildSSHCommand(*server)
 		if err != nil {
 			t.Fatalf("buildSSHCommand() error = %v", err)
 		}
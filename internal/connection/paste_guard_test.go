@@ -0,0 +1,37 @@
+package connection
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+
+	"sshm/internal/config"
+	"sshm/internal/tmux"
+)
+
+func TestGuardPastes(t *testing.T) {
+	original := tmux.GetExecCommand()
+	defer tmux.SetExecCommand(original)
+	var calls []string
+	tmux.SetExecCommand(func(name string, arg ...string) *exec.Cmd {
+		calls = append(calls, strings.Join(arg, " "))
+		return exec.Command("true")
+	})
+	SetExecutable("/opt/it's/sshm")
+	defer SetExecutable("")
+
+	tm := tmux.NewManager()
+	GuardPastes(tm, "dev", []config.Server{{Name: "dev"}})
+	if len(calls) != 0 {
+		t.Fatalf("Expected unprotected sessions to be left alone, got %v", calls)
+	}
+
+	GuardPastes(tm, "prod", []config.Server{{Name: "web"}, {Name: "db", Protected: true}})
+	if len(calls) != 2 || calls[0] != "set-option -t prod @sshm-paste-guard 1" {
+		t.Fatalf("Expected the session to be guarded, got %v", calls)
+	}
+	// Shell-quoted, then escaped for tmux's double quotes
+	if !strings.Contains(calls[1], `"'/opt/it'\\''s/sshm' sessions paste-guard`) {
+		t.Errorf("Expected the quoted executable in the binding, got %q", calls[1])
+	}
+}
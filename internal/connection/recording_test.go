@@ -0,0 +1,36 @@
+package connection
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+
+	"sshm/internal/config"
+	"sshm/internal/tmux"
+)
+
+func TestRecordSession(t *testing.T) {
+	original := tmux.GetExecCommand()
+	defer tmux.SetExecCommand(original)
+	var calls []string
+	tmux.SetExecCommand(func(name string, arg ...string) *exec.Cmd {
+		calls = append(calls, strings.Join(arg, " "))
+		if arg[0] == "list-panes" {
+			return exec.Command("printf", "%%1\\t0\\tweb\\t80\\t24\\n")
+		}
+		return exec.Command("true")
+	})
+	SetExecutable("/opt/sshm")
+	defer SetExecutable("")
+
+	tm := tmux.NewManager()
+	RecordSession(tm, "dev", []config.Server{{Name: "dev"}})
+	if len(calls) != 0 {
+		t.Fatalf("Expected sessions without recorded servers to be left alone, got %v", calls)
+	}
+
+	RecordSession(tm, "prod", []config.Server{{Name: "web", Record: true}})
+	if len(calls) != 2 || !strings.HasPrefix(calls[1], "pipe-pane -t %1 '/opt/sshm' recordings capture 'prod' 'web' 80 24") {
+		t.Errorf("Expected the window to be piped to the recorder, got %v", calls)
+	}
+}
@@ -0,0 +1,37 @@
+package connection
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"sshm/internal/config"
+	"sshm/internal/secrets"
+)
+
+func TestBuildSSHCommandAnswersPassphraseFromKeyring(t *testing.T) {
+	defer secrets.Use(secrets.NewMemoryStore())()
+
+	server := config.Server{Name: "locked-db", Hostname: "db.example.com", Port: 22, Username: "ops",
+		AuthType: "key", KeyPath: "~/.ssh/id_ed25519"}
+	if err := secrets.Save(&server, "", "it's locked"); err != nil {
+		t.Fatal(err)
+	}
+
+	sshCmd, env, err := buildSSHCommand(server)
+	if err != nil {
+		t.Fatalf("buildSSHCommand() error = %v", err)
+	}
+	if !strings.HasPrefix(sshCmd, "sshpass -P passphrase -e ssh -t ops@db.example.com") {
+		t.Errorf("Expected sshpass to answer the passphrase prompt, got: %s", sshCmd)
+	}
+	if !reflect.DeepEqual(env, []string{"SSHPASS=it's locked"}) {
+		t.Errorf("Expected the passphrase in the window environment, got %v", env)
+	}
+
+	// A missing secret falls back to the interactive prompt
+	server.KeyringID = "passphrase-missing"
+	if sshCmd, env, _ := buildSSHCommand(server); strings.Contains(sshCmd, "sshpass") || env != nil {
+		t.Errorf("Expected interactive SSH without the secret, got: %s", sshCmd)
+	}
+}
@@ -0,0 +1,344 @@
+package daemon
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"sshm/internal/config"
+	"sshm/internal/events"
+	"sshm/internal/history"
+	"sshm/internal/monitor"
+	"sshm/internal/sshconfig"
+	"sshm/internal/statuscache"
+	"sshm/internal/tmux"
+	"sshm/internal/warmpool"
+	"sshm/internal/webhook"
+)
+
+// DefaultInterval is how often servers are checked when not configured
+const DefaultInterval = 60 * time.Second
+
+// maxConcurrentChecks limits how many servers are checked at once
+const maxConcurrentChecks = 5
+
+// ServerStatus is the last known status of one server
+type ServerStatus struct {
+	Name      string        `json:"name"`
+	Hostname  string        `json:"hostname"`
+	Status    string        `json:"status"`
+	Latency   time.Duration `json:"latency,omitempty"`
+	CheckedAt time.Time     `json:"checked_at"`
+	Address   string        `json:"address,omitempty"`
+	Profiles  []string      `json:"profiles,omitempty"`
+}
+
+// Session is an active tmux session
+type Session struct {
+	Name     string `json:"name"`
+	Windows  int    `json:"windows"`
+	Attached bool   `json:"attached"`
+}
+
+// Snapshot is the state shown on the status page
+type Snapshot struct {
+	UpdatedAt time.Time      `json:"updated_at"`
+	Servers   []ServerStatus `json:"servers"`
+	Sessions  []Session      `json:"sessions"`
+}
+
+// Daemon periodically checks every server, keeps the latest results for the
+// status page and writes them to the status cache for 'sshm statusline'
+type Daemon struct {
+	interval  time.Duration
+	cachePath string
+
+	// Variables to allow mocking in tests
+	loadConfig   func() (*config.Config, error)
+	checkServer  func(config.Server, config.RetryPolicy) (string, int, time.Duration)
+	checkTCP     func(config.Server) (string, time.Duration)
+	listSessions func() ([]tmux.SessionInfo, error)
+
+	// Servers in stealth profiles are only checked when their window comes due
+	schedule *monitor.Schedule
+
+	// Receives the results of each round for the stats view, nil when not recorded
+	history *history.HistoryManager
+
+	// Calls the webhooks of servers going online or offline
+	webhooks *webhook.Tracker
+
+	// Receives a status_changed event per changed server, nil when not streamed
+	events *events.Emitter
+
+	// Keeps connections open to warm and watched servers when the pool is enabled
+	warm *warmpool.Pool
+
+	// Receives failed syncs with the SSH config, nil when not reported
+	onSyncError func(err error)
+
+	mu       sync.RWMutex
+	snapshot Snapshot
+}
+
+// New creates a daemon checking servers every interval (DefaultInterval when <= 0)
+// and writing results to the status cache at cachePath (not written when empty)
+func New(interval time.Duration, cachePath string) *Daemon {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	manager := tmux.NewManager()
+	return &Daemon{
+		interval:    interval,
+		cachePath:   cachePath,
+		loadConfig:  config.Load,
+		checkServer: monitor.CheckServer,
+		checkTCP:    monitor.CheckTCP,
+		schedule:    monitor.NewSchedule(),
+		webhooks:    webhook.NewTracker(),
+		warm:        warmpool.New(),
+		listSessions: func() ([]tmux.SessionInfo, error) {
+			if !manager.IsAvailable() {
+				return nil, nil
+			}
+			return manager.RefreshSessionInfo()
+		},
+	}
+}
+
+// SetHistory records the results of each round in the history database, where
+// 'sshm stats' computes availability from them
+func (d *Daemon) SetHistory(h *history.HistoryManager) {
+	d.history = h
+}
+
+// SetEvents streams status changes as machine-readable events (sshm daemon --events)
+func (d *Daemon) SetEvents(e *events.Emitter) {
+	d.events = e
+}
+
+// OnWebhookError receives the webhook calls that failed
+func (d *Daemon) OnWebhookError(fn func(server string, hook config.Webhook, err error)) {
+	d.webhooks.OnError = fn
+}
+
+// OnSSHSyncError receives the syncs with the SSH config that failed
+func (d *Daemon) OnSSHSyncError(fn func(err error)) {
+	d.onSyncError = fn
+}
+
+// Run checks all servers immediately and then every interval until ctx is cancelled
+func (d *Daemon) Run(ctx context.Context, onRound func(Snapshot)) error {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		snapshot, err := d.CheckOnce()
+		if err == nil && onRound != nil {
+			onRound(snapshot)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// CheckOnce reloads the configuration, so edits are picked up without a restart,
+// checks every server that hasn't expired and publishes the results. Servers in
+// stealth profiles get a TCP connect once per stealth window and keep their last
+// result in between.
+func (d *Daemon) CheckOnce() (Snapshot, error) {
+	cfg, err := d.loadConfig()
+	if err != nil {
+		return d.Snapshot(), err
+	}
+	d.warm.Refresh(cfg)
+	if cfg.SSHSync.Enabled {
+		if err := syncSSHConfig(cfg); err != nil && d.onSyncError != nil {
+			d.onSyncError(err)
+		}
+	}
+
+	now := time.Now()
+	var servers []config.Server
+	for _, server := range cfg.GetServers() {
+		if !server.IsExpired(now) {
+			servers = append(servers, server)
+		}
+	}
+
+	previous := make(map[string]ServerStatus)
+	for _, status := range d.Snapshot().Servers {
+		previous[status.Name] = status
+	}
+
+	results := make([]ServerStatus, len(servers))
+	limiter := monitor.NewRateLimiter(cfg.StatusChecks.MaxPerMinute)
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, maxConcurrentChecks)
+	for i, server := range servers {
+		plan := monitor.PlanFor(cfg, server, d.interval)
+		if plan.Stealth && !d.schedule.Due(server.Name, plan, now) {
+			last, ok := previous[server.Name]
+			if !ok {
+				last = ServerStatus{Name: server.Name, Hostname: server.Hostname, Status: "scheduled"}
+			}
+			last.Profiles = serverProfiles(cfg, server.Name)
+			results[i] = last
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, server config.Server) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+			limiter.Wait(context.Background())
+
+			var status string
+			var latency time.Duration
+			if plan.Stealth {
+				status, latency = d.checkTCP(server)
+			} else {
+				status, _, latency = d.checkServer(server, cfg.RetryPolicyFor(server))
+			}
+			address, _ := monitor.LastAddress(server.Name)
+			results[i] = ServerStatus{
+				Name:      server.Name,
+				Hostname:  server.Hostname,
+				Status:    status,
+				Latency:   latency,
+				CheckedAt: time.Now(),
+				Address:   address,
+				Profiles:  serverProfiles(cfg, server.Name),
+			}
+		}(i, server)
+	}
+	wg.Wait()
+	sort.Slice(results, func(i, j int) bool { return results[i].Name < results[j].Name })
+
+	snapshot := Snapshot{UpdatedAt: time.Now(), Servers: results, Sessions: d.sessions()}
+
+	d.mu.Lock()
+	d.snapshot = snapshot
+	d.mu.Unlock()
+
+	d.saveStatusCache(snapshot)
+	d.recordStatusSamples(snapshot, now)
+	d.fireWebhooks(servers, snapshot, now)
+	d.emitStatusChanges(previous, snapshot)
+	return snapshot, nil
+}
+
+// emitStatusChanges reports the servers whose status differs from the previous round
+func (d *Daemon) emitStatusChanges(previous map[string]ServerStatus, snapshot Snapshot) {
+	for _, status := range snapshot.Servers {
+		if last := previous[status.Name].Status; status.Status != last {
+			d.events.Emit(events.Event{Type: events.StatusChanged, Server: status.Name, Status: status.Status, Previous: last})
+		}
+	}
+}
+
+// Snapshot returns the results of the latest check round
+func (d *Daemon) Snapshot() Snapshot {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.snapshot
+}
+
+// sessions lists active tmux sessions; tmux being unavailable is not an error
+func (d *Daemon) sessions() []Session {
+	infos, err := d.listSessions()
+	if err != nil {
+		return nil
+	}
+	sessions := make([]Session, 0, len(infos))
+	for _, info := range infos {
+		sessions = append(sessions, Session{
+			Name:     info.Name,
+			Windows:  info.Windows,
+			Attached: info.Status != "detached",
+		})
+	}
+	return sessions
+}
+
+// saveStatusCache writes the results for cheap readers such as 'sshm statusline'
+func (d *Daemon) saveStatusCache(snapshot Snapshot) {
+	if d.cachePath == "" {
+		return
+	}
+	cache := &statuscache.Cache{Servers: make(map[string]statuscache.Entry), DaemonAt: time.Now(), DaemonInterval: d.interval}
+	for _, server := range snapshot.Servers {
+		cache.Servers[server.Name] = statuscache.Entry{Status: server.Status, Latency: server.Latency, CheckedAt: server.CheckedAt, Address: server.Address}
+	}
+	// The cache is a convenience for other tools; failing to write it doesn't stop the daemon
+	cache.Save(d.cachePath)
+}
+
+// recordStatusSamples stores the servers checked this round, leaving out stealth
+// servers that kept an earlier result
+func (d *Daemon) recordStatusSamples(snapshot Snapshot, roundStart time.Time) {
+	if d.history == nil {
+		return
+	}
+	var samples []history.StatusSample
+	for _, server := range snapshot.Servers {
+		if server.CheckedAt.Before(roundStart) {
+			continue
+		}
+		samples = append(samples, history.StatusSample{ServerName: server.Name, Status: server.Status, Latency: server.Latency, CheckedAt: server.CheckedAt})
+	}
+	// Like the status cache, samples are a convenience that doesn't stop the daemon
+	d.history.RecordStatusSamples(samples)
+}
+
+// fireWebhooks calls the webhooks of the servers checked this round whose
+// reachability changed since their previous check
+func (d *Daemon) fireWebhooks(servers []config.Server, snapshot Snapshot, roundStart time.Time) {
+	byName := make(map[string]config.Server, len(servers))
+	for _, server := range servers {
+		byName[server.Name] = server
+	}
+	for _, status := range snapshot.Servers {
+		if status.CheckedAt.Before(roundStart) {
+			continue
+		}
+		d.webhooks.Observe(byName[status.Name], status.Status, status.CheckedAt)
+	}
+}
+
+// serverProfiles returns the names of the profiles containing a server
+func serverProfiles(cfg *config.Config, serverName string) []string {
+	var profiles []string
+	for _, profile := range cfg.GetProfiles() {
+		for _, name := range profile.Servers {
+			if name == serverName {
+				profiles = append(profiles, profile.Name)
+				break
+			}
+		}
+	}
+	return profiles
+}
+
+// syncSSHConfig syncs the servers with the SSH config, saving the configuration
+// when hosts were imported or updated
+func syncSSHConfig(cfg *config.Config) error {
+	syncer, err := sshconfig.NewSyncer(cfg.SSHSync)
+	if err != nil {
+		return err
+	}
+	result, err := syncer.Sync(cfg)
+	if err != nil {
+		return err
+	}
+	if result.ConfigChanged {
+		return cfg.Save()
+	}
+	return nil
+}
@@ -0,0 +1,209 @@
+package daemon
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"sshm/internal/config"
+	"sshm/internal/events"
+	"sshm/internal/monitor"
+	"sshm/internal/statuscache"
+	"sshm/internal/tmux"
+)
+
+func newTestDaemon(t *testing.T) *Daemon {
+	past := time.Now().Add(-time.Hour)
+	cfg := &config.Config{
+		Servers: []config.Server{
+			{Name: "web1", Hostname: "10.0.0.1", Port: 22},
+			{Name: "db1", Hostname: "10.0.0.2", Port: 22},
+			{Name: "scratch", Hostname: "10.0.0.3", Port: 22, ExpiresAt: &past},
+		},
+		Profiles: []config.Profile{{Name: "prod", Servers: []string{"web1", "db1"}}},
+	}
+
+	d := New(time.Minute, filepath.Join(t.TempDir(), statuscache.FileName))
+	d.loadConfig = func() (*config.Config, error) { return cfg, nil }
+	d.checkServer = func(server config.Server, _ config.RetryPolicy) (string, int, time.Duration) {
+		if server.Name == "db1" {
+			return "unreachable", 1, 0
+		}
+		return "online", 1, 12 * time.Millisecond
+	}
+	d.listSessions = func() ([]tmux.SessionInfo, error) {
+		return []tmux.SessionInfo{{Name: "web1", Windows: 2, Status: "attached"}}, nil
+	}
+	return d
+}
+
+func TestCheckOnce(t *testing.T) {
+	d := newTestDaemon(t)
+
+	snapshot, err := d.CheckOnce()
+	if err != nil {
+		t.Fatalf("CheckOnce failed: %v", err)
+	}
+	if len(snapshot.Servers) != 2 {
+		t.Fatalf("Expected expired servers to be skipped, got %+v", snapshot.Servers)
+	}
+	if snapshot.Servers[0].Name != "db1" || snapshot.Servers[0].Status != "unreachable" || snapshot.Servers[1].Profiles[0] != "prod" {
+		t.Errorf("Unexpected server statuses: %+v", snapshot.Servers)
+	}
+	if len(snapshot.Sessions) != 1 || !snapshot.Sessions[0].Attached {
+		t.Errorf("Unexpected sessions: %+v", snapshot.Sessions)
+	}
+
+	cache, err := statuscache.Load(d.cachePath)
+	if err != nil || cache.Servers["web1"].Status != "online" {
+		t.Errorf("Expected status cache to be written, got %+v, %v", cache, err)
+	}
+	if err == nil && !cache.DaemonRunning() {
+		t.Error("Expected the cache to mark the daemon as running")
+	}
+}
+
+func TestCheckOnceStealth(t *testing.T) {
+	d := newTestDaemon(t)
+	cfg, _ := d.loadConfig()
+	cfg.Profiles = append(cfg.Profiles, config.Profile{Name: "dmz", Servers: []string{"web1"}, Stealth: true})
+
+	tcpChecks := 0
+	d.checkTCP = func(server config.Server) (string, time.Duration) {
+		tcpChecks++
+		return "online", time.Millisecond
+	}
+	d.checkServer = func(server config.Server, _ config.RetryPolicy) (string, int, time.Duration) {
+		if server.Name == "web1" {
+			t.Error("Expected no SSH check of a stealth server")
+		}
+		return "online", 1, 0
+	}
+
+	// The first stealth check falls at the very end of the window
+	d.schedule.Due("web1", monitor.CheckPlan{Period: time.Hour, Stealth: true}, time.Now().Add(-2*time.Hour))
+	snapshot, err := d.CheckOnce()
+	if err != nil {
+		t.Fatalf("CheckOnce failed: %v", err)
+	}
+	if tcpChecks != 1 || snapshot.Servers[1].Status != "online" {
+		t.Fatalf("Expected one TCP check of web1, got %d checks and %+v", tcpChecks, snapshot.Servers)
+	}
+
+	// Not due again until the next window; the last result is kept
+	snapshot, _ = d.CheckOnce()
+	if tcpChecks != 1 || snapshot.Servers[1].Status != "online" || snapshot.Servers[1].Profiles[0] != "prod" {
+		t.Errorf("Expected web1 to keep its result until due, got %d checks and %+v", tcpChecks, snapshot.Servers)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	d := newTestDaemon(t)
+	d.CheckOnce()
+	handler := d.Handler("secret")
+
+	request := func(method, target, auth string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, nil)
+		if auth != "" {
+			req.Header.Set("Authorization", "Bearer "+auth)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := request(http.MethodGet, "/", ""); rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 without token, got %d", rec.Code)
+	}
+	if rec := request(http.MethodGet, "/?token=wrong", ""); rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 with wrong token, got %d", rec.Code)
+	}
+	if rec := request(http.MethodPost, "/", "secret"); rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected 405 for POST, got %d", rec.Code)
+	}
+
+	rec := request(http.MethodGet, "/?token=secret", "")
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "1 of 2 servers online") {
+		t.Errorf("Unexpected status page (%d): %s", rec.Code, rec.Body.String())
+	}
+
+	rec = request(http.MethodGet, "/status.json", "secret")
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"name":"web1"`) {
+		t.Errorf("Unexpected JSON status (%d): %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestIsLoopback(t *testing.T) {
+	for addr, want := range map[string]bool{
+		"127.0.0.1:8722": true,
+		"localhost:8722": true,
+		"[::1]:8722":     true,
+		"0.0.0.0:8722":   false,
+		":8722":          false,
+		"10.0.0.5:8722":  false,
+	} {
+		if got := IsLoopback(addr); got != want {
+			t.Errorf("IsLoopback(%q) = %v, want %v", addr, got, want)
+		}
+	}
+}
+
+func TestCheckOnceCallsWebhooksOnTransitions(t *testing.T) {
+	calls := make(chan string, 4)
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls <- r.URL.Path
+	}))
+	defer hook.Close()
+
+	status := "unreachable"
+	cfg := &config.Config{Servers: []config.Server{
+		{Name: "staging", Hostname: "10.0.0.9", Port: 22, Webhooks: []config.Webhook{{URL: hook.URL + "/deploy", On: config.WebhookOnOnline}}},
+	}}
+	d := New(time.Minute, "")
+	d.loadConfig = func() (*config.Config, error) { return cfg, nil }
+	d.checkServer = func(config.Server, config.RetryPolicy) (string, int, time.Duration) { return status, 1, 0 }
+	d.listSessions = func() ([]tmux.SessionInfo, error) { return nil, nil }
+
+	d.CheckOnce()
+	status = "online"
+	d.CheckOnce()
+	d.CheckOnce()
+
+	select {
+	case path := <-calls:
+		if path != "/deploy" {
+			t.Errorf("Unexpected webhook path %s", path)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected the webhook to be called when the server came back")
+	}
+	select {
+	case path := <-calls:
+		t.Errorf("Expected a single call, got another to %s", path)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+type closingBuffer struct{ strings.Builder }
+
+func (b *closingBuffer) Close() error { return nil }
+
+func TestCheckOnceEmitsStatusChanges(t *testing.T) {
+	d := newTestDaemon(t)
+	var stream closingBuffer
+	d.SetEvents(events.New(&stream, "daemon"))
+
+	d.CheckOnce()
+	d.CheckOnce()
+
+	lines := strings.Split(strings.TrimSpace(stream.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected one event per server on the first round only, got %q", stream.String())
+	}
+	if !strings.Contains(lines[0], `"type":"status_changed","source":"daemon","server":"db1","status":"unreachable"`) {
+		t.Errorf("Unexpected event %s", lines[0])
+	}
+}
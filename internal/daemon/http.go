@@ -0,0 +1,162 @@
+package daemon
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"html/template"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// pageRefreshSeconds is how often the status page reloads itself
+const pageRefreshSeconds = 30
+
+// statusPageTemplate renders the read-only wallboard
+var statusPageTemplate = template.Must(template.New("status").Funcs(template.FuncMap{
+	"join": func(values []string) string { return strings.Join(values, ", ") },
+	"latency": func(d time.Duration) string {
+		if d <= 0 {
+			return ""
+		}
+		return d.Round(time.Millisecond).String()
+	},
+	"when": func(t time.Time) string {
+		if t.IsZero() {
+			return "never"
+		}
+		return t.Local().Format("2006-01-02 15:04:05")
+	},
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta http-equiv="refresh" content="{{.Refresh}}">
+<title>sshm status</title>
+<style>
+body { font-family: monospace; background: #111; color: #ddd; margin: 2em; }
+h1 { font-size: 1.4em; } h2 { font-size: 1.1em; margin-top: 2em; }
+table { border-collapse: collapse; } th, td { text-align: left; padding: 0.3em 1.2em 0.3em 0; }
+th { color: #fc0; } .online { color: #4c4; } .offline { color: #e44; } .muted { color: #888; }
+</style>
+</head>
+<body>
+<h1>sshm status</h1>
+<p class="muted">{{.Online}} of {{len .Snapshot.Servers}} servers online · updated {{when .Snapshot.UpdatedAt}}</p>
+<table>
+<tr><th>Server</th><th>Host</th><th>Status</th><th>Latency</th><th>Profiles</th><th>Checked</th></tr>
+{{range .Snapshot.Servers}}<tr>
+<td>{{.Name}}</td><td>{{.Hostname}}</td>
+<td class="{{if eq .Status "online"}}online{{else}}offline{{end}}">{{.Status}}</td>
+<td>{{latency .Latency}}</td><td>{{join .Profiles}}</td><td class="muted">{{when .CheckedAt}}</td>
+</tr>{{else}}<tr><td colspan="6" class="muted">No status yet</td></tr>{{end}}
+</table>
+<h2>Active sessions</h2>
+<table>
+<tr><th>Session</th><th>Windows</th><th>State</th></tr>
+{{range .Snapshot.Sessions}}<tr><td>{{.Name}}</td><td>{{.Windows}}</td><td>{{if .Attached}}attached{{else}}detached{{end}}</td></tr>
+{{else}}<tr><td colspan="3" class="muted">No active sessions</td></tr>{{end}}
+</table>
+</body>
+</html>
+`))
+
+// Handler serves the read-only status page at / and the same data as JSON at
+// /status.json. When token is set it must be given as a bearer token or ?token=.
+func (d *Daemon) Handler(token string) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		snapshot := d.Snapshot()
+		online := 0
+		for _, server := range snapshot.Servers {
+			if server.Status == "online" {
+				online++
+			}
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		statusPageTemplate.Execute(w, struct {
+			Snapshot Snapshot
+			Online   int
+			Refresh  int
+		}{snapshot, online, pageRefreshSeconds})
+	})
+	mux.HandleFunc("/status.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(d.Snapshot())
+	})
+	return readOnly(requireToken(token, mux))
+}
+
+// Serve serves the status page on addr until ctx is cancelled
+func (d *Daemon) Serve(ctx context.Context, addr, token string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	server := &http.Server{Handler: d.Handler(token), ReadHeaderTimeout: 10 * time.Second}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
+	if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// IsLoopback reports whether addr only accepts connections from this machine
+func IsLoopback(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// readOnly rejects anything but GET and HEAD and sets defensive headers
+func readOnly(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		w.Header().Set("Content-Security-Policy", "default-src 'none'; style-src 'unsafe-inline'")
+		w.Header().Set("Cache-Control", "no-store")
+		next.ServeHTTP(w, r)
+	})
+}
+
+// requireToken rejects requests without the token; an empty token allows everything
+func requireToken(token string, next http.Handler) http.Handler {
+	if token == "" {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		given := r.URL.Query().Get("token")
+		if bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+			given = bearer
+		}
+		if subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,94 @@
+// Package diagnostics builds and runs network triage tools (ping, traceroute,
+// mtr) against a server, locally or from another server such as a bastion.
+package diagnostics
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"sshm/internal/config"
+	"sshm/internal/power"
+	"sshm/internal/shellquote"
+)
+
+// Supported tools
+const (
+	ToolPing       = "ping"
+	ToolTraceroute = "traceroute"
+	ToolMTR        = "mtr"
+)
+
+// Tools lists the supported tools in menu order
+var Tools = []string{ToolPing, ToolTraceroute, ToolMTR}
+
+// Counts used when output is captured, so the tools finish on their own
+const (
+	capturedPings     = 10
+	capturedMTRCycles = 10
+)
+
+// localCommand runs a shell command on this machine (variable to allow mocking in tests)
+var localCommand = func(command string) *exec.Cmd {
+	return exec.Command("sh", "-c", command)
+}
+
+// Command returns the shell command running tool against host. Interactive
+// commands run in a terminal until stopped; captured ones finish by themselves
+// and avoid full-screen output.
+func Command(tool, host string, interactive bool) (string, error) {
+	if strings.TrimSpace(host) == "" {
+		return "", fmt.Errorf("a host is required")
+	}
+	quoted := shellquote.Quote(host)
+	switch tool {
+	case ToolPing:
+		if interactive {
+			return "ping " + quoted, nil
+		}
+		return fmt.Sprintf("ping -c %d %s", capturedPings, quoted), nil
+	case ToolTraceroute:
+		return "traceroute " + quoted, nil
+	case ToolMTR:
+		if interactive {
+			return "mtr " + quoted, nil
+		}
+		return fmt.Sprintf("mtr --report --report-wide --report-cycles %d %s", capturedMTRCycles, quoted), nil
+	default:
+		return "", fmt.Errorf("unknown tool '%s' (expected one of: %s)", tool, strings.Join(Tools, ", "))
+	}
+}
+
+// Stream runs a captured command locally, or on via when set, passing each
+// output line to onLine until it finishes or ctx is cancelled
+func Stream(ctx context.Context, via *config.Server, command string, onLine func(string)) error {
+	if via != nil {
+		return power.Stream(ctx, *via, command, onLine)
+	}
+	if err := power.StreamCommand(ctx, localCommand(command), onLine); err != nil {
+		return fmt.Errorf("%s failed: %w", strings.Fields(command)[0], err)
+	}
+	return nil
+}
+
+// TerminalCommand returns the command line to type in a terminal: the command
+// itself, or an interactive ssh to via running it there
+func TerminalCommand(via *config.Server, command string) (string, error) {
+	if via == nil {
+		return command, nil
+	}
+	if via.IsRestricted() {
+		return "", fmt.Errorf("%s is restricted to its forced command", via.Name)
+	}
+	line := "ssh -t"
+	if via.Port != 0 && via.Port != 22 {
+		line += fmt.Sprintf(" -p %d", via.Port)
+	}
+	if via.AuthType == "key" && via.KeyPath != "" {
+		line += fmt.Sprintf(" -i %s", via.KeyPath)
+	}
+	line += via.GetSSHOptions()
+	line += fmt.Sprintf(" %s@%s %s", via.Username, via.GetEffectiveHostname(), shellquote.Quote(command))
+	return line, nil
+}
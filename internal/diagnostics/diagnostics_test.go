@@ -0,0 +1,78 @@
+package diagnostics
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"sshm/internal/config"
+)
+
+func TestCommand(t *testing.T) {
+	tests := []struct {
+		tool        string
+		interactive bool
+		expected    string
+	}{
+		{ToolPing, false, "ping -c 10 'web1.example.com'"},
+		{ToolPing, true, "ping 'web1.example.com'"},
+		{ToolTraceroute, false, "traceroute 'web1.example.com'"},
+		{ToolMTR, false, "mtr --report --report-wide --report-cycles 10 'web1.example.com'"},
+		{ToolMTR, true, "mtr 'web1.example.com'"},
+	}
+	for _, test := range tests {
+		command, err := Command(test.tool, "web1.example.com", test.interactive)
+		if err != nil || command != test.expected {
+			t.Errorf("Command(%s, %v) = %q, %v; expected %q", test.tool, test.interactive, command, err, test.expected)
+		}
+	}
+
+	if _, err := Command("nmap", "web1.example.com", false); err == nil {
+		t.Error("Expected an unknown tool to be rejected")
+	}
+	if _, err := Command(ToolPing, " ", false); err == nil {
+		t.Error("Expected an empty host to be rejected")
+	}
+}
+
+func TestTerminalCommand(t *testing.T) {
+	command := "mtr 'db1.internal'"
+	if line, _ := TerminalCommand(nil, command); line != command {
+		t.Errorf("Expected a local command unchanged, got %q", line)
+	}
+
+	bastion := &config.Server{Name: "bastion", Hostname: "bastion.example.com", Port: 2222, Username: "ops", AuthType: "key", KeyPath: "/keys/id"}
+	line, err := TerminalCommand(bastion, command)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `ssh -t -p 2222 -i /keys/id ops@bastion.example.com 'mtr '\''db1.internal'\'''`
+	if line != expected {
+		t.Errorf("Expected %q, got %q", expected, line)
+	}
+
+	bastion.Restricted = &config.RestrictedAccess{Command: "backup"}
+	if _, err := TerminalCommand(bastion, command); err == nil {
+		t.Error("Expected a restricted server to be refused")
+	}
+}
+
+func TestStreamLocal(t *testing.T) {
+	original := localCommand
+	var ran string
+	localCommand = func(command string) *exec.Cmd {
+		ran = command
+		return exec.Command("sh", "-c", "echo 'PING web1'; echo '1 packets received'")
+	}
+	t.Cleanup(func() { localCommand = original })
+
+	var lines []string
+	err := Stream(context.Background(), nil, "ping -c 10 'web1'", func(line string) { lines = append(lines, line) })
+	if err != nil {
+		t.Fatalf("Stream failed: %v", err)
+	}
+	if ran != "ping -c 10 'web1'" || strings.Join(lines, "|") != "PING web1|1 packets received" {
+		t.Errorf("Unexpected run %q with output %v", ran, lines)
+	}
+}
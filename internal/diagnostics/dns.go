@@ -0,0 +1,339 @@
+package diagnostics
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"os"
+	"strings"
+	"time"
+)
+
+// DNS record types shown by the DNS inspection
+const (
+	RecordA     = "A"
+	RecordAAAA  = "AAAA"
+	RecordCNAME = "CNAME"
+	RecordPTR   = "PTR"
+)
+
+var recordTypes = map[uint16]string{1: RecordA, 5: RecordCNAME, 12: RecordPTR, 28: RecordAAAA}
+
+// DNSTimeout is how long to wait for each DNS answer
+const DNSTimeout = 3 * time.Second
+
+// resolvConfPath is where the system resolver is configured (variable to allow mocking in tests)
+var resolvConfPath = "/etc/resolv.conf"
+
+// errNoSuchName is returned for NXDOMAIN answers
+var errNoSuchName = errors.New("no such name")
+
+// Record is one resource record of an answer
+type Record struct {
+	Type  string `json:"type"`
+	Name  string `json:"name"`
+	Value string `json:"value"`
+	TTL   uint32 `json:"ttl"`
+}
+
+// DNSReport holds the records found for a server's hostname
+type DNSReport struct {
+	Host        string   `json:"host"`
+	Resolver    string   `json:"resolver"`
+	Records     []Record `json:"records"`
+	Addresses   []string `json:"addresses"`
+	LastAddress string   `json:"last_address,omitempty"` // Address the status checker last connected to
+	Changed     bool     `json:"changed"`                // LastAddress is not among Addresses
+}
+
+// SystemResolver returns the first nameserver configured in /etc/resolv.conf
+func SystemResolver() (string, error) {
+	file, err := os.Open(resolvConfPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read the system resolver configuration: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && fields[0] == "nameserver" {
+			return fields[1], nil
+		}
+	}
+	return "", fmt.Errorf("no nameserver in %s", resolvConfPath)
+}
+
+// resolverAddress adds the DNS port to a resolver given as a bare IP address
+func resolverAddress(resolver string) string {
+	if _, _, err := net.SplitHostPort(resolver); err == nil {
+		return resolver
+	}
+	return net.JoinHostPort(strings.Trim(resolver, "[]"), "53")
+}
+
+// InspectDNS resolves host's A, AAAA and CNAME records and the PTR records of
+// its addresses, asking resolver or the system resolver when empty. The TTLs
+// are the ones the resolver answered with, so cached records count down.
+// lastAddress, when known, is compared with the addresses found.
+func InspectDNS(ctx context.Context, host, resolver, lastAddress string) (DNSReport, error) {
+	if resolver == "" {
+		var err error
+		if resolver, err = SystemResolver(); err != nil {
+			return DNSReport{}, err
+		}
+	}
+	report := DNSReport{Host: host, Resolver: resolverAddress(resolver), LastAddress: lastAddress}
+
+	seen := make(map[Record]bool)
+	add := func(records []Record) {
+		for _, record := range records {
+			key := record
+			key.TTL = 0
+			if !seen[key] {
+				seen[key] = true
+				report.Records = append(report.Records, record)
+			}
+		}
+	}
+
+	if ip := net.ParseIP(host); ip != nil {
+		report.Addresses = []string{ip.String()}
+	} else {
+		missing := 0
+		for _, qtype := range []uint16{1, 28} {
+			records, err := queryDNS(ctx, report.Resolver, host, qtype)
+			if errors.Is(err, errNoSuchName) {
+				missing++
+				continue
+			}
+			if err != nil {
+				return report, err
+			}
+			add(records)
+			for _, record := range records {
+				if record.Type == RecordA || record.Type == RecordAAAA {
+					report.Addresses = append(report.Addresses, record.Value)
+				}
+			}
+		}
+		if missing == 2 {
+			return report, fmt.Errorf("%s does not exist according to %s (NXDOMAIN)", host, report.Resolver)
+		}
+	}
+
+	// Reverse lookups are informative only; many addresses have no PTR record
+	for _, address := range report.Addresses {
+		name, err := reverseName(address)
+		if err != nil {
+			continue
+		}
+		if records, err := queryDNS(ctx, report.Resolver, name, 12); err == nil {
+			add(records)
+		}
+	}
+
+	if lastAddress != "" {
+		report.Changed = true
+		last := net.ParseIP(lastAddress)
+		for _, address := range report.Addresses {
+			if last != nil && last.Equal(net.ParseIP(address)) {
+				report.Changed = false
+			}
+		}
+	}
+	return report, nil
+}
+
+// reverseName returns the in-addr.arpa or ip6.arpa name of an address
+func reverseName(address string) (string, error) {
+	ip := net.ParseIP(address)
+	if ip == nil {
+		return "", fmt.Errorf("invalid address '%s'", address)
+	}
+	if v4 := ip.To4(); v4 != nil {
+		return fmt.Sprintf("%d.%d.%d.%d.in-addr.arpa", v4[3], v4[2], v4[1], v4[0]), nil
+	}
+	const hexDigits = "0123456789abcdef"
+	var b strings.Builder
+	for i := len(ip) - 1; i >= 0; i-- {
+		b.WriteByte(hexDigits[ip[i]&0x0f])
+		b.WriteByte('.')
+		b.WriteByte(hexDigits[ip[i]>>4])
+		b.WriteByte('.')
+	}
+	b.WriteString("ip6.arpa")
+	return b.String(), nil
+}
+
+// queryDNS asks resolver for the records of one type over UDP, retrying over
+// TCP when the answer was truncated
+func queryDNS(ctx context.Context, resolver, name string, qtype uint16) ([]Record, error) {
+	id := uint16(rand.Intn(1 << 16))
+	query, err := buildQuery(id, name, qtype)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := exchange(ctx, "udp", resolver, query)
+	if err == nil && len(response) > 2 && response[2]&0x02 != 0 {
+		response, err = exchange(ctx, "tcp", resolver, query)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("DNS query for %s failed: %w", name, err)
+	}
+	return parseResponse(response, id)
+}
+
+// exchange sends one query and reads its answer, length-prefixed over TCP
+func exchange(ctx context.Context, network, resolver string, query []byte) ([]byte, error) {
+	dialer := net.Dialer{Timeout: DNSTimeout}
+	conn, err := dialer.DialContext(ctx, network, resolver)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(DNSTimeout))
+
+	if network == "tcp" {
+		framed := make([]byte, 2, 2+len(query))
+		binary.BigEndian.PutUint16(framed, uint16(len(query)))
+		query = append(framed, query...)
+	}
+	if _, err := conn.Write(query); err != nil {
+		return nil, err
+	}
+
+	if network == "tcp" {
+		var size [2]byte
+		if _, err := io.ReadFull(conn, size[:]); err != nil {
+			return nil, err
+		}
+		response := make([]byte, binary.BigEndian.Uint16(size[:]))
+		_, err := io.ReadFull(conn, response)
+		return response, err
+	}
+	response := make([]byte, 4096)
+	n, err := conn.Read(response)
+	return response[:n], err
+}
+
+// buildQuery encodes a recursive query for one name and type
+func buildQuery(id uint16, name string, qtype uint16) ([]byte, error) {
+	msg := make([]byte, 12, 512)
+	binary.BigEndian.PutUint16(msg[0:], id)
+	msg[2] = 0x01                          // Recursion desired
+	binary.BigEndian.PutUint16(msg[4:], 1) // One question
+
+	for _, label := range strings.Split(strings.TrimSuffix(name, "."), ".") {
+		if label == "" || len(label) > 63 {
+			return nil, fmt.Errorf("invalid DNS name '%s'", name)
+		}
+		msg = append(msg, byte(len(label)))
+		msg = append(msg, label...)
+	}
+	msg = append(msg, 0, byte(qtype>>8), byte(qtype), 0, 1) // Class IN
+	return msg, nil
+}
+
+// parseResponse decodes the answer section of a response to the query with id,
+// keeping the record types of interest
+func parseResponse(msg []byte, id uint16) ([]Record, error) {
+	if len(msg) < 12 {
+		return nil, fmt.Errorf("short DNS response")
+	}
+	if binary.BigEndian.Uint16(msg[0:]) != id {
+		return nil, fmt.Errorf("DNS response does not match the query")
+	}
+	switch rcode := msg[3] & 0x0f; rcode {
+	case 0:
+	case 3:
+		return nil, errNoSuchName
+	default:
+		return nil, fmt.Errorf("DNS server answered with error code %d", rcode)
+	}
+
+	questions := int(binary.BigEndian.Uint16(msg[4:]))
+	answers := int(binary.BigEndian.Uint16(msg[6:]))
+	offset := 12
+	for i := 0; i < questions; i++ {
+		_, next, err := readName(msg, offset)
+		if err != nil {
+			return nil, err
+		}
+		offset = next + 4
+	}
+
+	var records []Record
+	for i := 0; i < answers; i++ {
+		name, next, err := readName(msg, offset)
+		if err != nil {
+			return nil, err
+		}
+		if next+10 > len(msg) {
+			return nil, fmt.Errorf("truncated DNS record")
+		}
+		rtype := binary.BigEndian.Uint16(msg[next:])
+		ttl := binary.BigEndian.Uint32(msg[next+4:])
+		length := int(binary.BigEndian.Uint16(msg[next+8:]))
+		data := next + 10
+		if data+length > len(msg) {
+			return nil, fmt.Errorf("truncated DNS record")
+		}
+		offset = data + length
+
+		record := Record{Type: recordTypes[rtype], Name: name, TTL: ttl}
+		switch record.Type {
+		case RecordA, RecordAAAA:
+			record.Value = net.IP(msg[data : data+length]).String()
+		case RecordCNAME, RecordPTR:
+			if record.Value, _, err = readName(msg, data); err != nil {
+				return nil, err
+			}
+		default:
+			continue
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// readName decodes a possibly compressed name at offset and returns it with the
+// offset following it
+func readName(msg []byte, offset int) (string, int, error) {
+	var labels []string
+	next := -1
+	for jumps := 0; ; {
+		if offset >= len(msg) {
+			return "", 0, fmt.Errorf("truncated DNS name")
+		}
+		length := int(msg[offset])
+		switch {
+		case length == 0:
+			if next < 0 {
+				next = offset + 1
+			}
+			return strings.Join(labels, "."), next, nil
+		case length&0xc0 == 0xc0:
+			if offset+1 >= len(msg) || jumps > 10 {
+				return "", 0, fmt.Errorf("invalid DNS name compression")
+			}
+			if next < 0 {
+				next = offset + 2
+			}
+			offset = int(binary.BigEndian.Uint16(msg[offset:]) & 0x3fff)
+			jumps++
+		default:
+			if offset+1+length > len(msg) {
+				return "", 0, fmt.Errorf("truncated DNS name")
+			}
+			labels = append(labels, string(msg[offset+1:offset+1+length]))
+			offset += 1 + length
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package diagnostics
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakeAnswer is a record the fake DNS server answers with
+type fakeAnswer struct {
+	qtype uint16
+	name  string
+	rtype uint16
+	data  []byte
+}
+
+func encodeName(name string) []byte {
+	var out []byte
+	for _, label := range strings.Split(name, ".") {
+		out = append(out, byte(len(label)))
+		out = append(out, label...)
+	}
+	return append(out, 0)
+}
+
+// startFakeDNS answers queries from answers; names without any answer are NXDOMAIN
+func startFakeDNS(t *testing.T, answers map[string][]fakeAnswer) string {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("UDP not available: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			query := buf[:n]
+			name, next, err := readName(query, 12)
+			if err != nil {
+				continue
+			}
+			qtype := binary.BigEndian.Uint16(query[next:])
+
+			response := append([]byte{}, query[:next+4]...)
+			response[2] |= 0x80
+			records, known := answers[name]
+			if !known {
+				response[3] = 3
+			}
+			count := 0
+			for _, answer := range records {
+				if answer.qtype != qtype {
+					continue
+				}
+				if answer.name == name {
+					response = append(response, 0xc0, 12) // Compressed pointer to the question
+				} else {
+					response = append(response, encodeName(answer.name)...)
+				}
+				response = binary.BigEndian.AppendUint16(response, answer.rtype)
+				response = binary.BigEndian.AppendUint16(response, 1)
+				response = binary.BigEndian.AppendUint32(response, 300)
+				response = binary.BigEndian.AppendUint16(response, uint16(len(answer.data)))
+				response = append(response, answer.data...)
+				count++
+			}
+			binary.BigEndian.PutUint16(response[6:], uint16(count))
+			conn.WriteTo(response, addr)
+		}
+	}()
+	return conn.LocalAddr().String()
+}
+
+func TestInspectDNS(t *testing.T) {
+	resolver := startFakeDNS(t, map[string][]fakeAnswer{
+		"www.example.com": {
+			{qtype: 1, name: "www.example.com", rtype: 5, data: encodeName("web.example.com")},
+			{qtype: 1, name: "web.example.com", rtype: 1, data: []byte{10, 0, 0, 5}},
+			{qtype: 28, name: "www.example.com", rtype: 5, data: encodeName("web.example.com")},
+		},
+		"5.0.0.10.in-addr.arpa": {
+			{qtype: 12, name: "5.0.0.10.in-addr.arpa", rtype: 12, data: encodeName("web.example.com")},
+		},
+	})
+
+	report, err := InspectDNS(context.Background(), "www.example.com", resolver, "10.0.0.9")
+	if err != nil {
+		t.Fatalf("InspectDNS failed: %v", err)
+	}
+
+	var got []string
+	for _, record := range report.Records {
+		got = append(got, record.Type+" "+record.Name+" "+record.Value)
+		if record.TTL != 300 {
+			t.Errorf("Expected TTL 300 for %v", record)
+		}
+	}
+	expected := []string{
+		"CNAME www.example.com web.example.com",
+		"A web.example.com 10.0.0.5",
+		"PTR 5.0.0.10.in-addr.arpa web.example.com",
+	}
+	if strings.Join(got, "|") != strings.Join(expected, "|") {
+		t.Errorf("Expected records %v, got %v", expected, got)
+	}
+	if !report.Changed {
+		t.Error("Expected a different last address to be flagged")
+	}
+
+	report, _ = InspectDNS(context.Background(), "www.example.com", resolver, "10.0.0.5")
+	if report.Changed {
+		t.Error("Expected the last address to match")
+	}
+
+	if _, err := InspectDNS(context.Background(), "missing.example.com", resolver, ""); err == nil || !strings.Contains(err.Error(), "NXDOMAIN") {
+		t.Errorf("Expected an NXDOMAIN error, got %v", err)
+	}
+}
+
+func TestSystemResolver(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "resolv.conf")
+	os.WriteFile(path, []byte("# generated\nsearch example.com\nnameserver 192.0.2.53\nnameserver 192.0.2.54\n"), 0644)
+	original := resolvConfPath
+	resolvConfPath = path
+	t.Cleanup(func() { resolvConfPath = original })
+
+	resolver, err := SystemResolver()
+	if err != nil || resolver != "192.0.2.53" {
+		t.Errorf("Expected the first nameserver, got %q, %v", resolver, err)
+	}
+	if address := resolverAddress(resolver); address != "192.0.2.53:53" {
+		t.Errorf("Expected the DNS port to be added, got %s", address)
+	}
+	if address := resolverAddress("2001:db8::53"); address != "[2001:db8::53]:53" {
+		t.Errorf("Expected an IPv6 resolver with the DNS port, got %s", address)
+	}
+}
+
+func TestReverseName(t *testing.T) {
+	if name, _ := reverseName("192.0.2.10"); name != "10.2.0.192.in-addr.arpa" {
+		t.Errorf("Unexpected IPv4 reverse name %s", name)
+	}
+	name, _ := reverseName("2001:db8::1")
+	if !strings.HasPrefix(name, "1.0.0.0.") || !strings.HasSuffix(name, ".8.b.d.0.1.0.0.2.ip6.arpa") {
+		t.Errorf("Unexpected IPv6 reverse name %s", name)
+	}
+}
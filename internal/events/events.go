@@ -0,0 +1,140 @@
+// Package events writes a machine-readable stream of what sshm does, one JSON
+// object per line, for integrations and end-to-end tests.
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Event types
+const (
+	ServerSelected    = "server_selected"
+	ConnectionStarted = "connection_started"
+	StatusChanged     = "status_changed"
+	ConfigSaved       = "config_saved"
+)
+
+// Event is one line of the stream. Fields that don't apply to the type are omitted.
+type Event struct {
+	Time     time.Time `json:"time"`
+	Type     string    `json:"type"`
+	Source   string    `json:"source"`             // "tui" or "daemon"
+	Server   string    `json:"server,omitempty"`   // Server the event is about
+	Profile  string    `json:"profile,omitempty"`  // Profile of a group connection
+	Session  string    `json:"session,omitempty"`  // tmux session of a connection
+	Status   string    `json:"status,omitempty"`   // New status of status_changed
+	Previous string    `json:"previous,omitempty"` // Status before status_changed, empty on the first check
+	Path     string    `json:"path,omitempty"`     // File written by config_saved
+}
+
+// Emitter writes events to a file, file descriptor or socket. A nil Emitter
+// drops events, so callers don't check whether a stream was requested.
+type Emitter struct {
+	mu     sync.Mutex
+	w      io.WriteCloser
+	source string
+	failed bool
+	now    func() time.Time
+}
+
+// Open opens an event stream target:
+//
+//	fd:3              an inherited file descriptor
+//	unix:/run/x.sock  a Unix socket to connect to
+//	tcp:host:port     a TCP address to connect to
+//	anything else     a file, appended to
+//
+// source names the emitting program in every event, e.g. "tui".
+func Open(target, source string) (*Emitter, error) {
+	w, err := openTarget(target)
+	if err != nil {
+		return nil, err
+	}
+	return New(w, source), nil
+}
+
+// New returns an emitter writing to w
+func New(w io.WriteCloser, source string) *Emitter {
+	return &Emitter{w: w, source: source, now: time.Now}
+}
+
+func openTarget(target string) (io.WriteCloser, error) {
+	switch {
+	case strings.TrimSpace(target) == "":
+		return nil, fmt.Errorf("event stream target is empty")
+	case strings.HasPrefix(target, "fd:"):
+		fd, err := strconv.Atoi(strings.TrimPrefix(target, "fd:"))
+		if err != nil || fd < 0 {
+			return nil, fmt.Errorf("invalid file descriptor in '%s'", target)
+		}
+		if fd <= 2 {
+			return nil, fmt.Errorf("file descriptor %d is reserved for the terminal; use 3 or higher", fd)
+		}
+		file := os.NewFile(uintptr(fd), target)
+		if _, err := file.Stat(); err != nil {
+			return nil, fmt.Errorf("file descriptor %d is not open: %w", fd, err)
+		}
+		return file, nil
+	case strings.HasPrefix(target, "unix:"):
+		conn, err := net.Dial("unix", strings.TrimPrefix(target, "unix:"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to event socket: %w", err)
+		}
+		return conn, nil
+	case strings.HasPrefix(target, "tcp:"):
+		conn, err := net.Dial("tcp", strings.TrimPrefix(target, "tcp:"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to event socket: %w", err)
+		}
+		return conn, nil
+	default:
+		file, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open event file: %w", err)
+		}
+		return file, nil
+	}
+}
+
+// Emit writes an event, filling in its time and source. Once a write fails,
+// e.g. because the reader went away, later events are dropped.
+func (e *Emitter) Emit(event Event) {
+	if e == nil {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.failed {
+		return
+	}
+
+	if event.Time.IsZero() {
+		event.Time = e.now().UTC()
+	}
+	event.Source = e.source
+	line, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+	if _, err := e.w.Write(append(line, '\n')); err != nil {
+		e.failed = true
+	}
+}
+
+// Close closes the underlying file or connection
+func (e *Emitter) Close() error {
+	if e == nil {
+		return nil
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.w.Close()
+}
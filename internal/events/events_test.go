@@ -0,0 +1,98 @@
+package events
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEmitWritesJSONLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.ndjson")
+	emitter, err := Open(path, "tui")
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	emitter.now = func() time.Time { return time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC) }
+
+	emitter.Emit(Event{Type: ServerSelected, Server: "web1"})
+	emitter.Emit(Event{Type: StatusChanged, Server: "web1", Status: "offline", Previous: "online"})
+	if err := emitter.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines, got %q", data)
+	}
+	if lines[0] != `{"time":"2026-01-02T03:04:05Z","type":"server_selected","source":"tui","server":"web1"}` {
+		t.Errorf("Unexpected first event %s", lines[0])
+	}
+	var event Event
+	if err := json.Unmarshal([]byte(lines[1]), &event); err != nil {
+		t.Fatalf("Invalid JSON %s: %v", lines[1], err)
+	}
+	if event.Status != "offline" || event.Previous != "online" {
+		t.Errorf("Unexpected status change %+v", event)
+	}
+}
+
+func TestEmitToUnixSocket(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "events.sock")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Skipf("Unix sockets unavailable: %v", err)
+	}
+	defer listener.Close()
+
+	received := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		line, _ := bufio.NewReader(conn).ReadString('\n')
+		received <- line
+	}()
+
+	emitter, err := Open("unix:"+socket, "daemon")
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer emitter.Close()
+	emitter.Emit(Event{Type: ConfigSaved})
+
+	select {
+	case line := <-received:
+		if !strings.Contains(line, `"type":"config_saved","source":"daemon"`) {
+			t.Errorf("Unexpected event %s", line)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("No event received")
+	}
+}
+
+func TestOpenRejectsTerminalDescriptors(t *testing.T) {
+	for _, target := range []string{"fd:1", "fd:x", ""} {
+		if _, err := Open(target, "tui"); err == nil {
+			t.Errorf("Expected Open(%q) to fail", target)
+		}
+	}
+}
+
+func TestNilEmitter(t *testing.T) {
+	var emitter *Emitter
+	emitter.Emit(Event{Type: ConfigSaved}) // Must not panic
+	if err := emitter.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+}
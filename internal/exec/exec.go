@@ -0,0 +1,199 @@
+// Package exec runs a command on many servers at once over ssh, with a limit
+// on the connections open at the same time, streaming each host's output as it
+// arrives.
+package exec
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"sshm/internal/config"
+	"sshm/internal/power"
+)
+
+// DefaultConcurrency is how many servers run the command at the same time by default
+const DefaultConcurrency = 10
+
+// Result statuses
+const (
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+	StatusCancelled = "cancelled" // Stopped while running, or never started
+)
+
+// sshCommand builds the ssh command running command on a server (variable to allow mocking in tests)
+var sshCommand = power.SSHCommand
+
+// Options tunes a run. The callbacks may be called from several goroutines,
+// but never at the same time.
+type Options struct {
+	Concurrency int           // Servers running the command at the same time, DefaultConcurrency when 0
+	Timeout     time.Duration // Per-server limit, none when 0
+	OnStart     func(server config.Server)
+	OnLine      func(server config.Server, line string)
+	OnResult    func(result Result)
+}
+
+// Result is the outcome of the command on one server
+type Result struct {
+	Server   config.Server
+	Status   string
+	Output   string // Combined standard output and error
+	Err      error
+	Duration time.Duration
+}
+
+// Failed reports whether the command failed on the server or was cancelled
+func (r Result) Failed() bool {
+	return r.Status != StatusSucceeded
+}
+
+// Summary counts the results of a run
+type Summary struct {
+	Succeeded int
+	Failed    int
+	Cancelled int
+}
+
+// String describes the summary, e.g. "3 succeeded, 1 failed"
+func (s Summary) String() string {
+	parts := []string{fmt.Sprintf("%d succeeded", s.Succeeded), fmt.Sprintf("%d failed", s.Failed)}
+	if s.Cancelled > 0 {
+		parts = append(parts, fmt.Sprintf("%d cancelled", s.Cancelled))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Summarize counts the results of a run
+func Summarize(results []Result) Summary {
+	var summary Summary
+	for _, result := range results {
+		switch result.Status {
+		case StatusSucceeded:
+			summary.Succeeded++
+		case StatusCancelled:
+			summary.Cancelled++
+		default:
+			summary.Failed++
+		}
+	}
+	return summary
+}
+
+// Run runs command on every server, at most opts.Concurrency at a time, and
+// returns the results in the order of servers. Cancelling ctx kills the
+// running commands and skips the servers that have not started.
+func Run(ctx context.Context, servers []config.Server, command string, opts Options) ([]Result, error) {
+	if strings.TrimSpace(command) == "" {
+		return nil, fmt.Errorf("a command is required")
+	}
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency
+	}
+
+	var callbacks sync.Mutex
+	notify := func(fn func()) {
+		callbacks.Lock()
+		defer callbacks.Unlock()
+		fn()
+	}
+
+	results := make([]Result, len(servers))
+	slots := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, server := range servers {
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			results[i] = Result{Server: server, Status: StatusCancelled, Err: ctx.Err()}
+			if opts.OnResult != nil {
+				notify(func() { opts.OnResult(results[i]) })
+			}
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, server config.Server) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			if opts.OnStart != nil {
+				notify(func() { opts.OnStart(server) })
+			}
+			results[i] = runOne(ctx, server, command, opts.Timeout, func(line string) {
+				if opts.OnLine != nil {
+					notify(func() { opts.OnLine(server, line) })
+				}
+			})
+			if opts.OnResult != nil {
+				notify(func() { opts.OnResult(results[i]) })
+			}
+		}(i, server)
+	}
+	wg.Wait()
+	return results, nil
+}
+
+// runOne runs the command on one server, passing each output line to onLine
+func runOne(ctx context.Context, server config.Server, command string, timeout time.Duration, onLine func(string)) Result {
+	result := Result{Server: server}
+	started := time.Now()
+
+	cmd, err := sshCommand(server, command)
+	if err != nil {
+		result.Status = StatusFailed
+		result.Err = err
+		return result
+	}
+
+	runCtx := ctx
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	var output strings.Builder
+	err = power.StreamCommand(runCtx, cmd, func(line string) {
+		output.WriteString(line)
+		output.WriteString("\n")
+		onLine(line)
+	})
+	result.Output = strings.TrimRight(output.String(), "\n")
+	result.Duration = time.Since(started)
+
+	switch {
+	case ctx.Err() != nil:
+		result.Status = StatusCancelled
+		result.Err = ctx.Err()
+	case runCtx.Err() != nil:
+		result.Status = StatusFailed
+		result.Err = fmt.Errorf("timed out after %v", timeout)
+	case err != nil:
+		result.Status = StatusFailed
+		result.Err = describeExit(err)
+	default:
+		result.Status = StatusSucceeded
+	}
+	return result
+}
+
+// describeExit turns the error of a finished ssh command into a readable one;
+// ssh exits with 255 when it could not connect
+func describeExit(err error) error {
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return err
+	}
+	if exitErr.ExitCode() == 255 {
+		return fmt.Errorf("ssh failed (exit status 255)")
+	}
+	return fmt.Errorf("exit status %d", exitErr.ExitCode())
+}
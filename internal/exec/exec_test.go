@@ -0,0 +1,144 @@
+package exec
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"sshm/internal/config"
+)
+
+// mockSSH runs script locally in place of ssh, with the server name in $SERVER
+func mockSSH(t *testing.T, script string) {
+	original := sshCommand
+	sshCommand = func(server config.Server, command string) (*exec.Cmd, error) {
+		if server.IsRestricted() {
+			return nil, fmt.Errorf("%s is restricted to its forced command", server.Name)
+		}
+		cmd := exec.Command("sh", "-c", script)
+		cmd.Env = append(cmd.Environ(), "SERVER="+server.Name)
+		return cmd, nil
+	}
+	t.Cleanup(func() { sshCommand = original })
+}
+
+func testServers(names ...string) []config.Server {
+	servers := make([]config.Server, len(names))
+	for i, name := range names {
+		servers[i] = config.Server{Name: name, Hostname: name + ".example.com", Username: "ops"}
+	}
+	return servers
+}
+
+func TestRunStreamsOutputAndReportsFailures(t *testing.T) {
+	mockSSH(t, `echo "hello from $SERVER"; echo oops >&2; [ "$SERVER" != db1 ] || exit 3`)
+
+	var mu sync.Mutex
+	lines := map[string][]string{}
+	results, err := Run(context.Background(), testServers("web1", "web2", "db1"), "uptime", Options{
+		OnLine: func(server config.Server, line string) {
+			mu.Lock()
+			defer mu.Unlock()
+			lines[server.Name] = append(lines[server.Name], line)
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(results) != 3 || results[0].Server.Name != "web1" || results[2].Server.Name != "db1" {
+		t.Fatalf("Expected the results in server order, got %+v", results)
+	}
+	if results[0].Status != StatusSucceeded || results[0].Output != "hello from web1\noops" {
+		t.Errorf("Unexpected result for web1: %+v", results[0])
+	}
+	if results[2].Status != StatusFailed || results[2].Err == nil || !strings.Contains(results[2].Err.Error(), "exit status 3") {
+		t.Errorf("Expected db1 to fail with its exit status, got %+v", results[2])
+	}
+	if len(lines["web2"]) != 2 || lines["web2"][0] != "hello from web2" {
+		t.Errorf("Expected web2's lines to be streamed, got %v", lines["web2"])
+	}
+	if summary := Summarize(results); summary != (Summary{Succeeded: 2, Failed: 1}) || summary.String() != "2 succeeded, 1 failed" {
+		t.Errorf("Unexpected summary %+v", summary)
+	}
+}
+
+func TestRunLimitsConcurrency(t *testing.T) {
+	mockSSH(t, "sleep 0.1")
+
+	var mu sync.Mutex
+	running, peak := 0, 0
+	_, err := Run(context.Background(), testServers("a", "b", "c", "d", "e", "f"), "true", Options{
+		Concurrency: 2,
+		OnStart: func(config.Server) {
+			mu.Lock()
+			defer mu.Unlock()
+			running++
+			if running > peak {
+				peak = running
+			}
+		},
+		OnResult: func(Result) {
+			mu.Lock()
+			defer mu.Unlock()
+			running--
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if peak != 2 {
+		t.Errorf("Expected at most 2 servers at a time, got %d", peak)
+	}
+}
+
+func TestRunCancelKillsAndSkips(t *testing.T) {
+	mockSSH(t, "exec sleep 10")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	started := make(chan struct{}, 1)
+	go func() {
+		<-started
+		cancel()
+	}()
+
+	begin := time.Now()
+	results, err := Run(ctx, testServers("a", "b", "c"), "true", Options{
+		Concurrency: 1,
+		OnStart:     func(config.Server) { started <- struct{}{} },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if time.Since(begin) > 5*time.Second {
+		t.Fatal("Expected cancelling to kill the running command")
+	}
+	if summary := Summarize(results); summary.Cancelled != 3 {
+		t.Errorf("Expected every server to be cancelled, got %+v", results)
+	}
+}
+
+func TestRunTimeoutAndRestrictedServers(t *testing.T) {
+	mockSSH(t, "exec sleep 10")
+
+	servers := testServers("slow", "locked")
+	servers[1].Restricted = &config.RestrictedAccess{Command: "backup"}
+	results, err := Run(context.Background(), servers, "true", Options{Timeout: 100 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results[0].Status != StatusFailed || !strings.Contains(results[0].Err.Error(), "timed out") {
+		t.Errorf("Expected the slow server to time out, got %+v", results[0])
+	}
+	if results[1].Status != StatusFailed || !strings.Contains(results[1].Err.Error(), "restricted") {
+		t.Errorf("Expected the restricted server to be refused, got %+v", results[1])
+	}
+
+	if _, err := Run(context.Background(), servers, " ", Options{}); err == nil {
+		t.Error("Expected an empty command to be rejected")
+	}
+}
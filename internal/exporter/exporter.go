@@ -0,0 +1,134 @@
+package exporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+	"sshm/internal/config"
+)
+
+// DefaultFormat is used when neither a format nor a known file extension is given
+const DefaultFormat = "yaml"
+
+// Exporter writes servers and profiles in a file format. Register one to make
+// the format available to 'sshm export' and the TUI export dialog.
+type Exporter interface {
+	Name() string         // Format name, as given to --format, e.g. "yaml"
+	Extensions() []string // File extensions, the first being the default, e.g. ".yaml"
+	Marshal(cfg config.Config) ([]byte, error)
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   []Exporter // In registration order, built-in formats first
+)
+
+func init() {
+	Register(yamlExporter{})
+	Register(jsonExporter{})
+}
+
+// Register adds an exporter. Names are case-insensitive; registering a name
+// twice is an error.
+func Register(e Exporter) error {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	name := strings.ToLower(e.Name())
+	if name == "" {
+		return fmt.Errorf("exporter name must not be empty")
+	}
+	for _, existing := range registry {
+		if strings.ToLower(existing.Name()) == name {
+			return fmt.Errorf("exporter '%s' is already registered", name)
+		}
+	}
+	registry = append(registry, e)
+	return nil
+}
+
+// Lookup returns the exporter of a format name
+func Lookup(name string) (Exporter, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	for _, e := range registry {
+		if strings.EqualFold(e.Name(), name) {
+			return e, true
+		}
+	}
+	return nil, false
+}
+
+// ForPath returns the exporter handling a file's extension
+func ForPath(path string) (Exporter, bool) {
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext == "" {
+		return nil, false
+	}
+
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	for _, e := range registry {
+		for _, candidate := range e.Extensions() {
+			if strings.ToLower(candidate) == ext {
+				return e, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// Names lists the registered format names in registration order
+func Names() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for _, e := range registry {
+		names = append(names, strings.ToLower(e.Name()))
+	}
+	return names
+}
+
+// DetectFormat returns the format of a file from its extension, DefaultFormat
+// when no exporter claims it
+func DetectFormat(path string) string {
+	if e, ok := ForPath(path); ok {
+		return strings.ToLower(e.Name())
+	}
+	return DefaultFormat
+}
+
+// DefaultExtension returns the extension files of a format get
+func DefaultExtension(e Exporter) string {
+	if extensions := e.Extensions(); len(extensions) > 0 {
+		return extensions[0]
+	}
+	return ""
+}
+
+// yamlExporter writes the native sshm configuration format
+type yamlExporter struct{}
+
+func (yamlExporter) Name() string         { return "yaml" }
+func (yamlExporter) Extensions() []string { return []string{".yaml", ".yml"} }
+
+func (yamlExporter) Marshal(cfg config.Config) ([]byte, error) {
+	return yaml.Marshal(cfg)
+}
+
+// jsonExporter writes the configuration as indented JSON
+type jsonExporter struct{}
+
+func (jsonExporter) Name() string         { return "json" }
+func (jsonExporter) Extensions() []string { return []string{".json"} }
+
+func (jsonExporter) Marshal(cfg config.Config) ([]byte, error) {
+	return json.MarshalIndent(cfg, "", "  ")
+}
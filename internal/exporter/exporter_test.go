@@ -0,0 +1,78 @@
+package exporter
+
+import (
+	"strings"
+	"testing"
+
+	"sshm/internal/config"
+)
+
+// csvExporter stands in for a third-party format
+type csvExporter struct{}
+
+func (csvExporter) Name() string         { return "CSV" }
+func (csvExporter) Extensions() []string { return []string{".csv"} }
+
+func (csvExporter) Marshal(cfg config.Config) ([]byte, error) {
+	var b strings.Builder
+	for _, server := range cfg.Servers {
+		b.WriteString(server.Name + "," + server.Hostname + "\n")
+	}
+	return []byte(b.String()), nil
+}
+
+func TestBuiltinExporters(t *testing.T) {
+	names := Names()
+	if len(names) < 2 || names[0] != "yaml" || names[1] != "json" {
+		t.Fatalf("Expected yaml and json first, got %v", names)
+	}
+
+	cfg := config.Config{Servers: []config.Server{{Name: "web", Hostname: "10.0.0.1", Port: 22}}}
+	for _, name := range []string{"yaml", "JSON"} {
+		e, ok := Lookup(name)
+		if !ok {
+			t.Fatalf("Expected %s to be registered", name)
+		}
+		data, err := e.Marshal(cfg)
+		if err != nil {
+			t.Fatalf("Marshal with %s failed: %v", name, err)
+		}
+		if !strings.Contains(string(data), "10.0.0.1") {
+			t.Errorf("Expected the server in the %s export, got:\n%s", name, data)
+		}
+	}
+}
+
+func TestDetectFormat(t *testing.T) {
+	tests := map[string]string{
+		"servers.yaml": "yaml",
+		"servers.YML":  "yaml",
+		"servers.json": "json",
+		"servers.txt":  DefaultFormat,
+		"servers":      DefaultFormat,
+	}
+	for path, want := range tests {
+		if got := DetectFormat(path); got != want {
+			t.Errorf("DetectFormat(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestRegister(t *testing.T) {
+	if err := Register(csvExporter{}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := Register(csvExporter{}); err == nil {
+		t.Error("Expected registering a name twice to fail")
+	}
+
+	if e, ok := Lookup("csv"); !ok || DefaultExtension(e) != ".csv" {
+		t.Error("Expected csv to be found case-insensitively")
+	}
+	if got := DetectFormat("inventory.csv"); got != "csv" {
+		t.Errorf("Expected csv files to map to the csv exporter, got %q", got)
+	}
+	if names := Names(); names[len(names)-1] != "csv" {
+		t.Errorf("Expected csv last in %v", names)
+	}
+}
@@ -0,0 +1,80 @@
+package history
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+// BannerAcknowledgment records that a user accepted a server's login banner
+type BannerAcknowledgment struct {
+	ID             int       `json:"id"`
+	ServerName     string    `json:"server_name"`
+	Host           string    `json:"host"`
+	User           string    `json:"user"`
+	Banner         string    `json:"banner"`
+	BannerHash     string    `json:"banner_hash"`
+	AcknowledgedAt time.Time `json:"acknowledged_at"`
+}
+
+// HashBanner returns a stable fingerprint for banner text
+func HashBanner(banner string) string {
+	sum := sha256.Sum256([]byte(banner))
+	return hex.EncodeToString(sum[:])
+}
+
+// RecordBannerAcknowledgment stores a banner acknowledgment for compliance auditing
+func (h *HistoryManager) RecordBannerAcknowledgment(ack BannerAcknowledgment) (int64, error) {
+	if ack.AcknowledgedAt.IsZero() {
+		ack.AcknowledgedAt = time.Now()
+	}
+	if ack.BannerHash == "" {
+		ack.BannerHash = HashBanner(ack.Banner)
+	}
+
+	result, err := h.db.Exec(`
+		INSERT INTO banner_acknowledgments (server_name, host, user, banner, banner_hash, acknowledged_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, ack.ServerName, ack.Host, ack.User, ack.Banner, ack.BannerHash, ack.AcknowledgedAt)
+	if err != nil {
+		return 0, fmt.Errorf("failed to record banner acknowledgment: %w", err)
+	}
+
+	return result.LastInsertId()
+}
+
+// GetBannerAcknowledgments returns acknowledgments for a server (all servers if empty), newest first
+func (h *HistoryManager) GetBannerAcknowledgments(serverName string, limit int) ([]BannerAcknowledgment, error) {
+	query := `
+		SELECT id, server_name, host, user, banner, banner_hash, acknowledged_at
+		FROM banner_acknowledgments
+	`
+	var args []interface{}
+	if serverName != "" {
+		query += " WHERE server_name = ?"
+		args = append(args, serverName)
+	}
+	query += " ORDER BY acknowledged_at DESC, id DESC"
+	if limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, limit)
+	}
+
+	rows, err := h.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query banner acknowledgments: %w", err)
+	}
+	defer rows.Close()
+
+	var acks []BannerAcknowledgment
+	for rows.Next() {
+		var ack BannerAcknowledgment
+		if err := rows.Scan(&ack.ID, &ack.ServerName, &ack.Host, &ack.User, &ack.Banner, &ack.BannerHash, &ack.AcknowledgedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan banner acknowledgment: %w", err)
+		}
+		acks = append(acks, ack)
+	}
+
+	return acks, rows.Err()
+}
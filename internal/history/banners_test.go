@@ -0,0 +1,56 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestBannerAcknowledgments(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "sshm-banner-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	manager, err := NewHistoryManager(filepath.Join(tempDir, "history.db"))
+	if err != nil {
+		t.Fatalf("Failed to create history manager: %v", err)
+	}
+	defer manager.Close()
+
+	now := time.Now()
+	acks := []BannerAcknowledgment{
+		{ServerName: "prod", Host: "prod.example.com", User: "admin", Banner: "Authorized use only", AcknowledgedAt: now.Add(-time.Hour)},
+		{ServerName: "prod", Host: "prod.example.com", User: "admin", Banner: "Authorized use only", AcknowledgedAt: now},
+		{ServerName: "staging", Host: "staging.example.com", User: "deploy", Banner: "Staging", AcknowledgedAt: now},
+	}
+	for _, ack := range acks {
+		if _, err := manager.RecordBannerAcknowledgment(ack); err != nil {
+			t.Fatalf("Failed to record acknowledgment: %v", err)
+		}
+	}
+
+	prod, err := manager.GetBannerAcknowledgments("prod", 0)
+	if err != nil {
+		t.Fatalf("Failed to get acknowledgments: %v", err)
+	}
+	if len(prod) != 2 {
+		t.Fatalf("Expected 2 acknowledgments for prod, got %d", len(prod))
+	}
+	if !prod[0].AcknowledgedAt.After(prod[1].AcknowledgedAt) {
+		t.Error("Expected acknowledgments ordered newest first")
+	}
+	if prod[0].BannerHash != HashBanner("Authorized use only") {
+		t.Error("Expected banner hash to be filled in")
+	}
+
+	all, err := manager.GetBannerAcknowledgments("", 1)
+	if err != nil {
+		t.Fatalf("Failed to get acknowledgments: %v", err)
+	}
+	if len(all) != 1 {
+		t.Errorf("Expected limit to be applied, got %d", len(all))
+	}
+}
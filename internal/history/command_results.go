@@ -0,0 +1,113 @@
+package history
+
+import (
+	"fmt"
+	"time"
+)
+
+// Outcomes of a remote command
+const (
+	CommandSucceeded = "success"
+	CommandFailed    = "failed"
+)
+
+// CommandResult is the last outcome of a remote command on a server. Only the
+// latest run of each command is kept per server.
+type CommandResult struct {
+	ServerName string    `json:"server_name"`
+	Action     string    `json:"action"`            // Power action, e.g. "custom" or "reboot"
+	Command    string    `json:"command,omitempty"` // Command of custom actions
+	Status     string    `json:"status"`            // CommandSucceeded or CommandFailed
+	Output     string    `json:"output,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	RanAt      time.Time `json:"ran_at"`
+}
+
+// Failed reports whether the command failed on the server
+func (r CommandResult) Failed() bool {
+	return r.Status != CommandSucceeded
+}
+
+// CommandRun summarizes the cached results of one command across servers
+type CommandRun struct {
+	Action  string    `json:"action"`
+	Command string    `json:"command,omitempty"`
+	Servers int       `json:"servers"`
+	Failed  int       `json:"failed"`
+	LastRun time.Time `json:"last_run"`
+}
+
+// RecordCommandResult stores the result of a command on a server, replacing
+// the previous result of the same command there
+func (h *HistoryManager) RecordCommandResult(result CommandResult) error {
+	if result.RanAt.IsZero() {
+		result.RanAt = time.Now()
+	}
+	_, err := h.db.Exec(`
+		INSERT OR REPLACE INTO command_results (server_name, action, command, status, output, error_message, ran_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, result.ServerName, result.Action, result.Command, result.Status, result.Output, result.Error, result.RanAt)
+	if err != nil {
+		return fmt.Errorf("failed to record command result: %w", err)
+	}
+	return nil
+}
+
+// GetCommandResults returns the last result of a command on every server it ran on
+func (h *HistoryManager) GetCommandResults(action, command string) ([]CommandResult, error) {
+	rows, err := h.db.Query(`
+		SELECT server_name, action, command, status, COALESCE(output, ''), COALESCE(error_message, ''), ran_at
+		FROM command_results
+		WHERE action = ? AND command = ?
+		ORDER BY server_name
+	`, action, command)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query command results: %w", err)
+	}
+	defer rows.Close()
+
+	var results []CommandResult
+	for rows.Next() {
+		var result CommandResult
+		if err := rows.Scan(&result.ServerName, &result.Action, &result.Command, &result.Status, &result.Output, &result.Error, &result.RanAt); err != nil {
+			return nil, fmt.Errorf("failed to scan command result row: %w", err)
+		}
+		results = append(results, result)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating command result rows: %w", err)
+	}
+
+	return results, nil
+}
+
+// GetCommandRuns lists the commands with cached results, most recently run first
+func (h *HistoryManager) GetCommandRuns(limit int) ([]CommandRun, error) {
+	rows, err := h.db.Query(`
+		SELECT action, command, COUNT(*), SUM(CASE WHEN status = 'success' THEN 0 ELSE 1 END), MAX(ran_at)
+		FROM command_results
+		GROUP BY action, command
+		ORDER BY MAX(ran_at) DESC
+		LIMIT ?
+	`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query command runs: %w", err)
+	}
+	defer rows.Close()
+
+	var runs []CommandRun
+	for rows.Next() {
+		var run CommandRun
+		var last string
+		if err := rows.Scan(&run.Action, &run.Command, &run.Servers, &run.Failed, &last); err != nil {
+			return nil, fmt.Errorf("failed to scan command run row: %w", err)
+		}
+		run.LastRun = parseSQLiteTime(last)
+		runs = append(runs, run)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating command run rows: %w", err)
+	}
+
+	return runs, nil
+}
@@ -0,0 +1,54 @@
+package history
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCommandResults(t *testing.T) {
+	manager, err := NewHistoryManager(filepath.Join(t.TempDir(), "history.db"))
+	if err != nil {
+		t.Fatalf("Failed to create history manager: %v", err)
+	}
+	defer manager.Close()
+
+	earlier := time.Now().Add(-time.Hour)
+	results := []CommandResult{
+		{ServerName: "web", Action: "custom", Command: "uptime", Status: CommandFailed, Error: "exit status 255", RanAt: earlier},
+		{ServerName: "db", Action: "custom", Command: "uptime", Status: CommandSucceeded, Output: "up 3 days", RanAt: earlier},
+		{ServerName: "web", Action: "reboot", Status: CommandSucceeded, RanAt: earlier.Add(-time.Hour)},
+		// A later run replaces the cached result
+		{ServerName: "web", Action: "custom", Command: "uptime", Status: CommandSucceeded, Output: "up 1 day"},
+	}
+	for _, result := range results {
+		if err := manager.RecordCommandResult(result); err != nil {
+			t.Fatalf("Failed to record command result: %v", err)
+		}
+	}
+
+	cached, err := manager.GetCommandResults("custom", "uptime")
+	if err != nil {
+		t.Fatalf("Failed to get command results: %v", err)
+	}
+	if len(cached) != 2 || cached[0].ServerName != "db" || cached[1].ServerName != "web" {
+		t.Fatalf("Expected the results of db and web, got %+v", cached)
+	}
+	if cached[1].Failed() || cached[1].Output != "up 1 day" {
+		t.Errorf("Expected the latest result of web, got %+v", cached[1])
+	}
+	if !cached[1].RanAt.After(earlier) {
+		t.Errorf("Expected the run time to be recorded, got %v", cached[1].RanAt)
+	}
+
+	runs, err := manager.GetCommandRuns(10)
+	if err != nil {
+		t.Fatalf("Failed to get command runs: %v", err)
+	}
+	if len(runs) != 2 || runs[0].Command != "uptime" || runs[1].Action != "reboot" {
+		t.Fatalf("Expected uptime then reboot, got %+v", runs)
+	}
+	if runs[0].Servers != 2 || runs[0].Failed != 0 || runs[0].LastRun.IsZero() {
+		t.Errorf("Unexpected summary of uptime: %+v", runs[0])
+	}
+}
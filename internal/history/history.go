@@ -22,7 +22,7 @@ type ConnectionHistoryEntry struct {
 	Host            string    `json:"host"`
 	User            string    `json:"user"`
 	Port            int       `json:"port"`
-	ConnectionType  string    `json:"connection_type"` // 'single' or 'group'
+	ConnectionType  string    `json:"connection_type"` // 'single', 'group' or 'power:<action>'
 	Status          string    `json:"status"`          // 'success', 'failed', 'timeout', 'cancelled'
 	StartTime       time.Time `json:"start_time"`
 	EndTime         time.Time `json:"end_time,omitempty"`
@@ -0,0 +1,162 @@
+package history
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// HostKeyChange records that a server's pinned host keys were replaced after a rekeying
+type HostKeyChange struct {
+	ID                int       `json:"id"`
+	ServerName        string    `json:"server_name"`
+	Host              string    `json:"host"`
+	User              string    `json:"user"`
+	OldFingerprints   []string  `json:"old_fingerprints"`
+	NewFingerprints   []string  `json:"new_fingerprints"`
+	KnownHostsUpdated bool      `json:"known_hosts_updated"`
+	ChangedAt         time.Time `json:"changed_at"`
+}
+
+// RecordHostKeyChange stores a host key rotation for auditing
+func (h *HistoryManager) RecordHostKeyChange(change HostKeyChange) (int64, error) {
+	if change.ChangedAt.IsZero() {
+		change.ChangedAt = time.Now()
+	}
+
+	result, err := h.db.Exec(`
+		INSERT INTO host_key_changes (server_name, host, user, old_fingerprints, new_fingerprints, known_hosts_updated, changed_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, change.ServerName, change.Host, change.User, strings.Join(change.OldFingerprints, " "),
+		strings.Join(change.NewFingerprints, " "), change.KnownHostsUpdated, change.ChangedAt)
+	if err != nil {
+		return 0, fmt.Errorf("failed to record host key change: %w", err)
+	}
+
+	return result.LastInsertId()
+}
+
+// GetHostKeyChanges returns host key rotations for a server (all servers if empty), newest first
+func (h *HistoryManager) GetHostKeyChanges(serverName string, limit int) ([]HostKeyChange, error) {
+	query := `
+		SELECT id, server_name, host, COALESCE(user, ''), COALESCE(old_fingerprints, ''), new_fingerprints, known_hosts_updated, changed_at
+		FROM host_key_changes
+	`
+	var args []interface{}
+	if serverName != "" {
+		query += " WHERE server_name = ?"
+		args = append(args, serverName)
+	}
+	query += " ORDER BY changed_at DESC, id DESC"
+	if limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, limit)
+	}
+
+	rows, err := h.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query host key changes: %w", err)
+	}
+	defer rows.Close()
+
+	var changes []HostKeyChange
+	for rows.Next() {
+		var change HostKeyChange
+		var oldFingerprints, newFingerprints string
+		if err := rows.Scan(&change.ID, &change.ServerName, &change.Host, &change.User, &oldFingerprints, &newFingerprints,
+			&change.KnownHostsUpdated, &change.ChangedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan host key change: %w", err)
+		}
+		change.OldFingerprints = strings.Fields(oldFingerprints)
+		change.NewFingerprints = strings.Fields(newFingerprints)
+		changes = append(changes, change)
+	}
+
+	return changes, rows.Err()
+}
+
+// HostKeyObservation records the host keys a server presented when it was
+// connected to. Keys are "type fingerprint" pairs, e.g. "ssh-ed25519 SHA256:...".
+type HostKeyObservation struct {
+	ID         int       `json:"id"`
+	ServerName string    `json:"server_name"`
+	Host       string    `json:"host"`
+	Keys       []string  `json:"keys"`
+	Changed    bool      `json:"changed"`  // The keys differed from the last accepted observation
+	Accepted   bool      `json:"accepted"` // The keys are the ones later observations are compared with
+	ObservedAt time.Time `json:"observed_at"`
+}
+
+// RecordHostKeyObservation stores the host keys observed at a connection
+func (h *HistoryManager) RecordHostKeyObservation(observation HostKeyObservation) (int64, error) {
+	if observation.ObservedAt.IsZero() {
+		observation.ObservedAt = time.Now()
+	}
+
+	result, err := h.db.Exec(`
+		INSERT INTO host_key_observations (server_name, host, keys, changed, accepted, observed_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, observation.ServerName, observation.Host, strings.Join(observation.Keys, ","), observation.Changed,
+		observation.Accepted, observation.ObservedAt)
+	if err != nil {
+		return 0, fmt.Errorf("failed to record host key observation: %w", err)
+	}
+
+	return result.LastInsertId()
+}
+
+// LastAcceptedHostKeys returns the latest accepted observation of the host keys
+// stored under host in known_hosts, or nil when none was recorded. Looking them
+// up by host rather than server name keeps them across server renames.
+func (h *HistoryManager) LastAcceptedHostKeys(host string) (*HostKeyObservation, error) {
+	observations, err := h.queryHostKeyObservations(`WHERE host = ? AND accepted = 1`, []interface{}{host}, 1)
+	if err != nil || len(observations) == 0 {
+		return nil, err
+	}
+	return &observations[0], nil
+}
+
+// GetHostIdentityChanges returns the observations whose keys differed from the
+// accepted ones for a server (all servers if empty), newest first
+func (h *HistoryManager) GetHostIdentityChanges(serverName string, limit int) ([]HostKeyObservation, error) {
+	where := "WHERE changed = 1"
+	var args []interface{}
+	if serverName != "" {
+		where += " AND server_name = ?"
+		args = append(args, serverName)
+	}
+	return h.queryHostKeyObservations(where, args, limit)
+}
+
+func (h *HistoryManager) queryHostKeyObservations(where string, args []interface{}, limit int) ([]HostKeyObservation, error) {
+	query := `
+		SELECT id, server_name, host, keys, changed, accepted, observed_at
+		FROM host_key_observations
+	` + where + " ORDER BY observed_at DESC, id DESC"
+	if limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, limit)
+	}
+
+	rows, err := h.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query host key observations: %w", err)
+	}
+	defer rows.Close()
+
+	var observations []HostKeyObservation
+	for rows.Next() {
+		var observation HostKeyObservation
+		var keys string
+		if err := rows.Scan(&observation.ID, &observation.ServerName, &observation.Host, &keys, &observation.Changed,
+			&observation.Accepted, &observation.ObservedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan host key observation: %w", err)
+		}
+		if keys != "" {
+			observation.Keys = strings.Split(keys, ",")
+		}
+		observations = append(observations, observation)
+	}
+
+	return observations, rows.Err()
+}
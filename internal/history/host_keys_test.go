@@ -0,0 +1,85 @@
+package history
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestHostKeyChanges(t *testing.T) {
+	manager, err := NewHistoryManager(filepath.Join(t.TempDir(), "history.db"))
+	if err != nil {
+		t.Fatalf("Failed to create history manager: %v", err)
+	}
+	defer manager.Close()
+
+	now := time.Now()
+	changes := []HostKeyChange{
+		{ServerName: "web1", Host: "web1.example.com", User: "ops", NewFingerprints: []string{"SHA256:aaa"}, ChangedAt: now.Add(-time.Hour)},
+		{ServerName: "web1", Host: "web1.example.com", User: "ops", OldFingerprints: []string{"SHA256:aaa"},
+			NewFingerprints: []string{"SHA256:bbb", "SHA256:ccc"}, KnownHostsUpdated: true, ChangedAt: now},
+		{ServerName: "db1", Host: "db1.example.com", NewFingerprints: []string{"SHA256:ddd"}, ChangedAt: now},
+	}
+	for _, change := range changes {
+		if _, err := manager.RecordHostKeyChange(change); err != nil {
+			t.Fatalf("Failed to record host key change: %v", err)
+		}
+	}
+
+	web, err := manager.GetHostKeyChanges("web1", 0)
+	if err != nil {
+		t.Fatalf("Failed to get host key changes: %v", err)
+	}
+	if len(web) != 2 {
+		t.Fatalf("Expected 2 changes for web1, got %d", len(web))
+	}
+	latest := web[0]
+	if len(latest.OldFingerprints) != 1 || len(latest.NewFingerprints) != 2 || latest.NewFingerprints[1] != "SHA256:ccc" || !latest.KnownHostsUpdated {
+		t.Errorf("Unexpected latest change %+v", latest)
+	}
+	if len(web[1].OldFingerprints) != 0 {
+		t.Errorf("Expected no previous fingerprints on the first pin, got %v", web[1].OldFingerprints)
+	}
+
+	all, err := manager.GetHostKeyChanges("", 1)
+	if err != nil || len(all) != 1 {
+		t.Errorf("Expected the limit to apply, got %d changes, %v", len(all), err)
+	}
+}
+
+func TestHostKeyObservations(t *testing.T) {
+	manager, err := NewHistoryManager(filepath.Join(t.TempDir(), "history.db"))
+	if err != nil {
+		t.Fatalf("Failed to create history manager: %v", err)
+	}
+	defer manager.Close()
+
+	if last, err := manager.LastAcceptedHostKeys("web1.example.com"); err != nil || last != nil {
+		t.Fatalf("Expected no observation yet, got %+v, %v", last, err)
+	}
+
+	now := time.Now()
+	observations := []HostKeyObservation{
+		{ServerName: "web1", Host: "web1.example.com", Keys: []string{"ssh-ed25519 SHA256:aaa", "ssh-rsa SHA256:bbb"}, Accepted: true, ObservedAt: now.Add(-2 * time.Hour)},
+		{ServerName: "web1", Host: "web1.example.com", Keys: []string{"ssh-ed25519 SHA256:evil"}, Changed: true, ObservedAt: now.Add(-time.Hour)},
+		{ServerName: "db1", Host: "db1.example.com", Keys: []string{"ssh-ed25519 SHA256:ddd"}, Accepted: true, ObservedAt: now},
+	}
+	for _, observation := range observations {
+		if _, err := manager.RecordHostKeyObservation(observation); err != nil {
+			t.Fatalf("Failed to record host key observation: %v", err)
+		}
+	}
+
+	last, err := manager.LastAcceptedHostKeys("web1.example.com")
+	if err != nil || last == nil {
+		t.Fatalf("Expected an accepted observation, got %v", err)
+	}
+	if len(last.Keys) != 2 || last.Keys[1] != "ssh-rsa SHA256:bbb" || last.Changed {
+		t.Errorf("Expected the rejected change to be skipped, got %+v", last)
+	}
+
+	changes, err := manager.GetHostIdentityChanges("", 0)
+	if err != nil || len(changes) != 1 || changes[0].Keys[0] != "ssh-ed25519 SHA256:evil" || changes[0].Accepted {
+		t.Errorf("Expected the one change, got %+v, %v", changes, err)
+	}
+}
@@ -104,6 +104,141 @@ func getMigrations() []Migration {
 				DROP VIEW IF EXISTS connection_stats;
 			`,
 		},
+		{
+			Version:     4,
+			Description: "Add login banner acknowledgments",
+			Up: `
+				CREATE TABLE IF NOT EXISTS banner_acknowledgments (
+					id INTEGER PRIMARY KEY AUTOINCREMENT,
+					server_name TEXT NOT NULL,
+					host TEXT NOT NULL,
+					user TEXT NOT NULL,
+					banner TEXT NOT NULL,
+					banner_hash TEXT NOT NULL,
+					acknowledged_at DATETIME NOT NULL
+				);
+
+				CREATE INDEX IF NOT EXISTS idx_banner_acknowledgments_server ON banner_acknowledgments(server_name);
+			`,
+			Down: `
+				DROP INDEX IF EXISTS idx_banner_acknowledgments_server;
+				DROP TABLE IF EXISTS banner_acknowledgments;
+			`,
+		},
+		{
+			Version:     5,
+			Description: "Add status check samples",
+			Up: `
+				CREATE TABLE IF NOT EXISTS status_samples (
+					id INTEGER PRIMARY KEY AUTOINCREMENT,
+					server_name TEXT NOT NULL,
+					status TEXT NOT NULL,
+					latency_ms INTEGER,
+					checked_at DATETIME NOT NULL
+				);
+
+				CREATE INDEX IF NOT EXISTS idx_status_samples_checked_at ON status_samples(checked_at);
+			`,
+			Down: `
+				DROP INDEX IF EXISTS idx_status_samples_checked_at;
+				DROP TABLE IF EXISTS status_samples;
+			`,
+		},
+		{
+			Version:     6,
+			Description: "Add last remote command result per server",
+			Up: `
+				CREATE TABLE IF NOT EXISTS command_results (
+					server_name TEXT NOT NULL,
+					action TEXT NOT NULL,
+					command TEXT NOT NULL,
+					status TEXT NOT NULL,
+					output TEXT,
+					error_message TEXT,
+					ran_at DATETIME NOT NULL,
+					PRIMARY KEY (server_name, action, command)
+				);
+
+				CREATE INDEX IF NOT EXISTS idx_command_results_ran_at ON command_results(ran_at);
+			`,
+			Down: `
+				DROP INDEX IF EXISTS idx_command_results_ran_at;
+				DROP TABLE IF EXISTS command_results;
+			`,
+		},
+		{
+			Version:     7,
+			Description: "Add time spent attached to sessions",
+			Up: `
+				CREATE TABLE IF NOT EXISTS session_time (
+					id INTEGER PRIMARY KEY AUTOINCREMENT,
+					session_name TEXT NOT NULL,
+					server_name TEXT,
+					profile_name TEXT,
+					attached_at DATETIME NOT NULL,
+					detached_at DATETIME NOT NULL
+				);
+
+				CREATE INDEX IF NOT EXISTS idx_session_time_attached_at ON session_time(attached_at);
+			`,
+			Down: `
+				DROP INDEX IF EXISTS idx_session_time_attached_at;
+				DROP TABLE IF EXISTS session_time;
+			`,
+		},
+		{
+			Version:     8,
+			Description: "Add host key rotation audit records",
+			Up: `
+				CREATE TABLE IF NOT EXISTS host_key_changes (
+					id INTEGER PRIMARY KEY AUTOINCREMENT,
+					server_name TEXT NOT NULL,
+					host TEXT NOT NULL,
+					user TEXT,
+					old_fingerprints TEXT,
+					new_fingerprints TEXT NOT NULL,
+					known_hosts_updated BOOLEAN NOT NULL DEFAULT 0,
+					changed_at DATETIME NOT NULL
+				);
+
+				CREATE INDEX IF NOT EXISTS idx_host_key_changes_server ON host_key_changes(server_name);
+			`,
+			Down: `
+				DROP INDEX IF EXISTS idx_host_key_changes_server;
+				DROP TABLE IF EXISTS host_key_changes;
+			`,
+		},
+		{
+			Version:     9,
+			Description: "Add host keys observed at connections",
+			Up: `
+				CREATE TABLE IF NOT EXISTS host_key_observations (
+					id INTEGER PRIMARY KEY AUTOINCREMENT,
+					server_name TEXT NOT NULL,
+					host TEXT NOT NULL,
+					keys TEXT NOT NULL,
+					changed BOOLEAN NOT NULL DEFAULT 0,
+					accepted BOOLEAN NOT NULL DEFAULT 1,
+					observed_at DATETIME NOT NULL
+				);
+
+				CREATE INDEX IF NOT EXISTS idx_host_key_observations_server ON host_key_observations(server_name, observed_at);
+			`,
+			Down: `
+				DROP INDEX IF EXISTS idx_host_key_observations_server;
+				DROP TABLE IF EXISTS host_key_observations;
+			`,
+		},
+		{
+			Version:     10,
+			Description: "Look up accepted host keys by known_hosts name",
+			Up: `
+				CREATE INDEX IF NOT EXISTS idx_host_key_observations_host ON host_key_observations(host, observed_at);
+			`,
+			Down: `
+				DROP INDEX IF EXISTS idx_host_key_observations_host;
+			`,
+		},
 	}
 }
 
@@ -0,0 +1,64 @@
+package history
+
+import (
+	"fmt"
+	"time"
+)
+
+// SessionTime is one stretch of time attached to a tmux session from the TUI
+type SessionTime struct {
+	SessionName string    `json:"session_name"`
+	ServerName  string    `json:"server_name,omitempty"`  // Server of the session, empty for profile sessions
+	ProfileName string    `json:"profile_name,omitempty"` // Profile of the session, or the first profile of its server
+	AttachedAt  time.Time `json:"attached_at"`
+	DetachedAt  time.Time `json:"detached_at"`
+}
+
+// Duration returns how long the session was attached
+func (s SessionTime) Duration() time.Duration {
+	return s.DetachedAt.Sub(s.AttachedAt)
+}
+
+// RecordSessionTime stores the time spent attached to a session
+func (h *HistoryManager) RecordSessionTime(entry SessionTime) error {
+	if !entry.DetachedAt.After(entry.AttachedAt) {
+		return nil
+	}
+	_, err := h.db.Exec(`
+		INSERT INTO session_time (session_name, server_name, profile_name, attached_at, detached_at)
+		VALUES (?, ?, ?, ?, ?)
+	`, entry.SessionName, entry.ServerName, entry.ProfileName, entry.AttachedAt, entry.DetachedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record session time: %w", err)
+	}
+	return nil
+}
+
+// GetSessionTimes returns the time attached to sessions that ended after since,
+// oldest first
+func (h *HistoryManager) GetSessionTimes(since time.Time) ([]SessionTime, error) {
+	rows, err := h.db.Query(`
+		SELECT session_name, COALESCE(server_name, ''), COALESCE(profile_name, ''), attached_at, detached_at
+		FROM session_time
+		WHERE detached_at >= ?
+		ORDER BY attached_at
+	`, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query session time: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []SessionTime
+	for rows.Next() {
+		var entry SessionTime
+		if err := rows.Scan(&entry.SessionName, &entry.ServerName, &entry.ProfileName, &entry.AttachedAt, &entry.DetachedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan session time row: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating session time rows: %w", err)
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,47 @@
+package history
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSessionTime(t *testing.T) {
+	manager, err := NewHistoryManager(filepath.Join(t.TempDir(), "history.db"))
+	if err != nil {
+		t.Fatalf("Failed to create history manager: %v", err)
+	}
+	defer manager.Close()
+
+	now := time.Now()
+	entries := []SessionTime{
+		{SessionName: "web", ServerName: "web", ProfileName: "prod", AttachedAt: now.Add(-48 * time.Hour), DetachedAt: now.Add(-47 * time.Hour)},
+		{SessionName: "prod", ProfileName: "prod", AttachedAt: now.Add(-2 * time.Hour), DetachedAt: now.Add(-90 * time.Minute)},
+		// Nothing is recorded for sessions that were never really attached
+		{SessionName: "db", ServerName: "db", AttachedAt: now, DetachedAt: now},
+	}
+	for _, entry := range entries {
+		if err := manager.RecordSessionTime(entry); err != nil {
+			t.Fatalf("Failed to record session time: %v", err)
+		}
+	}
+
+	recorded, err := manager.GetSessionTimes(now.Add(-72 * time.Hour))
+	if err != nil {
+		t.Fatalf("Failed to get session time: %v", err)
+	}
+	if len(recorded) != 2 || recorded[0].ServerName != "web" || recorded[1].ServerName != "" {
+		t.Fatalf("Expected the web and prod sessions, got %+v", recorded)
+	}
+	if recorded[0].Duration() != time.Hour || recorded[1].Duration() != 30*time.Minute {
+		t.Errorf("Unexpected durations %v and %v", recorded[0].Duration(), recorded[1].Duration())
+	}
+
+	recent, err := manager.GetSessionTimes(now.Add(-24 * time.Hour))
+	if err != nil {
+		t.Fatalf("Failed to get session time: %v", err)
+	}
+	if len(recent) != 1 || recent[0].SessionName != "prod" {
+		t.Errorf("Expected only the prod session, got %+v", recent)
+	}
+}
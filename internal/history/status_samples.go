@@ -0,0 +1,141 @@
+package history
+
+import (
+	"fmt"
+	"time"
+
+	sqlite3 "github.com/mattn/go-sqlite3"
+)
+
+// statusSampleRetention bounds the status check samples kept; one a server every
+// 30 seconds adds up quickly
+const statusSampleRetention = 7 * 24 * time.Hour
+
+// StatusSample is the outcome of one background status check
+type StatusSample struct {
+	ServerName string        `json:"server_name"`
+	Status     string        `json:"status"`
+	Latency    time.Duration `json:"latency,omitempty"`
+	CheckedAt  time.Time     `json:"checked_at"`
+}
+
+// Availability counts the status checks of a server and how many found it online
+type Availability struct {
+	Checks int `json:"checks"`
+	Online int `json:"online"`
+}
+
+// Percent returns the share of checks that found the server online
+func (a Availability) Percent() float64 {
+	if a.Checks == 0 {
+		return 0
+	}
+	return float64(a.Online) * 100 / float64(a.Checks)
+}
+
+// ConnectionCount summarizes the connections made to one server
+type ConnectionCount struct {
+	Total          int       `json:"total"`
+	Successful     int       `json:"successful"`
+	LastConnection time.Time `json:"last_connection"`
+}
+
+// RecordStatusSamples stores the results of a round of status checks and drops
+// samples past the retention period
+func (h *HistoryManager) RecordStatusSamples(samples []StatusSample) error {
+	tx, err := h.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to record status samples: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	for _, sample := range samples {
+		if sample.CheckedAt.IsZero() {
+			sample.CheckedAt = now
+		}
+		if _, err := tx.Exec(`
+			INSERT INTO status_samples (server_name, status, latency_ms, checked_at)
+			VALUES (?, ?, ?, ?)
+		`, sample.ServerName, sample.Status, sample.Latency.Milliseconds(), sample.CheckedAt); err != nil {
+			return fmt.Errorf("failed to record status sample: %w", err)
+		}
+	}
+	if _, err := tx.Exec("DELETE FROM status_samples WHERE checked_at < ?", now.Add(-statusSampleRetention)); err != nil {
+		return fmt.Errorf("failed to cleanup old status samples: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// GetAvailability returns the availability of every server checked since the given time
+func (h *HistoryManager) GetAvailability(since time.Time) (map[string]Availability, error) {
+	rows, err := h.db.Query(`
+		SELECT server_name, COUNT(*), SUM(CASE WHEN status = 'online' THEN 1 ELSE 0 END)
+		FROM status_samples
+		WHERE checked_at >= ?
+		GROUP BY server_name
+	`, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query availability: %w", err)
+	}
+	defer rows.Close()
+
+	availability := make(map[string]Availability)
+	for rows.Next() {
+		var name string
+		var a Availability
+		if err := rows.Scan(&name, &a.Checks, &a.Online); err != nil {
+			return nil, fmt.Errorf("failed to scan availability row: %w", err)
+		}
+		availability[name] = a
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating availability rows: %w", err)
+	}
+
+	return availability, nil
+}
+
+// GetConnectionCounts returns how often each server was connected to, counting
+// single server connections only
+func (h *HistoryManager) GetConnectionCounts() (map[string]ConnectionCount, error) {
+	rows, err := h.db.Query(`
+		SELECT server_name, COUNT(*), SUM(CASE WHEN status = 'success' THEN 1 ELSE 0 END), MAX(start_time)
+		FROM connection_history
+		WHERE connection_type = 'single'
+		GROUP BY server_name
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query connection counts: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]ConnectionCount)
+	for rows.Next() {
+		var name string
+		var count ConnectionCount
+		var last string
+		if err := rows.Scan(&name, &count.Total, &count.Successful, &last); err != nil {
+			return nil, fmt.Errorf("failed to scan connection count row: %w", err)
+		}
+		count.LastConnection = parseSQLiteTime(last)
+		counts[name] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating connection count rows: %w", err)
+	}
+
+	return counts, nil
+}
+
+// parseSQLiteTime parses a timestamp read back through an aggregate, which the
+// driver returns as text, returning the zero time when no format matches
+func parseSQLiteTime(value string) time.Time {
+	for _, layout := range sqlite3.SQLiteTimestampFormats {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return parsed
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,90 @@
+package history
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStatusSamplesAvailability(t *testing.T) {
+	manager, err := NewHistoryManager(filepath.Join(t.TempDir(), "history.db"))
+	if err != nil {
+		t.Fatalf("Failed to create history manager: %v", err)
+	}
+	defer manager.Close()
+
+	now := time.Now()
+	samples := []StatusSample{
+		{ServerName: "web", Status: "online", Latency: 20 * time.Millisecond, CheckedAt: now.Add(-time.Hour)},
+		{ServerName: "web", Status: "online", CheckedAt: now},
+		{ServerName: "web", Status: "unreachable", CheckedAt: now},
+		{ServerName: "db", Status: "online", CheckedAt: now.Add(-48 * time.Hour)},
+		{ServerName: "old", Status: "online", CheckedAt: now.Add(-30 * 24 * time.Hour)},
+	}
+	if err := manager.RecordStatusSamples(samples); err != nil {
+		t.Fatalf("Failed to record status samples: %v", err)
+	}
+
+	availability, err := manager.GetAvailability(now.Add(-24 * time.Hour))
+	if err != nil {
+		t.Fatalf("Failed to get availability: %v", err)
+	}
+	if web := availability["web"]; web.Checks != 3 || web.Online != 2 {
+		t.Errorf("Unexpected availability of web: %+v", web)
+	}
+	if _, ok := availability["db"]; ok {
+		t.Error("Expected samples outside the window to be left out")
+	}
+
+	all, _ := manager.GetAvailability(time.Time{})
+	if _, ok := all["old"]; ok {
+		t.Error("Expected samples past the retention period to be dropped")
+	}
+	if _, ok := all["db"]; !ok {
+		t.Error("Expected samples within the retention period to be kept")
+	}
+}
+
+func TestAvailabilityPercent(t *testing.T) {
+	if got := (Availability{}).Percent(); got != 0 {
+		t.Errorf("Expected 0%% without checks, got %v", got)
+	}
+	if got := (Availability{Checks: 4, Online: 3}).Percent(); got != 75 {
+		t.Errorf("Expected 75%%, got %v", got)
+	}
+}
+
+func TestGetConnectionCounts(t *testing.T) {
+	manager, err := NewHistoryManager(filepath.Join(t.TempDir(), "history.db"))
+	if err != nil {
+		t.Fatalf("Failed to create history manager: %v", err)
+	}
+	defer manager.Close()
+
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.Local)
+	for i, entry := range []ConnectionHistoryEntry{
+		{ServerName: "web", Status: "success", StartTime: start},
+		{ServerName: "web", Status: "failed", StartTime: start.Add(time.Hour)},
+		{ServerName: "db", Status: "success", StartTime: start},
+		{ServerName: "prod", ConnectionType: "group", Status: "success", StartTime: start},
+	} {
+		entry.Host, entry.User, entry.Port = "10.0.0.1", "deploy", 22
+		if entry.ConnectionType == "" {
+			entry.ConnectionType = "single"
+		}
+		if _, err := manager.RecordConnection(entry); err != nil {
+			t.Fatalf("Failed to record connection %d: %v", i, err)
+		}
+	}
+
+	counts, err := manager.GetConnectionCounts()
+	if err != nil {
+		t.Fatalf("Failed to get connection counts: %v", err)
+	}
+	if web := counts["web"]; web.Total != 2 || web.Successful != 1 || !web.LastConnection.Equal(start.Add(time.Hour)) {
+		t.Errorf("Unexpected connection count of web: %+v", web)
+	}
+	if _, ok := counts["prod"]; ok {
+		t.Error("Expected group connections to be left out")
+	}
+}
@@ -0,0 +1,304 @@
+// Package hostkey compares the host keys servers present with known_hosts and
+// the fingerprints pinned in the configuration, and rotates both after a
+// planned rekeying.
+package hostkey
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/ssh"
+	"sshm/internal/config"
+)
+
+// maxParallel limits how many servers are scanned at the same time
+const maxParallel = 5
+
+// scanTimeout is the ssh-keyscan timeout in seconds
+const scanTimeout = "5"
+
+// execCommand is a variable to allow mocking in tests
+var execCommand = exec.Command
+
+// Report states
+const (
+	StatusOK       = "ok"       // Presented keys match known_hosts and the pinned fingerprints
+	StatusChanged  = "changed"  // known_hosts or the pins disagree with the presented keys
+	StatusUnpinned = "unpinned" // Nothing pinned yet; known_hosts, if any, agrees
+	StatusError    = "error"    // The keys could not be fetched
+)
+
+// Comparison results for known_hosts and pins
+const (
+	MatchOK      = "match"
+	MatchChanged = "changed"
+	MatchMissing = "missing" // No known_hosts entry or no pinned fingerprint
+)
+
+// Key is a host key with its SHA256 fingerprint
+type Key struct {
+	Type        string
+	Fingerprint string
+	Line        string // known_hosts entry for the server
+}
+
+// Report compares a server's presented host keys with known_hosts and its pins
+type Report struct {
+	Server     string
+	Host       string // Name the server's keys are stored under in known_hosts
+	Scanned    []Key
+	Known      []Key
+	Pinned     []string
+	KnownHosts string // MatchOK, MatchChanged or MatchMissing
+	Pins       string // MatchOK, MatchChanged or MatchMissing
+	Status     string
+	Error      string
+}
+
+// Fingerprints returns the fingerprints of the presented keys, the new pins after a rotation
+func (r Report) Fingerprints() []string {
+	return fingerprints(r.Scanned)
+}
+
+// NeedsRotation reports whether the pins or known_hosts should be updated to the presented keys
+func (r Report) NeedsRotation() bool {
+	return r.Status == StatusChanged || r.Status == StatusUnpinned
+}
+
+// DefaultKnownHostsPath returns the user's known_hosts file
+func DefaultKnownHostsPath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".ssh", "known_hosts"), nil
+}
+
+// KnownHostsName returns the name ssh stores the server's keys under: the
+// configured hostname (the HostKeyAlias when resolve_to is set), in brackets
+// with the port when it isn't 22
+func KnownHostsName(server config.Server) string {
+	if strings.TrimSpace(server.ResolveTo) != "" {
+		return server.Hostname
+	}
+	if server.Port != 0 && server.Port != 22 {
+		return fmt.Sprintf("[%s]:%d", server.Hostname, server.Port)
+	}
+	return server.Hostname
+}
+
+// Scan fetches the host keys the server presents with ssh-keyscan
+func Scan(server config.Server) ([]Key, error) {
+	args := []string{"-T", scanTimeout}
+	if server.Port != 0 && server.Port != 22 {
+		args = append(args, "-p", fmt.Sprintf("%d", server.Port))
+	}
+	args = append(args, server.GetEffectiveHostname())
+
+	output, err := execCommand("ssh-keyscan", args...).Output()
+	keys := parseKeys(string(output), KnownHostsName(server))
+	if len(keys) == 0 {
+		if err != nil {
+			return nil, fmt.Errorf("ssh-keyscan failed: %w", err)
+		}
+		return nil, fmt.Errorf("no host keys received from %s", server.GetEffectiveHostname())
+	}
+	return keys, nil
+}
+
+// KnownHosts returns the server's keys recorded in a known_hosts file, hashed
+// entries included; a missing file or entry yields no keys
+func KnownHosts(server config.Server, path string) ([]Key, error) {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	name := KnownHostsName(server)
+	output, err := execCommand("ssh-keygen", "-F", name, "-f", path).Output()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		return nil, nil // Not found
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to search %s: %w", path, err)
+	}
+	return parseKeys(string(output), name), nil
+}
+
+// parseKeys parses known_hosts formatted lines, as printed by ssh-keyscan and
+// ssh-keygen -F, into keys stored under name. Markers such as @revoked and
+// duplicate keys are skipped.
+func parseKeys(output, name string) []Key {
+	var keys []Key
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		marker, _, publicKey, _, _, err := ssh.ParseKnownHosts([]byte(line))
+		if err != nil || marker != "" {
+			continue
+		}
+		fingerprint := ssh.FingerprintSHA256(publicKey)
+		if seen[fingerprint] {
+			continue
+		}
+		seen[fingerprint] = true
+		keys = append(keys, Key{
+			Type:        publicKey.Type(),
+			Fingerprint: fingerprint,
+			Line:        name + " " + strings.TrimSpace(string(ssh.MarshalAuthorizedKey(publicKey))),
+		})
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].Type < keys[j].Type })
+	return keys
+}
+
+// Check scans a server and compares its keys with known_hosts and its pins
+func Check(server config.Server, knownHostsPath string) Report {
+	report := Report{Server: server.Name, Host: KnownHostsName(server), Pinned: server.HostKeys}
+
+	scanned, err := Scan(server)
+	if err != nil {
+		report.Status = StatusError
+		report.Error = err.Error()
+		return report
+	}
+	report.Scanned = scanned
+
+	known, err := KnownHosts(server, knownHostsPath)
+	if err != nil {
+		report.Status = StatusError
+		report.Error = err.Error()
+		return report
+	}
+	report.Known = known
+
+	report.KnownHosts = compareKnown(scanned, known)
+	report.Pins = comparePins(scanned, server.HostKeys)
+	switch {
+	case report.KnownHosts == MatchChanged || report.Pins == MatchChanged:
+		report.Status = StatusChanged
+	case report.Pins == MatchMissing:
+		report.Status = StatusUnpinned
+	default:
+		report.Status = StatusOK
+	}
+	return report
+}
+
+// CheckAll checks the servers in parallel, keeping their order
+func CheckAll(servers []config.Server, knownHostsPath string) []Report {
+	reports := make([]Report, len(servers))
+
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, maxParallel)
+	for i, server := range servers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+			reports[i] = Check(server, knownHostsPath)
+		}()
+	}
+	wg.Wait()
+	return reports
+}
+
+// compareKnown compares presented keys with known_hosts: a recorded key of the
+// same type with another fingerprint is a change
+func compareKnown(scanned, known []Key) string {
+	if len(known) == 0 {
+		return MatchMissing
+	}
+	presented := make(map[string]bool)
+	for _, key := range scanned {
+		presented[key.Fingerprint] = true
+	}
+	matched := false
+	for _, key := range known {
+		if presented[key.Fingerprint] {
+			matched = true
+			continue
+		}
+		for _, candidate := range scanned {
+			if candidate.Type == key.Type {
+				return MatchChanged
+			}
+		}
+	}
+	if !matched {
+		return MatchChanged
+	}
+	return MatchOK
+}
+
+// comparePins compares presented keys with pinned fingerprints: every pin must
+// still be presented, while additional presented keys are fine
+func comparePins(scanned []Key, pinned []string) string {
+	if len(pinned) == 0 {
+		return MatchMissing
+	}
+	presented := fingerprints(scanned)
+	for _, fingerprint := range pinned {
+		if !contains(presented, fingerprint) {
+			return MatchChanged
+		}
+	}
+	return MatchOK
+}
+
+// UpdateKnownHosts replaces the server's entries in a known_hosts file with the
+// presented keys. ssh-keygen keeps the previous file as <path>.old.
+func UpdateKnownHosts(path string, report Report) error {
+	if len(report.Scanned) == 0 {
+		return fmt.Errorf("no host keys to write for %s", report.Server)
+	}
+
+	if _, err := os.Stat(path); err == nil {
+		if output, err := execCommand("ssh-keygen", "-R", report.Host, "-f", path).CombinedOutput(); err != nil {
+			return fmt.Errorf("failed to remove old keys of %s: %s", report.Host, strings.TrimSpace(string(output)))
+		}
+	} else if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer file.Close()
+	for _, key := range report.Scanned {
+		if _, err := fmt.Fprintln(file, key.Line); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+func fingerprints(keys []Key) []string {
+	result := make([]string, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, key.Fingerprint)
+	}
+	return result
+}
+
+func contains(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,144 @@
+package hostkey
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+	"sshm/internal/config"
+)
+
+// testKey returns a known_hosts line for a new ed25519 key and its fingerprint
+func testKey(t *testing.T, host string) (string, string) {
+	public, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := ssh.NewPublicKey(public)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return host + " " + strings.TrimSpace(string(ssh.MarshalAuthorizedKey(key))), ssh.FingerprintSHA256(key)
+}
+
+// mockOutputs makes ssh-keyscan and ssh-keygen -F print the given lines
+func mockOutputs(t *testing.T, scanned, known string) {
+	original := execCommand
+	execCommand = func(name string, args ...string) *exec.Cmd {
+		switch {
+		case name == "ssh-keyscan":
+			return exec.Command("printf", "%s", scanned)
+		case name == "ssh-keygen" && args[0] == "-F" && known == "":
+			return exec.Command("false")
+		case name == "ssh-keygen" && args[0] == "-F":
+			return exec.Command("printf", "%s", known)
+		}
+		return original(name, args...)
+	}
+	t.Cleanup(func() { execCommand = original })
+}
+
+func knownHostsFile(t *testing.T) string {
+	path := filepath.Join(t.TempDir(), "known_hosts")
+	if err := os.WriteFile(path, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestKnownHostsName(t *testing.T) {
+	tests := []struct {
+		server config.Server
+		want   string
+	}{
+		{config.Server{Hostname: "web1.example.com", Port: 22}, "web1.example.com"},
+		{config.Server{Hostname: "web1.example.com", Port: 2222}, "[web1.example.com]:2222"},
+		{config.Server{Hostname: "web1.example.com", Port: 2222, ResolveTo: "10.0.0.1"}, "web1.example.com"},
+	}
+	for _, tt := range tests {
+		if got := KnownHostsName(tt.server); got != tt.want {
+			t.Errorf("KnownHostsName(%+v) = %s, want %s", tt.server, got, tt.want)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	current, currentFingerprint := testKey(t, "web1.example.com")
+	old, oldFingerprint := testKey(t, "web1.example.com")
+	path := knownHostsFile(t)
+	server := config.Server{Name: "web1", Hostname: "web1.example.com", Port: 22}
+
+	tests := []struct {
+		name       string
+		known      string
+		pinned     []string
+		knownHosts string
+		pins       string
+		status     string
+	}{
+		{"all match", current, []string{currentFingerprint}, MatchOK, MatchOK, StatusOK},
+		{"not pinned", current, nil, MatchOK, MatchMissing, StatusUnpinned},
+		{"unknown host", "", nil, MatchMissing, MatchMissing, StatusUnpinned},
+		{"rekeyed", old, []string{oldFingerprint}, MatchChanged, MatchChanged, StatusChanged},
+		{"stale pin", current, []string{oldFingerprint}, MatchOK, MatchChanged, StatusChanged},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockOutputs(t, "# web1.example.com:22 SSH-2.0-OpenSSH_9.6\n"+current+"\n", tt.known)
+			server.HostKeys = tt.pinned
+
+			report := Check(server, path)
+			if report.KnownHosts != tt.knownHosts || report.Pins != tt.pins || report.Status != tt.status {
+				t.Errorf("Check() = known_hosts %s, pins %s, status %s (%s); want %s, %s, %s",
+					report.KnownHosts, report.Pins, report.Status, report.Error, tt.knownHosts, tt.pins, tt.status)
+			}
+			if fingerprints := report.Fingerprints(); len(fingerprints) != 1 || fingerprints[0] != currentFingerprint {
+				t.Errorf("Unexpected presented fingerprints %v", fingerprints)
+			}
+		})
+	}
+}
+
+func TestCheckScanFailure(t *testing.T) {
+	original := execCommand
+	execCommand = func(name string, args ...string) *exec.Cmd { return exec.Command("false") }
+	t.Cleanup(func() { execCommand = original })
+
+	report := Check(config.Server{Name: "web1", Hostname: "web1.example.com"}, knownHostsFile(t))
+	if report.Status != StatusError || report.Error == "" || report.NeedsRotation() {
+		t.Errorf("Expected a scan error, got %+v", report)
+	}
+}
+
+func TestUpdateKnownHosts(t *testing.T) {
+	if _, err := exec.LookPath("ssh-keygen"); err != nil {
+		t.Skip("ssh-keygen not available")
+	}
+
+	current, _ := testKey(t, "web1.example.com")
+	old, _ := testKey(t, "web1.example.com")
+	other, _ := testKey(t, "db1.example.com")
+	path := knownHostsFile(t)
+	if err := os.WriteFile(path, []byte(old+"\n"+other+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	report := Report{Server: "web1", Host: "web1.example.com", Scanned: parseKeys(current, "web1.example.com")}
+	if err := UpdateKnownHosts(path, report); err != nil {
+		t.Fatalf("UpdateKnownHosts() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := string(data)
+	if strings.Contains(content, old) || !strings.Contains(content, current) || !strings.Contains(content, other) {
+		t.Errorf("Expected only web1's key to be replaced, got:\n%s", content)
+	}
+}
@@ -0,0 +1,136 @@
+package hostkey
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"sshm/internal/config"
+	"sshm/internal/history"
+)
+
+// IdentityChange is a server presenting other host keys than the ones accepted
+// at an earlier connection
+type IdentityChange struct {
+	Server       string
+	Host         string
+	Previous     []Key
+	PreviousSeen time.Time
+	Current      []Key
+}
+
+// Summary describes the change for alerts
+func (c IdentityChange) Summary() string {
+	return fmt.Sprintf("%s presented different host keys than on %s.\n\nBefore: %s\nNow: %s",
+		c.Server, c.PreviousSeen.Local().Format("2006-01-02 15:04"), describeKeys(c.Previous), describeKeys(c.Current))
+}
+
+// Observable reports whether the keys a server presents at a connection can be
+// observed: servers reached through jump hosts or an ssh template aren't
+// scanned, since ssh-keyscan would reach another host or none at all
+func Observable(server config.Server) bool {
+	return len(server.ProxyJump) == 0 && strings.TrimSpace(server.SSHTemplate) == ""
+}
+
+// ObserveIdentity scans the keys the server presents and compares them with the
+// ones accepted at earlier connections. Unchanged keys, and those of a server
+// seen for the first time, are recorded as accepted; changed ones are recorded
+// for auditing and returned until AcceptIdentity accepts them.
+func ObserveIdentity(h *history.HistoryManager, server config.Server) (*IdentityChange, error) {
+	if !Observable(server) {
+		return nil, nil
+	}
+	scanned, err := Scan(server)
+	if err != nil {
+		return nil, err
+	}
+
+	last, err := h.LastAcceptedHostKeys(KnownHostsName(server))
+	if err != nil {
+		return nil, err
+	}
+	if last == nil || compareKnown(scanned, observedKeys(last.Keys)) == MatchOK {
+		return nil, AcceptIdentity(h, server.Name, KnownHostsName(server), scanned)
+	}
+
+	change := &IdentityChange{
+		Server:       server.Name,
+		Host:         KnownHostsName(server),
+		Previous:     observedKeys(last.Keys),
+		PreviousSeen: last.ObservedAt,
+		Current:      scanned,
+	}
+	_, err = h.RecordHostKeyObservation(history.HostKeyObservation{
+		ServerName: server.Name,
+		Host:       change.Host,
+		Keys:       keyPairs(scanned),
+		Changed:    true,
+	})
+	return change, err
+}
+
+// AcceptIdentity records keys as the ones later connections to the server are
+// compared with; host is the name its keys are stored under in known_hosts
+func AcceptIdentity(h *history.HistoryManager, serverName, host string, keys []Key) error {
+	_, err := h.RecordHostKeyObservation(history.HostKeyObservation{
+		ServerName: serverName,
+		Host:       host,
+		Keys:       keyPairs(keys),
+		Accepted:   true,
+	})
+	return err
+}
+
+// UnacceptedChanges returns the names of the servers whose host keys changed
+// and weren't accepted since, most recently changed first. Connections to them
+// keep warning until their new keys are trusted.
+func UnacceptedChanges(h *history.HistoryManager) ([]string, error) {
+	changes, err := h.GetHostIdentityChanges("", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	seen := make(map[string]bool)
+	for _, change := range changes {
+		if seen[change.Host] {
+			continue
+		}
+		seen[change.Host] = true
+		last, err := h.LastAcceptedHostKeys(change.Host)
+		if err != nil {
+			return nil, err
+		}
+		if last == nil || last.ID < change.ID {
+			names = append(names, change.ServerName)
+		}
+	}
+	return names, nil
+}
+
+// keyPairs returns keys as "type fingerprint" pairs for the history
+func keyPairs(keys []Key) []string {
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, key.Type+" "+key.Fingerprint)
+	}
+	return pairs
+}
+
+// observedKeys parses "type fingerprint" pairs from the history
+func observedKeys(pairs []string) []Key {
+	var keys []Key
+	for _, pair := range pairs {
+		if keyType, fingerprint, ok := strings.Cut(pair, " "); ok {
+			keys = append(keys, Key{Type: keyType, Fingerprint: fingerprint})
+		}
+	}
+	return keys
+}
+
+func describeKeys(keys []Key) string {
+	if len(keys) == 0 {
+		return "none"
+	}
+	return strings.Join(keyPairs(keys), ", ")
+}
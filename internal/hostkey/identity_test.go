@@ -0,0 +1,97 @@
+package hostkey
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"sshm/internal/config"
+	"sshm/internal/history"
+)
+
+func TestObserveIdentity(t *testing.T) {
+	h, err := history.NewHistoryManager(filepath.Join(t.TempDir(), "history.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+	server := config.Server{Name: "web1", Hostname: "web1.example.com", Port: 22}
+	original, _ := testKey(t, "web1.example.com")
+	replaced, replacedFingerprint := testKey(t, "web1.example.com")
+
+	// The first connection is trusted, like ssh's first connection
+	mockOutputs(t, original+"\n", "")
+	if change, err := ObserveIdentity(h, server); err != nil || change != nil {
+		t.Fatalf("Expected the first observation to be accepted, got %+v, %v", change, err)
+	}
+	if change, err := ObserveIdentity(h, server); err != nil || change != nil {
+		t.Fatalf("Expected unchanged keys to pass, got %+v, %v", change, err)
+	}
+
+	mockOutputs(t, replaced+"\n", "")
+	change, err := ObserveIdentity(h, server)
+	if err != nil || change == nil {
+		t.Fatalf("Expected the changed key to be reported, got %v", err)
+	}
+	if change.Current[0].Fingerprint != replacedFingerprint || len(change.Previous) != 1 || !strings.Contains(change.Summary(), replacedFingerprint) {
+		t.Errorf("Unexpected change %+v", change)
+	}
+	// Until accepted, the change is reported at every connection
+	if change, _ := ObserveIdentity(h, server); change == nil {
+		t.Error("Expected the unaccepted change to be reported again")
+	}
+	if changes, _ := h.GetHostIdentityChanges("web1", 0); len(changes) != 2 {
+		t.Errorf("Expected both changed observations in the history, got %d", len(changes))
+	}
+	if names, err := UnacceptedChanges(h); err != nil || len(names) != 1 || names[0] != "web1" {
+		t.Errorf("UnacceptedChanges() = %v, %v, want [web1]", names, err)
+	}
+
+	if err := AcceptIdentity(h, change.Server, change.Host, change.Current); err != nil {
+		t.Fatal(err)
+	}
+	if change, err := ObserveIdentity(h, server); err != nil || change != nil {
+		t.Errorf("Expected the accepted keys to pass, got %+v, %v", change, err)
+	}
+	if names, err := UnacceptedChanges(h); err != nil || len(names) != 0 {
+		t.Errorf("UnacceptedChanges() after accepting = %v, %v, want none", names, err)
+	}
+}
+
+func TestObserveIdentityAfterRename(t *testing.T) {
+	h, err := history.NewHistoryManager(filepath.Join(t.TempDir(), "history.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+	original, _ := testKey(t, "web1.example.com")
+	replaced, _ := testKey(t, "web1.example.com")
+
+	mockOutputs(t, original+"\n", "")
+	if change, err := ObserveIdentity(h, config.Server{Name: "web1", Hostname: "web1.example.com", Port: 22}); err != nil || change != nil {
+		t.Fatalf("Expected the first observation to be accepted, got %+v, %v", change, err)
+	}
+
+	// A renamed server is still the same host, so its new key isn't "first seen"
+	renamed := config.Server{Name: "prod-web1", Hostname: "web1.example.com", Port: 22}
+	mockOutputs(t, replaced+"\n", "")
+	change, err := ObserveIdentity(h, renamed)
+	if err != nil || change == nil {
+		t.Fatalf("Expected the changed key to be reported after the rename, got %v", err)
+	}
+	if names, err := UnacceptedChanges(h); err != nil || len(names) != 1 || names[0] != "prod-web1" {
+		t.Errorf("UnacceptedChanges() = %v, %v, want [prod-web1]", names, err)
+	}
+}
+
+func TestObservable(t *testing.T) {
+	if !Observable(config.Server{Hostname: "web1"}) {
+		t.Error("Expected a direct server to be observable")
+	}
+	if Observable(config.Server{Hostname: "db1", ProxyJump: []string{"bastion"}}) {
+		t.Error("Expected a server behind a jump host not to be observable")
+	}
+	if Observable(config.Server{Hostname: "db1", SSHTemplate: "{ssh} -o ProxyCommand=x {user}@{host}"}) {
+		t.Error("Expected a server with an ssh template not to be observable")
+	}
+}
@@ -0,0 +1,250 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+	"sshm/internal/config"
+)
+
+// DefaultFormat is assumed when neither the content nor the file name tells the format
+const DefaultFormat = "yaml"
+
+// SniffLen is how much of a file is read to recognize its format
+const SniffLen = 64 * 1024
+
+// Importer reads servers and profiles from a file format. Register one to make
+// the format available to 'sshm import' and the TUI import dialog.
+type Importer interface {
+	Name() string         // Format name, as given to --type, e.g. "yaml"
+	Extensions() []string // File extensions, used when the content is not recognized
+	// CanParse reports whether data, possibly only the first SniffLen bytes of a
+	// file, looks like this format. It must be cheap and never fully parse.
+	CanParse(data []byte) bool
+	Parse(data []byte) ([]config.Server, []config.Profile, error)
+}
+
+// PathMatcher is implemented by importers that recognize files by name rather
+// than extension, such as ~/.ssh/config
+type PathMatcher interface {
+	MatchesPath(path string) bool
+}
+
+// FileParser is implemented by importers whose files refer to other files, such
+// as SSH configs with Include directives; it is used instead of Parse when the
+// file's path is known
+type FileParser interface {
+	ParseFile(path string) ([]config.Server, []config.Profile, error)
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   []Importer // In registration order, which is also the sniffing order
+)
+
+func init() {
+	Register(yamlImporter{})
+	Register(jsonImporter{})
+	Register(sshImporter{})
+}
+
+// Register adds an importer. Names are case-insensitive; registering a name
+// twice is an error.
+func Register(i Importer) error {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	name := strings.ToLower(i.Name())
+	if name == "" {
+		return fmt.Errorf("importer name must not be empty")
+	}
+	for _, existing := range registry {
+		if strings.ToLower(existing.Name()) == name {
+			return fmt.Errorf("importer '%s' is already registered", name)
+		}
+	}
+	registry = append(registry, i)
+	return nil
+}
+
+// Lookup returns the importer of a format name
+func Lookup(name string) (Importer, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	for _, i := range registry {
+		if strings.EqualFold(i.Name(), name) {
+			return i, true
+		}
+	}
+	return nil, false
+}
+
+// Names lists the registered format names in registration order
+func Names() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for _, i := range registry {
+		names = append(names, strings.ToLower(i.Name()))
+	}
+	return names
+}
+
+// DetectFormat returns the format of a file from the start of its content,
+// falling back to its name when no importer recognizes the content, and to
+// DefaultFormat when nothing matches at all
+func DetectFormat(path string, data []byte) string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	if len(data) > 0 {
+		for _, i := range registry {
+			if i.CanParse(data) {
+				return strings.ToLower(i.Name())
+			}
+		}
+	}
+
+	ext := strings.ToLower(filepath.Ext(path))
+	for _, i := range registry {
+		for _, candidate := range i.Extensions() {
+			if ext != "" && strings.ToLower(candidate) == ext {
+				return strings.ToLower(i.Name())
+			}
+		}
+	}
+	for _, i := range registry {
+		if matcher, ok := i.(PathMatcher); ok && matcher.MatchesPath(path) {
+			return strings.ToLower(i.Name())
+		}
+	}
+	return DefaultFormat
+}
+
+// DetectFile sniffs the first SniffLen bytes of a file to detect its format. A
+// file that cannot be read is detected by name only.
+func DetectFile(path string) string {
+	var data []byte
+	if file, err := os.Open(path); err == nil {
+		data, _ = io.ReadAll(io.LimitReader(file, SniffLen))
+		file.Close()
+	}
+	return DetectFormat(path, data)
+}
+
+// yamlImporter reads the native sshm configuration format
+type yamlImporter struct{}
+
+func (yamlImporter) Name() string         { return "yaml" }
+func (yamlImporter) Extensions() []string { return []string{".yaml", ".yml"} }
+
+// CanParse looks for a top-level servers or profiles key
+func (yamlImporter) CanParse(data []byte) bool {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "servers:") || strings.HasPrefix(line, "profiles:") {
+			return true
+		}
+	}
+	return false
+}
+
+func (yamlImporter) Parse(data []byte) ([]config.Server, []config.Profile, error) {
+	var cfg config.Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse YAML: %w", err)
+	}
+	return cfg.Servers, cfg.Profiles, nil
+}
+
+// jsonImporter reads configurations exported as JSON
+type jsonImporter struct{}
+
+func (jsonImporter) Name() string         { return "json" }
+func (jsonImporter) Extensions() []string { return []string{".json"} }
+
+// CanParse looks for an object with a servers or profiles key
+func (jsonImporter) CanParse(data []byte) bool {
+	trimmed := bytes.TrimSpace(data)
+	if !bytes.HasPrefix(trimmed, []byte("{")) {
+		return false
+	}
+	return bytes.Contains(trimmed, []byte(`"servers"`)) || bytes.Contains(trimmed, []byte(`"profiles"`))
+}
+
+func (jsonImporter) Parse(data []byte) ([]config.Server, []config.Profile, error) {
+	var cfg config.Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse JSON: %w", err)
+	}
+	return cfg.Servers, cfg.Profiles, nil
+}
+
+// sshImporter reads Host blocks from OpenSSH client configuration files
+type sshImporter struct{}
+
+func (sshImporter) Name() string         { return "ssh" }
+func (sshImporter) Extensions() []string { return nil }
+
+// CanParse looks for a Host or Match block
+func (sshImporter) CanParse(data []byte) bool {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		keyword := strings.ToLower(fields[0])
+		if keyword == "host" || keyword == "match" {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchesPath recognizes the usual SSH config file names
+func (sshImporter) MatchesPath(path string) bool {
+	base := strings.ToLower(filepath.Base(path))
+	return base == "config" || base == "ssh_config" || strings.Contains(base, "ssh")
+}
+
+func (sshImporter) Parse(data []byte) ([]config.Server, []config.Profile, error) {
+	servers, err := config.ParseSSHConfigReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, nil, err
+	}
+	return servers, nil, nil
+}
+
+// ParseFile also reads the files the config includes
+func (sshImporter) ParseFile(path string) ([]config.Server, []config.Profile, error) {
+	file, err := config.ReadSSHConfig(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	return file.Servers, nil, nil
+}
+
+// ParseFile parses a file with an importer, letting it follow references to
+// other files when it can
+func ParseFile(i Importer, path string) ([]config.Server, []config.Profile, error) {
+	if parser, ok := i.(FileParser); ok {
+		return parser.ParseFile(path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	return i.Parse(data)
+}
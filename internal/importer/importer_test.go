@@ -0,0 +1,106 @@
+package importer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"sshm/internal/config"
+)
+
+const (
+	yamlSample = "servers:\n  - name: web\n    hostname: 10.0.0.1\n    port: 22\n    username: deploy\n    auth_type: key\n    key_path: ~/.ssh/id_rsa\nprofiles:\n  - name: prod\n    servers: [web]\n"
+	jsonSample = `{"servers": [{"name": "web", "hostname": "10.0.0.1", "port": 22, "username": "deploy", "auth_type": "key", "key_path": "~/.ssh/id_rsa"}]}`
+	sshSample  = "# jump hosts\nHost web\n  HostName 10.0.0.1\n  User deploy\n  IdentityFile ~/.ssh/id_rsa\n"
+)
+
+// csvImporter stands in for a third-party format
+type csvImporter struct{}
+
+func (csvImporter) Name() string         { return "csv" }
+func (csvImporter) Extensions() []string { return []string{".csv"} }
+
+func (csvImporter) CanParse(data []byte) bool {
+	return strings.HasPrefix(string(data), "name,hostname")
+}
+
+func (csvImporter) Parse(data []byte) ([]config.Server, []config.Profile, error) {
+	var servers []config.Server
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n")[1:] {
+		fields := strings.Split(line, ",")
+		servers = append(servers, config.Server{Name: fields[0], Hostname: fields[1], Port: 22})
+	}
+	return servers, nil, nil
+}
+
+func TestBuiltinImportersContract(t *testing.T) {
+	samples := map[string]string{"yaml": yamlSample, "json": jsonSample, "ssh": sshSample}
+	for name, sample := range samples {
+		imp, ok := Lookup(name)
+		if !ok {
+			t.Fatalf("Expected %s to be registered", name)
+		}
+		for other, otherSample := range samples {
+			if got := imp.CanParse([]byte(otherSample)); got != (other == name) {
+				t.Errorf("%s CanParse(%s sample) = %v", name, other, got)
+			}
+		}
+
+		servers, _, err := imp.Parse([]byte(sample))
+		if err != nil {
+			t.Fatalf("%s Parse failed: %v", name, err)
+		}
+		if len(servers) != 1 || servers[0].Name != "web" || servers[0].Hostname != "10.0.0.1" || servers[0].Username != "deploy" {
+			t.Errorf("%s Parse returned %+v", name, servers)
+		}
+	}
+}
+
+func TestDetectFormat(t *testing.T) {
+	tests := []struct {
+		path string
+		data string
+		want string
+	}{
+		{"servers.txt", jsonSample, "json"}, // content beats the extension
+		{"export.yaml", sshSample, "ssh"},   // even a misleading one
+		{"servers.json", "", "json"},        // no content: extension
+		{"servers.yml", "", "yaml"},
+		{"ssh_config", "", "ssh"},              // no content: well-known name
+		{"notes.txt", "hello world\n", "yaml"}, // nothing matches: default
+	}
+	for _, tt := range tests {
+		if got := DetectFormat(tt.path, []byte(tt.data)); got != tt.want {
+			t.Errorf("DetectFormat(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestDetectFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hosts")
+	if err := os.WriteFile(path, []byte(jsonSample), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if got := DetectFile(path); got != "json" {
+		t.Errorf("Expected JSON content to be sniffed, got %q", got)
+	}
+	if got := DetectFile(filepath.Join(t.TempDir(), "missing.json")); got != "json" {
+		t.Errorf("Expected a missing file to be detected by name, got %q", got)
+	}
+}
+
+func TestRegister(t *testing.T) {
+	if err := Register(csvImporter{}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := Register(csvImporter{}); err == nil {
+		t.Error("Expected registering a name twice to fail")
+	}
+	if got := DetectFormat("inventory.dat", []byte("name,hostname\nweb,10.0.0.1\n")); got != "csv" {
+		t.Errorf("Expected the csv importer to recognize its content, got %q", got)
+	}
+	if names := Names(); names[len(names)-1] != "csv" {
+		t.Errorf("Expected csv last in %v", names)
+	}
+}
@@ -0,0 +1,38 @@
+package monitor
+
+import (
+	"net"
+	"sync"
+)
+
+var (
+	lastAddresses   = make(map[string]string)
+	lastAddressesMu sync.RWMutex
+)
+
+// LastAddress returns the IP address the last status check of the server
+// connected to, which tells when DNS now points somewhere else
+func LastAddress(serverName string) (string, bool) {
+	lastAddressesMu.RLock()
+	defer lastAddressesMu.RUnlock()
+	address, ok := lastAddresses[serverName]
+	return address, ok
+}
+
+func recordAddress(serverName, address string) {
+	if address == "" {
+		return
+	}
+	lastAddressesMu.Lock()
+	defer lastAddressesMu.Unlock()
+	lastAddresses[serverName] = address
+}
+
+// remoteIP returns the IP address of a connection's remote end
+func remoteIP(conn net.Conn) string {
+	host, _, err := net.SplitHostPort(conn.RemoteAddr().String())
+	if err != nil {
+		return ""
+	}
+	return host
+}
@@ -0,0 +1,91 @@
+package monitor
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"sshm/internal/config"
+)
+
+// AuthAttempt is a method of the authentication chain that did not work
+type AuthAttempt struct {
+	Step config.AuthStep
+	Err  error
+}
+
+// AuthResult is the outcome of the last status check of a server
+type AuthResult struct {
+	Succeeded *config.AuthStep // Method that authenticated, nil when none did
+	Attempts  []AuthAttempt    // Methods tried before, in order
+	Err       error            // Why no method could be used, e.g. an invalid auth_chain
+}
+
+// Summary describes the result for the detail pane, e.g.
+// "agent (after key:~/.ssh/work: permission denied)"
+func (r AuthResult) Summary() string {
+	if r.Succeeded != nil {
+		if len(r.Attempts) == 0 {
+			return r.Succeeded.String()
+		}
+		return fmt.Sprintf("%s (after %s)", r.Succeeded, r.describeAttempts())
+	}
+	if r.Err != nil {
+		return r.Err.Error()
+	}
+	return r.describeAttempts()
+}
+
+// chainError reports every method that was attempted
+func (r AuthResult) chainError() error {
+	if len(r.Attempts) == 0 {
+		return fmt.Errorf("no authentication method configured")
+	}
+	return fmt.Errorf("all authentication methods failed: %s", r.describeAttempts())
+}
+
+func (r AuthResult) describeAttempts() string {
+	parts := make([]string, 0, len(r.Attempts))
+	for _, attempt := range r.Attempts {
+		parts = append(parts, fmt.Sprintf("%s: %v", attempt.Step, attempt.Err))
+	}
+	return strings.Join(parts, "; ")
+}
+
+var (
+	authResults   = make(map[string]AuthResult)
+	authResultsMu sync.RWMutex
+)
+
+// LastAuthResult returns the authentication outcome of the server's last status check
+func LastAuthResult(serverName string) (AuthResult, bool) {
+	authResultsMu.RLock()
+	defer authResultsMu.RUnlock()
+	result, ok := authResults[serverName]
+	return result, ok
+}
+
+func recordAuthResult(serverName string, result AuthResult) {
+	authResultsMu.Lock()
+	defer authResultsMu.Unlock()
+	authResults[serverName] = result
+}
+
+// orderedAuthSteps returns the server's chain with the method that last succeeded first
+func orderedAuthSteps(server config.Server) ([]config.AuthStep, error) {
+	steps, err := server.AuthSteps()
+	if err != nil {
+		return nil, err
+	}
+	previous, ok := LastAuthResult(server.Name)
+	if !ok || previous.Succeeded == nil {
+		return steps, nil
+	}
+	for i, step := range steps {
+		if step == *previous.Succeeded && i > 0 {
+			ordered := append([]config.AuthStep{step}, steps[:i]...)
+			return append(ordered, steps[i+1:]...), nil
+		}
+	}
+	return steps, nil
+}
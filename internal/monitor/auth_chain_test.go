@@ -0,0 +1,142 @@
+package monitor
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+	"sshm/internal/config"
+	"sshm/internal/secrets"
+	sshmssh "sshm/internal/ssh"
+)
+
+func TestAuthMethodFollowsChain(t *testing.T) {
+	original := vaultPassword
+	defer func() { vaultPassword = original }()
+	vaultPassword = func(server config.Server) (string, error) {
+		return "secret", nil
+	}
+
+	t.Setenv("SSH_AUTH_SOCK", "")
+	server := config.Server{Name: "chain-web", AuthType: "key", KeyPath: "/missing/key",
+		AuthChain: []string{"agent", "key", "password-vault"}}
+	if _, err := AuthMethod(server); err != nil {
+		t.Errorf("Expected the vault password to be used after agent and key failed, got %v", err)
+	}
+
+	vaultPassword = func(server config.Server) (string, error) {
+		return "", errors.New("no password stored")
+	}
+	_, err := AuthMethod(server)
+	if err == nil {
+		t.Fatal("Expected an error when no method can be prepared")
+	}
+	for _, attempted := range []string{"agent:", "key:/missing/key:", "password-vault: no password stored"} {
+		if !strings.Contains(err.Error(), attempted) {
+			t.Errorf("Expected error %q to name the attempted method %q", err, attempted)
+		}
+	}
+}
+
+func TestAuthMethodUsesStoredPassphrase(t *testing.T) {
+	defer secrets.Use(secrets.NewMemoryStore())()
+
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block, err := ssh.MarshalPrivateKeyWithPassphrase(key, "", []byte("key pass"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyPath := filepath.Join(t.TempDir(), "id_ed25519")
+	if err := os.WriteFile(keyPath, pem.EncodeToMemory(block), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	server := config.Server{Name: "locked-db", Hostname: "db.example.com", Port: 22, Username: "ops",
+		AuthType: "key", KeyPath: keyPath, PassphraseProtected: true}
+	step := config.AuthStep{Method: config.AuthStepKey, KeyPath: keyPath}
+	if _, err := authMethodForStep(server, step); err == nil || !strings.Contains(err.Error(), "isn't in the keyring") {
+		t.Errorf("Expected a missing passphrase to fail without prompting, got %v", err)
+	}
+
+	if err := secrets.Save(&server, "", "key pass"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := authMethodForStep(server, step); err != nil {
+		t.Errorf("Expected the stored passphrase to unlock the key, got %v", err)
+	}
+}
+
+func TestOrderedAuthStepsPrefersLastSuccess(t *testing.T) {
+	server := config.Server{Name: "ordered-web", AuthType: "key", KeyPath: "/k", AuthChain: []string{"agent", "key", "password-vault"}}
+	steps, _ := orderedAuthSteps(server)
+	if steps[0].Method != config.AuthStepAgent {
+		t.Fatalf("Expected the configured order without a previous result, got %+v", steps)
+	}
+
+	succeeded := config.AuthStep{Method: config.AuthStepPasswordVault}
+	recordAuthResult(server.Name, AuthResult{Succeeded: &succeeded})
+	steps, _ = orderedAuthSteps(server)
+	var order []string
+	for _, step := range steps {
+		order = append(order, step.String())
+	}
+	if got := strings.Join(order, ","); got != "password-vault,agent,key:/k" {
+		t.Errorf("orderedAuthSteps() = %s, want the last successful method first", got)
+	}
+}
+
+func TestAuthResultSummary(t *testing.T) {
+	key := config.AuthStep{Method: config.AuthStepKey, KeyPath: "~/.ssh/work"}
+	agent := config.AuthStep{Method: config.AuthStepAgent}
+
+	result := AuthResult{Succeeded: &agent, Attempts: []AuthAttempt{{Step: key, Err: errors.New("permission denied")}}}
+	if got := result.Summary(); got != "agent (after key:~/.ssh/work: permission denied)" {
+		t.Errorf("Summary() = %q", got)
+	}
+
+	failed := AuthResult{Attempts: []AuthAttempt{{Step: key, Err: errors.New("permission denied")}, {Step: agent, Err: errors.New("no agent")}}}
+	failed.Err = failed.chainError()
+	if got := failed.Summary(); got != "all authentication methods failed: key:~/.ssh/work: permission denied; agent: no agent" {
+		t.Errorf("Summary() = %q", got)
+	}
+}
+
+type tokenProvider struct{}
+
+func (tokenProvider) Name() string { return "chain-sso" }
+
+func (tokenProvider) AuthMethod(request sshmssh.AuthRequest) (ssh.AuthMethod, error) {
+	if request.Options["token"] == "" {
+		return nil, errors.New("not logged in")
+	}
+	return ssh.Password(request.Options["token"]), nil
+}
+
+func (tokenProvider) SSHArgs(request sshmssh.AuthRequest) ([]string, error) { return nil, nil }
+
+func TestAuthMethodUsesProvider(t *testing.T) {
+	if err := sshmssh.RegisterAuthProvider(tokenProvider{}); err != nil {
+		t.Fatal(err)
+	}
+	defer sshmssh.UnregisterAuthProvider("chain-sso")
+
+	server := config.Server{Name: "provider-web", AuthProvider: "chain-sso", AuthChain: []string{"provider"},
+		AuthProviderOptions: map[string]string{"token": "abc"}}
+	if _, err := AuthMethod(server); err != nil {
+		t.Errorf("Expected the provider to authenticate, got %v", err)
+	}
+
+	server.AuthProviderOptions = nil
+	if _, err := AuthMethod(server); err == nil || !strings.Contains(err.Error(), "provider:chain-sso: auth provider 'chain-sso': not logged in") {
+		t.Errorf("Expected the provider's error, got %v", err)
+	}
+}
@@ -0,0 +1,194 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"sshm/internal/config"
+	"sshm/internal/hostkey"
+	"sshm/internal/retry"
+	"sshm/internal/secrets"
+	sshmssh "sshm/internal/ssh"
+)
+
+// CheckTimeout is how long a single connection test may take
+const CheckTimeout = 5 * time.Second
+
+// CheckServer checks the connection status of a single server, retrying according
+// to policy, and returns the status with the attempts used and the duration of the
+// successful check. Status checks are shared by the TUI and 'sshm daemon'.
+//
+// The server's authentication chain is tried in order, starting with the method
+// that last succeeded; the outcome is available from LastAuthResult, the
+// address the check reached from LastAddress and the server's version and
+// banner from LastProbe.
+func CheckServer(server config.Server, policyConfig config.RetryPolicy) (string, int, time.Duration) {
+	// Create SSH client configuration
+	clientConfig := sshmssh.ClientConfig{
+		Hostname:  server.GetEffectiveHostname(),
+		Port:      server.Port,
+		Username:  server.Username,
+		Timeout:   CheckTimeout,
+		Multiplex: server.ControlMaster,
+	}
+	// The server and its jump hosts are verified against known_hosts before
+	// any credentials are offered
+	if strings.TrimSpace(server.ResolveTo) != "" {
+		clientConfig.HostKeyAlias = server.Hostname
+	}
+	if path, err := hostkey.DefaultKnownHostsPath(); err == nil {
+		clientConfig.KnownHostsFile = path
+	}
+
+	steps, err := orderedAuthSteps(server)
+	if err != nil {
+		recordAuthResult(server.Name, AuthResult{Err: err})
+		return "auth error", 1, 0
+	}
+
+	// Servers behind jump hosts are checked through them, like their sessions
+	clientConfig.Jumps, err = JumpHops(server)
+	if err != nil {
+		recordAuthResult(server.Name, AuthResult{Err: err})
+		return "auth error", 1, 0
+	}
+
+	policy, err := retry.FromConfig(policyConfig)
+	if err != nil {
+		policy, _ = retry.FromConfig(config.RetryPolicy{})
+	}
+
+	result := AuthResult{}
+	totalAttempts := 0
+	for _, step := range steps {
+		auth, err := authMethodForStep(server, step)
+		if err != nil {
+			result.Attempts = append(result.Attempts, AuthAttempt{Step: step, Err: err})
+			continue
+		}
+
+		// Test the connection, timing each attempt so the successful one reports latency
+		var latency time.Duration
+		var probe sshmssh.ProbeResult
+		attempts, err := retry.Do(context.Background(), policy, sshmssh.ClassifyError, func() error {
+			start := time.Now()
+			var err error
+			probe, err = sshmssh.ProbeConnectionDetails(clientConfig, auth)
+			latency = time.Since(start)
+			recordAddress(server.Name, probe.Address)
+			return err
+		})
+		totalAttempts += attempts
+		if err == nil {
+			// Connection successful
+			result.Succeeded = &step
+			recordAuthResult(server.Name, result)
+			recordProbe(server.Name, probe)
+			return "online", totalAttempts, latency
+		}
+
+		status := sshmssh.ClassifyError(err)
+		if status != sshmssh.StatusAuthFailed {
+			// Other methods can't help when the host itself can't be reached, and
+			// the last result still tells which method works
+			return status, totalAttempts, 0
+		}
+		result.Attempts = append(result.Attempts, AuthAttempt{Step: step, Err: err})
+	}
+
+	result.Err = result.chainError()
+	recordAuthResult(server.Name, result)
+	if totalAttempts == 0 {
+		// No method could even be prepared
+		return "auth error", 1, 0
+	}
+	return sshmssh.StatusAuthFailed, totalAttempts, 0
+}
+
+// AuthMethod creates a non-interactive SSH authentication method for the server
+// from the first method of its authentication chain that can be prepared,
+// preferring the one that last succeeded
+func AuthMethod(server config.Server) (ssh.AuthMethod, error) {
+	steps, err := orderedAuthSteps(server)
+	if err != nil {
+		return nil, err
+	}
+
+	var attempts []AuthAttempt
+	for _, step := range steps {
+		auth, err := authMethodForStep(server, step)
+		if err == nil {
+			return auth, nil
+		}
+		attempts = append(attempts, AuthAttempt{Step: step, Err: err})
+	}
+	return nil, AuthResult{Attempts: attempts}.chainError()
+}
+
+// vaultPassword reads a server's password from the keyring, falling back to a
+// plaintext password in the configuration. It is a variable so tests can avoid
+// the real keyring.
+var vaultPassword = func(server config.Server) (string, error) {
+	// Password servers reference their password's ID; others may keep one
+	// under the default ID for a password step of their chain
+	id := secrets.DefaultID(server.Name, secrets.Password)
+	if server.AuthType == "password" && secrets.ID(server) != "" {
+		id = secrets.ID(server)
+	}
+	store, err := secrets.Open()
+	if err == nil {
+		if password, err := store.Get(id); err == nil {
+			return password, nil
+		}
+	}
+	if server.Password != "" {
+		return server.Password, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("password vault unavailable: %w", err)
+	}
+	return "", fmt.Errorf("no password stored for %s", server.Name)
+}
+
+// authMethodForStep prepares one method of the chain. Status checks can't prompt,
+// so a passphrase-protected key without its passphrase in the keyring fails
+// here and the chain moves on.
+func authMethodForStep(server config.Server, step config.AuthStep) (ssh.AuthMethod, error) {
+	switch step.Method {
+	case config.AuthStepAgent:
+		return sshmssh.NewAgentAuth()
+	case config.AuthStepKey:
+		if server.PassphraseProtected && step.KeyPath == server.KeyPath {
+			passphrase, err := secrets.Lookup(server)
+			if err != nil {
+				return nil, fmt.Errorf("the passphrase of %s isn't in the keyring (see 'sshm keyring set'): %w", step.KeyPath, err)
+			}
+			return sshmssh.NewKeyAuth(step.KeyPath, passphrase)
+		}
+		return sshmssh.NewKeyAuth(step.KeyPath, "")
+	case config.AuthStepPasswordVault:
+		password, err := vaultPassword(server)
+		if err != nil {
+			return nil, err
+		}
+		return sshmssh.NewPasswordAuth(password), nil
+	case config.AuthStepProvider:
+		return sshmssh.ProviderAuthMethod(step.Provider, ProviderRequest(server))
+	default:
+		return nil, fmt.Errorf("unknown auth method '%s'", step.Method)
+	}
+}
+
+// ProviderRequest describes the server to its auth provider
+func ProviderRequest(server config.Server) sshmssh.AuthRequest {
+	return sshmssh.AuthRequest{
+		ServerName: server.Name,
+		Hostname:   server.GetEffectiveHostname(),
+		Port:       server.Port,
+		Username:   server.Username,
+		Options:    server.AuthProviderOptions,
+	}
+}
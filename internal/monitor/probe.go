@@ -0,0 +1,34 @@
+package monitor
+
+import (
+	"sync"
+	"time"
+
+	sshmssh "sshm/internal/ssh"
+)
+
+// Probe is what the last successful status check learned about a server
+type Probe struct {
+	sshmssh.ProbeResult
+	At time.Time
+}
+
+var (
+	lastProbes   = make(map[string]Probe)
+	lastProbesMu sync.RWMutex
+)
+
+// LastProbe returns the server's version and banner as seen by its last
+// successful status check, and when that was
+func LastProbe(serverName string) (Probe, bool) {
+	lastProbesMu.RLock()
+	defer lastProbesMu.RUnlock()
+	probe, ok := lastProbes[serverName]
+	return probe, ok
+}
+
+func recordProbe(serverName string, result sshmssh.ProbeResult) {
+	lastProbesMu.Lock()
+	defer lastProbesMu.Unlock()
+	lastProbes[serverName] = Probe{ProbeResult: result, At: time.Now()}
+}
@@ -0,0 +1,50 @@
+package monitor
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"strings"
+
+	"sshm/internal/config"
+	sshmssh "sshm/internal/ssh"
+)
+
+// JumpHops prepares the server's jump hosts for a connection test. Hops that
+// are sshm servers authenticate like their own status checks; other hops use
+// the agent or the default keys, and the local user unless the hop names one,
+// as ssh -J does.
+func JumpHops(server config.Server) ([]sshmssh.JumpHop, error) {
+	var hops []sshmssh.JumpHop
+	for _, hop := range server.JumpHosts() {
+		auth, err := AuthMethod(hop)
+		if err != nil {
+			return nil, fmt.Errorf("jump host %s: %w", hop.Name, err)
+		}
+		jump := sshmssh.JumpHop{
+			Hostname: hop.GetEffectiveHostname(),
+			Port:     hop.Port,
+			Username: hop.Username,
+			Auth:     auth,
+		}
+		if strings.TrimSpace(hop.ResolveTo) != "" {
+			jump.HostKeyAlias = hop.Hostname
+		}
+		if jump.Port == 0 {
+			jump.Port = 22
+		}
+		if jump.Username == "" {
+			jump.Username = localUsername()
+		}
+		hops = append(hops, jump)
+	}
+	return hops, nil
+}
+
+// localUsername is the user ssh logs in as when none is given
+func localUsername() string {
+	if current, err := user.Current(); err == nil {
+		return current.Username
+	}
+	return os.Getenv("USER")
+}
@@ -0,0 +1,135 @@
+package monitor
+
+import (
+	"context"
+	"math/rand"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"sshm/internal/config"
+	sshmssh "sshm/internal/ssh"
+)
+
+// CheckPlan describes how often and how a server is checked
+type CheckPlan struct {
+	Period  time.Duration // Average time between checks
+	Jitter  float64       // Random spread of each period as a fraction (0-1)
+	Stealth bool          // TCP connect only; the first check falls anywhere in the period
+}
+
+// stealthJitter spreads stealth checks between half and one and a half windows
+const stealthJitter = 0.5
+
+// PlanFor returns the check plan of a server. Servers in stealth profiles are
+// checked once per stealth window; the others every interval.
+func PlanFor(cfg *config.Config, server config.Server, interval time.Duration) CheckPlan {
+	if window, ok := cfg.StealthWindowFor(server.Name); ok {
+		return CheckPlan{Period: window, Jitter: stealthJitter, Stealth: true}
+	}
+	return CheckPlan{Period: interval, Jitter: cfg.StatusChecks.JitterFraction()}
+}
+
+// Schedule gives every server its own randomized due time, so checks trickle out
+// instead of going out as synchronized sweeps that intrusion detection systems
+// report as scanning
+type Schedule struct {
+	mu     sync.Mutex
+	next   map[string]time.Time
+	random func() float64 // Variable to allow deterministic tests
+}
+
+// NewSchedule creates an empty schedule
+func NewSchedule() *Schedule {
+	return &Schedule{next: make(map[string]time.Time), random: rand.Float64}
+}
+
+// Due reports whether the server's check is due at now and, if so, schedules the
+// next one. A server seen for the first time is scheduled at a random offset:
+// within its jitter for ordinary servers, anywhere in the period for stealth ones.
+func (s *Schedule) Due(name string, plan CheckPlan, now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	next, ok := s.next[name]
+	if !ok {
+		spread := float64(plan.Period) * plan.Jitter
+		if plan.Stealth {
+			spread = float64(plan.Period)
+		}
+		next = now.Add(time.Duration(s.random() * spread))
+		s.next[name] = next
+	}
+	if now.Before(next) {
+		return false
+	}
+
+	delay := float64(plan.Period) * (1 + plan.Jitter*(2*s.random()-1))
+	s.next[name] = now.Add(time.Duration(delay))
+	return true
+}
+
+// Scheduled reports whether a first check of the server has been scheduled
+func (s *Schedule) Scheduled(name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.next[name]
+	return ok
+}
+
+// RateLimiter spaces out the start of checks to at most a number per minute
+type RateLimiter struct {
+	mu      sync.Mutex
+	spacing time.Duration
+	next    time.Time
+}
+
+// NewRateLimiter allows perMinute checks a minute; nil, meaning unlimited, when
+// perMinute is not positive
+func NewRateLimiter(perMinute int) *RateLimiter {
+	if perMinute <= 0 {
+		return nil
+	}
+	return &RateLimiter{spacing: time.Minute / time.Duration(perMinute)}
+}
+
+// Wait blocks until the next check may start or ctx is done. A nil limiter never waits.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	if l == nil {
+		return ctx.Err()
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	start := l.next
+	if start.Before(now) {
+		start = now
+	}
+	l.next = start.Add(l.spacing)
+	l.mu.Unlock()
+
+	timer := time.NewTimer(time.Until(start))
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// CheckTCP only opens and closes a TCP connection to the server's SSH port. No
+// handshake or login takes place, so nothing shows up in the server's auth logs.
+func CheckTCP(server config.Server) (string, time.Duration) {
+	address := net.JoinHostPort(server.GetEffectiveHostname(), strconv.Itoa(server.Port))
+	start := time.Now()
+	conn, err := net.DialTimeout("tcp", address, CheckTimeout)
+	if err != nil {
+		return sshmssh.ClassifyError(err), 0
+	}
+	latency := time.Since(start)
+	recordAddress(server.Name, remoteIP(conn))
+	conn.Close()
+	return "online", latency
+}
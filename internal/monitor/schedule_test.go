@@ -0,0 +1,111 @@
+package monitor
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"sshm/internal/config"
+)
+
+func TestPlanFor(t *testing.T) {
+	cfg := &config.Config{
+		Profiles: []config.Profile{
+			{Name: "dmz", Servers: []string{"edge"}, Stealth: true, StealthWindow: "1h"},
+			{Name: "prod", Servers: []string{"edge", "web"}},
+		},
+		StatusChecks: config.StatusCheckConfig{Jitter: 0.5},
+	}
+
+	plan := PlanFor(cfg, config.Server{Name: "edge"}, 30*time.Second)
+	if !plan.Stealth || plan.Period != time.Hour {
+		t.Errorf("Expected a stealth plan over the profile window, got %+v", plan)
+	}
+	plan = PlanFor(cfg, config.Server{Name: "web"}, 30*time.Second)
+	if plan.Stealth || plan.Period != 30*time.Second || plan.Jitter != 0.5 {
+		t.Errorf("Expected the configured interval and jitter, got %+v", plan)
+	}
+}
+
+func TestScheduleDue(t *testing.T) {
+	s := NewSchedule()
+	s.random = func() float64 { return 1 }
+	now := time.Unix(1700000000, 0)
+	plan := CheckPlan{Period: 30 * time.Second, Jitter: 0.2}
+
+	// First check at the end of the jitter window
+	if s.Due("web", plan, now) {
+		t.Error("Expected the first check to be offset by the jitter")
+	}
+	if !s.Scheduled("web") || s.Scheduled("db") {
+		t.Error("Expected only web to be scheduled")
+	}
+	if !s.Due("web", plan, now.Add(6*time.Second)) {
+		t.Fatal("Expected the first check to be due after the offset")
+	}
+	// Next check one period plus the full jitter later
+	if s.Due("web", plan, now.Add(41*time.Second)) {
+		t.Error("Expected the next check to wait for the jittered period")
+	}
+	if !s.Due("web", plan, now.Add(42*time.Second)) {
+		t.Error("Expected the next check to be due after the jittered period")
+	}
+
+	// Stealth servers are first checked anywhere in the window
+	stealth := CheckPlan{Period: time.Hour, Jitter: 0.5, Stealth: true}
+	if s.Due("edge", stealth, now) || s.Due("edge", stealth, now.Add(59*time.Minute)) {
+		t.Error("Expected the stealth check to be spread over the window")
+	}
+	if !s.Due("edge", stealth, now.Add(time.Hour)) {
+		t.Error("Expected the stealth check to be due at the end of the window")
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	if NewRateLimiter(0) != nil {
+		t.Error("Expected no limiter without a limit")
+	}
+	var unlimited *RateLimiter
+	if err := unlimited.Wait(context.Background()); err != nil {
+		t.Errorf("Expected a nil limiter not to wait, got %v", err)
+	}
+
+	limiter := NewRateLimiter(600) // One check every 100ms
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		limiter.Wait(context.Background())
+	}
+	if elapsed := time.Since(start); elapsed < 200*time.Millisecond {
+		t.Errorf("Expected checks to be spaced out, 3 took %v", elapsed)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	limiter = NewRateLimiter(1)
+	limiter.Wait(ctx)
+	if err := limiter.Wait(ctx); err == nil {
+		t.Error("Expected a cancelled wait to fail")
+	}
+}
+
+func TestCheckTCP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("TCP not available: %v", err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+
+	server := config.Server{Name: "local", Hostname: "127.0.0.1", Port: port}
+	if status, _ := CheckTCP(server); status != "online" {
+		t.Errorf("Expected a listening port to be online, got %s", status)
+	}
+	if address, _ := LastAddress("local"); address != "127.0.0.1" {
+		t.Errorf("Expected the check to record 127.0.0.1, got %q", address)
+	}
+
+	listener.Close()
+	if status, _ := CheckTCP(server); status != "refused" {
+		t.Errorf("Expected a closed port to be refused, got %s", status)
+	}
+}
@@ -0,0 +1,246 @@
+// Package portcheck probes TCP ports on a server, directly or from another
+// server, to tell a host that is down from one where only ssh is blocked.
+package portcheck
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"sshm/internal/config"
+	"sshm/internal/power"
+	"sshm/internal/shellquote"
+)
+
+// Port states
+const (
+	StateOpen     = "open"     // Something accepted the connection
+	StateClosed   = "closed"   // The host refused the connection, so it is up
+	StateFiltered = "filtered" // No answer within the timeout: dropped by a firewall or the host is down
+)
+
+// Result is the state of one probed port
+type Result struct {
+	Port      int           `json:"port"`
+	State     string        `json:"state"`
+	Latency   time.Duration `json:"-"`                    // Time to connect, open ports only
+	LatencyMS float64       `json:"latency_ms,omitempty"` // Latency in milliseconds, for JSON output
+	Detail    string        `json:"detail,omitempty"`     // Why a port wasn't open, when known
+}
+
+// Report is the outcome of probing a server's ports
+type Report struct {
+	Server  string   `json:"server"`
+	Host    string   `json:"host"`
+	Via     string   `json:"via,omitempty"` // Server the probes ran from, empty when local
+	SSHPort int      `json:"ssh_port"`
+	Results []Result `json:"results"`
+	Verdict string   `json:"verdict"`
+}
+
+// dial connects to an address (variable to allow mocking in tests)
+var dial = func(ctx context.Context, address string) (net.Conn, error) {
+	var dialer net.Dialer
+	return dialer.DialContext(ctx, "tcp", address)
+}
+
+// sshCommand builds the ssh command running the probe on another server (variable to allow mocking in tests)
+var sshCommand = power.SSHCommand
+
+// Ports returns the ports to probe on a server: its ssh port first, then the
+// configured ports
+func Ports(server config.Server, configured []int) []int {
+	sshPort := server.Port
+	if sshPort == 0 {
+		sshPort = 22
+	}
+	ports := []int{sshPort}
+	for _, port := range configured {
+		if port != sshPort {
+			ports = append(ports, port)
+		}
+	}
+	return ports
+}
+
+// Run probes the server's ssh port and the configured ports, from the local
+// machine or, when via is set, from that server
+func Run(ctx context.Context, server config.Server, via *config.Server, configured []int, timeout time.Duration) (Report, error) {
+	ports := Ports(server, configured)
+	report := Report{Server: server.Name, Host: server.GetEffectiveHostname(), SSHPort: ports[0]}
+
+	if via != nil {
+		report.Via = via.Name
+		results, err := CheckVia(ctx, *via, report.Host, ports, timeout)
+		if err != nil {
+			return report, err
+		}
+		report.Results = results
+	} else {
+		report.Results = Check(ctx, report.Host, ports, timeout)
+	}
+	report.Verdict = Verdict(report.SSHPort, report.Results)
+	return report, nil
+}
+
+// Check probes the ports on host from the local machine, in parallel
+func Check(ctx context.Context, host string, ports []int, timeout time.Duration) []Result {
+	results := make([]Result, len(ports))
+	var wg sync.WaitGroup
+	for i, port := range ports {
+		wg.Add(1)
+		go func(i, port int) {
+			defer wg.Done()
+			results[i] = probe(ctx, host, port, timeout)
+		}(i, port)
+	}
+	wg.Wait()
+	return results
+}
+
+func probe(ctx context.Context, host string, port int, timeout time.Duration) Result {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	conn, err := dial(ctx, net.JoinHostPort(host, strconv.Itoa(port)))
+	if err == nil {
+		conn.Close()
+		latency := time.Since(start)
+		return Result{Port: port, State: StateOpen, Latency: latency, LatencyMS: float64(latency.Microseconds()) / 1000}
+	}
+	return Result{Port: port, State: classify(err), Detail: describe(err)}
+}
+
+// classify maps a dial error to closed (refused) or filtered (no answer)
+func classify(err error) string {
+	if errors.Is(err, syscall.ECONNREFUSED) {
+		return StateClosed
+	}
+	return StateFiltered
+}
+
+// describe shortens a dial error to its cause
+func describe(err error) string {
+	var netErr net.Error
+	switch {
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return "connection refused"
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, os.ErrDeadlineExceeded),
+		errors.As(err, &netErr) && netErr.Timeout():
+		return "no answer"
+	case errors.Is(err, syscall.EHOSTUNREACH), errors.Is(err, syscall.ENETUNREACH):
+		return "unreachable"
+	}
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return "host name not found"
+	}
+	return err.Error()
+}
+
+// CheckVia probes the ports on host from the via server, e.g. a jump host
+// inside the same network, using bash's /dev/tcp and timeout there
+func CheckVia(ctx context.Context, via config.Server, host string, ports []int, timeout time.Duration) ([]Result, error) {
+	cmd, err := sshCommand(via, viaCommand(host, ports, timeout))
+	if err != nil {
+		return nil, err
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	stop := make(chan struct{})
+	defer close(stop)
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start ssh for %s: %w", via.Name, err)
+	}
+	go func() {
+		select {
+		case <-ctx.Done():
+			cmd.Process.Kill()
+		case <-stop:
+		}
+	}()
+	if err := cmd.Wait(); err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return nil, fmt.Errorf("probe from %s failed: %s", via.Name, message)
+		}
+		return nil, fmt.Errorf("probe from %s failed: %w", via.Name, err)
+	}
+	return parseViaOutput(stdout.String(), ports)
+}
+
+// viaCommand probes every port in parallel and prints "<port> <state>" lines;
+// timeout exits with 124 when the connection hangs
+func viaCommand(host string, ports []int, timeout time.Duration) string {
+	seconds := int(timeout.Round(time.Second) / time.Second)
+	if seconds < 1 {
+		seconds = 1
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "h=%s; ", shellquote.Quote(host))
+	for _, port := range ports {
+		fmt.Fprintf(&b, `{ if timeout %d bash -c "exec 3<>/dev/tcp/\$1/%d" _ "$h" 2>/dev/null; then echo "%d open"; `+
+			`elif [ $? -eq 124 ]; then echo "%d filtered"; else echo "%d closed"; fi; } & `, seconds, port, port, port, port)
+	}
+	b.WriteString("wait")
+	return b.String()
+}
+
+// parseViaOutput reads the states printed by viaCommand, in the order of ports
+func parseViaOutput(output string, ports []int) ([]Result, error) {
+	states := make(map[int]string)
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		if port, err := strconv.Atoi(fields[0]); err == nil {
+			states[port] = fields[1]
+		}
+	}
+
+	results := make([]Result, 0, len(ports))
+	for _, port := range ports {
+		state, ok := states[port]
+		if !ok {
+			return nil, fmt.Errorf("no result for port %d (bash and timeout are required on the probing server)", port)
+		}
+		results = append(results, Result{Port: port, State: state})
+	}
+	return results, nil
+}
+
+// Verdict explains the results in terms of the ssh port
+func Verdict(sshPort int, results []Result) string {
+	counts := make(map[string]int)
+	sshState := ""
+	for _, result := range results {
+		counts[result.State]++
+		if result.Port == sshPort {
+			sshState = result.State
+		}
+	}
+
+	switch {
+	case sshState == StateOpen:
+		return fmt.Sprintf("SSH port %d is open: the host is up and accepts SSH connections", sshPort)
+	case counts[StateOpen] > 0:
+		return fmt.Sprintf("The host is up, but SSH port %d is %s: sshd isn't listening or SSH is blocked", sshPort, sshState)
+	case sshState == StateClosed:
+		return fmt.Sprintf("The host is up (it refuses connections), but nothing listens on SSH port %d", sshPort)
+	case counts[StateClosed] > 0:
+		return fmt.Sprintf("The host is up (it refuses some ports), but SSH port %d is filtered by a firewall", sshPort)
+	default:
+		return "No port answered: the host is down, unreachable from here or fully firewalled"
+	}
+}
@@ -0,0 +1,126 @@
+package portcheck
+
+import (
+	"context"
+	"net"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"sshm/internal/config"
+)
+
+// listeningPorts returns a port accepting connections and one refusing them
+func listeningPorts(t *testing.T) (open, closed int) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	refused, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	closed = refused.Addr().(*net.TCPAddr).Port
+	refused.Close()
+	return listener.Addr().(*net.TCPAddr).Port, closed
+}
+
+func TestPorts(t *testing.T) {
+	ports := Ports(config.Server{Port: 2222}, []int{22, 2222, 443})
+	if len(ports) != 3 || ports[0] != 2222 || ports[1] != 22 || ports[2] != 443 {
+		t.Errorf("Expected the ssh port first without duplicates, got %v", ports)
+	}
+	if ports := Ports(config.Server{}, []int{80}); ports[0] != 22 {
+		t.Errorf("Expected port 22 by default, got %v", ports)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	open, closed := listeningPorts(t)
+
+	results := Check(context.Background(), "127.0.0.1", []int{open, closed}, time.Second)
+	if results[0].State != StateOpen || results[0].Latency <= 0 {
+		t.Errorf("Expected port %d to be open, got %+v", open, results[0])
+	}
+	if results[1].State != StateClosed || results[1].Detail != "connection refused" {
+		t.Errorf("Expected port %d to be closed, got %+v", closed, results[1])
+	}
+}
+
+func TestCheckFiltered(t *testing.T) {
+	original := dial
+	dial = func(ctx context.Context, address string) (net.Conn, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	t.Cleanup(func() { dial = original })
+
+	results := Check(context.Background(), "10.0.0.1", []int{22}, 20*time.Millisecond)
+	if results[0].State != StateFiltered || results[0].Detail != "no answer" {
+		t.Errorf("Expected a port without answer to be filtered, got %+v", results[0])
+	}
+}
+
+func TestCheckVia(t *testing.T) {
+	original := sshCommand
+	sshCommand = func(server config.Server, command string) (*exec.Cmd, error) {
+		return exec.Command("sh", "-c", command), nil
+	}
+	t.Cleanup(func() { sshCommand = original })
+	open, closed := listeningPorts(t)
+
+	results, err := CheckVia(context.Background(), config.Server{Name: "bastion"}, "127.0.0.1", []int{open, closed}, time.Second)
+	if err != nil {
+		t.Fatalf("CheckVia failed: %v", err)
+	}
+	if results[0].Port != open || results[0].State != StateOpen {
+		t.Errorf("Expected port %d to be open, got %+v", open, results[0])
+	}
+	if results[1].Port != closed || results[1].State != StateClosed {
+		t.Errorf("Expected port %d to be closed, got %+v", closed, results[1])
+	}
+}
+
+func TestParseViaOutputMissingPort(t *testing.T) {
+	if _, err := parseViaOutput("22 open\n", []int{22, 80}); err == nil {
+		t.Error("Expected a missing port to be an error")
+	}
+}
+
+func TestVerdict(t *testing.T) {
+	tests := []struct {
+		results  []Result
+		expected string
+	}{
+		{[]Result{{Port: 22, State: StateOpen}}, "accepts SSH"},
+		{[]Result{{Port: 22, State: StateFiltered}, {Port: 443, State: StateOpen}}, "SSH is blocked"},
+		{[]Result{{Port: 22, State: StateClosed}, {Port: 443, State: StateFiltered}}, "nothing listens"},
+		{[]Result{{Port: 22, State: StateFiltered}, {Port: 80, State: StateClosed}}, "filtered by a firewall"},
+		{[]Result{{Port: 22, State: StateFiltered}, {Port: 80, State: StateFiltered}}, "host is down"},
+	}
+	for _, test := range tests {
+		if verdict := Verdict(22, test.results); !strings.Contains(verdict, test.expected) {
+			t.Errorf("Expected %q in the verdict for %+v, got %q", test.expected, test.results, verdict)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	open, closed := listeningPorts(t)
+	server := config.Server{Name: "web1", Hostname: "127.0.0.1", Port: closed}
+
+	report, err := Run(context.Background(), server, nil, []int{open}, time.Second)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if report.SSHPort != closed || len(report.Results) != 2 || report.Via != "" {
+		t.Fatalf("Unexpected report: %+v", report)
+	}
+	if !strings.Contains(report.Verdict, "SSH is blocked") {
+		t.Errorf("Expected the verdict to point at ssh, got %q", report.Verdict)
+	}
+}
@@ -0,0 +1,175 @@
+package power
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"sshm/internal/config"
+	"sshm/internal/secrets"
+)
+
+// Power actions
+const (
+	ActionReboot   = "reboot"
+	ActionShutdown = "shutdown"
+	ActionCustom   = "custom"
+)
+
+// Actions lists the supported power actions
+var Actions = []string{ActionReboot, ActionShutdown, ActionCustom}
+
+// Reboot and shutdown check sudo first, then detach so the ssh session
+// returns cleanly before the host goes down
+const (
+	rebootCommand   = "sudo -n true && { nohup sh -c 'sleep 1; sudo -n shutdown -r now' >/dev/null 2>&1 & }"
+	shutdownCommand = "sudo -n true && { nohup sh -c 'sleep 1; sudo -n shutdown -h now' >/dev/null 2>&1 & }"
+)
+
+// Default timing used while waiting for a rebooted host to return
+const (
+	DefaultWaitTimeout  = 5 * time.Minute
+	DefaultWaitInterval = 3 * time.Second
+)
+
+// execCommand is a variable to allow mocking in tests
+var execCommand = exec.CommandContext
+
+// dialServer checks whether a server's SSH port accepts connections (variable to allow mocking in tests)
+var dialServer = func(server config.Server) error {
+	port := server.Port
+	if port == 0 {
+		port = 22
+	}
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort(server.GetEffectiveHostname(), fmt.Sprintf("%d", port)), 3*time.Second)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// Command returns the remote command for an action; custom actions run the given command
+func Command(action, custom string) (string, error) {
+	switch action {
+	case ActionReboot:
+		return rebootCommand, nil
+	case ActionShutdown:
+		return shutdownCommand, nil
+	case ActionCustom:
+		if strings.TrimSpace(custom) == "" {
+			return "", fmt.Errorf("a command is required for custom actions")
+		}
+		return custom, nil
+	default:
+		return "", fmt.Errorf("unknown action '%s' (expected one of: %s)", action, strings.Join(Actions, ", "))
+	}
+}
+
+// WatchAfter reports whether the host should be watched until it returns after the action
+func WatchAfter(action string) bool {
+	return action == ActionReboot
+}
+
+// RequiresTypedConfirmation reports whether any of the servers is protected,
+// in which case the user must type the target name to confirm
+func RequiresTypedConfirmation(servers []config.Server) bool {
+	for _, server := range servers {
+		if server.Protected {
+			return true
+		}
+	}
+	return false
+}
+
+// ConfirmationMatches checks a typed confirmation against the expected target name
+func ConfirmationMatches(expected, typed string) bool {
+	return strings.TrimSpace(typed) == expected
+}
+
+// Run executes a command on the server over ssh and returns its combined output
+func Run(server config.Server, command string) (string, error) {
+	return RunContext(context.Background(), server, command)
+}
+
+// RunContext is Run, killing ssh when ctx is cancelled
+func RunContext(ctx context.Context, server config.Server, command string) (string, error) {
+	cmd, err := buildCommand(ctx, server, command)
+	if err != nil {
+		return "", err
+	}
+
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	// ssh started by sshpass may hold the output open after sshpass is killed
+	cmd.WaitDelay = time.Second
+	if err := cmd.Run(); err != nil {
+		return strings.TrimSpace(output.String()), fmt.Errorf("command failed on %s: %w", server.Name, err)
+	}
+	return strings.TrimSpace(output.String()), nil
+}
+
+// WaitForReturn waits until the server stops accepting connections and then
+// accepts them again, or the timeout elapses
+func WaitForReturn(server config.Server, timeout, interval time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	wentDown := false
+
+	for time.Now().Before(deadline) {
+		reachable := dialServer(server) == nil
+		if !reachable {
+			wentDown = true
+		} else if wentDown {
+			return nil
+		}
+		time.Sleep(interval)
+	}
+
+	if !wentDown {
+		return fmt.Errorf("%s did not go down within %v", server.Name, timeout)
+	}
+	return fmt.Errorf("%s did not come back within %v", server.Name, timeout)
+}
+
+// SSHCommand returns the ssh command running command on the server without a
+// terminal, for callers that feed or read its standard streams themselves
+func SSHCommand(server config.Server, command string) (*exec.Cmd, error) {
+	return buildCommand(context.Background(), server, command)
+}
+
+// buildCommand builds a non-interactive ssh command running the remote command.
+// Restricted servers are refused since they may only run their forced command.
+func buildCommand(ctx context.Context, server config.Server, command string) (*exec.Cmd, error) {
+	if server.IsRestricted() {
+		return nil, fmt.Errorf("%s is restricted to its forced command", server.Name)
+	}
+	args := []string{"-o", "ConnectTimeout=10"}
+	if server.Port != 0 && server.Port != 22 {
+		args = append(args, "-p", fmt.Sprintf("%d", server.Port))
+	}
+	if server.AuthType == "key" && server.KeyPath != "" {
+		args = append(args, "-i", server.KeyPath)
+	}
+	args = append(args, strings.Fields(server.GetSSHOptions())...)
+	destination := fmt.Sprintf("%s@%s", server.Username, server.GetEffectiveHostname())
+
+	// Actions run without a terminal, so passwords and key passphrases can only
+	// come from the keyring
+	if secrets.Referenced(server) {
+		flags, secret, err := secrets.SSHPass(server)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve secret from keyring: %w", err)
+		}
+		cmd := execCommand(ctx, "sshpass", append(append(flags, "-e", "ssh"), append(args, destination, command)...)...)
+		cmd.Env = append(os.Environ(), "SSHPASS="+secret)
+		return cmd, nil
+	}
+
+	args = append(args, "-o", "BatchMode=yes", destination, command)
+	return execCommand(ctx, "ssh", args...), nil
+}
@@ -0,0 +1,160 @@
+package power
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"sshm/internal/config"
+)
+
+func mockExec(t *testing.T, captured *[]string, name string, args ...string) {
+	original := execCommand
+	execCommand = func(ctx context.Context, cmdName string, arg ...string) *exec.Cmd {
+		*captured = append([]string{cmdName}, arg...)
+		return exec.CommandContext(ctx, name, args...)
+	}
+	t.Cleanup(func() { execCommand = original })
+}
+
+func TestCommand(t *testing.T) {
+	if command, err := Command(ActionReboot, ""); err != nil || !strings.Contains(command, "shutdown -r now") {
+		t.Errorf("Unexpected reboot command %q (%v)", command, err)
+	}
+	if command, err := Command(ActionShutdown, ""); err != nil || !strings.Contains(command, "shutdown -h now") {
+		t.Errorf("Unexpected shutdown command %q (%v)", command, err)
+	}
+	if command, err := Command(ActionCustom, "systemctl restart nginx"); err != nil || command != "systemctl restart nginx" {
+		t.Errorf("Unexpected custom command %q (%v)", command, err)
+	}
+	if _, err := Command(ActionCustom, "  "); err == nil {
+		t.Error("Expected error for empty custom command")
+	}
+	if _, err := Command("hibernate", ""); err == nil {
+		t.Error("Expected error for unknown action")
+	}
+}
+
+func TestRequiresTypedConfirmation(t *testing.T) {
+	servers := []config.Server{{Name: "web1"}, {Name: "db1"}}
+	if RequiresTypedConfirmation(servers) {
+		t.Error("Unprotected servers should not require typed confirmation")
+	}
+
+	servers[1].Protected = true
+	if !RequiresTypedConfirmation(servers) {
+		t.Error("Expected typed confirmation when any server is protected")
+	}
+
+	if !ConfirmationMatches("db1", " db1 ") || ConfirmationMatches("db1", "db") {
+		t.Error("Unexpected confirmation matching")
+	}
+}
+
+func TestRunBuildsSSHCommand(t *testing.T) {
+	var captured []string
+	mockExec(t, &captured, "echo", "done")
+
+	server := config.Server{Name: "web1", Hostname: "web1.example.com", Port: 2222, Username: "admin", AuthType: "key", KeyPath: "/keys/id"}
+	output, err := Run(server, "uptime")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if output != "done" {
+		t.Errorf("Expected command output, got %q", output)
+	}
+
+	command := strings.Join(captured, " ")
+	for _, want := range []string{"ssh ", "-p 2222", "-i /keys/id", "BatchMode=yes", "admin@web1.example.com uptime"} {
+		if !strings.Contains(command, want) {
+			t.Errorf("Expected %q in command: %s", want, command)
+		}
+	}
+}
+
+func TestRunReportsFailure(t *testing.T) {
+	var captured []string
+	mockExec(t, &captured, "false")
+
+	if _, err := Run(config.Server{Name: "web1", Hostname: "h", Username: "u"}, "uptime"); err == nil {
+		t.Error("Expected error when the remote command fails")
+	}
+}
+
+func TestRunRefusesRestrictedServer(t *testing.T) {
+	var captured []string
+	mockExec(t, &captured, "echo", "done")
+
+	server := config.Server{Name: "noc-gw", Hostname: "h", Username: "u", Restricted: &config.RestrictedAccess{Command: "menu"}}
+	if _, err := Run(server, "uptime"); err == nil {
+		t.Error("Expected restricted server to be refused")
+	}
+	if len(captured) != 0 {
+		t.Errorf("Expected no command to be run, got: %v", captured)
+	}
+}
+
+func TestWaitForReturn(t *testing.T) {
+	results := []error{nil, errors.New("down"), errors.New("down"), nil}
+	original := dialServer
+	dialServer = func(config.Server) error {
+		err := results[0]
+		if len(results) > 1 {
+			results = results[1:]
+		}
+		return err
+	}
+	t.Cleanup(func() { dialServer = original })
+
+	if err := WaitForReturn(config.Server{Name: "web1"}, time.Second, time.Millisecond); err != nil {
+		t.Errorf("Expected server to return, got: %v", err)
+	}
+}
+
+func TestTailCommand(t *testing.T) {
+	command, err := TailCommand("/var/log/it's here.log")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if command != `tail -n 200 -F -- '/var/log/it'\''s here.log'` {
+		t.Errorf("Unexpected tail command %q", command)
+	}
+	if _, err := TailCommand(" "); err == nil {
+		t.Error("Expected error for an empty path")
+	}
+}
+
+func TestStreamPassesLines(t *testing.T) {
+	var captured []string
+	mockExec(t, &captured, "printf", "one\ntwo\n")
+
+	server := config.Server{Name: "web1", Hostname: "web1.example.com", Username: "admin", AuthType: "key"}
+	var lines []string
+	if err := Stream(context.Background(), server, "tail -F /var/log/syslog", func(line string) {
+		lines = append(lines, line)
+	}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if strings.Join(lines, "|") != "one|two" {
+		t.Errorf("Unexpected lines %v", lines)
+	}
+}
+
+func TestStreamStopsOnCancel(t *testing.T) {
+	var captured []string
+	mockExec(t, &captured, "sleep", "10")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	server := config.Server{Name: "web1", Hostname: "web1.example.com", Username: "admin", AuthType: "key"}
+	start := time.Now()
+	if err := Stream(ctx, server, "tail -F /var/log/syslog", func(string) {}); err != nil {
+		t.Errorf("Expected cancelling not to be an error, got: %v", err)
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Error("Expected the command to be stopped when cancelled")
+	}
+}
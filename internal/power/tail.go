@@ -0,0 +1,80 @@
+package power
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"sshm/internal/config"
+	"sshm/internal/shellquote"
+)
+
+// TailLines is how many existing lines tail shows before following a file
+const TailLines = 200
+
+// TailCommand returns the remote command following a file, across rotations
+func TailCommand(path string) (string, error) {
+	if strings.TrimSpace(path) == "" {
+		return "", fmt.Errorf("a remote file path is required")
+	}
+	return fmt.Sprintf("tail -n %d -F -- %s", TailLines, shellquote.Quote(path)), nil
+}
+
+// Stream runs a command on the server over ssh and passes each line of its
+// combined output to onLine until the command exits or ctx is cancelled.
+// Cancelling is not an error.
+func Stream(ctx context.Context, server config.Server, command string, onLine func(string)) error {
+	cmd, err := buildCommand(context.Background(), server, command)
+	if err != nil {
+		return err
+	}
+	if err := StreamCommand(ctx, cmd, onLine); err != nil {
+		return fmt.Errorf("command failed on %s: %w", server.Name, err)
+	}
+	return nil
+}
+
+// StreamCommand runs cmd and passes each line of its combined output to onLine
+// until it exits or ctx is cancelled. Cancelling is not an error.
+func StreamCommand(ctx context.Context, cmd *exec.Cmd, onLine func(string)) error {
+	reader, writer := io.Pipe()
+	cmd.Stdout = writer
+	cmd.Stderr = writer
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start %s: %w", filepath.Base(cmd.Path), err)
+	}
+
+	waitErr := make(chan error, 1)
+	go func() {
+		err := cmd.Wait()
+		writer.Close()
+		waitErr <- err
+	}()
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-ctx.Done():
+			cmd.Process.Kill()
+		case <-stop:
+		}
+	}()
+
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		onLine(scanner.Text())
+	}
+	// Keep ssh from blocking on a full pipe after an overlong line
+	io.Copy(io.Discard, reader)
+
+	err := <-waitErr
+	if ctx.Err() != nil {
+		return nil
+	}
+	return err
+}
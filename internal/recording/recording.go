@@ -0,0 +1,365 @@
+// Package recording captures terminal output as asciinema v2 casts, lists the
+// casts in a directory and plays them back.
+package recording
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Extension is the file extension of recordings
+const Extension = ".cast"
+
+// Header is the first line of an asciinema v2 cast
+type Header struct {
+	Version   int               `json:"version"`
+	Width     int               `json:"width"`
+	Height    int               `json:"height"`
+	Timestamp int64             `json:"timestamp,omitempty"` // Unix time the recording started
+	Title     string            `json:"title,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+}
+
+// Event is a line of a cast: output ("o") or input ("i") at Time seconds
+// after the recording started
+type Event struct {
+	Time float64
+	Type string
+	Data string
+}
+
+// Cast is a loaded recording
+type Cast struct {
+	Header Header
+	Events []Event
+}
+
+// Duration returns the time of the last event
+func (c Cast) Duration() time.Duration {
+	if len(c.Events) == 0 {
+		return 0
+	}
+	return time.Duration(c.Events[len(c.Events)-1].Time * float64(time.Second))
+}
+
+// unsafeFileChars are replaced in the parts of recording file names
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// FileName returns the file a window of a session recorded from started is saved in
+func FileName(session, window string, started time.Time) string {
+	name := unsafeFileChars.ReplaceAllString(session, "_")
+	if window != "" && window != session {
+		name += "-" + unsafeFileChars.ReplaceAllString(window, "_")
+	}
+	return fmt.Sprintf("%s-%s%s", name, started.Format("20060102-150405"), Extension)
+}
+
+// Recorder writes output to a cast as it arrives. Events are written
+// unbuffered so a recording in progress can already be played.
+type Recorder struct {
+	w       io.Writer
+	started time.Time
+	now     func() time.Time
+	pending []byte // Start of a character split across writes
+}
+
+// NewRecorder writes the header and returns a recorder timing events from now.
+// A zero header version is set to 2 and a zero timestamp to the current time.
+func NewRecorder(w io.Writer, header Header) (*Recorder, error) {
+	return newRecorder(w, header, time.Now)
+}
+
+func newRecorder(w io.Writer, header Header, now func() time.Time) (*Recorder, error) {
+	started := now()
+	if header.Version == 0 {
+		header.Version = 2
+	}
+	if header.Timestamp == 0 {
+		header.Timestamp = started.Unix()
+	}
+	line, err := json.Marshal(header)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(append(line, '\n')); err != nil {
+		return nil, fmt.Errorf("failed to write recording header: %w", err)
+	}
+	return &Recorder{w: w, started: started, now: now}, nil
+}
+
+// Write records p as output. A character cut off at the end of p is held
+// back until the rest of it arrives, since events must be valid UTF-8.
+func (r *Recorder) Write(p []byte) (int, error) {
+	data := append(r.pending, p...)
+	cut := incompleteSuffix(data)
+	r.pending = append([]byte(nil), data[len(data)-cut:]...)
+	if err := r.writeEvent(data[:len(data)-cut]); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Close records output held back by Write
+func (r *Recorder) Close() error {
+	data := r.pending
+	r.pending = nil
+	return r.writeEvent(data)
+}
+
+func (r *Recorder) writeEvent(data []byte) error {
+	if len(data) == 0 {
+		return nil
+	}
+	text, err := json.Marshal(string(data))
+	if err != nil {
+		return err
+	}
+	elapsed := r.now().Sub(r.started).Seconds()
+	if _, err := fmt.Fprintf(r.w, "[%.6f, \"o\", %s]\n", elapsed, text); err != nil {
+		return fmt.Errorf("failed to write recording: %w", err)
+	}
+	return nil
+}
+
+// incompleteSuffix returns how many bytes at the end of data start a UTF-8
+// character whose remaining bytes are missing
+func incompleteSuffix(data []byte) int {
+	for n := 1; n < utf8.UTFMax && n <= len(data); n++ {
+		start := data[len(data)-n]
+		if !utf8.RuneStart(start) {
+			continue
+		}
+		if utf8.FullRune(data[len(data)-n:]) {
+			return 0
+		}
+		return n
+	}
+	return 0
+}
+
+// Read parses a cast
+func Read(r io.Reader) (Cast, error) {
+	reader := bufio.NewReader(r)
+	line, err := reader.ReadBytes('\n')
+	if err != nil && (err != io.EOF || len(line) == 0) {
+		return Cast{}, fmt.Errorf("failed to read recording header: %w", err)
+	}
+	var cast Cast
+	if err := json.Unmarshal(line, &cast.Header); err != nil {
+		return Cast{}, fmt.Errorf("invalid recording header: %w", err)
+	}
+	if cast.Header.Version != 2 {
+		return Cast{}, fmt.Errorf("unsupported recording version %d", cast.Header.Version)
+	}
+
+	for number := 2; ; number++ {
+		line, err := reader.ReadBytes('\n')
+		if trimmed := strings.TrimSpace(string(line)); trimmed != "" {
+			event, parseErr := parseEvent([]byte(trimmed))
+			if parseErr != nil {
+				// The last line of a recording cut off mid-write is dropped
+				if err == io.EOF {
+					break
+				}
+				return Cast{}, fmt.Errorf("invalid recording event on line %d: %w", number, parseErr)
+			}
+			cast.Events = append(cast.Events, event)
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return Cast{}, fmt.Errorf("failed to read recording: %w", err)
+		}
+	}
+	return cast, nil
+}
+
+func parseEvent(line []byte) (Event, error) {
+	var fields []json.RawMessage
+	if err := json.Unmarshal(line, &fields); err != nil {
+		return Event{}, err
+	}
+	if len(fields) != 3 {
+		return Event{}, fmt.Errorf("expected 3 fields, got %d", len(fields))
+	}
+	var event Event
+	if err := json.Unmarshal(fields[0], &event.Time); err != nil {
+		return Event{}, err
+	}
+	if err := json.Unmarshal(fields[1], &event.Type); err != nil {
+		return Event{}, err
+	}
+	if err := json.Unmarshal(fields[2], &event.Data); err != nil {
+		return Event{}, err
+	}
+	return event, nil
+}
+
+// Load reads the cast at path
+func Load(path string) (Cast, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return Cast{}, fmt.Errorf("failed to open recording: %w", err)
+	}
+	defer file.Close()
+	cast, err := Read(file)
+	if err != nil {
+		return Cast{}, fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+	return cast, nil
+}
+
+// Recording describes a recording in a directory
+type Recording struct {
+	Path     string
+	Title    string
+	Started  time.Time
+	Duration time.Duration
+	Size     int64
+	Problem  string // Why the recording can't be read, if it can't
+}
+
+// List returns the recordings in dir, newest first. A missing directory has no
+// recordings.
+func List(dir string) ([]Recording, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read recordings directory: %w", err)
+	}
+
+	var recordings []Recording
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != Extension {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		recording := Recording{
+			Path:    filepath.Join(dir, entry.Name()),
+			Title:   strings.TrimSuffix(entry.Name(), Extension),
+			Started: info.ModTime(),
+			Size:    info.Size(),
+		}
+		cast, err := Load(recording.Path)
+		if err != nil {
+			recording.Problem = err.Error()
+		} else {
+			if cast.Header.Title != "" {
+				recording.Title = cast.Header.Title
+			}
+			if cast.Header.Timestamp > 0 {
+				recording.Started = time.Unix(cast.Header.Timestamp, 0)
+			}
+			recording.Duration = cast.Duration()
+		}
+		recordings = append(recordings, recording)
+	}
+	sort.SliceStable(recordings, func(i, j int) bool {
+		return recordings[i].Started.After(recordings[j].Started)
+	})
+	return recordings, nil
+}
+
+// PlayOptions controls the pace of playback
+type PlayOptions struct {
+	Speed     float64       // Playback speed, e.g. 2 for twice as fast (default: 1)
+	IdleLimit time.Duration // Longest pause between events, 0 for none
+}
+
+// Play writes the output of the cast to w at the pace it was recorded,
+// stopping early when ctx is done
+func Play(ctx context.Context, cast Cast, w io.Writer, options PlayOptions) error {
+	speed := options.Speed
+	if speed <= 0 {
+		speed = 1
+	}
+	previous := 0.0
+	for _, event := range cast.Events {
+		if event.Type != "o" {
+			continue
+		}
+		wait := time.Duration((event.Time - previous) / speed * float64(time.Second))
+		previous = event.Time
+		if options.IdleLimit > 0 && wait > options.IdleLimit {
+			wait = options.IdleLimit
+		}
+		if wait > 0 {
+			timer := time.NewTimer(wait)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return ctx.Err()
+			case <-timer.C:
+			}
+		} else if err := ctx.Err(); err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, event.Data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PlayTerminal clears the terminal and plays the cast on it until it ends or
+// Ctrl+C is pressed, which isn't reported as an error
+func PlayTerminal(cast Cast, options PlayOptions) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	fmt.Print("\x1b[0m\x1b[2J\x1b[H")
+	err := Play(ctx, cast, os.Stdout, options)
+	// Leave the terminal with default colors and a visible cursor
+	fmt.Print("\x1b[0m\x1b[?25h\r\n")
+	if ctx.Err() != nil {
+		return nil
+	}
+	return err
+}
+
+// FormatSize returns a file size in B, KB or MB
+func FormatSize(size int64) string {
+	switch {
+	case size >= 1024*1024:
+		return fmt.Sprintf("%.1f MB", float64(size)/(1024*1024))
+	case size >= 1024:
+		return fmt.Sprintf("%.1f KB", float64(size)/1024)
+	}
+	return fmt.Sprintf("%d B", size)
+}
+
+// Find returns the recording in dir called name, with or without its
+// extension, or the one at path name when it's a path to a file
+func Find(dir, name string) (string, error) {
+	if strings.ContainsRune(name, os.PathSeparator) {
+		if _, err := os.Stat(name); err != nil {
+			return "", fmt.Errorf("recording '%s' not found", name)
+		}
+		return name, nil
+	}
+	if filepath.Ext(name) != Extension {
+		name += Extension
+	}
+	path := filepath.Join(dir, name)
+	if _, err := os.Stat(path); err != nil {
+		return "", fmt.Errorf("recording '%s' not found in %s", strings.TrimSuffix(name, Extension), dir)
+	}
+	return path, nil
+}
@@ -0,0 +1,180 @@
+package recording
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecorder(t *testing.T) {
+	started := time.Date(2026, 10, 18, 9, 30, 0, 0, time.UTC)
+	now := started
+	clock := func() time.Time { return now }
+
+	var buf bytes.Buffer
+	recorder, err := newRecorder(&buf, Header{Width: 80, Height: 24, Title: "web1"}, clock)
+	if err != nil {
+		t.Fatalf("newRecorder failed: %v", err)
+	}
+
+	now = started.Add(1500 * time.Millisecond)
+	recorder.Write([]byte("$ ls\r\n"))
+	// "é" split across two writes is only recorded once complete
+	now = started.Add(2 * time.Second)
+	recorder.Write([]byte("caf\xc3"))
+	now = started.Add(3 * time.Second)
+	recorder.Write([]byte("\xa9\r\n"))
+	if err := recorder.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	expected := []string{
+		`{"version":2,"width":80,"height":24,"timestamp":1792315800,"title":"web1"}`,
+		`[1.500000, "o", "$ ls\r\n"]`,
+		`[2.000000, "o", "caf"]`,
+		`[3.000000, "o", "é\r\n"]`,
+	}
+	if len(lines) != len(expected) {
+		t.Fatalf("Expected %d lines, got %q", len(expected), lines)
+	}
+	for i := range expected {
+		if lines[i] != expected[i] {
+			t.Errorf("Line %d = %s, want %s", i, lines[i], expected[i])
+		}
+	}
+
+	cast, err := Read(&buf)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if cast.Header.Title != "web1" || len(cast.Events) != 3 {
+		t.Fatalf("Expected the recorded header and 3 events, got %+v", cast)
+	}
+	if cast.Events[2].Data != "é\r\n" {
+		t.Errorf("Expected the split character to be joined, got %q", cast.Events[2].Data)
+	}
+	if cast.Duration() != 3*time.Second {
+		t.Errorf("Expected a 3s recording, got %v", cast.Duration())
+	}
+}
+
+func TestReadCutOffRecording(t *testing.T) {
+	input := `{"version":2,"width":80,"height":24}
+[0.5, "o", "hello"]
+[1.0, "o", "wor`
+	cast, err := Read(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Expected a recording cut off mid-write to be readable, got %v", err)
+	}
+	if len(cast.Events) != 1 {
+		t.Errorf("Expected the cut off event to be dropped, got %+v", cast.Events)
+	}
+
+	if _, err := Read(strings.NewReader(`{"version":1}`)); err == nil {
+		t.Error("Expected version 1 recordings to be rejected")
+	}
+	if _, err := Read(strings.NewReader("{\"version\":2}\nnot json\n[1, \"o\", \"x\"]\n")); err == nil {
+		t.Error("Expected an invalid event to be reported")
+	}
+}
+
+func TestFileName(t *testing.T) {
+	started := time.Date(2026, 10, 18, 9, 30, 5, 0, time.UTC)
+	if name := FileName("prod", "web/1", started); name != "prod-web_1-20261018-093005.cast" {
+		t.Errorf("Unexpected file name %q", name)
+	}
+	if name := FileName("web1", "web1", started); name != "web1-20261018-093005.cast" {
+		t.Errorf("Expected the window to be left out when named like the session, got %q", name)
+	}
+}
+
+func TestList(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"old.cast":  "{\"version\":2,\"width\":80,\"height\":24,\"timestamp\":1000,\"title\":\"old\"}\n[2.5, \"o\", \"x\"]\n",
+		"new.cast":  "{\"version\":2,\"width\":80,\"height\":24,\"timestamp\":2000,\"title\":\"new\"}\n",
+		"bad.cast":  "garbage\n",
+		"notes.txt": "not a recording",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	recordings, err := List(dir)
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(recordings) != 3 {
+		t.Fatalf("Expected 3 recordings, got %+v", recordings)
+	}
+	var titles []string
+	for _, recording := range recordings {
+		titles = append(titles, recording.Title)
+	}
+	// The unreadable recording is dated by its file, so it's the newest
+	if strings.Join(titles, ",") != "bad,new,old" {
+		t.Errorf("Expected newest first, got %v", titles)
+	}
+	if recordings[0].Problem == "" {
+		t.Error("Expected the unreadable recording to carry a problem")
+	}
+	if recordings[2].Duration != 2500*time.Millisecond {
+		t.Errorf("Expected a 2.5s duration, got %v", recordings[2].Duration)
+	}
+
+	if recordings, err := List(filepath.Join(dir, "missing")); err != nil || len(recordings) != 0 {
+		t.Errorf("Expected no recordings in a missing directory, got %v, %v", recordings, err)
+	}
+}
+
+func TestPlay(t *testing.T) {
+	cast := Cast{Events: []Event{
+		{Time: 0.01, Type: "o", Data: "a"},
+		{Time: 0.02, Type: "i", Data: "typed"},
+		{Time: 60, Type: "o", Data: "b"},
+	}}
+
+	var out bytes.Buffer
+	started := time.Now()
+	err := Play(context.Background(), cast, &out, PlayOptions{Speed: 2, IdleLimit: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("Play failed: %v", err)
+	}
+	if out.String() != "ab" {
+		t.Errorf("Expected only output events, got %q", out.String())
+	}
+	if elapsed := time.Since(started); elapsed > 5*time.Second {
+		t.Errorf("Expected the idle limit to cap pauses, took %v", elapsed)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	out.Reset()
+	if err := Play(ctx, cast, &out, PlayOptions{}); err == nil {
+		t.Error("Expected a cancelled playback to stop")
+	}
+}
+
+func TestFind(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "web1-20261018-093005.cast")
+	if err := os.WriteFile(path, []byte("{\"version\":2}\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"web1-20261018-093005", "web1-20261018-093005.cast", path} {
+		if found, err := Find(dir, name); err != nil || found != path {
+			t.Errorf("Find(%q) = %q, %v; want %q", name, found, err, path)
+		}
+	}
+	if _, err := Find(dir, "web2"); err == nil {
+		t.Error("Expected a missing recording to be reported")
+	}
+}
@@ -0,0 +1,173 @@
+package retry
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	"sshm/internal/config"
+)
+
+// Default values used when a retry policy leaves a field unset
+const (
+	DefaultAttempts       = 1
+	DefaultInitialBackoff = time.Second
+	DefaultMaxBackoff     = 30 * time.Second
+	DefaultMultiplier     = 2.0
+)
+
+// DefaultRetryOn lists the failure classifications retried when a policy does not specify any
+var DefaultRetryOn = []string{"unreachable", "refused", "error"}
+
+// sleep waits for the given duration or until the context is cancelled (variable to allow mocking in tests)
+var sleep = func(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Policy is a parsed, ready to use retry policy
+type Policy struct {
+	Attempts       int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Multiplier     float64
+	Jitter         float64
+	RetryOn        []string
+}
+
+// Classifier maps an error to a failure classification such as "unreachable"
+type Classifier func(error) string
+
+// ExhaustedError is returned when every allowed attempt failed
+type ExhaustedError struct {
+	Attempts int
+	Class    string
+	Err      error
+}
+
+// Error implements the error interface
+func (e *ExhaustedError) Error() string {
+	return fmt.Sprintf("failed after %d attempts: %v", e.Attempts, e.Err)
+}
+
+// Unwrap returns the error of the final attempt
+func (e *ExhaustedError) Unwrap() error {
+	return e.Err
+}
+
+// FromConfig converts a configured retry policy into a Policy, filling in defaults
+func FromConfig(cfg config.RetryPolicy) (Policy, error) {
+	if err := cfg.Validate(); err != nil {
+		return Policy{}, err
+	}
+
+	policy := Policy{
+		Attempts:       cfg.Attempts,
+		InitialBackoff: DefaultInitialBackoff,
+		MaxBackoff:     DefaultMaxBackoff,
+		Multiplier:     cfg.Multiplier,
+		Jitter:         cfg.Jitter,
+		RetryOn:        cfg.RetryOn,
+	}
+
+	if policy.Attempts <= 0 {
+		policy.Attempts = DefaultAttempts
+	}
+	if policy.Multiplier == 0 {
+		policy.Multiplier = DefaultMultiplier
+	}
+	if len(policy.RetryOn) == 0 {
+		policy.RetryOn = DefaultRetryOn
+	}
+
+	// Durations were already checked by Validate
+	if cfg.InitialBackoff != "" {
+		policy.InitialBackoff, _ = time.ParseDuration(cfg.InitialBackoff)
+	}
+	if cfg.MaxBackoff != "" {
+		policy.MaxBackoff, _ = time.ParseDuration(cfg.MaxBackoff)
+	}
+
+	return policy, nil
+}
+
+// Backoff returns the delay to wait after the given failed attempt (1-based)
+func (p Policy) Backoff(attempt int) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+
+	delay := float64(p.InitialBackoff) * math.Pow(p.Multiplier, float64(attempt-1))
+	if p.MaxBackoff > 0 && delay > float64(p.MaxBackoff) {
+		delay = float64(p.MaxBackoff)
+	}
+
+	if p.Jitter > 0 {
+		// Spread the delay uniformly within +/- jitter of its value
+		delay += delay * p.Jitter * (2*rand.Float64() - 1)
+	}
+
+	if delay < 0 {
+		return 0
+	}
+	return time.Duration(delay)
+}
+
+// ShouldRetry reports whether a failure with the given classification is retried
+func (p Policy) ShouldRetry(class string) bool {
+	for _, candidate := range p.RetryOn {
+		if candidate == class {
+			return true
+		}
+	}
+	return false
+}
+
+// Do runs fn until it succeeds, the policy gives up, or the context is cancelled.
+// It returns the number of attempts made. When more than one attempt failed the
+// error is an *ExhaustedError so callers can tell it apart from a first-try failure.
+func Do(ctx context.Context, p Policy, classify Classifier, fn func() error) (int, error) {
+	attempts := p.Attempts
+	if attempts <= 0 {
+		attempts = DefaultAttempts
+	}
+
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if err = fn(); err == nil {
+			return attempt, nil
+		}
+
+		class := ""
+		if classify != nil {
+			class = classify(err)
+		}
+
+		if attempt == attempts || !p.ShouldRetry(class) {
+			return attempt, wrapFailure(attempt, class, err)
+		}
+
+		if sleepErr := sleep(ctx, p.Backoff(attempt)); sleepErr != nil {
+			return attempt, wrapFailure(attempt, class, err)
+		}
+	}
+
+	return attempts, err
+}
+
+// wrapFailure marks failures that happened after at least one retry
+func wrapFailure(attempts int, class string, err error) error {
+	if attempts > 1 {
+		return &ExhaustedError{Attempts: attempts, Class: class, Err: err}
+	}
+	return err
+}
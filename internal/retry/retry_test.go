@@ -0,0 +1,198 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"sshm/internal/config"
+	sshmssh "sshm/internal/ssh"
+)
+
+func noSleep(t *testing.T) *[]time.Duration {
+	var delays []time.Duration
+	original := sleep
+	sleep = func(ctx context.Context, d time.Duration) error {
+		delays = append(delays, d)
+		return ctx.Err()
+	}
+	t.Cleanup(func() { sleep = original })
+	return &delays
+}
+
+func classifyAs(class string) Classifier {
+	return func(error) string { return class }
+}
+
+func TestFromConfigDefaults(t *testing.T) {
+	policy, err := FromConfig(config.RetryPolicy{})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if policy.Attempts != DefaultAttempts {
+		t.Errorf("Expected %d attempts, got %d", DefaultAttempts, policy.Attempts)
+	}
+	if policy.InitialBackoff != DefaultInitialBackoff {
+		t.Errorf("Expected initial backoff %v, got %v", DefaultInitialBackoff, policy.InitialBackoff)
+	}
+	if policy.Multiplier != DefaultMultiplier {
+		t.Errorf("Expected multiplier %v, got %v", DefaultMultiplier, policy.Multiplier)
+	}
+	if len(policy.RetryOn) != len(DefaultRetryOn) {
+		t.Errorf("Expected default retry classifications, got %v", policy.RetryOn)
+	}
+}
+
+func TestFromConfigInvalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy config.RetryPolicy
+	}{
+		{"negative attempts", config.RetryPolicy{Attempts: -1}},
+		{"bad duration", config.RetryPolicy{InitialBackoff: "soon"}},
+		{"jitter too large", config.RetryPolicy{Jitter: 1.5}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := FromConfig(tt.policy); err == nil {
+				t.Error("Expected validation error")
+			}
+		})
+	}
+}
+
+func TestBackoffGrowsAndCaps(t *testing.T) {
+	policy := Policy{
+		InitialBackoff: time.Second,
+		MaxBackoff:     5 * time.Second,
+		Multiplier:     2,
+	}
+
+	expected := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second}
+	for i, want := range expected {
+		if got := policy.Backoff(i + 1); got != want {
+			t.Errorf("Attempt %d: expected %v, got %v", i+1, want, got)
+		}
+	}
+}
+
+func TestBackoffJitterStaysInRange(t *testing.T) {
+	policy := Policy{InitialBackoff: time.Second, Multiplier: 2, Jitter: 0.5}
+
+	for i := 0; i < 100; i++ {
+		got := policy.Backoff(1)
+		if got < 500*time.Millisecond || got > 1500*time.Millisecond {
+			t.Fatalf("Jittered backoff %v outside expected range", got)
+		}
+	}
+}
+
+func TestDoSucceedsAfterRetries(t *testing.T) {
+	delays := noSleep(t)
+	policy := Policy{Attempts: 3, InitialBackoff: time.Second, Multiplier: 2, RetryOn: []string{"unreachable"}}
+
+	calls := 0
+	attempts, err := Do(context.Background(), policy, classifyAs("unreachable"), func() error {
+		calls++
+		if calls < 3 {
+			return errors.New("timeout")
+		}
+		return nil
+	})
+
+	if err != nil {
+		t.Fatalf("Expected success, got: %v", err)
+	}
+	if attempts != 3 {
+		t.Errorf("Expected 3 attempts, got %d", attempts)
+	}
+	if len(*delays) != 2 {
+		t.Errorf("Expected 2 backoff delays, got %d", len(*delays))
+	}
+}
+
+func TestDoExhausted(t *testing.T) {
+	noSleep(t)
+	policy := Policy{Attempts: 2, RetryOn: []string{"refused"}}
+
+	attempts, err := Do(context.Background(), policy, classifyAs("refused"), func() error {
+		return errors.New("connection refused")
+	})
+
+	var exhausted *ExhaustedError
+	if !errors.As(err, &exhausted) {
+		t.Fatalf("Expected ExhaustedError, got: %v", err)
+	}
+	if attempts != 2 || exhausted.Attempts != 2 {
+		t.Errorf("Expected 2 attempts, got %d (error reports %d)", attempts, exhausted.Attempts)
+	}
+	if exhausted.Class != "refused" {
+		t.Errorf("Expected class 'refused', got '%s'", exhausted.Class)
+	}
+}
+
+func TestDoDoesNotRetryUnlistedClassification(t *testing.T) {
+	noSleep(t)
+	policy := Policy{Attempts: 5, RetryOn: []string{"unreachable"}}
+
+	calls := 0
+	attempts, err := Do(context.Background(), policy, classifyAs("auth failed"), func() error {
+		calls++
+		return errors.New("permission denied")
+	})
+
+	if calls != 1 || attempts != 1 {
+		t.Errorf("Expected a single attempt, got %d", calls)
+	}
+
+	var exhausted *ExhaustedError
+	if errors.As(err, &exhausted) {
+		t.Error("First-try failure should not be reported as exhausted")
+	}
+}
+
+func TestDoStopsOnCancelledContext(t *testing.T) {
+	noSleep(t)
+	policy := Policy{Attempts: 5, RetryOn: []string{"unreachable"}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	calls := 0
+	Do(ctx, policy, classifyAs("unreachable"), func() error {
+		calls++
+		return errors.New("timeout")
+	})
+
+	if calls != 1 {
+		t.Errorf("Expected retries to stop after cancellation, got %d calls", calls)
+	}
+}
+
+func TestDoRetriesByConnectErrorKind(t *testing.T) {
+	noSleep(t)
+	policy := Policy{Attempts: 3, RetryOn: DefaultRetryOn}
+
+	timeouts := 0
+	_, err := Do(context.Background(), policy, sshmssh.ClassifyError, func() error {
+		timeouts++
+		return &sshmssh.ConnectError{Kind: sshmssh.ErrTimeout, Err: errors.New("dial tcp 10.0.0.1:22: i/o timeout")}
+	})
+	if timeouts != 3 || !errors.Is(err, sshmssh.ErrTimeout) {
+		t.Errorf("Expected timeouts to be retried and still match ErrTimeout, got %d attempts and %v", timeouts, err)
+	}
+
+	for _, kind := range []error{sshmssh.ErrAuth, sshmssh.ErrHostKey} {
+		calls := 0
+		Do(context.Background(), policy, sshmssh.ClassifyError, func() error {
+			calls++
+			return &sshmssh.ConnectError{Kind: kind, Err: errors.New("rejected")}
+		})
+		if calls != 1 {
+			t.Errorf("Expected %v not to be retried, got %d attempts", kind, calls)
+		}
+	}
+}
@@ -0,0 +1,228 @@
+// Package runbook reads a local directory of markdown runbooks and finds the
+// ones describing a server, so operational docs can be read next to the hosts
+// they are about.
+package runbook
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+	"sshm/internal/config"
+)
+
+// frontMatterDelimiter opens and closes the YAML front matter of a runbook
+const frontMatterDelimiter = "---"
+
+// Runbook is one markdown file of the runbooks directory
+type Runbook struct {
+	Path     string   // Relative to the runbooks directory, with forward slashes
+	Title    string   // From the front matter, the first heading or the file name
+	Servers  []string // Server names, aliases or patterns like web-* from the front matter
+	Profiles []string // Profile names from the front matter
+	Body     string   // The markdown without its front matter
+	Problem  string   // Why the front matter was ignored, if it was
+}
+
+// frontMatter is what a runbook may declare at its top between --- lines
+type frontMatter struct {
+	Title    string   `yaml:"title"`
+	Servers  []string `yaml:"servers"`
+	Profiles []string `yaml:"profiles"`
+}
+
+// Load reads every markdown file below dir, sorted by path
+func Load(dir string) ([]Runbook, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read runbooks directory: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("runbooks directory %s is not a directory", dir)
+	}
+
+	var runbooks []Runbook
+	err = filepath.WalkDir(dir, func(file string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if file != dir && strings.HasPrefix(entry.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !isMarkdown(entry.Name()) {
+			return nil
+		}
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("failed to read runbook %s: %w", file, err)
+		}
+		relative, err := filepath.Rel(dir, file)
+		if err != nil {
+			return err
+		}
+		runbooks = append(runbooks, Parse(filepath.ToSlash(relative), data))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(runbooks, func(i, j int) bool { return runbooks[i].Path < runbooks[j].Path })
+	return runbooks, nil
+}
+
+// isMarkdown reports whether a file name has a markdown extension
+func isMarkdown(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".md", ".markdown":
+		return true
+	}
+	return false
+}
+
+// Parse reads a runbook's front matter and title
+func Parse(relativePath string, data []byte) Runbook {
+	runbook := Runbook{Path: relativePath, Body: string(data)}
+
+	if header, body, ok := splitFrontMatter(data); ok {
+		var matter frontMatter
+		if err := yaml.Unmarshal(header, &matter); err != nil {
+			runbook.Problem = fmt.Sprintf("invalid front matter: %s", err.Error())
+		} else {
+			runbook.Title = strings.TrimSpace(matter.Title)
+			runbook.Servers = matter.Servers
+			runbook.Profiles = matter.Profiles
+			runbook.Body = string(body)
+		}
+	}
+
+	if runbook.Title == "" {
+		runbook.Title = firstHeading(runbook.Body)
+	}
+	if runbook.Title == "" {
+		runbook.Title = strings.TrimSuffix(path.Base(relativePath), path.Ext(relativePath))
+	}
+	return runbook
+}
+
+// splitFrontMatter separates the YAML between the leading --- lines from the rest
+func splitFrontMatter(data []byte) (header, body []byte, ok bool) {
+	data = bytes.TrimPrefix(data, []byte("\ufeff"))
+	lines := bytes.SplitAfter(data, []byte("\n"))
+	if len(lines) == 0 || strings.TrimSpace(string(lines[0])) != frontMatterDelimiter {
+		return nil, nil, false
+	}
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimSpace(string(lines[i])) == frontMatterDelimiter {
+			return bytes.Join(lines[1:i], nil), bytes.Join(lines[i+1:], nil), true
+		}
+	}
+	return nil, nil, false
+}
+
+// firstHeading returns the text of the first markdown heading
+func firstHeading(body string) string {
+	for _, line := range strings.Split(body, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "#") {
+			return strings.TrimSpace(strings.TrimLeft(line, "#"))
+		}
+	}
+	return ""
+}
+
+// Describes reports whether the runbook is about the server: its front matter
+// lists the server or one of its profiles, or its file name or one of its
+// directories is the server's name, an alias or one of its profiles
+func (r Runbook) Describes(server config.Server, profiles []string) bool {
+	for _, pattern := range r.Servers {
+		if server.MatchesName(pattern) {
+			return true
+		}
+		if matched, _ := path.Match(pattern, server.Name); matched {
+			return true
+		}
+	}
+	for _, profile := range r.Profiles {
+		if containsName(profiles, profile) {
+			return true
+		}
+	}
+
+	for _, name := range r.pathNames() {
+		if server.MatchesName(name) || containsName(profiles, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// pathNames returns the file name without extension and the directories the
+// runbook is in
+func (r Runbook) pathNames() []string {
+	parts := strings.Split(r.Path, "/")
+	last := len(parts) - 1
+	parts[last] = strings.TrimSuffix(parts[last], path.Ext(parts[last]))
+	return parts
+}
+
+// containsName reports whether names holds name
+func containsName(names []string, name string) bool {
+	for _, existing := range names {
+		if existing == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ForServer returns the runbooks describing a server
+func ForServer(runbooks []Runbook, cfg *config.Config, server config.Server) []Runbook {
+	profiles := cfg.ServerProfiles(server.Name)
+	var matching []Runbook
+	for _, runbook := range runbooks {
+		if runbook.Describes(server, profiles) {
+			matching = append(matching, runbook)
+		}
+	}
+	return matching
+}
+
+// Hit is a line of a runbook containing the searched text
+type Hit struct {
+	Runbook int    // Index into the searched runbooks
+	Line    int    // 0-based line of the body, -1 when only the title matched
+	Text    string // The matching line, trimmed
+}
+
+// Search finds the runbooks whose title or lines contain query, ignoring case
+func Search(runbooks []Runbook, query string) []Hit {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return nil
+	}
+
+	var hits []Hit
+	for i, runbook := range runbooks {
+		found := false
+		for number, line := range strings.Split(runbook.Body, "\n") {
+			if strings.Contains(strings.ToLower(line), query) {
+				hits = append(hits, Hit{Runbook: i, Line: number, Text: strings.TrimSpace(line)})
+				found = true
+			}
+		}
+		if !found && strings.Contains(strings.ToLower(runbook.Title), query) {
+			hits = append(hits, Hit{Runbook: i, Line: -1, Text: runbook.Title})
+		}
+	}
+	return hits
+}
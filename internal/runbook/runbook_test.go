@@ -0,0 +1,133 @@
+package runbook
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"sshm/internal/config"
+)
+
+func writeRunbook(t *testing.T, dir, name, content string) {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	writeRunbook(t, dir, "web-01.md", "# Restarting the web server\n\nsudo systemctl restart nginx\n")
+	writeRunbook(t, dir, "production/failover.md", "Promote the replica.\n")
+	writeRunbook(t, dir, "api.markdown", "---\ntitle: API deploys\nservers: [api-*]\nprofiles: [staging]\n---\n# Deploying\n")
+	writeRunbook(t, dir, "notes.txt", "not a runbook")
+	writeRunbook(t, dir, ".git/HEAD.md", "ignored")
+
+	runbooks, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(runbooks) != 3 {
+		t.Fatalf("Expected 3 runbooks, got %+v", runbooks)
+	}
+
+	api := runbooks[0]
+	if api.Path != "api.markdown" || api.Title != "API deploys" || api.Body != "# Deploying\n" {
+		t.Errorf("Unexpected front matter runbook %+v", api)
+	}
+	if len(api.Servers) != 1 || api.Servers[0] != "api-*" || len(api.Profiles) != 1 {
+		t.Errorf("Expected the front matter's servers and profiles, got %+v", api)
+	}
+	if runbooks[1].Path != "production/failover.md" || runbooks[1].Title != "failover" {
+		t.Errorf("Expected the file name as title without a heading, got %+v", runbooks[1])
+	}
+	if runbooks[2].Title != "Restarting the web server" {
+		t.Errorf("Expected the first heading as title, got %q", runbooks[2].Title)
+	}
+}
+
+func TestLoadRequiresDirectory(t *testing.T) {
+	if _, err := Load(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("Expected an error for a missing directory")
+	}
+}
+
+func TestParseInvalidFrontMatter(t *testing.T) {
+	runbook := Parse("broken.md", []byte("---\nservers: [unclosed\n---\n# Broken\n"))
+	if runbook.Problem == "" {
+		t.Error("Expected the invalid front matter to be reported")
+	}
+	if runbook.Title != "Broken" {
+		t.Errorf("Expected the heading as title, got %q", runbook.Title)
+	}
+}
+
+func TestForServer(t *testing.T) {
+	cfg := &config.Config{
+		Servers: []config.Server{
+			{Name: "web-01", Aliases: []string{"www"}},
+			{Name: "api-02"},
+			{Name: "db-01"},
+		},
+		Profiles: []config.Profile{{Name: "production", Servers: []string{"web-01", "db-01"}}},
+	}
+	runbooks := []Runbook{
+		Parse("web-01.md", []byte("by name")),
+		Parse("www/cache.md", []byte("by alias directory")),
+		Parse("production/failover.md", []byte("by profile directory")),
+		Parse("api.md", []byte("---\nservers: [api-*]\n---\nby pattern")),
+		Parse("db.md", []byte("---\nprofiles: [production]\n---\nby front matter profile")),
+	}
+
+	tests := []struct {
+		server string
+		want   []string
+	}{
+		{"web-01", []string{"web-01.md", "www/cache.md", "production/failover.md", "db.md"}},
+		{"api-02", []string{"api.md"}},
+		{"db-01", []string{"production/failover.md", "db.md"}},
+	}
+	for _, tt := range tests {
+		server, _ := cfg.GetServer(tt.server)
+		got := ForServer(runbooks, cfg, *server)
+		var paths []string
+		for _, runbook := range got {
+			paths = append(paths, runbook.Path)
+		}
+		if len(paths) != len(tt.want) {
+			t.Errorf("ForServer(%s) = %v, want %v", tt.server, paths, tt.want)
+			continue
+		}
+		for i := range paths {
+			if paths[i] != tt.want[i] {
+				t.Errorf("ForServer(%s) = %v, want %v", tt.server, paths, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestSearch(t *testing.T) {
+	runbooks := []Runbook{
+		Parse("disk.md", []byte("# Disk full\n\nClean /var/log first.\nThen check DISK quotas.\n")),
+		Parse("cert.md", []byte("---\ntitle: Renewing disk certificates\n---\nRun certbot.\n")),
+	}
+
+	hits := Search(runbooks, "disk")
+	if len(hits) != 3 {
+		t.Fatalf("Expected 3 hits, got %+v", hits)
+	}
+	if hits[0].Line != 0 || hits[1].Line != 3 || hits[1].Text != "Then check DISK quotas." {
+		t.Errorf("Unexpected line hits %+v", hits[:2])
+	}
+	if hits[2].Runbook != 1 || hits[2].Line != -1 {
+		t.Errorf("Expected a title-only hit, got %+v", hits[2])
+	}
+	if hits := Search(runbooks, "  "); hits != nil {
+		t.Errorf("Expected no hits for a blank query, got %+v", hits)
+	}
+}
@@ -0,0 +1,102 @@
+package secrets
+
+import (
+	"fmt"
+	"strings"
+
+	"sshm/internal/config"
+)
+
+// Request is a secret that servers about to be connected need and the store
+// doesn't have. Servers referencing the same ID share one request.
+type Request struct {
+	ID      string
+	Kind    Kind
+	Servers []string
+}
+
+// Label describes the request in a prompt, e.g. "Password for web1, web2"
+func (r Request) Label() string {
+	kind := string(r.Kind)
+	return fmt.Sprintf("%s%s for %s", strings.ToUpper(kind[:1]), kind[1:], strings.Join(r.Servers, ", "))
+}
+
+// Collected holds the passwords and key passphrases of a group of servers,
+// gathered before connecting so that no connection stops at a prompt
+type Collected struct {
+	secrets map[string]string // Server name -> secret
+	Missing []Request         // Secrets to ask for, in server order
+}
+
+// Needs reports whether connecting to the server prompts for a password or a
+// key passphrase
+func Needs(server config.Server) bool {
+	return server.AuthType == "password" || (server.AuthType == "key" && server.PassphraseProtected)
+}
+
+// Collect reads the secrets the servers need from the store, opening it only
+// once, and lists those it doesn't have as requests to ask for up front
+func Collect(servers []config.Server) *Collected {
+	collected := &Collected{secrets: make(map[string]string)}
+	store, openErr := Open()
+
+	requests := make(map[string]int) // Secret ID -> index in Missing
+	for _, server := range servers {
+		if !Needs(server) {
+			continue
+		}
+		kind, _ := KindOf(server)
+		if kind == Password && server.Password != "" {
+			collected.secrets[server.Name] = server.Password
+			continue
+		}
+		id := ID(server)
+		if id != "" && openErr == nil {
+			if secret, err := store.Get(id); err == nil {
+				collected.secrets[server.Name] = secret
+				continue
+			}
+		}
+
+		if id == "" {
+			id = DefaultID(server.Name, kind)
+		}
+		if index, ok := requests[id]; ok {
+			collected.Missing[index].Servers = append(collected.Missing[index].Servers, server.Name)
+			continue
+		}
+		requests[id] = len(collected.Missing)
+		collected.Missing = append(collected.Missing, Request{ID: id, Kind: kind, Servers: []string{server.Name}})
+	}
+	return collected
+}
+
+// Provide answers a request for the servers sharing it; an empty secret
+// leaves them to be typed in manually
+func (c *Collected) Provide(request Request, secret string) {
+	if secret == "" {
+		return
+	}
+	for _, name := range request.Servers {
+		c.secrets[name] = secret
+	}
+}
+
+// Secret returns the collected password or passphrase of the named server
+func (c *Collected) Secret(serverName string) (string, bool) {
+	if c == nil {
+		return "", false
+	}
+	secret, ok := c.secrets[serverName]
+	return secret, ok
+}
+
+// SSHPassFlags returns the sshpass flags that make it answer the prompt ssh
+// shows for the server's password or key passphrase
+func SSHPassFlags(server config.Server) []string {
+	if server.AuthType == "key" {
+		// sshpass waits for "assword" unless told which prompt to answer
+		return []string{"-P", "passphrase"}
+	}
+	return nil
+}
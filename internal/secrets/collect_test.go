@@ -0,0 +1,54 @@
+package secrets
+
+import (
+	"reflect"
+	"testing"
+
+	"sshm/internal/config"
+)
+
+func TestCollectAsksOnceForMissingSecrets(t *testing.T) {
+	store := NewMemoryStore()
+	defer Use(store)()
+	store.Set("ops-password", "stored")
+
+	servers := []config.Server{
+		{Name: "web1", AuthType: "password", UseKeyring: true, KeyringID: "ops-password"},
+		{Name: "web2", AuthType: "password", UseKeyring: true, KeyringID: "shared-password"},
+		{Name: "web3", AuthType: "password", UseKeyring: true, KeyringID: "shared-password"},
+		{Name: "db1", AuthType: "key", KeyPath: "~/.ssh/db", PassphraseProtected: true},
+		{Name: "agent", AuthType: "key", KeyPath: "~/.ssh/id_ed25519"},
+		{Name: "legacy", AuthType: "password", Password: "plain"},
+	}
+	collected := Collect(servers)
+
+	if secret, ok := collected.Secret("web1"); !ok || secret != "stored" {
+		t.Errorf("Expected web1's password from the keyring, got %q", secret)
+	}
+	if secret, ok := collected.Secret("legacy"); !ok || secret != "plain" {
+		t.Errorf("Expected the configured password of legacy, got %q", secret)
+	}
+	if _, ok := collected.Secret("agent"); ok {
+		t.Error("Expected no secret for a key without a passphrase")
+	}
+
+	want := []Request{
+		{ID: "shared-password", Kind: Password, Servers: []string{"web2", "web3"}},
+		{ID: "passphrase-db1", Kind: Passphrase, Servers: []string{"db1"}},
+	}
+	if !reflect.DeepEqual(collected.Missing, want) {
+		t.Fatalf("Expected one request per missing secret, got %+v", collected.Missing)
+	}
+	if label := collected.Missing[0].Label(); label != "Password for web2, web3" {
+		t.Errorf("Unexpected label %q", label)
+	}
+
+	collected.Provide(collected.Missing[0], "typed")
+	collected.Provide(collected.Missing[1], "")
+	if secret, _ := collected.Secret("web3"); secret != "typed" {
+		t.Errorf("Expected the answer to cover every server sharing the secret, got %q", secret)
+	}
+	if _, ok := collected.Secret("db1"); ok {
+		t.Error("Expected an empty answer to leave the passphrase to be typed")
+	}
+}
@@ -0,0 +1,276 @@
+// Package secrets keeps the passwords of password-auth servers and the
+// passphrases of their keys in the operating system's credential store (macOS
+// Keychain, Secret Service, Windows Credential Manager), so connections and
+// status checks don't have to prompt. The configuration only references a
+// server's secret by its ID.
+package secrets
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	kr "github.com/99designs/keyring"
+	"sshm/internal/config"
+	"sshm/internal/keyring"
+)
+
+// Kind is what a secret unlocks
+type Kind string
+
+const (
+	Password   Kind = "password"
+	Passphrase Kind = "passphrase"
+)
+
+// ErrNotFound is returned when a secret isn't in the store, or a server
+// references none
+var ErrNotFound = errors.New("secret not found")
+
+// Store keeps secrets under their IDs
+type Store interface {
+	Get(id string) (string, error)
+	Set(id, secret string) error
+	Delete(id string) error
+	Backend() string
+}
+
+// keyringStore keeps secrets in the system keyring, or the encrypted file
+// keyring where none is available
+type keyringStore struct {
+	manager keyring.KeyringManager
+}
+
+// Keyring opens the keyring of the given service ("auto", "keychain",
+// "secret-service", "wincred", "pass" or "file") under namespace
+func Keyring(service, namespace string) (Store, error) {
+	manager := keyring.NewKeyringManagerWithNamespace(service, namespace)
+	if manager == nil {
+		return nil, fmt.Errorf("failed to open the %s keyring", service)
+	}
+	return &keyringStore{manager: manager}, nil
+}
+
+func (s *keyringStore) Get(id string) (string, error) {
+	secret, err := s.manager.Retrieve(id)
+	if errors.Is(err, kr.ErrKeyNotFound) {
+		return "", fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	return secret, err
+}
+
+func (s *keyringStore) Set(id, secret string) error {
+	return s.manager.Store(id, secret)
+}
+
+func (s *keyringStore) Delete(id string) error {
+	return s.manager.Delete(id)
+}
+
+func (s *keyringStore) Backend() string {
+	return s.manager.ServiceName()
+}
+
+// MemoryStore keeps secrets in memory, for tests
+type MemoryStore struct {
+	mu      sync.Mutex
+	secrets map[string]string
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{secrets: make(map[string]string)}
+}
+
+func (s *MemoryStore) Get(id string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	secret, ok := s.secrets[id]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	return secret, nil
+}
+
+func (s *MemoryStore) Set(id, secret string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.secrets[id] = secret
+	return nil
+}
+
+func (s *MemoryStore) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.secrets, id)
+	return nil
+}
+
+func (s *MemoryStore) Backend() string {
+	return "memory"
+}
+
+var (
+	openMu sync.Mutex
+	// open returns the store secrets are read from and written to
+	open = func() (Store, error) {
+		return Keyring("auto", keyring.DefaultNamespace)
+	}
+)
+
+// Open returns the store secrets are kept in
+func Open() (Store, error) {
+	openMu.Lock()
+	fn := open
+	openMu.Unlock()
+	return fn()
+}
+
+// Use makes the package keep secrets in store until the returned function
+// restores the previous one, so tests can avoid the real keyring
+func Use(store Store) (restore func()) {
+	openMu.Lock()
+	defer openMu.Unlock()
+	previous := open
+	open = func() (Store, error) { return store, nil }
+	return func() {
+		openMu.Lock()
+		defer openMu.Unlock()
+		open = previous
+	}
+}
+
+// KindOf returns the kind of secret the server authenticates with
+func KindOf(server config.Server) (Kind, error) {
+	switch server.AuthType {
+	case "password":
+		return Password, nil
+	case "key":
+		return Passphrase, nil
+	default:
+		return "", fmt.Errorf("server %s uses auth type '%s', which takes no secret", server.Name, server.AuthType)
+	}
+}
+
+// DefaultID returns the ID a server's secret is stored under unless it
+// references another one
+func DefaultID(serverName string, kind Kind) string {
+	if kind == Passphrase {
+		return "passphrase-" + serverName
+	}
+	return keyring.GeneratePasswordKeyringID(serverName)
+}
+
+// ID returns the ID of the secret the server references, or "" when it keeps
+// none in the store
+func ID(server config.Server) string {
+	if !server.UseKeyring {
+		return ""
+	}
+	return server.KeyringID
+}
+
+// Referenced reports whether the server authenticates with a secret it keeps
+// in the store: the password of a password-auth server, or the passphrase of a
+// passphrase-protected key
+func Referenced(server config.Server) bool {
+	if ID(server) == "" {
+		return false
+	}
+	return server.AuthType == "password" || (server.AuthType == "key" && server.PassphraseProtected)
+}
+
+// Get reads the secret stored under id
+func Get(id string) (string, error) {
+	store, err := Open()
+	if err != nil {
+		return "", err
+	}
+	return store.Get(id)
+}
+
+// Lookup reads the secret the server references
+func Lookup(server config.Server) (string, error) {
+	id := ID(server)
+	if id == "" {
+		return "", fmt.Errorf("%w: %s references no stored secret", ErrNotFound, server.Name)
+	}
+	return Get(id)
+}
+
+// Save stores the server's password or key passphrase under id, or under the
+// ID it already references or its default one when id is empty, and makes the
+// server reference it instead of keeping a plaintext password. Servers that
+// share a secret reference the same ID.
+func Save(server *config.Server, id, secret string) error {
+	kind, err := KindOf(*server)
+	if err != nil {
+		return err
+	}
+	if id == "" {
+		id = ID(*server)
+	}
+	if id == "" {
+		id = DefaultID(server.Name, kind)
+	}
+
+	store, err := Open()
+	if err != nil {
+		return err
+	}
+	if err := store.Set(id, secret); err != nil {
+		return fmt.Errorf("failed to store the %s of %s: %w", kind, server.Name, err)
+	}
+	server.UseKeyring = true
+	server.KeyringID = id
+	server.Password = ""
+	if kind == Passphrase {
+		server.PassphraseProtected = true
+	}
+	return nil
+}
+
+// Forget makes the named server stop referencing its secret, deleting the
+// secret from the store unless another server references it too. It reports
+// whether the secret was deleted.
+func Forget(cfg *config.Config, name string) (bool, error) {
+	server, err := cfg.GetServer(name)
+	if err != nil {
+		return false, err
+	}
+	id := ID(*server)
+	if id == "" {
+		return false, fmt.Errorf("%w: %s references no stored secret", ErrNotFound, server.Name)
+	}
+
+	shared := false
+	for _, other := range cfg.Servers {
+		if other.Name != server.Name && ID(other) == id {
+			shared = true
+			break
+		}
+	}
+	if !shared {
+		store, err := Open()
+		if err != nil {
+			return false, err
+		}
+		if err := store.Delete(id); err != nil {
+			return false, fmt.Errorf("failed to delete secret %s: %w", id, err)
+		}
+	}
+
+	server.UseKeyring = false
+	server.KeyringID = ""
+	return !shared, cfg.UpdateServer(*server)
+}
+
+// SSHPass returns the sshpass flags and the secret that answer the prompt ssh
+// shows for the server's password or key passphrase. Check Referenced first.
+func SSHPass(server config.Server) ([]string, string, error) {
+	secret, err := Lookup(server)
+	if err != nil {
+		return nil, "", err
+	}
+	return SSHPassFlags(server), secret, nil
+}
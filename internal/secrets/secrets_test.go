@@ -0,0 +1,133 @@
+package secrets
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"sshm/internal/config"
+)
+
+func testConfig() *config.Config {
+	return &config.Config{Servers: []config.Server{
+		{Name: "web", Hostname: "web.example.com", Port: 22, Username: "ops", AuthType: "password", Password: "plain"},
+		{Name: "db", Hostname: "db.example.com", Port: 22, Username: "ops", AuthType: "key", KeyPath: "~/.ssh/id_ed25519"},
+		{Name: "cache", Hostname: "cache.example.com", Port: 22, Username: "ops", AuthType: "password"},
+	}}
+}
+
+func TestSaveReferencesSecretByID(t *testing.T) {
+	store := NewMemoryStore()
+	defer Use(store)()
+
+	cfg := testConfig()
+	web := cfg.Servers[0]
+	if err := Save(&web, "", "s3cret"); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if !web.UseKeyring || web.KeyringID != "password-web" || web.Password != "" {
+		t.Errorf("Expected web to reference password-web without a plaintext password, got %+v", web)
+	}
+	if got, _ := store.Get("password-web"); got != "s3cret" {
+		t.Errorf("Expected the password in the store, got %q", got)
+	}
+
+	db := cfg.Servers[1]
+	if err := Save(&db, "", "key pass"); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if db.KeyringID != "passphrase-db" || !db.PassphraseProtected {
+		t.Errorf("Expected db to reference its passphrase, got %+v", db)
+	}
+	if got, err := Lookup(db); err != nil || got != "key pass" {
+		t.Errorf("Lookup() = %q, %v", got, err)
+	}
+
+	// An existing reference is kept when overwriting the secret
+	if err := Save(&web, "", "rotated"); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if got, _ := Lookup(web); web.KeyringID != "password-web" || got != "rotated" {
+		t.Errorf("Expected the rotated password under password-web, got %q under %s", got, web.KeyringID)
+	}
+}
+
+func TestLookupWithoutReference(t *testing.T) {
+	defer Use(NewMemoryStore())()
+
+	if _, err := Lookup(testConfig().Servers[0]); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for a server without a secret, got %v", err)
+	}
+}
+
+func TestReferenced(t *testing.T) {
+	tests := []struct {
+		server config.Server
+		want   bool
+	}{
+		{config.Server{AuthType: "password", UseKeyring: true, KeyringID: "password-web"}, true},
+		{config.Server{AuthType: "password", Password: "plain"}, false},
+		{config.Server{AuthType: "key", UseKeyring: true, KeyringID: "passphrase-db", PassphraseProtected: true}, true},
+		{config.Server{AuthType: "key", UseKeyring: true, KeyringID: "passphrase-db"}, false},
+	}
+	for _, tt := range tests {
+		if got := Referenced(tt.server); got != tt.want {
+			t.Errorf("Referenced(%+v) = %v, want %v", tt.server, got, tt.want)
+		}
+	}
+}
+
+func TestForgetKeepsSharedSecrets(t *testing.T) {
+	store := NewMemoryStore()
+	defer Use(store)()
+
+	cfg := testConfig()
+	for _, name := range []string{"web", "cache"} {
+		server, _ := cfg.GetServer(name)
+		if err := Save(server, "shared-ops", "s3cret"); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+		if err := cfg.UpdateServer(*server); err != nil {
+			t.Fatalf("UpdateServer() error = %v", err)
+		}
+	}
+
+	deleted, err := Forget(cfg, "web")
+	if err != nil || deleted {
+		t.Fatalf("Expected web to stop referencing the shared secret without deleting it, got %v, %v", deleted, err)
+	}
+	if _, err := store.Get("shared-ops"); err != nil {
+		t.Errorf("Expected the shared secret to be kept for cache, got %v", err)
+	}
+
+	deleted, err = Forget(cfg, "cache")
+	if err != nil || !deleted {
+		t.Fatalf("Expected the last reference to delete the secret, got %v, %v", deleted, err)
+	}
+	if _, err := store.Get("shared-ops"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected the secret to be deleted, got %v", err)
+	}
+	if cfg.Servers[2].UseKeyring || cfg.Servers[2].KeyringID != "" {
+		t.Errorf("Expected cache to reference no secret, got %+v", cfg.Servers[2])
+	}
+
+	if _, err := Forget(cfg, "cache"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound forgetting twice, got %v", err)
+	}
+}
+
+func TestSSHPass(t *testing.T) {
+	defer Use(NewMemoryStore())()
+
+	server := config.Server{Name: "db", Hostname: "db.example.com", Port: 22, Username: "ops", AuthType: "key", KeyPath: "~/.ssh/id_ed25519"}
+	if err := Save(&server, "", "key pass"); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	flags, secret, err := SSHPass(server)
+	if err != nil {
+		t.Fatalf("SSHPass() error = %v", err)
+	}
+	if !reflect.DeepEqual(flags, []string{"-P", "passphrase"}) || secret != "key pass" {
+		t.Errorf("SSHPass() = %v, %q", flags, secret)
+	}
+}
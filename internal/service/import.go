@@ -0,0 +1,82 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+
+	"sshm/internal/config"
+)
+
+// ImportResult is what an import changed
+type ImportResult struct {
+	Added           int          `json:"added"`
+	Updated         int          `json:"updated"`
+	Profiles        int          `json:"profiles"`
+	Skipped         []ImportSkip `json:"skipped,omitempty"`
+	SkippedProfiles []ImportSkip `json:"skipped_profiles,omitempty"`
+}
+
+// ImportSkip is a server or profile left out of an import
+type ImportSkip struct {
+	Name  string `json:"name"`
+	Error string `json:"error"`
+}
+
+// Import adds the servers that don't exist yet and replaces those that do,
+// replaces the profiles of the same names, and saves the configuration.
+// Invalid servers, servers whose aliases conflict and invalid profiles are
+// skipped; a profile that can't be imported leaves the existing one in place.
+func (s *Service) Import(servers []config.Server, profiles []config.Profile) (ImportResult, error) {
+	var result ImportResult
+	valid := make([]config.Server, 0, len(servers))
+	for _, server := range servers {
+		if err := server.Validate(); err != nil {
+			result.Skipped = append(result.Skipped, ImportSkip{Name: server.Name, Error: err.Error()})
+			continue
+		}
+		valid = append(valid, server)
+	}
+
+	added, updated, errs := s.cfg.UpsertServers(valid)
+	result.Added, result.Updated = added, updated
+	names := make([]string, 0, len(errs))
+	for name := range errs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		result.Skipped = append(result.Skipped, ImportSkip{Name: name, Error: errs[name].Error()})
+	}
+
+	for _, profile := range profiles {
+		if err := s.replaceProfile(profile); err != nil {
+			result.SkippedProfiles = append(result.SkippedProfiles, ImportSkip{Name: profile.Name, Error: err.Error()})
+			continue
+		}
+		result.Profiles++
+	}
+
+	if err := s.commit(); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// replaceProfile adds the profile in place of the one of the same name, which
+// is kept when the new one can't be added
+func (s *Service) replaceProfile(profile config.Profile) error {
+	if err := profile.Validate(); err != nil {
+		return fmt.Errorf("invalid profile configuration: %w", err)
+	}
+	existing, err := s.cfg.GetProfile(profile.Name)
+	if err != nil {
+		return s.cfg.AddProfile(profile)
+	}
+	previous := *existing
+	s.cfg.RemoveProfile(profile.Name)
+	if err := s.cfg.AddProfile(profile); err != nil {
+		s.cfg.Profiles = append(s.cfg.Profiles, previous)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package service
+
+import (
+	"sshm/internal/config"
+)
+
+// CreateProfile adds a new profile and saves the configuration
+func (s *Service) CreateProfile(profile config.Profile) error {
+	if err := s.cfg.AddProfile(profile); err != nil {
+		return err
+	}
+	return s.commit()
+}
+
+// UpdateProfile replaces the named profile and saves the configuration; the
+// profile may be renamed
+func (s *Service) UpdateProfile(name string, profile config.Profile) error {
+	if err := s.cfg.ReplaceProfile(name, profile); err != nil {
+		return err
+	}
+	return s.commit()
+}
+
+// DeleteProfile deletes the named profile and saves the configuration. Its
+// servers stay in the configuration.
+func (s *Service) DeleteProfile(name string) error {
+	if err := s.cfg.RemoveProfile(name); err != nil {
+		return err
+	}
+	return s.commit()
+}
+
+// ProfileHasProtectedServer reports whether any server in the profile is
+// protected, which makes deleting the profile ask for confirmation
+func (s *Service) ProfileHasProtectedServer(name string) bool {
+	servers, err := s.cfg.GetServersByProfile(name)
+	if err != nil {
+		return false
+	}
+	for _, server := range servers {
+		if server.Protected {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"sshm/internal/config"
+)
+
+// now returns the current time (variable to allow mocking in tests)
+var now = time.Now
+
+// AddServer adds a new server, assigns it to the named profiles and saves the
+// configuration. Nothing is changed when a step fails.
+func (s *Service) AddServer(server config.Server, profiles ...string) error {
+	for _, name := range profiles {
+		if _, err := s.cfg.GetProfile(name); err != nil {
+			return err
+		}
+	}
+	snapshot := s.snapshot()
+	if err := s.cfg.AddServer(server); err != nil {
+		return err
+	}
+	for _, name := range profiles {
+		if err := s.cfg.AssignServerToProfile(server.Name, name); err != nil {
+			s.rollback(snapshot)
+			return err
+		}
+	}
+	return s.commit()
+}
+
+// UpdateServer replaces the named server and saves the configuration. The
+// server may be renamed; its profiles and the servers jumping through it
+// follow the new name.
+func (s *Service) UpdateServer(name string, server config.Server) error {
+	existing, err := s.cfg.GetServer(name)
+	if err != nil {
+		return err
+	}
+	if err := s.cfg.ReplaceServer(existing.Name, server); err != nil {
+		return err
+	}
+	return s.commit()
+}
+
+// RemoveServer deletes the named server, or moves it to the archive, and
+// saves the configuration. Deleting it deletes the profiles it leaves empty.
+func (s *Service) RemoveServer(name string, archive bool) error {
+	var err error
+	if archive {
+		err = s.cfg.ArchiveServer(name, now())
+	} else {
+		err = s.cfg.DeleteServer(name)
+	}
+	if err != nil {
+		return err
+	}
+	return s.commit()
+}
+
+// AssignServer adds the server to the profile and saves the configuration
+func (s *Service) AssignServer(serverName, profileName string) error {
+	if err := s.cfg.AssignServerToProfile(serverName, profileName); err != nil {
+		return err
+	}
+	return s.commit()
+}
+
+// UnassignServer removes the server from the profile and saves the configuration
+func (s *Service) UnassignServer(serverName, profileName string) error {
+	if err := s.cfg.UnassignServerFromProfile(serverName, profileName); err != nil {
+		return err
+	}
+	return s.commit()
+}
+
+// AssignSuggested makes the suggested profile assignments and saves the
+// configuration once, returning how many servers were assigned. When one of
+// them fails none are made; when saving fails they stay made, unsaved.
+func (s *Service) AssignSuggested(suggestions []config.ProfileSuggestion) (int, error) {
+	if len(suggestions) == 0 {
+		return 0, nil
+	}
+	snapshot := s.snapshot()
+	for _, suggestion := range suggestions {
+		if err := s.cfg.AssignServerToProfile(suggestion.Server, suggestion.Profile); err != nil {
+			s.rollback(snapshot)
+			return 0, fmt.Errorf("failed to assign %s to %s: %w", suggestion.Server, suggestion.Profile, err)
+		}
+	}
+	return len(suggestions), s.commit()
+}
@@ -0,0 +1,73 @@
+// Package service holds the changes the TUI and the CLI make to the
+// configuration and to sshm's tmux sessions, so that adding a server, editing a
+// profile or cleaning up sessions behaves the same from either.
+package service
+
+import (
+	"fmt"
+	"slices"
+
+	"sshm/internal/config"
+)
+
+// Service applies changes to a loaded configuration and saves each one
+type Service struct {
+	cfg  *config.Config
+	save func() error
+}
+
+// New returns a service changing cfg and persisting it with save. The TUI
+// passes its own save, which reconciles changes made outside sshm.
+func New(cfg *config.Config, save func() error) *Service {
+	return &Service{cfg: cfg, save: save}
+}
+
+// Load loads the configuration and returns a service that saves it in place
+func Load() (*Service, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, err
+	}
+	return New(cfg, cfg.Save), nil
+}
+
+// Config returns the configuration the service changes
+func (s *Service) Config() *config.Config {
+	return s.cfg
+}
+
+// commit saves the configuration after a change
+func (s *Service) commit() error {
+	if err := s.save(); err != nil {
+		return fmt.Errorf("failed to save configuration: %w", err)
+	}
+	return nil
+}
+
+// inventory is a copy of the servers and profiles, put back when a change made
+// of several steps fails halfway
+type inventory struct {
+	servers  []config.Server
+	profiles []config.Profile
+}
+
+// snapshot copies the servers and profiles before a change of several steps
+func (s *Service) snapshot() inventory {
+	profiles := slices.Clone(s.cfg.Profiles)
+	for i := range profiles {
+		profiles[i].Servers = slices.Clone(profiles[i].Servers)
+	}
+	return inventory{servers: slices.Clone(s.cfg.Servers), profiles: profiles}
+}
+
+// rollback puts back the servers and profiles of a snapshot
+func (s *Service) rollback(snapshot inventory) {
+	s.cfg.Servers = snapshot.servers
+	s.cfg.Profiles = snapshot.profiles
+}
+
+// Save saves changes made directly on Config(), such as the profiles proposed
+// after an import
+func (s *Service) Save() error {
+	return s.commit()
+}
@@ -0,0 +1,217 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"sshm/internal/config"
+)
+
+func testServer(name string) config.Server {
+	return config.Server{Name: name, Hostname: name + ".example.com", Port: 22, Username: "ops", AuthType: "key", KeyPath: "~/.ssh/id"}
+}
+
+// newTestService returns a service over an in-memory configuration that
+// counts its saves
+func newTestService(saveErr error) (*Service, *int) {
+	cfg := &config.Config{
+		Servers:  []config.Server{testServer("web"), testServer("db")},
+		Profiles: []config.Profile{{Name: "prod", Description: "Production", Servers: []string{"web", "db"}, QuickStats: true}},
+	}
+	saves := 0
+	return New(cfg, func() error {
+		saves++
+		return saveErr
+	}), &saves
+}
+
+func TestServerChangesAreSaved(t *testing.T) {
+	svc, saves := newTestService(nil)
+
+	if err := svc.AddServer(testServer("cache")); err != nil {
+		t.Fatalf("AddServer failed: %v", err)
+	}
+	if err := svc.AddServer(testServer("cache")); err == nil {
+		t.Error("Expected adding a duplicate server to fail")
+	}
+	if err := svc.AddServer(testServer("queue"), "missing"); err == nil {
+		t.Error("Expected adding a server to an unknown profile to fail")
+	}
+	if _, err := svc.Config().GetServer("queue"); err == nil {
+		t.Error("Expected a failed add to leave the configuration unchanged")
+	}
+
+	bastion := testServer("bastion")
+	if err := svc.AddServer(bastion); err != nil {
+		t.Fatal(err)
+	}
+	jumping := testServer("internal")
+	jumping.ProxyJump = []string{"bastion"}
+	if err := svc.AddServer(jumping); err != nil {
+		t.Fatal(err)
+	}
+
+	bastion.Name = "jump"
+	if err := svc.UpdateServer("bastion", bastion); err != nil {
+		t.Fatalf("UpdateServer failed: %v", err)
+	}
+	internal, _ := svc.Config().GetServer("internal")
+	if len(internal.ProxyJump) != 1 || internal.ProxyJump[0] != "jump" {
+		t.Errorf("Expected the rename to carry over to jump references, got %v", internal.ProxyJump)
+	}
+
+	web := testServer("web-01")
+	if err := svc.UpdateServer("web", web); err != nil {
+		t.Fatal(err)
+	}
+	if profile, _ := svc.Config().GetProfile("prod"); profile.Servers[0] != "web-01" {
+		t.Errorf("Expected the rename to carry over to profiles, got %v", profile.Servers)
+	}
+
+	if err := svc.RemoveServer("db", true); err != nil {
+		t.Fatalf("RemoveServer with archive failed: %v", err)
+	}
+	if len(svc.Config().Archived) != 1 || svc.Config().Archived[0].Name != "db" {
+		t.Errorf("Expected db in the archive, got %+v", svc.Config().Archived)
+	}
+	if err := svc.RemoveServer("web-01", false); err != nil {
+		t.Fatalf("RemoveServer failed: %v", err)
+	}
+	if _, err := svc.Config().GetProfile("prod"); err == nil {
+		t.Error("Expected the profile left empty to be deleted")
+	}
+	if err := svc.RemoveServer("missing", false); err == nil {
+		t.Error("Expected removing an unknown server to fail")
+	}
+
+	if *saves != 7 {
+		t.Errorf("Expected a save per successful change, got %d", *saves)
+	}
+}
+
+func TestProfileChangesAreSaved(t *testing.T) {
+	svc, saves := newTestService(nil)
+
+	if err := svc.CreateProfile(config.Profile{Name: "staging"}); err != nil {
+		t.Fatalf("CreateProfile failed: %v", err)
+	}
+	if err := svc.AssignServer("web", "staging"); err != nil {
+		t.Fatalf("AssignServer failed: %v", err)
+	}
+	if err := svc.AssignServer("missing", "staging"); err == nil {
+		t.Error("Expected assigning an unknown server to fail")
+	}
+
+	// Editing keeps the settings the edit doesn't touch
+	prod, _ := svc.Config().GetProfile("prod")
+	edited := *prod
+	edited.Name = "production"
+	edited.Description = "Live"
+	if err := svc.UpdateProfile("prod", edited); err != nil {
+		t.Fatalf("UpdateProfile failed: %v", err)
+	}
+	production, err := svc.Config().GetProfile("production")
+	if err != nil || !production.QuickStats || len(production.Servers) != 2 {
+		t.Errorf("Expected the renamed profile to keep its settings, got %+v (%v)", production, err)
+	}
+	edited.Name = "staging"
+	if err := svc.UpdateProfile("production", edited); err == nil {
+		t.Error("Expected renaming onto an existing profile to fail")
+	}
+
+	if err := svc.UnassignServer("web", "staging"); err != nil {
+		t.Fatalf("UnassignServer failed: %v", err)
+	}
+	if err := svc.DeleteProfile("staging"); err != nil {
+		t.Fatalf("DeleteProfile failed: %v", err)
+	}
+	if _, err := svc.Config().GetServer("web"); err != nil {
+		t.Error("Expected deleting a profile to keep its servers")
+	}
+
+	if *saves != 5 {
+		t.Errorf("Expected a save per successful change, got %d", *saves)
+	}
+}
+
+func TestSaveErrorsAreReported(t *testing.T) {
+	diskFull := errors.New("disk full")
+	svc, _ := newTestService(diskFull)
+	if err := svc.CreateProfile(config.Profile{Name: "staging"}); !errors.Is(err, diskFull) {
+		t.Errorf("Expected the save error, got %v", err)
+	}
+}
+
+func TestImport(t *testing.T) {
+	svc, saves := newTestService(nil)
+
+	updated := testServer("web")
+	updated.Hostname = "web2.example.com"
+	clash := testServer("cache")
+	clash.Aliases = []string{"db"}
+	invalid := config.Server{Name: "broken"}
+
+	result, err := svc.Import(
+		[]config.Server{updated, testServer("api"), clash, invalid},
+		[]config.Profile{{Name: "prod", Servers: []string{"api"}}, {Name: "api", Servers: []string{"api"}}},
+	)
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	if result.Added != 1 || result.Updated != 1 || result.Profiles != 2 {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+	if len(result.Skipped) != 2 || result.Skipped[0].Name != "broken" || result.Skipped[1].Name != "cache" {
+		t.Errorf("Expected the invalid and conflicting servers to be skipped, got %+v", result.Skipped)
+	}
+	if web, _ := svc.Config().GetServer("web"); web.Hostname != "web2.example.com" {
+		t.Errorf("Expected web to be replaced, got %s", web.Hostname)
+	}
+	if prod, _ := svc.Config().GetProfile("prod"); len(prod.Servers) != 1 {
+		t.Errorf("Expected prod to be replaced, got %v", prod.Servers)
+	}
+	if *saves != 1 {
+		t.Errorf("Expected a single save, got %d", *saves)
+	}
+}
+
+func TestImportKeepsProfilesThatFailToImport(t *testing.T) {
+	svc, _ := newTestService(nil)
+
+	result, err := svc.Import(nil, []config.Profile{{Name: "prod", StealthWindow: "soon"}, {Name: "api"}})
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	if result.Profiles != 1 || len(result.SkippedProfiles) != 1 || result.SkippedProfiles[0].Name != "prod" {
+		t.Errorf("Expected only the invalid profile to be skipped, got %+v", result)
+	}
+	if prod, err := svc.Config().GetProfile("prod"); err != nil || len(prod.Servers) != 2 {
+		t.Errorf("Expected the existing prod profile to be kept, got %+v, %v", prod, err)
+	}
+}
+
+func TestAssignSuggested(t *testing.T) {
+	svc, saves := newTestService(nil)
+	if err := svc.CreateProfile(config.Profile{Name: "staging"}); err != nil {
+		t.Fatal(err)
+	}
+	*saves = 0
+
+	// A failing assignment leaves the others unmade
+	assigned, err := svc.AssignSuggested([]config.ProfileSuggestion{{Server: "web", Profile: "staging"}, {Server: "missing", Profile: "staging"}})
+	if err == nil || assigned != 0 {
+		t.Errorf("AssignSuggested() = %d, %v, want 0 and an error", assigned, err)
+	}
+	if staging, _ := svc.Config().GetProfile("staging"); len(staging.Servers) != 0 || *saves != 0 {
+		t.Errorf("Expected nothing assigned or saved, got %v and %d saves", staging.Servers, *saves)
+	}
+
+	// A failing save still reports the assignments made
+	diskFull := errors.New("disk full")
+	svc, _ = newTestService(diskFull)
+	svc.Config().Profiles = append(svc.Config().Profiles, config.Profile{Name: "staging", Servers: []string{}})
+	assigned, err = svc.AssignSuggested([]config.ProfileSuggestion{{Server: "web", Profile: "staging"}, {Server: "db", Profile: "staging"}})
+	if !errors.Is(err, diskFull) || assigned != 2 {
+		t.Errorf("AssignSuggested() = %d, %v, want 2 and the save error", assigned, err)
+	}
+}
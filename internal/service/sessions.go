@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"sshm/internal/tmux"
+)
+
+// ErrSessionNotFound is returned when killing a session tmux doesn't have
+var ErrSessionNotFound = errors.New("session not found")
+
+// OrphanedIdle is how long a detached session must be idle to be cleaned up
+const OrphanedIdle = 48 * time.Hour
+
+// Sessions is the part of the tmux manager that session operations use
+type Sessions interface {
+	ListSessions() ([]string, error)
+	SessionStates() ([]tmux.SessionState, error)
+	KillSession(sessionName string) error
+}
+
+// KillSession kills the named tmux session
+func KillSession(sessions Sessions, name string) error {
+	names, err := sessions.ListSessions()
+	if err != nil {
+		return fmt.Errorf("failed to list sessions: %w", err)
+	}
+	found := false
+	for _, session := range names {
+		if session == name {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return fmt.Errorf("%w: %s", ErrSessionNotFound, name)
+	}
+	return sessions.KillSession(name)
+}
+
+// Orphaned reports whether a session has no windows, or nobody is attached
+// and it has been idle for OrphanedIdle
+func Orphaned(state tmux.SessionState, now time.Time) bool {
+	if state.Windows == 0 {
+		return true
+	}
+	if state.Clients > 0 || state.Activity.IsZero() {
+		return false
+	}
+	return now.Sub(state.Activity) >= OrphanedIdle
+}
+
+// OrphanedSessions returns the names of the orphaned sessions
+func OrphanedSessions(sessions Sessions) ([]string, error) {
+	// Without a tmux server there are no sessions, but no states to read either
+	names, err := sessions.ListSessions()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+	if len(names) == 0 {
+		return nil, nil
+	}
+	states, err := sessions.SessionStates()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+	current := now()
+	var orphaned []string
+	for _, state := range states {
+		if Orphaned(state, current) {
+			orphaned = append(orphaned, state.Name)
+		}
+	}
+	return orphaned, nil
+}
+
+// CleanupResult is the outcome of a session cleanup
+type CleanupResult struct {
+	Killed []string          `json:"killed"`
+	Failed map[string]string `json:"failed,omitempty"` // Session name -> error
+}
+
+// CleanupSessions kills the named sessions, carrying on past the ones that
+// fail, and reports progress after each. It stops early when ctx is
+// cancelled, returning what was killed so far with the context's error.
+func CleanupSessions(ctx context.Context, sessions Sessions, names []string, progress func(done, total int)) (CleanupResult, error) {
+	result := CleanupResult{Killed: []string{}}
+	for i, name := range names {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		if progress != nil {
+			progress(i, len(names))
+		}
+		if err := sessions.KillSession(name); err != nil {
+			if result.Failed == nil {
+				result.Failed = make(map[string]string)
+			}
+			result.Failed[name] = err.Error()
+			continue
+		}
+		result.Killed = append(result.Killed, name)
+	}
+	return result, nil
+}
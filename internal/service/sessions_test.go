@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"sshm/internal/tmux"
+)
+
+type fakeSessions struct {
+	states  []tmux.SessionState
+	killed  []string
+	failing map[string]bool
+}
+
+func (f *fakeSessions) ListSessions() ([]string, error) {
+	var names []string
+	for _, state := range f.states {
+		names = append(names, state.Name)
+	}
+	return names, nil
+}
+
+func (f *fakeSessions) SessionStates() ([]tmux.SessionState, error) { return f.states, nil }
+
+func (f *fakeSessions) KillSession(name string) error {
+	if f.failing[name] {
+		return errors.New("no such session")
+	}
+	f.killed = append(f.killed, name)
+	return nil
+}
+
+func TestKillSession(t *testing.T) {
+	sessions := &fakeSessions{states: []tmux.SessionState{{Name: "web"}}}
+	if err := KillSession(sessions, "web"); err != nil {
+		t.Fatalf("KillSession failed: %v", err)
+	}
+	if err := KillSession(sessions, "db"); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("Expected ErrSessionNotFound, got %v", err)
+	}
+	if !reflect.DeepEqual(sessions.killed, []string{"web"}) {
+		t.Errorf("Expected only web to be killed, got %v", sessions.killed)
+	}
+}
+
+func TestOrphanRule(t *testing.T) {
+	current := time.Now()
+	testCases := []struct {
+		state  tmux.SessionState
+		orphan bool
+	}{
+		{tmux.SessionState{Name: "empty", Windows: 0, Activity: current}, true},
+		{tmux.SessionState{Name: "idle", Windows: 1, Activity: current.Add(-72 * time.Hour)}, true},
+		{tmux.SessionState{Name: "attached", Windows: 1, Clients: 1, Activity: current.Add(-72 * time.Hour)}, false},
+		{tmux.SessionState{Name: "recent", Windows: 2, Activity: current.Add(-time.Hour)}, false},
+		{tmux.SessionState{Name: "unknown", Windows: 1}, false},
+	}
+	for _, tc := range testCases {
+		if got := Orphaned(tc.state, current); got != tc.orphan {
+			t.Errorf("Orphaned(%s) = %v, want %v", tc.state.Name, got, tc.orphan)
+		}
+	}
+}
+
+func TestCleanupSessions(t *testing.T) {
+	defer func(original func() time.Time) { now = original }(now)
+	current := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	now = func() time.Time { return current }
+
+	sessions := &fakeSessions{
+		states: []tmux.SessionState{
+			{Name: "old", Windows: 1, Activity: current.Add(-72 * time.Hour)},
+			{Name: "live", Windows: 1, Clients: 1, Activity: current},
+			{Name: "empty"},
+		},
+		failing: map[string]bool{"empty": true},
+	}
+	names, err := OrphanedSessions(sessions)
+	if err != nil || !reflect.DeepEqual(names, []string{"old", "empty"}) {
+		t.Fatalf("Expected old and empty to be orphaned, got %v (%v)", names, err)
+	}
+
+	result, err := CleanupSessions(context.Background(), sessions, names, nil)
+	if err != nil {
+		t.Fatalf("CleanupSessions failed: %v", err)
+	}
+	if !reflect.DeepEqual(result.Killed, []string{"old"}) || result.Failed["empty"] == "" {
+		t.Errorf("Expected old killed and empty failed, got %+v", result)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if result, err := CleanupSessions(ctx, sessions, names, nil); !errors.Is(err, context.Canceled) || len(result.Killed) != 0 {
+		t.Errorf("Expected a cancelled cleanup to stop before killing, got %+v (%v)", result, err)
+	}
+}
@@ -0,0 +1,121 @@
+package shellalias
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"sshm/internal/config"
+	"sshm/internal/shellquote"
+)
+
+// Supported shells
+const (
+	ShellBash = "bash" // Also used for zsh and other POSIX shells
+	ShellFish = "fish"
+)
+
+// DefaultPrefix is prepended to server names to form alias names
+const DefaultPrefix = "ssh-"
+
+// DefaultOutput is where the alias file is written when no path is configured
+const DefaultOutput = "~/.sshm/aliases.sh"
+
+// unsafeNameChars matches characters not allowed in alias names
+var unsafeNameChars = regexp.MustCompile(`[^A-Za-z0-9_-]+`)
+
+// AliasName returns the shell alias name for a server
+func AliasName(prefix, serverName string) string {
+	return prefix + strings.Trim(unsafeNameChars.ReplaceAllString(serverName, "-"), "-")
+}
+
+// Generate renders a sourceable file with one alias (or function) per server
+func Generate(servers []config.Server, settings config.ShellAliasConfig) (string, error) {
+	shell := settings.Shell
+	if shell == "" || shell == "zsh" || shell == "sh" {
+		shell = ShellBash
+	}
+	if shell != ShellBash && shell != ShellFish {
+		return "", fmt.Errorf("unsupported shell '%s' (expected bash, zsh or fish)", settings.Shell)
+	}
+	prefix := settings.Prefix
+	if prefix == "" {
+		prefix = DefaultPrefix
+	}
+
+	var b strings.Builder
+	b.WriteString("# Generated by sshm. Do not edit: run 'sshm alias sync' to update.\n")
+	fmt.Fprintf(&b, "# Source this file from your shell startup file.\n\n")
+
+	seen := make(map[string]string)
+	for _, server := range servers {
+		name := AliasName(prefix, server.Name)
+		if other, exists := seen[name]; exists {
+			return "", fmt.Errorf("servers '%s' and '%s' both map to alias '%s'", other, server.Name, name)
+		}
+		seen[name] = server.Name
+
+		target := shellquote.Quote(server.Name)
+		switch {
+		case shell == ShellFish && settings.Functions:
+			fmt.Fprintf(&b, "function %s\n    sshm connect %s $argv\nend\n", name, target)
+		case shell == ShellFish:
+			fmt.Fprintf(&b, "alias %s %s\n", name, shellquote.Quote("sshm connect "+target))
+		case settings.Functions:
+			fmt.Fprintf(&b, "%s() { sshm connect %s \"$@\"; }\n", name, target)
+		default:
+			fmt.Fprintf(&b, "alias %s=%s\n", name, shellquote.Quote("sshm connect "+target))
+		}
+	}
+	return b.String(), nil
+}
+
+// SelectServers returns the configured servers, or all servers when none are selected
+func SelectServers(cfg *config.Config, names []string) ([]config.Server, error) {
+	if len(names) == 0 {
+		return cfg.GetServers(), nil
+	}
+
+	servers := make([]config.Server, 0, len(names))
+	for _, name := range names {
+		server, err := cfg.GetServer(name)
+		if err != nil {
+			return nil, err
+		}
+		servers = append(servers, *server)
+	}
+	return servers, nil
+}
+
+// Write generates the alias file for the configuration's alias settings and
+// returns its path and the number of aliases written
+func Write(cfg *config.Config) (string, int, error) {
+	settings := cfg.ShellAliases
+	servers, err := SelectServers(cfg, settings.Servers)
+	if err != nil {
+		return "", 0, err
+	}
+
+	content, err := Generate(servers, settings)
+	if err != nil {
+		return "", 0, err
+	}
+
+	output := settings.Output
+	if output == "" {
+		output = DefaultOutput
+	}
+	path, err := config.ExpandPath(output)
+	if err != nil {
+		return "", 0, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return "", 0, fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return "", 0, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return path, len(servers), nil
+}
@@ -0,0 +1,73 @@
+package shellalias
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"sshm/internal/config"
+)
+
+func TestGenerateAliases(t *testing.T) {
+	servers := []config.Server{{Name: "prod-web1"}, {Name: "db.primary"}}
+
+	content, err := Generate(servers, config.ShellAliasConfig{})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	for _, want := range []string{
+		`alias ssh-prod-web1='sshm connect '\''prod-web1'\'''`,
+		`alias ssh-db-primary=`,
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("Expected %q in:\n%s", want, content)
+		}
+	}
+}
+
+func TestGenerateFunctionsAndFish(t *testing.T) {
+	servers := []config.Server{{Name: "web1"}}
+
+	content, _ := Generate(servers, config.ShellAliasConfig{Prefix: "s-", Functions: true})
+	if !strings.Contains(content, `s-web1() { sshm connect 'web1' "$@"; }`) {
+		t.Errorf("Unexpected function output:\n%s", content)
+	}
+
+	content, _ = Generate(servers, config.ShellAliasConfig{Shell: "fish", Functions: true})
+	if !strings.Contains(content, "function ssh-web1\n    sshm connect 'web1' $argv\nend") {
+		t.Errorf("Unexpected fish output:\n%s", content)
+	}
+
+	if _, err := Generate(servers, config.ShellAliasConfig{Shell: "powershell"}); err == nil {
+		t.Error("Expected error for unsupported shell")
+	}
+}
+
+func TestGenerateDetectsCollisions(t *testing.T) {
+	servers := []config.Server{{Name: "web.1"}, {Name: "web-1"}}
+	if _, err := Generate(servers, config.ShellAliasConfig{}); err == nil {
+		t.Error("Expected error when two servers map to the same alias")
+	}
+}
+
+func TestWriteSelectedServers(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "aliases.sh")
+	cfg := &config.Config{
+		Servers:      []config.Server{{Name: "web1"}, {Name: "web2"}},
+		ShellAliases: config.ShellAliasConfig{Output: output, Servers: []string{"web2"}},
+	}
+
+	path, count, err := Write(cfg)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if path != output || count != 1 {
+		t.Errorf("Unexpected result: %s, %d", path, count)
+	}
+
+	data, _ := os.ReadFile(output)
+	if strings.Contains(string(data), "web1") || !strings.Contains(string(data), "ssh-web2") {
+		t.Errorf("Unexpected alias file:\n%s", data)
+	}
+}
@@ -0,0 +1,33 @@
+// Package shellquote quotes values for POSIX shells, for the commands sshm
+// types into tmux windows, runs with sh -c or sends to remote servers.
+package shellquote
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Quote wraps value in single quotes so the shell takes it literally
+func Quote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+var plainWord = regexp.MustCompile(`^[a-zA-Z0-9_@%+=:,./-]+$`)
+
+// Arg quotes value unless the shell takes it literally anyway, keeping
+// commands shown to the user readable
+func Arg(value string) string {
+	if plainWord.MatchString(value) {
+		return value
+	}
+	return Quote(value)
+}
+
+// Join quotes each value with Arg and joins them with spaces
+func Join(values []string) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = Arg(value)
+	}
+	return strings.Join(quoted, " ")
+}
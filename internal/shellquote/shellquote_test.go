@@ -0,0 +1,30 @@
+package shellquote
+
+import (
+	"os/exec"
+	"testing"
+)
+
+func TestQuoteRoundTrips(t *testing.T) {
+	values := []string{"plain", "with space", "it's", "$(reboot)", "a;b", "~/.ssh/id", "", `back\slash "double"`}
+	for _, value := range values {
+		for name, quote := range map[string]func(string) string{"Quote": Quote, "Arg": Arg} {
+			out, err := exec.Command("sh", "-c", "printf %s "+quote(value)).Output()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(out) != value {
+				t.Errorf("%s(%q) reads back as %q", name, value, out)
+			}
+		}
+	}
+}
+
+func TestArgLeavesPlainWords(t *testing.T) {
+	if got := Arg("/tmp/sshm-share.sock"); got != "/tmp/sshm-share.sock" {
+		t.Errorf("Arg() = %q, want the word unquoted", got)
+	}
+	if got := Join([]string{"ssh", "-t", "ops@web 1"}); got != "ssh -t 'ops@web 1'" {
+		t.Errorf("Join() = %q", got)
+	}
+}
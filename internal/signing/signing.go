@@ -0,0 +1,164 @@
+package signing
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// SignatureSuffix is appended to an exported file's path for its detached signature
+const SignatureSuffix = ".asc"
+
+// Signature verification policies for imports
+const (
+	PolicyAuto    = "auto"    // Verify when a signature exists; reject invalid signatures
+	PolicyWarn    = "warn"    // Verify when a signature exists; only warn on invalid signatures
+	PolicyRequire = "require" // Reject unsigned files and invalid signatures
+	PolicyOff     = "off"     // Never verify
+)
+
+// Policies lists the supported verification policies
+var Policies = []string{PolicyAuto, PolicyWarn, PolicyRequire, PolicyOff}
+
+// ErrNoSignature is returned when a file has no detached signature next to it
+var ErrNoSignature = errors.New("no signature found")
+
+// gpgBinary is the GnuPG executable used for signing and verification
+var gpgBinary = "gpg"
+
+// execCommand is a variable to allow mocking in tests
+var execCommand = exec.Command
+
+// Result describes the outcome of a signature verification
+type Result struct {
+	Valid       bool
+	KeyID       string
+	Signer      string // User ID of the signing key, when known
+	Fingerprint string
+	Reason      string // Why the signature is not valid
+}
+
+// SignaturePath returns the path of the detached signature for path
+func SignaturePath(path string) string {
+	return path + SignatureSuffix
+}
+
+// Sign writes an ASCII-armored detached signature for path to SignaturePath(path),
+// using keyID or gpg's default key when empty, and returns the signature path
+func Sign(path, keyID string) (string, error) {
+	sigPath := SignaturePath(path)
+	args := []string{"--batch", "--yes", "--armor", "--detach-sign", "--output", sigPath}
+	if keyID != "" {
+		args = append(args, "--local-user", keyID)
+	}
+	args = append(args, path)
+
+	cmd := execCommand(gpgBinary, args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("gpg signing failed: %s", gpgError(err, stderr.String()))
+	}
+	return sigPath, nil
+}
+
+// Verify checks the detached signature next to path. It returns ErrNoSignature when
+// there is none; otherwise the result reports whether the signature is good.
+func Verify(path string) (*Result, error) {
+	sigPath := SignaturePath(path)
+	if _, err := os.Stat(sigPath); os.IsNotExist(err) {
+		return nil, ErrNoSignature
+	}
+
+	cmd := execCommand(gpgBinary, "--batch", "--status-fd", "1", "--verify", sigPath, path)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	runErr := cmd.Run()
+
+	result := parseVerifyStatus(stdout.String())
+	if runErr != nil && result.Valid {
+		// gpg failed after reporting a good signature; don't trust it
+		result.Valid = false
+		result.Reason = gpgError(runErr, stderr.String())
+	}
+	if runErr != nil && result.Reason == "" {
+		if _, ok := runErr.(*exec.ExitError); !ok {
+			return nil, fmt.Errorf("failed to run gpg: %w", runErr)
+		}
+		result.Reason = gpgError(runErr, stderr.String())
+	}
+	return result, nil
+}
+
+// parseVerifyStatus interprets gpg --status-fd output. Only GOODSIG together with
+// VALIDSIG counts as valid; expired or revoked keys and bad signatures do not.
+func parseVerifyStatus(status string) *Result {
+	result := &Result{}
+	var good, validSig bool
+
+	scanner := bufio.NewScanner(strings.NewReader(status))
+	for scanner.Scan() {
+		fields := strings.Fields(strings.TrimPrefix(scanner.Text(), "[GNUPG:] "))
+		if len(fields) == 0 {
+			continue
+		}
+		switch fields[0] {
+		case "GOODSIG":
+			good = true
+			result.KeyID, result.Signer = statusKeyAndUser(fields)
+		case "VALIDSIG":
+			validSig = true
+			if len(fields) > 1 {
+				result.Fingerprint = fields[1]
+			}
+		case "BADSIG":
+			result.KeyID, result.Signer = statusKeyAndUser(fields)
+			result.Reason = "bad signature, the file was modified after signing"
+		case "EXPKEYSIG":
+			result.KeyID, result.Signer = statusKeyAndUser(fields)
+			result.Reason = "signed with an expired key"
+		case "REVKEYSIG":
+			result.KeyID, result.Signer = statusKeyAndUser(fields)
+			result.Reason = "signed with a revoked key"
+		case "NO_PUBKEY":
+			if len(fields) > 1 {
+				result.KeyID = fields[1]
+			}
+			result.Reason = "the signing key is not in your keyring"
+		case "ERRSIG":
+			if result.Reason == "" {
+				result.Reason = "the signature could not be checked"
+			}
+		case "NODATA":
+			result.Reason = "the signature file is not a valid signature"
+		}
+	}
+
+	result.Valid = good && validSig && result.Reason == ""
+	if !result.Valid && result.Reason == "" {
+		result.Reason = "no valid signature"
+	}
+	return result
+}
+
+// statusKeyAndUser returns the key ID and user ID of a GOODSIG/BADSIG style status line
+func statusKeyAndUser(fields []string) (string, string) {
+	if len(fields) < 2 {
+		return "", ""
+	}
+	return fields[1], strings.Join(fields[2:], " ")
+}
+
+// gpgError combines a gpg process error with the last line it printed
+func gpgError(err error, stderr string) string {
+	lines := strings.Split(strings.TrimSpace(stderr), "\n")
+	if last := strings.TrimSpace(lines[len(lines)-1]); last != "" {
+		return strings.TrimPrefix(last, "gpg: ")
+	}
+	return err.Error()
+}
@@ -0,0 +1,95 @@
+package signing
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func mockExec(t *testing.T, captured *[]string, name string, args ...string) {
+	original := execCommand
+	execCommand = func(cmdName string, arg ...string) *exec.Cmd {
+		*captured = append([]string{cmdName}, arg...)
+		return exec.Command(name, args...)
+	}
+	t.Cleanup(func() { execCommand = original })
+}
+
+func TestParseVerifyStatus(t *testing.T) {
+	good := parseVerifyStatus(`[GNUPG:] NEWSIG
+[GNUPG:] GOODSIG 0123456789ABCDEF Ops Team <ops@example.com>
+[GNUPG:] VALIDSIG FINGERPRINT0123 2026-01-01 1767225600 0 4 0 22 8 00 FINGERPRINT0123
+`)
+	if !good.Valid || good.KeyID != "0123456789ABCDEF" || good.Signer != "Ops Team <ops@example.com>" || good.Fingerprint != "FINGERPRINT0123" {
+		t.Errorf("Unexpected result for good signature: %+v", good)
+	}
+
+	tests := map[string]string{
+		"[GNUPG:] BADSIG 0123456789ABCDEF Ops Team":                                                  "modified after signing",
+		"[GNUPG:] ERRSIG 0123456789ABCDEF 22 8 00 1767225600 9\n[GNUPG:] NO_PUBKEY 0123456789ABCDEF": "not in your keyring",
+		"[GNUPG:] EXPKEYSIG 0123456789ABCDEF Ops Team\n[GNUPG:] VALIDSIG FPR":                        "expired key",
+		"[GNUPG:] NODATA 1": "not a valid signature",
+		"":                  "no valid signature",
+	}
+	for status, reason := range tests {
+		result := parseVerifyStatus(status)
+		if result.Valid || !strings.Contains(result.Reason, reason) {
+			t.Errorf("parseVerifyStatus(%q) = %+v, want invalid with reason %q", status, result, reason)
+		}
+	}
+}
+
+func TestVerifyWithoutSignature(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "servers.yaml")
+	if err := os.WriteFile(path, []byte("servers: []\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Verify(path); !errors.Is(err, ErrNoSignature) {
+		t.Errorf("Expected ErrNoSignature, got %v", err)
+	}
+}
+
+func TestVerifyRunsGPG(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "servers.yaml")
+	for _, file := range []string{path, SignaturePath(path)} {
+		if err := os.WriteFile(file, []byte("data"), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var captured []string
+	mockExec(t, &captured, "echo", "[GNUPG:] GOODSIG ABCDEF Ops\n[GNUPG:] VALIDSIG FPR")
+	result, err := Verify(path)
+	if err != nil || !result.Valid {
+		t.Fatalf("Expected valid signature, got %+v, %v", result, err)
+	}
+	want := []string{"gpg", "--batch", "--status-fd", "1", "--verify", SignaturePath(path), path}
+	if strings.Join(captured, " ") != strings.Join(want, " ") {
+		t.Errorf("Expected command %v, got %v", want, captured)
+	}
+
+	mockExec(t, &captured, "sh", "-c", "echo '[GNUPG:] GOODSIG ABCDEF Ops'; echo '[GNUPG:] VALIDSIG FPR'; exit 2")
+	if result, err := Verify(path); err != nil || result.Valid {
+		t.Errorf("A failing gpg run must never be reported as valid, got %+v, %v", result, err)
+	}
+}
+
+func TestSignCommand(t *testing.T) {
+	var captured []string
+	mockExec(t, &captured, "true")
+	sigPath, err := Sign("/tmp/servers.yaml", "ops@example.com")
+	if err != nil || sigPath != "/tmp/servers.yaml.asc" {
+		t.Fatalf("Unexpected result %q, %v", sigPath, err)
+	}
+	if !strings.Contains(strings.Join(captured, " "), "--detach-sign --output /tmp/servers.yaml.asc --local-user ops@example.com /tmp/servers.yaml") {
+		t.Errorf("Unexpected gpg arguments: %v", captured)
+	}
+
+	mockExec(t, &captured, "sh", "-c", "echo 'gpg: signing failed: No secret key' >&2; exit 2")
+	if _, err := Sign("/tmp/servers.yaml", ""); err == nil || !strings.Contains(err.Error(), "No secret key") {
+		t.Errorf("Expected gpg error to be reported, got %v", err)
+	}
+}
@@ -0,0 +1,139 @@
+package ssh
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"strings"
+	"syscall"
+
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// Kinds of connection failures. Errors returned while connecting wrap one of
+// them when the cause is known, so callers test them with errors.Is instead of
+// matching error text.
+var (
+	ErrTimeout     = errors.New("connection timed out")
+	ErrUnreachable = errors.New("host unreachable")
+	ErrRefused     = errors.New("connection refused")
+	ErrAuth        = errors.New("authentication failed")
+	ErrHostKey     = errors.New("host key verification failed")
+)
+
+// ConnectError is a failed connection whose cause is one of the error kinds
+type ConnectError struct {
+	Kind error // ErrTimeout, ErrUnreachable, ErrRefused, ErrAuth or ErrHostKey
+	Err  error // The underlying error
+}
+
+// Error implements the error interface
+func (e *ConnectError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the kind and the underlying error, so errors.Is and errors.As
+// match either
+func (e *ConnectError) Unwrap() []error {
+	return []error{e.Kind, e.Err}
+}
+
+// Classify returns err wrapped in a ConnectError when its cause is known, and
+// err itself otherwise
+func Classify(err error) error {
+	if err == nil {
+		return nil
+	}
+	var connectErr *ConnectError
+	if errors.As(err, &connectErr) {
+		return err
+	}
+	kind := kindOf(err)
+	if kind == nil {
+		kind = kindOfMessage(strings.ToLower(err.Error()))
+	}
+	if kind != nil {
+		return &ConnectError{Kind: kind, Err: err}
+	}
+	return err
+}
+
+// dialError classifies a failure to open the connection from the type of the
+// error alone
+func dialError(err error) error {
+	if kind := kindOf(err); kind != nil {
+		return &ConnectError{Kind: kind, Err: err}
+	}
+	return err
+}
+
+// handshakeError classifies a failed SSH handshake. Host key mismatches come as
+// knownhosts errors; once the host key was accepted, what is left to fail is
+// authentication, which x/crypto/ssh reports as a plain error.
+func handshakeError(err error, hostKeyAccepted bool) error {
+	if kind := kindOf(err); kind != nil {
+		return &ConnectError{Kind: kind, Err: err}
+	}
+	if hostKeyAccepted {
+		return &ConnectError{Kind: ErrAuth, Err: err}
+	}
+	return err
+}
+
+// kindOf finds the cause of a connection failure from the types it wraps, or
+// returns nil when none tells
+func kindOf(err error) error {
+	var netErr net.Error
+	var keyErr *knownhosts.KeyError
+	var revokedErr *knownhosts.RevokedError
+	switch {
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, os.ErrDeadlineExceeded),
+		errors.As(err, &netErr) && netErr.Timeout():
+		return ErrTimeout
+	case errors.Is(err, syscall.EHOSTUNREACH), errors.Is(err, syscall.ENETUNREACH):
+		return ErrUnreachable
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return ErrRefused
+	case errors.As(err, &keyErr), errors.As(err, &revokedErr):
+		return ErrHostKey
+	}
+	return nil
+}
+
+// kindOfMessage classifies failures that only come as text, like the standard
+// error of the ssh command. A changed host key is checked first: ssh mentions
+// authentication when it disables it because of one.
+func kindOfMessage(message string) error {
+	switch {
+	case strings.Contains(message, "host key verification failed") || strings.Contains(message, "remote host identification has changed"):
+		return ErrHostKey
+	case strings.Contains(message, "timeout") || strings.Contains(message, "timed out"):
+		return ErrTimeout
+	case strings.Contains(message, "no route"):
+		return ErrUnreachable
+	case strings.Contains(message, "unable to authenticate") || strings.Contains(message, "authentication") ||
+		strings.Contains(message, "permission denied"):
+		return ErrAuth
+	case strings.Contains(message, "connection refused"):
+		return ErrRefused
+	}
+	return nil
+}
+
+// Hint suggests what to do about a connection failure, or returns "" when
+// its cause is unknown
+func Hint(err error) string {
+	err = Classify(err)
+	switch {
+	case errors.Is(err, ErrAuth):
+		return "Check the username and the password or key the server accepts"
+	case errors.Is(err, ErrRefused):
+		return "Nothing accepts connections on the SSH port; check the port and that sshd is running"
+	case errors.Is(err, ErrTimeout), errors.Is(err, ErrUnreachable):
+		return "The host did not answer; check the hostname, the network or VPN, and firewalls"
+	case errors.Is(err, ErrHostKey):
+		return "The host key changed; compare it with 'sshm hostkey check' before trusting it"
+	}
+	return ""
+}
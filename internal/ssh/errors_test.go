@@ -0,0 +1,111 @@
+package ssh
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+func TestClassifyWrapsKnownCauses(t *testing.T) {
+	refused := &net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}
+	timeout := &net.OpError{Op: "dial", Net: "tcp", Err: os.ErrDeadlineExceeded}
+	unreachable := &net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.EHOSTUNREACH)}
+
+	tests := []struct {
+		err    error
+		kind   error
+		status string
+	}{
+		{fmt.Errorf("failed to connect to 10.0.0.1:22: %w", refused), ErrRefused, StatusRefused},
+		{timeout, ErrTimeout, StatusUnreachable},
+		{unreachable, ErrUnreachable, StatusUnreachable},
+		{&knownhosts.KeyError{Want: []knownhosts.KnownKey{{Filename: "known_hosts", Line: 3}}}, ErrHostKey, StatusHostKey},
+		{errors.New("ssh: handshake failed: ssh: unable to authenticate, attempted methods [none password], no supported methods remain"), ErrAuth, StatusAuthFailed},
+		{errors.New("Host key verification failed."), ErrHostKey, StatusHostKey},
+		{fmt.Errorf("connection test timed out after 5s"), ErrTimeout, StatusUnreachable},
+	}
+	for _, tt := range tests {
+		err := Classify(tt.err)
+		if !errors.Is(err, tt.kind) {
+			t.Errorf("Classify(%v) is not %v", tt.err, tt.kind)
+		}
+		if !errors.Is(err, tt.err) || err.Error() != tt.err.Error() {
+			t.Errorf("Expected Classify(%v) to keep the underlying error, got %v", tt.err, err)
+		}
+		if got := ClassifyError(tt.err); got != tt.status {
+			t.Errorf("ClassifyError(%v) = %q, want %q", tt.err, got, tt.status)
+		}
+	}
+
+	// The syscall error is still reachable through the classification
+	var opErr *net.OpError
+	if !errors.As(Classify(refused), &opErr) {
+		t.Error("Expected errors.As to reach the underlying *net.OpError")
+	}
+	other := errors.New("something else")
+	if Classify(other) != other || Classify(nil) != nil {
+		t.Error("Expected unknown causes to be returned as they are")
+	}
+}
+
+func TestConnectReturnsTypedErrors(t *testing.T) {
+	// A closed port refuses the connection
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	closedPort := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	client := NewClient(ClientConfig{Hostname: "127.0.0.1", Port: closedPort, Username: "ops", Timeout: 5 * time.Second})
+	if err := client.Connect(NewPasswordAuth("secret")); !errors.Is(err, ErrRefused) {
+		t.Errorf("Expected ErrRefused from a closed port, got %v", err)
+	}
+
+	port, _ := startJumpServer(t)
+	client = NewClient(ClientConfig{Hostname: "127.0.0.1", Port: port, Username: "ops", Timeout: 5 * time.Second})
+	if err := client.Connect(NewPasswordAuth("wrong")); !errors.Is(err, ErrAuth) {
+		t.Errorf("Expected ErrAuth for a rejected password, got %v", err)
+	}
+}
+
+func TestClassifyPrefersHostKeyInMixedMessages(t *testing.T) {
+	// ssh disables password authentication when the host key changed, and says so
+	stderr := errors.New("@@@ WARNING: REMOTE HOST IDENTIFICATION HAS CHANGED! @@@\n" +
+		"Password authentication is disabled to avoid man-in-the-middle attacks.\n" +
+		"Permission denied (publickey).\nHost key verification failed.")
+	if err := Classify(stderr); !errors.Is(err, ErrHostKey) || errors.Is(err, ErrAuth) {
+		t.Errorf("Classify(%q) = %v, want only ErrHostKey", stderr, err)
+	}
+}
+
+func TestHandshakeErrorUsesHowFarItGot(t *testing.T) {
+	// The wording doesn't matter once the host key was accepted
+	disconnect := errors.New("ssh: handshake failed: EOF")
+	if err := handshakeError(disconnect, true); !errors.Is(err, ErrAuth) {
+		t.Errorf("Expected ErrAuth after the host key was accepted, got %v", err)
+	}
+	if err := handshakeError(disconnect, false); err != disconnect {
+		t.Errorf("Expected an unknown cause before the host key was accepted, got %v", err)
+	}
+
+	keyErr := fmt.Errorf("ssh: handshake failed: %w", &knownhosts.KeyError{Want: []knownhosts.KnownKey{{Filename: "known_hosts", Line: 3}}})
+	if err := handshakeError(keyErr, false); !errors.Is(err, ErrHostKey) {
+		t.Errorf("Expected ErrHostKey for a mismatched key, got %v", err)
+	}
+
+	// Dial failures are told apart by their type, not their text
+	refused := &net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}
+	if err := dialError(refused); !errors.Is(err, ErrRefused) {
+		t.Errorf("Expected ErrRefused, got %v", err)
+	}
+	if err := dialError(errors.New("permission denied")); errors.Is(err, ErrAuth) {
+		t.Errorf("Expected dial errors not to be classified from their text, got %v", err)
+	}
+}
@@ -0,0 +1,198 @@
+package ssh
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// startJumpServer runs an SSH server that forwards direct-tcpip channels, as a
+// bastion does for ssh -J, counting the forwards
+func startJumpServer(t *testing.T) (int, *int32) {
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := ssh.NewSignerFromKey(private)
+	if err != nil {
+		t.Fatal(err)
+	}
+	serverConfig := &ssh.ServerConfig{
+		PasswordCallback: func(conn ssh.ConnMetadata, password []byte) (*ssh.Permissions, error) {
+			if string(password) != "bastion" {
+				return nil, fmt.Errorf("wrong password")
+			}
+			return nil, nil
+		},
+	}
+	serverConfig.AddHostKey(signer)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	var forwards int32
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				_, channels, requests, err := ssh.NewServerConn(conn, serverConfig)
+				if err != nil {
+					return
+				}
+				go ssh.DiscardRequests(requests)
+				for newChannel := range channels {
+					var target struct {
+						Host     string
+						Port     uint32
+						OrigHost string
+						OrigPort uint32
+					}
+					if newChannel.ChannelType() != "direct-tcpip" || ssh.Unmarshal(newChannel.ExtraData(), &target) != nil {
+						newChannel.Reject(ssh.UnknownChannelType, "only forwarding")
+						continue
+					}
+					upstream, err := net.Dial("tcp", net.JoinHostPort(target.Host, fmt.Sprint(target.Port)))
+					if err != nil {
+						newChannel.Reject(ssh.ConnectionFailed, err.Error())
+						continue
+					}
+					channel, channelRequests, err := newChannel.Accept()
+					if err != nil {
+						upstream.Close()
+						continue
+					}
+					atomic.AddInt32(&forwards, 1)
+					go ssh.DiscardRequests(channelRequests)
+					go func() {
+						io.Copy(channel, upstream)
+						channel.Close()
+					}()
+					go func() {
+						io.Copy(upstream, channel)
+						upstream.Close()
+					}()
+				}
+			}()
+		}
+	}()
+	return listener.Addr().(*net.TCPAddr).Port, &forwards
+}
+
+func TestProbeThroughJumpHosts(t *testing.T) {
+	targetPort, logins := startTestServer(t)
+	firstPort, firstForwards := startJumpServer(t)
+	secondPort, secondForwards := startJumpServer(t)
+
+	config := ClientConfig{
+		Hostname: "127.0.0.1",
+		Port:     targetPort,
+		Username: "ops",
+		Timeout:  5 * time.Second,
+		Jumps: []JumpHop{
+			{Hostname: "127.0.0.1", Port: firstPort, Username: "jump", Auth: NewPasswordAuth("bastion")},
+			{Hostname: "127.0.0.1", Port: secondPort, Username: "jump", Auth: NewPasswordAuth("bastion")},
+		},
+	}
+	result, err := ProbeConnectionDetails(config, NewPasswordAuth("secret"))
+	if err != nil {
+		t.Fatalf("ProbeConnectionDetails() error = %v", err)
+	}
+	if atomic.LoadInt32(logins) != 1 {
+		t.Errorf("Expected the target to authenticate once, got %d", atomic.LoadInt32(logins))
+	}
+	// The first hop forwards to the second, which forwards to the target
+	if atomic.LoadInt32(firstForwards) != 1 || atomic.LoadInt32(secondForwards) != 1 {
+		t.Errorf("Expected one forward per hop, got %d and %d", atomic.LoadInt32(firstForwards), atomic.LoadInt32(secondForwards))
+	}
+	if result.Address != "" {
+		t.Errorf("Expected no address through jump hosts, got %q", result.Address)
+	}
+	if result.ServerVersion == "" {
+		t.Error("Expected the target's version")
+	}
+}
+
+func TestProbeThroughJumpHostAuthFailure(t *testing.T) {
+	targetPort, logins := startTestServer(t)
+	jumpPort, _ := startJumpServer(t)
+
+	config := ClientConfig{
+		Hostname: "127.0.0.1",
+		Port:     targetPort,
+		Username: "ops",
+		Timeout:  5 * time.Second,
+		Jumps:    []JumpHop{{Hostname: "127.0.0.1", Port: jumpPort, Username: "jump", Auth: NewPasswordAuth("wrong")}},
+	}
+	_, err := ProbeConnectionDetails(config, NewPasswordAuth("secret"))
+	if err == nil {
+		t.Fatal("Expected the jump host to reject the password")
+	}
+	if ClassifyError(err) != StatusAuthFailed {
+		t.Errorf("ClassifyError() = %s for %v", ClassifyError(err), err)
+	}
+	if atomic.LoadInt32(logins) != 0 {
+		t.Error("Expected the target never to be reached")
+	}
+}
+
+func TestProbeThroughJumpHostWithChangedKey(t *testing.T) {
+	targetPort, logins := startTestServer(t)
+	jumpPort, forwards := startJumpServer(t)
+
+	// known_hosts has another key for the bastion, under its alias
+	_, otherPrivate, _ := ed25519.GenerateKey(rand.Reader)
+	otherKey, err := ssh.NewPublicKey(otherPrivate.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	knownHosts := filepath.Join(t.TempDir(), "known_hosts")
+	line := knownhosts.Line([]string{knownhosts.Normalize(net.JoinHostPort("bastion.example.com", fmt.Sprint(jumpPort)))}, otherKey)
+	if err := os.WriteFile(knownHosts, []byte(line+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	config := ClientConfig{
+		Hostname:       "127.0.0.1",
+		Port:           targetPort,
+		Username:       "ops",
+		Timeout:        5 * time.Second,
+		KnownHostsFile: knownHosts,
+		Jumps: []JumpHop{{Hostname: "127.0.0.1", Port: jumpPort, Username: "jump", Auth: NewPasswordAuth("bastion"),
+			HostKeyAlias: "bastion.example.com"}},
+	}
+	if _, err := ProbeConnectionDetails(config, NewPasswordAuth("secret")); !errors.Is(err, ErrHostKey) {
+		t.Fatalf("ProbeConnectionDetails() error = %v, want ErrHostKey", err)
+	}
+	if atomic.LoadInt32(forwards) != 0 || atomic.LoadInt32(logins) != 0 {
+		t.Error("Expected nothing to go through an unverified bastion")
+	}
+}
+
+func TestClientConfigValidateJumps(t *testing.T) {
+	config := ClientConfig{Hostname: "db", Port: 22, Username: "ops", Timeout: time.Second,
+		Jumps: []JumpHop{{Hostname: "bastion", Port: 22, Username: "ops"}}}
+	if err := config.Validate(); err == nil {
+		t.Error("Expected a jump host without authentication to be rejected")
+	}
+	config.Jumps[0].Auth = NewPasswordAuth("secret")
+	if err := config.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
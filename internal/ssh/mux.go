@@ -0,0 +1,140 @@
+package ssh
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// MuxIdleTimeout is how long a pooled connection may go unused before it is closed
+const MuxIdleTimeout = 10 * time.Minute
+
+// pooledClient is an authenticated connection kept open for later probes
+type pooledClient struct {
+	client   *ssh.Client
+	hops     []*ssh.Client // Jump host connections the client goes through
+	banner   string        // Banner received when the connection was made
+	lastUsed time.Time
+}
+
+var (
+	muxClients = make(map[string]*pooledClient)
+	muxMu      sync.Mutex
+)
+
+// muxKey identifies the connections that can be shared
+func muxKey(config ClientConfig) string {
+	key := fmt.Sprintf("%s@%s:%d", config.Username, config.Hostname, config.Port)
+	for _, hop := range config.Jumps {
+		key += fmt.Sprintf(" via %s@%s:%d", hop.Username, hop.Hostname, hop.Port)
+	}
+	return key
+}
+
+// close closes the connection and those to its jump hosts
+func (p *pooledClient) close() {
+	p.client.Close()
+	for i := len(p.hops) - 1; i >= 0; i-- {
+		p.hops[i].Close()
+	}
+}
+
+// probeMultiplexed probes over the pooled connection to the host when there is
+// a working one, authenticating only when there isn't. The connection stays in
+// the pool afterwards, so repeated status checks cost one session each.
+func probeMultiplexed(config ClientConfig, auth ssh.AuthMethod) (ProbeResult, error) {
+	key := muxKey(config)
+	if pooled := takeMultiplexed(key); pooled != nil {
+		client := &Client{config: config, client: pooled.client, hops: pooled.hops, banner: pooled.banner}
+		result := probeResult(client)
+		if err := verifyWithin(client, config.Timeout); err == nil {
+			putMultiplexed(key, client)
+			return result, nil
+		}
+		// The connection died; authenticate again below
+		pooled.close()
+	}
+
+	client := NewClient(config)
+	if err := client.Connect(auth); err != nil {
+		return ProbeResult{}, err
+	}
+	result := probeResult(client)
+	if err := verifyWithin(client, config.Timeout); err != nil {
+		client.Disconnect()
+		return result, err
+	}
+	putMultiplexed(key, client)
+	return result, nil
+}
+
+// verifyWithin runs the connection test command, giving up after timeout since
+// a connection whose peer vanished can block until TCP notices
+func verifyWithin(client *Client, timeout time.Duration) error {
+	done := make(chan error, 1)
+	go func() {
+		_, err := client.ExecuteCommand("echo 'connection test'")
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(timeout):
+		client.client.Close()
+		return &ConnectError{Kind: ErrTimeout, Err: fmt.Errorf("connection test timed out after %s", timeout)}
+	}
+}
+
+// takeMultiplexed removes the host's pooled connection from the pool, so
+// concurrent probes never share one mid-check
+func takeMultiplexed(key string) *pooledClient {
+	muxMu.Lock()
+	defer muxMu.Unlock()
+	closeIdleLocked(time.Now())
+	pooled, ok := muxClients[key]
+	if !ok {
+		return nil
+	}
+	delete(muxClients, key)
+	return pooled
+}
+
+// putMultiplexed returns a working connection to the pool
+func putMultiplexed(key string, client *Client) {
+	muxMu.Lock()
+	defer muxMu.Unlock()
+	if existing, ok := muxClients[key]; ok {
+		existing.close()
+	}
+	muxClients[key] = &pooledClient{client: client.client, hops: client.hops, banner: client.banner, lastUsed: time.Now()}
+}
+
+// closeIdleLocked closes pooled connections unused for MuxIdleTimeout
+func closeIdleLocked(now time.Time) {
+	for key, pooled := range muxClients {
+		if now.Sub(pooled.lastUsed) >= MuxIdleTimeout {
+			pooled.close()
+			delete(muxClients, key)
+		}
+	}
+}
+
+// IsMultiplexed reports whether a pooled connection to the host is open
+func IsMultiplexed(config ClientConfig) bool {
+	muxMu.Lock()
+	defer muxMu.Unlock()
+	_, ok := muxClients[muxKey(config)]
+	return ok
+}
+
+// CloseMultiplexed closes every pooled connection, e.g. when sshm exits
+func CloseMultiplexed() {
+	muxMu.Lock()
+	defer muxMu.Unlock()
+	for key, pooled := range muxClients {
+		pooled.close()
+		delete(muxClients, key)
+	}
+}
@@ -0,0 +1,112 @@
+package ssh
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// startTestServer runs an SSH server that accepts the password "secret" and
+// answers every exec request with success, counting the authentications
+func startTestServer(t *testing.T) (int, *int32) {
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := ssh.NewSignerFromKey(private)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var logins int32
+	serverConfig := &ssh.ServerConfig{
+		PasswordCallback: func(conn ssh.ConnMetadata, password []byte) (*ssh.Permissions, error) {
+			atomic.AddInt32(&logins, 1)
+			return nil, nil
+		},
+	}
+	serverConfig.AddHostKey(signer)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveTestConn(conn, serverConfig)
+		}
+	}()
+	return listener.Addr().(*net.TCPAddr).Port, &logins
+}
+
+func serveTestConn(conn net.Conn, serverConfig *ssh.ServerConfig) {
+	_, channels, requests, err := ssh.NewServerConn(conn, serverConfig)
+	if err != nil {
+		return
+	}
+	go ssh.DiscardRequests(requests)
+	for newChannel := range channels {
+		channel, channelRequests, err := newChannel.Accept()
+		if err != nil {
+			continue
+		}
+		go func() {
+			for request := range channelRequests {
+				request.Reply(request.Type == "exec", nil)
+				if request.Type == "exec" {
+					channel.Write([]byte("connection test\n"))
+					channel.SendRequest("exit-status", false, ssh.Marshal(struct{ Status uint32 }{0}))
+					channel.Close()
+				}
+			}
+		}()
+	}
+}
+
+func TestProbeMultiplexedReusesConnection(t *testing.T) {
+	port, logins := startTestServer(t)
+	defer CloseMultiplexed()
+
+	config := ClientConfig{Hostname: "127.0.0.1", Port: port, Username: "ops", Timeout: 5 * time.Second, Multiplex: true}
+	for i := 0; i < 3; i++ {
+		address, err := ProbeConnection(config, NewPasswordAuth("secret"))
+		if err != nil {
+			t.Fatalf("ProbeConnection() #%d error = %v", i+1, err)
+		}
+		if address != "127.0.0.1" {
+			t.Errorf("ProbeConnection() address = %s", address)
+		}
+	}
+	if got := atomic.LoadInt32(logins); got != 1 {
+		t.Errorf("Expected one authentication for three probes, got %d", got)
+	}
+	if !IsMultiplexed(config) {
+		t.Error("Expected the connection to stay pooled")
+	}
+
+	// Without multiplexing every probe authenticates
+	config.Multiplex = false
+	if _, err := ProbeConnection(config, NewPasswordAuth("secret")); err != nil {
+		t.Fatalf("ProbeConnection() error = %v", err)
+	}
+	if got := atomic.LoadInt32(logins); got != 2 {
+		t.Errorf("Expected a second authentication, got %d", got)
+	}
+
+	CloseMultiplexed()
+	config.Multiplex = true
+	if IsMultiplexed(config) {
+		t.Error("Expected CloseMultiplexed to empty the pool")
+	}
+}
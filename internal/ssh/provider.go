@@ -0,0 +1,130 @@
+package ssh
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/ssh"
+
+	"sshm/internal/shellquote"
+)
+
+// AuthRequest describes the connection an auth provider authenticates
+type AuthRequest struct {
+	ServerName string
+	Hostname   string // Address that is dialed
+	Port       int
+	Username   string
+	Options    map[string]string // The server's auth_provider_options
+}
+
+// AuthProvider is a custom authentication backend, e.g. corporate SSO or
+// short-lived certificates from an internal CA. Servers reference a provider by
+// name in auth_provider or as "provider:<name>" in their auth_chain.
+type AuthProvider interface {
+	// Name is how servers reference the provider
+	Name() string
+	// AuthMethod returns the method sshm's own connections use: status checks,
+	// the connectivity test before connecting and banner fetches. It must not
+	// prompt; return an error when the user has to log in first.
+	AuthMethod(request AuthRequest) (ssh.AuthMethod, error)
+	// SSHArgs returns the arguments added to the ssh command of interactive
+	// sessions, e.g. ["-i", key, "-o", "CertificateFile=" + certificate]
+	SSHArgs(request AuthRequest) ([]string, error)
+}
+
+var providerNamePattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+var (
+	authProviders   = make(map[string]AuthProvider)
+	authProvidersMu sync.RWMutex
+)
+
+// RegisterAuthProvider makes a provider available to servers by its name
+func RegisterAuthProvider(provider AuthProvider) error {
+	if provider == nil {
+		return fmt.Errorf("auth provider is nil")
+	}
+	name := provider.Name()
+	if !providerNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid auth provider name '%s'", name)
+	}
+
+	authProvidersMu.Lock()
+	defer authProvidersMu.Unlock()
+	if _, exists := authProviders[name]; exists {
+		return fmt.Errorf("auth provider '%s' is already registered", name)
+	}
+	authProviders[name] = provider
+	return nil
+}
+
+// UnregisterAuthProvider removes a registered provider
+func UnregisterAuthProvider(name string) {
+	authProvidersMu.Lock()
+	defer authProvidersMu.Unlock()
+	delete(authProviders, name)
+}
+
+// LookupAuthProvider returns the provider registered under name
+func LookupAuthProvider(name string) (AuthProvider, error) {
+	authProvidersMu.RLock()
+	provider, ok := authProviders[name]
+	authProvidersMu.RUnlock()
+	if !ok {
+		registered := AuthProviderNames()
+		if len(registered) == 0 {
+			return nil, fmt.Errorf("unknown auth provider '%s' (none registered)", name)
+		}
+		return nil, fmt.Errorf("unknown auth provider '%s' (registered: %s)", name, strings.Join(registered, ", "))
+	}
+	return provider, nil
+}
+
+// AuthProviderNames returns the names of the registered providers, sorted
+func AuthProviderNames() []string {
+	authProvidersMu.RLock()
+	defer authProvidersMu.RUnlock()
+	names := make([]string, 0, len(authProviders))
+	for name := range authProviders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ProviderSSHOptions returns the provider's ssh arguments as a string to append
+// to an ssh command, each argument quoted for the shell when needed
+func ProviderSSHOptions(name string, request AuthRequest) (string, error) {
+	provider, err := LookupAuthProvider(name)
+	if err != nil {
+		return "", err
+	}
+	args, err := provider.SSHArgs(request)
+	if err != nil {
+		return "", fmt.Errorf("auth provider '%s': %w", name, err)
+	}
+
+	var options strings.Builder
+	for _, arg := range args {
+		options.WriteString(" ")
+		options.WriteString(shellquote.Arg(arg))
+	}
+	return options.String(), nil
+}
+
+// ProviderAuthMethod returns the provider's authentication method for sshm's own connections
+func ProviderAuthMethod(name string, request AuthRequest) (ssh.AuthMethod, error) {
+	provider, err := LookupAuthProvider(name)
+	if err != nil {
+		return nil, err
+	}
+	auth, err := provider.AuthMethod(request)
+	if err != nil {
+		return nil, fmt.Errorf("auth provider '%s': %w", name, err)
+	}
+	return auth, nil
+}
@@ -0,0 +1,82 @@
+package ssh
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+)
+
+type testProvider struct {
+	name string
+	args []string
+	err  error
+}
+
+func (p testProvider) Name() string { return p.name }
+
+func (p testProvider) AuthMethod(request AuthRequest) (ssh.AuthMethod, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	return ssh.Password(request.Options["token"]), nil
+}
+
+func (p testProvider) SSHArgs(request AuthRequest) ([]string, error) {
+	return p.args, p.err
+}
+
+func TestRegisterAuthProvider(t *testing.T) {
+	provider := testProvider{name: "test-sso"}
+	if err := RegisterAuthProvider(provider); err != nil {
+		t.Fatalf("RegisterAuthProvider() error = %v", err)
+	}
+	defer UnregisterAuthProvider("test-sso")
+
+	if err := RegisterAuthProvider(provider); err == nil {
+		t.Error("Expected an error when registering a name twice")
+	}
+	for _, invalid := range []AuthProvider{nil, testProvider{name: ""}, testProvider{name: "has space"}} {
+		if err := RegisterAuthProvider(invalid); err == nil {
+			t.Errorf("Expected an error registering %v", invalid)
+		}
+	}
+
+	if _, err := LookupAuthProvider("test-sso"); err != nil {
+		t.Errorf("LookupAuthProvider() error = %v", err)
+	}
+	_, err := LookupAuthProvider("missing")
+	if err == nil || !strings.Contains(err.Error(), "test-sso") {
+		t.Errorf("Expected the error to list registered providers, got %v", err)
+	}
+	if names := AuthProviderNames(); len(names) != 1 || names[0] != "test-sso" {
+		t.Errorf("AuthProviderNames() = %v", names)
+	}
+}
+
+func TestProviderSSHOptions(t *testing.T) {
+	if err := RegisterAuthProvider(testProvider{name: "test-ca", args: []string{"-i", "/tmp/key", "-o", "CertificateFile=/tmp/my cert"}}); err != nil {
+		t.Fatal(err)
+	}
+	defer UnregisterAuthProvider("test-ca")
+
+	options, err := ProviderSSHOptions("test-ca", AuthRequest{ServerName: "web1"})
+	if err != nil {
+		t.Fatalf("ProviderSSHOptions() error = %v", err)
+	}
+	if want := " -i /tmp/key -o 'CertificateFile=/tmp/my cert'"; options != want {
+		t.Errorf("ProviderSSHOptions() = %q, want %q", options, want)
+	}
+	if _, err := ProviderAuthMethod("test-ca", AuthRequest{}); err != nil {
+		t.Errorf("ProviderAuthMethod() error = %v", err)
+	}
+
+	if err := RegisterAuthProvider(testProvider{name: "test-expired", err: errors.New("login required")}); err != nil {
+		t.Fatal(err)
+	}
+	defer UnregisterAuthProvider("test-expired")
+	if _, err := ProviderAuthMethod("test-expired", AuthRequest{}); err == nil || !strings.Contains(err.Error(), "'test-expired': login required") {
+		t.Errorf("Expected the provider's error, got %v", err)
+	}
+}
@@ -1,15 +1,19 @@
 package ssh
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
 	"golang.org/x/crypto/ssh"
 	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
 	"golang.org/x/term"
 )
 
@@ -19,12 +23,36 @@ type ClientConfig struct {
 	Port     int
 	Username string
 	Timeout  time.Duration
+	// Multiplex keeps the connection after a successful probe and reuses it
+	// for later probes of the same host instead of authenticating again
+	Multiplex bool
+	// Jumps are the hosts connected through to reach the server, in order
+	Jumps []JumpHop
+	// KnownHostsFile verifies the server's host key when set: keys that differ
+	// from the file's or are revoked fail the connection
+	KnownHostsFile string
+	// HostKeyAlias is the name the server's keys are stored under when it
+	// isn't the dialed hostname, like ssh -o HostKeyAlias
+	HostKeyAlias string
+}
+
+// JumpHop is a host the connection is tunneled through, like ssh -J
+type JumpHop struct {
+	Hostname string
+	Port     int
+	Username string
+	Auth     ssh.AuthMethod
+	// HostKeyAlias is the name the hop's keys are stored under when it isn't
+	// Hostname; they are verified against the server's KnownHostsFile
+	HostKeyAlias string
 }
 
 // Client represents an SSH client wrapper
 type Client struct {
 	config ClientConfig
 	client *ssh.Client
+	hops   []*ssh.Client // Connections to the jump hosts, closed with the client
+	banner string        // Pre-authentication banner received while connecting
 }
 
 // NewClient creates a new SSH client with the given configuration
@@ -52,41 +80,174 @@ func (c *ClientConfig) Validate() error {
 		return fmt.Errorf("timeout must be greater than 0")
 	}
 
+	for _, hop := range c.Jumps {
+		if strings.TrimSpace(hop.Hostname) == "" || strings.TrimSpace(hop.Username) == "" {
+			return fmt.Errorf("jump hosts need a hostname and a username")
+		}
+		if hop.Port <= 0 || hop.Port > 65535 {
+			return fmt.Errorf("jump host %s: port must be between 1 and 65535", hop.Hostname)
+		}
+		if hop.Auth == nil {
+			return fmt.Errorf("jump host %s has no authentication method", hop.Hostname)
+		}
+	}
+
 	return nil
 }
 
 // Connect establishes an SSH connection using the provided authentication method
 func (c *Client) Connect(auth ssh.AuthMethod) error {
+	return c.dial([]ssh.AuthMethod{auth})
+}
+
+// dial connects to the server through its jump hosts, offering the given
+// authentication methods; without any only the "none" method is tried
+func (c *Client) dial(auth []ssh.AuthMethod) error {
 	if err := c.config.Validate(); err != nil {
 		return fmt.Errorf("invalid configuration: %w", err)
 	}
 
+	hostKeyCallback, err := c.config.hostKeyCallback()
+	if err != nil {
+		return err
+	}
 	config := &ssh.ClientConfig{
-		User: c.config.Username,
-		Auth: []ssh.AuthMethod{auth},
-		HostKeyCallback: ssh.InsecureIgnoreHostKey(), // TODO: Implement proper host key verification
+		User:            c.config.Username,
+		Auth:            auth,
+		HostKeyCallback: hostKeyCallback,
 		Timeout:         c.config.Timeout,
+		BannerCallback: func(message string) error {
+			c.banner += message
+			return nil
+		},
 	}
 
 	address := fmt.Sprintf("%s:%d", c.config.Hostname, c.config.Port)
 	
-	client, err := ssh.Dial("tcp", address, config)
+	client, hops, err := dialThrough(c.config.Jumps, c.config.KnownHostsFile, address, config)
 	if err != nil {
-		return fmt.Errorf("failed to connect to %s: %w", address, err)
+		return err
 	}
 
 	c.client = client
+	c.hops = hops
 	return nil
 }
 
-// Disconnect closes the SSH connection
+// hostKeyCallback verifies the server's host key against KnownHostsFile.
+// Hosts the file has no key for are accepted, since ssh asks about them itself
+// when the session connects. Without a file every key is accepted.
+func (c ClientConfig) hostKeyCallback() (ssh.HostKeyCallback, error) {
+	if c.KnownHostsFile == "" {
+		return ssh.InsecureIgnoreHostKey(), nil
+	}
+	if _, err := os.Stat(c.KnownHostsFile); os.IsNotExist(err) {
+		return ssh.InsecureIgnoreHostKey(), nil
+	}
+	check, err := knownhosts.New(c.KnownHostsFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", c.KnownHostsFile, err)
+	}
+	return func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+		if c.HostKeyAlias != "" {
+			hostname = net.JoinHostPort(c.HostKeyAlias, strconv.Itoa(c.Port))
+		}
+		err := check(hostname, remote, key)
+		var keyErr *knownhosts.KeyError
+		if errors.As(err, &keyErr) && len(keyErr.Want) == 0 {
+			return nil
+		}
+		return err
+	}, nil
+}
+
+// dialThrough connects to address, tunneling through each jump host in turn
+// when there are any. The jump hosts' keys are verified against knownHostsFile
+// like the server's. The jump host connections are returned so they can be
+// closed with the final one.
+func dialThrough(jumps []JumpHop, knownHostsFile, address string, config *ssh.ClientConfig) (*ssh.Client, []*ssh.Client, error) {
+	var hops []*ssh.Client
+	closeHops := func() {
+		for i := len(hops) - 1; i >= 0; i-- {
+			hops[i].Close()
+		}
+	}
+
+	dial := func(target string, clientConfig *ssh.ClientConfig) (*ssh.Client, error) {
+		var conn net.Conn
+		var err error
+		if len(hops) == 0 {
+			conn, err = net.DialTimeout("tcp", target, clientConfig.Timeout)
+		} else {
+			// The jump host resolves the name, which may only exist on its network
+			ctx, cancel := context.WithTimeout(context.Background(), config.Timeout)
+			defer cancel()
+			conn, err = hops[len(hops)-1].DialContext(ctx, "tcp", target)
+		}
+		if err != nil {
+			return nil, dialError(err)
+		}
+		return handshake(conn, target, clientConfig)
+	}
+
+	for _, jump := range jumps {
+		hopAddress := fmt.Sprintf("%s:%d", jump.Hostname, jump.Port)
+		hostKeyCallback, err := ClientConfig{KnownHostsFile: knownHostsFile, HostKeyAlias: jump.HostKeyAlias, Port: jump.Port}.hostKeyCallback()
+		if err != nil {
+			closeHops()
+			return nil, nil, err
+		}
+		hop, err := dial(hopAddress, &ssh.ClientConfig{
+			User:            jump.Username,
+			Auth:            []ssh.AuthMethod{jump.Auth},
+			HostKeyCallback: hostKeyCallback,
+			Timeout:         config.Timeout,
+		})
+		if err != nil {
+			closeHops()
+			return nil, nil, fmt.Errorf("failed to connect to jump host %s: %w", hopAddress, err)
+		}
+		hops = append(hops, hop)
+	}
+
+	client, err := dial(address, config)
+	if err != nil {
+		closeHops()
+		return nil, nil, fmt.Errorf("failed to connect to %s: %w", address, err)
+	}
+	return client, hops, nil
+}
+
+// handshake runs the SSH handshake over conn, keeping track of whether the host
+// key was accepted to tell why it failed
+func handshake(conn net.Conn, target string, config *ssh.ClientConfig) (*ssh.Client, error) {
+	hostKeyAccepted := false
+	checked := *config
+	checked.HostKeyCallback = func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+		err := config.HostKeyCallback(hostname, remote, key)
+		hostKeyAccepted = err == nil
+		return err
+	}
+	clientConn, channels, requests, err := ssh.NewClientConn(conn, target, &checked)
+	if err != nil {
+		conn.Close()
+		return nil, handshakeError(err, hostKeyAccepted)
+	}
+	return ssh.NewClient(clientConn, channels, requests), nil
+}
+
+// Disconnect closes the SSH connection and those to its jump hosts
 func (c *Client) Disconnect() error {
+	var err error
 	if c.client != nil {
-		err := c.client.Close()
+		err = c.client.Close()
 		c.client = nil
-		return err
 	}
-	return nil
+	for i := len(c.hops) - 1; i >= 0; i-- {
+		c.hops[i].Close()
+	}
+	c.hops = nil
+	return err
 }
 
 // IsConnected returns true if the client is connected
@@ -94,6 +255,34 @@ func (c *Client) IsConnected() bool {
 	return c.client != nil
 }
 
+// RemoteIP returns the IP address of the connected server, without the port
+func (c *Client) RemoteIP() string {
+	if c.client == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(c.client.RemoteAddr().String())
+	if err != nil {
+		return c.client.RemoteAddr().String()
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.IsUnspecified() {
+		return "" // Reached through a jump host, which doesn't tell the address
+	}
+	return host
+}
+
+// ServerVersion returns the version the server announced, e.g. "SSH-2.0-OpenSSH_9.6"
+func (c *Client) ServerVersion() string {
+	if c.client == nil {
+		return ""
+	}
+	return string(c.client.ServerVersion())
+}
+
+// Banner returns the pre-authentication banner the server sent, if any
+func (c *Client) Banner() string {
+	return c.banner
+}
+
 // ExecuteCommand executes a command on the remote server and returns the output
 func (c *Client) ExecuteCommand(command string) (string, error) {
 	if !c.IsConnected() {
@@ -114,6 +303,25 @@ func (c *Client) ExecuteCommand(command string) (string, error) {
 	return string(output), nil
 }
 
+// FetchBanner connects just far enough to receive the server's pre-authentication
+// login banner. No credentials are offered, so the server never sees a login attempt
+// beyond the protocol's initial "none" method. It dials like Connect, through the
+// same jump hosts and host key verification.
+func FetchBanner(config ClientConfig) (string, error) {
+	client := NewClient(config)
+	err := client.dial(nil)
+	client.Disconnect()
+	if err == nil {
+		// The server accepted the "none" method; the banner (if any) was already received
+		return client.Banner(), nil
+	}
+
+	if client.Banner() != "" || ClassifyError(err) == StatusAuthFailed {
+		return client.Banner(), nil
+	}
+	return "", err
+}
+
 // NewKeyAuth creates an SSH authentication method using a private key
 func NewKeyAuth(keyPath, passphrase string) (ssh.AuthMethod, error) {
 	if strings.TrimSpace(keyPath) == "" {
@@ -144,7 +352,8 @@ func NewKeyAuth(keyPath, passphrase string) (ssh.AuthMethod, error) {
 		signer, err = ssh.ParsePrivateKey(keyBytes)
 		if err != nil {
 			// If it fails, it might be encrypted - prompt for passphrase
-			if strings.Contains(err.Error(), "encrypted") {
+			var missing *ssh.PassphraseMissingError
+			if errors.As(err, &missing) {
 				fmt.Print("Enter passphrase for key: ")
 				passphraseBytes, err := term.ReadPassword(int(os.Stdin.Fd()))
 				fmt.Println() // New line after password input
@@ -219,17 +428,79 @@ func expandPath(path string) (string, error) {
 	return path, nil
 }
 
+// Connection failure classifications shared by the status checker and retry policies
+const (
+	StatusUnreachable = "unreachable"
+	StatusAuthFailed  = "auth failed"
+	StatusRefused     = "refused"
+	StatusHostKey     = "host key changed"
+	StatusError       = "error"
+)
+
+// ClassifyError maps a connection error to one of the failure classifications
+func ClassifyError(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	err = Classify(err)
+	switch {
+	case errors.Is(err, ErrTimeout), errors.Is(err, ErrUnreachable):
+		return StatusUnreachable
+	case errors.Is(err, ErrAuth):
+		return StatusAuthFailed
+	case errors.Is(err, ErrRefused):
+		return StatusRefused
+	case errors.Is(err, ErrHostKey):
+		return StatusHostKey
+	default:
+		return StatusError
+	}
+}
+
 // TestConnection tests if a connection can be established with the given configuration and auth
 func TestConnection(config ClientConfig, auth ssh.AuthMethod) error {
+	_, err := ProbeConnection(config, auth)
+	return err
+}
+
+// ProbeConnection tests the connection like TestConnection and also returns the
+// IP address it reached, which is empty when the connection failed
+func ProbeConnection(config ClientConfig, auth ssh.AuthMethod) (string, error) {
+	result, err := ProbeConnectionDetails(config, auth)
+	return result.Address, err
+}
+
+// ProbeResult is what a probe learned about the server
+type ProbeResult struct {
+	Address       string // IP address reached, empty when the connection failed
+	ServerVersion string // Version the server announced, e.g. "SSH-2.0-OpenSSH_9.6"
+	Banner        string // Pre-authentication banner, if any
+}
+
+// ProbeConnectionDetails tests the connection like ProbeConnection and also
+// returns the server's version and banner
+func ProbeConnectionDetails(config ClientConfig, auth ssh.AuthMethod) (ProbeResult, error) {
+	if config.Multiplex {
+		return probeMultiplexed(config, auth)
+	}
+
 	client := NewClient(config)
 	
 	if err := client.Connect(auth); err != nil {
-		return err
+		return ProbeResult{}, err
 	}
 	
 	defer client.Disconnect()
 	
+	result := probeResult(client)
+	
 	// Try to execute a simple command to verify the connection works
 	_, err := client.ExecuteCommand("echo 'connection test'")
-	return err
+	return result, err
+}
+
+// probeResult describes a connected client
+func probeResult(client *Client) ProbeResult {
+	return ProbeResult{Address: client.RemoteIP(), ServerVersion: client.ServerVersion(), Banner: client.Banner()}
 }
\ No newline at end of file
@@ -1,8 +1,18 @@
 package ssh
 
 import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
 )
 
 func TestNewClient(t *testing.T) {
@@ -220,4 +230,118 @@ func TestExecuteCommand(t *testing.T) {
 	if output != "" {
 		t.Errorf("Expected empty output when not connected, got: %s", output)
 	}
-}
\ No newline at end of file
+}
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		err      error
+		expected string
+	}{
+		{nil, ""},
+		{fmt.Errorf("dial tcp 10.0.0.1:22: i/o timeout"), StatusUnreachable},
+		{fmt.Errorf("dial tcp: connect: no route to host"), StatusUnreachable},
+		{fmt.Errorf("ssh: unable to authenticate, attempted methods [none publickey]; no supported methods remain: authentication failed"), StatusAuthFailed},
+		{fmt.Errorf("Permission denied (publickey)"), StatusAuthFailed},
+		{fmt.Errorf("dial tcp 127.0.0.1:22: connect: connection refused"), StatusRefused},
+		{fmt.Errorf("something else"), StatusError},
+	}
+
+	for _, tt := range tests {
+		if got := ClassifyError(tt.err); got != tt.expected {
+			t.Errorf("ClassifyError(%v) = %q, want %q", tt.err, got, tt.expected)
+		}
+	}
+}
+
+func TestParseQuickStats(t *testing.T) {
+	stats, err := ParseQuickStats("0.40\n71\n38\n")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if stats.Load1 != 0.4 || stats.DiskPercent != 71 || stats.MemPercent != 38 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+	if got := stats.String(); got != "load 0.4 | 71% disk | 38% mem" {
+		t.Errorf("Unexpected rendering: %s", got)
+	}
+
+	if _, err := ParseQuickStats("0.40\n"); err == nil {
+		t.Error("Expected error for truncated output")
+	}
+	if _, err := ParseQuickStats("high\n71\n38"); err == nil {
+		t.Error("Expected error for invalid load average")
+	}
+}
+
+// startBannerServer runs an SSH server that sends banner before rejecting
+// every login, returning its port and host key
+func startBannerServer(t *testing.T, banner string) (int, ssh.PublicKey) {
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := ssh.NewSignerFromKey(private)
+	if err != nil {
+		t.Fatal(err)
+	}
+	serverConfig := &ssh.ServerConfig{
+		BannerCallback: func(conn ssh.ConnMetadata) string { return banner },
+		PasswordCallback: func(conn ssh.ConnMetadata, password []byte) (*ssh.Permissions, error) {
+			return nil, fmt.Errorf("wrong password")
+		},
+	}
+	serverConfig.AddHostKey(signer)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go ssh.NewServerConn(conn, serverConfig)
+		}
+	}()
+	return listener.Addr().(*net.TCPAddr).Port, signer.PublicKey()
+}
+
+func TestFetchBannerVerifiesHostKey(t *testing.T) {
+	port, hostKey := startBannerServer(t, "Authorized use only\n")
+	_, otherPrivate, _ := ed25519.GenerateKey(rand.Reader)
+	otherKey, err := ssh.NewPublicKey(otherPrivate.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	writeKnownHosts := func(name string, key ssh.PublicKey) string {
+		path := filepath.Join(t.TempDir(), "known_hosts")
+		line := knownhosts.Line([]string{knownhosts.Normalize(net.JoinHostPort(name, fmt.Sprint(port)))}, key)
+		if err := os.WriteFile(path, []byte(line+"\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	config := ClientConfig{Hostname: "127.0.0.1", Port: port, Username: "ops", Timeout: 5 * time.Second}
+
+	// The key recorded under the alias is checked rather than the dialed address
+	config.KnownHostsFile = writeKnownHosts("db.internal", hostKey)
+	config.HostKeyAlias = "db.internal"
+	banner, err := FetchBanner(config)
+	if err != nil || banner != "Authorized use only\n" {
+		t.Fatalf("FetchBanner() = %q, %v, want the banner", banner, err)
+	}
+
+	// Hosts missing from the file are left for ssh to ask about
+	config.KnownHostsFile = writeKnownHosts("other.internal", otherKey)
+	if _, err := FetchBanner(config); err != nil {
+		t.Fatalf("FetchBanner() of an unknown host error = %v", err)
+	}
+
+	config.KnownHostsFile = writeKnownHosts("db.internal", otherKey)
+	if _, err := FetchBanner(config); !errors.Is(err, ErrHostKey) {
+		t.Fatalf("FetchBanner() with a changed host key error = %v, want ErrHostKey", err)
+	}
+}
@@ -0,0 +1,68 @@
+package ssh
+
+import (
+	"bufio"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// quickStatsCommand prints the 1 minute load average, root filesystem usage and
+// memory usage on separate lines, using only tools present on any Linux host
+const quickStatsCommand = `cut -d' ' -f1 /proc/loadavg; df -P / | awk 'NR==2{sub("%","",$5); print $5}'; free | awk '/^Mem:/{printf "%d\n", $3*100/$2}'`
+
+// QuickStats is a cheap snapshot of a server's load, disk and memory usage
+type QuickStats struct {
+	Load1       float64 // 1 minute load average
+	DiskPercent int     // Root filesystem usage
+	MemPercent  int     // Memory in use
+}
+
+// String renders the stats compactly, e.g. "load 0.4 | 71% disk | 38% mem"
+func (s QuickStats) String() string {
+	return fmt.Sprintf("load %s | %d%% disk | %d%% mem", strconv.FormatFloat(s.Load1, 'f', -1, 64), s.DiskPercent, s.MemPercent)
+}
+
+// FetchQuickStats connects to the server and collects its quick stats with a single command
+func FetchQuickStats(config ClientConfig, auth ssh.AuthMethod) (QuickStats, error) {
+	client := NewClient(config)
+	if err := client.Connect(auth); err != nil {
+		return QuickStats{}, err
+	}
+	defer client.Disconnect()
+
+	output, err := client.ExecuteCommand(quickStatsCommand)
+	if err != nil {
+		return QuickStats{}, err
+	}
+	return ParseQuickStats(output)
+}
+
+// ParseQuickStats parses the output of the quick stats command
+func ParseQuickStats(output string) (QuickStats, error) {
+	var lines []string
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if len(lines) < 3 {
+		return QuickStats{}, fmt.Errorf("unexpected stats output: %q", output)
+	}
+
+	var stats QuickStats
+	var err error
+	if stats.Load1, err = strconv.ParseFloat(lines[0], 64); err != nil {
+		return QuickStats{}, fmt.Errorf("invalid load average %q", lines[0])
+	}
+	if stats.DiskPercent, err = strconv.Atoi(lines[1]); err != nil {
+		return QuickStats{}, fmt.Errorf("invalid disk usage %q", lines[1])
+	}
+	if stats.MemPercent, err = strconv.Atoi(lines[2]); err != nil {
+		return QuickStats{}, fmt.Errorf("invalid memory usage %q", lines[2])
+	}
+	return stats, nil
+}
@@ -0,0 +1,124 @@
+// Package sshconfig keeps sshm's servers and an OpenSSH config file in sync.
+// sshm owns a marked section at the top of the file; everything outside it,
+// including the files it includes, belongs to the user and is only read.
+package sshconfig
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"sshm/internal/config"
+)
+
+// Markers delimiting the section of the SSH config file written by sshm
+const (
+	BeginMarker = "# BEGIN sshm managed hosts"
+	EndMarker   = "# END sshm managed hosts"
+)
+
+// Host is what is synced of a server: the settings both sides understand
+type Host struct {
+	Name         string `json:"name"`
+	Hostname     string `json:"hostname"`
+	User         string `json:"user"`
+	Port         int    `json:"port"`
+	IdentityFile string `json:"identity_file,omitempty"`
+}
+
+// hostFromServer returns the synced settings of a server
+func hostFromServer(server config.Server) Host {
+	port := server.Port
+	if port == 0 {
+		port = 22
+	}
+	return Host{Name: server.Name, Hostname: server.Hostname, User: server.Username, Port: port, IdentityFile: server.KeyPath}
+}
+
+// apply copies the host's settings onto a server, leaving everything else as is
+func (h Host) apply(server *config.Server) {
+	server.Hostname = h.Hostname
+	server.Username = h.User
+	server.Port = h.Port
+	server.KeyPath = h.IdentityFile
+	if h.IdentityFile != "" {
+		server.AuthType = "key"
+	} else if server.AuthType == "key" {
+		server.AuthType = "password" // As imported hosts without an IdentityFile
+	}
+}
+
+// newServer returns a server for a host found only in the SSH config
+func (h Host) newServer() config.Server {
+	server := config.Server{Name: h.Name, AuthType: "password"}
+	h.apply(&server)
+	return server
+}
+
+// validHostName reports whether a server name can be an ssh Host pattern
+func validHostName(name string) bool {
+	return name != "" && !strings.ContainsAny(name, " \t*?!,\"#")
+}
+
+// splitManaged separates the file into the parts before and after the managed
+// section and the section's body
+func splitManaged(content []byte) (before, body, after []byte, found bool) {
+	lines := bytes.SplitAfter(content, []byte("\n"))
+	begin, end := -1, -1
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(string(line))
+		switch {
+		case begin < 0 && strings.HasPrefix(trimmed, BeginMarker):
+			begin = i
+		case begin >= 0 && strings.HasPrefix(trimmed, EndMarker):
+			end = i
+		}
+		if end >= 0 {
+			break
+		}
+	}
+	if begin < 0 || end < 0 {
+		return content, nil, nil, false
+	}
+	return bytes.Join(lines[:begin], nil), bytes.Join(lines[begin+1:end], nil), bytes.Join(lines[end+1:], nil), true
+}
+
+// parseHosts reads the hosts of SSH config content, keyed by name
+func parseHosts(servers []config.Server) map[string]Host {
+	hosts := make(map[string]Host, len(servers))
+	for _, server := range servers {
+		if _, seen := hosts[server.Name]; !seen { // ssh uses the first match
+			hosts[server.Name] = hostFromServer(server)
+		}
+	}
+	return hosts
+}
+
+// renderManaged writes the managed section. It ends with "Host *" so options
+// the user wrote above their first Host block keep applying to every host.
+func renderManaged(hosts map[string]Host) []byte {
+	names := make([]string, 0, len(hosts))
+	for name := range hosts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "%s (written by 'sshm sync'; edits here are synced back)\n", BeginMarker)
+	for _, name := range names {
+		host := hosts[name]
+		fmt.Fprintf(&b, "Host %s\n", host.Name)
+		fmt.Fprintf(&b, "    HostName %s\n", host.Hostname)
+		fmt.Fprintf(&b, "    User %s\n", host.User)
+		if host.Port != 0 && host.Port != 22 {
+			fmt.Fprintf(&b, "    Port %d\n", host.Port)
+		}
+		if host.IdentityFile != "" {
+			fmt.Fprintf(&b, "    IdentityFile %s\n", host.IdentityFile)
+		}
+	}
+	b.WriteString("Host *\n")
+	fmt.Fprintf(&b, "%s\n", EndMarker)
+	return b.Bytes()
+}
@@ -0,0 +1,75 @@
+package sshconfig
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"sshm/internal/config"
+)
+
+// stateFileName stores what the last sync saw, next to config.yaml
+const stateFileName = "ssh-sync.json"
+
+// State is what the last sync agreed on, the base of the next three-way merge
+type State struct {
+	Hosts    map[string]Host `json:"hosts"`             // Values both sides had after the last sync
+	External map[string]Host `json:"external"`          // Host blocks outside the managed section at the last sync
+	Ignored  []string        `json:"ignored,omitempty"` // Deleted in sshm while still defined by the user: not imported again
+}
+
+// StatePath returns the sync state file next to the configuration file
+func StatePath() (string, error) {
+	configPath, err := config.DefaultConfigPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(configPath), stateFileName), nil
+}
+
+// LoadState reads the sync state; a missing file is the state before the first sync
+func LoadState(path string) (*State, error) {
+	state := &State{}
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read sync state: %w", err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, state); err != nil {
+			return nil, fmt.Errorf("failed to parse sync state: %w", err)
+		}
+	}
+	if state.Hosts == nil {
+		state.Hosts = make(map[string]Host)
+	}
+	if state.External == nil {
+		state.External = make(map[string]Host)
+	}
+	return state, nil
+}
+
+// Save writes the sync state
+func (s *State) Save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode sync state: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write sync state: %w", err)
+	}
+	return nil
+}
+
+// isIgnored reports whether a host was deleted in sshm and stays out
+func (s *State) isIgnored(name string) bool {
+	for _, ignored := range s.Ignored {
+		if ignored == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,295 @@
+package sshconfig
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"sshm/internal/config"
+)
+
+// What a sync did to a host
+const (
+	ActionImport   = "import"   // New Host block added to sshm
+	ActionPull     = "pull"     // Edit made in the SSH config taken into sshm
+	ActionPush     = "push"     // Server written to the managed section
+	ActionRemove   = "remove"   // Server deleted in sshm removed from the managed section
+	ActionConflict = "conflict" // Edited on both sides, left alone
+	ActionSkip     = "skip"     // Name that can't be an ssh Host
+)
+
+// Change is one thing a sync did, or would do in a dry run
+type Change struct {
+	Host   string
+	Action string
+	Detail string
+}
+
+// Conflict is a host edited both in sshm and in the SSH config since the last sync
+type Conflict struct {
+	Host      string
+	SSHM      Host
+	SSHConfig Host
+}
+
+// Result reports a sync
+type Result struct {
+	Changes       []Change
+	Conflicts     []Conflict
+	ConfigChanged bool // Servers were added or updated; the caller saves the configuration
+	FileChanged   bool // The managed section of the SSH config was rewritten
+}
+
+// Syncer syncs the servers with one SSH config file
+type Syncer struct {
+	Path      string // SSH config file
+	StatePath string // Sync state file, see StatePath
+	Prefer    string // config.SSHSyncPrefer*: which side wins conflicts
+	DryRun    bool   // Report what would change without writing anything
+}
+
+// NewSyncer returns a syncer for the configured SSH config file
+func NewSyncer(settings config.SSHSyncConfig) (*Syncer, error) {
+	if err := settings.Validate(); err != nil {
+		return nil, err
+	}
+	path, err := settings.ConfigPath()
+	if err != nil {
+		return nil, err
+	}
+	statePath, err := StatePath()
+	if err != nil {
+		return nil, err
+	}
+	return &Syncer{Path: path, StatePath: statePath, Prefer: settings.Prefer}, nil
+}
+
+// Sync merges each host three ways, using what the last sync agreed on as the
+// base: a side that changed wins over one that didn't, and hosts changed on
+// both sides are conflicts resolved by Prefer. New Host blocks, including those
+// in included files, are imported into cfg; servers that aren't defined by the
+// user's Host blocks, or differ from them, are written to the managed section,
+// which precedes the user's blocks so its values take effect.
+func (s *Syncer) Sync(cfg *config.Config) (Result, error) {
+	var result Result
+
+	content, err := os.ReadFile(s.Path)
+	if err != nil && !os.IsNotExist(err) {
+		return result, fmt.Errorf("failed to read SSH config: %w", err)
+	}
+	before, body, after, _ := splitManaged(content)
+
+	managedServers, err := config.ParseSSHConfigReader(bytes.NewReader(body))
+	if err != nil {
+		return result, err
+	}
+	managed := parseHosts(managedServers)
+	userFile, err := config.ParseSSHConfigContent(append(append([]byte{}, before...), after...), s.Path)
+	if err != nil {
+		return result, err
+	}
+	external := parseHosts(userFile.Servers)
+
+	state, err := LoadState(s.StatePath)
+	if err != nil {
+		return result, err
+	}
+
+	servers := make(map[string]config.Server)
+	for _, server := range cfg.GetServers() {
+		servers[server.Name] = server
+	}
+
+	names := make(map[string]bool)
+	for _, hosts := range []map[string]Host{managed, external, state.Hosts} {
+		for name := range hosts {
+			names[name] = true
+		}
+	}
+	for name := range servers {
+		names[name] = true
+	}
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+
+	next := &State{Hosts: make(map[string]Host), External: external}
+	write := make(map[string]Host)
+	record := func(name, action, detail string) {
+		result.Changes = append(result.Changes, Change{Host: name, Action: action, Detail: detail})
+	}
+
+	for _, name := range sorted {
+		server, hasOurs := servers[name]
+		base, hasBase := state.Hosts[name]
+		man, hasMan := managed[name]
+		ext, hasExt := external[name]
+
+		if hasOurs && !validHostName(name) {
+			record(name, ActionSkip, "the name can't be an ssh Host")
+			continue
+		}
+
+		// The SSH config's side: a hand edit of the managed section, else an
+		// edit of the user's own block, else the unchanged base
+		hasTheirs := hasMan || hasExt
+		var theirs Host
+		switch {
+		case hasMan && (!hasBase || man != base):
+			theirs = man
+		case hasExt && (!hasBase || ext != state.External[name]):
+			theirs = ext
+		case hasTheirs:
+			theirs = base
+		}
+		theirsChanged := hasTheirs && (!hasBase || theirs != base)
+
+		if !hasOurs {
+			switch {
+			case state.isIgnored(name):
+				next.Ignored = append(next.Ignored, name)
+			case hasBase:
+				// Deleted in sshm: out of the managed section, and not imported again
+				if hasExt {
+					next.Ignored = append(next.Ignored, name)
+					record(name, ActionRemove, fmt.Sprintf("deleted in sshm; still defined in %s, which sshm doesn't edit", userFile.Sources[name]))
+				} else {
+					record(name, ActionRemove, "deleted in sshm")
+				}
+			case hasTheirs:
+				server := theirs.newServer()
+				if !s.DryRun {
+					if err := cfg.AddServer(server); err != nil {
+						record(name, ActionSkip, err.Error())
+						continue
+					}
+				}
+				result.ConfigChanged = true
+				source := userFile.Sources[name]
+				if source == "" {
+					source = s.Path
+				}
+				record(name, ActionImport, "new Host block in "+source)
+				next.Hosts[name] = theirs
+			}
+			continue
+		}
+
+		ours := hostFromServer(server)
+		oursChanged := !hasBase || ours != base
+		final := ours
+		switch {
+		case !hasTheirs:
+			if hasBase {
+				record(name, ActionPush, "missing from the SSH config, written again")
+			} else {
+				record(name, ActionPush, "added to the SSH config")
+			}
+		case ours == theirs:
+		case theirsChanged && !oursChanged:
+			final = theirs
+		case oursChanged && !theirsChanged:
+			record(name, ActionPush, "sshm's edit written to the SSH config")
+		default:
+			switch s.Prefer {
+			case config.SSHSyncPreferSSHM:
+				record(name, ActionPush, "conflict resolved in favor of sshm")
+			case config.SSHSyncPreferSSH:
+				final = theirs
+			default:
+				result.Conflicts = append(result.Conflicts, Conflict{Host: name, SSHM: ours, SSHConfig: theirs})
+				record(name, ActionConflict, "edited in sshm and in the SSH config")
+				if hasMan {
+					write[name] = man
+				}
+				if hasBase {
+					next.Hosts[name] = base
+				}
+				continue
+			}
+		}
+
+		if final != ours {
+			final.apply(&server)
+			if !s.DryRun {
+				if err := cfg.UpdateServer(server); err != nil {
+					record(name, ActionSkip, err.Error())
+					continue
+				}
+			}
+			result.ConfigChanged = true
+			record(name, ActionPull, "edit in the SSH config taken into sshm")
+		}
+		next.Hosts[name] = final
+		// The user's own block suffices while it says the same
+		if !hasExt || ext != final {
+			write[name] = final
+		}
+	}
+
+	rendered := renderManaged(write)
+	var updated []byte
+	if _, _, _, found := splitManaged(content); found {
+		updated = append(append(append([]byte{}, before...), rendered...), after...)
+	} else if len(write) > 0 {
+		updated = rendered
+		if len(content) > 0 {
+			updated = append(append(updated, '\n'), content...)
+		}
+	} else {
+		updated = content
+	}
+	result.FileChanged = !bytes.Equal(updated, content)
+
+	if s.DryRun {
+		return result, nil
+	}
+	if result.FileChanged {
+		if err := writeFile(s.Path, updated); err != nil {
+			return result, err
+		}
+	}
+	if err := next.Save(s.StatePath); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// writeFile replaces the SSH config through a temporary file, keeping its
+// permissions and writing through a symlink to its target, as dotfile managers use
+func writeFile(path string, data []byte) error {
+	if target, err := filepath.EvalSymlinks(path); err == nil {
+		path = target
+	}
+	mode := os.FileMode(0600)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+
+	temporary, err := os.CreateTemp(filepath.Dir(path), ".config.sshm-*")
+	if err != nil {
+		return fmt.Errorf("failed to write SSH config: %w", err)
+	}
+	defer os.Remove(temporary.Name())
+	if _, err := temporary.Write(data); err != nil {
+		temporary.Close()
+		return fmt.Errorf("failed to write SSH config: %w", err)
+	}
+	if err := temporary.Close(); err != nil {
+		return fmt.Errorf("failed to write SSH config: %w", err)
+	}
+	if err := os.Chmod(temporary.Name(), mode); err != nil {
+		return fmt.Errorf("failed to write SSH config: %w", err)
+	}
+	if err := os.Rename(temporary.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace SSH config: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,192 @@
+package sshconfig
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"sshm/internal/config"
+)
+
+const userConfig = `ServerAliveInterval 30
+Include config.d/*
+
+Host legacy
+    HostName legacy.example.com
+    User root
+`
+
+// newTestSyncer writes the SSH config and an included file into a temporary directory
+func newTestSyncer(t *testing.T) *Syncer {
+	t.Helper()
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "config.d"), 0700)
+	os.WriteFile(filepath.Join(dir, "config"), []byte(userConfig), 0600)
+	os.WriteFile(filepath.Join(dir, "config.d", "work"), []byte("Host build\n    HostName build.example.com\n    User ci\n    Port 2200\n"), 0600)
+	return &Syncer{Path: filepath.Join(dir, "config"), StatePath: filepath.Join(dir, "state.json")}
+}
+
+func testConfig() *config.Config {
+	return &config.Config{Servers: []config.Server{
+		{Name: "web1", Hostname: "10.0.0.1", Port: 22, Username: "deploy", AuthType: "key", KeyPath: "~/.ssh/web"},
+	}}
+}
+
+func runSync(t *testing.T, syncer *Syncer, cfg *config.Config) Result {
+	t.Helper()
+	result, err := syncer.Sync(cfg)
+	if err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	return result
+}
+
+func actions(result Result) string {
+	var parts []string
+	for _, change := range result.Changes {
+		parts = append(parts, change.Host+":"+change.Action)
+	}
+	return strings.Join(parts, ",")
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestFirstSync(t *testing.T) {
+	syncer := newTestSyncer(t)
+	cfg := testConfig()
+
+	result := runSync(t, syncer, cfg)
+	if got := actions(result); got != "build:import,legacy:import,web1:push" {
+		t.Errorf("Unexpected changes: %s", got)
+	}
+	if !result.ConfigChanged || !result.FileChanged {
+		t.Errorf("Expected both sides to change, got %+v", result)
+	}
+	build, err := cfg.GetServer("build")
+	if err != nil || build.Port != 2200 || build.Username != "ci" {
+		t.Errorf("Expected the included host to be imported, got %+v, %v", build, err)
+	}
+
+	content := readFile(t, syncer.Path)
+	if !strings.HasPrefix(content, BeginMarker) {
+		t.Errorf("Expected the managed section at the top, got:\n%s", content)
+	}
+	if !strings.Contains(content, "Host web1\n    HostName 10.0.0.1\n    User deploy\n    IdentityFile ~/.ssh/web\nHost *\n"+EndMarker+"\n\n"+userConfig) {
+		t.Errorf("Expected web1 in the managed section and the user's config kept, got:\n%s", content)
+	}
+	if strings.Contains(content, "Host legacy\n    HostName legacy.example.com\n    User root\nHost *") {
+		t.Error("Expected hosts defined by the user to stay out of the managed section")
+	}
+
+	if result := runSync(t, syncer, cfg); len(result.Changes) != 0 || result.FileChanged {
+		t.Errorf("Expected a second sync to change nothing, got %+v", result)
+	}
+}
+
+func TestSyncEdits(t *testing.T) {
+	syncer := newTestSyncer(t)
+	cfg := testConfig()
+	runSync(t, syncer, cfg)
+
+	// Edited in sshm: written to the managed section, overriding the user's block
+	legacy, _ := cfg.GetServer("legacy")
+	legacy.Username = "admin"
+	cfg.UpdateServer(*legacy)
+	if got := actions(runSync(t, syncer, cfg)); got != "legacy:push" {
+		t.Errorf("Expected the sshm edit to be pushed, got %s", got)
+	}
+	if !strings.Contains(readFile(t, syncer.Path), "Host legacy\n    HostName legacy.example.com\n    User admin\n") {
+		t.Errorf("Expected an override of legacy, got:\n%s", readFile(t, syncer.Path))
+	}
+
+	// Edited in the managed section: taken into sshm
+	content := strings.Replace(readFile(t, syncer.Path), "HostName 10.0.0.1", "HostName 10.0.0.9", 1)
+	os.WriteFile(syncer.Path, []byte(content), 0600)
+	if got := actions(runSync(t, syncer, cfg)); got != "web1:pull" {
+		t.Errorf("Expected the managed section edit to be pulled, got %s", got)
+	}
+	if web1, _ := cfg.GetServer("web1"); web1.Hostname != "10.0.0.9" {
+		t.Errorf("Expected the new hostname, got %s", web1.Hostname)
+	}
+
+	// Edited in an included file: taken into sshm
+	included := filepath.Join(filepath.Dir(syncer.Path), "config.d", "work")
+	os.WriteFile(included, []byte("Host build\n    HostName build.example.com\n    User ci\n    Port 2201\n"), 0600)
+	if got := actions(runSync(t, syncer, cfg)); got != "build:pull" {
+		t.Errorf("Expected the included edit to be pulled, got %s", got)
+	}
+	if build, _ := cfg.GetServer("build"); build.Port != 2201 {
+		t.Errorf("Expected the new port, got %d", build.Port)
+	}
+}
+
+func TestSyncConflicts(t *testing.T) {
+	syncer := newTestSyncer(t)
+	cfg := testConfig()
+	runSync(t, syncer, cfg)
+
+	web1, _ := cfg.GetServer("web1")
+	web1.Username = "ops"
+	cfg.UpdateServer(*web1)
+	content := strings.Replace(readFile(t, syncer.Path), "User deploy", "User admin", 1)
+	os.WriteFile(syncer.Path, []byte(content), 0600)
+
+	result := runSync(t, syncer, cfg)
+	if len(result.Conflicts) != 1 || result.Conflicts[0].SSHM.User != "ops" || result.Conflicts[0].SSHConfig.User != "admin" {
+		t.Fatalf("Expected a conflict on web1, got %+v", result)
+	}
+	if !strings.Contains(readFile(t, syncer.Path), "User admin") {
+		t.Error("Expected the conflicting section to be left alone")
+	}
+
+	syncer.Prefer = config.SSHSyncPreferSSH
+	if got := actions(runSync(t, syncer, cfg)); got != "web1:pull" {
+		t.Errorf("Expected the SSH config to win, got %s", got)
+	}
+	if web1, _ := cfg.GetServer("web1"); web1.Username != "admin" {
+		t.Errorf("Expected the SSH config's user, got %s", web1.Username)
+	}
+}
+
+func TestSyncDeletedInSSHM(t *testing.T) {
+	syncer := newTestSyncer(t)
+	cfg := testConfig()
+	runSync(t, syncer, cfg)
+
+	cfg.RemoveServer("web1")
+	cfg.RemoveServer("legacy")
+	if got := actions(runSync(t, syncer, cfg)); got != "legacy:remove,web1:remove" {
+		t.Errorf("Expected both removals, got %s", got)
+	}
+	if strings.Contains(readFile(t, syncer.Path), "Host web1") {
+		t.Error("Expected web1 to leave the managed section")
+	}
+	if result := runSync(t, syncer, cfg); len(result.Changes) != 0 {
+		t.Errorf("Expected legacy not to be imported again, got %s", actions(result))
+	}
+}
+
+func TestSyncDryRun(t *testing.T) {
+	syncer := newTestSyncer(t)
+	syncer.DryRun = true
+	cfg := testConfig()
+
+	result := runSync(t, syncer, cfg)
+	if !result.FileChanged || len(result.Changes) != 3 {
+		t.Errorf("Expected the changes to be reported, got %+v", result)
+	}
+	if readFile(t, syncer.Path) != userConfig || len(cfg.GetServers()) != 1 {
+		t.Error("Expected a dry run to write nothing")
+	}
+	if _, err := os.Stat(syncer.StatePath); !os.IsNotExist(err) {
+		t.Error("Expected no state file after a dry run")
+	}
+}
@@ -0,0 +1,152 @@
+// Package stats summarizes the inventory from the configuration and the
+// connection and status check history
+package stats
+
+import (
+	"sort"
+	"time"
+
+	"sshm/internal/config"
+	"sshm/internal/history"
+)
+
+// AvailabilityWindow is the period the online percentage covers
+const AvailabilityWindow = 24 * time.Hour
+
+// DefaultTop is how many of the most connected servers are listed
+const DefaultTop = 5
+
+// unassigned groups servers without a profile or tag
+const unassigned = "(none)"
+
+// Count is the number of servers in one group
+type Count struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// ServerConnections is the connection count of one server
+type ServerConnections struct {
+	Name           string    `json:"name"`
+	Connections    int       `json:"connections"`
+	LastConnection time.Time `json:"last_connection"`
+}
+
+// Inventory is the summary shown by 'sshm stats' and the TUI stats view
+type Inventory struct {
+	Total          int                 `json:"total"`
+	ByAuthType     []Count             `json:"by_auth_type"`
+	ByProfile      []Count             `json:"by_profile"`
+	ByTag          []Count             `json:"by_tag"`
+	Checks         int                 `json:"checks_24h"`  // Status checks in the availability window
+	Online         int                 `json:"online_24h"`  // Of which found the server online
+	CheckedServers int                 `json:"checked_24h"` // Servers with at least one check
+	MostConnected  []ServerConnections `json:"most_connected"`
+	NeverConnected []string            `json:"never_connected"`
+}
+
+// OnlinePercent returns the share of status checks in the window that found
+// their server online
+func (i Inventory) OnlinePercent() float64 {
+	return history.Availability{Checks: i.Checks, Online: i.Online}.Percent()
+}
+
+// Compute builds the inventory summary. Connection counts and availability come
+// from the history database; servers absent from connections were never
+// connected to. At most top servers are listed as most connected.
+func Compute(cfg *config.Config, connections map[string]history.ConnectionCount, availability map[string]history.Availability, top int) Inventory {
+	servers := cfg.GetServers()
+	inventory := Inventory{Total: len(servers)}
+
+	authTypes := make(map[string]int)
+	profiles := make(map[string]int)
+	tags := make(map[string]int)
+	for _, server := range servers {
+		authTypes[server.AuthType]++
+
+		inProfile := false
+		for _, profile := range cfg.GetProfiles() {
+			for _, name := range profile.Servers {
+				if name == server.Name {
+					profiles[profile.Name]++
+					inProfile = true
+					break
+				}
+			}
+		}
+		if !inProfile {
+			profiles[unassigned]++
+		}
+
+		if len(server.Tags) == 0 {
+			tags[unassigned]++
+		}
+		for _, tag := range server.Tags {
+			tags[tag]++
+		}
+
+		if a, ok := availability[server.Name]; ok && a.Checks > 0 {
+			inventory.Checks += a.Checks
+			inventory.Online += a.Online
+			inventory.CheckedServers++
+		}
+
+		count, ok := connections[server.Name]
+		if !ok || count.Total == 0 {
+			inventory.NeverConnected = append(inventory.NeverConnected, server.Name)
+			continue
+		}
+		inventory.MostConnected = append(inventory.MostConnected, ServerConnections{
+			Name:           server.Name,
+			Connections:    count.Total,
+			LastConnection: count.LastConnection,
+		})
+	}
+
+	inventory.ByAuthType = sortedCounts(authTypes)
+	inventory.ByProfile = sortedCounts(profiles)
+	inventory.ByTag = sortedCounts(tags)
+	sort.Strings(inventory.NeverConnected)
+
+	sort.Slice(inventory.MostConnected, func(a, b int) bool {
+		x, y := inventory.MostConnected[a], inventory.MostConnected[b]
+		if x.Connections != y.Connections {
+			return x.Connections > y.Connections
+		}
+		return x.Name < y.Name
+	})
+	if top >= 0 && len(inventory.MostConnected) > top {
+		inventory.MostConnected = inventory.MostConnected[:top]
+	}
+
+	return inventory
+}
+
+// sortedCounts orders groups by size, largest first, then by name
+func sortedCounts(groups map[string]int) []Count {
+	counts := make([]Count, 0, len(groups))
+	for name, count := range groups {
+		counts = append(counts, Count{Name: name, Count: count})
+	}
+	sort.Slice(counts, func(a, b int) bool {
+		if counts[a].Count != counts[b].Count {
+			return counts[a].Count > counts[b].Count
+		}
+		return counts[a].Name < counts[b].Name
+	})
+	return counts
+}
+
+// FromHistory computes the inventory summary with the connection counts and the
+// last 24 hours of status checks recorded in the history database
+func FromHistory(cfg *config.Config, h *history.HistoryManager, top int) (Inventory, error) {
+	connections, err := h.GetConnectionCounts()
+	if err != nil {
+		return Inventory{}, err
+	}
+	availability, err := h.GetAvailability(time.Now().Add(-AvailabilityWindow))
+	if err != nil {
+		return Inventory{}, err
+	}
+	return Compute(cfg, connections, availability, top), nil
+}
@@ -0,0 +1,64 @@
+package stats
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"sshm/internal/config"
+	"sshm/internal/history"
+)
+
+func TestCompute(t *testing.T) {
+	cfg := &config.Config{
+		Servers: []config.Server{
+			{Name: "web1", AuthType: "key", Tags: []string{"web", "prod"}},
+			{Name: "web2", AuthType: "key", Tags: []string{"web"}},
+			{Name: "db1", AuthType: "password", Tags: []string{"prod"}},
+			{Name: "lab", AuthType: "key"},
+		},
+		Profiles: []config.Profile{
+			{Name: "production", Servers: []string{"web1", "db1"}},
+			{Name: "web", Servers: []string{"web1", "web2"}},
+		},
+	}
+	last := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	connections := map[string]history.ConnectionCount{
+		"web1":    {Total: 3, Successful: 3, LastConnection: last},
+		"db1":     {Total: 7, Successful: 6},
+		"web2":    {Total: 3, Successful: 1},
+		"removed": {Total: 50},
+	}
+	availability := map[string]history.Availability{
+		"web1":    {Checks: 10, Online: 10},
+		"db1":     {Checks: 10, Online: 5},
+		"removed": {Checks: 10},
+	}
+
+	inventory := Compute(cfg, connections, availability, 2)
+
+	if inventory.Total != 4 {
+		t.Errorf("Total = %d, want 4", inventory.Total)
+	}
+	if want := []Count{{"key", 3}, {"password", 1}}; !reflect.DeepEqual(inventory.ByAuthType, want) {
+		t.Errorf("ByAuthType = %+v, want %+v", inventory.ByAuthType, want)
+	}
+	if want := []Count{{"production", 2}, {"web", 2}, {"(none)", 1}}; !reflect.DeepEqual(inventory.ByProfile, want) {
+		t.Errorf("ByProfile = %+v, want %+v", inventory.ByProfile, want)
+	}
+	if want := []Count{{"prod", 2}, {"web", 2}, {"(none)", 1}}; !reflect.DeepEqual(inventory.ByTag, want) {
+		t.Errorf("ByTag = %+v, want %+v", inventory.ByTag, want)
+	}
+
+	// Servers no longer in the inventory don't count
+	if inventory.Checks != 20 || inventory.Online != 15 || inventory.CheckedServers != 2 || inventory.OnlinePercent() != 75 {
+		t.Errorf("Unexpected availability: %+v", inventory)
+	}
+	want := []ServerConnections{{Name: "db1", Connections: 7}, {Name: "web1", Connections: 3, LastConnection: last}}
+	if !reflect.DeepEqual(inventory.MostConnected, want) {
+		t.Errorf("MostConnected = %+v, want %+v", inventory.MostConnected, want)
+	}
+	if !reflect.DeepEqual(inventory.NeverConnected, []string{"lab"}) {
+		t.Errorf("NeverConnected = %v, want [lab]", inventory.NeverConnected)
+	}
+}
@@ -0,0 +1,114 @@
+package stats
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"sshm/internal/history"
+)
+
+// Groupings of the time report
+const (
+	TimeByServer  = "server"
+	TimeByProfile = "profile"
+)
+
+// DefaultTimeWeeks is how many weeks the time report covers, the current one included
+const DefaultTimeWeeks = 4
+
+// WeekTime is the time spent attached to the sessions of one server or
+// profile during one week
+type WeekTime struct {
+	Week     time.Time     `json:"week"` // Monday 00:00, local time
+	Name     string        `json:"name"`
+	Duration time.Duration `json:"duration"`
+	Hours    float64       `json:"hours"`
+}
+
+// WeekStart returns the Monday 00:00 starting the week of t, in t's location
+func WeekStart(t time.Time) time.Time {
+	offset := (int(t.Weekday()) + 6) % 7 // Days since Monday
+	year, month, day := t.Date()
+	return time.Date(year, month, day-offset, 0, 0, 0, 0, t.Location())
+}
+
+// TimeReport sums the time attached to sessions per week and server or profile,
+// counting only time after since. Sessions spanning weeks are split between
+// them. By server, profile sessions are reported as "profile <name>" since they
+// cover several servers; by profile, sessions of servers without a profile are
+// reported as "(none)". Weeks come oldest first, and within a week the most time
+// comes first.
+func TimeReport(entries []history.SessionTime, by string, since time.Time) ([]WeekTime, error) {
+	if by != TimeByServer && by != TimeByProfile {
+		return nil, fmt.Errorf("unknown grouping '%s' (use %s or %s)", by, TimeByServer, TimeByProfile)
+	}
+
+	type key struct {
+		week time.Time
+		name string
+	}
+	totals := make(map[key]time.Duration)
+	for _, entry := range entries {
+		name := entry.ServerName
+		if by == TimeByProfile {
+			name = entry.ProfileName
+		} else if name == "" {
+			name = "profile " + entry.ProfileName
+		}
+		if name == "" {
+			name = unassigned
+		}
+
+		start, end := entry.AttachedAt.Local(), entry.DetachedAt.Local()
+		if start.Before(since) {
+			start = since
+		}
+		for start.Before(end) {
+			week := WeekStart(start)
+			next := week.AddDate(0, 0, 7)
+			stop := end
+			if next.Before(stop) {
+				stop = next
+			}
+			totals[key{week, name}] += stop.Sub(start)
+			start = stop
+		}
+	}
+
+	report := make([]WeekTime, 0, len(totals))
+	for k, duration := range totals {
+		report = append(report, WeekTime{Week: k.week, Name: k.name, Duration: duration, Hours: duration.Hours()})
+	}
+	sort.Slice(report, func(a, b int) bool {
+		x, y := report[a], report[b]
+		if !x.Week.Equal(y.Week) {
+			return x.Week.Before(y.Week)
+		}
+		if x.Duration != y.Duration {
+			return x.Duration > y.Duration
+		}
+		return x.Name < y.Name
+	})
+	return report, nil
+}
+
+// TimeFromHistory reports the time spent per week over the given number of
+// weeks, the current one included, from the session time in the history database
+func TimeFromHistory(h *history.HistoryManager, by string, weeks int, now time.Time) ([]WeekTime, error) {
+	if weeks <= 0 {
+		weeks = DefaultTimeWeeks
+	}
+	since := WeekStart(now.Local()).AddDate(0, 0, -7*(weeks-1))
+	entries, err := h.GetSessionTimes(since)
+	if err != nil {
+		return nil, err
+	}
+	return TimeReport(entries, by, since)
+}
+
+// FormatHours renders a duration as hours and minutes, e.g. "3h05m"
+func FormatHours(d time.Duration) string {
+	d = d.Round(time.Minute)
+	return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+}
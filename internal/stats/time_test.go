@@ -0,0 +1,78 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"sshm/internal/history"
+)
+
+func TestWeekStart(t *testing.T) {
+	sunday := time.Date(2026, 10, 18, 23, 30, 0, 0, time.UTC)
+	if got := WeekStart(sunday); !got.Equal(time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("WeekStart(Sunday) = %v", got)
+	}
+	monday := time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC)
+	if got := WeekStart(monday); !got.Equal(monday) {
+		t.Errorf("WeekStart(Monday) = %v", got)
+	}
+}
+
+func TestTimeReport(t *testing.T) {
+	at := func(day, hour int) time.Time { return time.Date(2026, 10, day, hour, 0, 0, 0, time.Local) }
+	entries := []history.SessionTime{
+		{ServerName: "web", ProfileName: "prod", AttachedAt: at(5, 9), DetachedAt: at(5, 12)},
+		{ServerName: "db", ProfileName: "prod", AttachedAt: at(6, 9), DetachedAt: at(6, 10)},
+		// Sunday night into Monday is split between the weeks
+		{ServerName: "web", ProfileName: "prod", AttachedAt: at(11, 22), DetachedAt: at(12, 1)},
+		{ProfileName: "staging", AttachedAt: at(13, 9), DetachedAt: at(13, 11)},
+		{ServerName: "scratch", AttachedAt: at(13, 14), DetachedAt: at(13, 15)},
+		// Only the part after since counts
+		{ServerName: "old", AttachedAt: at(4, 20), DetachedAt: at(5, 1)},
+	}
+	since := at(5, 0)
+
+	byServer, err := TimeReport(entries, TimeByServer, since)
+	if err != nil {
+		t.Fatalf("TimeReport() error = %v", err)
+	}
+	want := []struct {
+		week  int
+		name  string
+		hours float64
+	}{
+		{5, "web", 5},
+		{5, "db", 1},
+		{5, "old", 1},
+		{12, "profile staging", 2},
+		{12, "scratch", 1},
+		{12, "web", 1},
+	}
+	if len(byServer) != len(want) {
+		t.Fatalf("expected %d rows, got %+v", len(want), byServer)
+	}
+	for i, w := range want {
+		got := byServer[i]
+		if !got.Week.Equal(at(w.week, 0)) || got.Name != w.name || got.Hours != w.hours {
+			t.Errorf("row %d = %s %s %.1fh, want week of the %d %s %.1fh", i, got.Week.Format("2006-01-02"), got.Name, got.Hours, w.week, w.name, w.hours)
+		}
+	}
+
+	byProfile, err := TimeReport(entries, TimeByProfile, since)
+	if err != nil {
+		t.Fatalf("TimeReport() error = %v", err)
+	}
+	if len(byProfile) != 5 || byProfile[0].Name != "prod" || byProfile[0].Hours != 6 || byProfile[1].Name != unassigned {
+		t.Errorf("unexpected report by profile: %+v", byProfile)
+	}
+
+	if _, err := TimeReport(entries, "team", since); err == nil {
+		t.Error("expected an error for an unknown grouping")
+	}
+}
+
+func TestFormatHours(t *testing.T) {
+	if got := FormatHours(3*time.Hour + 5*time.Minute + 20*time.Second); got != "3h05m" {
+		t.Errorf("FormatHours() = %q", got)
+	}
+}
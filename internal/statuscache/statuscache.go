@@ -0,0 +1,106 @@
+package statuscache
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"sshm/internal/config"
+)
+
+// FileName is the status cache file stored next to config.yaml
+const FileName = "status-cache.json"
+
+// Entry is the last known status of one server
+type Entry struct {
+	Status    string        `json:"status"`
+	Latency   time.Duration `json:"latency,omitempty"`
+	CheckedAt time.Time     `json:"checked_at"`
+	Address   string        `json:"address,omitempty"` // IP address the check connected to
+}
+
+// Cache holds the last known status of every checked server. It is written by
+// whatever runs status checks (the TUI or 'sshm daemon') and read by cheap
+// consumers such as 'sshm statusline' without touching the network.
+type Cache struct {
+	UpdatedAt time.Time        `json:"updated_at"`
+	Servers   map[string]Entry `json:"servers"`
+
+	// Set by 'sshm daemon' at every round; while it runs, it owns the webhooks
+	DaemonAt       time.Time     `json:"daemon_at,omitempty"`
+	DaemonInterval time.Duration `json:"daemon_interval,omitempty"`
+}
+
+// DefaultPath returns the status cache path next to the configuration file
+func DefaultPath() (string, error) {
+	configPath, err := config.DefaultConfigPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(configPath), FileName), nil
+}
+
+// Load reads the cache; a missing file yields an empty cache
+func Load(path string) (*Cache, error) {
+	cache := &Cache{Servers: make(map[string]Entry)}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return cache, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read status cache: %w", err)
+	}
+	if err := json.Unmarshal(data, cache); err != nil {
+		return nil, fmt.Errorf("failed to parse status cache: %w", err)
+	}
+	if cache.Servers == nil {
+		cache.Servers = make(map[string]Entry)
+	}
+	return cache, nil
+}
+
+// Save writes the cache atomically so readers never see a partial file
+func (c *Cache) Save(path string) error {
+	c.UpdatedAt = time.Now()
+	data, err := json.Marshal(c)
+	if err != nil {
+		return fmt.Errorf("failed to marshal status cache: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create status cache directory: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write status cache: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write status cache: %w", err)
+	}
+	return nil
+}
+
+// Offline returns the names of servers whose last check did not succeed
+func (c *Cache) Offline() []string {
+	var offline []string
+	for name, entry := range c.Servers {
+		if entry.Status != "online" && entry.Status != "checking" && entry.Status != "" {
+			offline = append(offline, name)
+		}
+	}
+	return offline
+}
+
+// DaemonRunning reports whether 'sshm daemon' finished a round recently enough
+// to still be running, allowing one late round
+func (c *Cache) DaemonRunning() bool {
+	return !c.DaemonAt.IsZero() && time.Since(c.DaemonAt) <= 2*c.DaemonInterval
+}
+
+// IsStale reports whether the cache was not updated within maxAge
+func (c *Cache) IsStale(maxAge time.Duration) bool {
+	return c.UpdatedAt.IsZero() || time.Since(c.UpdatedAt) > maxAge
+}
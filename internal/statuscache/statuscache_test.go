@@ -0,0 +1,56 @@
+package statuscache
+
+import (
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestSaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+
+	cache, err := Load(path)
+	if err != nil {
+		t.Fatalf("Expected empty cache for missing file, got: %v", err)
+	}
+	if !cache.IsStale(time.Minute) {
+		t.Error("Expected a never-written cache to be stale")
+	}
+
+	cache.Servers["web1"] = Entry{Status: "online", Latency: 20 * time.Millisecond, CheckedAt: time.Now()}
+	cache.Servers["db1"] = Entry{Status: "unreachable", CheckedAt: time.Now()}
+	cache.Servers["new"] = Entry{Status: "checking"}
+	if err := cache.Save(path); err != nil {
+		t.Fatalf("Expected no error saving, got: %v", err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Expected no error loading, got: %v", err)
+	}
+	if loaded.IsStale(time.Minute) {
+		t.Error("Expected freshly saved cache not to be stale")
+	}
+	if loaded.Servers["web1"].Latency != 20*time.Millisecond {
+		t.Errorf("Unexpected entry: %+v", loaded.Servers["web1"])
+	}
+
+	if loaded.DaemonRunning() {
+		t.Error("Expected a cache written by the TUI not to mark the daemon as running")
+	}
+	loaded.DaemonAt, loaded.DaemonInterval = time.Now().Add(-90*time.Second), time.Minute
+	if !loaded.DaemonRunning() {
+		t.Error("Expected a daemon one round late to still be running")
+	}
+	loaded.DaemonAt = time.Now().Add(-3 * time.Minute)
+	if loaded.DaemonRunning() {
+		t.Error("Expected a daemon silent for three rounds to be stopped")
+	}
+
+	offline := loaded.Offline()
+	sort.Strings(offline)
+	if len(offline) != 1 || offline[0] != "db1" {
+		t.Errorf("Expected only db1 offline, got %v", offline)
+	}
+}
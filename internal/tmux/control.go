@@ -0,0 +1,243 @@
+package tmux
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// controlReplyTimeout bounds the wait for a control mode reply so a wedged tmux
+// server can't hang the caller
+const controlReplyTimeout = 5 * time.Second
+
+// ErrControlClosed is returned by commands sent after the control client exited,
+// e.g. because the tmux server stopped or its last session closed
+var ErrControlClosed = errors.New("tmux control client closed")
+
+// controlReply is the output of one command, framed by %begin and %end or %error
+type controlReply struct {
+	lines []string
+	err   error
+}
+
+// ControlClient is a persistent tmux control mode (-C) client. Commands are sent
+// over one connection and their replies arrive as structured blocks, so queries
+// need no shell pipelines and see a consistent server state.
+type ControlClient struct {
+	stdin   io.WriteCloser
+	wait    func() error
+	mu      sync.Mutex // Serializes commands so replies match their requests
+	replies chan controlReply
+	events  chan string
+	done    chan struct{}
+}
+
+// StartControlClient attaches a read-only control mode client to the tmux server.
+// The client ignores pane output and its size, so it neither slows down nor
+// resizes the user's sessions. It fails when no tmux server or session exists.
+func StartControlClient() (*ControlClient, error) {
+	cmd := execCommand("tmux", "-C", "attach-session", "-r", "-f", "no-output,ignore-size")
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start tmux control client: %w", err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start tmux control client: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start tmux control client: %w", err)
+	}
+
+	c := &ControlClient{
+		stdin:   stdin,
+		wait:    cmd.Wait,
+		replies: make(chan controlReply, 1),
+		events:  make(chan string, 64),
+		done:    make(chan struct{}),
+	}
+	go c.read(stdout)
+
+	// tmux answers the attach itself with the first block
+	if _, err := c.awaitReply(); err != nil {
+		c.Close()
+		return nil, fmt.Errorf("failed to attach tmux control client: %w", err)
+	}
+	return c, nil
+}
+
+// read splits the control mode stream into command replies and notifications
+func (c *ControlClient) read(stdout io.Reader) {
+	defer close(c.done)
+	defer close(c.events)
+
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	var block []string
+	inBlock := false
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case !inBlock && strings.HasPrefix(line, "%begin "):
+			inBlock, block = true, nil
+		case inBlock && strings.HasPrefix(line, "%end "):
+			inBlock = false
+			c.replies <- controlReply{lines: block}
+		case inBlock && strings.HasPrefix(line, "%error "):
+			inBlock = false
+			c.replies <- controlReply{err: errors.New(strings.Join(block, "; "))}
+		case inBlock:
+			block = append(block, line)
+		case strings.HasPrefix(line, "%"):
+			// Notifications are dropped rather than blocking replies when nobody reads them
+			select {
+			case c.events <- line:
+			default:
+			}
+		}
+	}
+}
+
+// awaitReply waits for the next reply block
+func (c *ControlClient) awaitReply() ([]string, error) {
+	select {
+	case reply := <-c.replies:
+		return reply.lines, reply.err
+	case <-c.done:
+		// A reply may have been queued just before the stream ended
+		select {
+		case reply := <-c.replies:
+			return reply.lines, reply.err
+		default:
+			return nil, ErrControlClosed
+		}
+	case <-time.After(controlReplyTimeout):
+		// A late reply would be taken for the next command's; detach instead
+		c.stdin.Close()
+		return nil, fmt.Errorf("timed out waiting for tmux")
+	}
+}
+
+// Command runs a tmux command and returns its output lines
+func (c *ControlClient) Command(args ...string) ([]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.Alive() {
+		return nil, ErrControlClosed
+	}
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = `"` + escapeTmuxDoubleQuoted(arg) + `"`
+	}
+	if _, err := io.WriteString(c.stdin, strings.Join(quoted, " ")+"\n"); err != nil {
+		return nil, ErrControlClosed
+	}
+	return c.awaitReply()
+}
+
+// Events returns tmux notifications such as "%sessions-changed"; the channel is
+// closed when the client exits
+func (c *ControlClient) Events() <-chan string {
+	return c.events
+}
+
+// Alive reports whether the client is still connected
+func (c *ControlClient) Alive() bool {
+	select {
+	case <-c.done:
+		return false
+	default:
+		return true
+	}
+}
+
+// Close detaches the client
+func (c *ControlClient) Close() error {
+	c.stdin.Close()
+	select {
+	case <-c.done:
+	case <-time.After(controlReplyTimeout):
+	}
+	return c.wait()
+}
+
+// SessionState is a session as reported by the tmux server
+type SessionState struct {
+	Name     string
+	Windows  int
+	Clients  int // Attached clients, not counting control mode clients such as sshm's
+	Activity time.Time
+	Created  time.Time
+}
+
+// Status describes whether anyone is attached to the session
+func (s SessionState) Status() string {
+	switch {
+	case s.Clients > 1:
+		return "multi-attached"
+	case s.Clients == 1:
+		return "attached"
+	default:
+		return "detached"
+	}
+}
+
+// sessionStateFormat and clientFormat separate fields with tabs so session names
+// containing spaces are read back intact
+const (
+	sessionStateFormat = "#{session_name}\t#{session_windows}\t#{session_activity}\t#{session_created}"
+	clientFormat       = "#{client_session}\t#{client_control_mode}"
+)
+
+// Sessions returns the state of every session
+func (c *ControlClient) Sessions() ([]SessionState, error) {
+	sessionLines, err := c.Command("list-sessions", "-F", sessionStateFormat)
+	if err != nil {
+		return nil, err
+	}
+	clientLines, err := c.Command("list-clients", "-F", clientFormat)
+	if err != nil {
+		return nil, err
+	}
+	return parseSessionStates(sessionLines, clientLines), nil
+}
+
+// parseSessionStates combines list-sessions and list-clients output
+func parseSessionStates(sessionLines, clientLines []string) []SessionState {
+	clients := make(map[string]int)
+	for _, line := range clientLines {
+		fields := strings.Split(line, "\t")
+		if len(fields) == 2 && fields[1] != "1" {
+			clients[fields[0]]++
+		}
+	}
+
+	var sessions []SessionState
+	for _, line := range sessionLines {
+		fields := strings.Split(line, "\t")
+		if len(fields) < 4 {
+			continue
+		}
+		state := SessionState{Name: fields[0], Clients: clients[fields[0]]}
+		state.Windows, _ = strconv.Atoi(fields[1])
+		state.Activity = parseUnixTime(fields[2])
+		state.Created = parseUnixTime(fields[3])
+		sessions = append(sessions, state)
+	}
+	return sessions
+}
+
+// parseUnixTime parses a tmux timestamp, returning the zero time when invalid
+func parseUnixTime(value string) time.Time {
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || seconds <= 0 {
+		return time.Time{}
+	}
+	return time.Unix(seconds, 0)
+}
@@ -0,0 +1,131 @@
+package tmux
+
+import (
+	"os/exec"
+	"testing"
+	"time"
+)
+
+// fakeControlMode mimics tmux -C: it acknowledges the attach and then answers
+// each command with a block echoing the command line, or an error for "bogus"
+func fakeControlMode(name string, arg ...string) *exec.Cmd {
+	script := `printf '%%begin 1 1 0\n%%end 1 1 0\n%%session-changed $1 web\n'
+while read -r line; do
+  case "$line" in
+    *bogus*) printf '%%begin 2 2 1\nunknown command: bogus\n%%error 2 2 1\n' ;;
+    *) printf '%%begin 2 2 1\n%s\n%%end 2 2 1\n' "$line" ;;
+  esac
+done`
+	return exec.Command("sh", "-c", script)
+}
+
+func TestControlClientCommand(t *testing.T) {
+	original := execCommand
+	defer func() { execCommand = original }()
+	execCommand = fakeControlMode
+
+	client, err := StartControlClient()
+	if err != nil {
+		t.Fatalf("StartControlClient() error = %v", err)
+	}
+	defer client.Close()
+
+	lines, err := client.Command("list-sessions", "-F", "#{session_name}\t$HOME \"x\"")
+	if err != nil {
+		t.Fatalf("Command() error = %v", err)
+	}
+	expected := `"list-sessions" "-F" "#{session_name}` + "\t" + `\$HOME \"x\""`
+	if len(lines) != 1 || lines[0] != expected {
+		t.Errorf("Command() = %q, want [%q]", lines, expected)
+	}
+
+	if _, err := client.Command("bogus"); err == nil || err.Error() != "unknown command: bogus" {
+		t.Errorf("Expected the tmux error to be returned, got %v", err)
+	}
+
+	select {
+	case event := <-client.Events():
+		if event != "%session-changed $1 web" {
+			t.Errorf("Unexpected event %q", event)
+		}
+	case <-time.After(time.Second):
+		t.Error("Expected the session-changed notification")
+	}
+}
+
+func TestControlClientClosed(t *testing.T) {
+	original := execCommand
+	defer func() { execCommand = original }()
+	execCommand = func(name string, arg ...string) *exec.Cmd {
+		return exec.Command("printf", "%%begin 1 1 0\n%%end 1 1 0\n%%exit\n")
+	}
+
+	client, err := StartControlClient()
+	if err != nil {
+		t.Fatalf("StartControlClient() error = %v", err)
+	}
+	<-client.done
+	if client.Alive() {
+		t.Error("Expected the client to be closed after tmux exited")
+	}
+	if _, err := client.Command("list-sessions"); err != ErrControlClosed {
+		t.Errorf("Expected ErrControlClosed, got %v", err)
+	}
+
+	execCommand = func(name string, arg ...string) *exec.Cmd {
+		return exec.Command("sh", "-c", "echo 'no sessions' >&2; exit 1")
+	}
+	if _, err := StartControlClient(); err == nil {
+		t.Error("Expected an error when tmux refuses to attach")
+	}
+}
+
+func TestParseSessionStates(t *testing.T) {
+	sessions := []string{
+		"web\t2\t1700000000\t1690000000",
+		"my group\t3\t1700000500\t1690000000",
+		"malformed",
+	}
+	clients := []string{
+		"web\t0",
+		"web\t1",
+		"my group\t0",
+		"my group\t0",
+	}
+
+	states := parseSessionStates(sessions, clients)
+	if len(states) != 2 {
+		t.Fatalf("Expected 2 sessions, got %d", len(states))
+	}
+	if states[0].Name != "web" || states[0].Windows != 2 || states[0].Clients != 1 || states[0].Status() != "attached" {
+		t.Errorf("Unexpected state for web: %+v", states[0])
+	}
+	if !states[0].Activity.Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("Unexpected activity %v", states[0].Activity)
+	}
+	if states[1].Name != "my group" || states[1].Status() != "multi-attached" {
+		t.Errorf("Unexpected state for my group: %+v", states[1])
+	}
+	if (SessionState{}).Status() != "detached" {
+		t.Error("Expected a session without clients to be detached")
+	}
+}
+
+func TestFormatActivityTime(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	tests := []struct {
+		activity time.Time
+		expected string
+	}{
+		{time.Time{}, "unknown"},
+		{now.Add(-10 * time.Second), "just now"},
+		{now.Add(-5 * time.Minute), "5m ago"},
+		{now.Add(-3 * time.Hour), "3h ago"},
+		{now.Add(-50 * time.Hour), "2d ago"},
+	}
+	for _, tt := range tests {
+		if got := formatActivityTime(tt.activity, now); got != tt.expected {
+			t.Errorf("formatActivityTime(%v) = %q, want %q", tt.activity, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package tmux
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// outputWatchHistory is how many lines of history each capture reaches back,
+// enough to catch bursts of output between two checks
+const outputWatchHistory = 200
+
+// OutputWatch follows the output of every pane of a detached session for lines
+// matching a pattern. Only output printed after the watch started is matched.
+type OutputWatch struct {
+	Session string
+	Pattern *regexp.Regexp
+	seen    map[string][]string // Last capture of each pane, by pane id
+}
+
+// OutputMatch is a new line of output matching a watch's pattern
+type OutputMatch struct {
+	Window string // Name of the window the line was printed in
+	Line   string
+}
+
+// NewOutputWatch compiles the pattern watched for in a session's output
+func NewOutputWatch(sessionName, pattern string) (*OutputWatch, error) {
+	if strings.TrimSpace(pattern) == "" {
+		return nil, fmt.Errorf("a pattern to watch for is required")
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern '%s': %w", pattern, err)
+	}
+	return &OutputWatch{Session: sessionName, Pattern: re}, nil
+}
+
+// CheckOutput captures the panes of the watched session and returns the lines
+// printed since the last check that match the pattern. The first check only
+// records what the panes show.
+func (m *Manager) CheckOutput(watch *OutputWatch) ([]OutputMatch, error) {
+	panes, err := m.query("list-panes", "-s", "-t", watch.Session, "-F", "#{pane_id}\t#{window_name}")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list panes of session '%s': %w", watch.Session, err)
+	}
+
+	first := watch.seen == nil
+	seen := make(map[string][]string, len(panes))
+	var matches []OutputMatch
+	for _, pane := range panes {
+		id, window, _ := strings.Cut(pane, "\t")
+		capture, err := m.query("capture-pane", "-p", "-J", "-t", id, "-S", fmt.Sprintf("-%d", outputWatchHistory))
+		if err != nil {
+			continue
+		}
+		capture = trimTrailingBlank(capture)
+		seen[id] = capture
+
+		previous, known := watch.seen[id]
+		if first {
+			continue
+		}
+		if !known {
+			previous = nil // A window opened since the last check: all of it is new
+		}
+		for _, line := range newOutputLines(previous, capture) {
+			if watch.Pattern.MatchString(line) {
+				matches = append(matches, OutputMatch{Window: window, Line: strings.TrimSpace(line)})
+			}
+		}
+	}
+	watch.seen = seen
+	return matches, nil
+}
+
+// newOutputLines returns the lines of current that weren't in previous, the
+// earlier capture of the same pane. Output scrolls, so the new lines follow
+// the longest tail of previous that current starts with. The last line of
+// previous may have been a prompt or partial line completed since; when the
+// whole of previous can't be found, it is matched without it.
+func newOutputLines(previous, current []string) []string {
+	if len(previous) == 0 {
+		return current
+	}
+	if overlap := outputOverlap(previous, current); overlap > 0 {
+		return current[overlap:]
+	}
+	if overlap := outputOverlap(previous[:len(previous)-1], current); overlap > 0 {
+		return current[overlap:]
+	}
+	return current
+}
+
+// outputOverlap returns the length of the longest tail of previous that
+// current starts with
+func outputOverlap(previous, current []string) int {
+	for start := 0; start < len(previous); start++ {
+		tail := previous[start:]
+		if len(tail) > len(current) {
+			continue
+		}
+		matched := true
+		for i, line := range tail {
+			if current[i] != line {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return len(tail)
+		}
+	}
+	return 0
+}
+
+// trimTrailingBlank drops the empty lines below the last output of a pane
+func trimTrailingBlank(lines []string) []string {
+	end := len(lines)
+	for end > 0 && strings.TrimSpace(lines[end-1]) == "" {
+		end--
+	}
+	return lines[:end]
+}
@@ -0,0 +1,84 @@
+package tmux
+
+import (
+	"os/exec"
+	"reflect"
+	"testing"
+)
+
+func TestNewOutputLines(t *testing.T) {
+	tests := []struct {
+		name     string
+		previous []string
+		current  []string
+		want     []string
+	}{
+		{"nothing new", []string{"a", "b"}, []string{"a", "b"}, nil},
+		{"appended", []string{"a", "b"}, []string{"a", "b", "c"}, []string{"c"}},
+		{"scrolled", []string{"a", "b", "c"}, []string{"b", "c", "d", "e"}, []string{"d", "e"}},
+		{"repeated line", []string{"a"}, []string{"a", "a"}, []string{"a"}},
+		{"prompt completed", []string{"out", "$ "}, []string{"out", "$ make", "ERROR"}, []string{"$ make", "ERROR"}},
+		{"cleared", []string{"a", "b"}, []string{"x"}, []string{"x"}},
+		{"first capture", nil, []string{"a"}, []string{"a"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newOutputLines(tt.previous, tt.current)
+			if len(got) == 0 && len(tt.want) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("newOutputLines() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewOutputWatchRejectsInvalidPatterns(t *testing.T) {
+	if _, err := NewOutputWatch("deploy", ""); err == nil {
+		t.Error("Expected an empty pattern to be rejected")
+	}
+	if _, err := NewOutputWatch("deploy", "ERROR("); err == nil {
+		t.Error("Expected an invalid regular expression to be rejected")
+	}
+}
+
+func TestCheckOutput(t *testing.T) {
+	original := execCommand
+	defer func() { execCommand = original }()
+	screen := "building"
+	execCommand = func(name string, arg ...string) *exec.Cmd {
+		switch arg[0] {
+		case "list-panes":
+			return exec.Command("printf", "%%1\\tweb-01\\n")
+		case "capture-pane":
+			return exec.Command("printf", "%s\n\n", screen)
+		}
+		return exec.Command("true")
+	}
+
+	manager := &Manager{}
+	watch, err := NewOutputWatch("deploy", "ERROR|deploy finished")
+	if err != nil {
+		t.Fatalf("NewOutputWatch() error = %v", err)
+	}
+
+	screen = "ERROR from an earlier run"
+	if matches, err := manager.CheckOutput(watch); err != nil || len(matches) != 0 {
+		t.Fatalf("Expected the first check to only record the output, got %v, %v", matches, err)
+	}
+
+	screen = "ERROR from an earlier run\nstep 1\ndeploy finished in 3s"
+	matches, err := manager.CheckOutput(watch)
+	if err != nil {
+		t.Fatalf("CheckOutput() error = %v", err)
+	}
+	want := []OutputMatch{{Window: "web-01", Line: "deploy finished in 3s"}}
+	if !reflect.DeepEqual(matches, want) {
+		t.Errorf("CheckOutput() = %v, want %v", matches, want)
+	}
+
+	if matches, _ := manager.CheckOutput(watch); len(matches) != 0 {
+		t.Errorf("Expected no matches without new output, got %v", matches)
+	}
+}
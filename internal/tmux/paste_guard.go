@@ -0,0 +1,55 @@
+package tmux
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PasteGuardOption is the session option marking sessions whose pastes are guarded
+const PasteGuardOption = "@sshm-paste-guard"
+
+// GuardPastes routes the paste key (prefix + ]) of the session through command,
+// which decides whether to paste or ask first. The session, pane and client are
+// appended as arguments. Other sessions keep the plain bracketed paste.
+func (m *Manager) GuardPastes(sessionName, command string) error {
+	if output, err := execCommand("tmux", "set-option", "-t", sessionName, PasteGuardOption, "1").CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to mark session '%s' for the paste guard: %s", sessionName, strings.TrimSpace(string(output)))
+	}
+
+	guard := fmt.Sprintf("run-shell -b \"%s\"", escapeTmuxDoubleQuoted(command+" '#{session_name}' '#{pane_id}' '#{client_name}'"))
+	condition := fmt.Sprintf("#{%s}", PasteGuardOption)
+	cmd := execCommand("tmux", "bind-key", "-T", "prefix", "]", "if-shell", "-F", condition, guard, "paste-buffer -p")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to bind the guarded paste key: %s", strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// BufferContents returns the most recent paste buffer
+func (m *Manager) BufferContents() (string, error) {
+	output, err := execCommand("tmux", "show-buffer").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to read the paste buffer: %w", err)
+	}
+	return string(output), nil
+}
+
+// PasteBuffer pastes the most recent buffer into a pane, bracketed when the
+// application in the pane asked for bracketed paste
+func (m *Manager) PasteBuffer(paneID string) error {
+	if err := execCommand("tmux", "paste-buffer", "-p", "-t", paneID).Run(); err != nil {
+		return fmt.Errorf("failed to paste into pane '%s': %w", paneID, err)
+	}
+	return nil
+}
+
+// ConfirmPaste asks on the client with prompt and pastes into the pane on 'y'
+func (m *Manager) ConfirmPaste(clientName, paneID, prompt string) error {
+	paste := fmt.Sprintf("paste-buffer -p -t %s", paneID)
+	// '#' starts a format in the prompt
+	prompt = strings.ReplaceAll(prompt, "#", "##")
+	if err := execCommand("tmux", "confirm-before", "-t", clientName, "-p", prompt, paste).Run(); err != nil {
+		return fmt.Errorf("failed to ask for paste confirmation: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package tmux
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestGuardPastes(t *testing.T) {
+	original := execCommand
+	defer func() { execCommand = original }()
+	var calls [][]string
+	execCommand = func(name string, arg ...string) *exec.Cmd {
+		calls = append(calls, arg)
+		return exec.Command("true")
+	}
+
+	manager := &Manager{}
+	if err := manager.GuardPastes("prod-db", "'/usr/bin/sshm' sessions paste-guard"); err != nil {
+		t.Fatalf("GuardPastes() error = %v", err)
+	}
+
+	if len(calls) != 2 {
+		t.Fatalf("Expected 2 tmux calls, got %v", calls)
+	}
+	if strings.Join(calls[0], " ") != "set-option -t prod-db @sshm-paste-guard 1" {
+		t.Errorf("Expected the session to be marked, got %v", calls[0])
+	}
+	bind := calls[1]
+	if strings.Join(bind[:7], " ") != "bind-key -T prefix ] if-shell -F #{@sshm-paste-guard}" {
+		t.Errorf("Unexpected binding %v", bind)
+	}
+	if bind[7] != `run-shell -b "'/usr/bin/sshm' sessions paste-guard '#{session_name}' '#{pane_id}' '#{client_name}'"` {
+		t.Errorf("Unexpected guarded command %q", bind[7])
+	}
+	if bind[8] != "paste-buffer -p" {
+		t.Errorf("Expected unguarded sessions to paste as usual, got %q", bind[8])
+	}
+}
+
+func TestConfirmPaste(t *testing.T) {
+	original := execCommand
+	defer func() { execCommand = original }()
+	var call []string
+	execCommand = func(name string, arg ...string) *exec.Cmd {
+		call = arg
+		return exec.Command("true")
+	}
+
+	manager := &Manager{}
+	if err := manager.ConfirmPaste("/dev/pts/3", "%4", "Paste 12 lines into #1? (y/n)"); err != nil {
+		t.Fatalf("ConfirmPaste() error = %v", err)
+	}
+	expected := []string{"confirm-before", "-t", "/dev/pts/3", "-p", "Paste 12 lines into ##1? (y/n)", "paste-buffer -p -t %4"}
+	if strings.Join(call, "|") != strings.Join(expected, "|") {
+		t.Errorf("Expected %v, got %v", expected, call)
+	}
+}
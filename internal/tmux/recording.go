@@ -0,0 +1,41 @@
+package tmux
+
+import (
+	"fmt"
+	"strings"
+
+	"sshm/internal/shellquote"
+)
+
+// RecordPanes pipes the output of every pane of the session into command, run
+// by the shell with the session name, window name, pane width and pane height
+// appended as arguments. Panes already piped are left alone, so the session's
+// recordings keep going when it is recorded again. It returns how many panes
+// started recording.
+func (m *Manager) RecordPanes(sessionName, command string) (int, error) {
+	lines, err := m.query("list-panes", "-s", "-t", sessionName, "-F", "#{pane_id}\t#{pane_pipe}\t#{window_name}\t#{pane_width}\t#{pane_height}")
+	if err != nil {
+		return 0, fmt.Errorf("failed to list panes of session '%s': %w", sessionName, err)
+	}
+
+	started := 0
+	var failed []string
+	for _, line := range lines {
+		fields := strings.Split(line, "\t")
+		if len(fields) != 5 || fields[1] == "1" {
+			continue
+		}
+		paneID, windowName, width, height := fields[0], fields[2], fields[3], fields[4]
+		// tmux expands formats in the command, so '#' must be doubled
+		recorder := strings.ReplaceAll(fmt.Sprintf("%s %s %s %s %s", command, shellquote.Quote(sessionName), shellquote.Quote(windowName), width, height), "#", "##")
+		if output, err := execCommand("tmux", "pipe-pane", "-t", paneID, recorder).CombinedOutput(); err != nil {
+			failed = append(failed, fmt.Sprintf("%s (%s)", windowName, strings.TrimSpace(string(output))))
+			continue
+		}
+		started++
+	}
+	if len(failed) > 0 {
+		return started, fmt.Errorf("failed to record windows of session '%s': %s", sessionName, strings.Join(failed, ", "))
+	}
+	return started, nil
+}
@@ -0,0 +1,35 @@
+package tmux
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestRecordPanes(t *testing.T) {
+	original := execCommand
+	defer func() { execCommand = original }()
+	var piped []string
+	execCommand = func(name string, arg ...string) *exec.Cmd {
+		switch arg[0] {
+		case "list-panes":
+			return exec.Command("printf", "%%1\\t0\\tweb#1\\t120\\t40\\n%%2\\t1\\tdb\\t80\\t24\\n")
+		case "pipe-pane":
+			piped = append(piped, strings.Join(arg[1:], " "))
+		}
+		return exec.Command("true")
+	}
+
+	started, err := (&Manager{}).RecordPanes("it's prod", "'/usr/bin/sshm' recordings capture")
+	if err != nil {
+		t.Fatalf("RecordPanes failed: %v", err)
+	}
+	// The already piped pane keeps its recording
+	if started != 1 || len(piped) != 1 {
+		t.Fatalf("Expected only the unpiped pane to be recorded, got %d: %v", started, piped)
+	}
+	expected := `-t %1 '/usr/bin/sshm' recordings capture 'it'\''s prod' 'web##1' 120 40`
+	if piped[0] != expected {
+		t.Errorf("pipe-pane got %q, want %q", piped[0], expected)
+	}
+}
@@ -0,0 +1,209 @@
+package tmux
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"sshm/internal/shellquote"
+)
+
+// ShareOptions control who may join a shared session and how
+type ShareOptions struct {
+	User     string // Local account granted access to the tmux server (tmux 3.3+ server-access)
+	Group    string // Group given access to the socket; without one, every account on the host, which takes tmux 3.3+
+	ReadOnly bool   // Teammates watch without typing
+	Host     string // Address teammates ssh to for the ssh invitation, defaults to this host's name
+	Login    string // Account teammates log in as over ssh, defaults to the current user
+}
+
+// Invitation holds the commands a teammate runs to join a shared session
+type Invitation struct {
+	Session  string
+	Socket   string
+	Local    string   // For an account on this host, through the tmux socket
+	SSH      string   // For a teammate elsewhere, logging in as the session owner
+	Warnings []string // Why an invitation may not work as is
+}
+
+// ShareSession opens a session's tmux server to a teammate: the user is granted
+// access, the socket is made accessible to the group or every local account
+// and the invitation commands are returned. Without a user or group the socket
+// is left alone and only the ssh invitation applies. Opening the socket to
+// every account takes tmux 3.3's access list, which keeps the others out; on
+// older tmux a group is required. Nothing is changed when sharing fails.
+func (m *Manager) ShareSession(sessionName string, options ShareOptions) (Invitation, error) {
+	if !m.SessionExists(sessionName) {
+		return Invitation{}, fmt.Errorf("session '%s' does not exist", sessionName)
+	}
+
+	socket, err := m.SocketPath(sessionName)
+	if err != nil {
+		return Invitation{}, err
+	}
+	invitation := Invitation{Session: sessionName, Socket: socket}
+
+	if options.User != "" {
+		warning, err := grantServerAccess(options.User, options.ReadOnly)
+		if err != nil {
+			return Invitation{}, err
+		}
+		if warning != "" {
+			if options.Group == "" {
+				return Invitation{}, fmt.Errorf("tmux is older than 3.3 and can't limit who joins; give a group to share the socket with")
+			}
+			invitation.Warnings = append(invitation.Warnings, warning)
+		}
+	}
+
+	if options.User != "" || options.Group != "" {
+		if err := shareSocket(socket, options.Group); err != nil {
+			if options.User != "" {
+				_ = execCommand("tmux", "server-access", "-d", options.User).Run()
+			}
+			return Invitation{}, err
+		}
+		if warning := socketDirectoryWarning(socket, options.Group); warning != "" {
+			invitation.Warnings = append(invitation.Warnings, warning)
+		}
+	} else {
+		invitation.Warnings = append(invitation.Warnings, "No account or group given: the socket stays private, so only the ssh invitation works")
+	}
+
+	readOnly := ""
+	if options.ReadOnly {
+		readOnly = " -r"
+	}
+	invitation.Local = fmt.Sprintf("tmux -S %s attach-session -t %s%s", shellquote.Arg(socket), shellquote.Arg(sessionName), readOnly)
+
+	host := options.Host
+	if host == "" {
+		if host, err = os.Hostname(); err != nil {
+			host = "localhost"
+		}
+	}
+	login := options.Login
+	if login == "" {
+		if current, err := user.Current(); err == nil {
+			login = current.Username
+		}
+	}
+	target := host
+	if login != "" {
+		target = login + "@" + host
+	}
+	invitation.SSH = fmt.Sprintf("ssh -t %s tmux -S %s attach-session -t %s%s", target, shellquote.Arg(socket), shellquote.Arg(sessionName), readOnly)
+	if options.ReadOnly {
+		invitation.Warnings = append(invitation.Warnings, "Over ssh the teammate logs in as you, so read-only relies on them keeping -r")
+	}
+	return invitation, nil
+}
+
+// UnshareSession revokes a user's access and makes the socket private again.
+// It affects every session of the tmux server, as sharing did.
+func (m *Manager) UnshareSession(sessionName, userName string) error {
+	socket, err := m.SocketPath(sessionName)
+	if err != nil {
+		return err
+	}
+	if userName != "" {
+		// Fails on tmux older than 3.3, where the socket permissions alone decide
+		_ = execCommand("tmux", "server-access", "-d", userName).Run()
+	}
+	info, err := os.Stat(socket)
+	if err != nil {
+		return fmt.Errorf("failed to read the tmux socket: %w", err)
+	}
+	if err := os.Chmod(socket, info.Mode().Perm()&^0077); err != nil {
+		return fmt.Errorf("failed to make the tmux socket private: %w", err)
+	}
+	return nil
+}
+
+// SocketPath returns the socket of the tmux server running the session
+func (m *Manager) SocketPath(sessionName string) (string, error) {
+	output, err := execCommand("tmux", "display-message", "-p", "-t", sessionName, "#{socket_path}").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to find the tmux socket: %w", err)
+	}
+	socket := strings.TrimSpace(string(output))
+	if socket == "" {
+		return "", fmt.Errorf("tmux did not report its socket")
+	}
+	return socket, nil
+}
+
+// ClientCount returns the number of clients attached to a session, leaving out
+// control mode clients
+func (m *Manager) ClientCount(sessionName string) (int, error) {
+	return m.userClientCount(sessionName)
+}
+
+// shareSocket makes the socket readable and writable by the group, or by every
+// account when group is empty. tmux keeps the execute bits for its own use.
+// The socket's mode is put back when it can't be shared.
+func shareSocket(socket, group string) error {
+	info, err := os.Stat(socket)
+	if err != nil {
+		return fmt.Errorf("failed to read the tmux socket: %w", err)
+	}
+	restore := func() {
+		_ = os.Chmod(socket, info.Mode().Perm())
+	}
+
+	mode := info.Mode().Perm() | 0066
+	if group != "" {
+		found, err := user.LookupGroup(group)
+		if err != nil {
+			return fmt.Errorf("unknown group '%s'", group)
+		}
+		gid, _ := strconv.Atoi(found.Gid)
+		if err := os.Chown(socket, -1, gid); err != nil {
+			restore()
+			return fmt.Errorf("failed to give group '%s' the tmux socket: %w", group, err)
+		}
+		mode = info.Mode().Perm()&^0007 | 0060
+	}
+	if err := os.Chmod(socket, mode); err != nil {
+		restore()
+		return fmt.Errorf("failed to change the tmux socket permissions: %w", err)
+	}
+	return nil
+}
+
+// socketDirectoryWarning explains when the socket's directory keeps other
+// accounts out, as tmux's default /tmp/tmux-UID directory does
+func socketDirectoryWarning(socket, group string) string {
+	dir := filepath.Dir(socket)
+	info, err := os.Stat(dir)
+	if err != nil {
+		return ""
+	}
+	perm := info.Mode().Perm()
+	if perm&0001 != 0 || (group != "" && perm&0010 != 0) {
+		return ""
+	}
+	return fmt.Sprintf("%s is private (%04o), so other accounts can't reach the socket; start sshm with TMUX_TMPDIR set to a shared directory for local invitations", dir, perm)
+}
+
+// grantServerAccess allows a user on the tmux server. On tmux older than 3.3
+// there is no access list and the socket permissions alone decide.
+func grantServerAccess(userName string, readOnly bool) (string, error) {
+	args := []string{"server-access", "-a"}
+	if readOnly {
+		args = append(args, "-r")
+	}
+	args = append(args, userName)
+	output, err := execCommand("tmux", args...).CombinedOutput()
+	if err == nil {
+		return "", nil
+	}
+	message := strings.TrimSpace(string(output))
+	if strings.Contains(message, "unknown command") {
+		return "tmux is older than 3.3: anyone who can reach the socket can join, read-only isn't enforced", nil
+	}
+	return "", fmt.Errorf("failed to grant %s access: %s", userName, message)
+}
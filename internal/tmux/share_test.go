@@ -0,0 +1,140 @@
+package tmux
+
+import (
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// mockShare makes tmux report socket as the server socket and records the
+// server-access calls; serverAccess is the shell snippet run for them
+func mockShare(t *testing.T, socket, serverAccess string) *[][]string {
+	original := execCommand
+	t.Cleanup(func() { execCommand = original })
+	var calls [][]string
+	execCommand = func(name string, arg ...string) *exec.Cmd {
+		switch arg[0] {
+		case "display-message":
+			return exec.Command("printf", "%s\n", socket)
+		case "server-access":
+			calls = append(calls, arg)
+			return exec.Command("sh", "-c", serverAccess)
+		}
+		return exec.Command("true")
+	}
+	return &calls
+}
+
+func socketFile(t *testing.T) string {
+	socket := filepath.Join(t.TempDir(), "default")
+	if err := os.WriteFile(socket, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	return socket
+}
+
+func TestShareSession(t *testing.T) {
+	socket := socketFile(t)
+	calls := mockShare(t, socket, "true")
+	manager := &Manager{existingSessions: []string{"web1"}}
+
+	invitation, err := manager.ShareSession("web1", ShareOptions{User: "alice", ReadOnly: true, Host: "devbox", Login: "bob"})
+	if err != nil {
+		t.Fatalf("ShareSession() error = %v", err)
+	}
+	if want := "tmux -S " + socket + " attach-session -t web1 -r"; invitation.Local != want {
+		t.Errorf("Local = %q, want %q", invitation.Local, want)
+	}
+	if want := "ssh -t bob@devbox tmux -S " + socket + " attach-session -t web1 -r"; invitation.SSH != want {
+		t.Errorf("SSH = %q, want %q", invitation.SSH, want)
+	}
+	if len(*calls) != 1 || strings.Join((*calls)[0], " ") != "server-access -a -r alice" {
+		t.Errorf("Expected alice to be granted read-only access, got %v", *calls)
+	}
+
+	info, err := os.Stat(socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0666 {
+		t.Errorf("Expected the socket to be opened to every account, got %04o", info.Mode().Perm())
+	}
+
+	if err := manager.UnshareSession("web1", "alice"); err != nil {
+		t.Fatalf("UnshareSession() error = %v", err)
+	}
+	if info, _ := os.Stat(socket); info.Mode().Perm() != 0600 {
+		t.Errorf("Expected the socket to be private again, got %04o", info.Mode().Perm())
+	}
+}
+
+func TestShareSessionWithoutAccount(t *testing.T) {
+	socket := socketFile(t)
+	calls := mockShare(t, socket, "true")
+	manager := &Manager{existingSessions: []string{"web1"}}
+
+	invitation, err := manager.ShareSession("web1", ShareOptions{Host: "devbox", Login: "bob"})
+	if err != nil {
+		t.Fatalf("ShareSession() error = %v", err)
+	}
+	if info, _ := os.Stat(socket); info.Mode().Perm() != 0600 {
+		t.Errorf("Expected the socket to stay private, got %04o", info.Mode().Perm())
+	}
+	if len(*calls) != 0 || len(invitation.Warnings) != 1 {
+		t.Errorf("Expected only a warning, got calls %v and warnings %v", *calls, invitation.Warnings)
+	}
+
+	if _, err := manager.ShareSession("missing", ShareOptions{}); err == nil {
+		t.Error("Expected an error for a missing session")
+	}
+}
+
+func TestShareSessionOldTmux(t *testing.T) {
+	socket := socketFile(t)
+	mockShare(t, socket, "echo 'unknown command: server-access' >&2; exit 1")
+	manager := &Manager{existingSessions: []string{"web1"}}
+
+	// Without an access list only a group keeps other accounts out
+	if _, err := manager.ShareSession("web1", ShareOptions{User: "alice", Host: "devbox", Login: "bob"}); err == nil {
+		t.Fatal("Expected sharing with a user alone to fail on old tmux")
+	}
+	if info, _ := os.Stat(socket); info.Mode().Perm() != 0600 {
+		t.Errorf("Expected the socket to stay private, got %04o", info.Mode().Perm())
+	}
+
+	group, err := user.LookupGroupId(strconv.Itoa(os.Getgid()))
+	if err != nil {
+		t.Skipf("no group to share with: %v", err)
+	}
+	invitation, err := manager.ShareSession("web1", ShareOptions{User: "alice", Group: group.Name, Host: "devbox", Login: "bob"})
+	if err != nil {
+		t.Fatalf("ShareSession() error = %v", err)
+	}
+	found := false
+	for _, warning := range invitation.Warnings {
+		found = found || strings.Contains(warning, "older than 3.3")
+	}
+	if !found {
+		t.Errorf("Expected a warning about old tmux, got %v", invitation.Warnings)
+	}
+	if info, _ := os.Stat(socket); info.Mode().Perm() != 0660 {
+		t.Errorf("Expected the socket opened to the group only, got %04o", info.Mode().Perm())
+	}
+}
+
+func TestShareSessionAccessFailureKeepsSocketPrivate(t *testing.T) {
+	socket := socketFile(t)
+	mockShare(t, socket, "echo 'no such user' >&2; exit 1")
+	manager := &Manager{existingSessions: []string{"web1"}}
+
+	if _, err := manager.ShareSession("web1", ShareOptions{User: "nobody-here"}); err == nil {
+		t.Fatal("Expected the failed grant to fail sharing")
+	}
+	if info, _ := os.Stat(socket); info.Mode().Perm() != 0600 {
+		t.Errorf("Expected the socket to stay private, got %04o", info.Mode().Perm())
+	}
+}
@@ -0,0 +1,61 @@
+package tmux
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// StartupDelay is how long after ssh starts startup commands are typed,
+// leaving time to log in (a variable to allow shorter waits in tests)
+var StartupDelay = 2 * time.Second
+
+// StartupCommander is optionally implemented by servers that type commands
+// into their window once connected, e.g. exporting variables and changing
+// to an application directory
+type StartupCommander interface {
+	StartupInput() []string
+}
+
+// SendWhenConnected types lines into the window once its ssh connection is up
+// and delay has passed. Nothing is sent when ssh never starts or has already
+// exited back to the local shell, so the lines never run locally.
+func (m *Manager) SendWhenConnected(target string, lines []string, delay time.Duration) error {
+	return m.sendWhenConnected(target, fmt.Sprintf("window '%s'", target), lines, delay)
+}
+
+// sendWhenConnected is SendWhenConnected with the window described as label in errors
+func (m *Manager) sendWhenConnected(target, label string, lines []string, delay time.Duration) error {
+	if len(lines) == 0 {
+		return nil
+	}
+	if !m.waitForConnection(target) {
+		return fmt.Errorf("%s did not connect; '%s' was not sent", label, strings.Join(lines, "; "))
+	}
+	time.Sleep(delay)
+	if !m.paneConnected(target) {
+		return fmt.Errorf("%s disconnected; '%s' was not sent", label, strings.Join(lines, "; "))
+	}
+	for _, line := range lines {
+		if err := m.SendKeysToWindow(target, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// startupInput returns what the server types once connected, if anything
+func startupInput(server Server) []string {
+	if commander, ok := server.(StartupCommander); ok {
+		return commander.StartupInput()
+	}
+	return nil
+}
+
+// sendStartupInput types the servers' startup input into their windows in the
+// background, keyed by window target, so attaching isn't held up
+func (m *Manager) sendStartupInput(inputs map[string][]string) {
+	for target, lines := range inputs {
+		go m.SendWhenConnected(target, lines, StartupDelay)
+	}
+}
@@ -0,0 +1,109 @@
+package tmux
+
+import (
+	"os/exec"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// startupServer is a test server typing startup input once connected
+type startupServer struct {
+	mockServer
+	startup []string
+}
+
+func (s *startupServer) StartupInput() []string { return s.startup }
+
+func TestSendWhenConnected(t *testing.T) {
+	original := execCommand
+	defer func() { execCommand = original }()
+	originalInterval, originalTimeout := connectPollInterval, connectTimeout
+	connectPollInterval, connectTimeout = time.Millisecond, 20*time.Millisecond
+	defer func() { connectPollInterval, connectTimeout = originalInterval, originalTimeout }()
+
+	connected := true
+	var sent []string
+	execCommand = func(name string, arg ...string) *exec.Cmd {
+		switch arg[0] {
+		case "display-message":
+			if connected {
+				return exec.Command("printf", "0\tssh\n")
+			}
+			return exec.Command("printf", "0\tbash\n")
+		case "send-keys":
+			sent = append(sent, arg[3])
+		}
+		return exec.Command("true")
+	}
+
+	manager := &Manager{}
+	lines := []string{"export APP_ENV='prod'", "cd /srv/app && sudo -i"}
+	if err := manager.SendWhenConnected("web1:0", lines, 0); err != nil {
+		t.Fatalf("SendWhenConnected() error = %v", err)
+	}
+	if strings.Join(sent, "|") != strings.Join(lines, "|") {
+		t.Errorf("Expected the lines in order, got %v", sent)
+	}
+
+	// Nothing is typed into a local shell
+	sent = nil
+	connected = false
+	err := manager.SendWhenConnected("web1:0", lines, 0)
+	if err == nil || !strings.Contains(err.Error(), "did not connect") || len(sent) != 0 {
+		t.Errorf("Expected nothing sent to a local shell, got %v and %v", err, sent)
+	}
+}
+
+func TestConnectToProfileSendsStartupInput(t *testing.T) {
+	original := execCommand
+	defer func() { execCommand = original }()
+	originalDelay := StartupDelay
+	StartupDelay = 0
+	defer func() { StartupDelay = originalDelay }()
+
+	var mu sync.Mutex
+	var sent []string
+	execCommand = func(name string, arg ...string) *exec.Cmd {
+		switch {
+		case len(arg) > 0 && arg[0] == "-V":
+			return exec.Command("echo", "tmux 3.3")
+		case len(arg) > 0 && arg[0] == "list-sessions":
+			return exec.Command("true")
+		case len(arg) > 0 && arg[0] == "display-message":
+			return exec.Command("printf", "0\tssh\n")
+		case len(arg) > 0 && arg[0] == "send-keys":
+			mu.Lock()
+			sent = append(sent, arg[2]+" "+arg[3])
+			mu.Unlock()
+		}
+		return exec.Command("true")
+	}
+
+	manager := &Manager{}
+	servers := []Server{
+		&startupServer{mockServer: mockServer{name: "web1", hostname: "web1.example.com", port: 22, username: "ops", authType: "password", valid: true}, startup: []string{"cd /srv/app"}},
+		&mockServer{name: "web2", hostname: "web2.example.com", port: 22, username: "ops", authType: "password", valid: true},
+	}
+	if _, _, err := manager.ConnectToProfile("prod", servers); err != nil {
+		t.Fatalf("ConnectToProfile() error = %v", err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		mu.Lock()
+		got := strings.Join(sent, "\n")
+		mu.Unlock()
+		if strings.Contains(got, "prod:0 cd /srv/app") {
+			if strings.Contains(got, "prod:1 cd /srv/app") {
+				t.Errorf("Expected startup input only in web1's window, got:\n%s", got)
+			}
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected web1's startup command, got:\n%s", got)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
@@ -0,0 +1,111 @@
+package tmux
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TemplateWindow is a window of a templated session: its own SSH connection,
+// then the server's Startup input and Command typed once connected (none
+// leaves a shell)
+type TemplateWindow struct {
+	Name    string
+	Startup []string
+	Command string
+}
+
+// How often and how long to wait for ssh to start in a templated window
+// (variables to allow shorter waits in tests)
+var (
+	connectPollInterval = 200 * time.Millisecond
+	connectTimeout      = 30 * time.Second
+)
+
+// ConnectWithTemplate connects like ConnectToServer, creating one window per
+// template window, each running sshCommand with env set. Window commands are sent in the
+// background once ssh runs and delay has passed, so attaching isn't held up.
+// An existing session is returned for reattachment as is.
+func (m *Manager) ConnectWithTemplate(serverName, sshCommand string, windows []TemplateWindow, delay time.Duration, env ...string) (string, bool, error) {
+	if len(windows) == 0 {
+		return m.ConnectToServer(serverName, sshCommand, env...)
+	}
+	if !m.IsAvailable() {
+		return "", false, fmt.Errorf("tmux is not available on this system")
+	}
+
+	normalizedSessionName := normalizeSessionName(serverName)
+	if m.SessionExists(normalizedSessionName) {
+		return normalizedSessionName, true, nil
+	}
+
+	sessionName := m.generateUniqueSessionName(serverName)
+	if err := m.CreateSession(sessionName); err != nil {
+		return "", false, err
+	}
+	for i, window := range windows {
+		// The first window is the session's default window
+		if i > 0 {
+			if err := m.CreateWindow(sessionName, window.Name); err != nil {
+				return "", false, err
+			}
+		} else if err := m.RenameWindow(sessionName, "0", window.Name); err != nil {
+			return "", false, err
+		}
+		target := fmt.Sprintf("%s:%d", sessionName, i)
+		if err := m.respawnWindow(target, env, false); err != nil {
+			return "", false, err
+		}
+		if err := m.SendKeysToWindow(target, sshCommand); err != nil {
+			return "", false, err
+		}
+	}
+
+	go m.sendTemplateCommands(sessionName, windows, delay)
+	return sessionName, false, nil
+}
+
+// sendTemplateCommands types each window's startup input and command once its
+// connection is up.
+// A window whose ssh never starts, or already exited back to the local shell,
+// is skipped so the command doesn't run locally.
+func (m *Manager) sendTemplateCommands(sessionName string, windows []TemplateWindow, delay time.Duration) []error {
+	var errs []error
+	for i, window := range windows {
+		lines := append([]string{}, window.Startup...)
+		if window.Command != "" {
+			lines = append(lines, window.Command)
+		}
+		target := fmt.Sprintf("%s:%d", sessionName, i)
+		if err := m.sendWhenConnected(target, fmt.Sprintf("window '%s'", window.Name), lines, delay); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// waitForConnection waits until the window's pane runs something other than a
+// local shell, i.e. ssh has started
+func (m *Manager) waitForConnection(target string) bool {
+	deadline := time.Now().Add(connectTimeout)
+	for {
+		if m.paneConnected(target) {
+			return true
+		}
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(connectPollInterval)
+	}
+}
+
+// paneConnected reports whether the window's pane runs a command other than a local shell
+func (m *Manager) paneConnected(target string) bool {
+	lines, err := m.query("display-message", "-p", "-t", target, "#{pane_dead}\t#{pane_current_command}")
+	if err != nil || len(lines) == 0 {
+		return false
+	}
+	dead, command, _ := strings.Cut(lines[0], "\t")
+	state := WindowState{Command: command, Dead: dead == "1"}
+	return state.Command != "" && !state.Disconnected()
+}
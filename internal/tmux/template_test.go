@@ -0,0 +1,78 @@
+package tmux
+
+import (
+	"os/exec"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestConnectWithTemplate(t *testing.T) {
+	original := execCommand
+	defer func() { execCommand = original }()
+	var mu sync.Mutex
+	var calls []string
+	execCommand = func(name string, arg ...string) *exec.Cmd {
+		mu.Lock()
+		calls = append(calls, strings.Join(arg, " "))
+		mu.Unlock()
+		return exec.Command("true")
+	}
+
+	manager := &Manager{}
+	windows := []TemplateWindow{{Name: "shell"}, {Name: "logs"}}
+	session, existing, err := manager.ConnectWithTemplate("web1", "ssh ops@web1", windows, 0)
+	if err != nil || existing || session != "web1" {
+		t.Fatalf("ConnectWithTemplate() = %q, %v, %v", session, existing, err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	expected := []string{
+		"new-session -d -s web1",
+		"rename-window -t web1:0 shell",
+		"send-keys -t web1:0 ssh ops@web1 Enter",
+		"new-window -t web1 -n logs -a",
+		"send-keys -t web1:1 ssh ops@web1 Enter",
+	}
+	got := strings.Join(calls, "\n")
+	for _, call := range expected {
+		if !strings.Contains(got, call) {
+			t.Errorf("Expected tmux call %q, got:\n%s", call, got)
+		}
+	}
+}
+
+func TestSendTemplateCommands(t *testing.T) {
+	original := execCommand
+	defer func() { execCommand = original }()
+	originalInterval, originalTimeout := connectPollInterval, connectTimeout
+	connectPollInterval, connectTimeout = time.Millisecond, 20*time.Millisecond
+	defer func() { connectPollInterval, connectTimeout = originalInterval, originalTimeout }()
+
+	var sent []string
+	execCommand = func(name string, arg ...string) *exec.Cmd {
+		switch {
+		case arg[0] == "display-message" && arg[3] == "web1:1":
+			return exec.Command("printf", "0\tssh\n")
+		case arg[0] == "display-message":
+			// The third window's ssh failed and it is back at the local shell
+			return exec.Command("printf", "0\tzsh\n")
+		case arg[0] == "send-keys":
+			sent = append(sent, arg[2]+" "+arg[3])
+		}
+		return exec.Command("true")
+	}
+
+	manager := &Manager{}
+	windows := []TemplateWindow{{Name: "shell"}, {Name: "logs", Command: "journalctl -f"}, {Name: "top", Command: "htop"}}
+	errs := manager.sendTemplateCommands("web1", windows, 0)
+
+	if strings.Join(sent, "|") != "web1:1 journalctl -f" {
+		t.Errorf("Expected only the connected window's command, got %v", sent)
+	}
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "'htop' was not sent") {
+		t.Errorf("Expected the disconnected window to be reported, got %v", errs)
+	}
+}
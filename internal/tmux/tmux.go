@@ -4,8 +4,11 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 )
 
 // execCommand is a variable to allow mocking in tests
@@ -25,6 +28,9 @@ func SetExecCommand(fn func(string, ...string) *exec.Cmd) {
 type Manager struct {
 	// For testing purposes, we can inject existing sessions
 	existingSessions []string
+
+	control   *ControlClient // Attached lazily by SessionStates, nil until then
+	controlMu sync.Mutex
 }
 
 // NewManager creates a new tmux manager instance
@@ -83,6 +89,40 @@ func normalizeSessionName(name string) string {
 	return normalized
 }
 
+// RenamedSession returns the name a session of a server gets when the server is
+// renamed, keeping the "-N" suffix of additional sessions
+func RenamedSession(sessionName, oldServerName, newServerName string) string {
+	return normalizeSessionName(newServerName) + strings.TrimPrefix(sessionName, normalizeSessionName(oldServerName))
+}
+
+// ServerForSession returns the server whose session is sessionName, accounting
+// for name normalization and the "-N" suffix of additional sessions, or ""
+func ServerForSession(sessionName string, serverNames []string) string {
+	for _, name := range serverNames {
+		if normalizeSessionName(name) == sessionName {
+			return name
+		}
+	}
+	if i := strings.LastIndex(sessionName, "-"); i > 0 {
+		if _, err := strconv.Atoi(sessionName[i+1:]); err == nil {
+			return ServerForSession(sessionName[:i], serverNames)
+		}
+	}
+	return ""
+}
+
+// SessionsOfServer returns the sessions among sessionNames that ServerForSession
+// attributes to serverName
+func SessionsOfServer(sessionNames []string, serverName string, serverNames []string) []string {
+	var sessions []string
+	for _, session := range sessionNames {
+		if ServerForSession(session, serverNames) == serverName {
+			sessions = append(sessions, session)
+		}
+	}
+	return sessions
+}
+
 // generateUniqueSessionName creates a unique session name by appending a counter if needed
 func (m *Manager) generateUniqueSessionName(baseName string) string {
 	// Normalize the base name to match tmux behavior
@@ -135,8 +175,9 @@ func (m *Manager) AttachSession(sessionName string) error {
 	return nil
 }
 
-// ConnectToServer creates a tmux session and connects to a server via SSH, or reattaches to existing session
-func (m *Manager) ConnectToServer(serverName, sshCommand string) (string, bool, error) {
+// ConnectToServer creates a tmux session and connects to a server via SSH, or reattaches to existing session.
+// env holds KEY=value variables the window's shell needs for sshCommand, like SSHPASS; see Environment.
+func (m *Manager) ConnectToServer(serverName, sshCommand string, env ...string) (string, bool, error) {
 	// Check if tmux is available
 	if !m.IsAvailable() {
 		return "", false, fmt.Errorf("tmux is not available on this system")
@@ -161,6 +202,10 @@ func (m *Manager) ConnectToServer(serverName, sshCommand string) (string, bool,
 		return "", false, err
 	}
 
+	if err := m.respawnWindow(sessionName, env, false); err != nil {
+		return "", false, err
+	}
+
 	// Send the SSH command to the session
 	err = m.SendKeys(sessionName, sshCommand)
 	if err != nil {
@@ -170,6 +215,18 @@ func (m *Manager) ConnectToServer(serverName, sshCommand string) (string, bool,
 	return sessionName, false, nil
 }
 
+// ProfileSessionExists reports whether the group session of a profile is open,
+// in which case connecting to the profile only reattaches to it
+func (m *Manager) ProfileSessionExists(profileName string) bool {
+	return m.SessionExists(normalizeSessionName(profileName))
+}
+
+// ServerSessionExists reports whether the session of a server is open, in which
+// case connecting to the server only reattaches to it
+func (m *Manager) ServerSessionExists(serverName string) bool {
+	return m.SessionExists(normalizeSessionName(serverName))
+}
+
 // SessionExists checks if a session with the given name exists
 func (m *Manager) SessionExists(sessionName string) bool {
 	sessions, err := m.ListSessions()
@@ -189,6 +246,79 @@ func (m *Manager) KillSession(sessionName string) error {
 	return nil
 }
 
+// RenameSession renames a tmux session
+func (m *Manager) RenameSession(sessionName, newName string) error {
+	cmd := execCommand("tmux", "rename-session", "-t", sessionName, newName)
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to rename session '%s': %w", sessionName, err)
+	}
+	return nil
+}
+
+// RenameServerSessions renames the sessions of the servers renamed from the
+// keys of renames to their values, given the server names before the rename.
+// Sessions are renamed once their new name is free, so servers can take each
+// other's names. It returns the renamed sessions, old name to new.
+func (m *Manager) RenameServerSessions(renames map[string]string, serverNames []string) (map[string]string, error) {
+	sessions, err := m.ListSessions()
+	if err != nil {
+		return nil, err
+	}
+
+	pending := make(map[string]string)
+	current := make(map[string]bool, len(sessions))
+	for _, session := range sessions {
+		current[session] = true
+		server := ServerForSession(session, serverNames)
+		if newServer, ok := renames[server]; ok && server != "" {
+			if newName := RenamedSession(session, server, newServer); newName != session {
+				pending[session] = newName
+			}
+		}
+	}
+
+	renamed := make(map[string]string)
+	var failures []string
+	for len(pending) > 0 {
+		progress := false
+		for _, session := range sortedKeys(pending) {
+			newName := pending[session]
+			if current[newName] {
+				continue
+			}
+			delete(pending, session)
+			progress = true
+			if err := m.RenameSession(session, newName); err != nil {
+				failures = append(failures, err.Error())
+				continue
+			}
+			delete(current, session)
+			current[newName] = true
+			renamed[session] = newName
+		}
+		if !progress {
+			for _, session := range sortedKeys(pending) {
+				failures = append(failures, fmt.Sprintf("session '%s' kept its name: '%s' exists", session, pending[session]))
+			}
+			break
+		}
+	}
+	if len(failures) > 0 {
+		return renamed, fmt.Errorf("%s", strings.Join(failures, "; "))
+	}
+	return renamed, nil
+}
+
+// sortedKeys returns the keys of m in order
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 // Server interface for tmux operations - avoiding circular import
 type Server interface {
 	GetName() string
@@ -200,6 +330,57 @@ type Server interface {
 	Validate() error
 }
 
+// ConnectionOptionsProvider is optionally implemented by servers that dial a
+// different address than their hostname or need extra ssh options
+type ConnectionOptionsProvider interface {
+	GetEffectiveHostname() string
+	GetSSHOptions() string
+}
+
+// CommandBuilder is optionally implemented by servers that build their own ssh
+// command, which sshm's servers do so every window connects the same way
+type CommandBuilder interface {
+	SSHCommand() (string, error)
+}
+
+// Environment is optionally implemented by servers whose ssh command needs
+// variables set in the window's shell, like SSHPASS for sshpass to answer the
+// password prompt. They are handed to tmux rather than typed, so secrets never
+// show on screen, in the shell history, in recordings or in ps.
+type Environment interface {
+	WindowEnvironment() []string // KEY=value pairs
+}
+
+// windowEnvironment returns the variables the server's windows need, if any
+func windowEnvironment(server Server) []string {
+	if provider, ok := server.(Environment); ok {
+		return provider.WindowEnvironment()
+	}
+	return nil
+}
+
+// respawnWindow restarts the shell of a window with env set in its
+// environment. Nothing is done without env, unless the window's pane is dead.
+func (m *Manager) respawnWindow(target string, env []string, dead bool) error {
+	if len(env) == 0 && !dead {
+		return nil
+	}
+	args := []string{"respawn-pane", "-k", "-t", target}
+	for _, variable := range env {
+		args = append(args, "-e", variable)
+	}
+	if err := execCommand("tmux", args...).Run(); err != nil {
+		return fmt.Errorf("failed to respawn window '%s': %w", target, err)
+	}
+	return nil
+}
+
+// Refuser is optionally implemented by servers whose windows may not connect
+// yet, e.g. until a login banner is accepted; Refused tells why
+type Refuser interface {
+	Refused() error
+}
+
 // ConnectToProfile creates a tmux session for a profile with multiple windows for servers
 func (m *Manager) ConnectToProfile(profileName string, servers []Server) (string, bool, error) {
 	// Check if tmux is available
@@ -227,6 +408,7 @@ func (m *Manager) ConnectToProfile(profileName string, servers []Server) (string
 	}
 
 	// Create windows for each server and send SSH commands
+	startup := make(map[string][]string)
 	for i, server := range servers {
 		serverName := server.GetName()
 		
@@ -252,24 +434,42 @@ func (m *Manager) ConnectToProfile(profileName string, servers []Server) (string
 
 		// Send the SSH command to the appropriate window
 		windowTarget := fmt.Sprintf("%s:%d", sessionName, i)
+		if err := m.respawnWindow(windowTarget, windowEnvironment(server), false); err != nil {
+			return "", false, err
+		}
 		err = m.SendKeysToWindow(windowTarget, sshCommand)
 		if err != nil {
 			return "", false, fmt.Errorf("failed to send SSH command to window %s: %w", windowTarget, err)
 		}
+		if lines := startupInput(server); len(lines) > 0 {
+			startup[windowTarget] = lines
+		}
 	}
 
+	m.sendStartupInput(startup)
 	return sessionName, false, nil
 }
 
 // buildSSHCommand builds an SSH command string for a server
 func (m *Manager) buildSSHCommand(server Server) (string, error) {
+	if builder, ok := server.(CommandBuilder); ok {
+		return builder.SSHCommand()
+	}
+
 	// Validate server configuration
 	if err := server.Validate(); err != nil {
 		return "", fmt.Errorf("invalid server configuration: %w", err)
 	}
 
+	hostname := server.GetHostname()
+	extraOptions := ""
+	if provider, ok := server.(ConnectionOptionsProvider); ok {
+		hostname = provider.GetEffectiveHostname()
+		extraOptions = provider.GetSSHOptions()
+	}
+
 	// Build base SSH command with pseudo-terminal allocation
-	sshCmd := fmt.Sprintf("ssh -t %s@%s", server.GetUsername(), server.GetHostname())
+	sshCmd := fmt.Sprintf("ssh -t %s@%s", server.GetUsername(), hostname)
 
 	// Add port if not default
 	if server.GetPort() != 22 {
@@ -284,6 +484,9 @@ func (m *Manager) buildSSHCommand(server Server) (string, error) {
 	// Add common SSH options
 	sshCmd += " -o ServerAliveInterval=60 -o ServerAliveCountMax=3"
 
+	// Add server-specific options
+	sshCmd += extraOptions
+
 	return sshCmd, nil
 }
 
@@ -318,6 +521,267 @@ func (m *Manager) SendKeysToWindow(windowTarget, command string) error {
 	return nil
 }
 
+// ListWindows returns the window names of a session in window index order
+func (m *Manager) ListWindows(sessionName string) ([]string, error) {
+	cmd := execCommand("tmux", "list-windows", "-t", sessionName, "-F", "#{window_name}")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list windows for session '%s': %w", sessionName, err)
+	}
+
+	trimmed := strings.TrimSpace(string(output))
+	if trimmed == "" {
+		return []string{}, nil
+	}
+	return strings.Split(trimmed, "\n"), nil
+}
+
+// Window is a window to create in a new session; Server is nil for a plain shell
+type Window struct {
+	Name   string
+	Server Server
+}
+
+// CreateSessionWithWindows creates a session with the given windows and connects
+// each window that has a server, e.g. to restore a saved workspace
+func (m *Manager) CreateSessionWithWindows(sessionName string, windows []Window) error {
+	if err := m.CreateSession(sessionName); err != nil {
+		return err
+	}
+
+	startup := make(map[string][]string)
+	for i, window := range windows {
+		// The first window is the session's default window
+		if i > 0 {
+			if err := m.CreateWindow(sessionName, window.Name); err != nil {
+				return err
+			}
+		} else if window.Name != "" {
+			if err := m.RenameWindow(sessionName, "0", window.Name); err != nil {
+				return err
+			}
+		}
+
+		if window.Server == nil {
+			continue
+		}
+		sshCommand, err := m.buildSSHCommand(window.Server)
+		if err != nil {
+			return fmt.Errorf("failed to build SSH command for %s: %w", window.Server.GetName(), err)
+		}
+		target := fmt.Sprintf("%s:%d", sessionName, i)
+		if err := m.respawnWindow(target, windowEnvironment(window.Server), false); err != nil {
+			return err
+		}
+		if err := m.SendKeysToWindow(target, sshCommand); err != nil {
+			return err
+		}
+		if lines := startupInput(window.Server); len(lines) > 0 {
+			startup[target] = lines
+		}
+	}
+	m.sendStartupInput(startup)
+	return nil
+}
+
+// localShells are pane commands meaning the window's connection has ended and
+// the window is back at the local shell
+var localShells = []string{"bash", "zsh", "sh", "dash", "fish", "ksh", "tcsh", "csh", "login"}
+
+// WindowState describes the first pane of a session window
+type WindowState struct {
+	Index      int
+	Name       string
+	Command    string // Command running in the pane, e.g. "ssh" or "zsh"
+	Dead       bool   // The pane's process exited and the pane was kept (remain-on-exit)
+	ExitStatus int    // Exit status of a dead pane's process
+}
+
+// Disconnected reports whether the window no longer runs a connection: its pane
+// is dead or back at a local shell
+func (w WindowState) Disconnected() bool {
+	return w.Dead || contains(localShells, w.Command)
+}
+
+// ListWindowStates returns the state of each window of a session in index order
+func (m *Manager) ListWindowStates(sessionName string) ([]WindowState, error) {
+	format := "#{window_index}\t#{window_name}\t#{pane_current_command}\t#{pane_dead}\t#{pane_dead_status}"
+	lines, err := m.query("list-windows", "-t", sessionName, "-F", format)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list windows for session '%s': %w", sessionName, err)
+	}
+
+	var states []WindowState
+	for _, line := range lines {
+		fields := strings.Split(line, "\t")
+		if len(fields) < 4 {
+			continue
+		}
+		index, err := strconv.Atoi(fields[0])
+		if err != nil {
+			continue
+		}
+		state := WindowState{Index: index, Name: fields[1], Command: fields[2], Dead: fields[3] == "1"}
+		if len(fields) > 4 {
+			state.ExitStatus, _ = strconv.Atoi(fields[4])
+		}
+		states = append(states, state)
+	}
+	return states, nil
+}
+
+// connectionLostMessages are printed by ssh when a connection drops, unlike the
+// "Connection to host closed." that also follows a normal logout
+var connectionLostMessages = []string{
+	"broken pipe",
+	"not responding",
+	"timed out",
+	"connection reset",
+	"closed by remote host",
+	"send disconnect",
+	"network is unreachable",
+	"no route to host",
+}
+
+// sshErrorStatus is the exit status of ssh when the connection failed
+const sshErrorStatus = 255
+
+// LostWindows returns the windows of a session whose SSH connection dropped: the
+// pane died with ssh's error status, or it is back at a local shell right after
+// ssh reported a broken pipe or timeout. Windows the user logged out of are not
+// included.
+func (m *Manager) LostWindows(sessionName string) ([]WindowState, error) {
+	states, err := m.ListWindowStates(sessionName)
+	if err != nil {
+		return nil, err
+	}
+
+	var lost []WindowState
+	for _, state := range states {
+		if !state.Disconnected() {
+			continue
+		}
+		if state.Dead {
+			if state.ExitStatus == sshErrorStatus {
+				lost = append(lost, state)
+			}
+			continue
+		}
+		tail, err := m.query("capture-pane", "-p", "-t", fmt.Sprintf("%s:%d", sessionName, state.Index), "-S", "-15")
+		if err != nil {
+			continue
+		}
+		if connectionLostOutput(tail) {
+			lost = append(lost, state)
+		}
+	}
+	return lost, nil
+}
+
+// connectionLostOutput reports whether pane output ends with an ssh connection
+// error, ignoring the prompt and blank lines printed after it
+func connectionLostOutput(lines []string) bool {
+	checked := 0
+	for i := len(lines) - 1; i >= 0 && checked < 3; i-- {
+		line := strings.ToLower(strings.TrimSpace(lines[i]))
+		if line == "" {
+			continue
+		}
+		checked++
+		for _, message := range connectionLostMessages {
+			if strings.Contains(line, message) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// query runs a read-only tmux command through the control mode client when one
+// is attached and as a separate process otherwise
+func (m *Manager) query(args ...string) ([]string, error) {
+	m.controlMu.Lock()
+	client := m.control
+	m.controlMu.Unlock()
+	if client != nil && client.Alive() {
+		if lines, err := client.Command(args...); err != ErrControlClosed {
+			return lines, err
+		}
+	}
+
+	output, err := execCommand("tmux", args...).Output()
+	if err != nil {
+		return nil, err
+	}
+	return outputLines(output), nil
+}
+
+// RepairResult reports what RepairSession did
+type RepairResult struct {
+	Reconnected []string // Windows whose connection was relaunched
+	Unknown     []string // Disconnected windows not named after any of the given servers
+	Refused     []string // Disconnected windows whose server refuses to connect yet
+	Healthy     int      // Windows that still run a connection
+}
+
+// RepairSession relaunches the SSH connection of every disconnected window whose
+// name matches one of servers, leaving working windows alone, and types the
+// servers' startup input again. A single-server
+// session's only window is matched by the session name instead.
+func (m *Manager) RepairSession(sessionName string, servers []Server) (RepairResult, error) {
+	var result RepairResult
+	states, err := m.ListWindowStates(sessionName)
+	if err != nil {
+		return result, err
+	}
+
+	byName := make(map[string]Server, len(servers))
+	var names []string
+	for _, server := range servers {
+		byName[server.GetName()] = server
+		names = append(names, server.GetName())
+	}
+	sessionServer := ServerForSession(sessionName, names)
+
+	startup := make(map[string][]string)
+	for _, state := range states {
+		if !state.Disconnected() {
+			result.Healthy++
+			continue
+		}
+		server, ok := byName[state.Name]
+		if !ok && len(states) == 1 && sessionServer != "" {
+			server, ok = byName[sessionServer], true
+		}
+		if !ok {
+			result.Unknown = append(result.Unknown, state.Name)
+			continue
+		}
+		if refuser, ok := server.(Refuser); ok && refuser.Refused() != nil {
+			result.Refused = append(result.Refused, state.Name)
+			continue
+		}
+
+		sshCommand, err := m.buildSSHCommand(server)
+		if err != nil {
+			return result, fmt.Errorf("failed to build SSH command for %s: %w", server.GetName(), err)
+		}
+		windowTarget := fmt.Sprintf("%s:%d", sessionName, state.Index)
+		if err := m.respawnWindow(windowTarget, windowEnvironment(server), state.Dead); err != nil {
+			return result, err
+		}
+		if err := m.SendKeysToWindow(windowTarget, sshCommand); err != nil {
+			return result, err
+		}
+		if lines := startupInput(server); len(lines) > 0 {
+			startup[windowTarget] = lines
+		}
+		result.Reconnected = append(result.Reconnected, state.Name)
+	}
+	m.sendStartupInput(startup)
+	return result, nil
+}
+
 // GetSessionInfo returns detailed information about a session
 func (m *Manager) GetSessionInfo(sessionName string) (map[string]string, error) {
 	if !m.SessionExists(sessionName) {
@@ -346,6 +810,12 @@ func (m *Manager) GetSessionInfo(sessionName string) (map[string]string, error)
 		"created":        fields[5],
 	}
 
+	// session_attached also counts control mode clients such as sshm's own
+	if clients, err := m.userClientCount(sessionName); err == nil {
+		info["attached"] = boolFlag(clients > 0)
+		info["many_attached"] = boolFlag(clients > 1)
+	}
+
 	return info, nil
 }
 
@@ -411,60 +881,130 @@ func (m *Manager) GetSessionActivity(sessionName string) (string, error) {
 
 // RefreshSessionInfo provides updated session information for TUI integration
 func (m *Manager) RefreshSessionInfo() ([]SessionInfo, error) {
-	detailedSessions, err := m.ListSessionsDetailed()
+	states, err := m.SessionStates()
 	if err != nil {
 		return nil, err
 	}
 
-	var sessionInfos []SessionInfo
-	for _, sessionData := range detailedSessions {
-		info := SessionInfo{
-			Name:         sessionData["name"],
-			Windows:      0, // Will be set below
-			Status:       "active", // Default
-			LastActivity: "unknown",
-		}
+	sessionInfos := make([]SessionInfo, 0, len(states))
+	for _, state := range states {
+		sessionInfos = append(sessionInfos, SessionInfo{
+			Name:         state.Name,
+			Windows:      state.Windows,
+			Clients:      state.Clients,
+			Status:       state.Status(),
+			LastActivity: formatActivityTime(state.Activity, time.Now()),
+		})
+	}
+	return sessionInfos, nil
+}
 
-		// Parse window count
-		if windowStr, ok := sessionData["windows"]; ok {
-			if windows, err := strconv.Atoi(windowStr); err == nil {
-				info.Windows = windows
-			}
+// SessionStates returns the state of every session from the control mode client,
+// attaching it on first use and again after it exited. Without a usable control
+// client the state is read with one-shot list commands instead.
+func (m *Manager) SessionStates() ([]SessionState, error) {
+	if client := m.controlClient(); client != nil {
+		if states, err := client.Sessions(); err == nil {
+			return states, nil
 		}
+	}
 
-		// Determine status
-		if sessionData["attached"] == "1" {
-			if sessionData["many_attached"] == "1" {
-				info.Status = "multi-attached"
-			} else {
-				info.Status = "attached"
-			}
-		} else {
-			info.Status = "detached"
-		}
+	sessionOutput, err := execCommand("tmux", "list-sessions", "-F", sessionStateFormat).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tmux sessions: %w", err)
+	}
+	clientOutput, err := execCommand("tmux", "list-clients", "-F", clientFormat).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tmux clients: %w", err)
+	}
+	return parseSessionStates(outputLines(sessionOutput), outputLines(clientOutput)), nil
+}
+
+// controlClient returns the attached control client, starting one when needed
+func (m *Manager) controlClient() *ControlClient {
+	m.controlMu.Lock()
+	defer m.controlMu.Unlock()
+
+	if m.control != nil && m.control.Alive() {
+		return m.control
+	}
+	if m.control != nil {
+		m.control.Close()
+		m.control = nil
+	}
+	client, err := StartControlClient()
+	if err != nil {
+		return nil
+	}
+	m.control = client
+	return client
+}
+
+// Close detaches the control mode client, if any
+func (m *Manager) Close() {
+	m.controlMu.Lock()
+	defer m.controlMu.Unlock()
+	if m.control != nil {
+		m.control.Close()
+		m.control = nil
+	}
+}
 
-		// Parse activity time (this would need more sophisticated parsing in real implementation)
-		if activity, ok := sessionData["activity"]; ok && activity != "" {
-			info.LastActivity = m.formatActivityTime(activity)
+// userClientCount counts the clients attached to a session, leaving out control
+// mode clients
+func (m *Manager) userClientCount(sessionName string) (int, error) {
+	output, err := execCommand("tmux", "list-clients", "-t", sessionName, "-F", "#{client_control_mode}").Output()
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	for _, line := range outputLines(output) {
+		if line == "0" {
+			count++
 		}
+	}
+	return count, nil
+}
 
-		sessionInfos = append(sessionInfos, info)
+// outputLines splits command output into lines, without a trailing empty line
+func outputLines(output []byte) []string {
+	trimmed := strings.TrimRight(string(output), "\n")
+	if trimmed == "" {
+		return nil
 	}
+	return strings.Split(trimmed, "\n")
+}
 
-	return sessionInfos, nil
+func boolFlag(value bool) string {
+	if value {
+		return "1"
+	}
+	return "0"
 }
 
-// formatActivityTime formats tmux activity timestamp to human-readable format
-func (m *Manager) formatActivityTime(timestamp string) string {
-	// This is a simplified implementation
-	// In a real implementation, you'd parse the tmux timestamp and format it
-	return "recent" // Placeholder
+// formatActivityTime formats a session's last activity relative to now
+func formatActivityTime(activity, now time.Time) string {
+	if activity.IsZero() {
+		return "unknown"
+	}
+	diff := now.Sub(activity)
+	switch {
+	case diff < time.Minute:
+		return "just now"
+	case diff < time.Hour:
+		return fmt.Sprintf("%dm ago", int(diff.Minutes()))
+	case diff < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(diff.Hours()))
+	default:
+		return fmt.Sprintf("%dd ago", int(diff.Hours()/24))
+	}
 }
 
 // SessionInfo represents session information for TUI integration
 type SessionInfo struct {
 	Name         string
 	Windows      int
+	Clients      int // Attached clients, teammates who joined included
 	Status       string
 	LastActivity string
 }
@@ -478,3 +1018,57 @@ func contains(slice []string, item string) bool {
 	}
 	return false
 }
+
+// HookEvents are the tmux events that change the session list shown by sshm
+var HookEvents = []string{"session-created", "session-closed", "session-renamed", "client-attached", "client-detached"}
+
+// hookTarget addresses sshm's entry in a hook's array so user hooks for the same
+// event are left alone
+func hookTarget(event string, index int) string {
+	return fmt.Sprintf("%s[%d]", event, index)
+}
+
+// InstallHooks registers command as a background run-shell for every HookEvents
+// entry at the given array index
+func (m *Manager) InstallHooks(index int, command string) error {
+	hook := fmt.Sprintf("run-shell -b \"%s\"", escapeTmuxDoubleQuoted(command))
+	for _, event := range HookEvents {
+		cmd := execCommand("tmux", "set-hook", "-g", hookTarget(event, index), hook)
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("failed to set tmux hook '%s': %s", event, strings.TrimSpace(string(output)))
+		}
+	}
+	return nil
+}
+
+// HooksInstalled reports whether the hooks set by InstallHooks are still present;
+// they disappear when the tmux server exits
+func (m *Manager) HooksInstalled(index int) bool {
+	cmd := execCommand("tmux", "show-hooks", "-g", HookEvents[0])
+	output, err := cmd.Output()
+	if err != nil {
+		return false
+	}
+	return strings.Contains(string(output), hookTarget(HookEvents[0], index)+" ")
+}
+
+// RemoveHooks unregisters the hooks set by InstallHooks
+func (m *Manager) RemoveHooks(index int) error {
+	var failed []string
+	for _, event := range HookEvents {
+		if err := execCommand("tmux", "set-hook", "-gu", hookTarget(event, index)).Run(); err != nil {
+			failed = append(failed, event)
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to remove tmux hooks: %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// escapeTmuxDoubleQuoted escapes s for use inside a double-quoted tmux string,
+// where tmux would otherwise expand variables and interpret backslashes
+func escapeTmuxDoubleQuoted(s string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, `$`, `\$`)
+	return replacer.Replace(s)
+}
@@ -1,8 +1,11 @@
 package tmux
 
 import (
+  "errors"
   "fmt"
   "os/exec"
+  "reflect"
+  "strings"
   "testing"
 )
 
@@ -961,4 +964,416 @@ func stringSliceEqual(a, b []string) bool {
     }
   }
   return true
-}
\ No newline at end of file
+}
+type mockServerWithOptions struct {
+	mockServer
+	effectiveHostname string
+	sshOptions        string
+}
+
+func (s *mockServerWithOptions) GetEffectiveHostname() string { return s.effectiveHostname }
+func (s *mockServerWithOptions) GetSSHOptions() string        { return s.sshOptions }
+
+func TestBuildSSHCommandWithConnectionOptions(t *testing.T) {
+	manager := NewManager()
+	server := &mockServerWithOptions{
+		mockServer:        mockServer{name: "intranet", hostname: "app.corp.local", port: 22, username: "ops", authType: "password", valid: true},
+		effectiveHostname: "10.0.4.12",
+		sshOptions:        " -o HostKeyAlias=app.corp.local",
+	}
+
+	result, err := manager.buildSSHCommand(server)
+	if err != nil {
+		t.Fatalf("buildSSHCommand() error = %v", err)
+	}
+
+	expected := "ssh -t ops@10.0.4.12 -o ServerAliveInterval=60 -o ServerAliveCountMax=3 -o HostKeyAlias=app.corp.local"
+	if result != expected {
+		t.Errorf("buildSSHCommand() = %v, want %v", result, expected)
+	}
+}
+
+type mockCommandServer struct {
+	mockServer
+	command string
+}
+
+func (s *mockCommandServer) SSHCommand() (string, error) { return s.command, nil }
+
+func TestBuildSSHCommandUsesServerCommand(t *testing.T) {
+	manager := NewManager()
+	server := &mockCommandServer{
+		mockServer: mockServer{name: "web1", hostname: "web1.example.com", port: 22, username: "ops", authType: "password", valid: true},
+		command:    "sshpass -e ssh -t ops@web1.example.com -o ServerAliveInterval=60 -o ServerAliveCountMax=3",
+	}
+
+	result, err := manager.buildSSHCommand(server)
+	if err != nil {
+		t.Fatalf("buildSSHCommand() error = %v", err)
+	}
+	if result != server.command {
+		t.Errorf("buildSSHCommand() = %v, want %v", result, server.command)
+	}
+}
+
+func TestServerForSession(t *testing.T) {
+	servers := []string{"web.prod", "db-1", "db"}
+
+	tests := map[string]string{
+		"web_prod":   "web.prod",
+		"web_prod-2": "web.prod",
+		"db-1":       "db-1",
+		"db-1-1":     "db-1",
+		"db-3":       "db",
+		"unknown":    "",
+	}
+	for session, want := range tests {
+		if got := ServerForSession(session, servers); got != want {
+			t.Errorf("ServerForSession(%q) = %q, want %q", session, got, want)
+		}
+	}
+}
+
+func TestSessionsOfServer(t *testing.T) {
+	servers := []string{"web.prod", "db-1", "db"}
+	sessions := []string{"web_prod", "db-1", "db-2", "db", "web_prod-2", "other"}
+
+	got := SessionsOfServer(sessions, "db", servers)
+	if strings.Join(got, ",") != "db-2,db" {
+		t.Errorf("SessionsOfServer(db) = %v, want [db-2 db]", got)
+	}
+	if got := SessionsOfServer(sessions, "web.prod", servers); len(got) != 2 {
+		t.Errorf("SessionsOfServer(web.prod) = %v, want 2 sessions", got)
+	}
+}
+
+func TestRenamedSession(t *testing.T) {
+	if got := RenamedSession("web_example_com-2", "web.example.com", "aws-web"); got != "aws-web-2" {
+		t.Errorf("RenamedSession() = %q, want aws-web-2", got)
+	}
+	if got := RenamedSession("db", "db", "db.prod"); got != "db_prod" {
+		t.Errorf("RenamedSession() = %q, want db_prod", got)
+	}
+}
+
+func TestRenameServerSessions(t *testing.T) {
+	original := execCommand
+	defer func() { execCommand = original }()
+	var calls []string
+	execCommand = func(name string, arg ...string) *exec.Cmd {
+		calls = append(calls, strings.Join(arg, " "))
+		return exec.Command("true")
+	}
+
+	manager := &Manager{existingSessions: []string{"a", "a-a", "a-2", "db", "other"}}
+	renames := map[string]string{"a": "a-a", "a-a": "a-a-a"}
+	renamed, err := manager.RenameServerSessions(renames, []string{"a", "a-a", "db"})
+	if err != nil {
+		t.Fatalf("RenameServerSessions() error = %v", err)
+	}
+	// a-a gives up its session name before a takes it
+	want := []string{"rename-session -t a-2 a-a-2", "rename-session -t a-a a-a-a", "rename-session -t a a-a"}
+	if strings.Join(calls, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected renames %v, got %v", want, calls)
+	}
+	if len(renamed) != 3 || renamed["a"] != "a-a" {
+		t.Errorf("Unexpected renamed sessions %v", renamed)
+	}
+
+	// Servers swapping names can't swap their sessions
+	calls = nil
+	manager = &Manager{existingSessions: []string{"a", "b"}}
+	if _, err := manager.RenameServerSessions(map[string]string{"a": "b", "b": "a"}, []string{"a", "b"}); err == nil || len(calls) != 0 {
+		t.Errorf("Expected swapped sessions to be reported and left alone, got %v, %v", err, calls)
+	}
+}
+
+func TestListWindows(t *testing.T) {
+  original := execCommand
+  defer func() { execCommand = original }()
+  execCommand = func(name string, arg ...string) *exec.Cmd {
+    return exec.Command("printf", "api\ndb\n")
+  }
+
+  manager := &Manager{}
+  windows, err := manager.ListWindows("prod")
+  if err != nil {
+    t.Fatalf("ListWindows() error = %v", err)
+  }
+  if len(windows) != 2 || windows[0] != "api" || windows[1] != "db" {
+    t.Errorf("ListWindows() = %v, want [api db]", windows)
+  }
+}
+
+func TestCreateSessionWithWindows(t *testing.T) {
+  original := execCommand
+  defer func() { execCommand = original }()
+  var calls []string
+  execCommand = func(name string, arg ...string) *exec.Cmd {
+    calls = append(calls, strings.Join(arg, " "))
+    return exec.Command("true")
+  }
+
+  manager := &Manager{}
+  server := &mockServer{name: "api", hostname: "api.example.com", port: 22, username: "ops", authType: "key", keyPath: "~/.ssh/id_ed25519", valid: true}
+  err := manager.CreateSessionWithWindows("prod", []Window{{Name: "api", Server: server}, {Name: "logs"}})
+  if err != nil {
+    t.Fatalf("CreateSessionWithWindows() error = %v", err)
+  }
+
+  if len(calls) != 4 {
+    t.Fatalf("Expected 4 tmux calls, got %d: %v", len(calls), calls)
+  }
+  if !strings.HasPrefix(calls[2], "send-keys -t prod:0 ssh -t ops@api.example.com") {
+    t.Errorf("Expected ssh command sent to the first window, got %q", calls[2])
+  }
+  if calls[3] != "new-window -t prod -n logs -a" {
+    t.Errorf("Expected plain second window, got %q", calls[3])
+  }
+}
+
+func TestWindowStateDisconnected(t *testing.T) {
+  tests := []struct {
+    state    WindowState
+    expected bool
+  }{
+    {WindowState{Command: "ssh"}, false},
+    {WindowState{Command: "scp"}, false},
+    {WindowState{Command: "zsh"}, true},
+    {WindowState{Command: "ssh", Dead: true}, true},
+  }
+  for _, tt := range tests {
+    if got := tt.state.Disconnected(); got != tt.expected {
+      t.Errorf("Disconnected() for %+v = %v, want %v", tt.state, got, tt.expected)
+    }
+  }
+}
+
+func TestRepairSession(t *testing.T) {
+  original := execCommand
+  defer func() { execCommand = original }()
+  var calls []string
+  execCommand = func(name string, arg ...string) *exec.Cmd {
+    calls = append(calls, strings.Join(arg, " "))
+    if arg[0] == "list-windows" {
+      return exec.Command("printf", "0\tweb\tssh\t0\n1\tdb\tbash\t0\n2\tcache\tssh\t1\n3\tnotes\tzsh\t0\n")
+    }
+    return exec.Command("true")
+  }
+
+  servers := []Server{
+    &mockServer{name: "web", hostname: "web.example.com", port: 22, username: "ops", valid: true},
+    &mockServer{name: "db", hostname: "db.example.com", port: 22, username: "ops", valid: true},
+    &mockServer{name: "cache", hostname: "cache.example.com", port: 22, username: "ops", valid: true},
+  }
+  manager := &Manager{}
+  result, err := manager.RepairSession("prod", servers)
+  if err != nil {
+    t.Fatalf("RepairSession() error = %v", err)
+  }
+
+  if result.Healthy != 1 || len(result.Reconnected) != 2 || len(result.Unknown) != 1 || result.Unknown[0] != "notes" {
+    t.Errorf("Unexpected result: %+v", result)
+  }
+  expected := []string{"send-keys -t prod:1 ssh -t ops@db.example.com", "respawn-pane -k -t prod:2", "send-keys -t prod:2 ssh -t ops@cache.example.com"}
+  if len(calls) != 4 {
+    t.Fatalf("Expected 4 tmux calls, got %d: %v", len(calls), calls)
+  }
+  for i, prefix := range expected {
+    if !strings.HasPrefix(calls[i+1], prefix) {
+      t.Errorf("Call %d = %q, want prefix %q", i+1, calls[i+1], prefix)
+    }
+  }
+}
+
+type mockEnvServer struct {
+  mockCommandServer
+  env []string
+}
+
+func (s *mockEnvServer) WindowEnvironment() []string { return s.env }
+
+func TestWindowEnvironmentIsNeverTyped(t *testing.T) {
+  original := execCommand
+  defer func() { execCommand = original }()
+  var calls []string
+  execCommand = func(name string, arg ...string) *exec.Cmd {
+    calls = append(calls, strings.Join(arg, " "))
+    return exec.Command("true")
+  }
+
+  server := &mockEnvServer{
+    mockCommandServer: mockCommandServer{
+      mockServer: mockServer{name: "web1", hostname: "web1.example.com", port: 22, username: "ops", valid: true},
+      command:    "sshpass -e ssh -t ops@web1.example.com",
+    },
+    env: []string{"SSHPASS=s3cret"},
+  }
+  manager := &Manager{}
+  if err := manager.CreateSessionWithWindows("prod", []Window{{Name: "web1", Server: server}}); err != nil {
+    t.Fatalf("CreateSessionWithWindows() error = %v", err)
+  }
+
+  respawned := false
+  for _, call := range calls {
+    if call == "respawn-pane -k -t prod:0 -e SSHPASS=s3cret" {
+      respawned = true
+    } else if strings.Contains(call, "s3cret") {
+      t.Errorf("Expected the secret only in the window environment, got %q", call)
+    }
+    if strings.HasPrefix(call, "send-keys") && !respawned {
+      t.Errorf("Expected the environment set before the command is typed, got %v", calls)
+    }
+  }
+  if !respawned {
+    t.Errorf("Expected the window to be respawned with its environment, got %v", calls)
+  }
+}
+
+type mockRefusingServer struct {
+  mockServer
+}
+
+func (s *mockRefusingServer) Refused() error { return errors.New("login banner not accepted yet") }
+
+func TestRepairSessionLeavesRefusedWindows(t *testing.T) {
+  original := execCommand
+  defer func() { execCommand = original }()
+  var calls []string
+  execCommand = func(name string, arg ...string) *exec.Cmd {
+    calls = append(calls, strings.Join(arg, " "))
+    if arg[0] == "list-windows" {
+      return exec.Command("printf", "0\tweb\tbash\t0\n1\tdb\tbash\t0\n")
+    }
+    return exec.Command("true")
+  }
+
+  servers := []Server{
+    &mockServer{name: "web", hostname: "web.example.com", port: 22, username: "ops", valid: true},
+    &mockRefusingServer{mockServer{name: "db", hostname: "db.example.com", port: 22, username: "ops", valid: true}},
+  }
+  result, err := (&Manager{}).RepairSession("prod", servers)
+  if err != nil {
+    t.Fatalf("RepairSession() error = %v", err)
+  }
+  if !reflect.DeepEqual(result.Reconnected, []string{"web"}) || !reflect.DeepEqual(result.Refused, []string{"db"}) {
+    t.Errorf("Unexpected result: %+v", result)
+  }
+  if len(calls) != 2 {
+    t.Errorf("Expected only web's window to be sent keys, got %v", calls)
+  }
+}
+
+func TestRepairSingleServerSession(t *testing.T) {
+  original := execCommand
+  defer func() { execCommand = original }()
+  var calls []string
+  execCommand = func(name string, arg ...string) *exec.Cmd {
+    calls = append(calls, strings.Join(arg, " "))
+    if arg[0] == "list-windows" {
+      return exec.Command("printf", "0\tzsh\tzsh\t0\n")
+    }
+    return exec.Command("true")
+  }
+
+  servers := []Server{&mockServer{name: "web.example", hostname: "web.example.com", port: 22, username: "ops", valid: true}}
+  result, err := (&Manager{}).RepairSession("web_example-1", servers)
+  if err != nil {
+    t.Fatalf("RepairSession() error = %v", err)
+  }
+  if len(result.Reconnected) != 1 || !strings.HasPrefix(calls[1], "send-keys -t web_example-1:0 ssh -t ops@web.example.com") {
+    t.Errorf("Expected the only window to be reconnected, got %+v with calls %v", result, calls)
+  }
+}
+
+func TestInstallHooks(t *testing.T) {
+  original := execCommand
+  defer func() { execCommand = original }()
+  var calls [][]string
+  execCommand = func(name string, arg ...string) *exec.Cmd {
+    calls = append(calls, arg)
+    return exec.Command("true")
+  }
+
+  manager := NewManager()
+  if err := manager.InstallHooks(4242, `'/opt/$HOME/sshm' sessions notify '/tmp/sshm.sock'`); err != nil {
+    t.Fatalf("InstallHooks() error = %v", err)
+  }
+  if len(calls) != len(HookEvents) {
+    t.Fatalf("Expected %d tmux calls, got %d", len(HookEvents), len(calls))
+  }
+  expected := []string{"set-hook", "-g", "session-created[4242]", `run-shell -b "'/opt/\$HOME/sshm' sessions notify '/tmp/sshm.sock'"`}
+  if strings.Join(calls[0], "|") != strings.Join(expected, "|") {
+    t.Errorf("First call = %q, want %q", calls[0], expected)
+  }
+
+  calls = nil
+  if err := manager.RemoveHooks(4242); err != nil {
+    t.Fatalf("RemoveHooks() error = %v", err)
+  }
+  if len(calls) != len(HookEvents) || calls[0][1] != "-gu" || calls[0][2] != "session-created[4242]" {
+    t.Errorf("Unexpected remove calls: %q", calls)
+  }
+}
+
+func TestHooksInstalled(t *testing.T) {
+  original := execCommand
+  defer func() { execCommand = original }()
+  execCommand = func(name string, arg ...string) *exec.Cmd {
+    return exec.Command("printf", "session-created[0] display-message hi\nsession-created[4242] run-shell -b x\n")
+  }
+
+  manager := NewManager()
+  if !manager.HooksInstalled(4242) {
+    t.Error("Expected hooks at index 4242 to be reported as installed")
+  }
+  if manager.HooksInstalled(42) {
+    t.Error("Expected hooks at index 42 to be reported as missing")
+  }
+}
+
+func TestLostWindows(t *testing.T) {
+  original := execCommand
+  defer func() { execCommand = original }()
+  execCommand = func(name string, arg ...string) *exec.Cmd {
+    switch {
+    case arg[0] == "list-windows":
+      return exec.Command("printf", "0\tweb\tssh\t0\t\n1\tdb\tzsh\t0\t\n2\tcache\tbash\t0\t\n3\tlogs\tssh\t1\t255\n4\tapi\tssh\t1\t0\n")
+    case arg[0] == "capture-pane" && arg[3] == "prod:1":
+      return exec.Command("printf", "ops@db:~$ \nclient_loop: send disconnect: Broken pipe\nme@laptop ~ %% \n\n\n")
+    case arg[0] == "capture-pane":
+      return exec.Command("printf", "ops@cache:~$ exit\nlogout\nConnection to cache.example.com closed.\nme@laptop ~ %% \n")
+    }
+    return exec.Command("true")
+  }
+
+  lost, err := NewManager().LostWindows("prod")
+  if err != nil {
+    t.Fatalf("LostWindows() error = %v", err)
+  }
+  var names []string
+  for _, window := range lost {
+    names = append(names, window.Name)
+  }
+  if strings.Join(names, ",") != "db,logs" {
+    t.Errorf("LostWindows() = %v, want [db logs]", names)
+  }
+}
+
+func TestConnectionLostOutput(t *testing.T) {
+  tests := []struct {
+    lines    []string
+    expected bool
+  }{
+    {[]string{"Timeout, server 10.0.0.5 not responding.", "$ "}, true},
+    {[]string{"Read from remote host web: Connection reset by peer", "", "$"}, true},
+    {[]string{"Connection to web closed.", "$ "}, false},
+    {[]string{"ping timed out", "ok", "more output", "still more", "$ "}, false},
+    {nil, false},
+  }
+  for _, tt := range tests {
+    if got := connectionLostOutput(tt.lines); got != tt.expected {
+      t.Errorf("connectionLostOutput(%q) = %v, want %v", tt.lines, got, tt.expected)
+    }
+  }
+}
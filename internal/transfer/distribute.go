@@ -0,0 +1,160 @@
+package transfer
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"sshm/internal/config"
+	"sshm/internal/shellquote"
+)
+
+// DefaultDistributionParallel is how many servers a distribution copies to at once unless configured
+const DefaultDistributionParallel = 8
+
+// Stages of a distribution to one server, reported with the failure
+const (
+	StageCopy   = "copy"
+	StageVerify = "verify"
+	StagePost   = "post"
+)
+
+// Distribution pushes one local file to the same path on many servers, e.g. a
+// certificate or a config file, optionally running a command afterwards
+type Distribution struct {
+	Source      string
+	Destination string // Remote path, the same on every server
+	PostCommand string // Run on each server once the file is in place, e.g. "sudo systemctl reload nginx"
+	Parallel    int    // Servers copied to at once (0 = DefaultDistributionParallel)
+}
+
+// DistributionResult is the outcome on one server
+type DistributionResult struct {
+	Server   string
+	Stage    string // Where it failed, empty on success
+	Output   string // Output of the post-copy command
+	Err      error
+	Duration time.Duration
+}
+
+// Checksum returns the SHA-256 of a local file in hex
+func Checksum(file string) (string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return "", fmt.Errorf("failed to open %s: %w", file, err)
+	}
+	defer f.Close()
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", file, err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// Run copies the file to every server in parallel, calling onResult as each
+// finishes. Every copy lands in a temporary file next to the destination and
+// only replaces it once its checksum matches, so a failed copy never leaves a
+// truncated file behind. The results are returned in the order of servers.
+func (d Distribution) Run(ctx context.Context, servers []config.Server, onResult func(DistributionResult)) ([]DistributionResult, error) {
+	info, err := os.Stat(d.Source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", d.Source, err)
+	}
+	if info.IsDir() {
+		return nil, fmt.Errorf("%s is a directory; only single files can be distributed", d.Source)
+	}
+	if strings.TrimSpace(d.Destination) == "" || strings.HasSuffix(d.Destination, "/") {
+		return nil, fmt.Errorf("the remote path must name a file")
+	}
+	checksum, err := Checksum(d.Source)
+	if err != nil {
+		return nil, err
+	}
+
+	parallel := d.Parallel
+	if parallel <= 0 {
+		parallel = DefaultDistributionParallel
+	}
+
+	results := make([]DistributionResult, len(servers))
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	semaphore := make(chan struct{}, parallel)
+	for i, server := range servers {
+		wg.Add(1)
+		go func(i int, server config.Server) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			result := d.distributeTo(ctx, server, info, checksum)
+			results[i] = result
+			if onResult != nil {
+				mu.Lock()
+				onResult(result)
+				mu.Unlock()
+			}
+		}(i, server)
+	}
+	wg.Wait()
+	return results, nil
+}
+
+// distributeTo copies, verifies and installs the file on one server, then runs the post command
+func (d Distribution) distributeTo(ctx context.Context, server config.Server, info os.FileInfo, checksum string) DistributionResult {
+	started := time.Now()
+	result := DistributionResult{Server: server.Name}
+	fail := func(stage string, err error) DistributionResult {
+		result.Stage = stage
+		result.Err = err
+		result.Duration = time.Since(started)
+		return result
+	}
+	if ctx.Err() != nil {
+		return fail(StageCopy, ctx.Err())
+	}
+
+	temporary := temporaryPath(d.Destination)
+	job := Job{Server: server, Direction: Upload, Source: d.Source, Destination: temporary, Size: info.Size()}
+	if err := job.Run(ctx, nil); err != nil {
+		_, _ = runRemote(context.Background(), server, "rm -f -- "+shellquote.Quote(temporary), nil)
+		return fail(StageCopy, err)
+	}
+
+	if _, err := runRemote(ctx, server, installCommand(temporary, d.Destination, checksum, info.Mode().Perm()), nil); err != nil {
+		return fail(StageVerify, err)
+	}
+
+	if d.PostCommand != "" {
+		output, err := runRemote(ctx, server, d.PostCommand, nil)
+		result.Output = strings.TrimSpace(output)
+		if err != nil {
+			return fail(StagePost, err)
+		}
+	}
+	result.Duration = time.Since(started)
+	return result
+}
+
+// temporaryPath is where a copy lands before it is verified: a hidden file
+// next to the destination, so the final rename stays on one filesystem
+func temporaryPath(destination string) string {
+	return path.Join(path.Dir(destination), "."+path.Base(destination)+".sshm-tmp")
+}
+
+// installCommand checks the copy's SHA-256, removing it on a mismatch, and
+// moves it over the destination with the source's permissions
+func installCommand(temporary, destination, checksum string, mode os.FileMode) string {
+	tmp := shellquote.Quote(temporary)
+	return fmt.Sprintf(`sum=$( (sha256sum -- %[1]s 2>/dev/null || shasum -a 256 -- %[1]s) | cut -d' ' -f1); `+
+		`if [ "$sum" != %[2]s ]; then rm -f -- %[1]s; echo "checksum mismatch: expected %[2]s, got ${sum:-none}" >&2; exit 1; fi; `+
+		`chmod %04[3]o %[1]s && mv -f -- %[1]s %[4]s`,
+		tmp, checksum, mode, shellquote.Quote(destination))
+}
@@ -0,0 +1,94 @@
+package transfer
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"sshm/internal/config"
+	"sshm/internal/shellquote"
+)
+
+// runInServerDirs runs the "remote" commands of each server in its own local directory
+func runInServerDirs(t *testing.T, root string) {
+	t.Helper()
+	original := sshCommand
+	sshCommand = func(server config.Server, command string) (*exec.Cmd, error) {
+		return exec.Command("sh", "-c", "cd "+shellquote.Quote(filepath.Join(root, server.Name))+" && "+command), nil
+	}
+	t.Cleanup(func() { sshCommand = original })
+}
+
+func TestDistributionRun(t *testing.T) {
+	root := t.TempDir()
+	runInServerDirs(t, root)
+	for _, name := range []string{"web1", "web2"} {
+		os.MkdirAll(filepath.Join(root, name), 0755)
+	}
+	source := filepath.Join(t.TempDir(), "app.conf")
+	os.WriteFile(source, []byte("payload"), 0640)
+
+	servers := []config.Server{{Name: "web1"}, {Name: "web2"}, {Name: "gone"}}
+	distribution := Distribution{Source: source, Destination: "app.conf", PostCommand: "cat app.conf && echo ' reloaded'", Parallel: 2}
+
+	var reported []string
+	results, err := distribution.Run(context.Background(), servers, func(result DistributionResult) {
+		reported = append(reported, result.Server)
+	})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if len(results) != 3 || len(reported) != 3 {
+		t.Fatalf("Expected a result per server, got %+v and %v", results, reported)
+	}
+
+	for _, result := range results[:2] {
+		if result.Err != nil {
+			t.Fatalf("Expected %s to succeed, got %v", result.Server, result.Err)
+		}
+		if result.Output != "payload reloaded" {
+			t.Errorf("Expected the post command output on %s, got %q", result.Server, result.Output)
+		}
+		installed := filepath.Join(root, result.Server, "app.conf")
+		data, _ := os.ReadFile(installed)
+		info, _ := os.Stat(installed)
+		if string(data) != "payload" || info.Mode().Perm() != 0640 {
+			t.Errorf("Expected the file with the source's mode on %s, got %q %v", result.Server, data, info.Mode())
+		}
+		if _, err := os.Stat(filepath.Join(root, result.Server, ".app.conf.sshm-tmp")); !os.IsNotExist(err) {
+			t.Errorf("Expected no temporary file left on %s", result.Server)
+		}
+	}
+	if results[2].Err == nil || results[2].Stage != StageCopy {
+		t.Errorf("Expected the unreachable server to fail copying, got %+v", results[2])
+	}
+}
+
+func TestDistributionRejectsDirectories(t *testing.T) {
+	distribution := Distribution{Source: t.TempDir(), Destination: "/etc/app.conf"}
+	if _, err := distribution.Run(context.Background(), nil, nil); err == nil {
+		t.Error("Expected a directory to be rejected")
+	}
+}
+
+func TestInstallCommandChecksumMismatch(t *testing.T) {
+	dir := t.TempDir()
+	temporary := filepath.Join(dir, ".app.conf.sshm-tmp")
+	destination := filepath.Join(dir, "app.conf")
+	os.WriteFile(temporary, []byte("truncat"), 0644)
+	os.WriteFile(destination, []byte("original"), 0644)
+
+	output, err := exec.Command("sh", "-c", installCommand(temporary, destination, strings.Repeat("0", 64), 0644)).CombinedOutput()
+	if err == nil || !strings.Contains(string(output), "checksum mismatch") {
+		t.Fatalf("Expected a checksum mismatch, got %v: %s", err, output)
+	}
+	if data, _ := os.ReadFile(destination); string(data) != "original" {
+		t.Errorf("Expected the destination untouched, got %q", data)
+	}
+	if _, err := os.Stat(temporary); !os.IsNotExist(err) {
+		t.Error("Expected the bad copy to be removed")
+	}
+}
@@ -0,0 +1,212 @@
+package transfer
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"sshm/internal/config"
+)
+
+// Throttle limits the bytes per second passing through it, shared by every
+// transfer using it
+type Throttle struct {
+	mu     sync.Mutex
+	rate   float64 // Bytes per second, 0 when unlimited
+	tokens float64 // Bytes that may pass right away; negative when in debt
+	last   time.Time
+}
+
+// NewThrottle returns a throttle passing bytesPerSecond, or everything when 0
+func NewThrottle(bytesPerSecond int64) *Throttle {
+	return &Throttle{rate: float64(bytesPerSecond), last: time.Now()}
+}
+
+// SetRate changes the limit, e.g. after the configuration changed
+func (t *Throttle) SetRate(bytesPerSecond int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.rate = float64(bytesPerSecond)
+}
+
+// Wait accounts for n bytes and sleeps until they fit within the rate, or ctx is done
+func (t *Throttle) Wait(ctx context.Context, n int) error {
+	t.mu.Lock()
+	if t.rate <= 0 {
+		t.mu.Unlock()
+		return nil
+	}
+	now := time.Now()
+	// Allow up to a second of burst, so short pauses don't slow the average down
+	t.tokens += now.Sub(t.last).Seconds() * t.rate
+	if t.tokens > t.rate {
+		t.tokens = t.rate
+	}
+	t.last = now
+	t.tokens -= float64(n)
+	var delay time.Duration
+	if t.tokens < 0 {
+		delay = time.Duration(-t.tokens / t.rate * float64(time.Second))
+	}
+	t.mu.Unlock()
+
+	if delay == 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Scheduler runs transfers within the configured limits: at most so many at
+// once, globally and per server, in the order they were queued, sharing the
+// global and per-server bandwidth
+type Scheduler struct {
+	mu        sync.Mutex
+	limits    config.TransferLimits
+	running   int
+	perServer map[string]int
+	waiting   []*slotRequest
+	global    *Throttle
+	throttles map[string]*Throttle
+}
+
+// slotRequest is a transfer waiting for a free slot
+type slotRequest struct {
+	server config.Server
+	ready  chan struct{}
+}
+
+// NewScheduler returns a scheduler applying the global limits
+func NewScheduler(limits config.TransferLimits) (*Scheduler, error) {
+	s := &Scheduler{perServer: make(map[string]int), throttles: make(map[string]*Throttle), global: NewThrottle(0)}
+	if err := s.Configure(limits); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Configure replaces the global limits; running transfers pick up the new bandwidth
+func (s *Scheduler) Configure(limits config.TransferLimits) error {
+	if err := limits.Validate(); err != nil {
+		return err
+	}
+	bandwidth, _ := limits.BandwidthBytes()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.limits = limits
+	s.global.SetRate(bandwidth)
+	s.dispatch()
+	return nil
+}
+
+// Run waits for a free slot, calls onStart, then runs the job within the
+// bandwidth limits
+func (s *Scheduler) Run(ctx context.Context, job Job, onStart func(), progress func(done, total int64)) error {
+	if err := s.acquire(ctx, job.Server); err != nil {
+		return err
+	}
+	defer s.release(job.Server)
+	if onStart != nil {
+		onStart()
+	}
+
+	job.throttles = []*Throttle{s.global, s.serverThrottle(job.Server)}
+	return job.Run(ctx, progress)
+}
+
+// serverThrottle returns the throttle shared by the server's transfers
+func (s *Scheduler) serverThrottle(server config.Server) *Throttle {
+	bandwidth, _ := server.Transfers.BandwidthBytes()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	throttle, ok := s.throttles[server.Name]
+	if !ok {
+		throttle = NewThrottle(bandwidth)
+		s.throttles[server.Name] = throttle
+	} else {
+		throttle.SetRate(bandwidth)
+	}
+	return throttle
+}
+
+func (s *Scheduler) acquire(ctx context.Context, server config.Server) error {
+	request := &slotRequest{server: server, ready: make(chan struct{})}
+	s.mu.Lock()
+	s.waiting = append(s.waiting, request)
+	s.dispatch()
+	s.mu.Unlock()
+
+	select {
+	case <-request.ready:
+		return nil
+	case <-ctx.Done():
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		for i, waiting := range s.waiting {
+			if waiting == request {
+				s.waiting = append(s.waiting[:i], s.waiting[i+1:]...)
+				return ctx.Err()
+			}
+		}
+		// The slot was granted while giving up
+		s.releaseLocked(server)
+		return ctx.Err()
+	}
+}
+
+func (s *Scheduler) release(server config.Server) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.releaseLocked(server)
+}
+
+func (s *Scheduler) releaseLocked(server config.Server) {
+	s.running--
+	s.perServer[server.Name]--
+	s.dispatch()
+}
+
+// dispatch starts waiting transfers in order while slots are free; a transfer
+// held back by its server's limit doesn't block those to other servers
+func (s *Scheduler) dispatch() {
+	for i := 0; i < len(s.waiting); {
+		request := s.waiting[i]
+		if s.running >= s.maxConcurrent() {
+			return
+		}
+		if !s.canStart(request.server) {
+			i++
+			continue
+		}
+		s.running++
+		s.perServer[request.server.Name]++
+		s.waiting = append(s.waiting[:i], s.waiting[i+1:]...)
+		close(request.ready)
+	}
+}
+
+// canStart reports whether both the global and the server's limit have room
+func (s *Scheduler) canStart(server config.Server) bool {
+	if s.running >= s.maxConcurrent() {
+		return false
+	}
+	if server.Transfers != nil && server.Transfers.MaxConcurrent > 0 {
+		return s.perServer[server.Name] < server.Transfers.MaxConcurrent
+	}
+	return true
+}
+
+func (s *Scheduler) maxConcurrent() int {
+	if s.limits.MaxConcurrent > 0 {
+		return s.limits.MaxConcurrent
+	}
+	return config.DefaultMaxConcurrentTransfers
+}
@@ -0,0 +1,132 @@
+package transfer
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"sshm/internal/config"
+)
+
+func TestThrottleLimitsRate(t *testing.T) {
+	throttle := NewThrottle(100000)
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := throttle.Wait(context.Background(), 20000); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 500*time.Millisecond {
+		t.Errorf("Expected 60000 bytes at 100000 B/s to take about 0.6s, took %v", elapsed)
+	}
+
+	unlimited := NewThrottle(0)
+	start = time.Now()
+	unlimited.Wait(context.Background(), 1<<30)
+	if time.Since(start) > 50*time.Millisecond {
+		t.Error("Expected an unlimited throttle not to wait")
+	}
+}
+
+func TestThrottleWaitCancelled(t *testing.T) {
+	throttle := NewThrottle(1000)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := throttle.Wait(ctx, 100000); err != context.Canceled {
+		t.Errorf("Expected the wait to be cancelled, got %v", err)
+	}
+}
+
+// acquired asks for a slot in the background and returns a check of whether
+// it was granted within a short time
+func acquired(s *Scheduler, server config.Server) (chan error, func() bool) {
+	result := make(chan error, 1)
+	go func() { result <- s.acquire(context.Background(), server) }()
+	return result, func() bool {
+		select {
+		case <-result:
+			return true
+		case <-time.After(50 * time.Millisecond):
+			return false
+		}
+	}
+}
+
+func TestSchedulerConcurrencyLimits(t *testing.T) {
+	s, err := NewScheduler(config.TransferLimits{MaxConcurrent: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	web1 := config.Server{Name: "web1", Transfers: &config.TransferLimits{MaxConcurrent: 1}}
+	db1 := config.Server{Name: "db1"}
+
+	if err := s.acquire(context.Background(), web1); err != nil {
+		t.Fatal(err)
+	}
+	secondWeb, waitSecondWeb := acquired(s, web1)
+	if waitSecondWeb() {
+		t.Fatal("Expected a second transfer to web1 to wait for the per-server limit")
+	}
+	if _, started := acquired(s, db1); !started() {
+		t.Fatal("Expected a transfer to db1 to start past the waiting web1 transfer")
+	}
+	_, waitThird := acquired(s, db1)
+	if waitThird() {
+		t.Fatal("Expected the global limit of 2 to hold back a third transfer")
+	}
+
+	s.release(web1)
+	select {
+	case <-secondWeb:
+	case <-time.After(time.Second):
+		t.Fatal("Expected the waiting web1 transfer to start once a slot was released")
+	}
+}
+
+func TestSchedulerAcquireCancelled(t *testing.T) {
+	s, _ := NewScheduler(config.TransferLimits{MaxConcurrent: 1})
+	server := config.Server{Name: "web1"}
+	s.acquire(context.Background(), server)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := s.acquire(ctx, server); err == nil {
+		t.Fatal("Expected waiting for a slot to be cancelled")
+	}
+	if len(s.waiting) != 0 {
+		t.Errorf("Expected the cancelled request to leave the queue, got %d waiting", len(s.waiting))
+	}
+}
+
+func TestSchedulerRunAppliesBandwidth(t *testing.T) {
+	runLocally(t)
+	dir := t.TempDir()
+	source := filepath.Join(dir, "big.bin")
+	os.WriteFile(source, make([]byte, 96*1024), 0644)
+
+	s, err := NewScheduler(config.TransferLimits{Bandwidth: "128K"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	started := false
+	job := Job{Server: config.Server{Name: "web1"}, Direction: Upload, Source: source, Destination: filepath.Join(dir, "copy.bin"), Size: 96 * 1024}
+
+	begin := time.Now()
+	if err := s.Run(context.Background(), job, func() { started = true }, nil); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if !started {
+		t.Error("Expected onStart to be called")
+	}
+	if elapsed := time.Since(begin); elapsed < 500*time.Millisecond {
+		t.Errorf("Expected 96 KiB at 128 KiB/s to take about 0.75s, took %v", elapsed)
+	}
+}
+
+func TestNewSchedulerRejectsInvalidBandwidth(t *testing.T) {
+	if _, err := NewScheduler(config.TransferLimits{Bandwidth: "fast"}); err == nil {
+		t.Error("Expected an invalid global bandwidth to be rejected")
+	}
+}
@@ -0,0 +1,336 @@
+// Package transfer lists directories and copies files between the local
+// machine and a server by streaming them over ssh. Transfers resume from
+// the bytes already at the destination, so large files survive interruptions.
+package transfer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"sshm/internal/config"
+	"sshm/internal/power"
+	"sshm/internal/shellquote"
+)
+
+// sshCommand builds the ssh command running a remote command (variable to allow mocking in tests)
+var sshCommand = power.SSHCommand
+
+// Entry is a file or directory in a listing
+type Entry struct {
+	Name string
+	Dir  bool
+	Size int64
+}
+
+// Direction is which way a file is copied
+type Direction int
+
+const (
+	Upload   Direction = iota // Local to remote
+	Download                  // Remote to local
+)
+
+// String returns the direction as a verb
+func (d Direction) String() string {
+	if d == Upload {
+		return "upload"
+	}
+	return "download"
+}
+
+// Job copies or moves one file. Offset is where the copy starts, normally the
+// size of a partial destination file being resumed, or 0 to overwrite it.
+type Job struct {
+	Server      config.Server
+	Direction   Direction
+	Source      string
+	Destination string
+	Size        int64
+	Offset      int64
+	Move        bool // Remove the source once the copy succeeded
+
+	throttles []*Throttle // Bandwidth limits applied by the scheduler
+}
+
+// Name describes the job, e.g. "upload app.tar.gz to web1"
+func (j Job) Name() string {
+	verb := j.Direction.String()
+	if j.Move {
+		verb = "move"
+	}
+	if j.Direction == Upload {
+		return fmt.Sprintf("%s %s to %s", verb, filepath.Base(j.Source), j.Server.Name)
+	}
+	return fmt.Sprintf("%s %s from %s", verb, path.Base(j.Source), j.Server.Name)
+}
+
+// ListLocal lists a local directory, directories first
+func ListLocal(dir string) ([]Entry, error) {
+	items, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", dir, err)
+	}
+	entries := make([]Entry, 0, len(items))
+	for _, item := range items {
+		entry := Entry{Name: item.Name(), Dir: item.IsDir()}
+		if info, err := item.Info(); err == nil && !entry.Dir {
+			entry.Size = info.Size()
+		}
+		entries = append(entries, entry)
+	}
+	sortEntries(entries)
+	return entries, nil
+}
+
+// ListRemote lists a directory on the server, directories first, and returns
+// its absolute path; an empty dir lists the login directory
+func ListRemote(ctx context.Context, server config.Server, dir string) (string, []Entry, error) {
+	output, err := runRemote(ctx, server, listCommand(dir), nil)
+	if err != nil {
+		return "", nil, err
+	}
+	resolved, entries, err := parseListing(output)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to list %s on %s: %w", dir, server.Name, err)
+	}
+	return resolved, entries, nil
+}
+
+// listCommand prints the absolute directory path, then one "type<TAB>size<TAB>name"
+// line per entry, using only POSIX shell and utilities
+func listCommand(dir string) string {
+	if dir == "" {
+		dir = "."
+	}
+	return "cd -- " + shellquote.Quote(dir) + ` && pwd && for f in * .[!.]* ..?*; do ` +
+		`[ -e "$f" ] || [ -L "$f" ] || continue; ` +
+		`if [ -d "$f" ]; then printf 'd\t0\t%s\n' "$f"; ` +
+		`else printf 'f\t%s\t%s\n' "$(wc -c < "$f" 2>/dev/null | tr -d ' ')" "$f"; fi; done`
+}
+
+// parseListing parses the output of listCommand
+func parseListing(output string) (string, []Entry, error) {
+	lines := strings.Split(strings.TrimRight(output, "\n"), "\n")
+	dir := strings.TrimSpace(lines[0])
+	if dir == "" {
+		return "", nil, fmt.Errorf("missing directory path in listing")
+	}
+
+	var entries []Entry
+	for _, line := range lines[1:] {
+		fields := strings.SplitN(line, "\t", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		size, _ := strconv.ParseInt(fields[1], 10, 64)
+		entries = append(entries, Entry{Name: fields[2], Dir: fields[0] == "d", Size: size})
+	}
+	sortEntries(entries)
+	return dir, entries, nil
+}
+
+// sortEntries puts directories first, each group by name
+func sortEntries(entries []Entry) {
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Dir != entries[j].Dir {
+			return entries[i].Dir
+		}
+		return entries[i].Name < entries[j].Name
+	})
+}
+
+// ResumeOffset returns where to resume a copy when the destination already has
+// existing bytes of a size-byte file, or 0 when it has to start over
+func ResumeOffset(existing, size int64) int64 {
+	if existing > 0 && existing < size {
+		return existing
+	}
+	return 0
+}
+
+// Run copies the file, calling progress with the bytes at the destination so
+// far, then removes the source of a move. Cancelling ctx stops the copy and
+// leaves the partial file for a later resume.
+func (j Job) Run(ctx context.Context, progress func(done, total int64)) error {
+	var err error
+	if j.Direction == Upload {
+		err = j.upload(ctx, progress)
+	} else {
+		err = j.download(ctx, progress)
+	}
+	if err != nil {
+		return err
+	}
+	if !j.Move {
+		return nil
+	}
+
+	if j.Direction == Upload {
+		if err := os.Remove(j.Source); err != nil {
+			return fmt.Errorf("copied but failed to remove %s: %w", j.Source, err)
+		}
+		return nil
+	}
+	if _, err := runRemote(ctx, j.Server, "rm -f -- "+shellquote.Quote(j.Source), nil); err != nil {
+		return fmt.Errorf("copied but failed to remove %s: %w", j.Source, err)
+	}
+	return nil
+}
+
+func (j Job) upload(ctx context.Context, progress func(done, total int64)) error {
+	file, err := os.Open(j.Source)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", j.Source, err)
+	}
+	defer file.Close()
+	if _, err := file.Seek(j.Offset, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to resume %s: %w", j.Source, err)
+	}
+
+	reader := &countingReader{ctx: ctx, reader: file, done: j.Offset, total: j.Size, progress: progress, throttles: j.throttles}
+	_, err = runRemote(ctx, j.Server, uploadCommand(j.Destination, j.Offset), reader)
+	return err
+}
+
+func (j Job) download(ctx context.Context, progress func(done, total int64)) error {
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if j.Offset > 0 {
+		flags = os.O_WRONLY | os.O_APPEND
+	}
+	file, err := os.OpenFile(j.Destination, flags, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", j.Destination, err)
+	}
+	defer file.Close()
+
+	cmd, err := sshCommand(j.Server, downloadCommand(j.Source, j.Offset))
+	if err != nil {
+		return err
+	}
+	writer := &countingWriter{ctx: ctx, writer: file, done: j.Offset, total: j.Size, progress: progress, throttles: j.throttles}
+	return runCmd(ctx, j.Server, cmd, nil, writer)
+}
+
+// uploadCommand writes standard input to the remote path, appending when resuming
+func uploadCommand(remotePath string, offset int64) string {
+	if offset > 0 {
+		return "cat >> " + shellquote.Quote(remotePath)
+	}
+	return "cat > " + shellquote.Quote(remotePath)
+}
+
+// downloadCommand prints the remote file from offset onwards
+func downloadCommand(remotePath string, offset int64) string {
+	return fmt.Sprintf("tail -c +%d -- %s", offset+1, shellquote.Quote(remotePath))
+}
+
+// runRemote runs a command on the server and returns its standard output
+func runRemote(ctx context.Context, server config.Server, command string, stdin io.Reader) (string, error) {
+	cmd, err := sshCommand(server, command)
+	if err != nil {
+		return "", err
+	}
+	var output bytes.Buffer
+	if err := runCmd(ctx, server, cmd, stdin, &output); err != nil {
+		return "", err
+	}
+	return output.String(), nil
+}
+
+// runCmd runs an ssh command, killing it when ctx is cancelled
+func runCmd(ctx context.Context, server config.Server, cmd *exec.Cmd, stdin io.Reader, stdout io.Writer) error {
+	var stderr bytes.Buffer
+	cmd.Stdin = stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start ssh for %s: %w", server.Name, err)
+	}
+
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-ctx.Done():
+			cmd.Process.Kill()
+		case <-stop:
+		}
+	}()
+
+	err := cmd.Wait()
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return fmt.Errorf("%s: %s", server.Name, message)
+		}
+		return fmt.Errorf("command failed on %s: %w", server.Name, err)
+	}
+	return nil
+}
+
+// countingReader reports the bytes read so far, slowed down by the throttles
+type countingReader struct {
+	ctx         context.Context
+	reader      io.Reader
+	done, total int64
+	progress    func(done, total int64)
+	throttles   []*Throttle
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	if n > 0 {
+		if waitErr := throttle(r.ctx, r.throttles, n); waitErr != nil {
+			return 0, waitErr
+		}
+		r.done += int64(n)
+		if r.progress != nil {
+			r.progress(r.done, r.total)
+		}
+	}
+	return n, err
+}
+
+// countingWriter reports the bytes written so far, slowed down by the throttles
+type countingWriter struct {
+	ctx         context.Context
+	writer      io.Writer
+	done, total int64
+	progress    func(done, total int64)
+	throttles   []*Throttle
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.writer.Write(p)
+	if n > 0 {
+		if waitErr := throttle(w.ctx, w.throttles, n); waitErr != nil {
+			return n, waitErr
+		}
+		w.done += int64(n)
+		if w.progress != nil {
+			w.progress(w.done, w.total)
+		}
+	}
+	return n, err
+}
+
+// throttle waits until n bytes fit within every throttle
+func throttle(ctx context.Context, throttles []*Throttle, n int) error {
+	for _, t := range throttles {
+		if err := t.Wait(ctx, n); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package transfer
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"sshm/internal/config"
+)
+
+// runLocally runs the "remote" commands in a local shell
+func runLocally(t *testing.T) {
+	t.Helper()
+	original := sshCommand
+	sshCommand = func(server config.Server, command string) (*exec.Cmd, error) {
+		return exec.Command("sh", "-c", command), nil
+	}
+	t.Cleanup(func() { sshCommand = original })
+}
+
+func TestListRemote(t *testing.T) {
+	runLocally(t)
+	dir := t.TempDir()
+	os.Mkdir(filepath.Join(dir, "logs"), 0755)
+	os.WriteFile(filepath.Join(dir, "b.txt"), []byte("hello"), 0644)
+	os.WriteFile(filepath.Join(dir, ".hidden"), []byte("x"), 0644)
+	os.WriteFile(filepath.Join(dir, "with space"), nil, 0644)
+
+	resolved, entries, err := ListRemote(context.Background(), config.Server{Name: "web1"}, dir)
+	if err != nil {
+		t.Fatalf("ListRemote failed: %v", err)
+	}
+	if resolved == "" || !strings.HasSuffix(resolved, filepath.Base(dir)) {
+		t.Errorf("Expected the resolved directory, got %q", resolved)
+	}
+
+	expected := []Entry{
+		{Name: "logs", Dir: true},
+		{Name: ".hidden", Size: 1},
+		{Name: "b.txt", Size: 5},
+		{Name: "with space"},
+	}
+	if len(entries) != len(expected) {
+		t.Fatalf("Expected %d entries, got %+v", len(expected), entries)
+	}
+	for i, entry := range entries {
+		if entry != expected[i] {
+			t.Errorf("Entry %d: expected %+v, got %+v", i, expected[i], entry)
+		}
+	}
+}
+
+func TestListLocal(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "a.txt"), []byte("abc"), 0644)
+	os.Mkdir(filepath.Join(dir, "z"), 0755)
+
+	entries, err := ListLocal(dir)
+	if err != nil {
+		t.Fatalf("ListLocal failed: %v", err)
+	}
+	if len(entries) != 2 || !entries[0].Dir || entries[1].Name != "a.txt" || entries[1].Size != 3 {
+		t.Errorf("Unexpected entries: %+v", entries)
+	}
+}
+
+func TestResumeOffset(t *testing.T) {
+	tests := []struct {
+		existing, size, expected int64
+	}{
+		{0, 100, 0},
+		{40, 100, 40},
+		{100, 100, 0},
+		{150, 100, 0},
+	}
+	for _, test := range tests {
+		if offset := ResumeOffset(test.existing, test.size); offset != test.expected {
+			t.Errorf("ResumeOffset(%d, %d) = %d, expected %d", test.existing, test.size, offset, test.expected)
+		}
+	}
+}
+
+func TestDownloadResumes(t *testing.T) {
+	runLocally(t)
+	dir := t.TempDir()
+	source := filepath.Join(dir, "remote.bin")
+	destination := filepath.Join(dir, "local.bin")
+	os.WriteFile(source, []byte("0123456789"), 0644)
+	os.WriteFile(destination, []byte("0123"), 0644)
+
+	var last int64
+	job := Job{Direction: Download, Source: source, Destination: destination, Size: 10, Offset: 4}
+	if err := job.Run(context.Background(), func(done, total int64) { last = done }); err != nil {
+		t.Fatalf("Download failed: %v", err)
+	}
+
+	data, _ := os.ReadFile(destination)
+	if string(data) != "0123456789" {
+		t.Errorf("Expected the download to resume, got %q", data)
+	}
+	if last != 10 {
+		t.Errorf("Expected progress to reach 10 bytes, got %d", last)
+	}
+}
+
+func TestUploadMoveRemovesSource(t *testing.T) {
+	runLocally(t)
+	dir := t.TempDir()
+	source := filepath.Join(dir, "local's.txt")
+	destination := filepath.Join(dir, "remote.txt")
+	os.WriteFile(source, []byte("payload"), 0644)
+	os.WriteFile(destination, []byte("stale content"), 0644)
+
+	job := Job{Direction: Upload, Source: source, Destination: destination, Size: 7, Move: true}
+	if err := job.Run(context.Background(), nil); err != nil {
+		t.Fatalf("Upload failed: %v", err)
+	}
+
+	data, _ := os.ReadFile(destination)
+	if string(data) != "payload" {
+		t.Errorf("Expected the destination to be overwritten, got %q", data)
+	}
+	if _, err := os.Stat(source); !os.IsNotExist(err) {
+		t.Errorf("Expected the moved source to be removed, got %v", err)
+	}
+}
+
+func TestRunReportsRemoteErrors(t *testing.T) {
+	runLocally(t)
+	dir := t.TempDir()
+
+	job := Job{Server: config.Server{Name: "web1"}, Direction: Download, Source: filepath.Join(dir, "missing"), Destination: filepath.Join(dir, "out")}
+	err := job.Run(context.Background(), nil)
+	if err == nil || !strings.Contains(err.Error(), "web1") {
+		t.Errorf("Expected an error naming the server, got %v", err)
+	}
+}
@@ -0,0 +1,235 @@
+package tui
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"sshm/internal/config"
+	"sshm/internal/power"
+	"sshm/internal/tmux"
+)
+
+// Runs custom action commands until done or cancelled from the tasks overlay
+// (variables to allow mocking in tests)
+var (
+	runLocalAction = func(ctx context.Context, command string) (string, error) {
+		var output bytes.Buffer
+		cmd := exec.CommandContext(ctx, "sh", "-c", command)
+		// Children of the shell may hold the output open after it is killed
+		cmd.WaitDelay = time.Second
+		cmd.Stdout = &output
+		cmd.Stderr = &output
+		err := cmd.Run()
+		return strings.TrimSpace(output.String()), err
+	}
+	runRemoteAction = power.RunContext
+)
+
+// actionTarget is the item a custom action runs against
+type actionTarget struct {
+	Scope   string
+	Label   string          // e.g. "web1" or "profile production"
+	Servers []config.Server // The server, the profile's servers or the session's server
+	Profile string
+	Session string
+}
+
+// actionRun is one command of a custom action: run locally, or on Server
+type actionRun struct {
+	Server  *config.Server // Set for remote commands
+	Label   string
+	Command string
+}
+
+// plan expands the action's command for the target: once locally, or once per
+// server for remote actions
+func (target actionTarget) plan(action config.CustomAction) ([]actionRun, error) {
+	if err := action.Validate(); err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(target.Servers))
+	for _, server := range target.Servers {
+		names = append(names, server.Name)
+	}
+	ctx := config.ActionContext{Profile: target.Profile, Servers: names, Session: target.Session}
+
+	if !action.IsRemote() {
+		if target.Scope != config.ActionScopeProfile && len(target.Servers) == 1 {
+			ctx.Server = &target.Servers[0]
+		}
+		command, err := action.ExpandCommand(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return []actionRun{{Label: "local", Command: command}}, nil
+	}
+
+	if len(target.Servers) == 0 {
+		return nil, fmt.Errorf("%s is not a configured server, so '%s' has nowhere to run", target.Label, action.Name)
+	}
+	runs := make([]actionRun, 0, len(target.Servers))
+	for i := range target.Servers {
+		server := target.Servers[i]
+		if server.IsRestricted() {
+			return nil, fmt.Errorf("%s is restricted to its forced command; '%s' can't run there", server.Name, action.Name)
+		}
+		ctx.Server = &server
+		command, err := action.ExpandCommand(ctx)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, actionRun{Server: &server, Label: server.Name, Command: command})
+	}
+	return runs, nil
+}
+
+// selectedActionTargets returns what the actions menu applies to: the selected
+// server and the active profile, or the selected session
+func (t *TUIApp) selectedActionTargets() []actionTarget {
+	var targets []actionTarget
+	switch t.focusedPanel {
+	case "servers":
+		if serverName := t.getSelectedServerName(); serverName != "" {
+			if server, err := t.config.GetServer(serverName); err == nil {
+				targets = append(targets, actionTarget{Scope: config.ActionScopeServer, Label: server.Name, Servers: []config.Server{*server}})
+			}
+		}
+		if t.currentFilter != "" && t.currentFilter != "all" && !t.isUnassignedFilter() {
+			if servers, err := t.config.GetServersByProfile(t.currentFilter); err == nil {
+				targets = append(targets, actionTarget{Scope: config.ActionScopeProfile, Label: "profile " + t.currentFilter, Servers: servers, Profile: t.currentFilter})
+			}
+		}
+	case "sessions":
+		if t.sessionPanel == nil {
+			break
+		}
+		row, _ := t.sessionPanel.GetSelection()
+		if row <= 0 || row > len(t.sessions) {
+			break
+		}
+		sessionName := t.sessions[row-1].Name
+		target := actionTarget{Scope: config.ActionScopeSession, Label: "session " + sessionName, Session: sessionName}
+		var names []string
+		for _, server := range t.config.GetServers() {
+			names = append(names, server.Name)
+		}
+		if serverName := tmux.ServerForSession(sessionName, names); serverName != "" {
+			if server, err := t.config.GetServer(serverName); err == nil {
+				target.Servers = []config.Server{*server}
+			}
+		}
+		targets = append(targets, target)
+	}
+	return targets
+}
+
+// customActionMenuItems returns the custom actions defined for the selected item
+func (t *TUIApp) customActionMenuItems() []menuItem {
+	var items []menuItem
+	for _, target := range t.selectedActionTargets() {
+		for _, action := range t.config.ActionsFor(target.Scope) {
+			target, action := target, action
+			where := "local"
+			if action.IsRemote() {
+				where = "remote"
+			}
+			items = append(items, menuItem{
+				Label:       action.Name,
+				Description: fmt.Sprintf("%s · %s: %s", target.Label, where, action.Command),
+				Run:         func() { t.startCustomAction(target, action) },
+			})
+		}
+	}
+	return items
+}
+
+// showActionsMenu lists the custom actions defined for the selected item
+func (t *TUIApp) showActionsMenu() {
+	items := t.customActionMenuItems()
+	if len(items) == 0 {
+		t.showTransientStatus("[yellow]No custom actions for this item; define them under 'actions:' in the configuration[white]")
+		return
+	}
+	t.showMenu("⚙ Actions", items)
+}
+
+// startCustomAction runs the action, asking first when it is configured to or
+// when it runs remotely on a protected server
+func (t *TUIApp) startCustomAction(target actionTarget, action config.CustomAction) {
+	runs, err := target.plan(action)
+	if err != nil {
+		t.showErrorModal(err.Error())
+		return
+	}
+	remoteOnProtected := action.IsRemote() && power.RequiresTypedConfirmation(target.Servers)
+	if !action.Confirm && !remoteOnProtected {
+		t.runCustomAction(action, runs)
+		return
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Run '%s' on %s?\n\n", action.Name, target.Label)
+	for _, run := range runs {
+		fmt.Fprintf(&b, "%s: %s\n", run.Label, run.Command)
+	}
+	if remoteOnProtected {
+		b.WriteString("\nThis includes protected servers.")
+	}
+	modal := tview.NewModal().
+		SetText(b.String()).
+		AddButtons([]string{"Run", "Cancel"}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			t.modalManager.HideModal()
+			if buttonIndex == 0 {
+				t.runCustomAction(action, runs)
+			}
+		})
+	if remoteOnProtected {
+		modal.SetBackgroundColor(tcell.ColorDarkRed)
+	}
+	t.modalManager.ShowModal(modal)
+}
+
+// runCustomAction runs the commands in the background and shows their output
+func (t *TUIApp) runCustomAction(action config.CustomAction, runs []actionRun) {
+	t.showTransientStatus(fmt.Sprintf("[yellow]Running %s...[white]", action.Name))
+	op, ctx := t.tasks.start(taskSpec{Name: action.Name, Total: len(runs), Cancellable: true})
+
+	go func() {
+		defer op.Finish()
+		var b strings.Builder
+		for i, run := range runs {
+			if ctx.Err() != nil {
+				fmt.Fprintf(&b, "[gray]- %s[white]: skipped, cancelled\n", run.Label)
+				continue
+			}
+			op.Update(i, len(runs), run.Label)
+			var output string
+			var err error
+			if run.Server != nil {
+				output, err = runRemoteAction(ctx, *run.Server, run.Command)
+			} else {
+				output, err = runLocalAction(ctx, run.Command)
+			}
+			if err != nil {
+				fmt.Fprintf(&b, "[red]✗ %s[white]: %s\n", run.Label, tview.Escape(err.Error()))
+			} else {
+				fmt.Fprintf(&b, "[green]✓ %s[white]\n", run.Label)
+			}
+			if output != "" {
+				fmt.Fprintf(&b, "[gray]%s[white]\n", tview.Escape(output))
+			}
+		}
+		b.WriteString("\n[gray]Press Enter, Escape or q to close[white]")
+
+		t.app.QueueUpdateDraw(func() {
+			t.showTextPanel(fmt.Sprintf("Action › %s", action.Name), b.String())
+		})
+	}()
+}
@@ -0,0 +1,59 @@
+package tui
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"sshm/internal/config"
+)
+
+func TestActionTargetPlan(t *testing.T) {
+	servers := []config.Server{
+		{Name: "web1", Hostname: "10.0.0.1", Username: "deploy", AuthType: "password"},
+		{Name: "web2", Hostname: "10.0.0.2", Username: "deploy", AuthType: "password"},
+	}
+	profile := actionTarget{Scope: config.ActionScopeProfile, Label: "profile prod", Servers: servers, Profile: "prod"}
+
+	runs, err := profile.plan(config.CustomAction{Name: "Restart", Scope: "profile", Run: "remote", Command: "sudo systemctl restart app # {name}"})
+	if err != nil {
+		t.Fatalf("plan() error = %v", err)
+	}
+	if len(runs) != 2 || runs[0].Server.Name != "web1" || runs[1].Command != "sudo systemctl restart app # web2" {
+		t.Errorf("Expected one remote run per server, got %+v", runs)
+	}
+
+	runs, err = profile.plan(config.CustomAction{Name: "Notify", Scope: "profile", Command: "notify {profile} {servers}"})
+	if err != nil {
+		t.Fatalf("plan() error = %v", err)
+	}
+	if len(runs) != 1 || runs[0].Server != nil || runs[0].Command != "notify prod web1 web2" {
+		t.Errorf("Expected a single local run, got %+v", runs)
+	}
+
+	session := actionTarget{Scope: config.ActionScopeSession, Label: "session adhoc", Session: "adhoc"}
+	if _, err := session.plan(config.CustomAction{Name: "Uptime", Scope: "session", Run: "remote", Command: "uptime"}); err == nil {
+		t.Error("Expected a remote action on a session without a server to fail")
+	}
+
+	restricted := servers[0]
+	restricted.Restricted = &config.RestrictedAccess{Command: "backup"}
+	target := actionTarget{Scope: config.ActionScopeServer, Label: "web1", Servers: []config.Server{restricted}}
+	if _, err := target.plan(config.CustomAction{Name: "Uptime", Run: "remote", Command: "uptime"}); err == nil || !strings.Contains(err.Error(), "restricted") {
+		t.Errorf("Expected restricted servers to be refused, got %v", err)
+	}
+}
+
+func TestRunLocalActionStopsWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	start := time.Now()
+	if _, err := runLocalAction(ctx, "sleep 10"); err == nil {
+		t.Error("Expected the cancelled command to fail")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Expected cancelling to stop the command, it ran for %v", elapsed)
+	}
+}
@@ -0,0 +1,143 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"sshm/internal/audit"
+	"sshm/internal/config"
+)
+
+// auditUpdates checks pending package updates on the servers of the active
+// profile, or on all servers when no profile is selected
+func (t *TUIApp) auditUpdates() {
+	servers, scope, ok := t.auditScope()
+	if !ok {
+		return
+	}
+
+	t.showTransientStatus(fmt.Sprintf("[yellow]Checking pending updates on %d server(s)...[white]", len(servers)))
+
+	op, _ := t.tasks.start(taskSpec{Name: "update audit"})
+	go func(servers []config.Server) {
+		defer op.Finish()
+		auditedAt := time.Now()
+		results := audit.Updates(servers)
+		t.app.QueueUpdateDraw(func() {
+			t.showAuditResults(scope, results, auditedAt)
+		})
+	}(servers)
+}
+
+// auditScope returns the servers of the active profile, or all servers when
+// no profile is selected, showing an error when there are none
+func (t *TUIApp) auditScope() ([]config.Server, string, bool) {
+	servers := t.config.GetServers()
+	scope := "all servers"
+	if t.isUnassignedFilter() {
+		servers = t.config.UnassignedServers()
+		scope = "unassigned servers"
+	} else if t.currentFilter != "" && t.currentFilter != "all" {
+		profileServers, err := t.config.GetServersByProfile(t.currentFilter)
+		if err != nil {
+			t.showErrorModal(fmt.Sprintf("Profile '%s' not found: %s", t.currentFilter, err.Error()))
+			return nil, "", false
+		}
+		servers = profileServers
+		scope = fmt.Sprintf("profile '%s'", t.currentFilter)
+	}
+	if len(servers) == 0 {
+		t.showErrorModal("No servers to audit")
+		return nil, "", false
+	}
+	return servers, scope, true
+}
+
+// showAuditResults renders the update audit as a table; 'e' exports it to CSV
+func (t *TUIApp) showAuditResults(scope string, results []audit.Result, auditedAt time.Time) {
+	table := tview.NewTable().
+		SetSelectable(true, false).
+		SetFixed(1, 0)
+	table.SetBorder(true).
+		SetTitle(fmt.Sprintf(" Pending Updates › %s ", scope)).
+		SetBorderColor(tcell.ColorYellow)
+
+	for col, header := range []string{"Server", "Manager", "Pending", "Security", "Error"} {
+		table.SetCell(0, col, tview.NewTableCell(header).
+			SetTextColor(tcell.ColorYellow).
+			SetAttributes(tcell.AttrBold).
+			SetSelectable(false).
+			SetExpansion(1))
+	}
+
+	withSecurity := 0
+	for i, result := range results {
+		rowColor := tcell.ColorWhite
+		switch {
+		case result.Error != "":
+			rowColor = tcell.ColorGray
+		case result.HasSecurityUpdates():
+			rowColor = tcell.ColorRed
+			withSecurity++
+		case result.Total > 0:
+			rowColor = tcell.ColorYellow
+		}
+		table.SetCell(i+1, 0, tview.NewTableCell(result.Server).SetTextColor(rowColor))
+		table.SetCell(i+1, 1, tview.NewTableCell(result.Manager).SetTextColor(rowColor))
+		table.SetCell(i+1, 2, tview.NewTableCell(fmt.Sprintf("%d", result.Total)).SetTextColor(rowColor))
+		table.SetCell(i+1, 3, tview.NewTableCell(fmt.Sprintf("%d", result.Security)).SetTextColor(rowColor))
+		table.SetCell(i+1, 4, tview.NewTableCell(result.Error).SetTextColor(rowColor).SetExpansion(2))
+	}
+
+	footer := tview.NewTextView().
+		SetDynamicColors(true).
+		SetText(fmt.Sprintf("[red]%d of %d server(s) with security updates[white]  •  [gray]e: export CSV  •  Escape/q: close[white]",
+			withSecurity, len(results)))
+
+	panel := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(table, 0, 1, true).
+		AddItem(footer, 1, 0, false)
+
+	panel.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape || event.Rune() == 'q' || event.Rune() == 'Q' {
+			t.hideTextPanel()
+			return nil
+		}
+		if event.Rune() == 'e' || event.Rune() == 'E' {
+			path, err := exportAuditCSV(results, auditedAt)
+			if err != nil {
+				footer.SetText(fmt.Sprintf("[red]Export failed: %s[white]", tview.Escape(err.Error())))
+			} else {
+				footer.SetText(fmt.Sprintf("[green]Exported to %s[white]  •  [gray]Escape/q: close[white]", path))
+			}
+			return nil
+		}
+		return event
+	})
+
+	t.modalManager.ShowModal(panel)
+}
+
+// exportAuditCSV writes audit results to a timestamped CSV file in the home directory
+func exportAuditCSV(results []audit.Result, auditedAt time.Time) (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+
+	path := filepath.Join(homeDir, fmt.Sprintf("sshm-updates-%s.csv", auditedAt.Format("20060102-150405")))
+	file, err := os.Create(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	defer file.Close()
+
+	if err := audit.WriteCSV(file, results, auditedAt); err != nil {
+		return "", err
+	}
+	return path, nil
+}
@@ -0,0 +1,149 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"sshm/internal/config"
+	"sshm/internal/connection"
+)
+
+// requestBannerAcknowledgment fetches the server's login banner and only calls
+// proceed once the user has accepted it. Servers without a banner connect directly.
+func (t *TUIApp) requestBannerAcknowledgment(server config.Server, proceed func()) {
+	modal := tview.NewModal().
+		SetText(fmt.Sprintf("📜 Fetching login banner for %s...\n\nPlease wait...", server.Name)).
+		SetBackgroundColor(tcell.ColorDarkBlue)
+	t.setRoot(modal)
+
+	go func() {
+		banner, err := t.connectionManager.FetchBanner(server)
+		t.app.QueueUpdateDraw(func() {
+			if err != nil {
+				t.showErrorModal(fmt.Sprintf("Failed to fetch login banner for '%s': %s", server.Name, err.Error()))
+				return
+			}
+			if strings.TrimSpace(banner) == "" {
+				proceed()
+				return
+			}
+			t.showBannerAcknowledgment(server, banner, proceed)
+		})
+	}()
+}
+
+// requestBannerAcknowledgments has the login banner of each server of a group
+// connect that requires it accepted in turn, then calls proceed with them.
+// Declining any of them cancels the connect.
+func (t *TUIApp) requestBannerAcknowledgments(servers []config.Server, proceed func(connection.BannerAcks)) {
+	flagged := 0
+	for _, server := range servers {
+		if server.RequireBannerAck {
+			flagged++
+		}
+	}
+	if flagged == 0 {
+		proceed(nil)
+		return
+	}
+
+	modal := tview.NewModal().
+		SetText(fmt.Sprintf("📜 Fetching login banners of %d server(s)...\n\nPlease wait...", flagged)).
+		SetBackgroundColor(tcell.ColorDarkBlue)
+	t.setRoot(modal)
+
+	go func() {
+		pending, acks, err := t.connectionManager.PendingBanners(servers)
+		t.app.QueueUpdateDraw(func() {
+			if err != nil {
+				t.showErrorModal(err.Error())
+				return
+			}
+			t.acknowledgeBanners(pending, acks, proceed)
+		})
+	}()
+}
+
+// acknowledgeBanners shows the pending banners one after the other, adding
+// each accepted one to acks
+func (t *TUIApp) acknowledgeBanners(pending []connection.PendingBanner, acks connection.BannerAcks, proceed func(connection.BannerAcks)) {
+	if len(pending) == 0 {
+		proceed(acks)
+		return
+	}
+	next := pending[0]
+	t.showBannerAcknowledgment(next.Server, next.Banner, func() {
+		acks[next.Server.Name] = true
+		t.acknowledgeBanners(pending[1:], acks, proceed)
+	})
+}
+
+// showBannerAcknowledgment displays the banner with Accept/Decline buttons
+func (t *TUIApp) showBannerAcknowledgment(server config.Server, banner string, proceed func()) {
+	text := tview.NewTextView().
+		SetText(banner).
+		SetScrollable(true).
+		SetWordWrap(true)
+	text.SetBorder(true).
+		SetTitle(fmt.Sprintf(" Login banner: %s ", server.Name)).
+		SetTitleAlign(tview.AlignLeft)
+
+	closeDialog := func() {
+		if t.modalManager != nil && t.modalManager.IsModalActive() {
+			t.modalManager.HideModal()
+		} else {
+			t.setRoot(t.layout)
+			t.app.SetFocus(t.serverList)
+		}
+	}
+
+	buttons := tview.NewForm().
+		AddButton("Accept", func() {
+			closeDialog()
+			if err := t.connectionManager.AcknowledgeBanner(server, banner); err != nil {
+				t.showErrorModal(fmt.Sprintf("Failed to record banner acknowledgment: %s", err.Error()))
+				return
+			}
+			proceed()
+		}).
+		AddButton("Decline", closeDialog).
+		SetButtonsAlign(tview.AlignCenter)
+	buttons.SetCancelFunc(closeDialog)
+
+	dialog := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(text, 0, 1, false).
+		AddItem(buttons, 3, 0, true)
+
+	// Let the arrow keys scroll the banner while the buttons keep focus
+	dialog.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyUp, tcell.KeyDown, tcell.KeyPgUp, tcell.KeyPgDn:
+			row, col := text.GetScrollOffset()
+			switch event.Key() {
+			case tcell.KeyUp:
+				row--
+			case tcell.KeyDown:
+				row++
+			case tcell.KeyPgUp:
+				row -= 10
+			case tcell.KeyPgDn:
+				row += 10
+			}
+			if row < 0 {
+				row = 0
+			}
+			text.ScrollTo(row, col)
+			return nil
+		}
+		return event
+	})
+
+	if t.modalManager != nil {
+		t.modalManager.ShowModal(dialog)
+	} else {
+		t.setRoot(dialog)
+		t.app.SetFocus(buttons)
+	}
+}
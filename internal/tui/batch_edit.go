@@ -0,0 +1,135 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"sshm/internal/config"
+)
+
+// batchEditActions are the choices of the batch edit form and the fields they change
+var batchEditActions = []struct {
+	Label string
+	Field string
+}{
+	{"Add tag", config.BatchAddTag},
+	{"Remove tag", config.BatchRemoveTag},
+	{"Set owner", "owner"},
+	{"Set team", "team"},
+	{"Set cost center", "cost_center"},
+	{"Set environment", "environment"},
+}
+
+// showBatchEditForm applies a tag or metadata value to every server matching the
+// active search and profile filters, previewing which servers will change
+func (t *TUIApp) showBatchEditForm() {
+	if t.focusedPanel != "servers" {
+		return
+	}
+
+	servers := t.visibleServers()
+	if len(servers) == 0 {
+		t.showErrorModal("No servers match the current search and profile")
+		return
+	}
+
+	labels := make([]string, len(batchEditActions))
+	for i, action := range batchEditActions {
+		labels[i] = action.Label
+	}
+
+	preview := tview.NewTextView().SetDynamicColors(true).SetScrollable(true)
+	preview.SetBorder(true).SetTitle(" Preview ")
+
+	form := tview.NewForm().
+		AddDropDown("Action", labels, 0, nil).
+		AddInputField("Value", "", 40, nil, nil).
+		AddButton("Apply", nil).
+		AddButton("Cancel", nil)
+	form.SetBorder(true).
+		SetTitle(fmt.Sprintf(" # Batch Edit (%d servers) ", len(servers))).
+		SetTitleAlign(tview.AlignCenter)
+
+	actionDropdown := form.GetFormItem(0).(*tview.DropDown)
+	valueField := form.GetFormItem(1).(*tview.InputField)
+
+	currentEdit := func() config.BatchEdit {
+		index, _ := actionDropdown.GetCurrentOption()
+		if index < 0 {
+			index = 0
+		}
+		return config.BatchEdit{Field: batchEditActions[index].Field, Value: valueField.GetText()}
+	}
+	updatePreview := func() {
+		preview.SetText(renderBatchEditPreview(servers, currentEdit()))
+	}
+	actionDropdown.SetSelectedFunc(func(text string, index int) { updatePreview() })
+	valueField.SetChangedFunc(func(text string) { updatePreview() })
+	updatePreview()
+
+	form.GetButton(0).SetSelectedFunc(func() {
+		names := make([]string, len(servers))
+		for i, server := range servers {
+			names[i] = server.Name
+		}
+
+		changed, err := t.config.ApplyBatchEdit(names, currentEdit())
+		if err != nil {
+			t.showErrorModal(fmt.Sprintf("Batch edit failed: %s", err.Error()))
+			return
+		}
+		if len(changed) > 0 {
+			if err := t.saveConfig(); err != nil {
+				t.showErrorModal(fmt.Sprintf("Failed to save configuration: %s", err.Error()))
+				return
+			}
+		}
+
+		t.modalManager.HideModal()
+		t.refreshServerList()
+		t.showTransientStatus(fmt.Sprintf("[green]✓ Updated %d of %d server(s)[white]", len(changed), len(servers)))
+	})
+	form.GetButton(1).SetSelectedFunc(func() {
+		t.modalManager.HideModal()
+	})
+	form.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape {
+			t.modalManager.HideModal()
+			return nil
+		}
+		return event
+	})
+
+	layout := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(form, 9, 0, true).
+		AddItem(preview, 0, 1, false)
+	t.modalManager.ShowModal(layout)
+}
+
+// renderBatchEditPreview lists which servers a batch edit changes and which it leaves alone
+func renderBatchEditPreview(servers []config.Server, edit config.BatchEdit) string {
+	if err := edit.Validate(); err != nil {
+		return fmt.Sprintf("[gray]%s[white]", tview.Escape(err.Error()))
+	}
+
+	var changed, unchanged []string
+	for _, server := range servers {
+		if edit.Apply(&server) {
+			changed = append(changed, server.Name)
+		} else {
+			unchanged = append(unchanged, server.Name)
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "[yellow]%d server(s) will change:[white]\n", len(changed))
+	for _, name := range changed {
+		fmt.Fprintf(&b, "  [green]~[white] %s\n", tview.Escape(name))
+	}
+	if len(unchanged) > 0 {
+		fmt.Fprintf(&b, "\n[gray]%d already up to date: %s[white]\n", len(unchanged), tview.Escape(strings.Join(unchanged, ", ")))
+	}
+	return b.String()
+}
@@ -0,0 +1,30 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"sshm/internal/config"
+)
+
+func TestRenderBatchEditPreview(t *testing.T) {
+	servers := []config.Server{
+		{Name: "web1", Tags: []string{"web"}},
+		{Name: "web2"},
+	}
+
+	preview := renderBatchEditPreview(servers, config.BatchEdit{Field: config.BatchAddTag, Value: "web"})
+	if !strings.Contains(preview, "1 server(s) will change") || !strings.Contains(preview, "web2") {
+		t.Errorf("Expected web2 listed as changing, got %q", preview)
+	}
+	if !strings.Contains(preview, "1 already up to date: web1") {
+		t.Errorf("Expected web1 listed as unchanged, got %q", preview)
+	}
+	if servers[1].HasTag("web") {
+		t.Error("Preview must not modify the servers")
+	}
+
+	if preview := renderBatchEditPreview(servers, config.BatchEdit{Field: config.BatchAddTag}); !strings.Contains(preview, "tag must not be empty") {
+		t.Errorf("Expected validation message, got %q", preview)
+	}
+}
@@ -0,0 +1,140 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"sshm/internal/config"
+)
+
+// showBatchRenameForm renames every server matching the active search and
+// profile filters with a find/replace or a template, previewing the new names
+// and their collisions
+func (t *TUIApp) showBatchRenameForm() {
+	if t.focusedPanel != "servers" {
+		return
+	}
+
+	servers := t.visibleServers()
+	if len(servers) == 0 {
+		t.showErrorModal("No servers match the current search and profile")
+		return
+	}
+	names := make([]string, len(servers))
+	for i, server := range servers {
+		names[i] = server.Name
+	}
+
+	preview := tview.NewTextView().SetDynamicColors(true).SetScrollable(true)
+	preview.SetBorder(true).SetTitle(" Preview ")
+
+	form := tview.NewForm().
+		AddInputField("Find", "", 30, nil, nil).
+		AddInputField("Replace", "", 30, nil, nil).
+		AddCheckbox("Regexp", false, nil).
+		AddInputField("Template", "", 30, nil, nil).
+		AddButton("Rename", nil).
+		AddButton("Cancel", nil)
+	form.SetBorder(true).
+		SetTitle(fmt.Sprintf(" $ Batch Rename (%d servers) ", len(servers))).
+		SetTitleAlign(tview.AlignCenter)
+
+	findField := form.GetFormItem(0).(*tview.InputField)
+	replaceField := form.GetFormItem(1).(*tview.InputField)
+	regexpBox := form.GetFormItem(2).(*tview.Checkbox)
+	templateField := form.GetFormItem(3).(*tview.InputField)
+	templateField.SetPlaceholder("e.g. aws-{name}")
+
+	currentRename := func() config.BatchRename {
+		return config.BatchRename{
+			Find:     findField.GetText(),
+			Replace:  replaceField.GetText(),
+			Regexp:   regexpBox.IsChecked(),
+			Template: templateField.GetText(),
+		}
+	}
+	updatePreview := func() {
+		plan, err := t.config.PlanBatchRename(names, currentRename())
+		preview.SetText(renderBatchRenamePreview(plan, err))
+	}
+	for _, field := range []*tview.InputField{findField, replaceField, templateField} {
+		field.SetChangedFunc(func(text string) { updatePreview() })
+	}
+	regexpBox.SetChangedFunc(func(checked bool) { updatePreview() })
+	updatePreview()
+
+	form.GetButton(0).SetSelectedFunc(func() {
+		var before []string
+		for _, server := range t.config.GetServers() {
+			before = append(before, server.Name)
+		}
+		plan, err := t.config.ApplyBatchRename(names, currentRename())
+		if err != nil {
+			t.showErrorModal(fmt.Sprintf("Batch rename failed: %s", err.Error()))
+			return
+		}
+		if len(plan.Renames) > 0 {
+			if err := t.saveConfig(); err != nil {
+				t.showErrorModal(fmt.Sprintf("Failed to save configuration: %s", err.Error()))
+				return
+			}
+		}
+
+		t.modalManager.HideModal()
+		status := fmt.Sprintf("[green]✓ Renamed %d of %d server(s)[white]", len(plan.Renames), len(servers))
+		if len(plan.Renames) > 0 && t.tmuxManager != nil {
+			renames := make(map[string]string, len(plan.Renames))
+			for _, rename := range plan.Renames {
+				renames[rename.From] = rename.To
+			}
+			if _, err := t.tmuxManager.RenameServerSessions(renames, before); err != nil {
+				status = fmt.Sprintf("[yellow]Renamed %d server(s); sessions not renamed: %s[white]", len(plan.Renames), tview.Escape(err.Error()))
+			}
+		}
+		t.refreshServerList()
+		t.refreshSessions()
+		t.showTransientStatus(status)
+	})
+	form.GetButton(1).SetSelectedFunc(func() {
+		t.modalManager.HideModal()
+	})
+	form.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape {
+			t.modalManager.HideModal()
+			return nil
+		}
+		return event
+	})
+
+	layout := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(form, 13, 0, true).
+		AddItem(preview, 0, 1, false)
+	t.modalManager.ShowModal(layout)
+}
+
+// renderBatchRenamePreview lists the new names of a batch rename, with the
+// collisions that keep it from being applied first
+func renderBatchRenamePreview(plan config.RenamePlan, err error) string {
+	if err != nil {
+		return fmt.Sprintf("[gray]%s[white]", tview.Escape(err.Error()))
+	}
+
+	var b strings.Builder
+	if len(plan.Collisions) > 0 {
+		fmt.Fprintf(&b, "[red]%d collision(s), nothing will be renamed:[white]\n", len(plan.Collisions))
+		for _, collision := range plan.Collisions {
+			fmt.Fprintf(&b, "  [red]✗[white] %s\n", tview.Escape(collision))
+		}
+		b.WriteString("\n")
+	}
+	fmt.Fprintf(&b, "[yellow]%d server(s) will be renamed:[white]\n", len(plan.Renames))
+	for _, rename := range plan.Renames {
+		fmt.Fprintf(&b, "  [green]~[white] %s → %s\n", tview.Escape(rename.From), tview.Escape(rename.To))
+	}
+	if len(plan.Unchanged) > 0 {
+		fmt.Fprintf(&b, "\n[gray]%d unchanged: %s[white]\n", len(plan.Unchanged), tview.Escape(strings.Join(plan.Unchanged, ", ")))
+	}
+	return b.String()
+}
@@ -0,0 +1,32 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"sshm/internal/config"
+)
+
+func TestRenderBatchRenamePreview(t *testing.T) {
+	plan := config.RenamePlan{
+		Renames:   []config.ServerRename{{From: "web1.example.com", To: "web1"}},
+		Unchanged: []string{"db1"},
+	}
+	preview := renderBatchRenamePreview(plan, nil)
+	if !strings.Contains(preview, "web1.example.com → web1") || !strings.Contains(preview, "1 unchanged: db1") {
+		t.Errorf("Expected the new name and the unchanged server, got %q", preview)
+	}
+	if strings.Contains(preview, "collision") {
+		t.Errorf("Expected no collisions, got %q", preview)
+	}
+
+	plan.Collisions = []string{"web1.example.com would be named web1 like web1"}
+	if preview := renderBatchRenamePreview(plan, nil); !strings.Contains(preview, "nothing will be renamed") {
+		t.Errorf("Expected the collision to block the rename, got %q", preview)
+	}
+
+	if preview := renderBatchRenamePreview(config.RenamePlan{}, errors.New("a text to find or a template is required")); !strings.Contains(preview, "template is required") {
+		t.Errorf("Expected the validation message, got %q", preview)
+	}
+}
@@ -0,0 +1,165 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"sshm/internal/config"
+	"sshm/internal/history"
+	"sshm/internal/power"
+)
+
+// commandRunsShown caps the commands listed in the results picker
+const commandRunsShown = 20
+
+// commandLabel names a command in results: the command itself for custom
+// actions, the action otherwise
+func commandLabel(action, command string) string {
+	if action == power.ActionCustom {
+		return command
+	}
+	return action
+}
+
+// showCommandRuns lists the commands with cached results to open one of them
+func (t *TUIApp) showCommandRuns() {
+	if t.connectionManager == nil || t.connectionManager.GetHistoryManager() == nil {
+		t.showErrorModal("Command results are not available: history database not initialized")
+		return
+	}
+	runs, err := t.connectionManager.GetHistoryManager().GetCommandRuns(commandRunsShown)
+	if err != nil {
+		t.showErrorModal(fmt.Sprintf("Failed to load command results: %s", err.Error()))
+		return
+	}
+	if len(runs) == 0 {
+		t.showTransientStatus("[yellow]No command results yet; run a power action first[white]")
+		return
+	}
+
+	list := tview.NewList().ShowSecondaryText(true)
+	for _, run := range runs {
+		run := run
+		secondary := fmt.Sprintf("%d server(s), %d failed, last run %s", run.Servers, run.Failed, run.LastRun.Local().Format("2006-01-02 15:04"))
+		list.AddItem(tview.Escape(commandLabel(run.Action, run.Command)), secondary, 0, func() {
+			t.modalManager.HideModal()
+			t.showCommandResults(run.Action, run.Command, "")
+		})
+	}
+	list.SetBorder(true).
+		SetTitle(" Command Results ").
+		SetTitleAlign(tview.AlignCenter)
+	list.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape || event.Rune() == 'q' {
+			t.modalManager.HideModal()
+			return nil
+		}
+		return event
+	})
+	t.modalManager.ShowModal(list)
+}
+
+// showCommandResults shows the last result of a command on each server it ran
+// on, with f re-running it on the servers where it failed and a on all of them
+func (t *TUIApp) showCommandResults(action, command, note string) {
+	results, err := t.connectionManager.GetHistoryManager().GetCommandResults(action, command)
+	if err != nil {
+		t.showErrorModal(fmt.Sprintf("Failed to load command results: %s", err.Error()))
+		return
+	}
+
+	var failed, all []string
+	for _, result := range results {
+		all = append(all, result.ServerName)
+		if result.Failed() {
+			failed = append(failed, result.ServerName)
+		}
+	}
+
+	text := renderCommandResults(results, time.Now())
+	if note != "" {
+		text += "\n" + note + "\n"
+	}
+	text += fmt.Sprintf("\n[gray]Press f to re-run on the %d failed server(s), a to re-run on all, Enter, Escape or q to close[white]", len(failed))
+
+	panel := t.showTextPanel(fmt.Sprintf("Results › %s", commandLabel(action, command)), text)
+	close := panel.GetInputCapture()
+	panel.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Rune() {
+		case 'f':
+			t.rerunCommand(action, command, failed)
+			return nil
+		case 'a':
+			t.rerunCommand(action, command, all)
+			return nil
+		}
+		return close(event)
+	})
+}
+
+// rerunCommand runs a command again on the named servers that still exist.
+// Protected servers are left to the power action form, which asks for the
+// typed confirmation they need.
+func (t *TUIApp) rerunCommand(action, command string, serverNames []string) {
+	if len(serverNames) == 0 {
+		t.showTransientStatus("[yellow]Nothing to re-run[white]")
+		return
+	}
+
+	var servers []config.Server
+	for _, name := range serverNames {
+		if server, err := t.config.GetServer(name); err == nil {
+			servers = append(servers, *server)
+		}
+	}
+	if len(servers) == 0 {
+		t.showErrorModal("None of these servers exist anymore")
+		return
+	}
+	if power.RequiresTypedConfirmation(servers) {
+		t.showErrorModal("These servers include protected ones.\n\nRe-run from the power action form (Ctrl+P), which asks for confirmation.")
+		return
+	}
+
+	t.hideTextPanel()
+	t.runPowerAction(servers, action, command)
+}
+
+// formatAge renders how long ago something happened in its largest unit
+func formatAge(age time.Duration) string {
+	switch {
+	case age < time.Minute:
+		return fmt.Sprintf("%ds", int(age.Seconds()))
+	case age < time.Hour:
+		return fmt.Sprintf("%dm", int(age.Minutes()))
+	case age < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(age.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(age.Hours()/24))
+	}
+}
+
+// renderCommandResults lists the cached results, failures first
+func renderCommandResults(results []history.CommandResult, now time.Time) string {
+	var b strings.Builder
+	for _, failedFirst := range []bool{true, false} {
+		for _, result := range results {
+			if result.Failed() != failedFirst {
+				continue
+			}
+			age := formatAge(now.Sub(result.RanAt))
+			if result.Failed() {
+				fmt.Fprintf(&b, "[red]✗ %s[white] [gray](%s ago)[white]: %s\n", tview.Escape(result.ServerName), age, tview.Escape(result.Error))
+			} else {
+				fmt.Fprintf(&b, "[green]✓ %s[white] [gray](%s ago)[white]\n", tview.Escape(result.ServerName), age)
+			}
+			if result.Output != "" {
+				fmt.Fprintf(&b, "[gray]%s[white]\n", tview.Escape(result.Output))
+			}
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,53 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"sshm/internal/history"
+	"sshm/internal/power"
+)
+
+func TestCommandLabel(t *testing.T) {
+	if got := commandLabel(power.ActionCustom, "uptime"); got != "uptime" {
+		t.Errorf("commandLabel(custom) = %q, want %q", got, "uptime")
+	}
+	if got := commandLabel("reboot", ""); got != "reboot" {
+		t.Errorf("commandLabel(reboot) = %q, want %q", got, "reboot")
+	}
+}
+
+func TestFormatAge(t *testing.T) {
+	tests := map[time.Duration]string{
+		30 * time.Second: "30s",
+		5 * time.Minute:  "5m",
+		3 * time.Hour:    "3h",
+		50 * time.Hour:   "2d",
+	}
+	for age, want := range tests {
+		if got := formatAge(age); got != want {
+			t.Errorf("formatAge(%v) = %q, want %q", age, got, want)
+		}
+	}
+}
+
+func TestRenderCommandResultsListsFailuresFirst(t *testing.T) {
+	now := time.Now()
+	results := []history.CommandResult{
+		{ServerName: "alpha", Status: history.CommandSucceeded, Output: "up 3 days", RanAt: now.Add(-2 * time.Minute)},
+		{ServerName: "beta", Status: history.CommandFailed, Error: "connection refused", RanAt: now.Add(-time.Hour)},
+	}
+
+	text := renderCommandResults(results, now)
+	beta := strings.Index(text, "beta")
+	alpha := strings.Index(text, "alpha")
+	if beta < 0 || alpha < 0 || beta > alpha {
+		t.Fatalf("expected the failed server first, got:\n%s", text)
+	}
+	for _, want := range []string{"connection refused", "up 3 days", "(2m ago)", "(1h ago)"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in:\n%s", want, text)
+		}
+	}
+}
@@ -0,0 +1,218 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"sshm/internal/config"
+	"sshm/internal/history"
+)
+
+// compareHistoryLimit is the number of recent connections shown per server
+const compareHistoryLimit = 5
+
+// compareRow is one line of the side by side comparison
+type compareRow struct {
+	label string
+	left  string
+	right string
+}
+
+// differs reports whether both sides have a different value
+func (r compareRow) differs() bool {
+	return r.left != r.right
+}
+
+// markServerForCompare marks the selected server for comparison. Marking a second
+// server opens the comparison; marking the same server again clears the mark.
+func (t *TUIApp) markServerForCompare() {
+	if t.focusedPanel != "servers" {
+		return
+	}
+
+	serverName := t.getSelectedServerName()
+	if serverName == "" {
+		return
+	}
+
+	switch t.compareMark {
+	case "":
+		t.compareMark = serverName
+		t.showTransientStatus(fmt.Sprintf("[yellow]Marked '%s' for compare — press Space on another server[white]", serverName))
+	case serverName:
+		t.compareMark = ""
+		t.showTransientStatus("[yellow]Compare mark cleared[white]")
+	default:
+		first := t.compareMark
+		t.compareMark = ""
+		t.showServerComparison(first, serverName)
+	}
+}
+
+// showServerComparison displays two servers side by side, highlighting differences
+func (t *TUIApp) showServerComparison(leftName, rightName string) {
+	left, err := t.config.GetServer(leftName)
+	if err != nil {
+		t.showErrorModal(fmt.Sprintf("Server '%s' not found: %s", leftName, err.Error()))
+		return
+	}
+	right, err := t.config.GetServer(rightName)
+	if err != nil {
+		t.showErrorModal(fmt.Sprintf("Server '%s' not found: %s", rightName, err.Error()))
+		return
+	}
+
+	rows := compareServers(*left, *right, t.getServerProfiles(left.Name), t.getServerProfiles(right.Name))
+
+	leftStatus, _ := t.getCachedConnectionStatus(left.Name)
+	rightStatus, _ := t.getCachedConnectionStatus(right.Name)
+	rows = append(rows, compareRow{"Status", leftStatus, rightStatus})
+	rows = append(rows, t.compareHistory(left.Name, right.Name)...)
+
+	table := tview.NewTable().
+		SetBorders(false).
+		SetSelectable(true, false).
+		SetFixed(1, 1)
+	table.SetBorder(true).
+		SetTitle(fmt.Sprintf(" Compare › %s ↔ %s ", left.Name, right.Name)).
+		SetBorderColor(tcell.ColorYellow)
+
+	for col, header := range []string{"Field", left.Name, right.Name} {
+		table.SetCell(0, col, tview.NewTableCell(header).
+			SetTextColor(tcell.ColorYellow).
+			SetAttributes(tcell.AttrBold).
+			SetSelectable(false).
+			SetExpansion(1))
+	}
+
+	differences := 0
+	for i, row := range rows {
+		valueColor := tcell.ColorWhite
+		label := row.label
+		if row.differs() {
+			differences++
+			valueColor = tcell.ColorOrange
+			label = "≠ " + label
+		}
+		table.SetCell(i+1, 0, tview.NewTableCell(label).SetTextColor(tcell.ColorAqua).SetExpansion(1))
+		table.SetCell(i+1, 1, tview.NewTableCell(orDash(row.left)).SetTextColor(valueColor).SetExpansion(2))
+		table.SetCell(i+1, 2, tview.NewTableCell(orDash(row.right)).SetTextColor(valueColor).SetExpansion(2))
+	}
+
+	footer := tview.NewTextView().
+		SetDynamicColors(true).
+		SetText(fmt.Sprintf("[orange]%d difference(s)[white]  •  [gray]Press Enter, Escape or q to close[white]", differences))
+
+	panel := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(table, 0, 1, true).
+		AddItem(footer, 1, 0, false)
+
+	panel.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEnter, tcell.KeyEscape:
+			t.hideTextPanel()
+			return nil
+		}
+		if event.Rune() == 'q' || event.Rune() == 'Q' {
+			t.hideTextPanel()
+			return nil
+		}
+		return event
+	})
+
+	if t.modalManager != nil {
+		t.modalManager.ShowModal(panel)
+	} else {
+		t.setRoot(panel)
+		t.app.SetFocus(panel)
+	}
+}
+
+// compareServers lists the configuration fields of two servers side by side
+func compareServers(left, right config.Server, leftProfiles, rightProfiles []string) []compareRow {
+	rows := []compareRow{
+		{"Hostname", left.Hostname, right.Hostname},
+		{"Effective address", left.GetEffectiveHostname(), right.GetEffectiveHostname()},
+		{"Port", fmt.Sprintf("%d", left.Port), fmt.Sprintf("%d", right.Port)},
+		{"Username", left.Username, right.Username},
+		{"Auth type", left.AuthType, right.AuthType},
+		{"Key path", left.KeyPath, right.KeyPath},
+		{"Aliases", strings.Join(left.Aliases, ", "), strings.Join(right.Aliases, ", ")},
+		{"Tags", strings.Join(left.Tags, ", "), strings.Join(right.Tags, ", ")},
+		{"Profiles", strings.Join(leftProfiles, ", "), strings.Join(rightProfiles, ", ")},
+		{"Tunnels", describeTunnels(left.Tunnels), describeTunnels(right.Tunnels)},
+		{"Database", describeDatabase(left.Database), describeDatabase(right.Database)},
+		{"Banner ack", fmt.Sprintf("%t", left.RequireBannerAck), fmt.Sprintf("%t", right.RequireBannerAck)},
+	}
+	return rows
+}
+
+// compareHistory summarizes the recent connection history of both servers
+func (t *TUIApp) compareHistory(leftName, rightName string) []compareRow {
+	if t.connectionManager == nil {
+		return nil
+	}
+
+	summarize := func(serverName string) (stats, recent, lastError string) {
+		if s, err := t.connectionManager.GetConnectionStats(serverName, ""); err == nil && s.TotalConnections > 0 {
+			stats = fmt.Sprintf("%d total, %.0f%% success", s.TotalConnections, s.SuccessRate)
+		}
+
+		entries, err := t.connectionManager.GetConnectionHistory(history.HistoryFilter{
+			ServerName: serverName,
+			Limit:      compareHistoryLimit,
+		})
+		if err != nil {
+			return stats, "", ""
+		}
+
+		var statuses []string
+		for _, entry := range entries {
+			statuses = append(statuses, entry.Status)
+			if lastError == "" && entry.ErrorMessage != "" {
+				lastError = entry.ErrorMessage
+			}
+		}
+		return stats, strings.Join(statuses, ", "), lastError
+	}
+
+	leftStats, leftRecent, leftError := summarize(leftName)
+	rightStats, rightRecent, rightError := summarize(rightName)
+	return []compareRow{
+		{"Connections", leftStats, rightStats},
+		{"Recent results", leftRecent, rightRecent},
+		{"Last error", leftError, rightError},
+	}
+}
+
+// describeTunnels formats tunnel definitions on a single line
+func describeTunnels(tunnels []config.Tunnel) string {
+	var parts []string
+	for _, spec := range tunnels {
+		parts = append(parts, fmt.Sprintf("%s (%d → %s)", spec.Name, spec.LocalPort, spec.Destination()))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// describeDatabase formats database metadata on a single line
+func describeDatabase(database *config.DatabaseConfig) string {
+	if database == nil {
+		return ""
+	}
+	dbTunnel := database.Tunnel()
+	description := fmt.Sprintf("%s on %s:%d", database.Engine, dbTunnel.GetRemoteHost(), dbTunnel.RemotePort)
+	if database.Name != "" {
+		description += fmt.Sprintf(" (%s)", database.Name)
+	}
+	return description
+}
+
+// orDash renders empty values as a gray dash
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
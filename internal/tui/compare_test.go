@@ -0,0 +1,32 @@
+package tui
+
+import (
+	"testing"
+
+	"sshm/internal/config"
+)
+
+func TestCompareServersHighlightsDifferences(t *testing.T) {
+	left := config.Server{Name: "replica1", Hostname: "10.0.0.1", Port: 22, Username: "deploy", AuthType: "key", Tags: []string{"db"}}
+	right := config.Server{Name: "replica2", Hostname: "10.0.0.2", Port: 22, Username: "deploy", AuthType: "key", Tags: []string{"db", "canary"}}
+
+	rows := compareServers(left, right, []string{"prod"}, []string{"prod"})
+
+	differing := make(map[string]bool)
+	for _, row := range rows {
+		if row.differs() {
+			differing[row.label] = true
+		}
+	}
+
+	for _, label := range []string{"Hostname", "Effective address", "Tags"} {
+		if !differing[label] {
+			t.Errorf("Expected '%s' to be highlighted as different", label)
+		}
+	}
+	for _, label := range []string{"Port", "Username", "Auth type", "Profiles"} {
+		if differing[label] {
+			t.Errorf("Expected '%s' to be equal", label)
+		}
+	}
+}
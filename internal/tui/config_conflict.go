@@ -0,0 +1,163 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"sshm/internal/config"
+	"sshm/internal/service"
+)
+
+// service returns the service that changes the configuration and saves it with
+// saveConfig, shared with the CLI
+func (t *TUIApp) service() *service.Service {
+	return service.New(t.config, t.saveConfig)
+}
+
+// saveConfig saves the configuration, unless config.yaml changed on disk since
+// it was loaded: then the external changes are not overwritten silently but the
+// user is asked how to reconcile them, and the changes stay in memory until then
+func (t *TUIApp) saveConfig() error {
+	changed, err := t.config.ChangedOnDisk()
+	if err != nil {
+		return err
+	}
+	if !changed {
+		return t.writeConfig()
+	}
+
+	if !t.configConflict {
+		t.configConflict = true
+		// Callers hide their own modals once saved, so prompt after they are done
+		if t.app != nil {
+			t.app.QueueUpdateDraw(t.showConfigConflict)
+		}
+	}
+	return nil
+}
+
+// showConfigConflict asks whether to keep the in-memory configuration, take the
+// one on disk or merge the two entry by entry
+func (t *TUIApp) showConfigConflict() {
+	modal := tview.NewModal().
+		SetText("The configuration file was changed outside sshm since it was loaded.\n\nYour unsaved changes would overwrite it. How should they be reconciled?").
+		AddButtons([]string{"Keep mine", "Take theirs", "Merge per entry", "Later"}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			t.modalManager.HideModal()
+			t.configConflict = false
+			switch buttonLabel {
+			case "Keep mine":
+				t.keepMyConfig()
+			case "Take theirs":
+				t.takeTheirConfig()
+			case "Merge per entry":
+				t.mergeConfig()
+			default:
+				t.showTransientStatus("[yellow]Changes not saved; they will be reconciled on the next save[white]")
+			}
+		}).
+		SetBackgroundColor(tcell.ColorDarkRed)
+	modal.SetTitle(" Configuration Changed on Disk ")
+	t.modalManager.ShowModal(modal)
+}
+
+// keepMyConfig overwrites the configuration on disk with the one in memory
+func (t *TUIApp) keepMyConfig() {
+	if err := t.writeConfig(); err != nil {
+		t.showErrorModal(fmt.Sprintf("Failed to save configuration: %s", err.Error()))
+		return
+	}
+	t.showTransientStatus("[green]✓ Saved; changes made on disk were overwritten[white]")
+}
+
+// takeTheirConfig discards the in-memory changes and loads the configuration on disk
+func (t *TUIApp) takeTheirConfig() {
+	if err := t.RefreshConfig(); err != nil {
+		t.showErrorModal(err.Error())
+		return
+	}
+	t.showTransientStatus("[yellow]Loaded the configuration on disk; your unsaved changes were discarded[white]")
+}
+
+// mergeConfig takes over changes made on disk that don't clash with the ones
+// made here and lets the user pick a side for every entry changed on both
+func (t *TUIApp) mergeConfig() {
+	merge, err := t.config.PrepareMerge()
+	if err != nil {
+		t.showErrorModal(fmt.Sprintf("Failed to read the configuration on disk: %s", err.Error()))
+		return
+	}
+	if len(merge.Conflicts) == 0 {
+		t.applyConfigMerge(merge)
+		return
+	}
+
+	form := tview.NewForm()
+	for i, conflict := range merge.Conflicts {
+		i := i
+		form.AddDropDown(fmt.Sprintf("%s %s", conflict.Kind, conflict.Name), []string{"Keep mine", "Take theirs"}, 0, func(option string, index int) {
+			merge.Conflicts[i].TakeTheirs = index == 1
+		})
+	}
+	form.AddButton("Merge", func() {
+		t.modalManager.HideModal()
+		t.applyConfigMerge(merge)
+	})
+	form.AddButton("Cancel", func() {
+		t.modalManager.HideModal()
+	})
+	form.SetBorder(true).
+		SetTitle(fmt.Sprintf(" Merge Configuration (%d conflict(s)) ", len(merge.Conflicts))).
+		SetTitleAlign(tview.AlignCenter)
+	form.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape {
+			t.modalManager.HideModal()
+			return nil
+		}
+		return event
+	})
+
+	versions := tview.NewTextView().SetDynamicColors(true).SetScrollable(true).
+		SetText(renderMergeConflicts(merge.Conflicts))
+	versions.SetBorder(true).SetTitle(" Mine / Theirs ")
+
+	layout := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(form, 0, 1, true).
+		AddItem(versions, 0, 1, false)
+	t.modalManager.ShowModal(layout)
+}
+
+// applyConfigMerge saves the merged configuration and refreshes the views
+func (t *TUIApp) applyConfigMerge(merge *config.ConfigMerge) {
+	merge.Apply()
+	if err := t.writeConfig(); err != nil {
+		t.showErrorModal(fmt.Sprintf("Failed to save configuration: %s", err.Error()))
+		return
+	}
+
+	t.initializeProfileTabs()
+	t.updateProfileDisplay()
+	t.refreshServerList()
+
+	theirs := 0
+	for _, conflict := range merge.Conflicts {
+		if conflict.TakeTheirs {
+			theirs++
+		}
+	}
+	t.showTransientStatus(fmt.Sprintf("[green]✓ Merged %d change(s) made on disk; %d of %d conflict(s) resolved with theirs[white]",
+		merge.TheirChanges, theirs, len(merge.Conflicts)))
+}
+
+// renderMergeConflicts shows both versions of every conflicting entry
+func renderMergeConflicts(conflicts []config.MergeConflict) string {
+	var b strings.Builder
+	for _, conflict := range conflicts {
+		fmt.Fprintf(&b, "[yellow]%s %s[white]\n", conflict.Kind, tview.Escape(conflict.Name))
+		fmt.Fprintf(&b, "  mine:   %s\n", tview.Escape(conflict.Mine))
+		fmt.Fprintf(&b, "  theirs: %s\n", tview.Escape(conflict.Theirs))
+	}
+	return b.String()
+}
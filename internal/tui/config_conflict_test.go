@@ -0,0 +1,63 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"sshm/internal/config"
+)
+
+func TestSaveConfigDefersOnExternalChange(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	cfg, err := config.LoadFromPath(configPath)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	app := &TUIApp{config: cfg}
+
+	server := config.Server{Name: "web", Hostname: "10.0.0.1", Port: 22, Username: "deploy", AuthType: "key", KeyPath: "~/.ssh/id_rsa"}
+	if err := cfg.AddServer(server); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.saveConfig(); err != nil {
+		t.Fatalf("Expected an unchanged file to be saved, got: %v", err)
+	}
+	if app.configConflict {
+		t.Fatal("Expected no conflict without external changes")
+	}
+
+	external := "servers: []\n"
+	if err := os.WriteFile(configPath, []byte(external), 0600); err != nil {
+		t.Fatal(err)
+	}
+	server.Name = "db"
+	if err := cfg.AddServer(server); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.saveConfig(); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if !app.configConflict {
+		t.Error("Expected the external change to be reported")
+	}
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != external {
+		t.Errorf("Expected the external change not to be overwritten, got:\n%s", data)
+	}
+}
+
+func TestRenderMergeConflicts(t *testing.T) {
+	text := renderMergeConflicts([]config.MergeConflict{
+		{Kind: config.MergeServer, Name: "web", Mine: "admin@web:22 (key)", Theirs: "(deleted)"},
+	})
+	for _, want := range []string{"server web", "mine:   admin@web:22 (key)", "theirs: (deleted)"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected %q in:\n%s", want, text)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"sshm/internal/config"
+)
+
+// errUnlockCancelled is returned when the unlock prompt is closed without a passphrase
+var errUnlockCancelled = errors.New("the encrypted configuration wasn't unlocked")
+
+// loadConfig loads the configuration, asking for the passphrase first when it
+// is encrypted and its passphrase isn't available otherwise
+func loadConfig() (*config.Config, error) {
+	cfg, err := config.Load()
+	if !errors.Is(err, config.ErrPassphraseRequired) && !errors.Is(err, config.ErrWrongPassphrase) {
+		return cfg, err
+	}
+	path, pathErr := config.DefaultConfigPath()
+	if pathErr != nil {
+		return nil, err
+	}
+	if err := unlockConfig(path); err != nil {
+		return nil, err
+	}
+	return config.Load()
+}
+
+// unlockConfig shows a passphrase prompt until the configuration at path is
+// unlocked or the prompt is closed with Escape
+func unlockConfig(path string) error {
+	app := tview.NewApplication()
+	var result error
+	prompt := newConfigUnlockPrompt(path, func(err error) {
+		result = err
+		app.Stop()
+	})
+	if err := app.SetRoot(prompt.layout, true).SetFocus(prompt.field).Run(); err != nil {
+		return err
+	}
+	return result
+}
+
+// configUnlockPrompt asks for the passphrase of an encrypted configuration
+type configUnlockPrompt struct {
+	path    string
+	message *tview.TextView
+	field   *tview.InputField
+	layout  tview.Primitive
+	done    func(error)
+}
+
+// newConfigUnlockPrompt creates the prompt; done is called with nil once the
+// configuration is unlocked, or with errUnlockCancelled on Escape
+func newConfigUnlockPrompt(path string, done func(error)) *configUnlockPrompt {
+	p := &configUnlockPrompt{path: path, done: done}
+
+	p.message = tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextAlign(tview.AlignCenter)
+	p.setMessage("[white]Enter the passphrase and press Enter to unlock, Escape to quit")
+
+	p.field = tview.NewInputField().
+		SetLabel("Passphrase: ").
+		SetMaskCharacter('*').
+		SetFieldWidth(32).
+		SetFieldBackgroundColor(tcell.ColorBlack).
+		SetLabelColor(tcell.ColorYellow)
+	p.field.SetDoneFunc(p.submit)
+
+	p.layout = tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(tview.NewBox(), 0, 1, false).
+		AddItem(p.message, 4, 0, false).
+		AddItem(tview.NewFlex().
+			AddItem(tview.NewBox(), 0, 1, false).
+			AddItem(p.field, 44, 0, true).
+			AddItem(tview.NewBox(), 0, 1, false), 1, 0, true).
+		AddItem(tview.NewBox(), 0, 1, false)
+	return p
+}
+
+func (p *configUnlockPrompt) setMessage(text string) {
+	p.message.SetText(fmt.Sprintf("[yellow::b]🔒 The configuration is encrypted[::-]\n[gray]%s[white]\n\n%s", tview.Escape(p.path), text))
+}
+
+func (p *configUnlockPrompt) submit(key tcell.Key) {
+	switch key {
+	case tcell.KeyEscape:
+		p.done(errUnlockCancelled)
+		return
+	case tcell.KeyEnter:
+	default:
+		return
+	}
+
+	passphrase := p.field.GetText()
+	if passphrase == "" {
+		return
+	}
+	err := config.Unlock(p.path, passphrase)
+	switch {
+	case err == nil:
+		p.done(nil)
+	case errors.Is(err, config.ErrWrongPassphrase):
+		p.field.SetText("")
+		p.setMessage("[red]Wrong passphrase, try again")
+	default:
+		p.done(err)
+	}
+}
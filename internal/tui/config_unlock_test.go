@@ -0,0 +1,47 @@
+package tui
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"sshm/internal/config"
+)
+
+func TestConfigUnlockPrompt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	cfg, err := config.LoadFromPath(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.SetPassphrase("correct horse battery"); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	var results []error
+	prompt := newConfigUnlockPrompt(path, func(err error) { results = append(results, err) })
+
+	prompt.field.SetText("wrong passphrase")
+	prompt.submit(tcell.KeyEnter)
+	if len(results) != 0 {
+		t.Fatalf("Expected a wrong passphrase to keep the prompt open, got %v", results)
+	}
+	if !strings.Contains(prompt.message.GetText(true), "Wrong passphrase") || prompt.field.GetText() != "" {
+		t.Errorf("Expected the wrong passphrase to be reported and cleared, got %q", prompt.message.GetText(true))
+	}
+
+	prompt.field.SetText("correct horse battery")
+	prompt.submit(tcell.KeyEnter)
+	if len(results) != 1 || results[0] != nil {
+		t.Fatalf("Expected the configuration to be unlocked, got %v", results)
+	}
+
+	prompt.submit(tcell.KeyEscape)
+	if len(results) != 2 || results[1] != errUnlockCancelled {
+		t.Errorf("Expected Escape to cancel, got %v", results)
+	}
+}
@@ -0,0 +1,84 @@
+package tui
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"sshm/internal/config"
+	"sshm/internal/tmux"
+)
+
+// shouldConfirm reports whether an action needs a confirmation prompt according to
+// the ui.confirmations setting; protected tells whether a protected server is affected
+func (t *TUIApp) shouldConfirm(action string, protected bool) bool {
+	if t.config == nil {
+		return true
+	}
+	return t.config.UI.ShouldConfirm(action, protected)
+}
+
+// isServerProtected reports whether the named server is protected
+func (t *TUIApp) isServerProtected(serverName string) bool {
+	server, err := t.config.GetServer(serverName)
+	return err == nil && server.Protected
+}
+
+// isProfileProtected reports whether any server in the profile is protected
+func (t *TUIApp) isProfileProtected(profileName string) bool {
+	servers, err := t.config.GetServersByProfile(profileName)
+	if err != nil {
+		return false
+	}
+	for _, server := range servers {
+		if server.Protected {
+			return true
+		}
+	}
+	return false
+}
+
+// isSessionProtected reports whether a tmux session belongs to a protected server
+func (t *TUIApp) isSessionProtected(sessionName string) bool {
+	var names []string
+	for _, server := range t.config.GetServers() {
+		names = append(names, server.Name)
+	}
+	serverName := tmux.ServerForSession(sessionName, names)
+	return serverName != "" && t.isServerProtected(serverName)
+}
+
+// requestQuit quits the TUI, asking first when background operations or tunnels
+// started from the TUI are still running since quitting interrupts them
+func (t *TUIApp) requestQuit() {
+	// Background operations would be killed silently, so always list them first
+	if len(t.tasks.Blocking()) > 0 && t.modalManager != nil {
+		t.showQuitTasksModal()
+		return
+	}
+
+	running, protected := 0, false
+	if t.tunnelManager != nil {
+		for _, tun := range t.tunnelManager.List() {
+			running++
+			protected = protected || t.isServerProtected(tun.ServerName)
+		}
+	}
+	if running == 0 || t.modalManager == nil || !t.shouldConfirm(config.ConfirmQuit, protected) {
+		t.Stop()
+		return
+	}
+
+	modal := tview.NewModal().
+		SetText(fmt.Sprintf("%d tunnel(s) are running and will be closed.\n\nQuit anyway?", running)).
+		AddButtons([]string{"Quit", "Cancel"}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			t.modalManager.HideModal()
+			if buttonLabel == "Quit" {
+				t.Stop()
+			}
+		}).
+		SetBackgroundColor(tcell.ColorDarkRed)
+	modal.SetTitle(" Quit ")
+	t.modalManager.ShowModal(modal)
+}
@@ -0,0 +1,222 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"sshm/internal/config"
+	"sshm/internal/tunnel"
+)
+
+// menuItem is an entry of a pop-up menu
+type menuItem struct {
+	Label       string
+	Key         string // The key binding doing the same outside the menu, shown as a hint
+	Description string
+	Run         func()
+}
+
+// showMenu shows items in a pop-up list; each item closes the menu before it runs
+func (t *TUIApp) showMenu(title string, items []menuItem) {
+	menu := tview.NewList().ShowSecondaryText(true)
+	for _, item := range items {
+		item := item
+		label := tview.Escape(item.Label)
+		if item.Key != "" {
+			label = fmt.Sprintf("%s [gray](%s)[white]", label, item.Key)
+		}
+		menu.AddItem(label, tview.Escape(item.Description), 0, func() {
+			t.modalManager.HideModal()
+			item.Run()
+		})
+	}
+	menu.SetBorder(true).
+		SetTitle(fmt.Sprintf(" %s ", title)).
+		SetTitleAlign(tview.AlignCenter)
+	menu.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Rune() == 'q' {
+			t.modalManager.HideModal()
+			return nil
+		}
+		return event
+	})
+	t.modalManager.ShowModal(menu)
+}
+
+// showContextMenu lists what can be done with the selected server or session
+func (t *TUIApp) showContextMenu() {
+	switch t.focusedPanel {
+	case "servers":
+		serverName := t.getSelectedServerName()
+		if serverName == "" {
+			return
+		}
+		server, err := t.config.GetServer(serverName)
+		if err != nil {
+			t.showErrorModal(fmt.Sprintf("Server '%s' not found: %s", serverName, err.Error()))
+			return
+		}
+		t.showMenu(fmt.Sprintf("☰ %s", server.Name), append(t.serverMenuItems(*server), t.customActionMenuItems()...))
+	case "sessions":
+		if t.sessionPanel == nil {
+			return
+		}
+		row, _ := t.sessionPanel.GetSelection()
+		if row <= 0 || row > len(t.sessions) {
+			return
+		}
+		session := t.sessions[row-1]
+		t.showMenu(fmt.Sprintf("☰ %s", session.Name), append(t.sessionMenuItems(session), t.customActionMenuItems()...))
+	}
+}
+
+// serverMenuItems returns the built-in actions applicable to a server
+func (t *TUIApp) serverMenuItems(server config.Server) []menuItem {
+	items := []menuItem{
+		{"Connect", "Enter", fmt.Sprintf("Open a tmux session on %s", server.Name), t.connectToSelectedServer},
+		{"Details", "l", "Show the server's configuration and status", t.showServerDetails},
+		{"Edit", "e", "Edit the server in a form", t.editSelectedServer},
+		{"Edit as YAML", "Ctrl+E", "Edit the server in $EDITOR", t.editSelectedServerInEditor},
+		{"Tags", "", tagsDescription(server), func() { t.showTagsForm(server.Name) }},
+	}
+	if len(server.Tunnels) > 0 {
+		items = append(items,
+			menuItem{"Start tunnels", "f", fmt.Sprintf("Start the %d configured tunnel(s)", len(server.Tunnels)), t.startSelectedServerTunnels},
+			menuItem{"Open service", "g", "Open a tunnelled web service in the browser", t.openSelectedServerService},
+		)
+	}
+	socks := server.SOCKSTunnel()
+	if t.tunnelManager != nil && t.tunnelManager.IsRunning(tunnel.Key(server.Name, socks.Name)) {
+		items = append(items, menuItem{"Stop SOCKS proxy", "Ctrl+S", "Stop the SOCKS proxy through this server", t.toggleSelectedServerSOCKS})
+	} else {
+		items = append(items, menuItem{"SOCKS proxy", "Ctrl+S", "Route browser traffic through this server (ssh -D)", t.toggleSelectedServerSOCKS})
+	}
+	items = append(items, menuItem{"Distribute file", "", "Push a local file to this server or the active profile", t.showDistributeForm})
+	items = append(items, menuItem{"Run command on profile", ":", "Run a command on every server of a profile in parallel", t.showRunCommandForm})
+	if !t.isOffline() {
+		items = append(items, menuItem{"Diagnostics", "Ctrl+G", "Port check, DNS records, ping, traceroute or mtr", t.showDiagnosticsMenu})
+	}
+	items = append(items,
+		menuItem{"Watch until online", "Ctrl+W", "Notify when the server comes back", t.toggleWatchSelectedServer},
+		menuItem{"Mark for compare", "Space", "Compare with another marked server", t.markServerForCompare},
+		menuItem{"Delete", "d", "Remove the server from the configuration", t.deleteSelectedServer},
+	)
+	return items
+}
+
+// sessionMenuItems returns the built-in actions applicable to a session
+func (t *TUIApp) sessionMenuItems(session SessionInfo) []menuItem {
+	items := []menuItem{
+		{"Attach", "Enter", "Attach to the session; detach to return here", t.attachToSelectedSession},
+	}
+	if len(session.LostWindows) > 0 {
+		items = append(items, menuItem{"Repair", "Ctrl+R", fmt.Sprintf("Reconnect %d disconnected window(s)", len(session.LostWindows)), t.repairSelectedSession})
+	}
+	items = append(items,
+		menuItem{"Invite teammate", "Ctrl+Y", "Share the session for pair debugging", t.inviteToSelectedSession},
+		outputWatchMenuItem(t.isOutputWatched(session.Name), t.toggleOutputWatchSelectedSession),
+		menuItem{"Kill", "y", "Terminate the session", t.killSelectedSession},
+	)
+	return items
+}
+
+// tagsDescription summarizes a server's tags for the menu
+func tagsDescription(server config.Server) string {
+	if len(server.Tags) == 0 {
+		return "No tags; add some for grouping and filtering"
+	}
+	return "Edit tags: " + strings.Join(server.Tags, ", ")
+}
+
+// showTagsForm edits the tags of a server as a comma-separated list
+func (t *TUIApp) showTagsForm(serverName string) {
+	server, err := t.config.GetServer(serverName)
+	if err != nil {
+		t.showErrorModal(fmt.Sprintf("Server '%s' not found: %s", serverName, err.Error()))
+		return
+	}
+
+	form := tview.NewForm().
+		AddInputField("Tags", strings.Join(server.Tags, ", "), 50, nil, nil)
+	form.SetBorder(true).
+		SetTitle(fmt.Sprintf(" Tags: %s ", server.Name)).
+		SetTitleAlign(tview.AlignCenter)
+	tagsField := form.GetFormItem(0).(*tview.InputField)
+
+	form.AddButton("Save", func() {
+		updated := *server
+		updated.Tags = parseTags(tagsField.GetText())
+		if err := t.service().UpdateServer(server.Name, updated); err != nil {
+			t.showErrorModal(fmt.Sprintf("Failed to update tags: %s", err.Error()))
+			return
+		}
+		t.modalManager.HideModal()
+		t.refreshServerList()
+		t.showTransientStatus(fmt.Sprintf("[green]✓ Updated the tags of %s[white]", server.Name))
+	})
+	form.AddButton("Cancel", func() {
+		t.modalManager.HideModal()
+	})
+	t.modalManager.ShowModal(form)
+}
+
+// parseTags splits a comma-separated list of tags, dropping blanks and
+// case-insensitive duplicates
+func parseTags(text string) []string {
+	var tags []string
+	for _, tag := range strings.Split(text, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			continue
+		}
+		duplicate := false
+		for _, existing := range tags {
+			if strings.EqualFold(existing, tag) {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// handleContextClick selects the row under a right-click in the server or
+// session list and opens its context menu. It reports whether the click was handled.
+func (t *TUIApp) handleContextClick(event *tcell.EventMouse) bool {
+	x, y := event.Position()
+	switch {
+	case t.isTreeMode() && t.serverTree != nil && t.serverTree.InRect(x, y):
+		// The tree can't tell which node is under the pointer, so use the current one
+		t.setContextPanel("servers")
+	case !t.isTreeMode() && t.serverList.InRect(x, y):
+		row, _ := t.serverList.CellAt(x, y)
+		if row <= 0 || row >= t.serverList.GetRowCount() {
+			return false
+		}
+		t.serverList.Select(row, 0)
+		t.selectedRow = row
+		t.setContextPanel("servers")
+	case t.sessionPanel != nil && t.sessionPanel.InRect(x, y):
+		row, _ := t.sessionPanel.CellAt(x, y)
+		if row <= 0 || row > len(t.sessions) {
+			return false
+		}
+		t.sessionPanel.Select(row, 0)
+		t.setContextPanel("sessions")
+	default:
+		return false
+	}
+	t.showContextMenu()
+	return true
+}
+
+// setContextPanel focuses the panel a context menu was opened from
+func (t *TUIApp) setContextPanel(panel string) {
+	t.focusedPanel = panel
+	t.updatePanelHighlight()
+}
@@ -0,0 +1,47 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"sshm/internal/config"
+)
+
+func TestParseTags(t *testing.T) {
+	tags := parseTags(" web, prod ,, Web,region:eu ")
+	if strings.Join(tags, "|") != "web|prod|region:eu" {
+		t.Errorf("Unexpected tags %v", tags)
+	}
+	if tags := parseTags(" , "); len(tags) != 0 {
+		t.Errorf("Expected no tags, got %v", tags)
+	}
+}
+
+func TestServerMenuItems(t *testing.T) {
+	app := &TUIApp{}
+	labels := func(items []menuItem) string {
+		var names []string
+		for _, item := range items {
+			names = append(names, item.Label)
+		}
+		return strings.Join(names, ",")
+	}
+
+	plain := config.Server{Name: "web1"}
+	if got := labels(app.serverMenuItems(plain)); strings.Contains(got, "Start tunnels") {
+		t.Errorf("Expected no tunnel items without tunnels, got %s", got)
+	}
+
+	withTunnels := config.Server{Name: "web1", Tunnels: []config.Tunnel{{LocalPort: 8080, RemoteHost: "localhost", RemotePort: 80}}}
+	got := labels(app.serverMenuItems(withTunnels))
+	for _, want := range []string{"Connect", "Edit", "Tags", "Start tunnels", "SOCKS proxy", "Diagnostics", "Delete"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Expected %s in the menu, got %s", want, got)
+		}
+	}
+
+	lost := SessionInfo{Name: "web1", LostWindows: []string{"1"}}
+	if got := labels(app.sessionMenuItems(lost)); got != "Attach,Repair,Invite teammate,Watch output,Kill" {
+		t.Errorf("Unexpected session items %s", got)
+	}
+}
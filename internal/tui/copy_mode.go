@@ -0,0 +1,227 @@
+package tui
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// colorTagPattern matches tview color and style tags such as [yellow], [::b] and [-]
+var colorTagPattern = regexp.MustCompile(`\[([a-zA-Z]+|#[0-9a-fA-F]{6}|-)?(:([a-zA-Z]+|#[0-9a-fA-F]{6}|-)?(:([lbidrus]+|-)?)?)?\]`)
+
+// stripColorTags returns panel text as it appears on screen, without markup
+func stripColorTags(text string) string {
+	return colorTagPattern.ReplaceAllString(text, "")
+}
+
+// clipboardCommand returns the command that copies its stdin to the system clipboard
+func clipboardCommand() (string, []string, bool) {
+	candidates := [][]string{{"wl-copy"}, {"xclip", "-selection", "clipboard"}, {"xsel", "--clipboard", "--input"}}
+	switch runtime.GOOS {
+	case "darwin":
+		candidates = [][]string{{"pbcopy"}}
+	case "windows":
+		candidates = [][]string{{"clip"}}
+	}
+
+	for _, candidate := range candidates {
+		if _, err := exec.LookPath(candidate[0]); err == nil {
+			return candidate[0], candidate[1:], true
+		}
+	}
+	return "", nil, false
+}
+
+// copyToClipboard copies text to the system clipboard, falling back to the OSC 52
+// terminal escape sequence, which also works over SSH (variable to allow mocking in tests)
+var copyToClipboard = func(text string) error {
+	if name, args, ok := clipboardCommand(); ok {
+		cmd := exec.Command(name, args...)
+		cmd.Stdin = strings.NewReader(text)
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("%s failed: %w", name, err)
+		}
+		return nil
+	}
+
+	tty, err := os.OpenFile("/dev/tty", os.O_WRONLY, 0)
+	if err != nil {
+		return fmt.Errorf("no clipboard tool found (install xclip, xsel or wl-copy)")
+	}
+	defer tty.Close()
+	_, err = fmt.Fprintf(tty, "\x1b]52;c;%s\x07", base64.StdEncoding.EncodeToString([]byte(text)))
+	return err
+}
+
+// copyModeView shows panel text line by line with a cursor and a line-wise
+// selection, navigated with vi-style keys
+type copyModeView struct {
+	table  *tview.Table
+	title  string
+	lines  []string
+	cursor int
+	anchor int // First line of the selection, -1 when nothing is selected
+}
+
+// newCopyModeView splits text into lines, dropping color markup
+func newCopyModeView(title, text string) *copyModeView {
+	view := &copyModeView{
+		table:  tview.NewTable(),
+		title:  title,
+		lines:  strings.Split(strings.TrimRight(stripColorTags(text), "\n"), "\n"),
+		anchor: -1,
+	}
+
+	view.table.SetSelectable(true, false).
+		SetSelectedStyle(tcell.StyleDefault.Reverse(true))
+	view.table.SetBorder(true).SetBorderColor(tcell.ColorAqua)
+	for i, line := range view.lines {
+		view.table.SetCell(i, 0, tview.NewTableCell(tview.Escape(line)).SetExpansion(1))
+	}
+	view.setTitle("")
+	view.render()
+	return view
+}
+
+// setTitle shows the panel title with an optional status message
+func (c *copyModeView) setTitle(status string) {
+	title := fmt.Sprintf(" Copy › %s ", c.title)
+	if status != "" {
+		title += "— " + status + " "
+	}
+	c.table.SetTitle(title)
+}
+
+// selection returns the first and last selected line, or the cursor line
+func (c *copyModeView) selection() (int, int) {
+	if c.anchor < 0 {
+		return c.cursor, c.cursor
+	}
+	if c.anchor < c.cursor {
+		return c.anchor, c.cursor
+	}
+	return c.cursor, c.anchor
+}
+
+// selectedText returns the selected lines, or the cursor line without a selection
+func (c *copyModeView) selectedText() string {
+	start, end := c.selection()
+	return strings.Join(c.lines[start:end+1], "\n")
+}
+
+// moveTo places the cursor on a line, clamped to the text
+func (c *copyModeView) moveTo(line int) {
+	if line < 0 {
+		line = 0
+	}
+	if line >= len(c.lines) {
+		line = len(c.lines) - 1
+	}
+	c.cursor = line
+	c.render()
+}
+
+// toggleSelection starts or clears a selection at the cursor
+func (c *copyModeView) toggleSelection() {
+	if c.anchor >= 0 {
+		c.anchor = -1
+	} else {
+		c.anchor = c.cursor
+	}
+	c.render()
+}
+
+// render highlights the selected lines and scrolls the cursor into view
+func (c *copyModeView) render() {
+	start, end := c.selection()
+	for i := range c.lines {
+		cell := c.table.GetCell(i, 0)
+		if c.anchor >= 0 && i >= start && i <= end {
+			cell.SetBackgroundColor(tcell.ColorDarkCyan)
+		} else {
+			cell.SetBackgroundColor(tcell.ColorDefault)
+		}
+	}
+	c.table.Select(c.cursor, 0)
+}
+
+// pageSize returns half the visible height, used for Ctrl+D and Ctrl+U
+func (c *copyModeView) pageSize() int {
+	_, _, _, height := c.table.GetInnerRect()
+	if height < 2 {
+		return 1
+	}
+	return height / 2
+}
+
+// showCopyMode opens panel text in copy mode on top of the panel; Escape returns to it
+func (t *TUIApp) showCopyMode(title, text string) {
+	view := newCopyModeView(title, text)
+
+	view.table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyUp:
+			view.moveTo(view.cursor - 1)
+			return nil
+		case tcell.KeyDown:
+			view.moveTo(view.cursor + 1)
+			return nil
+		case tcell.KeyCtrlU, tcell.KeyPgUp:
+			view.moveTo(view.cursor - view.pageSize())
+			return nil
+		case tcell.KeyCtrlD, tcell.KeyPgDn:
+			view.moveTo(view.cursor + view.pageSize())
+			return nil
+		case tcell.KeyHome:
+			view.moveTo(0)
+			return nil
+		case tcell.KeyEnd:
+			view.moveTo(len(view.lines) - 1)
+			return nil
+		}
+
+		switch event.Rune() {
+		case 'k':
+			view.moveTo(view.cursor - 1)
+		case 'j':
+			view.moveTo(view.cursor + 1)
+		case 'g':
+			view.moveTo(0)
+		case 'G':
+			view.moveTo(len(view.lines) - 1)
+		case 'v', 'V':
+			view.toggleSelection()
+		case 'y', 'Y':
+			start, end := view.selection()
+			if err := copyToClipboard(view.selectedText()); err != nil {
+				view.setTitle(fmt.Sprintf("copy failed: %v", err))
+			} else {
+				view.setTitle(fmt.Sprintf("%d line(s) copied", end-start+1))
+			}
+			view.anchor = -1
+			view.render()
+		case 'q', 'Q':
+			t.hideTextPanel()
+		}
+		return nil // The table's own navigation would bypass the selection
+	})
+	view.table.SetSelectionChangedFunc(func(row, column int) {
+		if row != view.cursor {
+			view.moveTo(row) // Mouse clicks move the cursor too
+		}
+	})
+
+	if t.modalManager != nil {
+		t.modalManager.ShowModal(view.table)
+	} else {
+		t.setRoot(view.table)
+		t.app.SetFocus(view.table)
+	}
+}
@@ -0,0 +1,46 @@
+package tui
+
+import "testing"
+
+func TestStripColorTags(t *testing.T) {
+	got := stripColorTags("[yellow::b]Help[::-] [yellow]q[white]: quit, see [1] and [-]done")
+	want := "Help q: quit, see [1] and done"
+	if got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
+
+func TestCopyModeSelection(t *testing.T) {
+	view := newCopyModeView("Test", "[yellow]first[white]\nsecond\nthird\nfourth\n")
+	if len(view.lines) != 4 {
+		t.Fatalf("Expected 4 lines, got %d", len(view.lines))
+	}
+
+	// Without a selection the cursor line is copied
+	if got := view.selectedText(); got != "first" {
+		t.Errorf("Expected cursor line, got %q", got)
+	}
+
+	view.moveTo(1)
+	view.toggleSelection()
+	view.moveTo(3)
+	if got := view.selectedText(); got != "second\nthird\nfourth" {
+		t.Errorf("Unexpected selection: %q", got)
+	}
+
+	// Selecting upwards from the anchor works the same
+	view.moveTo(0)
+	if got := view.selectedText(); got != "first\nsecond" {
+		t.Errorf("Unexpected upward selection: %q", got)
+	}
+
+	view.moveTo(100)
+	if view.cursor != 3 {
+		t.Errorf("Expected cursor to be clamped to the last line, got %d", view.cursor)
+	}
+
+	view.toggleSelection()
+	if view.anchor != -1 {
+		t.Error("Expected second toggle to clear the selection")
+	}
+}
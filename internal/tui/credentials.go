@@ -0,0 +1,78 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// credentialStatusText returns the status bar badge counting credentials due
+// within the warning window, empty when none are
+func (t *TUIApp) credentialStatusText() string {
+	if t.config == nil {
+		return ""
+	}
+
+	now := time.Now()
+	reminders := t.config.CredentialReminders(now, t.config.UI.CredentialWarningWindow())
+	if len(reminders) == 0 {
+		return ""
+	}
+	background := "orange"
+	for _, reminder := range reminders {
+		if reminder.IsOverdue(now) {
+			background = "red"
+			break
+		}
+	}
+	return fmt.Sprintf(" | [black:%s] 🔑 %d credential(s) due (!) [-:-]", background, len(reminders))
+}
+
+// showCredentialReminders lists key rotations and account expiries that are due
+// within the warning window or overdue
+func (t *TUIApp) showCredentialReminders() {
+	now := time.Now()
+	window := t.config.UI.CredentialWarningWindow()
+	reminders := t.config.CredentialReminders(now, window)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "[yellow]Credentials due within %d days[white]\n\n", int(window.Hours()/24))
+	if len(reminders) == 0 {
+		b.WriteString("[green]Nothing due. Record dates with 'sshm credentials set <server>'.[white]\n")
+	}
+	for _, reminder := range reminders {
+		due := reminder.Due.Local().Format("2006-01-02")
+		if reminder.IsOverdue(now) {
+			fmt.Fprintf(&b, "[red]%-20s %-15s %s  overdue by %s[white]\n", reminder.Server, reminder.Kind, due, formatDays(now.Sub(reminder.Due)))
+		} else {
+			fmt.Fprintf(&b, "[orange]%-20s %-15s %s  in %s[white]\n", reminder.Server, reminder.Kind, due, formatDays(reminder.Due.Sub(now)))
+		}
+	}
+	b.WriteString("\n[gray]After rotating, set the next date with 'sshm credentials set <server> --key-rotation-due 90d'. Press Enter, Escape or q to close[white]")
+	t.showTextPanel("Credential Reminders", b.String())
+}
+
+// formatDays renders a duration in whole days, or "<1 day"
+func formatDays(d time.Duration) string {
+	days := int(d.Hours() / 24)
+	switch days {
+	case 0:
+		return "<1 day"
+	case 1:
+		return "1 day"
+	default:
+		return fmt.Sprintf("%d days", days)
+	}
+}
+
+// describeCredentialDate formats an optional credential date, highlighting it once due
+func describeCredentialDate(date *time.Time) string {
+	if date == nil {
+		return ""
+	}
+	text := date.Local().Format("2006-01-02")
+	if !time.Now().Before(*date) {
+		return "[red]" + text + " (overdue)[white]"
+	}
+	return text
+}
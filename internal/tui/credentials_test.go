@@ -0,0 +1,28 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"sshm/internal/config"
+)
+
+func TestCredentialStatusText(t *testing.T) {
+	app := &TUIApp{config: &config.Config{Servers: []config.Server{{Name: "web1"}}}}
+	if got := app.credentialStatusText(); got != "" {
+		t.Errorf("Expected no badge without dates, got %q", got)
+	}
+
+	soon := time.Now().Add(48 * time.Hour)
+	app.config.Servers[0].Credentials = &config.CredentialDates{KeyRotationDue: &soon}
+	if got := app.credentialStatusText(); !strings.Contains(got, "1 credential(s) due") || !strings.Contains(got, "orange") {
+		t.Errorf("Expected an orange badge, got %q", got)
+	}
+
+	past := time.Now().Add(-time.Hour)
+	app.config.Servers[0].Credentials.AccountExpires = &past
+	if got := app.credentialStatusText(); !strings.Contains(got, "2 credential(s) due") || !strings.Contains(got, "red") {
+		t.Errorf("Expected a red badge once a date passed, got %q", got)
+	}
+}
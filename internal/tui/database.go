@@ -0,0 +1,60 @@
+package tui
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"sshm/internal/tunnel"
+)
+
+// launchSelectedDatabaseClient opens the tunnel to the selected server's database and
+// runs the configured client in the foreground, returning to the TUI when it exits
+func (t *TUIApp) launchSelectedDatabaseClient() {
+	if t.focusedPanel != "servers" {
+		return
+	}
+
+	serverName := t.getSelectedServerName()
+	if serverName == "" {
+		return
+	}
+
+	server, err := t.config.GetServer(serverName)
+	if err != nil {
+		t.showErrorModal(fmt.Sprintf("Server '%s' not found: %s", serverName, err.Error()))
+		return
+	}
+	if !server.IsDatabase() {
+		t.showErrorModal(fmt.Sprintf("Server '%s' has no database configured.\n\nUse 'sshm db set %s --engine <engine>' to add one.", server.Name, server.Name))
+		return
+	}
+
+	modal := tview.NewModal().
+		SetText(fmt.Sprintf("🗄️ Opening tunnel to %s database on %s...\n\nPlease wait...", server.Database.Engine, server.Name)).
+		SetBackgroundColor(tcell.ColorDarkBlue)
+	t.setRoot(modal)
+
+	serverCopy := *server
+	go func() {
+		_, command, err := t.tunnelManager.StartDatabase(serverCopy)
+		t.app.QueueUpdateDraw(func() {
+			if err != nil {
+				t.showErrorModal(fmt.Sprintf("Failed to open database tunnel: %s", err.Error()))
+				return
+			}
+
+			var runErr error
+			t.app.Suspend(func() {
+				fmt.Printf("Running: %s\n", command)
+				runErr = tunnel.ClientProcess(command).Run()
+			})
+
+			t.setRoot(t.layout)
+			t.app.SetFocus(t.serverList)
+			if runErr != nil {
+				t.showErrorModal(fmt.Sprintf("Database client exited with error: %s", runErr.Error()))
+			}
+		})
+	}()
+}
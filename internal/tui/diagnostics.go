@@ -0,0 +1,266 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"sshm/internal/config"
+	"sshm/internal/diagnostics"
+	"sshm/internal/monitor"
+	"sshm/internal/statuscache"
+)
+
+// Where diagnostic tools show their output
+const (
+	diagnosticOutputViewer = "Viewer"
+	diagnosticOutputTmux   = "tmux window"
+)
+
+// diagnosticsSession is the tmux session collecting diagnostic windows
+const diagnosticsSession = "sshm-diagnostics"
+
+// showDiagnosticsMenu lists the network diagnostics for the selected server
+func (t *TUIApp) showDiagnosticsMenu() {
+	if t.focusedPanel != "servers" {
+		return
+	}
+	serverName := t.getSelectedServerName()
+	if serverName == "" {
+		return
+	}
+	server, err := t.config.GetServer(serverName)
+	if err != nil {
+		t.showErrorModal(fmt.Sprintf("Server '%s' not found: %s", serverName, err.Error()))
+		return
+	}
+	if t.isOffline() {
+		t.showTransientStatus("[yellow]Offline mode: network diagnostics are disabled (Ctrl+N)[white]")
+		return
+	}
+
+	menu := tview.NewList().ShowSecondaryText(true)
+	menu.AddItem("Port check", "Open, closed or filtered: is the host down or only ssh blocked?", 'c', func() {
+		t.modalManager.HideModal()
+		t.showPortCheckForm()
+	})
+	menu.AddItem("DNS records", fmt.Sprintf("Resolve %s and compare with the address of the last status check", server.GetEffectiveHostname()), 'd', func() {
+		t.modalManager.HideModal()
+		t.showDNSForm(*server)
+	})
+	shortcuts := map[string]rune{diagnostics.ToolPing: 'p', diagnostics.ToolTraceroute: 't', diagnostics.ToolMTR: 'm'}
+	for _, tool := range diagnostics.Tools {
+		tool := tool
+		menu.AddItem(tool, fmt.Sprintf("Run %s against %s", tool, server.GetEffectiveHostname()), shortcuts[tool], func() {
+			t.modalManager.HideModal()
+			t.showDiagnosticForm(*server, tool)
+		})
+	}
+	menu.SetBorder(true).
+		SetTitle(fmt.Sprintf(" 🩺 Diagnostics: %s ", server.Name)).
+		SetTitleAlign(tview.AlignCenter)
+	menu.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Rune() == 'q' {
+			t.modalManager.HideModal()
+			return nil
+		}
+		return event
+	})
+	t.modalManager.ShowModal(menu)
+}
+
+// diagnosticSources lists where probes can run from: this machine, then every
+// other server that allows running commands, e.g. a bastion
+func (t *TUIApp) diagnosticSources(server config.Server) []string {
+	sources := []string{portCheckFromHere}
+	for _, other := range t.config.GetServers() {
+		if other.Name != server.Name && !other.IsRestricted() {
+			sources = append(sources, other.Name)
+		}
+	}
+	return sources
+}
+
+// diagnosticSource resolves a choice of diagnosticSources, nil for this machine
+func (t *TUIApp) diagnosticSource(name string) (*config.Server, error) {
+	if name == portCheckFromHere {
+		return nil, nil
+	}
+	return t.config.GetServer(name)
+}
+
+// showDiagnosticForm asks where to run the tool from and where to show its output
+func (t *TUIApp) showDiagnosticForm(server config.Server, tool string) {
+	form := tview.NewForm().
+		AddInputField("Target", server.GetEffectiveHostname(), 40, nil, nil).
+		AddDropDown("Run from", t.diagnosticSources(server), 0, nil).
+		AddDropDown("Output", []string{diagnosticOutputViewer, diagnosticOutputTmux}, 0, nil).
+		AddButton("Run", nil).
+		AddButton("Cancel", nil)
+	form.SetBorder(true).
+		SetTitle(fmt.Sprintf(" 🩺 %s %s ", tool, server.Name)).
+		SetTitleAlign(tview.AlignCenter)
+
+	targetField := form.GetFormItem(0).(*tview.InputField)
+	sourceDropdown := form.GetFormItem(1).(*tview.DropDown)
+	outputDropdown := form.GetFormItem(2).(*tview.DropDown)
+
+	form.GetButton(0).SetSelectedFunc(func() {
+		_, sourceName := sourceDropdown.GetCurrentOption()
+		via, err := t.diagnosticSource(sourceName)
+		if err != nil {
+			t.showErrorModal(err.Error())
+			return
+		}
+		_, output := outputDropdown.GetCurrentOption()
+		host := strings.TrimSpace(targetField.GetText())
+
+		if output == diagnosticOutputTmux {
+			if err := t.runDiagnosticInTmux(server, via, tool, host); err != nil {
+				t.showErrorModal(err.Error())
+			}
+			return
+		}
+		command, err := diagnostics.Command(tool, host, false)
+		if err != nil {
+			t.showErrorModal(err.Error())
+			return
+		}
+		t.modalManager.HideModal()
+		title := fmt.Sprintf("%s %s", tool, host)
+		if via != nil {
+			title += " from " + via.Name
+		}
+		t.startStreamViewer(title, func(ctx context.Context, onLine func(string)) error {
+			return diagnostics.Stream(ctx, via, command, onLine)
+		})
+	})
+	form.GetButton(1).SetSelectedFunc(func() {
+		t.modalManager.HideModal()
+	})
+	form.SetCancelFunc(func() {
+		t.modalManager.HideModal()
+	})
+	t.modalManager.ShowModal(form)
+}
+
+// runDiagnosticInTmux runs the tool interactively in a window of the
+// diagnostics tmux session and attaches to it
+func (t *TUIApp) runDiagnosticInTmux(server config.Server, via *config.Server, tool, host string) error {
+	if !t.tmuxManager.IsAvailable() {
+		return fmt.Errorf("tmux is not available; choose the viewer output instead")
+	}
+	command, err := diagnostics.Command(tool, host, true)
+	if err != nil {
+		return err
+	}
+	line, err := diagnostics.TerminalCommand(via, command)
+	if err != nil {
+		return err
+	}
+
+	// A new window becomes the session's active one, so the keys go there
+	if t.tmuxManager.SessionExists(diagnosticsSession) {
+		err = t.tmuxManager.CreateWindow(diagnosticsSession, fmt.Sprintf("%s-%s", tool, server.Name))
+	} else {
+		err = t.tmuxManager.CreateSession(diagnosticsSession)
+	}
+	if err != nil {
+		return err
+	}
+	if err := t.tmuxManager.SendKeys(diagnosticsSession, line); err != nil {
+		return err
+	}
+
+	t.modalManager.HideModal()
+	t.attachToSession(diagnosticsSession)
+	return nil
+}
+
+// showDNSForm asks which DNS server to resolve the server's hostname with
+func (t *TUIApp) showDNSForm(server config.Server) {
+	form := tview.NewForm().
+		AddInputField("Host", server.GetEffectiveHostname(), 40, nil, nil).
+		AddInputField("DNS server", "", 40, nil, nil).
+		AddButton("Resolve", nil).
+		AddButton("Cancel", nil)
+	form.SetBorder(true).
+		SetTitle(fmt.Sprintf(" 🩺 DNS records: %s ", server.Name)).
+		SetTitleAlign(tview.AlignCenter)
+
+	hostField := form.GetFormItem(0).(*tview.InputField)
+	resolverField := form.GetFormItem(1).(*tview.InputField)
+	resolverField.SetPlaceholder("system resolver")
+
+	form.GetButton(0).SetSelectedFunc(func() {
+		host := strings.TrimSpace(hostField.GetText())
+		if host == "" {
+			t.showErrorModal("A host is required")
+			return
+		}
+		t.modalManager.HideModal()
+		t.runDNSInspection(server, host, strings.TrimSpace(resolverField.GetText()))
+	})
+	form.GetButton(1).SetSelectedFunc(func() {
+		t.modalManager.HideModal()
+	})
+	form.SetCancelFunc(func() {
+		t.modalManager.HideModal()
+	})
+	t.modalManager.ShowModal(form)
+}
+
+// runDNSInspection resolves host in the background and shows the records
+func (t *TUIApp) runDNSInspection(server config.Server, host, resolver string) {
+	tk, ctx := t.tasks.start(taskSpec{Name: "DNS lookup of " + host, Cancellable: true, SafeToInterrupt: true})
+	lastAddress, ok := monitor.LastAddress(server.Name)
+	if !ok {
+		// Not checked in this session yet; an earlier session or the daemon may have
+		if path, err := statuscache.DefaultPath(); err == nil {
+			if cache, err := statuscache.Load(path); err == nil {
+				lastAddress = cache.Servers[server.Name].Address
+			}
+		}
+	}
+
+	go func() {
+		defer tk.Finish()
+		report, err := diagnostics.InspectDNS(ctx, host, resolver, lastAddress)
+		if ctx.Err() != nil {
+			return
+		}
+		t.app.QueueUpdateDraw(func() {
+			if err != nil {
+				t.showErrorModal(fmt.Sprintf("DNS lookup of %s failed: %s", host, err.Error()))
+				return
+			}
+			t.showTextPanel(fmt.Sprintf(" 🩺 DNS of %s ", server.Name), renderDNSReport(server.Name, report))
+		})
+	}()
+}
+
+// renderDNSReport renders the records with their TTLs and flags an address
+// change since the last status check
+func renderDNSReport(serverName string, report diagnostics.DNSReport) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "[yellow]%s[white] asked %s\n\n", tview.Escape(report.Host), tview.Escape(report.Resolver))
+	if len(report.Records) == 0 {
+		b.WriteString("  [gray]No records found[white]\n")
+	}
+	for _, record := range report.Records {
+		fmt.Fprintf(&b, "  %-6s %s → [green]%s[white] [gray]TTL %ds[white]\n",
+			record.Type, tview.Escape(record.Name), tview.Escape(record.Value), record.TTL)
+	}
+
+	switch {
+	case report.LastAddress == "":
+		fmt.Fprintf(&b, "\n[gray]No status check has connected to %s yet[white]\n", tview.Escape(serverName))
+	case report.Changed:
+		fmt.Fprintf(&b, "\n[red]⚠ The last status check connected to %s, which is not among the resolved addresses[white]\n", tview.Escape(report.LastAddress))
+	default:
+		fmt.Fprintf(&b, "\n[green]The last status check connected to %s, as resolved[white]\n", tview.Escape(report.LastAddress))
+	}
+	return b.String()
+}
@@ -0,0 +1,53 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"sshm/internal/config"
+	"sshm/internal/diagnostics"
+)
+
+func TestDiagnosticSources(t *testing.T) {
+	cfg := &config.Config{Servers: []config.Server{
+		{Name: "web1", Hostname: "10.0.0.5", Username: "deploy"},
+		{Name: "bastion", Hostname: "bastion.example.com", Username: "ops"},
+		{Name: "backup", Hostname: "backup.example.com", Username: "ops", Restricted: &config.RestrictedAccess{Command: "backup"}},
+	}}
+	app := &TUIApp{config: cfg}
+
+	sources := app.diagnosticSources(cfg.Servers[0])
+	if strings.Join(sources, ",") != portCheckFromHere+",bastion" {
+		t.Errorf("Expected this machine and bastion, got %v", sources)
+	}
+
+	via, err := app.diagnosticSource(portCheckFromHere)
+	if err != nil || via != nil {
+		t.Errorf("Expected no server for this machine, got %v, %v", via, err)
+	}
+	via, err = app.diagnosticSource("bastion")
+	if err != nil || via == nil || via.Name != "bastion" {
+		t.Errorf("Expected the bastion server, got %v, %v", via, err)
+	}
+}
+
+func TestRenderDNSReport(t *testing.T) {
+	report := diagnostics.DNSReport{
+		Host:     "www.example.com",
+		Resolver: "1.1.1.1:53",
+		Records: []diagnostics.Record{
+			{Type: diagnostics.RecordCNAME, Name: "www.example.com", Value: "web.example.com", TTL: 60},
+			{Type: diagnostics.RecordA, Name: "web.example.com", Value: "10.0.0.5", TTL: 300},
+		},
+		Addresses:   []string{"10.0.0.5"},
+		LastAddress: "10.0.0.9",
+		Changed:     true,
+	}
+
+	text := renderDNSReport("web1", report)
+	for _, expected := range []string{"asked 1.1.1.1:53", "CNAME", "web.example.com", "10.0.0.5", "TTL 300s", "10.0.0.9, which is not among"} {
+		if !strings.Contains(text, expected) {
+			t.Errorf("Expected %q in the report, got:\n%s", expected, text)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"sshm/internal/config"
+	"sshm/internal/power"
+	"sshm/internal/transfer"
+)
+
+// showDistributeForm pushes a local file to the same path on the selected
+// server or on every server of the active profile
+func (t *TUIApp) showDistributeForm() {
+	if t.focusedPanel != "servers" {
+		return
+	}
+
+	targets := []string{}
+	serverName := t.getSelectedServerName()
+	profileName := ""
+	if t.currentFilter != "" && t.currentFilter != "all" && !t.isUnassignedFilter() {
+		profileName = t.currentFilter
+		targets = append(targets, "Profile: "+profileName)
+	}
+	if serverName != "" {
+		targets = append(targets, serverName)
+	}
+	if len(targets) == 0 {
+		return
+	}
+
+	form := tview.NewForm().
+		AddDropDown("Target", targets, 0, nil).
+		AddInputField("Local file", "", 50, nil, nil).
+		AddInputField("Remote path", "", 50, nil, nil).
+		AddInputField("Post-copy command", "", 50, nil, nil).
+		AddInputField("Confirm (protected)", "", 30, nil, nil)
+	form.SetBorder(true).
+		SetTitle(" Distribute File ").
+		SetTitleAlign(tview.AlignCenter)
+
+	targetDropdown := form.GetFormItem(0).(*tview.DropDown)
+	text := func(index int) string {
+		return strings.TrimSpace(form.GetFormItem(index).(*tview.InputField).GetText())
+	}
+
+	form.AddButton("Push", func() {
+		targetIndex, _ := targetDropdown.GetCurrentOption()
+		source, err := config.ExpandPath(text(1))
+		if err != nil {
+			t.showErrorModal(err.Error())
+			return
+		}
+		distribution := transfer.Distribution{Source: source, Destination: text(2), PostCommand: text(3)}
+		if distribution.Source == "" || distribution.Destination == "" {
+			t.showErrorModal("Both the local file and the remote path are required")
+			return
+		}
+
+		target := serverName
+		var servers []config.Server
+		if targets[targetIndex] != serverName {
+			target = profileName
+			profileServers, err := t.config.GetServersByProfile(profileName)
+			if err != nil || len(profileServers) == 0 {
+				t.showErrorModal(fmt.Sprintf("No servers found in profile '%s'", profileName))
+				return
+			}
+			servers = profileServers
+		} else {
+			server, err := t.config.GetServer(serverName)
+			if err != nil {
+				t.showErrorModal(fmt.Sprintf("Server '%s' not found: %s", serverName, err.Error()))
+				return
+			}
+			servers = []config.Server{*server}
+		}
+
+		if power.RequiresTypedConfirmation(servers) && !power.ConfirmationMatches(target, text(4)) {
+			t.showErrorModal(fmt.Sprintf("'%s' includes protected servers.\n\nType '%s' in the Confirm field to proceed.", target, target))
+			return
+		}
+
+		t.modalManager.HideModal()
+		t.runDistribution(distribution, servers)
+	})
+	form.AddButton("Cancel", func() {
+		t.modalManager.HideModal()
+	})
+	form.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape {
+			t.modalManager.HideModal()
+			return nil
+		}
+		return event
+	})
+
+	t.modalManager.ShowModal(form)
+}
+
+// runDistribution pushes the file in the background and shows the outcome per server
+func (t *TUIApp) runDistribution(distribution transfer.Distribution, servers []config.Server) {
+	t.showTransientStatus(fmt.Sprintf("[yellow]Pushing %s to %d server(s)...[white]", tview.Escape(distribution.Destination), len(servers)))
+
+	op, ctx := t.tasks.start(taskSpec{Name: "distribute " + distribution.Destination, Unit: "servers", Total: len(servers), Cancellable: true})
+	go func() {
+		defer op.Finish()
+		var finished int32
+		results, err := distribution.Run(ctx, servers, func(result transfer.DistributionResult) {
+			op.Update(int(atomic.AddInt32(&finished, 1)), len(servers), result.Server)
+		})
+		t.app.QueueUpdateDraw(func() {
+			if err != nil {
+				t.showErrorModal(fmt.Sprintf("Distribution failed: %s", err.Error()))
+				return
+			}
+			t.showTextPanel(fmt.Sprintf("Distribute › %s", distribution.Destination), renderDistributionResults(results))
+		})
+	}()
+}
+
+// renderDistributionResults lists the outcome on each server, failures first
+func renderDistributionResults(results []transfer.DistributionResult) string {
+	var failed, succeeded strings.Builder
+	failures := 0
+	for _, result := range results {
+		if result.Err != nil {
+			failures++
+			fmt.Fprintf(&failed, "[red]✗ %s[white]: %s failed: %s\n", tview.Escape(result.Server), result.Stage, tview.Escape(result.Err.Error()))
+		} else {
+			fmt.Fprintf(&succeeded, "[green]✓ %s[white]: verified in %v\n", tview.Escape(result.Server), result.Duration.Round(10*time.Millisecond))
+		}
+		if result.Output != "" {
+			target := &succeeded
+			if result.Err != nil {
+				target = &failed
+			}
+			fmt.Fprintf(target, "[gray]%s[white]\n", tview.Escape(result.Output))
+		}
+	}
+
+	var b strings.Builder
+	if failures > 0 {
+		fmt.Fprintf(&b, "[yellow]%d of %d server(s) failed[white]\n\n", failures, len(results))
+	} else {
+		fmt.Fprintf(&b, "[green]In place and verified on all %d server(s)[white]\n\n", len(results))
+	}
+	b.WriteString(failed.String())
+	b.WriteString(succeeded.String())
+	b.WriteString("\n[gray]Press Enter, Escape or q to close[white]")
+	return b.String()
+}
@@ -0,0 +1,29 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"sshm/internal/transfer"
+)
+
+func TestRenderDistributionResults(t *testing.T) {
+	text := renderDistributionResults([]transfer.DistributionResult{
+		{Server: "web1", Duration: 120 * time.Millisecond, Output: "reloaded"},
+		{Server: "web2", Stage: transfer.StageVerify, Err: errors.New("checksum mismatch")},
+	})
+
+	if !strings.Contains(text, "1 of 2 server(s) failed") {
+		t.Errorf("Expected a failure summary, got %q", text)
+	}
+	failed := strings.Index(text, "web2[white]: verify failed: checksum mismatch")
+	succeeded := strings.Index(text, "web1[white]: verified in 120ms")
+	if failed < 0 || succeeded < 0 || failed > succeeded {
+		t.Errorf("Expected the failure listed before the success, got %q", text)
+	}
+	if !strings.Contains(text, "[gray]reloaded[white]") {
+		t.Errorf("Expected the post command output, got %q", text)
+	}
+}
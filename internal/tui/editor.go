@@ -0,0 +1,174 @@
+package tui
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"gopkg.in/yaml.v3"
+	"sshm/internal/config"
+)
+
+// editorArgs returns the user's editor command line from $VISUAL or $EDITOR, falling back to vi
+func editorArgs() []string {
+	for _, variable := range []string{"VISUAL", "EDITOR"} {
+		if fields := strings.Fields(os.Getenv(variable)); len(fields) > 0 {
+			return fields
+		}
+	}
+	return []string{"vi"}
+}
+
+// editSelectedServerInEditor opens the selected server as YAML in $EDITOR and
+// replaces it with the saved result, which may rename the server
+func (t *TUIApp) editSelectedServerInEditor() {
+	serverName := t.getSelectedServerName()
+	if serverName == "" {
+		return
+	}
+	server, err := t.config.GetServer(serverName)
+	if err != nil {
+		t.showErrorModal(fmt.Sprintf("Server '%s' not found: %s", serverName, err.Error()))
+		return
+	}
+	if t.config.IsIncludedServer(*server) {
+		t.showErrorModal(fmt.Sprintf("Server '%s' comes from an include and cannot be edited here", server.Name))
+		return
+	}
+
+	data, err := yaml.Marshal(server)
+	if err != nil {
+		t.showErrorModal(fmt.Sprintf("Failed to prepare server for editing: %s", err.Error()))
+		return
+	}
+	header := fmt.Sprintf("# Editing server '%s'. Save and quit to apply; empty the file to cancel.\n", server.Name)
+
+	t.editInExternalEditor("sshm-server-*.yaml", append([]byte(header), data...), func(edited []byte) error {
+		updated, err := config.ParseServerYAML(edited)
+		if err != nil {
+			return err
+		}
+		if err := t.service().UpdateServer(serverName, updated); err != nil {
+			return err
+		}
+		t.refreshServerList()
+		t.showTransientStatus(fmt.Sprintf("[green]Server '%s' updated[white]", updated.Name))
+		return nil
+	})
+}
+
+// editConfigInEditor opens the main configuration file in $EDITOR and writes it
+// back only when the result loads and validates
+func (t *TUIApp) editConfigInEditor() {
+	configPath := t.config.Path()
+	data, err := config.ReadConfigFile(configPath)
+	if err != nil && !os.IsNotExist(err) {
+		t.showErrorModal(fmt.Sprintf("Failed to read configuration: %s", err.Error()))
+		return
+	}
+
+	t.editInExternalEditor("sshm-config-*.yaml", data, func(edited []byte) error {
+		if err := config.ReplaceConfigFile(configPath, edited); err != nil {
+			return err
+		}
+		if err := t.RefreshConfig(); err != nil {
+			return err
+		}
+		t.showTransientStatus("[green]Configuration updated[white]")
+		return nil
+	})
+}
+
+// editInExternalEditor suspends the TUI to edit initial in $EDITOR and passes the
+// result to apply. When apply fails the error is shown with the choice to edit
+// the same text again or discard it. Unchanged or empty text cancels. The text,
+// which may come from an encrypted config, is kept in a private directory that
+// is removed however the edit ends.
+func (t *TUIApp) editInExternalEditor(pattern string, initial []byte, apply func([]byte) error) {
+	dir, err := os.MkdirTemp("", "sshm-edit-*")
+	if err != nil {
+		t.showErrorModal(fmt.Sprintf("Failed to create temporary file: %s", err.Error()))
+		return
+	}
+	// MkdirTemp creates the directory readable only by the user
+	cleanup := func() { os.RemoveAll(dir) }
+	file, err := os.CreateTemp(dir, pattern)
+	if err != nil {
+		cleanup()
+		t.showErrorModal(fmt.Sprintf("Failed to create temporary file: %s", err.Error()))
+		return
+	}
+	path := file.Name()
+	_, err = file.Write(initial)
+	file.Close()
+	if err != nil {
+		cleanup()
+		t.showErrorModal(fmt.Sprintf("Failed to write temporary file: %s", err.Error()))
+		return
+	}
+
+	var edit func()
+	edit = func() {
+		args := editorArgs()
+		var runErr error
+		t.app.Suspend(func() {
+			cmd := exec.Command(args[0], append(args[1:], path)...)
+			cmd.Stdin = os.Stdin
+			cmd.Stdout = os.Stdout
+			cmd.Stderr = os.Stderr
+			runErr = cmd.Run()
+		})
+		if runErr != nil {
+			cleanup()
+			t.showErrorModal(fmt.Sprintf("Editor '%s' failed: %s", strings.Join(args, " "), runErr.Error()))
+			return
+		}
+
+		edited, err := os.ReadFile(path)
+		if err != nil {
+			cleanup()
+			t.showErrorModal(fmt.Sprintf("Failed to read edited file: %s", err.Error()))
+			return
+		}
+		if bytes.Equal(edited, initial) || len(bytes.TrimSpace(edited)) == 0 {
+			cleanup()
+			t.showTransientStatus("[gray]No changes[white]")
+			return
+		}
+
+		if err := apply(edited); err != nil {
+			t.showEditErrorModal(err, edit, cleanup)
+			return
+		}
+		cleanup()
+	}
+	edit()
+}
+
+// showEditErrorModal reports an invalid edit and offers to reopen the editor.
+// Closing it any other way than "Edit again", e.g. with Escape, discards.
+func (t *TUIApp) showEditErrorModal(err error, editAgain, discard func()) {
+	again := false
+	modal := tview.NewModal().
+		SetText(fmt.Sprintf("The edit was not applied:\n\n%s", err.Error())).
+		AddButtons([]string{"Edit again", "Discard"}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			again = buttonLabel == "Edit again"
+			t.modalManager.HideModal()
+			if again {
+				editAgain()
+			}
+		}).
+		SetBackgroundColor(tcell.ColorDarkRed)
+	modal.SetTitle(" Invalid Edit ")
+	t.modalManager.ShowModal(modal)
+	t.modalManager.OnClose(modal, func() {
+		if !again {
+			discard()
+		}
+	})
+}
@@ -0,0 +1,32 @@
+package tui
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+func TestEditErrorModalDiscardsWhenClosed(t *testing.T) {
+	app := &TUIApp{app: tview.NewApplication()}
+	app.modalManager = NewModalManager(app.app, tview.NewFlex())
+
+	discarded, editedAgain := 0, 0
+	app.showEditErrorModal(errors.New("invalid"), func() { editedAgain++ }, func() { discarded++ })
+
+	// Escape is handled globally, hiding the modal without going through its buttons
+	app.modalManager.HideModal()
+	if discarded != 1 || editedAgain != 0 {
+		t.Errorf("Expected Escape to discard the edit once, got %d discards and %d edits", discarded, editedAgain)
+	}
+
+	app.showEditErrorModal(errors.New("invalid"), func() { editedAgain++ }, func() { discarded++ })
+	modal := app.modalManager.GetCurrentModal().(*tview.Modal)
+	modal.SetFocus(0)
+	handler := modal.InputHandler()
+	handler(tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone), func(tview.Primitive) {})
+	if editedAgain != 1 || discarded != 1 {
+		t.Errorf("Expected Edit again to keep the edit, got %d discards and %d edits", discarded, editedAgain)
+	}
+}
@@ -0,0 +1,36 @@
+package tui
+
+import (
+	"sshm/internal/events"
+)
+
+// eventEmitter receives the events of new TUI instances; nil drops them
+var eventEmitter *events.Emitter
+
+// SetEventEmitter streams the TUI's events (sshm tui --events). It must be
+// called before NewTUIApp.
+func SetEventEmitter(emitter *events.Emitter) {
+	eventEmitter = emitter
+}
+
+// emitServerSelected reports the server in the selected row, once per change
+func (t *TUIApp) emitServerSelected(row int) {
+	if t.events == nil || row <= 0 {
+		return
+	}
+	cell := t.serverList.GetCell(row, 0)
+	if cell == nil || cell.Text == "" || cell.Text == t.lastSelectedEvent {
+		return
+	}
+	t.lastSelectedEvent = cell.Text
+	t.events.Emit(events.Event{Type: events.ServerSelected, Server: cell.Text})
+}
+
+// writeConfig saves the configuration to disk and reports it
+func (t *TUIApp) writeConfig() error {
+	if err := t.config.Save(); err != nil {
+		return err
+	}
+	t.events.Emit(events.Event{Type: events.ConfigSaved, Path: t.config.Path()})
+	return nil
+}
@@ -0,0 +1,34 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// archiveExpiredServers moves scratch servers whose expiry has passed into the
+// archive while the TUI is running, saving the configuration when any were archived
+func (t *TUIApp) archiveExpiredServers() {
+	if t.config == nil {
+		return
+	}
+
+	archived := t.config.ArchiveExpired(time.Now())
+	if len(archived) == 0 {
+		return
+	}
+
+	t.statusMutex.Lock()
+	for _, name := range archived {
+		delete(t.connectionStatus, name)
+		delete(t.statusStats, name)
+	}
+	t.statusMutex.Unlock()
+
+	if err := t.saveConfig(); err != nil {
+		return
+	}
+	if t.statusBar != nil {
+		t.showTransientStatus(fmt.Sprintf("[orange]⏳ Archived expired server(s): %s[white]", strings.Join(archived, ", ")))
+	}
+}
@@ -948,6 +948,8 @@ type ModalManager struct {
 	app        *tview.Application
 	layout     *tview.Flex
 	modalStack []tview.Primitive
+	onClose    map[tview.Primitive]func() // Called when the modal is hidden
+	setRoot    func(tview.Primitive) bool // Shows a root unless the TUI is locked; nil shows it directly
 }
 
 // NewModalManager creates a new modal manager
@@ -962,8 +964,20 @@ func NewModalManager(app *tview.Application, layout *tview.Flex) *ModalManager {
 // ShowModal displays a modal on top of the current interface
 func (mm *ModalManager) ShowModal(modal tview.Primitive) {
 	mm.modalStack = append(mm.modalStack, modal)
-	mm.app.SetRoot(modal, true)
-	mm.app.SetFocus(modal)
+	mm.show(modal)
+}
+
+// show makes p the root and focuses it. While the TUI is locked it is only
+// recorded, to be shown once unlocked.
+func (mm *ModalManager) show(p tview.Primitive) {
+	if mm.setRoot != nil {
+		if !mm.setRoot(p) {
+			return
+		}
+	} else {
+		mm.app.SetRoot(p, true)
+	}
+	mm.app.SetFocus(p)
 }
 
 // HideModal hides the current modal and returns to the previous one or main interface
@@ -973,17 +987,33 @@ func (mm *ModalManager) HideModal() {
 	}
 	
 	// Remove the current modal
+	closed := mm.modalStack[len(mm.modalStack)-1]
 	mm.modalStack = mm.modalStack[:len(mm.modalStack)-1]
+	mm.closed(closed)
 	
 	if len(mm.modalStack) > 0 {
 		// Show the previous modal
-		previousModal := mm.modalStack[len(mm.modalStack)-1]
-		mm.app.SetRoot(previousModal, true)
-		mm.app.SetFocus(previousModal)
+		mm.show(mm.modalStack[len(mm.modalStack)-1])
 	} else {
 		// Return to main layout
-		mm.app.SetRoot(mm.layout, true)
-		mm.app.SetFocus(mm.layout)
+		mm.show(mm.layout)
+	}
+}
+
+// OnClose registers fn to run once modal is hidden, however it gets closed,
+// e.g. to stop work feeding a viewer
+func (mm *ModalManager) OnClose(modal tview.Primitive, fn func()) {
+	if mm.onClose == nil {
+		mm.onClose = make(map[tview.Primitive]func())
+	}
+	mm.onClose[modal] = fn
+}
+
+// closed runs the close callback registered for a modal that was hidden
+func (mm *ModalManager) closed(modal tview.Primitive) {
+	if fn, ok := mm.onClose[modal]; ok {
+		delete(mm.onClose, modal)
+		fn()
 	}
 }
 
@@ -1002,9 +1032,12 @@ func (mm *ModalManager) GetCurrentModal() tview.Primitive {
 
 // ClearAllModals closes all modals and returns to the main interface
 func (mm *ModalManager) ClearAllModals() {
+	closed := mm.modalStack
 	mm.modalStack = make([]tview.Primitive, 0)
-	mm.app.SetRoot(mm.layout, true)
-	mm.app.SetFocus(mm.layout)
+	for i := len(closed) - 1; i >= 0; i-- {
+		mm.closed(closed[i])
+	}
+	mm.show(mm.layout)
 }
 
 // ShowInfoModal displays an informational modal with title and message
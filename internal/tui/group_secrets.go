@@ -0,0 +1,46 @@
+package tui
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"sshm/internal/secrets"
+)
+
+// showGroupSecretsForm asks for every password and key passphrase a group
+// connect needs and the keyring doesn't have, in one form, then calls
+// onConnect. Secrets left empty are typed in their windows instead.
+func (t *TUIApp) showGroupSecretsForm(profileName string, collected *secrets.Collected, onConnect func()) {
+	form := tview.NewForm()
+	for _, request := range collected.Missing {
+		form.AddPasswordField(request.Label(), "", 30, '*', nil)
+	}
+	form.SetBorder(true).
+		SetTitle(fmt.Sprintf(" 🔑 Secrets for %s ", profileName)).
+		SetTitleAlign(tview.AlignCenter)
+
+	form.AddButton("Connect", func() {
+		for i, request := range collected.Missing {
+			collected.Provide(request, form.GetFormItem(i).(*tview.InputField).GetText())
+		}
+		t.modalManager.HideModal()
+		onConnect()
+	})
+	form.AddButton("Type in windows", func() {
+		t.modalManager.HideModal()
+		onConnect()
+	})
+	form.AddButton("Cancel", func() {
+		t.modalManager.HideModal()
+	})
+	form.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape {
+			t.modalManager.HideModal()
+			return nil
+		}
+		return event
+	})
+
+	t.modalManager.ShowModal(form)
+}
@@ -0,0 +1,248 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"sshm/internal/config"
+	"sshm/internal/monitor"
+)
+
+// healthRefreshInterval is how often the open health view redraws from the cache
+const healthRefreshInterval = 2 * time.Second
+
+// healthBannerWidth caps the banner column, which shows the first line only
+const healthBannerWidth = 40
+
+// serverHealth is what status checks learned about a server beyond its status,
+// kept next to connectionStatus for the health view
+type serverHealth struct {
+	CheckedAt     time.Time
+	LastOnline    time.Time // Last check that found the server online
+	AuthMethod    string    // Method of the auth chain that succeeded
+	ServerVersion string    // e.g. "SSH-2.0-OpenSSH_9.6"
+	Banner        string
+}
+
+// recordHealth updates a server's health record after a check. The caller holds statusMutex.
+func (t *TUIApp) recordHealth(server config.Server, status string, now time.Time) {
+	health := t.statusHealth[server.Name]
+	health.CheckedAt = now
+	if status == "online" {
+		health.LastOnline = now
+	}
+	if t.isStealth(server) {
+		health.AuthMethod = "tcp only"
+	} else if result, ok := monitor.LastAuthResult(server.Name); ok && result.Succeeded != nil {
+		health.AuthMethod = result.Succeeded.String()
+	}
+	if probe, ok := monitor.LastProbe(server.Name); ok {
+		health.ServerVersion = probe.ServerVersion
+		health.Banner = probe.Banner
+	}
+	t.statusHealth[server.Name] = health
+}
+
+// healthRow is one server of the health view
+type healthRow struct {
+	Name    string
+	Status  string
+	Latency time.Duration
+	Health  serverHealth
+}
+
+// healthRows reads every server's status and health record from the cache
+func (t *TUIApp) healthRows() []healthRow {
+	servers := t.config.GetServers()
+	t.statusMutex.RLock()
+	rows := make([]healthRow, 0, len(servers))
+	for _, server := range servers {
+		status, ok := t.connectionStatus[server.Name]
+		if !ok {
+			status = "unknown"
+		}
+		rows = append(rows, healthRow{
+			Name:    server.Name,
+			Status:  status,
+			Latency: t.statusLatency[server.Name],
+			Health:  t.statusHealth[server.Name],
+		})
+	}
+	t.statusMutex.RUnlock()
+	sort.Slice(rows, func(i, j int) bool { return rows[i].Name < rows[j].Name })
+	return rows
+}
+
+// toggleHealthView opens the health view, or closes it when it is open
+func (t *TUIApp) toggleHealthView() {
+	if t.healthViewClose != nil {
+		t.healthViewClose()
+		return
+	}
+	t.showHealthView()
+}
+
+// showHealthView shows latency, last time online, the auth method used and the
+// SSH version and banner of every server, redrawn while status checks run in
+// the background
+func (t *TUIApp) showHealthView() {
+	table := tview.NewTable().
+		SetSelectable(true, false).
+		SetFixed(1, 1)
+	table.SetBorder(true).
+		SetBorderColor(tcell.ColorYellow)
+
+	footer := tview.NewTextView().
+		SetDynamicColors(true).
+		SetText("[gray]r: check all now  •  l: details  •  +/Escape/q: close[white]")
+
+	var rows []healthRow
+	render := func() {
+		rows = t.healthRows()
+		online := renderHealthTable(table, rows, time.Now())
+		table.SetTitle(fmt.Sprintf(" Health: %d/%d online ", online, len(rows)))
+	}
+	render()
+
+	panel := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(table, 0, 1, true).
+		AddItem(footer, 1, 0, false)
+
+	stop := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(healthRefreshInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				t.app.QueueUpdateDraw(render)
+			}
+		}
+	}()
+
+	t.healthViewClose = func() {
+		close(stop)
+		t.healthViewClose = nil
+		t.modalManager.HideModal()
+	}
+
+	panel.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case event.Key() == tcell.KeyEscape, event.Rune() == 'q', event.Rune() == 'Q', event.Rune() == '+':
+			t.healthViewClose()
+			return nil
+		case event.Rune() == 'r' || event.Rune() == 'R':
+			if t.isOffline() {
+				footer.SetText("[yellow]Offline mode: press Ctrl+N to check servers again[white]")
+				return nil
+			}
+			footer.SetText("[yellow]Checking all servers...[white]")
+			go func() {
+				t.updateAllConnectionStatus()
+				t.app.QueueUpdateDraw(func() {
+					render()
+					footer.SetText("[gray]r: check all now  •  l: details  •  +/Escape/q: close[white]")
+				})
+			}()
+			return nil
+		case event.Rune() == 'l' || event.Rune() == 'L':
+			row, _ := table.GetSelection()
+			if row >= 1 && row <= len(rows) {
+				server, err := t.config.GetServer(rows[row-1].Name)
+				if err != nil {
+					return nil
+				}
+				t.healthViewClose()
+				t.showTextPanel(fmt.Sprintf("Server › %s", server.Name), t.renderServerDetails(*server))
+			}
+			return nil
+		}
+		return event
+	})
+
+	t.modalManager.ShowModal(panel)
+}
+
+// renderHealthTable fills the table with one row per server and returns how many are online
+func renderHealthTable(table *tview.Table, rows []healthRow, now time.Time) int {
+	selected, _ := table.GetSelection()
+	table.Clear()
+	for col, header := range []string{"Server", "Status", "Latency", "Last online", "Checked", "Auth", "SSH version", "Banner"} {
+		table.SetCell(0, col, tview.NewTableCell(header).
+			SetTextColor(tcell.ColorYellow).
+			SetSelectable(false))
+	}
+
+	online := 0
+	for i, row := range rows {
+		statusColor := tcell.ColorRed
+		latency := "-"
+		switch row.Status {
+		case "online":
+			online++
+			statusColor = tcell.ColorGreen
+			latency = row.Latency.Round(time.Millisecond).String()
+		case "checking", "unknown", offlineStatus, statusScheduled:
+			statusColor = tcell.ColorGray
+		}
+
+		cells := []*tview.TableCell{
+			tview.NewTableCell(tview.Escape(row.Name)),
+			tview.NewTableCell(row.Status).SetTextColor(statusColor),
+			tview.NewTableCell(latency),
+			tview.NewTableCell(formatAgo(row.Health.LastOnline, now)),
+			tview.NewTableCell(formatAgo(row.Health.CheckedAt, now)).SetTextColor(tcell.ColorLightGray),
+			tview.NewTableCell(tview.Escape(orDash(row.Health.AuthMethod))),
+			tview.NewTableCell(tview.Escape(orDash(strings.TrimPrefix(row.Health.ServerVersion, "SSH-2.0-")))),
+			tview.NewTableCell(tview.Escape(orDash(bannerSummary(row.Health.Banner)))).SetTextColor(tcell.ColorLightGray).SetExpansion(1),
+		}
+		for col, cell := range cells {
+			table.SetCell(i+1, col, cell)
+		}
+	}
+
+	if selected < 1 || selected > len(rows) {
+		selected = 1
+	}
+	table.Select(selected, 0)
+	return online
+}
+
+// formatAgo renders how long ago a time was, or "never" for the zero time
+func formatAgo(at, now time.Time) string {
+	if at.IsZero() {
+		return "never"
+	}
+	elapsed := now.Sub(at)
+	switch {
+	case elapsed < time.Minute:
+		return fmt.Sprintf("%ds ago", int(elapsed.Seconds()))
+	case elapsed < time.Hour:
+		return fmt.Sprintf("%dm ago", int(elapsed.Minutes()))
+	case elapsed < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(elapsed.Hours()))
+	default:
+		return fmt.Sprintf("%dd ago", int(elapsed.Hours()/24))
+	}
+}
+
+// bannerSummary returns the first non-empty line of a banner, shortened to the column width
+func bannerSummary(banner string) string {
+	for _, line := range strings.Split(banner, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if runes := []rune(line); len(runes) > healthBannerWidth {
+			return string(runes[:healthBannerWidth-1]) + "…"
+		}
+		return line
+	}
+	return ""
+}
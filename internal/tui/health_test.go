@@ -0,0 +1,71 @@
+package tui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rivo/tview"
+)
+
+func TestFormatAgo(t *testing.T) {
+	now := time.Date(2025, 1, 2, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		at   time.Time
+		want string
+	}{
+		{time.Time{}, "never"},
+		{now.Add(-5 * time.Second), "5s ago"},
+		{now.Add(-3 * time.Minute), "3m ago"},
+		{now.Add(-2 * time.Hour), "2h ago"},
+		{now.Add(-50 * time.Hour), "2d ago"},
+	}
+	for _, tt := range tests {
+		if got := formatAgo(tt.at, now); got != tt.want {
+			t.Errorf("formatAgo(%v) = %q, want %q", tt.at, got, tt.want)
+		}
+	}
+}
+
+func TestBannerSummary(t *testing.T) {
+	if got := bannerSummary("\n  Authorized use only  \nSecond line"); got != "Authorized use only" {
+		t.Errorf("bannerSummary() = %q, want first non-empty line", got)
+	}
+	long := "This banner is far longer than the column of the health view allows"
+	got := []rune(bannerSummary(long))
+	if len(got) != healthBannerWidth || got[len(got)-1] != '…' {
+		t.Errorf("bannerSummary() = %q, want %d runes ending in an ellipsis", string(got), healthBannerWidth)
+	}
+	if got := bannerSummary(""); got != "" {
+		t.Errorf("bannerSummary(\"\") = %q, want empty", got)
+	}
+}
+
+func TestRenderHealthTable(t *testing.T) {
+	now := time.Now()
+	rows := []healthRow{
+		{Name: "db", Status: "online", Latency: 12 * time.Millisecond, Health: serverHealth{
+			CheckedAt: now, LastOnline: now, AuthMethod: "key", ServerVersion: "SSH-2.0-OpenSSH_9.6",
+		}},
+		{Name: "web", Status: "offline", Health: serverHealth{CheckedAt: now}},
+	}
+
+	table := tview.NewTable()
+	if online := renderHealthTable(table, rows, now); online != 1 {
+		t.Errorf("renderHealthTable() online = %d, want 1", online)
+	}
+	if table.GetRowCount() != 3 {
+		t.Fatalf("row count = %d, want header plus 2", table.GetRowCount())
+	}
+	checks := map[[2]int]string{
+		{1, 2}: "12ms",
+		{1, 6}: "OpenSSH_9.6",
+		{2, 2}: "-",
+		{2, 3}: "never",
+		{2, 5}: "-",
+	}
+	for pos, want := range checks {
+		if got := table.GetCell(pos[0], pos[1]).Text; got != want {
+			t.Errorf("cell %v = %q, want %q", pos, got, want)
+		}
+	}
+}
@@ -42,7 +42,39 @@ func (h *HelpSystem) getServersHelpContent() string {
 [white::b]🚀 Server Management:[white::-]
 [yellow]a[white]: Add new server with connection details
 [yellow]e[white]: Edit selected server configuration
-[yellow]d[white]: Delete selected server (with confirmation)
+[yellow]d[white]: Delete or archive selected server, showing what it affects
+[yellow]l[white]: Show selected server details
+[yellow];[white]: Runbooks: markdown files in runbooks.dir about the selected server, / searches all of them
+[yellow]*[white]: Recordings of the sessions of servers with record set, newest first: Enter plays, d deletes
+[yellow].[white] or right-click: Context menu with every action for the selected server and its custom actions
+[yellow]Ctrl+W[white]: Watch selected server (fast status checks, shown in the watch list)
+[yellow]Ctrl+P[white]: Reboot, shut down or run a command on the selected server or profile; Last results re-runs failed servers
+[yellow]:[white]: Run a command on every server of a profile in parallel, streaming each server's output; x cancels, r shows the results
+[yellow]Ctrl+U[white]: Audit pending package updates on the active profile (e exports CSV)
+[yellow]@[white]: Audit authorized_keys on the active profile: which keys grant access where, unknown keys first (e exports CSV)
+[yellow]Ctrl+D[white]: Diff the inventory against a backup or exported file
+[yellow]Ctrl+L[white]: Tail a remote file (tail -F) in a viewer: Space pauses, / searches, n/N jump between matches
+[yellow]Ctrl+X[white]: Transfer files: local and remote panes, c/m copy or move the selected file, queued with resume
+[yellow]Ctrl+G[white]: Diagnostics: port check, DNS records, ping, traceroute or mtr from here or another server, in a viewer or tmux window
+[yellow]Ctrl+K[white]: Custom actions defined under 'actions:' for the selected server, profile or session
+[yellow]Ctrl+E[white]: Edit the selected server as YAML in $EDITOR
+%s[yellow]Ctrl+N[white]: Toggle offline mode (no status or update checks, for air-gapped use)
+[yellow]Ctrl+T[white]: Inventory statistics (servers by auth type, profile and tag, availability, usage, hours per server per week)
+[yellow]Space[white]: Mark server for compare; Space on a second server shows both side by side
+[yellow]#[white]: Add/remove a tag or set metadata on every server matching the current search and profile
+[yellow]$[white]: Rename every server matching the current search and profile with a find/replace or a template like aws-{name}
+[yellow]h[white]: Cycle view: table, tree by profile, tree by tag (←/→ collapse/expand groups)
+[yellow]%%[white]: Latency map: servers bucketed by latency and by region:<name> tag
+[yellow]&[white]: Tasks: running background tasks with progress, x cancels one
+[yellow]![white]: Credential reminders: key rotations and account expiries due soon (badge in the status bar)
+[yellow]+[white]: Health view: latency, last online, auth method, SSH version and banner of every server, refreshed in the background
+[yellow]=[white]: Sync with ~/.ssh/config: import new Host blocks, write sshm's servers to a managed section
+[yellow]<[white]/[yellow]>[white]: Shrink/widen the server list
+[yellow]f[white]: Start the selected server's tunnels
+[yellow]t[white]: Show tunnels panel (o: open, r: restart, x: stop)
+[yellow]g[white]: Open the selected server's forwarded web service in the browser
+[yellow]Ctrl+S[white]: Start/stop a SOCKS proxy through the selected server for browser traffic (shown in the status bar, writes a PAC file)
+[yellow]n[white]: Launch the database client for the selected server
 [yellow]Enter[white]: Connect to server via SSH/tmux
 
 [white::b]📁 Profile Navigation:[white::-]
@@ -55,7 +87,7 @@ func (h *HelpSystem) getServersHelpContent() string {
 [yellow]c[white]: Create new profile
 [yellow]o[white]: Edit current profile name/description
 [yellow]x[white]: Delete current profile (with confirmation)
-[yellow]i[white]: Assign server to current profile
+[yellow]i[white]: Assign server to current profile (on the Unassigned tab: suggest profiles)
 [yellow]u[white]: Unassign server from current profile
 
 [white::b]🧭 Navigation:[white::-]
@@ -80,10 +112,20 @@ Server Count: [aqua]%d[white] 🖥️
 [green]•[white] [yellow]b[white] connects all servers in profile as group session
 
 [lime]Press [white]?[lime] or [white]Enter[lime] or [white]Escape[white] to close • [lime]g[white] General • [lime]s[white] Shortcuts`,
+		h.editConfigHelpLine(),
 		h.getCurrentProfileName(),
 		h.getVisibleServerCount())
 }
 
+// editConfigHelpLine describes Ctrl+O, which is unavailable while the
+// configuration is locked
+func (h *HelpSystem) editConfigHelpLine() string {
+	if h.app.config != nil && h.app.config.Locked {
+		return ""
+	}
+	return "[yellow]Ctrl+O[white]: Edit the whole configuration file in $EDITOR (validated before saving)\n"
+}
+
 // getSessionsHelpContent returns help content specific to the sessions panel
 func (h *HelpSystem) getSessionsHelpContent() string {
 	return fmt.Sprintf(`[yellow::b]🔗 SSHM Help - Sessions Panel  🔗[::-]
@@ -92,8 +134,19 @@ func (h *HelpSystem) getSessionsHelpContent() string {
 [yellow]Enter[white]: Attach to session (suspend TUI)
 [yellow]y[white]: Kill selected session
 [yellow]z[white]: Cleanup orphaned sessions
+[yellow]Ctrl+R[white]: Repair session: reconnect windows whose SSH connection died
+[yellow]Ctrl+Y[white]: Invite a teammate: share the session's tmux socket and show the join commands
+[yellow]~[white]: Watch the session's output for a pattern (e.g. ERROR) and notify on a match; ~ again stops
+[yellow]*[white]: Recordings of sessions ('sshm recordings enable <server>' records new ones): Enter plays, d deletes
+[yellow]Ctrl+K[white]: Custom actions defined under 'actions:' with scope: session
+[yellow].[white] or right-click: Context menu with every action for the selected session
+[yellow]Ctrl+F[white]: Forgotten sessions: detached and idle past ui.session_reminder_minutes, Enter attaches, k kills
 [yellow]r[white]: Refresh session list manually
 
+[white::b]⚠ Connection Lost:[white::-]
+Sessions whose SSH connection dropped (broken pipe, timeout) are shown as
+[fuchsia]connection lost[white]; [yellow]Enter[white] offers to reconnect them before attaching
+
 [white::b]🧭 Navigation:[white::-]
 [yellow]↑/↓, j/k[white]: Move up/down in session list
 [yellow]s[white]: Switch focus to Servers panel
@@ -105,6 +158,7 @@ func (h *HelpSystem) getSessionsHelpContent() string {
 [yellow]🟡 attached[white]: One client connected
 [orange]🟠 multi-attached[white]: Multiple clients
 [red]🔴 inactive[white]: Connection issues
+[gray]'sshm settings status --palette colorblind --symbols' adds ✓ ~ ! ✗ marks and colorblind-safe colors[white]
 
 [white::b]📊 Current Context:[white::-]
 Active Sessions: [aqua]%d[white] 🔗
@@ -134,7 +188,8 @@ func (h *HelpSystem) getGeneralHelpContent() string {
 [yellow]?[white]: Show context-sensitive help
 
 [white::b]⌨️  Global Shortcuts:[white::-]
-[yellow]q / Ctrl+C[white]: Quit application safely
+[yellow]q[white]: Quit (asks first while operations or tunnels are running)
+[yellow]Ctrl+C[white]: Quit immediately
 [yellow]?[white]: Show/hide help system
 [yellow]r[white]: Refresh all data
 [yellow]s[white]: Switch between panels
@@ -230,6 +285,10 @@ func (h *HelpSystem) displayHelpModal(content string) {
 			// Close help
 			h.closeHelpModal()
 			return nil
+		case 'c', 'C':
+			// Open the help text in copy mode for scrolling and copying
+			h.app.showCopyMode("Help", content)
+			return nil
 		}
 
 		return event
@@ -239,7 +298,7 @@ func (h *HelpSystem) displayHelpModal(content string) {
 	if h.app.modalManager != nil {
 		h.app.modalManager.ShowModal(modal)
 	} else {
-		h.app.app.SetRoot(modal, true)
+		h.app.setRoot(modal)
 		h.app.app.SetFocus(modal)
 	}
 }
@@ -249,12 +308,15 @@ func (h *HelpSystem) getShortcutsReference() string {
 	return `[yellow::b]⌨️  SSHM TUI - Keyboard Shortcuts Reference  ⌨️[::-]
 
 [white::b]🌐 Global Shortcuts (work anywhere):[white::-]
-[yellow]q / Ctrl+C[white]: Quit application safely
+[yellow]q[white]: Quit (asks first while operations or tunnels are running)
+[yellow]Ctrl+C[white]: Quit immediately
 [yellow]?[white]: Show context-sensitive help
 [yellow]r[white]: Refresh all data from disk
 [yellow]s[white]: Switch focus between panels
 [yellow]v[white]: View connection history dashboard
 [yellow]Escape[white]: Cancel/close modals and dialogs
+[yellow].[white] or right-click: Context menu for the selected server or session
+[yellow]c[white]: Copy mode in help and detail panels (j/k move, v select, y copy)
 
 [white::b]🖥️  Servers Panel Navigation:[white::-]
 [yellow]↑/↓ or j/k[white]: Navigate up/down in server list
@@ -267,7 +329,7 @@ func (h *HelpSystem) getShortcutsReference() string {
 [yellow]a[white]: Add new server configuration
 [yellow]e[white]: Edit selected server details
 [yellow]d[white]: Delete server (with confirmation)
-[yellow]i[white]: Assign server to current profile
+[yellow]i[white]: Assign server to current profile (on the Unassigned tab: suggest profiles)
 [yellow]u[white]: Unassign server from profile
 
 [white::b]📋 Profile Operations:[white::-]
@@ -281,6 +343,9 @@ func (h *HelpSystem) getShortcutsReference() string {
 [yellow]Enter[white]: Attach to session (suspend TUI)
 [yellow]y[white]: Kill selected session
 [yellow]z[white]: Cleanup orphaned sessions
+[yellow]Ctrl+R[white]: Repair session: reconnect windows whose SSH connection died
+[yellow]Ctrl+Y[white]: Invite a teammate to the session (Clients column shows who joined)
+[yellow]~[white]: Watch the session's output for a pattern and notify on a match
 [yellow]Home/End[white]: Jump to first/last session
 
 [white::b]📁 Configuration Management:[white::-]
@@ -319,6 +384,9 @@ func (h *HelpSystem) getVisibleServerCount() int {
 	if h.app.currentFilter == "" {
 		return len(h.app.config.GetServers())
 	}
+	if h.app.isUnassignedFilter() {
+		return len(h.app.config.UnassignedServers())
+	}
 	servers, err := h.app.config.GetServersByProfile(h.app.currentFilter)
 	if err != nil {
 		return 0
@@ -356,7 +424,7 @@ func (h *HelpSystem) closeHelpModal() {
 	if h.app.modalManager != nil {
 		h.app.modalManager.HideModal()
 	} else {
-		h.app.app.SetRoot(h.app.layout, true)
+		h.app.setRoot(h.app.layout)
 		h.app.app.SetFocus(h.app.layout)
 	}
 }
@@ -387,7 +455,7 @@ Press [lime]?[white] for detailed help`
 	if h.app.modalManager != nil {
 		h.app.modalManager.ShowModal(modal)
 	} else {
-		h.app.app.SetRoot(modal, true)
+		h.app.setRoot(modal)
 		h.app.app.SetFocus(modal)
 	}
 }
@@ -663,11 +663,11 @@ func (hd *HistoryDashboard) showFilterModal() {
 
 		// Refresh data and close modal
 		hd.refreshData()
-		hd.app.SetRoot(hd.layout, true)
+		hd.setRoot(hd.layout)
 	})
 
 	form.AddButton("Cancel", func() {
-		hd.app.SetRoot(hd.layout, true)
+		hd.setRoot(hd.layout)
 	})
 
 	// Add input capture for escape key and enhance dropdown navigation
@@ -675,7 +675,7 @@ func (hd *HistoryDashboard) showFilterModal() {
 		switch event.Key() {
 		case tcell.KeyEscape:
 			// Close form without applying
-			hd.app.SetRoot(hd.layout, true)
+			hd.setRoot(hd.layout)
 			return nil
 		case tcell.KeyEnter:
 			// Let the form handle Enter for dropdowns and buttons
@@ -695,7 +695,7 @@ func (hd *HistoryDashboard) showFilterModal() {
 	})
 
 	// Show the form and set focus
-	hd.app.SetRoot(form, true)
+	hd.setRoot(form)
 	hd.app.SetFocus(form)
 }
 
@@ -741,7 +741,7 @@ View Mode: [aqua]%s[white] 👁️
 		SetText(helpText).
 		AddButtons([]string{"Close"}).
 		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
-			hd.app.SetRoot(hd.layout, true)
+			hd.setRoot(hd.layout)
 		}).
 		SetBackgroundColor(tcell.ColorDarkBlue)
 
@@ -749,13 +749,13 @@ View Mode: [aqua]%s[white] 👁️
 	modal.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
 		switch event.Key() {
 		case tcell.KeyEnter, tcell.KeyEscape:
-			hd.app.SetRoot(hd.layout, true)
+			hd.setRoot(hd.layout)
 			return nil
 		}
 		return event
 	})
 
-	hd.app.SetRoot(modal, true)
+	hd.setRoot(modal)
 }
 
 // GetLayout returns the dashboard layout for embedding
@@ -829,12 +829,22 @@ func (hd *HistoryDashboard) getAvailableProfiles() []string {
 	return profiles
 }
 
+// setRoot shows p through the parent TUI, which keeps it behind the lock screen
+// while locked
+func (hd *HistoryDashboard) setRoot(p tview.Primitive) {
+	if hd.parentTUI != nil {
+		hd.parentTUI.setRoot(p)
+		return
+	}
+	hd.app.SetRoot(p, true)
+}
+
 // closeDashboard closes the dashboard and returns to main TUI
 func (hd *HistoryDashboard) closeDashboard() {
 	hd.Close()
 	// Return to parent TUI
 	if hd.parentTUI != nil {
-		hd.app.SetRoot(hd.parentTUI.layout, true)
+		hd.setRoot(hd.parentTUI.layout)
 		hd.app.SetFocus(hd.parentTUI.layout)
 		// Restore the global input capture for the main TUI
 		hd.parentTUI.setupKeyBindings()
@@ -0,0 +1,108 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"sshm/internal/config"
+	"sshm/internal/hostkey"
+)
+
+// confirmHostIdentity compares the host keys the servers of a new session
+// present with those seen at earlier connections, before the session opens. A
+// changed protected server only connects once the user trusts its new keys;
+// changes of other servers stay in the status bar until their keys are rotated.
+// It reports whether to connect and blocks until the user answers, so run it
+// off the UI goroutine.
+func (t *TUIApp) confirmHostIdentity(servers []config.Server) bool {
+	if t.connectionManager == nil || t.connectionManager.GetHistoryManager() == nil {
+		return true
+	}
+	history := t.connectionManager.GetHistoryManager()
+	defer t.app.QueueUpdateDraw(t.refreshHostKeyWarnings)
+
+	for _, server := range servers {
+		change, err := hostkey.ObserveIdentity(history, server)
+		if err != nil || change == nil || !server.Protected {
+			continue
+		}
+		trusted := make(chan bool, 1)
+		t.app.QueueUpdateDraw(func() {
+			t.showHostIdentityModal(*change, trusted)
+		})
+		if !<-trusted {
+			return false
+		}
+	}
+	return true
+}
+
+// showHostIdentityModal warns that a protected server presented other host keys
+// and sends whether the user trusts them. Closing it any other way than trusting,
+// e.g. with Escape, cancels the connection.
+func (t *TUIApp) showHostIdentityModal(change hostkey.IdentityChange, trusted chan<- bool) {
+	text := fmt.Sprintf("⚠ HOST IDENTITY CHANGED\n\n%s\n\nSomeone may be intercepting the connection, or the host was reinstalled. "+
+		"Don't connect before checking with the host's owner.", change.Summary())
+
+	answered := false
+	modal := tview.NewModal().
+		SetText(text).
+		AddButtons([]string{"Cancel connection", "Trust new keys"}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			switch buttonLabel {
+			case "Cancel connection":
+				t.modalManager.HideModal()
+				t.setRoot(t.layout)
+				t.app.SetFocus(t.serverList)
+			case "Trust new keys":
+				answered = true
+				t.modalManager.HideModal()
+				if err := hostkey.AcceptIdentity(t.connectionManager.GetHistoryManager(), change.Server, change.Host, change.Current); err != nil {
+					t.showErrorModal(fmt.Sprintf("Failed to record the new host keys: %s", err.Error()))
+					trusted <- false
+					return
+				}
+				trusted <- true
+			}
+		}).
+		SetBackgroundColor(tcell.ColorDarkRed)
+	modal.SetTitle(" Host identity changed ")
+	t.modalManager.ShowModal(modal)
+	t.modalManager.OnClose(modal, func() {
+		if answered {
+			return
+		}
+		answered = true
+		t.showTransientStatus(fmt.Sprintf("[yellow]Connection to %s cancelled[white]", tview.Escape(change.Server)))
+		select {
+		case trusted <- false:
+		default:
+		}
+	})
+}
+
+// refreshHostKeyWarnings reloads the servers whose changed host keys weren't
+// trusted yet, shown in the status bar until they are
+func (t *TUIApp) refreshHostKeyWarnings() {
+	if t.connectionManager == nil || t.connectionManager.GetHistoryManager() == nil {
+		return
+	}
+	names, err := hostkey.UnacceptedChanges(t.connectionManager.GetHistoryManager())
+	if err != nil {
+		return
+	}
+	t.hostKeyWarnings = names
+	if t.serverList != nil {
+		t.updateStatusBar(t.serverList.GetRowCount() - 1)
+	}
+}
+
+// hostKeyStatusText renders the servers whose host keys changed for the status bar
+func (t *TUIApp) hostKeyStatusText() string {
+	if len(t.hostKeyWarnings) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" | [white:red] ⚠ Host keys changed: %s (sshm hostkey rotate) [-:-]", tview.Escape(strings.Join(t.hostKeyWarnings, ", ")))
+}
@@ -0,0 +1,30 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/rivo/tview"
+	"sshm/internal/hostkey"
+)
+
+func TestHostIdentityModalEscapeCancels(t *testing.T) {
+	app := &TUIApp{app: tview.NewApplication(), statusBar: tview.NewTextView()}
+	app.modalManager = NewModalManager(app.app, tview.NewFlex())
+
+	trusted := make(chan bool, 1)
+	app.showHostIdentityModal(hostkey.IdentityChange{Server: "db1", Host: "db1.internal"}, trusted)
+
+	// Escape is handled globally, hiding the modal without going through its buttons
+	app.modalManager.HideModal()
+	select {
+	case answer := <-trusted:
+		if answer {
+			t.Error("Expected Escape to cancel the connection")
+		}
+	default:
+		t.Fatal("Expected Escape to answer instead of leaving the connection waiting")
+	}
+	if app.modalManager.IsModalActive() {
+		t.Error("Expected the warning to be closed")
+	}
+}
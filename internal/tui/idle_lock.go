@@ -0,0 +1,173 @@
+package tui
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// idleLockCheckInterval controls how often the idle timer is evaluated
+const idleLockCheckInterval = 5 * time.Second
+
+// IdleLock tracks user activity and locks the TUI after a period without input
+type IdleLock struct {
+	mu           sync.Mutex
+	timeout      time.Duration
+	lastActivity time.Time
+	locked       bool
+	now          func() time.Time // Allows tests to control time
+}
+
+// NewIdleLock creates an idle lock with the given timeout (0 disables locking)
+func NewIdleLock(timeout time.Duration) *IdleLock {
+	return &IdleLock{
+		timeout:      timeout,
+		lastActivity: time.Now(),
+		now:          time.Now,
+	}
+}
+
+// Enabled reports whether idle locking is configured
+func (l *IdleLock) Enabled() bool {
+	return l.timeout > 0
+}
+
+// RecordActivity resets the idle timer
+func (l *IdleLock) RecordActivity() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.lastActivity = l.now()
+}
+
+// ShouldLock reports whether the idle timeout elapsed and the TUI is not locked yet
+func (l *IdleLock) ShouldLock() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.timeout > 0 && !l.locked && l.now().Sub(l.lastActivity) >= l.timeout
+}
+
+// IsLocked reports whether the TUI is currently locked
+func (l *IdleLock) IsLocked() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.locked
+}
+
+// SetLocked updates the lock state; unlocking also resets the idle timer
+func (l *IdleLock) SetLocked(locked bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.locked = locked
+	if !locked {
+		l.lastActivity = l.now()
+	}
+}
+
+// startIdleLockMonitoring periodically checks for inactivity and locks the TUI
+func (t *TUIApp) startIdleLockMonitoring() {
+	if t.idleLock == nil || !t.idleLock.Enabled() {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(idleLockCheckInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-t.stopChan:
+				return
+			case <-ticker.C:
+				// Time spent attached to a tmux session doesn't count as idle TUI time
+				if t.sessionHandler != nil && t.sessionHandler.IsAttached() {
+					t.idleLock.RecordActivity()
+					continue
+				}
+				if t.running && t.idleLock.ShouldLock() {
+					t.app.QueueUpdateDraw(t.lockScreen)
+				}
+			}
+		}
+	}()
+}
+
+// lockScreen hides the inventory behind a lock screen until the PIN is entered
+func (t *TUIApp) lockScreen() {
+	t.idleLock.SetLocked(true)
+
+	hasPIN := t.config.UI.HasLockPIN()
+
+	message := tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextAlign(tview.AlignCenter)
+	if hasPIN {
+		message.SetText("[yellow::b]🔒 SSHM is locked[::-]\n\n[white]Enter your PIN and press Enter to unlock")
+	} else {
+		message.SetText("[yellow::b]🔒 SSHM is locked[::-]\n\n[white]Press Enter to unlock")
+	}
+
+	pinField := tview.NewInputField().
+		SetLabel("PIN: ").
+		SetMaskCharacter('*').
+		SetFieldWidth(12).
+		SetFieldBackgroundColor(tcell.ColorBlack).
+		SetLabelColor(tcell.ColorYellow)
+
+	pinField.SetDoneFunc(func(key tcell.Key) {
+		if key != tcell.KeyEnter {
+			return
+		}
+		if !t.config.UI.VerifyLockPIN(pinField.GetText()) {
+			pinField.SetText("")
+			message.SetText("[yellow::b]🔒 SSHM is locked[::-]\n\n[red]Incorrect PIN, try again")
+			return
+		}
+		t.unlockScreen()
+	})
+
+	form := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(tview.NewBox(), 0, 1, false).
+		AddItem(message, 3, 0, false).
+		AddItem(tview.NewFlex().
+			AddItem(tview.NewBox(), 0, 1, false).
+			AddItem(pinField, 20, 0, true).
+			AddItem(tview.NewBox(), 0, 1, false), 1, 0, true).
+		AddItem(tview.NewBox(), 0, 1, false)
+
+	t.lockField = pinField
+	t.lockedRoot, t.lockedFocus = t.root, t.app.GetFocus()
+	t.app.SetRoot(form, true)
+	t.app.SetFocus(pinField)
+}
+
+// unlockScreen restores the interface that was visible before locking, or the
+// one shown while locked, e.g. a connection result
+func (t *TUIApp) unlockScreen() {
+	t.idleLock.SetLocked(false)
+	t.lockField = nil
+
+	root := t.root
+	if root == nil {
+		root = t.layout
+	}
+	t.app.SetRoot(root, true)
+	if t.root == t.lockedRoot && t.lockedFocus != nil {
+		// Nothing new was shown while locked; put the focus back where it was
+		t.app.SetFocus(t.lockedFocus)
+	}
+	t.lockedRoot, t.lockedFocus = nil, nil
+}
+
+// setRoot shows root as the whole screen and reports whether it was shown.
+// While locked the lock screen stays up and root is shown once unlocked, so
+// background updates such as connection results never reveal the inventory.
+func (t *TUIApp) setRoot(root tview.Primitive) bool {
+	t.root = root
+	if t.idleLock != nil && t.idleLock.IsLocked() {
+		return false
+	}
+	t.app.SetRoot(root, true)
+	return true
+}
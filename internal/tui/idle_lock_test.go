@@ -0,0 +1,99 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"sshm/internal/config"
+)
+
+func TestIdleLockDisabled(t *testing.T) {
+	lock := NewIdleLock(0)
+	if lock.Enabled() {
+		t.Error("Expected idle lock to be disabled with zero timeout")
+	}
+	if lock.ShouldLock() {
+		t.Error("Disabled idle lock should never lock")
+	}
+}
+
+func TestIdleLockTimeout(t *testing.T) {
+	current := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	lock := NewIdleLock(10 * time.Minute)
+	lock.now = func() time.Time { return current }
+	lock.RecordActivity()
+
+	current = current.Add(9 * time.Minute)
+	if lock.ShouldLock() {
+		t.Error("Should not lock before the timeout")
+	}
+
+	lock.RecordActivity()
+	current = current.Add(9 * time.Minute)
+	if lock.ShouldLock() {
+		t.Error("Activity should reset the idle timer")
+	}
+
+	current = current.Add(2 * time.Minute)
+	if !lock.ShouldLock() {
+		t.Error("Expected lock after the idle timeout")
+	}
+
+	lock.SetLocked(true)
+	if lock.ShouldLock() {
+		t.Error("An already locked TUI should not lock again")
+	}
+
+	lock.SetLocked(false)
+	if lock.IsLocked() || lock.ShouldLock() {
+		t.Error("Unlocking should reset the idle timer")
+	}
+}
+
+func TestModalsWaitBehindLockScreen(t *testing.T) {
+	screen := tcell.NewSimulationScreen("UTF-8")
+	screen.SetSize(80, 24)
+	app := tview.NewApplication().SetScreen(screen)
+	layout := tview.NewFlex()
+	tuiApp := &TUIApp{
+		app:      app,
+		layout:   layout,
+		config:   &config.Config{},
+		idleLock: NewIdleLock(time.Minute),
+	}
+	tuiApp.modalManager = NewModalManager(app, layout)
+	tuiApp.modalManager.setRoot = tuiApp.setRoot
+	tuiApp.setRoot(layout)
+
+	screenText := func() string {
+		app.ForceDraw()
+		cells, width, _ := screen.GetContents()
+		var text strings.Builder
+		for i, cell := range cells {
+			if i > 0 && i%width == 0 {
+				text.WriteByte('\n')
+			}
+			text.WriteString(string(cell.Runes))
+		}
+		return text.String()
+	}
+
+	tuiApp.lockScreen()
+	modal := tview.NewModal().SetText("Connected to web1")
+	tuiApp.modalManager.ShowModal(modal)
+
+	if text := screenText(); !strings.Contains(text, "SSHM is locked") || strings.Contains(text, "Connected to web1") {
+		t.Errorf("Expected the lock screen to stay up while locked, got:\n%s", text)
+	}
+	if app.GetFocus() != tuiApp.lockField {
+		t.Error("Expected the PIN field to keep the focus while locked")
+	}
+
+	tuiApp.unlockScreen()
+	if text := screenText(); !strings.Contains(text, "Connected to web1") {
+		t.Errorf("Expected the modal shown while locked to appear once unlocked, got:\n%s", text)
+	}
+}
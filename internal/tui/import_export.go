@@ -1,18 +1,24 @@
 package tui
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
-	"gopkg.in/yaml.v3"
 	"sshm/internal/config"
+	"sshm/internal/exporter"
+	"sshm/internal/importer"
+	"sshm/internal/service"
+	"sshm/internal/signing"
 )
 
 // FocusManager handles element cycling and focus management for modals
@@ -84,6 +90,7 @@ type ImportExportModal struct {
 	filePathField    *tview.InputField
 	formatField      *tview.DropDown
 	profileField     *tview.DropDown
+	contentsField    *tview.DropDown // Export only: full or sanitized
 	browseButton     *tview.Button
 	actionButton     *tview.Button
 	cancelButton     *tview.Button
@@ -164,7 +171,7 @@ func (ie *ImportExportModal) createCenteredModal(title, instruction, actionIcon
 	if ie.isImport {
 		fieldsHeight = 12 // Import: file path + browse + format
 	} else {
-		fieldsHeight = 16 // Export: file path + browse + format + profile  
+		fieldsHeight = 19 // Export: file path + browse + format + profile + contents
 	}
 	
 	contentLayout := tview.NewFlex().SetDirection(tview.FlexRow).
@@ -197,7 +204,7 @@ func (ie *ImportExportModal) createCenteredModal(title, instruction, actionIcon
 	if ie.app.modalManager != nil {
 		ie.app.modalManager.ShowModal(centeredModal)
 	} else {
-		ie.app.app.SetRoot(centeredModal, true)
+		ie.app.setRoot(centeredModal)
 		ie.app.app.SetFocus(centeredModal)
 	}
 }
@@ -264,7 +271,20 @@ func (ie *ImportExportModal) createCenteredFieldsLayout() *tview.Flex {
 		fieldsLayout.AddItem(tview.NewBox(), 1, 0, false)          // 10. Spacer
 		fieldsLayout.AddItem(profileLabel, 1, 1, false)            // 11. Profile Filter label (fixed height)
 		fieldsLayout.AddItem(profileDropdownRow, 1, 1, false)      // 12. Profile dropdown (fixed height)
-		fieldsLayout.AddItem(tview.NewBox(), 1, 0, false)          // 13. Bottom section padding
+		
+		contentsLabel := tview.NewTextView()
+		contentsLabel.SetText("Contents").
+			SetTextAlign(tview.AlignCenter)
+		
+		contentsDropdownRow := tview.NewFlex().SetDirection(tview.FlexColumn).
+			AddItem(tview.NewBox(), 0, 1, false).    // Left spacer
+			AddItem(ie.contentsField, 0, 1, false).  // Contents dropdown centered
+			AddItem(tview.NewBox(), 0, 1, false)     // Right spacer
+		
+		fieldsLayout.AddItem(tview.NewBox(), 1, 0, false)          // 13. Spacer
+		fieldsLayout.AddItem(contentsLabel, 1, 1, false)           // 14. Contents label
+		fieldsLayout.AddItem(contentsDropdownRow, 1, 1, false)     // 15. Contents dropdown
+		fieldsLayout.AddItem(tview.NewBox(), 1, 0, false)          // 16. Bottom section padding
 	}
 	
 	return fieldsLayout
@@ -301,7 +321,7 @@ func (ie *ImportExportModal) createCompactButtonsLayout() *tview.Flex {
 
 // setupFocusManager configures the focus manager with all focusable elements in proper tab order
 func (ie *ImportExportModal) setupFocusManager() {
-	// File path → browse button → format dropdown → profile dropdown (export only) → action button → cancel button
+	// File path → browse button → format dropdown → profile and contents dropdowns (export only) → action button → cancel button
 	focusableElements := []tview.Primitive{
 		ie.filePathField,
 		ie.browseButton,
@@ -312,6 +332,9 @@ func (ie *ImportExportModal) setupFocusManager() {
 	if !ie.isImport && ie.profileField != nil {
 		focusableElements = append(focusableElements, ie.profileField)
 	}
+	if !ie.isImport && ie.contentsField != nil {
+		focusableElements = append(focusableElements, ie.contentsField)
+	}
 	
 	// Add action buttons
 	focusableElements = append(focusableElements, ie.actionButton, ie.cancelButton)
@@ -328,38 +351,51 @@ func (ie *ImportExportModal) setFormatSelection(format string) {
 		return
 	}
 	
-	// Map internal format to dropdown options
-	var targetOption string
-	switch format {
-	case "yaml":
-		targetOption = "YAML"
-	case "json":
-		targetOption = "JSON"
-	case "ssh":
-		targetOption = "SSH Config"
-	default:
-		return // Don't change selection
+	options := exportFormatOptions()
+	if ie.isImport {
+		options = append([]string{"Auto-detect"}, importFormatOptions()...)
 	}
-	
-	// Find and set the option - tview DropDown doesn't have GetOption, so we'll set based on known options
-	switch targetOption {
-	case "YAML":
-		if ie.isImport {
-			ie.formatField.SetCurrentOption(1) // Auto-detect(0), YAML(1), JSON(2), SSH Config(3)
-		} else {
-			ie.formatField.SetCurrentOption(0) // YAML(0), JSON(1)
-		}
-	case "JSON":
-		if ie.isImport {
-			ie.formatField.SetCurrentOption(2) // Auto-detect(0), YAML(1), JSON(2), SSH Config(3)
-		} else {
-			ie.formatField.SetCurrentOption(1) // YAML(0), JSON(1)
-		}
-	case "SSH Config":
-		if ie.isImport {
-			ie.formatField.SetCurrentOption(3) // Auto-detect(0), YAML(1), JSON(2), SSH Config(3)
+	for i, option := range options {
+		if option == formatLabel(format) {
+			ie.formatField.SetCurrentOption(i)
+			return
 		}
 	}
+	// Unknown formats don't change the selection
+}
+
+// formatLabel is how a format name is shown in the format dropdowns
+func formatLabel(name string) string {
+	if name == "ssh" {
+		return "SSH Config"
+	}
+	return strings.ToUpper(name)
+}
+
+// importFormatOptions lists the registered importers for the format dropdown
+func importFormatOptions() []string {
+	names := importer.Names()
+	options := make([]string, len(names))
+	for i, name := range names {
+		options[i] = formatLabel(name)
+	}
+	return options
+}
+
+// Options of the export contents dropdown
+const (
+	exportContentsFull      = "Full"
+	exportContentsSanitized = "Sanitized (names, hosts, profiles)"
+)
+
+// exportFormatOptions lists the registered exporters for the format dropdown
+func exportFormatOptions() []string {
+	names := exporter.Names()
+	options := make([]string, len(names))
+	for i, name := range names {
+		options[i] = formatLabel(name)
+	}
+	return options
 }
 
 // launchExternalFzf launches the external fzf binary for file selection in fullscreen
@@ -547,9 +583,9 @@ func (ie *ImportExportModal) createFormFields() {
 	// Format selection field with professional styling
 	ie.formatField = tview.NewDropDown()
 	if ie.isImport {
-		ie.formatField.SetOptions([]string{"Auto-detect", "YAML", "JSON", "SSH Config"}, nil)
+		ie.formatField.SetOptions(append([]string{"Auto-detect"}, importFormatOptions()...), nil)
 	} else {
-		ie.formatField.SetOptions([]string{"YAML", "JSON"}, nil)
+		ie.formatField.SetOptions(exportFormatOptions(), nil)
 	}
 	ie.formatField.SetCurrentOption(0).
 		SetFieldBackgroundColor(tcell.ColorDarkBlue).
@@ -601,6 +637,17 @@ func (ie *ImportExportModal) createFormFields() {
 		
 		// Override tview dropdown space key behavior for profile field
 		ie.setupDropdownKeyHandling(ie.profileField)
+		
+		// Sanitized exports keep only names, hosts and profiles, per the sanitize rules
+		ie.contentsField = tview.NewDropDown()
+		ie.contentsField.SetOptions([]string{exportContentsFull, exportContentsSanitized}, nil).
+			SetCurrentOption(0).
+			SetFieldBackgroundColor(tcell.ColorDarkBlue).
+			SetFieldTextColor(tcell.ColorWhite)
+		ie.contentsField.SetSelectedFunc(func(option string, optionIndex int) {
+			ie.showSelectionFeedback(ie.contentsField, option)
+		})
+		ie.setupDropdownKeyHandling(ie.contentsField)
 	}
 }
 
@@ -876,20 +923,14 @@ func (ie *ImportExportModal) updateFilePathExtension() {
 	format := ie.normalizeFormat(formatText)
 	
 	// Determine new extension
-	var newExt string
-	switch format {
-	case "yaml":
-		newExt = ".yaml"
-	case "json":
-		newExt = ".json"
-	default:
-		newExt = ".yaml" // Default to yaml
+	newExt := ".yaml" // Default to yaml
+	if exp, ok := exporter.Lookup(format); ok && exporter.DefaultExtension(exp) != "" {
+		newExt = exporter.DefaultExtension(exp)
 	}
 	
-	// Remove existing extension if present
-	currentExt := strings.ToLower(filepath.Ext(currentPath))
-	if currentExt == ".yaml" || currentExt == ".yml" || currentExt == ".json" {
-		currentPath = strings.TrimSuffix(currentPath, currentExt)
+	// Remove existing extension if it belongs to an export format
+	if _, ok := exporter.ForPath(currentPath); ok {
+		currentPath = strings.TrimSuffix(currentPath, filepath.Ext(currentPath))
 	}
 	
 	// Add new extension
@@ -987,23 +1028,32 @@ func (ie *ImportExportModal) handleImport() {
 	ie.showProgressIndicator(progress)
 	
 	// Perform import in background
+	progress.task, _ = ie.app.tasks.start(taskSpec{Name: "import"})
 	go func() {
+		defer progress.task.Finish()
 		// Update progress - reading file
 		progress.Update(1, 4, "Reading configuration file...")
 		ie.app.app.QueueUpdateDraw(func() {
 			ie.showProgressIndicator(progress)
 		})
 		
-		err := ie.performImportWithProgress(filePath, format, progress)
+		report, err := ie.performImportWithProgress(filePath, format, progress)
 		ie.app.app.QueueUpdateDraw(func() {
 			if err != nil {
 				progress.SetError(err)
 				ie.showProgressIndicator(progress)
 			} else {
-				progress.Complete("Configuration imported successfully")
+				progress.Complete(fmt.Sprintf("Imported %d new and %d updated server(s), %d skipped", report.Added, report.Updated, len(report.Skipped)))
 				ie.showProgressIndicator(progress)
 				// Refresh the TUI
 				ie.app.RefreshConfig()
+				// Per-entry errors and the signature are listed in a scrollable result view
+				if len(report.Skipped) > 0 || report.Signature != "" {
+					ie.app.showTextPanel("Import Results", renderImportReport(report))
+				}
+				if len(report.Proposals) > 0 {
+					ie.app.showProfileProposals(report.Proposals)
+				}
 			}
 		})
 	}()
@@ -1036,6 +1086,12 @@ func (ie *ImportExportModal) handleExport() {
 		}
 	}
 	
+	sanitize := false
+	if ie.contentsField != nil {
+		_, contents := ie.contentsField.GetCurrentOption()
+		sanitize = contents == exportContentsSanitized
+	}
+	
 	// Create progress indicator
 	progress := NewImportExportProgressIndicator("Exporting configuration...")
 	
@@ -1043,14 +1099,16 @@ func (ie *ImportExportModal) handleExport() {
 	ie.showProgressIndicator(progress)
 	
 	// Perform export in background
+	progress.task, _ = ie.app.tasks.start(taskSpec{Name: "export"})
 	go func() {
+		defer progress.task.Finish()
 		// Update progress - preparing export
 		progress.Update(1, 3, "Preparing configuration for export...")
 		ie.app.app.QueueUpdateDraw(func() {
 			ie.showProgressIndicator(progress)
 		})
 		
-		err := ie.performExportWithProgress(filePath, format, profileName, progress)
+		err := ie.performExportWithProgress(filePath, format, profileName, sanitize, progress)
 		ie.app.app.QueueUpdateDraw(func() {
 			if err != nil {
 				progress.SetError(err)
@@ -1068,99 +1126,241 @@ func (ie *ImportExportModal) handleCancel() {
 	if ie.app.modalManager != nil {
 		ie.app.modalManager.HideModal()
 	} else {
-		ie.app.app.SetRoot(ie.app.layout, true)
+		ie.app.setRoot(ie.app.layout)
 		ie.app.app.SetFocus(ie.app.layout)
 	}
 }
 
-// performImportWithProgress executes the actual import operation with progress updates
-func (ie *ImportExportModal) performImportWithProgress(filePath, format string, progress *ImportExportProgressIndicator) error {
+// importBatchSize is how many imported servers are inserted per UI update
+const importBatchSize = 250
+
+// importSkip is a server entry left out of an import
+type importSkip struct {
+	Index int // Position in the file, from 0
+	Name  string
+	Err   error
+}
+
+// importReport summarizes an import for the result view
+type importReport struct {
+	Added     int
+	Updated   int
+	Profiles  int
+	Skipped   []importSkip
+	Proposals []config.ProfileProposal // Profiles inferred for SSH config hosts, created once confirmed
+	Signature string                   // Who signed the file, when it has a good detached signature
+}
+
+// verifyImportSignature applies the auto policy of 'sshm import' to the
+// detached signature of an import file: unsigned files are imported, files
+// whose signature isn't good are rejected. It returns who signed the file.
+func verifyImportSignature(filePath string) (string, error) {
+	result, err := signing.Verify(filePath)
+	if errors.Is(err, signing.ErrNoSignature) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("signature verification failed for %s: %w", filePath, err)
+	}
+	if !result.Valid {
+		return "", fmt.Errorf("signature verification failed for %s: %s (use 'sshm import --signature warn' to import anyway)", filePath, result.Reason)
+	}
+	return fmt.Sprintf("%s (key %s)", result.Signer, result.KeyID), nil
+}
+
+// performImportWithProgress executes the actual import operation with progress updates.
+// YAML and JSON files are streamed and validated in parallel, and valid servers are
+// inserted in batches on the UI goroutine so the modal stays responsive.
+func (ie *ImportExportModal) performImportWithProgress(filePath, format string, progress *ImportExportProgressIndicator) (*importReport, error) {
 	// Step 1: Read file
 	progress.Update(1, 4, "Reading configuration file...")
-	data, err := os.ReadFile(filePath)
+	report := &importReport{}
+	signer, err := verifyImportSignature(filePath)
 	if err != nil {
-		return fmt.Errorf("failed to read file: %w", err)
+		return nil, err
 	}
-	
-	// Step 2: Parse configuration
-	progress.Update(2, 4, "Parsing configuration...")
-	var servers []config.Server
+	report.Signature = signer
 	var profiles []config.Profile
+	var pending []config.Server // Servers imported along with the profiles
 	
-	// Parse based on format
 	switch format {
-	case "yaml":
-		servers, profiles, err = ie.parseYAMLConfig(data)
-	case "json":
-		servers, profiles, err = ie.parseJSONConfig(data)
-	case "ssh":
-		servers, err = config.ParseSSHConfig(filePath)
-		profiles = nil // SSH config doesn't have profiles
+	case "yaml", "json":
+		file, err := os.Open(filePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read file: %w", err)
+		}
+		defer file.Close()
+		size := int64(0)
+		if info, err := file.Stat(); err == nil {
+			size = info.Size()
+		}
+		reader := &countingReader{r: file}
+		
+		// Step 2: Parse, validate and insert servers batch by batch
+		var batch []config.Server
+		flush := func() {
+			servers := batch
+			batch = nil
+			ie.onUIThread(func() {
+				added, updated, errs := ie.app.config.UpsertServers(servers)
+				report.Added += added
+				report.Updated += updated
+				for name, err := range errs {
+					report.Skipped = append(report.Skipped, importSkip{Index: -1, Name: name, Err: err})
+				}
+				ie.showProgressIndicator(progress)
+			})
+		}
+		seen := 0
+		profiles, err = config.StreamImport(context.Background(), reader, format, 0, func(entry config.ImportEntry) {
+			seen++
+			if entry.Err != nil {
+				report.Skipped = append(report.Skipped, importSkip{Index: entry.Index, Name: entry.Server.Name, Err: entry.Err})
+			} else {
+				batch = append(batch, entry.Server)
+			}
+			if len(batch) >= importBatchSize {
+				percent := 0
+				if size > 0 {
+					percent = int(reader.Count() * 100 / size)
+				}
+				progress.Update(percent, 100, fmt.Sprintf("Imported %d of %d servers read...", seen-len(report.Skipped), seen))
+				flush()
+			}
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse configuration: %w", err)
+		}
+		if len(batch) > 0 {
+			flush()
+		}
+		if seen == 0 {
+			return nil, fmt.Errorf("no valid server configurations found in file")
+		}
 	default:
-		return fmt.Errorf("unsupported format: %s", format)
-	}
-	
-	if err != nil {
-		return fmt.Errorf("failed to parse configuration: %w", err)
-	}
-	
-	if len(servers) == 0 {
-		return fmt.Errorf("no valid server configurations found in file")
-	}
-	
-	// Step 3: Import servers and profiles
-	progress.Update(3, 4, fmt.Sprintf("Importing %d servers and %d profiles...", len(servers), len(profiles)))
-	imported := 0
-	updated := 0
-	
-	for _, server := range servers {
-		// Validate server
-		if err := server.Validate(); err != nil {
-			continue // Skip invalid servers
+		imp, ok := importer.Lookup(format)
+		if !ok {
+			return nil, fmt.Errorf("unsupported format: %s", format)
 		}
 		
-		// Check if server exists
-		_, err := ie.app.config.GetServer(server.Name)
-		if err == nil {
-			// Server exists - update it
-			if err := ie.app.config.RemoveServer(server.Name); err != nil {
-				continue
+		// Step 2: Parse configuration
+		progress.Update(2, 4, "Parsing configuration...")
+		var servers []config.Server
+		var err error
+		servers, profiles, err = importer.ParseFile(imp, filePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse configuration: %w", err)
+		}
+		// Hosts of an SSH config are grouped into profiles once confirmed
+		if format == "ssh" {
+			if file, err := config.ReadSSHConfig(filePath); err == nil {
+				report.Proposals = config.InferSSHProfiles(file)
 			}
-			updated++
-		} else {
-			imported++
+		}
+		if len(servers) == 0 {
+			return nil, fmt.Errorf("no valid server configurations found in file")
 		}
 		
-		// Add server
-		if err := ie.app.config.AddServer(server); err != nil {
-			continue
+		// Step 3: Import servers
+		progress.Update(3, 4, fmt.Sprintf("Importing %d servers...", len(servers)))
+		var valid []config.Server
+		for i, server := range servers {
+			if err := server.Validate(); err != nil {
+				report.Skipped = append(report.Skipped, importSkip{Index: i, Name: server.Name, Err: err})
+				continue
+			}
+			valid = append(valid, server)
 		}
+		pending = valid
 	}
 	
-	// Import profiles
-	for _, profile := range profiles {
-		// Check if profile exists
-		_, err := ie.app.config.GetProfile(profile.Name)
-		if err == nil {
-			// Profile exists - remove it first
-			ie.app.config.RemoveProfile(profile.Name)
+	// Step 4: Import profiles and save configuration
+	progress.Update(4, 4, "Saving configuration...")
+	var saveErr error
+	ie.onUIThread(func() {
+		// Shared with 'sshm import': servers are upserted and profiles of the same name replaced
+		var result service.ImportResult
+		result, saveErr = ie.app.service().Import(pending, profiles)
+		report.Added += result.Added
+		report.Updated += result.Updated
+		report.Profiles = result.Profiles
+		for _, skip := range result.Skipped {
+			report.Skipped = append(report.Skipped, importSkip{Index: -1, Name: skip.Name, Err: errors.New(skip.Error)})
 		}
-		
-		// Add profile
-		ie.app.config.AddProfile(profile)
+		for _, skip := range result.SkippedProfiles {
+			report.Skipped = append(report.Skipped, importSkip{Index: -1, Name: "profile " + skip.Name, Err: errors.New(skip.Error)})
+		}
+	})
+	if saveErr != nil {
+		return nil, saveErr
 	}
 	
-	// Step 4: Save configuration
-	progress.Update(4, 4, "Saving configuration...")
-	if err := ie.app.config.Save(); err != nil {
-		return fmt.Errorf("failed to save configuration: %w", err)
+	return report, nil
+}
+
+// onUIThread runs fn on the UI goroutine and waits for it, so background work can
+// change the configuration without racing the UI
+func (ie *ImportExportModal) onUIThread(fn func()) {
+	done := make(chan struct{})
+	ie.app.app.QueueUpdateDraw(func() {
+		defer close(done)
+		fn()
+	})
+	<-done
+}
+
+// countingReader counts the bytes read so far to report streaming progress
+type countingReader struct {
+	r     io.Reader
+	mu    sync.Mutex
+	count int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.mu.Lock()
+	c.count += int64(n)
+	c.mu.Unlock()
+	return n, err
+}
+
+// Count returns the number of bytes read so far
+func (c *countingReader) Count() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.count
+}
+
+// renderImportReport renders the import result view, listing every skipped entry
+func renderImportReport(report *importReport) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "[green]✅ %d server(s) added, %d updated, %d profile(s) imported[white]\n", report.Added, report.Updated, report.Profiles)
+	if report.Signature != "" {
+		fmt.Fprintf(&b, "[green]🔏 Signature verified: %s[white]\n", tview.Escape(report.Signature))
+	}
+	if len(report.Skipped) == 0 {
+		return b.String()
 	}
 	
-	return nil
+	skipped := append([]importSkip(nil), report.Skipped...)
+	sort.SliceStable(skipped, func(i, j int) bool { return skipped[i].Index < skipped[j].Index })
+	fmt.Fprintf(&b, "[red]%d entr(ies) skipped:[white]\n\n", len(skipped))
+	for _, skip := range skipped {
+		name := skip.Name
+		if name == "" {
+			name = "(unnamed)"
+		}
+		position := ""
+		if skip.Index >= 0 {
+			position = fmt.Sprintf("#%d ", skip.Index+1)
+		}
+		fmt.Fprintf(&b, "  [yellow]%s%s[white]: %s\n", position, tview.Escape(name), tview.Escape(skip.Err.Error()))
+	}
+	return b.String()
 }
 
 // performExportWithProgress executes the actual export operation with progress updates
-func (ie *ImportExportModal) performExportWithProgress(filePath, format, profileName string, progress *ImportExportProgressIndicator) error {
+func (ie *ImportExportModal) performExportWithProgress(filePath, format, profileName string, sanitize bool, progress *ImportExportProgressIndicator) error {
 	// Step 1: Create directory if needed
 	progress.Update(1, 3, "Creating output directory...")
 	dir := filepath.Dir(filePath)
@@ -1196,19 +1396,20 @@ func (ie *ImportExportModal) performExportWithProgress(filePath, format, profile
 		}
 	}
 	
-	// Marshal data
-	var data []byte
-	var err error
+	if sanitize {
+		var err error
+		exportConfig.Servers, exportConfig.Profiles, err = ie.app.config.Sanitize.Sanitized(exportConfig.Servers, exportConfig.Profiles)
+		if err != nil {
+			return fmt.Errorf("failed to sanitize export: %w", err)
+		}
+	}
 	
-	switch format {
-	case "yaml":
-		data, err = yaml.Marshal(exportConfig)
-	case "json":
-		data, err = json.MarshalIndent(exportConfig, "", "  ")
-	default:
+	// Marshal data with the format's exporter
+	exp, ok := exporter.Lookup(format)
+	if !ok {
 		return fmt.Errorf("unsupported format: %s", format)
 	}
-	
+	data, err := exp.Marshal(exportConfig)
 	if err != nil {
 		return fmt.Errorf("failed to marshal configuration: %w", err)
 	}
@@ -1222,40 +1423,10 @@ func (ie *ImportExportModal) performExportWithProgress(filePath, format, profile
 	return nil
 }
 
-// parseYAMLConfig parses YAML configuration data
-func (ie *ImportExportModal) parseYAMLConfig(data []byte) ([]config.Server, []config.Profile, error) {
-	var cfg config.Config
-	if err := yaml.Unmarshal(data, &cfg); err != nil {
-		return nil, nil, err
-	}
-	return cfg.Servers, cfg.Profiles, nil
-}
-
-// parseJSONConfig parses JSON configuration data
-func (ie *ImportExportModal) parseJSONConfig(data []byte) ([]config.Server, []config.Profile, error) {
-	var cfg config.Config
-	if err := json.Unmarshal(data, &cfg); err != nil {
-		return nil, nil, err
-	}
-	return cfg.Servers, cfg.Profiles, nil
-}
-
-// detectFileFormat detects file format based on extension
+// detectFileFormat detects file format from its content, or its name when the
+// content is not recognized
 func (ie *ImportExportModal) detectFileFormat(filePath string) string {
-	ext := strings.ToLower(filepath.Ext(filePath))
-	base := strings.ToLower(filepath.Base(filePath))
-	
-	switch ext {
-	case ".yaml", ".yml":
-		return "yaml"
-	case ".json":
-		return "json"
-	default:
-		if base == "config" || base == "ssh_config" || strings.Contains(base, "ssh") {
-			return "ssh"
-		}
-		return "yaml" // Default
-	}
+	return importer.DetectFile(filePath)
 }
 
 // normalizeFormat converts display format to internal format
@@ -1277,9 +1448,11 @@ func (ie *ImportExportModal) normalizeFormat(displayFormat string) string {
 // isFormatSupported checks if a format is supported
 func (ie *ImportExportModal) isFormatSupported(format string, isImport bool) bool {
 	if isImport {
-		return format == "yaml" || format == "json" || format == "ssh"
+		_, ok := importer.Lookup(format)
+		return ok
 	}
-	return format == "yaml" || format == "json"
+	_, ok := exporter.Lookup(format)
+	return ok
 }
 
 // showProgress displays progress information
@@ -1315,13 +1488,15 @@ func (ie *ImportExportModal) showSuccess(message string) {
 	}()
 }
 
-// ProgressIndicator represents operation progress for import/export
+// ImportExportProgressIndicator represents operation progress for import/export;
+// updates are mirrored to the task shown in the tasks overlay
 type ImportExportProgressIndicator struct {
 	Message   string
 	Current   int
 	Total     int
 	Completed bool
 	Error     error
+	task      *task // Background task shown in the tasks overlay, if any
 }
 
 // NewImportExportProgressIndicator creates a new progress indicator
@@ -1336,6 +1511,9 @@ func (p *ImportExportProgressIndicator) Update(current, total int, message strin
 	p.Current = current
 	p.Total = total
 	p.Message = message
+	if p.task != nil {
+		p.task.Update(current, total, message)
+	}
 }
 
 // Complete marks the operation as completed
@@ -1510,7 +1688,7 @@ func (fb *FileSystemBrowser) Show(app *TUIApp) {
 	if app.modalManager != nil {
 		app.modalManager.ShowModal(border)
 	} else {
-		app.app.SetRoot(border, true)
+		app.setRoot(border)
 		app.app.SetFocus(fb.fileList)
 	}
 }
@@ -1734,3 +1912,39 @@ func (fb *FileSystemBrowser) selectCurrentItem(app *TUIApp) {
 
 
 
+
+// showProfileProposals asks whether to create the profiles inferred for
+// imported SSH config hosts
+func (t *TUIApp) showProfileProposals(proposals []config.ProfileProposal) {
+	modal := tview.NewModal().
+		SetText(renderProfileProposals(proposals)).
+		AddButtons([]string{"Create profiles", "Leave unassigned"}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			t.modalManager.HideModal()
+			if buttonIndex != 0 {
+				return
+			}
+			created, extended, err := t.config.ApplyProfileProposals(proposals)
+			if err == nil {
+				err = t.saveConfig()
+			}
+			if err != nil {
+				t.showErrorModal(fmt.Sprintf("Failed to create profiles: %s", err.Error()))
+				return
+			}
+			t.RefreshConfig()
+			t.showTransientStatus(fmt.Sprintf("[green]✓ Created %d profile(s), added servers to %d existing[white]", created, extended))
+		})
+	t.modalManager.ShowModal(modal)
+}
+
+// renderProfileProposals lists the inferred profiles and why each was proposed
+func renderProfileProposals(proposals []config.ProfileProposal) string {
+	var b strings.Builder
+	b.WriteString("Group the imported hosts into profiles?\n\n")
+	for _, proposal := range proposals {
+		fmt.Fprintf(&b, "%s (%d): %s\n", proposal.Profile, len(proposal.Servers), strings.Join(proposal.Servers, ", "))
+		fmt.Fprintf(&b, "  %s\n", strings.Join(proposal.Reasons, "; "))
+	}
+	return b.String()
+}
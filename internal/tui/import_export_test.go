@@ -1496,4 +1496,39 @@ func TestImportExportModal_DropdownSelectionRegistration(t *testing.T) {
 				map[bool]string{true: "import", false: "export"}[tt.isImport])
 		})
 	}
-}
\ No newline at end of file
+}
+func TestRenderImportReport(t *testing.T) {
+	report := &importReport{Added: 3, Updated: 1, Profiles: 2, Skipped: []importSkip{
+		{Index: 7, Name: "web-08", Err: errors.New("hostname is required")},
+		{Index: 2, Name: "", Err: errors.New("server name is required")},
+	}}
+	text := renderImportReport(report)
+	if !strings.Contains(text, "3 server(s) added, 1 updated, 2 profile(s) imported") {
+		t.Errorf("Missing summary in %q", text)
+	}
+	if strings.Index(text, "#3 (unnamed)") > strings.Index(text, "#8 web-08") {
+		t.Errorf("Expected skipped entries in file order: %q", text)
+	}
+}
+
+func TestVerifyImportSignature(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "servers.yaml")
+	if err := os.WriteFile(path, []byte("servers: []\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if signer, err := verifyImportSignature(path); err != nil || signer != "" {
+		t.Errorf("Expected an unsigned file to be imported, got %q, %v", signer, err)
+	}
+
+	if err := os.WriteFile(path+".asc", []byte("not a signature"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := verifyImportSignature(path); err == nil {
+		t.Error("Expected a bad signature to reject the import")
+	}
+
+	text := renderImportReport(&importReport{Added: 1, Signature: "Ops <ops@example.com> (key ABCD)"})
+	if !strings.Contains(text, "Signature verified: Ops <ops@example.com> (key ABCD)") {
+		t.Errorf("Expected the signer in the report, got %q", text)
+	}
+}
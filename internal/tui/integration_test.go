@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"sshm/internal/config"
+	"sshm/internal/connection"
 	"sshm/internal/tmux"
 )
 
@@ -180,7 +181,7 @@ func TestTUIConnectionIntegration(t *testing.T) {
 		t.Fatalf("Failed to get server config: %v", err)
 	}
 
-	sshCommand, err := tuiApp.buildSSHCommand(*server)
+	sshCommand, err := connection.SSHCommand(*server)
 	if err != nil {
 		t.Errorf("Failed to build SSH command: %v", err)
 	}
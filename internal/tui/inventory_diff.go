@@ -0,0 +1,74 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"sshm/internal/config"
+)
+
+// showInventoryDiffForm asks for a file to compare with the current inventory
+func (t *TUIApp) showInventoryDiffForm() {
+	form := tview.NewForm().
+		AddInputField("Compare with file", "", 50, nil, nil).
+		AddButton("Compare", nil).
+		AddButton("Cancel", nil)
+	form.SetBorder(true).
+		SetTitle(" Inventory Diff ").
+		SetTitleAlign(tview.AlignCenter)
+
+	pathField := form.GetFormItem(0).(*tview.InputField)
+
+	form.GetButton(0).SetSelectedFunc(func() {
+		path := strings.TrimSpace(pathField.GetText())
+		if path == "" {
+			t.showErrorModal("A file path is required")
+			return
+		}
+
+		other, err := config.ReadInventory(path)
+		if err != nil {
+			t.showErrorModal(err.Error())
+			return
+		}
+
+		t.modalManager.HideModal()
+		t.showTextPanel(fmt.Sprintf("Diff › %s → current", path), renderInventoryDiff(config.DiffInventories(other, t.config)))
+	})
+	form.GetButton(1).SetSelectedFunc(func() {
+		t.modalManager.HideModal()
+	})
+	form.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape {
+			t.modalManager.HideModal()
+			return nil
+		}
+		return event
+	})
+
+	t.modalManager.ShowModal(form)
+}
+
+// renderInventoryDiff formats an inventory diff for the text panel
+func renderInventoryDiff(diff config.InventoryDiff) string {
+	var b strings.Builder
+
+	if diff.IsEmpty() {
+		b.WriteString("[green]Inventories are identical[white]\n")
+	}
+	for _, line := range diff.Lines() {
+		lineColor := "yellow"
+		switch line.Kind {
+		case config.DiffAdded:
+			lineColor = "green"
+		case config.DiffRemoved:
+			lineColor = "red"
+		}
+		fmt.Fprintf(&b, "[%s]%s %s[white]\n", lineColor, line.Kind, tview.Escape(line.Text))
+	}
+
+	b.WriteString("\n[gray]Press Enter, Escape or q to close[white]")
+	return b.String()
+}
@@ -0,0 +1,171 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"sshm/internal/audit"
+	"sshm/internal/config"
+)
+
+// auditAuthorizedKeys gathers the authorized_keys entries of the servers in
+// the active profile, or all servers, and shows which keys grant access where
+func (t *TUIApp) auditAuthorizedKeys() {
+	servers, scope, ok := t.auditScope()
+	if !ok {
+		return
+	}
+	known, err := audit.LoadKnownKeys(t.config)
+	if err != nil {
+		t.showErrorModal(fmt.Sprintf("Invalid known_keys: %s", err.Error()))
+		return
+	}
+
+	t.showTransientStatus(fmt.Sprintf("[yellow]Gathering authorized_keys on %d server(s)...[white]", len(servers)))
+
+	op, _ := t.tasks.start(taskSpec{Name: "authorized_keys audit"})
+	go func(servers []config.Server) {
+		defer op.Finish()
+		auditedAt := time.Now()
+		results := audit.AuthorizedKeys(servers, false)
+		t.app.QueueUpdateDraw(func() {
+			t.showKeyAuditResults(scope, results, known, auditedAt)
+		})
+	}(servers)
+}
+
+// showKeyAuditResults renders the keys found, unknown ones first in red;
+// 'u' shows only unknown keys, 's' the per-server problems and 'e' exports CSV
+func (t *TUIApp) showKeyAuditResults(scope string, results []audit.KeysResult, known audit.KnownKeys, auditedAt time.Time) {
+	keys := audit.AggregateKeys(results, known)
+
+	table := tview.NewTable().
+		SetSelectable(true, false).
+		SetFixed(1, 0)
+	table.SetBorder(true).
+		SetTitle(fmt.Sprintf(" Authorized Keys › %s ", scope)).
+		SetBorderColor(tcell.ColorYellow)
+
+	unknownOnly := false
+	footer := tview.NewTextView().SetDynamicColors(true)
+	help := "[gray]u: unknown only  •  s: server problems  •  e: export CSV  •  Escape/q: close[white]"
+	footer.SetText(tview.Escape(audit.KeysSummary(results, keys)) + "  •  " + help)
+
+	render := func() {
+		renderKeyAuditTable(table, keys, unknownOnly)
+		table.Select(1, 0)
+	}
+	render()
+
+	panel := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(table, 0, 1, true).
+		AddItem(footer, 1, 0, false)
+
+	panel.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape || event.Rune() == 'q' || event.Rune() == 'Q' {
+			t.hideTextPanel()
+			return nil
+		}
+		switch event.Rune() {
+		case 'u', 'U':
+			unknownOnly = !unknownOnly
+			render()
+			return nil
+		case 's', 'S':
+			t.showTextPanel(fmt.Sprintf("Authorized Keys › %s › Servers", scope), renderKeyAuditProblems(results))
+			return nil
+		case 'e', 'E':
+			path, err := exportKeyAuditCSV(results, known, auditedAt)
+			if err != nil {
+				footer.SetText(fmt.Sprintf("[red]Export failed: %s[white]", tview.Escape(err.Error())))
+			} else {
+				footer.SetText(fmt.Sprintf("[green]Exported to %s[white]  •  %s", path, help))
+			}
+			return nil
+		}
+		return event
+	})
+
+	t.modalManager.ShowModal(panel)
+}
+
+// renderKeyAuditTable fills the table with one row per key
+func renderKeyAuditTable(table *tview.Table, keys []audit.KeyAccess, unknownOnly bool) {
+	table.Clear()
+	for col, header := range []string{"Fingerprint", "Type", "Comment", "Known as", "Logins"} {
+		table.SetCell(0, col, tview.NewTableCell(header).
+			SetTextColor(tcell.ColorYellow).
+			SetAttributes(tcell.AttrBold).
+			SetSelectable(false).
+			SetExpansion(1))
+	}
+
+	row := 1
+	for _, key := range keys {
+		if unknownOnly && !key.IsUnknown() {
+			continue
+		}
+		rowColor := tcell.ColorWhite
+		knownAs := key.Known
+		if key.IsUnknown() {
+			rowColor = tcell.ColorRed
+			knownAs = "unknown"
+		}
+		table.SetCell(row, 0, tview.NewTableCell(key.Fingerprint).SetTextColor(rowColor))
+		table.SetCell(row, 1, tview.NewTableCell(key.Type).SetTextColor(rowColor))
+		table.SetCell(row, 2, tview.NewTableCell(tview.Escape(strings.Join(key.Comments, ", "))).SetTextColor(rowColor))
+		table.SetCell(row, 3, tview.NewTableCell(tview.Escape(knownAs)).SetTextColor(rowColor))
+		table.SetCell(row, 4, tview.NewTableCell(tview.Escape(strings.Join(key.Logins, ", "))).SetTextColor(rowColor).SetExpansion(3))
+		row++
+	}
+	if row == 1 {
+		table.SetCell(1, 0, tview.NewTableCell("No keys found").SetTextColor(tcell.ColorGray).SetSelectable(false))
+	}
+}
+
+// renderKeyAuditProblems lists the servers that couldn't be audited fully
+func renderKeyAuditProblems(results []audit.KeysResult) string {
+	var b strings.Builder
+	for _, result := range results {
+		switch {
+		case result.Error != "":
+			fmt.Fprintf(&b, "[red]%s[white]: %s\n", tview.Escape(result.Server), tview.Escape(result.Error))
+			continue
+		case len(result.Unreadable) > 0:
+			fmt.Fprintf(&b, "[yellow]%s[white]: couldn't read %s\n", tview.Escape(result.Server), tview.Escape(strings.Join(result.Unreadable, ", ")))
+		}
+		if result.Invalid > 0 {
+			fmt.Fprintf(&b, "[yellow]%s[white]: %d line(s) aren't public keys\n", tview.Escape(result.Server), result.Invalid)
+		}
+	}
+	if b.Len() == 0 {
+		b.WriteString("[green]Every server's authorized_keys could be read[white]\n")
+	}
+	b.WriteString("\n[gray]Press Enter, Escape or q to close[white]")
+	return b.String()
+}
+
+// exportKeyAuditCSV writes the audit to a timestamped CSV file in the home directory
+func exportKeyAuditCSV(results []audit.KeysResult, known audit.KnownKeys, auditedAt time.Time) (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+
+	path := filepath.Join(homeDir, fmt.Sprintf("sshm-keys-%s.csv", auditedAt.Format("20060102-150405")))
+	file, err := os.Create(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	defer file.Close()
+
+	if err := audit.WriteKeysCSV(file, results, known, auditedAt); err != nil {
+		return "", err
+	}
+	return path, nil
+}
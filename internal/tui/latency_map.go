@@ -0,0 +1,199 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"sshm/internal/config"
+)
+
+// regionTagPrefixes mark the tag holding a server's region, e.g. "region:eu-west"
+var regionTagPrefixes = []string{"region:", "region="}
+
+// noRegion groups servers without a region tag
+const noRegion = "(no region)"
+
+// Latency buckets, from fastest to unreachable
+const (
+	bucketFast     = "fast"
+	bucketSlow     = "slow"
+	bucketCritical = "critical"
+	bucketOffline  = "offline"
+	bucketUnknown  = "unknown"
+)
+
+// latencyBuckets lists the buckets in display order with their label color
+var latencyBuckets = []struct {
+	Name  string
+	Color string
+}{
+	{bucketFast, "green"},
+	{bucketSlow, "yellow"},
+	{bucketCritical, "orange"},
+	{bucketOffline, "red"},
+	{bucketUnknown, "gray"},
+}
+
+// latencyBarWidth is the width of the longest bucket bar
+const latencyBarWidth = 30
+
+// latencySample is one server's last status check
+type latencySample struct {
+	Server  config.Server
+	Status  string
+	Latency time.Duration
+}
+
+// showLatencyMap buckets the visible servers by measured latency and region so a
+// slow or unreachable region stands out from isolated host failures
+func (t *TUIApp) showLatencyMap() {
+	servers := t.visibleServers()
+
+	t.statusMutex.RLock()
+	samples := make([]latencySample, 0, len(servers))
+	for _, server := range servers {
+		status, ok := t.connectionStatus[server.Name]
+		if !ok {
+			status = "checking"
+		}
+		samples = append(samples, latencySample{Server: server, Status: status, Latency: t.statusLatency[server.Name]})
+	}
+	t.statusMutex.RUnlock()
+
+	warn, critical := t.config.UI.Status.LatencyThresholds()
+	t.showTextPanel("Latency Map", renderLatencyMap(samples, warn, critical))
+}
+
+// latencyBucket classifies a sample using the status column latency thresholds
+func latencyBucket(sample latencySample, warn, critical time.Duration) string {
+	switch {
+	case sample.Status == "checking" || sample.Status == "unknown":
+		return bucketUnknown
+	case sample.Status != "online":
+		return bucketOffline
+	case sample.Latency > critical:
+		return bucketCritical
+	case sample.Latency > warn:
+		return bucketSlow
+	default:
+		return bucketFast
+	}
+}
+
+// serverRegion returns the region from a server's "region:" tag
+func serverRegion(server config.Server) string {
+	for _, tag := range server.Tags {
+		for _, prefix := range regionTagPrefixes {
+			if region, ok := strings.CutPrefix(strings.ToLower(tag), prefix); ok && region != "" {
+				return region
+			}
+		}
+	}
+	return noRegion
+}
+
+// renderLatencyMap renders a bar per latency bucket followed by one line per region
+// with a colored block per server, flagging regions that are entirely down or slow
+func renderLatencyMap(samples []latencySample, warn, critical time.Duration) string {
+	var b strings.Builder
+	if len(samples) == 0 {
+		return "[gray]No servers to show[white]"
+	}
+
+	counts := make(map[string]int)
+	regions := make(map[string][]latencySample)
+	for _, sample := range samples {
+		counts[latencyBucket(sample, warn, critical)]++
+		region := serverRegion(sample.Server)
+		regions[region] = append(regions[region], sample)
+	}
+
+	labels := map[string]string{
+		bucketFast:     fmt.Sprintf("< %v", warn),
+		bucketSlow:     fmt.Sprintf("%v–%v", warn, critical),
+		bucketCritical: fmt.Sprintf("> %v", critical),
+		bucketOffline:  "unreachable",
+		bucketUnknown:  "not checked",
+	}
+
+	b.WriteString("[yellow::b]By latency[-::-]\n")
+	for _, bucket := range latencyBuckets {
+		count := counts[bucket.Name]
+		bar := strings.Repeat("█", (count*latencyBarWidth+len(samples)-1)/len(samples))
+		fmt.Fprintf(&b, "  %-12s [%s]%-*s[white] %d\n", labels[bucket.Name], bucket.Color, latencyBarWidth, bar, count)
+	}
+
+	names := make([]string, 0, len(regions))
+	for region := range regions {
+		names = append(names, region)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		// Servers without a region are listed last
+		if (names[i] == noRegion) != (names[j] == noRegion) {
+			return names[j] == noRegion
+		}
+		return names[i] < names[j]
+	})
+
+	b.WriteString("\n[yellow::b]By region[-::-] [gray](tag servers with region:<name>)[white]\n")
+	for _, region := range names {
+		regionSamples := regions[region]
+		sort.Slice(regionSamples, func(i, j int) bool { return regionSamples[i].Server.Name < regionSamples[j].Server.Name })
+
+		var blocks strings.Builder
+		regionCounts := make(map[string]int)
+		var latencies []time.Duration
+		for _, sample := range regionSamples {
+			bucket := latencyBucket(sample, warn, critical)
+			regionCounts[bucket]++
+			if sample.Status == "online" {
+				latencies = append(latencies, sample.Latency)
+			}
+			for _, candidate := range latencyBuckets {
+				if candidate.Name == bucket {
+					fmt.Fprintf(&blocks, "[%s]■[white]", candidate.Color)
+				}
+			}
+		}
+
+		summary := fmt.Sprintf("%d/%d online", len(latencies), len(regionSamples))
+		if len(latencies) > 0 {
+			summary += fmt.Sprintf(", median %v", medianLatency(latencies).Round(time.Millisecond))
+		}
+		fmt.Fprintf(&b, "  %-16s %s  %s%s\n", region, blocks.String(), summary, regionWarning(regionSamples, regionCounts))
+	}
+
+	b.WriteString("\n[green]■[gray] fast  [yellow]■[gray] slow  [orange]■[gray] critical  [red]■[gray] unreachable  ■ not checked[white]")
+	return b.String()
+}
+
+// regionWarning flags a region whose servers are all unreachable or all slow, which
+// points at a regional problem rather than isolated host failures
+func regionWarning(samples []latencySample, counts map[string]int) string {
+	total := len(samples) - counts[bucketUnknown]
+	if total < 2 {
+		return ""
+	}
+	switch {
+	case counts[bucketOffline] == total:
+		return "  [red::b]⚠ entire region unreachable[-::-]"
+	case counts[bucketSlow]+counts[bucketCritical] == total:
+		return "  [orange::b]⚠ entire region slow[-::-]"
+	case counts[bucketOffline] > 0:
+		return fmt.Sprintf("  [gray](%d isolated failure(s))[white]", counts[bucketOffline])
+	}
+	return ""
+}
+
+// medianLatency returns the median of the given latencies
+func medianLatency(latencies []time.Duration) time.Duration {
+	sorted := append([]time.Duration(nil), latencies...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[middle-1] + sorted[middle]) / 2
+	}
+	return sorted[middle]
+}
@@ -0,0 +1,63 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"sshm/internal/config"
+)
+
+func TestLatencyBucket(t *testing.T) {
+	warn, critical := 100*time.Millisecond, 500*time.Millisecond
+	tests := []struct {
+		sample latencySample
+		want   string
+	}{
+		{latencySample{Status: "online", Latency: 20 * time.Millisecond}, bucketFast},
+		{latencySample{Status: "online", Latency: 200 * time.Millisecond}, bucketSlow},
+		{latencySample{Status: "online", Latency: time.Second}, bucketCritical},
+		{latencySample{Status: "unreachable"}, bucketOffline},
+		{latencySample{Status: "checking"}, bucketUnknown},
+	}
+	for _, tt := range tests {
+		if got := latencyBucket(tt.sample, warn, critical); got != tt.want {
+			t.Errorf("latencyBucket(%+v) = %s, want %s", tt.sample, got, tt.want)
+		}
+	}
+}
+
+func TestRenderLatencyMap(t *testing.T) {
+	server := func(name, region string) config.Server {
+		return config.Server{Name: name, Tags: []string{"region:" + region}}
+	}
+	samples := []latencySample{
+		{Server: server("eu1", "eu-west"), Status: "unreachable"},
+		{Server: server("eu2", "eu-west"), Status: "refused"},
+		{Server: server("us1", "us-east"), Status: "online", Latency: 30 * time.Millisecond},
+		{Server: server("us2", "us-east"), Status: "unreachable"},
+		{Server: config.Server{Name: "lab"}, Status: "online", Latency: 10 * time.Millisecond},
+	}
+
+	text := renderLatencyMap(samples, 100*time.Millisecond, 500*time.Millisecond)
+	lines := strings.Split(text, "\n")
+
+	var euLine, usLine string
+	for _, line := range lines {
+		switch {
+		case strings.Contains(line, "eu-west"):
+			euLine = line
+		case strings.Contains(line, "us-east"):
+			usLine = line
+		}
+	}
+	if !strings.Contains(euLine, "0/2 online") || !strings.Contains(euLine, "entire region unreachable") {
+		t.Errorf("Expected eu-west flagged as unreachable, got %q", euLine)
+	}
+	if !strings.Contains(usLine, "1/2 online, median 30ms") || !strings.Contains(usLine, "1 isolated failure") {
+		t.Errorf("Expected us-east with an isolated failure, got %q", usLine)
+	}
+	if strings.Index(text, "us-east") > strings.Index(text, noRegion) {
+		t.Error("Expected servers without a region to be listed last")
+	}
+}
@@ -77,12 +77,9 @@ func (t *TUIApp) CreateAddServerForm() *TUIForm {
 			return &ValidationError{Field: "general", Message: fmt.Sprintf("Server validation failed: %s", err.Error())}
 		}
 		
-		// Add server to configuration
-		t.config.Servers = append(t.config.Servers, server)
-		
-		// Save configuration
-		if err := t.config.Save(); err != nil {
-			return &ValidationError{Field: "general", Message: fmt.Sprintf("Failed to save configuration: %s", err.Error())}
+		// Add server to configuration and save it
+		if err := t.service().AddServer(server); err != nil {
+			return &ValidationError{Field: "general", Message: fmt.Sprintf("Failed to add server: %s", err.Error())}
 		}
 		
 		// Refresh UI
@@ -213,17 +210,9 @@ func (t *TUIApp) CreateEditServerForm(serverName string) *TUIForm {
 			return &ValidationError{Field: "general", Message: fmt.Sprintf("Server validation failed: %s", err.Error())}
 		}
 		
-		// Find and replace the server in configuration
-		for i, s := range t.config.Servers {
-			if s.Name == serverName {
-				t.config.Servers[i] = updatedServer
-				break
-			}
-		}
-		
-		// Save configuration
-		if err := t.config.Save(); err != nil {
-			return &ValidationError{Field: "general", Message: fmt.Sprintf("Failed to save configuration: %s", err.Error())}
+		// Replace the server in configuration and save it
+		if err := t.service().UpdateServer(serverName, updatedServer); err != nil {
+			return &ValidationError{Field: "general", Message: fmt.Sprintf("Failed to update server: %s", err.Error())}
 		}
 		
 		// Refresh UI
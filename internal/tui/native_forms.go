@@ -3,6 +3,7 @@ package tui
 import (
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
@@ -10,6 +11,45 @@ import (
 	"sshm/internal/config"
 )
 
+// metadataFormIndex is the index of the first metadata field (owner) in the server forms
+const metadataFormIndex = 8
+
+// startupFormIndex is the index of the startup commands field in the server forms,
+// followed by the remote environment
+const startupFormIndex = 12
+
+// startupFromForm reads the startup commands and remote environment fields,
+// one command or NAME=value per line
+func startupFromForm(form *tview.Form, index int) ([]string, map[string]string, error) {
+	lines := func(offset int) []string {
+		var values []string
+		for _, line := range strings.Split(form.GetFormItem(index+offset).(*tview.TextArea).GetText(), "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				values = append(values, line)
+			}
+		}
+		return values
+	}
+	env, err := config.ParseRemoteEnv(lines(1))
+	if err != nil {
+		return nil, nil, err
+	}
+	return lines(0), env, nil
+}
+
+// metadataFromForm reads the owner, team, cost center and environment fields starting at index
+func metadataFromForm(form *tview.Form, index int) config.ServerMetadata {
+	value := func(offset int) string {
+		return strings.TrimSpace(form.GetFormItem(index + offset).(*tview.InputField).GetText())
+	}
+	return config.ServerMetadata{
+		Owner:       value(0),
+		Team:        value(1),
+		CostCenter:  value(2),
+		Environment: value(3),
+	}
+}
+
 // CreateNativeAddServerForm creates a form using tview's native form with proper password masking
 func (t *TUIApp) CreateNativeAddServerForm() *tview.Form {
 	form := tview.NewForm().
@@ -21,6 +61,12 @@ func (t *TUIApp) CreateNativeAddServerForm() *tview.Form {
 		AddPasswordField("Password", "", 30, '*', nil).
 		AddInputField("Key Path (optional)", "", 50, nil, nil).
 		AddCheckbox("Passphrase Protected", false, nil).
+		AddInputField("Owner (optional)", "", 30, nil, nil).
+		AddInputField("Team (optional)", "", 30, nil, nil).
+		AddInputField("Cost Center (optional)", "", 20, nil, nil).
+		AddInputField("Environment (optional)", "", 20, nil, nil).
+		AddTextArea("Startup Commands (one per line)", "", 50, 3, 0, nil).
+		AddTextArea("Remote Env (NAME=value per line)", "", 50, 3, 0, nil).
 		AddButton("Submit", nil).
 		AddButton("Cancel", nil)
 
@@ -92,6 +138,12 @@ func (t *TUIApp) CreateNativeAddServerForm() *tview.Form {
 
 		// Handle passphrase protected
 		server.PassphraseProtected = passphraseCheckbox.IsChecked()
+		server.Metadata = metadataFromForm(form, metadataFormIndex)
+		server.StartupCommands, server.RemoteEnv, err = startupFromForm(form, startupFormIndex)
+		if err != nil {
+			t.showErrorModal(err.Error())
+			return
+		}
 
 		// Handle password authentication with keyring storage
 		if authType == "password" {
@@ -121,12 +173,9 @@ func (t *TUIApp) CreateNativeAddServerForm() *tview.Form {
 			return
 		}
 
-		// Add server to configuration
-		t.config.Servers = append(t.config.Servers, server)
-
-		// Save configuration
-		if err := t.config.Save(); err != nil {
-			t.showErrorModal(fmt.Sprintf("Failed to save configuration: %s", err.Error()))
+		// Add server to configuration and save it
+		if err := t.service().AddServer(server); err != nil {
+			t.showErrorModal(fmt.Sprintf("Failed to add server: %s", err.Error()))
 			return
 		}
 
@@ -181,6 +230,12 @@ func (t *TUIApp) CreateNativeEditServerForm(serverName string) *tview.Form {
 		AddPasswordField("Password", "", 30, '*', nil). // Always empty for security
 		AddInputField("Key Path (optional)", server.KeyPath, 50, nil, nil).
 		AddCheckbox("Passphrase Protected", server.PassphraseProtected, nil).
+		AddInputField("Owner (optional)", server.Metadata.Owner, 30, nil, nil).
+		AddInputField("Team (optional)", server.Metadata.Team, 30, nil, nil).
+		AddInputField("Cost Center (optional)", server.Metadata.CostCenter, 20, nil, nil).
+		AddInputField("Environment (optional)", server.Metadata.Environment, 20, nil, nil).
+		AddTextArea("Startup Commands (one per line)", strings.Join(server.StartupCommands, "\n"), 50, 3, 0, nil).
+		AddTextArea("Remote Env (NAME=value per line)", strings.Join(config.FormatRemoteEnv(server.RemoteEnv), "\n"), 50, 3, 0, nil).
 		AddButton("Update", nil).
 		AddButton("Cancel", nil)
 
@@ -261,6 +316,12 @@ func (t *TUIApp) CreateNativeEditServerForm(serverName string) *tview.Form {
 
 		// Handle passphrase protected
 		updatedServer.PassphraseProtected = passphraseCheckbox.IsChecked()
+		updatedServer.Metadata = metadataFromForm(form, metadataFormIndex)
+		updatedServer.StartupCommands, updatedServer.RemoteEnv, err = startupFromForm(form, startupFormIndex)
+		if err != nil {
+			t.showErrorModal(err.Error())
+			return
+		}
 
 		// Handle password authentication with keyring storage
 		if authType == "password" {
@@ -293,17 +354,9 @@ func (t *TUIApp) CreateNativeEditServerForm(serverName string) *tview.Form {
 			return
 		}
 
-		// Find and replace the server in configuration
-		for i, s := range t.config.Servers {
-			if s.Name == serverName {
-				t.config.Servers[i] = updatedServer
-				break
-			}
-		}
-
-		// Save configuration
-		if err := t.config.Save(); err != nil {
-			t.showErrorModal(fmt.Sprintf("Failed to save configuration: %s", err.Error()))
+		// Replace the server in configuration and save it
+		if err := t.service().UpdateServer(serverName, updatedServer); err != nil {
+			t.showErrorModal(fmt.Sprintf("Failed to update server: %s", err.Error()))
 			return
 		}
 
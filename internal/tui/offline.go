@@ -0,0 +1,50 @@
+package tui
+
+// offlineStatus is shown for every server while offline mode suppresses checks
+const offlineStatus = "offline mode"
+
+// startOffline makes new TUI instances start in offline mode
+var startOffline bool
+
+// SetStartOffline makes the TUI start in offline mode. It must be called before
+// NewTUIApp, which starts the first status check.
+func SetStartOffline(offline bool) {
+	startOffline = offline
+}
+
+// isOffline reports whether network activity is suppressed
+func (t *TUIApp) isOffline() bool {
+	return t.offline.Load()
+}
+
+// markServersOffline replaces every cached status with the offline status, so no
+// server is left showing a stale result or "checking"
+func (t *TUIApp) markServersOffline() {
+	t.statusMutex.Lock()
+	defer t.statusMutex.Unlock()
+	for _, server := range t.config.GetServers() {
+		t.connectionStatus[server.Name] = offlineStatus
+		t.statusAttempts[server.Name] = 0
+		t.statusLatency[server.Name] = 0
+		t.statusStats[server.Name] = ""
+	}
+}
+
+// toggleOffline switches offline mode. Going back online checks every server and
+// looks for updates right away instead of waiting for the next poll.
+func (t *TUIApp) toggleOffline() {
+	offline := !t.offline.Load()
+	t.offline.Store(offline)
+
+	if offline {
+		t.markServersOffline()
+		t.refreshServerList()
+		t.showTransientStatus("[gray]✈ Offline mode: status and update checks are disabled[white]")
+		return
+	}
+
+	t.refreshServerList()
+	t.showTransientStatus("[green]🌐 Online: checking servers...[white]")
+	t.startUpdateCheck()
+	go t.updateAllConnectionStatus()
+}
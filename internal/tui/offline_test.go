@@ -0,0 +1,55 @@
+package tui
+
+import (
+	"testing"
+	"time"
+
+	"sshm/internal/config"
+)
+
+func newOfflineTestApp() *TUIApp {
+	app := &TUIApp{
+		config: &config.Config{Servers: []config.Server{
+			{Name: "web", Hostname: "10.0.0.1", Port: 22, Username: "deploy", AuthType: "key"},
+			{Name: "db", Hostname: "10.0.0.2", Port: 22, Username: "deploy", AuthType: "key"},
+		}},
+		connectionStatus: map[string]string{"web": "online"},
+		statusAttempts:   map[string]int{"web": 1},
+		statusLatency:    map[string]time.Duration{"web": 20 * time.Millisecond},
+		statusStats:      map[string]string{"web": "load 0.1"},
+	}
+	app.offline.Store(true)
+	return app
+}
+
+func TestUpdateAllConnectionStatusOffline(t *testing.T) {
+	app := newOfflineTestApp()
+
+	// Unreachable addresses would block for the dial timeout if a check ran
+	done := make(chan struct{})
+	go func() {
+		app.updateAllConnectionStatus()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Expected no status checks in offline mode")
+	}
+
+	for _, name := range []string{"web", "db"} {
+		if got := app.connectionStatus[name]; got != offlineStatus {
+			t.Errorf("Status of %s = %q, want %q", name, got, offlineStatus)
+		}
+	}
+	if app.statusLatency["web"] != 0 || app.statusStats["web"] != "" {
+		t.Error("Expected stale latency and stats to be cleared")
+	}
+}
+
+func TestFormatStatusOffline(t *testing.T) {
+	text, _ := formatStatus(offlineStatus, 0, 0, config.StatusDisplayConfig{})
+	if text != statusLED+" "+offlineStatus {
+		t.Errorf("Unexpected offline rendering: %s", text)
+	}
+}
@@ -0,0 +1,185 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rivo/tview"
+	"sshm/internal/tmux"
+)
+
+// outputWatchInterval is how often watched sessions' panes are captured
+const outputWatchInterval = 3 * time.Second
+
+// outputWatch is a session whose output is watched for a pattern
+type outputWatch struct {
+	watch   *tmux.OutputWatch
+	desktop bool // Also raise a desktop notification on a match
+}
+
+// toggleOutputWatchSelectedSession asks for a pattern to watch the selected
+// session's output for, or stops watching it
+func (t *TUIApp) toggleOutputWatchSelectedSession() {
+	if t.sessionPanel == nil || t.focusedPanel != "sessions" {
+		return
+	}
+	currentRow, _ := t.sessionPanel.GetSelection()
+	if currentRow <= 0 || currentRow > len(t.sessions) {
+		return
+	}
+	sessionName := t.sessions[currentRow-1].Name
+
+	t.outputWatchMu.Lock()
+	existing, watched := t.outputWatches[sessionName]
+	if watched {
+		delete(t.outputWatches, sessionName)
+	}
+	t.outputWatchMu.Unlock()
+	if watched {
+		t.showTransientStatus(fmt.Sprintf("[yellow]Stopped watching the output of %s for /%s/[white]",
+			sessionName, tview.Escape(existing.watch.Pattern.String())))
+		return
+	}
+	t.showOutputWatchForm(sessionName)
+}
+
+// showOutputWatchForm collects the pattern to watch a session's output for
+func (t *TUIApp) showOutputWatchForm(sessionName string) {
+	form := tview.NewForm().
+		AddInputField("Pattern (regular expression)", t.lastOutputPattern, 40, nil, nil).
+		AddCheckbox("Desktop notification", true, nil)
+	form.SetBorder(true).
+		SetTitle(fmt.Sprintf(" 👁 Watch Output of %s ", sessionName)).
+		SetTitleAlign(tview.AlignCenter)
+
+	form.AddButton("Watch", func() {
+		pattern := form.GetFormItem(0).(*tview.InputField).GetText()
+		watch, err := tmux.NewOutputWatch(sessionName, pattern)
+		if err != nil {
+			t.showErrorModal(err.Error())
+			return
+		}
+		desktop := form.GetFormItem(1).(*tview.Checkbox).IsChecked()
+		t.modalManager.HideModal()
+		t.startOutputWatch(watch, desktop)
+	})
+	form.AddButton("Cancel", func() {
+		t.modalManager.HideModal()
+	})
+	t.modalManager.ShowModal(form)
+}
+
+// startOutputWatch records what the session shows now, so only output printed
+// from here on is matched, and adds it to the watched sessions
+func (t *TUIApp) startOutputWatch(watch *tmux.OutputWatch, desktop bool) {
+	if _, err := t.tmuxManager.CheckOutput(watch); err != nil {
+		t.showErrorModal(fmt.Sprintf("Failed to watch session '%s': %s", watch.Session, err.Error()))
+		return
+	}
+	t.lastOutputPattern = watch.Pattern.String()
+
+	t.outputWatchMu.Lock()
+	if t.outputWatches == nil {
+		t.outputWatches = make(map[string]*outputWatch)
+	}
+	t.outputWatches[watch.Session] = &outputWatch{watch: watch, desktop: desktop}
+	t.outputWatchMu.Unlock()
+
+	t.showTransientStatus(fmt.Sprintf("[green]👁 Watching the output of %s for /%s/ — detach freely, ~ stops[white]",
+		watch.Session, tview.Escape(watch.Pattern.String())))
+}
+
+// isOutputWatched reports whether a session's output is being watched
+func (t *TUIApp) isOutputWatched(sessionName string) bool {
+	t.outputWatchMu.Lock()
+	defer t.outputWatchMu.Unlock()
+	_, ok := t.outputWatches[sessionName]
+	return ok
+}
+
+// startOutputWatches periodically checks the watched sessions for new output
+// matching their pattern
+func (t *TUIApp) startOutputWatches() {
+	go func() {
+		ticker := time.NewTicker(outputWatchInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-t.stopChan:
+				return
+			case <-ticker.C:
+				if !t.running {
+					continue
+				}
+				t.checkOutputWatches()
+			}
+		}
+	}()
+}
+
+// checkOutputWatches captures every watched session once, dropping the
+// sessions that no longer exist
+func (t *TUIApp) checkOutputWatches() {
+	t.outputWatchMu.Lock()
+	watches := make([]*outputWatch, 0, len(t.outputWatches))
+	for _, watch := range t.outputWatches {
+		watches = append(watches, watch)
+	}
+	t.outputWatchMu.Unlock()
+
+	for _, watch := range watches {
+		sessionName := watch.watch.Session
+		matches, err := t.tmuxManager.CheckOutput(watch.watch)
+		if err != nil {
+			if t.tmuxManager.SessionExists(sessionName) {
+				continue // Try again next time
+			}
+			t.outputWatchMu.Lock()
+			delete(t.outputWatches, sessionName)
+			t.outputWatchMu.Unlock()
+			t.app.QueueUpdateDraw(func() {
+				t.showTransientStatus(fmt.Sprintf("[yellow]Stopped watching %s: the session ended[white]", sessionName))
+			})
+			continue
+		}
+		if len(matches) > 0 {
+			t.notifyOutputMatches(sessionName, matches, watch.desktop)
+		}
+	}
+}
+
+// notifyOutputMatches shows a toast and, when asked for, a desktop
+// notification with the first matching line
+func (t *TUIApp) notifyOutputMatches(sessionName string, matches []tmux.OutputMatch, desktop bool) {
+	summary := outputMatchSummary(matches)
+	if desktop {
+		// Best effort: not every desktop has a notification tool
+		desktopNotify("sshm: "+sessionName, summary)
+	}
+	t.app.QueueUpdateDraw(func() {
+		t.showTransientStatus(fmt.Sprintf("[yellow]👁 %s: %s[white]", sessionName, tview.Escape(summary)))
+	})
+}
+
+// outputMatchSummary describes the matches of one check in a line
+func outputMatchSummary(matches []tmux.OutputMatch) string {
+	first := matches[0]
+	summary := first.Line
+	if first.Window != "" {
+		summary = "[" + first.Window + "] " + summary
+	}
+	if len(matches) > 1 {
+		summary += fmt.Sprintf(" (+%d more)", len(matches)-1)
+	}
+	return strings.TrimSpace(summary)
+}
+
+// outputWatchMenuItem starts or stops watching a session's output
+func outputWatchMenuItem(watched bool, toggle func()) menuItem {
+	if watched {
+		return menuItem{"Stop watching output", "~", "No longer notify on matching output", toggle}
+	}
+	return menuItem{"Watch output", "~", "Notify when new output matches a pattern", toggle}
+}
@@ -0,0 +1,28 @@
+package tui
+
+import (
+	"testing"
+
+	"sshm/internal/tmux"
+)
+
+func TestOutputMatchSummary(t *testing.T) {
+	single := []tmux.OutputMatch{{Window: "web-01", Line: "deploy finished"}}
+	if got := outputMatchSummary(single); got != "[web-01] deploy finished" {
+		t.Errorf("outputMatchSummary() = %q", got)
+	}
+
+	several := []tmux.OutputMatch{{Line: "ERROR: disk full"}, {Line: "ERROR: retrying"}, {Line: "ERROR: giving up"}}
+	if got := outputMatchSummary(several); got != "ERROR: disk full (+2 more)" {
+		t.Errorf("outputMatchSummary() = %q", got)
+	}
+}
+
+func TestOutputWatchMenuItem(t *testing.T) {
+	if item := outputWatchMenuItem(false, nil); item.Label != "Watch output" {
+		t.Errorf("Expected to offer watching, got %q", item.Label)
+	}
+	if item := outputWatchMenuItem(true, nil); item.Label != "Stop watching output" {
+		t.Errorf("Expected to offer stopping, got %q", item.Label)
+	}
+}
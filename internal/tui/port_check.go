@@ -0,0 +1,129 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rivo/tview"
+	"sshm/internal/config"
+	"sshm/internal/portcheck"
+)
+
+// portCheckFromHere is the "via" choice probing from the local machine
+const portCheckFromHere = "(this machine)"
+
+// showPortCheckForm asks which ports to probe on the selected server and from where
+func (t *TUIApp) showPortCheckForm() {
+	if t.focusedPanel != "servers" {
+		return
+	}
+	serverName := t.getSelectedServerName()
+	if serverName == "" {
+		return
+	}
+	server, err := t.config.GetServer(serverName)
+	if err != nil {
+		t.showErrorModal(fmt.Sprintf("Server '%s' not found: %s", serverName, err.Error()))
+		return
+	}
+	if t.isOffline() {
+		t.showTransientStatus("[yellow]Offline mode: port checks are disabled (Ctrl+N)[white]")
+		return
+	}
+
+	form := tview.NewForm().
+		AddInputField("Ports", config.FormatPorts(t.config.PortCheck.PortList()), 40, nil, nil).
+		AddDropDown("Probe from", t.diagnosticSources(*server), 0, nil).
+		AddButton("Check", nil).
+		AddButton("Cancel", nil)
+	form.SetBorder(true).
+		SetTitle(fmt.Sprintf(" 🔌 Port Check: %s ", server.Name)).
+		SetTitleAlign(tview.AlignCenter)
+
+	portsField := form.GetFormItem(0).(*tview.InputField)
+	viaDropdown := form.GetFormItem(1).(*tview.DropDown)
+
+	form.GetButton(0).SetSelectedFunc(func() {
+		ports, err := config.ParsePorts(portsField.GetText())
+		if err != nil {
+			t.showErrorModal(err.Error())
+			return
+		}
+		_, viaName := viaDropdown.GetCurrentOption()
+		via, err := t.diagnosticSource(viaName)
+		if err != nil {
+			t.showErrorModal(err.Error())
+			return
+		}
+		t.modalManager.HideModal()
+		t.runPortCheck(*server, via, ports)
+	})
+	form.GetButton(1).SetSelectedFunc(func() {
+		t.modalManager.HideModal()
+	})
+	form.SetCancelFunc(func() {
+		t.modalManager.HideModal()
+	})
+	t.modalManager.ShowModal(form)
+}
+
+// runPortCheck probes the ports in the background and shows the results
+func (t *TUIApp) runPortCheck(server config.Server, via *config.Server, ports []int) {
+	t.showTransientStatus(fmt.Sprintf("[yellow]Checking ports on %s...[white]", server.Name))
+	tk, ctx := t.tasks.start(taskSpec{Name: "port check on " + server.Name, Cancellable: true, SafeToInterrupt: true})
+	timeout := t.config.PortCheck.TimeoutDuration()
+
+	go func() {
+		defer tk.Finish()
+		report, err := portcheck.Run(ctx, server, via, ports, timeout)
+		if ctx.Err() != nil {
+			return
+		}
+		t.app.QueueUpdateDraw(func() {
+			if err != nil {
+				t.showErrorModal(fmt.Sprintf("Port check on %s failed: %s", server.Name, err.Error()))
+				return
+			}
+			t.showTextPanel(fmt.Sprintf(" 🔌 Ports of %s ", server.Name), renderPortReport(report))
+		})
+	}()
+}
+
+// renderPortReport renders the port states with a verdict about ssh
+func renderPortReport(report portcheck.Report) string {
+	from := "this machine"
+	if report.Via != "" {
+		from = report.Via
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "[yellow]%s[white] (%s) probed from %s\n\n", tview.Escape(report.Server), tview.Escape(report.Host), tview.Escape(from))
+
+	sshOpen := false
+	for _, result := range report.Results {
+		stateColor := "red"
+		switch result.State {
+		case portcheck.StateOpen:
+			stateColor = "green"
+		case portcheck.StateClosed:
+			stateColor = "yellow"
+		}
+		detail := result.Detail
+		if result.State == portcheck.StateOpen && result.Latency > 0 {
+			detail = result.Latency.Round(time.Millisecond).String()
+		}
+		name := ""
+		if result.Port == report.SSHPort {
+			name = " (ssh)"
+			sshOpen = result.State == portcheck.StateOpen
+		}
+		fmt.Fprintf(&b, "  %5d%-6s [%s]%-8s[white] [gray]%s[white]\n", result.Port, name, stateColor, result.State, tview.Escape(detail))
+	}
+
+	verdictColor := "yellow"
+	if sshOpen {
+		verdictColor = "green"
+	}
+	fmt.Fprintf(&b, "\n[%s]%s[white]\n", verdictColor, tview.Escape(report.Verdict))
+	return b.String()
+}
@@ -0,0 +1,30 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"sshm/internal/portcheck"
+)
+
+func TestRenderPortReport(t *testing.T) {
+	report := portcheck.Report{
+		Server:  "web1",
+		Host:    "10.0.0.5",
+		Via:     "bastion",
+		SSHPort: 22,
+		Results: []portcheck.Result{
+			{Port: 22, State: portcheck.StateFiltered, Detail: "no answer"},
+			{Port: 443, State: portcheck.StateOpen, Latency: 12 * time.Millisecond},
+		},
+	}
+	report.Verdict = portcheck.Verdict(report.SSHPort, report.Results)
+
+	text := renderPortReport(report)
+	for _, expected := range []string{"probed from bastion", "22 (ssh)", "filtered", "12ms", "SSH is blocked"} {
+		if !strings.Contains(text, expected) {
+			t.Errorf("Expected %q in the report, got:\n%s", expected, text)
+		}
+	}
+}
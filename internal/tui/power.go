@@ -0,0 +1,159 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"sshm/internal/config"
+	"sshm/internal/power"
+)
+
+// powerActionLabels are the action choices shown in the power action form, in power.Actions order
+var powerActionLabels = []string{"Reboot", "Shutdown", "Custom command"}
+
+// showPowerActionForm lets the user reboot, shut down or run a command on the
+// selected server or on every server of the active profile
+func (t *TUIApp) showPowerActionForm() {
+	if t.focusedPanel != "servers" {
+		return
+	}
+
+	targets := []string{}
+	serverName := t.getSelectedServerName()
+	if serverName != "" {
+		targets = append(targets, serverName)
+	}
+	profileName := ""
+	if t.currentFilter != "" && t.currentFilter != "all" && !t.isUnassignedFilter() {
+		profileName = t.currentFilter
+		targets = append(targets, "Profile: "+profileName)
+	}
+	if len(targets) == 0 {
+		return
+	}
+
+	form := tview.NewForm().
+		AddDropDown("Target", targets, 0, nil).
+		AddDropDown("Action", powerActionLabels, 0, nil).
+		AddInputField("Command", "", 50, nil, nil).
+		AddInputField("Confirm (protected)", "", 30, nil, nil).
+		AddButton("Run", nil).
+		AddButton("Cancel", nil).
+		AddButton("Last results", nil)
+	form.SetBorder(true).
+		SetTitle(" ⚡ Power Action ").
+		SetTitleAlign(tview.AlignCenter)
+
+	targetDropdown := form.GetFormItem(0).(*tview.DropDown)
+	actionDropdown := form.GetFormItem(1).(*tview.DropDown)
+	commandField := form.GetFormItem(2).(*tview.InputField)
+	confirmField := form.GetFormItem(3).(*tview.InputField)
+
+	form.GetButton(0).SetSelectedFunc(func() {
+		targetIndex, _ := targetDropdown.GetCurrentOption()
+		actionIndex, _ := actionDropdown.GetCurrentOption()
+		action := power.Actions[actionIndex]
+		command := commandField.GetText()
+
+		if _, err := power.Command(action, command); err != nil {
+			t.showErrorModal(err.Error())
+			return
+		}
+
+		target := serverName
+		var servers []config.Server
+		if targets[targetIndex] != serverName {
+			target = profileName
+			profileServers, err := t.config.GetServersByProfile(profileName)
+			if err != nil || len(profileServers) == 0 {
+				t.showErrorModal(fmt.Sprintf("No servers found in profile '%s'", profileName))
+				return
+			}
+			servers = profileServers
+		} else {
+			server, err := t.config.GetServer(serverName)
+			if err != nil {
+				t.showErrorModal(fmt.Sprintf("Server '%s' not found: %s", serverName, err.Error()))
+				return
+			}
+			servers = []config.Server{*server}
+		}
+
+		if power.RequiresTypedConfirmation(servers) && !power.ConfirmationMatches(target, confirmField.GetText()) {
+			t.showErrorModal(fmt.Sprintf("'%s' includes protected servers.\n\nType '%s' in the Confirm field to proceed.", target, target))
+			return
+		}
+
+		t.modalManager.HideModal()
+		t.runPowerAction(servers, action, command)
+	})
+	form.GetButton(1).SetSelectedFunc(func() {
+		t.modalManager.HideModal()
+	})
+	form.GetButton(2).SetSelectedFunc(func() {
+		t.modalManager.HideModal()
+		t.showCommandRuns()
+	})
+	form.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape {
+			t.modalManager.HideModal()
+			return nil
+		}
+		return event
+	})
+
+	t.modalManager.ShowModal(form)
+}
+
+// runPowerAction runs the action on each server in the background, reports the
+// results and watches rebooted hosts until they are back online. The results stay
+// available afterwards through the Last results button of the power action form.
+func (t *TUIApp) runPowerAction(servers []config.Server, action, command string) {
+	t.showTransientStatus(fmt.Sprintf("[yellow]Running %s on %d server(s)...[white]", action, len(servers)))
+
+	op, ctx := t.tasks.start(taskSpec{Name: action, Total: len(servers), Cancellable: true})
+	go func() {
+		defer op.Finish()
+		var b strings.Builder
+		var succeeded []string
+		for i, server := range servers {
+			if ctx.Err() != nil {
+				fmt.Fprintf(&b, "[gray]- %s[white]: skipped, cancelled\n", server.Name)
+				continue
+			}
+			op.Update(i, len(servers), server.Name)
+			output, err := t.connectionManager.RunPowerAction(server, action, command)
+			if err != nil {
+				fmt.Fprintf(&b, "[red]✗ %s[white]: %s\n", server.Name, tview.Escape(err.Error()))
+			} else {
+				succeeded = append(succeeded, server.Name)
+				fmt.Fprintf(&b, "[green]✓ %s[white]: %s sent\n", server.Name, action)
+			}
+			if output != "" {
+				fmt.Fprintf(&b, "[gray]%s[white]\n", tview.Escape(output))
+			}
+		}
+
+		note := ""
+		if power.WatchAfter(action) && len(succeeded) > 0 {
+			note = "[yellow]Watching rebooted servers until they are back online[white]"
+			b.WriteString("\n" + note + "\n")
+		}
+		b.WriteString("\n[gray]Press Enter, Escape or q to close[white]")
+
+		t.app.QueueUpdateDraw(func() {
+			if power.WatchAfter(action) {
+				for _, serverName := range succeeded {
+					t.watchUntilOnline(serverName)
+				}
+			}
+			if t.connectionManager.GetHistoryManager() != nil {
+				t.showCommandResults(action, command, note)
+				return
+			}
+			t.showTextPanel(fmt.Sprintf("Power › %s", action), b.String())
+		})
+	}()
+}
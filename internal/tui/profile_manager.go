@@ -40,16 +40,11 @@ func (t *TUIApp) CreateProfileForm() *TUIForm {
 			Servers:     []string{},
 		}
 
-		// Add profile to configuration
-		if err := t.config.AddProfile(profile); err != nil {
+		// Add profile to configuration and save it
+		if err := t.service().CreateProfile(profile); err != nil {
 			return &ValidationError{Field: "general", Message: fmt.Sprintf("Failed to add profile: %s", err.Error())}
 		}
 
-		// Save configuration
-		if err := t.config.Save(); err != nil {
-			return &ValidationError{Field: "general", Message: fmt.Sprintf("Failed to save configuration: %s", err.Error())}
-		}
-
 		// Refresh UI
 		t.initializeProfileTabs()
 		t.updateProfileDisplay()
@@ -107,24 +102,14 @@ func (t *TUIApp) CreateEditProfileForm(profileName string) *TUIForm {
 	}
 
 	onSubmit := func(data map[string]interface{}) error {
-		// Update profile configuration
-		updatedProfile := config.Profile{
-			Name:        data["name"].(string),
-			Description: data["description"].(string),
-			Servers:     profile.Servers, // Keep existing server assignments
-		}
+		// Update profile configuration, keeping server assignments and other settings
+		updatedProfile := *profile
+		updatedProfile.Name = data["name"].(string)
+		updatedProfile.Description = data["description"].(string)
 
-		// Find and replace the profile in configuration
-		for i, p := range t.config.Profiles {
-			if p.Name == profileName {
-				t.config.Profiles[i] = updatedProfile
-				break
-			}
-		}
-
-		// Save configuration
-		if err := t.config.Save(); err != nil {
-			return &ValidationError{Field: "general", Message: fmt.Sprintf("Failed to save configuration: %s", err.Error())}
+		// Replace the profile in configuration and save it
+		if err := t.service().UpdateProfile(profileName, updatedProfile); err != nil {
+			return &ValidationError{Field: "general", Message: fmt.Sprintf("Failed to update profile: %s", err.Error())}
 		}
 
 		// Refresh UI
@@ -306,16 +291,11 @@ func (t *TUIApp) CreateServerAssignmentForm(profileName string) *TUIForm {
 	onSubmit := func(data map[string]interface{}) error {
 		serverName := data["server"].(string)
 
-		// Assign server to profile
-		if err := t.config.AssignServerToProfile(serverName, profileName); err != nil {
+		// Assign server to profile and save the configuration
+		if err := t.service().AssignServer(serverName, profileName); err != nil {
 			return &ValidationError{Field: "general", Message: fmt.Sprintf("Failed to assign server: %s", err.Error())}
 		}
 
-		// Save configuration
-		if err := t.config.Save(); err != nil {
-			return &ValidationError{Field: "general", Message: fmt.Sprintf("Failed to save configuration: %s", err.Error())}
-		}
-
 		// Refresh UI
 		t.initializeProfileTabs()
 		t.updateProfileDisplay()
@@ -416,16 +396,11 @@ func (t *TUIApp) CreateServerUnassignmentForm(profileName string) *TUIForm {
 	onSubmit := func(data map[string]interface{}) error {
 		serverName := data["server"].(string)
 
-		// Unassign server from profile
-		if err := t.config.UnassignServerFromProfile(serverName, profileName); err != nil {
+		// Unassign server from profile and save the configuration
+		if err := t.service().UnassignServer(serverName, profileName); err != nil {
 			return &ValidationError{Field: "general", Message: fmt.Sprintf("Failed to unassign server: %s", err.Error())}
 		}
 
-		// Save configuration
-		if err := t.config.Save(); err != nil {
-			return &ValidationError{Field: "general", Message: fmt.Sprintf("Failed to save configuration: %s", err.Error())}
-		}
-
 		// Refresh UI
 		t.initializeProfileTabs()
 		t.updateProfileDisplay()
@@ -554,16 +529,9 @@ func (t *TUIApp) getUnassignedServers(profileName string) []config.Server {
 
 // deleteProfileFromConfig removes a profile from the configuration
 func (t *TUIApp) deleteProfileFromConfig(profileName string) error {
-	// Remove profile from configuration
-	if err := t.config.RemoveProfile(profileName); err != nil {
+	if err := t.service().DeleteProfile(profileName); err != nil {
 		return fmt.Errorf("failed to remove profile: %w", err)
 	}
-
-	// Save the updated configuration
-	if err := t.config.Save(); err != nil {
-		return fmt.Errorf("failed to save configuration: %w", err)
-	}
-
 	return nil
 }
 
@@ -639,6 +607,18 @@ func (t *TUIApp) ShowEditProfileModal(profileName string) {
 
 // ShowDeleteProfileModal displays the delete profile confirmation modal
 func (t *TUIApp) ShowDeleteProfileModal(profileName string) {
+	// Delete straight away when the prompt is turned off for this profile
+	if _, err := t.config.GetProfile(profileName); err == nil && !t.shouldConfirm(config.ConfirmDeleteProfile, t.isProfileProtected(profileName)) {
+		if err := t.deleteProfileFromConfig(profileName); err != nil {
+			t.showErrorModal(fmt.Sprintf("Error deleting profile: %s", err.Error()))
+			return
+		}
+		t.initializeProfileTabs()
+		t.updateProfileDisplay()
+		t.refreshServerList()
+		return
+	}
+
 	modal := t.CreateDeleteProfileModal(profileName)
 
 	if t.modalManager != nil {
@@ -0,0 +1,152 @@
+package tui
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"sshm/internal/recording"
+)
+
+// recordingIdleLimit caps pauses when playing recordings in the TUI, so a
+// session left idle doesn't look like a frozen player
+const recordingIdleLimit = 2 * time.Second
+
+// showRecordings lists the recorded sessions, newest first. Enter plays the
+// selected recording in the terminal and d deletes it.
+func (t *TUIApp) showRecordings() {
+	dir, err := t.config.Recording.Path()
+	if err != nil {
+		t.showErrorModal(fmt.Sprintf("Invalid recordings directory: %s", err.Error()))
+		return
+	}
+	recordings, err := recording.List(dir)
+	if err != nil {
+		t.showErrorModal(err.Error())
+		return
+	}
+	if len(recordings) == 0 {
+		t.showTransientStatus(fmt.Sprintf("[yellow]No recordings in %s; record a server with 'sshm recordings enable <server>'[white]", tview.Escape(dir)))
+		return
+	}
+
+	table := tview.NewTable().SetSelectable(true, false).SetFixed(1, 0)
+	table.SetBorder(true).SetTitle(fmt.Sprintf(" ⏺ Recordings (%d) ", len(recordings)))
+	table.SetSelectedStyle(tcell.StyleDefault.Background(tcell.ColorDarkBlue).Foreground(tcell.ColorWhite))
+	fillRecordingsTable(table, recordings)
+	table.Select(1, 0)
+
+	footer := tview.NewTextView().SetDynamicColors(true).
+		SetText("[gray]Enter: play (Ctrl+C stops)  •  d: delete  •  Escape/q: close[white]")
+	panel := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(table, 0, 1, true).
+		AddItem(footer, 1, 0, false)
+
+	selected := func() (recording.Recording, bool) {
+		row, _ := table.GetSelection()
+		if row <= 0 || row > len(recordings) {
+			return recording.Recording{}, false
+		}
+		return recordings[row-1], true
+	}
+
+	table.SetSelectedFunc(func(row, _ int) {
+		if rec, ok := selected(); ok {
+			t.playRecording(rec)
+		}
+	})
+	panel.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case event.Key() == tcell.KeyEscape || event.Rune() == 'q' || event.Rune() == 'Q':
+			t.modalManager.HideModal()
+			return nil
+		case event.Rune() == 'd' || event.Rune() == 'D':
+			rec, ok := selected()
+			if !ok {
+				return nil
+			}
+			t.confirmDeleteRecording(rec, func() {
+				row, _ := table.GetSelection()
+				recordings = append(recordings[:row-1], recordings[row:]...)
+				if len(recordings) == 0 {
+					t.modalManager.HideModal()
+					return
+				}
+				table.SetTitle(fmt.Sprintf(" ⏺ Recordings (%d) ", len(recordings)))
+				fillRecordingsTable(table, recordings)
+				if row > len(recordings) {
+					row = len(recordings)
+				}
+				table.Select(row, 0)
+			})
+			return nil
+		}
+		return event
+	})
+
+	t.modalManager.ShowModal(panel)
+}
+
+// fillRecordingsTable shows recordings in table below its header
+func fillRecordingsTable(table *tview.Table, recordings []recording.Recording) {
+	table.Clear()
+	for column, header := range []string{"Started", "Duration", "Size", "Title"} {
+		table.SetCell(0, column, tview.NewTableCell(header).SetTextColor(tcell.ColorYellow).SetSelectable(false))
+	}
+	for i, rec := range recordings {
+		row := i + 1
+		title := tview.Escape(rec.Title)
+		titleColor := tcell.ColorWhite
+		if rec.Problem != "" {
+			title = "unreadable: " + tview.Escape(rec.Problem)
+			titleColor = tcell.ColorRed
+		}
+		table.SetCell(row, 0, tview.NewTableCell(rec.Started.Format("2006-01-02 15:04")).SetTextColor(tcell.ColorLightGray))
+		table.SetCell(row, 1, tview.NewTableCell(rec.Duration.Round(time.Second).String()).SetTextColor(tcell.ColorLightBlue).SetAlign(tview.AlignRight))
+		table.SetCell(row, 2, tview.NewTableCell(recording.FormatSize(rec.Size)).SetTextColor(tcell.ColorLightGray).SetAlign(tview.AlignRight))
+		table.SetCell(row, 3, tview.NewTableCell(title).SetTextColor(titleColor).SetExpansion(1))
+	}
+}
+
+// playRecording suspends the TUI and plays the recording in the terminal
+func (t *TUIApp) playRecording(rec recording.Recording) {
+	cast, err := recording.Load(rec.Path)
+	if err != nil {
+		t.showErrorModal(fmt.Sprintf("Failed to read recording: %s", err.Error()))
+		return
+	}
+
+	var playErr error
+	t.app.Suspend(func() {
+		playErr = recording.PlayTerminal(cast, recording.PlayOptions{IdleLimit: recordingIdleLimit})
+		fmt.Print("Press Enter to return to sshm")
+		bufio.NewReader(os.Stdin).ReadString('\n')
+	})
+	if playErr != nil {
+		t.showErrorModal(fmt.Sprintf("Playback failed: %s", playErr.Error()))
+	}
+}
+
+// confirmDeleteRecording deletes the recording once confirmed, then calls deleted
+func (t *TUIApp) confirmDeleteRecording(rec recording.Recording, deleted func()) {
+	modal := tview.NewModal().
+		SetText(fmt.Sprintf("Delete the recording %s from %s?", tview.Escape(rec.Title), rec.Started.Format("2006-01-02 15:04"))).
+		AddButtons([]string{"Delete", "Cancel"}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			t.modalManager.HideModal()
+			if buttonLabel != "Delete" {
+				return
+			}
+			if err := os.Remove(rec.Path); err != nil {
+				t.showErrorModal(fmt.Sprintf("Failed to delete recording: %s", err.Error()))
+				return
+			}
+			deleted()
+		}).
+		SetBackgroundColor(tcell.ColorDarkRed)
+	modal.SetTitle(" Delete recording ")
+	t.modalManager.ShowModal(modal)
+}
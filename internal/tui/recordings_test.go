@@ -0,0 +1,36 @@
+package tui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"sshm/internal/recording"
+)
+
+func TestFillRecordingsTable(t *testing.T) {
+	table := tview.NewTable()
+	started := time.Date(2026, 10, 18, 9, 30, 0, 0, time.Local)
+	fillRecordingsTable(table, []recording.Recording{
+		{Title: "prod:web1", Started: started, Duration: 90*time.Second + 400*time.Millisecond, Size: 2048},
+		{Title: "broken", Started: started, Problem: "invalid recording header"},
+	})
+
+	if rows := table.GetRowCount(); rows != 3 {
+		t.Fatalf("Expected a header and 2 rows, got %d", rows)
+	}
+	if text := table.GetCell(1, 0).Text; text != "2026-10-18 09:30" {
+		t.Errorf("Expected the start time, got %q", text)
+	}
+	if text := table.GetCell(1, 1).Text; text != "1m30s" {
+		t.Errorf("Expected the duration rounded to seconds, got %q", text)
+	}
+	if text := table.GetCell(1, 2).Text; text != "2.0 KB" {
+		t.Errorf("Expected the size, got %q", text)
+	}
+	cell := table.GetCell(2, 3)
+	if color, _, _ := cell.Style.Decompose(); cell.Text != "unreadable: invalid recording header" || color != tcell.ColorRed {
+		t.Errorf("Expected the unreadable recording in red, got %q", cell.Text)
+	}
+}
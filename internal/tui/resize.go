@@ -0,0 +1,71 @@
+package tui
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// Terminal size limits. Below the minimum the interface is replaced by an
+// overlay asking for a larger terminal; below the compact width the profiles
+// and sessions pane is hidden so the server list keeps readable columns.
+const (
+	minTerminalWidth    = 60
+	minTerminalHeight   = 15
+	compactLayoutWidth  = 100
+	compactLayoutHeight = 24
+)
+
+// setupResizeHandling reflows the layout whenever the terminal size changes
+func (t *TUIApp) setupResizeHandling() {
+	t.app.SetBeforeDrawFunc(func(screen tcell.Screen) bool {
+		width, height := screen.Size()
+		t.applyTerminalSize(width, height)
+		if t.terminalTooSmall {
+			drawTooSmallOverlay(screen, width, height)
+			return true // Skip drawing the panels, they would only be corrupted
+		}
+		return false
+	})
+}
+
+// applyTerminalSize switches between the full, compact and too-small layouts
+func (t *TUIApp) applyTerminalSize(width, height int) {
+	t.terminalTooSmall = width < minTerminalWidth || height < minTerminalHeight
+
+	compact := width < compactLayoutWidth
+	if compact != t.compactLayout {
+		t.compactLayout = compact
+		t.resizeServerPanel(0)
+	}
+
+	// The profile tabs are optional on short terminals; the tab name is in the status bar
+	if t.rightPane != nil && t.profileNavigator != nil {
+		navigatorHeight := 3
+		if height < compactLayoutHeight {
+			navigatorHeight = 0
+		}
+		t.rightPane.ResizeItem(t.profileNavigator, navigatorHeight, 0)
+	}
+}
+
+// drawTooSmallOverlay replaces the interface with the required terminal size
+func drawTooSmallOverlay(screen tcell.Screen, width, height int) {
+	screen.Clear()
+
+	lines := []string{
+		"[yellow::b]Terminal too small[::-]",
+		fmt.Sprintf("[white]Current: %dx%d", width, height),
+		fmt.Sprintf("[white]Required: %dx%d", minTerminalWidth, minTerminalHeight),
+		"[gray]Resize the window or press q to quit",
+	}
+
+	top := (height - len(lines)) / 2
+	if top < 0 {
+		top = 0
+	}
+	for i, line := range lines {
+		tview.Print(screen, line, 0, top+i, width, tview.AlignCenter, tcell.ColorWhite)
+	}
+}
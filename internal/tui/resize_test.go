@@ -0,0 +1,67 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestApplyTerminalSize(t *testing.T) {
+	t.Setenv("SSHM_CONFIG_DIR", t.TempDir())
+
+	app, err := NewTUIApp()
+	if err != nil {
+		t.Fatalf("Failed to create TUI app: %v", err)
+	}
+
+	app.applyTerminalSize(160, 50)
+	if app.compactLayout || app.terminalTooSmall {
+		t.Errorf("Expected the full layout on a large terminal")
+	}
+
+	app.applyTerminalSize(80, 20)
+	if !app.compactLayout || app.terminalTooSmall {
+		t.Errorf("Expected the compact layout on an 80x20 terminal")
+	}
+
+	app.applyTerminalSize(40, 10)
+	if !app.terminalTooSmall {
+		t.Errorf("Expected the too small overlay on a 40x10 terminal")
+	}
+
+	app.applyTerminalSize(120, 40)
+	if app.compactLayout || app.terminalTooSmall {
+		t.Errorf("Expected the full layout to come back after growing the terminal")
+	}
+}
+
+func TestDrawTooSmallOverlay(t *testing.T) {
+	screen := tcell.NewSimulationScreen("")
+	if err := screen.Init(); err != nil {
+		t.Fatalf("Failed to init screen: %v", err)
+	}
+	defer screen.Fini()
+	screen.SetSize(50, 10)
+
+	drawTooSmallOverlay(screen, 50, 10)
+	screen.Show()
+
+	cells, width, height := screen.GetContents()
+	var text strings.Builder
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			for _, r := range cells[y*width+x].Runes {
+				text.WriteRune(r)
+			}
+		}
+		text.WriteRune('\n')
+	}
+
+	output := text.String()
+	for _, expected := range []string{"Terminal too small", "Current: 50x10", "Required: 60x15"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected overlay to contain %q, got:\n%s", expected, output)
+		}
+	}
+}
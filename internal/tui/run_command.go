@@ -0,0 +1,299 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"sshm/internal/config"
+	sshmexec "sshm/internal/exec"
+	"sshm/internal/power"
+)
+
+// showRunCommandForm runs a command on every server of a profile in parallel,
+// the active profile being preselected
+func (t *TUIApp) showRunCommandForm() {
+	profiles := t.config.GetProfiles()
+	if len(profiles) == 0 {
+		t.showErrorModal("No profiles yet; add servers to a profile to run commands on them")
+		return
+	}
+	names := make([]string, len(profiles))
+	selected := 0
+	for i, profile := range profiles {
+		names[i] = profile.Name
+		if profile.Name == t.currentFilter {
+			selected = i
+		}
+	}
+
+	form := tview.NewForm().
+		AddDropDown("Profile", names, selected, nil).
+		AddInputField("Command", "", 50, nil, nil).
+		AddInputField("Parallel", strconv.Itoa(sshmexec.DefaultConcurrency), 5, tview.InputFieldInteger, nil).
+		AddInputField("Confirm (protected)", "", 30, nil, nil).
+		AddButton("Run", nil).
+		AddButton("Cancel", nil).
+		AddButton("Last results", nil)
+	form.SetBorder(true).
+		SetTitle(" : Run Command on Profile ").
+		SetTitleAlign(tview.AlignCenter)
+
+	profileDropdown := form.GetFormItem(0).(*tview.DropDown)
+	commandField := form.GetFormItem(1).(*tview.InputField)
+	parallelField := form.GetFormItem(2).(*tview.InputField)
+	confirmField := form.GetFormItem(3).(*tview.InputField)
+
+	form.GetButton(0).SetSelectedFunc(func() {
+		_, profileName := profileDropdown.GetCurrentOption()
+		command := strings.TrimSpace(commandField.GetText())
+		if command == "" {
+			t.showErrorModal("A command is required")
+			return
+		}
+		parallel, _ := strconv.Atoi(parallelField.GetText())
+
+		servers, err := t.config.GetServersByProfile(profileName)
+		if err != nil || len(servers) == 0 {
+			t.showErrorModal(fmt.Sprintf("No servers found in profile '%s'", profileName))
+			return
+		}
+		if power.RequiresTypedConfirmation(servers) && !power.ConfirmationMatches(profileName, confirmField.GetText()) {
+			t.showErrorModal(fmt.Sprintf("'%s' includes protected servers.\n\nType '%s' in the Confirm field to proceed.", profileName, profileName))
+			return
+		}
+
+		t.modalManager.HideModal()
+		t.runCommandOnServers(profileName, servers, command, parallel)
+	})
+	form.GetButton(1).SetSelectedFunc(func() {
+		t.modalManager.HideModal()
+	})
+	form.GetButton(2).SetSelectedFunc(func() {
+		t.modalManager.HideModal()
+		t.showCommandRuns()
+	})
+	form.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape {
+			t.modalManager.HideModal()
+			return nil
+		}
+		return event
+	})
+
+	t.modalManager.ShowModal(form)
+}
+
+// commandRunHost is the state of one server in the results panel of a parallel run
+type commandRunHost struct {
+	status string // A sshmexec status, "running", or empty while waiting
+	output strings.Builder
+	err    error
+}
+
+// commandRunStatusIcon marks a server in the results panel of a parallel run
+func commandRunStatusIcon(status string) string {
+	switch status {
+	case sshmexec.StatusSucceeded:
+		return "[green]✓[white]"
+	case sshmexec.StatusFailed:
+		return "[red]✗[white]"
+	case sshmexec.StatusCancelled:
+		return "[gray]-[white]"
+	case "running":
+		return "[yellow]⏳[white]"
+	default:
+		return "[gray]·[white]"
+	}
+}
+
+// renderCommandRunSummary describes the progress of a parallel run
+func renderCommandRunSummary(command string, results []sshmexec.Result, running, total int, elapsed time.Duration) string {
+	summary := sshmexec.Summarize(results)
+	finished := len(results) == total && running == 0
+	state := fmt.Sprintf("[yellow]%d running, %d waiting[white]", running, total-len(results)-running)
+	if finished {
+		state = "[green]done[white]"
+		if summary.Failed+summary.Cancelled > 0 {
+			state = "[red]done with failures[white]"
+		}
+	}
+	return fmt.Sprintf(" [::b]%s[::-]  %s  [green]%d ✓[white]  [red]%d ✗[white]  %d/%d in %v",
+		tview.Escape(command), state, summary.Succeeded, summary.Failed+summary.Cancelled, len(results), total, elapsed.Round(time.Second))
+}
+
+// runCommandOnServers runs the command on the servers in parallel, streaming
+// each host's output into a results panel: the hosts on the left with their
+// status, the output of the selected one on the right. Closing the panel leaves
+// the run going in the background; its results stay available through Last
+// results.
+func (t *TUIApp) runCommandOnServers(profileName string, servers []config.Server, command string, parallel int) {
+	op, taskCtx := t.tasks.start(taskSpec{Name: fmt.Sprintf("run '%s' on %s", command, profileName), Unit: "servers", Total: len(servers), Cancellable: true})
+	ctx, cancel := context.WithCancel(taskCtx)
+
+	hosts := make(map[string]*commandRunHost, len(servers))
+	var results []sshmexec.Result
+	running := 0
+	started := time.Now()
+
+	summaryView := tview.NewTextView().SetDynamicColors(true)
+	hostList := tview.NewList().ShowSecondaryText(false).SetHighlightFullLine(true)
+	hostList.SetBorder(true).SetTitle(" Servers ")
+	outputView := tview.NewTextView().SetDynamicColors(true).SetScrollable(true).SetWrap(true)
+	outputView.SetBorder(true)
+	footer := tview.NewTextView().SetDynamicColors(true).
+		SetText(" [yellow]↑/↓[white] server  [yellow]x[white] cancel  [yellow]r[white] results (re-run failed)  [yellow]Esc/q[white] close, keeps running")
+
+	selectedHost := func() string {
+		if len(servers) == 0 {
+			return ""
+		}
+		return servers[hostList.GetCurrentItem()].Name
+	}
+	showHost := func(name string) {
+		host := hosts[name]
+		outputView.SetTitle(fmt.Sprintf(" %s ", tview.Escape(name)))
+		text := tview.Escape(host.output.String())
+		if host.err != nil {
+			text += fmt.Sprintf("\n[red]%s[white]\n", tview.Escape(host.err.Error()))
+		}
+		outputView.SetText(text).ScrollToEnd()
+	}
+	updateHost := func(index int) {
+		server := servers[index]
+		hostList.SetItemText(index, fmt.Sprintf("%s %s", commandRunStatusIcon(hosts[server.Name].status), tview.Escape(server.Name)), "")
+	}
+	updateSummary := func() {
+		summaryView.SetText(renderCommandRunSummary(command, results, running, len(servers), time.Since(started)))
+	}
+
+	for i, server := range servers {
+		hosts[server.Name] = &commandRunHost{}
+		hostList.AddItem("", "", 0, nil)
+		updateHost(i)
+	}
+	hostList.SetChangedFunc(func(index int, mainText, secondaryText string, shortcut rune) {
+		showHost(servers[index].Name)
+	})
+	showHost(servers[0].Name)
+	updateSummary()
+
+	indexOf := make(map[string]int, len(servers))
+	for i, server := range servers {
+		indexOf[server.Name] = i
+	}
+
+	layout := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(summaryView, 1, 0, false).
+		AddItem(tview.NewFlex().
+			AddItem(hostList, 30, 0, true).
+			AddItem(outputView, 0, 1, false), 0, 1, true).
+		AddItem(footer, 1, 0, false)
+	layout.SetBorder(true).
+		SetTitle(fmt.Sprintf(" Run › %s ", tview.Escape(profileName))).
+		SetBorderColor(tcell.ColorYellow)
+	hostList.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEscape:
+			t.modalManager.HideModal()
+			return nil
+		case tcell.KeyPgUp, tcell.KeyPgDn:
+			outputView.InputHandler()(event, nil)
+			return nil
+		}
+		switch event.Rune() {
+		case 'q':
+			t.modalManager.HideModal()
+			return nil
+		case 'x':
+			cancel()
+			return nil
+		case 'r':
+			if len(results) < len(servers) {
+				t.showTransientStatus("[yellow]Still running; results are listed once every server is done[white]")
+				return nil
+			}
+			t.modalManager.HideModal()
+			t.showCommandResults(power.ActionCustom, command, "")
+			return nil
+		}
+		return event
+	})
+	t.modalManager.ShowModal(layout)
+
+	// Keep the elapsed time moving while servers are slow to answer
+	ticker := time.NewTicker(time.Second)
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				t.app.QueueUpdateDraw(updateSummary)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	opts := sshmexec.Options{
+		Concurrency: parallel,
+		OnStart: func(server config.Server) {
+			t.app.QueueUpdateDraw(func() {
+				running++
+				hosts[server.Name].status = "running"
+				updateHost(indexOf[server.Name])
+				updateSummary()
+			})
+		},
+		OnLine: func(server config.Server, line string) {
+			t.app.QueueUpdateDraw(func() {
+				host := hosts[server.Name]
+				host.output.WriteString(line + "\n")
+				if selectedHost() == server.Name {
+					fmt.Fprintln(outputView, tview.Escape(line))
+					outputView.ScrollToEnd()
+				}
+			})
+		},
+		OnResult: func(result sshmexec.Result) {
+			t.app.QueueUpdateDraw(func() {
+				host := hosts[result.Server.Name]
+				if host.status == "running" {
+					running--
+				}
+				host.status = result.Status
+				host.err = result.Err
+				results = append(results, result)
+				op.Update(len(results), len(servers), result.Server.Name)
+				updateHost(indexOf[result.Server.Name])
+				if selectedHost() == result.Server.Name {
+					showHost(result.Server.Name)
+				}
+				updateSummary()
+			})
+		},
+	}
+
+	go func() {
+		defer op.Finish()
+		defer cancel()
+		defer ticker.Stop()
+		if _, err := t.connectionManager.RunCommand(ctx, servers, command, opts); err != nil {
+			t.app.QueueUpdateDraw(func() {
+				t.showErrorModal(fmt.Sprintf("Run failed: %s", err.Error()))
+			})
+			return
+		}
+		t.app.QueueUpdateDraw(func() {
+			summary := sshmexec.Summarize(results)
+			status := fmt.Sprintf("[green]✓ '%s' on %s: %s[white]", tview.Escape(command), tview.Escape(profileName), summary)
+			if summary.Failed+summary.Cancelled > 0 {
+				status = fmt.Sprintf("[red]✗ '%s' on %s: %s[white]", tview.Escape(command), tview.Escape(profileName), summary)
+			}
+			t.showTransientStatus(status)
+		})
+	}()
+}
@@ -0,0 +1,38 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"sshm/internal/config"
+	sshmexec "sshm/internal/exec"
+)
+
+func TestRenderCommandRunSummary(t *testing.T) {
+	results := []sshmexec.Result{
+		{Server: config.Server{Name: "web1"}, Status: sshmexec.StatusSucceeded},
+		{Server: config.Server{Name: "web2"}, Status: sshmexec.StatusFailed, Err: errors.New("exit status 1")},
+	}
+
+	summary := renderCommandRunSummary("uptime", results, 1, 4, 3*time.Second)
+	for _, want := range []string{"uptime", "1 running, 1 waiting", "1 ✓", "1 ✗", "2/4 in 3s"} {
+		if !strings.Contains(summary, want) {
+			t.Errorf("Expected %q in the summary of a run in progress, got %q", want, summary)
+		}
+	}
+
+	summary = renderCommandRunSummary("uptime", results, 0, 2, 5*time.Second)
+	if !strings.Contains(summary, "done with failures") {
+		t.Errorf("Expected a finished run with a failure to say so, got %q", summary)
+	}
+	summary = renderCommandRunSummary("uptime", results[:1], 0, 1, 5*time.Second)
+	if !strings.Contains(summary, "[green]done") {
+		t.Errorf("Expected a successful run to be done, got %q", summary)
+	}
+
+	if commandRunStatusIcon("running") == commandRunStatusIcon("") {
+		t.Error("Expected running and waiting servers to be told apart")
+	}
+}
@@ -0,0 +1,219 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"sshm/internal/runbook"
+)
+
+// showRunbooks browses the runbooks directory, starting with the runbooks of
+// the selected server when it has any. '/' searches every runbook and 'a'
+// switches between the server's runbooks and all of them.
+func (t *TUIApp) showRunbooks() {
+	dir, err := t.config.Runbooks.Path()
+	if err != nil {
+		t.showErrorModal(fmt.Sprintf("Invalid runbooks directory: %s", err.Error()))
+		return
+	}
+	if dir == "" {
+		t.showTransientStatus("[yellow]No runbooks directory; set runbooks.dir to a folder of markdown files[white]")
+		return
+	}
+	all, err := runbook.Load(dir)
+	if err != nil {
+		t.showErrorModal(err.Error())
+		return
+	}
+	if len(all) == 0 {
+		t.showTransientStatus(fmt.Sprintf("[yellow]No markdown runbooks in %s[white]", tview.Escape(dir)))
+		return
+	}
+
+	var forServer []runbook.Runbook
+	serverName := ""
+	if t.focusedPanel == "servers" {
+		if name := t.getSelectedServerName(); name != "" {
+			if server, err := t.config.GetServer(name); err == nil {
+				serverName = server.Name
+				forServer = runbook.ForServer(all, t.config, *server)
+			}
+		}
+	}
+
+	list := tview.NewList().ShowSecondaryText(true)
+	list.SetBorder(true)
+	content := tview.NewTextView().
+		SetDynamicColors(true).
+		SetScrollable(true).
+		SetWrap(true)
+	content.SetBorder(true)
+	search := tview.NewInputField().
+		SetLabel("Search: ").
+		SetFieldBackgroundColor(tcell.ColorDarkSlateGray)
+	footer := tview.NewTextView().SetDynamicColors(true)
+	help := "[gray]Enter/Tab: read  •  /: search  •  a: this server/all  •  Escape/q: close[white]"
+	footer.SetText(help)
+
+	columns := tview.NewFlex().
+		AddItem(list, 0, 1, true).
+		AddItem(content, 0, 2, false)
+	panel := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(columns, 0, 1, true).
+		AddItem(footer, 1, 0, false)
+
+	showRunbook := func(book runbook.Runbook, line int) {
+		content.SetTitle(fmt.Sprintf(" %s ", tview.Escape(book.Path)))
+		text := renderMarkdown(book.Body, line)
+		if book.Problem != "" {
+			text = fmt.Sprintf("[red]%s[white]\n\n%s", tview.Escape(book.Problem), text)
+		}
+		content.SetText(text)
+		if line > 0 {
+			content.ScrollTo(line, 0)
+		} else {
+			content.ScrollToBeginning()
+		}
+	}
+
+	// listRunbooks fills the list with runbooks, showing the selected one
+	listRunbooks := func(title string, books []runbook.Runbook) {
+		list.Clear()
+		list.SetTitle(fmt.Sprintf(" 📖 %s ", tview.Escape(title)))
+		// Set before adding items, which reports the first one as selected
+		list.SetChangedFunc(func(index int, _, _ string, _ rune) {
+			showRunbook(books[index], -1)
+		})
+		for _, book := range books {
+			list.AddItem(tview.Escape(book.Title), tview.Escape(book.Path), 0, func() {
+				t.app.SetFocus(content)
+			})
+		}
+		if len(books) > 0 {
+			list.SetCurrentItem(0)
+			showRunbook(books[0], -1)
+		} else {
+			content.SetTitle("")
+			content.SetText("[gray]No runbooks[white]")
+		}
+	}
+
+	// listHits fills the list with search results, showing each at its line
+	listHits := func(query string, hits []runbook.Hit) {
+		list.Clear()
+		list.SetTitle(fmt.Sprintf(" 🔍 %d match(es) for %q ", len(hits), query))
+		list.SetChangedFunc(func(index int, _, _ string, _ rune) {
+			showRunbook(all[hits[index].Runbook], hits[index].Line)
+		})
+		for _, hit := range hits {
+			list.AddItem(tview.Escape(all[hit.Runbook].Title), tview.Escape(hit.Text), 0, func() {
+				t.app.SetFocus(content)
+			})
+		}
+		if len(hits) > 0 {
+			list.SetCurrentItem(0)
+			showRunbook(all[hits[0].Runbook], hits[0].Line)
+		} else {
+			content.SetTitle("")
+			content.SetText("[gray]Nothing found[white]")
+		}
+	}
+
+	showingAll := serverName == "" || len(forServer) == 0
+	showScope := func() {
+		if showingAll {
+			listRunbooks("All runbooks", all)
+		} else {
+			listRunbooks("Runbooks › "+serverName, forServer)
+		}
+	}
+	showScope()
+	if serverName != "" && len(forServer) == 0 {
+		footer.SetText(fmt.Sprintf("[yellow]No runbooks mention %s; showing all[white]  •  %s", tview.Escape(serverName), help))
+	}
+
+	closeSearch := func() {
+		panel.RemoveItem(search)
+		t.app.SetFocus(list)
+	}
+	search.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEnter {
+			query := strings.TrimSpace(search.GetText())
+			if query != "" {
+				listHits(query, runbook.Search(all, query))
+			}
+		}
+		closeSearch()
+	})
+
+	panel.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if t.app.GetFocus() == search {
+			return event
+		}
+		switch {
+		case event.Key() == tcell.KeyEscape || event.Rune() == 'q' || event.Rune() == 'Q':
+			if t.app.GetFocus() == content {
+				t.app.SetFocus(list)
+				return nil
+			}
+			t.modalManager.HideModal()
+			return nil
+		case event.Key() == tcell.KeyTab:
+			if t.app.GetFocus() == content {
+				t.app.SetFocus(list)
+			} else {
+				t.app.SetFocus(content)
+			}
+			return nil
+		case event.Rune() == '/':
+			search.SetText("")
+			panel.AddItem(search, 1, 0, true)
+			t.app.SetFocus(search)
+			return nil
+		case event.Rune() == 'a' || event.Rune() == 'A':
+			if serverName != "" && len(forServer) > 0 {
+				showingAll = !showingAll
+			}
+			showScope()
+			t.app.SetFocus(list)
+			return nil
+		}
+		return event
+	})
+
+	t.modalManager.ShowModal(panel)
+}
+
+// renderMarkdown shows markdown as colored text: headings stand out, code
+// blocks are dimmed and list items get bullets. The highlighted line, counted
+// from 0, is shown reversed; -1 highlights none.
+func renderMarkdown(body string, highlight int) string {
+	var b strings.Builder
+	inCode := false
+	for number, line := range strings.Split(body, "\n") {
+		trimmed := strings.TrimSpace(line)
+		var rendered string
+		switch {
+		case strings.HasPrefix(trimmed, "```"):
+			inCode = !inCode
+			rendered = "[gray]" + tview.Escape(line) + "[white]"
+		case inCode:
+			rendered = "[gray]" + tview.Escape(line) + "[white]"
+		case strings.HasPrefix(trimmed, "#"):
+			rendered = "[yellow::b]" + tview.Escape(strings.TrimSpace(strings.TrimLeft(trimmed, "#"))) + "[white::-]"
+		case strings.HasPrefix(trimmed, "- ") || strings.HasPrefix(trimmed, "* "):
+			indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+			rendered = indent + "• " + tview.Escape(trimmed[2:])
+		default:
+			rendered = tview.Escape(line)
+		}
+		if number == highlight {
+			rendered = "[::r]" + rendered + "[::-]"
+		}
+		b.WriteString(rendered)
+		b.WriteString("\n")
+	}
+	return b.String()
+}
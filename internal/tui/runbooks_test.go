@@ -0,0 +1,25 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderMarkdown(t *testing.T) {
+	body := "# Disk full\n- clean [logs]\n```\nrm -rf /tmp/*\n```\nplain"
+	rendered := renderMarkdown(body, 5)
+	lines := strings.Split(rendered, "\n")
+
+	if lines[0] != "[yellow::b]Disk full[white::-]" {
+		t.Errorf("Expected a highlighted heading, got %q", lines[0])
+	}
+	if lines[1] != "• clean [logs[]" {
+		t.Errorf("Expected a bullet with escaped text, got %q", lines[1])
+	}
+	if lines[3] != "[gray]rm -rf /tmp/*[white]" {
+		t.Errorf("Expected code to be dimmed, got %q", lines[3])
+	}
+	if lines[5] != "[::r]plain[::-]" {
+		t.Errorf("Expected the highlighted line to be reversed, got %q", lines[5])
+	}
+}
@@ -0,0 +1,187 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rivo/tview"
+	"sshm/internal/config"
+	"sshm/internal/monitor"
+	sshmssh "sshm/internal/ssh"
+)
+
+// showServerDetails displays the details of the currently selected server
+func (t *TUIApp) showServerDetails() {
+	if t.focusedPanel != "servers" {
+		return
+	}
+
+	serverName := t.getSelectedServerName()
+	if serverName == "" {
+		return
+	}
+
+	server, err := t.config.GetServer(serverName)
+	if err != nil {
+		t.showErrorModal(fmt.Sprintf("Server '%s' not found: %s", serverName, err.Error()))
+		return
+	}
+
+	t.showTextPanel(fmt.Sprintf("Server › %s", server.Name), t.renderServerDetails(*server))
+}
+
+// getSelectedServerName returns the name of the server in the selected row, or "" if none
+func (t *TUIApp) getSelectedServerName() string {
+	currentRow, _ := t.serverList.GetSelection()
+	if currentRow <= 0 {
+		return ""
+	}
+
+	nameCell := t.serverList.GetCell(currentRow, 0)
+	if nameCell == nil {
+		return ""
+	}
+	return nameCell.Text
+}
+
+// renderServerDetails formats a server's configuration for the details panel
+func (t *TUIApp) renderServerDetails(server config.Server) string {
+	var b strings.Builder
+
+	addField := func(label, value string) {
+		if value == "" {
+			value = "[gray]-[white]"
+		}
+		fmt.Fprintf(&b, "[yellow]%-18s[white] %s\n", label+":", value)
+	}
+
+	addField("Name", server.Name)
+	addField("Hostname", server.Hostname)
+	addField("Effective address", server.GetEffectiveHostname())
+	if len(server.ProxyJump) > 0 {
+		addField("Jump chain", describeJumpChain(t.config, server))
+	}
+	addField("Aliases", strings.Join(server.Aliases, ", "))
+	addField("Tags", strings.Join(server.Tags, ", "))
+	addField("Port", fmt.Sprintf("%d", server.Port))
+	addField("Username", server.Username)
+	addField("Auth type", server.AuthType)
+	addField("Key path", server.KeyPath)
+	addField("Auth chain", describeAuthChain(server))
+	addField("Profiles", strings.Join(t.getServerProfiles(server.Name), ", "))
+	if !server.Metadata.IsEmpty() {
+		addField("Owner", server.Metadata.Owner)
+		addField("Team", server.Metadata.Team)
+		addField("Cost center", server.Metadata.CostCenter)
+		addField("Environment", server.Metadata.Environment)
+	}
+	if server.Timezone != "" {
+		addField("Timezone", server.Timezone)
+		addField("Local time", server.LocalTime(time.Now()))
+	}
+
+	addField("Tunnels", describeTunnels(server.Tunnels))
+	addField("Database", describeDatabase(server.Database))
+	if server.ExpiresAt != nil {
+		expiry := server.ExpiresAt.Local().Format("2006-01-02 15:04")
+		if server.IsExpiringSoon(time.Now()) {
+			expiry = "[orange]" + expiry + " (expiring soon)[white]"
+		}
+		addField("Expires", expiry)
+	}
+	if len(server.RemoteEnv) > 0 {
+		addField("Remote env", tview.Escape(strings.Join(config.FormatRemoteEnv(server.RemoteEnv), " ")))
+	}
+	if len(server.StartupCommands) > 0 {
+		addField("Startup commands", tview.Escape(strings.Join(server.StartupCommands, " ; ")))
+	}
+	addField("Pinned host keys", strings.Join(server.HostKeys, ", "))
+	if server.ControlMaster {
+		addField("Multiplexing", describeMultiplexing(server))
+	}
+	if server.Warm || server.Watch && t.config.WarmPool.Enabled {
+		addField("Warm connection", describeWarmConnection(server))
+	}
+	if probe, ok := monitor.LastProbe(server.Name); ok {
+		addField("SSH version", tview.Escape(probe.ServerVersion))
+	}
+	if !server.Credentials.IsEmpty() {
+		addField("Key rotation due", describeCredentialDate(server.Credentials.KeyRotationDue))
+		addField("Account expires", describeCredentialDate(server.Credentials.AccountExpires))
+	}
+	if server.Restricted != nil {
+		addField("Restricted to", strings.Join(server.Restricted.Commands(), ", "))
+	}
+
+	status, _ := t.getCachedConnectionStatus(server.Name)
+	addField("Status", status)
+	if result, ok := monitor.LastAuthResult(server.Name); ok {
+		if result.Succeeded != nil {
+			addField("Authenticated", "[green]"+tview.Escape(result.Summary())+"[white]")
+		} else {
+			addField("Authentication", "[red]"+tview.Escape(result.Summary())+"[white]")
+		}
+	}
+	if t.config.QuickStatsEnabled(server.Name) {
+		addField("Stats", t.getCachedQuickStats(server.Name))
+	}
+
+	b.WriteString("\n[gray]Press Enter, Escape or q to close[white]")
+	return b.String()
+}
+
+// describeJumpChain renders the hosts a server is reached through, e.g.
+// "bastion (admin@bastion.example.com) → 10.0.0.5:2222 → web"
+func describeJumpChain(cfg *config.Config, server config.Server) string {
+	hops, err := cfg.JumpChain(server)
+	if err != nil {
+		return "[red]" + tview.Escape(err.Error()) + "[white]"
+	}
+	parts := make([]string, 0, len(hops)+1)
+	for _, hop := range hops {
+		destination := hop.GetEffectiveHostname()
+		if hop.Username != "" {
+			destination = hop.Username + "@" + destination
+		}
+		if hop.Port != 0 && hop.Port != 22 {
+			destination = fmt.Sprintf("%s:%d", destination, hop.Port)
+		}
+		if destination == hop.Name {
+			parts = append(parts, tview.Escape(hop.Name))
+		} else {
+			parts = append(parts, fmt.Sprintf("%s [gray](%s)[white]", tview.Escape(hop.Name), tview.Escape(destination)))
+		}
+	}
+	return strings.Join(append(parts, tview.Escape(server.Name)), " → ")
+}
+
+// describeAuthChain renders the methods sshm tries in order, e.g. "agent → key:~/.ssh/work"
+func describeAuthChain(server config.Server) string {
+	steps, err := server.AuthSteps()
+	if err != nil {
+		return "[red]" + tview.Escape(err.Error()) + "[white]"
+	}
+	parts := make([]string, 0, len(steps))
+	for _, step := range steps {
+		parts = append(parts, step.String())
+	}
+	chain := strings.Join(parts, " → ")
+	if len(server.AuthChain) == 0 && chain != "" {
+		chain += " [gray](from auth type)[white]"
+	}
+	return chain
+}
+
+// describeMultiplexing tells whether status checks currently reuse a connection
+func describeMultiplexing(server config.Server) string {
+	pooled := sshmssh.IsMultiplexed(sshmssh.ClientConfig{
+		Hostname: server.GetEffectiveHostname(),
+		Port:     server.Port,
+		Username: server.Username,
+	})
+	if pooled {
+		return "controlmaster [green](status checks reuse an open connection)[white]"
+	}
+	return "controlmaster [gray](no open connection yet)[white]"
+}
@@ -0,0 +1,120 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"sshm/internal/config"
+	"sshm/internal/tmux"
+)
+
+// removalImpact returns what removing the server affects, including the
+// sessions open to it and the tunnels running through it
+func (t *TUIApp) removalImpact(serverName string) (config.RemovalImpact, error) {
+	impact, err := t.config.RemovalImpact(serverName)
+	if err != nil {
+		return impact, err
+	}
+	if t.tmuxManager != nil {
+		if sessions, err := t.tmuxManager.ListSessions(); err == nil {
+			var names []string
+			for _, server := range t.config.GetServers() {
+				names = append(names, server.Name)
+			}
+			impact.Sessions = tmux.SessionsOfServer(sessions, impact.Server.Name, names)
+		}
+	}
+	if t.tunnelManager != nil {
+		for _, tun := range t.tunnelManager.List() {
+			if tun.ServerName == impact.Server.Name {
+				impact.RunningTunnels++
+			}
+		}
+	}
+	return impact, nil
+}
+
+// removeServer removes a server once confirmed, showing what else the removal
+// affects. Removals that affect more than the server itself are confirmed even
+// when the delete_server prompt is turned off.
+func (t *TUIApp) removeServer(serverName string) {
+	impact, err := t.removalImpact(serverName)
+	if err != nil {
+		t.showErrorModal(fmt.Sprintf("Error deleting server: %s", err.Error()))
+		return
+	}
+	if !impact.HasEffects() && !t.shouldConfirm(config.ConfirmDeleteServer, impact.Server.Protected) {
+		t.finishServerRemoval(serverName, false)
+		return
+	}
+
+	modal := tview.NewModal().
+		SetText(removalText(impact)).
+		AddButtons([]string{"Delete", "Archive", "Cancel"}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			t.modalManager.HideModal()
+			switch buttonLabel {
+			case "Delete":
+				t.finishServerRemoval(serverName, false)
+			case "Archive":
+				t.finishServerRemoval(serverName, true)
+			}
+		}).
+		SetBackgroundColor(tcell.ColorDarkRed)
+	modal.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case event.Key() == tcell.KeyEscape:
+			t.modalManager.HideModal()
+			return nil
+		case event.Rune() == 'd' || event.Rune() == 'D':
+			// 'd' also confirms, consistent with the key that opened the modal
+			t.modalManager.HideModal()
+			t.finishServerRemoval(serverName, false)
+			return nil
+		case event.Rune() == 'a' || event.Rune() == 'A':
+			t.modalManager.HideModal()
+			t.finishServerRemoval(serverName, true)
+			return nil
+		}
+		return event
+	})
+	modal.SetTitle(" Delete server ")
+	t.modalManager.ShowModal(modal)
+}
+
+// removalText describes the removal of a server for the confirmation modal
+func removalText(impact config.RemovalImpact) string {
+	var text strings.Builder
+	fmt.Fprintf(&text, "Delete server '%s'?\n", impact.Server.Name)
+	if summary := impact.Summary(); len(summary) > 0 {
+		text.WriteString("\nThis also affects:\n")
+		for _, line := range summary {
+			fmt.Fprintf(&text, "• %s\n", line)
+		}
+	}
+	text.WriteString("\nDeleting can't be undone; Archive keeps the server for 'sshm archive restore'.")
+	return text.String()
+}
+
+// finishServerRemoval deletes or archives the server and refreshes the lists
+func (t *TUIApp) finishServerRemoval(serverName string, archive bool) {
+	if archive {
+		if err := t.archiveServerFromConfig(serverName); err != nil {
+			t.showErrorModal(fmt.Sprintf("Error archiving server: %s", err.Error()))
+			return
+		}
+		t.showTransientStatus(fmt.Sprintf("[green]Archived %s; restore it with 'sshm archive restore %s'[white]", tview.Escape(serverName), tview.Escape(serverName)))
+	} else if err := t.deleteServerFromConfig(serverName); err != nil {
+		t.showErrorModal(fmt.Sprintf("Error deleting server: %s", err.Error()))
+		return
+	}
+	t.refreshServerList()
+	t.refreshSessions()
+}
+
+// archiveServerFromConfig moves a server to the archive and saves the configuration
+func (t *TUIApp) archiveServerFromConfig(serverName string) error {
+	return t.service().RemoveServer(serverName, true)
+}
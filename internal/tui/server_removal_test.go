@@ -0,0 +1,26 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"sshm/internal/config"
+)
+
+func TestRemovalText(t *testing.T) {
+	impact := config.RemovalImpact{
+		Server:   config.Server{Name: "bastion"},
+		Profiles: []string{"prod"},
+		Sessions: []string{"bastion", "bastion-2"},
+	}
+	text := removalText(impact)
+	for _, want := range []string{"Delete server 'bastion'?", "This also affects:", "• Removed from profiles: prod", "• Open sessions: bastion, bastion-2", "Archive keeps"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected %q in:\n%s", want, text)
+		}
+	}
+
+	if text := removalText(config.RemovalImpact{Server: config.Server{Name: "web1"}}); strings.Contains(text, "This also affects") {
+		t.Errorf("Expected no effects for web1, got:\n%s", text)
+	}
+}
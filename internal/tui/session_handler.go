@@ -43,6 +43,10 @@ type TUIState struct {
 	SelectedSession      int
 	FocusedPanel         string
 	CurrentFilter        string
+	SearchFilter         string
+	SelectedServer       string // Selected server by name, so the selection survives list changes
+	ViewMode             string
+	ServerPanelWidth     int
 }
 
 // SessionMonitor monitors tmux sessions for detachment events
@@ -141,6 +145,7 @@ func (sh *SessionReturnHandler) suspendTUIAndAttach() error {
 	time.Sleep(100 * time.Millisecond)
 	
 	// Attach to the tmux session (this will block until user detaches)
+	attachedAt := time.Now()
 	err := sh.tmuxManager.AttachSession(sh.sessionName)
 	
 	// If attachment fails, restart TUI immediately
@@ -154,6 +159,9 @@ func (sh *SessionReturnHandler) suspendTUIAndAttach() error {
 	// Now we need to restart the TUI application to return user to SSHM interface
 	sh.isAttached = false
 	
+	// Time spent in the session feeds the per-server time report
+	sh.tuiApp.recordSessionTime(sh.sessionName, attachedAt, time.Now())
+	
 	// Stop monitoring as we're returning to TUI
 	sh.StopSessionMonitoring()
 	
@@ -505,12 +513,18 @@ func (sh *SessionReturnHandler) SaveTUIState() *TUIState {
 	state := &TUIState{
 		SelectedProfile:      sh.tuiApp.GetSelectedProfile(),
 		SelectedProfileIndex: sh.tuiApp.GetSelectedProfileIndex(),
-		CurrentView:          "main", // Could be enhanced to track actual view
-		ScrollPosition:       0,      // Could be enhanced to track scroll
+		CurrentView:          "main",
 		SelectedRow:          sh.tuiApp.GetSelectedRow(),
 		SelectedSession:      sh.tuiApp.GetSelectedSession(),
 		FocusedPanel:         sh.tuiApp.GetFocusedPanel(),
 		CurrentFilter:        sh.tuiApp.GetCurrentFilter(),
+		SearchFilter:         sh.tuiApp.searchFilter,
+		ViewMode:             sh.tuiApp.viewMode,
+		ServerPanelWidth:     sh.tuiApp.serverPanelWidth,
+	}
+	if sh.tuiApp.serverList != nil {
+		state.ScrollPosition, _ = sh.tuiApp.serverList.GetOffset()
+		state.SelectedServer = sh.tuiApp.getSelectedServerName()
 	}
 
 	sh.tuiState = state
@@ -532,6 +546,7 @@ func (sh *SessionReturnHandler) RestoreTUIState(state *TUIState) {
 	sh.tuiApp.SetSelectedSession(state.SelectedSession)
 	sh.tuiApp.SetFocusedPanel(state.FocusedPanel)
 	sh.tuiApp.SetCurrentFilter(state.CurrentFilter)
+	sh.tuiApp.restoreSessionContext(state, sh.sessionName)
 	
 	// Store state reference
 	sh.tuiState = state
@@ -636,6 +651,7 @@ func (sh *SessionReturnHandler) restartTUIWithStateRestoration() error {
 		newTUIApp.SetSelectedSession(sh.tuiState.SelectedSession)
 		newTUIApp.SetFocusedPanel(sh.tuiState.FocusedPanel)
 		newTUIApp.SetCurrentFilter(sh.tuiState.CurrentFilter)
+		newTUIApp.restoreSessionContext(sh.tuiState, sh.sessionName)
 	}
 	
 	// Show the return message with state information
@@ -666,6 +682,7 @@ func (sh *SessionReturnHandler) restartTUIAfterError(attachError error) error {
 		newTUIApp.SetSelectedSession(sh.tuiState.SelectedSession)
 		newTUIApp.SetFocusedPanel(sh.tuiState.FocusedPanel)
 		newTUIApp.SetCurrentFilter(sh.tuiState.CurrentFilter)
+		newTUIApp.restoreSessionContext(sh.tuiState, "")
 	}
 	
 	// Show the error message
@@ -782,4 +799,49 @@ func (sh *SessionReturnHandler) Cleanup() {
 			sh.eventChannel = nil
 		}
 	}()
-}
\ No newline at end of file
+}
+// restoreSessionContext puts back the search, view, selection and scroll position
+// saved before attaching to a tmux session, and selects and highlights the session
+// the user just left in the refreshed sessions panel
+func (t *TUIApp) restoreSessionContext(state *TUIState, leftSession string) {
+	if state == nil || t.serverList == nil {
+		return
+	}
+
+	t.searchFilter = state.SearchFilter
+	if state.ServerPanelWidth != 0 {
+		t.resizeServerPanel(state.ServerPanelWidth - t.serverPanelWidth)
+	}
+	if state.ViewMode != "" && state.ViewMode != t.viewMode {
+		t.setServerView(state.ViewMode)
+	} else {
+		t.refreshServerList()
+	}
+
+	if state.SelectedServer != "" {
+		t.selectServerRow(state.SelectedServer)
+	} else if state.SelectedRow > 0 && state.SelectedRow < t.serverList.GetRowCount() {
+		t.serverList.Select(state.SelectedRow, 0)
+	}
+	t.serverList.SetOffset(state.ScrollPosition, 0)
+	if t.isTreeMode() {
+		t.refreshServerList()
+	}
+
+	t.lastSession = leftSession
+	t.refreshSessions()
+	for i, session := range t.sessions {
+		if session.Name == leftSession {
+			t.selectedSession = i + 1
+			t.sessionPanel.Select(t.selectedSession, 0)
+			break
+		}
+	}
+
+	if state.FocusedPanel == "sessions" && t.sessionPanel != nil {
+		t.focusedPanel = "sessions"
+	} else {
+		t.focusedPanel = "servers"
+	}
+	t.updatePanelHighlight()
+}
@@ -2,9 +2,11 @@ package tui
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
+	"sshm/internal/config"
 	"sshm/internal/tmux"
 )
 
@@ -630,4 +632,54 @@ func TestSessionReturnHandler_MonitoringLifecycle(t *testing.T) {
 
 	// 6. Test cleanup after monitoring
 	handler.Cleanup()
-}
\ No newline at end of file
+}
+func TestSessionReturnHandler_RestoresSessionContext(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Setenv("SSHM_CONFIG_DIR", tempDir)
+
+	cfg := &config.Config{
+		Servers: []config.Server{
+			{Name: "web1", Hostname: "web1.example.com", Port: 22, Username: "deploy", AuthType: "key", KeyPath: "/home/user/.ssh/id_rsa"},
+			{Name: "web2", Hostname: "web2.example.com", Port: 22, Username: "deploy", AuthType: "key", KeyPath: "/home/user/.ssh/id_rsa"},
+			{Name: "db1", Hostname: "db1.example.com", Port: 22, Username: "postgres", AuthType: "key", KeyPath: "/home/user/.ssh/id_rsa"},
+		},
+	}
+	if err := cfg.SaveToPath(filepath.Join(tempDir, "config.yaml")); err != nil {
+		t.Fatalf("Failed to save config: %v", err)
+	}
+
+	tuiApp, err := NewTUIApp()
+	if err != nil {
+		t.Fatalf("Failed to create TUI app: %v", err)
+	}
+	tuiApp.searchFilter = "web"
+	tuiApp.refreshServerList()
+	tuiApp.selectServerRow("web2")
+
+	handler := NewSessionReturnHandler(tuiApp, tmux.NewManager())
+	handler.sessionName = "web2"
+	state := handler.SaveTUIState()
+	if state.SelectedServer != "web2" || state.SearchFilter != "web" {
+		t.Fatalf("Expected selection and search to be saved, got %+v", state)
+	}
+
+	// A fresh TUI, as created when returning from tmux, starts with a reset view
+	restored, err := NewTUIApp()
+	if err != nil {
+		t.Fatalf("Failed to create TUI app: %v", err)
+	}
+	restored.restoreSessionContext(state, handler.sessionName)
+
+	if restored.searchFilter != "web" {
+		t.Errorf("Expected search filter to be restored, got %q", restored.searchFilter)
+	}
+	if rows := restored.serverList.GetRowCount() - 1; rows != 2 {
+		t.Errorf("Expected the search to filter the list to 2 servers, got %d", rows)
+	}
+	if name := restored.getSelectedServerName(); name != "web2" {
+		t.Errorf("Expected web2 to be selected, got %q", name)
+	}
+	if restored.lastSession != "web2" {
+		t.Errorf("Expected the detached session to be highlighted, got %q", restored.lastSession)
+	}
+}
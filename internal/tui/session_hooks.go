@@ -0,0 +1,139 @@
+package tui
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	"sshm/internal/shellquote"
+	"sshm/internal/tmux"
+)
+
+const (
+	// polledRefreshInterval is used while tmux hooks are not installed, e.g. before
+	// the tmux server is started
+	polledRefreshInterval = 5 * time.Second
+	// hookedRefreshInterval is the safety-net poll while tmux hooks report changes
+	hookedRefreshInterval = 30 * time.Second
+	// hookIndexBase offsets sshm's entries in tmux hook arrays away from the low
+	// indexes users tend to set
+	hookIndexBase = 4200
+)
+
+// SetNotifyExecutable sets the sshm binary the tmux session hooks run. Hooks
+// are only installed once the CLI sets it, so tests never point them at a test
+// binary.
+func (t *TUIApp) SetNotifyExecutable(path string) {
+	t.notifyExecutable = path
+}
+
+// sessionNotifier receives change notifications from tmux hooks over a Unix
+// socket so the sessions panel updates as soon as a session is created, closed
+// or attached to
+type sessionNotifier struct {
+	listener  net.Listener
+	socket    string
+	command   string // Shell command run by the hooks
+	hookIndex int
+	changes   chan struct{}
+	installed atomic.Bool // Hooks are present in the running tmux server
+}
+
+// NotifySessionChange tells the TUI listening on socket that tmux sessions
+// changed. It is called by the hooks through 'sshm sessions notify'.
+func NotifySessionChange(socket string) error {
+	conn, err := net.DialTimeout("unix", socket, time.Second)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// startSessionNotifier listens for tmux hook notifications and installs the hooks.
+// Any failure, including tmux not being installed, leaves the sessions panel on
+// plain polling.
+func (t *TUIApp) startSessionNotifier() {
+	if t.sessionNotifier != nil || t.notifyExecutable == "" {
+		return
+	}
+
+	socket := filepath.Join(os.TempDir(), fmt.Sprintf("sshm-%d.sock", os.Getpid()))
+	os.Remove(socket)
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		return
+	}
+	os.Chmod(socket, 0600)
+
+	n := &sessionNotifier{
+		listener:  listener,
+		socket:    socket,
+		command:   shellquote.Quote(t.notifyExecutable) + " sessions notify " + shellquote.Quote(socket),
+		hookIndex: hookIndexBase + os.Getpid()%1000,
+		changes:   make(chan struct{}, 1),
+	}
+	t.sessionNotifier = n
+
+	go func() {
+		defer close(n.changes)
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+			// Bursts of events collapse into one pending refresh
+			select {
+			case n.changes <- struct{}{}:
+			default:
+			}
+		}
+	}()
+	go func() {
+		for range n.changes {
+			if t.running {
+				t.refreshSessions()
+				t.app.QueueUpdateDraw(func() {})
+			}
+		}
+	}()
+
+	// Installing talks to tmux, which must not delay startup
+	go n.ensureHooks(t.tmuxManager)
+}
+
+// ensureHooks installs the hooks unless the tmux server still has them. They are
+// lost whenever the tmux server exits, so this runs on every poll.
+func (n *sessionNotifier) ensureHooks(m *tmux.Manager) {
+	if m.HooksInstalled(n.hookIndex) {
+		n.installed.Store(true)
+		return
+	}
+	n.installed.Store(m.InstallHooks(n.hookIndex, n.command) == nil)
+}
+
+// stopSessionNotifier removes the hooks and closes the socket
+func (t *TUIApp) stopSessionNotifier() {
+	n := t.sessionNotifier
+	if n == nil {
+		return
+	}
+	t.sessionNotifier = nil
+	if n.installed.Load() {
+		t.tmuxManager.RemoveHooks(n.hookIndex)
+	}
+	n.listener.Close()
+	os.Remove(n.socket)
+}
+
+// sessionRefreshInterval is how often the sessions panel polls tmux; rarely while
+// hooks report changes as they happen
+func (t *TUIApp) sessionRefreshInterval() time.Duration {
+	if n := t.sessionNotifier; n != nil && n.installed.Load() {
+		return hookedRefreshInterval
+	}
+	return polledRefreshInterval
+}
@@ -0,0 +1,62 @@
+package tui
+
+import (
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"sshm/internal/shellquote"
+)
+
+func TestNotifySessionChange(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "notify.sock")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Skipf("Unix sockets not available: %v", err)
+	}
+	defer listener.Close()
+
+	accepted := make(chan struct{})
+	go func() {
+		if conn, err := listener.Accept(); err == nil {
+			conn.Close()
+			close(accepted)
+		}
+	}()
+
+	if err := NotifySessionChange(socket); err != nil {
+		t.Fatalf("NotifySessionChange() error = %v", err)
+	}
+	select {
+	case <-accepted:
+	case <-time.After(time.Second):
+		t.Fatal("Expected the listener to receive a connection")
+	}
+
+	if err := NotifySessionChange(filepath.Join(t.TempDir(), "missing.sock")); err == nil {
+		t.Error("Expected an error when nothing listens on the socket")
+	}
+}
+
+func TestSessionRefreshInterval(t *testing.T) {
+	app := &TUIApp{}
+	if got := app.sessionRefreshInterval(); got != polledRefreshInterval {
+		t.Errorf("Without hooks interval = %v, want %v", got, polledRefreshInterval)
+	}
+
+	app.sessionNotifier = &sessionNotifier{}
+	if got := app.sessionRefreshInterval(); got != polledRefreshInterval {
+		t.Errorf("With hooks not installed interval = %v, want %v", got, polledRefreshInterval)
+	}
+	app.sessionNotifier.installed.Store(true)
+	if got := app.sessionRefreshInterval(); got != hookedRefreshInterval {
+		t.Errorf("With hooks installed interval = %v, want %v", got, hookedRefreshInterval)
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	if got := shellquote.Quote("/it's/sshm"); got != `'/it'\''s/sshm'` {
+		t.Errorf("shellquote.Quote() = %s", got)
+	}
+}
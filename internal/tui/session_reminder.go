@@ -0,0 +1,176 @@
+package tui
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"sshm/internal/tmux"
+)
+
+// sessionReminderCheck is how often sessions are looked at for reminders
+const sessionReminderCheck = time.Minute
+
+// forgottenSession is a session created by sshm that nobody is attached to and
+// that has been idle for a while
+type forgottenSession struct {
+	Name string
+	Idle time.Duration
+}
+
+// forgottenSessions returns the detached sessions of configured servers and
+// profiles idle for at least idle, longest idle first
+func forgottenSessions(states []tmux.SessionState, ownerNames []string, now time.Time, idle time.Duration) []forgottenSession {
+	var forgotten []forgottenSession
+	for _, state := range states {
+		if state.Clients > 0 || state.Activity.IsZero() || now.Sub(state.Activity) < idle {
+			continue
+		}
+		// Sessions sshm didn't create are none of its business
+		if tmux.ServerForSession(state.Name, ownerNames) == "" {
+			continue
+		}
+		forgotten = append(forgotten, forgottenSession{Name: state.Name, Idle: now.Sub(state.Activity)})
+	}
+	sort.Slice(forgotten, func(a, b int) bool {
+		if forgotten[a].Idle != forgotten[b].Idle {
+			return forgotten[a].Idle > forgotten[b].Idle
+		}
+		return forgotten[a].Name < forgotten[b].Name
+	})
+	return forgotten
+}
+
+// sessionOwnerNames lists the names sshm gives sessions: servers and profiles
+func (t *TUIApp) sessionOwnerNames() []string {
+	names := t.config.ProfileNames()
+	for _, server := range t.config.GetServers() {
+		names = append(names, server.Name)
+	}
+	return names
+}
+
+// startSessionReminders periodically reminds of forgotten sessions when
+// ui.session_reminder_minutes is set
+func (t *TUIApp) startSessionReminders() {
+	interval := t.config.UI.SessionReminderInterval()
+	if interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(sessionReminderCheck)
+		defer ticker.Stop()
+
+		var lastReminder time.Time
+		for {
+			select {
+			case <-t.stopChan:
+				return
+			case now := <-ticker.C:
+				if !t.running || now.Sub(lastReminder) < interval || !t.tmuxManager.IsAvailable() {
+					continue
+				}
+				states, err := t.tmuxManager.SessionStates()
+				if err != nil {
+					continue
+				}
+				forgotten := forgottenSessions(states, t.sessionOwnerNames(), now, interval)
+				if len(forgotten) == 0 {
+					continue
+				}
+				lastReminder = now
+				t.remindForgottenSessions(forgotten)
+			}
+		}
+	}()
+}
+
+// remindForgottenSessions shows a reminder toast and, when configured, a
+// desktop notification listing the forgotten sessions
+func (t *TUIApp) remindForgottenSessions(forgotten []forgottenSession) {
+	names := make([]string, 0, len(forgotten))
+	for _, session := range forgotten {
+		names = append(names, session.Name)
+	}
+	summary := fmt.Sprintf("%d forgotten session(s): %s", len(forgotten), strings.Join(names, ", "))
+
+	if t.config.UI.SessionReminderDesktop {
+		// Best effort: not every desktop has a notification tool
+		desktopNotify("sshm", summary)
+	}
+	t.app.QueueUpdateDraw(func() {
+		t.showTransientStatus(fmt.Sprintf("[yellow]⏰ %s — Ctrl+F to attach or kill[white]", tview.Escape(summary)))
+	})
+}
+
+// showForgottenSessions lists the detached idle sessions, Enter attaching to
+// one and k killing it
+func (t *TUIApp) showForgottenSessions() {
+	idle := t.config.UI.SessionReminderInterval()
+	if idle <= 0 {
+		t.showTransientStatus("[yellow]Session reminders are off; set ui.session_reminder_minutes[white]")
+		return
+	}
+	if !t.tmuxManager.IsAvailable() {
+		t.showErrorModal("tmux is not available")
+		return
+	}
+	states, err := t.tmuxManager.SessionStates()
+	if err != nil {
+		t.showErrorModal(fmt.Sprintf("Failed to read sessions: %s", err.Error()))
+		return
+	}
+	forgotten := forgottenSessions(states, t.sessionOwnerNames(), time.Now(), idle)
+	if len(forgotten) == 0 {
+		t.showTransientStatus("[green]No forgotten sessions[white]")
+		return
+	}
+
+	list := tview.NewList().ShowSecondaryText(true)
+	for _, session := range forgotten {
+		name := session.Name
+		list.AddItem(tview.Escape(name), fmt.Sprintf("Detached, idle for %s", formatAge(session.Idle)), 0, func() {
+			t.modalManager.HideModal()
+			t.attachToSession(name)
+		})
+	}
+	list.SetBorder(true).
+		SetTitle(" Forgotten Sessions — Enter attach, k kill, Esc close ").
+		SetTitleAlign(tview.AlignCenter)
+	list.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case event.Key() == tcell.KeyEscape || event.Rune() == 'q':
+			t.modalManager.HideModal()
+			return nil
+		case event.Rune() == 'k':
+			name := forgotten[list.GetCurrentItem()].Name
+			t.modalManager.HideModal()
+			t.killSession(name)
+			return nil
+		}
+		return event
+	})
+	t.modalManager.ShowModal(list)
+}
+
+// desktopNotify shows a desktop notification with notify-send or osascript
+// (variable to allow mocking in tests)
+var desktopNotify = func(title, message string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("osascript", "-e", fmt.Sprintf("display notification %q with title %q", message, title))
+	default:
+		if _, err := exec.LookPath("notify-send"); err != nil {
+			return fmt.Errorf("no desktop notification tool found (install notify-send)")
+		}
+		cmd = exec.Command("notify-send", title, message)
+	}
+	return cmd.Run()
+}
@@ -0,0 +1,35 @@
+package tui
+
+import (
+	"testing"
+	"time"
+
+	"sshm/internal/tmux"
+)
+
+func TestForgottenSessions(t *testing.T) {
+	now := time.Now()
+	states := []tmux.SessionState{
+		{Name: "web", Activity: now.Add(-2 * time.Hour)},
+		{Name: "web-1", Activity: now.Add(-3 * time.Hour)},
+		{Name: "prod", Activity: now.Add(-10 * time.Minute)},        // Not idle long enough
+		{Name: "db", Clients: 1, Activity: now.Add(-5 * time.Hour)}, // Someone is attached
+		{Name: "scratchpad", Activity: now.Add(-5 * time.Hour)},     // Not created by sshm
+		{Name: "staging_example_com", Activity: now.Add(-90 * time.Minute)},
+	}
+	owners := []string{"web", "db", "prod", "staging.example.com"}
+
+	forgotten := forgottenSessions(states, owners, now, time.Hour)
+	want := []string{"web-1", "web", "staging_example_com"}
+	if len(forgotten) != len(want) {
+		t.Fatalf("Expected %v, got %+v", want, forgotten)
+	}
+	for i, name := range want {
+		if forgotten[i].Name != name {
+			t.Errorf("Expected %s at %d, got %s", name, i, forgotten[i].Name)
+		}
+	}
+	if forgotten[0].Idle != 3*time.Hour {
+		t.Errorf("Expected the idle time to be reported, got %v", forgotten[0].Idle)
+	}
+}
@@ -0,0 +1,82 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rivo/tview"
+	"sshm/internal/connection"
+	"sshm/internal/tmux"
+)
+
+// repairSelectedSession relaunches the SSH connections of the windows in the
+// selected session whose connection died or never succeeded
+func (t *TUIApp) repairSelectedSession() {
+	if t.sessionPanel == nil || t.focusedPanel != "sessions" {
+		return
+	}
+	currentRow, _ := t.sessionPanel.GetSelection()
+	if currentRow <= 0 || currentRow > len(t.sessions) {
+		return
+	}
+	t.repairSession(t.sessions[currentRow-1].Name)
+}
+
+// repairSession reconnects the disconnected windows of a session and reports
+// whether the repair ran
+func (t *TUIApp) repairSession(sessionName string) bool {
+	// Group sessions are named after their profile and reuse its bootstrap
+	profileName := tmux.ServerForSession(sessionName, t.config.ProfileNames())
+	configured := t.config.ServersWithBootstrap(profileName)
+	acks := t.connectionManager.AcknowledgedBanners(configured)
+	result, err := t.tmuxManager.RepairSession(sessionName, connection.TmuxServers(configured, acks))
+	if err != nil {
+		t.showSessionErrorModal(fmt.Sprintf("Failed to repair session '%s': %s", sessionName, err.Error()))
+		return false
+	}
+	t.refreshSessions()
+	t.showTransientStatus(describeRepair(sessionName, result))
+	return true
+}
+
+// showConnectionLostModal offers to reconnect a session whose SSH connection
+// dropped before attaching to it
+func (t *TUIApp) showConnectionLostModal(session SessionInfo) {
+	message := fmt.Sprintf("The SSH connection was lost in session '%s':\n\n%s\n\nReconnect before attaching?",
+		session.Name, strings.Join(session.LostWindows, ", "))
+
+	modal := tview.NewModal().
+		SetText(message).
+		AddButtons([]string{"Reconnect", "Attach anyway", "Cancel"}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			t.modalManager.HideModal()
+			switch buttonLabel {
+			case "Reconnect":
+				if t.repairSession(session.Name) {
+					t.attachToSession(session.Name)
+				}
+			case "Attach anyway":
+				t.attachToSession(session.Name)
+			}
+		})
+	modal.SetTitle(" Connection Lost ")
+	t.modalManager.ShowModal(modal)
+}
+
+// describeRepair renders the outcome of a session repair for the status bar
+func describeRepair(sessionName string, result tmux.RepairResult) string {
+	if len(result.Reconnected) == 0 && len(result.Unknown) == 0 && len(result.Refused) == 0 {
+		return fmt.Sprintf("[green]All %d window(s) of '%s' are connected[white]", result.Healthy, sessionName)
+	}
+	text := fmt.Sprintf("[green]Reconnected %d window(s) in '%s'", len(result.Reconnected), sessionName)
+	if len(result.Reconnected) > 0 {
+		text += ": " + strings.Join(result.Reconnected, ", ")
+	}
+	if len(result.Unknown) > 0 {
+		text += fmt.Sprintf("[yellow]; %d disconnected window(s) match no server: %s", len(result.Unknown), strings.Join(result.Unknown, ", "))
+	}
+	if len(result.Refused) > 0 {
+		text += fmt.Sprintf("[yellow]; login banner to accept first (connect on its own): %s", strings.Join(result.Refused, ", "))
+	}
+	return text + "[white]"
+}
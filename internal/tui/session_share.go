@@ -0,0 +1,88 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/rivo/tview"
+	"sshm/internal/tmux"
+)
+
+// sessionClients returns the attached clients of a session, -1 when its state
+// couldn't be read
+func sessionClients(session SessionInfo) int {
+	if session.Status == "unknown" {
+		return -1
+	}
+	return session.Clients
+}
+
+// inviteToSelectedSession asks who may join the selected session for pair debugging
+func (t *TUIApp) inviteToSelectedSession() {
+	if t.sessionPanel == nil || t.focusedPanel != "sessions" {
+		return
+	}
+	currentRow, _ := t.sessionPanel.GetSelection()
+	if currentRow <= 0 || currentRow > len(t.sessions) {
+		return
+	}
+	t.showInviteForm(t.sessions[currentRow-1].Name)
+}
+
+// showInviteForm collects the teammate's account, group and access mode
+func (t *TUIApp) showInviteForm(sessionName string) {
+	hostname, _ := os.Hostname()
+	form := tview.NewForm().
+		AddInputField("Teammate account (local)", "", 30, nil, nil).
+		AddInputField("Group", "", 30, nil, nil).
+		AddInputField("SSH host", hostname, 40, nil, nil).
+		AddCheckbox("Read-only", false, nil)
+	form.SetBorder(true).
+		SetTitle(fmt.Sprintf(" Invite to %s ", sessionName)).
+		SetTitleAlign(tview.AlignCenter)
+
+	form.AddButton("Invite", func() {
+		options := tmux.ShareOptions{
+			User:     strings.TrimSpace(form.GetFormItem(0).(*tview.InputField).GetText()),
+			Group:    strings.TrimSpace(form.GetFormItem(1).(*tview.InputField).GetText()),
+			Host:     strings.TrimSpace(form.GetFormItem(2).(*tview.InputField).GetText()),
+			ReadOnly: form.GetFormItem(3).(*tview.Checkbox).IsChecked(),
+		}
+		invitation, err := t.tmuxManager.ShareSession(sessionName, options)
+		if err != nil {
+			t.showErrorModal(fmt.Sprintf("Failed to share session '%s': %s", sessionName, err.Error()))
+			return
+		}
+		t.modalManager.HideModal()
+		t.showInvitation(invitation)
+	})
+	form.AddButton("Cancel", func() {
+		t.modalManager.HideModal()
+	})
+	t.modalManager.ShowModal(form)
+}
+
+// showInvitation shows the commands a teammate runs to join, copying the one
+// that works from anywhere to the clipboard
+func (t *TUIApp) showInvitation(invitation tmux.Invitation) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "[yellow]Join session %s[white]\n\n", tview.Escape(invitation.Session))
+	b.WriteString("[yellow]From an account on this host[white]\n")
+	fmt.Fprintf(&b, "  [green]%s[white]\n\n", tview.Escape(invitation.Local))
+	b.WriteString("[yellow]From another machine[white] (their public key must be in your ~/.ssh/authorized_keys)\n")
+	fmt.Fprintf(&b, "  [green]%s[white]\n", tview.Escape(invitation.SSH))
+
+	if len(invitation.Warnings) > 0 {
+		b.WriteString("\n")
+		for _, warning := range invitation.Warnings {
+			fmt.Fprintf(&b, "[orange]⚠ %s[white]\n", tview.Escape(warning))
+		}
+	}
+
+	if err := copyToClipboard(invitation.SSH); err == nil {
+		b.WriteString("\n[gray]The ssh command was copied to the clipboard.[white]\n")
+	}
+	b.WriteString("\n[gray]The Clients column shows who joined. Run 'sshm sessions unshare' to make the socket private again. Press Enter, Escape or q to close[white]")
+	t.showTextPanel("Session Invitation", b.String())
+}
@@ -0,0 +1,12 @@
+package tui
+
+import "testing"
+
+func TestSessionClients(t *testing.T) {
+	if clients := sessionClients(SessionInfo{Status: "multi-attached", Clients: 3}); clients != 3 {
+		t.Errorf("Expected 3 clients, got %d", clients)
+	}
+	if clients := sessionClients(SessionInfo{Status: "unknown"}); clients != -1 {
+		t.Errorf("Expected unknown clients for an unreadable session, got %d", clients)
+	}
+}
@@ -0,0 +1,70 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rivo/tview"
+	"sshm/internal/history"
+	"sshm/internal/stats"
+	"sshm/internal/tmux"
+)
+
+// recordSessionTime keeps the time spent attached to a session in the history
+// database, attributed to the session's server or profile, for the time report
+func (t *TUIApp) recordSessionTime(sessionName string, attachedAt, detachedAt time.Time) {
+	if t.config == nil || t.connectionManager == nil || t.connectionManager.GetHistoryManager() == nil {
+		return
+	}
+
+	entry := history.SessionTime{SessionName: sessionName, AttachedAt: attachedAt, DetachedAt: detachedAt}
+	var names []string
+	for _, server := range t.config.GetServers() {
+		names = append(names, server.Name)
+	}
+	if serverName := tmux.ServerForSession(sessionName, names); serverName != "" {
+		entry.ServerName = serverName
+	profiles:
+		for _, profile := range t.config.GetProfiles() {
+			for _, name := range profile.Servers {
+				if name == serverName {
+					entry.ProfileName = profile.Name
+					break profiles
+				}
+			}
+		}
+	} else {
+		entry.ProfileName = tmux.ServerForSession(sessionName, t.config.ProfileNames())
+	}
+
+	// Like status samples, session time is a convenience; failing to store it is not an error
+	t.connectionManager.GetHistoryManager().RecordSessionTime(entry)
+}
+
+// renderTimeReport renders the hours spent per week with tview color tags
+func renderTimeReport(report []stats.WeekTime) string {
+	if len(report) == 0 {
+		return "  [gray]No time attached to sessions recorded yet[white]\n"
+	}
+
+	var b strings.Builder
+	var week time.Time
+	var total time.Duration
+	flush := func() {
+		if !week.IsZero() {
+			fmt.Fprintf(&b, "    [gray]%-22s %s[white]\n", "total", stats.FormatHours(total))
+		}
+	}
+	for _, entry := range report {
+		if !entry.Week.Equal(week) {
+			flush()
+			week, total = entry.Week, 0
+			fmt.Fprintf(&b, "  [aqua]Week of %s[white]\n", week.Format("Mon 2006-01-02"))
+		}
+		total += entry.Duration
+		fmt.Fprintf(&b, "    %-22s %s\n", tview.Escape(entry.Name), stats.FormatHours(entry.Duration))
+	}
+	flush()
+	return b.String()
+}
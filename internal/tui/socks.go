@@ -0,0 +1,124 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"sshm/internal/tunnel"
+)
+
+// toggleSelectedServerSOCKS starts a SOCKS proxy through the selected server, or
+// stops it when it is already running
+func (t *TUIApp) toggleSelectedServerSOCKS() {
+	if t.focusedPanel != "servers" {
+		return
+	}
+
+	serverName := t.getSelectedServerName()
+	if serverName == "" {
+		return
+	}
+
+	server, err := t.config.GetServer(serverName)
+	if err != nil {
+		t.showErrorModal(fmt.Sprintf("Server '%s' not found: %s", serverName, err.Error()))
+		return
+	}
+
+	spec := server.SOCKSTunnel()
+	key := tunnel.Key(server.Name, spec.Name)
+	if t.tunnelManager.IsRunning(key) {
+		if err := t.tunnelManager.Stop(key); err != nil {
+			t.showErrorModal(err.Error())
+			return
+		}
+		t.refreshSOCKSIndicator()
+		t.showTransientStatus(fmt.Sprintf("[gray]🧦 SOCKS proxy through %s stopped[white]", server.Name))
+		return
+	}
+
+	t.showTransientStatus(fmt.Sprintf("[yellow]🧦 Starting SOCKS proxy through %s...[white]", server.Name))
+	serverCopy := *server
+	go func() {
+		started, err := t.tunnelManager.StartSOCKS(serverCopy)
+		t.app.QueueUpdateDraw(func() {
+			t.refreshSOCKSIndicator()
+			if err != nil {
+				t.showErrorModal(fmt.Sprintf("Failed to start SOCKS proxy through %s: %s", serverCopy.Name, err.Error()))
+				return
+			}
+			t.showSOCKSInstructions(serverCopy.Name, started.LocalPort)
+		})
+	}()
+}
+
+// showSOCKSInstructions writes the PAC file for a running proxy and explains how
+// to point a browser at it
+func (t *TUIApp) showSOCKSInstructions(serverName string, port int) {
+	pac := "[red]PAC file not written[white]"
+	if path, err := tunnel.DefaultPACPath(); err == nil {
+		if err := tunnel.WritePAC(path, port); err == nil {
+			pac = "file://" + path
+		} else {
+			pac = fmt.Sprintf("[red]%s[white]", err.Error())
+		}
+	}
+
+	text := fmt.Sprintf(`[yellow]SOCKS proxy through %s[white]
+
+Listening on [green]127.0.0.1:%d[white] (SOCKS5)
+
+[yellow]Automatic configuration[white]
+  Proxy auto-config URL: %s
+
+[yellow]Firefox[white]
+  Settings → Network Settings → Manual proxy configuration
+  SOCKS Host 127.0.0.1, Port %d, SOCKS v5, check "Proxy DNS when using SOCKS v5"
+
+[yellow]Chrome / Chromium[white]
+  chromium --proxy-server="socks5://127.0.0.1:%d"
+
+[yellow]Command line[white]
+  curl --socks5-hostname 127.0.0.1:%d https://example.com
+  export ALL_PROXY=socks5h://127.0.0.1:%d
+
+[gray]Press Ctrl+S on the server again, or x in the Tunnels panel (t), to stop it. Press Enter, Escape or q to close[white]`,
+		serverName, port, pac, port, port, port, port)
+	t.showTextPanel("SOCKS Proxy", text)
+}
+
+// socksStatusText returns the status bar indicator for running SOCKS proxies
+func (t *TUIApp) socksStatusText() string {
+	if t.tunnelManager == nil {
+		return ""
+	}
+
+	var parts []string
+	for _, proxy := range t.tunnelManager.SOCKSProxies() {
+		background := "green"
+		if proxy.State == tunnel.StateDegraded {
+			background = "yellow"
+		}
+		parts = append(parts, fmt.Sprintf("[black:%s] SOCKS :%d via %s [-:-]", background, proxy.LocalPort, proxy.ServerName))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return " | " + strings.Join(parts, " ")
+}
+
+// refreshSOCKSIndicator redraws the status bar when the SOCKS indicator changed,
+// e.g. after a proxy was started, stopped or found degraded by a health check
+func (t *TUIApp) refreshSOCKSIndicator() {
+	indicator := t.socksStatusText()
+	if indicator == t.socksIndicator || t.statusBar == nil || t.serverList == nil {
+		return
+	}
+	t.socksIndicator = indicator
+
+	serverCount := t.serverList.GetRowCount() - 1
+	if serverCount < 0 {
+		serverCount = 0
+	}
+	t.updateStatusBar(serverCount)
+}
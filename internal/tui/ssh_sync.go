@@ -0,0 +1,133 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"sshm/internal/config"
+	"sshm/internal/sshconfig"
+)
+
+// syncSSHConfig syncs the servers with the SSH config file, resolving conflicts
+// as prefer says or as configured when it is empty. Quiet syncs, as on startup,
+// only speak up when something changed.
+func (t *TUIApp) syncSSHConfig(prefer string, quiet bool) {
+	syncer, err := sshconfig.NewSyncer(t.config.SSHSync)
+	if err != nil {
+		t.showErrorModal(fmt.Sprintf("SSH config sync: %s", err.Error()))
+		return
+	}
+	if prefer != "" {
+		syncer.Prefer = prefer
+	}
+
+	result, err := syncer.Sync(t.config)
+	if err != nil {
+		t.showErrorModal(fmt.Sprintf("SSH config sync failed: %s", err.Error()))
+		return
+	}
+	if result.ConfigChanged {
+		if err := t.saveConfig(); err != nil {
+			t.showErrorModal(fmt.Sprintf("Failed to save configuration: %s", err.Error()))
+			return
+		}
+		t.refreshServerList()
+	}
+
+	switch {
+	case len(result.Conflicts) > 0:
+		t.showSSHSyncConflicts(result)
+	case quiet:
+		if len(result.Changes) > 0 {
+			t.showTransientStatus(fmt.Sprintf("[green]SSH config synced: %s[white]", summarizeSSHSync(result)))
+		}
+	default:
+		t.showTextPanel("SSH Config Sync", renderSSHSyncResult(syncer.Path, result))
+	}
+}
+
+// showSSHSyncConflicts asks which side wins for hosts edited on both
+func (t *TUIApp) showSSHSyncConflicts(result sshconfig.Result) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d host(s) were edited both in sshm and in the SSH config:\n\n", len(result.Conflicts))
+	for _, conflict := range result.Conflicts {
+		fmt.Fprintf(&b, "%s\n  sshm: %s\n  ssh:  %s\n", conflict.Host, describeSyncedHost(conflict.SSHM), describeSyncedHost(conflict.SSHConfig))
+	}
+	b.WriteString("\nWhich side should win?")
+
+	modal := tview.NewModal().
+		SetText(b.String()).
+		AddButtons([]string{"Keep sshm", "Take SSH config", "Later"}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			t.modalManager.HideModal()
+			switch buttonLabel {
+			case "Keep sshm":
+				t.syncSSHConfig(config.SSHSyncPreferSSHM, false)
+			case "Take SSH config":
+				t.syncSSHConfig(config.SSHSyncPreferSSH, false)
+			default:
+				t.showTransientStatus("[yellow]SSH config conflicts left for the next sync[white]")
+			}
+		}).
+		SetBackgroundColor(tcell.ColorDarkRed)
+	modal.SetTitle(" SSH Config Conflicts ")
+	t.modalManager.ShowModal(modal)
+}
+
+// renderSSHSyncResult lists what a sync did, host by host
+func renderSSHSyncResult(path string, result sshconfig.Result) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "[yellow]Synced with %s[white]\n\n", tview.Escape(path))
+	if len(result.Changes) == 0 {
+		b.WriteString("Already in sync.\n")
+	}
+	for _, change := range result.Changes {
+		fmt.Fprintf(&b, "%s %s[white]: %s\n", sshSyncActionLabel(change.Action), tview.Escape(change.Host), tview.Escape(change.Detail))
+	}
+	b.WriteString("\n[gray]Press Enter, Escape or q to close[white]")
+	return b.String()
+}
+
+// sshSyncActionLabel renders an action in its color
+func sshSyncActionLabel(action string) string {
+	switch action {
+	case sshconfig.ActionImport:
+		return "[green]+ imported"
+	case sshconfig.ActionPull:
+		return "[aqua]← updated"
+	case sshconfig.ActionPush:
+		return "[aqua]→ written"
+	case sshconfig.ActionRemove:
+		return "[orange]- removed"
+	case sshconfig.ActionConflict:
+		return "[red]! conflict"
+	default:
+		return "[gray]· skipped"
+	}
+}
+
+// summarizeSSHSync counts the changes by action for the status bar
+func summarizeSSHSync(result sshconfig.Result) string {
+	counts := make(map[string]int)
+	for _, change := range result.Changes {
+		counts[change.Action]++
+	}
+	var parts []string
+	for _, action := range []string{sshconfig.ActionImport, sshconfig.ActionPull, sshconfig.ActionPush, sshconfig.ActionRemove, sshconfig.ActionSkip} {
+		if counts[action] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[action], action))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// describeSyncedHost renders a host's synced settings on one line
+func describeSyncedHost(host sshconfig.Host) string {
+	description := fmt.Sprintf("%s@%s:%d", host.User, host.Hostname, host.Port)
+	if host.IdentityFile != "" {
+		description += " key " + host.IdentityFile
+	}
+	return description
+}
@@ -0,0 +1,79 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rivo/tview"
+	"sshm/internal/stats"
+)
+
+// showInventoryStats shows the inventory summary: servers by auth type, profile
+// and tag, availability over the last 24 hours, connection usage and the hours
+// spent attached to each server's sessions in recent weeks
+func (t *TUIApp) showInventoryStats() {
+	if t.connectionManager == nil || t.connectionManager.GetHistoryManager() == nil {
+		t.showErrorModal("Inventory statistics need the history database")
+		return
+	}
+
+	inventory, err := stats.FromHistory(t.config, t.connectionManager.GetHistoryManager(), stats.DefaultTop)
+	if err != nil {
+		t.showErrorModal(fmt.Sprintf("Failed to read history: %v", err))
+		return
+	}
+	report, err := stats.TimeFromHistory(t.connectionManager.GetHistoryManager(), stats.TimeByServer, stats.DefaultTimeWeeks, time.Now())
+	if err != nil {
+		t.showErrorModal(fmt.Sprintf("Failed to read history: %v", err))
+		return
+	}
+
+	text := renderInventoryStats(inventory)
+	text += fmt.Sprintf("\n[yellow]Time attached per server (last %d weeks)[white]\n", stats.DefaultTimeWeeks)
+	text += renderTimeReport(report)
+	t.showTextPanel("Inventory Statistics", text)
+}
+
+// renderInventoryStats renders the inventory summary with tview color tags
+func renderInventoryStats(inventory stats.Inventory) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "[yellow]Total servers:[white] %d\n", inventory.Total)
+
+	for _, group := range []struct {
+		title  string
+		counts []stats.Count
+	}{
+		{"By auth type", inventory.ByAuthType},
+		{"By profile", inventory.ByProfile},
+		{"By tag", inventory.ByTag},
+	} {
+		fmt.Fprintf(&b, "\n[yellow]%s[white]\n", group.title)
+		for _, count := range group.counts {
+			fmt.Fprintf(&b, "  %-24s %d\n", tview.Escape(count.Name), count.Count)
+		}
+	}
+
+	b.WriteString("\n[yellow]Availability (last 24h)[white]\n")
+	if inventory.Checks == 0 {
+		b.WriteString("  [gray]No status checks recorded yet[white]\n")
+	} else {
+		fmt.Fprintf(&b, "  [green]%.1f%%[white] online across %d check(s) of %d server(s)\n",
+			inventory.OnlinePercent(), inventory.Checks, inventory.CheckedServers)
+	}
+
+	b.WriteString("\n[yellow]Most connected[white]\n")
+	if len(inventory.MostConnected) == 0 {
+		b.WriteString("  [gray]No connections recorded[white]\n")
+	}
+	for _, server := range inventory.MostConnected {
+		fmt.Fprintf(&b, "  %-24s %d\n", tview.Escape(server.Name), server.Connections)
+	}
+
+	if len(inventory.NeverConnected) > 0 {
+		fmt.Fprintf(&b, "\n[yellow]Never connected to (%d)[white]\n  %s\n",
+			len(inventory.NeverConnected), tview.Escape(strings.Join(inventory.NeverConnected, ", ")))
+	}
+
+	return b.String()
+}
@@ -0,0 +1,48 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"sshm/internal/stats"
+)
+
+func TestRenderInventoryStats(t *testing.T) {
+	text := renderInventoryStats(stats.Inventory{
+		Total:          2,
+		ByAuthType:     []stats.Count{{Name: "key", Count: 2}},
+		ByTag:          []stats.Count{{Name: "[prod]", Count: 1}},
+		Checks:         4,
+		Online:         3,
+		CheckedServers: 2,
+		NeverConnected: []string{"lab"},
+	})
+
+	for _, want := range []string{"Total servers:[white] 2", "75.0%", "No connections recorded", "Never connected to (1)", "lab", "[prod[]"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected %q in:\n%s", want, text)
+		}
+	}
+	if strings.Contains(renderInventoryStats(stats.Inventory{}), "%!") {
+		t.Error("Unexpected formatting error in empty stats")
+	}
+}
+
+func TestRenderTimeReport(t *testing.T) {
+	week := time.Date(2026, 10, 12, 0, 0, 0, 0, time.Local)
+	text := renderTimeReport([]stats.WeekTime{
+		{Week: week, Name: "web", Duration: 3 * time.Hour},
+		{Week: week, Name: "db", Duration: 30 * time.Minute},
+		{Week: week.AddDate(0, 0, 7), Name: "web", Duration: time.Hour},
+	})
+
+	for _, want := range []string{"Week of Mon 2026-10-12", "Week of Mon 2026-10-19", "3h00m", "0h30m", "3h30m"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected %q in:\n%s", want, text)
+		}
+	}
+	if !strings.Contains(renderTimeReport(nil), "No time attached") {
+		t.Error("Expected a note when no session time is recorded")
+	}
+}
@@ -0,0 +1,269 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"sshm/internal/config"
+	sshmssh "sshm/internal/ssh"
+	"sshm/internal/tui/views"
+)
+
+// statusLED is the indicator drawn in front of the status text
+const statusLED = "●"
+
+// Keys used to look up status colors besides the status classifications themselves
+const (
+	statusKeyWarn     = "warn"     // Online but slower than the warning threshold
+	statusKeyCritical = "critical" // Online but slower than the critical threshold
+	statusKeyRetried  = "retried"  // Failure that persisted across retries
+)
+
+// statusHostKey is the classification of servers whose host key changed
+const statusHostKey = sshmssh.StatusHostKey
+
+// defaultStatusColors maps status classifications and severities to colors
+var defaultStatusColors = map[string]tcell.Color{
+	"online":          tcell.ColorGreen,
+	"checking":        tcell.ColorYellow,
+	"unreachable":     tcell.ColorRed,
+	"refused":         tcell.ColorRed,
+	"error":           tcell.ColorRed,
+	"auth error":      tcell.ColorRed,
+	"auth failed":     tcell.ColorOrange,
+	statusHostKey:     tcell.ColorOrange,
+	"unknown":         tcell.ColorGray,
+	offlineStatus:     tcell.ColorGray,
+	statusScheduled:   tcell.ColorGray,
+	statusKeyWarn:     tcell.ColorYellow,
+	statusKeyCritical: tcell.ColorRed,
+	statusKeyRetried:  tcell.ColorDarkMagenta,
+}
+
+// Severities group status keys and session statuses so palettes and symbols
+// treat every kind of status alike
+const (
+	severityOK        = "ok"        // Online, ready
+	severityDegraded  = "degraded"  // Online but slow, or in use
+	severityAttention = "attention" // Needs a look: authentication problems, very slow, shared
+	severityFailed    = "failed"    // Down or unusable
+	severityPending   = "pending"   // Being checked
+	severityNeutral   = "neutral"   // Not checked or unknown
+)
+
+// statusSeverities classifies the status keys
+var statusSeverities = map[string]string{
+	"online":          severityOK,
+	"checking":        severityPending,
+	"unreachable":     severityFailed,
+	"refused":         severityFailed,
+	"error":           severityFailed,
+	"auth error":      severityFailed,
+	"auth failed":     severityAttention,
+	statusHostKey:     severityAttention,
+	"unknown":         severityNeutral,
+	offlineStatus:     severityNeutral,
+	statusScheduled:   severityNeutral,
+	statusKeyWarn:     severityDegraded,
+	statusKeyCritical: severityAttention,
+	statusKeyRetried:  severityFailed,
+}
+
+// severitySymbols mark each severity without relying on color
+var severitySymbols = map[string]string{
+	severityOK:        "✓",
+	severityDegraded:  "~",
+	severityAttention: "!",
+	severityFailed:    "✗",
+	severityPending:   "…",
+	severityNeutral:   "-",
+}
+
+// paletteColors are the colors of each severity in the palettes other than
+// the default one, which keeps a color per status key
+var paletteColors = map[string]map[string]tcell.Color{
+	config.PaletteColorblind: {
+		severityOK:        tcell.NewHexColor(0x56B4E9), // Sky blue
+		severityDegraded:  tcell.NewHexColor(0xF0E442), // Yellow
+		severityAttention: tcell.NewHexColor(0xE69F00), // Orange
+		severityFailed:    tcell.NewHexColor(0xD55E00), // Vermillion
+		severityPending:   tcell.ColorWhite,
+		severityNeutral:   tcell.ColorGray,
+	},
+	config.PaletteMonochrome: {
+		severityOK:        tcell.ColorWhite,
+		severityDegraded:  tcell.ColorWhite,
+		severityAttention: tcell.ColorWhite,
+		severityFailed:    tcell.ColorWhite,
+		severityPending:   tcell.ColorSilver,
+		severityNeutral:   tcell.ColorGray,
+	},
+}
+
+// formatStatus renders a status cell as an LED, or a symbol when configured,
+// plus text, picking the color from the classification, the latency
+// thresholds, the palette and any colors configured by the user
+func formatStatus(status string, attempts int, latency time.Duration, display config.StatusDisplayConfig) (string, tcell.Color) {
+	if _, known := defaultStatusColors[status]; !known {
+		status = "unknown"
+	}
+
+	key := status
+	text := status
+	switch {
+	case status == "online" && latency > 0:
+		text = fmt.Sprintf("%s %dms", status, latency.Milliseconds())
+		warn, critical := display.LatencyThresholds()
+		if latency >= critical {
+			key = statusKeyCritical
+		} else if latency >= warn {
+			key = statusKeyWarn
+		}
+	case attempts > 1 && status != "online" && status != "checking":
+		// Failures that survived retries are shown with their attempt count
+		text = fmt.Sprintf("%s ×%d", status, attempts)
+		key = statusKeyRetried
+	}
+
+	return fmt.Sprintf("%s %s", statusIndicator(statusSeverities[key], display), text), statusColor(key, display)
+}
+
+// statusIndicator returns the mark drawn in front of a status: the LED, or the
+// severity's symbol when symbols are on
+func statusIndicator(severity string, display config.StatusDisplayConfig) string {
+	if !display.ShowSymbols() {
+		return statusLED
+	}
+	if symbol, ok := severitySymbols[severity]; ok {
+		return symbol
+	}
+	return severitySymbols[severityNeutral]
+}
+
+// statusColor resolves the color for a status key, preferring the user's
+// configuration, then the palette
+func statusColor(key string, display config.StatusDisplayConfig) tcell.Color {
+	if name, ok := display.ColorFor(key); ok {
+		if color := tcell.GetColor(name); color != tcell.ColorDefault {
+			return color
+		}
+	}
+	if colors, ok := paletteColors[display.PaletteName()]; ok {
+		return severityColor(colors, statusSeverities[key])
+	}
+	if color, ok := defaultStatusColors[key]; ok {
+		return color
+	}
+	return tcell.ColorGray
+}
+
+// severityColor returns a palette's color for a severity
+func severityColor(colors map[string]tcell.Color, severity string) tcell.Color {
+	if color, ok := colors[severity]; ok {
+		return color
+	}
+	return colors[severityNeutral]
+}
+
+// sessionStatusSeverities classifies the statuses of the sessions panel
+var sessionStatusSeverities = map[string]string{
+	"active":              severityOK,
+	"detached":            severityOK,
+	"attached":            severityDegraded,
+	"multi-attached":      severityAttention,
+	"inactive":            severityFailed,
+	sessionConnectionLost: severityFailed,
+}
+
+// defaultSessionColors are the colors of session statuses in the default palette
+var defaultSessionColors = map[string]tcell.Color{
+	"active":              tcell.ColorGreen,
+	"detached":            tcell.ColorGreen,
+	"attached":            tcell.ColorYellow,
+	"multi-attached":      tcell.ColorOrange,
+	"inactive":            tcell.ColorRed,
+	sessionConnectionLost: tcell.ColorFuchsia,
+}
+
+// formatSessionStatus renders a session's status with the same palette and
+// symbols as the server status column
+func formatSessionStatus(status string, display config.StatusDisplayConfig) (string, tcell.Color) {
+	severity, ok := sessionStatusSeverities[status]
+	if !ok {
+		severity = severityNeutral
+	}
+
+	color := tcell.ColorGray
+	if colors, ok := paletteColors[display.PaletteName()]; ok {
+		color = severityColor(colors, severity)
+	} else if defaultColor, ok := defaultSessionColors[status]; ok {
+		color = defaultColor
+	}
+
+	if display.ShowSymbols() {
+		return statusIndicator(severity, display) + " " + status, color
+	}
+	return status, color
+}
+
+// profileBadge summarizes the status of a profile's servers for its tab: the
+// number of failed servers, else of servers needing attention, and "" when
+// none do
+func profileBadge(statuses []string, display config.StatusDisplayConfig) string {
+	failed, attention := 0, 0
+	for _, status := range statuses {
+		switch statusSeverities[status] {
+		case severityFailed:
+			failed++
+		case severityAttention:
+			attention++
+		}
+	}
+
+	severity, count, key := severityFailed, failed, "unreachable"
+	if failed == 0 {
+		severity, count, key = severityAttention, attention, "auth failed"
+	}
+	if count == 0 {
+		return ""
+	}
+	return fmt.Sprintf("[%s]%s%d[white]", statusColor(key, display).String(), statusIndicator(severity, display), count)
+}
+
+// profileStatusBadge returns the badge of a profile tab; the All and
+// Unassigned tabs have none
+func (t *TUIApp) profileStatusBadge(tab string) string {
+	profile, err := t.config.GetProfile(tab)
+	if err != nil {
+		return ""
+	}
+	t.statusMutex.RLock()
+	statuses := make([]string, 0, len(profile.Servers))
+	for _, name := range profile.Servers {
+		statuses = append(statuses, t.connectionStatus[name])
+	}
+	t.statusMutex.RUnlock()
+	return profileBadge(statuses, t.config.UI.Status)
+}
+
+// serverProvider feeds the server list from the status checks
+type serverProvider struct {
+	t *TUIApp
+}
+
+// ServerStatus implements views.ServerProvider
+func (p serverProvider) ServerStatus(serverName string) views.Status {
+	text, color := p.t.getCachedConnectionStatus(serverName)
+	return views.Status{Text: text, Color: color}
+}
+
+// ServerProfiles implements views.ServerProvider
+func (p serverProvider) ServerProfiles(serverName string) []string {
+	return p.t.getServerProfiles(serverName)
+}
+
+// ServerStats implements views.StatsProvider
+func (p serverProvider) ServerStats(serverName string) string {
+	return p.t.getCachedQuickStats(serverName)
+}
@@ -0,0 +1,123 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"sshm/internal/config"
+)
+
+func TestFormatStatusLatencyThresholds(t *testing.T) {
+	display := config.StatusDisplayConfig{LatencyWarnMS: 300, LatencyCriticalMS: 1000}
+
+	tests := []struct {
+		name    string
+		latency time.Duration
+		color   tcell.Color
+	}{
+		{"fast", 50 * time.Millisecond, tcell.ColorGreen},
+		{"slow", 400 * time.Millisecond, tcell.ColorYellow},
+		{"very slow", 1500 * time.Millisecond, tcell.ColorRed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, color := formatStatus("online", 1, tt.latency, display)
+			if color != tt.color {
+				t.Errorf("Expected color %v, got %v", tt.color, color)
+			}
+			if !strings.HasPrefix(text, statusLED+" online") || !strings.HasSuffix(text, "ms") {
+				t.Errorf("Unexpected status text: %s", text)
+			}
+		})
+	}
+}
+
+func TestFormatStatusClassifications(t *testing.T) {
+	display := config.StatusDisplayConfig{}
+
+	if text, color := formatStatus("auth failed", 1, 0, display); text != statusLED+" auth failed" || color != tcell.ColorOrange {
+		t.Errorf("Unexpected auth failure rendering: %s %v", text, color)
+	}
+	if text, color := formatStatus("unreachable", 3, 0, display); text != statusLED+" unreachable ×3" || color != tcell.ColorDarkMagenta {
+		t.Errorf("Unexpected retried failure rendering: %s %v", text, color)
+	}
+	if text, _ := formatStatus("something odd", 1, 0, display); text != statusLED+" unknown" {
+		t.Errorf("Expected unknown status, got %s", text)
+	}
+}
+
+func TestFormatStatusCustomColors(t *testing.T) {
+	display := config.StatusDisplayConfig{Colors: map[string]string{
+		"unreachable": "purple",
+		"warn":        "orange",
+		"online":      "not-a-color",
+	}}
+
+	if _, color := formatStatus("unreachable", 1, 0, display); color != tcell.ColorPurple {
+		t.Errorf("Expected configured color for classification, got %v", color)
+	}
+	if _, color := formatStatus("online", 1, 500*time.Millisecond, display); color != tcell.ColorOrange {
+		t.Errorf("Expected configured color for warning severity, got %v", color)
+	}
+	if _, color := formatStatus("online", 1, 10*time.Millisecond, display); color != tcell.ColorGreen {
+		t.Errorf("Expected invalid color name to fall back to default, got %v", color)
+	}
+}
+
+func TestFormatStatusSymbolsAndPalettes(t *testing.T) {
+	symbols := config.StatusDisplayConfig{Symbols: true}
+	if text, color := formatStatus("online", 1, 0, symbols); text != "✓ online" || color != tcell.ColorGreen {
+		t.Errorf("Expected a check mark keeping the default color, got %s %v", text, color)
+	}
+	if text, _ := formatStatus("unreachable", 1, 0, symbols); text != "✗ unreachable" {
+		t.Errorf("Expected a cross for failures, got %s", text)
+	}
+	if text, _ := formatStatus("online", 1, 500*time.Millisecond, symbols); !strings.HasPrefix(text, "~ online") {
+		t.Errorf("Expected a tilde for slow servers, got %s", text)
+	}
+
+	colorblind := config.StatusDisplayConfig{Palette: config.PaletteColorblind}
+	_, online := formatStatus("online", 1, 0, colorblind)
+	_, failed := formatStatus("refused", 1, 0, colorblind)
+	if online == tcell.ColorGreen || failed == tcell.ColorRed || online == failed {
+		t.Errorf("Expected distinct colorblind-safe colors, got %v and %v", online, failed)
+	}
+	colorblind.Colors = map[string]string{"refused": "purple"}
+	if _, color := formatStatus("refused", 1, 0, colorblind); color != tcell.ColorPurple {
+		t.Errorf("Expected configured colors to override the palette, got %v", color)
+	}
+
+	monochrome := config.StatusDisplayConfig{Palette: config.PaletteMonochrome}
+	if text, _ := formatStatus("auth failed", 1, 0, monochrome); text != "! auth failed" {
+		t.Errorf("Expected monochrome to always show symbols, got %s", text)
+	}
+}
+
+func TestFormatSessionStatus(t *testing.T) {
+	if text, color := formatSessionStatus("attached", config.StatusDisplayConfig{}); text != "attached" || color != tcell.ColorYellow {
+		t.Errorf("Expected the default session rendering, got %s %v", text, color)
+	}
+	symbols := config.StatusDisplayConfig{Symbols: true}
+	if text, color := formatSessionStatus(sessionConnectionLost, symbols); text != "✗ connection lost" || color != tcell.ColorFuchsia {
+		t.Errorf("Unexpected lost session rendering: %s %v", text, color)
+	}
+	if text, _ := formatSessionStatus("detached", symbols); text != "✓ detached" {
+		t.Errorf("Unexpected detached session rendering: %s", text)
+	}
+}
+
+func TestProfileBadge(t *testing.T) {
+	display := config.StatusDisplayConfig{Symbols: true}
+	if badge := profileBadge([]string{"online", "checking", ""}, display); badge != "" {
+		t.Errorf("Expected no badge for a healthy profile, got %q", badge)
+	}
+	if badge := profileBadge([]string{"online", "unreachable", "refused", "auth failed"}, display); badge != "[red]✗2[white]" {
+		t.Errorf("Expected failures to be counted, got %q", badge)
+	}
+	if badge := profileBadge([]string{"auth failed"}, config.StatusDisplayConfig{}); badge != "[orange]"+statusLED+"1[white]" {
+		t.Errorf("Expected an LED badge without symbols, got %q", badge)
+	}
+}
@@ -0,0 +1,56 @@
+package tui
+
+import (
+	"time"
+
+	"sshm/internal/config"
+	"sshm/internal/monitor"
+)
+
+const (
+	// statusCheckInterval is the average time between checks of each server
+	statusCheckInterval = 30 * time.Second
+	// scheduleTick is how often the schedule is consulted for due servers
+	scheduleTick = time.Second
+	// statusScheduled is shown for servers whose first check is still to come
+	statusScheduled = "scheduled"
+)
+
+// checkableServers returns the servers status checks apply to; expired scratch
+// servers are never checked
+func (t *TUIApp) checkableServers(now time.Time) []config.Server {
+	var servers []config.Server
+	for _, server := range t.config.GetServers() {
+		if !server.IsExpired(now) {
+			servers = append(servers, server)
+		}
+	}
+	return servers
+}
+
+// dueServers returns the servers whose scheduled check is due. Servers still
+// waiting for their first check are shown as scheduled rather than checking.
+func (t *TUIApp) dueServers(now time.Time) []config.Server {
+	var due []config.Server
+	for _, server := range t.checkableServers(now) {
+		plan := monitor.PlanFor(t.config, server, statusCheckInterval)
+		if t.statusSchedule.Due(server.Name, plan, now) {
+			due = append(due, server)
+			continue
+		}
+
+		t.statusMutex.Lock()
+		if _, ok := t.connectionStatus[server.Name]; !ok {
+			t.connectionStatus[server.Name] = statusScheduled
+		}
+		t.statusMutex.Unlock()
+	}
+	return due
+}
+
+// isStealth reports whether a server belongs to a stealth profile, whose checks
+// are TCP-only
+func (t *TUIApp) isStealth(server config.Server) bool {
+	_, stealth := t.config.StealthWindowFor(server.Name)
+	return stealth
+}
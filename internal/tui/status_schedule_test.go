@@ -0,0 +1,37 @@
+package tui
+
+import (
+	"testing"
+	"time"
+
+	"sshm/internal/config"
+	"sshm/internal/monitor"
+)
+
+func TestDueServersMarksScheduled(t *testing.T) {
+	app := &TUIApp{
+		config: &config.Config{
+			Servers: []config.Server{
+				{Name: "web", Hostname: "10.0.0.1", Port: 22},
+				{Name: "edge", Hostname: "10.0.0.2", Port: 22},
+			},
+			Profiles: []config.Profile{{Name: "dmz", Servers: []string{"edge"}, Stealth: true, StealthWindow: "1000h"}},
+		},
+		connectionStatus: make(map[string]string),
+		statusSchedule:   monitor.NewSchedule(),
+	}
+
+	// Ordinary servers are due within the jitter, stealth ones within their window
+	now := time.Now()
+	app.dueServers(now)
+	due := app.dueServers(now.Add(statusCheckInterval / 5))
+	if len(due) != 1 || due[0].Name != "web" {
+		t.Fatalf("Expected only web to be due, got %+v", due)
+	}
+	if got := app.connectionStatus["edge"]; got != statusScheduled {
+		t.Errorf("Expected the stealth server to be shown as scheduled, got %q", got)
+	}
+	if !app.isStealth(app.config.Servers[1]) || app.isStealth(app.config.Servers[0]) {
+		t.Error("Expected only edge to be stealth")
+	}
+}
@@ -0,0 +1,360 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"sshm/internal/config"
+	"sshm/internal/power"
+)
+
+const (
+	// tailRecentPaths is how many recent paths are remembered per server
+	tailRecentPaths = 10
+	// tailMaxLines caps the lines kept in the viewer
+	tailMaxLines = 5000
+)
+
+// rememberTailPath puts path first in the server's recent tail paths
+func rememberTailPath(recent []string, path string) []string {
+	paths := []string{path}
+	for _, existing := range recent {
+		if existing != path && len(paths) < tailRecentPaths {
+			paths = append(paths, existing)
+		}
+	}
+	return paths
+}
+
+// showTailPrompt asks for a remote file to follow on the selected server,
+// offering the paths tailed there before
+func (t *TUIApp) showTailPrompt() {
+	if t.focusedPanel != "servers" {
+		return
+	}
+	serverName := t.getSelectedServerName()
+	if serverName == "" {
+		return
+	}
+	server, err := t.config.GetServer(serverName)
+	if err != nil {
+		t.showErrorModal(fmt.Sprintf("Server '%s' not found: %s", serverName, err.Error()))
+		return
+	}
+	if t.isOffline() {
+		t.showTransientStatus("[yellow]Offline mode: tailing remote files is disabled (Ctrl+N)[white]")
+		return
+	}
+	if server.IsRestricted() {
+		t.showErrorModal(fmt.Sprintf("%s is restricted to its forced command", server.Name))
+		return
+	}
+
+	recent := t.tailPaths[server.Name]
+	initial := ""
+	if len(recent) > 0 {
+		initial = recent[0]
+	}
+
+	form := tview.NewForm().
+		AddInputField("Remote path", initial, 50, nil, nil).
+		AddButton("Tail", nil).
+		AddButton("Cancel", nil)
+	form.SetBorder(true).
+		SetTitle(fmt.Sprintf(" 📜 Tail File on %s ", server.Name)).
+		SetTitleAlign(tview.AlignCenter)
+
+	pathField := form.GetFormItem(0).(*tview.InputField)
+	pathField.SetAutocompleteFunc(func(text string) []string {
+		var matches []string
+		for _, path := range recent {
+			if strings.Contains(path, text) {
+				matches = append(matches, path)
+			}
+		}
+		return matches
+	})
+
+	form.GetButton(0).SetSelectedFunc(func() {
+		path := strings.TrimSpace(pathField.GetText())
+		if _, err := power.TailCommand(path); err != nil {
+			t.showErrorModal(err.Error())
+			return
+		}
+		t.modalManager.HideModal()
+		t.startTail(*server, path)
+	})
+	form.GetButton(1).SetSelectedFunc(func() {
+		t.modalManager.HideModal()
+	})
+	form.SetCancelFunc(func() {
+		t.modalManager.HideModal()
+	})
+	t.modalManager.ShowModal(form)
+}
+
+// startTail remembers the path and opens a viewer following the file
+func (t *TUIApp) startTail(server config.Server, path string) {
+	if t.tailPaths == nil {
+		t.tailPaths = make(map[string][]string)
+	}
+	t.tailPaths[server.Name] = rememberTailPath(t.tailPaths[server.Name], path)
+
+	command, _ := power.TailCommand(path)
+	t.startStreamViewer(fmt.Sprintf("%s:%s", server.Name, path), func(ctx context.Context, onLine func(string)) error {
+		return power.Stream(ctx, server, command, onLine)
+	})
+}
+
+// startStreamViewer opens a viewer showing the lines produced by stream; closing
+// the viewer cancels the stream's context
+func (t *TUIApp) startStreamViewer(title string, stream func(ctx context.Context, onLine func(string)) error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	viewer := newTailViewer(t, title, cancel)
+	viewer.show()
+
+	go func() {
+		err := stream(ctx, viewer.append)
+		if ctx.Err() != nil {
+			return
+		}
+		message := "[gray]— finished —[white]"
+		if err != nil {
+			message = fmt.Sprintf("[red]— %s —[white]", tview.Escape(err.Error()))
+		}
+		t.app.QueueUpdateDraw(func() {
+			viewer.footerNote = message
+			viewer.render()
+		})
+	}()
+}
+
+// tailViewer streams the output of a command such as tail -F, following the
+// end unless paused, with / searching and n/N jumping between matches
+type tailViewer struct {
+	app    *TUIApp
+	title  string
+	cancel context.CancelFunc
+
+	layout *tview.Flex
+	view   *tview.TextView
+	footer *tview.TextView
+
+	mu      sync.Mutex
+	lines   []string
+	queued  bool // A redraw is queued for new lines
+	pending int  // Lines received while paused
+
+	paused     bool
+	search     string
+	matchLine  int // Line of the current match, -1 when none
+	footerNote string
+}
+
+func newTailViewer(app *TUIApp, title string, cancel context.CancelFunc) *tailViewer {
+	v := &tailViewer{app: app, title: title, cancel: cancel, matchLine: -1}
+	v.view = tview.NewTextView().
+		SetDynamicColors(true).
+		SetScrollable(true).
+		SetWrap(false)
+	v.view.SetBorder(true).SetBorderColor(tcell.ColorYellow)
+	v.footer = tview.NewTextView().SetDynamicColors(true)
+	v.layout = tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(v.view, 0, 1, true).
+		AddItem(v.footer, 1, 0, false)
+	v.view.SetInputCapture(v.handleKey)
+	return v
+}
+
+// show opens the viewer over the main layout; closing it stops the remote tail
+func (v *tailViewer) show() {
+	v.render()
+	v.app.modalManager.ShowModal(v.layout)
+	v.app.modalManager.OnClose(v.layout, v.cancel)
+}
+
+// close closes the viewer
+func (v *tailViewer) close() {
+	v.app.modalManager.HideModal()
+}
+
+// append adds a line from the stream; it may be called from any goroutine
+func (v *tailViewer) append(line string) {
+	v.mu.Lock()
+	v.lines = append(v.lines, line)
+	if len(v.lines) > tailMaxLines {
+		dropped := len(v.lines) - tailMaxLines
+		v.lines = v.lines[dropped:]
+		if v.matchLine >= 0 {
+			v.matchLine -= dropped
+		}
+	}
+	if v.paused {
+		v.pending++
+	}
+	queue := !v.queued
+	v.queued = true
+	v.mu.Unlock()
+
+	if queue {
+		v.app.app.QueueUpdateDraw(func() {
+			v.mu.Lock()
+			v.queued = false
+			v.mu.Unlock()
+			if !v.paused {
+				v.render()
+			} else {
+				v.renderFooter()
+			}
+		})
+	}
+}
+
+// render redraws the lines, highlighting search matches, and scrolls to the
+// end of the file unless paused
+func (v *tailViewer) render() {
+	v.mu.Lock()
+	text := renderTailLines(v.lines, v.search)
+	v.mu.Unlock()
+
+	row, _ := v.view.GetScrollOffset()
+	v.view.SetText(text)
+	if v.paused {
+		v.view.ScrollTo(row, 0)
+	} else {
+		v.view.ScrollToEnd()
+	}
+	v.renderFooter()
+}
+
+// renderFooter shows the state and keys of the viewer
+func (v *tailViewer) renderFooter() {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	state := "[green]following[white]"
+	if v.paused {
+		state = fmt.Sprintf("[yellow]paused, %d new line(s)[white]", v.pending)
+	}
+	v.view.SetTitle(fmt.Sprintf(" 📜 %s — %s ", tview.Escape(v.title), state))
+
+	keys := "[gray]Space pause/resume · / search · n/N next/previous match · q close[white]"
+	if v.search != "" {
+		keys = fmt.Sprintf("[gray]search:[white] %s  %s", tview.Escape(v.search), keys)
+	}
+	if v.footerNote != "" {
+		keys = v.footerNote + "  " + keys
+	}
+	v.footer.SetText(keys)
+}
+
+// renderTailLines escapes the lines for display and highlights occurrences of search
+func renderTailLines(lines []string, search string) string {
+	var b strings.Builder
+	for _, line := range lines {
+		if search == "" || !strings.Contains(line, search) {
+			b.WriteString(tview.Escape(line))
+		} else {
+			parts := strings.Split(line, search)
+			for i, part := range parts {
+				if i > 0 {
+					b.WriteString("[black:yellow]" + tview.Escape(search) + "[-:-]")
+				}
+				b.WriteString(tview.Escape(part))
+			}
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// setPaused stops or resumes following the end of the file
+func (v *tailViewer) setPaused(paused bool) {
+	v.mu.Lock()
+	v.paused = paused
+	v.pending = 0
+	v.mu.Unlock()
+	v.render()
+}
+
+// jumpToMatch pauses on the next (direction 1) or previous (-1) line containing
+// the search term
+func (v *tailViewer) jumpToMatch(direction int) {
+	v.mu.Lock()
+	found := -1
+	if v.search != "" && len(v.lines) > 0 {
+		start := v.matchLine
+		if start < 0 {
+			start, _ = v.view.GetScrollOffset()
+			start -= direction
+		}
+		for i := 1; i <= len(v.lines); i++ {
+			line := ((start+direction*i)%len(v.lines) + len(v.lines)) % len(v.lines)
+			if strings.Contains(v.lines[line], v.search) {
+				found = line
+				break
+			}
+		}
+	}
+	v.matchLine = found
+	v.mu.Unlock()
+
+	if found < 0 {
+		v.app.showTransientStatus("[yellow]No matches[white]")
+		return
+	}
+	v.setPaused(true)
+	v.view.ScrollTo(found, 0)
+}
+
+// promptSearch replaces the footer with a search field
+func (v *tailViewer) promptSearch() {
+	input := tview.NewInputField().SetLabel("/").SetText(v.search)
+	input.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEnter {
+			v.mu.Lock()
+			v.search = input.GetText()
+			v.matchLine = -1
+			v.mu.Unlock()
+		}
+		v.layout.RemoveItem(input)
+		v.layout.AddItem(v.footer, 1, 0, false)
+		v.app.app.SetFocus(v.view)
+		v.render()
+		if key == tcell.KeyEnter && v.search != "" {
+			v.jumpToMatch(-1)
+		}
+	})
+	v.layout.RemoveItem(v.footer)
+	v.layout.AddItem(input, 1, 0, true)
+	v.app.app.SetFocus(input)
+}
+
+func (v *tailViewer) handleKey(event *tcell.EventKey) *tcell.EventKey {
+	if event.Key() == tcell.KeyEscape {
+		v.close()
+		return nil
+	}
+	switch event.Rune() {
+	case 'q', 'Q':
+		v.close()
+		return nil
+	case ' ', 'p':
+		v.setPaused(!v.paused)
+		return nil
+	case '/':
+		v.promptSearch()
+		return nil
+	case 'n':
+		v.jumpToMatch(1)
+		return nil
+	case 'N':
+		v.jumpToMatch(-1)
+		return nil
+	}
+	return event
+}
@@ -0,0 +1,65 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/rivo/tview"
+)
+
+func TestRememberTailPath(t *testing.T) {
+	paths := rememberTailPath(nil, "/var/log/syslog")
+	paths = rememberTailPath(paths, "/var/log/nginx/error.log")
+	paths = rememberTailPath(paths, "/var/log/syslog")
+
+	if strings.Join(paths, ",") != "/var/log/syslog,/var/log/nginx/error.log" {
+		t.Errorf("Expected the reused path first without duplicates, got %v", paths)
+	}
+
+	for i := 0; i < tailRecentPaths+5; i++ {
+		paths = rememberTailPath(paths, fmt.Sprintf("/tmp/%d.log", i))
+	}
+	if len(paths) != tailRecentPaths {
+		t.Errorf("Expected %d recent paths, got %d", tailRecentPaths, len(paths))
+	}
+	if paths[0] != fmt.Sprintf("/tmp/%d.log", tailRecentPaths+4) {
+		t.Errorf("Expected the latest path first, got %s", paths[0])
+	}
+}
+
+func TestRenderTailLines(t *testing.T) {
+	text := renderTailLines([]string{"GET /health 200", "GET /login 500 [error]"}, "500")
+
+	lines := strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines, got %d: %q", len(lines), text)
+	}
+	if lines[0] != "GET /health 200" {
+		t.Errorf("Expected a line without matches unchanged, got %q", lines[0])
+	}
+	if !strings.Contains(lines[1], "[black:yellow]500[-:-]") {
+		t.Errorf("Expected the match to be highlighted, got %q", lines[1])
+	}
+	if !strings.Contains(lines[1], "[error[]") {
+		t.Errorf("Expected tags in the output to be escaped, got %q", lines[1])
+	}
+}
+
+func TestTailViewerStopsWhenClosed(t *testing.T) {
+	app := &TUIApp{app: tview.NewApplication()}
+	app.modalManager = NewModalManager(app.app, tview.NewFlex())
+
+	stopped := false
+	viewer := newTailViewer(app, "web1:/var/log/syslog", func() { stopped = true })
+	viewer.show()
+
+	// Escape is handled globally, hiding the modal without going through the viewer
+	app.modalManager.HideModal()
+	if !stopped {
+		t.Error("Expected closing the viewer to stop the remote tail")
+	}
+	if app.modalManager.IsModalActive() {
+		t.Error("Expected the viewer to be closed")
+	}
+}
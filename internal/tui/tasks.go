@@ -0,0 +1,367 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// taskSpec describes a long-running background task started from the TUI
+type taskSpec struct {
+	Name            string // Short name, e.g. "export" or "connect to profile 'web'"
+	Unit            string // What Total counts, e.g. "connections"; empty to show a percentage
+	Total           int    // Number of steps, 0 when unknown
+	Cancellable     bool   // Whether the task stops cleanly when its context is cancelled
+	SafeToInterrupt bool   // Whether quitting may interrupt the task without asking, e.g. status checks
+}
+
+// task is a running background task such as an import, a group connect or a
+// bulk status check, shown in the tasks overlay
+type task struct {
+	manager *taskManager
+	id      int
+	spec    taskSpec
+	started time.Time
+	done    int                // Protected by manager.mu
+	total   int                // Protected by manager.mu
+	message string             // Protected by manager.mu
+	cancel  context.CancelFunc // Nil when the task cannot be cancelled safely
+}
+
+// taskInfo is a snapshot of a running task
+type taskInfo struct {
+	ID              int
+	Name            string
+	Unit            string
+	Message         string
+	Done            int
+	Total           int
+	Started         time.Time
+	Cancellable     bool
+	SafeToInterrupt bool
+}
+
+// taskManager tracks the running background tasks. The zero value is ready to use.
+type taskManager struct {
+	mu           sync.Mutex
+	nextID       int
+	running      map[int]*task
+	quitWhenIdle bool   // Quit once the last task that blocks quitting finishes
+	onIdle       func() // Called without the lock when quitWhenIdle is set and nothing blocks quitting
+}
+
+// start registers a new task; cancellable tasks get a context that is cancelled
+// from the tasks overlay or when the user cancels tasks on quit
+func (m *taskManager) start(spec taskSpec) (*task, context.Context) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ctx := context.Background()
+	tk := &task{manager: m, spec: spec, started: time.Now(), total: spec.Total}
+	if spec.Cancellable {
+		ctx, tk.cancel = context.WithCancel(ctx)
+	}
+	m.nextID++
+	tk.id = m.nextID
+	if m.running == nil {
+		m.running = make(map[int]*task)
+	}
+	m.running[tk.id] = tk
+	return tk, ctx
+}
+
+// Progress records how much of the task is done
+func (tk *task) Progress(done, total int) {
+	tk.manager.mu.Lock()
+	defer tk.manager.mu.Unlock()
+	tk.done, tk.total = done, total
+}
+
+// Update records progress together with a message describing the current step
+func (tk *task) Update(done, total int, message string) {
+	tk.manager.mu.Lock()
+	defer tk.manager.mu.Unlock()
+	tk.done, tk.total, tk.message = done, total, message
+}
+
+// Finish removes the task and triggers a pending quit once nothing blocks quitting
+func (tk *task) Finish() {
+	m := tk.manager
+	m.mu.Lock()
+	delete(m.running, tk.id)
+	if tk.cancel != nil {
+		tk.cancel()
+	}
+	var onIdle func()
+	if m.quitWhenIdle && m.blockingLocked() == 0 {
+		m.quitWhenIdle = false
+		onIdle = m.onIdle
+	}
+	m.mu.Unlock()
+
+	if onIdle != nil {
+		onIdle()
+	}
+}
+
+// List returns a snapshot of the running tasks in start order
+func (m *taskManager) List() []taskInfo {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	infos := make([]taskInfo, 0, len(m.running))
+	for _, tk := range m.running {
+		infos = append(infos, taskInfo{
+			ID:              tk.id,
+			Name:            tk.spec.Name,
+			Unit:            tk.spec.Unit,
+			Message:         tk.message,
+			Done:            tk.done,
+			Total:           tk.total,
+			Started:         tk.started,
+			Cancellable:     tk.cancel != nil,
+			SafeToInterrupt: tk.spec.SafeToInterrupt,
+		})
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].ID < infos[j].ID })
+	return infos
+}
+
+// Blocking returns the running tasks that quitting would interrupt
+func (m *taskManager) Blocking() []taskInfo {
+	var blocking []taskInfo
+	for _, info := range m.List() {
+		if !info.SafeToInterrupt {
+			blocking = append(blocking, info)
+		}
+	}
+	return blocking
+}
+
+// blockingLocked counts the tasks that block quitting; m.mu must be held
+func (m *taskManager) blockingLocked() int {
+	count := 0
+	for _, tk := range m.running {
+		if !tk.spec.SafeToInterrupt {
+			count++
+		}
+	}
+	return count
+}
+
+// Cancel cancels one task and reports whether it could be cancelled
+func (m *taskManager) Cancel(id int) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	tk, ok := m.running[id]
+	if !ok || tk.cancel == nil {
+		return false
+	}
+	tk.cancel()
+	return true
+}
+
+// CancelAll cancels every cancellable task and returns how many blocking tasks cannot be cancelled
+func (m *taskManager) CancelAll() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	uncancellable := 0
+	for _, tk := range m.running {
+		if tk.cancel != nil {
+			tk.cancel()
+		} else if !tk.spec.SafeToInterrupt {
+			uncancellable++
+		}
+	}
+	return uncancellable
+}
+
+// QuitWhenIdle arranges for onIdle to run once no task blocks quitting. It returns
+// false when nothing blocks quitting, in which case the caller quits itself.
+func (m *taskManager) QuitWhenIdle(onIdle func()) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.blockingLocked() == 0 {
+		return false
+	}
+	m.quitWhenIdle = true
+	m.onIdle = onIdle
+	return true
+}
+
+// AbortQuit forgets a pending quit
+func (m *taskManager) AbortQuit() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.quitWhenIdle = false
+	m.onIdle = nil
+}
+
+// describeTask renders one task for the quit summary, e.g. "export 60% complete"
+func describeTask(info taskInfo) string {
+	switch {
+	case info.Total > 0 && info.Unit != "":
+		return fmt.Sprintf("%s: %d of %d %s in progress", info.Name, info.Total-info.Done, info.Total, info.Unit)
+	case info.Total > 0:
+		return fmt.Sprintf("%s %d%% complete", info.Name, info.Done*100/info.Total)
+	default:
+		return fmt.Sprintf("%s in progress", info.Name)
+	}
+}
+
+// taskProgress renders the progress column of the tasks overlay
+func taskProgress(info taskInfo) string {
+	if info.Total <= 0 {
+		return "…"
+	}
+	const width = 10
+	filled := info.Done * width / info.Total
+	return fmt.Sprintf("%s%s %3d%%", strings.Repeat("█", filled), strings.Repeat("░", width-filled), info.Done*100/info.Total)
+}
+
+// showTasksOverlay lists the running background tasks with their progress and
+// elapsed time; x cancels the selected task
+func (t *TUIApp) showTasksOverlay() {
+	table := tview.NewTable().
+		SetSelectable(true, false).
+		SetFixed(1, 0)
+	table.SetBorder(true).
+		SetTitle(" Tasks ").
+		SetBorderColor(tcell.ColorYellow)
+
+	footer := tview.NewTextView().
+		SetDynamicColors(true).
+		SetText("[gray]x: cancel selected task  •  Escape/q: close[white]")
+
+	var shown []taskInfo
+	render := func() {
+		shown = t.tasks.List()
+		table.Clear()
+		for col, header := range []string{"Task", "Progress", "Elapsed", "Step"} {
+			table.SetCell(0, col, tview.NewTableCell(header).
+				SetTextColor(tcell.ColorYellow).
+				SetSelectable(false))
+		}
+		if len(shown) == 0 {
+			table.SetCell(1, 0, tview.NewTableCell("No tasks running").SetTextColor(tcell.ColorGray))
+			return
+		}
+		now := time.Now()
+		for i, info := range shown {
+			name := info.Name
+			if !info.Cancellable {
+				name += " [gray](not cancellable)"
+			}
+			table.SetCell(i+1, 0, tview.NewTableCell(name))
+			table.SetCell(i+1, 1, tview.NewTableCell(taskProgress(info)).SetTextColor(tcell.ColorGreen))
+			table.SetCell(i+1, 2, tview.NewTableCell(now.Sub(info.Started).Round(time.Second).String()))
+			table.SetCell(i+1, 3, tview.NewTableCell(tview.Escape(info.Message)).SetExpansion(1))
+		}
+	}
+	render()
+
+	panel := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(table, 0, 1, true).
+		AddItem(footer, 1, 0, false)
+
+	// Refresh the progress and elapsed time every second while the overlay is open
+	stop := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				t.app.QueueUpdateDraw(render)
+			}
+		}
+	}()
+
+	panel.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape || event.Rune() == 'q' || event.Rune() == 'Q' {
+			close(stop)
+			t.modalManager.HideModal()
+			return nil
+		}
+		if event.Rune() == 'x' || event.Key() == tcell.KeyDelete {
+			row, _ := table.GetSelection()
+			if row < 1 || row > len(shown) {
+				return nil
+			}
+			info := shown[row-1]
+			if t.tasks.Cancel(info.ID) {
+				footer.SetText(fmt.Sprintf("[yellow]Cancelling %s...[white]", tview.Escape(info.Name)))
+			} else {
+				footer.SetText(fmt.Sprintf("[red]%s cannot be cancelled safely[white]", tview.Escape(info.Name)))
+			}
+			return nil
+		}
+		return event
+	})
+
+	t.modalManager.ShowModal(panel)
+}
+
+// showQuitTasksModal lists the running background tasks and lets the user wait
+// for them, cancel them or quit anyway
+func (t *TUIApp) showQuitTasksModal() {
+	var b strings.Builder
+	b.WriteString("Background operations are still running:\n\n")
+	for _, info := range t.tasks.Blocking() {
+		fmt.Fprintf(&b, "• %s\n", describeTask(info))
+	}
+	b.WriteString("\nQuitting now interrupts them.")
+
+	waitThenQuit := func(note string) {
+		waiting := t.tasks.QuitWhenIdle(func() {
+			t.app.QueueUpdateDraw(t.requestQuit)
+		})
+		if !waiting {
+			t.requestQuit()
+			return
+		}
+		t.showTransientStatus(fmt.Sprintf("[yellow]%sQuitting once %d operation(s) finish (press q again to change)[white]", note, len(t.tasks.Blocking())))
+	}
+
+	modal := tview.NewModal().
+		SetText(b.String()).
+		AddButtons([]string{"Wait", "Cancel operations", "Force quit", "Back"}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			t.modalManager.HideModal()
+			switch buttonLabel {
+			case "Wait":
+				waitThenQuit("")
+			case "Cancel operations":
+				note := "Cancelling. "
+				if uncancellable := t.tasks.CancelAll(); uncancellable > 0 {
+					note = fmt.Sprintf("%d operation(s) cannot be cancelled safely. ", uncancellable)
+				}
+				waitThenQuit(note)
+			case "Force quit":
+				t.Stop()
+			default:
+				t.tasks.AbortQuit()
+			}
+		}).
+		SetBackgroundColor(tcell.ColorDarkRed)
+	modal.SetTitle(" Operations Running ")
+	modal.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape {
+			t.modalManager.HideModal()
+			t.tasks.AbortQuit()
+			return nil
+		}
+		return event
+	})
+	t.modalManager.ShowModal(modal)
+}
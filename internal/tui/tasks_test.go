@@ -0,0 +1,81 @@
+package tui
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDescribeTask(t *testing.T) {
+	tests := []struct {
+		info taskInfo
+		want string
+	}{
+		{taskInfo{Name: "export", Done: 3, Total: 5}, "export 60% complete"},
+		{taskInfo{Name: "connect to profile 'web'", Unit: "connections", Total: 3}, "connect to profile 'web': 3 of 3 connections in progress"},
+		{taskInfo{Name: "update audit"}, "update audit in progress"},
+	}
+	for _, tt := range tests {
+		if got := describeTask(tt.info); got != tt.want {
+			t.Errorf("describeTask(%q) = %q, want %q", tt.info.Name, got, tt.want)
+		}
+	}
+}
+
+func TestTaskProgress(t *testing.T) {
+	if got := taskProgress(taskInfo{Done: 1, Total: 4}); got != "██░░░░░░░░  25%" {
+		t.Errorf("taskProgress() = %q", got)
+	}
+	if got := taskProgress(taskInfo{}); got != "…" {
+		t.Errorf("taskProgress() without total = %q", got)
+	}
+}
+
+func TestTaskManager(t *testing.T) {
+	var tasks taskManager
+
+	export, _ := tasks.start(taskSpec{Name: "export", Total: 5})
+	cleanup, ctx := tasks.start(taskSpec{Name: "session cleanup", Cancellable: true})
+	status, statusCtx := tasks.start(taskSpec{Name: "status check", Cancellable: true, SafeToInterrupt: true})
+	export.Update(3, 5, "Writing file...")
+
+	var names []string
+	for _, info := range tasks.Blocking() {
+		names = append(names, describeTask(info))
+	}
+	if want := []string{"export 60% complete", "session cleanup in progress"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("Blocking() = %v, want %v", names, want)
+	}
+	if list := tasks.List(); len(list) != 3 || list[0].Message != "Writing file..." {
+		t.Errorf("Unexpected task list: %+v", list)
+	}
+
+	if tasks.Cancel(export.id) {
+		t.Error("Expected export not to be cancellable")
+	}
+	if !tasks.Cancel(status.id) || statusCtx.Err() == nil {
+		t.Error("Expected status check to be cancelled")
+	}
+	if uncancellable := tasks.CancelAll(); uncancellable != 1 {
+		t.Errorf("Expected 1 uncancellable task, got %d", uncancellable)
+	}
+	if ctx.Err() == nil {
+		t.Error("Expected cleanup context to be cancelled")
+	}
+
+	quit := 0
+	if !tasks.QuitWhenIdle(func() { quit++ }) {
+		t.Fatal("Expected to wait for running tasks")
+	}
+	cleanup.Finish()
+	if quit != 0 {
+		t.Error("Quit before all tasks finished")
+	}
+	export.Finish()
+	if quit != 1 {
+		t.Errorf("Expected quit once only safe tasks remain, got %d calls", quit)
+	}
+	status.Finish()
+	if tasks.QuitWhenIdle(func() {}) {
+		t.Error("Expected nothing to wait for once idle")
+	}
+}
@@ -0,0 +1,53 @@
+package tui
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// showTextPanel displays read-only, scrollable text in a bordered modal panel
+func (t *TUIApp) showTextPanel(title, text string) *tview.TextView {
+	textView := tview.NewTextView().
+		SetDynamicColors(true).
+		SetScrollable(true).
+		SetWrap(true).
+		SetText(text)
+	textView.SetBorder(true).
+		SetTitle(" " + title + " ").
+		SetBorderColor(tcell.ColorYellow)
+
+	textView.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEnter, tcell.KeyEscape:
+			t.hideTextPanel()
+			return nil
+		}
+		switch event.Rune() {
+		case 'q', 'Q':
+			t.hideTextPanel()
+			return nil
+		case 'c':
+			t.showCopyMode(title, text)
+			return nil
+		}
+		return event
+	})
+
+	if t.modalManager != nil {
+		t.modalManager.ShowModal(textView)
+	} else {
+		t.setRoot(textView)
+		t.app.SetFocus(textView)
+	}
+	return textView
+}
+
+// hideTextPanel closes a panel opened by showTextPanel
+func (t *TUIApp) hideTextPanel() {
+	if t.modalManager != nil {
+		t.modalManager.HideModal()
+	} else {
+		t.setRoot(t.layout)
+		t.app.SetFocus(t.layout)
+	}
+}
@@ -0,0 +1,544 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"sshm/internal/config"
+	"sshm/internal/transfer"
+)
+
+// transferListTimeout bounds listing a remote directory
+const transferListTimeout = 30 * time.Second
+
+// Queued transfer states
+const (
+	transferQueued = iota
+	transferRunning
+	transferDone
+	transferFailed
+)
+
+// transferPane is one side of the transfer view
+type transferPane struct {
+	remote  bool
+	title   string
+	dir     string
+	entries []transfer.Entry
+	table   *tview.Table
+}
+
+// join returns the path of name inside the pane's directory
+func (p *transferPane) join(name string) string {
+	if p.remote {
+		return path.Join(p.dir, name)
+	}
+	return filepath.Join(p.dir, name)
+}
+
+// parent returns the parent of the pane's directory
+func (p *transferPane) parent() string {
+	if p.remote {
+		return path.Dir(p.dir)
+	}
+	return filepath.Dir(p.dir)
+}
+
+// find returns the entry called name, if listed
+func (p *transferPane) find(name string) (transfer.Entry, bool) {
+	for _, entry := range p.entries {
+		if entry.Name == name {
+			return entry, true
+		}
+	}
+	return transfer.Entry{}, false
+}
+
+// queuedTransfer is a job in the transfer queue
+type queuedTransfer struct {
+	job   transfer.Job
+	state int
+	done  int64
+	meter throughputMeter
+	err   error
+}
+
+// throughputMeter measures the bytes per second of a transfer over the last second
+type throughputMeter struct {
+	rate      float64
+	sampledAt time.Time
+	sampled   int64
+}
+
+// sample records the bytes done so far and reports whether the rate was updated
+func (m *throughputMeter) sample(done int64, now time.Time) bool {
+	if m.sampledAt.IsZero() {
+		m.sampledAt, m.sampled = now, done
+		return false
+	}
+	elapsed := now.Sub(m.sampledAt)
+	if elapsed < time.Second {
+		return false
+	}
+	m.rate = float64(done-m.sampled) / elapsed.Seconds()
+	m.sampledAt, m.sampled = now, done
+	return true
+}
+
+// transferView is a two-pane file manager copying and moving files between
+// the local machine (left) and a server (right), queued within the transfer limits
+type transferView struct {
+	app    *TUIApp
+	server config.Server
+
+	local, remote *transferPane
+	active        *transferPane
+	layout        *tview.Flex
+	queueView     *tview.TextView
+
+	scheduler *transfer.Scheduler // Shared by every transfer view, applying the transfer limits
+
+	mu     sync.Mutex
+	jobs   []*queuedTransfer
+	closed bool // The view was closed; finished jobs no longer refresh it
+}
+
+// showTransferView opens the transfer view for the selected server
+func (t *TUIApp) showTransferView() {
+	if t.focusedPanel != "servers" {
+		return
+	}
+	serverName := t.getSelectedServerName()
+	if serverName == "" {
+		return
+	}
+	server, err := t.config.GetServer(serverName)
+	if err != nil {
+		t.showErrorModal(fmt.Sprintf("Server '%s' not found: %s", serverName, err.Error()))
+		return
+	}
+	if t.isOffline() {
+		t.showTransientStatus("[yellow]Offline mode: file transfers are disabled (Ctrl+N)[white]")
+		return
+	}
+	if server.IsRestricted() {
+		t.showErrorModal(fmt.Sprintf("%s is restricted to its forced command", server.Name))
+		return
+	}
+
+	scheduler, err := t.transferScheduler()
+	if err != nil {
+		t.showErrorModal(fmt.Sprintf("Invalid transfers settings: %s", err.Error()))
+		return
+	}
+
+	localDir, err := os.Getwd()
+	if err != nil {
+		localDir, _ = os.UserHomeDir()
+	}
+	v := newTransferView(t, *server, localDir)
+	v.scheduler = scheduler
+	v.show()
+	v.refreshPane(v.local, localDir)
+	v.refreshPane(v.remote, "")
+}
+
+// transferScheduler returns the scheduler shared by all transfers, applying the
+// current global limits
+func (t *TUIApp) transferScheduler() (*transfer.Scheduler, error) {
+	if t.transfers == nil {
+		scheduler, err := transfer.NewScheduler(t.config.Transfers)
+		if err != nil {
+			return nil, err
+		}
+		t.transfers = scheduler
+		return scheduler, nil
+	}
+	if err := t.transfers.Configure(t.config.Transfers); err != nil {
+		return nil, err
+	}
+	return t.transfers, nil
+}
+
+func newTransferView(app *TUIApp, server config.Server, localDir string) *transferView {
+	v := &transferView{app: app, server: server}
+	v.local = &transferPane{title: "Local", dir: localDir, table: newTransferTable()}
+	v.remote = &transferPane{remote: true, title: server.Name, table: newTransferTable()}
+	v.active = v.local
+
+	v.queueView = tview.NewTextView().SetDynamicColors(true)
+	v.queueView.SetBorder(true).SetTitle(" Queue ")
+
+	footer := tview.NewTextView().
+		SetDynamicColors(true).
+		SetText("[yellow]Tab[white]: Switch pane  [yellow]Enter[white]: Open dir  [yellow]Backspace[white]: Parent  [yellow]c/F5[white]: Copy  [yellow]m/F6[white]: Move  [yellow]r[white]: Refresh  [yellow]Esc/q[white]: Close")
+
+	panes := tview.NewFlex().
+		AddItem(v.local.table, 0, 1, true).
+		AddItem(v.remote.table, 0, 1, false)
+	v.layout = tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(panes, 0, 1, true).
+		AddItem(v.queueView, 6, 0, false).
+		AddItem(footer, 1, 0, false)
+
+	for _, pane := range []*transferPane{v.local, v.remote} {
+		pane := pane
+		pane.table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+			return v.handleKey(pane, event)
+		})
+	}
+	v.renderPane(v.local)
+	v.renderPane(v.remote)
+	v.renderQueue()
+	return v
+}
+
+func newTransferTable() *tview.Table {
+	table := tview.NewTable().
+		SetSelectable(true, false).
+		SetFixed(1, 0)
+	table.SetBorder(true)
+	return table
+}
+
+// show opens the view over the main layout. Transfers keep running after it
+// closes and can be cancelled from the tasks overlay.
+func (v *transferView) show() {
+	v.app.modalManager.ShowModal(v.layout)
+	v.app.modalManager.OnClose(v.layout, func() {
+		v.mu.Lock()
+		v.closed = true
+		v.mu.Unlock()
+	})
+	v.app.app.SetFocus(v.active.table)
+}
+
+// refreshPane lists dir in the pane, in the background for the server
+func (v *transferView) refreshPane(pane *transferPane, dir string) {
+	if !pane.remote {
+		entries, err := transfer.ListLocal(dir)
+		if err != nil {
+			v.app.showErrorModal(err.Error())
+			return
+		}
+		pane.dir, pane.entries = dir, entries
+		v.renderPane(pane)
+		return
+	}
+
+	pane.table.SetTitle(fmt.Sprintf(" %s: loading… ", tview.Escape(pane.title)))
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), transferListTimeout)
+		defer cancel()
+		resolved, entries, err := transfer.ListRemote(ctx, v.server, dir)
+		v.app.app.QueueUpdateDraw(func() {
+			if err != nil {
+				v.renderPane(pane)
+				v.app.showErrorModal(err.Error())
+				return
+			}
+			pane.dir, pane.entries = resolved, entries
+			v.renderPane(pane)
+		})
+	}()
+}
+
+// renderPane redraws a pane's listing, keeping the selected row
+func (v *transferView) renderPane(pane *transferPane) {
+	selected, _ := pane.table.GetSelection()
+	pane.table.Clear()
+
+	color := tcell.ColorGray
+	if pane == v.active {
+		color = tcell.ColorYellow
+	}
+	pane.table.SetBorderColor(color)
+	pane.table.SetTitle(fmt.Sprintf(" %s: %s ", tview.Escape(pane.title), tview.Escape(pane.dir)))
+
+	pane.table.SetCell(0, 0, tview.NewTableCell("Name").SetTextColor(tcell.ColorYellow).SetSelectable(false).SetExpansion(1))
+	pane.table.SetCell(0, 1, tview.NewTableCell("Size").SetTextColor(tcell.ColorYellow).SetSelectable(false).SetAlign(tview.AlignRight))
+	pane.table.SetCell(1, 0, tview.NewTableCell("..").SetReference("..").SetTextColor(tcell.ColorBlue))
+	pane.table.SetCell(1, 1, tview.NewTableCell(""))
+	for i, entry := range pane.entries {
+		row := i + 2
+		name, size := tview.Escape(entry.Name), formatTransferSize(entry.Size)
+		textColor := tcell.ColorWhite
+		if entry.Dir {
+			name, size, textColor = name+"/", "<dir>", tcell.ColorBlue
+		}
+		pane.table.SetCell(row, 0, tview.NewTableCell(name).SetReference(entry.Name).SetTextColor(textColor).SetExpansion(1))
+		pane.table.SetCell(row, 1, tview.NewTableCell(size).SetAlign(tview.AlignRight))
+	}
+
+	if selected < 1 || selected >= pane.table.GetRowCount() {
+		selected = 1
+	}
+	pane.table.Select(selected, 0)
+}
+
+// selectedEntry returns the entry under the cursor; ok is false on ".."
+func (p *transferPane) selectedEntry() (entry transfer.Entry, ok bool) {
+	row, _ := p.table.GetSelection()
+	if row < 2 || row-2 >= len(p.entries) {
+		return transfer.Entry{}, false
+	}
+	return p.entries[row-2], true
+}
+
+func (v *transferView) handleKey(pane *transferPane, event *tcell.EventKey) *tcell.EventKey {
+	switch event.Key() {
+	case tcell.KeyTab:
+		v.active = v.otherPane(pane)
+		v.renderPane(v.local)
+		v.renderPane(v.remote)
+		v.app.app.SetFocus(v.active.table)
+		return nil
+	case tcell.KeyEnter:
+		entry, ok := pane.selectedEntry()
+		if !ok {
+			v.refreshPane(pane, pane.parent())
+		} else if entry.Dir {
+			v.refreshPane(pane, pane.join(entry.Name))
+		}
+		return nil
+	case tcell.KeyBackspace, tcell.KeyBackspace2:
+		v.refreshPane(pane, pane.parent())
+		return nil
+	case tcell.KeyF5:
+		v.transferSelected(pane, false)
+		return nil
+	case tcell.KeyF6:
+		v.transferSelected(pane, true)
+		return nil
+	}
+
+	switch event.Rune() {
+	case 'q', 'Q':
+		v.app.modalManager.HideModal()
+		return nil
+	case 'c':
+		v.transferSelected(pane, false)
+		return nil
+	case 'm':
+		v.transferSelected(pane, true)
+		return nil
+	case 'r':
+		v.refreshPane(pane, pane.dir)
+		return nil
+	}
+	return event
+}
+
+func (v *transferView) otherPane(pane *transferPane) *transferPane {
+	if pane == v.local {
+		return v.remote
+	}
+	return v.local
+}
+
+// transferSelected queues copying or moving the selected file to the other
+// pane's directory, asking first when the destination already exists
+func (v *transferView) transferSelected(pane *transferPane, move bool) {
+	entry, ok := pane.selectedEntry()
+	if !ok || entry.Dir {
+		v.app.showTransientStatus("[yellow]Select a file to transfer; directories are not supported[white]")
+		return
+	}
+	target := v.otherPane(pane)
+	if target.dir == "" {
+		v.app.showTransientStatus("[yellow]The remote directory is still loading[white]")
+		return
+	}
+
+	job := transfer.Job{
+		Server:      v.server,
+		Direction:   transfer.Download,
+		Source:      pane.join(entry.Name),
+		Destination: target.join(entry.Name),
+		Size:        entry.Size,
+		Move:        move,
+	}
+	if !pane.remote {
+		job.Direction = transfer.Upload
+	}
+
+	existing, exists := target.find(entry.Name)
+	if !exists {
+		v.enqueue(job)
+		return
+	}
+	if existing.Dir {
+		v.app.showErrorModal(fmt.Sprintf("%s is a directory", job.Destination))
+		return
+	}
+
+	buttons := []string{"Overwrite", "Cancel"}
+	offset := transfer.ResumeOffset(existing.Size, entry.Size)
+	if offset > 0 {
+		buttons = []string{"Resume", "Overwrite", "Cancel"}
+	}
+	modal := tview.NewModal().
+		SetText(fmt.Sprintf("%s already exists (%s of %s).", job.Destination, formatTransferSize(existing.Size), formatTransferSize(entry.Size))).
+		AddButtons(buttons).
+		SetDoneFunc(func(_ int, label string) {
+			v.app.modalManager.HideModal()
+			switch label {
+			case "Resume":
+				job.Offset = offset
+				v.enqueue(job)
+			case "Overwrite":
+				v.enqueue(job)
+			}
+		})
+	v.app.modalManager.ShowModal(modal)
+}
+
+// enqueue adds a job to the queue; it starts once the transfer limits allow
+func (v *transferView) enqueue(job transfer.Job) {
+	queued := &queuedTransfer{job: job}
+	v.mu.Lock()
+	v.jobs = append(v.jobs, queued)
+	v.mu.Unlock()
+
+	v.renderQueue()
+	go v.run(queued)
+}
+
+// run performs one job as a cancellable task, then refreshes both panes
+func (v *transferView) run(queued *queuedTransfer) {
+	tk, ctx := v.app.tasks.start(taskSpec{Name: queued.job.Name(), Total: int(queued.job.Size), Cancellable: true})
+	tk.Update(int(queued.job.Offset), int(queued.job.Size), "waiting for a transfer slot")
+	lastPercent := -1
+	onStart := func() {
+		tk.Update(int(queued.job.Offset), int(queued.job.Size), "")
+		v.mu.Lock()
+		queued.state = transferRunning
+		queued.meter.sample(queued.job.Offset, time.Now())
+		v.mu.Unlock()
+		v.app.app.QueueUpdateDraw(v.renderQueue)
+	}
+	err := v.scheduler.Run(ctx, queued.job, onStart, func(done, total int64) {
+		tk.Progress(int(done), int(total))
+		v.mu.Lock()
+		queued.done = done
+		sampled := queued.meter.sample(done, time.Now())
+		v.mu.Unlock()
+		if percent := transferPercent(done, total); percent != lastPercent || sampled {
+			lastPercent = percent
+			v.app.app.QueueUpdateDraw(v.renderQueue)
+		}
+	})
+	tk.Finish()
+
+	v.mu.Lock()
+	queued.state, queued.err = transferDone, err
+	if err != nil {
+		queued.state = transferFailed
+	}
+	closed := v.closed
+	v.mu.Unlock()
+
+	if closed {
+		if err != nil && !errors.Is(err, context.Canceled) {
+			v.app.app.QueueUpdateDraw(func() {
+				v.app.showTransientStatus(fmt.Sprintf("[red]%s failed: %s[white]", queued.job.Name(), tview.Escape(err.Error())))
+			})
+		}
+		return
+	}
+	v.app.app.QueueUpdateDraw(func() {
+		v.renderQueue()
+		v.refreshPane(v.local, v.local.dir)
+		v.refreshPane(v.remote, v.remote.dir)
+	})
+}
+
+// renderQueue shows the most recent jobs with their state and progress
+func (v *transferView) renderQueue() {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if len(v.jobs) == 0 {
+		v.queueView.SetText("[gray]No transfers yet: select a file and press c to copy or m to move it to the other pane[white]")
+		return
+	}
+	var lines []string
+	for _, queued := range v.jobs {
+		lines = append(lines, describeQueuedTransfer(queued))
+	}
+	v.queueView.SetText(strings.Join(lines, "\n"))
+	v.queueView.ScrollToEnd()
+	v.queueView.SetTitle(queueTitle(v.jobs))
+}
+
+// queueTitle sums up the running transfers and their throughput
+func queueTitle(jobs []*queuedTransfer) string {
+	running, waiting := 0, 0
+	var rate float64
+	for _, queued := range jobs {
+		switch queued.state {
+		case transferRunning:
+			running++
+			rate += queued.meter.rate
+		case transferQueued:
+			waiting++
+		}
+	}
+	if running == 0 && waiting == 0 {
+		return " Queue "
+	}
+	return fmt.Sprintf(" Queue: %d running, %d waiting, %s/s ", running, waiting, formatTransferSize(int64(rate)))
+}
+
+// describeQueuedTransfer renders one line of the queue
+func describeQueuedTransfer(queued *queuedTransfer) string {
+	name := tview.Escape(queued.job.Name())
+	switch queued.state {
+	case transferRunning:
+		return fmt.Sprintf("[yellow]▶ %3d%%[white] %s (%s of %s, %s/s)", transferPercent(queued.done, queued.job.Size), name,
+			formatTransferSize(queued.done), formatTransferSize(queued.job.Size), formatTransferSize(int64(queued.meter.rate)))
+	case transferDone:
+		return fmt.Sprintf("[green]✓[white] %s", name)
+	case transferFailed:
+		if errors.Is(queued.err, context.Canceled) {
+			return fmt.Sprintf("[yellow]✗[white] %s: cancelled, copy it again to resume", name)
+		}
+		return fmt.Sprintf("[red]✗[white] %s: %s", name, tview.Escape(queued.err.Error()))
+	default:
+		if queued.job.Offset > 0 {
+			return fmt.Sprintf("[gray]… %s (resuming at %s)[white]", name, formatTransferSize(queued.job.Offset))
+		}
+		return fmt.Sprintf("[gray]… %s[white]", name)
+	}
+}
+
+// transferPercent returns how much of a transfer is done, in percent
+func transferPercent(done, total int64) int {
+	if total <= 0 {
+		return 100
+	}
+	return int(done * 100 / total)
+}
+
+// formatTransferSize formats a byte count, e.g. "512 B" or "1.5 MiB"
+func formatTransferSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	value, exponent := float64(size)/unit, 0
+	for value >= unit && exponent < 4 {
+		value /= unit
+		exponent++
+	}
+	return fmt.Sprintf("%.1f %ciB", value, "KMGTP"[exponent])
+}
@@ -0,0 +1,107 @@
+package tui
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rivo/tview"
+	"sshm/internal/config"
+	"sshm/internal/transfer"
+)
+
+func TestFormatTransferSize(t *testing.T) {
+	tests := map[int64]string{
+		0:               "0 B",
+		1023:            "1023 B",
+		1536:            "1.5 KiB",
+		5 * 1024 * 1024: "5.0 MiB",
+		3 << 30:         "3.0 GiB",
+	}
+	for size, expected := range tests {
+		if got := formatTransferSize(size); got != expected {
+			t.Errorf("formatTransferSize(%d) = %q, expected %q", size, got, expected)
+		}
+	}
+}
+
+func TestDescribeQueuedTransfer(t *testing.T) {
+	job := transfer.Job{Server: config.Server{Name: "web1"}, Direction: transfer.Upload, Source: "/tmp/app.tar.gz", Size: 200}
+
+	running := describeQueuedTransfer(&queuedTransfer{job: job, state: transferRunning, done: 50, meter: throughputMeter{rate: 2048}})
+	if !strings.Contains(running, " 25%") || !strings.Contains(running, "upload app.tar.gz to web1") || !strings.Contains(running, "2.0 KiB/s") {
+		t.Errorf("Unexpected running line: %q", running)
+	}
+
+	cancelled := describeQueuedTransfer(&queuedTransfer{job: job, state: transferFailed, err: context.Canceled})
+	if !strings.Contains(cancelled, "resume") {
+		t.Errorf("Expected a cancelled transfer to mention resuming, got %q", cancelled)
+	}
+
+	job.Offset = 100
+	queued := describeQueuedTransfer(&queuedTransfer{job: job})
+	if !strings.Contains(queued, "resuming at 100 B") {
+		t.Errorf("Expected a queued resume to show its offset, got %q", queued)
+	}
+}
+
+func TestThroughputMeter(t *testing.T) {
+	var meter throughputMeter
+	start := time.Now()
+	if meter.sample(0, start) {
+		t.Error("Expected the first sample only to start measuring")
+	}
+	if meter.sample(4096, start.Add(500*time.Millisecond)) {
+		t.Error("Expected no rate before a second has passed")
+	}
+	if !meter.sample(8192, start.Add(2*time.Second)) || meter.rate != 4096 {
+		t.Errorf("Expected 4096 B/s, got %v", meter.rate)
+	}
+}
+
+func TestQueueTitle(t *testing.T) {
+	jobs := []*queuedTransfer{
+		{state: transferRunning, meter: throughputMeter{rate: 1024}},
+		{state: transferRunning, meter: throughputMeter{rate: 2048}},
+		{state: transferQueued},
+		{state: transferDone},
+	}
+	if title := queueTitle(jobs); title != " Queue: 2 running, 1 waiting, 3.0 KiB/s " {
+		t.Errorf("Unexpected queue title %q", title)
+	}
+	if title := queueTitle(jobs[3:]); title != " Queue " {
+		t.Errorf("Expected a plain title without active transfers, got %q", title)
+	}
+}
+
+func TestTransferViewLocalPane(t *testing.T) {
+	dir := t.TempDir()
+	os.Mkdir(filepath.Join(dir, "sub"), 0755)
+	os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("hello"), 0644)
+
+	app := &TUIApp{app: tview.NewApplication()}
+	v := newTransferView(app, config.Server{Name: "web1"}, dir)
+	v.refreshPane(v.local, dir)
+
+	// Row 1 is "..", then directories before files
+	v.local.table.Select(2, 0)
+	if entry, ok := v.local.selectedEntry(); !ok || entry.Name != "sub" || !entry.Dir {
+		t.Fatalf("Expected sub/ to be listed first, got %+v", entry)
+	}
+	v.local.table.Select(3, 0)
+	if entry, ok := v.local.selectedEntry(); !ok || entry.Name != "notes.txt" || entry.Size != 5 {
+		t.Fatalf("Expected notes.txt, got %+v", entry)
+	}
+
+	v.refreshPane(v.local, v.local.join("sub"))
+	if v.local.dir != filepath.Join(dir, "sub") {
+		t.Errorf("Expected to enter sub, got %s", v.local.dir)
+	}
+	v.refreshPane(v.local, v.local.parent())
+	if v.local.dir != dir {
+		t.Errorf("Expected to return to %s, got %s", dir, v.local.dir)
+	}
+}
@@ -0,0 +1,239 @@
+package tui
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"sshm/internal/config"
+)
+
+// Server list display modes, cycled with 'h'
+const (
+	viewModeTable         = "table"
+	viewModeTreeByProfile = "profile"
+	viewModeTreeByTag     = "tag"
+)
+
+// Group names for servers without a profile or tag
+const (
+	unassignedGroup = "Unassigned"
+	untaggedGroup   = "Untagged"
+)
+
+// treeGroupRef marks tree nodes that represent a group rather than a server
+type treeGroupRef struct {
+	name string
+}
+
+// setupServerTree creates the tree view shown in place of the server table in tree mode
+func (t *TUIApp) setupServerTree() {
+	t.serverTree = tview.NewTreeView().
+		SetGraphicsColor(tcell.ColorGray).
+		SetTopLevel(1) // Hide the root node
+	t.serverTree.SetBorder(true).SetTitle(" Servers ")
+	t.collapsedGroups = make(map[string]bool)
+
+	// Keep the table selection in sync so every server action works in tree mode
+	t.serverTree.SetChangedFunc(func(node *tview.TreeNode) {
+		if serverName, ok := node.GetReference().(string); ok {
+			t.selectServerRow(serverName)
+		}
+	})
+}
+
+// cycleServerView switches between the flat table and the tree grouped by profile or tag
+func (t *TUIApp) cycleServerView() {
+	switch t.viewMode {
+	case viewModeTreeByProfile:
+		t.setServerView(viewModeTreeByTag)
+	case viewModeTreeByTag:
+		t.setServerView(viewModeTable)
+	default:
+		t.setServerView(viewModeTreeByProfile)
+	}
+}
+
+// setServerView shows the servers as a table or as a tree grouped by profile or tag
+func (t *TUIApp) setServerView(mode string) {
+	t.viewMode = mode
+	if t.viewMode == viewModeTable {
+		t.serverPages.SwitchToPage(viewModeTable)
+		t.app.SetFocus(t.serverList)
+	} else {
+		t.serverPages.SwitchToPage("tree")
+		t.app.SetFocus(t.serverTree)
+	}
+	t.refreshServerList()
+	t.updatePanelHighlight()
+}
+
+// isTreeMode reports whether the servers are shown as a tree
+func (t *TUIApp) isTreeMode() bool {
+	return t.viewMode == viewModeTreeByProfile || t.viewMode == viewModeTreeByTag
+}
+
+// refreshServerTree rebuilds the tree from the filtered server list, keeping
+// collapsed groups collapsed and the selected server selected
+func (t *TUIApp) refreshServerTree(servers []config.Server) {
+	if t.serverTree == nil {
+		return
+	}
+
+	selectedServer := t.getSelectedServerName()
+	root := tview.NewTreeNode("servers")
+	var selectedNode *tview.TreeNode
+
+	groupBy := "Profile"
+	if t.viewMode == viewModeTreeByTag {
+		groupBy = "Tag"
+	}
+	t.serverTree.SetTitle(fmt.Sprintf(" Servers by %s ", groupBy))
+
+	for _, group := range t.groupServers(servers) {
+		groupNode := tview.NewTreeNode(fmt.Sprintf("%s (%d)", group.name, len(group.servers))).
+			SetReference(treeGroupRef{name: group.name}).
+			SetColor(tcell.ColorAqua).
+			SetSelectable(true).
+			SetExpanded(!t.collapsedGroups[group.name])
+
+		for _, server := range group.servers {
+			status, statusColor := t.getCachedConnectionStatus(server.Name)
+			serverNode := tview.NewTreeNode(fmt.Sprintf("%s  [%s]", server.Name, status)).
+				SetReference(server.Name).
+				SetColor(statusColor)
+			groupNode.AddChild(serverNode)
+			if server.Name == selectedServer && selectedNode == nil && groupNode.IsExpanded() {
+				selectedNode = serverNode
+			}
+		}
+		root.AddChild(groupNode)
+	}
+
+	t.serverTree.SetRoot(root)
+	if selectedNode != nil {
+		t.serverTree.SetCurrentNode(selectedNode)
+	} else if children := root.GetChildren(); len(children) > 0 {
+		t.serverTree.SetCurrentNode(children[0])
+	}
+}
+
+// serverGroup is a named group of servers in the tree view
+type serverGroup struct {
+	name    string
+	servers []config.Server
+}
+
+// groupServers groups servers by profile or tag depending on the view mode.
+// Servers in several groups appear in each of them.
+func (t *TUIApp) groupServers(servers []config.Server) []serverGroup {
+	var groups []serverGroup
+	index := make(map[string]int)
+	add := func(name string, server config.Server) {
+		i, ok := index[name]
+		if !ok {
+			i = len(groups)
+			index[name] = i
+			groups = append(groups, serverGroup{name: name})
+		}
+		groups[i].servers = append(groups[i].servers, server)
+	}
+
+	var leftovers []config.Server
+	for _, server := range servers {
+		var names []string
+		if t.viewMode == viewModeTreeByTag {
+			names = server.Tags
+		} else {
+			names = t.getServerProfiles(server.Name)
+		}
+		if len(names) == 0 {
+			leftovers = append(leftovers, server)
+			continue
+		}
+		for _, name := range names {
+			add(name, server)
+		}
+	}
+
+	leftoverName := unassignedGroup
+	if t.viewMode == viewModeTreeByTag {
+		leftoverName = untaggedGroup
+	}
+	for _, server := range leftovers {
+		add(leftoverName, server)
+	}
+	return groups
+}
+
+// handleTreeKey handles navigation keys in tree mode; it reports whether the key was consumed
+func (t *TUIApp) handleTreeKey(event *tcell.EventKey) bool {
+	switch event.Key() {
+	case tcell.KeyUp:
+		t.serverTree.Move(-1)
+		return true
+	case tcell.KeyDown:
+		t.serverTree.Move(1)
+		return true
+	case tcell.KeyLeft:
+		t.setCurrentGroupExpanded(false)
+		return true
+	case tcell.KeyRight:
+		t.setCurrentGroupExpanded(true)
+		return true
+	case tcell.KeyEnter:
+		node := t.serverTree.GetCurrentNode()
+		if node == nil {
+			return true
+		}
+		if _, isGroup := node.GetReference().(treeGroupRef); isGroup {
+			t.setCurrentGroupExpanded(!node.IsExpanded())
+			return true
+		}
+		t.connectToSelectedServer()
+		return true
+	}
+
+	switch event.Rune() {
+	case 'j', 'J':
+		t.serverTree.Move(1)
+		return true
+	case 'k', 'K':
+		t.serverTree.Move(-1)
+		return true
+	}
+	return false
+}
+
+// setCurrentGroupExpanded expands or collapses the group of the current node
+func (t *TUIApp) setCurrentGroupExpanded(expanded bool) {
+	node := t.serverTree.GetCurrentNode()
+	if node == nil {
+		return
+	}
+
+	if _, isGroup := node.GetReference().(treeGroupRef); !isGroup {
+		// Collapsing from a server collapses its group and moves the selection onto it
+		path := t.serverTree.GetPath(node)
+		if expanded || len(path) < 2 {
+			return
+		}
+		node = path[len(path)-2]
+		t.serverTree.SetCurrentNode(node)
+	}
+
+	ref := node.GetReference().(treeGroupRef)
+	node.SetExpanded(expanded)
+	t.collapsedGroups[ref.name] = !expanded
+}
+
+// selectServerRow selects the table row of the named server
+func (t *TUIApp) selectServerRow(serverName string) {
+	for row := 1; row < t.serverList.GetRowCount(); row++ {
+		if cell := t.serverList.GetCell(row, 0); cell != nil && cell.Text == serverName {
+			t.serverList.Select(row, 0)
+			t.selectedRow = row
+			return
+		}
+	}
+}
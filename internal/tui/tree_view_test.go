@@ -0,0 +1,43 @@
+package tui
+
+import (
+	"testing"
+
+	"sshm/internal/config"
+)
+
+func TestGroupServers(t *testing.T) {
+	servers := []config.Server{
+		{Name: "web1", Tags: []string{"web"}},
+		{Name: "db1", Tags: []string{"database", "web"}},
+		{Name: "loose"},
+	}
+	app := &TUIApp{config: &config.Config{
+		Servers: servers,
+		Profiles: []config.Profile{
+			{Name: "prod", Servers: []string{"web1", "db1"}},
+		},
+	}}
+
+	app.viewMode = viewModeTreeByProfile
+	groups := app.groupServers(servers)
+	if len(groups) != 2 || groups[0].name != "prod" || groups[1].name != unassignedGroup {
+		t.Fatalf("Unexpected profile groups: %+v", groups)
+	}
+	if len(groups[0].servers) != 2 || groups[1].servers[0].Name != "loose" {
+		t.Errorf("Unexpected profile group members: %+v", groups)
+	}
+
+	app.viewMode = viewModeTreeByTag
+	groups = app.groupServers(servers)
+	names := make([]string, len(groups))
+	for i, group := range groups {
+		names[i] = group.name
+	}
+	if len(groups) != 3 || names[0] != "web" || names[1] != "database" || names[2] != untaggedGroup {
+		t.Fatalf("Unexpected tag groups: %v", names)
+	}
+	if len(groups[0].servers) != 2 {
+		t.Errorf("Expected servers with several tags to appear in each group, got %d in 'web'", len(groups[0].servers))
+	}
+}
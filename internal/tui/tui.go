@@ -2,12 +2,12 @@ package tui
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"os"
-	"path/filepath"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gdamore/tcell/v2"
@@ -15,8 +15,20 @@ import (
 	"golang.org/x/crypto/ssh"
 	"sshm/internal/config"
 	"sshm/internal/connection"
+	"sshm/internal/events"
+	"sshm/internal/history"
+	"sshm/internal/monitor"
+	"sshm/internal/retry"
+	"sshm/internal/secrets"
+	"sshm/internal/service"
+	"sshm/internal/statuscache"
 	sshmssh "sshm/internal/ssh"
 	"sshm/internal/tmux"
+	"sshm/internal/transfer"
+	"sshm/internal/tui/views"
+	"sshm/internal/tunnel"
+	"sshm/internal/warmpool"
+	"sshm/internal/webhook"
 )
 
 // SessionInfo represents tmux session information
@@ -24,16 +36,25 @@ type SessionInfo struct {
 	Name         string
 	Status       string
 	Windows      int
+	Clients      int // Attached clients, teammates who joined included
 	LastActivity string
+	LostWindows  []string // Windows whose SSH connection dropped; Status is then sessionConnectionLost
 }
 
+// sessionConnectionLost is the status of a session with a dropped SSH connection
+const sessionConnectionLost = "connection lost"
+
 // TUIApp represents the main TUI application
 type TUIApp struct {
 	app               *tview.Application
 	layout            *tview.Flex
-	serverList        *tview.Table
-	profileNavigator  *tview.TextView
-	sessionPanel      *tview.Table
+	serverList        *views.ServerList
+	serverTree        *tview.TreeView
+	serverPages       *tview.Pages // Holds the server table and tree so they can be swapped
+	mainLayout        *tview.Flex  // Server list on the left, profiles and sessions on the right
+	rightPane         *tview.Flex
+	profileNavigator  *views.ProfileNavigator
+	sessionPanel      *views.SessionsPanel
 	statusBar         *tview.TextView
 	config            *config.Config
 	tmuxManager       *tmux.Manager
@@ -41,6 +62,14 @@ type TUIApp struct {
 	modalManager      *ModalManager
 	sessionHandler    *SessionReturnHandler
 	helpSystem        *HelpSystem
+	idleLock          *IdleLock
+	root              tview.Primitive   // Last root shown, or to show once unlocked
+	lockField         *tview.InputField // PIN field of the lock screen while locked
+	lockedRoot        tview.Primitive   // Root when the TUI locked
+	lockedFocus       tview.Primitive   // Focus when the TUI locked
+	tunnelManager     *tunnel.Manager
+	tunnelsPanel      *tview.Flex
+	tunnelsTable      *tview.Table
 	
 	// Application state
 	running              bool
@@ -55,16 +84,52 @@ type TUIApp struct {
 	sessions             []SessionInfo // Current session list
 	selectedSession      int      // Currently selected session (0 = header, 1+ = data rows)
 	focusedPanel         string   // Currently focused panel: "servers" or "sessions"
+	viewMode             string   // Server list display: "table", or a tree grouped by "profile" or "tag"
+	collapsedGroups      map[string]bool // Tree groups the user collapsed, by group name
+	compareMark          string   // Server marked with Space, compared with the next one marked
+	events               *events.Emitter // Machine-readable event stream (--events); nil when not requested
+	lastSelectedEvent    string   // Server of the last server_selected event, to report changes only
+	watchPanel           *tview.TextView        // Watch list overlay, hidden when nothing is watched
+	watches              map[string]*watchState // Fast-check state of watched servers, by name
+	watchMu              sync.Mutex             // Protects watches
+	updateNotice         string                 // Newer release shown in the status bar, empty when up to date
+	serverPanelWidth     int                    // Share of the width used by the server list, in percent
+	lastSession          string                 // Session the user just detached from, highlighted in the sessions panel
+	compactLayout        bool                   // Profiles and sessions pane hidden on narrow terminals
+	terminalTooSmall     bool                   // Interface replaced by the "terminal too small" overlay
 	
 	// Connection status tracking
 	connectionStatus     map[string]string // Cache for connection status by server name
-	statusMutex          sync.RWMutex      // Protects connectionStatus map
+	statusAttempts       map[string]int    // Attempts used by failures that were retried, by server name
+	statusLatency        map[string]time.Duration // Round-trip time of the last successful check, by server name
+	statusStats          map[string]string        // Rendered quick stats of online servers in opted-in profiles
+	statusHealth         map[string]serverHealth  // Last online time, auth method and SSH version for the health view
+	healthViewClose      func()                   // Closes the health view while it is open
+	statusMutex          sync.RWMutex      // Protects the connection status maps
+	
+	tunnelMonitorStop    chan struct{} // Closed to stop tunnel health checks
+	socksIndicator       string        // SOCKS proxies last shown in the status bar
+	tasks                taskManager   // Running background tasks, shown in the tasks overlay
+	sessionNotifier      *sessionNotifier // Receives session changes from tmux hooks, nil when polling only
+	notifyExecutable     string           // sshm binary the tmux hooks run; no hooks are installed without it
+	hostKeyWarnings      []string         // Servers whose changed host keys weren't trusted yet
+	offline              atomic.Bool      // Status and update checks suppressed, toggled with Ctrl+N
+	statusSchedule       *monitor.Schedule    // Randomized due time of each server's next status check
+	statusLimiter        *monitor.RateLimiter // Caps status checks per minute, nil when unlimited
+	configConflict       bool                 // Asking how to reconcile external config changes
+	webhooks             *webhook.Tracker     // Calls the webhooks of servers going online or offline
+	tailPaths            map[string][]string  // Recently tailed remote paths per server, most recent first
+	transfers            *transfer.Scheduler  // Runs file transfers within the configured limits, created on first use
+	warmPool             *warmpool.Pool       // Connections kept open to warm and watched servers
+	outputWatches        map[string]*outputWatch // Sessions whose output is watched for a pattern, by name
+	outputWatchMu        sync.Mutex              // Protects outputWatches
+	lastOutputPattern    string                  // Pattern last watched for, offered again
 }
 
 // NewTUIApp creates a new TUI application instance
 func NewTUIApp() (*TUIApp, error) {
-	// Load configuration
-	cfg, err := config.Load()
+	// Load configuration, unlocking it first when it is encrypted
+	cfg, err := loadConfig()
 	if err != nil {
 		return nil, fmt.Errorf("failed to load configuration: %w", err)
 	}
@@ -83,7 +148,27 @@ func NewTUIApp() (*TUIApp, error) {
 		connectionManager: connectionManager,
 		focusedPanel:      "servers", // Default focus on servers panel
 		connectionStatus:  make(map[string]string),
+		statusAttempts:    make(map[string]int),
+		statusLatency:     make(map[string]time.Duration),
+		statusStats:       make(map[string]string),
+		statusHealth:      make(map[string]serverHealth),
+		idleLock:          NewIdleLock(cfg.UI.IdleLockTimeout()),
+		tunnelManager:     tunnel.NewManager(),
+		statusSchedule:    monitor.NewSchedule(),
+		statusLimiter:     monitor.NewRateLimiter(cfg.StatusChecks.MaxPerMinute),
+		webhooks:          webhook.NewTracker(),
+		events:            eventEmitter,
+		warmPool:          warmpool.New(),
+	}
+	tuiApp.offline.Store(startOffline)
+	tuiApp.webhooks.OnError = func(server string, hook config.Webhook, err error) {
+		if tuiApp.running {
+			tuiApp.app.QueueUpdateDraw(func() {
+				tuiApp.showTransientStatus(fmt.Sprintf("[red]Webhook of %s failed: %s[white]", server, tview.Escape(err.Error())))
+			})
+		}
 	}
+	connectionManager.SetRetryPolicy(cfg.Retry)
 
 	// Setup the UI layout
 	if err := tuiApp.setupLayout(); err != nil {
@@ -92,6 +177,7 @@ func NewTUIApp() (*TUIApp, error) {
 
 	// Initialize modal manager after layout is setup
 	tuiApp.modalManager = NewModalManager(tuiApp.app, tuiApp.layout)
+	tuiApp.modalManager.setRoot = tuiApp.setRoot
 
 	// Initialize session handler
 	tuiApp.sessionHandler = NewSessionReturnHandler(tuiApp, tuiApp.tmuxManager)
@@ -102,6 +188,9 @@ func NewTUIApp() (*TUIApp, error) {
 	// Setup global key bindings
 	tuiApp.setupKeyBindings()
 
+	// Reflow the layout for the terminal size on every draw
+	tuiApp.setupResizeHandling()
+
 	return tuiApp, nil
 }
 
@@ -114,25 +203,21 @@ func (t *TUIApp) setupLayout() error {
 	t.statusBar = tview.NewTextView().
 		SetDynamicColors(true)
 
-	// Create server list table
-	t.serverList = tview.NewTable()
-	t.serverList.SetBorder(true).SetTitle(" Servers ")
-	t.serverList.SetBorders(false)
-	t.serverList.SetSelectable(true, false)
-	t.serverList.SetSelectedStyle(tcell.StyleDefault.Background(tcell.ColorDarkBlue).Foreground(tcell.ColorWhite))
-
-	// Setup server list headers
-	t.serverList.SetCell(0, 0, tview.NewTableCell("Name").SetTextColor(tcell.ColorYellow).SetSelectable(false).SetAlign(tview.AlignLeft))
-	t.serverList.SetCell(0, 1, tview.NewTableCell("Host").SetTextColor(tcell.ColorYellow).SetSelectable(false).SetAlign(tview.AlignLeft))
-	t.serverList.SetCell(0, 2, tview.NewTableCell("Port").SetTextColor(tcell.ColorYellow).SetSelectable(false).SetAlign(tview.AlignCenter))
-	t.serverList.SetCell(0, 3, tview.NewTableCell("User").SetTextColor(tcell.ColorYellow).SetSelectable(false).SetAlign(tview.AlignLeft))
-	t.serverList.SetCell(0, 4, tview.NewTableCell("Auth").SetTextColor(tcell.ColorYellow).SetSelectable(false).SetAlign(tview.AlignCenter))
-	t.serverList.SetCell(0, 5, tview.NewTableCell("Status").SetTextColor(tcell.ColorYellow).SetSelectable(false).SetAlign(tview.AlignCenter))
-	t.serverList.SetCell(0, 6, tview.NewTableCell("Profile").SetTextColor(tcell.ColorYellow).SetSelectable(false).SetAlign(tview.AlignLeft))
-
-	// Create profile navigator
-	t.profileNavigator = tview.NewTextView()
-	t.profileNavigator.SetDynamicColors(true).SetBorder(true).SetTitle(" Profiles ")
+	// Create server list table, fed by the status checks
+	t.serverList = views.NewServerList(serverProvider{t})
+	t.serverList.SetSelectionChangedFunc(func(row, column int) {
+		t.emitServerSelected(row)
+	})
+
+	// Create the tree view used as an alternative to the table
+	t.setupServerTree()
+	t.viewMode = viewModeTable
+	t.serverPages = tview.NewPages().
+		AddPage(viewModeTable, t.serverList, true, true).
+		AddPage("tree", t.serverTree, true, false)
+
+	// Create profile navigator; profiles with failing servers carry a badge counting them
+	t.profileNavigator = views.NewProfileNavigator().SetBadgeFunc(t.profileStatusBadge)
 	
 	// Initialize profile tabs
 	t.initializeProfileTabs()
@@ -141,22 +226,25 @@ func (t *TUIApp) setupLayout() error {
 	t.setupSessionPanel()
 	
 	// Create right pane with profile navigator and session manager
-	rightPane := tview.NewFlex().SetDirection(tview.FlexRow).
+	t.rightPane = tview.NewFlex().SetDirection(tview.FlexRow).
 		AddItem(t.profileNavigator, 3, 0, false). // Fixed height for profile tabs
 		AddItem(t.sessionPanel, 0, 1, false)     // Session manager takes remaining space
 
 	// Create main horizontal layout: left pane (60%) server list, right pane (40%) profiles/sessions
-	mainLayout := tview.NewFlex().SetDirection(tview.FlexColumn).
-		AddItem(t.serverList, 0, 6, true).  // 60% width, focusable
-		AddItem(rightPane, 0, 4, false)    // 40% width, not focusable initially
+	t.serverPanelWidth = defaultServerPanelWidth
+	t.mainLayout = tview.NewFlex().SetDirection(tview.FlexColumn).
+		AddItem(t.serverPages, 0, t.serverPanelWidth, true).    // 60% width, focusable
+		AddItem(t.rightPane, 0, 100-t.serverPanelWidth, false) // 40% width, not focusable initially
 
-	// Create overall layout with status bar at bottom
+	// Create overall layout with the watch list and status bar at bottom
+	t.setupWatchPanel()
 	t.layout = tview.NewFlex().SetDirection(tview.FlexRow).
-		AddItem(mainLayout, 0, 1, true).
+		AddItem(t.mainLayout, 0, 1, true).
+		AddItem(t.watchPanel, 0, 0, false). // Sized by refreshWatchPanel
 		AddItem(t.statusBar, 1, 0, false)
 
 	// Set the main layout as root
-	t.app.SetRoot(t.layout, true)
+	t.setRoot(t.layout)
 
 	// Load server data and update profile display
 	t.refreshServerList()
@@ -172,27 +260,12 @@ func (t *TUIApp) setupLayout() error {
 
 // setupSessionPanel initializes the session manager panel
 func (t *TUIApp) setupSessionPanel() {
-	// Only create session panel if tmux is available
+	// Without tmux the panel only says so in its title
+	t.sessionPanel = views.NewSessionsPanel(t.tmuxManager.IsAvailable())
 	if !t.tmuxManager.IsAvailable() {
-		// Create a simple text view indicating tmux is not available
-		t.sessionPanel = tview.NewTable()
-		t.sessionPanel.SetBorder(true).SetTitle(" Sessions (tmux not available) ")
 		return
 	}
 
-	// Create session table
-	t.sessionPanel = tview.NewTable()
-	t.sessionPanel.SetBorder(true).SetTitle(" Sessions ")
-	t.sessionPanel.SetBorders(false)
-	t.sessionPanel.SetSelectable(true, false)
-	t.sessionPanel.SetSelectedStyle(tcell.StyleDefault.Background(tcell.ColorDarkBlue).Foreground(tcell.ColorWhite))
-
-	// Setup session table headers
-	t.sessionPanel.SetCell(0, 0, tview.NewTableCell("Session").SetTextColor(tcell.ColorYellow).SetSelectable(false).SetAlign(tview.AlignLeft))
-	t.sessionPanel.SetCell(0, 1, tview.NewTableCell("Status").SetTextColor(tcell.ColorYellow).SetSelectable(false).SetAlign(tview.AlignCenter))
-	t.sessionPanel.SetCell(0, 2, tview.NewTableCell("Windows").SetTextColor(tcell.ColorYellow).SetSelectable(false).SetAlign(tview.AlignCenter))
-	t.sessionPanel.SetCell(0, 3, tview.NewTableCell("Last Activity").SetTextColor(tcell.ColorYellow).SetSelectable(false).SetAlign(tview.AlignLeft))
-
 	// Set initial selection to first data row if it exists
 	t.selectedSession = 1
 	t.sessionPanel.Select(1, 0)
@@ -216,6 +289,11 @@ func (t *TUIApp) initializeProfileTabs() {
 		t.profileTabs = append(t.profileTabs, profile.Name)
 	}
 	
+	// Servers outside every profile get their own tab
+	if t.hasUnassignedTab() {
+		t.profileTabs = append(t.profileTabs, unassignedTab)
+	}
+	
 	// Try to preserve previous selection, or default to 0 (All tab)
 	newSelectedIndex := 0
 	if previouslySelectedProfile != "" {
@@ -233,43 +311,57 @@ func (t *TUIApp) initializeProfileTabs() {
 
 // updateProfileDisplay updates the profile navigator display
 func (t *TUIApp) updateProfileDisplay() {
-	tabText := t.renderProfileTabs()
-	t.profileNavigator.SetText(tabText)
-	
-	// Update the title to show the currently selected profile
-	if t.selectedProfileIndex >= 0 && t.selectedProfileIndex < len(t.profileTabs) {
-		selectedProfile := t.profileTabs[t.selectedProfileIndex]
-		title := fmt.Sprintf(" Profiles › [aqua]%s[white] ", selectedProfile)
-		t.profileNavigator.SetTitle(title)
-	} else {
-		t.profileNavigator.SetTitle(" Profiles ")
-	}
+	t.profileNavigator.Render(t.profileTabs, t.selectedProfileIndex)
 }
 
 // renderProfileTabs generates the tab display text with enhanced highlighting
 func (t *TUIApp) renderProfileTabs() string {
-	if len(t.profileTabs) == 0 {
-		return "[white]No profiles configured"
-	}
-	
-	var tabStrings []string
-	for i, tab := range t.profileTabs {
-		if i == t.selectedProfileIndex {
-			// Enhanced highlighting for selected tab with background and bold styling
-			tabStrings = append(tabStrings, fmt.Sprintf("[black:aqua:b] %s [white::-]", tab))
-		} else {
-			// Subtle styling for non-selected tabs
-			tabStrings = append(tabStrings, fmt.Sprintf("[lightgray]%s[white]", tab))
-		}
-	}
-	
-	// Join tabs with enhanced separators
-	return strings.Join(tabStrings, " [darkgray]│[white] ")
+	return views.RenderTabs(t.profileTabs, t.selectedProfileIndex, t.profileStatusBadge)
 }
 
 // setupKeyBindings configures global key bindings
 func (t *TUIApp) setupKeyBindings() {
+	t.app.SetMouseCapture(func(event *tcell.EventMouse, action tview.MouseAction) (*tcell.EventMouse, tview.MouseAction) {
+		if t.idleLock != nil {
+			if t.idleLock.IsLocked() {
+				return nil, action // Ignore the mouse while locked
+			}
+			t.idleLock.RecordActivity()
+		}
+		// Right-clicking a server or session opens its context menu
+		if action == tview.MouseRightClick && (t.modalManager == nil || !t.modalManager.IsModalActive()) && !t.terminalTooSmall {
+			if t.handleContextClick(event) {
+				return nil, action
+			}
+		}
+		return event, action
+	})
+
 	t.app.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		// While locked, only the lock screen receives input
+		if t.idleLock != nil {
+			if t.idleLock.IsLocked() {
+				if event.Key() == tcell.KeyCtrlC {
+					t.Stop()
+					return nil
+				}
+				// Keep input on the PIN field even if a background update moved the focus
+				if t.lockField != nil && t.app.GetFocus() != t.lockField {
+					t.app.SetFocus(t.lockField)
+				}
+				return event
+			}
+			t.idleLock.RecordActivity()
+		}
+		
+		// Nothing is visible behind the "terminal too small" overlay, so only allow quitting
+		if t.terminalTooSmall {
+			if event.Key() == tcell.KeyCtrlC || event.Rune() == 'q' || event.Rune() == 'Q' {
+				t.Stop()
+			}
+			return nil
+		}
+		
 		// Check if modal is active first - let modals handle their own keys
 		if t.modalManager != nil && t.modalManager.IsModalActive() {
 			// If a modal is active, let it handle the key first
@@ -283,6 +375,11 @@ func (t *TUIApp) setupKeyBindings() {
 			return event // Let modal handle other keys
 		}
 		
+		// In tree mode the tree handles navigation and Enter
+		if t.isTreeMode() && t.focusedPanel == "servers" && t.handleTreeKey(event) {
+			return nil
+		}
+		
 		// Handle special keys first (only when no modal is active)
 		switch event.Key() {
 		case tcell.KeyCtrlC:
@@ -324,6 +421,56 @@ func (t *TUIApp) setupKeyBindings() {
 				t.switchFocus()
 			}
 			return nil
+		case tcell.KeyCtrlW:
+			t.toggleWatchSelectedServer()
+			return nil
+		case tcell.KeyCtrlF:
+			t.showForgottenSessions()
+			return nil
+		case tcell.KeyCtrlL:
+			t.showTailPrompt()
+			return nil
+		case tcell.KeyCtrlX:
+			t.showTransferView()
+			return nil
+		case tcell.KeyCtrlG:
+			t.showDiagnosticsMenu()
+			return nil
+		case tcell.KeyCtrlK:
+			t.showActionsMenu()
+			return nil
+		case tcell.KeyCtrlP:
+			t.showPowerActionForm()
+			return nil
+		case tcell.KeyCtrlU:
+			t.auditUpdates()
+			return nil
+		case tcell.KeyCtrlD:
+			t.showInventoryDiffForm()
+			return nil
+		case tcell.KeyCtrlE:
+			t.editSelectedServerInEditor()
+			return nil
+		case tcell.KeyCtrlR:
+			t.repairSelectedSession()
+			return nil
+		case tcell.KeyCtrlO:
+			if !t.config.Locked {
+				t.editConfigInEditor()
+			}
+			return nil
+		case tcell.KeyCtrlN:
+			t.toggleOffline()
+			return nil
+		case tcell.KeyCtrlT:
+			t.showInventoryStats()
+			return nil
+		case tcell.KeyCtrlS:
+			t.toggleSelectedServerSOCKS()
+			return nil
+		case tcell.KeyCtrlY:
+			t.inviteToSelectedSession()
+			return nil
 		case tcell.KeyBacktab: // Shift+Tab
 			if t.focusedPanel == "servers" {
 				t.switchToPreviousProfile()
@@ -336,7 +483,7 @@ func (t *TUIApp) setupKeyBindings() {
 		// Handle character keys
 		switch event.Rune() {
 		case 'q', 'Q':
-			t.Stop()
+			t.requestQuit()
 			return nil
 		case '?':
 			t.showHelp()
@@ -407,6 +554,72 @@ func (t *TUIApp) setupKeyBindings() {
 		case 'v', 'V':
 			t.showHistoryDashboard()
 			return nil
+		case 'l', 'L':
+			t.showServerDetails()
+			return nil
+		case 'f', 'F':
+			t.startSelectedServerTunnels()
+			return nil
+		case 't', 'T':
+			t.showTunnels()
+			return nil
+		case 'g', 'G':
+			t.openSelectedServerService()
+			return nil
+		case 'n', 'N':
+			t.launchSelectedDatabaseClient()
+			return nil
+		case 'h', 'H':
+			t.cycleServerView()
+			return nil
+		case '<':
+			t.resizeServerPanel(-serverPanelWidthStep)
+			return nil
+		case '>':
+			t.resizeServerPanel(serverPanelWidthStep)
+			return nil
+		case ' ':
+			t.markServerForCompare()
+			return nil
+		case '#':
+			t.showBatchEditForm()
+			return nil
+		case '$':
+			t.showBatchRenameForm()
+			return nil
+		case ':':
+			t.showRunCommandForm()
+			return nil
+		case '&':
+			t.showTasksOverlay()
+			return nil
+		case '%':
+			t.showLatencyMap()
+			return nil
+		case '.':
+			t.showContextMenu()
+			return nil
+		case '=':
+			t.syncSSHConfig("", false)
+			return nil
+		case '+':
+			t.toggleHealthView()
+			return nil
+		case '@':
+			t.auditAuthorizedKeys()
+			return nil
+		case '~':
+			t.toggleOutputWatchSelectedSession()
+			return nil
+		case ';':
+			t.showRunbooks()
+			return nil
+		case '*':
+			t.showRecordings()
+			return nil
+		case '!':
+			t.showCredentialReminders()
+			return nil
 		}
 		
 		return event
@@ -462,11 +675,17 @@ func (t *TUIApp) switchFocus() {
 func (t *TUIApp) updatePanelHighlight() {
 	if t.focusedPanel == "servers" {
 		t.serverList.SetBorderColor(tcell.ColorYellow)
+		if t.serverTree != nil {
+			t.serverTree.SetBorderColor(tcell.ColorYellow)
+		}
 		if t.sessionPanel != nil {
 			t.sessionPanel.SetBorderColor(tcell.ColorWhite)
 		}
 	} else {
 		t.serverList.SetBorderColor(tcell.ColorWhite)
+		if t.serverTree != nil {
+			t.serverTree.SetBorderColor(tcell.ColorWhite)
+		}
 		if t.sessionPanel != nil {
 			t.sessionPanel.SetBorderColor(tcell.ColorYellow)
 		}
@@ -530,19 +749,49 @@ func (t *TUIApp) connectToSelectedServer() {
 		return
 	}
 	
+	// Servers flagged for compliance must have their login banner accepted first
+	if server.RequireBannerAck {
+		t.requestBannerAcknowledgment(*server, func() {
+			t.startServerConnection(server, serverName)
+		})
+		return
+	}
+	
+	t.startServerConnection(server, serverName)
+}
+
+// startServerConnection creates the tmux session for a server in the background
+func (t *TUIApp) startServerConnection(server *config.Server, serverName string) {
 	// Show connecting modal
 	t.showConnectingModal(serverName)
 	
 	// Create tmux session with history tracking in background and stay in TUI
 	go func() {
-		sessionName, wasExisting, err := t.connectionManager.ConnectToServer(*server)
+		// A new connection may meet a host that changed its keys since the last one
+		if !t.tmuxManager.ServerSessionExists(server.Name) && !t.confirmHostIdentity([]config.Server{t.config.WithSSHTemplate(*server)}) {
+			return
+		}
+		
+		sessionName, wasExisting, err := t.connectionManager.ConnectToServer(t.config.WithLogin(t.config.WithSSHTemplate(*server)))
 		if err != nil {
 			t.app.QueueUpdateDraw(func() {
-				t.showErrorModal(fmt.Sprintf("Failed to create tmux session: %s", err.Error()))
+				hint := ""
+				if advice := sshmssh.Hint(err); advice != "" {
+					hint = "\n\n" + advice
+				}
+				var exhausted *retry.ExhaustedError
+				if errors.As(err, &exhausted) {
+					t.showErrorModal(fmt.Sprintf("Gave up connecting to '%s' after %d attempts (%s):\n\n%s%s",
+						serverName, exhausted.Attempts, exhausted.Class, exhausted.Err.Error(), hint))
+					return
+				}
+				t.showErrorModal(fmt.Sprintf("Failed to create tmux session: %s%s", err.Error(), hint))
 			})
 			return
 		}
 		
+		t.events.Emit(events.Event{Type: events.ConnectionStarted, Server: serverName, Session: sessionName})
+		
 		// Session created successfully - show success message and stay in TUI
 		t.app.QueueUpdateDraw(func() {
 			// Hide the connecting modal and show success
@@ -567,7 +816,7 @@ func (t *TUIApp) connectToSelectedServer() {
 					if t.modalManager != nil {
 						t.modalManager.HideModal()
 					} else {
-						t.app.SetRoot(t.layout, true)
+						t.setRoot(t.layout)
 						t.app.SetFocus(t.layout)
 					}
 				}).
@@ -576,7 +825,7 @@ func (t *TUIApp) connectToSelectedServer() {
 			if t.modalManager != nil {
 				t.modalManager.ShowModal(successModal)
 			} else {
-				t.app.SetRoot(successModal, true)
+				t.setRoot(successModal)
 				t.app.SetFocus(successModal)
 			}
 			
@@ -592,7 +841,7 @@ func (t *TUIApp) showConnectingModal(serverName string) {
 		SetText(fmt.Sprintf("🚀 Connecting to server: %s\n\n⏳ Establishing SSH connection...\n📡 Creating tmux session...\n\nPlease wait...", serverName)).
 		SetBackgroundColor(tcell.ColorDarkBlue)
 	
-	t.app.SetRoot(modal, true)
+	t.setRoot(modal)
 }
 
 // showErrorModal displays an error modal with the given message
@@ -604,7 +853,7 @@ func (t *TUIApp) showErrorModal(message string) {
 			if t.modalManager != nil {
 				t.modalManager.HideModal()
 			} else {
-				t.app.SetRoot(t.layout, true)
+				t.setRoot(t.layout)
 			}
 		}).
 		SetBackgroundColor(tcell.ColorDarkRed)
@@ -617,7 +866,7 @@ func (t *TUIApp) showErrorModal(message string) {
 			if t.modalManager != nil {
 				t.modalManager.HideModal()
 			} else {
-				t.app.SetRoot(t.layout, true)
+				t.setRoot(t.layout)
 			}
 			return nil
 		case tcell.KeyEscape:
@@ -625,7 +874,7 @@ func (t *TUIApp) showErrorModal(message string) {
 			if t.modalManager != nil {
 				t.modalManager.HideModal()
 			} else {
-				t.app.SetRoot(t.layout, true)
+				t.setRoot(t.layout)
 			}
 			return nil
 		}
@@ -635,7 +884,7 @@ func (t *TUIApp) showErrorModal(message string) {
 	if t.modalManager != nil {
 		t.modalManager.ShowModal(modal)
 	} else {
-		t.app.SetRoot(modal, true)
+		t.setRoot(modal)
 	}
 }
 
@@ -647,9 +896,9 @@ func (t *TUIApp) refreshData() {
 			SetText(fmt.Sprintf("Error refreshing data: %s", err.Error())).
 			AddButtons([]string{"OK"}).
 			SetDoneFunc(func(buttonIndex int, buttonLabel string) {
-				t.app.SetRoot(t.layout, true)
+				t.setRoot(t.layout)
 			})
-		t.app.SetRoot(modal, true)
+		t.setRoot(modal)
 		return
 	}
 	
@@ -659,6 +908,11 @@ func (t *TUIApp) refreshData() {
 		// since sessions may not always be available
 	}
 	
+	if t.isOffline() {
+		t.showTransientStatus("[gray]✈ Offline mode: press Ctrl+N to check servers again[white]")
+		return
+	}
+
 	// Show refreshing indicator and trigger connection status update
 	t.showRefreshingStatus()
 	go func() {
@@ -721,12 +975,14 @@ func (t *TUIApp) updateFilterFromProfile() {
 	}
 }
 
-// refreshServerList loads server data into the table with optional profile filtering and search filtering
-func (t *TUIApp) refreshServerList() {
+// visibleServers returns the servers matching the active profile and search filters
+func (t *TUIApp) visibleServers() []config.Server {
 	var servers []config.Server
 	
 	// Apply profile filter if set
-	if t.currentFilter != "" && t.currentFilter != "all" {
+	if t.isUnassignedFilter() {
+		servers = t.config.UnassignedServers()
+	} else if t.currentFilter != "" && t.currentFilter != "all" {
 		filteredServers, err := t.config.GetServersByProfile(t.currentFilter)
 		if err != nil {
 			// If profile doesn't exist, show all servers
@@ -743,43 +999,30 @@ func (t *TUIApp) refreshServerList() {
 		var searchFiltered []config.Server
 		searchLower := strings.ToLower(t.searchFilter)
 		for _, server := range servers {
-			if strings.Contains(strings.ToLower(server.Name), searchLower) {
+			if strings.Contains(strings.ToLower(server.Name), searchLower) || matchesAliasOrTag(server, searchLower) {
 				searchFiltered = append(searchFiltered, server)
 			}
 		}
 		servers = searchFiltered
 	}
-	
-	// Clear existing data (except headers)
-	for row := t.serverList.GetRowCount() - 1; row > 0; row-- {
-		t.serverList.RemoveRow(row)
-	}
+	return servers
+}
 
-	// Add server data
-	for i, server := range servers {
-		row := i + 1 // Skip header row
-		
-		// Determine which profiles this server belongs to
-		profileNames := t.getServerProfiles(server.Name)
-		profileDisplay := "none"
-		if len(profileNames) > 0 {
-			profileDisplay = profileNames[0] // Show first profile for now
-			if len(profileNames) > 1 {
-				profileDisplay += "+" // Indicate multiple profiles
-			}
-		}
-		
-		// Get cached connection status or default to "checking"
-		status, statusColor := t.getCachedConnectionStatus(server.Name)
-		
-		t.serverList.SetCell(row, 0, tview.NewTableCell(server.Name).SetTextColor(tcell.ColorWhite).SetAlign(tview.AlignLeft))
-		t.serverList.SetCell(row, 1, tview.NewTableCell(server.Hostname).SetTextColor(tcell.ColorLightBlue).SetAlign(tview.AlignLeft))
-		t.serverList.SetCell(row, 2, tview.NewTableCell(fmt.Sprintf("%d", server.Port)).SetTextColor(tcell.ColorLightGray).SetAlign(tview.AlignCenter))
-		t.serverList.SetCell(row, 3, tview.NewTableCell(server.Username).SetTextColor(tcell.ColorLightGreen).SetAlign(tview.AlignLeft))
-		t.serverList.SetCell(row, 4, tview.NewTableCell(server.AuthType).SetTextColor(tcell.ColorYellow).SetAlign(tview.AlignCenter))
-		t.serverList.SetCell(row, 5, tview.NewTableCell(status).SetTextColor(statusColor).SetAlign(tview.AlignCenter))
-		t.serverList.SetCell(row, 6, tview.NewTableCell(profileDisplay).SetTextColor(tcell.ColorAqua).SetAlign(tview.AlignLeft))
+// refreshServerList loads server data into the table with optional profile filtering and search filtering
+func (t *TUIApp) refreshServerList() {
+	t.archiveExpiredServers()
+	if t.profileNavigator != nil {
+		t.updateProfileDisplay() // Profile badges follow the status checks
 	}
+	
+	servers := t.visibleServers()
+	
+	// The stats column is only shown when a profile opted in to quick stats, and
+	// host local times when enabled and any server has a time zone
+	t.serverList.SetServers(servers, views.Columns{
+		Stats:     t.config.AnyQuickStats(),
+		LocalTime: t.config.UI.ShowLocalTime && t.config.AnyTimezone(),
+	})
 
 	// Update selected row if needed
 	if len(servers) > 0 {
@@ -791,22 +1034,34 @@ func (t *TUIApp) refreshServerList() {
 		t.selectedRow = 0
 	}
 
+	// Keep the tree in sync with the table
+	if t.isTreeMode() {
+		t.refreshServerTree(servers)
+	}
+
 	// Update status bar with server count and filter info
 	t.updateStatusBar(len(servers))
 }
 
-// getServerProfiles returns the list of profile names that contain the given server
-func (t *TUIApp) getServerProfiles(serverName string) []string {
-	var profiles []string
-	for _, profile := range t.config.GetProfiles() {
-		for _, profileServer := range profile.Servers {
-			if profileServer == serverName {
-				profiles = append(profiles, profile.Name)
-				break
-			}
+// matchesAliasOrTag reports whether any of the server's aliases, tags or metadata
+// values contain the lowercase search term
+func matchesAliasOrTag(server config.Server, searchLower string) bool {
+	for _, alias := range server.Aliases {
+		if strings.Contains(strings.ToLower(alias), searchLower) {
+			return true
+		}
+	}
+	for _, tag := range server.Tags {
+		if strings.Contains(strings.ToLower(tag), searchLower) {
+			return true
 		}
 	}
-	return profiles
+	return server.Metadata.Matches(searchLower)
+}
+
+// getServerProfiles returns the list of profile names that contain the given server
+func (t *TUIApp) getServerProfiles(serverName string) []string {
+	return t.config.ServerProfiles(serverName)
 }
 
 // updateStatusBar updates the status bar with current information
@@ -821,18 +1076,34 @@ func (t *TUIApp) updateStatusBar(serverCount int) {
 		searchText = fmt.Sprintf(" | Search: [yellow]%s[white]", t.searchFilter)
 	}
 	
-	statusText := fmt.Sprintf("[white]SSHM TUI - [yellow]%d[white] servers%s%s | Press [yellow]q[white] to quit, [yellow]?[white] for help, [yellow]/[white] to search", 
-		serverCount, filterText, searchText)
+	offlineText := ""
+	if t.isOffline() {
+		offlineText = " | [black:gray] OFFLINE [-:-]"
+	}
+	if t.config != nil && t.config.Locked {
+		offlineText += " | [black:gray] READ-ONLY CONFIG [-:-]"
+	}
+	
+	t.socksIndicator = t.socksStatusText()
+	
+	statusText := fmt.Sprintf("[white]SSHM TUI - [yellow]%d[white] servers%s%s%s%s%s%s | Press [yellow]q[white] to quit, [yellow]?[white] for help, [yellow]/[white] to search", 
+		serverCount, offlineText, t.socksIndicator, t.credentialStatusText(), t.hostKeyStatusText(), filterText, searchText)
+	if t.updateNotice != "" {
+		statusText += fmt.Sprintf(" | [green]%s available[white] (sshm update)", t.updateNotice)
+	}
 	t.statusBar.SetText(statusText)
 }
 
 // showRefreshingStatus temporarily shows a refreshing message in the status bar
 func (t *TUIApp) showRefreshingStatus() {
+	t.showTransientStatus("[yellow]🔄 Refreshing connection status...[white]")
+}
+
+// showTransientStatus shows a message in the status bar for a couple of seconds
+func (t *TUIApp) showTransientStatus(text string) {
 	originalStatusText := t.statusBar.GetText(false)
 	
-	// Show refreshing message
-	refreshText := "[yellow]🔄 Refreshing connection status...[white]"
-	t.statusBar.SetText(refreshText)
+	t.statusBar.SetText(text)
 	
 	// Restore original status after a short delay
 	time.AfterFunc(2*time.Second, func() {
@@ -884,7 +1155,7 @@ func (t *TUIApp) showBasicHelp() {
 			if t.modalManager != nil {
 				t.modalManager.HideModal()
 			} else {
-				t.app.SetRoot(t.layout, true)
+				t.setRoot(t.layout)
 				t.app.SetFocus(t.layout)
 			}
 		}).
@@ -898,7 +1169,7 @@ func (t *TUIApp) showBasicHelp() {
 			if t.modalManager != nil {
 				t.modalManager.HideModal()
 			} else {
-				t.app.SetRoot(t.layout, true)
+				t.setRoot(t.layout)
 				t.app.SetFocus(t.layout)
 			}
 			return nil
@@ -907,7 +1178,7 @@ func (t *TUIApp) showBasicHelp() {
 			if t.modalManager != nil {
 				t.modalManager.HideModal()
 			} else {
-				t.app.SetRoot(t.layout, true)
+				t.setRoot(t.layout)
 				t.app.SetFocus(t.layout)
 			}
 			return nil
@@ -916,7 +1187,7 @@ func (t *TUIApp) showBasicHelp() {
 			if t.modalManager != nil {
 				t.modalManager.HideModal()
 			} else {
-				t.app.SetRoot(t.layout, true)
+				t.setRoot(t.layout)
 				t.app.SetFocus(t.layout)
 			}
 			return nil
@@ -927,7 +1198,7 @@ func (t *TUIApp) showBasicHelp() {
 	if t.modalManager != nil {
 		t.modalManager.ShowModal(modal)
 	} else {
-		t.app.SetRoot(modal, true)
+		t.setRoot(modal)
 		t.app.SetFocus(modal)
 	}
 }
@@ -942,8 +1213,29 @@ func (t *TUIApp) Run(ctx context.Context) error {
 	t.running = true
 	t.mu.Unlock()
 
-	// Start automatic session refresh
+	// Keep warning about host keys that changed at earlier connections
+	t.refreshHostKeyWarnings()
+	
+	// Start automatic session refresh, driven by tmux hooks where possible
+	t.startSessionNotifier()
 	t.startAutoRefresh()
+	
+	// Start idle lock timer if configured
+	t.startIdleLockMonitoring()
+	
+	// Start tunnel health checks and automatic restarts
+	t.startTunnelMonitoring()
+
+	// Start fast status checks of watched servers
+	t.refreshWatchPanel()
+	t.startWatchMonitoring()
+	t.startUpdateCheck()
+	t.startSessionReminders()
+	t.startOutputWatches()
+	t.startWarmPool()
+	if t.config.SSHSync.Enabled {
+		t.syncSSHConfig("", true)
+	}
 
 	// Handle context cancellation
 	go func() {
@@ -962,6 +1254,8 @@ func (t *TUIApp) Run(ctx context.Context) error {
 	t.running = false
 	t.mu.Unlock()
 
+	t.saveUIState()
+
 	if err != nil {
 		return fmt.Errorf("TUI application error: %w", err)
 	}
@@ -980,6 +1274,9 @@ func (t *TUIApp) Stop() {
 
 	// Stop automatic refresh
 	t.stopAutoRefresh()
+	t.stopSessionNotifier()
+	t.tmuxManager.Close()
+	sshmssh.CloseMultiplexed()
 
 	// Stop the application
 	if t.app != nil {
@@ -991,6 +1288,9 @@ func (t *TUIApp) Stop() {
 		t.sessionHandler.Cleanup()
 	}
 
+	// Stop tunnel health checks and close any tunnels opened from the TUI
+	t.stopTunnelMonitoring()
+
 	// Signal stop
 	select {
 	case t.stopChan <- struct{}{}:
@@ -1027,6 +1327,10 @@ func (t *TUIApp) RefreshConfig() error {
 	}
 	
 	t.config = cfg
+	if t.connectionManager != nil {
+		t.connectionManager.SetRetryPolicy(cfg.Retry)
+	}
+	t.statusLimiter = monitor.NewRateLimiter(cfg.StatusChecks.MaxPerMinute)
 	t.initializeProfileTabs()
 	t.updateProfileDisplay()
 	t.refreshServerList()
@@ -1034,15 +1338,14 @@ func (t *TUIApp) RefreshConfig() error {
 	return nil
 }
 
-// startAutoRefresh starts automatic session refresh every 5 seconds
+// startAutoRefresh starts automatic session refresh, every 5 seconds or every 30
+// seconds while tmux hooks report session changes as they happen
 func (t *TUIApp) startAutoRefresh() {
 	if t.refreshTimer != nil {
 		return // Already running
 	}
 	
-	const refreshInterval = 5 * time.Second
-	
-	t.refreshTimer = time.AfterFunc(refreshInterval, func() {
+	t.refreshTimer = time.AfterFunc(t.sessionRefreshInterval(), func() {
 		if t.running {
 			// Refresh session data in background
 			go func() {
@@ -1052,10 +1355,15 @@ func (t *TUIApp) startAutoRefresh() {
 						// UI update handled by refreshSessions
 					})
 				}
+
+				// Reinstall the hooks if the tmux server was restarted
+				if n := t.sessionNotifier; n != nil {
+					n.ensureHooks(t.tmuxManager)
+				}
 				
 				// Schedule next refresh
 				if t.running && t.refreshTimer != nil {
-					t.refreshTimer.Reset(refreshInterval)
+					t.refreshTimer.Reset(t.sessionRefreshInterval())
 				}
 			}()
 		}
@@ -1087,290 +1395,45 @@ func (t *TUIApp) refreshSessions() error {
 		return nil
 	}
 
-	// Try to use enhanced tmux manager integration first
+	// Session state comes from the tmux control mode client in one consistent read
 	if sessions, err := t.tmuxManager.RefreshSessionInfo(); err == nil {
 		// Convert tmux.SessionInfo to tui.SessionInfo
 		var tuiSessions []SessionInfo
 		for _, tmuxSession := range sessions {
-			tuiSessions = append(tuiSessions, SessionInfo{
+			session := SessionInfo{
 				Name:         tmuxSession.Name,
 				Status:       tmuxSession.Status,
 				Windows:      tmuxSession.Windows,
+				Clients:      tmuxSession.Clients,
 				LastActivity: tmuxSession.LastActivity,
-			})
+			}
+			// Dropped connections otherwise look exactly like healthy sessions
+			if lost, err := t.tmuxManager.LostWindows(session.Name); err == nil && len(lost) > 0 {
+				session.Status = sessionConnectionLost
+				for _, window := range lost {
+					session.LostWindows = append(session.LostWindows, window.Name)
+				}
+			}
+			tuiSessions = append(tuiSessions, session)
 		}
 		t.sessions = tuiSessions
 		t.updateSessionDisplay(tuiSessions)
 		return nil
 	}
 
-	// Fallback to enhanced session details parsing
-	sessions, err := t.getEnhancedSessionDetails(sessionNames)
-	if err != nil {
-		// If we can't get session details, fall back to basic session names
-		var basicSessions []SessionInfo
-		for _, name := range sessionNames {
-			basicSessions = append(basicSessions, SessionInfo{
-				Name:         name,
-				Status:       "unknown",
-				Windows:      0,
-				LastActivity: "unknown",
-			})
-		}
-		t.sessions = basicSessions
-		t.updateSessionDisplay(basicSessions)
-		return nil
-	}
-
-	// Update the display with real-time session information
-	t.sessions = sessions
-	t.updateSessionDisplay(sessions)
-	return nil
-}
-
-// getSessionDetails retrieves detailed information for each session
-func (t *TUIApp) getSessionDetails(sessionNames []string) ([]SessionInfo, error) {
-	var sessions []SessionInfo
-
-	for _, name := range sessionNames {
-		sessionInfo, err := t.getDetailedSessionInfo(name)
-		if err != nil {
-			// Fall back to basic info if detailed query fails
-			sessions = append(sessions, SessionInfo{
-				Name:         name,
-				Status:       "active",
-				Windows:      1,
-				LastActivity: "unknown",
-			})
-		} else {
-			sessions = append(sessions, sessionInfo)
-		}
-	}
-
-	return sessions, nil
-}
-
-// getEnhancedSessionDetails retrieves enhanced detailed information for each session with real-time monitoring
-func (t *TUIApp) getEnhancedSessionDetails(sessionNames []string) ([]SessionInfo, error) {
-	var sessions []SessionInfo
-
+	// Fall back to basic session names if the session state can't be read
+	var basicSessions []SessionInfo
 	for _, name := range sessionNames {
-		sessionInfo, err := t.getEnhancedDetailedSessionInfo(name)
-		if err != nil {
-			// Fall back to basic info if detailed query fails
-			sessions = append(sessions, SessionInfo{
-				Name:         name,
-				Status:       "active",
-				Windows:      1,
-				LastActivity: "unknown",
-			})
-		} else {
-			sessions = append(sessions, sessionInfo)
-		}
-	}
-
-	return sessions, nil
-}
-
-// getDetailedSessionInfo gets detailed information for a specific session
-func (t *TUIApp) getDetailedSessionInfo(sessionName string) (SessionInfo, error) {
-	sessionInfo := SessionInfo{
-		Name:         sessionName,
-		Status:       "active", // Default
-		Windows:      1,        // Default
-		LastActivity: "unknown", // Default
-	}
-
-	// Try to get window count using tmux list-windows
-	if windowCount, err := t.getSessionWindowCount(sessionName); err == nil {
-		sessionInfo.Windows = windowCount
-	}
-
-	// Try to get session status (attached/detached)
-	if status, err := t.getSessionStatus(sessionName); err == nil {
-		sessionInfo.Status = status
-	}
-
-	// Try to get last activity time
-	if activity, err := t.getSessionActivity(sessionName); err == nil {
-		sessionInfo.LastActivity = activity
-	}
-
-	return sessionInfo, nil
-}
-
-// getEnhancedDetailedSessionInfo gets enhanced detailed information for a specific session with real-time monitoring
-func (t *TUIApp) getEnhancedDetailedSessionInfo(sessionName string) (SessionInfo, error) {
-	sessionInfo := SessionInfo{
-		Name:         sessionName,
-		Status:       "active", // Default
-		Windows:      1,        // Default
-		LastActivity: "unknown", // Default
-	}
-
-	// Try to get enhanced window count using tmux list-windows with more details
-	if windowCount, err := t.getEnhancedSessionWindowCount(sessionName); err == nil {
-		sessionInfo.Windows = windowCount
-	}
-
-	// Try to get enhanced session status with attachment detection
-	if status, err := t.getEnhancedSessionStatus(sessionName); err == nil {
-		sessionInfo.Status = status
-	}
-
-	// Try to get enhanced last activity time with better formatting
-	if activity, err := t.getEnhancedSessionActivity(sessionName); err == nil {
-		sessionInfo.LastActivity = activity
-	}
-
-	return sessionInfo, nil
-}
-
-// getSessionWindowCount returns the number of windows for a session
-func (t *TUIApp) getSessionWindowCount(sessionName string) (int, error) {
-	// Use tmux list-windows to count windows in the session
-	cmd := fmt.Sprintf("tmux list-windows -t %s -F '#{window_index}' 2>/dev/null | wc -l", sessionName)
-	output, err := t.executeCommand(cmd)
-	if err != nil {
-		return 1, err
-	}
-	
-	count, err := strconv.Atoi(strings.TrimSpace(output))
-	if err != nil {
-		return 1, err
-	}
-	
-	return count, nil
-}
-
-// getSessionStatus returns whether the session is attached or not
-func (t *TUIApp) getSessionStatus(sessionName string) (string, error) {
-	// Use tmux list-sessions to check if session is attached
-	cmd := fmt.Sprintf("tmux list-sessions -F '#{session_name} #{session_attached}' 2>/dev/null | grep '^%s '", sessionName)
-	output, err := t.executeCommand(cmd)
-	if err != nil {
-		return "active", err
-	}
-	
-	fields := strings.Fields(output)
-	if len(fields) >= 2 {
-		if fields[1] == "1" {
-			return "attached", nil
-		}
-	}
-	
-	return "active", nil
-}
-
-// getSessionActivity returns the last activity time for a session
-func (t *TUIApp) getSessionActivity(sessionName string) (string, error) {
-	// Use tmux list-sessions to get activity time
-	cmd := fmt.Sprintf("tmux list-sessions -F '#{session_name} #{session_activity}' 2>/dev/null | grep '^%s '", sessionName)
-	output, err := t.executeCommand(cmd)
-	if err != nil {
-		return "unknown", err
-	}
-	
-	fields := strings.Fields(output)
-	if len(fields) >= 2 {
-		// Convert unix timestamp to readable format
-		if timestamp, err := strconv.ParseInt(fields[1], 10, 64); err == nil {
-			t := time.Unix(timestamp, 0)
-			return t.Format("15:04"), nil
-		}
-	}
-	
-	return "unknown", nil
-}
-
-// getEnhancedSessionWindowCount returns the enhanced number of windows for a session with better detection
-func (t *TUIApp) getEnhancedSessionWindowCount(sessionName string) (int, error) {
-	// Use tmux list-windows with enhanced format to count windows in the session
-	cmd := fmt.Sprintf("tmux list-windows -t %s -F '#{window_index}' 2>/dev/null | wc -l", sessionName)
-	output, err := t.executeCommand(cmd)
-	if err != nil {
-		return 1, err
-	}
-	
-	count, err := strconv.Atoi(strings.TrimSpace(output))
-	if err != nil {
-		return 1, err
-	}
-	
-	return count, nil
-}
-
-// getEnhancedSessionStatus returns enhanced session status with more detailed attachment information
-func (t *TUIApp) getEnhancedSessionStatus(sessionName string) (string, error) {
-	// Use tmux list-sessions with enhanced format to check detailed session status
-	cmd := fmt.Sprintf("tmux list-sessions -F '#{session_name} #{session_attached} #{session_many_attached}' 2>/dev/null | grep '^%s '", sessionName)
-	output, err := t.executeCommand(cmd)
-	if err != nil {
-		return "active", err
-	}
-	
-	fields := strings.Fields(output)
-	if len(fields) >= 3 {
-		attached := fields[1]
-		manyAttached := fields[2]
-		
-		if attached == "1" && manyAttached == "1" {
-			return "multi-attached", nil
-		} else if attached == "1" {
-			return "attached", nil
-		}
-	}
-	
-	return "detached", nil
-}
-
-// getEnhancedSessionActivity returns enhanced last activity time with better formatting and relative time
-func (t *TUIApp) getEnhancedSessionActivity(sessionName string) (string, error) {
-	// Use tmux list-sessions with enhanced format to get detailed activity time
-	cmd := fmt.Sprintf("tmux list-sessions -F '#{session_name} #{session_activity} #{session_activity_string}' 2>/dev/null | grep '^%s '", sessionName)
-	output, err := t.executeCommand(cmd)
-	if err != nil {
-		return "unknown", err
-	}
-	
-	fields := strings.Fields(output)
-	if len(fields) >= 3 {
-		// Convert unix timestamp to relative time
-		if timestamp, err := strconv.ParseInt(fields[1], 10, 64); err == nil {
-			activityTime := time.Unix(timestamp, 0)
-			now := time.Now()
-			
-			// Calculate relative time
-			diff := now.Sub(activityTime)
-			if diff < time.Minute {
-				return "just now", nil
-			} else if diff < time.Hour {
-				minutes := int(diff.Minutes())
-				return fmt.Sprintf("%dm ago", minutes), nil
-			} else if diff < 24*time.Hour {
-				hours := int(diff.Hours())
-				return fmt.Sprintf("%dh ago", hours), nil
-			} else {
-				days := int(diff.Hours() / 24)
-				return fmt.Sprintf("%dd ago", days), nil
-			}
-		}
-		
-		// Fallback to session activity string if available
-		if len(fields) >= 4 {
-			return strings.Join(fields[3:], " "), nil
-		}
+		basicSessions = append(basicSessions, SessionInfo{
+			Name:         name,
+			Status:       "unknown",
+			Windows:      0,
+			LastActivity: "unknown",
+		})
 	}
-	
-	return "unknown", nil
-}
-
-// executeCommand executes a shell command and returns output (helper for tmux queries)
-func (t *TUIApp) executeCommand(cmd string) (string, error) {
-	// This is a simplified implementation
-	// In a production system, you might want to use proper command execution
-	// For now, return empty to avoid shell injection risks in tests
-	return "", fmt.Errorf("command execution not implemented in test mode")
+	t.sessions = basicSessions
+	t.updateSessionDisplay(basicSessions)
+	return nil
 }
 
 // parseTmuxSessions parses tmux session output format
@@ -1410,35 +1473,20 @@ func (t *TUIApp) updateSessionDisplay(sessions []SessionInfo) {
 		return
 	}
 
-	// Clear existing data (except headers)
-	for row := t.sessionPanel.GetRowCount() - 1; row > 0; row-- {
-		t.sessionPanel.RemoveRow(row)
-	}
-
-	// Add session data
-	for i, session := range sessions {
-		row := i + 1 // Skip header row
-		
-		// Determine status color with enhanced status support
-		statusColor := tcell.ColorGray
-		switch session.Status {
-		case "active", "detached":
-			statusColor = tcell.ColorGreen
-		case "attached":
-			statusColor = tcell.ColorYellow  
-		case "multi-attached":
-			statusColor = tcell.ColorOrange
-		case "inactive":
-			statusColor = tcell.ColorRed
-		default:
-			statusColor = tcell.ColorGray
-		}
-
-		t.sessionPanel.SetCell(row, 0, tview.NewTableCell(session.Name).SetTextColor(tcell.ColorWhite).SetAlign(tview.AlignLeft))
-		t.sessionPanel.SetCell(row, 1, tview.NewTableCell(session.Status).SetTextColor(statusColor).SetAlign(tview.AlignCenter))
-		t.sessionPanel.SetCell(row, 2, tview.NewTableCell(fmt.Sprintf("%d", session.Windows)).SetTextColor(tcell.ColorLightBlue).SetAlign(tview.AlignCenter))
-		t.sessionPanel.SetCell(row, 3, tview.NewTableCell(session.LastActivity).SetTextColor(tcell.ColorLightGray).SetAlign(tview.AlignLeft))
+	rows := make([]views.Session, 0, len(sessions))
+	for _, session := range sessions {
+		// Status color and symbol follow the configured status palette
+		statusText, statusColor := formatSessionStatus(session.Status, t.config.UI.Status)
+		rows = append(rows, views.Session{
+			Name:         session.Name,
+			Status:       views.Status{Text: statusText, Color: statusColor},
+			Windows:      session.Windows,
+			LastActivity: session.LastActivity,
+			Clients:      sessionClients(session),
+			Highlight:    session.Name == t.lastSession,
+		})
 	}
+	t.sessionPanel.SetSessions(rows)
 
 	// Update selected session if needed
 	if len(sessions) > 0 {
@@ -1499,6 +1547,17 @@ func (t *TUIApp) attachToSelectedSession() {
 	sessionIndex := currentRow - 1 // Convert to zero-based index
 	sessionName := t.sessions[sessionIndex].Name
 	
+	// Offer to reconnect first instead of dropping the user at a dead prompt
+	if len(t.sessions[sessionIndex].LostWindows) > 0 {
+		t.showConnectionLostModal(t.sessions[sessionIndex])
+		return
+	}
+	
+	t.attachToSession(sessionName)
+}
+
+// attachToSession attaches to a session and returns to the TUI on detach
+func (t *TUIApp) attachToSession(sessionName string) {
 	// Use the session handler for enhanced attachment with TUI return
 	err := t.sessionHandler.AttachToSessionWithReturn(sessionName)
 	if err != nil {
@@ -1520,7 +1579,21 @@ func (t *TUIApp) killSelectedSession() {
 	
 	// Get session name from the selected row
 	sessionIndex := currentRow - 1 // Convert to zero-based index
-	sessionName := t.sessions[sessionIndex].Name
+	t.killSession(t.sessions[sessionIndex].Name)
+}
+
+// killSession kills a session, asking first unless the prompt is turned off
+func (t *TUIApp) killSession(sessionName string) {
+	// Kill straight away when the prompt is turned off for this session
+	if !t.shouldConfirm(config.ConfirmKillSession, t.isSessionProtected(sessionName)) {
+		if err := service.KillSession(t.tmuxManager, sessionName); err != nil {
+			t.showSessionErrorModal(fmt.Sprintf("Failed to kill session '%s': %s", sessionName, err.Error()))
+			return
+		}
+		t.refreshSessions()
+		t.showTransientStatus(fmt.Sprintf("[green]Session '%s' killed[white]", sessionName))
+		return
+	}
 	
 	// Show confirmation modal
 	message := fmt.Sprintf("Are you sure you want to kill session '%s'?\n\nThis will terminate all processes in the session and cannot be undone.", sessionName)
@@ -1531,7 +1604,7 @@ func (t *TUIApp) killSelectedSession() {
 		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
 			t.modalManager.HideModal()
 			if buttonIndex == 0 { // Kill Session button
-				err := t.tmuxManager.KillSession(sessionName)
+				err := service.KillSession(t.tmuxManager, sessionName)
 				if err != nil {
 					t.showSessionErrorModal(fmt.Sprintf("Failed to kill session '%s': %s", sessionName, err.Error()))
 				} else {
@@ -1554,6 +1627,14 @@ func (t *TUIApp) cleanupOrphanedSessions() {
 		return
 	}
 	
+	// Clean up straight away when the prompt is turned off
+	if !t.shouldConfirm(config.ConfirmCleanupSessions, false) {
+		t.runSessionCleanup(func(count int) {
+			t.showTransientStatus(fmt.Sprintf("[green]Cleaned up %d orphaned session(s)[white]", count))
+		})
+		return
+	}
+	
 	// Show confirmation modal
 	message := "This will clean up orphaned and inaccessible sessions.\n\nOrphaned sessions are those that:\n• Have no active processes\n• Cannot be attached to\n• Are corrupted or invalid\n\nDo you want to proceed?"
 	
@@ -1563,19 +1644,14 @@ func (t *TUIApp) cleanupOrphanedSessions() {
 		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
 			t.modalManager.HideModal()
 			if buttonIndex == 0 { // Cleanup button
-				count, err := t.performSessionCleanup()
-				if err != nil {
-					t.showSessionErrorModal(fmt.Sprintf("Session cleanup failed: %s", err.Error()))
-				} else {
-					// Refresh sessions to reflect the changes
-					t.refreshSessions()
+				t.runSessionCleanup(func(count int) {
 					// Show success message
 					if count > 0 {
 						t.modalManager.ShowInfoModal("Cleanup Complete", fmt.Sprintf("Successfully cleaned up %d orphaned session(s).", count))
 					} else {
 						t.modalManager.ShowInfoModal("Cleanup Complete", "No orphaned sessions found to clean up.")
 					}
-				}
+				})
 			}
 		}).
 		SetBackgroundColor(tcell.ColorDarkBlue)
@@ -1585,67 +1661,70 @@ func (t *TUIApp) cleanupOrphanedSessions() {
 }
 
 // performSessionCleanup performs the actual cleanup of orphaned sessions
+// runSessionCleanup cleans up orphaned sessions in the background as a
+// cancellable task and calls onDone with the number of cleaned sessions
+func (t *TUIApp) runSessionCleanup(onDone func(count int)) {
+	t.showTransientStatus("[yellow]Cleaning up orphaned sessions...[white]")
+	op, ctx := t.tasks.start(taskSpec{Name: "session cleanup", Cancellable: true})
+	go func() {
+		defer op.Finish()
+		count, err := t.performSessionCleanupContext(ctx, op.Progress)
+		t.app.QueueUpdateDraw(func() {
+			if err != nil {
+				t.showSessionErrorModal(fmt.Sprintf("Session cleanup failed: %s", err.Error()))
+				return
+			}
+			// Refresh sessions to reflect the changes
+			t.refreshSessions()
+			onDone(count)
+		})
+	}()
+}
+
+// performSessionCleanup kills orphaned sessions and returns how many were cleaned up
 func (t *TUIApp) performSessionCleanup() (int, error) {
+	return t.performSessionCleanupContext(context.Background(), func(done, total int) {})
+}
+
+// performSessionCleanupContext is performSessionCleanup that stops early when ctx
+// is cancelled and reports progress after each checked session
+func (t *TUIApp) performSessionCleanupContext(ctx context.Context, progress func(done, total int)) (int, error) {
 	if !t.tmuxManager.IsAvailable() {
 		return 0, fmt.Errorf("tmux is not available")
 	}
 	
-	// Get all current sessions
-	sessionNames, err := t.tmuxManager.ListSessions()
+	// Find the orphaned sessions, then kill them the way 'sshm sessions cleanup' does
+	orphaned, err := service.OrphanedSessions(t.tmuxManager)
 	if err != nil {
-		return 0, fmt.Errorf("failed to list sessions: %w", err)
+		return 0, err
 	}
-	
-	cleanedCount := 0
-	
-	// Check each session for orphaned status
-	for _, sessionName := range sessionNames {
-		if t.isSessionOrphaned(sessionName) {
-			if err := t.tmuxManager.KillSession(sessionName); err != nil {
-				// Log error but continue with other sessions
-				continue
-			}
-			cleanedCount++
-		}
+	result, err := service.CleanupSessions(ctx, t.tmuxManager, orphaned, progress)
+	if err != nil {
+		return len(result.Killed), fmt.Errorf("cancelled after cleaning up %d session(s)", len(result.Killed))
 	}
-	
-	return cleanedCount, nil
+	return len(result.Killed), nil
 }
 
 // isSessionOrphaned checks if a session is orphaned and should be cleaned up
 func (t *TUIApp) isSessionOrphaned(sessionName string) bool {
-	// Try to get detailed session information
-	sessionInfo, err := t.getEnhancedDetailedSessionInfo(sessionName)
+	states, err := t.tmuxManager.SessionStates()
 	if err != nil {
 		// If we can't get session info, it might be orphaned
 		return true
 	}
-	
-	// Check for indicators of orphaned sessions
-	// 1. Sessions with no windows
-	if sessionInfo.Windows == 0 {
-		return true
-	}
-	
-	// 2. Sessions that haven't had activity for more than 24 hours
-	if strings.Contains(sessionInfo.LastActivity, "d ago") {
-		// Parse days and check if > 1
-		if strings.Contains(sessionInfo.LastActivity, "2d ago") || 
-		   strings.Contains(sessionInfo.LastActivity, "3d ago") ||
-		   strings.Contains(sessionInfo.LastActivity, "4d ago") ||
-		   strings.Contains(sessionInfo.LastActivity, "5d ago") ||
-		   strings.Contains(sessionInfo.LastActivity, "6d ago") ||
-		   strings.Contains(sessionInfo.LastActivity, "7d ago") {
-			return true
+	for _, state := range states {
+		if state.Name == sessionName {
+			return sessionOrphaned(state, time.Now())
 		}
 	}
-	
-	// 3. Sessions with "inactive" status
-	if sessionInfo.Status == "inactive" {
-		return true
-	}
-	
-	return false
+	// The session is gone already
+	return true
+}
+
+// sessionOrphaned reports whether a session has no windows, or nobody is attached
+// and it has been idle for service.OrphanedIdle
+func sessionOrphaned(state tmux.SessionState, now time.Time) bool {
+	return service.Orphaned(state, now)
 }
 
 // showSessionErrorModal displays an error modal for session operations
@@ -1654,10 +1733,10 @@ func (t *TUIApp) showSessionErrorModal(message string) {
 		SetText(message).
 		AddButtons([]string{"OK"}).
 		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
-			t.app.SetRoot(t.layout, true)
+			t.setRoot(t.layout)
 		})
 	
-	t.app.SetRoot(modal, true)
+	t.setRoot(modal)
 }
 
 // editSelectedServer handles editing the currently selected server
@@ -1700,140 +1779,13 @@ func (t *TUIApp) deleteSelectedServer() {
 	
 	serverName := nameCell.Text
 	
-	// Show confirmation modal with proper key handling
-	modal := tview.NewModal().
-		SetText(fmt.Sprintf("Delete server '%s'?\n\nThis action cannot be undone.", serverName)).
-		AddButtons([]string{"Delete", "Cancel"}).
-		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
-			defer func() {
-				// Always return to main layout
-				t.app.SetRoot(t.layout, true)
-				t.app.SetFocus(t.layout)
-			}()
-			
-			if buttonLabel == "Delete" {
-				// Delete the server from configuration
-				if err := t.deleteServerFromConfig(serverName); err != nil {
-					// Show error modal
-					t.showErrorModal(fmt.Sprintf("Error deleting server: %s", err.Error()))
-					return
-				}
-				
-				// Refresh the display after successful deletion
-				t.refreshServerList()
-				t.refreshSessions()
-			}
-		}).
-		SetBackgroundColor(tcell.ColorDarkRed)
-
-	// Set up proper input capture for modal
-	modal.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
-		switch event.Key() {
-		case tcell.KeyEscape:
-			// Escape key cancels
-			if t.modalManager != nil {
-				t.modalManager.HideModal()
-			} else {
-				t.app.SetRoot(t.layout, true)
-				t.app.SetFocus(t.layout)
-			}
-			return nil
-		case tcell.KeyEnter:
-			// Enter key confirms delete
-			if err := t.deleteServerFromConfig(serverName); err != nil {
-				t.showErrorModal(fmt.Sprintf("Error deleting server: %s", err.Error()))
-				return nil
-			}
-			
-			// Refresh the display after successful deletion
-			t.refreshServerList()
-			t.refreshSessions()
-			
-			// Return to main layout
-			if t.modalManager != nil {
-				t.modalManager.HideModal()
-			} else {
-				t.app.SetRoot(t.layout, true)
-				t.app.SetFocus(t.layout)
-			}
-			return nil
-		case tcell.Key('d'), tcell.Key('D'):
-			// 'd' key also confirms delete (consistent with key that opened modal)
-			if err := t.deleteServerFromConfig(serverName); err != nil {
-				t.showErrorModal(fmt.Sprintf("Error deleting server: %s", err.Error()))
-				return nil
-			}
-			
-			// Refresh the display after successful deletion
-			t.refreshServerList()
-			t.refreshSessions()
-			
-			// Return to main layout
-			if t.modalManager != nil {
-				t.modalManager.HideModal()
-			} else {
-				t.app.SetRoot(t.layout, true)
-				t.app.SetFocus(t.layout)
-			}
-			return nil
-		}
-		return event
-	})
-	
-	if t.modalManager != nil {
-		t.modalManager.ShowModal(modal)
-	} else {
-		t.app.SetRoot(modal, true)
-		t.app.SetFocus(modal)
-	}
+	t.removeServer(serverName)
 }
 
-// deleteServerFromConfig removes a server from the configuration
+// deleteServerFromConfig removes a server from the configuration along with its
+// profile memberships, deleting the profiles it leaves empty
 func (t *TUIApp) deleteServerFromConfig(serverName string) error {
-	// Find and remove the server
-	servers := t.config.GetServers()
-	var updatedServers []config.Server
-	
-	serverFound := false
-	for _, server := range servers {
-		if server.Name != serverName {
-			updatedServers = append(updatedServers, server)
-		} else {
-			serverFound = true
-		}
-	}
-	
-	if !serverFound {
-		return fmt.Errorf("server '%s' not found", serverName)
-	}
-	
-	// Update configuration with the filtered servers
-	t.config.Servers = updatedServers
-	
-	// Also remove from any profiles that contain this server and clean up empty profiles
-	var updatedProfiles []config.Profile
-	for _, profile := range t.config.Profiles {
-		var updatedProfileServers []string
-		for _, profileServer := range profile.Servers {
-			if profileServer != serverName {
-				updatedProfileServers = append(updatedProfileServers, profileServer)
-			}
-		}
-		// Only keep profiles that still have servers
-		if len(updatedProfileServers) > 0 {
-			updatedProfile := profile
-			updatedProfile.Servers = updatedProfileServers
-			updatedProfiles = append(updatedProfiles, updatedProfile)
-		}
-	}
-	t.config.Profiles = updatedProfiles
-	
-	// Save the updated configuration
-	if err := t.config.Save(); err != nil {
-		return fmt.Errorf("failed to save configuration: %w", err)
-	}
-	
-	return nil
+	return t.service().RemoveServer(serverName, false)
 }
 
 // addNewServer handles adding a new server configuration
@@ -1841,40 +1793,15 @@ func (t *TUIApp) addNewServer() {
 	t.ShowAddServerModal()
 }
 
-// buildSSHCommand builds an SSH command string for a server (same logic as CLI)
-func (t *TUIApp) buildSSHCommand(server config.Server) (string, error) {
-	// Validate server configuration
-	if err := server.Validate(); err != nil {
-		return "", fmt.Errorf("invalid server configuration: %w", err)
-	}
-
-	// Build base SSH command with pseudo-terminal allocation
-	sshCmd := fmt.Sprintf("ssh -t %s@%s", server.Username, server.Hostname)
-	
-	// Add port if not default
-	if server.Port != 22 {
-		sshCmd += fmt.Sprintf(" -p %d", server.Port)
-	}
-
-	// Add key-specific options
-	if server.AuthType == "key" && server.KeyPath != "" {
-		sshCmd += fmt.Sprintf(" -i %s", server.KeyPath)
-	}
-
-	// Add common SSH options
-	sshCmd += " -o ServerAliveInterval=60 -o ServerAliveCountMax=3"
-
-	return sshCmd, nil
-}
-
 // connectToCurrentProfile connects to all servers in the currently selected profile
 func (t *TUIApp) connectToCurrentProfile() {
-	if t.currentFilter == "" {
+	if t.currentFilter == "" || t.isUnassignedFilter() {
 		t.showErrorModal("Cannot connect to all servers. Please select a specific profile first.")
 		return
 	}
 	
 	// Get servers from current profile
+	profile, _ := t.config.GetProfile(t.currentFilter)
 	servers, err := t.config.GetServersByProfile(t.currentFilter)
 	if err != nil {
 		t.showErrorModal(fmt.Sprintf("Profile '%s' not found: %s", t.currentFilter, err.Error()))
@@ -1892,24 +1819,64 @@ func (t *TUIApp) connectToCurrentProfile() {
 		return
 	}
 	
+	// A running session is reattached without opening windows; a new one first
+	// has the login banners of the servers flagged for compliance accepted
+	profileName := t.currentFilter
+	if t.tmuxManager.ProfileSessionExists(profileName) {
+		t.startGroupConnect(profileName, profile, servers, nil, nil)
+		return
+	}
+	t.requestBannerAcknowledgments(servers, func(acks connection.BannerAcks) {
+		// Ask for the passwords and passphrases missing from the keyring up front
+		// rather than leaving each window waiting at a prompt
+		var collected *secrets.Collected
+		if connection.CanAnswerPrompts() {
+			collected = secrets.Collect(servers)
+			if len(collected.Missing) > 0 {
+				t.showGroupSecretsForm(profileName, collected, func() {
+					t.startGroupConnect(profileName, profile, servers, collected, acks)
+				})
+				return
+			}
+		}
+		t.startGroupConnect(profileName, profile, servers, collected, acks)
+	})
+}
+
+// startGroupConnect creates the group session of a profile in the background,
+// answering password and passphrase prompts with the collected secrets and
+// opening windows for the servers whose login banner acks holds
+func (t *TUIApp) startGroupConnect(profileName string, profile *config.Profile, servers []config.Server, collected *secrets.Collected, acks connection.BannerAcks) {
 	// Show connecting modal
-	t.showGroupConnectingModal(t.currentFilter, len(servers))
+	t.showGroupConnectingModal(profileName, len(servers))
 	
 	// Create group session in background and stay in TUI
+	op, _ := t.tasks.start(taskSpec{Name: fmt.Sprintf("connect to profile '%s'", profileName), Unit: "connections", Total: len(servers)})
 	go func() {
+		defer op.Finish()
+		
 		// Convert config.Server slice to tmux.Server interface slice
-		tmuxServers := make([]tmux.Server, len(servers))
+		connectServers := make([]config.Server, len(servers))
 		for i, server := range servers {
-			tmuxServers[i] = &server
+			connectServers[i] = profile.WithLogin(profile.WithSSHTemplate(profile.WithBootstrap(server)))
+		}
+		
+		// New windows may meet hosts that changed their keys since the last connection
+		if !t.tmuxManager.ProfileSessionExists(profileName) && !t.confirmHostIdentity(connectServers) {
+			return
 		}
+		tmuxServers, manual := connection.GroupServers(connectServers, collected, acks)
 		
-		sessionName, wasExisting, err := t.tmuxManager.ConnectToProfile(t.currentFilter, tmuxServers)
+		sessionName, wasExisting, err := t.tmuxManager.ConnectToProfile(profileName, tmuxServers)
 		if err != nil {
 			t.app.QueueUpdateDraw(func() {
 				t.showErrorModal(fmt.Sprintf("Failed to create group session: %s", err.Error()))
 			})
 			return
 		}
+		connection.GuardPastes(t.tmuxManager, sessionName, servers)
+		connection.RecordSession(t.tmuxManager, sessionName, servers)
+		t.events.Emit(events.Event{Type: events.ConnectionStarted, Profile: profile.Name, Session: sessionName})
 		
 		// Group session created successfully - show success message and stay in TUI
 		t.app.QueueUpdateDraw(func() {
@@ -1924,6 +1891,9 @@ func (t *TUIApp) connectToCurrentProfile() {
 					statusMsg += fmt.Sprintf("   • Window %d: %s (%s@%s:%d)\n", 
 						i+1, server.Name, server.Username, server.Hostname, server.Port)
 				}
+				if len(manual) > 0 {
+					statusMsg += fmt.Sprintf("\n🔑 Type the password or passphrase in the window(s) of: %s\n", strings.Join(manual, ", "))
+				}
 				statusMsg += "\n💡 Switch to Sessions tab (press 's') and press Enter on the session to attach."
 			}
 			
@@ -1941,7 +1911,7 @@ func (t *TUIApp) connectToCurrentProfile() {
 					if t.modalManager != nil {
 						t.modalManager.HideModal()
 					} else {
-						t.app.SetRoot(t.layout, true)
+						t.setRoot(t.layout)
 						t.app.SetFocus(t.layout)
 					}
 				}).
@@ -1950,7 +1920,7 @@ func (t *TUIApp) connectToCurrentProfile() {
 			if t.modalManager != nil {
 				t.modalManager.ShowModal(successModal)
 			} else {
-				t.app.SetRoot(successModal, true)
+				t.setRoot(successModal)
 				t.app.SetFocus(successModal)
 			}
 			
@@ -1966,7 +1936,7 @@ func (t *TUIApp) showGroupConnectingModal(profileName string, serverCount int) {
 		SetText(fmt.Sprintf("🚀 Connecting to profile: %s\n\n📊 Creating group session for %d server(s)...\n🔗 Setting up tmux windows...\n⚡ Establishing SSH connections...\n\nPlease wait...", profileName, serverCount)).
 		SetBackgroundColor(tcell.ColorDarkBlue)
 	
-	t.app.SetRoot(modal, true)
+	t.setRoot(modal)
 }
 
 // Profile management action handlers
@@ -1978,7 +1948,7 @@ func (t *TUIApp) createNewProfile() {
 
 // deleteCurrentProfile handles deleting the currently selected profile
 func (t *TUIApp) deleteCurrentProfile() {
-	if t.currentFilter == "" {
+	if t.currentFilter == "" || t.isUnassignedFilter() {
 		t.showErrorModal("No profile selected. Please select a profile first.")
 		return
 	}
@@ -1987,15 +1957,20 @@ func (t *TUIApp) deleteCurrentProfile() {
 
 // editCurrentProfile handles editing the currently selected profile
 func (t *TUIApp) editCurrentProfile() {
-	if t.currentFilter == "" {
+	if t.currentFilter == "" || t.isUnassignedFilter() {
 		t.showErrorModal("No profile selected. Please select a profile first.")
 		return
 	}
 	t.ShowEditProfileModal(t.currentFilter)
 }
 
-// assignServerToProfile handles assigning the selected server to the current profile
+// assignServerToProfile handles assigning the selected server to the current
+// profile; on the Unassigned tab it suggests profiles for all of its servers
 func (t *TUIApp) assignServerToProfile() {
+	if t.isUnassignedFilter() {
+		t.showProfileCleanupWizard()
+		return
+	}
 	if t.currentFilter == "" {
 		t.showErrorModal("No profile selected. Please select a profile first.")
 		return
@@ -2005,7 +1980,7 @@ func (t *TUIApp) assignServerToProfile() {
 
 // unassignServerFromProfile handles unassigning the selected server from the current profile
 func (t *TUIApp) unassignServerFromProfile() {
-	if t.currentFilter == "" {
+	if t.currentFilter == "" || t.isUnassignedFilter() {
 		t.showErrorModal("No profile selected. Please select a profile first.")
 		return
 	}
@@ -2123,37 +2098,52 @@ func (t *TUIApp) SetCurrentFilter(filter string) {
 func (t *TUIApp) getCachedConnectionStatus(serverName string) (string, tcell.Color) {
 	t.statusMutex.RLock()
 	status, exists := t.connectionStatus[serverName]
+	attempts := t.statusAttempts[serverName]
+	latency := t.statusLatency[serverName]
 	t.statusMutex.RUnlock()
 	
 	if !exists {
-		return "checking", tcell.ColorYellow
-	}
-	
-	// Map status strings to colors
-	switch status {
-	case "online":
-		return status, tcell.ColorGreen
-	case "unreachable", "refused", "error":
-		return status, tcell.ColorRed
-	case "auth failed":
-		return status, tcell.ColorOrange
-	case "auth error":
-		return status, tcell.ColorRed
-	case "checking":
-		return status, tcell.ColorYellow
-	default:
-		return "unknown", tcell.ColorGray
+		status = "checking"
+	}
+	
+	return formatStatus(status, attempts, latency, t.config.UI.Status)
+}
+
+// getCachedQuickStats returns the rendered quick stats of a server, or "" if none
+func (t *TUIApp) getCachedQuickStats(serverName string) string {
+	t.statusMutex.RLock()
+	defer t.statusMutex.RUnlock()
+	return t.statusStats[serverName]
+}
+
+// fetchQuickStats collects load, disk and memory usage with a single SSH command
+func (t *TUIApp) fetchQuickStats(server config.Server) string {
+	auth, err := t.getAuthMethod(server)
+	if err != nil {
+		return ""
+	}
+	stats, err := sshmssh.FetchQuickStats(sshmssh.ClientConfig{
+		Hostname: server.GetEffectiveHostname(),
+		Port:     server.Port,
+		Username: server.Username,
+		Timeout:  5 * time.Second,
+	}, auth)
+	if err != nil {
+		return "stats unavailable"
 	}
+	return stats.String()
 }
 
-// startConnectionStatusMonitoring starts background monitoring of connection status
+// startConnectionStatusMonitoring starts background monitoring of connection
+// status. Each server is checked on its own jittered schedule rather than all of
+// them every 30 seconds at once.
 func (t *TUIApp) startConnectionStatusMonitoring() {
+	if t.isOffline() {
+		t.markServersOffline()
+	}
+	
 	go func() {
-		// Initial status check for all servers
-		t.updateAllConnectionStatus()
-		
-		// Set up periodic updates every 30 seconds
-		ticker := time.NewTicker(30 * time.Second)
+		ticker := time.NewTicker(scheduleTick)
 		defer ticker.Stop()
 		
 		for {
@@ -2161,21 +2151,41 @@ func (t *TUIApp) startConnectionStatusMonitoring() {
 			case <-t.stopChan:
 				return
 			case <-ticker.C:
-				if t.running {
-					t.updateAllConnectionStatus()
+				if !t.running || t.isOffline() {
+					continue
+				}
+				if due := t.dueServers(time.Now()); len(due) > 0 {
+					t.updateConnectionStatus(due)
 				}
 			}
 		}
 	}()
 }
 
-// updateAllConnectionStatus updates connection status for all servers
+// updateAllConnectionStatus updates connection status for all servers at once,
+// as asked for with the refresh key
 func (t *TUIApp) updateAllConnectionStatus() {
-	servers := t.config.GetServers()
+	if t.isOffline() {
+		t.markServersOffline()
+		if t.running && t.app != nil {
+			t.app.QueueUpdateDraw(func() {
+				t.refreshServerList()
+			})
+		}
+		return
+	}
 	
-	// First, mark all servers as "checking" to show activity
+	t.updateConnectionStatus(t.checkableServers(time.Now()))
+}
+
+// updateConnectionStatus checks the given servers, at most 5 at a time and no
+// faster than the configured rate limit
+func (t *TUIApp) updateConnectionStatus(servers []config.Server) {
+	// First, mark the servers as "checking" to show activity
 	t.statusMutex.Lock()
+	previous := make(map[string]string, len(servers))
 	for _, server := range servers {
+		previous[server.Name] = t.connectionStatus[server.Name]
 		t.connectionStatus[server.Name] = "checking"
 	}
 	t.statusMutex.Unlock()
@@ -2187,6 +2197,14 @@ func (t *TUIApp) updateAllConnectionStatus() {
 		})
 	}
 	
+	// Shown in the tasks overlay; quitting may interrupt it without asking
+	tk, ctx := t.tasks.start(taskSpec{Name: "status check", Unit: "servers", Total: len(servers), Cancellable: true, SafeToInterrupt: true})
+	defer tk.Finish()
+	var checked int32
+	
+	// A running daemon calls the webhooks so each transition is announced once
+	daemonOwnsWebhooks := t.daemonRunning()
+	
 	// Update connection status in parallel for better performance
 	var wg sync.WaitGroup
 	semaphore := make(chan struct{}, 5) // Limit to 5 concurrent checks
@@ -2198,13 +2216,48 @@ func (t *TUIApp) updateAllConnectionStatus() {
 			semaphore <- struct{}{} // Acquire semaphore
 			defer func() { <-semaphore }() // Release semaphore
 			
-			status := t.checkSingleConnectionStatus(srv)
+			// Going offline mid-check leaves the remaining servers alone
+			if t.isOffline() {
+				return
+			}
+			
+			// Cancelled checks leave the server without a known status
+			if t.statusLimiter.Wait(ctx) != nil {
+				t.statusMutex.Lock()
+				t.connectionStatus[srv.Name] = "unknown"
+				t.statusMutex.Unlock()
+				return
+			}
+			
+			status, attempts, latency := t.checkSingleConnectionStatus(srv)
+			tk.Update(int(atomic.AddInt32(&checked, 1)), len(servers), srv.Name)
+			
+			// Online servers in opted-in profiles also report load, disk and memory
+			stats := ""
+			if status == "online" && t.config.QuickStatsEnabled(srv.Name) && !srv.IsRestricted() && !t.isStealth(srv) {
+				stats = t.fetchQuickStats(srv)
+			}
 			
 			// Update cache
 			t.statusMutex.Lock()
 			t.connectionStatus[srv.Name] = status
+			t.statusAttempts[srv.Name] = attempts
+			t.statusLatency[srv.Name] = latency
+			t.statusStats[srv.Name] = stats
+			t.recordHealth(srv, status, time.Now())
 			t.statusMutex.Unlock()
 			
+			if status != previous[srv.Name] {
+				t.events.Emit(events.Event{Type: events.StatusChanged, Server: srv.Name, Status: status, Previous: previous[srv.Name]})
+			}
+			
+			// Automations hooked to the server hear about it coming back or going away
+			if t.webhooks != nil && daemonOwnsWebhooks {
+				t.webhooks.Record(srv, status)
+			} else if t.webhooks != nil {
+				t.webhooks.Observe(srv, status, time.Now())
+			}
+			
 			// Trigger UI update
 			if t.running && t.app != nil {
 				t.app.QueueUpdateDraw(func() {
@@ -2215,99 +2268,86 @@ func (t *TUIApp) updateAllConnectionStatus() {
 	}
 	
 	wg.Wait()
+	
+	// Share the results with cheap readers such as 'sshm statusline'
+	t.saveStatusCache()
+	t.recordStatusSamples(servers)
 }
 
-// checkSingleConnectionStatus checks the connection status of a single server
-func (t *TUIApp) checkSingleConnectionStatus(server config.Server) string {
-	// Create SSH client configuration
-	clientConfig := sshmssh.ClientConfig{
-		Hostname: server.Hostname,
-		Port:     server.Port,
-		Username: server.Username,
-		Timeout:  5 * time.Second, // 5 second timeout for connection test
+// recordStatusSamples keeps the results of completed checks in the history
+// database, where the stats view computes availability from them
+func (t *TUIApp) recordStatusSamples(servers []config.Server) {
+	if t.connectionManager == nil || t.connectionManager.GetHistoryManager() == nil {
+		return
 	}
 	
-	// Get authentication method based on server config
-	auth, err := t.getAuthMethod(server)
-	if err != nil {
-		return "auth error"
-	}
-	
-	// Test the connection
-	if err := sshmssh.TestConnection(clientConfig, auth); err != nil {
-		// Connection failed - determine specific error type
-		if strings.Contains(err.Error(), "timeout") || strings.Contains(err.Error(), "no route") {
-			return "unreachable"
-		} else if strings.Contains(err.Error(), "authentication") || strings.Contains(err.Error(), "permission denied") {
-			return "auth failed"
-		} else if strings.Contains(err.Error(), "connection refused") {
-			return "refused"
-		} else {
-			return "error"
+	now := time.Now()
+	var samples []history.StatusSample
+	t.statusMutex.RLock()
+	for _, server := range servers {
+		switch status := t.connectionStatus[server.Name]; status {
+		case "", "checking", "unknown", offlineStatus, statusScheduled:
+		default:
+			samples = append(samples, history.StatusSample{ServerName: server.Name, Status: status, Latency: t.statusLatency[server.Name], CheckedAt: now})
 		}
 	}
+	t.statusMutex.RUnlock()
 	
-	// Connection successful
-	return "online"
+	// Like the status cache, samples are a convenience; failing to store them is not an error
+	t.connectionManager.GetHistoryManager().RecordStatusSamples(samples)
+}
+
+// saveStatusCache writes the cached connection statuses to the status cache file
+func (t *TUIApp) saveStatusCache() {
+	path, err := statuscache.DefaultPath()
+	if err != nil {
+		return
+	}
+	
+	cache := &statuscache.Cache{Servers: make(map[string]statuscache.Entry)}
+	now := time.Now()
+	t.statusMutex.RLock()
+	for name, status := range t.connectionStatus {
+		address, _ := monitor.LastAddress(name)
+		cache.Servers[name] = statuscache.Entry{Status: status, Latency: t.statusLatency[name], CheckedAt: now, Address: address}
+	}
+	t.statusMutex.RUnlock()
+	
+	// The daemon's mark is kept so it goes on owning the webhooks
+	if existing, err := statuscache.Load(path); err == nil {
+		cache.DaemonAt, cache.DaemonInterval = existing.DaemonAt, existing.DaemonInterval
+	}
+	
+	// The cache is a convenience for other tools; failing to write it is not an error for the TUI
+	cache.Save(path)
+}
+
+// daemonRunning reports whether 'sshm daemon' is checking servers, according
+// to the status cache it writes
+func (t *TUIApp) daemonRunning() bool {
+	path, err := statuscache.DefaultPath()
+	if err != nil {
+		return false
+	}
+	cache, err := statuscache.Load(path)
+	return err == nil && cache.DaemonRunning()
+}
+
+// checkSingleConnectionStatus checks the connection status of a single server,
+// retrying according to its retry policy, and returns the status with the attempts
+// used and the duration of the successful check. Servers in stealth profiles only
+// get a TCP connect.
+func (t *TUIApp) checkSingleConnectionStatus(server config.Server) (string, int, time.Duration) {
+	if t.isStealth(server) {
+		status, latency := monitor.CheckTCP(server)
+		return status, 1, latency
+	}
+	return monitor.CheckServer(server, t.config.RetryPolicyFor(server))
 }
 
 // getAuthMethod creates an SSH authentication method for the given server
 func (t *TUIApp) getAuthMethod(server config.Server) (ssh.AuthMethod, error) {
-	switch server.AuthType {
-	case "key":
-		if server.KeyPath == "" {
-			return nil, fmt.Errorf("key path is required for key authentication")
-		}
-		
-		// For TUI mode, we don't want interactive password prompts
-		// If the key is passphrase-protected, we'll need to handle this differently
-		// For now, try without passphrase and fallback to agent
-		auth, err := sshmssh.NewKeyAuth(server.KeyPath, "")
-		if err != nil {
-			// Try SSH agent as fallback
-			if agentAuth, agentErr := sshmssh.NewAgentAuth(); agentErr == nil {
-				return agentAuth, nil
-			}
-			return nil, fmt.Errorf("failed to load key and no SSH agent available: %w", err)
-		}
-		return auth, nil
-		
-	case "password":
-		// For TUI mode, we can't prompt for password interactively
-		// Return error - password auth needs to be handled differently in TUI
-		return nil, fmt.Errorf("password authentication not supported in status check")
-		
-	case "agent":
-		return sshmssh.NewAgentAuth()
-		
-	default:
-		// Try agent first, then look for default key
-		if agentAuth, err := sshmssh.NewAgentAuth(); err == nil {
-			return agentAuth, nil
-		}
-		
-		// Try default key locations
-		homeDir, err := os.UserHomeDir()
-		if err != nil {
-			return nil, fmt.Errorf("no authentication method available")
-		}
-		
-		defaultKeys := []string{
-			filepath.Join(homeDir, ".ssh", "id_rsa"),
-			filepath.Join(homeDir, ".ssh", "id_ed25519"),
-			filepath.Join(homeDir, ".ssh", "id_ecdsa"),
-		}
-		
-		for _, keyPath := range defaultKeys {
-			if _, err := os.Stat(keyPath); err == nil {
-				if auth, err := sshmssh.NewKeyAuth(keyPath, ""); err == nil {
-					return auth, nil
-				}
-			}
-		}
-		
-		return nil, fmt.Errorf("no valid authentication method found")
-	}
+	return monitor.AuthMethod(server)
 }
 
 // showSearchInput shows a modal with input field for server name filtering
@@ -2386,6 +2426,6 @@ func (t *TUIApp) showHistoryDashboard() {
 	t.app.SetInputCapture(nil)
 	
 	// Show the dashboard
-	t.app.SetRoot(dashboardLayout, true)
+	t.setRoot(dashboardLayout)
 	t.app.SetFocus(dashboardLayout)
 }
\ No newline at end of file
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"sshm/internal/config"
+	"sshm/internal/tmux"
 )
 
 func TestTUIApplication_NewApp(t *testing.T) {
@@ -2674,35 +2675,20 @@ func TestEnhancedSessionMonitoring_EnhancedDetails(t *testing.T) {
 		t.Fatalf("Failed to create TUI app: %v", err)
 	}
 
-	// Test enhanced session details methods don't panic
-	sessionName := "test-session"
-
+	// Test session refresh through the tmux manager doesn't panic
 	defer func() {
 		if r := recover(); r != nil {
 			t.Errorf("Enhanced session details panicked: %v", r)
 		}
 	}()
 
-	// Test enhanced session info gathering
-	_, err = app.getEnhancedDetailedSessionInfo(sessionName)
-	if err != nil {
-		t.Logf("Enhanced session info returned error (expected in test environment): %v", err)
-	}
-
-	// Test individual enhanced methods
-	_, err = app.getEnhancedSessionWindowCount(sessionName)
-	if err != nil {
-		t.Logf("Enhanced window count returned error (expected in test environment): %v", err)
-	}
-
-	_, err = app.getEnhancedSessionStatus(sessionName)
-	if err != nil {
-		t.Logf("Enhanced session status returned error (expected in test environment): %v", err)
+	if err := app.refreshSessions(); err != nil {
+		t.Errorf("Session refresh should not fail: %v", err)
 	}
-
-	_, err = app.getEnhancedSessionActivity(sessionName)
-	if err != nil {
-		t.Logf("Enhanced session activity returned error (expected in test environment): %v", err)
+	for _, session := range app.sessions {
+		if session.Name == "" || session.Status == "" {
+			t.Errorf("Expected refreshed sessions to have a name and status, got %+v", session)
+		}
 	}
 }
 
@@ -2761,6 +2747,47 @@ func TestEnhancedSessionMonitoring_RealTimeUpdates(t *testing.T) {
 	}
 }
 
+// TestEnhancedSessionMonitoring_ConnectionLost tests that dropped connections stand out
+func TestEnhancedSessionMonitoring_ConnectionLost(t *testing.T) {
+	tempDir := t.TempDir()
+	os.Setenv("SSHM_CONFIG_DIR", tempDir)
+	defer os.Unsetenv("SSHM_CONFIG_DIR")
+
+	app, err := NewTUIApp()
+	if err != nil {
+		t.Fatalf("Failed to create TUI app: %v", err)
+	}
+	if app.sessionPanel == nil {
+		t.Skip("Session panel not initialized")
+	}
+
+	sessions := []SessionInfo{
+		{Name: "healthy", Status: "detached", Windows: 1, LastActivity: "just now"},
+		{Name: "dropped", Status: sessionConnectionLost, Windows: 2, LastActivity: "5m ago", LostWindows: []string{"db"}},
+	}
+	app.sessions = sessions
+	app.updateSessionDisplay(sessions)
+
+	healthy := app.sessionPanel.GetCell(1, 1)
+	dropped := app.sessionPanel.GetCell(2, 1)
+	if dropped.Text != sessionConnectionLost {
+		t.Errorf("Expected status %q, got %q", sessionConnectionLost, dropped.Text)
+	}
+	healthyColor, _, _ := healthy.Style.Decompose()
+	droppedColor, _, _ := dropped.Style.Decompose()
+	if droppedColor == healthyColor {
+		t.Error("Expected a lost connection to be shown in a different color than a healthy session")
+	}
+
+	// Attaching to a dropped session asks to reconnect first
+	app.focusedPanel = "sessions"
+	app.sessionPanel.Select(2, 0)
+	app.attachToSelectedSession()
+	if !app.modalManager.IsModalActive() {
+		t.Error("Expected the reconnect prompt before attaching to a session with a lost connection")
+	}
+}
+
 // TestEnhancedSessionMonitoring_ErrorHandling tests error handling in enhanced monitoring
 func TestEnhancedSessionMonitoring_ErrorHandling(t *testing.T) {
 	// Create a temporary directory for test config
@@ -2781,18 +2808,6 @@ func TestEnhancedSessionMonitoring_ErrorHandling(t *testing.T) {
 		}
 	}()
 
-	// Test with empty session names
-	_, err = app.getEnhancedSessionDetails([]string{""})
-	if err != nil {
-		t.Logf("Enhanced session details handled empty session name: %v", err)
-	}
-
-	// Test with invalid session names
-	_, err = app.getEnhancedSessionDetails([]string{"invalid-session-name"})
-	if err != nil {
-		t.Logf("Enhanced session details handled invalid session: %v", err)
-	}
-
 	// Test session refresh with no tmux
 	err = app.refreshSessions()
 	if err != nil {
@@ -2918,8 +2933,8 @@ func TestSessionCleanup_OrphanDetection(t *testing.T) {
 		},
 	}
 
-	// Note: The actual orphan detection would call getEnhancedDetailedSessionInfo,
-	// which relies on tmux commands that return errors in test environment.
+	// Note: The actual orphan detection reads the session state from tmux,
+	// which returns errors in test environment.
 	// So we test the logic indirectly by testing performSessionCleanup which uses it.
 	
 	defer func() {
@@ -2942,6 +2957,28 @@ func TestSessionCleanup_OrphanDetection(t *testing.T) {
 	}
 }
 
+// TestSessionCleanup_OrphanRule tests which session states count as orphaned
+func TestSessionCleanup_OrphanRule(t *testing.T) {
+	now := time.Now()
+	testCases := []struct {
+		state        tmux.SessionState
+		expectOrphan bool
+		description  string
+	}{
+		{tmux.SessionState{Name: "empty", Windows: 0, Activity: now}, true, "session without windows"},
+		{tmux.SessionState{Name: "idle", Windows: 1, Activity: now.Add(-72 * time.Hour)}, true, "detached session idle for days"},
+		{tmux.SessionState{Name: "attached", Windows: 1, Clients: 1, Activity: now.Add(-72 * time.Hour)}, false, "attached session idle for days"},
+		{tmux.SessionState{Name: "recent", Windows: 2, Activity: now.Add(-time.Hour)}, false, "recently active session"},
+		{tmux.SessionState{Name: "unknown", Windows: 1}, false, "session with unknown activity"},
+	}
+
+	for _, tc := range testCases {
+		if got := sessionOrphaned(tc.state, now); got != tc.expectOrphan {
+			t.Errorf("%s: sessionOrphaned() = %v, want %v", tc.description, got, tc.expectOrphan)
+		}
+	}
+}
+
 // TestSessionCleanup_PerformCleanup tests the actual cleanup performance
 func TestSessionCleanup_PerformCleanup(t *testing.T) {
 	// Create a temporary directory for test config
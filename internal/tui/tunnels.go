@@ -0,0 +1,358 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"sshm/internal/config"
+	"sshm/internal/tunnel"
+)
+
+// startSelectedServerTunnels starts every tunnel configured on the selected server
+func (t *TUIApp) startSelectedServerTunnels() {
+	if t.focusedPanel != "servers" {
+		return
+	}
+
+	serverName := t.getSelectedServerName()
+	if serverName == "" {
+		return
+	}
+
+	server, err := t.config.GetServer(serverName)
+	if err != nil {
+		t.showErrorModal(fmt.Sprintf("Server '%s' not found: %s", serverName, err.Error()))
+		return
+	}
+	if len(server.Tunnels) == 0 {
+		t.showErrorModal(fmt.Sprintf("Server '%s' has no tunnels configured.\n\nUse 'sshm tunnel add %s' to add one.", server.Name, server.Name))
+		return
+	}
+
+	t.startTunnels(*server, server.Tunnels, nil)
+}
+
+// startTunnels starts the given tunnels one by one. When a local port is taken the
+// user is asked whether a free port should be chosen instead.
+func (t *TUIApp) startTunnels(server config.Server, specs []config.Tunnel, results []string) {
+	if len(specs) == 0 {
+		t.showTunnelStartResults(server, results)
+		return
+	}
+
+	spec, remaining := specs[0], specs[1:]
+	started, err := t.tunnelManager.Start(server, spec, false)
+
+	var conflict *tunnel.PortConflictError
+	if errors.As(err, &conflict) {
+		modal := tview.NewModal().
+			SetText(fmt.Sprintf("⚠️ Tunnel '%s'\n\n%s.\n\nUse a free local port instead?", spec.Name, conflict.Error())).
+			AddButtons([]string{"Use free port", "Skip"}).
+			SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+				t.modalManager.HideModal()
+				if buttonLabel != "Use free port" {
+					t.startTunnels(server, remaining, append(results, fmt.Sprintf("[gray]%s: skipped (port %d in use)[white]", spec.Name, conflict.Port)))
+					return
+				}
+				started, err := t.tunnelManager.Start(server, spec, true)
+				t.startTunnels(server, remaining, append(results, describeTunnelStart(spec, started, err)))
+			}).
+			SetBackgroundColor(tcell.ColorDarkGoldenrod)
+		t.modalManager.ShowModal(modal)
+		return
+	}
+
+	t.startTunnels(server, remaining, append(results, describeTunnelStart(spec, started, err)))
+}
+
+// showTunnelStartResults summarizes the outcome of starting a server's tunnels
+func (t *TUIApp) showTunnelStartResults(server config.Server, results []string) {
+	text := fmt.Sprintf("[yellow]Tunnels for %s[white]\n\n%s\n\n[gray]Press t to view all tunnels. Press Enter, Escape or q to close[white]",
+		server.Name, strings.Join(results, "\n"))
+	t.showTextPanel("Tunnels", text)
+}
+
+// describeTunnelStart formats a tunnel start result, including the port actually bound
+func describeTunnelStart(spec config.Tunnel, started *tunnel.Tunnel, err error) string {
+	if err != nil {
+		return fmt.Sprintf("[red]%s: %s[white]", spec.Name, err.Error())
+	}
+	line := fmt.Sprintf("[green]%s[white]: localhost:%d → %s", spec.Name, started.LocalPort, started.Destination())
+	if started.ConfiguredPort != 0 && started.LocalPort != started.ConfiguredPort {
+		line += fmt.Sprintf(" [yellow](port %d was taken)[white]", started.ConfiguredPort)
+	}
+	return line
+}
+
+// openSelectedServerService opens the selected server's forwarded web service in the
+// system browser, starting its tunnel first when it isn't running
+func (t *TUIApp) openSelectedServerService() {
+	if t.focusedPanel != "servers" {
+		return
+	}
+
+	serverName := t.getSelectedServerName()
+	if serverName == "" {
+		return
+	}
+
+	server, err := t.config.GetServer(serverName)
+	if err != nil {
+		t.showErrorModal(fmt.Sprintf("Server '%s' not found: %s", serverName, err.Error()))
+		return
+	}
+	if len(server.Tunnels) == 0 {
+		t.showErrorModal(fmt.Sprintf("Server '%s' has no tunnels configured.\n\nUse 'sshm tunnel add %s' to add one.", server.Name, server.Name))
+		return
+	}
+
+	// Prefer a tunnel with an explicit URL template, it's most likely the web service;
+	// SOCKS proxies have nothing to open otherwise
+	spec := server.Tunnels[0]
+	for _, candidate := range server.Tunnels {
+		if candidate.URL != "" {
+			spec = candidate
+			break
+		}
+		if spec.Dynamic && !candidate.Dynamic {
+			spec = candidate
+		}
+	}
+
+	key := tunnel.Key(server.Name, spec.Name)
+	if !t.tunnelManager.IsRunning(key) {
+		// The user asked to open the service, so don't stop to ask about busy ports
+		if _, err := t.tunnelManager.Start(*server, spec, true); err != nil {
+			t.showErrorModal(fmt.Sprintf("Failed to start tunnel '%s': %s", spec.Name, err.Error()))
+			return
+		}
+	}
+
+	if err := t.openTunnelInBrowser(key); err != nil {
+		t.showErrorModal(err.Error())
+	}
+}
+
+// openTunnelInBrowser opens a running tunnel's URL in the system browser
+func (t *TUIApp) openTunnelInBrowser(key string) error {
+	tun, ok := t.tunnelManager.Get(key)
+	if !ok {
+		return fmt.Errorf("tunnel %s not found", key)
+	}
+	if tun.URL == "" {
+		return fmt.Errorf("tunnel %s is a SOCKS proxy; configure the browser to use localhost:%d (Ctrl+S on its server shows how)", key, tun.LocalPort)
+	}
+	if err := tunnel.OpenURL(tun.URL); err != nil {
+		return err
+	}
+	t.showTransientStatus(fmt.Sprintf("[green]🌐 Opened %s[white]", tun.URL))
+	return nil
+}
+
+// startTunnelMonitoring periodically checks tunnel health, restarts dead tunnels
+// and refreshes the Tunnels panel while it is visible
+func (t *TUIApp) startTunnelMonitoring() {
+	if t.tunnelManager == nil {
+		return
+	}
+
+	t.tunnelMonitorStop = make(chan struct{})
+	stop := t.tunnelMonitorStop
+
+	go func() {
+		ticker := time.NewTicker(tunnel.DefaultCheckInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				t.tunnelManager.CheckHealth()
+				t.app.QueueUpdateDraw(func() {
+					if t.isTunnelsPanelVisible() {
+						t.refreshTunnelsTable()
+					}
+					t.refreshSOCKSIndicator()
+				})
+			}
+		}
+	}()
+}
+
+// stopTunnelMonitoring stops health checks and closes all tunnels
+func (t *TUIApp) stopTunnelMonitoring() {
+	if t.tunnelMonitorStop != nil {
+		close(t.tunnelMonitorStop)
+		t.tunnelMonitorStop = nil
+	}
+	if t.tunnelManager != nil {
+		t.tunnelManager.StopAll()
+	}
+}
+
+// showTunnels opens the Tunnels panel listing tunnels started from the TUI
+func (t *TUIApp) showTunnels() {
+	t.tunnelsTable = tview.NewTable().
+		SetBorders(false).
+		SetSelectable(true, false).
+		SetFixed(1, 0)
+
+	footer := tview.NewTextView().
+		SetDynamicColors(true).
+		SetText("[yellow]o[white]: Open in browser  [yellow]r[white]: Restart  [yellow]x[white]: Stop  [yellow]Esc/q[white]: Close")
+
+	t.tunnelsPanel = tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(t.tunnelsTable, 0, 1, true).
+		AddItem(footer, 1, 0, false)
+	t.tunnelsPanel.SetBorder(true).
+		SetTitle(" Tunnels ").
+		SetBorderColor(tcell.ColorYellow)
+
+	t.tunnelsTable.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape {
+			t.hideTunnels()
+			return nil
+		}
+		switch event.Rune() {
+		case 'q', 'Q':
+			t.hideTunnels()
+			return nil
+		case 'r', 'R':
+			t.actOnSelectedTunnel(t.tunnelManager.Restart)
+			return nil
+		case 'x', 'X':
+			t.actOnSelectedTunnel(t.tunnelManager.Stop)
+			return nil
+		case 'o', 'O':
+			t.actOnSelectedTunnel(t.openTunnelInBrowser)
+			return nil
+		}
+		return event
+	})
+
+	t.refreshTunnelsTable()
+
+	if t.modalManager != nil {
+		t.modalManager.ShowModal(t.tunnelsPanel)
+	} else {
+		t.setRoot(t.tunnelsPanel)
+		t.app.SetFocus(t.tunnelsPanel)
+	}
+}
+
+// hideTunnels closes the Tunnels panel
+func (t *TUIApp) hideTunnels() {
+	t.tunnelsPanel = nil
+	t.tunnelsTable = nil
+	t.hideTextPanel()
+}
+
+// isTunnelsPanelVisible reports whether the Tunnels panel is the active modal
+func (t *TUIApp) isTunnelsPanelVisible() bool {
+	return t.tunnelsPanel != nil && t.modalManager != nil && t.modalManager.GetCurrentModal() == t.tunnelsPanel
+}
+
+// actOnSelectedTunnel applies a manager action to the selected tunnel and refreshes the panel
+func (t *TUIApp) actOnSelectedTunnel(action func(key string) error) {
+	row, _ := t.tunnelsTable.GetSelection()
+	cell := t.tunnelsTable.GetCell(row, 0)
+	if row <= 0 || cell == nil || cell.GetReference() == nil {
+		return
+	}
+
+	if err := action(cell.GetReference().(string)); err != nil {
+		t.showErrorModal(err.Error())
+		return
+	}
+	t.refreshTunnelsTable()
+}
+
+// refreshTunnelsTable redraws the Tunnels panel from the tunnel manager's state
+func (t *TUIApp) refreshTunnelsTable() {
+	if t.tunnelsTable == nil {
+		return
+	}
+
+	selected, _ := t.tunnelsTable.GetSelection()
+	t.tunnelsTable.Clear()
+
+	headers := []string{"Tunnel", "State", "Port", "Destination", "Restarts", "Last Check", "Details"}
+	for col, header := range headers {
+		t.tunnelsTable.SetCell(0, col, tview.NewTableCell(header).
+			SetTextColor(tcell.ColorYellow).
+			SetSelectable(false).
+			SetExpansion(1))
+	}
+
+	tunnels := t.tunnelManager.List()
+	for i, tun := range tunnels {
+		row := i + 1
+
+		port := fmt.Sprintf("%d", tun.LocalPort)
+		if tun.ConfiguredPort != 0 && tun.LocalPort != tun.ConfiguredPort {
+			port += fmt.Sprintf(" (configured %d)", tun.ConfiguredPort)
+		}
+
+		lastCheck := "-"
+		if !tun.LastCheck.IsZero() {
+			lastCheck = tun.LastCheck.Format("15:04:05")
+		}
+
+		details := tun.Error
+		if tun.State == tunnel.StateDown && !tun.NextRestart.IsZero() {
+			details = fmt.Sprintf("restarting at %s: %s", tun.NextRestart.Format("15:04:05"), tun.Error)
+		}
+
+		t.tunnelsTable.SetCell(row, 0, tview.NewTableCell(tun.Key()).SetReference(tun.Key()).SetExpansion(1))
+		t.tunnelsTable.SetCell(row, 1, tview.NewTableCell(tun.State).SetTextColor(tunnelStateColor(tun.State)).SetExpansion(1))
+		t.tunnelsTable.SetCell(row, 2, tview.NewTableCell(port).SetExpansion(1))
+		t.tunnelsTable.SetCell(row, 3, tview.NewTableCell(tun.Destination()).SetExpansion(1))
+		t.tunnelsTable.SetCell(row, 4, tview.NewTableCell(fmt.Sprintf("%d", tun.Restarts)).SetExpansion(1))
+		t.tunnelsTable.SetCell(row, 5, tview.NewTableCell(lastCheck).SetExpansion(1))
+		t.tunnelsTable.SetCell(row, 6, tview.NewTableCell(details).SetTextColor(tcell.ColorGray).SetExpansion(2))
+	}
+
+	row := len(tunnels) + 1
+	if len(tunnels) == 0 {
+		t.tunnelsTable.SetCell(row, 0, tview.NewTableCell("No tunnels running. Select a server and press f to start its tunnels.").
+			SetTextColor(tcell.ColorGray).
+			SetSelectable(false))
+		row++
+	}
+
+	for _, conflict := range t.config.FindLocalPortConflicts() {
+		t.tunnelsTable.SetCell(row, 0, tview.NewTableCell(fmt.Sprintf("Port %d is configured for %s", conflict.Port, strings.Join(conflict.Tunnels, ", "))).
+			SetTextColor(tcell.ColorRed).
+			SetSelectable(false))
+		row++
+	}
+
+	if selected < 1 {
+		selected = 1
+	}
+	if selected > len(tunnels) {
+		selected = len(tunnels)
+	}
+	if selected >= 1 {
+		t.tunnelsTable.Select(selected, 0)
+	}
+}
+
+// tunnelStateColor returns the display color for a tunnel state
+func tunnelStateColor(state string) tcell.Color {
+	switch state {
+	case tunnel.StateUp:
+		return tcell.ColorGreen
+	case tunnel.StateDegraded:
+		return tcell.ColorYellow
+	case tunnel.StateDown:
+		return tcell.ColorRed
+	default:
+		return tcell.ColorGray
+	}
+}
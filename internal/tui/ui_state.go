@@ -0,0 +1,181 @@
+package tui
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"sshm/internal/config"
+)
+
+// uiStateFileName stores the TUI state between runs, next to config.yaml
+const uiStateFileName = "ui-state.json"
+
+// Server list width limits, in percent of the screen
+const (
+	defaultServerPanelWidth = 60
+	minServerPanelWidth     = 30
+	maxServerPanelWidth     = 85
+	serverPanelWidthStep    = 5
+)
+
+// uiState is what the TUI restores at startup so a restart doesn't lose the user's place
+type uiState struct {
+	Profile          string   `json:"profile,omitempty"`
+	Search           string   `json:"search,omitempty"`
+	SelectedServer   string   `json:"selected_server,omitempty"`
+	FocusedPanel     string   `json:"focused_panel,omitempty"`
+	ViewMode         string   `json:"view_mode,omitempty"`
+	CollapsedGroups  []string `json:"collapsed_groups,omitempty"`
+	ServerPanelWidth int      `json:"server_panel_width,omitempty"`
+
+	TailPaths map[string][]string `json:"tail_paths,omitempty"`
+}
+
+// uiStatePath returns the state file path next to the configuration file
+func uiStatePath() (string, error) {
+	configPath, err := config.DefaultConfigPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(configPath), uiStateFileName), nil
+}
+
+// loadUIState reads the saved state; a missing file yields the zero state
+func loadUIState(path string) (uiState, error) {
+	var state uiState
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return state, fmt.Errorf("failed to read UI state: %w", err)
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return uiState{}, fmt.Errorf("failed to parse UI state: %w", err)
+	}
+	return state, nil
+}
+
+// save writes the state file
+func (s uiState) save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode UI state: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write UI state: %w", err)
+	}
+	return nil
+}
+
+// captureUIState records the current profile tab, search, selection, focus and layout
+func (t *TUIApp) captureUIState() uiState {
+	state := uiState{
+		Profile:          t.currentFilter,
+		Search:           t.searchFilter,
+		SelectedServer:   t.getSelectedServerName(),
+		FocusedPanel:     t.focusedPanel,
+		ViewMode:         t.viewMode,
+		ServerPanelWidth: t.serverPanelWidth,
+		TailPaths:        t.tailPaths,
+	}
+	for group, collapsed := range t.collapsedGroups {
+		if collapsed {
+			state.CollapsedGroups = append(state.CollapsedGroups, group)
+		}
+	}
+	sort.Strings(state.CollapsedGroups)
+	return state
+}
+
+// restoreUIState applies a saved state, ignoring profiles and servers that no longer exist
+func (t *TUIApp) restoreUIState(state uiState) {
+	if state.ServerPanelWidth != 0 {
+		t.resizeServerPanel(state.ServerPanelWidth - t.serverPanelWidth)
+	}
+
+	for _, group := range state.CollapsedGroups {
+		t.collapsedGroups[group] = true
+	}
+	if state.TailPaths != nil {
+		t.tailPaths = state.TailPaths
+	}
+
+	t.selectedProfileIndex = 0
+	for i, tab := range t.profileTabs {
+		if tab == state.Profile {
+			t.selectedProfileIndex = i
+			break
+		}
+	}
+	t.updateFilterFromProfile()
+	t.searchFilter = state.Search
+	t.updateProfileDisplay()
+
+	switch state.ViewMode {
+	case viewModeTreeByProfile, viewModeTreeByTag:
+		t.setServerView(state.ViewMode)
+	default:
+		t.refreshServerList()
+	}
+
+	if state.SelectedServer != "" {
+		t.selectServerRow(state.SelectedServer)
+		if t.isTreeMode() {
+			t.refreshServerList()
+		}
+	}
+
+	if state.FocusedPanel == "sessions" && t.sessionPanel != nil {
+		t.focusedPanel = "sessions"
+		t.updatePanelHighlight()
+	}
+}
+
+// RestoreSavedState restores the state saved when the previous run exited, if any.
+// Call it before Run when starting the TUI fresh.
+func (t *TUIApp) RestoreSavedState() {
+	path, err := uiStatePath()
+	if err != nil {
+		return
+	}
+	if state, err := loadUIState(path); err == nil {
+		t.restoreUIState(state)
+	}
+}
+
+// saveUIState persists the current state for the next run
+func (t *TUIApp) saveUIState() {
+	path, err := uiStatePath()
+	if err != nil {
+		return
+	}
+	t.captureUIState().save(path)
+}
+
+// resizeServerPanel widens (positive delta) or shrinks the server list, in percent
+func (t *TUIApp) resizeServerPanel(delta int) {
+	width := t.serverPanelWidth + delta
+	if width < minServerPanelWidth {
+		width = minServerPanelWidth
+	}
+	if width > maxServerPanelWidth {
+		width = maxServerPanelWidth
+	}
+	t.serverPanelWidth = width
+
+	if t.mainLayout == nil {
+		return
+	}
+	if t.compactLayout {
+		// Narrow terminals only show the server list
+		t.mainLayout.ResizeItem(t.serverPages, 0, 1)
+		t.mainLayout.ResizeItem(t.rightPane, 0, 0)
+		return
+	}
+	t.mainLayout.ResizeItem(t.serverPages, 0, width)
+	t.mainLayout.ResizeItem(t.rightPane, 0, 100-width)
+}
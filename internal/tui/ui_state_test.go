@@ -0,0 +1,91 @@
+package tui
+
+import (
+	"path/filepath"
+	"testing"
+
+	"sshm/internal/config"
+)
+
+func TestUIStateRestore(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Setenv("SSHM_CONFIG_DIR", tempDir)
+
+	cfg := &config.Config{
+		Servers: []config.Server{
+			{Name: "web1", Hostname: "web1.example.com", Port: 22, Username: "deploy", AuthType: "key", KeyPath: "/home/user/.ssh/id_rsa"},
+			{Name: "web2", Hostname: "web2.example.com", Port: 22, Username: "deploy", AuthType: "key", KeyPath: "/home/user/.ssh/id_rsa"},
+			{Name: "db1", Hostname: "db1.example.com", Port: 22, Username: "postgres", AuthType: "key", KeyPath: "/home/user/.ssh/id_rsa"},
+		},
+		Profiles: []config.Profile{{Name: "web", Servers: []string{"web1", "web2"}}},
+	}
+	if err := cfg.SaveToPath(filepath.Join(tempDir, "config.yaml")); err != nil {
+		t.Fatalf("Failed to save config: %v", err)
+	}
+
+	saved := uiState{
+		Profile:          "web",
+		SelectedServer:   "web2",
+		ViewMode:         viewModeTable,
+		ServerPanelWidth: 70,
+		TailPaths:        map[string][]string{"web1": {"/var/log/syslog"}},
+	}
+	path, err := uiStatePath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := saved.save(path); err != nil {
+		t.Fatalf("Failed to save UI state: %v", err)
+	}
+
+	app, err := NewTUIApp()
+	if err != nil {
+		t.Fatalf("Failed to create TUI app: %v", err)
+	}
+	app.RestoreSavedState()
+
+	if app.currentFilter != "web" || app.profileTabs[app.selectedProfileIndex] != "web" {
+		t.Errorf("Expected the 'web' profile tab to be restored, got filter %q", app.currentFilter)
+	}
+	if name := app.getSelectedServerName(); name != "web2" {
+		t.Errorf("Expected web2 to be selected, got %q", name)
+	}
+	if app.serverPanelWidth != 70 {
+		t.Errorf("Expected server panel width 70, got %d", app.serverPanelWidth)
+	}
+
+	captured := app.captureUIState()
+	if paths := captured.TailPaths["web1"]; len(paths) != 1 || paths[0] != "/var/log/syslog" {
+		t.Errorf("Expected the tailed paths of web1 to be restored, got %v", captured.TailPaths)
+	}
+	if captured.Profile != "web" || captured.SelectedServer != "web2" || captured.ServerPanelWidth != 70 {
+		t.Errorf("Unexpected captured state: %+v", captured)
+	}
+}
+
+func TestUIStateIgnoresMissingProfile(t *testing.T) {
+	t.Setenv("SSHM_CONFIG_DIR", t.TempDir())
+
+	app, err := NewTUIApp()
+	if err != nil {
+		t.Fatalf("Failed to create TUI app: %v", err)
+	}
+	app.restoreUIState(uiState{Profile: "gone", SelectedServer: "gone", ServerPanelWidth: 500})
+
+	if app.selectedProfileIndex != 0 || app.currentFilter != "" {
+		t.Errorf("Expected fallback to the All tab, got index %d filter %q", app.selectedProfileIndex, app.currentFilter)
+	}
+	if app.serverPanelWidth != maxServerPanelWidth {
+		t.Errorf("Expected width to be clamped to %d, got %d", maxServerPanelWidth, app.serverPanelWidth)
+	}
+}
+
+func TestLoadUIStateMissingFile(t *testing.T) {
+	state, err := loadUIState(filepath.Join(t.TempDir(), uiStateFileName))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if state.Profile != "" || state.ServerPanelWidth != 0 {
+		t.Errorf("Expected zero state, got %+v", state)
+	}
+}
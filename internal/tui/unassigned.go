@@ -0,0 +1,104 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"sshm/internal/config"
+)
+
+// unassignedTab is the virtual profile tab listing servers that belong to no profile
+const unassignedTab = "Unassigned"
+
+// hasUnassignedTab reports whether the Unassigned tab is shown: only when some
+// profiles exist, some servers are in none of them and no real profile has the name
+func (t *TUIApp) hasUnassignedTab() bool {
+	if len(t.config.GetProfiles()) == 0 || len(t.config.UnassignedServers()) == 0 {
+		return false
+	}
+	_, err := t.config.GetProfile(unassignedTab)
+	return err != nil
+}
+
+// isUnassignedFilter reports whether the Unassigned tab is selected
+func (t *TUIApp) isUnassignedFilter() bool {
+	if t.currentFilter != unassignedTab {
+		return false
+	}
+	_, err := t.config.GetProfile(unassignedTab)
+	return err != nil
+}
+
+// showProfileCleanupWizard suggests a profile for each unassigned server from its
+// tags and hostname and assigns the suggestions the user keeps checked
+func (t *TUIApp) showProfileCleanupWizard() {
+	unassigned := len(t.config.UnassignedServers())
+	suggestions := t.config.SuggestProfileAssignments()
+	if len(suggestions) == 0 {
+		t.showTransientStatus(fmt.Sprintf("[yellow]None of the %d unassigned server(s) resembles a profile; assign them from a profile tab with i[white]", unassigned))
+		return
+	}
+
+	accepted := make([]bool, len(suggestions))
+	form := tview.NewForm()
+	for i, suggestion := range suggestions {
+		i := i
+		accepted[i] = true
+		form.AddCheckbox(fmt.Sprintf("%s → %s", suggestion.Server, suggestion.Profile), true, func(checked bool) {
+			accepted[i] = checked
+		})
+	}
+	form.AddButton("Assign", func() {
+		var chosen []config.ProfileSuggestion
+		for i, suggestion := range suggestions {
+			if accepted[i] {
+				chosen = append(chosen, suggestion)
+			}
+		}
+		assigned, err := t.service().AssignSuggested(chosen)
+		if err != nil {
+			t.showErrorModal(fmt.Sprintf("Failed to assign servers: %s", err.Error()))
+			return
+		}
+
+		t.modalManager.HideModal()
+		t.initializeProfileTabs()
+		t.updateProfileDisplay()
+		t.refreshServerList()
+		t.showTransientStatus(fmt.Sprintf("[green]✓ Assigned %d of %d unassigned server(s)[white]", assigned, unassigned))
+	})
+	form.AddButton("Cancel", func() {
+		t.modalManager.HideModal()
+	})
+	form.SetBorder(true).
+		SetTitle(fmt.Sprintf(" Profile Cleanup (%d of %d unassigned) ", len(suggestions), unassigned)).
+		SetTitleAlign(tview.AlignCenter)
+	form.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape {
+			t.modalManager.HideModal()
+			return nil
+		}
+		return event
+	})
+
+	reasons := tview.NewTextView().SetDynamicColors(true).SetScrollable(true).
+		SetText(renderProfileSuggestions(suggestions))
+	reasons.SetBorder(true).SetTitle(" Why ")
+
+	layout := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(form, 0, 1, true).
+		AddItem(reasons, 0, 1, false)
+	t.modalManager.ShowModal(layout)
+}
+
+// renderProfileSuggestions explains each suggested assignment
+func renderProfileSuggestions(suggestions []config.ProfileSuggestion) string {
+	var b strings.Builder
+	for _, suggestion := range suggestions {
+		fmt.Fprintf(&b, "[yellow]%s[white] → [aqua]%s[white]: %s\n",
+			tview.Escape(suggestion.Server), tview.Escape(suggestion.Profile), tview.Escape(strings.Join(suggestion.Reasons, ", ")))
+	}
+	return b.String()
+}
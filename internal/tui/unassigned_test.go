@@ -0,0 +1,47 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"sshm/internal/config"
+)
+
+func TestUnassignedTab(t *testing.T) {
+	app := &TUIApp{config: &config.Config{
+		Servers:  []config.Server{{Name: "web1"}, {Name: "lab"}},
+		Profiles: []config.Profile{{Name: "web", Servers: []string{"web1"}}},
+	}}
+
+	if !app.hasUnassignedTab() {
+		t.Fatal("Expected the Unassigned tab with a server outside every profile")
+	}
+	app.currentFilter = unassignedTab
+	if !app.isUnassignedFilter() {
+		t.Fatal("Expected the Unassigned filter to be active")
+	}
+	if servers := app.visibleServers(); len(servers) != 1 || servers[0].Name != "lab" {
+		t.Errorf("Expected only lab on the Unassigned tab, got %+v", servers)
+	}
+
+	// A real profile with the name takes precedence
+	app.config.Profiles = append(app.config.Profiles, config.Profile{Name: unassignedTab, Servers: []string{"web1"}})
+	if app.hasUnassignedTab() || app.isUnassignedFilter() {
+		t.Error("Expected no virtual tab when a profile is named Unassigned")
+	}
+
+	// Without profiles every server is unassigned and the tab adds nothing
+	app.config.Profiles = nil
+	if app.hasUnassignedTab() {
+		t.Error("Expected no Unassigned tab without profiles")
+	}
+}
+
+func TestRenderProfileSuggestions(t *testing.T) {
+	text := renderProfileSuggestions([]config.ProfileSuggestion{
+		{Server: "web2", Profile: "web", Reasons: []string{"named like web1", "shares tag '[x]' with web1"}},
+	})
+	if !strings.Contains(text, "named like web1, shares tag '[x[]' with web1") {
+		t.Errorf("Unexpected suggestion rendering: %s", text)
+	}
+}
@@ -0,0 +1,28 @@
+package tui
+
+import "sshm/internal/update"
+
+// startUpdateCheck looks for a newer release in the background and, when one
+// exists, mentions it in the status bar. GitHub is asked at most once a day and
+// never in offline mode.
+func (t *TUIApp) startUpdateCheck() {
+	if t.config.Update.DisableNotice || t.isOffline() {
+		return
+	}
+
+	go func() {
+		statePath, err := update.DefaultCheckPath()
+		if err != nil {
+			return
+		}
+		latest, err := update.AvailableVersion(t.config.Update.Channel, statePath)
+		if err != nil || latest == "" || !t.running {
+			return
+		}
+
+		t.app.QueueUpdateDraw(func() {
+			t.updateNotice = latest
+			t.refreshServerList()
+		})
+	}()
+}
@@ -0,0 +1,64 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rivo/tview"
+)
+
+// ProfileNavigator shows profile tabs in a row, the selected one highlighted
+// and named in the title
+type ProfileNavigator struct {
+	*tview.TextView
+	badge func(tab string) string
+}
+
+// NewProfileNavigator creates a navigator without tabs
+func NewProfileNavigator() *ProfileNavigator {
+	n := &ProfileNavigator{TextView: tview.NewTextView()}
+	n.SetDynamicColors(true).SetBorder(true).SetTitle(" Profiles ")
+	return n
+}
+
+// SetBadgeFunc sets what is shown after each tab, e.g. a count of failing
+// servers; "" shows nothing
+func (n *ProfileNavigator) SetBadgeFunc(badge func(tab string) string) *ProfileNavigator {
+	n.badge = badge
+	return n
+}
+
+// Render shows the tabs, selected being the index of the selected one
+func (n *ProfileNavigator) Render(tabs []string, selected int) {
+	n.SetText(RenderTabs(tabs, selected, n.badge))
+	if selected >= 0 && selected < len(tabs) {
+		n.SetTitle(fmt.Sprintf(" Profiles › [aqua]%s[white] ", tabs[selected]))
+	} else {
+		n.SetTitle(" Profiles ")
+	}
+}
+
+// RenderTabs returns the text of a tab row, each tab followed by its badge
+// when badge is set
+func RenderTabs(tabs []string, selected int, badge func(tab string) string) string {
+	if len(tabs) == 0 {
+		return "[white]No profiles configured"
+	}
+
+	tabStrings := make([]string, 0, len(tabs))
+	for i, tab := range tabs {
+		var tabString string
+		if i == selected {
+			tabString = fmt.Sprintf("[black:aqua:b] %s [white::-]", tab)
+		} else {
+			tabString = fmt.Sprintf("[lightgray]%s[white]", tab)
+		}
+		if badge != nil {
+			if text := badge(tab); text != "" {
+				tabString += " " + text
+			}
+		}
+		tabStrings = append(tabStrings, tabString)
+	}
+	return strings.Join(tabStrings, " [darkgray]│[white] ")
+}
@@ -0,0 +1,177 @@
+package views
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"sshm/internal/config"
+)
+
+// ServerProvider supplies what the server list shows about a server besides
+// its configuration
+type ServerProvider interface {
+	// ServerStatus returns the status shown in the Status column
+	ServerStatus(serverName string) Status
+	// ServerProfiles returns the profiles the server belongs to
+	ServerProfiles(serverName string) []string
+}
+
+// StatsProvider is implemented by providers that also collect quick stats,
+// shown in the Stats column when Columns.Stats is set
+type StatsProvider interface {
+	ServerStats(serverName string) string
+}
+
+// Columns selects the optional columns of the server list
+type Columns struct {
+	Stats     bool // Load, disk and memory from the StatsProvider
+	LocalTime bool // Each host's local time, for servers with a time zone
+}
+
+// ServerList is the table of servers: name, host, port, user, auth, status
+// and profile, plus the optional columns. Row 0 is the header, so the server
+// in row n is Servers()[n-1].
+type ServerList struct {
+	*tview.Table
+	provider ServerProvider
+	servers  []config.Server
+}
+
+// NewServerList creates an empty server list drawing its statuses and
+// profiles from provider
+func NewServerList(provider ServerProvider) *ServerList {
+	l := &ServerList{Table: tview.NewTable(), provider: provider}
+	l.SetBorder(true).SetTitle(" Servers ")
+	l.SetBorders(false)
+	l.SetSelectable(true, false)
+	l.SetSelectedStyle(selectedStyle)
+
+	l.SetCell(0, 0, headerCell("Name", tview.AlignLeft))
+	l.SetCell(0, 1, headerCell("Host", tview.AlignLeft))
+	l.SetCell(0, 2, headerCell("Port", tview.AlignCenter))
+	l.SetCell(0, 3, headerCell("User", tview.AlignLeft))
+	l.SetCell(0, 4, headerCell("Auth", tview.AlignCenter))
+	l.SetCell(0, 5, headerCell("Status", tview.AlignCenter))
+	l.SetCell(0, 6, headerCell("Profile", tview.AlignLeft))
+	return l
+}
+
+// SetServers replaces the servers shown, keeping the selected row
+func (l *ServerList) SetServers(servers []config.Server, columns Columns) {
+	l.servers = servers
+	clearRows(l.Table)
+
+	// Optional columns keep an empty header while hidden, so the layout doesn't shift
+	statsHeader, localTimeHeader := "", ""
+	if columns.Stats {
+		statsHeader = "Stats"
+	}
+	if columns.LocalTime {
+		localTimeHeader = "Local time"
+	}
+	l.SetCell(0, 7, headerCell(statsHeader, tview.AlignLeft))
+	l.SetCell(0, 8, headerCell(localTimeHeader, tview.AlignLeft))
+
+	stats, _ := l.provider.(StatsProvider)
+	now := time.Now()
+	for i, server := range servers {
+		row := i + 1
+
+		profileDisplay := "none"
+		if profiles := l.provider.ServerProfiles(server.Name); len(profiles) > 0 {
+			profileDisplay = profiles[0]
+			if len(profiles) > 1 {
+				profileDisplay += "+" // Indicate multiple profiles
+			}
+		}
+
+		hostDisplay := server.Hostname
+		if server.GetEffectiveHostname() != server.Hostname {
+			hostDisplay = fmt.Sprintf("%s → %s", server.Hostname, server.GetEffectiveHostname())
+		}
+
+		// Scratch servers close to their expiry stand out in orange
+		nameColor := tcell.ColorWhite
+		if server.IsExpiringSoon(now) {
+			nameColor = tcell.ColorOrange
+		}
+
+		status := l.provider.ServerStatus(server.Name)
+		l.SetCell(row, 0, tview.NewTableCell(server.Name).SetTextColor(nameColor).SetAlign(tview.AlignLeft))
+		l.SetCell(row, 1, tview.NewTableCell(hostDisplay).SetTextColor(tcell.ColorLightBlue).SetAlign(tview.AlignLeft))
+		l.SetCell(row, 2, tview.NewTableCell(fmt.Sprintf("%d", server.Port)).SetTextColor(tcell.ColorLightGray).SetAlign(tview.AlignCenter))
+		l.SetCell(row, 3, tview.NewTableCell(server.Username).SetTextColor(tcell.ColorLightGreen).SetAlign(tview.AlignLeft))
+		l.SetCell(row, 4, tview.NewTableCell(server.AuthType).SetTextColor(tcell.ColorYellow).SetAlign(tview.AlignCenter))
+		l.SetCell(row, 5, tview.NewTableCell(status.Text).SetTextColor(status.Color).SetAlign(tview.AlignCenter))
+		l.SetCell(row, 6, tview.NewTableCell(profileDisplay).SetTextColor(tcell.ColorAqua).SetAlign(tview.AlignLeft))
+		if columns.Stats && stats != nil {
+			l.SetCell(row, 7, tview.NewTableCell(stats.ServerStats(server.Name)).SetTextColor(tcell.ColorLightGray).SetAlign(tview.AlignLeft))
+		}
+		if columns.LocalTime {
+			l.SetCell(row, 8, tview.NewTableCell(server.LocalTime(now)).SetTextColor(tcell.ColorLightCyan).SetAlign(tview.AlignLeft))
+		}
+	}
+}
+
+// Servers returns the servers shown, in row order
+func (l *ServerList) Servers() []config.Server {
+	return l.servers
+}
+
+// SelectedServer returns the server in the selected row
+func (l *ServerList) SelectedServer() (config.Server, bool) {
+	row, _ := l.GetSelection()
+	if row <= 0 || row > len(l.servers) {
+		return config.Server{}, false
+	}
+	return l.servers[row-1], true
+}
+
+// SetSelectedServerFunc calls handler with the server a row is chosen for,
+// with Enter or a double click
+func (l *ServerList) SetSelectedServerFunc(handler func(server config.Server)) *ServerList {
+	l.SetSelectedFunc(func(row, _ int) {
+		if row > 0 && row <= len(l.servers) {
+			handler(l.servers[row-1])
+		}
+	})
+	return l
+}
+
+// ConfigProvider serves a configuration's profile memberships, with statuses
+// set by the embedding tool; servers without one show "-"
+type ConfigProvider struct {
+	cfg      *config.Config
+	mu       sync.RWMutex
+	statuses map[string]Status
+}
+
+// NewConfigProvider creates a provider for the servers of cfg
+func NewConfigProvider(cfg *config.Config) *ConfigProvider {
+	return &ConfigProvider{cfg: cfg, statuses: make(map[string]Status)}
+}
+
+// SetStatus sets the status shown for a server
+func (p *ConfigProvider) SetStatus(serverName string, status Status) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.statuses[serverName] = status
+}
+
+// ServerStatus implements ServerProvider
+func (p *ConfigProvider) ServerStatus(serverName string) Status {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if status, ok := p.statuses[serverName]; ok {
+		return status
+	}
+	return Status{Text: "-", Color: tcell.ColorGray}
+}
+
+// ServerProfiles implements ServerProvider
+func (p *ConfigProvider) ServerProfiles(serverName string) []string {
+	return p.cfg.ServerProfiles(serverName)
+}
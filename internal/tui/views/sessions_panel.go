@@ -0,0 +1,98 @@
+package views
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// Session is a row of the sessions panel
+type Session struct {
+	Name         string
+	Status       Status
+	Windows      int
+	LastActivity string
+	Clients      int  // Attached clients, -1 when unknown
+	Highlight    bool // Stands out, e.g. the session the user just detached from
+}
+
+// SessionsPanel is the table of tmux sessions: name, status, windows, last
+// activity and attached clients. Row 0 is the header.
+type SessionsPanel struct {
+	*tview.Table
+	sessions []Session
+}
+
+// NewSessionsPanel creates an empty sessions panel. Without tmux it only
+// says so in its title.
+func NewSessionsPanel(tmuxAvailable bool) *SessionsPanel {
+	p := &SessionsPanel{Table: tview.NewTable()}
+	if !tmuxAvailable {
+		p.SetBorder(true).SetTitle(" Sessions (tmux not available) ")
+		return p
+	}
+
+	p.SetBorder(true).SetTitle(" Sessions ")
+	p.SetBorders(false)
+	p.SetSelectable(true, false)
+	p.SetSelectedStyle(selectedStyle)
+
+	p.SetCell(0, 0, headerCell("Session", tview.AlignLeft))
+	p.SetCell(0, 1, headerCell("Status", tview.AlignCenter))
+	p.SetCell(0, 2, headerCell("Windows", tview.AlignCenter))
+	p.SetCell(0, 3, headerCell("Last Activity", tview.AlignLeft))
+	p.SetCell(0, 4, headerCell("Clients", tview.AlignCenter))
+	return p
+}
+
+// SetSessions replaces the sessions shown
+func (p *SessionsPanel) SetSessions(sessions []Session) {
+	p.sessions = sessions
+	clearRows(p.Table)
+
+	for i, session := range sessions {
+		row := i + 1
+		nameCell := tview.NewTableCell(session.Name).SetTextColor(tcell.ColorWhite).SetAlign(tview.AlignLeft)
+		if session.Highlight {
+			nameCell.SetTextColor(tcell.ColorAqua).SetAttributes(tcell.AttrBold)
+		}
+		p.SetCell(row, 0, nameCell)
+		p.SetCell(row, 1, tview.NewTableCell(session.Status.Text).SetTextColor(session.Status.Color).SetAlign(tview.AlignCenter))
+		p.SetCell(row, 2, tview.NewTableCell(fmt.Sprintf("%d", session.Windows)).SetTextColor(tcell.ColorLightBlue).SetAlign(tview.AlignCenter))
+		p.SetCell(row, 3, tview.NewTableCell(session.LastActivity).SetTextColor(tcell.ColorLightGray).SetAlign(tview.AlignLeft))
+		p.SetCell(row, 4, clientsCell(session.Clients))
+	}
+}
+
+// Sessions returns the sessions shown, in row order
+func (p *SessionsPanel) Sessions() []Session {
+	return p.sessions
+}
+
+// SelectedSession returns the session in the selected row
+func (p *SessionsPanel) SelectedSession() (Session, bool) {
+	row, _ := p.GetSelection()
+	if row <= 0 || row > len(p.sessions) {
+		return Session{}, false
+	}
+	return p.sessions[row-1], true
+}
+
+// clientsCell shows how many clients are attached to a session, standing out
+// once a teammate joined
+func clientsCell(clients int) *tview.TableCell {
+	if clients < 0 {
+		return tview.NewTableCell("-").SetTextColor(tcell.ColorGray).SetAlign(tview.AlignCenter)
+	}
+	cell := tview.NewTableCell(fmt.Sprintf("%d", clients)).SetAlign(tview.AlignCenter)
+	switch {
+	case clients > 1:
+		cell.SetTextColor(tcell.ColorOrange).SetAttributes(tcell.AttrBold)
+	case clients == 1:
+		cell.SetTextColor(tcell.ColorWhite)
+	default:
+		cell.SetTextColor(tcell.ColorGray)
+	}
+	return cell
+}
@@ -0,0 +1,44 @@
+// Package views provides sshm's server list, sessions panel and profile
+// navigator as tview primitives. Each embeds the tview widget it draws with,
+// so it can be added to any layout and styled like one, and shows what the
+// caller gives it: servers, sessions and profile tabs are set on it, while
+// statuses and profile memberships come from a ServerProvider.
+//
+// The sshm TUI composes its main screen from these views. Other tools can
+// embed them too, e.g. a server picker without status checks:
+//
+//	list := views.NewServerList(views.NewConfigProvider(cfg))
+//	list.SetServers(cfg.GetServers(), views.Columns{})
+//	list.SetSelectedServerFunc(func(server config.Server) {
+//		app.Stop()
+//		connect(server)
+//	})
+//	app.SetRoot(list, true)
+package views
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// Status is a status as shown in a view: its text, usually with a leading
+// indicator, and color
+type Status struct {
+	Text  string
+	Color tcell.Color
+}
+
+// selectedStyle highlights the selected row of the server list and sessions panel
+var selectedStyle = tcell.StyleDefault.Background(tcell.ColorDarkBlue).Foreground(tcell.ColorWhite)
+
+// headerCell returns a column header
+func headerCell(text string, align int) *tview.TableCell {
+	return tview.NewTableCell(text).SetTextColor(tcell.ColorYellow).SetSelectable(false).SetAlign(align)
+}
+
+// clearRows removes every row of a table but the header
+func clearRows(table *tview.Table) {
+	for row := table.GetRowCount() - 1; row > 0; row-- {
+		table.RemoveRow(row)
+	}
+}
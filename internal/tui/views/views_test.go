@@ -0,0 +1,159 @@
+package views
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"sshm/internal/config"
+)
+
+func testConfig() *config.Config {
+	return &config.Config{
+		Servers: []config.Server{
+			{Name: "web1", Hostname: "10.0.0.1", Port: 22, Username: "deploy", AuthType: "key"},
+			{Name: "db1", Hostname: "10.0.0.2", Port: 2222, Username: "admin", AuthType: "password"},
+		},
+		Profiles: []config.Profile{
+			{Name: "prod", Servers: []string{"web1", "db1"}},
+			{Name: "web", Servers: []string{"web1"}},
+		},
+	}
+}
+
+func TestServerList(t *testing.T) {
+	cfg := testConfig()
+	provider := NewConfigProvider(cfg)
+	provider.SetStatus("web1", Status{Text: "● Online", Color: tcell.ColorGreen})
+
+	list := NewServerList(provider)
+	list.SetServers(cfg.Servers, Columns{})
+
+	if rows := list.GetRowCount(); rows != 3 {
+		t.Fatalf("Expected a header and 2 server rows, got %d rows", rows)
+	}
+	if text := list.GetCell(1, 5).Text; text != "● Online" {
+		t.Errorf("Expected the status set on the provider, got %q", text)
+	}
+	if text := list.GetCell(2, 5).Text; text != "-" {
+		t.Errorf("Expected '-' for a server without a status, got %q", text)
+	}
+	if text := list.GetCell(1, 6).Text; text != "prod+" {
+		t.Errorf("Expected 'prod+' for a server in two profiles, got %q", text)
+	}
+	if text := list.GetCell(0, 7).Text; text != "" {
+		t.Errorf("Expected the hidden stats column to have no header, got %q", text)
+	}
+
+	list.Select(2, 0)
+	server, ok := list.SelectedServer()
+	if !ok || server.Name != "db1" {
+		t.Errorf("Expected db1 to be selected, got %q (%v)", server.Name, ok)
+	}
+
+	// Replacing the servers drops the rows of the old ones
+	list.SetServers(cfg.Servers[:1], Columns{})
+	if rows := list.GetRowCount(); rows != 2 {
+		t.Errorf("Expected a header and 1 server row, got %d rows", rows)
+	}
+	if servers := list.Servers(); len(servers) != 1 || servers[0].Name != "web1" {
+		t.Errorf("Expected only web1 to be listed, got %v", servers)
+	}
+}
+
+type statsProvider struct {
+	*ConfigProvider
+}
+
+func (statsProvider) ServerStats(serverName string) string {
+	return "load 0.5"
+}
+
+func TestServerListStatsColumn(t *testing.T) {
+	cfg := testConfig()
+	list := NewServerList(statsProvider{NewConfigProvider(cfg)})
+	list.SetServers(cfg.Servers, Columns{Stats: true})
+
+	if text := list.GetCell(0, 7).Text; text != "Stats" {
+		t.Errorf("Expected a Stats header, got %q", text)
+	}
+	if text := list.GetCell(1, 7).Text; text != "load 0.5" {
+		t.Errorf("Expected the provider's stats, got %q", text)
+	}
+}
+
+func TestSessionsPanel(t *testing.T) {
+	panel := NewSessionsPanel(true)
+	panel.SetSessions([]Session{
+		{Name: "web1", Status: Status{Text: "attached", Color: tcell.ColorGreen}, Windows: 2, Clients: 1, Highlight: true},
+		{Name: "db1", Status: Status{Text: "unknown", Color: tcell.ColorGray}, Windows: 1, Clients: -1},
+	})
+
+	if rows := panel.GetRowCount(); rows != 3 {
+		t.Fatalf("Expected a header and 2 session rows, got %d rows", rows)
+	}
+	if color, _, _ := panel.GetCell(1, 0).Style.Decompose(); color != tcell.ColorAqua {
+		t.Errorf("Expected the highlighted session in aqua, got %v", color)
+	}
+	if text := panel.GetCell(2, 4).Text; text != "-" {
+		t.Errorf("Expected '-' for unknown clients, got %q", text)
+	}
+
+	panel.Select(1, 0)
+	session, ok := panel.SelectedSession()
+	if !ok || session.Name != "web1" {
+		t.Errorf("Expected web1 to be selected, got %q (%v)", session.Name, ok)
+	}
+
+	if unavailable := NewSessionsPanel(false); !strings.Contains(unavailable.GetTitle(), "not available") {
+		t.Errorf("Expected the title to say tmux isn't available, got %q", unavailable.GetTitle())
+	}
+}
+
+func TestClientsCell(t *testing.T) {
+	tests := []struct {
+		clients int
+		text    string
+		color   tcell.Color
+	}{
+		{-1, "-", tcell.ColorGray},
+		{0, "0", tcell.ColorGray},
+		{1, "1", tcell.ColorWhite},
+		{3, "3", tcell.ColorOrange},
+	}
+	for _, tt := range tests {
+		cell := clientsCell(tt.clients)
+		if cell.Text != tt.text {
+			t.Errorf("clientsCell(%d) text = %q, want %q", tt.clients, cell.Text, tt.text)
+		}
+		if color, _, _ := cell.Style.Decompose(); color != tt.color {
+			t.Errorf("clientsCell(%d) color = %v, want %v", tt.clients, color, tt.color)
+		}
+	}
+}
+
+func TestRenderTabs(t *testing.T) {
+	if text := RenderTabs(nil, 0, nil); !strings.Contains(text, "No profiles configured") {
+		t.Errorf("Expected a placeholder without tabs, got %q", text)
+	}
+
+	badge := func(tab string) string {
+		if tab == "prod" {
+			return "[red]2✗[white]"
+		}
+		return ""
+	}
+	text := RenderTabs([]string{"all", "prod"}, 1, badge)
+	if !strings.Contains(text, "[lightgray]all[white]") {
+		t.Errorf("Expected an unselected 'all' tab, got %q", text)
+	}
+	if !strings.Contains(text, "[black:aqua:b] prod [white::-] [red]2✗[white]") {
+		t.Errorf("Expected the selected 'prod' tab with its badge, got %q", text)
+	}
+
+	nav := NewProfileNavigator()
+	nav.Render([]string{"all", "prod"}, 1)
+	if !strings.Contains(nav.GetTitle(), "prod") {
+		t.Errorf("Expected the title to name the selected profile, got %q", nav.GetTitle())
+	}
+}
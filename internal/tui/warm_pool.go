@@ -0,0 +1,41 @@
+package tui
+
+import (
+	"time"
+
+	"sshm/internal/config"
+	"sshm/internal/warmpool"
+)
+
+// startWarmPool keeps connections open to warm and watched servers while the
+// TUI runs, so pressing Enter attaches without a fresh handshake. Connections
+// outlive the TUI until they have been idle for the pool's idle timeout.
+func (t *TUIApp) startWarmPool() {
+	if !t.config.WarmPool.Enabled {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(warmpool.RefreshInterval)
+		defer ticker.Stop()
+
+		for {
+			if t.running && !t.isOffline() {
+				t.warmPool.Refresh(t.config)
+			}
+			select {
+			case <-t.stopChan:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// describeWarmConnection tells whether sessions to the server attach over a
+// warm connection
+func describeWarmConnection(server config.Server) string {
+	if server.HasWarmConnection() {
+		return "[green]open[white] (sessions attach instantly)"
+	}
+	return "[gray]not open[white]"
+}
@@ -0,0 +1,197 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rivo/tview"
+	"sshm/internal/config"
+)
+
+// watchState is the latest fast-check result of a watched server
+type watchState struct {
+	lastCheck   time.Time
+	untilOnline bool // Stop watching once the server is back online
+	wentOffline bool // The server was seen offline since the watch started
+}
+
+// setupWatchPanel creates the watch list overlay shown above the status bar
+func (t *TUIApp) setupWatchPanel() {
+	t.watchPanel = tview.NewTextView().
+		SetDynamicColors(true).
+		SetWrap(false)
+	t.watchPanel.SetBorder(true).SetTitle(" 👁 Watching ")
+	t.watches = make(map[string]*watchState)
+}
+
+// toggleWatchSelectedServer flags or unflags the selected server for fast status checks
+func (t *TUIApp) toggleWatchSelectedServer() {
+	if t.focusedPanel != "servers" {
+		return
+	}
+
+	serverName := t.getSelectedServerName()
+	if serverName == "" {
+		return
+	}
+
+	server, err := t.config.GetServer(serverName)
+	if err != nil {
+		t.showErrorModal(fmt.Sprintf("Server '%s' not found: %s", serverName, err.Error()))
+		return
+	}
+
+	server.Watch = !server.Watch
+	if err := t.service().UpdateServer(server.Name, *server); err != nil {
+		t.showErrorModal(fmt.Sprintf("Failed to update server: %s", err.Error()))
+		return
+	}
+
+	if server.Watch {
+		t.showTransientStatus(fmt.Sprintf("[yellow]Watching '%s' every %v[white]", server.Name, t.config.UI.WatchInterval()))
+	} else {
+		t.watchMu.Lock()
+		delete(t.watches, server.Name)
+		t.watchMu.Unlock()
+		t.showTransientStatus(fmt.Sprintf("[yellow]Stopped watching '%s'[white]", server.Name))
+	}
+	t.refreshWatchPanel()
+}
+
+// watchUntilOnline watches a server at the fast interval until it is reachable again
+func (t *TUIApp) watchUntilOnline(serverName string) {
+	t.watchMu.Lock()
+	if t.watches == nil {
+		t.watches = make(map[string]*watchState)
+	}
+	t.watches[serverName] = &watchState{untilOnline: true}
+	t.watchMu.Unlock()
+	t.refreshWatchPanel()
+}
+
+// watchedServers returns the servers flagged for watching or temporarily watched
+func (t *TUIApp) watchedServers() []config.Server {
+	t.watchMu.Lock()
+	defer t.watchMu.Unlock()
+
+	var watched []config.Server
+	for _, server := range t.config.GetServers() {
+		if _, temporary := t.watches[server.Name]; server.Watch || temporary {
+			watched = append(watched, server)
+		}
+	}
+	sort.Slice(watched, func(i, j int) bool { return watched[i].Name < watched[j].Name })
+	return watched
+}
+
+// startWatchMonitoring checks watched servers at the watch interval,
+// independently of the slower schedule used for all servers
+func (t *TUIApp) startWatchMonitoring() {
+	go func() {
+		ticker := time.NewTicker(t.config.UI.WatchInterval())
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-t.stopChan:
+				return
+			case <-ticker.C:
+				if t.running {
+					t.checkWatchedServers()
+				}
+			}
+		}
+	}()
+}
+
+// checkWatchedServers runs one status check of every watched server
+func (t *TUIApp) checkWatchedServers() {
+	if t.isOffline() {
+		return
+	}
+	for _, server := range t.watchedServers() {
+		status, attempts, latency := t.checkSingleConnectionStatus(server)
+
+		t.statusMutex.Lock()
+		t.connectionStatus[server.Name] = status
+		t.statusAttempts[server.Name] = attempts
+		t.statusLatency[server.Name] = latency
+		t.statusMutex.Unlock()
+
+		t.watchMu.Lock()
+		state, ok := t.watches[server.Name]
+		if !ok {
+			state = &watchState{}
+			t.watches[server.Name] = state
+		}
+		state.lastCheck = time.Now()
+		if status != "online" && status != "checking" {
+			state.wentOffline = true
+		}
+		// A host being rebooted is still online right after the command, so only
+		// stop once it went away and came back
+		backOnline := state.untilOnline && state.wentOffline && status == "online"
+		if backOnline {
+			delete(t.watches, server.Name)
+		}
+		t.watchMu.Unlock()
+
+		if backOnline && t.app != nil {
+			serverName := server.Name
+			t.app.QueueUpdateDraw(func() {
+				t.showTransientStatus(fmt.Sprintf("[green]'%s' is back online[white]", serverName))
+			})
+		}
+	}
+
+	t.saveStatusCache()
+
+	if t.app != nil {
+		t.app.QueueUpdateDraw(func() {
+			t.refreshServerList()
+			t.refreshWatchPanel()
+		})
+	}
+}
+
+// refreshWatchPanel redraws the watch list and hides it when nothing is watched
+func (t *TUIApp) refreshWatchPanel() {
+	if t.watchPanel == nil || t.layout == nil {
+		return
+	}
+
+	watched := t.watchedServers()
+	if len(watched) == 0 {
+		t.layout.ResizeItem(t.watchPanel, 0, 0)
+		return
+	}
+
+	var b strings.Builder
+	for i, server := range watched {
+		status, statusColor := t.getCachedConnectionStatus(server.Name)
+
+		t.watchMu.Lock()
+		state := t.watches[server.Name]
+		checked := "not checked yet"
+		suffix := ""
+		if state != nil {
+			if !state.lastCheck.IsZero() {
+				checked = fmt.Sprintf("checked %ds ago", int(time.Since(state.lastCheck).Seconds()))
+			}
+			if state.untilOnline {
+				suffix = " [gray](until online)[white]"
+			}
+		}
+		t.watchMu.Unlock()
+
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "[%s]%s[white]  %-20s [gray]%s[white]%s", statusColor.String(), status, server.Name, checked, suffix)
+	}
+
+	t.watchPanel.SetText(b.String())
+	t.layout.ResizeItem(t.watchPanel, len(watched)+2, 0)
+}
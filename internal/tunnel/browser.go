@@ -0,0 +1,29 @@
+package tunnel
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// browserCommand returns the command that opens a URL in the system browser
+func browserCommand(url string) (string, []string) {
+	switch runtime.GOOS {
+	case "darwin":
+		return "open", []string{url}
+	case "windows":
+		return "rundll32", []string{"url.dll,FileProtocolHandler", url}
+	default:
+		return "xdg-open", []string{url}
+	}
+}
+
+// OpenURL opens a URL in the system browser without waiting for it to close
+func OpenURL(url string) error {
+	name, args := browserCommand(url)
+	cmd := execCommand(name, args...)
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to open %s: %w", url, err)
+	}
+	go cmd.Wait()
+	return nil
+}
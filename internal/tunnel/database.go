@@ -0,0 +1,56 @@
+package tunnel
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"time"
+
+	"sshm/internal/config"
+)
+
+// DatabaseReadyTimeout bounds how long to wait for a database tunnel to accept connections
+const DatabaseReadyTimeout = 15 * time.Second
+
+// StartDatabase starts (or reuses) the tunnel to a server's database, waits until it
+// accepts connections and returns the client command to run against it
+func (m *Manager) StartDatabase(server config.Server) (*Tunnel, string, error) {
+	if !server.IsDatabase() {
+		return nil, "", fmt.Errorf("server '%s' has no database configured", server.Name)
+	}
+
+	spec := server.Database.Tunnel()
+	key := Key(server.Name, spec.Name)
+
+	var tun Tunnel
+	if existing, ok := m.Get(key); ok && m.IsRunning(key) {
+		tun = existing
+	} else {
+		started, err := m.Start(server, spec, true)
+		if err != nil {
+			return nil, "", err
+		}
+		tun = *started
+	}
+
+	if err := WaitReady(tun.LocalPort, DatabaseReadyTimeout); err != nil {
+		return nil, "", err
+	}
+
+	return &tun, server.Database.ClientCommand(server, tun.LocalPort), nil
+}
+
+// ClientProcess builds an interactive process for a database client command line
+func ClientProcess(command string) *exec.Cmd {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = execCommand("cmd", "/C", command)
+	} else {
+		cmd = execCommand("sh", "-c", command)
+	}
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd
+}
@@ -0,0 +1,56 @@
+package tunnel
+
+import (
+	"fmt"
+	"net"
+	"time"
+)
+
+// PortConflictError is returned when a tunnel's local port is already in use
+type PortConflictError struct {
+	Port  int
+	Owner string // Managed tunnel holding the port, if known
+}
+
+// Error implements the error interface
+func (e *PortConflictError) Error() string {
+	if e.Owner != "" {
+		return fmt.Sprintf("local port %d is already used by tunnel %s", e.Port, e.Owner)
+	}
+	return fmt.Sprintf("local port %d is already in use", e.Port)
+}
+
+// PortAvailable reports whether a local TCP port can be bound on the loopback interface
+func PortAvailable(port int) bool {
+	listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", port))
+	if err != nil {
+		return false
+	}
+	listener.Close()
+	return true
+}
+
+// FindFreePort asks the OS for an unused local TCP port
+func FindFreePort() (int, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, fmt.Errorf("failed to find a free local port: %w", err)
+	}
+	defer listener.Close()
+	return listener.Addr().(*net.TCPAddr).Port, nil
+}
+
+// WaitReady waits until a local port accepts connections or the timeout elapses
+func WaitReady(port int, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		err := dialLocal(port)
+		if err == nil {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("local port %d did not become ready within %v: %w", port, timeout, err)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
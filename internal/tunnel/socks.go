@@ -0,0 +1,83 @@
+package tunnel
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"sshm/internal/config"
+)
+
+// SOCKSReadyTimeout bounds how long to wait for a SOCKS proxy to accept connections
+const SOCKSReadyTimeout = 15 * time.Second
+
+// PACFileName is the proxy auto-config file written next to the configuration
+const PACFileName = "proxy.pac"
+
+// StartSOCKS starts (or reuses) a SOCKS proxy through the server and waits
+// until it accepts connections. The server's dynamic tunnel is used when one
+// is configured, otherwise a proxy on config.DefaultSOCKSPort or a free port.
+func (m *Manager) StartSOCKS(server config.Server) (*Tunnel, error) {
+	spec := server.SOCKSTunnel()
+	key := Key(server.Name, spec.Name)
+
+	var tun Tunnel
+	if existing, ok := m.Get(key); ok && m.IsRunning(key) {
+		tun = existing
+	} else {
+		started, err := m.Start(server, spec, true)
+		if err != nil {
+			return nil, err
+		}
+		tun = *started
+	}
+
+	if err := WaitReady(tun.LocalPort, SOCKSReadyTimeout); err != nil {
+		return nil, err
+	}
+	return &tun, nil
+}
+
+// SOCKSProxies returns the SOCKS proxies that are currently running, sorted by key
+func (m *Manager) SOCKSProxies() []Tunnel {
+	var proxies []Tunnel
+	for _, tun := range m.List() {
+		if tun.Dynamic && (tun.State == StateUp || tun.State == StateDegraded) {
+			proxies = append(proxies, tun)
+		}
+	}
+	return proxies
+}
+
+// PAC returns a proxy auto-config script sending browser traffic through the
+// SOCKS proxy on the local port; local addresses are reached directly
+func PAC(port int) string {
+	return fmt.Sprintf(`function FindProxyForURL(url, host) {
+  if (isPlainHostName(host) || host === "localhost" || host === "127.0.0.1") {
+    return "DIRECT";
+  }
+  return "SOCKS5 127.0.0.1:%d; SOCKS 127.0.0.1:%d";
+}
+`, port, port)
+}
+
+// DefaultPACPath returns where the PAC file is written, next to the configuration file
+func DefaultPACPath() (string, error) {
+	configPath, err := config.DefaultConfigPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(configPath), PACFileName), nil
+}
+
+// WritePAC writes the proxy auto-config script for the local port to path
+func WritePAC(path string, port int) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", path, err)
+	}
+	if err := os.WriteFile(path, []byte(PAC(port)), 0600); err != nil {
+		return fmt.Errorf("failed to write PAC file: %w", err)
+	}
+	return nil
+}
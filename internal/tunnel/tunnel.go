@@ -0,0 +1,419 @@
+package tunnel
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"sshm/internal/config"
+	"sshm/internal/retry"
+	"sshm/internal/secrets"
+)
+
+// Tunnel states
+const (
+	StateUp       = "up"       // ssh is running and the local endpoint accepts connections
+	StateDegraded = "degraded" // ssh is running but the local endpoint is not accepting connections
+	StateDown     = "down"     // ssh exited; the tunnel will be restarted
+	StateStopped  = "stopped"  // Stopped by the user
+)
+
+// Health check interval and restart backoff for tunnels whose ssh process exited
+const (
+	DefaultCheckInterval     = 10 * time.Second
+	DefaultRestartBackoff    = 2 * time.Second
+	DefaultMaxRestartBackoff = time.Minute
+)
+
+// execCommand is a variable to allow mocking in tests
+var execCommand = exec.Command
+
+// dialLocal checks whether a local endpoint accepts connections (variable to allow mocking in tests)
+var dialLocal = func(port int) error {
+	conn, err := net.DialTimeout("tcp", fmt.Sprintf("127.0.0.1:%d", port), 2*time.Second)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// Tunnel is a snapshot of a managed port forward
+type Tunnel struct {
+	ServerName     string
+	Name           string
+	ConfiguredPort int
+	LocalPort      int // Port actually bound; differs from ConfiguredPort after automatic selection
+	RemoteHost     string
+	RemotePort     int
+	URL            string // Browser URL for the forwarded service
+	Dynamic        bool   // SOCKS proxy rather than a port forward
+	State          string
+	StartedAt      time.Time
+	LastCheck      time.Time
+	Restarts       int
+	NextRestart    time.Time // When a down tunnel will be restarted
+	Error          string
+}
+
+// Destination describes where the tunnel leads, for display
+func (t *Tunnel) Destination() string {
+	if t.Dynamic {
+		return "SOCKS proxy"
+	}
+	return fmt.Sprintf("%s:%d", t.RemoteHost, t.RemotePort)
+}
+
+// Key identifies a tunnel as "server/tunnel"
+func (t *Tunnel) Key() string {
+	return Key(t.ServerName, t.Name)
+}
+
+// Key builds the identifier for a server's tunnel
+func Key(serverName, tunnelName string) string {
+	return serverName + "/" + tunnelName
+}
+
+// managedTunnel is the manager's mutable record for a tunnel
+type managedTunnel struct {
+	Tunnel
+	server   config.Server
+	spec     config.Tunnel
+	cmd      *exec.Cmd
+	failures int // Consecutive restarts without becoming healthy
+}
+
+// Manager starts, monitors and restarts tunnels
+type Manager struct {
+	mu      sync.Mutex
+	tunnels map[string]*managedTunnel
+	backoff retry.Policy
+	now     func() time.Time // Allows tests to control time
+}
+
+// NewManager creates a new tunnel manager
+func NewManager() *Manager {
+	return &Manager{
+		tunnels: make(map[string]*managedTunnel),
+		backoff: retry.Policy{
+			InitialBackoff: DefaultRestartBackoff,
+			MaxBackoff:     DefaultMaxRestartBackoff,
+			Multiplier:     2,
+			Jitter:         0.1,
+		},
+		now: time.Now,
+	}
+}
+
+// ResolveLocalPort returns the local port to bind for a tunnel. When the configured
+// port is taken, a free port is chosen if autoPort is set; otherwise a
+// *PortConflictError is returned so the caller can offer automatic selection.
+func (m *Manager) ResolveLocalPort(spec config.Tunnel, autoPort bool) (int, error) {
+	if spec.LocalPort == 0 {
+		return FindFreePort()
+	}
+
+	if owner := m.ownerOf(spec.LocalPort); owner == "" && PortAvailable(spec.LocalPort) {
+		return spec.LocalPort, nil
+	} else if !autoPort && !spec.AutoPort {
+		return 0, &PortConflictError{Port: spec.LocalPort, Owner: owner}
+	}
+
+	return FindFreePort()
+}
+
+// Start launches a tunnel for the server. autoPort forces automatic free-port
+// selection when the configured local port is taken.
+func (m *Manager) Start(server config.Server, spec config.Tunnel, autoPort bool) (*Tunnel, error) {
+	key := Key(server.Name, spec.Name)
+
+	m.mu.Lock()
+	if existing, ok := m.tunnels[key]; ok && existing.isRunning() {
+		m.mu.Unlock()
+		return nil, fmt.Errorf("tunnel %s is already running on port %d", key, existing.LocalPort)
+	}
+	m.mu.Unlock()
+
+	localPort, err := m.ResolveLocalPort(spec, autoPort)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &managedTunnel{
+		Tunnel: Tunnel{
+			ServerName:     server.Name,
+			Name:           spec.Name,
+			ConfiguredPort: spec.LocalPort,
+			LocalPort:      localPort,
+			RemoteHost:     spec.GetRemoteHost(),
+			RemotePort:     spec.RemotePort,
+			URL:            spec.BrowserURL(localPort),
+			Dynamic:        spec.Dynamic,
+		},
+		server: server,
+		spec:   spec,
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.launch(entry); err != nil {
+		return nil, err
+	}
+	m.tunnels[key] = entry
+
+	snapshot := entry.Tunnel
+	return &snapshot, nil
+}
+
+// launch starts the ssh process for a tunnel; the caller must hold m.mu
+func (m *Manager) launch(entry *managedTunnel) error {
+	cmd, err := buildTunnelCommand(entry.server, entry.spec, entry.LocalPort)
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start tunnel %s: %w", entry.Key(), err)
+	}
+
+	entry.cmd = cmd
+	entry.State = StateUp
+	entry.StartedAt = m.now()
+	entry.Error = ""
+	entry.NextRestart = time.Time{}
+
+	go m.wait(entry, cmd)
+	return nil
+}
+
+// wait marks a tunnel down when its ssh process exits and schedules a restart
+func (m *Manager) wait(entry *managedTunnel, cmd *exec.Cmd) {
+	err := cmd.Wait()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Ignore exits of processes that were stopped or replaced by a restart
+	if entry.cmd != cmd || entry.State == StateStopped {
+		return
+	}
+
+	entry.State = StateDown
+	entry.Error = "ssh exited"
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	entry.failures++
+	entry.NextRestart = m.now().Add(m.backoff.Backoff(entry.failures))
+}
+
+// CheckHealth verifies running tunnels accept local connections and restarts
+// tunnels whose restart backoff has elapsed
+func (m *Manager) CheckHealth() {
+	type probe struct {
+		entry *managedTunnel
+		cmd   *exec.Cmd
+		port  int
+	}
+
+	m.mu.Lock()
+	var probes []probe
+	for _, entry := range m.tunnels {
+		if entry.isRunning() {
+			probes = append(probes, probe{entry: entry, cmd: entry.cmd, port: entry.LocalPort})
+		}
+	}
+	m.mu.Unlock()
+
+	// Dial without holding the lock so slow checks don't block List
+	results := make([]error, len(probes))
+	for i, p := range probes {
+		results[i] = dialLocal(p.port)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	for i, p := range probes {
+		entry := p.entry
+		if entry.cmd != p.cmd || !entry.isRunning() {
+			continue // Restarted, stopped or exited while probing
+		}
+		entry.LastCheck = now
+		if results[i] != nil {
+			entry.State = StateDegraded
+			entry.Error = fmt.Sprintf("local endpoint not accepting connections: %v", results[i])
+		} else {
+			entry.State = StateUp
+			entry.Error = ""
+			entry.failures = 0
+		}
+	}
+
+	for _, entry := range m.tunnels {
+		if entry.State != StateDown || now.Before(entry.NextRestart) {
+			continue
+		}
+		entry.Restarts++
+		if err := m.launch(entry); err != nil {
+			entry.Error = err.Error()
+			entry.failures++
+			entry.NextRestart = now.Add(m.backoff.Backoff(entry.failures))
+		}
+	}
+}
+
+// StartMonitoring runs CheckHealth every interval until stop is closed or signalled
+func (m *Manager) StartMonitoring(interval time.Duration, stop <-chan struct{}) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				m.CheckHealth()
+			}
+		}
+	}()
+}
+
+// Restart stops a tunnel and starts it again on the same local port
+func (m *Manager) Restart(key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.tunnels[key]
+	if !ok {
+		return fmt.Errorf("tunnel %s not found", key)
+	}
+
+	m.kill(entry)
+	entry.Restarts++
+	entry.failures = 0
+	return m.launch(entry)
+}
+
+// Stop terminates a tunnel without restarting it
+func (m *Manager) Stop(key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.tunnels[key]
+	if !ok {
+		return fmt.Errorf("tunnel %s not found", key)
+	}
+
+	m.kill(entry)
+	entry.State = StateStopped
+	entry.NextRestart = time.Time{}
+	return nil
+}
+
+// kill terminates a tunnel's ssh process; the caller must hold m.mu
+func (m *Manager) kill(entry *managedTunnel) {
+	if entry.isRunning() && entry.cmd != nil && entry.cmd.Process != nil {
+		entry.cmd.Process.Kill()
+	}
+	entry.cmd = nil
+}
+
+// StopAll terminates every tunnel
+func (m *Manager) StopAll() {
+	for _, tunnel := range m.List() {
+		if tunnel.State != StateStopped {
+			m.Stop(tunnel.Key())
+		}
+	}
+}
+
+// List returns a snapshot of all known tunnels sorted by key
+func (m *Manager) List() []Tunnel {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	tunnels := make([]Tunnel, 0, len(m.tunnels))
+	for _, entry := range m.tunnels {
+		tunnels = append(tunnels, entry.Tunnel)
+	}
+	sort.Slice(tunnels, func(i, j int) bool { return tunnels[i].Key() < tunnels[j].Key() })
+	return tunnels
+}
+
+// Get returns a snapshot of the tunnel with the given key
+func (m *Manager) Get(key string) (Tunnel, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.tunnels[key]
+	if !ok {
+		return Tunnel{}, false
+	}
+	return entry.Tunnel, true
+}
+
+// IsRunning reports whether the tunnel with the given key is up or degraded
+func (m *Manager) IsRunning(key string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.tunnels[key]
+	return ok && entry.isRunning()
+}
+
+// ownerOf returns the key of the running managed tunnel bound to port, if any
+func (m *Manager) ownerOf(port int) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for key, entry := range m.tunnels {
+		if entry.isRunning() && entry.LocalPort == port {
+			return key
+		}
+	}
+	return ""
+}
+
+// isRunning reports whether the tunnel's ssh process is expected to be alive
+func (t *managedTunnel) isRunning() bool {
+	return t.State == StateUp || t.State == StateDegraded
+}
+
+// buildTunnelCommand builds a non-interactive ssh command that only forwards ports
+func buildTunnelCommand(server config.Server, spec config.Tunnel, localPort int) (*exec.Cmd, error) {
+	args := []string{
+		"-N",
+		"-o", "ExitOnForwardFailure=yes",
+		"-o", "ServerAliveInterval=30",
+		"-o", "ServerAliveCountMax=3",
+	}
+	args = append(args, spec.ForwardArgs(localPort)...)
+	if server.Port != 0 && server.Port != 22 {
+		args = append(args, "-p", fmt.Sprintf("%d", server.Port))
+	}
+	if server.AuthType == "key" && server.KeyPath != "" {
+		args = append(args, "-i", server.KeyPath)
+	}
+	args = append(args, strings.Fields(server.GetSSHOptions())...)
+	destination := fmt.Sprintf("%s@%s", server.Username, server.GetEffectiveHostname())
+
+	// Tunnels run in the background, so passwords and key passphrases can only
+	// come from the keyring
+	if secrets.Referenced(server) {
+		flags, secret, err := secrets.SSHPass(server)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve secret from keyring: %w", err)
+		}
+		cmd := execCommand("sshpass", append(append(flags, "-e", "ssh"), append(args, destination)...)...)
+		cmd.Env = append(os.Environ(), "SSHPASS="+secret)
+		return cmd, nil
+	}
+
+	args = append(args, "-o", "BatchMode=yes", destination)
+	return execCommand("ssh", args...), nil
+}
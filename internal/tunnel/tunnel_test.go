@@ -0,0 +1,367 @@
+package tunnel
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"sshm/internal/config"
+)
+
+func mockExec(t *testing.T, captured *[]string) {
+	mockExecWith(t, captured, "sleep", "30")
+}
+
+func mockExecWith(t *testing.T, captured *[]string, name string, args ...string) {
+	original := execCommand
+	execCommand = func(cmdName string, arg ...string) *exec.Cmd {
+		if captured != nil {
+			*captured = append([]string{cmdName}, arg...)
+		}
+		return exec.Command(name, args...)
+	}
+	t.Cleanup(func() { execCommand = original })
+}
+
+func mockDial(t *testing.T, err error) {
+	original := dialLocal
+	dialLocal = func(port int) error { return err }
+	t.Cleanup(func() { dialLocal = original })
+}
+
+func waitForState(t *testing.T, manager *Manager, key, state string) Tunnel {
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		for _, tunnel := range manager.List() {
+			if tunnel.Key() == key && tunnel.State == state {
+				return tunnel
+			}
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Tunnel %s never reached state %s: %+v", key, state, manager.List())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func testServer() config.Server {
+	return config.Server{
+		Name:     "web",
+		Hostname: "web.example.com",
+		Port:     2222,
+		Username: "ops",
+		AuthType: "key",
+		KeyPath:  "/keys/id_ed25519",
+	}
+}
+
+func occupyPort(t *testing.T) int {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to occupy port: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	return listener.Addr().(*net.TCPAddr).Port
+}
+
+func TestPortAvailable(t *testing.T) {
+	port := occupyPort(t)
+	if PortAvailable(port) {
+		t.Errorf("Expected port %d to be reported as taken", port)
+	}
+
+	free, err := FindFreePort()
+	if err != nil {
+		t.Fatalf("Failed to find free port: %v", err)
+	}
+	if !PortAvailable(free) {
+		t.Errorf("Expected port %d to be available", free)
+	}
+}
+
+func TestResolveLocalPortConflict(t *testing.T) {
+	manager := NewManager()
+	port := occupyPort(t)
+	spec := config.Tunnel{Name: "web", LocalPort: port, RemotePort: 80}
+
+	_, err := manager.ResolveLocalPort(spec, false)
+	var conflict *PortConflictError
+	if !errors.As(err, &conflict) {
+		t.Fatalf("Expected PortConflictError, got: %v", err)
+	}
+	if conflict.Port != port {
+		t.Errorf("Expected conflict on port %d, got %d", port, conflict.Port)
+	}
+
+	chosen, err := manager.ResolveLocalPort(spec, true)
+	if err != nil {
+		t.Fatalf("Expected automatic port selection, got: %v", err)
+	}
+	if chosen == port {
+		t.Error("Expected a different port to be chosen")
+	}
+}
+
+func TestStartBuildsForwardCommand(t *testing.T) {
+	var captured []string
+	mockExec(t, &captured)
+
+	manager := NewManager()
+	defer manager.StopAll()
+
+	free, err := FindFreePort()
+	if err != nil {
+		t.Fatalf("Failed to find free port: %v", err)
+	}
+	spec := config.Tunnel{Name: "admin", LocalPort: free, RemotePort: 8080}
+
+	tunnel, err := manager.Start(testServer(), spec, false)
+	if err != nil {
+		t.Fatalf("Failed to start tunnel: %v", err)
+	}
+	if tunnel.LocalPort != free || tunnel.State != StateUp {
+		t.Errorf("Unexpected tunnel state: %+v", tunnel)
+	}
+
+	command := strings.Join(captured, " ")
+	for _, want := range []string{"ssh -N", "-L " + spec.ForwardSpec(free), "-p 2222", "-i /keys/id_ed25519", "BatchMode=yes", "ops@web.example.com"} {
+		if !strings.Contains(command, want) {
+			t.Errorf("Expected command to contain %q, got: %s", want, command)
+		}
+	}
+
+	if tunnel.URL != fmt.Sprintf("http://localhost:%d", free) {
+		t.Errorf("Expected browser URL for the bound port, got %s", tunnel.URL)
+	}
+	if !manager.IsRunning(tunnel.Key()) {
+		t.Error("Expected tunnel to be reported as running")
+	}
+
+	// A second start of the same tunnel is rejected while it is up
+	if _, err := manager.Start(testServer(), spec, false); err == nil {
+		t.Error("Expected error starting an already running tunnel")
+	}
+}
+
+func TestStartAutoSelectsPortWhenTaken(t *testing.T) {
+	mockExec(t, nil)
+
+	manager := NewManager()
+	defer manager.StopAll()
+
+	port := occupyPort(t)
+	spec := config.Tunnel{Name: "admin", LocalPort: port, RemotePort: 8080, AutoPort: true}
+
+	tunnel, err := manager.Start(testServer(), spec, false)
+	if err != nil {
+		t.Fatalf("Failed to start tunnel: %v", err)
+	}
+	if tunnel.LocalPort == port || tunnel.ConfiguredPort != port {
+		t.Errorf("Expected a new port to be chosen, got %d (configured %d)", tunnel.LocalPort, tunnel.ConfiguredPort)
+	}
+}
+
+func TestStopMarksTunnelStopped(t *testing.T) {
+	mockExec(t, nil)
+
+	manager := NewManager()
+	spec := config.Tunnel{Name: "admin", RemotePort: 8080}
+
+	tunnel, err := manager.Start(testServer(), spec, false)
+	if err != nil {
+		t.Fatalf("Failed to start tunnel: %v", err)
+	}
+	if err := manager.Stop(tunnel.Key()); err != nil {
+		t.Fatalf("Failed to stop tunnel: %v", err)
+	}
+
+	// Give the wait goroutine a moment to observe the exit
+	time.Sleep(50 * time.Millisecond)
+
+	tunnels := manager.List()
+	if len(tunnels) != 1 || tunnels[0].State != StateStopped {
+		t.Errorf("Expected tunnel to be stopped, got: %+v", tunnels)
+	}
+}
+
+func TestCheckHealthMarksDegradedAndRecovers(t *testing.T) {
+	mockExec(t, nil)
+
+	manager := NewManager()
+	defer manager.StopAll()
+
+	tunnel, err := manager.Start(testServer(), config.Tunnel{Name: "admin", RemotePort: 8080}, false)
+	if err != nil {
+		t.Fatalf("Failed to start tunnel: %v", err)
+	}
+
+	mockDial(t, errors.New("connection refused"))
+	manager.CheckHealth()
+	if got := manager.List()[0]; got.State != StateDegraded {
+		t.Errorf("Expected degraded state, got %s", got.State)
+	}
+
+	mockDial(t, nil)
+	manager.CheckHealth()
+	if got := manager.List()[0]; got.State != StateUp || got.LastCheck.IsZero() {
+		t.Errorf("Expected tunnel %s to recover, got %+v", tunnel.Key(), got)
+	}
+}
+
+func TestCheckHealthRestartsExitedTunnelAfterBackoff(t *testing.T) {
+	mockExecWith(t, nil, "false")
+	mockDial(t, nil)
+
+	manager := NewManager()
+	defer manager.StopAll()
+	current := time.Now()
+	manager.now = func() time.Time { return current }
+
+	tunnel, err := manager.Start(testServer(), config.Tunnel{Name: "admin", RemotePort: 8080}, false)
+	if err != nil {
+		t.Fatalf("Failed to start tunnel: %v", err)
+	}
+
+	down := waitForState(t, manager, tunnel.Key(), StateDown)
+	if !down.NextRestart.After(current) {
+		t.Fatalf("Expected a restart to be scheduled in the future, got %v", down.NextRestart)
+	}
+
+	// Before the backoff elapses nothing is restarted
+	manager.CheckHealth()
+	if got := manager.List()[0]; got.Restarts != 0 {
+		t.Errorf("Expected no restart before backoff, got %d", got.Restarts)
+	}
+
+	mockExec(t, nil)
+	current = down.NextRestart.Add(time.Millisecond)
+	manager.CheckHealth()
+
+	got := manager.List()[0]
+	if got.Restarts != 1 || got.State != StateUp {
+		t.Errorf("Expected tunnel to be restarted once and up, got %+v", got)
+	}
+}
+
+func TestRestartTunnel(t *testing.T) {
+	mockExec(t, nil)
+
+	manager := NewManager()
+	defer manager.StopAll()
+
+	tunnel, err := manager.Start(testServer(), config.Tunnel{Name: "admin", RemotePort: 8080}, false)
+	if err != nil {
+		t.Fatalf("Failed to start tunnel: %v", err)
+	}
+	if err := manager.Restart(tunnel.Key()); err != nil {
+		t.Fatalf("Failed to restart tunnel: %v", err)
+	}
+
+	got := manager.List()[0]
+	if got.State != StateUp || got.Restarts != 1 || got.LocalPort != tunnel.LocalPort {
+		t.Errorf("Unexpected tunnel after restart: %+v", got)
+	}
+}
+
+func TestOpenURL(t *testing.T) {
+	var captured []string
+	mockExecWith(t, &captured, "true")
+
+	if err := OpenURL("http://localhost:8080"); err != nil {
+		t.Fatalf("Failed to open URL: %v", err)
+	}
+
+	name, args := browserCommand("http://localhost:8080")
+	if captured[0] != name || captured[len(captured)-1] != "http://localhost:8080" || len(captured) != len(args)+1 {
+		t.Errorf("Unexpected browser command: %v", captured)
+	}
+}
+
+func TestStartDatabase(t *testing.T) {
+	mockExec(t, nil)
+	mockDial(t, nil)
+
+	manager := NewManager()
+	defer manager.StopAll()
+
+	server := testServer()
+	server.Database = &config.DatabaseConfig{Engine: "postgres", Name: "orders"}
+
+	tun, command, err := manager.StartDatabase(server)
+	if err != nil {
+		t.Fatalf("Failed to start database tunnel: %v", err)
+	}
+	if tun.RemotePort != 5432 {
+		t.Errorf("Expected tunnel to postgres port, got %d", tun.RemotePort)
+	}
+	expected := fmt.Sprintf("psql -h 127.0.0.1 -p %d -U ops orders", tun.LocalPort)
+	if command != expected {
+		t.Errorf("Expected command %q, got %q", expected, command)
+	}
+
+	// A running database tunnel is reused
+	again, _, err := manager.StartDatabase(server)
+	if err != nil || again.LocalPort != tun.LocalPort {
+		t.Errorf("Expected running tunnel to be reused, got %v (err %v)", again, err)
+	}
+
+	if _, _, err := manager.StartDatabase(testServer()); err == nil {
+		t.Error("Expected error for server without database")
+	}
+}
+
+func TestStartSOCKS(t *testing.T) {
+	var captured []string
+	mockExec(t, &captured)
+	mockDial(t, nil)
+
+	manager := NewManager()
+	defer manager.StopAll()
+
+	started, err := manager.StartSOCKS(testServer())
+	if err != nil {
+		t.Fatalf("Failed to start SOCKS proxy: %v", err)
+	}
+	if !started.Dynamic || started.URL != "" {
+		t.Errorf("Unexpected SOCKS tunnel: %+v", started)
+	}
+	if command := strings.Join(captured, " "); !strings.Contains(command, fmt.Sprintf("-D 127.0.0.1:%d", started.LocalPort)) {
+		t.Errorf("Expected a dynamic forward, got: %s", command)
+	}
+
+	proxies := manager.SOCKSProxies()
+	if len(proxies) != 1 || proxies[0].Key() != started.Key() {
+		t.Errorf("Expected the proxy to be listed, got %+v", proxies)
+	}
+
+	// Starting again reuses the running proxy
+	again, err := manager.StartSOCKS(testServer())
+	if err != nil || again.LocalPort != started.LocalPort {
+		t.Errorf("Expected the running proxy to be reused, got %+v, %v", again, err)
+	}
+
+	manager.Stop(started.Key())
+	if proxies := manager.SOCKSProxies(); len(proxies) != 0 {
+		t.Errorf("Expected no proxies after stopping, got %+v", proxies)
+	}
+}
+
+func TestWritePAC(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sshm", PACFileName)
+	if err := WritePAC(path, 1080); err != nil {
+		t.Fatalf("WritePAC() error = %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"SOCKS5 127.0.0.1:1080; SOCKS 127.0.0.1:1080"`) || !strings.Contains(string(data), "FindProxyForURL") {
+		t.Errorf("Unexpected PAC file:\n%s", data)
+	}
+}
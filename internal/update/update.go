@@ -0,0 +1,345 @@
+package update
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"sshm/internal/config"
+)
+
+// Version is the running version, overridden at build time with
+// -ldflags "-X sshm/internal/update.Version=v1.5.0"
+var Version = "v1.4.0"
+
+// Release channels
+const (
+	ChannelStable = "stable"
+	ChannelBeta   = "beta"
+)
+
+const (
+	// Repository is the GitHub repository releases are published to
+	Repository = "igda-igda/sshm"
+	// ChecksumsAsset lists the SHA-256 of every binary in a release
+	ChecksumsAsset = "checksums.txt"
+	// CheckFileName caches the last new version check next to config.yaml
+	CheckFileName = "update-check.json"
+	// CheckInterval is how long a new version check is reused before asking GitHub again
+	CheckInterval = 24 * time.Hour
+	// maxBinarySize bounds downloads so a bad asset can't fill the disk
+	maxBinarySize = 200 << 20
+)
+
+// releasesURL is the GitHub releases API endpoint (variable to allow mocking in tests)
+var releasesURL = "https://api.github.com/repos/" + Repository + "/releases"
+
+var httpClient = &http.Client{Timeout: 60 * time.Second}
+
+// Asset is a file attached to a release
+type Asset struct {
+	Name string `json:"name"`
+	URL  string `json:"browser_download_url"`
+}
+
+// Release is a published GitHub release
+type Release struct {
+	TagName    string  `json:"tag_name"`
+	Prerelease bool    `json:"prerelease"`
+	Draft      bool    `json:"draft"`
+	HTMLURL    string  `json:"html_url"`
+	Assets     []Asset `json:"assets"`
+}
+
+// NormalizeChannel validates a channel name, defaulting to stable
+func NormalizeChannel(channel string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(channel)) {
+	case "", ChannelStable:
+		return ChannelStable, nil
+	case ChannelBeta:
+		return ChannelBeta, nil
+	default:
+		return "", fmt.Errorf("unknown release channel '%s' (use stable or beta)", channel)
+	}
+}
+
+// Latest returns the newest release on the channel; beta also considers pre-releases
+func Latest(channel string) (*Release, error) {
+	channel, err := NormalizeChannel(channel)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodGet, releasesURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch releases: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch releases: %s", resp.Status)
+	}
+
+	var releases []Release
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 10<<20)).Decode(&releases); err != nil {
+		return nil, fmt.Errorf("failed to parse releases: %w", err)
+	}
+
+	var latest *Release
+	for i := range releases {
+		release := &releases[i]
+		if release.Draft || (release.Prerelease && channel == ChannelStable) {
+			continue
+		}
+		if latest == nil || CompareVersions(release.TagName, latest.TagName) > 0 {
+			latest = release
+		}
+	}
+	if latest == nil {
+		return nil, fmt.Errorf("no %s releases found", channel)
+	}
+	return latest, nil
+}
+
+// IsNewer reports whether the release is newer than the running version
+func (r *Release) IsNewer() bool {
+	return CompareVersions(r.TagName, Version) > 0
+}
+
+// CompareVersions compares semantic versions such as "v1.4.0" and "1.5.0-beta.2",
+// returning -1, 0 or 1. A pre-release sorts before its final release.
+func CompareVersions(a, b string) int {
+	coreA, preA, _ := strings.Cut(strings.TrimPrefix(strings.TrimSpace(a), "v"), "-")
+	coreB, preB, _ := strings.Cut(strings.TrimPrefix(strings.TrimSpace(b), "v"), "-")
+
+	if c := compareFields(strings.Split(coreA, "."), strings.Split(coreB, ".")); c != 0 {
+		return c
+	}
+
+	switch {
+	case preA == preB:
+		return 0
+	case preA == "":
+		return 1
+	case preB == "":
+		return -1
+	}
+	return compareFields(strings.Split(preA, "."), strings.Split(preB, "."))
+}
+
+// compareFields compares dot-separated identifiers, numerically where both are numbers
+func compareFields(a, b []string) int {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		var fieldA, fieldB string
+		if i < len(a) {
+			fieldA = a[i]
+		}
+		if i < len(b) {
+			fieldB = b[i]
+		}
+
+		numA, errA := strconv.Atoi(fieldA)
+		numB, errB := strconv.Atoi(fieldB)
+		if fieldA == "" {
+			numA, errA = 0, nil
+		}
+		if fieldB == "" {
+			numB, errB = 0, nil
+		}
+
+		switch {
+		case errA == nil && errB == nil:
+			if numA != numB {
+				if numA < numB {
+					return -1
+				}
+				return 1
+			}
+		case fieldA != fieldB:
+			if fieldA < fieldB {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// AssetName returns the release binary name for a platform, e.g. "sshm_linux_amd64"
+func AssetName(goos, goarch string) string {
+	name := fmt.Sprintf("sshm_%s_%s", goos, goarch)
+	if goos == "windows" {
+		name += ".exe"
+	}
+	return name
+}
+
+func (r *Release) asset(name string) *Asset {
+	for i := range r.Assets {
+		if r.Assets[i].Name == name {
+			return &r.Assets[i]
+		}
+	}
+	return nil
+}
+
+// parseChecksums reads "<sha256>  <file>" lines as produced by sha256sum
+func parseChecksums(data []byte) map[string]string {
+	sums := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		sums[strings.TrimPrefix(fields[1], "*")] = strings.ToLower(fields[0])
+	}
+	return sums
+}
+
+// Apply downloads the release binary for this platform, verifies it against the
+// release checksums and replaces the executable at exePath in place.
+func Apply(release *Release, exePath string) error {
+	name := AssetName(runtime.GOOS, runtime.GOARCH)
+	binary := release.asset(name)
+	if binary == nil {
+		return fmt.Errorf("release %s has no binary for %s/%s", release.TagName, runtime.GOOS, runtime.GOARCH)
+	}
+	checksums := release.asset(ChecksumsAsset)
+	if checksums == nil {
+		return fmt.Errorf("release %s has no %s, refusing to install an unverified binary", release.TagName, ChecksumsAsset)
+	}
+
+	var sumsData bytes.Buffer
+	if err := download(checksums.URL, &sumsData, 1<<20); err != nil {
+		return err
+	}
+	expected, ok := parseChecksums(sumsData.Bytes())[name]
+	if !ok {
+		return fmt.Errorf("%s does not list %s", ChecksumsAsset, name)
+	}
+
+	info, err := os.Stat(exePath)
+	if err != nil {
+		return fmt.Errorf("failed to stat executable: %w", err)
+	}
+
+	// Download next to the executable so the final rename stays on one filesystem
+	tmp, err := os.CreateTemp(filepath.Dir(exePath), ".sshm-update-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	hash := sha256.New()
+	err = download(binary.URL, io.MultiWriter(tmp, hash), maxBinarySize)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	if actual := hex.EncodeToString(hash.Sum(nil)); actual != expected {
+		return fmt.Errorf("checksum mismatch for %s: expected %s, got %s", name, expected, actual)
+	}
+	if err := os.Chmod(tmp.Name(), info.Mode().Perm()); err != nil {
+		return fmt.Errorf("failed to set permissions: %w", err)
+	}
+
+	// A running executable can't be overwritten on every platform, but it can be renamed
+	backup := exePath + ".old"
+	os.Remove(backup)
+	if err := os.Rename(exePath, backup); err != nil {
+		return fmt.Errorf("failed to move current executable aside: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), exePath); err != nil {
+		os.Rename(backup, exePath)
+		return fmt.Errorf("failed to install new executable: %w", err)
+	}
+	os.Remove(backup)
+	return nil
+}
+
+// download copies a URL to w, failing when the body exceeds limit bytes
+func download(url string, w io.Writer, limit int64) error {
+	resp, err := httpClient.Get(url)
+	if err != nil {
+		return fmt.Errorf("failed to download %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to download %s: %s", url, resp.Status)
+	}
+
+	n, err := io.Copy(w, io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return fmt.Errorf("failed to download %s: %w", url, err)
+	}
+	if n > limit {
+		return fmt.Errorf("download of %s exceeds %d bytes", url, limit)
+	}
+	return nil
+}
+
+// checkState is the cached result of the last new version check
+type checkState struct {
+	CheckedAt time.Time `json:"checked_at"`
+	Channel   string    `json:"channel"`
+	Latest    string    `json:"latest"`
+}
+
+// DefaultCheckPath returns the new version check cache path next to the configuration file
+func DefaultCheckPath() (string, error) {
+	configPath, err := config.DefaultConfigPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(configPath), CheckFileName), nil
+}
+
+// AvailableVersion returns the newer version on the channel, or "" when up to date.
+// GitHub is asked at most once per CheckInterval; the answer is cached at statePath.
+func AvailableVersion(channel, statePath string) (string, error) {
+	channel, err := NormalizeChannel(channel)
+	if err != nil {
+		return "", err
+	}
+
+	var state checkState
+	if data, err := os.ReadFile(statePath); err == nil {
+		json.Unmarshal(data, &state)
+	}
+
+	if state.Channel != channel || time.Since(state.CheckedAt) >= CheckInterval {
+		release, err := Latest(channel)
+		if err != nil {
+			return "", err
+		}
+		state = checkState{CheckedAt: time.Now(), Channel: channel, Latest: release.TagName}
+		if data, err := json.Marshal(state); err == nil {
+			os.WriteFile(statePath, data, 0600)
+		}
+	}
+
+	if CompareVersions(state.Latest, Version) > 0 {
+		return state.Latest, nil
+	}
+	return "", nil
+}
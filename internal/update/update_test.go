@@ -0,0 +1,182 @@
+package update
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"v1.4.0", "v1.4.0", 0},
+		{"v1.5.0", "v1.4.0", 1},
+		{"1.4.0", "v1.10.0", -1},
+		{"v2.0", "v1.9.9", 1},
+		{"v1.5.0-beta.1", "v1.5.0", -1},
+		{"v1.5.0-beta.2", "v1.5.0-beta.1", 1},
+		{"v1.5.0-beta.10", "v1.5.0-beta.9", 1},
+		{"v1.5.0-beta.1", "v1.4.0", 1},
+	}
+
+	for _, tt := range tests {
+		if got := CompareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("CompareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeChannel(t *testing.T) {
+	if channel, err := NormalizeChannel(""); err != nil || channel != ChannelStable {
+		t.Errorf("Expected empty channel to default to stable, got %q (%v)", channel, err)
+	}
+	if channel, err := NormalizeChannel("Beta"); err != nil || channel != ChannelBeta {
+		t.Errorf("Expected beta channel, got %q (%v)", channel, err)
+	}
+	if _, err := NormalizeChannel("nightly"); err == nil {
+		t.Error("Expected unknown channel to be rejected")
+	}
+}
+
+func serveReleases(t *testing.T, releases []Release) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(releases)
+	}))
+	t.Cleanup(server.Close)
+
+	original := releasesURL
+	releasesURL = server.URL
+	t.Cleanup(func() { releasesURL = original })
+}
+
+func TestLatestByChannel(t *testing.T) {
+	serveReleases(t, []Release{
+		{TagName: "v1.6.0-beta.1", Prerelease: true},
+		{TagName: "v1.7.0", Draft: true},
+		{TagName: "v1.5.0"},
+		{TagName: "v1.4.0"},
+	})
+
+	stable, err := Latest(ChannelStable)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if stable.TagName != "v1.5.0" {
+		t.Errorf("Expected stable v1.5.0, got %s", stable.TagName)
+	}
+
+	beta, err := Latest(ChannelBeta)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if beta.TagName != "v1.6.0-beta.1" {
+		t.Errorf("Expected beta v1.6.0-beta.1, got %s", beta.TagName)
+	}
+}
+
+func TestParseChecksums(t *testing.T) {
+	sums := parseChecksums([]byte("abc123  sshm_linux_amd64\nDEF456 *sshm_windows_amd64.exe\n\nmalformed\n"))
+	if sums["sshm_linux_amd64"] != "abc123" {
+		t.Errorf("Unexpected linux checksum: %q", sums["sshm_linux_amd64"])
+	}
+	if sums["sshm_windows_amd64.exe"] != "def456" {
+		t.Errorf("Unexpected windows checksum: %q", sums["sshm_windows_amd64.exe"])
+	}
+	if len(sums) != 2 {
+		t.Errorf("Expected 2 checksums, got %d", len(sums))
+	}
+}
+
+func releaseServer(t *testing.T, binary []byte, checksum string) *Release {
+	name := AssetName(runtime.GOOS, runtime.GOARCH)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/" + name:
+			w.Write(binary)
+		case "/" + ChecksumsAsset:
+			fmt.Fprintf(w, "%s  %s\n", checksum, name)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return &Release{TagName: "v9.9.9", Assets: []Asset{
+		{Name: name, URL: server.URL + "/" + name},
+		{Name: ChecksumsAsset, URL: server.URL + "/" + ChecksumsAsset},
+	}}
+}
+
+func TestApplyReplacesExecutable(t *testing.T) {
+	binary := []byte("new sshm binary")
+	sum := sha256.Sum256(binary)
+	release := releaseServer(t, binary, hex.EncodeToString(sum[:]))
+
+	exePath := filepath.Join(t.TempDir(), "sshm")
+	if err := os.WriteFile(exePath, []byte("old sshm binary"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Apply(release, exePath); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	data, _ := os.ReadFile(exePath)
+	if string(data) != string(binary) {
+		t.Errorf("Expected executable to be replaced, got %q", data)
+	}
+	if info, _ := os.Stat(exePath); info.Mode().Perm() != 0755 {
+		t.Errorf("Expected permissions to be preserved, got %v", info.Mode().Perm())
+	}
+	if _, err := os.Stat(exePath + ".old"); !os.IsNotExist(err) {
+		t.Error("Expected backup to be removed")
+	}
+}
+
+func TestApplyRejectsChecksumMismatch(t *testing.T) {
+	release := releaseServer(t, []byte("tampered binary"), "0000")
+
+	exePath := filepath.Join(t.TempDir(), "sshm")
+	os.WriteFile(exePath, []byte("old sshm binary"), 0755)
+
+	if err := Apply(release, exePath); err == nil {
+		t.Fatal("Expected checksum mismatch error")
+	}
+
+	data, _ := os.ReadFile(exePath)
+	if string(data) != "old sshm binary" {
+		t.Errorf("Expected executable to be untouched, got %q", data)
+	}
+	entries, _ := os.ReadDir(filepath.Dir(exePath))
+	if len(entries) != 1 {
+		t.Errorf("Expected temporary download to be cleaned up, found %d files", len(entries))
+	}
+}
+
+func TestAvailableVersionUsesCache(t *testing.T) {
+	serveReleases(t, []Release{{TagName: "v99.0.0"}})
+	statePath := filepath.Join(t.TempDir(), CheckFileName)
+
+	latest, err := AvailableVersion("", statePath)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if latest != "v99.0.0" {
+		t.Errorf("Expected v99.0.0 to be available, got %q", latest)
+	}
+
+	// A fresh cached answer is reused without asking GitHub again
+	releasesURL = "http://127.0.0.1:0/unreachable"
+	if latest, err := AvailableVersion(ChannelStable, statePath); err != nil || latest != "v99.0.0" {
+		t.Errorf("Expected cached v99.0.0, got %q (%v)", latest, err)
+	}
+}
@@ -0,0 +1,196 @@
+// Package warmpool keeps OpenSSH master connections open to chosen servers, so
+// sessions attach over an authenticated connection instead of a fresh handshake
+package warmpool
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"sshm/internal/config"
+	"sshm/internal/monitor"
+	"sshm/internal/secrets"
+	sshmssh "sshm/internal/ssh"
+)
+
+// RefreshInterval is how often a running pool reopens connections that closed
+const RefreshInterval = time.Minute
+
+// execCommand is a variable to allow mocking in tests
+var execCommand = exec.Command
+
+// Entry is the state of one server in the pool
+type Entry struct {
+	Server string
+	Socket string
+	Warm   bool  // A master connection is open
+	Err    error // Why the last attempt to open one failed
+}
+
+// Pool remembers the master connections it opened, to close those of servers
+// that leave the pool
+type Pool struct {
+	mu     sync.Mutex
+	warmed map[string]config.Server
+}
+
+// New creates an empty pool
+func New() *Pool {
+	return &Pool{warmed: make(map[string]config.Server)}
+}
+
+// Refresh opens a master connection to every server the configuration keeps
+// warm that has none, and closes the ones this pool opened for servers no
+// longer in it, e.g. after a server stopped being watched
+func (p *Pool) Refresh(cfg *config.Config) []Entry {
+	servers := cfg.WarmServers()
+	idle := cfg.WarmPool.IdleTimeout()
+
+	wanted := make(map[string]bool, len(servers))
+	entries := make([]Entry, 0, len(servers))
+	for _, server := range servers {
+		wanted[server.Name] = true
+		socket, _ := server.WarmSocketPath()
+		entry := Entry{Server: server.Name, Socket: socket, Warm: IsWarm(server)}
+		if !entry.Warm {
+			if err := Open(server, idle); err != nil {
+				entry.Err = err
+			} else {
+				entry.Warm = true
+			}
+		}
+		if entry.Warm {
+			p.mu.Lock()
+			p.warmed[server.Name] = server
+			p.mu.Unlock()
+		}
+		entries = append(entries, entry)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for name, server := range p.warmed {
+		if !wanted[name] {
+			_ = Close(server)
+			delete(p.warmed, name)
+		}
+	}
+	return entries
+}
+
+// CloseAll closes every master connection this pool opened
+func (p *Pool) CloseAll() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for name, server := range p.warmed {
+		_ = Close(server)
+		delete(p.warmed, name)
+	}
+}
+
+// Open starts a master connection to the server in the background. It never
+// prompts: servers must authenticate with a key, the agent, a provider or a
+// keyring password. Without sessions the connection closes after idle.
+func Open(server config.Server, idle time.Duration) error {
+	socket, err := server.WarmSocketPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(socket), 0700); err != nil {
+		return fmt.Errorf("failed to create the socket directory: %w", err)
+	}
+
+	args := []string{
+		"-f", "-N", "-M",
+		"-o", "ControlPath=" + socket,
+		"-o", fmt.Sprintf("ControlPersist=%ds", int(idle.Seconds())),
+		"-o", "ConnectTimeout=10",
+		"-o", "ServerAliveInterval=30",
+		"-o", "ServerAliveCountMax=3",
+	}
+	if server.Port != 0 && server.Port != 22 {
+		args = append(args, "-p", fmt.Sprintf("%d", server.Port))
+	}
+	if server.AuthType == "key" && server.KeyPath != "" {
+		args = append(args, "-i", server.KeyPath)
+	}
+	if server.AuthProvider != "" {
+		provider, err := sshmssh.LookupAuthProvider(server.AuthProvider)
+		if err != nil {
+			return err
+		}
+		providerArgs, err := provider.SSHArgs(monitor.ProviderRequest(server))
+		if err != nil {
+			return fmt.Errorf("auth provider '%s': %w", server.AuthProvider, err)
+		}
+		args = append(args, providerArgs...)
+	}
+	args = append(args, strings.Fields(server.GetSSHOptions())...)
+	destination := fmt.Sprintf("%s@%s", server.Username, server.GetEffectiveHostname())
+
+	var cmd *exec.Cmd
+	if server.AuthType == "password" || secrets.Referenced(server) {
+		if !secrets.Referenced(server) {
+			return fmt.Errorf("password servers can only be kept warm with the password in the keyring")
+		}
+		flags, secret, err := secrets.SSHPass(server)
+		if err != nil {
+			return fmt.Errorf("failed to retrieve secret from keyring: %w", err)
+		}
+		cmd = execCommand("sshpass", append(append(flags, "-e", "ssh"), append(args, destination)...)...)
+		cmd.Env = append(os.Environ(), "SSHPASS="+secret)
+	} else {
+		args = append(args, "-o", "BatchMode=yes", destination)
+		cmd = execCommand("ssh", args...)
+	}
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	// ssh forks once authenticated; don't wait on a pipe the master may keep open
+	cmd.WaitDelay = time.Second
+	if err := cmd.Run(); err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return fmt.Errorf("failed to open a warm connection: %s", message)
+		}
+		return fmt.Errorf("failed to open a warm connection: %w", err)
+	}
+	return nil
+}
+
+// IsWarm reports whether the server's master connection is open and answering
+func IsWarm(server config.Server) bool {
+	if !server.HasWarmConnection() {
+		return false
+	}
+	return control(server, "check") == nil
+}
+
+// Close closes the server's master connection, ending the sessions using it
+func Close(server config.Server) error {
+	if !server.HasWarmConnection() {
+		return nil
+	}
+	return control(server, "exit")
+}
+
+// control sends a control command ("check" or "exit") to the master connection
+func control(server config.Server, command string) error {
+	socket, err := server.WarmSocketPath()
+	if err != nil {
+		return err
+	}
+	destination := fmt.Sprintf("%s@%s", server.Username, server.GetEffectiveHostname())
+	output, err := execCommand("ssh", "-O", command, "-o", "ControlPath="+socket, destination).CombinedOutput()
+	if err != nil {
+		if message := strings.TrimSpace(string(output)); message != "" {
+			return fmt.Errorf("%s", message)
+		}
+		return err
+	}
+	return nil
+}
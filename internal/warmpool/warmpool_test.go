@@ -0,0 +1,129 @@
+package warmpool
+
+import (
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"sshm/internal/config"
+)
+
+// mockExec records every command and runs true instead
+func mockExec(t *testing.T, calls *[][]string) {
+	original := execCommand
+	execCommand = func(name string, arg ...string) *exec.Cmd {
+		*calls = append(*calls, append([]string{name}, arg...))
+		return exec.Command("true")
+	}
+	t.Cleanup(func() { execCommand = original })
+}
+
+func testServer(name string) config.Server {
+	return config.Server{
+		Name:     name,
+		Hostname: name + ".example.com",
+		Port:     2222,
+		Username: "ops",
+		AuthType: "key",
+		KeyPath:  "/keys/id_ed25519",
+		Warm:     true,
+	}
+}
+
+// listenOn creates the server's warm socket, as an open master connection would
+func listenOn(t *testing.T, server config.Server) {
+	socket, err := server.WarmSocketPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(socket), 0700); err != nil {
+		t.Fatal(err)
+	}
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatalf("Failed to create socket: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+}
+
+func TestOpenStartsBackgroundMaster(t *testing.T) {
+	t.Setenv("SSHM_CONFIG_DIR", t.TempDir())
+	var calls [][]string
+	mockExec(t, &calls)
+
+	server := testServer("db")
+	if err := Open(server, 15*time.Minute); err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	if len(calls) != 1 {
+		t.Fatalf("Expected one ssh command, got %v", calls)
+	}
+	socket, _ := server.WarmSocketPath()
+	command := strings.Join(calls[0], " ")
+	for _, want := range []string{"ssh -f -N -M", "ControlPath=" + socket, "ControlPersist=900s", "-p 2222", "-i /keys/id_ed25519", "BatchMode=yes"} {
+		if !strings.Contains(command, want) {
+			t.Errorf("Expected %q in %q", want, command)
+		}
+	}
+	if last := calls[0][len(calls[0])-1]; last != "ops@db.example.com" {
+		t.Errorf("Expected the destination last, got %q", last)
+	}
+}
+
+func TestOpenRefusesPromptingPasswords(t *testing.T) {
+	t.Setenv("SSHM_CONFIG_DIR", t.TempDir())
+	var calls [][]string
+	mockExec(t, &calls)
+
+	server := testServer("legacy")
+	server.AuthType = "password"
+	if err := Open(server, time.Minute); err == nil || !strings.Contains(err.Error(), "keyring") {
+		t.Errorf("Expected a keyring error, got %v", err)
+	}
+	if len(calls) != 0 {
+		t.Errorf("Expected no ssh command, got %v", calls)
+	}
+}
+
+func TestRefresh(t *testing.T) {
+	t.Setenv("SSHM_CONFIG_DIR", t.TempDir())
+	var calls [][]string
+	mockExec(t, &calls)
+
+	open, missing := testServer("open"), testServer("missing")
+	listenOn(t, open)
+	cfg := &config.Config{
+		Servers:  []config.Server{open, missing},
+		WarmPool: config.WarmPoolConfig{Enabled: true},
+	}
+
+	pool := New()
+	entries := pool.Refresh(cfg)
+	if len(entries) != 2 || !entries[0].Warm || !entries[1].Warm {
+		t.Fatalf("Expected both servers warm, got %+v", entries)
+	}
+	if got := strings.Join(calls[0][:2], " "); got != "ssh -O" || calls[0][2] != "check" {
+		t.Errorf("Expected the open connection to be checked, got %v", calls[0])
+	}
+	if !strings.Contains(strings.Join(calls[1], " "), "-f -N -M") {
+		t.Errorf("Expected the missing connection to be opened, got %v", calls[1])
+	}
+
+	// Servers leaving the pool have their connection closed
+	calls = nil
+	cfg.Servers[0].Warm = false
+	pool.Refresh(cfg)
+	closed := false
+	for _, call := range calls {
+		if len(call) > 2 && call[1] == "-O" && call[2] == "exit" && strings.HasSuffix(call[len(call)-1], "open.example.com") {
+			closed = true
+		}
+	}
+	if !closed {
+		t.Errorf("Expected the connection of the server that left the pool to be closed, got %v", calls)
+	}
+}
@@ -0,0 +1,194 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"sshm/internal/config"
+)
+
+// Timeout is how long a webhook call may take
+const Timeout = 10 * time.Second
+
+// placeholder matches a {placeholder} in a payload
+var placeholder = regexp.MustCompile(`\{([^{}]*)\}`)
+
+// Transition is a server going online or offline between two status checks
+type Transition struct {
+	Server string    `json:"server"`
+	Host   string    `json:"host"`
+	From   string    `json:"from"` // config.WebhookOnOnline or config.WebhookOnOffline
+	To     string    `json:"to"`
+	Status string    `json:"status"` // Status reported by the check, e.g. "timeout"
+	At     time.Time `json:"at"`
+}
+
+// Reachability maps a status check result to config.WebhookOnOnline or
+// config.WebhookOnOffline, or "" while the status is not known
+func Reachability(status string) string {
+	switch status {
+	case "online":
+		return config.WebhookOnOnline
+	case "", "checking", "unknown", "scheduled", "offline mode":
+		return ""
+	default:
+		return config.WebhookOnOffline
+	}
+}
+
+// Tracker remembers the reachability of each server and calls its webhooks when
+// it changes. The first status seen for a server only sets the baseline, so
+// starting sshm does not fire every webhook.
+type Tracker struct {
+	mu    sync.Mutex
+	state map[string]string
+
+	// OnError receives failed calls; they are dropped when nil
+	OnError func(server string, hook config.Webhook, err error)
+
+	// send performs a call, a variable so tests can avoid the network
+	send func(ctx context.Context, hook config.Webhook, body []byte) error
+}
+
+// NewTracker creates a tracker with no known server states
+func NewTracker() *Tracker {
+	return &Tracker{state: make(map[string]string), send: Send}
+}
+
+// Observe records the status of a server's latest check and, when its
+// reachability changed, calls the webhooks configured for the new state in the
+// background. It returns the transition, nil when there was none.
+func (t *Tracker) Observe(server config.Server, status string, at time.Time) *Transition {
+	to := Reachability(status)
+	if to == "" {
+		return nil
+	}
+
+	t.mu.Lock()
+	from, known := t.state[server.Name]
+	t.state[server.Name] = to
+	t.mu.Unlock()
+	if !known || from == to {
+		return nil
+	}
+
+	transition := &Transition{Server: server.Name, Host: server.Hostname, From: from, To: to, Status: status, At: at}
+	for _, hook := range server.Webhooks {
+		if !hook.Fires(to) {
+			continue
+		}
+		go func(hook config.Webhook) {
+			ctx, cancel := context.WithTimeout(context.Background(), Timeout)
+			defer cancel()
+			if err := t.send(ctx, hook, Payload(hook, *transition)); err != nil && t.OnError != nil {
+				t.OnError(server.Name, hook, err)
+			}
+		}(hook)
+	}
+	return transition
+}
+
+// Record sets the reachability of a server without calling its webhooks, for
+// while another process owns them. Later observations compare against it.
+func (t *Tracker) Record(server config.Server, status string) {
+	to := Reachability(status)
+	if to == "" {
+		return
+	}
+	t.mu.Lock()
+	t.state[server.Name] = to
+	t.mu.Unlock()
+}
+
+// Payload renders the body of a webhook call: the payload template with its
+// placeholders substituted, or the transition as JSON when there is none. The
+// values are escaped for JSON string literals when the template is JSON, so a
+// server name can't break or extend the document.
+func Payload(hook config.Webhook, transition Transition) []byte {
+	if strings.TrimSpace(hook.Payload) == "" {
+		data, _ := json.Marshal(transition)
+		return data
+	}
+	values := map[string]string{
+		"name": transition.Server,
+		"host": transition.Host,
+		"from": transition.From,
+		"to":   transition.To,
+		"time": transition.At.UTC().Format(time.RFC3339),
+	}
+	if isJSONTemplate(hook) {
+		for name, value := range values {
+			encoded, _ := json.Marshal(value)
+			values[name] = string(encoded[1 : len(encoded)-1])
+		}
+	}
+	return []byte(substitute(hook.Payload, values))
+}
+
+// isJSONTemplate reports whether a payload template renders JSON, or is sent as
+// JSON through its Content-Type header
+func isJSONTemplate(hook config.Webhook) bool {
+	for name, value := range hook.Headers {
+		if strings.EqualFold(name, "Content-Type") && strings.Contains(strings.ToLower(value), "json") {
+			return true
+		}
+	}
+	return json.Valid([]byte(substitute(hook.Payload, nil)))
+}
+
+// substitute replaces the placeholders of a payload template with values; a
+// nil map replaces every placeholder with a plain word
+func substitute(template string, values map[string]string) string {
+	return placeholder.ReplaceAllStringFunc(template, func(match string) string {
+		if values == nil {
+			return "x"
+		}
+		if value, ok := values[match[1:len(match)-1]]; ok {
+			return value
+		}
+		return match
+	})
+}
+
+// Send calls a webhook with body. GET requests carry no body. Responses other
+// than 2xx are errors.
+func Send(ctx context.Context, hook config.Webhook, body []byte) error {
+	method := hook.GetMethod()
+	var reader io.Reader
+	if method != http.MethodGet {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, hook.URL, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	if reader != nil {
+		if json.Valid(body) {
+			req.Header.Set("Content-Type", "application/json")
+		} else {
+			req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+		}
+	}
+	for name, value := range hook.Headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook %s failed: %w", hook.URL, err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook %s returned %s", hook.URL, resp.Status)
+	}
+	return nil
+}
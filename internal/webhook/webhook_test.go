@@ -0,0 +1,173 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"sshm/internal/config"
+)
+
+func TestReachability(t *testing.T) {
+	tests := map[string]string{
+		"online":       config.WebhookOnOnline,
+		"timeout":      config.WebhookOnOffline,
+		"auth failed":  config.WebhookOnOffline,
+		"checking":     "",
+		"scheduled":    "",
+		"offline mode": "",
+		"":             "",
+	}
+	for status, want := range tests {
+		if got := Reachability(status); got != want {
+			t.Errorf("Reachability(%q) = %q, want %q", status, got, want)
+		}
+	}
+}
+
+func TestObserveFiresOnTransitionsOnly(t *testing.T) {
+	calls := make(chan string, 10)
+	tracker := NewTracker()
+	tracker.send = func(ctx context.Context, hook config.Webhook, body []byte) error {
+		calls <- hook.URL + " " + string(body)
+		return nil
+	}
+
+	server := config.Server{Name: "staging", Hostname: "staging.example.com", Webhooks: []config.Webhook{
+		{URL: "https://ci.example.com/deploy", On: config.WebhookOnOnline, Payload: "{name} is {to}"},
+		{URL: "https://ci.example.com/any"},
+	}}
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	if transition := tracker.Observe(server, "timeout", at); transition != nil {
+		t.Fatalf("first status must only set the baseline, got %+v", transition)
+	}
+	if transition := tracker.Observe(server, "checking", at); transition != nil {
+		t.Fatalf("transient status must be ignored, got %+v", transition)
+	}
+	if transition := tracker.Observe(server, "timeout", at); transition != nil {
+		t.Fatalf("unchanged state must not fire, got %+v", transition)
+	}
+
+	transition := tracker.Observe(server, "online", at)
+	if transition == nil || transition.From != config.WebhookOnOffline || transition.To != config.WebhookOnOnline {
+		t.Fatalf("expected an offline→online transition, got %+v", transition)
+	}
+	got := map[string]bool{}
+	for i := 0; i < 2; i++ {
+		select {
+		case call := <-calls:
+			got[strings.SplitN(call, " ", 2)[0]] = true
+			if strings.HasPrefix(call, "https://ci.example.com/deploy ") && call != "https://ci.example.com/deploy staging is online" {
+				t.Errorf("unexpected payload: %s", call)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("webhook was not called")
+		}
+	}
+	if !got["https://ci.example.com/deploy"] || !got["https://ci.example.com/any"] {
+		t.Errorf("expected both webhooks to be called, got %v", got)
+	}
+
+	// Going offline only calls the webhook without an "on" filter
+	tracker.Observe(server, "connection refused", at)
+	select {
+	case call := <-calls:
+		if !strings.HasPrefix(call, "https://ci.example.com/any ") {
+			t.Errorf("unexpected call: %s", call)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("webhook was not called")
+	}
+	select {
+	case call := <-calls:
+		t.Errorf("unexpected extra call: %s", call)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestRecordDoesNotFire(t *testing.T) {
+	tracker := NewTracker()
+	calls := make(chan string, 4)
+	tracker.send = func(ctx context.Context, hook config.Webhook, body []byte) error {
+		calls <- hook.URL
+		return nil
+	}
+	server := config.Server{Name: "web", Webhooks: []config.Webhook{{URL: "https://ci.example.com/any"}}}
+	at := time.Now()
+
+	// While the daemon owns the webhooks the tracker only follows the state
+	tracker.Observe(server, "timeout", at)
+	tracker.Record(server, "online")
+	if transition := tracker.Observe(server, "online", at); transition != nil {
+		t.Errorf("Expected the recorded state to be the baseline, got %+v", transition)
+	}
+	select {
+	case call := <-calls:
+		t.Errorf("unexpected call: %s", call)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestPayloadDefaultsToJSON(t *testing.T) {
+	transition := Transition{Server: "web", Host: "web.example.com", From: "offline", To: "online", Status: "online", At: time.Unix(0, 0).UTC()}
+	body := string(Payload(config.Webhook{URL: "https://example.com"}, transition))
+	for _, want := range []string{`"server":"web"`, `"from":"offline"`, `"to":"online"`} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected %s in %s", want, body)
+		}
+	}
+
+	body = string(Payload(config.Webhook{Payload: `{"text": "{name} ({host}) {from}→{to} at {time}"}`}, transition))
+	if body != `{"text": "web (web.example.com) offline→online at 1970-01-01T00:00:00Z"}` {
+		t.Errorf("unexpected payload: %s", body)
+	}
+}
+
+func TestPayloadEscapesJSONTemplates(t *testing.T) {
+	transition := Transition{Server: `web", "admin": true, "x": "`, Host: "web\\1", From: "offline", To: "online", At: time.Unix(0, 0).UTC()}
+	body := Payload(config.Webhook{Payload: `{"text": "{name} on {host} is {to}"}`}, transition)
+	var decoded map[string]any
+	if err := json.Unmarshal(body, &decoded); err != nil || len(decoded) != 1 {
+		t.Fatalf("Expected one JSON field, got %s (%v)", body, err)
+	}
+	if want := transition.Server + " on " + transition.Host + " is online"; decoded["text"] != want {
+		t.Errorf("text = %q, want %q", decoded["text"], want)
+	}
+
+	// Plain text templates are left alone
+	body = Payload(config.Webhook{Payload: `{name} is "{to}"`}, transition)
+	if string(body) != transition.Server+` is "online"` {
+		t.Errorf("unexpected plain payload: %s", body)
+	}
+}
+
+func TestSend(t *testing.T) {
+	var method, contentType, auth, body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, contentType, auth = r.Method, r.Header.Get("Content-Type"), r.Header.Get("Authorization")
+		data, _ := io.ReadAll(r.Body)
+		body = string(data)
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	hook := config.Webhook{URL: server.URL + "/ok", Headers: map[string]string{"Authorization": "Bearer secret"}}
+	if err := Send(context.Background(), hook, []byte(`{"a":1}`)); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if method != "POST" || contentType != "application/json" || auth != "Bearer secret" || body != `{"a":1}` {
+		t.Errorf("unexpected request: %s %s %s %s", method, contentType, auth, body)
+	}
+
+	if err := Send(context.Background(), config.Webhook{URL: server.URL + "/fail"}, []byte("down")); err == nil {
+		t.Error("expected an error for a 500 response")
+	}
+}
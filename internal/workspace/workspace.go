@@ -0,0 +1,249 @@
+// Package workspace saves the layout of sshm's tmux sessions as named workspaces
+// and recreates them later, e.g. to resume an incident layout after a reboot.
+package workspace
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"gopkg.in/yaml.v3"
+	"sshm/internal/config"
+	"sshm/internal/connection"
+	"sshm/internal/tmux"
+)
+
+// FileName is the workspace file stored next to config.yaml
+const FileName = "workspaces.yaml"
+
+// Window is a saved tmux window; Server is empty for a window not connected to a known server
+type Window struct {
+	Name   string `yaml:"name"`
+	Server string `yaml:"server,omitempty"`
+}
+
+// Session is a saved tmux session with its windows in index order
+type Session struct {
+	Name    string   `yaml:"name"`
+	Windows []Window `yaml:"windows"`
+}
+
+// Workspace is a named snapshot of sshm's tmux sessions
+type Workspace struct {
+	Name      string    `yaml:"name"`
+	CreatedAt time.Time `yaml:"created_at"`
+	Sessions  []Session `yaml:"sessions"`
+}
+
+// ServerNames returns the distinct servers connected in the workspace
+func (w Workspace) ServerNames() []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, session := range w.Sessions {
+		for _, window := range session.Windows {
+			if window.Server != "" && !seen[window.Server] {
+				seen[window.Server] = true
+				names = append(names, window.Server)
+			}
+		}
+	}
+	return names
+}
+
+// Store holds every saved workspace
+type Store struct {
+	Workspaces []Workspace `yaml:"workspaces"`
+}
+
+// DefaultPath returns the workspace file path next to the configuration file
+func DefaultPath() (string, error) {
+	configPath, err := config.DefaultConfigPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(configPath), FileName), nil
+}
+
+// Load reads the workspace file; a missing file yields an empty store
+func Load(path string) (*Store, error) {
+	store := &Store{}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read workspaces: %w", err)
+	}
+	if err := yaml.Unmarshal(data, store); err != nil {
+		return nil, fmt.Errorf("failed to parse workspaces: %w", err)
+	}
+	return store, nil
+}
+
+// Save writes the workspace file
+func (s *Store) Save(path string) error {
+	data, err := yaml.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("failed to marshal workspaces: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create workspace directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write workspaces: %w", err)
+	}
+	return nil
+}
+
+// Get returns the workspace with the given name
+func (s *Store) Get(name string) (*Workspace, error) {
+	for i := range s.Workspaces {
+		if s.Workspaces[i].Name == name {
+			return &s.Workspaces[i], nil
+		}
+	}
+	return nil, fmt.Errorf("workspace '%s' not found", name)
+}
+
+// Put adds a workspace, replacing one with the same name, and keeps the store sorted by name
+func (s *Store) Put(workspace Workspace) {
+	for i := range s.Workspaces {
+		if s.Workspaces[i].Name == workspace.Name {
+			s.Workspaces[i] = workspace
+			return
+		}
+	}
+	s.Workspaces = append(s.Workspaces, workspace)
+	sort.Slice(s.Workspaces, func(i, j int) bool { return s.Workspaces[i].Name < s.Workspaces[j].Name })
+}
+
+// Delete removes a workspace
+func (s *Store) Delete(name string) error {
+	for i := range s.Workspaces {
+		if s.Workspaces[i].Name == name {
+			s.Workspaces = append(s.Workspaces[:i], s.Workspaces[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("workspace '%s' not found", name)
+}
+
+// Lister lists tmux sessions and their windows
+type Lister interface {
+	ListSessions() ([]string, error)
+	ListWindows(sessionName string) ([]string, error)
+}
+
+// Capture snapshots the tmux sessions that belong to sshm: single-server sessions
+// named after a server and group sessions whose windows are named after servers.
+// Other tmux sessions are left out.
+func Capture(name string, tm Lister, serverNames []string) (Workspace, error) {
+	workspace := Workspace{Name: name, CreatedAt: time.Now()}
+
+	sessionNames, err := tm.ListSessions()
+	if err != nil {
+		return workspace, err
+	}
+	known := make(map[string]bool, len(serverNames))
+	for _, serverName := range serverNames {
+		known[serverName] = true
+	}
+
+	for _, sessionName := range sessionNames {
+		windowNames, err := tm.ListWindows(sessionName)
+		if err != nil {
+			return workspace, err
+		}
+
+		session := Session{Name: sessionName}
+		connected := false
+		sessionServer := tmux.ServerForSession(sessionName, serverNames)
+		for _, windowName := range windowNames {
+			window := Window{Name: windowName}
+			switch {
+			case known[windowName]:
+				window.Server = windowName
+			case sessionServer != "" && len(windowNames) == 1:
+				// Single-server sessions keep tmux's automatic window name
+				window.Server = sessionServer
+			}
+			connected = connected || window.Server != ""
+			session.Windows = append(session.Windows, window)
+		}
+		if connected {
+			workspace.Sessions = append(workspace.Sessions, session)
+		}
+	}
+
+	if len(workspace.Sessions) == 0 {
+		return workspace, fmt.Errorf("no sshm sessions are running")
+	}
+	return workspace, nil
+}
+
+// Creator checks for and creates tmux sessions
+type Creator interface {
+	SessionExists(sessionName string) bool
+	CreateSessionWithWindows(sessionName string, windows []tmux.Window) error
+}
+
+// Result is the outcome of restoring one session
+type Result struct {
+	Session        string
+	Skipped        bool     // The session was already running
+	MissingServers []string // Servers no longer in the configuration; their windows open a plain shell
+	BannerServers  []string // Servers whose login banner must be accepted first; their windows open a plain shell
+	Err            error
+}
+
+// Restore recreates the workspace's sessions that are not running and reconnects
+// their windows to the servers in cfg. Servers that require their login banner
+// accepted are only connected to when acks has them.
+func Restore(workspace Workspace, tm Creator, cfg *config.Config, acks connection.BannerAcks) []Result {
+	var results []Result
+	for _, session := range workspace.Sessions {
+		result := Result{Session: session.Name}
+		if tm.SessionExists(session.Name) {
+			result.Skipped = true
+			results = append(results, result)
+			continue
+		}
+
+		windows := make([]tmux.Window, 0, len(session.Windows))
+		for _, saved := range session.Windows {
+			window := tmux.Window{Name: saved.Name}
+			if saved.Server != "" {
+				server, err := cfg.GetServer(saved.Server)
+				if err != nil {
+					result.MissingServers = append(result.MissingServers, saved.Server)
+				} else if server.RequireBannerAck && !acks[server.Name] {
+					result.BannerServers = append(result.BannerServers, server.Name)
+				} else {
+					withBootstrap := cfg.WithLogin(cfg.WithSSHTemplate(*server))
+					for _, profile := range cfg.Profiles {
+						if contains(profile.Servers, server.Name) {
+							withBootstrap = profile.WithBootstrap(withBootstrap)
+						}
+					}
+					window.Server = connection.TmuxServer(withBootstrap, acks)
+				}
+			}
+			windows = append(windows, window)
+		}
+
+		result.Err = tm.CreateSessionWithWindows(session.Name, windows)
+		results = append(results, result)
+	}
+	return results
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
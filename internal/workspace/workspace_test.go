@@ -0,0 +1,159 @@
+package workspace
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"sshm/internal/config"
+	"sshm/internal/connection"
+	"sshm/internal/tmux"
+)
+
+type fakeTmux struct {
+	windows  map[string][]string
+	sessions []string
+	created  map[string][]tmux.Window
+}
+
+func (f *fakeTmux) ListSessions() ([]string, error) { return f.sessions, nil }
+
+func (f *fakeTmux) ListWindows(sessionName string) ([]string, error) {
+	return f.windows[sessionName], nil
+}
+
+func (f *fakeTmux) SessionExists(sessionName string) bool {
+	_, ok := f.windows[sessionName]
+	return ok
+}
+
+func (f *fakeTmux) CreateSessionWithWindows(sessionName string, windows []tmux.Window) error {
+	if sessionName == "broken" {
+		return errors.New("tmux failed")
+	}
+	if f.created == nil {
+		f.created = make(map[string][]tmux.Window)
+	}
+	f.created[sessionName] = windows
+	return nil
+}
+
+func TestCapture(t *testing.T) {
+	tm := &fakeTmux{
+		sessions: []string{"web_example", "web_example-1", "prod", "notes"},
+		windows: map[string][]string{
+			"web_example":   {"ssh"},
+			"web_example-1": {"ssh"},
+			"prod":          {"api", "db", "logs"},
+			"notes":         {"vim"},
+		},
+	}
+
+	workspace, err := Capture("incident", tm, []string{"web.example", "api", "db"})
+	if err != nil {
+		t.Fatalf("Capture failed: %v", err)
+	}
+
+	want := []Session{
+		{Name: "web_example", Windows: []Window{{Name: "ssh", Server: "web.example"}}},
+		{Name: "web_example-1", Windows: []Window{{Name: "ssh", Server: "web.example"}}},
+		{Name: "prod", Windows: []Window{{Name: "api", Server: "api"}, {Name: "db", Server: "db"}, {Name: "logs"}}},
+	}
+	if !reflect.DeepEqual(workspace.Sessions, want) {
+		t.Errorf("Capture() sessions = %+v, want %+v", workspace.Sessions, want)
+	}
+	if names := workspace.ServerNames(); !reflect.DeepEqual(names, []string{"web.example", "api", "db"}) {
+		t.Errorf("ServerNames() = %v", names)
+	}
+}
+
+func TestCaptureWithoutSessions(t *testing.T) {
+	tm := &fakeTmux{sessions: []string{"notes"}, windows: map[string][]string{"notes": {"vim"}}}
+	if _, err := Capture("empty", tm, []string{"web"}); err == nil {
+		t.Error("Expected error when no sshm sessions are running")
+	}
+}
+
+func TestRestore(t *testing.T) {
+	cfg := &config.Config{Servers: []config.Server{
+		{Name: "api", Hostname: "api.example.com", Port: 22, Username: "ops", AuthType: "key", KeyPath: "~/.ssh/id_ed25519"},
+	}}
+	tm := &fakeTmux{windows: map[string][]string{"running": {"ssh"}}}
+	workspace := Workspace{Name: "incident", Sessions: []Session{
+		{Name: "running", Windows: []Window{{Name: "ssh", Server: "api"}}},
+		{Name: "prod", Windows: []Window{{Name: "api", Server: "api"}, {Name: "old", Server: "decommissioned"}}},
+		{Name: "broken", Windows: []Window{{Name: "api", Server: "api"}}},
+	}}
+
+	results := Restore(workspace, tm, cfg, nil)
+	if len(results) != 3 {
+		t.Fatalf("Expected 3 results, got %d", len(results))
+	}
+	if !results[0].Skipped {
+		t.Error("Expected running session to be skipped")
+	}
+	if results[1].Err != nil || !reflect.DeepEqual(results[1].MissingServers, []string{"decommissioned"}) {
+		t.Errorf("Unexpected result for prod: %+v", results[1])
+	}
+	if results[2].Err == nil {
+		t.Error("Expected error for broken session")
+	}
+
+	windows := tm.created["prod"]
+	if len(windows) != 2 || windows[0].Server == nil || windows[0].Server.GetName() != "api" || windows[1].Server != nil {
+		t.Errorf("Unexpected windows created for prod: %+v", windows)
+	}
+}
+
+func TestRestoreRefusesUnacknowledgedBanners(t *testing.T) {
+	cfg := &config.Config{Servers: []config.Server{
+		{Name: "api", Hostname: "api.example.com", Port: 22, Username: "ops", AuthType: "key", KeyPath: "~/.ssh/id_ed25519", RequireBannerAck: true},
+		{Name: "db", Hostname: "db.example.com", Port: 22, Username: "ops", AuthType: "key", KeyPath: "~/.ssh/id_ed25519", RequireBannerAck: true},
+	}}
+	tm := &fakeTmux{windows: map[string][]string{}}
+	workspace := Workspace{Name: "incident", Sessions: []Session{
+		{Name: "prod", Windows: []Window{{Name: "api", Server: "api"}, {Name: "db", Server: "db"}}},
+	}}
+
+	results := Restore(workspace, tm, cfg, connection.BannerAcks{"db": true})
+	if len(results) != 1 || !reflect.DeepEqual(results[0].BannerServers, []string{"api"}) {
+		t.Fatalf("Expected api's window to wait for its banner, got %+v", results)
+	}
+	windows := tm.created["prod"]
+	if len(windows) != 2 || windows[0].Server != nil || windows[1].Server == nil {
+		t.Fatalf("Unexpected windows created for prod: %+v", windows)
+	}
+	if _, err := windows[1].Server.(tmux.CommandBuilder).SSHCommand(); err != nil {
+		t.Errorf("Expected the accepted server's window to connect, got %v", err)
+	}
+}
+
+func TestStoreRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+
+	store, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load of missing file failed: %v", err)
+	}
+	store.Put(Workspace{Name: "b", Sessions: []Session{{Name: "s"}}})
+	store.Put(Workspace{Name: "a"})
+	store.Put(Workspace{Name: "b"})
+	if err := store.Save(path); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(loaded.Workspaces) != 2 || loaded.Workspaces[0].Name != "a" {
+		t.Errorf("Unexpected workspaces: %+v", loaded.Workspaces)
+	}
+	if saved, _ := loaded.Get("b"); len(saved.Sessions) != 0 {
+		t.Error("Expected Put to replace workspace b")
+	}
+	if err := loaded.Delete("missing"); err == nil {
+		t.Error("Expected error deleting a missing workspace")
+	}
+}